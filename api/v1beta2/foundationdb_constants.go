@@ -30,6 +30,19 @@ const (
 	// InitContainerName represents the container name of the init container.
 	InitContainerName = "foundationdb-kubernetes-init"
 
+	// TraceLogForwarderContainerName represents the default container name of the trace log forwarder container.
+	TraceLogForwarderContainerName = "trace-log-forwarder"
+
+	// DefaultTraceLogDirectory represents the default directory for the trace logs of the fdbserver processes.
+	DefaultTraceLogDirectory = "/var/log/fdb-trace-logs"
+
 	// NoneFaultDomainKey represents the none fault domain, where every Pod is a fault domain.
 	NoneFaultDomainKey = "foundationdb.org/none"
+
+	// DefaultSidecarPort represents the port the sidecar listens on, if the Pod doesn't use the host network.
+	DefaultSidecarPort = 8080
+
+	// HostNetworkProcessesPerProcessClass represents the number of processes per Pod each process class has a port
+	// range for, if the Pods of this process class use the host network.
+	HostNetworkProcessesPerProcessClass = 10
 )
@@ -22,6 +22,7 @@ package v1beta2
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -33,6 +34,31 @@ type FoundationDBCustomParameter string
 // +kubebuilder:validation:MaxItems=100
 type FoundationDBCustomParameters []FoundationDBCustomParameter
 
+// hotReloadableParameters contains the parameters that are interpreted by fdbmonitor and are not passed to the
+// fdbserver processes. fdbmonitor applies changes to those parameters when it reloads the monitor conf, so changing
+// them doesn't require a restart of the fdbserver processes. All other parameters are passed as command line arguments
+// to the fdbserver processes and require a restart.
+var hotReloadableParameters = map[string]None{
+	"restart_delay":                {},
+	"initial_restart_delay":        {},
+	"restart_backoff":              {},
+	"restart_delay_reset_interval": {},
+	"disable_lifecycle_logging":    {},
+	"delete_envvars":               {},
+}
+
+// GetName returns the name of the custom parameter.
+func (customParameter FoundationDBCustomParameter) GetName() string {
+	return strings.TrimSpace(strings.Split(string(customParameter), "=")[0])
+}
+
+// IsHotReloadable returns true if fdbmonitor can apply a change of this parameter without restarting the fdbserver
+// processes. fdbmonitor accepts dashes and underscores in the parameter names.
+func (customParameter FoundationDBCustomParameter) IsHotReloadable() bool {
+	_, ok := hotReloadableParameters[strings.ReplaceAll(customParameter.GetName(), "-", "_")]
+	return ok
+}
+
 // GetKnobsForCLI returns the list of knobs that should be provided to the commandline when running
 // an command over the admin client.
 func (customParameters FoundationDBCustomParameters) GetKnobsForCLI() []string {
@@ -54,8 +80,7 @@ func (customParameters FoundationDBCustomParameters) ValidateCustomParameters()
 	violations := make([]string, 0)
 
 	for _, parameter := range customParameters {
-		parameterName := strings.Split(string(parameter), "=")[0]
-		parameterName = strings.TrimSpace(parameterName)
+		parameterName := parameter.GetName()
 
 		if _, ok := parameters[parameterName]; !ok {
 			parameters[parameterName] = None{}
@@ -74,3 +99,179 @@ func (customParameters FoundationDBCustomParameters) ValidateCustomParameters()
 
 	return nil
 }
+
+// FoundationDBParameter defines a single parameter that will be passed to the fdbserver processes.
+type FoundationDBParameter struct {
+	// Name defines the name of the parameter, e.g. knob_disable_posix_kernel_aio.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=100
+	Name string `json:"name"`
+
+	// Value defines the value of the parameter. If the value is empty, the parameter will be passed as flag
+	// without a value.
+	// +kubebuilder:validation:MaxLength=100
+	Value string `json:"value,omitempty"`
+
+	// Remove marks the parameter as removed. A removed parameter will not be inherited from the general
+	// process settings.
+	Remove bool `json:"remove,omitempty"`
+}
+
+// FoundationDBParameters defines a list of parameters that will be passed to the fdbserver processes.
+// +kubebuilder:validation:MaxItems=100
+type FoundationDBParameters []FoundationDBParameter
+
+// GetNormalizedName returns the name of the parameter with underscores instead of dashes, as fdbserver and
+// fdbmonitor accept both.
+func (parameter FoundationDBParameter) GetNormalizedName() string {
+	return strings.ReplaceAll(strings.TrimSpace(parameter.Name), "-", "_")
+}
+
+// IsHotReloadable returns true if fdbmonitor can apply a change of this parameter without restarting the fdbserver
+// processes.
+func (parameter FoundationDBParameter) IsHotReloadable() bool {
+	_, ok := hotReloadableParameters[parameter.GetNormalizedName()]
+	return ok
+}
+
+// GetArgument returns the command line argument for the parameter.
+func (parameter FoundationDBParameter) GetArgument() string {
+	if parameter.Value == "" {
+		return fmt.Sprintf("--%s", strings.TrimSpace(parameter.Name))
+	}
+
+	return fmt.Sprintf("--%s=%s", strings.TrimSpace(parameter.Name), parameter.Value)
+}
+
+// ToParameter converts the custom parameter into a typed parameter.
+func (customParameter FoundationDBCustomParameter) ToParameter() FoundationDBParameter {
+	name, value, _ := strings.Cut(string(customParameter), "=")
+
+	return FoundationDBParameter{
+		Name:  strings.TrimSpace(name),
+		Value: strings.TrimSpace(value),
+	}
+}
+
+// ToParameters converts the custom parameters into typed parameters.
+func (customParameters FoundationDBCustomParameters) ToParameters() FoundationDBParameters {
+	if customParameters == nil {
+		return nil
+	}
+
+	parameters := make(FoundationDBParameters, 0, len(customParameters))
+	for _, customParameter := range customParameters {
+		parameters = append(parameters, customParameter.ToParameter())
+	}
+
+	return parameters
+}
+
+// GetParameterMap returns a map of the parameter names to the parameter values, removed parameters are not included.
+func (parameters FoundationDBParameters) GetParameterMap() map[string]string {
+	parameterMap := make(map[string]string, len(parameters))
+	for _, parameter := range parameters {
+		if parameter.Remove {
+			continue
+		}
+
+		parameterMap[parameter.GetNormalizedName()] = parameter.Value
+	}
+
+	return parameterMap
+}
+
+// Merge merges the parameters with the provided defaults. Parameters with the same name override the defaults and
+// removed parameters will be dropped from the defaults. The order of the defaults is preserved and additional
+// parameters are appended. The result doesn't contain any removal markers.
+func (parameters FoundationDBParameters) Merge(defaults FoundationDBParameters) FoundationDBParameters {
+	if parameters == nil && defaults == nil {
+		return nil
+	}
+
+	overrides := make(map[string]FoundationDBParameter, len(parameters))
+	for _, parameter := range parameters {
+		overrides[parameter.GetNormalizedName()] = parameter
+	}
+
+	candidates := make(FoundationDBParameters, 0, len(defaults)+len(parameters))
+	candidates = append(candidates, defaults...)
+	candidates = append(candidates, parameters...)
+
+	merged := make(FoundationDBParameters, 0, len(candidates))
+	added := make(map[string]None, len(candidates))
+	for _, parameter := range candidates {
+		name := parameter.GetNormalizedName()
+		if _, ok := added[name]; ok {
+			continue
+		}
+
+		if override, ok := overrides[name]; ok {
+			parameter = override
+		}
+
+		added[name] = None{}
+		if parameter.Remove {
+			continue
+		}
+
+		merged = append(merged, parameter)
+	}
+
+	return merged
+}
+
+// Diff returns the names of the parameters that were added, changed or removed in the provided parameters.
+func (parameters FoundationDBParameters) Diff(other FoundationDBParameters) []string {
+	current := parameters.GetParameterMap()
+	desired := other.GetParameterMap()
+	changed := make([]string, 0)
+
+	for name, value := range desired {
+		currentValue, ok := current[name]
+		if !ok || currentValue != value {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range current {
+		if _, ok := desired[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// ValidateParameters ensures that no duplicate values are set and that no protected parameters are set.
+func (parameters FoundationDBParameters) ValidateParameters() error {
+	protectedParameters := map[string]None{"datadir": {}}
+	names := make(map[string]None, len(parameters))
+	violations := make([]string, 0)
+
+	for _, parameter := range parameters {
+		name := parameter.GetNormalizedName()
+
+		if name == "" {
+			violations = append(violations, "found parameter without a name")
+			continue
+		}
+
+		if _, ok := names[name]; ok {
+			violations = append(violations, fmt.Sprintf("found duplicated parameter: %v", parameter.Name))
+		}
+		names[name] = None{}
+
+		if _, ok := protectedParameters[name]; ok && !parameter.Remove {
+			violations = append(violations, fmt.Sprintf("found protected parameter: %v, please remove this parameter from the parameters list", parameter.Name))
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("found the following parameters violations:\n%s", strings.Join(violations, "\n"))
+	}
+
+	return nil
+}
@@ -87,4 +87,142 @@ var _ = Describe("FoundationDBCustomParameters", func() {
 				errors.New("found the following customParameters violations:\nfound protected customParameter: datadir, please remove this parameter from the customParameters list\nfound duplicated customParameter: test")),
 		)
 	})
+
+	DescribeTable("checking if a custom parameter is hot reloadable",
+		func(customParameter FoundationDBCustomParameter, expected bool) {
+			Expect(customParameter.IsHotReloadable()).To(Equal(expected))
+		},
+		Entry("a knob",
+			FoundationDBCustomParameter("knob_http_verbose_level=3"),
+			false),
+		Entry("a locality",
+			FoundationDBCustomParameter("locality_test=1"),
+			false),
+		Entry("the restart delay",
+			FoundationDBCustomParameter("restart_delay=10"),
+			true),
+		Entry("the restart delay with spaces",
+			FoundationDBCustomParameter("restart_delay = 10"),
+			true),
+		Entry("the restart backoff with dashes",
+			FoundationDBCustomParameter("restart-backoff=10"),
+			true),
+	)
+})
+
+var _ = Describe("FoundationDBParameters", func() {
+	DescribeTable("converting custom parameters",
+		func(customParameters FoundationDBCustomParameters, expected FoundationDBParameters) {
+			Expect(customParameters.ToParameters()).To(Equal(expected))
+		},
+		Entry("no custom parameters",
+			nil,
+			nil),
+		Entry("a knob",
+			FoundationDBCustomParameters{"knob_http_verbose_level=3"},
+			FoundationDBParameters{{Name: "knob_http_verbose_level", Value: "3"}}),
+		Entry("a knob with spaces",
+			FoundationDBCustomParameters{"knob_http_verbose_level = 3"},
+			FoundationDBParameters{{Name: "knob_http_verbose_level", Value: "3"}}),
+		Entry("a value that contains an equal sign",
+			FoundationDBCustomParameters{"locality_test=a=b"},
+			FoundationDBParameters{{Name: "locality_test", Value: "a=b"}}),
+		Entry("a flag without a value",
+			FoundationDBCustomParameters{"disable_lifecycle_logging"},
+			FoundationDBParameters{{Name: "disable_lifecycle_logging"}}),
+	)
+
+	DescribeTable("getting the argument",
+		func(parameter FoundationDBParameter, expected string) {
+			Expect(parameter.GetArgument()).To(Equal(expected))
+		},
+		Entry("a parameter with a value",
+			FoundationDBParameter{Name: "knob_http_verbose_level", Value: "3"},
+			"--knob_http_verbose_level=3"),
+		Entry("a parameter without a value",
+			FoundationDBParameter{Name: "disable_lifecycle_logging"},
+			"--disable_lifecycle_logging"),
+	)
+
+	DescribeTable("merging the parameters",
+		func(parameters FoundationDBParameters, defaults FoundationDBParameters, expected FoundationDBParameters) {
+			Expect(parameters.Merge(defaults)).To(Equal(expected))
+		},
+		Entry("no parameters",
+			nil,
+			nil,
+			nil),
+		Entry("only defaults",
+			nil,
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Value: "2"}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Value: "2"}}),
+		Entry("an additional parameter",
+			FoundationDBParameters{{Name: "knob_c", Value: "3"}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_c", Value: "3"}}),
+		Entry("an overridden parameter",
+			FoundationDBParameters{{Name: "knob_b", Value: "3"}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Value: "2"}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Value: "3"}}),
+		Entry("an overridden parameter with dashes",
+			FoundationDBParameters{{Name: "restart-delay", Value: "3"}},
+			FoundationDBParameters{{Name: "restart_delay", Value: "1"}},
+			FoundationDBParameters{{Name: "restart-delay", Value: "3"}}),
+		Entry("a removed parameter",
+			FoundationDBParameters{{Name: "knob_a", Remove: true}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Value: "2"}},
+			FoundationDBParameters{{Name: "knob_b", Value: "2"}}),
+		Entry("a removed parameter that is not defined in the defaults",
+			FoundationDBParameters{{Name: "knob_c", Remove: true}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}},
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}}),
+	)
+
+	DescribeTable("getting the difference of the parameters",
+		func(parameters FoundationDBParameters, other FoundationDBParameters, expected []string) {
+			Expect(parameters.Diff(other)).To(Equal(expected))
+		},
+		Entry("no parameters",
+			nil,
+			nil,
+			[]string{}),
+		Entry("the same parameters in a different order",
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Value: "2"}},
+			FoundationDBParameters{{Name: "knob_b", Value: "2"}, {Name: "knob_a", Value: "1"}},
+			[]string{}),
+		Entry("added, changed and removed parameters",
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Value: "2"}},
+			FoundationDBParameters{{Name: "knob_b", Value: "3"}, {Name: "knob_c", Value: "1"}},
+			[]string{"knob_a", "knob_b", "knob_c"}),
+		Entry("a removed parameter",
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}},
+			FoundationDBParameters{{Name: "knob_a", Remove: true}},
+			[]string{"knob_a"}),
+	)
+
+	DescribeTable("validating the parameters",
+		func(parameters FoundationDBParameters, expected error) {
+			err := parameters.ValidateParameters()
+			if expected == nil {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(Equal(expected))
+			}
+		},
+		Entry("no parameters",
+			FoundationDBParameters{},
+			nil),
+		Entry("valid parameters",
+			FoundationDBParameters{{Name: "knob_a", Value: "1"}, {Name: "knob_b", Remove: true}},
+			nil),
+		Entry("a parameter without a name",
+			FoundationDBParameters{{Value: "1"}},
+			errors.New("found the following parameters violations:\nfound parameter without a name")),
+		Entry("a protected parameter",
+			FoundationDBParameters{{Name: "datadir", Value: "test"}},
+			errors.New("found the following parameters violations:\nfound protected parameter: datadir, please remove this parameter from the parameters list")),
+		Entry("duplicated parameters",
+			FoundationDBParameters{{Name: "restart_delay", Value: "1"}, {Name: "restart-delay", Value: "2"}},
+			errors.New("found the following parameters violations:\nfound duplicated parameter: restart-delay")),
+	)
 })
@@ -53,6 +53,19 @@ type DatabaseConfiguration struct {
 	// +kubebuilder:validation:MaxItems=1024
 	ExcludedServers []ExcludedServers `json:"excluded_servers,omitempty"`
 
+	// PerpetualStorageWiggle defines if the perpetual storage wiggle should be enabled. A value of 1 enables the
+	// perpetual storage wiggle and a value of 0 disables it. If this is not set, the operator will not change the
+	// current setting.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	PerpetualStorageWiggle *int `json:"perpetual_storage_wiggle,omitempty"`
+
+	// PerpetualStorageWiggleLocality limits the perpetual storage wiggle to the processes matching the locality
+	// in the format <key>:<value>, e.g. zoneid:zone1. A value of 0 removes the limitation. If this is not set,
+	// the operator will not change the current setting.
+	// +kubebuilder:validation:MaxLength=200
+	PerpetualStorageWiggleLocality string `json:"perpetual_storage_wiggle_locality,omitempty"`
+
 	// RoleCounts defines how many processes the database should recruit for
 	// each role.
 	RoleCounts `json:""`
@@ -647,6 +660,14 @@ func (configuration DatabaseConfiguration) GetConfigurationString(version string
 
 	configurationString += " regions=" + regionString
 
+	if configuration.PerpetualStorageWiggle != nil {
+		configurationString += fmt.Sprintf(" perpetual_storage_wiggle=%d", *configuration.PerpetualStorageWiggle)
+	}
+
+	if configuration.PerpetualStorageWiggleLocality != "" {
+		configurationString += " perpetual_storage_wiggle_locality=" + configuration.PerpetualStorageWiggleLocality
+	}
+
 	return configurationString, nil
 }
 
@@ -37,6 +37,18 @@ const (
 	// deployments to a cluster.
 	BackupDeploymentLabel = "foundationdb.org/backup-for"
 
+	// TestScenarioPartitionLabel provides the label we use to select the pods
+	// whose sidecar is partitioned by a test scenario.
+	TestScenarioPartitionLabel = "foundationdb.org/test-scenario-partition"
+
+	// ClientLibraryCacheLabel provides the label we use to connect the
+	// workloads and volumes of a client library cache to the cache.
+	ClientLibraryCacheLabel = "foundationdb.org/client-library-cache"
+
+	// RestorableVersionAnnotation provides the annotation name we use to store
+	// the version at which a VolumeSnapshot of a backup can be restored.
+	RestorableVersionAnnotation = "foundationdb.org/restorable-version"
+
 	// PublicIPSourceAnnotation is an annotation key that specifies where a pod
 	// gets its public IP from.
 	PublicIPSourceAnnotation = "foundationdb.org/public-ip-source"
@@ -45,6 +57,26 @@ const (
 	// IP for a pod.
 	PublicIPAnnotation = "foundationdb.org/public-ip"
 
+	// ForceDeletionAnnotation is an annotation key that allows the deletion
+	// of a pod that would otherwise be rejected by the pod deletion
+	// protection, if the value is true.
+	ForceDeletionAnnotation = "foundationdb.org/force-deletion"
+
+	// RestoreInProgressAnnotation is an annotation key that marks a cluster
+	// as the destination of a restore that is in progress. The value is the
+	// name of the FoundationDBRestore.
+	RestoreInProgressAnnotation = "foundationdb.org/restore-in-progress"
+
+	// ExternalAccessLabel provides the label we use to mark the services that
+	// expose a coordinator to clients outside of the Kubernetes cluster. The
+	// value is the process group ID of the coordinator.
+	ExternalAccessLabel = "foundationdb.org/external-access-for"
+
+	// CliCommandLabel provides the label we use to mark the Pods and Secrets
+	// that run CLI commands for a cluster. The value is the name of the
+	// cluster.
+	CliCommandLabel = "foundationdb.org/cli-command-for"
+
 	// FDBProcessGroupIDLabel represents the label that is used to represent a instance ID
 	FDBProcessGroupIDLabel = "foundationdb.org/fdb-process-group-id"
 
@@ -61,6 +93,10 @@ const (
 	// holds the instance ID.
 	FDBLocalityInstanceIDKey = "instance_id"
 
+	// FDBLocalityInstanceIDExclusionPrefix represents the prefix of the exclusions
+	// that exclude the processes with a specific instance ID.
+	FDBLocalityInstanceIDExclusionPrefix = "locality_instance_id:"
+
 	// FDBLocalityZoneIDKey represents the key in the locality map that holds
 	// the zone ID.
 	FDBLocalityZoneIDKey = "zoneid"
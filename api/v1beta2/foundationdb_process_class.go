@@ -57,3 +57,25 @@ func (pClass ProcessClass) IsStateful() bool {
 func (pClass ProcessClass) IsTransaction() bool {
 	return pClass != ProcessClassStorage && pClass != ProcessClassGeneral
 }
+
+// GetReassignmentStrategy returns the strategy to assign a process group of this process class to the other process
+// class. Process groups can only be removed immediately if neither process class stores data.
+func (pClass ProcessClass) GetReassignmentStrategy(other ProcessClass) ProcessClassReassignmentStrategy {
+	if pClass.IsStateful() || other.IsStateful() {
+		return ProcessClassReassignmentStrategyReplacement
+	}
+
+	return ProcessClassReassignmentStrategyImmediate
+}
+
+// isKnownProcessClass returns true if the process class is one of the process classes that are managed by the
+// operator.
+func isKnownProcessClass(processClass ProcessClass) bool {
+	for _, knownProcessClass := range ProcessClasses {
+		if knownProcessClass == processClass {
+			return true
+		}
+	}
+
+	return false
+}
@@ -108,6 +108,44 @@ type FoundationDBStatusClusterInfo struct {
 
 	// ConnectionString represents the connection string in the cluster status json output.
 	ConnectionString string `json:"connection_string,omitempty"`
+
+	// StorageWiggler provides information about the perpetual storage wiggle.
+	StorageWiggler FoundationDBStatusStorageWiggler `json:"storage_wiggler,omitempty"`
+
+	// Workload provides information about the workload of the database.
+	Workload FoundationDBStatusWorkload `json:"workload,omitempty"`
+}
+
+// FoundationDBStatusWorkload provides information about the workload of the
+// database.
+type FoundationDBStatusWorkload struct {
+	// Operations provides information about the operations that are
+	// performed against the database.
+	Operations FoundationDBStatusWorkloadOperations `json:"operations,omitempty"`
+}
+
+// FoundationDBStatusWorkloadOperations provides information about the
+// operations that are performed against the database.
+type FoundationDBStatusWorkloadOperations struct {
+	// Reads provides the rate of read operations.
+	Reads FoundationDBStatusRate `json:"reads,omitempty"`
+
+	// Writes provides the rate of write operations.
+	Writes FoundationDBStatusRate `json:"writes,omitempty"`
+}
+
+// FoundationDBStatusRate provides the rate of an event in the status.
+type FoundationDBStatusRate struct {
+	// Hz provides the number of events per second.
+	Hz float64 `json:"hz,omitempty"`
+}
+
+// FoundationDBStatusStorageWiggler provides information about the perpetual
+// storage wiggle.
+type FoundationDBStatusStorageWiggler struct {
+	// WiggleServerAddresses contains the addresses of the storage servers
+	// that are currently wiggled.
+	WiggleServerAddresses []string `json:"wiggle_server_addresses,omitempty"`
 }
 
 // FaultTolerance provides information about the fault tolerance status
@@ -148,6 +186,58 @@ type FoundationDBStatusProcessInfo struct {
 
 	// Messages contains error messages from that fdbserver process instance
 	Messages []FoundationDBStatusProcessMessage `json:"messages,omitempty"`
+
+	// Disk provides information about the disk of the process.
+	Disk FoundationDBStatusProcessDiskInfo `json:"disk,omitempty"`
+
+	// Network provides information about the network connections of the process.
+	Network FoundationDBStatusProcessNetworkInfo `json:"network,omitempty"`
+}
+
+// processRoleAliases contains the roles that are reported under a different name depending on the version of
+// FoundationDB. Versions before 7.0 report a single "proxy" role, newer versions report separate "commit_proxy" and
+// "grv_proxy" roles.
+var processRoleAliases = map[ProcessRole][]ProcessRole{
+	ProcessRoleProxy:       {ProcessRoleCommitProxy, ProcessRoleGrvProxy},
+	ProcessRoleCommitProxy: {ProcessRoleProxy},
+	ProcessRoleGrvProxy:    {ProcessRoleProxy},
+}
+
+// HasRole returns true if the process has the provided role. Roles that were renamed between versions of
+// FoundationDB are matched by all of their names, e.g. ProcessRoleProxy matches the "commit_proxy" and "grv_proxy"
+// roles of a 7.x cluster and ProcessRoleCommitProxy matches the "proxy" role of a 6.3 cluster.
+func (process FoundationDBStatusProcessInfo) HasRole(role ProcessRole) bool {
+	for _, roleInfo := range process.Roles {
+		if ProcessRole(roleInfo.Role) == role {
+			return true
+		}
+
+		for _, alias := range processRoleAliases[role] {
+			if ProcessRole(roleInfo.Role) == alias {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FoundationDBStatusProcessDiskInfo represents the disk information of a
+// process in the status json
+type FoundationDBStatusProcessDiskInfo struct {
+	// FreeBytes provides the number of free bytes on the disk.
+	FreeBytes int64 `json:"free_bytes,omitempty"`
+
+	// TotalBytes provides the total number of bytes on the disk.
+	TotalBytes int64 `json:"total_bytes,omitempty"`
+}
+
+// FoundationDBStatusProcessNetworkInfo represents the network information of a
+// process in the status json
+type FoundationDBStatusProcessNetworkInfo struct {
+	// CurrentConnections provides the number of open connections of the process, this includes the connections of
+	// clients and of other processes of the cluster.
+	CurrentConnections int `json:"current_connections,omitempty"`
 }
 
 // FoundationDBStatusProcessMessage represents an error message in the status json
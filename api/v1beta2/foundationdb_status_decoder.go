@@ -0,0 +1,260 @@
+/*
+ * foundationdb_status_decoder.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// StatusDecodeResult provides information about the fields of the machine-readable status that couldn't be mapped to
+// the FoundationDBStatus. Newer versions of FoundationDB can add fields or change the type of fields, which should not
+// break the parsing of the fields the operator depends on.
+// +kubebuilder:object:generate=false
+type StatusDecodeResult struct {
+	// unknownFields contains the paths of the fields that are not part of the FoundationDBStatus.
+	unknownFields map[string]None
+
+	// invalidFields contains the paths of the fields whose value doesn't match the expected type.
+	invalidFields map[string]None
+
+	// presentFields contains the paths of the fields of the FoundationDBStatus that were present in the status.
+	presentFields map[string]None
+}
+
+// UnknownFields returns the sorted paths of the fields that are not part of the FoundationDBStatus. Map keys are
+// replaced with "*" and list elements are marked with "[]", e.g. "cluster.processes.*.roles[].kvstore_used_bytes".
+func (result *StatusDecodeResult) UnknownFields() []string {
+	return getSortedPaths(result.unknownFields)
+}
+
+// InvalidFields returns the sorted paths of the fields whose value couldn't be parsed into the expected type. These
+// fields keep their zero value.
+func (result *StatusDecodeResult) InvalidFields() []string {
+	return getSortedPaths(result.invalidFields)
+}
+
+// HasField returns true if the status contained the field with the provided path, e.g.
+// "cluster.recovery_state.seconds_since_last_recovered". This can be used to check if the running version of
+// FoundationDB reports a field instead of relying on the zero value.
+func (result *StatusDecodeResult) HasField(path string) bool {
+	_, ok := result.presentFields[path]
+	return ok
+}
+
+// getSortedPaths returns the paths of the set in sorted order.
+func getSortedPaths(paths map[string]None) []string {
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// ParseFoundationDBStatus parses the machine-readable status. In contrast to a strict decoding, fields that don't
+// match the expected type are skipped and reported in the result instead of failing the whole parsing. An error is
+// only returned if the data is not a JSON object.
+func ParseFoundationDBStatus(data []byte) (*FoundationDBStatus, *StatusDecodeResult, error) {
+	status := &FoundationDBStatus{}
+	result, err := status.decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return status, result, nil
+}
+
+// UnmarshalJSON parses the machine-readable status with the tolerant decoder, see ParseFoundationDBStatus.
+func (status *FoundationDBStatus) UnmarshalJSON(data []byte) error {
+	_, err := status.decode(data)
+	return err
+}
+
+// decode parses the data into the status.
+func (status *FoundationDBStatus) decode(data []byte) (*StatusDecodeResult, error) {
+	decoder := &StatusDecodeResult{
+		unknownFields: map[string]None{},
+		invalidFields: map[string]None{},
+		presentFields: map[string]None{},
+	}
+
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fields are decoded directly to prevent the recursion into UnmarshalJSON.
+	*status = FoundationDBStatus{}
+	decoder.decodeFields("", fields, reflect.ValueOf(status).Elem())
+
+	return decoder, nil
+}
+
+// joinPath appends the name to the path.
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// decodeValue decodes the raw value into the target.
+func (result *StatusDecodeResult) decodeValue(path string, raw json.RawMessage, target reflect.Value) {
+	result.presentFields[path] = None{}
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return
+	}
+
+	// Types with a custom parsing method, like the ProcessAddress, are parsed by their method.
+	if _, ok := target.Addr().Interface().(json.Unmarshaler); ok {
+		result.decodeLeaf(path, raw, target)
+		return
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		result.decodeValue(path, raw, target.Elem())
+	case reflect.Struct:
+		fields := map[string]json.RawMessage{}
+		err := json.Unmarshal(raw, &fields)
+		if err != nil {
+			result.invalidFields[path] = None{}
+			return
+		}
+		result.decodeFields(path, fields, target)
+	case reflect.Map:
+		if target.Type().Key().Kind() != reflect.String {
+			result.decodeLeaf(path, raw, target)
+			return
+		}
+
+		entries := map[string]json.RawMessage{}
+		err := json.Unmarshal(raw, &entries)
+		if err != nil {
+			result.invalidFields[path] = None{}
+			return
+		}
+
+		if target.IsNil() {
+			target.Set(reflect.MakeMapWithSize(target.Type(), len(entries)))
+		}
+
+		for key, value := range entries {
+			entry := reflect.New(target.Type().Elem()).Elem()
+			result.decodeValue(joinPath(path, "*"), value, entry)
+			target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), entry)
+		}
+	case reflect.Slice:
+		var elements []json.RawMessage
+		err := json.Unmarshal(raw, &elements)
+		if err != nil {
+			result.invalidFields[path] = None{}
+			return
+		}
+
+		slice := reflect.MakeSlice(target.Type(), len(elements), len(elements))
+		for idx, element := range elements {
+			result.decodeValue(path+"[]", element, slice.Index(idx))
+		}
+		target.Set(slice)
+	default:
+		result.decodeLeaf(path, raw, target)
+	}
+}
+
+// decodeLeaf decodes the raw value with the standard decoder and resets the target if the value doesn't match the
+// type.
+func (result *StatusDecodeResult) decodeLeaf(path string, raw json.RawMessage, target reflect.Value) {
+	value := reflect.New(target.Type())
+	err := json.Unmarshal(raw, value.Interface())
+	if err != nil {
+		result.invalidFields[path] = None{}
+		return
+	}
+
+	target.Set(value.Elem())
+}
+
+// decodeFields decodes the JSON object fields into the struct target.
+func (result *StatusDecodeResult) decodeFields(path string, fields map[string]json.RawMessage, target reflect.Value) {
+	structFields := map[string]reflect.Value{}
+	collectStructFields(target, structFields)
+
+	for name, raw := range fields {
+		field, ok := structFields[name]
+		if !ok {
+			// The standard decoder matches the field names case-insensitively.
+			for fieldName, structField := range structFields {
+				if strings.EqualFold(fieldName, name) {
+					field = structField
+					ok = true
+					break
+				}
+			}
+		}
+
+		fieldPath := joinPath(path, name)
+		if !ok {
+			result.unknownFields[fieldPath] = None{}
+			continue
+		}
+
+		result.decodeValue(fieldPath, raw, field)
+	}
+}
+
+// collectStructFields collects the fields of the struct by their JSON name. Embedded structs without a JSON name
+// are flattened like in the standard decoder.
+func collectStructFields(target reflect.Value, fields map[string]reflect.Value) {
+	targetType := target.Type()
+	for idx := 0; idx < targetType.NumField(); idx++ {
+		structField := targetType.Field(idx)
+		if !structField.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" && structField.Anonymous && structField.Type.Kind() == reflect.Struct {
+			collectStructFields(target.Field(idx), fields)
+			continue
+		}
+
+		if name == "" {
+			name = structField.Name
+		}
+
+		fields[name] = target.Field(idx)
+	}
+}
@@ -26,11 +26,104 @@ import (
 	"os"
 	"path/filepath"
 
+	"k8s.io/utils/pointer"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("FoundationDBStatus", func() {
+	When("parsing the storage wiggler information", func() {
+		It("should parse the wiggled addresses", func() {
+			status := &FoundationDBStatus{}
+			err := json.Unmarshal([]byte(`{"cluster":{"storage_wiggler":{"wiggle_server_addresses":["10.1.18.254:4501"],"wiggle_server_ids":["0ccb4e0feddb55"]}}}`), status)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Cluster.StorageWiggler.WiggleServerAddresses).To(ConsistOf("10.1.18.254:4501"))
+		})
+	})
+
+	When("parsing a status with fields that don't match the schema", func() {
+		var status *FoundationDBStatus
+		var result *StatusDecodeResult
+
+		BeforeEach(func() {
+			var err error
+			status, result, err = ParseFoundationDBStatus([]byte(`{
+				"cluster": {
+					"generation": "5",
+					"full_replication": true,
+					"new_section": {"enabled": true},
+					"processes": {
+						"abc": {
+							"address": "10.1.18.254:4501",
+							"uptime_seconds": "unknown",
+							"roles": [{"role": "storage", "stored_bytes": 10, "kvstore_used_bytes": 20}],
+							"locality": {"instance_id": "storage-1"}
+						}
+					}
+				},
+				"client": {"coordinators": {"quorum_reachable": true}}
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should parse the valid fields", func() {
+			Expect(status.Cluster.FullReplication).To(BeTrue())
+			Expect(status.Client.Coordinators.QuorumReachable).To(BeTrue())
+			Expect(status.Cluster.Processes).To(HaveKey(ProcessGroupID("abc")))
+			process := status.Cluster.Processes["abc"]
+			Expect(process.Address.String()).To(Equal("10.1.18.254:4501"))
+			Expect(process.Locality).To(HaveKeyWithValue("instance_id", "storage-1"))
+			Expect(process.Roles).To(ConsistOf(FoundationDBStatusProcessRoleInfo{Role: "storage", StoredBytes: 10}))
+		})
+
+		It("should skip the fields with a wrong type", func() {
+			Expect(status.Cluster.Generation).To(BeZero())
+			Expect(status.Cluster.Processes["abc"].UptimeSeconds).To(BeZero())
+			Expect(result.InvalidFields()).To(ConsistOf("cluster.generation", "cluster.processes.*.uptime_seconds"))
+		})
+
+		It("should record the unknown fields", func() {
+			Expect(result.UnknownFields()).To(ConsistOf("cluster.new_section", "cluster.processes.*.roles[].kvstore_used_bytes"))
+		})
+
+		It("should report the present fields", func() {
+			Expect(result.HasField("cluster.full_replication")).To(BeTrue())
+			Expect(result.HasField("cluster.processes.*.roles[].role")).To(BeTrue())
+			Expect(result.HasField("cluster.recovery_state")).To(BeFalse())
+		})
+
+		It("should be tolerant when using the standard decoder", func() {
+			decoded := &FoundationDBStatus{}
+			Expect(json.Unmarshal([]byte(`{"cluster":{"generation":"5","full_replication":true}}`), decoded)).NotTo(HaveOccurred())
+			Expect(decoded.Cluster.FullReplication).To(BeTrue())
+		})
+	})
+
+	When("the status is not a JSON object", func() {
+		It("should return an error", func() {
+			_, _, err := ParseFoundationDBStatus([]byte(`[]`))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("checking the roles of a process", func() {
+		DescribeTable("should match the roles across versions",
+			func(reportedRole ProcessRole, role ProcessRole, expected bool) {
+				process := FoundationDBStatusProcessInfo{
+					Roles: []FoundationDBStatusProcessRoleInfo{{Role: string(reportedRole)}},
+				}
+				Expect(process.HasRole(role)).To(Equal(expected))
+			},
+			Entry("the same role", ProcessRoleStorage, ProcessRoleStorage, true),
+			Entry("a different role", ProcessRoleStorage, ProcessRoleLog, false),
+			Entry("a 6.3 proxy as commit proxy", ProcessRoleProxy, ProcessRoleCommitProxy, true),
+			Entry("a 6.3 proxy as grv proxy", ProcessRoleProxy, ProcessRoleGrvProxy, true),
+			Entry("a 7.x commit proxy as proxy", ProcessRoleCommitProxy, ProcessRoleProxy, true),
+			Entry("a 7.x grv proxy as commit proxy", ProcessRoleGrvProxy, ProcessRoleCommitProxy, false),
+		)
+	})
+
 	When("parsing the status json with a 6.2 cluster", func() {
 		It("should parse all values correctly", func() {
 			statusFile, err := os.OpenFile(filepath.Join("testdata", "fdb_status_6_2.json"), os.O_RDONLY, os.ModePerm)
@@ -95,6 +188,13 @@ var _ = Describe("FoundationDBStatus", func() {
 							ProcessClass: ProcessClassLog,
 							CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --locality_instance_id=log-3 --locality_machineid=sample-cluster-log-3 --locality_zoneid=sample-cluster-log-3 --logdir=/var/log/fdb-trace-logs --loggroup=sample-cluster --public_address=10.1.38.93:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 							Excluded:     false,
+							Disk: FoundationDBStatusProcessDiskInfo{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
+							Network: FoundationDBStatusProcessNetworkInfo{
+								CurrentConnections: 6,
+							},
 							Locality: map[string]string{
 								"processid":   "b9c25278c0fa207bc2a73bda2300d0a9",
 								"zoneid":      "sample-cluster-log-3",
@@ -119,6 +219,13 @@ var _ = Describe("FoundationDBStatus", func() {
 							ProcessClass: ProcessClassStorage,
 							CommandLine:  "/usr/bin/fdbserver --class=storage --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --locality_instance_id=storage-3 --locality_machineid=sample-cluster-storage-3 --locality_zoneid=sample-cluster-storage-3 --logdir=/var/log/fdb-trace-logs --loggroup=sample-cluster --public_address=10.1.38.95:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 							Excluded:     false,
+							Disk: FoundationDBStatusProcessDiskInfo{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
+							Network: FoundationDBStatusProcessNetworkInfo{
+								CurrentConnections: 6,
+							},
 							Locality: map[string]string{
 								"instance_id": "storage-3",
 								"machineid":   "sample-cluster-storage-3",
@@ -147,6 +254,13 @@ var _ = Describe("FoundationDBStatus", func() {
 							ProcessClass: ProcessClassStorage,
 							CommandLine:  "/usr/bin/fdbserver --class=storage --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --locality_instance_id=storage-1 --locality_machineid=sample-cluster-storage-1 --locality_zoneid=sample-cluster-storage-1 --logdir=/var/log/fdb-trace-logs --loggroup=sample-cluster --public_address=10.1.38.92:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 							Excluded:     false,
+							Disk: FoundationDBStatusProcessDiskInfo{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
+							Network: FoundationDBStatusProcessNetworkInfo{
+								CurrentConnections: 6,
+							},
 							Locality: map[string]string{
 								"instance_id": "storage-1",
 								"machineid":   "sample-cluster-storage-1",
@@ -175,6 +289,13 @@ var _ = Describe("FoundationDBStatus", func() {
 							ProcessClass: ProcessClassLog,
 							CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --locality_instance_id=log-2 --locality_machineid=sample-cluster-log-2 --locality_zoneid=sample-cluster-log-2 --logdir=/var/log/fdb-trace-logs --loggroup=sample-cluster --public_address=10.1.38.105:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 							Excluded:     false,
+							Disk: FoundationDBStatusProcessDiskInfo{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
+							Network: FoundationDBStatusProcessNetworkInfo{
+								CurrentConnections: 7,
+							},
 							Locality: map[string]string{
 								"instance_id": "log-2",
 								"machineid":   "sample-cluster-log-2",
@@ -203,6 +324,13 @@ var _ = Describe("FoundationDBStatus", func() {
 							ProcessClass: ProcessClassLog,
 							CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --locality_instance_id=log-4 --locality_machineid=sample-cluster-log-4 --locality_zoneid=sample-cluster-log-4 --logdir=/var/log/fdb-trace-logs --loggroup=sample-cluster --public_address=10.1.38.102:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 							Excluded:     false,
+							Disk: FoundationDBStatusProcessDiskInfo{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
+							Network: FoundationDBStatusProcessNetworkInfo{
+								CurrentConnections: 15,
+							},
 							Locality: map[string]string{
 								"zoneid":      "sample-cluster-log-4",
 								"instance_id": "log-4",
@@ -230,6 +358,13 @@ var _ = Describe("FoundationDBStatus", func() {
 							ProcessClass: ProcessClassLog,
 							CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --locality_instance_id=log-1 --locality_machineid=sample-cluster-log-1 --locality_zoneid=sample-cluster-log-1 --logdir=/var/log/fdb-trace-logs --loggroup=sample-cluster --public_address=10.1.38.104:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 							Excluded:     false,
+							Disk: FoundationDBStatusProcessDiskInfo{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
+							Network: FoundationDBStatusProcessNetworkInfo{
+								CurrentConnections: 15,
+							},
 							Locality: map[string]string{
 								"instance_id": "log-1",
 								"machineid":   "sample-cluster-log-1",
@@ -269,6 +404,13 @@ var _ = Describe("FoundationDBStatus", func() {
 							ProcessClass: ProcessClassStorage,
 							CommandLine:  "/usr/bin/fdbserver --class=storage --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --locality_instance_id=storage-2 --locality_machineid=sample-cluster-storage-2 --locality_zoneid=sample-cluster-storage-2 --logdir=/var/log/fdb-trace-logs --loggroup=sample-cluster --public_address=10.1.38.94:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 							Excluded:     false,
+							Disk: FoundationDBStatusProcessDiskInfo{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
+							Network: FoundationDBStatusProcessNetworkInfo{
+								CurrentConnections: 17,
+							},
 							Locality: map[string]string{
 								"instance_id": "storage-2",
 								"machineid":   "sample-cluster-storage-2",
@@ -479,6 +621,12 @@ var _ = Describe("FoundationDBStatus", func() {
 					RecoveryState: RecoveryState{
 						Name: "fully_recovered",
 					},
+					Workload: FoundationDBStatusWorkload{
+						Operations: FoundationDBStatusWorkloadOperations{
+							Reads:  FoundationDBStatusRate{Hz: 10.1989},
+							Writes: FoundationDBStatusRate{Hz: 0.399989},
+						},
+					},
 				},
 			}))
 		})
@@ -489,13 +637,15 @@ var _ = Describe("FoundationDBStatus", func() {
 			IncompatibleConnections: []string{},
 			ConnectionString:        "test_cluster:aHeD9ocNXOUxi0dyzU3k7Bhg53SpyrBV@10.1.18.253:4501,10.1.18.254:4501,10.1.19.0:4501",
 			DatabaseConfiguration: DatabaseConfiguration{
-				RedundancyMode:  "double",
-				StorageEngine:   StorageEngineSSD2,
-				UsableRegions:   1,
-				Regions:         nil,
-				ExcludedServers: make([]ExcludedServers, 0),
-				RoleCounts:      RoleCounts{Storage: 0, Logs: 3, Proxies: 3, CommitProxies: 2, GrvProxies: 1, Resolvers: 1, LogRouters: -1, RemoteLogs: -1},
-				VersionFlags:    VersionFlags{LogSpill: 2, LogVersion: 0},
+				RedundancyMode:                 "double",
+				StorageEngine:                  StorageEngineSSD2,
+				UsableRegions:                  1,
+				Regions:                        nil,
+				ExcludedServers:                make([]ExcludedServers, 0),
+				RoleCounts:                     RoleCounts{Storage: 0, Logs: 3, Proxies: 3, CommitProxies: 2, GrvProxies: 1, Resolvers: 1, LogRouters: -1, RemoteLogs: -1},
+				VersionFlags:                   VersionFlags{LogSpill: 2, LogVersion: 0},
+				PerpetualStorageWiggle:         pointer.Int(0),
+				PerpetualStorageWiggleLocality: "0",
 			},
 			Processes: map[ProcessGroupID]FoundationDBStatusProcessInfo{
 				"eb48ada3a682e86363f06aa89e1041fa": {
@@ -506,6 +656,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassStorage,
 					CommandLine:  "/usr/bin/fdbserver --class=storage --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.18.254:4501 --locality_instance_id=storage-1 --locality_machineid=test-cluster-storage-1 --locality_zoneid=test-cluster-storage-1 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.18.254:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178145280,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 9,
+					},
 					Locality: map[string]string{
 						"instance_id": "storage-1",
 						"machineid":   "test-cluster-storage-1",
@@ -535,6 +692,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassStorage,
 					CommandLine:  "/usr/bin/fdbserver --class=storage --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.18.253:4501 --locality_instance_id=storage-3 --locality_machineid=test-cluster-storage-3 --locality_zoneid=test-cluster-storage-3 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.18.253:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178145280,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 9,
+					},
 					Locality: map[string]string{
 						"instance_id": "storage-3",
 						"machineid":   "test-cluster-storage-3",
@@ -564,6 +728,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassStorage,
 					CommandLine:  "/usr/bin/fdbserver --class=storage --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.19.0:4501 --locality_instance_id=storage-2 --locality_machineid=test-cluster-storage-2 --locality_zoneid=test-cluster-storage-2 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.19.0:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178145280,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 9,
+					},
 					Locality: map[string]string{
 						"instance_id": "storage-2",
 						"machineid":   "test-cluster-storage-2",
@@ -593,6 +764,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassLog,
 					CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.19.1:4501 --locality_instance_id=log-1 --locality_machineid=test-cluster-log-1 --locality_zoneid=test-cluster-log-1 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.19.1:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 7,
+					},
 					Locality: map[string]string{
 						"machineid":   "test-cluster-log-1",
 						"processid":   "f6e0f7fd80da429d20329ad95d793ca3",
@@ -625,6 +803,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassClusterController,
 					CommandLine:  "/usr/bin/fdbserver --class=cluster_controller --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.18.255:4501 --locality_instance_id=cluster_controller-1 --locality_machineid=test-cluster-cluster-controller-1 --locality_zoneid=test-cluster-cluster-controller-1 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.18.255:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 9,
+					},
 					Locality: map[string]string{
 						"processid":   "f75644abdf1b06c803b5c3c124fdd0a0",
 						"zoneid":      "test-cluster-cluster-controller-1",
@@ -649,6 +834,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassLog,
 					CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.19.2:4501 --locality_instance_id=log-3 --locality_machineid=test-cluster-log-3 --locality_zoneid=test-cluster-log-3 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.19.2:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 5,
+					},
 					Locality: map[string]string{
 						"instance_id": "log-3",
 						"machineid":   "test-cluster-log-3",
@@ -673,6 +865,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassLog,
 					CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.19.4:4501 --locality_instance_id=log-2 --locality_machineid=test-cluster-log-2 --locality_zoneid=test-cluster-log-2 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.19.4:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 6,
+					},
 					Locality: map[string]string{
 						"machineid":   "test-cluster-log-2",
 						"processid":   "78c1c84af4481f0df628d40358f0930a",
@@ -697,6 +896,13 @@ var _ = Describe("FoundationDBStatus", func() {
 					ProcessClass: ProcessClassLog,
 					CommandLine:  "/usr/bin/fdbserver --class=log --cluster_file=/var/fdb/data/fdb.cluster --datadir=/var/fdb/data --knob_disable_posix_kernel_aio=1 --listen_address=10.1.19.3:4501 --locality_instance_id=log-4 --locality_machineid=test-cluster-log-4 --locality_zoneid=test-cluster-log-4 --logdir=/var/log/fdb-trace-logs --loggroup=test-cluster --public_address=10.1.19.3:4501 --seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 					Excluded:     false,
+					Disk: FoundationDBStatusProcessDiskInfo{
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
+					Network: FoundationDBStatusProcessNetworkInfo{
+						CurrentConnections: 6,
+					},
 					Locality: map[string]string{
 						"instance_id": "log-4",
 						"machineid":   "test-cluster-log-4",
@@ -852,6 +1058,12 @@ var _ = Describe("FoundationDBStatus", func() {
 				SecondsSinceLastRecovered: 76.8155,
 			},
 			Generation: 2,
+			Workload: FoundationDBStatusWorkload{
+				Operations: FoundationDBStatusWorkloadOperations{
+					Reads:  FoundationDBStatusRate{Hz: 8.59959},
+					Writes: FoundationDBStatusRate{Hz: 0.39998900000000004},
+				},
+			},
 		}
 
 		It("should parse all values correctly", func() {
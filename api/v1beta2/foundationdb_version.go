@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"sync"
 )
 
 // Version represents a version of FoundationDB.
@@ -48,8 +49,29 @@ type Version struct {
 // VersionRegex describes the format of a FoundationDB version.
 var VersionRegex = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)(-rc(\d+))?`)
 
+// parsedVersions caches the versions that were parsed successfully, keyed by their string representation. The
+// versions of a cluster are parsed in many places during a single reconciliation, e.g. for every Pod, so this avoids
+// running the regular expression for the same version string again.
+var parsedVersions sync.Map
+
 // ParseFdbVersion parses a version from its string representation.
 func ParseFdbVersion(version string) (Version, error) {
+	if cached, ok := parsedVersions.Load(version); ok {
+		return cached.(Version), nil
+	}
+
+	parsed, err := parseFdbVersion(version)
+	if err != nil {
+		return Version{}, err
+	}
+
+	parsedVersions.Store(version, parsed)
+
+	return parsed, nil
+}
+
+// parseFdbVersion parses a version from its string representation without using the cache.
+func parseFdbVersion(version string) (Version, error) {
 	matches := VersionRegex.FindStringSubmatch(version)
 	if matches == nil {
 		return Version{}, fmt.Errorf("could not parse FDB version from %s", version)
@@ -209,6 +231,58 @@ func (version Version) SupportsRecoveryState() bool {
 	return version.IsAtLeast(Versions.SupportsRecoveryState)
 }
 
+// SupportsTenants returns true if the version of FDB supports tenants.
+func (version Version) SupportsTenants() bool {
+	return version.IsAtLeast(Versions.SupportsTenants)
+}
+
+// SupportsPerpetualStorageWiggle returns true if the version of FDB supports the perpetual storage wiggle.
+func (version Version) SupportsPerpetualStorageWiggle() bool {
+	return version.IsAtLeast(Versions.SupportsPerpetualStorageWiggle)
+}
+
+// SupportsPerpetualStorageWiggleLocality returns true if the version of FDB supports limiting the perpetual storage
+// wiggle to a locality.
+func (version Version) SupportsPerpetualStorageWiggleLocality() bool {
+	return version.IsAtLeast(Versions.SupportsPerpetualStorageWiggleLocality)
+}
+
+// SupportsTagQuotas returns true if the version of FDB supports throughput quotas for transaction tags.
+func (version Version) SupportsTagQuotas() bool {
+	return version.IsAtLeast(Versions.SupportsTagQuotas)
+}
+
+// SupportsAuthorization returns true if the version of FDB supports the token based authorization.
+func (version Version) SupportsAuthorization() bool {
+	return version.IsAtLeast(Versions.SupportsAuthorization)
+}
+
+// SupportsDNSInClusterFile returns true if the version of FDB supports DNS names in the cluster file.
+func (version Version) SupportsDNSInClusterFile() bool {
+	return version.IsAtLeast(Versions.SupportsDNSInClusterFile)
+}
+
+// SupportsRestorePrefixRemapping returns true if the version of FDB supports adding and removing key prefixes in a
+// restore.
+func (version Version) SupportsRestorePrefixRemapping() bool {
+	return version.IsAtLeast(Versions.SupportsRestorePrefixRemapping)
+}
+
+// SupportsManagementAPIExclusions returns true if the version of FDB reports the excluded servers that still hold
+// data in the management module of the special key space.
+func (version Version) SupportsManagementAPIExclusions() bool {
+	return version.IsAtLeast(Versions.SupportsManagementAPIExclusions)
+}
+
+// SupportsProcessClass returns true if the version of FDB supports processes with the provided process class.
+func (version Version) SupportsProcessClass(processClass ProcessClass) bool {
+	if processClass == ProcessClassGrvProxy || processClass == ProcessClassCommitProxy {
+		return version.HasSeparatedProxies()
+	}
+
+	return true
+}
+
 // Versions provides a shorthand for known versions.
 // This is only to be used in testing.
 var Versions = struct {
@@ -222,17 +296,33 @@ var Versions = struct {
 	IncompatibleVersion,
 	PreviousPatchVersion,
 	SupportsRecoveryState,
+	SupportsTenants,
+	SupportsTagQuotas,
+	SupportsAuthorization,
+	SupportsDNSInClusterFile,
+	SupportsPerpetualStorageWiggle,
+	SupportsPerpetualStorageWiggleLocality,
+	SupportsRestorePrefixRemapping,
+	SupportsManagementAPIExclusions,
 	Default Version
 }{
-	Default:                      Version{Major: 6, Minor: 2, Patch: 21},
-	IncompatibleVersion:          Version{Major: 6, Minor: 1, Patch: 0},
-	PreviousPatchVersion:         Version{Major: 6, Minor: 2, Patch: 20},
-	NextPatchVersion:             Version{Major: 6, Minor: 2, Patch: 22},
-	NextMajorVersion:             Version{Major: 7, Minor: 0, Patch: 0},
-	MinimumVersion:               Version{Major: 6, Minor: 2, Patch: 20},
-	SupportsRocksDBV1:            Version{Major: 7, Minor: 1, Patch: 0, ReleaseCandidate: 4},
-	SupportsIsPresent:            Version{Major: 7, Minor: 1, Patch: 4},
-	SupportsShardedRocksDB:       Version{Major: 7, Minor: 2, Patch: 0},
-	SupportsRedwood1Experimental: Version{Major: 7, Minor: 0, Patch: 0},
-	SupportsRecoveryState:        Version{Major: 7, Minor: 1, Patch: 22},
+	Default:                                Version{Major: 6, Minor: 2, Patch: 21},
+	IncompatibleVersion:                    Version{Major: 6, Minor: 1, Patch: 0},
+	PreviousPatchVersion:                   Version{Major: 6, Minor: 2, Patch: 20},
+	NextPatchVersion:                       Version{Major: 6, Minor: 2, Patch: 22},
+	NextMajorVersion:                       Version{Major: 7, Minor: 0, Patch: 0},
+	MinimumVersion:                         Version{Major: 6, Minor: 2, Patch: 20},
+	SupportsRocksDBV1:                      Version{Major: 7, Minor: 1, Patch: 0, ReleaseCandidate: 4},
+	SupportsIsPresent:                      Version{Major: 7, Minor: 1, Patch: 4},
+	SupportsShardedRocksDB:                 Version{Major: 7, Minor: 2, Patch: 0},
+	SupportsRedwood1Experimental:           Version{Major: 7, Minor: 0, Patch: 0},
+	SupportsRecoveryState:                  Version{Major: 7, Minor: 1, Patch: 22},
+	SupportsTenants:                        Version{Major: 7, Minor: 1, Patch: 0},
+	SupportsTagQuotas:                      Version{Major: 7, Minor: 3, Patch: 0},
+	SupportsAuthorization:                  Version{Major: 7, Minor: 2, Patch: 0},
+	SupportsDNSInClusterFile:               Version{Major: 7, Minor: 1, Patch: 0},
+	SupportsPerpetualStorageWiggle:         Version{Major: 7, Minor: 0, Patch: 0},
+	SupportsPerpetualStorageWiggleLocality: Version{Major: 7, Minor: 1, Patch: 0},
+	SupportsRestorePrefixRemapping:         Version{Major: 6, Minor: 3, Patch: 0},
+	SupportsManagementAPIExclusions:        Version{Major: 7, Minor: 0, Patch: 0},
 }
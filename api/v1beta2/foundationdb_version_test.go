@@ -21,6 +21,8 @@
 package v1beta2
 
 import (
+	"testing"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -78,6 +80,21 @@ var _ = Describe("[api] FDBVersion", func() {
 			Expect(err.Error()).To(Equal("could not parse FDB version from 6.2"))
 		})
 
+		It("should return the same version when the version is parsed again", func() {
+			version, err := ParseFdbVersion("7.1.25-rc2")
+			Expect(err).NotTo(HaveOccurred())
+
+			cachedVersion, err := ParseFdbVersion("7.1.25-rc2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cachedVersion).To(Equal(version))
+			Expect(cachedVersion).To(Equal(Version{Major: 7, Minor: 1, Patch: 25, ReleaseCandidate: 2}))
+
+			_, err = ParseFdbVersion("7.1")
+			Expect(err).To(HaveOccurred())
+			_, err = ParseFdbVersion("7.1")
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should format the version correctly", func() {
 			version := Version{Major: 6, Minor: 2, Patch: 11}
 			Expect(version.String()).To(Equal("6.2.11"))
@@ -156,4 +173,48 @@ var _ = Describe("[api] FDBVersion", func() {
 		)
 
 	})
+
+	When("checking if the version supports a process class", func() {
+		DescribeTable("should return if the process class is supported",
+			func(version Version, processClass ProcessClass, expected bool) {
+				Expect(version.SupportsProcessClass(processClass)).To(Equal(expected))
+			},
+			Entry("storage on 6.2", Version{Major: 6, Minor: 2, Patch: 20}, ProcessClassStorage, true),
+			Entry("proxy on 6.2", Version{Major: 6, Minor: 2, Patch: 20}, ProcessClassProxy, true),
+			Entry("grv proxy on 6.2", Version{Major: 6, Minor: 2, Patch: 20}, ProcessClassGrvProxy, false),
+			Entry("commit proxy on 6.2", Version{Major: 6, Minor: 2, Patch: 20}, ProcessClassCommitProxy, false),
+			Entry("grv proxy on 7.1", Version{Major: 7, Minor: 1, Patch: 0}, ProcessClassGrvProxy, true),
+			Entry("commit proxy on 7.1", Version{Major: 7, Minor: 1, Patch: 0}, ProcessClassCommitProxy, true),
+		)
+	})
+
+	When("checking if the version supports DNS names in the cluster file", func() {
+		It("should only be supported for 7.1 and newer", func() {
+			Expect(Version{Major: 6, Minor: 3, Patch: 25}.SupportsDNSInClusterFile()).To(BeFalse())
+			Expect(Version{Major: 7, Minor: 0, Patch: 0}.SupportsDNSInClusterFile()).To(BeFalse())
+			Expect(Version{Major: 7, Minor: 1, Patch: 0}.SupportsDNSInClusterFile()).To(BeTrue())
+		})
+	})
+
+	When("checking if the version supports exclusions in the management API", func() {
+		It("should only be supported for 7.0 and newer", func() {
+			Expect(Version{Major: 6, Minor: 3, Patch: 25}.SupportsManagementAPIExclusions()).To(BeFalse())
+			Expect(Version{Major: 7, Minor: 0, Patch: 0}.SupportsManagementAPIExclusions()).To(BeTrue())
+			Expect(Version{Major: 7, Minor: 1, Patch: 0}.SupportsManagementAPIExclusions()).To(BeTrue())
+		})
+	})
 })
+
+func BenchmarkParseFdbVersion(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseFdbVersion("7.1.25")
+	}
+}
+
+func BenchmarkParseFdbVersionUncached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = parseFdbVersion("7.1.25")
+	}
+}
@@ -18,7 +18,9 @@ package v1beta2
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +32,11 @@ import (
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".metadata.generation",description="Latest generation of the spec",priority=0
 // +kubebuilder:printcolumn:name="Reconciled",type="integer",JSONPath=".status.generations.reconciled",description="Last reconciled generation of the spec",priority=0
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Name of the backed up cluster",priority=0
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="Version of the backup agents",priority=1
+// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.backupDetails.running",description="Backup running",priority=0
+// +kubebuilder:printcolumn:name="Paused",type="boolean",JSONPath=".status.backupDetails.paused",description="Backup paused",priority=1
+// +kubebuilder:printcolumn:name="Restorable",type="boolean",JSONPath=".status.backupDetails.restorable",description="Backup restorable",priority=0
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:storageversion
 
@@ -101,6 +108,64 @@ type FoundationDBBackupSpec struct {
 	// SidecarContainer defines customization for the
 	// foundationdb-kubernetes-sidecar container.
 	SidecarContainer ContainerOverrides `json:"sidecarContainer,omitempty"`
+
+	// BackupMode defines how the backup is taken. The Continuous mode uses
+	// the backup agents to write a continuous backup into the blobstore, the
+	// Snapshot mode uses the backup agents to write a single snapshot without
+	// the continuous mutation log and the VolumeSnapshot mode takes
+	// VolumeSnapshots of the data volumes of the cluster.
+	// The default is Continuous.
+	// +kubebuilder:validation:Enum=Continuous;Snapshot;VolumeSnapshot
+	BackupMode BackupMode `json:"backupMode,omitempty"`
+
+	// VolumeSnapshotConfiguration defines the configuration for backups that
+	// use the VolumeSnapshot mode.
+	VolumeSnapshotConfiguration *VolumeSnapshotConfiguration `json:"volumeSnapshotConfiguration,omitempty"`
+
+	// PriorityClassName defines the name of the PriorityClass for the backup
+	// agent Pods. If set, this takes precedence over the priorityClassName in
+	// the Pod template.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// BackupMode defines how the backup of a cluster is taken.
+type BackupMode string
+
+const (
+	// BackupModeContinuous uses the backup agents to write a continuous backup
+	// into the blobstore.
+	BackupModeContinuous BackupMode = "Continuous"
+	// BackupModeSnapshot uses the backup agents to write a single snapshot
+	// into the blobstore. The backup stops once it is restorable.
+	BackupModeSnapshot BackupMode = "Snapshot"
+	// BackupModeVolumeSnapshot takes VolumeSnapshots of the data volumes of
+	// the cluster.
+	BackupModeVolumeSnapshot BackupMode = "VolumeSnapshot"
+)
+
+// VolumeSnapshotConfiguration describes how the VolumeSnapshots for a backup
+// are taken.
+type VolumeSnapshotConfiguration struct {
+	// VolumeSnapshotClassName defines the VolumeSnapshotClass that is used
+	// for the VolumeSnapshots. If empty the default VolumeSnapshotClass will
+	// be used.
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// LockDatabase defines if the database should be locked while the
+	// VolumeSnapshots are taken. If the database is not locked, the snapshots
+	// are only consistent if the storage system takes all snapshots at the
+	// same point in time and the operator only records the version before the
+	// snapshots were taken.
+	// The default is true.
+	LockDatabase *bool `json:"lockDatabase,omitempty"`
+
+	// LockTimeoutSeconds defines how long the database can be locked while
+	// the VolumeSnapshots are taken. If the VolumeSnapshots were not taken
+	// within this timeout, the operator will unlock the database and mark
+	// the backup as failed.
+	// The default is 300 (5 minutes).
+	// +kubebuilder:validation:Minimum=1
+	LockTimeoutSeconds *int `json:"lockTimeoutSeconds,omitempty"`
 }
 
 // FoundationDBBackupStatus describes the current status of the backup for a cluster.
@@ -120,6 +185,66 @@ type FoundationDBBackupStatus struct {
 	// Generations provides information about the latest generation to be
 	// reconciled, or to reach other stages in reconciliation.
 	Generations BackupGenerationStatus `json:"generations,omitempty"`
+
+	// VolumeSnapshotBackup provides information about the backup for backups
+	// that use the VolumeSnapshot mode.
+	VolumeSnapshotBackup *VolumeSnapshotBackupStatus `json:"volumeSnapshotBackup,omitempty"`
+
+	// Conditions provides the results of the checks the operator performs
+	// before it starts the backup, e.g. if the blobstore is reachable.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// BlobStoreReadyCondition indicates whether the credentials for the
+	// blobstore are valid and the blobstore is reachable.
+	BlobStoreReadyCondition = "BlobStoreReady"
+	// BlobStoreValidatedReason is the reason of the BlobStoreReady condition
+	// if all checks passed.
+	BlobStoreValidatedReason = "Validated"
+	// BlobStoreCredentialsInvalidReason is the reason of the BlobStoreReady
+	// condition if the credentials file is not valid.
+	BlobStoreCredentialsInvalidReason = "CredentialsInvalid"
+	// BlobStoreUnreachableReason is the reason of the BlobStoreReady
+	// condition if the blobstore is not reachable.
+	BlobStoreUnreachableReason = "BlobStoreUnreachable"
+)
+
+// VolumeSnapshotBackupStatus describes the set of VolumeSnapshots that make up
+// a backup.
+type VolumeSnapshotBackupStatus struct {
+	// Version provides the version of the database at which the backup can be
+	// restored.
+	Version int64 `json:"version,omitempty"`
+
+	// VolumeSnapshots provides the names of the VolumeSnapshots, keyed by the
+	// process group they were taken from. This map can be used as the
+	// volumeSnapshots of a cluster that is cloned from the backup.
+	VolumeSnapshots map[ProcessGroupID]string `json:"volumeSnapshots,omitempty"`
+
+	// LockUID provides the UID of the lock that the operator holds on the
+	// database while the VolumeSnapshots are taken.
+	LockUID string `json:"lockUID,omitempty"`
+
+	// LockDeadline provides the time when the operator will unlock the
+	// database and fail the backup if the VolumeSnapshots were not taken.
+	LockDeadline *metav1.Time `json:"lockDeadline,omitempty"`
+
+	// StartTime provides the time when the backup was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime provides the time when all VolumeSnapshots were ready to
+	// use.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// FailureTime provides the time when the backup failed. A failed backup
+	// will not be retried.
+	FailureTime *metav1.Time `json:"failureTime,omitempty"`
+
+	// FailureMessage provides the reason why the backup failed.
+	FailureMessage string `json:"failureMessage,omitempty"`
 }
 
 // FoundationDBBackupStatusBackupDetails provides information about the state
@@ -129,6 +254,25 @@ type FoundationDBBackupStatusBackupDetails struct {
 	Running               bool   `json:"running,omitempty"`
 	Paused                bool   `json:"paused,omitempty"`
 	SnapshotPeriodSeconds int    `json:"snapshotTime,omitempty"`
+
+	// Restorable indicates whether the backup contains enough data to be
+	// restored.
+	Restorable bool `json:"restorable,omitempty"`
+
+	// LastRestorableVersion provides the latest version of the database that
+	// can be restored from the backup.
+	LastRestorableVersion *int64 `json:"lastRestorableVersion,omitempty"`
+
+	// SecondsBehind provides the number of seconds that the latest restorable
+	// version lags behind the current version of the database.
+	SecondsBehind *int64 `json:"secondsBehind,omitempty"`
+
+	// StopAfterSnapshot indicates whether the backup stops once a snapshot
+	// is restorable instead of writing a continuous mutation log.
+	StopAfterSnapshot bool `json:"stopAfterSnapshot,omitempty"`
+
+	// Completed indicates whether the backup has completed.
+	Completed bool `json:"completed,omitempty"`
 }
 
 // BackupGenerationStatus stores information on which generations have reached
@@ -157,6 +301,11 @@ type BackupGenerationStatus struct {
 	// NeedsBackupReconfiguration provides the last generation that could not
 	// complete reconciliation because we need to modify backup parameters.
 	NeedsBackupReconfiguration int64 `json:"needsBackupModification,omitempty"`
+
+	// NeedsVolumeSnapshotBackup provides the last generation that could not
+	// complete reconciliation because the VolumeSnapshots for the backup are
+	// not ready.
+	NeedsVolumeSnapshotBackup int64 `json:"needsVolumeSnapshotBackup,omitempty"`
 }
 
 // BackupState defines the desired state of a backup
@@ -179,6 +328,9 @@ type URLParameter string
 type BlobStoreConfiguration struct {
 	// The name for the backup.
 	// If empty defaults to .metadata.name.
+	// For backups the variables $(CLUSTER_NAME), $(NAMESPACE) and $(DATE)
+	// will be replaced with the name of the cluster, the namespace and the
+	// creation date of the backup.
 	// +kubebuilder:validation:MaxLength=1024
 	BackupName string `json:"backupName,omitempty"`
 
@@ -189,6 +341,7 @@ type BlobStoreConfiguration struct {
 
 	// The backup bucket to write to.
 	// The default is "fdb-backups".
+	// For backups the same variables as for the backupName can be used.
 	// +kubebuilder:validation:MinLength=3
 	// +kubebuilder:validation:MaxLength=63
 	Bucket string `json:"bucket,omitempty"`
@@ -196,8 +349,117 @@ type BlobStoreConfiguration struct {
 	// Additional URL parameters passed to the blobstore URL.
 	// +kubebuilder:validation:MaxItems=100
 	URLParameters []URLParameter `json:"urlParameters,omitempty"`
+
+	// SecureConnection defines if the connection to the blobstore uses TLS.
+	// This will be passed as the secure_connection URL parameter.
+	// The default is true.
+	SecureConnection *bool `json:"secureConnection,omitempty"`
+
+	// RequestsPerSecond limits the number of requests per second to the
+	// blobstore. This will be passed as the requests_per_second URL
+	// parameter.
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond *int `json:"requestsPerSecond,omitempty"`
+
+	// ConcurrentRequests limits the number of concurrent requests to the
+	// blobstore. This will be passed as the concurrent_requests URL
+	// parameter.
+	// +kubebuilder:validation:Minimum=1
+	ConcurrentRequests *int `json:"concurrentRequests,omitempty"`
+
+	// MaxSendBytesPerSecond limits the upload rate to the blobstore. This
+	// will be passed as the max_send_bytes_per_second URL parameter.
+	// +kubebuilder:validation:Minimum=1
+	MaxSendBytesPerSecond *int64 `json:"maxSendBytesPerSecond,omitempty"`
+
+	// MaxRecvBytesPerSecond limits the download rate from the blobstore. This
+	// will be passed as the max_recv_bytes_per_second URL parameter.
+	// +kubebuilder:validation:Minimum=1
+	MaxRecvBytesPerSecond *int64 `json:"maxRecvBytesPerSecond,omitempty"`
+
+	// WorkloadIdentity defines the workload identity that is used to
+	// authenticate against the blobstore instead of static credentials.
+	WorkloadIdentity *WorkloadIdentityConfiguration `json:"workloadIdentity,omitempty"`
+}
+
+// WorkloadIdentityProvider defines the cloud provider whose workload identity
+// is used to authenticate against the blobstore.
+// +kubebuilder:validation:Enum=AWS;GCP;Azure
+type WorkloadIdentityProvider string
+
+const (
+	// WorkloadIdentityProviderAWS uses IAM roles for service accounts.
+	WorkloadIdentityProviderAWS WorkloadIdentityProvider = "AWS"
+	// WorkloadIdentityProviderGCP uses the GKE workload identity.
+	WorkloadIdentityProviderGCP WorkloadIdentityProvider = "GCP"
+	// WorkloadIdentityProviderAzure uses the Azure workload identity.
+	WorkloadIdentityProviderAzure WorkloadIdentityProvider = "Azure"
+)
+
+// WorkloadIdentityConfiguration describes how the backup agents
+// authenticate against the blobstore with the identity of their service
+// account.
+type WorkloadIdentityConfiguration struct {
+	// Provider defines the cloud provider of the workload identity.
+	Provider WorkloadIdentityProvider `json:"provider"`
+
+	// ServiceAccountName defines an existing service account that is used
+	// by the backup agents. If empty, the operator creates a service account
+	// with the name of the backup agent deployment and annotates it with the
+	// role.
+	// +kubebuilder:validation:MaxLength=253
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Role defines the identity the service account is bound to. This is the
+	// ARN of the IAM role for AWS, the email of the Google service account for
+	// GCP and the client ID of the managed identity for Azure.
+	// +kubebuilder:validation:MaxLength=2048
+	Role string `json:"role,omitempty"`
+
+	// TenantID defines the ID of the Azure tenant of the managed identity.
+	// This is only used for Azure.
+	// +kubebuilder:validation:MaxLength=256
+	TenantID string `json:"tenantID,omitempty"`
+
+	// Audience defines the audience of the projected service account token.
+	// The default is "sts.amazonaws.com" for AWS and
+	// "api://AzureADTokenExchange" for Azure. GCP uses the metadata server
+	// and doesn't need a projected token.
+	// +kubebuilder:validation:MaxLength=1024
+	Audience string `json:"audience,omitempty"`
+
+	// TokenExpirationSeconds defines the requested lifetime of the projected
+	// service account token.
+	// The default is 3600.
+	// +kubebuilder:validation:Minimum=600
+	TokenExpirationSeconds *int64 `json:"tokenExpirationSeconds,omitempty"`
 }
 
+const (
+	// BackupURLClusterNameVariable will be replaced with the name of the
+	// cluster in the backup name and the bucket of a backup.
+	BackupURLClusterNameVariable = "$(CLUSTER_NAME)"
+
+	// BackupURLNamespaceVariable will be replaced with the namespace of the
+	// backup in the backup name and the bucket of a backup.
+	BackupURLNamespaceVariable = "$(NAMESPACE)"
+
+	// BackupURLDateVariable will be replaced with the creation date of the
+	// backup in the format YYYY-MM-DD in the backup name and the bucket of a
+	// backup.
+	BackupURLDateVariable = "$(DATE)"
+)
+
+const (
+	// WorkloadIdentityTokenVolumeName is the name of the volume with the
+	// projected service account token.
+	WorkloadIdentityTokenVolumeName = "workload-identity-token"
+
+	// WorkloadIdentityTokenPath is the path where the projected service
+	// account token is mounted.
+	WorkloadIdentityTokenPath = "/var/run/secrets/foundationdb.org/workload-identity"
+)
+
 // ShouldRun determines whether a backup should be running.
 func (backup *FoundationDBBackup) ShouldRun() bool {
 	return backup.Spec.BackupState == "" || backup.Spec.BackupState == BackupStateRunning || backup.Spec.BackupState == BackupStatePaused
@@ -208,24 +470,80 @@ func (backup *FoundationDBBackup) ShouldBePaused() bool {
 	return backup.Spec.BackupState == BackupStatePaused
 }
 
+// UsesVolumeSnapshots determines whether the backup is taken with
+// VolumeSnapshots instead of the backup agents.
+func (backup *FoundationDBBackup) UsesVolumeSnapshots() bool {
+	return backup.Spec.BackupMode == BackupModeVolumeSnapshot
+}
+
+// ShouldStopAfterSnapshot determines whether the backup agents should only
+// write a single snapshot without the continuous mutation log.
+func (backup *FoundationDBBackup) ShouldStopAfterSnapshot() bool {
+	return backup.Spec.BackupMode == BackupModeSnapshot
+}
+
+// IsSnapshotCompleted determines whether the single snapshot of a backup in the
+// Snapshot mode has completed, in which case the backup should not be started
+// again.
+func (backup *FoundationDBBackup) IsSnapshotCompleted() bool {
+	if !backup.ShouldStopAfterSnapshot() || backup.Status.BackupDetails == nil {
+		return false
+	}
+
+	return backup.Status.BackupDetails.StopAfterSnapshot && backup.Status.BackupDetails.Completed
+}
+
+// ShouldLockDatabaseForVolumeSnapshots determines whether the database should
+// be locked while the VolumeSnapshots are taken.
+func (backup *FoundationDBBackup) ShouldLockDatabaseForVolumeSnapshots() bool {
+	if backup.Spec.VolumeSnapshotConfiguration == nil {
+		return true
+	}
+
+	return pointer.BoolDeref(backup.Spec.VolumeSnapshotConfiguration.LockDatabase, true)
+}
+
+// GetVolumeSnapshotLockTimeout returns the duration the database can be
+// locked while the VolumeSnapshots are taken.
+func (backup *FoundationDBBackup) GetVolumeSnapshotLockTimeout() time.Duration {
+	if backup.Spec.VolumeSnapshotConfiguration == nil {
+		return 5 * time.Minute
+	}
+
+	return time.Duration(pointer.IntDeref(backup.Spec.VolumeSnapshotConfiguration.LockTimeoutSeconds, 300)) * time.Second
+}
+
 // Bucket gets the bucket this backup will use.
-// This will fill in a default value if the bucket in the spec is empty.
+// This will fill in a default value if the bucket in the spec is empty and
+// replace the variables in the bucket.
 func (backup *FoundationDBBackup) Bucket() string {
 	if backup.Spec.BlobStoreConfiguration.Bucket == "" {
 		return "fdb-backups"
 	}
 
-	return backup.Spec.BlobStoreConfiguration.Bucket
+	return backup.expandBackupURLVariables(backup.Spec.BlobStoreConfiguration.Bucket)
 }
 
 // BackupName gets the name of the backup in the destination.
-// This will fill in a default value if the backup name in the spec is empty.
+// This will fill in a default value if the backup name in the spec is empty
+// and replace the variables in the backup name.
 func (backup *FoundationDBBackup) BackupName() string {
 	if backup.Spec.BlobStoreConfiguration.BackupName == "" {
 		return backup.ObjectMeta.Name
 	}
 
-	return backup.Spec.BlobStoreConfiguration.BackupName
+	return backup.expandBackupURLVariables(backup.Spec.BlobStoreConfiguration.BackupName)
+}
+
+// expandBackupURLVariables replaces the variables in the value with the
+// values of the backup. The date is taken from the creation timestamp, so
+// the URL of the backup doesn't change between reconciliations.
+func (backup *FoundationDBBackup) expandBackupURLVariables(value string) string {
+	return strings.NewReplacer(
+		BackupURLClusterNameVariable, backup.Spec.ClusterName,
+		BackupURLNamespaceVariable, backup.Namespace,
+		BackupURLDateVariable, backup.CreationTimestamp.UTC().Format("2006-01-02"),
+	).Replace(value)
 }
 
 // BackupURL gets the destination url of the backup.
@@ -252,6 +570,35 @@ type FoundationDBLiveBackupStatus struct {
 
 	// BackupAgentsPaused describes whether the backup agents are paused.
 	BackupAgentsPaused bool `json:"BackupAgentsPaused,omitempty"`
+
+	// Restorable describes whether the backup contains enough data to be
+	// restored.
+	Restorable bool `json:"Restorable,omitempty"`
+
+	// StopAfterSnapshot describes whether the backup stops once a snapshot is
+	// restorable.
+	StopAfterSnapshot bool `json:"StopAfterSnapshot,omitempty"`
+
+	// LatestRestorablePoint provides the latest point in time that can be
+	// restored from the backup.
+	LatestRestorablePoint *FoundationDBLiveBackupStatusRestorablePoint `json:"LatestRestorablePoint,omitempty"`
+}
+
+// FoundationDBLiveBackupStatusRestorablePoint describes a point in time that
+// can be restored from a backup.
+type FoundationDBLiveBackupStatusRestorablePoint struct {
+	// Version provides the version of the database at this point.
+	Version int64 `json:"Version,omitempty"`
+
+	// EpochSeconds provides the time of this point as unix timestamp.
+	EpochSeconds int64 `json:"EpochSeconds,omitempty"`
+
+	// Timestamp provides the formatted time of this point.
+	Timestamp string `json:"Timestamp,omitempty"`
+
+	// LagSeconds provides the number of seconds this point lags behind the
+	// current version of the database.
+	LagSeconds int64 `json:"LagSeconds,omitempty"`
 }
 
 // FoundationDBLiveBackupStatusState provides the state of a backup in the
@@ -259,11 +606,18 @@ type FoundationDBLiveBackupStatus struct {
 type FoundationDBLiveBackupStatusState struct {
 	// Running determines whether the backup is currently running.
 	Running bool `json:"Running,omitempty"`
+
+	// Completed determines whether the backup has completed.
+	Completed bool `json:"Completed,omitempty"`
 }
 
 // GetDesiredAgentCount determines how many backup agents we should run
-// for a cluster.
+// for a cluster. Backups that use VolumeSnapshots don't need any backup agents.
 func (backup *FoundationDBBackup) GetDesiredAgentCount() int {
+	if backup.UsesVolumeSnapshots() {
+		return 0
+	}
+
 	return pointer.IntDeref(backup.Spec.AgentCount, 2)
 }
 
@@ -278,10 +632,26 @@ func (backup *FoundationDBBackup) CheckReconciliation() (bool, error) {
 		reconciled = false
 	}
 
+	if backup.UsesVolumeSnapshots() {
+		snapshotBackup := backup.Status.VolumeSnapshotBackup
+		if backup.ShouldRun() && (snapshotBackup == nil || (snapshotBackup.CompletionTime == nil && snapshotBackup.FailureTime == nil)) {
+			backup.Status.Generations.NeedsVolumeSnapshotBackup = backup.ObjectMeta.Generation
+			reconciled = false
+		}
+
+		if reconciled {
+			backup.Status.Generations = BackupGenerationStatus{
+				Reconciled: backup.ObjectMeta.Generation,
+			}
+		}
+
+		return reconciled, nil
+	}
+
 	isRunning := backup.Status.BackupDetails != nil && backup.Status.BackupDetails.Running
 	isPaused := backup.Status.BackupDetails != nil && backup.Status.BackupDetails.Paused
 
-	if backup.ShouldRun() && !isRunning {
+	if backup.ShouldRun() && !isRunning && !backup.IsSnapshotCompleted() {
 		backup.Status.Generations.NeedsBackupStart = backup.ObjectMeta.Generation
 		reconciled = false
 	}
@@ -315,6 +685,83 @@ func (foundationDBBackupSpec *FoundationDBBackupSpec) GetAllowTagOverride() bool
 	return pointer.BoolDeref(foundationDBBackupSpec.AllowTagOverride, false)
 }
 
+// GetAudience returns the audience of the projected service account token.
+// This will fill in a default value for the provider if the audience in the
+// spec is empty. For GCP no token is projected so the audience is empty.
+func (identity *WorkloadIdentityConfiguration) GetAudience() string {
+	if identity.Audience != "" {
+		return identity.Audience
+	}
+
+	switch identity.Provider {
+	case WorkloadIdentityProviderAWS:
+		return "sts.amazonaws.com"
+	case WorkloadIdentityProviderAzure:
+		return "api://AzureADTokenExchange"
+	}
+
+	return ""
+}
+
+// GetTokenExpirationSeconds returns the requested lifetime of the projected
+// service account token.
+func (identity *WorkloadIdentityConfiguration) GetTokenExpirationSeconds() int64 {
+	return pointer.Int64Deref(identity.TokenExpirationSeconds, 3600)
+}
+
+// GetRoleAnnotations returns the annotations that bind a service account to
+// the role of the workload identity.
+func (identity *WorkloadIdentityConfiguration) GetRoleAnnotations() map[string]string {
+	switch identity.Provider {
+	case WorkloadIdentityProviderAWS:
+		return map[string]string{"eks.amazonaws.com/role-arn": identity.Role}
+	case WorkloadIdentityProviderGCP:
+		return map[string]string{"iam.gke.io/gcp-service-account": identity.Role}
+	case WorkloadIdentityProviderAzure:
+		return map[string]string{
+			"azure.workload.identity/client-id": identity.Role,
+			"azure.workload.identity/tenant-id": identity.TenantID,
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the workload identity contains all the fields that
+// are required for the provider.
+func (identity *WorkloadIdentityConfiguration) Validate() error {
+	switch identity.Provider {
+	case WorkloadIdentityProviderAWS, WorkloadIdentityProviderGCP:
+	case WorkloadIdentityProviderAzure:
+		if identity.TenantID == "" {
+			return fmt.Errorf("workload identity for Azure requires a tenantID")
+		}
+	default:
+		return fmt.Errorf("unknown workload identity provider %s", identity.Provider)
+	}
+
+	if identity.Role == "" && (identity.Provider != WorkloadIdentityProviderGCP || identity.ServiceAccountName == "") {
+		return fmt.Errorf("workload identity for %s requires a role", identity.Provider)
+	}
+
+	return nil
+}
+
+// GetBackupAgentServiceAccountName returns the name of the service account
+// that is used by the backup agents. This will be empty if no workload
+// identity is configured.
+func (backup *FoundationDBBackup) GetBackupAgentServiceAccountName() string {
+	if backup.Spec.BlobStoreConfiguration == nil || backup.Spec.BlobStoreConfiguration.WorkloadIdentity == nil {
+		return ""
+	}
+
+	if backup.Spec.BlobStoreConfiguration.WorkloadIdentity.ServiceAccountName != "" {
+		return backup.Spec.BlobStoreConfiguration.WorkloadIdentity.ServiceAccountName
+	}
+
+	return fmt.Sprintf("%s-backup-agents", backup.ObjectMeta.Name)
+}
+
 // getURL returns the blobstore URL for the specific configuration
 func (configuration *BlobStoreConfiguration) getURL(backup string, bucket string) string {
 	if configuration.AccountName == "" {
@@ -327,9 +774,96 @@ func (configuration *BlobStoreConfiguration) getURL(backup string, bucket string
 		sb.WriteString(string(param))
 	}
 
+	// Parameters that are defined in the URL parameters take precedence over
+	// the parameters of the configuration.
+	for _, param := range configuration.getGeneratedURLParameters() {
+		if configuration.hasURLParameter(param[0]) {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("&%s=%s", param[0], param[1]))
+	}
+
 	return fmt.Sprintf("blobstore://%s/%s?bucket=%s%s", configuration.AccountName, backup, bucket, sb.String())
 }
 
+// getGeneratedURLParameters returns the key and value of the URL parameters
+// that are defined by the fields of the configuration.
+func (configuration *BlobStoreConfiguration) getGeneratedURLParameters() [][2]string {
+	var params [][2]string
+	if configuration.SecureConnection != nil {
+		value := "0"
+		if *configuration.SecureConnection {
+			value = "1"
+		}
+		params = append(params, [2]string{"secure_connection", value})
+	}
+
+	if configuration.RequestsPerSecond != nil {
+		params = append(params, [2]string{"requests_per_second", strconv.Itoa(*configuration.RequestsPerSecond)})
+	}
+
+	if configuration.ConcurrentRequests != nil {
+		params = append(params, [2]string{"concurrent_requests", strconv.Itoa(*configuration.ConcurrentRequests)})
+	}
+
+	if configuration.MaxSendBytesPerSecond != nil {
+		params = append(params, [2]string{"max_send_bytes_per_second", strconv.FormatInt(*configuration.MaxSendBytesPerSecond, 10)})
+	}
+
+	if configuration.MaxRecvBytesPerSecond != nil {
+		params = append(params, [2]string{"max_recv_bytes_per_second", strconv.FormatInt(*configuration.MaxRecvBytesPerSecond, 10)})
+	}
+
+	// With IAM roles for service accounts the credentials must be resolved
+	// by the AWS SDK.
+	if configuration.WorkloadIdentity != nil && configuration.WorkloadIdentity.Provider == WorkloadIdentityProviderAWS {
+		params = append(params, [2]string{"sdk_auth", "1"})
+	}
+
+	return params
+}
+
+// urlParameterAliases contains the short names of the URL parameters that
+// are generated from the configuration.
+var urlParameterAliases = map[string]string{
+	"secure_connection":         "sc",
+	"requests_per_second":       "rps",
+	"concurrent_requests":       "cr",
+	"max_send_bytes_per_second": "sbps",
+	"max_recv_bytes_per_second": "rbps",
+}
+
+// hasURLParameter checks if the URL parameter is defined in the URL
+// parameters, either with its full or its short name.
+func (configuration *BlobStoreConfiguration) hasURLParameter(key string) bool {
+	return configuration.getURLParameter(key) != nil
+}
+
+// getURLParameter returns the value of the URL parameter if it is defined in
+// the URL parameters, either with its full or its short name.
+func (configuration *BlobStoreConfiguration) getURLParameter(key string) *string {
+	for _, param := range configuration.URLParameters {
+		paramKey, value, _ := strings.Cut(string(param), "=")
+		if paramKey == key || (urlParameterAliases[key] != "" && paramKey == urlParameterAliases[key]) {
+			return &value
+		}
+	}
+
+	return nil
+}
+
+// UsesSecureConnection returns true if the connection to the blobstore uses
+// TLS.
+func (configuration *BlobStoreConfiguration) UsesSecureConnection() bool {
+	value := configuration.getURLParameter("secure_connection")
+	if value != nil {
+		return *value != "0"
+	}
+
+	return pointer.BoolDeref(configuration.SecureConnection, true)
+}
+
 // BucketName gets the bucket this backup will use.
 // This will fill in a default value if the bucket in the spec is empty.
 func (configuration *BlobStoreConfiguration) BucketName() string {
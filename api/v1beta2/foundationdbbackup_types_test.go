@@ -21,9 +21,12 @@
 package v1beta2
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("[api] FoundationDBBackup", func() {
@@ -195,6 +198,119 @@ var _ = Describe("[api] FoundationDBBackup", func() {
 
 	})
 
+	When("checking reconciliation for a backup that uses VolumeSnapshots", func() {
+		BeforeEach(func() {
+			backup.ObjectMeta.Generation = 2
+			backup.Spec.BackupMode = BackupModeVolumeSnapshot
+			backup.Status = FoundationDBBackupStatus{
+				Generations: BackupGenerationStatus{
+					Reconciled: 1,
+				},
+				DeploymentConfigured: true,
+			}
+		})
+
+		It("should not need any backup agents", func() {
+			Expect(backup.GetDesiredAgentCount()).To(Equal(0))
+		})
+
+		It("should not be reconciled before the VolumeSnapshots are ready", func() {
+			backup.Status.VolumeSnapshotBackup = &VolumeSnapshotBackupStatus{
+				Version: 100,
+			}
+
+			result, err := backup.CheckReconciliation()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			Expect(backup.Status.Generations).To(Equal(BackupGenerationStatus{
+				Reconciled:                1,
+				NeedsVolumeSnapshotBackup: 2,
+			}))
+		})
+
+		It("should be reconciled once the VolumeSnapshots are ready", func() {
+			backup.Status.VolumeSnapshotBackup = &VolumeSnapshotBackupStatus{
+				Version:        100,
+				CompletionTime: &metav1.Time{},
+			}
+
+			result, err := backup.CheckReconciliation()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+			Expect(backup.Status.Generations).To(Equal(BackupGenerationStatus{
+				Reconciled: 2,
+			}))
+		})
+
+		It("should be reconciled without VolumeSnapshots if the backup is stopped", func() {
+			backup.Spec.BackupState = BackupStateStopped
+
+			result, err := backup.CheckReconciliation()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+
+		It("should lock the database by default", func() {
+			Expect(backup.ShouldLockDatabaseForVolumeSnapshots()).To(BeTrue())
+			backup.Spec.VolumeSnapshotConfiguration = &VolumeSnapshotConfiguration{
+				LockDatabase: pointer.Bool(false),
+			}
+			Expect(backup.ShouldLockDatabaseForVolumeSnapshots()).To(BeFalse())
+		})
+	})
+
+	When("checking reconciliation for a backup that uses the Snapshot mode", func() {
+		BeforeEach(func() {
+			backup.ObjectMeta.Generation = 2
+			backup.Spec.BackupMode = BackupModeSnapshot
+			backup.Status = FoundationDBBackupStatus{
+				AgentCount: 2,
+				Generations: BackupGenerationStatus{
+					Reconciled: 1,
+				},
+				DeploymentConfigured: true,
+				BackupDetails: &FoundationDBBackupStatusBackupDetails{
+					URL:                   "blobstore://test@test-service/sample-cluster?bucket=fdb-backups",
+					SnapshotPeriodSeconds: 864000,
+					StopAfterSnapshot:     true,
+				},
+			}
+		})
+
+		It("should stop after the snapshot", func() {
+			Expect(backup.ShouldStopAfterSnapshot()).To(BeTrue())
+		})
+
+		It("should not be reconciled before the backup is started", func() {
+			result, err := backup.CheckReconciliation()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			Expect(backup.Status.Generations).To(Equal(BackupGenerationStatus{
+				Reconciled:       1,
+				NeedsBackupStart: 2,
+			}))
+		})
+
+		It("should be reconciled once the snapshot has completed", func() {
+			backup.Status.BackupDetails.Completed = true
+
+			Expect(backup.IsSnapshotCompleted()).To(BeTrue())
+			result, err := backup.CheckReconciliation()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+			Expect(backup.Status.Generations).To(Equal(BackupGenerationStatus{
+				Reconciled: 2,
+			}))
+		})
+
+		It("should not treat a completed continuous backup as a completed snapshot", func() {
+			backup.Status.BackupDetails.Completed = true
+			backup.Status.BackupDetails.StopAfterSnapshot = false
+
+			Expect(backup.IsSnapshotCompleted()).To(BeFalse())
+		})
+	})
+
 	When("checking the backup state", func() {
 		It("should show the correct state", func() {
 			Expect(backup.ShouldRun()).To(BeTrue())
@@ -214,6 +330,38 @@ var _ = Describe("[api] FoundationDBBackup", func() {
 		})
 	})
 
+	When("validating the workload identity", func() {
+		DescribeTable("should validate the required fields",
+			func(identity WorkloadIdentityConfiguration, expectedError string) {
+				err := identity.Validate()
+				if expectedError == "" {
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+
+				Expect(err).To(MatchError(expectedError))
+			},
+			Entry("AWS with a role",
+				WorkloadIdentityConfiguration{Provider: WorkloadIdentityProviderAWS, Role: "arn"},
+				""),
+			Entry("AWS without a role",
+				WorkloadIdentityConfiguration{Provider: WorkloadIdentityProviderAWS, ServiceAccountName: "backup"},
+				"workload identity for AWS requires a role"),
+			Entry("GCP with an existing service account",
+				WorkloadIdentityConfiguration{Provider: WorkloadIdentityProviderGCP, ServiceAccountName: "backup"},
+				""),
+			Entry("GCP without a service account and role",
+				WorkloadIdentityConfiguration{Provider: WorkloadIdentityProviderGCP},
+				"workload identity for GCP requires a role"),
+			Entry("Azure without a tenant",
+				WorkloadIdentityConfiguration{Provider: WorkloadIdentityProviderAzure, Role: "client"},
+				"workload identity for Azure requires a tenantID"),
+			Entry("an unknown provider",
+				WorkloadIdentityConfiguration{Provider: "test", Role: "arn"},
+				"unknown workload identity provider test"),
+		)
+	})
+
 	When("getting the snapshot time", func() {
 		It("should return the snapshot time", func() {
 			Expect(backup.SnapshotPeriodSeconds()).To(Equal(864000))
@@ -229,6 +377,57 @@ var _ = Describe("[api] FoundationDBBackup", func() {
 			func(backup FoundationDBBackup, expected string) {
 				Expect(backup.BackupURL()).To(Equal(expected))
 			},
+			Entry("A Backup with a workload identity for AWS",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mybackup",
+					},
+					Spec: FoundationDBBackupSpec{
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							AccountName: "account@account",
+							WorkloadIdentity: &WorkloadIdentityConfiguration{
+								Provider: WorkloadIdentityProviderAWS,
+								Role:     "arn:aws:iam::123456789012:role/fdb-backup",
+							},
+						},
+					},
+				},
+				"blobstore://account@account/mybackup?bucket=fdb-backups&sdk_auth=1"),
+			Entry("A Backup with a workload identity for AWS and an explicit sdk_auth parameter",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mybackup",
+					},
+					Spec: FoundationDBBackupSpec{
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							AccountName: "account@account",
+							URLParameters: []URLParameter{
+								"sdk_auth=0",
+							},
+							WorkloadIdentity: &WorkloadIdentityConfiguration{
+								Provider: WorkloadIdentityProviderAWS,
+								Role:     "arn:aws:iam::123456789012:role/fdb-backup",
+							},
+						},
+					},
+				},
+				"blobstore://account@account/mybackup?bucket=fdb-backups&sdk_auth=0"),
+			Entry("A Backup with a workload identity for GCP",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mybackup",
+					},
+					Spec: FoundationDBBackupSpec{
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							AccountName: "account@account",
+							WorkloadIdentity: &WorkloadIdentityConfiguration{
+								Provider: WorkloadIdentityProviderGCP,
+								Role:     "fdb-backup@project.iam.gserviceaccount.com",
+							},
+						},
+					},
+				},
+				"blobstore://account@account/mybackup?bucket=fdb-backups"),
 			Entry("A Backup with a blobstore config with backup name",
 				FoundationDBBackup{
 					ObjectMeta: metav1.ObjectMeta{
@@ -301,6 +500,71 @@ var _ = Describe("[api] FoundationDBBackup", func() {
 					},
 				},
 				"blobstore://account@account/mybackup?bucket=fdb-backups&secure_connection=0"),
+			Entry("A Backup with a templated backup name and bucket",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "mybackup",
+						Namespace:         "prod",
+						CreationTimestamp: metav1.Date(2023, time.March, 22, 10, 0, 0, 0, time.UTC),
+					},
+					Spec: FoundationDBBackupSpec{
+						ClusterName: "sample-cluster",
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							AccountName: "account@account",
+							BackupName:  "$(CLUSTER_NAME)-$(DATE)",
+							Bucket:      "fdb-$(NAMESPACE)",
+						},
+					},
+				},
+				"blobstore://account@account/sample-cluster-2023-03-22?bucket=fdb-prod"),
+			Entry("A Backup with blobstore parameters",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mybackup",
+					},
+					Spec: FoundationDBBackupSpec{
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							AccountName:           "account@account",
+							SecureConnection:      pointer.Bool(false),
+							RequestsPerSecond:     pointer.Int(100),
+							ConcurrentRequests:    pointer.Int(20),
+							MaxSendBytesPerSecond: pointer.Int64(1000000),
+							MaxRecvBytesPerSecond: pointer.Int64(2000000),
+						},
+					},
+				},
+				"blobstore://account@account/mybackup?bucket=fdb-backups&secure_connection=0&requests_per_second=100&concurrent_requests=20&max_send_bytes_per_second=1000000&max_recv_bytes_per_second=2000000"),
+			Entry("A Backup with blobstore parameters that are also defined as URL parameters",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mybackup",
+					},
+					Spec: FoundationDBBackupSpec{
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							AccountName:       "account@account",
+							SecureConnection:  pointer.Bool(true),
+							RequestsPerSecond: pointer.Int(100),
+							URLParameters: []URLParameter{
+								"sc=0",
+								"requests_per_second=50",
+							},
+						},
+					},
+				},
+				"blobstore://account@account/mybackup?bucket=fdb-backups&sc=0&requests_per_second=50"),
+		)
+	})
+
+	When("checking if the blobstore uses a secure connection", func() {
+		DescribeTable("should return the expected result",
+			func(configuration BlobStoreConfiguration, expected bool) {
+				Expect(configuration.UsesSecureConnection()).To(Equal(expected))
+			},
+			Entry("no configuration", BlobStoreConfiguration{}, true),
+			Entry("secure connection disabled", BlobStoreConfiguration{SecureConnection: pointer.Bool(false)}, false),
+			Entry("secure connection disabled by the URL parameter", BlobStoreConfiguration{URLParameters: []URLParameter{"secure_connection=0"}}, false),
+			Entry("secure connection disabled by the short URL parameter", BlobStoreConfiguration{URLParameters: []URLParameter{"sc=0"}}, false),
+			Entry("URL parameter takes precedence", BlobStoreConfiguration{SecureConnection: pointer.Bool(false), URLParameters: []URLParameter{"sc=1"}}, true),
 		)
 	})
 })
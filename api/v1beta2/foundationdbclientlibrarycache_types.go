@@ -0,0 +1,206 @@
+/*
+Copyright 2020-2022 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdblibcache
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="Versions",type="string",JSONPath=".status.versions"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+
+// FoundationDBClientLibraryCache is the Schema for the foundationdbclientlibrarycaches API. A client library cache
+// provides the client libraries for all versions of the selected clusters in a volume, which can be mounted by
+// client applications and backup agents to use the multi-version client. This resource is only handled if the
+// operator runs with the client library caches enabled.
+type FoundationDBClientLibraryCache struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FoundationDBClientLibraryCacheSpec   `json:"spec,omitempty"`
+	Status FoundationDBClientLibraryCacheStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBClientLibraryCacheList contains a list of FoundationDBClientLibraryCache objects
+type FoundationDBClientLibraryCacheList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBClientLibraryCache `json:"items"`
+}
+
+// DefaultClientLibraryCacheHostPathRoot defines the directory on the nodes that contains the host paths of the client
+// library caches, if the operator doesn't configure a different directory.
+const DefaultClientLibraryCacheHostPathRoot = "/var/lib/fdb-client-libraries"
+
+// ClientLibraryCacheMode defines where the client libraries are cached.
+// +kubebuilder:validation:MaxLength=64
+type ClientLibraryCacheMode string
+
+const (
+	// ClientLibraryCacheModeNode caches the client libraries in a directory on
+	// every node, which is maintained by a DaemonSet.
+	ClientLibraryCacheModeNode ClientLibraryCacheMode = "Node"
+	// ClientLibraryCacheModeNamespace caches the client libraries in a single
+	// PersistentVolumeClaim, which is maintained by a Deployment.
+	ClientLibraryCacheModeNamespace ClientLibraryCacheMode = "Namespace"
+)
+
+// FoundationDBClientLibraryCacheSpec describes which client libraries are cached and where.
+type FoundationDBClientLibraryCacheSpec struct {
+	// Mode defines if the client libraries are cached on every node or in a
+	// volume for the namespace.
+	// Default: Node
+	// +kubebuilder:validation:Enum=Node;Namespace
+	Mode ClientLibraryCacheMode `json:"mode,omitempty"`
+
+	// ClusterSelector selects the clusters in the namespace of the cache whose
+	// versions should be cached. If not set, the versions of all clusters in
+	// the namespace are cached.
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// AdditionalVersions defines versions that should be cached in addition to
+	// the versions of the selected clusters.
+	// +kubebuilder:validation:MaxItems=20
+	AdditionalVersions []string `json:"additionalVersions,omitempty"`
+
+	// HostPath defines the directory on the nodes where the client libraries
+	// are stored in the Node mode. The directory must be inside the
+	// <hostPathRoot>/<namespace> directory, where the hostPathRoot is
+	// configured in the operator.
+	// Default: <hostPathRoot>/<namespace>/<name>
+	// +kubebuilder:validation:MaxLength=4096
+	HostPath string `json:"hostPath,omitempty"`
+
+	// VolumeClaimTemplate allows customizing the PersistentVolumeClaim in the
+	// Namespace mode. The volume must support the ReadWriteMany access mode
+	// to be mounted by multiple client applications.
+	VolumeClaimTemplate *corev1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+
+	// PodTemplate allows customizing the Pods that copy the client libraries.
+	PodTemplate *corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// ImageConfigs defines the sidecar images that provide the client
+	// libraries.
+	// +kubebuilder:validation:MaxItems=100
+	ImageConfigs []ImageConfig `json:"imageConfigs,omitempty"`
+}
+
+// FoundationDBClientLibraryCacheStatus describes the current status of a client library cache.
+type FoundationDBClientLibraryCacheStatus struct {
+	// Versions contains the versions whose client libraries are provided by
+	// the cache. For every minor version only the newest patch version is
+	// listed, since the client libraries are compatible across patch versions.
+	Versions []string `json:"versions,omitempty"`
+
+	// Ready defines if all Pods of the cache have copied the client libraries
+	// of the listed versions.
+	Ready bool `json:"ready,omitempty"`
+
+	// ObservedGeneration defines the generation of the cache that was last
+	// reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// GetMode returns the mode of the cache. Defaults to Node.
+func (cache *FoundationDBClientLibraryCache) GetMode() ClientLibraryCacheMode {
+	if cache.Spec.Mode == "" {
+		return ClientLibraryCacheModeNode
+	}
+
+	return cache.Spec.Mode
+}
+
+// GetHostPath returns the directory on the nodes where the client libraries are stored in the Node mode. The
+// hostPathRoot defaults to DefaultClientLibraryCacheHostPathRoot.
+func (cache *FoundationDBClientLibraryCache) GetHostPath(hostPathRoot string) string {
+	if cache.Spec.HostPath == "" {
+		if hostPathRoot == "" {
+			hostPathRoot = DefaultClientLibraryCacheHostPathRoot
+		}
+
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(hostPathRoot, "/"), cache.Namespace, cache.Name)
+	}
+
+	return cache.Spec.HostPath
+}
+
+// GetImageConfigs returns the image configs for the sidecar images that provide the client libraries.
+func (cache *FoundationDBClientLibraryCache) GetImageConfigs() []ImageConfig {
+	if len(cache.Spec.ImageConfigs) == 0 {
+		return []ImageConfig{{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"}}
+	}
+
+	return cache.Spec.ImageConfigs
+}
+
+// GetDesiredVersions returns the versions that should be cached for the provided clusters. This includes the running
+// and the desired version of every cluster, so the client libraries of the new version are available during an
+// upgrade. For every minor version only the newest patch version is returned, sorted by the version.
+func (cache *FoundationDBClientLibraryCache) GetDesiredVersions(clusters []FoundationDBCluster) ([]Version, error) {
+	versionStrings := make([]string, 0, 2*len(clusters)+len(cache.Spec.AdditionalVersions))
+	versionStrings = append(versionStrings, cache.Spec.AdditionalVersions...)
+	for _, cluster := range clusters {
+		versionStrings = append(versionStrings, cluster.Spec.Version, cluster.Status.RunningVersion)
+	}
+
+	minorVersions := make(map[string]Version, len(versionStrings))
+	for _, versionString := range versionStrings {
+		if versionString == "" {
+			continue
+		}
+
+		version, err := ParseFdbVersion(versionString)
+		if err != nil {
+			return nil, err
+		}
+
+		current, ok := minorVersions[version.Compact()]
+		if ok && current.IsAtLeast(version) {
+			continue
+		}
+
+		minorVersions[version.Compact()] = version
+	}
+
+	versions := make([]Version, 0, len(minorVersions))
+	for _, version := range minorVersions {
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return !versions[i].IsAtLeast(versions[j])
+	})
+
+	return versions, nil
+}
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBClientLibraryCache{}, &FoundationDBClientLibraryCacheList{})
+}
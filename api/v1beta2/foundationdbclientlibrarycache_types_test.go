@@ -0,0 +1,106 @@
+/*
+ * foundationdbclientlibrarycache_types_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("[api] FoundationDBClientLibraryCache", func() {
+	var cache *FoundationDBClientLibraryCache
+
+	BeforeEach(func() {
+		cache = &FoundationDBClientLibraryCache{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "libraries",
+				Namespace: "test",
+			},
+		}
+	})
+
+	When("getting the defaults", func() {
+		It("should return the defaults if nothing is set", func() {
+			Expect(cache.GetMode()).To(Equal(ClientLibraryCacheModeNode))
+			Expect(cache.GetHostPath("")).To(Equal("/var/lib/fdb-client-libraries/test/libraries"))
+			Expect(cache.GetHostPath("/mnt/libraries/")).To(Equal("/mnt/libraries/test/libraries"))
+			Expect(cache.GetImageConfigs()).To(Equal([]ImageConfig{{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"}}))
+		})
+
+		It("should return the configured values", func() {
+			cache.Spec.Mode = ClientLibraryCacheModeNamespace
+			cache.Spec.HostPath = "/opt/fdb"
+			cache.Spec.ImageConfigs = []ImageConfig{{BaseImage: "registry.example/sidecar"}}
+			Expect(cache.GetMode()).To(Equal(ClientLibraryCacheModeNamespace))
+			Expect(cache.GetHostPath("")).To(Equal("/opt/fdb"))
+			Expect(cache.GetImageConfigs()).To(Equal([]ImageConfig{{BaseImage: "registry.example/sidecar"}}))
+		})
+	})
+
+	When("getting the desired versions", func() {
+		var clusters []FoundationDBCluster
+
+		BeforeEach(func() {
+			clusters = []FoundationDBCluster{
+				{
+					Spec:   FoundationDBClusterSpec{Version: "7.1.26"},
+					Status: FoundationDBClusterStatus{RunningVersion: "6.3.24"},
+				},
+				{
+					Spec: FoundationDBClusterSpec{Version: "7.1.21"},
+				},
+			}
+		})
+
+		It("should return the newest patch version of every minor version sorted", func() {
+			versions, err := cache.GetDesiredVersions(clusters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]Version{
+				{Major: 6, Minor: 3, Patch: 24},
+				{Major: 7, Minor: 1, Patch: 26},
+			}))
+		})
+
+		It("should include the additional versions", func() {
+			cache.Spec.AdditionalVersions = []string{"7.2.0", "7.1.27"}
+			versions, err := cache.GetDesiredVersions(clusters)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]Version{
+				{Major: 6, Minor: 3, Patch: 24},
+				{Major: 7, Minor: 1, Patch: 27},
+				{Major: 7, Minor: 2, Patch: 0},
+			}))
+		})
+
+		It("should return an error for an invalid version", func() {
+			cache.Spec.AdditionalVersions = []string{"latest"}
+			_, err := cache.GetDesiredVersions(clusters)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return no versions without clusters", func() {
+			versions, err := cache.GetDesiredVersions(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+		})
+	})
+})
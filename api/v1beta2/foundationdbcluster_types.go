@@ -21,26 +21,36 @@ import (
 	"math"
 	"math/rand"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/pointer"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:shortName=fdb
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.processCounts.storage,statuspath=.status.storageProcessGroups,selectorpath=.status.storageSelector
 // +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".metadata.generation",description="Latest generation of the spec",priority=0
 // +kubebuilder:printcolumn:name="Reconciled",type="integer",JSONPath=".status.generations.reconciled",description="Last reconciled generation of the spec",priority=0
 // +kubebuilder:printcolumn:name="Available",type="boolean",JSONPath=".status.health.available",description="Database available",priority=0
 // +kubebuilder:printcolumn:name="FullReplication",type="boolean",JSONPath=".status.health.fullReplication",description="Database fully replicated",priority=0
+// +kubebuilder:printcolumn:name="Healthy",type="boolean",JSONPath=".status.health.healthy",description="Database healthy",priority=1
+// +kubebuilder:printcolumn:name="Redundancy",type="string",JSONPath=".status.databaseConfiguration.redundancy_mode",description="Redundancy mode of the database",priority=1
+// +kubebuilder:printcolumn:name="StorageEngine",type="string",JSONPath=".status.databaseConfiguration.storage_engine",description="Storage engine of the database",priority=1
 // +kubebuilder:printcolumn:name="ReconciledProcessGroups",type="integer",JSONPath=".status.reconciledProcessGroups",description="Number of reconciled process groups",priority=1
 // +kubebuilder:printcolumn:name="DesiredProcessGroups",type="integer",JSONPath=".status.desiredProcessGroups",description="Desired number of process groups",priority=1
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.runningVersion",description="Running version",priority=0
+// +kubebuilder:printcolumn:name="DesiredVersion",type="string",JSONPath=".spec.version",description="Desired version",priority=1
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the cluster",priority=0
+// +kubebuilder:printcolumn:name="Progress",type="integer",JSONPath=".status.reconciliationProgress",description="Percentage of the completed sub-reconcilers in the latest reconciliation",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:storageversion
 
@@ -86,12 +96,31 @@ type FoundationDBClusterSpec struct {
 	// infer the process counts based on the database configuration.
 	ProcessCounts ProcessCounts `json:"processCounts,omitempty"`
 
+	// DeriveProcessCounts defines if the storage, log and stateless process
+	// counts should always be derived from the database configuration. If this
+	// is enabled, the values for those process classes in ProcessCounts only
+	// act as a lower bound, so changes to the database configuration like the
+	// redundancy mode will adjust the process counts. A value of -1 will still
+	// disable the process class. Defaults to false.
+	DeriveProcessCounts *bool `json:"deriveProcessCounts,omitempty"`
+
 	// SeedConnectionString provides a connection string for the initial
 	// reconciliation.
 	//
-	// After the initial reconciliation, this will not be used.
+	// After the initial reconciliation, this will only be used if it is changed
+	// to a connection string with a different description or generation ID,
+	// e.g. after cloning a cluster from a restore. In this case the operator
+	// will rewrite the cluster file on all pods and restart all processes at
+	// the same time.
 	SeedConnectionString string `json:"seedConnectionString,omitempty"`
 
+	// ClusterDescription defines the desired description in the connection
+	// string. If this differs from the description of the current connection
+	// string, the operator will change the coordinators with the new
+	// description, which causes all processes to update their cluster files.
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_]+$`
+	ClusterDescription string `json:"clusterDescription,omitempty"`
+
 	// PartialConnectionString provides a way to specify part of the
 	// connection string (e.g. the database name and coordinator generation)
 	// without specifying the entire string. This does not allow for setting
@@ -120,6 +149,31 @@ type FoundationDBClusterSpec struct {
 	// +kubebuilder:validation:MaxItems=500
 	ProcessGroupsToRemoveWithoutExclusion []ProcessGroupID `json:"processGroupsToRemoveWithoutExclusion,omitempty"`
 
+	// FaultDomainsToDecommission defines the fault domains, e.g. a zone or a
+	// data center, that should be retired. The operator will exclude and remove
+	// all process groups that are running in those fault domains in batches.
+	// New Pods must not be scheduled into those fault domains, e.g. by cordoning
+	// the according nodes.
+	// +kubebuilder:validation:MinItems=0
+	// +kubebuilder:validation:MaxItems=100
+	FaultDomainsToDecommission []FaultDomainToDecommission `json:"faultDomainsToDecommission,omitempty"`
+
+	// PinnedProcessGroups defines process groups that must run on a specific
+	// node or in a specific zone, e.g. to keep the coordinators on dedicated
+	// nodes. The key is the process group ID. Pinned process groups are not
+	// replaced automatically, as the replacement would not be pinned.
+	// +kubebuilder:validation:MaxProperties=100
+	PinnedProcessGroups map[ProcessGroupID]ProcessGroupPin `json:"pinnedProcessGroups,omitempty"`
+
+	// ProcessGroupsToReassign defines process groups that should be assigned
+	// to a different process class. The key is the process group ID and the
+	// value is the new process class. The operator adds a process group with
+	// the new process class and removes the process group once it can be
+	// removed without losing capacity or data. The process counts must be
+	// updated accordingly.
+	// +kubebuilder:validation:MaxProperties=100
+	ProcessGroupsToReassign map[ProcessGroupID]ProcessClass `json:"processGroupsToReassign,omitempty"`
+
 	// ConfigMap allows customizing the config map the operator creates.
 	ConfigMap *corev1.ConfigMap `json:"configMap,omitempty"`
 
@@ -218,6 +272,527 @@ type FoundationDBClusterSpec struct {
 	// UseUnifiedImage determines if we should use the unified image rather than
 	// separate images for the main container and the sidecar container.
 	UseUnifiedImage *bool `json:"useUnifiedImage,omitempty"`
+
+	// PodLayout defines how the fdbserver processes are run inside the Pods.
+	// In the ProcessPerContainer layout every fdbserver process runs in its
+	// own container without a monitor process, which requires the unified
+	// image. The default is Monitor.
+	// +kubebuilder:validation:Enum=Monitor;ProcessPerContainer
+	PodLayout PodLayout `json:"podLayout,omitempty"`
+
+	// ManagedConnectionOnly defines if the operator should only manage the connection to an existing FoundationDB
+	// cluster that was not created by the operator, e.g. a cluster running on VMs. In this mode the operator will not
+	// create or manage any Pods, PVCs or Services and only performs monitoring, backup orchestration and the
+	// distribution of the client configuration. The SeedConnectionString must be set if this mode is enabled.
+	// +kubebuilder:default:=false
+	ManagedConnectionOnly *bool `json:"managedConnectionOnly,omitempty"`
+
+	// ExternalMigration defines the settings to migrate an existing FoundationDB cluster that was not created by the
+	// operator into operator-managed Pods. The operator-managed processes will join the existing cluster by using the
+	// SeedConnectionString, afterwards the external processes will be excluded and the coordinators will be moved to
+	// the operator-managed processes.
+	ExternalMigration *ExternalMigrationSpec `json:"externalMigration,omitempty"`
+
+	// Tenants defines the tenants that should be managed by the operator. Tenants that are not listed here will not
+	// be modified by the operator. This requires FoundationDB 7.1 or newer and a tenant mode that allows tenants.
+	// +kubebuilder:validation:MaxItems=1000
+	Tenants []TenantSpec `json:"tenants,omitempty"`
+
+	// TagQuotas defines the throughput quotas for transaction tags, e.g. to limit the throughput of a tenant. If a
+	// tag is removed from this list, the operator will clear its quota. Tags that were never listed here will not be
+	// modified by the operator. This requires FoundationDB 7.3 or newer.
+	// +kubebuilder:validation:MaxItems=1000
+	TagQuotas []TagQuota `json:"tagQuotas,omitempty"`
+
+	// DataDistribution defines the data distribution settings of the cluster.
+	DataDistribution *DataDistributionSpec `json:"dataDistribution,omitempty"`
+
+	// StorageAutoscaling defines the settings for scaling the storage processes based on their disk utilization.
+	StorageAutoscaling *StorageAutoscalingSpec `json:"storageAutoscaling,omitempty"`
+
+	// CloneFrom defines the VolumeSnapshots of another cluster that this cluster should be created from. This is
+	// only used while the cluster is created.
+	CloneFrom *CloneFromSpec `json:"cloneFrom,omitempty"`
+
+	// TraceLogs defines the settings for the trace logs of the fdbserver processes.
+	TraceLogs *TraceLogSpec `json:"traceLogs,omitempty"`
+
+	// CrashCollection defines the settings for collecting crash artifacts of the FoundationDB processes.
+	CrashCollection *CrashCollectionSpec `json:"crashCollection,omitempty"`
+
+	// Notifications defines the webhooks that the operator notifies about issues with this cluster.
+	Notifications *NotificationSpec `json:"notifications,omitempty"`
+
+	// Authorization defines the public keys that the fdbserver processes use to verify the tokens of clients for the
+	// token based authorization. This requires FoundationDB 7.2 or newer and TLS.
+	Authorization *AuthorizationSpec `json:"authorization,omitempty"`
+
+	// CertManager defines the certificates that the operator requests from
+	// cert-manager for the processes of the cluster. The operator mounts the
+	// issued certificates into the main and the sidecar container and restarts
+	// the processes when a certificate is renewed.
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
+
+	// CompatibilityMode defines the environment that the generated Pods must be compatible with. The mode openshift
+	// adjusts the generated Pods to work with the random UIDs that OpenShift assigns through its security context
+	// constraints, instead of the fixed UID that the images assume.
+	// The default is kubernetes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=kubernetes;openshift
+	CompatibilityMode CompatibilityMode `json:"compatibilityMode,omitempty"`
+
+	// OperatorConfigName defines the name of the FoundationDBOperatorConfig that provides the defaults for this
+	// cluster. The settings of the cluster spec take precedence over the defaults of the config. The config is
+	// only used if the operator runs with the operator configs enabled.
+	// The default is "default".
+	// +kubebuilder:validation:MaxLength=253
+	OperatorConfigName string `json:"operatorConfigName,omitempty"`
+}
+
+// CompatibilityMode defines the environment that the generated Pods must be compatible with.
+type CompatibilityMode string
+
+const (
+	// CompatibilityModeKubernetes generates Pods for a plain Kubernetes environment.
+	CompatibilityModeKubernetes CompatibilityMode = "kubernetes"
+	// CompatibilityModeOpenShift generates Pods that work with the random UIDs assigned by OpenShift.
+	CompatibilityModeOpenShift CompatibilityMode = "openshift"
+)
+
+// AuthorizationSpec defines the public keys for the token based authorization of a cluster.
+type AuthorizationSpec struct {
+	// PublicKeySecrets defines the secrets that contain the public keys as JSON Web Key Set. The keys of all
+	// secrets are merged into a single key set that is distributed to the fdbserver processes, so a key can be
+	// rotated by adding the secret with the new key before the secret with the old key is removed.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=10
+	PublicKeySecrets []corev1.SecretKeySelector `json:"publicKeySecrets"`
+}
+
+// CertManagerSpec defines the certificates that the operator requests from cert-manager.
+type CertManagerSpec struct {
+	// IssuerRef references the cert-manager issuer that issues the
+	// certificates.
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+
+	// Scope defines if all processes of the cluster share one certificate
+	// or if every process group gets its own certificate.
+	// The default is Cluster.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Cluster;ProcessGroup
+	Scope *CertificateScope `json:"scope,omitempty"`
+
+	// CommonName defines the common name of the certificates, this name can
+	// be used in the peer verification rules.
+	// The default is the name of the cluster.
+	// +kubebuilder:validation:MaxLength=64
+	CommonName string `json:"commonName,omitempty"`
+
+	// DNSNames defines additional DNS names of the certificates. If the
+	// cluster uses DNS names in the cluster file and the scope is
+	// ProcessGroup, the DNS name of the Pod is added.
+	// +kubebuilder:validation:MaxItems=100
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// Duration defines the requested lifetime of the certificates. If this
+	// is not set, the default of the issuer is used.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// RenewBefore defines how long before the expiry the certificates are
+	// renewed. If this is not set, the default of cert-manager is used.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerReference references a cert-manager issuer.
+type CertManagerIssuerReference struct {
+	// Name defines the name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// Kind defines the kind of the issuer, e.g. Issuer or ClusterIssuer.
+	// The default is Issuer.
+	// +kubebuilder:validation:MaxLength=64
+	Kind string `json:"kind,omitempty"`
+
+	// Group defines the API group of the issuer.
+	// The default is cert-manager.io.
+	// +kubebuilder:validation:MaxLength=253
+	Group string `json:"group,omitempty"`
+}
+
+// CertificateScope defines which processes share a certificate that is requested from cert-manager.
+type CertificateScope string
+
+const (
+	// CertificateScopeCluster uses a single certificate for all processes of the cluster.
+	CertificateScopeCluster CertificateScope = "Cluster"
+	// CertificateScopeProcessGroup uses a certificate for every process group, so a compromised key only affects a
+	// single Pod.
+	CertificateScopeProcessGroup CertificateScope = "ProcessGroup"
+)
+
+// NotificationSpec defines the webhooks that the operator notifies about issues with a cluster.
+type NotificationSpec struct {
+	// Webhooks defines the webhooks that will receive the notifications.
+	// +kubebuilder:validation:MaxItems=10
+	Webhooks []NotificationWebhook `json:"webhooks,omitempty"`
+
+	// ReconciliationBlockedThresholdSeconds defines how long the reconciliation must be blocked before the operator
+	// sends a notification.
+	// The default is 3600.
+	// +kubebuilder:validation:Minimum=0
+	ReconciliationBlockedThresholdSeconds *int `json:"reconciliationBlockedThresholdSeconds,omitempty"`
+}
+
+// NotificationWebhook defines a webhook that receives notifications from the operator.
+type NotificationWebhook struct {
+	// Name defines the name of the webhook, this name is used in the logs of the operator.
+	// +kubebuilder:validation:MaxLength=256
+	Name string `json:"name,omitempty"`
+
+	// Type defines the format of the payload that is sent to the webhook.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Generic;Slack;PagerDuty
+	// +kubebuilder:default:=Generic
+	Type NotificationWebhookType `json:"type,omitempty"`
+
+	// URL defines the URL of the webhook. For PagerDuty the URL defaults to the PagerDuty Events API.
+	// +kubebuilder:validation:MaxLength=4096
+	URL string `json:"url,omitempty"`
+
+	// SecretKeyRef references a key of a Secret in the namespace of the cluster. For Generic and Slack webhooks the
+	// Secret contains the URL of the webhook, which takes precedence over the URL field. For PagerDuty webhooks the
+	// Secret contains the routing key of the integration.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// MinimumSeverity defines the lowest severity of the notifications that are sent to this webhook.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Info;Warning;Critical
+	// +kubebuilder:default:=Warning
+	MinimumSeverity NotificationSeverity `json:"minimumSeverity,omitempty"`
+}
+
+// NotificationWebhookType defines the format of the payload that is sent to a webhook.
+// +kubebuilder:validation:MaxLength=256
+type NotificationWebhookType string
+
+const (
+	// NotificationWebhookTypeGeneric sends the notification as JSON object.
+	NotificationWebhookTypeGeneric NotificationWebhookType = "Generic"
+	// NotificationWebhookTypeSlack sends the notification as a Slack message.
+	NotificationWebhookTypeSlack NotificationWebhookType = "Slack"
+	// NotificationWebhookTypePagerDuty sends the notification as a PagerDuty event.
+	NotificationWebhookTypePagerDuty NotificationWebhookType = "PagerDuty"
+)
+
+// NotificationSeverity defines the severity of a notification.
+// +kubebuilder:validation:MaxLength=256
+type NotificationSeverity string
+
+const (
+	// NotificationSeverityInfo is used for notifications that don't require any action.
+	NotificationSeverityInfo NotificationSeverity = "Info"
+	// NotificationSeverityWarning is used for notifications that might require an action.
+	NotificationSeverityWarning NotificationSeverity = "Warning"
+	// NotificationSeverityCritical is used for notifications that require an immediate action.
+	NotificationSeverityCritical NotificationSeverity = "Critical"
+)
+
+// IsAtLeast returns true if the severity is at least as high as the other severity.
+func (severity NotificationSeverity) IsAtLeast(other NotificationSeverity) bool {
+	ranks := map[NotificationSeverity]int{
+		NotificationSeverityInfo:     0,
+		NotificationSeverityWarning:  1,
+		NotificationSeverityCritical: 2,
+	}
+
+	return ranks[severity] >= ranks[other]
+}
+
+// GetType returns the type of the webhook or Generic if unset.
+func (webhook NotificationWebhook) GetType() NotificationWebhookType {
+	if webhook.Type == "" {
+		return NotificationWebhookTypeGeneric
+	}
+
+	return webhook.Type
+}
+
+// GetMinimumSeverity returns the minimum severity of the webhook or Warning if unset.
+func (webhook NotificationWebhook) GetMinimumSeverity() NotificationSeverity {
+	if webhook.MinimumSeverity == "" {
+		return NotificationSeverityWarning
+	}
+
+	return webhook.MinimumSeverity
+}
+
+// NotificationStatus contains the state of the notifications that were sent for a cluster. The state is used to
+// send each notification only once.
+type NotificationStatus struct {
+	// ReconciliationBlocked is true if a notification was sent for the current blocked reconciliation.
+	ReconciliationBlocked bool `json:"reconciliationBlocked,omitempty"`
+
+	// FaultToleranceDegraded is true if a notification was sent that the data is not fully replicated.
+	FaultToleranceDegraded bool `json:"faultToleranceDegraded,omitempty"`
+}
+
+// CrashCollectionSpec defines the settings for collecting crash artifacts of the FoundationDB processes.
+type CrashCollectionSpec struct {
+	// VolumeClaimName defines the name of an existing PersistentVolumeClaim that will be mounted into the main
+	// container of all Pods. Every Pod gets its own subdirectory and the main container will use this directory as
+	// its working directory, so core files of the processes will be stored outside the Pod. The
+	// PersistentVolumeClaim must support the ReadWriteMany access mode.
+	// +kubebuilder:validation:MaxLength=253
+	VolumeClaimName string `json:"volumeClaimName,omitempty"`
+
+	// MaxCrashReports defines how many crash reports will be kept in the cluster status.
+	// The default is 10.
+	// +kubebuilder:validation:Minimum=0
+	MaxCrashReports *int `json:"maxCrashReports,omitempty"`
+
+	// RetentionSeconds defines how long crash reports will be kept in the cluster status.
+	// The default is 604800 (7 days).
+	// +kubebuilder:validation:Minimum=0
+	RetentionSeconds *int `json:"retentionSeconds,omitempty"`
+}
+
+// ClusterAction describes an action that the operator performed for a cluster.
+type ClusterAction struct {
+	// Timestamp defines when the action was performed.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Reconciler defines the sub-reconciler that performed the action.
+	Reconciler string `json:"reconciler,omitempty"`
+
+	// Action describes the action, e.g. the processes that were excluded.
+	// +kubebuilder:validation:MaxLength=1024
+	Action string `json:"action"`
+
+	// Reason describes why the operator performed the action.
+	// +kubebuilder:validation:MaxLength=1024
+	Reason string `json:"reason,omitempty"`
+
+	// Generation defines the generation of the cluster spec at the time of the action.
+	Generation int64 `json:"generation,omitempty"`
+}
+
+// CrashReport contains the metadata of a crash of a container of a Pod managed by the operator.
+type CrashReport struct {
+	// ProcessGroupID defines the process group of the crashed container.
+	ProcessGroupID ProcessGroupID `json:"processGroupID,omitempty"`
+
+	// PodName defines the name of the Pod of the crashed container.
+	PodName string `json:"podName,omitempty"`
+
+	// ContainerName defines the name of the crashed container.
+	ContainerName string `json:"containerName,omitempty"`
+
+	// ExitCode defines the exit code of the crashed container.
+	ExitCode int32 `json:"exitCode,omitempty"`
+
+	// Signal defines the signal that terminated the container, if any.
+	Signal int32 `json:"signal,omitempty"`
+
+	// Reason defines the reason for the termination reported by Kubernetes.
+	Reason string `json:"reason,omitempty"`
+
+	// Timestamp defines when the container terminated.
+	Timestamp metav1.Time `json:"timestamp,omitempty"`
+
+	// Location defines where the crash artifacts are stored, in the format <volume claim>:<directory>.
+	Location string `json:"location,omitempty"`
+}
+
+// TraceLogSpec defines the settings for the trace logs of the fdbserver processes.
+type TraceLogSpec struct {
+	// Format defines the format of the trace log files.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=xml;json
+	Format TraceLogFormat `json:"format,omitempty"`
+
+	// Directory defines the directory in the main container where the fdbserver processes write their trace logs.
+	// The trace log volume will be mounted at this directory. Defaults to /var/log/fdb-trace-logs.
+	// +kubebuilder:validation:Pattern:=^/.*
+	Directory string `json:"directory,omitempty"`
+
+	// MaxLogsSize defines the total size of all trace log files of a process, after which the oldest
+	// files will be deleted.
+	MaxLogsSize *resource.Quantity `json:"maxLogsSize,omitempty"`
+
+	// Forwarder defines a container that will be added to the pods to ship the trace logs, e.g. to a
+	// centralized logging system. The trace log volume will be mounted read-only at the trace log
+	// directory in this container.
+	Forwarder *corev1.Container `json:"forwarder,omitempty"`
+
+	// EventReceiverAddress defines the address of the metrics server of the operator, e.g.
+	// fdb-kubernetes-operator.default.svc:8080, when the trace event receiver is enabled. If set, the
+	// forwarder container gets the URL to send severe trace events to in the FDB_TRACE_EVENT_RECEIVER_URL
+	// environment variable and the token to authenticate the requests with in the
+	// FDB_TRACE_EVENT_RECEIVER_TOKEN environment variable.
+	EventReceiverAddress string `json:"eventReceiverAddress,omitempty"`
+}
+
+// CloneFromSpec defines the source of a cluster that is created from VolumeSnapshots of another cluster.
+type CloneFromSpec struct {
+	// ClusterName defines the name of the source cluster. The source cluster must be in the same namespace.
+	ClusterName string `json:"clusterName"`
+
+	// VolumeSnapshots maps the process group IDs of the source cluster to the names of the VolumeSnapshots of
+	// their data volumes. Every process group of the source cluster that is listed here will be recreated in the
+	// clone with the same process class and number.
+	VolumeSnapshots map[ProcessGroupID]string `json:"volumeSnapshots"`
+}
+
+// TraceLogFormat defines the format of the trace log files.
+// +kubebuilder:validation:MaxLength=10
+type TraceLogFormat string
+
+const (
+	// TraceLogFormatXML defines the XML format for trace log files.
+	TraceLogFormatXML TraceLogFormat = "xml"
+
+	// TraceLogFormatJSON defines the JSON format for trace log files.
+	TraceLogFormatJSON TraceLogFormat = "json"
+)
+
+// StorageAutoscalingSpec defines the settings for scaling the storage processes based on their disk utilization.
+type StorageAutoscalingSpec struct {
+	// Enabled defines if the operator should increase the number of storage processes when the disk utilization
+	// crosses the threshold. If this is disabled, the operator will only report the recommended number of storage
+	// processes. Defaults to false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// DiskUtilizationThreshold defines the average disk utilization of the storage processes in percent that
+	// will trigger a scale up. Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	DiskUtilizationThreshold *int `json:"diskUtilizationThreshold,omitempty"`
+
+	// MinStorageProcesses defines the minimum number of storage processes the autoscaler will recommend.
+	// +kubebuilder:validation:Minimum=0
+	MinStorageProcesses int `json:"minStorageProcesses,omitempty"`
+
+	// MaxStorageProcesses defines the maximum number of storage processes the autoscaler will recommend.
+	// +kubebuilder:validation:Minimum=1
+	MaxStorageProcesses int `json:"maxStorageProcesses"`
+}
+
+// StorageAutoscalingStatus contains the state of the storage autoscaling.
+type StorageAutoscalingStatus struct {
+	// DiskUtilization contains the average disk utilization of the storage processes in percent.
+	DiskUtilization int `json:"diskUtilization,omitempty"`
+
+	// RecommendedStorageProcesses contains the number of storage processes that is recommended based on the
+	// disk utilization.
+	RecommendedStorageProcesses int `json:"recommendedStorageProcesses,omitempty"`
+
+	// StorageProcesses contains the number of storage processes that was set by the autoscaler. This will only
+	// be used if the autoscaling is enabled and the value is greater than the storage process count from the spec.
+	StorageProcesses int `json:"storageProcesses,omitempty"`
+}
+
+// ProcessGroupPin defines the node or the zone that a process group is pinned to. Exactly one of NodeName and Zone
+// must be set.
+type ProcessGroupPin struct {
+	// NodeName defines the name of the node the process group must run on.
+	// +kubebuilder:validation:MaxLength=253
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Zone defines the value of the fault domain label of the nodes the
+	// process group must run on. The label is defined by the key of the fault
+	// domain.
+	// +kubebuilder:validation:MaxLength=63
+	Zone string `json:"zone,omitempty"`
+
+	// TolerateUnschedulable defines if the process group may run on a node
+	// that is cordoned, so dedicated nodes can be cordoned to prevent other
+	// Pods from being scheduled on them.
+	// Default is false.
+	TolerateUnschedulable bool `json:"tolerateUnschedulable,omitempty"`
+}
+
+// DataDistributionSpec defines the data distribution settings of the cluster.
+type DataDistributionSpec struct {
+	// Enabled defines if data distribution should be enabled. If this is not set, the operator will not change
+	// the data distribution mode of the cluster.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// TagQuota defines the throughput quota for a transaction tag.
+type TagQuota struct {
+	// Tag defines the transaction tag this quota applies to.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=16
+	Tag string `json:"tag"`
+
+	// TotalThroughput defines the maximum throughput in bytes per second for the tag. If TotalThroughput and
+	// ReservedThroughput are both 0, the quota for this tag will be cleared.
+	// +kubebuilder:validation:Minimum=0
+	TotalThroughput int64 `json:"totalThroughput,omitempty"`
+
+	// ReservedThroughput defines the throughput in bytes per second that is reserved for the tag. This must not
+	// be greater than TotalThroughput.
+	// +kubebuilder:validation:Minimum=0
+	ReservedThroughput int64 `json:"reservedThroughput,omitempty"`
+}
+
+// TenantSpec defines a tenant that should be managed by the operator.
+type TenantSpec struct {
+	// Name defines the name of the tenant.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9._-]+$`
+	Name string `json:"name"`
+
+	// Remove defines if the tenant should be removed from the cluster. A tenant can only be removed if it's empty.
+	Remove bool `json:"remove,omitempty"`
+}
+
+// TenantStatus provides information about a tenant in the cluster.
+type TenantStatus struct {
+	// Name defines the name of the tenant.
+	Name string `json:"name"`
+
+	// ID defines the ID that was assigned to the tenant.
+	ID int64 `json:"id,omitempty"`
+
+	// Prefix defines the printable representation of the key prefix that is used by the tenant.
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// ExternalMigrationSpec defines the settings for the migration of an existing FoundationDB cluster into
+// operator-managed Pods.
+type ExternalMigrationSpec struct {
+	// ExternalProcessAddresses defines the addresses of the processes of the existing cluster that should be replaced
+	// by the operator-managed processes. The addresses must be in the format "ip:port" with optional flags, e.g.
+	// "10.1.0.1:4500:tls".
+	// +kubebuilder:validation:MaxItems=1000
+	ExternalProcessAddresses []string `json:"externalProcessAddresses,omitempty"`
+}
+
+// PodLayout defines how the fdbserver processes are run inside the Pods.
+type PodLayout string
+
+const (
+	// PodLayoutMonitor runs all fdbserver processes of a Pod in the main container, managed by fdbmonitor or the
+	// Kubernetes monitor of the unified image.
+	PodLayoutMonitor PodLayout = "Monitor"
+
+	// PodLayoutProcessPerContainer runs every fdbserver process of a Pod in its own container, without a monitor
+	// process. Kubernetes restarts the container if the process exits.
+	PodLayoutProcessPerContainer PodLayout = "ProcessPerContainer"
+)
+
+// GetProcessContainerName returns the name of the container that runs the fdbserver process with the provided process
+// number in the ProcessPerContainer layout. The first process runs in the main container.
+func GetProcessContainerName(processNumber int) string {
+	if processNumber <= 1 {
+		return MainContainerName
+	}
+
+	return fmt.Sprintf("%s-%d", MainContainerName, processNumber)
 }
 
 // ImageType defines a single kind of images used in the cluster.
@@ -256,9 +831,25 @@ type FoundationDBClusterStatus struct {
 	// currently running.
 	RunningVersion string `json:"runningVersion,omitempty"`
 
+	// UnsupportedFeatures lists the features of the spec that are not
+	// supported by the running version and are ignored until the cluster
+	// is upgraded to a version that supports them.
+	UnsupportedFeatures []string `json:"unsupportedFeatures,omitempty"`
+
 	// ConnectionString defines the contents of the cluster file.
 	ConnectionString string `json:"connectionString,omitempty"`
 
+	// ExternalCoordinators defines the external addresses of the coordinators
+	// in the order of the coordinators in the connection string. This is only
+	// set if the external access is enabled. The addresses only allow to reach
+	// the coordinators, so they can't be used as a cluster file for clients.
+	ExternalCoordinators []string `json:"externalCoordinators,omitempty"`
+
+	// AppliedSeedConnectionString defines the seed connection string that was
+	// last applied to the cluster. This is used to detect changes of the seed
+	// connection string that require a rotation of the connection string.
+	AppliedSeedConnectionString string `json:"appliedSeedConnectionString,omitempty"`
+
 	// Configured defines whether we have configured the database yet.
 	Configured bool `json:"configured,omitempty"`
 
@@ -280,6 +871,14 @@ type FoundationDBClusterStatus struct {
 	// This information is used in multiple places to trigger the according action.
 	ProcessGroups []*ProcessGroupStatus `json:"processGroups,omitempty"`
 
+	// ProcessGroupTombstones contains the process groups that were recently removed from the cluster. The IDs of those
+	// process groups are not reused for new process groups until the tombstone expires.
+	ProcessGroupTombstones []ProcessGroupTombstone `json:"processGroupTombstones,omitempty"`
+
+	// OperatorExclusions contains the exclusions that the operator added and has not included again. Only those
+	// exclusions will be included if their address is reused by an active process group.
+	OperatorExclusions []string `json:"operatorExclusions,omitempty"`
+
 	// Locks contains information about the locking system.
 	Locks LockSystemStatus `json:"locks,omitempty"`
 
@@ -291,6 +890,348 @@ type FoundationDBClusterStatus struct {
 
 	// ReconciledProcessGroups reflects the number of process groups that have no condition and are not marked for removal.
 	ReconciledProcessGroups int `json:"reconciledProcessGroups,omitempty"`
+
+	// StorageProcessGroups reflects the number of storage process groups that are not marked for removal. This
+	// value is used as the replica count of the scale subresource.
+	StorageProcessGroups int `json:"storageProcessGroups,omitempty"`
+
+	// StorageSelector is the label selector for the Pods of the storage process groups. This value is used as
+	// the selector of the scale subresource.
+	StorageSelector string `json:"storageSelector,omitempty"`
+
+	// ReconciliationBlocked provides information about why the last reconciliation was requeued instead of
+	// being completed. This will be reset once a reconciliation completes.
+	ReconciliationBlocked *ReconciliationBlockedStatus `json:"reconciliationBlocked,omitempty"`
+
+	// DryRunActions contains the actions the operator would have taken during the last reconciliation if the
+	// cluster is reconciled in dry-run mode. This will be reset once the dry-run mode is disabled.
+	DryRunActions []string `json:"dryRunActions,omitempty"`
+
+	// ActionHistory contains the latest actions the operator performed for this cluster, ordered from the oldest to
+	// the newest action. The number of actions is limited by the ActionHistoryLimit in the automation options.
+	// +kubebuilder:validation:MaxItems=100
+	ActionHistory []ClusterAction `json:"actionHistory,omitempty"`
+
+	// MigrationPhase defines the current phase of the migration from an external cluster into operator-managed Pods.
+	// This will only be set if the ExternalMigration is defined in the spec.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=JoiningProcesses;ExcludingExternalProcesses;ChangingCoordinators;Completed
+	MigrationPhase MigrationPhase `json:"migrationPhase,omitempty"`
+
+	// Tenants contains the tenants that exist in the cluster and are defined in the spec.
+	Tenants []TenantStatus `json:"tenants,omitempty"`
+
+	// TagQuotas contains the current quotas of the transaction tags that are defined in the spec and of the tags
+	// that were removed from the spec until their quota is cleared.
+	TagQuotas []TagQuota `json:"tagQuotas,omitempty"`
+
+	// AuthorizationPublicKeyIDs contains the key IDs of the public keys that are distributed to the fdbserver
+	// processes for the token based authorization.
+	AuthorizationPublicKeyIDs []string `json:"authorizationPublicKeyIDs,omitempty"`
+
+	// ProcessEnvironmentHashes contains the hash of the data in the Secrets and ConfigMaps that are referenced in
+	// the environment variables of each process class.
+	ProcessEnvironmentHashes map[ProcessClass]string `json:"processEnvironmentHashes,omitempty"`
+
+	// TLSCertificateHashes contains the hash of the certificates that were
+	// issued by cert-manager, the key is the name of the certificate.
+	TLSCertificateHashes map[string]string `json:"tlsCertificateHashes,omitempty"`
+
+	// TLSCertificates contains the certificates that are mounted into the
+	// fdbserver processes and the expiration of the certificates that are
+	// currently used by the processes.
+	TLSCertificates []TLSCertificateStatus `json:"tlsCertificates,omitempty"`
+
+	// DataDistributionDisabled defines if data distribution is currently disabled in the cluster.
+	DataDistributionDisabled bool `json:"dataDistributionDisabled,omitempty"`
+
+	// StorageAutoscaling contains the state of the storage autoscaling.
+	StorageAutoscaling *StorageAutoscalingStatus `json:"storageAutoscaling,omitempty"`
+
+	// DatabaseConfigurationDrift reports a difference between the running database configuration and the
+	// configuration in the cluster spec that was not caused by a change of the cluster spec.
+	DatabaseConfigurationDrift *DatabaseConfigurationDriftStatus `json:"databaseConfigurationDrift,omitempty"`
+
+	// CrashReports contains the metadata of the most recent crashes of the containers managed by the operator.
+	// This will only be populated if the crash collection is enabled.
+	CrashReports []CrashReport `json:"crashReports,omitempty"`
+
+	// LastDestructiveAction contains the last destructive action that the operator performed. This will only be
+	// populated if a settle time is defined.
+	LastDestructiveAction *DestructiveActionStatus `json:"lastDestructiveAction,omitempty"`
+
+	// LastClusterFileVerification is the time when the operator verified the cluster files of all Pods the last
+	// time. This will only be populated if the cluster file verification is enabled.
+	LastClusterFileVerification *metav1.Time `json:"lastClusterFileVerification,omitempty"`
+
+	// LastObservedRecovery is the last time the operator observed an active recovery of the database. This will only
+	// be populated if the recovery freeze is enabled.
+	LastObservedRecovery *metav1.Time `json:"lastObservedRecovery,omitempty"`
+
+	// RecoveringSince is the time when the operator first observed the current active recovery of the database. This
+	// will only be populated if the recovery freeze is enabled and the database is in an active recovery.
+	RecoveringSince *metav1.Time `json:"recoveringSince,omitempty"`
+
+	// Notifications contains the state of the notifications that were sent for this cluster. This will only be
+	// populated if notifications are configured.
+	Notifications *NotificationStatus `json:"notifications,omitempty"`
+
+	// ActionBudget contains the Pod actions the operator performed in the current hourly window. This will only be
+	// populated if MaxActionsPerHour is defined.
+	ActionBudget *ActionBudgetStatus `json:"actionBudget,omitempty"`
+
+	// CoordinatorChange contains the state of the most recent coordinator change. The previous and the pending
+	// connection string are persisted before the coordinators are changed, so the operator can determine the
+	// authoritative connection string if it is interrupted during the change.
+	CoordinatorChange *CoordinatorChangeStatus `json:"coordinatorChange,omitempty"`
+
+	// FaultTolerance contains the fault tolerance of the database as reported in the database status. This will
+	// only be populated if the database was reachable during the last status update.
+	FaultTolerance *FaultToleranceStatus `json:"faultTolerance,omitempty"`
+
+	// IncompatibleClients contains the clients that are connected with a protocol version that is incompatible with
+	// the desired version. This will only be populated during a version incompatible upgrade.
+	IncompatibleClients *IncompatibleClientsStatus `json:"incompatibleClients,omitempty"`
+
+	// Phase defines the phase of the cluster, which is derived from the state of the reconciliation and the health
+	// of the database.
+	Phase ClusterPhase `json:"phase,omitempty"`
+
+	// ReconciliationProgress defines the percentage of the sub-reconcilers that were completed in the latest
+	// reconciliation. This is only a coarse indicator as the sub-reconcilers take different amounts of time.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ReconciliationProgress int `json:"reconciliationProgress,omitempty"`
+
+	// ProcessClassReassignments contains the process class reassignments that are in progress and the strategy
+	// that was chosen for them.
+	ProcessClassReassignments []ProcessClassReassignmentStatus `json:"processClassReassignments,omitempty"`
+
+	// Revisions contains the rollout state of the Pod template of every process class.
+	Revisions []ProcessClassRevisionStatus `json:"revisions,omitempty"`
+
+	// ProxyDrain contains the state of the drain of the processes with a proxy role that the operator is about to
+	// bounce. This will only be populated if the proxy draining is enabled.
+	ProxyDrain *ProxyDrainStatus `json:"proxyDrain,omitempty"`
+}
+
+// ProxyDrainStatus contains the state of the drain of the processes with a proxy role before they are bounced.
+type ProxyDrainStatus struct {
+	// ProcessGroupIDs defines the process groups with a proxy role that are drained.
+	ProcessGroupIDs []ProcessGroupID `json:"processGroupIDs,omitempty"`
+
+	// Timestamp defines when the drain was started.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+}
+
+// ProcessClassRevisionStatus contains the rollout state of the Pod template of a process class. A revision is the
+// hash of the Pod template of the process class, similar to the pod-template-hash of a ReplicaSet.
+type ProcessClassRevisionStatus struct {
+	// ProcessClass defines the process class of this revision status.
+	ProcessClass ProcessClass `json:"processClass"`
+
+	// UpdateRevision defines the revision of the desired Pod template for the process class.
+	UpdateRevision string `json:"updateRevision"`
+
+	// ProcessGroups defines the number of process groups of the process class that are not marked for removal.
+	ProcessGroups int `json:"processGroups,omitempty"`
+
+	// UpdatedProcessGroups defines the number of process groups of the process class that run with the
+	// UpdateRevision.
+	UpdatedProcessGroups int `json:"updatedProcessGroups,omitempty"`
+
+	// Revisions contains the number of process groups for every revision that is currently running. Process groups
+	// without a Pod are not counted.
+	Revisions []RevisionCount `json:"revisions,omitempty"`
+}
+
+// RevisionCount defines the number of process groups that run with a revision.
+type RevisionCount struct {
+	// Revision defines the revision of the Pod template.
+	Revision string `json:"revision"`
+
+	// ProcessGroups defines the number of process groups that run with this revision.
+	ProcessGroups int `json:"processGroups"`
+}
+
+// ProcessClassReassignmentStrategy defines how a process group is assigned to a different process class.
+// +kubebuilder:validation:Enum=Immediate;Replacement
+type ProcessClassReassignmentStrategy string
+
+const (
+	// ProcessClassReassignmentStrategyImmediate defines that neither process class stores data, so the process group
+	// is removed as soon as the process group with the new process class is added.
+	ProcessClassReassignmentStrategyImmediate ProcessClassReassignmentStrategy = "Immediate"
+	// ProcessClassReassignmentStrategyReplacement defines that at least one of the process classes stores data, so
+	// the process group is only removed once the process group with the new process class is running without any
+	// conditions. The data of the process group is moved during the exclusion.
+	ProcessClassReassignmentStrategyReplacement ProcessClassReassignmentStrategy = "Replacement"
+)
+
+// ProcessClassReassignmentStatus contains the information about the reassignment of a process group to a different
+// process class.
+type ProcessClassReassignmentStatus struct {
+	// ProcessGroupID defines the process group that is reassigned.
+	ProcessGroupID ProcessGroupID `json:"processGroupID"`
+
+	// ProcessClass defines the new process class.
+	ProcessClass ProcessClass `json:"processClass"`
+
+	// NewProcessGroupID defines the process group with the new process class.
+	NewProcessGroupID ProcessGroupID `json:"newProcessGroupID"`
+
+	// Strategy defines the strategy that was chosen for the reassignment.
+	Strategy ProcessClassReassignmentStrategy `json:"strategy"`
+
+	// Timestamp defines when the reassignment was started.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+}
+
+// ClusterPhase defines the phase of a cluster.
+// +kubebuilder:validation:Enum=Creating;Reconciling;Upgrading;Degraded;Ready
+type ClusterPhase string
+
+const (
+	// ClusterPhaseCreating defines that the database of the cluster was not configured yet.
+	ClusterPhaseCreating ClusterPhase = "Creating"
+	// ClusterPhaseReconciling defines that the operator is reconciling the latest generation of the cluster.
+	ClusterPhaseReconciling ClusterPhase = "Reconciling"
+	// ClusterPhaseUpgrading defines that the cluster is upgraded to a new version.
+	ClusterPhaseUpgrading ClusterPhase = "Upgrading"
+	// ClusterPhaseDegraded defines that the database is unavailable or not fully replicated.
+	ClusterPhaseDegraded ClusterPhase = "Degraded"
+	// ClusterPhaseReady defines that the cluster is reconciled and the database is available and fully replicated.
+	ClusterPhaseReady ClusterPhase = "Ready"
+)
+
+// IncompatibleClientsStatus contains the information about the clients that don't support the desired version.
+type IncompatibleClientsStatus struct {
+	// Version defines the desired version that the clients don't support.
+	Version string `json:"version,omitempty"`
+
+	// Count defines the number of clients that don't support the desired version.
+	Count int `json:"count,omitempty"`
+
+	// Clients contains the descriptions of the clients that don't support the desired version. Only the first
+	// 100 clients will be listed.
+	// +kubebuilder:validation:MaxItems=100
+	Clients []string `json:"clients,omitempty"`
+}
+
+// FaultToleranceStatus contains the number of zones that can fail without losing data or availability.
+type FaultToleranceStatus struct {
+	// MaxZoneFailuresWithoutLosingData defines the maximum number of zones that can fail before losing data.
+	MaxZoneFailuresWithoutLosingData int `json:"maxZoneFailuresWithoutLosingData"`
+
+	// MaxZoneFailuresWithoutLosingAvailability defines the maximum number of zones that can fail before losing
+	// availability.
+	MaxZoneFailuresWithoutLosingAvailability int `json:"maxZoneFailuresWithoutLosingAvailability"`
+
+	// DesiredFaultTolerance defines the number of zones that should be able to fail based on the redundancy mode.
+	DesiredFaultTolerance int `json:"desiredFaultTolerance"`
+}
+
+// CoordinatorChangeState defines the state of a coordinator change.
+type CoordinatorChangeState string
+
+const (
+	// CoordinatorChangeStateProposed defines that the operator selected new coordinators but the change was not
+	// confirmed by the database yet.
+	CoordinatorChangeStateProposed CoordinatorChangeState = "Proposed"
+	// CoordinatorChangeStateCommitted defines that the database accepted the new coordinators and the pending
+	// connection string is authoritative.
+	CoordinatorChangeStateCommitted CoordinatorChangeState = "Committed"
+	// CoordinatorChangeStateDistributed defines that the pending connection string was written into the cluster
+	// config map.
+	CoordinatorChangeStateDistributed CoordinatorChangeState = "Distributed"
+)
+
+// CoordinatorChangeStatus contains the information about a coordinator change.
+type CoordinatorChangeStatus struct {
+	// State defines the current state of the coordinator change.
+	// +kubebuilder:validation:Enum=Proposed;Committed;Distributed
+	State CoordinatorChangeState `json:"state,omitempty"`
+
+	// PreviousConnectionString defines the connection string before the coordinator change.
+	PreviousConnectionString string `json:"previousConnectionString,omitempty"`
+
+	// PendingConnectionString defines the connection string returned by the database after the coordinator
+	// change. This will only be populated once the change is committed.
+	PendingConnectionString string `json:"pendingConnectionString,omitempty"`
+
+	// Coordinators defines the addresses of the new coordinators.
+	Coordinators []string `json:"coordinators,omitempty"`
+
+	// Timestamp defines when the coordinator change entered the current state.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+}
+
+// ActionBudgetStatus contains the information about the Pod actions that were performed in the current hourly window.
+type ActionBudgetStatus struct {
+	// WindowStart defines when the current hourly window started.
+	WindowStart *metav1.Time `json:"windowStart,omitempty"`
+
+	// UsedActions defines how many Pod actions were performed in the current hourly window.
+	UsedActions int `json:"usedActions,omitempty"`
+}
+
+// MigrationPhase defines the phase of the migration from an external cluster into operator-managed Pods.
+type MigrationPhase string
+
+const (
+	// MigrationPhaseJoiningProcesses waits until all operator-managed processes have joined the external cluster.
+	MigrationPhaseJoiningProcesses MigrationPhase = "JoiningProcesses"
+	// MigrationPhaseExcludingExternalProcesses excludes the external processes and waits until the data was moved
+	// to the operator-managed processes.
+	MigrationPhaseExcludingExternalProcesses MigrationPhase = "ExcludingExternalProcesses"
+	// MigrationPhaseChangingCoordinators moves the coordinators from the external processes to the operator-managed
+	// processes.
+	MigrationPhaseChangingCoordinators MigrationPhase = "ChangingCoordinators"
+	// MigrationPhaseCompleted defines that the migration was completed.
+	MigrationPhaseCompleted MigrationPhase = "Completed"
+)
+
+// DestructiveActionStatus contains the information about a destructive action that the operator performed.
+type DestructiveActionStatus struct {
+	// Action describes the destructive action.
+	Action string `json:"action,omitempty"`
+
+	// Timestamp defines when the action was performed.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+}
+
+// DatabaseConfigurationDriftStatus contains the information about a database configuration change that was
+// made outside of the operator.
+type DatabaseConfigurationDriftStatus struct {
+	// Configuration provides the running database configuration as configuration string.
+	Configuration string `json:"configuration,omitempty"`
+
+	// DesiredConfiguration provides the desired database configuration from the spec as configuration string at the
+	// time the drift was detected.
+	DesiredConfiguration string `json:"desiredConfiguration,omitempty"`
+
+	// Timestamp defines when the drift was detected.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+}
+
+// ReconciliationBlockedStatus provides information about the sub-reconciler that requeued the reconciliation.
+type ReconciliationBlockedStatus struct {
+	// SubReconciler defines the name of the sub-reconciler that requeued the reconciliation.
+	// +kubebuilder:validation:MaxLength=256
+	SubReconciler string `json:"subReconciler,omitempty"`
+
+	// Message provides a human-readable explanation why the reconciliation was requeued.
+	// +kubebuilder:validation:MaxLength=4096
+	Message string `json:"message,omitempty"`
+
+	// Delay defines the delay that was chosen before the reconciliation is requeued.
+	Delay metav1.Duration `json:"delay,omitempty"`
+
+	// Timestamp provides the timestamp when the reconciliation was requeued.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+
+	// Since provides the timestamp since when the reconciliation is blocked without being completed in between.
+	Since *metav1.Time `json:"since,omitempty"`
 }
 
 // MaintenanceModeInfo contains information regarding the zone and process groups that are put
@@ -313,6 +1254,33 @@ type LockSystemStatus struct {
 	DenyList []string `json:"lockDenyList,omitempty"`
 }
 
+// ProcessGroupTombstone represents a process group that was removed from the cluster.
+type ProcessGroupTombstone struct {
+	// ProcessGroupID represents the ID of the removed process group.
+	ProcessGroupID ProcessGroupID `json:"processGroupID,omitempty"`
+
+	// RemovalTimestamp defines when the process group was removed from the cluster.
+	RemovalTimestamp *metav1.Time `json:"removalTimestamp,omitempty"`
+}
+
+// TLSCertificateStatus represents a certificate that is used by the fdbserver processes.
+type TLSCertificateStatus struct {
+	// SecretName defines the name of the secret that contains the certificate.
+	SecretName string `json:"secretName"`
+
+	// Key defines the key of the certificate in the secret.
+	Key string `json:"key"`
+
+	// Hash contains the hash of the certificate that is used by the processes.
+	Hash string `json:"hash,omitempty"`
+
+	// NotAfter defines when the certificate that is used by the processes expires.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// Expiring defines if the certificate that is used by the processes expires within the rotation window.
+	Expiring bool `json:"expiring,omitempty"`
+}
+
 // ProcessGroupStatus represents the status of a ProcessGroup.
 type ProcessGroupStatus struct {
 	// ProcessGroupID represents the ID of the process group
@@ -330,6 +1298,21 @@ type ProcessGroupStatus struct {
 	ExclusionSkipped bool `json:"exclusionSkipped,omitempty"`
 	// ProcessGroupConditions represents a list of degraded conditions that the process group is in.
 	ProcessGroupConditions []*ProcessGroupCondition `json:"processGroupConditions,omitempty"`
+	// Revision defines the revision of the Pod template of the process class that the Pod of this process group
+	// was last updated to. The revision is only changed once the Pod matches the desired spec.
+	Revision string `json:"revision,omitempty"`
+	// VolumeClaimTemplateSelector defines the name of the entry of the volumeClaimTemplateSelectors of the process
+	// class that is used for this process group. The entry is chosen when the process group is created and kept for
+	// the lifetime of the process group, so the process group stays in its fault domain or node pool.
+	// +kubebuilder:validation:MaxLength=100
+	VolumeClaimTemplateSelector string `json:"volumeClaimTemplateSelector,omitempty"`
+	// TLSCertificateHash defines the hash of the certificate issued by cert-manager that the processes of this
+	// process group were started with. If the hash differs from the hash in the tlsCertificateHashes of the cluster
+	// status, the processes will be restarted to use the renewed certificate.
+	TLSCertificateHash string `json:"tlsCertificateHash,omitempty"`
+	// PinnedFrom defines the pinned process group that this process group replaces. The process group inherits the
+	// pin of that process group, so the replacement runs on the same node or in the same zone.
+	PinnedFrom ProcessGroupID `json:"pinnedFrom,omitempty"`
 }
 
 // ProcessGroupID represents the ID of the process group
@@ -338,7 +1321,7 @@ type ProcessGroupID string
 
 // GetExclusionString returns the exclusion string
 func (processGroupStatus *ProcessGroupStatus) GetExclusionString() string {
-	return fmt.Sprintf("locality_instance_id:%s", processGroupStatus.ProcessGroupID)
+	return FDBLocalityInstanceIDExclusionPrefix + string(processGroupStatus.ProcessGroupID)
 }
 
 // IsExcluded returns if a process group is excluded
@@ -753,6 +1736,11 @@ const (
 	NodeTaintDetected ProcessGroupConditionType = "NodeTaintDetected"
 	// NodeTaintReplacing represents a Pod whose node has been tainted and the operator should replace the Pod
 	NodeTaintReplacing ProcessGroupConditionType = "NodeTaintReplacing"
+	// IncorrectClusterFile represents a Pod whose cluster file doesn't match the connection string of the cluster.
+	IncorrectClusterFile ProcessGroupConditionType = "IncorrectClusterFile"
+	// ExclusionTimedOut represents a process group that is marked for removal and whose exclusion didn't complete
+	// within the exclusion timeout.
+	ExclusionTimedOut ProcessGroupConditionType = "ExclusionTimedOut"
 )
 
 // AllProcessGroupConditionTypes returns all ProcessGroupConditionType
@@ -771,6 +1759,8 @@ func AllProcessGroupConditionTypes() []ProcessGroupConditionType {
 		ReadyCondition,
 		NodeTaintDetected,
 		NodeTaintReplacing,
+		IncorrectClusterFile,
+		ExclusionTimedOut,
 	}
 }
 
@@ -801,6 +1791,8 @@ func GetProcessGroupConditionType(processGroupConditionType string) (ProcessGrou
 		return NodeTaintDetected, nil
 	case "NodeTaintReplacing":
 		return NodeTaintReplacing, nil
+	case "ExclusionTimedOut":
+		return ExclusionTimedOut, nil
 	}
 
 	return "", fmt.Errorf("unknown process group condition type: %s", processGroupConditionType)
@@ -868,6 +1860,22 @@ type ClusterGenerationStatus struct {
 	// NeedsLockConfigurationChanges provides the last generation that is
 	// pending a change to the configuration of the locking system.
 	NeedsLockConfigurationChanges int64 `json:"needsLockConfigurationChanges,omitempty"`
+
+	// NeedsExternalMigration provides the last generation that is pending
+	// the migration from an external cluster.
+	NeedsExternalMigration int64 `json:"needsExternalMigration,omitempty"`
+
+	// NeedsTenantChanges provides the last generation that is pending
+	// the creation or deletion of a tenant.
+	NeedsTenantChanges int64 `json:"needsTenantChanges,omitempty"`
+
+	// NeedsTagQuotaChanges provides the last generation that is pending
+	// a change to the quotas of the transaction tags.
+	NeedsTagQuotaChanges int64 `json:"needsTagQuotaChanges,omitempty"`
+
+	// NeedsDataDistributionModeChange provides the last generation that is
+	// pending a change to the data distribution mode.
+	NeedsDataDistributionModeChange int64 `json:"needsDataDistributionModeChange,omitempty"`
 }
 
 // ClusterHealth represents different views into health in the cluster status.
@@ -894,6 +1902,16 @@ type FoundationDBClusterAutomationOptions struct {
 	// the database.
 	ConfigureDatabase *bool `json:"configureDatabase,omitempty"`
 
+	// ConfigureDatabaseMode defines how the operator handles changes to the database configuration that were made
+	// outside of the operator, e.g. with fdbcli. The mode Always reverts those changes, IfSafe only reverts those
+	// changes if the reversion doesn't change the redundancy mode, the storage engine or the regions and Never only
+	// reports the detected drift in the status. Changes of the cluster spec will be applied in all modes.
+	// The default is Always.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Always;IfSafe;Never
+	// +kubebuilder:default:=Always
+	ConfigureDatabaseMode ConfigureDatabaseMode `json:"configureDatabaseMode,omitempty"`
+
 	// KillProcesses defines whether the operator is allowed to bounce fdbserver
 	// processes.
 	KillProcesses *bool `json:"killProcesses,omitempty"`
@@ -915,6 +1933,12 @@ type FoundationDBClusterAutomationOptions struct {
 	// The default is false.
 	UseLocalitiesForExclusion *bool `json:"useLocalitiesForExclusion,omitempty"`
 
+	// IncludeReusedAddresses defines whether the operator includes addresses that it excluded itself and that are
+	// now used by an active process group again, e.g. because the IP address of a removed Pod was assigned to a new
+	// Pod. Exclusions that were not done by the operator are never included.
+	// The default is true.
+	IncludeReusedAddresses *bool `json:"includeReusedAddresses,omitempty"`
+
 	// IgnoreTerminatingPodsSeconds defines how long a Pod has to be in the Terminating Phase before
 	// we ignore it during reconciliation. This prevents Pod that are stuck in Terminating to block
 	// further reconciliation.
@@ -940,6 +1964,49 @@ type FoundationDBClusterAutomationOptions struct {
 	// +kubebuilder:validation:Minimum=0
 	MaxConcurrentReplacements *int `json:"maxConcurrentReplacements,omitempty"`
 
+	// DecommissionBatchSize defines how many process groups of the fault domains in
+	// FaultDomainsToDecommission can be removed concurrently. The operator will only
+	// mark the next batch for removal once the previous batch is removed.
+	// Default is 1.
+	// +kubebuilder:validation:Minimum=1
+	DecommissionBatchSize *int `json:"decommissionBatchSize,omitempty"`
+
+	// SettleTimeSeconds defines how long the operator waits after the last recovery of the database and after its
+	// last destructive action before it performs the next destructive action. Destructive actions are bouncing
+	// processes, changing the coordinators, changing the database configuration and deleting Pods for updates.
+	// This allows the cluster to settle between those actions and reduces the risk of cascading recoveries.
+	// The default is 0, which disables the settle time.
+	// +kubebuilder:validation:Minimum=0
+	SettleTimeSeconds *int `json:"settleTimeSeconds,omitempty"`
+
+	// RecoveryFreezeSeconds enables the recovery freeze and defines how long the database must be fully recovered
+	// before the operator performs the next destructive action. While the database is in an active recovery, the
+	// operator defers bouncing processes, excluding processes and deleting Pods. The recovery state is only reported by
+	// FoundationDB versions that support it, for all other versions the recovery freeze has no effect.
+	// If unset, the recovery freeze is disabled.
+	// +kubebuilder:validation:Minimum=0
+	RecoveryFreezeSeconds *int `json:"recoveryFreezeSeconds,omitempty"`
+
+	// MaxRecoveryFreezeSeconds defines how long the operator defers destructive actions while the database stays in
+	// an active recovery. If the database doesn't reach the fully recovered state within this time, the operator
+	// will perform the destructive actions anyway. This only has an effect if the recovery freeze is enabled.
+	// Defaults to 3600.
+	// +kubebuilder:validation:Minimum=0
+	MaxRecoveryFreezeSeconds *int `json:"maxRecoveryFreezeSeconds,omitempty"`
+
+	// CommandTimeoutSeconds defines the timeout for a single command the operator issues against this cluster, e.g.
+	// an fdbcli, fdbbackup or fdbrestore invocation or a read of the status through the client library. Commands that
+	// are retried with a backoff will start with this timeout. If unset the timeout defined by the --cli-timeout flag
+	// of the operator will be used.
+	// +kubebuilder:validation:Minimum=1
+	CommandTimeoutSeconds *int `json:"commandTimeoutSeconds,omitempty"`
+
+	// DetectionOnly defines if the operator should only detect issues like missing processes, failed Pods or lagging
+	// exclusions without remediating them. The findings will still be reported in the operator logs. This disables
+	// the automatic replacements and all custom remediation handlers.
+	// Default is false.
+	DetectionOnly *bool `json:"detectionOnly,omitempty"`
+
 	// DeletionMode defines the deletion mode for this cluster. This can be
 	// PodUpdateModeNone, PodUpdateModeAll, PodUpdateModeZone or PodUpdateModeProcessGroup. The
 	// DeletionMode defines how Pods are deleted in order to update them or
@@ -958,6 +2025,22 @@ type FoundationDBClusterAutomationOptions struct {
 	// +kubebuilder:default:=Zone
 	RemovalMode PodUpdateMode `json:"removalMode,omitempty"`
 
+	// ExclusionTimeoutSeconds defines how long the exclusion of a process group that is marked for removal may take.
+	// After the timeout the process group gets the ExclusionTimedOut condition and a warning event is emitted. If
+	// ForceRemovalOnExclusionTimeout is set, the process group will be removed without completing the exclusion.
+	// The default is 0, which disables the timeout.
+	// +kubebuilder:validation:Minimum=0
+	ExclusionTimeoutSeconds *int `json:"exclusionTimeoutSeconds,omitempty"`
+
+	// ForceRemovalOnExclusionTimeout defines if process groups whose exclusion timed out should be removed without
+	// completing the exclusion, e.g. because the data of the process group is already unreachable. Only process
+	// groups whose processes are missing in the machine-readable status for longer than the exclusion timeout are
+	// removed and only if the cluster has the desired fault tolerance. Removing a process group with an incomplete
+	// exclusion can lead to data loss if the process group holds the last copy of some data, so this should only be
+	// enabled as an escape hatch.
+	// Default is false.
+	ForceRemovalOnExclusionTimeout *bool `json:"forceRemovalOnExclusionTimeout,omitempty"`
+
 	// WaitBetweenRemovalsSeconds defines how long to wait between the last removal and the next removal. This is only an
 	// upper limit if the process group and the according resources are deleted faster than the provided duration the
 	// operator will move on with the next removal. The idea is to prevent a race condition were the operator deletes
@@ -966,6 +2049,21 @@ type FoundationDBClusterAutomationOptions struct {
 	// Defaults to 60.
 	WaitBetweenRemovalsSeconds *int `json:"waitBetweenRemovalsSeconds,omitempty"`
 
+	// StorageWiggleWaitSeconds defines how long the operator waits for the perpetual storage wiggle to finish with
+	// the wiggled storage servers before it excludes storage processes anyway. The wait starts when the process group
+	// is marked for removal.
+	// Defaults to 600.
+	// +kubebuilder:validation:Minimum=0
+	StorageWiggleWaitSeconds *int `json:"storageWiggleWaitSeconds,omitempty"`
+
+	// ProcessGroupTombstoneSeconds defines how long the ID of a removed process group is kept in the
+	// processGroupTombstones of the cluster status. New process groups never reuse the ID of a process group with a
+	// tombstone, to prevent that the processes of the new process group are confused with the processes of the removed
+	// process group in the localities or the exclusions of the database.
+	// Defaults to 3600.
+	// +kubebuilder:validation:Minimum=0
+	ProcessGroupTombstoneSeconds *int `json:"processGroupTombstoneSeconds,omitempty"`
+
 	// PodUpdateStrategy defines how Pod spec changes are rolled out either by replacing Pods or by deleting Pods.
 	// The default for this is ReplaceTransactionSystem.
 	// +kubebuilder:validation:Optional
@@ -973,6 +2071,21 @@ type FoundationDBClusterAutomationOptions struct {
 	// +kubebuilder:default:=ReplaceTransactionSystem
 	PodUpdateStrategy PodUpdateStrategy `json:"podUpdateStrategy,omitempty"`
 
+	// InPlacePodResize defines if the operator should resize Pods in place when only the resource requirements of
+	// their containers have changed, instead of recreating or replacing the Pods. This requires the
+	// InPlacePodVerticalScaling feature gate in Kubernetes. If the API server rejects the resize, the operator will
+	// recreate the Pods instead.
+	// Default is false.
+	InPlacePodResize *bool `json:"inPlacePodResize,omitempty"`
+
+	// RecreatePodsForSchedulingChanges defines if the operator should recreate Pods instead of replacing the process
+	// groups when only the scheduling constraints of the Pods have changed, e.g. the nodeSelector, the tolerations or
+	// the affinity. The recreated Pods keep their process group ID and their PVCs, so no data has to be moved. The Pods
+	// will be recreated based on the DeletionMode. This requires that the volumes can be attached to the nodes that
+	// match the new scheduling constraints.
+	// Default is false.
+	RecreatePodsForSchedulingChanges *bool `json:"recreatePodsForSchedulingChanges,omitempty"`
+
 	// UseManagementAPI defines if the operator should make use of the management API instead of
 	// using fdbcli to interact with the FoundationDB cluster.
 	UseManagementAPI *bool `json:"useManagementAPI,omitempty"`
@@ -983,6 +2096,187 @@ type FoundationDBClusterAutomationOptions struct {
 	// IgnoreLogGroupsForUpgrade defines the list of LogGroups that should be ignored during fdb version upgrade.
 	// +kubebuilder:validation:MaxItems=10
 	IgnoreLogGroupsForUpgrade []LogGroup `json:"ignoreLogGroupsForUpgrade,omitempty"`
+
+	// MaxIncompatibleClientsForUpgrade defines how many clients may still be connected with a protocol version that
+	// is incompatible with the desired version before the operator performs a version incompatible upgrade. The
+	// upgrade will be blocked as long as more clients are connected. Clients of the log groups in
+	// IgnoreLogGroupsForUpgrade will not be counted. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	MaxIncompatibleClientsForUpgrade *int `json:"maxIncompatibleClientsForUpgrade,omitempty"`
+
+	// DryRun defines if the operator should only compute and report the actions it would take for this cluster
+	// without performing any changes to the Kubernetes resources or the FoundationDB cluster. The actions will be
+	// reported as events and in the status of the cluster.
+	// Default is false.
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// ActionHistoryLimit defines how many of the latest actions the operator keeps in the actionHistory of the
+	// cluster status. Setting this to 0 disables the action history.
+	// Default is 20.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ActionHistoryLimit *int `json:"actionHistoryLimit,omitempty"`
+
+	// ClusterFileVerificationOptions contains options for the periodic verification of the cluster files of all
+	// Pods.
+	ClusterFileVerificationOptions ClusterFileVerificationOptions `json:"clusterFileVerificationOptions,omitempty"`
+
+	// LatencyProbeOptions contains options for the periodic latency probes against the cluster.
+	LatencyProbeOptions LatencyProbeOptions `json:"latencyProbeOptions,omitempty"`
+
+	// TLSCertificateRotationOptions contains options for the rotation of the
+	// certificates that are mounted into the fdbserver processes.
+	TLSCertificateRotationOptions TLSCertificateRotationOptions `json:"tlsCertificateRotationOptions,omitempty"`
+
+	// StatusReportOptions contains options for the FoundationDBClusterStatusReport that contains a snapshot of the
+	// machine-readable status of the cluster.
+	StatusReportOptions StatusReportOptions `json:"statusReportOptions,omitempty"`
+
+	// ProxyDrainingOptions contains options for draining the processes with a commit proxy or GRV proxy role before
+	// the operator bounces them.
+	ProxyDrainingOptions ProxyDrainingOptions `json:"proxyDrainingOptions,omitempty"`
+
+	// BounceScheduleOptions contains options for deferring bounces until the load of the cluster is low or until a
+	// low-traffic window.
+	BounceScheduleOptions BounceScheduleOptions `json:"bounceScheduleOptions,omitempty"`
+
+	// ActionBudget limits how many Pods the operator may create, delete or bounce. This caps the impact of a bad
+	// change of the cluster spec.
+	ActionBudget ActionBudgetOptions `json:"actionBudget,omitempty"`
+
+	// UseWorkJournal defines if the operator should store a work journal with the pending exclusions, the stage of
+	// the current upgrade and the state of the current coordinator change in the database of the cluster. Another
+	// instance of the operator resumes the operations from the work journal, even if the status of the cluster was
+	// lost.
+	// Default is false.
+	UseWorkJournal *bool `json:"useWorkJournal,omitempty"`
+}
+
+// ActionBudgetOptions controls how many Pod actions the operator may perform for a cluster. Pod actions are
+// creating Pods, deleting Pods for updates and bouncing processes. Once the budget is exhausted the operator
+// waits until new budget is available. Bounces during a version incompatible upgrade are counted but not limited,
+// as all processes must be restarted at the same time.
+type ActionBudgetOptions struct {
+	// MaxActionsPerReconcile defines how many Pod actions the operator may perform in a single reconciliation loop.
+	// The default is unset, which doesn't limit the Pod actions per reconciliation loop.
+	// +kubebuilder:validation:Minimum=1
+	MaxActionsPerReconcile *int `json:"maxActionsPerReconcile,omitempty"`
+
+	// MaxActionsPerHour defines how many Pod actions the operator may perform in a window of one hour.
+	// The default is unset, which doesn't limit the Pod actions per hour.
+	// +kubebuilder:validation:Minimum=1
+	MaxActionsPerHour *int `json:"maxActionsPerHour,omitempty"`
+}
+
+// ProxyDrainingOptions controls the delay before the operator bounces processes with a commit proxy or GRV proxy
+// role. Clients send their transactions to the proxies, so bouncing a proxy fails the in-flight transactions of its
+// clients. The operator can't stop clients from sending new transactions to a proxy, so the drain is only a delay
+// between the decision to bounce the processes and the bounce, e.g. to give an external system time to react.
+type ProxyDrainingOptions struct {
+	// Enabled defines if the operator should drain the processes with a proxy role before it bounces them.
+	// Default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// DelaySeconds defines how long the operator waits after it started the drain before it bounces the processes.
+	// Default is 10.
+	// +kubebuilder:validation:Minimum=0
+	DelaySeconds *int `json:"delaySeconds,omitempty"`
+}
+
+// BounceScheduleOptions controls when the operator bounces processes. If a maximum load or windows are defined, the
+// operator defers bounces until the load of the cluster drops below the maximum or until the current time is in one
+// of the windows, whatever comes first.
+type BounceScheduleOptions struct {
+	// MaxOperationsPerSecond defines the maximum load, as the sum of the read and write operations per second in the
+	// machine-readable status, at which the operator bounces processes. If unset, the load will not be checked.
+	// +kubebuilder:validation:Minimum=0
+	MaxOperationsPerSecond *int `json:"maxOperationsPerSecond,omitempty"`
+
+	// Windows defines the low-traffic windows in which the operator bounces processes independent of the load.
+	// +kubebuilder:validation:MaxItems=24
+	Windows []BounceWindow `json:"windows,omitempty"`
+
+	// UrgentUntil allows the operator to bounce processes independent of the load and the windows until the
+	// defined time, e.g. to roll out an urgent security fix.
+	UrgentUntil *metav1.Time `json:"urgentUntil,omitempty"`
+}
+
+// BounceWindow defines a daily window in which the operator bounces processes.
+type BounceWindow struct {
+	// Start defines the start of the window as time of the day in UTC in the format HH:MM.
+	// +kubebuilder:validation:Pattern:=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// DurationMinutes defines how long the window lasts.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1440
+	DurationMinutes int `json:"durationMinutes"`
+}
+
+// StatusReportOptions controls options for the FoundationDBClusterStatusReport of a cluster. The report contains a
+// periodically updated snapshot of the machine-readable status, which allows dashboards and other controllers to
+// consume the health of the cluster through the Kubernetes API without access to fdbcli.
+type StatusReportOptions struct {
+	// Enabled defines if the operator should maintain a FoundationDBClusterStatusReport for the cluster.
+	// Default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// IntervalSeconds defines the minimum time between two updates of the status report.
+	// Default is 60.
+	// +kubebuilder:validation:Minimum=10
+	IntervalSeconds *int `json:"intervalSeconds,omitempty"`
+}
+
+// LatencyProbeOptions controls options for the periodic latency probes. A latency probe measures the latency of
+// getting a read version, reading a key and committing a transaction from the operator and exports the measurements
+// as histograms. This provides a service level indicator that doesn't depend on the metrics reported by the clients.
+type LatencyProbeOptions struct {
+	// Enabled defines if the operator should periodically probe the latencies of the cluster.
+	// Default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// IntervalSeconds defines the minimum time between two latency probes.
+	// Default is 30.
+	// +kubebuilder:validation:Minimum=1
+	IntervalSeconds *int `json:"intervalSeconds,omitempty"`
+}
+
+// TLSCertificateRotationOptions controls options for the rotation of the certificates that are mounted into the
+// fdbserver processes. The processes only read the certificate during startup, so a renewed certificate in the secret
+// is only used after the processes were restarted.
+type TLSCertificateRotationOptions struct {
+	// Enabled defines if the operator should update the Pods to use the
+	// renewed certificate before the certificate that is used by the
+	// processes expires.
+	// Default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// RotationWindowSeconds defines how long before the expiration of the
+	// certificate that is used by the processes the Pods will be updated. The
+	// operator emits a warning event for certificates in the secrets that
+	// expire within this window.
+	// Default is 604800 (7 days).
+	// +kubebuilder:validation:Minimum=0
+	RotationWindowSeconds *int `json:"rotationWindowSeconds,omitempty"`
+}
+
+// ClusterFileVerificationOptions controls options for the periodic verification of the cluster files of all Pods.
+// A Pod with a stale cluster file is not able to rejoin the cluster after the coordinators have changed.
+type ClusterFileVerificationOptions struct {
+	// Enabled defines if the operator should periodically verify that the cluster file of every Pod matches the
+	// connection string of the cluster.
+	// Default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// IntervalSeconds defines the minimum time between two verifications of the cluster files.
+	// Default is 600.
+	// +kubebuilder:validation:Minimum=0
+	IntervalSeconds *int `json:"intervalSeconds,omitempty"`
+
+	// FixIncorrectClusterFiles defines if the operator should update the cluster file of Pods with an incorrect
+	// cluster file.
+	// Default is false.
+	FixIncorrectClusterFiles *bool `json:"fixIncorrectClusterFiles,omitempty"`
 }
 
 // LogGroup represents a LogGroup used by a FoundationDB process to log trace events. The LogGroup can be used to filter
@@ -1068,7 +2362,199 @@ type ProcessSettings struct {
 
 	// CustomParameters defines additional parameters to pass to the fdbserver
 	// process.
+	// Deprecated: use Parameters instead.
 	CustomParameters FoundationDBCustomParameters `json:"customParameters,omitempty"`
+
+	// Parameters defines additional parameters to pass to the fdbserver process. The parameters of the general
+	// process settings are used as defaults for all process classes, the parameters of a process class override
+	// the defaults with the same name and can remove a default by setting remove to true.
+	Parameters FoundationDBParameters `json:"parameters,omitempty"`
+
+	// Env defines additional environment variables for the fdbserver processes of this process class, e.g. the TLS
+	// password or proxy settings. The variables are added to the main container and can be referenced in the
+	// parameters with $NAME. Changes of the referenced Secrets and ConfigMaps are detected by the operator and
+	// will update the Pods.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom defines Secrets and ConfigMaps whose keys are added as environment variables to the main container.
+	// Changes of the referenced Secrets and ConfigMaps are detected by the operator and will update the Pods.
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// AdditionalContainers defines containers that will be added to the pod, e.g. logging or metrics agents.
+	// Those containers are not part of the spec comparison, so changing them will only affect newly created pods.
+	// The operator will wait until those containers are ready before interacting with the sidecar.
+	AdditionalContainers []corev1.Container `json:"additionalContainers,omitempty"`
+
+	// AdditionalInitContainers defines init containers that will be added to the pod after the operator's
+	// init container. Those containers are not part of the spec comparison, so changing them will only affect
+	// newly created pods.
+	AdditionalInitContainers []corev1.Container `json:"additionalInitContainers,omitempty"`
+
+	// DNS defines the DNS settings of the Pods. The generated settings are part of the spec comparison, so changing
+	// them will update the existing Pods.
+	DNS *PodDNSSettings `json:"dns,omitempty"`
+
+	// HealthProbes defines probes for the main container that check the health of the fdbserver processes
+	// instead of only checking that a port is open. This allows Kubernetes to restart the main container
+	// if fdbmonitor is hung.
+	HealthProbes *ProcessHealthProbes `json:"healthProbes,omitempty"`
+
+	// UseHostNetwork defines if the Pods of this process class should use the host network. When enabled, every
+	// process class gets a dedicated range of ports, so processes of different process classes can run on the
+	// same node. Pods of the same process class will not be scheduled on the same node, as their ports would
+	// conflict. This setting is only supported with the split image.
+	// The default is false.
+	UseHostNetwork *bool `json:"useHostNetwork,omitempty"`
+
+	// PriorityClassName defines the name of the PriorityClass for the Pods of this process class. The preemption
+	// policy is defined by the PriorityClass. If set, this takes precedence over the priorityClassName in the
+	// Pod template.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// VolumeClaimTemplateSelectors allows to use different volume claim templates for the fault domains or node
+	// pools of the cluster, e.g. if one zone offers local NVMe disks and the other zones only network SSDs. New
+	// process groups use the entry with the fewest process groups of the process class, the chosen entry is recorded
+	// in the status of the process group and the Pods are pinned to the nodes that match the node selector of their
+	// entry. Entries without a volume claim template use the VolumeClaimTemplate. Removing an entry will replace the
+	// process groups that use it.
+	// +kubebuilder:validation:MaxItems=10
+	VolumeClaimTemplateSelectors []VolumeClaimTemplateSelector `json:"volumeClaimTemplateSelectors,omitempty"`
+
+	// Architecture defines the CPU architecture of the nodes the Pods of this process class are scheduled on. If set,
+	// the Pods are only scheduled on nodes with a matching kubernetes.io/arch label and the images are selected from
+	// the image configs for this architecture, so Pods in mixed-architecture node pools don't run binaries for a
+	// different architecture. If unset and the image configs only define images for a single architecture, this
+	// architecture is used. If the image configs define images for multiple architectures, the architecture must be
+	// set. Otherwise the Pods can be scheduled on every node and multi-arch images should be used.
+	// +kubebuilder:validation:Enum=amd64;arm64
+	Architecture string `json:"architecture,omitempty"`
+}
+
+// ProcessHealthProbes defines the liveness and readiness probes for the main container that check the health
+// of the fdbserver processes. For the split image the probe checks that fdbmonitor and the expected number of
+// fdbserver processes are running, for the unified image the probe queries the health endpoint of the
+// fdb-kubernetes-monitor.
+type ProcessHealthProbes struct {
+	// EnableLivenessProbe defines if the main container should have a livenessProbe that checks the health of
+	// the processes.
+	// The default is false.
+	EnableLivenessProbe *bool `json:"enableLivenessProbe,omitempty"`
+
+	// EnableReadinessProbe defines if the main container should have a readinessProbe that checks the health
+	// of the processes.
+	// The default is false.
+	EnableReadinessProbe *bool `json:"enableReadinessProbe,omitempty"`
+
+	// PeriodSeconds defines how often the probes are performed.
+	// The default is 30.
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// TimeoutSeconds defines after how many seconds the probes time out.
+	// The default is 5.
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// FailureThreshold defines how many consecutive failures are required before the probe is considered failed.
+	// The default is 5.
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// PodDNSSettings defines the DNS settings of the Pods of a process class.
+type PodDNSSettings struct {
+	// Policy defines the DNS policy of the Pods. If unset the DNS policy of the Pod template will be used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	Policy corev1.DNSPolicy `json:"policy,omitempty"`
+
+	// Ndots defines the ndots option of the DNS resolver in the Pods.
+	// +kubebuilder:validation:Minimum=0
+	Ndots *int `json:"ndots,omitempty"`
+
+	// Searches defines additional search domains for the DNS resolver in the Pods. Those will be added to the
+	// search domains of the Pod template.
+	// +kubebuilder:validation:MaxItems=32
+	Searches []string `json:"searches,omitempty"`
+
+	// Subdomain defines the subdomain of the Pods. This must be the name of a headless service that selects the
+	// Pods. If unset the headless service of the cluster will be used, if enabled. The subdomain is also used for the
+	// DNS names of the processes in the cluster file.
+	// +kubebuilder:validation:MaxLength=63
+	Subdomain *string `json:"subdomain,omitempty"`
+
+	// SetHostname defines if the hostname of the Pods should be set to the Pod name. The default is true if the Pods
+	// have a subdomain.
+	SetHostname *bool `json:"setHostname,omitempty"`
+}
+
+// VolumeClaimTemplateSelector defines the volume claim template for the process groups in a fault domain or node
+// pool.
+type VolumeClaimTemplateSelector struct {
+	// Name identifies the fault domain or node pool.
+	// +kubebuilder:validation:MaxLength=100
+	Name string `json:"name"`
+
+	// NodeSelector defines the labels of the nodes in the fault domain or node pool, e.g. the
+	// topology.kubernetes.io/zone label. The node selector is added to the Pods that use this entry.
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// VolumeClaimTemplate allows customizing the persistent volume claim for the Pods in the fault domain or node
+	// pool, e.g. to use a different storage class.
+	VolumeClaimTemplate *corev1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+}
+
+// getParameters returns the parameters of the process settings. If only the deprecated custom parameters are defined,
+// they will be converted into parameters.
+func (settings ProcessSettings) getParameters() FoundationDBParameters {
+	if settings.Parameters != nil {
+		return settings.Parameters
+	}
+
+	return settings.CustomParameters.ToParameters()
+}
+
+// parameterVariableReference matches a reference to an environment variable in a parameter.
+var parameterVariableReference = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// GetParameterEnvironmentVariables returns the environment variables of the process settings that are referenced in
+// the parameters with $NAME.
+func (settings ProcessSettings) GetParameterEnvironmentVariables() []corev1.EnvVar {
+	if len(settings.Env) == 0 {
+		return nil
+	}
+
+	referenced := map[string]None{}
+	for _, parameter := range settings.getParameters() {
+		for _, match := range parameterVariableReference.FindAllStringSubmatch(parameter.GetArgument(), -1) {
+			referenced[match[1]] = None{}
+		}
+	}
+
+	variables := make([]corev1.EnvVar, 0, len(referenced))
+	for _, env := range settings.Env {
+		if _, ok := referenced[env.Name]; ok {
+			variables = append(variables, env)
+		}
+	}
+
+	return variables
+}
+
+// getMergedParameters merges the parameters of the process class with the parameters of the general process settings.
+// The deprecated custom parameters of a process class replace the general parameters completely, to keep the behaviour
+// of older specs.
+func getMergedParameters(general ProcessSettings, entry ProcessSettings, hasEntry bool) FoundationDBParameters {
+	defaults := general.getParameters()
+	if !hasEntry {
+		return FoundationDBParameters(nil).Merge(defaults)
+	}
+
+	if entry.Parameters == nil && entry.CustomParameters != nil {
+		return entry.CustomParameters.ToParameters().Merge(nil)
+	}
+
+	return entry.Parameters.Merge(defaults)
 }
 
 // GetProcessSettings gets settings for a process.
@@ -1092,11 +2578,122 @@ func (cluster *FoundationDBCluster) GetProcessSettings(processClass ProcessClass
 		if merged.CustomParameters == nil {
 			merged.CustomParameters = entry.CustomParameters
 		}
+		if merged.Env == nil {
+			merged.Env = entry.Env
+		}
+		if merged.EnvFrom == nil {
+			merged.EnvFrom = entry.EnvFrom
+		}
+		if merged.AdditionalContainers == nil {
+			merged.AdditionalContainers = entry.AdditionalContainers
+		}
+		if merged.AdditionalInitContainers == nil {
+			merged.AdditionalInitContainers = entry.AdditionalInitContainers
+		}
+		if merged.DNS == nil {
+			merged.DNS = entry.DNS
+		}
+		if merged.HealthProbes == nil {
+			merged.HealthProbes = entry.HealthProbes
+		}
+		if merged.UseHostNetwork == nil {
+			merged.UseHostNetwork = entry.UseHostNetwork
+		}
+		if merged.PriorityClassName == "" {
+			merged.PriorityClassName = entry.PriorityClassName
+		}
+		if merged.VolumeClaimTemplateSelectors == nil {
+			merged.VolumeClaimTemplateSelectors = entry.VolumeClaimTemplateSelectors
+		}
+		if merged.Architecture == "" {
+			merged.Architecture = entry.Architecture
+		}
 	}
 
+	merged.Parameters = getMergedParameters(cluster.Spec.Processes[ProcessClassGeneral], entry, present && processClass != ProcessClassGeneral)
+
 	return merged
 }
 
+// GetVolumeClaimTemplateSelector returns the entry of the volumeClaimTemplateSelectors of the process class that is
+// recorded in the status of the process group or nil if no entry is recorded or the recorded entry doesn't exist.
+func (cluster *FoundationDBCluster) GetVolumeClaimTemplateSelector(processClass ProcessClass, processGroupID ProcessGroupID) *VolumeClaimTemplateSelector {
+	processGroup := FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+	if processGroup == nil {
+		return nil
+	}
+
+	return cluster.findVolumeClaimTemplateSelector(processClass, processGroup.VolumeClaimTemplateSelector)
+}
+
+// findVolumeClaimTemplateSelector returns the entry of the volumeClaimTemplateSelectors of the process class with the
+// provided name or nil if no such entry exists.
+func (cluster *FoundationDBCluster) findVolumeClaimTemplateSelector(processClass ProcessClass, name string) *VolumeClaimTemplateSelector {
+	if name == "" {
+		return nil
+	}
+
+	selectors := cluster.GetProcessSettings(processClass).VolumeClaimTemplateSelectors
+	for idx := range selectors {
+		if selectors[idx].Name == name {
+			return &selectors[idx]
+		}
+	}
+
+	return nil
+}
+
+// ChooseVolumeClaimTemplateSelector returns the name of the entry of the volumeClaimTemplateSelectors of the process
+// class that is used by the fewest process groups that are not marked for removal. If multiple entries are used by
+// the same number of process groups, the first of them is returned. If no entries are defined, an empty string is
+// returned.
+func (cluster *FoundationDBCluster) ChooseVolumeClaimTemplateSelector(processClass ProcessClass) string {
+	selectors := cluster.GetProcessSettings(processClass).VolumeClaimTemplateSelectors
+	if len(selectors) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int, len(selectors))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.ProcessClass != processClass || processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		counts[processGroup.VolumeClaimTemplateSelector]++
+	}
+
+	chosen := selectors[0].Name
+	for _, selector := range selectors[1:] {
+		if counts[selector.Name] < counts[chosen] {
+			chosen = selector.Name
+		}
+	}
+
+	return chosen
+}
+
+// NeedsVolumeClaimTemplateSelector returns true if the process group has no valid entry of the
+// volumeClaimTemplateSelectors of its process class recorded, but the process class defines entries.
+func (cluster *FoundationDBCluster) NeedsVolumeClaimTemplateSelector(processGroup *ProcessGroupStatus) bool {
+	if len(cluster.GetProcessSettings(processGroup.ProcessClass).VolumeClaimTemplateSelectors) == 0 {
+		return false
+	}
+
+	return cluster.findVolumeClaimTemplateSelector(processGroup.ProcessClass, processGroup.VolumeClaimTemplateSelector) == nil
+}
+
+// GetVolumeClaimTemplate returns the volume claim template for the process group. If the entry of the
+// volumeClaimTemplateSelectors of the process group doesn't define a template, the volumeClaimTemplate of the process
+// class will be used.
+func (cluster *FoundationDBCluster) GetVolumeClaimTemplate(processClass ProcessClass, processGroupID ProcessGroupID) *corev1.PersistentVolumeClaim {
+	selector := cluster.GetVolumeClaimTemplateSelector(processClass, processGroupID)
+	if selector != nil && selector.VolumeClaimTemplate != nil {
+		return selector.VolumeClaimTemplate
+	}
+
+	return cluster.GetProcessSettings(processClass).VolumeClaimTemplate
+}
+
 // GetRoleCountsWithDefaults gets the role counts from the cluster spec and
 // fills in default values for any role counts that are 0.
 //
@@ -1185,9 +2782,65 @@ func (cluster *FoundationDBCluster) calculateProcessCount(addFaultTolerance bool
 // will only reflect the number of Pods that will be created to host storage server processes.
 // If storageServersPerPod is set the total amount of storage server processes will be
 // the storage process count multiplied by storageServersPerPod.
+// If deriveProcessCounts is enabled, the storage, log and stateless counts from the spec
+// will only be used if they are greater than the counts derived from the database configuration.
 func (cluster *FoundationDBCluster) GetProcessCountsWithDefaults() (ProcessCounts, error) {
+	processCounts, err := cluster.getProcessCountsFromSpec()
+	if err != nil {
+		return processCounts, err
+	}
+
+	if cluster.IsStorageAutoscalingEnabled() && cluster.Status.StorageAutoscaling != nil && processCounts.Storage >= 0 {
+		if cluster.Status.StorageAutoscaling.StorageProcesses > processCounts.Storage {
+			processCounts.Storage = cluster.Status.StorageAutoscaling.StorageProcesses
+		}
+	}
+
+	return processCounts, nil
+}
+
+// getProcessCountsFromSpec returns the process counts based on the cluster spec.
+func (cluster *FoundationDBCluster) getProcessCountsFromSpec() (ProcessCounts, error) {
+	if !cluster.UseDerivedProcessCounts() {
+		return cluster.calculateProcessCounts(cluster.Spec.ProcessCounts.DeepCopy())
+	}
+
+	// Clear the positive counts from the spec to get the counts derived from the database configuration and
+	// use the counts from the spec only if they are greater than the derived counts.
+	specCounts := cluster.Spec.ProcessCounts
+	processCounts := specCounts.DeepCopy()
+	if processCounts.Storage > 0 {
+		processCounts.Storage = 0
+	}
+	if processCounts.Log > 0 {
+		processCounts.Log = 0
+	}
+	if processCounts.Stateless > 0 {
+		processCounts.Stateless = 0
+	}
+
+	derivedCounts, err := cluster.calculateProcessCounts(processCounts)
+	if err != nil {
+		return derivedCounts, err
+	}
+
+	if specCounts.Storage > derivedCounts.Storage {
+		derivedCounts.Storage = specCounts.Storage
+	}
+	if specCounts.Log > derivedCounts.Log {
+		derivedCounts.Log = specCounts.Log
+	}
+	if specCounts.Stateless > derivedCounts.Stateless {
+		derivedCounts.Stateless = specCounts.Stateless
+	}
+
+	return derivedCounts, nil
+}
+
+// calculateProcessCounts fills in the process counts that are not set in the provided process counts based on the
+// database configuration.
+func (cluster *FoundationDBCluster) calculateProcessCounts(processCounts *ProcessCounts) (ProcessCounts, error) {
 	roleCounts := cluster.GetRoleCountsWithDefaults()
-	processCounts := cluster.Spec.ProcessCounts.DeepCopy()
 
 	isSatellite := false
 	isMain := false
@@ -1259,6 +2912,20 @@ func (cluster *FoundationDBCluster) DesiredFaultTolerance() int {
 	return DesiredFaultTolerance(cluster.Spec.DatabaseConfiguration.RedundancyMode)
 }
 
+// HasDesiredFaultTolerance returns true if the fault tolerance recorded in the cluster status meets the desired
+// fault tolerance and the database was available. If no fault tolerance is recorded, false will be returned.
+func (cluster *FoundationDBCluster) HasDesiredFaultTolerance() bool {
+	faultTolerance := cluster.Status.FaultTolerance
+	if faultTolerance == nil || !cluster.Status.Health.Available {
+		return false
+	}
+
+	desiredFaultTolerance := cluster.DesiredFaultTolerance()
+
+	return faultTolerance.MaxZoneFailuresWithoutLosingData >= desiredFaultTolerance &&
+		faultTolerance.MaxZoneFailuresWithoutLosingAvailability >= desiredFaultTolerance
+}
+
 // MinimumFaultDomains returns the number of fault domains the cluster needs
 // to function.
 func (cluster *FoundationDBCluster) MinimumFaultDomains() int {
@@ -1286,6 +2953,10 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 	}
 
 	cluster.Status.Generations = ClusterGenerationStatus{Reconciled: cluster.Status.Generations.Reconciled}
+	if cluster.IsManagedConnectionOnly() {
+		return cluster.checkManagedConnectionReconciliation(logger), nil
+	}
+
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		if !processGroup.IsMarkedForRemoval() {
 			continue
@@ -1322,6 +2993,8 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 
 	cluster.Status.DesiredProcessGroups = desiredCounts.Total()
 	cluster.Status.ReconciledProcessGroups = 0
+	cluster.Status.StorageProcessGroups = currentCounts.Storage
+	cluster.Status.StorageSelector = cluster.GetStorageSelector()
 
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		if processGroup.IsMarkedForRemoval() {
@@ -1359,9 +3032,14 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 
 	desiredConfiguration := cluster.DesiredDatabaseConfiguration()
 	if !equality.Semantic.DeepEqual(cluster.Status.DatabaseConfiguration, desiredConfiguration) {
-		logger.Info("Pending database configuration change", "state", "NeedsConfigurationChange", "current", cluster.Status.DatabaseConfiguration, "desired", desiredConfiguration)
-		cluster.Status.Generations.NeedsConfigurationChange = cluster.ObjectMeta.Generation
-		reconciled = false
+		// A drift that the operator will not revert should not block the reconciliation.
+		if cluster.Status.DatabaseConfigurationDrift != nil && !cluster.ShouldRevertDatabaseConfigurationDrift() {
+			logger.Info("Database configuration drift will not be reverted", "state", "DatabaseConfigurationDrift", "current", cluster.Status.DatabaseConfiguration, "desired", desiredConfiguration, "mode", cluster.GetConfigureDatabaseMode())
+		} else {
+			logger.Info("Pending database configuration change", "state", "NeedsConfigurationChange", "current", cluster.Status.DatabaseConfiguration, "desired", desiredConfiguration)
+			cluster.Status.Generations.NeedsConfigurationChange = cluster.ObjectMeta.Generation
+			reconciled = false
+		}
 	}
 
 	if cluster.Status.HasIncorrectConfigMap {
@@ -1423,6 +3101,32 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 		}
 	}
 
+	if cluster.IsExternalMigrationInProgress() {
+		logger.Info("Pending migration from external cluster", "state", "NeedsExternalMigration", "phase", cluster.Status.MigrationPhase)
+		cluster.Status.Generations.NeedsExternalMigration = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	tenantsToCreate, tenantsToRemove := cluster.GetPendingTenantChanges()
+	if len(tenantsToCreate) > 0 || len(tenantsToRemove) > 0 {
+		logger.Info("Pending tenant changes", "state", "NeedsTenantChanges", "create", tenantsToCreate, "remove", tenantsToRemove)
+		cluster.Status.Generations.NeedsTenantChanges = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	pendingTagQuotas := cluster.GetPendingTagQuotas()
+	if len(pendingTagQuotas) > 0 {
+		logger.Info("Pending tag quota changes", "state", "NeedsTagQuotaChanges", "quotas", pendingTagQuotas)
+		cluster.Status.Generations.NeedsTagQuotaChanges = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	if cluster.NeedsDataDistributionModeChange() {
+		logger.Info("Pending data distribution mode change", "state", "NeedsDataDistributionModeChange", "enabled", *cluster.Spec.DataDistribution.Enabled)
+		cluster.Status.Generations.NeedsDataDistributionModeChange = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
 	if reconciled {
 		cluster.Status.Generations.Reconciled = cluster.ObjectMeta.Generation
 	} else if cluster.Status.Generations.Reconciled == cluster.ObjectMeta.Generation {
@@ -1432,6 +3136,32 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 	return reconciled, nil
 }
 
+// checkManagedConnectionReconciliation compares the spec and the status for a cluster that is not managed by the
+// operator. Only the settings that are under the control of the operator will be checked.
+func (cluster *FoundationDBCluster) checkManagedConnectionReconciliation(logger logr.Logger) bool {
+	reconciled := true
+
+	if !cluster.Status.Health.Available {
+		logger.Info("Database unavailable", "state", "DatabaseUnavailable")
+		cluster.Status.Generations.DatabaseUnavailable = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	if cluster.Status.HasIncorrectConfigMap {
+		logger.Info("Pending ConfigMap (client config) configuration change", "state", "NeedsMonitorConfUpdate")
+		cluster.Status.Generations.NeedsMonitorConfUpdate = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	if reconciled {
+		cluster.Status.Generations.Reconciled = cluster.ObjectMeta.Generation
+	} else if cluster.Status.Generations.Reconciled == cluster.ObjectMeta.Generation {
+		cluster.Status.Generations.Reconciled = 0
+	}
+
+	return reconciled
+}
+
 // GetStorageServersPerPod returns the StorageServer per Pod.
 func (cluster *FoundationDBCluster) GetStorageServersPerPod() int {
 	if cluster.Spec.StorageServersPerPod <= 1 {
@@ -1507,6 +3237,47 @@ func (str *ConnectionString) GenerateNewGenerationID() error {
 	return nil
 }
 
+// IsBeingCloned returns true if the cluster is created from VolumeSnapshots of another cluster and the cloned
+// database has not been available yet.
+func (cluster *FoundationDBCluster) IsBeingCloned() bool {
+	return cluster.Spec.CloneFrom != nil && !cluster.Status.Configured
+}
+
+// NeedsConnectionStringRotation returns true if the seed connection string was changed since it was last applied and
+// has a different description or generation ID than the current connection string.
+func (cluster *FoundationDBCluster) NeedsConnectionStringRotation() bool {
+	if cluster.Spec.SeedConnectionString == "" || cluster.Status.AppliedSeedConnectionString == "" || cluster.Status.ConnectionString == "" {
+		return false
+	}
+
+	if cluster.Spec.SeedConnectionString == cluster.Status.AppliedSeedConnectionString {
+		return false
+	}
+
+	seedConnectionString, err := ParseConnectionString(cluster.Spec.SeedConnectionString)
+	if err != nil {
+		return false
+	}
+
+	currentConnectionString, err := ParseConnectionString(cluster.Status.ConnectionString)
+	if err != nil {
+		return false
+	}
+
+	return seedConnectionString.DatabaseName != currentConnectionString.DatabaseName || seedConnectionString.GenerationID != currentConnectionString.GenerationID
+}
+
+// GetDesiredConnectionString returns the connection string that should be written into the cluster file of the pods.
+// During a rotation of the connection string this will be the seed connection string, otherwise the current
+// connection string.
+func (cluster *FoundationDBCluster) GetDesiredConnectionString() string {
+	if cluster.NeedsConnectionStringRotation() {
+		return cluster.Spec.SeedConnectionString
+	}
+
+	return cluster.Status.ConnectionString
+}
+
 // GetFullAddress gets the full public address we should use for a process.
 // This will include the IP address, the port, and any additional flags.
 func (cluster *FoundationDBCluster) GetFullAddress(address string, processNumber int) ProcessAddress {
@@ -1536,6 +3307,18 @@ func (cluster *FoundationDBCluster) GetFullAddressList(address string, primaryOn
 		cluster.Status.RequiredAddresses.NonTLS)
 }
 
+// GetFullAddressForProcessClass gets the full public address we should use for a process of the provided process
+// class. This will take the port range of process classes that use the host network into account.
+func (cluster *FoundationDBCluster) GetFullAddressForProcessClass(address string, processNumber int, processClass ProcessClass) ProcessAddress {
+	return cluster.GetFullAddress(address, processNumber+cluster.GetProcessPortOffset(processClass))
+}
+
+// GetFullAddressListForProcessClass gets the full list of public addresses we should use for a process of the
+// provided process class. This will take the port range of process classes that use the host network into account.
+func (cluster *FoundationDBCluster) GetFullAddressListForProcessClass(address string, primaryOnly bool, processNumber int, processClass ProcessClass) []ProcessAddress {
+	return cluster.GetFullAddressList(address, primaryOnly, processNumber+cluster.GetProcessPortOffset(processClass))
+}
+
 // HasCoordinators checks whether this connection string matches a set of
 // coordinators.
 func (str *ConnectionString) HasCoordinators(coordinators []ProcessAddress) bool {
@@ -1563,6 +3346,19 @@ func (str *ConnectionString) HasCoordinators(coordinators []ProcessAddress) bool
 	return true
 }
 
+// FaultDomainToDecommission describes a fault domain that should be retired.
+type FaultDomainToDecommission struct {
+	// Key defines the locality key that identifies the fault domain. This can
+	// be zoneid or dcid.
+	// +kubebuilder:validation:Enum=zoneid;dcid
+	// +kubebuilder:default:=zoneid
+	Key string `json:"key,omitempty"`
+
+	// Value defines the value of the locality for the fault domain.
+	// +kubebuilder:validation:MaxLength=512
+	Value string `json:"value"`
+}
+
 // FoundationDBClusterFaultDomain describes the fault domain that a cluster is
 // replicated across.
 type FoundationDBClusterFaultDomain struct {
@@ -1642,8 +3438,15 @@ func (cluster *FoundationDBCluster) DesiredDatabaseConfiguration() DatabaseConfi
 // set in the configuration in the cluster spec.
 //
 // This allows us to compare the spec to the live configuration while ignoring
-// version flags that are unset in the spec.
+// version flags that are unset in the spec. The same applies to the perpetual
+// storage wiggle settings.
 func (cluster *FoundationDBCluster) ClearMissingVersionFlags(configuration *DatabaseConfiguration) {
+	if cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggle == nil {
+		configuration.PerpetualStorageWiggle = nil
+	}
+	if cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggleLocality == "" {
+		configuration.PerpetualStorageWiggleLocality = ""
+	}
 	if cluster.Spec.DatabaseConfiguration.LogVersion == 0 {
 		configuration.LogVersion = 0
 	}
@@ -1657,6 +3460,18 @@ func (cluster *FoundationDBCluster) IsBeingUpgraded() bool {
 	return cluster.Status.RunningVersion != "" && cluster.Status.RunningVersion != cluster.Spec.Version
 }
 
+// IsBeingDowngraded determines whether the cluster has a pending downgrade to an older version.
+func (cluster *FoundationDBCluster) IsBeingDowngraded() bool {
+	if !cluster.IsBeingUpgraded() {
+		return false
+	}
+
+	runningVersion, _ := ParseFdbVersion(cluster.Status.RunningVersion)
+	desiredVersion, _ := ParseFdbVersion(cluster.Spec.Version)
+
+	return !desiredVersion.IsAtLeast(runningVersion)
+}
+
 // IsBeingUpgradedWithVersionIncompatibleVersion determines whether the cluster has a pending upgrade to a version incompatible version.
 func (cluster *FoundationDBCluster) IsBeingUpgradedWithVersionIncompatibleVersion() bool {
 	if !cluster.IsBeingUpgraded() {
@@ -1682,6 +3497,33 @@ func (cluster *FoundationDBCluster) VersionCompatibleUpgradeInProgress() bool {
 	return runningVersion.IsProtocolCompatible(desiredVersion)
 }
 
+// GetProcessGroupPin returns the node or zone the process group is pinned to or nil if the process group is not
+// pinned. A process group that replaces a pinned process group inherits its pin.
+func (cluster *FoundationDBCluster) GetProcessGroupPin(processGroupID ProcessGroupID) *ProcessGroupPin {
+	pin, ok := cluster.Spec.PinnedProcessGroups[cluster.GetPinnedProcessGroupID(processGroupID)]
+	if !ok {
+		return nil
+	}
+
+	return &pin
+}
+
+// GetPinnedProcessGroupID returns the ID of the pinned process group whose pin applies to the process group. This is
+// the process group that was replaced by the process group or the process group itself.
+func (cluster *FoundationDBCluster) GetPinnedProcessGroupID(processGroupID ProcessGroupID) ProcessGroupID {
+	processGroup := FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+	if processGroup != nil && processGroup.PinnedFrom != "" {
+		return processGroup.PinnedFrom
+	}
+
+	return processGroupID
+}
+
+// IsProcessGroupPinned returns true if the process group is pinned to a node or a zone.
+func (cluster *FoundationDBCluster) IsProcessGroupPinned(processGroupID ProcessGroupID) bool {
+	return cluster.GetProcessGroupPin(processGroupID) != nil
+}
+
 // ProcessGroupIsBeingRemoved determines if an instance is pending removal.
 func (cluster *FoundationDBCluster) ProcessGroupIsBeingRemoved(processGroupID ProcessGroupID) bool {
 	if processGroupID == "" {
@@ -1748,7 +3590,34 @@ func (cluster *FoundationDBCluster) GetLockID() string {
 // NeedsExplicitListenAddress determines whether we pass a listen address
 // parameter to fdbserver.
 func (cluster *FoundationDBCluster) NeedsExplicitListenAddress() bool {
-	return cluster.GetPublicIPSource() == PublicIPSourceService || cluster.GetUseExplicitListenAddress()
+	return cluster.GetPublicIPSource() != PublicIPSourcePod || cluster.GetUseExplicitListenAddress()
+}
+
+// IsExternalAccessEnabled returns true if the coordinators should be exposed
+// outside of the Kubernetes cluster.
+func (cluster *FoundationDBCluster) IsExternalAccessEnabled() bool {
+	if cluster.Spec.Routing.ExternalAccess == nil {
+		return false
+	}
+
+	return pointer.BoolDeref(cluster.Spec.Routing.ExternalAccess.Enabled, false)
+}
+
+// GetExternalAccessServiceType returns the type of the services for the
+// external access to the coordinators. Defaults to LoadBalancer.
+func (cluster *FoundationDBCluster) GetExternalAccessServiceType() corev1.ServiceType {
+	if cluster.Spec.Routing.ExternalAccess == nil || cluster.Spec.Routing.ExternalAccess.ServiceType == nil {
+		return corev1.ServiceTypeLoadBalancer
+	}
+
+	return *cluster.Spec.Routing.ExternalAccess.ServiceType
+}
+
+// UsePublicIPFromService determines whether the public IP of the processes is
+// provided by a Service for every process group.
+func (cluster *FoundationDBCluster) UsePublicIPFromService() bool {
+	source := cluster.GetPublicIPSource()
+	return source == PublicIPSourceService || source == PublicIPSourceLoadBalancer
 }
 
 // GetPublicIPSource returns the set PublicIPSource or the default PublicIPSourcePod
@@ -1798,7 +3667,10 @@ type RoutingConfig struct {
 	// PublicIPSource specifies what source a process should use to get its
 	// public IPs.
 	//
-	// This supports the values `pod` and `service`.
+	// This supports the values `pod`, `service`, `node` and `loadBalancer`.
+	// For all sources except `pod` the processes will listen on the Pod IP
+	// and advertise the public IP to the other processes and the clients.
+	// +kubebuilder:validation:Enum=pod;service;node;loadBalancer
 	PublicIPSource *PublicIPSource `json:"publicIPSource,omitempty"`
 
 	// PodIPFamily tells the pod which family of IP addresses to use.
@@ -1825,6 +3697,30 @@ type RoutingConfig struct {
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=253
 	DNSDomain *string `json:"dnsDomain,omitempty"`
+
+	// ExternalAccess defines the services that expose the coordinators outside
+	// of the Kubernetes cluster. This only exposes the coordinators, clients
+	// outside of the Kubernetes cluster must be able to reach every process, see
+	// PublicIPSource.
+	ExternalAccess *ExternalAccessConfig `json:"externalAccess,omitempty"`
+}
+
+// ExternalAccessConfig defines how the coordinators are exposed outside of the
+// Kubernetes cluster.
+type ExternalAccessConfig struct {
+	// Enabled defines if the operator creates a service for every coordinator
+	// and provides the external addresses of those services in the status.
+	// Default: false
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// ServiceType defines the type of the services for the coordinators.
+	// Default: LoadBalancer
+	// +kubebuilder:validation:Enum=LoadBalancer;NodePort
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// Annotations defines additional annotations for the services of the
+	// coordinators, e.g. to configure the load balancer of the cloud provider.
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // RequiredAddressSet provides settings for which addresses we need to listen
@@ -1923,6 +3819,14 @@ const (
 
 	// PublicIPSourceService specifies that a pod gets its IP from a service.
 	PublicIPSourceService PublicIPSource = "service"
+
+	// PublicIPSourceNode specifies that a pod gets its IP from the node it is
+	// running on. The ports of the processes will be exposed as host ports.
+	PublicIPSourceNode PublicIPSource = "node"
+
+	// PublicIPSourceLoadBalancer specifies that a pod gets its IP from a
+	// service with the LoadBalancer type.
+	PublicIPSourceLoadBalancer PublicIPSource = "loadBalancer"
 )
 
 // AddStorageServerPerDisk adds serverPerDisk to the status field to keep track which ConfigMaps should be kept
@@ -2028,6 +3932,15 @@ func (cluster *FoundationDBCluster) GetIgnoreMissingProcessesSeconds() time.Dura
 	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.IgnoreMissingProcessesSeconds, 30)) * time.Second
 }
 
+// GetCommandTimeout returns the value of CommandTimeoutSeconds or the provided default timeout if unset.
+func (cluster *FoundationDBCluster) GetCommandTimeout(defaultTimeout time.Duration) time.Duration {
+	if cluster.Spec.AutomationOptions.CommandTimeoutSeconds == nil {
+		return defaultTimeout
+	}
+
+	return time.Duration(*cluster.Spec.AutomationOptions.CommandTimeoutSeconds) * time.Second
+}
+
 // GetFailedPodDuration returns the value of FailedPodDuration or 5 minutes if unset.
 func (cluster *FoundationDBCluster) GetFailedPodDuration() time.Duration {
 	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.FailedPodDurationSeconds, 300)) * time.Second
@@ -2049,6 +3962,12 @@ func (cluster *FoundationDBCluster) UseLocalitiesForExclusion() bool {
 	return fdbVersion.IsAtLeast(Versions.NextMajorVersion) && pointer.BoolDeref(cluster.Spec.AutomationOptions.UseLocalitiesForExclusion, false)
 }
 
+// ShouldIncludeReusedAddresses returns true if the operator should include the addresses it excluded itself once they
+// are used by an active process group again.
+func (cluster *FoundationDBCluster) ShouldIncludeReusedAddresses() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.IncludeReusedAddresses, true)
+}
+
 // GetProcessClassLabel provides the label that this cluster is using for the
 // process class when identifying resources.
 func (cluster *FoundationDBCluster) GetProcessClassLabel() string {
@@ -2074,6 +3993,232 @@ func (cluster *FoundationDBCluster) GetMaxConcurrentReplacements() int {
 	return pointer.IntDeref(cluster.Spec.AutomationOptions.MaxConcurrentReplacements, math.MaxInt64)
 }
 
+// IsRecoveryFreezeEnabled returns true if the operator should defer destructive actions during recoveries.
+func (cluster *FoundationDBCluster) IsRecoveryFreezeEnabled() bool {
+	return cluster.Spec.AutomationOptions.RecoveryFreezeSeconds != nil
+}
+
+// GetRecoveryFreezeTime returns the value of RecoveryFreezeSeconds as duration or 0 if unset.
+func (cluster *FoundationDBCluster) GetRecoveryFreezeTime() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.RecoveryFreezeSeconds, 0)) * time.Second
+}
+
+// GetProcessArchitecture returns the CPU architecture of the Pods of the process class. If the process settings don't
+// define an architecture and the image configs of the main and the sidecar container only define images for a single
+// architecture, this architecture is returned.
+func (cluster *FoundationDBCluster) GetProcessArchitecture(processClass ProcessClass) string {
+	architecture := cluster.GetProcessSettings(processClass).Architecture
+	if architecture != "" {
+		return architecture
+	}
+
+	architectures := GetImageConfigArchitectures(cluster.Spec.MainContainer.ImageConfigs, cluster.Spec.SidecarContainer.ImageConfigs)
+	if len(architectures) == 1 {
+		return architectures[0]
+	}
+
+	return ""
+}
+
+// GetMaxRecoveryFreezeTime returns the value of MaxRecoveryFreezeSeconds as duration or defaults to 1h.
+func (cluster *FoundationDBCluster) GetMaxRecoveryFreezeTime() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.MaxRecoveryFreezeSeconds, 3600)) * time.Second
+}
+
+// GetSettleTime returns the value of SettleTimeSeconds as duration or 0 if unset.
+func (cluster *FoundationDBCluster) GetSettleTime() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.SettleTimeSeconds, 0)) * time.Second
+}
+
+// GetMaxActionsPerReconcile returns the value of ActionBudget.MaxActionsPerReconcile or 0 if unset, which means
+// no limit.
+func (cluster *FoundationDBCluster) GetMaxActionsPerReconcile() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile, 0)
+}
+
+// GetMaxActionsPerHour returns the value of ActionBudget.MaxActionsPerHour or 0 if unset, which means no limit.
+func (cluster *FoundationDBCluster) GetMaxActionsPerHour() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour, 0)
+}
+
+// ClusterFileVerificationEnabled returns the value of ClusterFileVerificationOptions.Enabled or false if unset.
+func (cluster *FoundationDBCluster) ClusterFileVerificationEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.ClusterFileVerificationOptions.Enabled, false)
+}
+
+// GetClusterFileVerificationInterval returns the value of ClusterFileVerificationOptions.IntervalSeconds as duration
+// or 10 minutes if unset.
+func (cluster *FoundationDBCluster) GetClusterFileVerificationInterval() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.ClusterFileVerificationOptions.IntervalSeconds, 600)) * time.Second
+}
+
+// FixIncorrectClusterFiles returns the value of ClusterFileVerificationOptions.FixIncorrectClusterFiles or false if
+// unset.
+func (cluster *FoundationDBCluster) FixIncorrectClusterFiles() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.ClusterFileVerificationOptions.FixIncorrectClusterFiles, false)
+}
+
+// ProxyDrainingEnabled returns the value of ProxyDrainingOptions.Enabled or false if unset.
+func (cluster *FoundationDBCluster) ProxyDrainingEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.ProxyDrainingOptions.Enabled, false)
+}
+
+// GetProxyDrainingDelay returns the value of ProxyDrainingOptions.DelaySeconds as duration or 10 seconds if unset.
+func (cluster *FoundationDBCluster) GetProxyDrainingDelay() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.ProxyDrainingOptions.DelaySeconds, 10)) * time.Second
+}
+
+// IsBounceScheduleEnabled returns true if the operator should defer bounces until the load of the cluster is low or
+// until a bounce window.
+func (cluster *FoundationDBCluster) IsBounceScheduleEnabled() bool {
+	options := cluster.Spec.AutomationOptions.BounceScheduleOptions
+	return options.MaxOperationsPerSecond != nil || len(options.Windows) > 0
+}
+
+// LatencyProbesEnabled returns the value of LatencyProbeOptions.Enabled or false if unset.
+func (cluster *FoundationDBCluster) LatencyProbesEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.LatencyProbeOptions.Enabled, false)
+}
+
+// GetLatencyProbeInterval returns the value of LatencyProbeOptions.IntervalSeconds as duration or 30 seconds if unset.
+func (cluster *FoundationDBCluster) GetLatencyProbeInterval() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.LatencyProbeOptions.IntervalSeconds, 30)) * time.Second
+}
+
+// TLSCertificateRotationEnabled returns true if the operator should update the Pods to use renewed certificates.
+func (cluster *FoundationDBCluster) TLSCertificateRotationEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.TLSCertificateRotationOptions.Enabled, false)
+}
+
+// GetTLSCertificateRotationWindow returns the value of TLSCertificateRotationOptions.RotationWindowSeconds as
+// duration or 7 days if unset.
+func (cluster *FoundationDBCluster) GetTLSCertificateRotationWindow() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.TLSCertificateRotationOptions.RotationWindowSeconds, 604800)) * time.Second
+}
+
+// AuthorizationEnabled returns true if public keys for the token based authorization are defined.
+func (cluster *FoundationDBCluster) AuthorizationEnabled() bool {
+	return cluster.Spec.Authorization != nil && len(cluster.Spec.Authorization.PublicKeySecrets) > 0
+}
+
+// GetAuthorizationSecretName returns the name of the secret that contains the merged public keys for the token based
+// authorization.
+func (cluster *FoundationDBCluster) GetAuthorizationSecretName() string {
+	return fmt.Sprintf("%s-authorization", cluster.Name)
+}
+
+// UsesTraceEventReceiver returns true if the trace log forwarders should send severe trace events to the trace
+// event receiver of the operator.
+func (cluster *FoundationDBCluster) UsesTraceEventReceiver() bool {
+	return cluster.Spec.TraceLogs != nil && cluster.Spec.TraceLogs.EventReceiverAddress != ""
+}
+
+// GetTraceEventReceiverSecretName returns the name of the secret that contains the token that the trace log forwarders
+// use to authenticate against the trace event receiver of the operator.
+func (cluster *FoundationDBCluster) GetTraceEventReceiverSecretName() string {
+	return fmt.Sprintf("%s-trace-event-receiver", cluster.Name)
+}
+
+// CertManagerEnabled returns true if the operator requests the certificates of the processes from cert-manager.
+func (cluster *FoundationDBCluster) CertManagerEnabled() bool {
+	return cluster.Spec.CertManager != nil
+}
+
+// GetCertificateScope returns the scope of the certificates that are requested from cert-manager or Cluster if unset.
+func (cluster *FoundationDBCluster) GetCertificateScope() CertificateScope {
+	if cluster.Spec.CertManager == nil || cluster.Spec.CertManager.Scope == nil {
+		return CertificateScopeCluster
+	}
+
+	return *cluster.Spec.CertManager.Scope
+}
+
+// GetCertificateName returns the name of the certificate and of the secret that contains the certificate for the
+// process group. The name only depends on the process group if the certificates have the ProcessGroup scope.
+func (cluster *FoundationDBCluster) GetCertificateName(processGroupID ProcessGroupID) string {
+	if cluster.GetCertificateScope() == CertificateScopeProcessGroup {
+		return fmt.Sprintf("%s-%s-tls", cluster.Name, processGroupID)
+	}
+
+	return fmt.Sprintf("%s-tls", cluster.Name)
+}
+
+// StatusReportEnabled returns the value of StatusReportOptions.Enabled or false if unset.
+func (cluster *FoundationDBCluster) StatusReportEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.StatusReportOptions.Enabled, false)
+}
+
+// GetStatusReportInterval returns the value of StatusReportOptions.IntervalSeconds as duration or 60 seconds if unset.
+func (cluster *FoundationDBCluster) GetStatusReportInterval() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.StatusReportOptions.IntervalSeconds, 60)) * time.Second
+}
+
+// GetReconciliationBlockedNotificationThreshold returns the value of
+// Notifications.ReconciliationBlockedThresholdSeconds as duration or 1 hour if unset.
+func (cluster *FoundationDBCluster) GetReconciliationBlockedNotificationThreshold() time.Duration {
+	if cluster.Spec.Notifications == nil {
+		return time.Hour
+	}
+
+	return time.Duration(pointer.IntDeref(cluster.Spec.Notifications.ReconciliationBlockedThresholdSeconds, 3600)) * time.Second
+}
+
+// GetActionHistoryLimit returns the value of AutomationOptions.ActionHistoryLimit or 20 if unset.
+func (cluster *FoundationDBCluster) GetActionHistoryLimit() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.ActionHistoryLimit, 20)
+}
+
+// GetMaxCrashReports returns the value of CrashCollection.MaxCrashReports or 10 if unset.
+func (cluster *FoundationDBCluster) GetMaxCrashReports() int {
+	if cluster.Spec.CrashCollection == nil {
+		return 10
+	}
+
+	return pointer.IntDeref(cluster.Spec.CrashCollection.MaxCrashReports, 10)
+}
+
+// GetCrashReportRetention returns the value of CrashCollection.RetentionSeconds as duration or 7 days if unset.
+func (cluster *FoundationDBCluster) GetCrashReportRetention() time.Duration {
+	if cluster.Spec.CrashCollection == nil {
+		return 7 * 24 * time.Hour
+	}
+
+	return time.Duration(pointer.IntDeref(cluster.Spec.CrashCollection.RetentionSeconds, 604800)) * time.Second
+}
+
+// GetMaxIncompatibleClientsForUpgrade returns the value of MaxIncompatibleClientsForUpgrade or 0 if unset.
+func (cluster *FoundationDBCluster) GetMaxIncompatibleClientsForUpgrade() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.MaxIncompatibleClientsForUpgrade, 0)
+}
+
+// GetDecommissionBatchSize returns the value of DecommissionBatchSize or 1 if unset.
+func (cluster *FoundationDBCluster) GetDecommissionBatchSize() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.DecommissionBatchSize, 1)
+}
+
+// IsFaultDomainDecommissioned returns true if the provided localities are part of a fault domain
+// that should be decommissioned.
+func (cluster *FoundationDBCluster) IsFaultDomainDecommissioned(localities map[string]string) bool {
+	for _, faultDomain := range cluster.Spec.FaultDomainsToDecommission {
+		key := faultDomain.Key
+		if key == "" {
+			key = FDBLocalityZoneIDKey
+		}
+
+		value, ok := localities[key]
+		if ok && value == faultDomain.Value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDryRun returns the value of DryRun or false if unset.
+func (cluster *FoundationDBCluster) IsDryRun() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.DryRun, false)
+}
+
 // UseManagementAPI returns the value of UseManagementAPI or false if unset.
 func (cluster *FoundationDBCluster) UseManagementAPI() bool {
 	return pointer.BoolDeref(cluster.Spec.AutomationOptions.UseManagementAPI, false)
@@ -2100,15 +4245,42 @@ func (cluster *FoundationDBCluster) NeedsHeadlessService() bool {
 }
 
 // UseDNSInClusterFile determines whether we need to use DNS entries in the
-// cluster file for this cluster.
+// cluster file for this cluster. DNS entries will only be used once the
+// running version supports them.
 func (cluster *FoundationDBCluster) UseDNSInClusterFile() bool {
-	return pointer.BoolDeref(cluster.Spec.Routing.UseDNSInClusterFile, false)
+	if !pointer.BoolDeref(cluster.Spec.Routing.UseDNSInClusterFile, false) {
+		return false
+	}
+
+	version, err := ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil {
+		return true
+	}
+
+	return version.SupportsDNSInClusterFile()
 }
 
 // DefineDNSLocalityFields determines whether we need to put DNS entries in the
 // pod spec and process locality.
 func (cluster *FoundationDBCluster) DefineDNSLocalityFields() bool {
-	return pointer.BoolDeref(cluster.Spec.Routing.DefineDNSLocalityFields, false) || cluster.UseDNSInClusterFile()
+	return pointer.BoolDeref(cluster.Spec.Routing.DefineDNSLocalityFields, false) || pointer.BoolDeref(cluster.Spec.Routing.UseDNSInClusterFile, false)
+}
+
+// GetUnsupportedFeatures returns the features defined in the spec that are not
+// supported by the provided version. Those features are ignored by the operator
+// until the cluster runs a version that supports them.
+func (cluster *FoundationDBCluster) GetUnsupportedFeatures(version Version) []string {
+	var features []string
+
+	if pointer.BoolDeref(cluster.Spec.Routing.UseDNSInClusterFile, false) && !version.SupportsDNSInClusterFile() {
+		features = append(features, fmt.Sprintf("DNS names in the cluster file are not supported on version %s, IP addresses will be used", version))
+	}
+
+	if cluster.Spec.DatabaseConfiguration.AreSeparatedProxiesConfigured() && !version.HasSeparatedProxies() {
+		features = append(features, fmt.Sprintf("grv and commit proxies are not supported on version %s, proxies will be used", version))
+	}
+
+	return features
 }
 
 // GetDNSDomain gets the domain used when forming DNS names generated for a
@@ -2126,6 +4298,70 @@ func (cluster *FoundationDBCluster) GetRemovalMode() PodUpdateMode {
 	return cluster.Spec.AutomationOptions.DeletionMode
 }
 
+// GetExclusionTimeout returns the duration after which the exclusion of a process group that is marked for removal
+// times out or 0 if the timeout is disabled.
+func (cluster *FoundationDBCluster) GetExclusionTimeout() time.Duration {
+	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.ExclusionTimeoutSeconds, 0)) * time.Second
+}
+
+// GetForceRemovalOnExclusionTimeout returns cluster.Spec.AutomationOptions.ForceRemovalOnExclusionTimeout or if unset
+// the default false
+func (cluster *FoundationDBCluster) GetForceRemovalOnExclusionTimeout() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.ForceRemovalOnExclusionTimeout, false)
+}
+
+// GetProcessGroupTombstoneSeconds returns how long the tombstone of a removed process group is kept, defaults to 3600.
+func (cluster *FoundationDBCluster) GetProcessGroupTombstoneSeconds() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.ProcessGroupTombstoneSeconds, 3600)
+}
+
+// AddProcessGroupTombstone adds a tombstone for the removed process group. An existing tombstone for the same process
+// group ID will be refreshed.
+func (cluster *FoundationDBCluster) AddProcessGroupTombstone(processGroupID ProcessGroupID) {
+	now := metav1.Now()
+	for idx, tombstone := range cluster.Status.ProcessGroupTombstones {
+		if tombstone.ProcessGroupID == processGroupID {
+			cluster.Status.ProcessGroupTombstones[idx].RemovalTimestamp = &now
+			return
+		}
+	}
+
+	cluster.Status.ProcessGroupTombstones = append(cluster.Status.ProcessGroupTombstones, ProcessGroupTombstone{
+		ProcessGroupID:   processGroupID,
+		RemovalTimestamp: &now,
+	})
+}
+
+// GetActiveProcessGroupTombstones returns the tombstones of the cluster status that haven't expired yet.
+func (cluster *FoundationDBCluster) GetActiveProcessGroupTombstones() []ProcessGroupTombstone {
+	if len(cluster.Status.ProcessGroupTombstones) == 0 {
+		return nil
+	}
+
+	expiration := time.Now().Add(-time.Duration(cluster.GetProcessGroupTombstoneSeconds()) * time.Second)
+	tombstones := make([]ProcessGroupTombstone, 0, len(cluster.Status.ProcessGroupTombstones))
+	for _, tombstone := range cluster.Status.ProcessGroupTombstones {
+		if tombstone.RemovalTimestamp == nil || tombstone.RemovalTimestamp.Time.Before(expiration) {
+			continue
+		}
+
+		tombstones = append(tombstones, tombstone)
+	}
+
+	return tombstones
+}
+
+// HasProcessGroupTombstone returns true if the cluster has an active tombstone for the process group ID.
+func (cluster *FoundationDBCluster) HasProcessGroupTombstone(processGroupID ProcessGroupID) bool {
+	for _, tombstone := range cluster.GetActiveProcessGroupTombstones() {
+		if tombstone.ProcessGroupID == processGroupID {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetWaitBetweenRemovalsSeconds returns the WaitDurationBetweenRemovals if set or defaults to 60s.
 func (cluster *FoundationDBCluster) GetWaitBetweenRemovalsSeconds() int {
 	duration := pointer.IntDeref(cluster.Spec.AutomationOptions.WaitBetweenRemovalsSeconds, -1)
@@ -2136,11 +4372,50 @@ func (cluster *FoundationDBCluster) GetWaitBetweenRemovalsSeconds() int {
 	return duration
 }
 
+// GetStorageWiggleWaitSeconds returns the StorageWiggleWaitSeconds if set or defaults to 600s.
+func (cluster *FoundationDBCluster) GetStorageWiggleWaitSeconds() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.StorageWiggleWaitSeconds, 600)
+}
+
 // UseMaintenaceMode returns true if UseMaintenanceModeChecker is set.
 func (cluster *FoundationDBCluster) UseMaintenaceMode() bool {
 	return pointer.BoolDeref(cluster.Spec.AutomationOptions.MaintenanceModeOptions.UseMaintenanceModeChecker, false)
 }
 
+// GetTraceLogDirectory returns the directory for the trace logs of the fdbserver processes.
+func (cluster *FoundationDBCluster) GetTraceLogDirectory() string {
+	if cluster.Spec.TraceLogs == nil || cluster.Spec.TraceLogs.Directory == "" {
+		return DefaultTraceLogDirectory
+	}
+
+	return cluster.Spec.TraceLogs.Directory
+}
+
+// GetTraceLogFormat returns the format of the trace log files.
+func (cluster *FoundationDBCluster) GetTraceLogFormat() TraceLogFormat {
+	if cluster.Spec.TraceLogs == nil || cluster.Spec.TraceLogs.Format == "" {
+		return TraceLogFormatXML
+	}
+
+	return cluster.Spec.TraceLogs.Format
+}
+
+// UseInPlacePodResize returns true if Pods should be resized in place when only their resource requirements have
+// changed.
+func (cluster *FoundationDBCluster) UseInPlacePodResize() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.InPlacePodResize, false)
+}
+
+// UseRecreatePodsForSchedulingChanges returns the value of RecreatePodsForSchedulingChanges or false if unset.
+func (cluster *FoundationDBCluster) UseRecreatePodsForSchedulingChanges() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.RecreatePodsForSchedulingChanges, false)
+}
+
+// UseWorkJournal returns the value of UseWorkJournal or false if unset.
+func (cluster *FoundationDBCluster) UseWorkJournal() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.UseWorkJournal, false)
+}
+
 // GetMaintenaceModeTimeoutSeconds returns the timeout for maintenance zone after which it will be reset.
 func (cluster *FoundationDBCluster) GetMaintenaceModeTimeoutSeconds() int {
 	return pointer.IntDeref(cluster.Spec.AutomationOptions.MaintenanceModeOptions.MaintenanceModeTimeSeconds, 600)
@@ -2158,6 +4433,66 @@ const (
 	PodUpdateStrategyDelete PodUpdateStrategy = "Delete"
 )
 
+// ConfigureDatabaseMode defines how the operator handles changes to the database configuration that were made
+// outside of the operator.
+type ConfigureDatabaseMode string
+
+const (
+	// ConfigureDatabaseModeAlways reverts all database configuration changes made outside of the operator.
+	ConfigureDatabaseModeAlways ConfigureDatabaseMode = "Always"
+	// ConfigureDatabaseModeIfSafe reverts database configuration changes made outside of the operator only if the
+	// reversion doesn't change the redundancy mode, the storage engine or the regions.
+	ConfigureDatabaseModeIfSafe ConfigureDatabaseMode = "IfSafe"
+	// ConfigureDatabaseModeNever never reverts database configuration changes made outside of the operator.
+	ConfigureDatabaseModeNever ConfigureDatabaseMode = "Never"
+)
+
+// GetCompatibilityMode returns the CompatibilityMode of the cluster or CompatibilityModeKubernetes if unset.
+func (cluster *FoundationDBCluster) GetCompatibilityMode() CompatibilityMode {
+	if cluster.Spec.CompatibilityMode == "" {
+		return CompatibilityModeKubernetes
+	}
+
+	return cluster.Spec.CompatibilityMode
+}
+
+// GetOperatorConfigName returns the name of the FoundationDBOperatorConfig that provides the defaults for the cluster.
+func (cluster *FoundationDBCluster) GetOperatorConfigName() string {
+	if cluster.Spec.OperatorConfigName == "" {
+		return DefaultOperatorConfigName
+	}
+
+	return cluster.Spec.OperatorConfigName
+}
+
+// GetConfigureDatabaseMode returns the ConfigureDatabaseMode of the cluster or ConfigureDatabaseModeAlways if unset.
+func (cluster *FoundationDBCluster) GetConfigureDatabaseMode() ConfigureDatabaseMode {
+	if cluster.Spec.AutomationOptions.ConfigureDatabaseMode == "" {
+		return ConfigureDatabaseModeAlways
+	}
+
+	return cluster.Spec.AutomationOptions.ConfigureDatabaseMode
+}
+
+// ShouldRevertDatabaseConfigurationDrift returns true if the operator should revert the running database
+// configuration to the desired database configuration when a drift was detected.
+func (cluster *FoundationDBCluster) ShouldRevertDatabaseConfigurationDrift() bool {
+	switch cluster.GetConfigureDatabaseMode() {
+	case ConfigureDatabaseModeNever:
+		return false
+	case ConfigureDatabaseModeIfSafe:
+		current := cluster.Status.DatabaseConfiguration
+		desired := cluster.DesiredDatabaseConfiguration()
+
+		return current.RedundancyMode == desired.RedundancyMode &&
+			current.StorageEngine == desired.StorageEngine &&
+			current.UsableRegions == desired.UsableRegions &&
+			equality.Semantic.DeepEqual(current.Regions, desired.Regions)
+	default:
+		return true
+	}
+}
+
 // NeedsReplacement returns true if the Pod should be replaced if the Pod spec has changed
 func (cluster *FoundationDBCluster) NeedsReplacement(processGroup *ProcessGroupStatus) bool {
 	if cluster.Spec.AutomationOptions.PodUpdateStrategy == PodUpdateStrategyDelete {
@@ -2212,6 +4547,17 @@ func (cluster *FoundationDBCluster) GetMatchLabels() map[string]string {
 	}
 }
 
+// GetStorageSelector returns the label selector for the Pods of the storage process groups.
+func (cluster *FoundationDBCluster) GetStorageSelector() string {
+	selector := make(map[string]string, len(cluster.GetMatchLabels())+1)
+	for key, value := range cluster.GetMatchLabels() {
+		selector[key] = value
+	}
+	selector[cluster.GetProcessClassLabel()] = string(ProcessClassStorage)
+
+	return labels.SelectorFromSet(selector).String()
+}
+
 // GetUseExplicitListenAddress returns the UseExplicitListenAddress or if unset the default true
 func (cluster *FoundationDBCluster) GetUseExplicitListenAddress() bool {
 	return pointer.BoolDeref(cluster.Spec.UseExplicitListenAddress, true)
@@ -2231,6 +4577,11 @@ func (cluster *FoundationDBCluster) GetEnableAutomaticReplacements() bool {
 	return pointer.BoolDeref(cluster.Spec.AutomationOptions.Replacements.Enabled, true)
 }
 
+// GetDetectionOnly returns cluster.Spec.AutomationOptions.DetectionOnly or if unset the default false
+func (cluster *FoundationDBCluster) GetDetectionOnly() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.DetectionOnly, false)
+}
+
 // GetFailureDetectionTimeSeconds returns cluster.Spec.AutomationOptions.Replacements.FailureDetectionTimeSeconds or if unset the default 7200
 func (cluster *FoundationDBCluster) GetFailureDetectionTimeSeconds() int {
 	return pointer.IntDeref(cluster.Spec.AutomationOptions.Replacements.FailureDetectionTimeSeconds, 7200)
@@ -2256,6 +4607,11 @@ func (cluster *FoundationDBCluster) GetUseUnifiedImage() bool {
 	return pointer.BoolDeref(cluster.Spec.UseUnifiedImage, false)
 }
 
+// UsesProcessPerContainerLayout returns true if every fdbserver process should run in its own container.
+func (cluster *FoundationDBCluster) UsesProcessPerContainerLayout() bool {
+	return cluster.Spec.PodLayout == PodLayoutProcessPerContainer
+}
+
 // GetIgnoreTerminatingPodsSeconds returns the value of IgnoreTerminatingPodsSeconds or defaults to 10 minutes.
 func (cluster *FoundationDBCluster) GetIgnoreTerminatingPodsSeconds() int {
 	return pointer.IntDeref(cluster.Spec.AutomationOptions.IgnoreTerminatingPodsSeconds, int((10 * time.Minute).Seconds()))
@@ -2468,6 +4824,39 @@ func (cluster *FoundationDBCluster) GetCrashLoopContainerProcessGroups() map[str
 	return crashLoopTargets
 }
 
+// UseHostNetwork returns true if the Pods of the provided process class should use the host network.
+func (cluster *FoundationDBCluster) UseHostNetwork(processClass ProcessClass) bool {
+	return pointer.BoolDeref(cluster.GetProcessSettings(processClass).UseHostNetwork, false)
+}
+
+// GetProcessPortOffset returns the offset that will be added to the process number to calculate the ports of the
+// processes for the provided process class. Process classes that use the host network get a dedicated range of
+// ports based on their position in the list of process classes, so that processes of different process classes
+// can run on the same node without port conflicts. For all other process classes the offset will be 0.
+func (cluster *FoundationDBCluster) GetProcessPortOffset(processClass ProcessClass) int {
+	if !cluster.UseHostNetwork(processClass) {
+		return 0
+	}
+
+	return processClassIndices[processClass] * HostNetworkProcessesPerProcessClass
+}
+
+// GetProcessPortForProcessClass returns the expected port for a given process number of the provided process class
+// and the tls setting.
+func (cluster *FoundationDBCluster) GetProcessPortForProcessClass(processClass ProcessClass, processNumber int, tls bool) int {
+	return GetProcessPort(processNumber+cluster.GetProcessPortOffset(processClass), tls)
+}
+
+// GetSidecarPort returns the port the sidecar of the provided process class listens on. Process classes that use
+// the host network get a dedicated port based on their position in the list of process classes.
+func (cluster *FoundationDBCluster) GetSidecarPort(processClass ProcessClass) int {
+	if !cluster.UseHostNetwork(processClass) {
+		return DefaultSidecarPort
+	}
+
+	return DefaultSidecarPort + 1 + processClassIndices[processClass]
+}
+
 // Validate checks if all settings in the cluster are valid, if not and error will be returned. If multiple issues are
 // found all of them will be returned in a single error.
 func (cluster *FoundationDBCluster) Validate() error {
@@ -2483,6 +4872,23 @@ func (cluster *FoundationDBCluster) Validate() error {
 		validations = append(validations, fmt.Sprintf("storage engine %s is not supported on version %s", cluster.Spec.DatabaseConfiguration.StorageEngine, cluster.Spec.Version))
 	}
 
+	// Downgrades are only supported within the same protocol version, e.g. to roll back a patch release.
+	if cluster.IsBeingDowngraded() && cluster.IsBeingUpgradedWithVersionIncompatibleVersion() {
+		validations = append(validations, fmt.Sprintf("downgrade from version %s to version %s is only supported for protocol compatible versions", cluster.Status.RunningVersion, cluster.Spec.Version))
+	}
+
+	unsupportedProcessClasses := make([]string, 0)
+	for processClass, count := range cluster.Spec.ProcessCounts.Map() {
+		if count > 0 && !version.SupportsProcessClass(processClass) {
+			unsupportedProcessClasses = append(unsupportedProcessClasses, string(processClass))
+		}
+	}
+
+	sort.Strings(unsupportedProcessClasses)
+	for _, processClass := range unsupportedProcessClasses {
+		validations = append(validations, fmt.Sprintf("process class %s is not supported on version %s", processClass, cluster.Spec.Version))
+	}
+
 	// Check if all coordinator processes are stateful
 	for _, selection := range cluster.Spec.CoordinatorSelection {
 		if !selection.ProcessClass.IsStateful() {
@@ -2490,6 +4896,293 @@ func (cluster *FoundationDBCluster) Validate() error {
 		}
 	}
 
+	if cluster.Spec.TraceLogs != nil && cluster.Spec.TraceLogs.Forwarder != nil {
+		switch cluster.Spec.TraceLogs.Forwarder.Name {
+		case MainContainerName, SidecarContainerName, InitContainerName:
+			validations = append(validations, fmt.Sprintf("trace log forwarder container must not use the name %s", cluster.Spec.TraceLogs.Forwarder.Name))
+		}
+
+		if cluster.Spec.TraceLogs.Forwarder.Image == "" {
+			validations = append(validations, "trace log forwarder container must define an image")
+		}
+	}
+
+	for processClass, settings := range cluster.Spec.Processes {
+		containerNames := map[string]None{
+			MainContainerName:    {},
+			SidecarContainerName: {},
+			InitContainerName:    {},
+		}
+
+		// The additional fdbserver processes of a storage Pod run in their own containers.
+		if cluster.UsesProcessPerContainerLayout() {
+			for processNumber := 2; processNumber <= cluster.GetStorageServersPerPod(); processNumber++ {
+				containerNames[GetProcessContainerName(processNumber)] = None{}
+			}
+		}
+
+		additionalContainers := make([]corev1.Container, 0, len(settings.AdditionalContainers)+len(settings.AdditionalInitContainers))
+		additionalContainers = append(additionalContainers, settings.AdditionalContainers...)
+		additionalContainers = append(additionalContainers, settings.AdditionalInitContainers...)
+		for _, container := range additionalContainers {
+			if _, ok := containerNames[container.Name]; ok {
+				validations = append(validations, fmt.Sprintf("additional container %s for process class %s must have a unique name that is not used by the operator", container.Name, processClass))
+				continue
+			}
+
+			containerNames[container.Name] = None{}
+		}
+
+		selectorNames := make(map[string]None, len(settings.VolumeClaimTemplateSelectors))
+		for _, selector := range settings.VolumeClaimTemplateSelectors {
+			if selector.Name == "" {
+				validations = append(validations, fmt.Sprintf("volume claim template selectors for process class %s must have a name", processClass))
+				continue
+			}
+
+			if _, ok := selectorNames[selector.Name]; ok {
+				validations = append(validations, fmt.Sprintf("volume claim template selector %s for process class %s must have a unique name", selector.Name, processClass))
+			}
+			selectorNames[selector.Name] = None{}
+
+			if len(selector.NodeSelector) == 0 {
+				validations = append(validations, fmt.Sprintf("volume claim template selector %s for process class %s must define a node selector", selector.Name, processClass))
+			}
+		}
+
+		if settings.DNS != nil && settings.DNS.Policy == corev1.DNSNone {
+			if settings.PodTemplate == nil || settings.PodTemplate.Spec.DNSConfig == nil || len(settings.PodTemplate.Spec.DNSConfig.Nameservers) == 0 {
+				validations = append(validations, fmt.Sprintf("DNS policy None for process class %s requires nameservers in the dnsConfig of the Pod template", processClass))
+			}
+		}
+	}
+
+	var usesHostNetwork bool
+	for _, processClass := range ProcessClasses {
+		if !cluster.UseHostNetwork(processClass) {
+			continue
+		}
+
+		usesHostNetwork = true
+		if processClass == ProcessClassStorage && cluster.GetStorageServersPerPod() > HostNetworkProcessesPerProcessClass {
+			validations = append(validations, fmt.Sprintf("storageServersPerPod %d must not be greater than %d if the storage processes use the host network", cluster.GetStorageServersPerPod(), HostNetworkProcessesPerProcessClass))
+		}
+	}
+
+	if cluster.UsesProcessPerContainerLayout() {
+		if !cluster.GetUseUnifiedImage() {
+			validations = append(validations, "podLayout ProcessPerContainer requires the unified image")
+		}
+
+		if cluster.IsBeingUpgradedWithVersionIncompatibleVersion() {
+			validations = append(validations, fmt.Sprintf("upgrade from version %s to version %s is only supported for protocol compatible versions with podLayout ProcessPerContainer", cluster.Status.RunningVersion, cluster.Spec.Version))
+		}
+
+		// The operator can't read the values from Secrets and ConfigMaps when comparing the command lines of the
+		// processes.
+		for processClass := range cluster.Spec.Processes {
+			for _, variable := range cluster.GetProcessSettings(processClass).GetParameterEnvironmentVariables() {
+				if variable.ValueFrom != nil {
+					validations = append(validations, fmt.Sprintf("environment variable %s of process class %s must have a literal value to be referenced in the parameters with podLayout ProcessPerContainer", variable.Name, processClass))
+				}
+			}
+		}
+	}
+
+	if usesHostNetwork {
+		if cluster.GetUseUnifiedImage() {
+			validations = append(validations, "useHostNetwork is not supported with the unified image")
+		}
+
+		if cluster.UsePublicIPFromService() {
+			validations = append(validations, "useHostNetwork cannot be used together with the service as public IP source")
+		}
+	}
+
+	if cluster.Spec.CloneFrom != nil {
+		if cluster.Spec.CloneFrom.ClusterName == "" || cluster.Spec.CloneFrom.ClusterName == cluster.Name {
+			validations = append(validations, "cloneFrom.clusterName must be set to the name of another cluster")
+		}
+
+		if len(cluster.Spec.CloneFrom.VolumeSnapshots) == 0 {
+			validations = append(validations, "cloneFrom.volumeSnapshots must contain at least one VolumeSnapshot")
+		}
+
+		if cluster.Spec.SeedConnectionString != "" {
+			validations = append(validations, "cloneFrom and seedConnectionString cannot be used together")
+		}
+	}
+
+	if cluster.IsManagedConnectionOnly() && cluster.Spec.SeedConnectionString == "" {
+		validations = append(validations, "seedConnectionString must be set if managedConnectionOnly is enabled")
+	}
+
+	if len(cluster.Spec.Tenants) > 0 {
+		if !version.SupportsTenants() {
+			validations = append(validations, fmt.Sprintf("tenants are not supported on version %s", cluster.Spec.Version))
+		}
+
+		tenantNames := make(map[string]None, len(cluster.Spec.Tenants))
+		for _, tenant := range cluster.Spec.Tenants {
+			if _, ok := tenantNames[tenant.Name]; ok {
+				validations = append(validations, fmt.Sprintf("tenant %s is defined multiple times", tenant.Name))
+			}
+			tenantNames[tenant.Name] = None{}
+		}
+	}
+
+	if cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggle != nil && !version.SupportsPerpetualStorageWiggle() {
+		validations = append(validations, fmt.Sprintf("perpetual storage wiggle is not supported on version %s", cluster.Spec.Version))
+	}
+
+	wiggleLocality := cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggleLocality
+	if wiggleLocality != "" {
+		if !version.SupportsPerpetualStorageWiggleLocality() {
+			validations = append(validations, fmt.Sprintf("perpetual storage wiggle locality is not supported on version %s", cluster.Spec.Version))
+		}
+
+		if wiggleLocality != "0" && len(strings.SplitN(wiggleLocality, ":", 2)) != 2 {
+			validations = append(validations, fmt.Sprintf("perpetual storage wiggle locality %s must have the format <key>:<value> or be 0", wiggleLocality))
+		}
+	}
+
+	if cluster.Spec.StorageAutoscaling != nil && cluster.Spec.StorageAutoscaling.MinStorageProcesses > cluster.Spec.StorageAutoscaling.MaxStorageProcesses {
+		validations = append(validations, fmt.Sprintf("minStorageProcesses %d must not be greater than maxStorageProcesses %d", cluster.Spec.StorageAutoscaling.MinStorageProcesses, cluster.Spec.StorageAutoscaling.MaxStorageProcesses))
+	}
+
+	if len(cluster.Spec.TagQuotas) > 0 {
+		if !version.SupportsTagQuotas() {
+			validations = append(validations, fmt.Sprintf("tag quotas are not supported on version %s", cluster.Spec.Version))
+		}
+
+		tags := make(map[string]None, len(cluster.Spec.TagQuotas))
+		for _, quota := range cluster.Spec.TagQuotas {
+			if _, ok := tags[quota.Tag]; ok {
+				validations = append(validations, fmt.Sprintf("tag quota for %s is defined multiple times", quota.Tag))
+			}
+			tags[quota.Tag] = None{}
+
+			if quota.ReservedThroughput > quota.TotalThroughput {
+				validations = append(validations, fmt.Sprintf("reserved throughput for tag %s must not be greater than the total throughput", quota.Tag))
+			}
+		}
+	}
+
+	for processGroupID, pin := range cluster.Spec.PinnedProcessGroups {
+		if (pin.NodeName == "") == (pin.Zone == "") {
+			validations = append(validations, fmt.Sprintf("pin for process group %s must define either a node name or a zone", processGroupID))
+			continue
+		}
+
+		if pin.Zone != "" && (cluster.Spec.FaultDomain.Key == NoneFaultDomainKey || cluster.Spec.FaultDomain.Key == "foundationdb.org/kubernetes-cluster") {
+			validations = append(validations, fmt.Sprintf("pin for process group %s cannot use a zone with the fault domain key %s", processGroupID, cluster.Spec.FaultDomain.Key))
+		}
+	}
+
+	reassignedProcessGroups := make([]string, 0, len(cluster.Spec.ProcessGroupsToReassign))
+	for processGroupID := range cluster.Spec.ProcessGroupsToReassign {
+		reassignedProcessGroups = append(reassignedProcessGroups, string(processGroupID))
+	}
+
+	sort.Strings(reassignedProcessGroups)
+	reassignedCounts := map[ProcessClass]int{}
+	for _, processGroupID := range reassignedProcessGroups {
+		processClass := cluster.Spec.ProcessGroupsToReassign[ProcessGroupID(processGroupID)]
+		if !isKnownProcessClass(processClass) {
+			validations = append(validations, fmt.Sprintf("process group %s cannot be reassigned to the unknown process class %s", processGroupID, processClass))
+			continue
+		}
+
+		if !version.SupportsProcessClass(processClass) {
+			validations = append(validations, fmt.Sprintf("process group %s cannot be reassigned to process class %s, which is not supported on version %s", processGroupID, processClass, cluster.Spec.Version))
+			continue
+		}
+
+		// Process groups that already have the new process class are not reassigned again.
+		processGroup := FindProcessGroupByID(cluster.Status.ProcessGroups, ProcessGroupID(processGroupID))
+		if processGroup != nil && processGroup.ProcessClass == processClass {
+			continue
+		}
+
+		reassignedCounts[processClass]++
+	}
+
+	// The process counts must include the reassigned process groups, otherwise the operator would remove the new
+	// process groups again as excess process groups.
+	if len(reassignedCounts) > 0 {
+		counts, err := cluster.GetProcessCountsWithDefaults()
+		if err != nil {
+			return err
+		}
+
+		countsMap := counts.Map()
+		reassignedClasses := make([]string, 0, len(reassignedCounts))
+		for processClass := range reassignedCounts {
+			reassignedClasses = append(reassignedClasses, string(processClass))
+		}
+
+		sort.Strings(reassignedClasses)
+		for _, processClass := range reassignedClasses {
+			if countsMap[ProcessClass(processClass)] < reassignedCounts[ProcessClass(processClass)] {
+				validations = append(validations, fmt.Sprintf("process count for process class %s must be at least %d to keep the reassigned process groups", processClass, reassignedCounts[ProcessClass(processClass)]))
+			}
+		}
+	}
+
+	if cluster.Spec.Authorization != nil {
+		if !version.SupportsAuthorization() {
+			validations = append(validations, fmt.Sprintf("token based authorization is not supported on version %s", cluster.Spec.Version))
+		}
+
+		if !cluster.Spec.MainContainer.EnableTLS {
+			validations = append(validations, "token based authorization requires TLS to be enabled for the main container")
+		}
+
+		for _, secret := range cluster.Spec.Authorization.PublicKeySecrets {
+			if secret.Name == "" || secret.Key == "" {
+				validations = append(validations, "public key secrets for the token based authorization must define a name and a key")
+				break
+			}
+		}
+	}
+
+	// If the image configs define images for multiple architectures, the operator can't choose the images for process
+	// classes without an architecture.
+	architectures := GetImageConfigArchitectures(cluster.Spec.MainContainer.ImageConfigs, cluster.Spec.SidecarContainer.ImageConfigs)
+	if len(architectures) > 1 {
+		counts, err := cluster.GetProcessCountsWithDefaults()
+		if err != nil {
+			return err
+		}
+
+		processClasses := make([]string, 0)
+		for processClass, count := range counts.Map() {
+			if count > 0 && cluster.GetProcessSettings(processClass).Architecture == "" {
+				processClasses = append(processClasses, string(processClass))
+			}
+		}
+
+		sort.Strings(processClasses)
+		for _, processClass := range processClasses {
+			validations = append(validations, fmt.Sprintf("process class %s must define an architecture as the image configs define images for the architectures %s", processClass, strings.Join(architectures, ", ")))
+		}
+	}
+
+	if cluster.Spec.ExternalMigration != nil {
+		if cluster.Spec.SeedConnectionString == "" {
+			validations = append(validations, "seedConnectionString must be set if externalMigration is defined")
+		}
+
+		if cluster.IsManagedConnectionOnly() {
+			validations = append(validations, "externalMigration cannot be used if managedConnectionOnly is enabled")
+		}
+
+		_, err := cluster.GetExternalProcessAddresses()
+		if err != nil {
+			validations = append(validations, fmt.Sprintf("externalProcessAddresses contains an invalid address: %s", err.Error()))
+		}
+	}
+
 	if len(validations) == 0 {
 		return nil
 	}
@@ -2497,6 +5190,146 @@ func (cluster *FoundationDBCluster) Validate() error {
 	return fmt.Errorf(strings.Join(validations, ", "))
 }
 
+// IsStorageAutoscalingEnabled returns true if the operator should increase the number of storage processes based on
+// the disk utilization.
+func (cluster *FoundationDBCluster) IsStorageAutoscalingEnabled() bool {
+	return cluster.Spec.StorageAutoscaling != nil && pointer.BoolDeref(cluster.Spec.StorageAutoscaling.Enabled, false)
+}
+
+// GetStorageDiskUtilizationThreshold returns the disk utilization in percent that triggers a scale up of the storage
+// processes.
+func (cluster *FoundationDBCluster) GetStorageDiskUtilizationThreshold() int {
+	if cluster.Spec.StorageAutoscaling == nil {
+		return 80
+	}
+
+	return pointer.IntDeref(cluster.Spec.StorageAutoscaling.DiskUtilizationThreshold, 80)
+}
+
+// GetRecommendedStorageProcessCount returns the number of storage processes that is recommended for the provided
+// average disk utilization in percent. The result is bounded by the min and max storage processes of the storage
+// autoscaling settings.
+func (cluster *FoundationDBCluster) GetRecommendedStorageProcessCount(currentCount int, diskUtilization int) int {
+	if cluster.Spec.StorageAutoscaling == nil {
+		return currentCount
+	}
+
+	recommended := currentCount
+	threshold := cluster.GetStorageDiskUtilizationThreshold()
+	if diskUtilization >= threshold {
+		// Scale the process count so that the same amount of data would result in a utilization below the threshold.
+		recommended = currentCount*diskUtilization/threshold + 1
+	}
+
+	if recommended < cluster.Spec.StorageAutoscaling.MinStorageProcesses {
+		recommended = cluster.Spec.StorageAutoscaling.MinStorageProcesses
+	}
+
+	if recommended > cluster.Spec.StorageAutoscaling.MaxStorageProcesses {
+		recommended = cluster.Spec.StorageAutoscaling.MaxStorageProcesses
+	}
+
+	return recommended
+}
+
+// UseDerivedProcessCounts returns true if the storage, log and stateless process counts should always be derived
+// from the database configuration.
+func (cluster *FoundationDBCluster) UseDerivedProcessCounts() bool {
+	return pointer.BoolDeref(cluster.Spec.DeriveProcessCounts, false)
+}
+
+// IsManagedConnectionOnly returns true if the operator should only manage the connection to an existing cluster
+// without managing any Pods.
+func (cluster *FoundationDBCluster) IsManagedConnectionOnly() bool {
+	return pointer.BoolDeref(cluster.Spec.ManagedConnectionOnly, false)
+}
+
+// IsExternalMigrationInProgress returns true if an external migration is defined and not completed yet.
+func (cluster *FoundationDBCluster) IsExternalMigrationInProgress() bool {
+	return cluster.Spec.ExternalMigration != nil && cluster.Status.MigrationPhase != MigrationPhaseCompleted
+}
+
+// GetExternalProcessAddresses returns the parsed addresses of the external processes that should be replaced by the
+// operator-managed processes.
+func (cluster *FoundationDBCluster) GetExternalProcessAddresses() ([]ProcessAddress, error) {
+	if cluster.Spec.ExternalMigration == nil {
+		return nil, nil
+	}
+
+	addresses := make([]ProcessAddress, 0, len(cluster.Spec.ExternalMigration.ExternalProcessAddresses))
+	for _, address := range cluster.Spec.ExternalMigration.ExternalProcessAddresses {
+		parsed, err := ParseProcessAddress(address)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, parsed)
+	}
+
+	return addresses, nil
+}
+
+// GetPendingTenantChanges returns the names of the tenants that must be created and the names of the tenants
+// that must be removed based on the spec and the status.
+func (cluster *FoundationDBCluster) GetPendingTenantChanges() ([]string, []string) {
+	existingTenants := make(map[string]None, len(cluster.Status.Tenants))
+	for _, tenant := range cluster.Status.Tenants {
+		existingTenants[tenant.Name] = None{}
+	}
+
+	var tenantsToCreate, tenantsToRemove []string
+	for _, tenant := range cluster.Spec.Tenants {
+		_, exists := existingTenants[tenant.Name]
+		if tenant.Remove && exists {
+			tenantsToRemove = append(tenantsToRemove, tenant.Name)
+		} else if !tenant.Remove && !exists {
+			tenantsToCreate = append(tenantsToCreate, tenant.Name)
+		}
+	}
+
+	return tenantsToCreate, tenantsToRemove
+}
+
+// NeedsDataDistributionModeChange returns true if the data distribution mode defined in the spec differs from the
+// data distribution mode reported in the status.
+func (cluster *FoundationDBCluster) NeedsDataDistributionModeChange() bool {
+	if cluster.Spec.DataDistribution == nil || cluster.Spec.DataDistribution.Enabled == nil {
+		return false
+	}
+
+	return *cluster.Spec.DataDistribution.Enabled == cluster.Status.DataDistributionDisabled
+}
+
+// GetPendingTagQuotas returns the tag quotas from the spec that differ from the quotas reported in the status. Tags
+// that are reported in the status with a quota but were removed from the spec will be returned without throughput
+// values, so that their quota will be cleared.
+func (cluster *FoundationDBCluster) GetPendingTagQuotas() []TagQuota {
+	currentQuotas := make(map[string]TagQuota, len(cluster.Status.TagQuotas))
+	for _, quota := range cluster.Status.TagQuotas {
+		currentQuotas[quota.Tag] = quota
+	}
+
+	var pendingQuotas []TagQuota
+	for _, quota := range cluster.Spec.TagQuotas {
+		if currentQuotas[quota.Tag] != quota {
+			pendingQuotas = append(pendingQuotas, quota)
+		}
+		delete(currentQuotas, quota.Tag)
+	}
+
+	for _, quota := range cluster.Status.TagQuotas {
+		if _, ok := currentQuotas[quota.Tag]; !ok {
+			continue
+		}
+
+		if quota.TotalThroughput != 0 || quota.ReservedThroughput != 0 {
+			pendingQuotas = append(pendingQuotas, TagQuota{Tag: quota.Tag})
+		}
+	}
+
+	return pendingQuotas
+}
+
 // IsTaintFeatureDisabled return true if operator is configured to not replace Pods tainted Nodes OR
 // if operator's TaintReplacementOptions is not set.
 func (cluster *FoundationDBCluster) IsTaintFeatureDisabled() bool {
@@ -452,6 +452,116 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	When("getting the derived process counts", func() {
+		var cluster *FoundationDBCluster
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "default",
+				},
+				Spec: FoundationDBClusterSpec{
+					Version:             Versions.Default.String(),
+					DeriveProcessCounts: pointer.Bool(true),
+					DatabaseConfiguration: DatabaseConfiguration{
+						RedundancyMode: RedundancyModeDouble,
+					},
+				},
+			}
+		})
+
+		It("should use the greater of the derived and the defined counts", func() {
+			counts, err := cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(ProcessCounts{
+				Storage:   3,
+				Log:       4,
+				Stateless: 9,
+			}))
+
+			cluster.Spec.ProcessCounts = ProcessCounts{
+				Storage:   10,
+				Log:       2,
+				Stateless: -1,
+			}
+			counts, err = cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(ProcessCounts{
+				Storage:   10,
+				Log:       4,
+				Stateless: -1,
+			}))
+
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = RedundancyModeTriple
+			counts, err = cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(ProcessCounts{
+				Storage:   10,
+				Log:       5,
+				Stateless: -1,
+			}))
+
+			cluster.Spec.DeriveProcessCounts = nil
+			counts, err = cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(ProcessCounts{
+				Storage:   10,
+				Log:       2,
+				Stateless: -1,
+			}))
+		})
+	})
+
+	When("getting the process counts with storage autoscaling", func() {
+		var cluster *FoundationDBCluster
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: Versions.Default.String(),
+					DatabaseConfiguration: DatabaseConfiguration{
+						RedundancyMode: RedundancyModeDouble,
+					},
+					StorageAutoscaling: &StorageAutoscalingSpec{
+						Enabled:             pointer.Bool(true),
+						MaxStorageProcesses: 10,
+					},
+				},
+				Status: FoundationDBClusterStatus{
+					StorageAutoscaling: &StorageAutoscalingStatus{
+						StorageProcesses: 6,
+					},
+				},
+			}
+		})
+
+		It("should use the storage process count from the status", func() {
+			counts, err := cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Storage).To(Equal(6))
+
+			cluster.Spec.ProcessCounts.Storage = 8
+			counts, err = cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Storage).To(Equal(8))
+
+			cluster.Spec.ProcessCounts.Storage = 0
+			cluster.Spec.StorageAutoscaling.Enabled = nil
+			counts, err = cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Storage).To(Equal(3))
+		})
+
+		DescribeTable("getting the recommended storage process count", func(currentCount int, diskUtilization int, expected int) {
+			Expect(cluster.GetRecommendedStorageProcessCount(currentCount, diskUtilization)).To(Equal(expected))
+		},
+			Entry("below the threshold", 4, 50, 4),
+			Entry("at the threshold", 4, 80, 5),
+			Entry("above the threshold", 4, 95, 5),
+			Entry("far above the threshold", 8, 99, 10),
+			Entry("bounded by the maximum", 10, 90, 10),
+		)
+	})
+
 	When("getting the default process counts with cross cluster replication", func() {
 		It("should return the default process counts", func() {
 			cluster := &FoundationDBCluster{
@@ -676,6 +786,30 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	DescribeTable("checking if the cluster has the desired fault tolerance", func(faultTolerance *FaultToleranceStatus, available bool, expected bool) {
+		cluster := &FoundationDBCluster{
+			Spec: FoundationDBClusterSpec{
+				DatabaseConfiguration: DatabaseConfiguration{
+					RedundancyMode: RedundancyModeDouble,
+				},
+			},
+			Status: FoundationDBClusterStatus{
+				FaultTolerance: faultTolerance,
+				Health: ClusterHealth{
+					Available: available,
+				},
+			},
+		}
+
+		Expect(cluster.HasDesiredFaultTolerance()).To(Equal(expected))
+	},
+		Entry("no fault tolerance is recorded", nil, true, false),
+		Entry("the desired fault tolerance is met", &FaultToleranceStatus{MaxZoneFailuresWithoutLosingData: 1, MaxZoneFailuresWithoutLosingAvailability: 1}, true, true),
+		Entry("the database is not available", &FaultToleranceStatus{MaxZoneFailuresWithoutLosingData: 1, MaxZoneFailuresWithoutLosingAvailability: 1}, false, false),
+		Entry("data is not fully replicated", &FaultToleranceStatus{MaxZoneFailuresWithoutLosingData: 0, MaxZoneFailuresWithoutLosingAvailability: 1}, true, false),
+		Entry("availability would be lost", &FaultToleranceStatus{MaxZoneFailuresWithoutLosingData: 1, MaxZoneFailuresWithoutLosingAvailability: 0}, true, false),
+	)
+
 	When("parsing the backup status for 6.2", func() {
 		It("should be parsed correctly", func() {
 			statusFile, err := os.OpenFile(filepath.Join("testdata", "fdbbackup_status_6_2.json"), os.O_RDONLY, os.ModePerm)
@@ -695,6 +829,32 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	When("parsing the backup status for 7.1", func() {
+		It("should be parsed correctly", func() {
+			statusFile, err := os.OpenFile(filepath.Join("testdata", "fdbbackup_status_7_1.json"), os.O_RDONLY, os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+			defer statusFile.Close()
+			statusDecoder := json.NewDecoder(statusFile)
+			status := FoundationDBLiveBackupStatus{}
+			err = statusDecoder.Decode(&status)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(FoundationDBLiveBackupStatus{
+				DestinationURL:          "blobstore://minio@minio-service:9000/sample-cluster?bucket=fdb-backups",
+				SnapshotIntervalSeconds: 864000,
+				Status: FoundationDBLiveBackupStatusState{
+					Running: true,
+				},
+				Restorable: true,
+				LatestRestorablePoint: &FoundationDBLiveBackupStatusRestorablePoint{
+					Version:      1592184263,
+					EpochSeconds: 1682510823,
+					Timestamp:    "2023/04/26.12:07:03+0000",
+					LagSeconds:   7,
+				},
+			}))
+		})
+	})
+
 	coordinators := []ProcessAddress{
 		{
 			IPAddress: net.ParseIP("127.0.0.1"),
@@ -782,6 +942,34 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	DescribeTable("checking if the connection string must be rotated", func(seedConnectionString string, appliedSeedConnectionString string, expected bool) {
+		currentConnectionString := "test:abcd@127.0.0.1:4501,127.0.0.2:4501,127.0.0.3:4501"
+		cluster := &FoundationDBCluster{
+			Spec: FoundationDBClusterSpec{
+				SeedConnectionString: seedConnectionString,
+			},
+			Status: FoundationDBClusterStatus{
+				ConnectionString:            currentConnectionString,
+				AppliedSeedConnectionString: appliedSeedConnectionString,
+			},
+		}
+
+		Expect(cluster.NeedsConnectionStringRotation()).To(Equal(expected))
+		if expected {
+			Expect(cluster.GetDesiredConnectionString()).To(Equal(seedConnectionString))
+		} else {
+			Expect(cluster.GetDesiredConnectionString()).To(Equal(currentConnectionString))
+		}
+	},
+		Entry("without a seed connection string", "", "", false),
+		Entry("with a seed connection string that was never applied", "clone:efgh@127.0.0.1:4501", "", false),
+		Entry("with an unchanged seed connection string", "clone:efgh@127.0.0.1:4501", "clone:efgh@127.0.0.1:4501", false),
+		Entry("with a changed seed connection string with the same generation", "test:abcd@127.0.0.1:4501", "test:abcd@127.0.0.2:4501", false),
+		Entry("with a changed seed connection string with a new description", "clone:abcd@127.0.0.1:4501", "test:abcd@127.0.0.1:4501", true),
+		Entry("with a changed seed connection string with a new generation", "test:efgh@127.0.0.1:4501", "test:abcd@127.0.0.1:4501", true),
+		Entry("with an invalid seed connection string", "invalid", "test:abcd@127.0.0.1:4501", false),
+	)
+
 	When("getting the cluster database configuration", func() {
 		var cluster *FoundationDBCluster
 
@@ -1074,6 +1262,12 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 
 			Expect(configuration.GetConfigurationString("7.0.0")).To(Equal("double ssd usable_regions=1 logs=5 resolvers=0 log_routers=0 remote_logs=0 commit_proxies=4 grv_proxies=2 log_spill:=3 regions=[]"))
 			Expect(configuration.GetConfigurationString("7.1.0-rc1")).To(Equal("double ssd usable_regions=1 logs=5 resolvers=0 log_routers=0 remote_logs=0 commit_proxies=4 grv_proxies=2 log_spill:=3 regions=[]"))
+
+			configuration.PerpetualStorageWiggleLocality = "zoneid:zone1"
+			Expect(configuration.GetConfigurationString("7.1.0")).To(Equal("double ssd usable_regions=1 logs=5 resolvers=0 log_routers=0 remote_logs=0 commit_proxies=4 grv_proxies=2 log_spill:=3 regions=[] perpetual_storage_wiggle_locality=zoneid:zone1"))
+
+			configuration.PerpetualStorageWiggle = pointer.Int(1)
+			Expect(configuration.GetConfigurationString("7.1.0")).To(Equal("double ssd usable_regions=1 logs=5 resolvers=0 log_routers=0 remote_logs=0 commit_proxies=4 grv_proxies=2 log_spill:=3 regions=[] perpetual_storage_wiggle=1 perpetual_storage_wiggle_locality=zoneid:zone1"))
 		})
 
 		When("CommitProxies and GrvProxies are not configured", func() {
@@ -3435,9 +3629,168 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
 					Reconciled: 2,
 				}))
+
+				cluster = createCluster()
+				cluster.Spec.ExternalMigration = &ExternalMigrationSpec{ExternalProcessAddresses: []string{"127.0.0.1:4501"}}
+				cluster.Status.MigrationPhase = MigrationPhaseExcludingExternalProcesses
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeFalse())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled:             1,
+					NeedsExternalMigration: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Spec.ExternalMigration = &ExternalMigrationSpec{ExternalProcessAddresses: []string{"127.0.0.1:4501"}}
+				cluster.Status.MigrationPhase = MigrationPhaseCompleted
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Spec.Tenants = []TenantSpec{{Name: "tenant1"}}
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeFalse())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled:         1,
+					NeedsTenantChanges: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Spec.Tenants = []TenantSpec{{Name: "tenant1"}, {Name: "tenant2", Remove: true}}
+				cluster.Status.Tenants = []TenantStatus{{Name: "tenant1"}}
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Spec.TagQuotas = []TagQuota{{Tag: "tenant1", TotalThroughput: 1000}}
+				cluster.Status.TagQuotas = []TagQuota{{Tag: "tenant1", TotalThroughput: 500}}
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeFalse())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled:           1,
+					NeedsTagQuotaChanges: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Spec.TagQuotas = []TagQuota{{Tag: "tenant1", TotalThroughput: 1000}}
+				cluster.Status.TagQuotas = []TagQuota{{Tag: "tenant1", TotalThroughput: 1000}}
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Spec.DataDistribution = &DataDistributionSpec{Enabled: pointer.Bool(false)}
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeFalse())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled:                      1,
+					NeedsDataDistributionModeChange: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Spec.DataDistribution = &DataDistributionSpec{Enabled: pointer.Bool(false)}
+				cluster.Status.DataDistributionDisabled = true
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled: 2,
+				}))
+
+				cluster = createCluster()
+				cluster.Status.DataDistributionDisabled = true
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled: 2,
+				}))
 			})
 		})
 
+		When("the operator only manages the connection to the cluster", func() {
+			BeforeEach(func() {
+				createCluster = func() *FoundationDBCluster {
+					return &FoundationDBCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:       "sample-cluster",
+							Namespace:  "default",
+							Generation: 2,
+						},
+						Spec: FoundationDBClusterSpec{
+							Version:               "7.1.26",
+							ManagedConnectionOnly: pointer.Bool(true),
+							SeedConnectionString:  "test:test@127.0.0.1:4501",
+							DatabaseConfiguration: DatabaseConfiguration{
+								RedundancyMode: RedundancyModeTriple,
+							},
+						},
+						Status: FoundationDBClusterStatus{
+							Health: ClusterHealth{
+								Available: true,
+								Healthy:   true,
+							},
+							Generations: ClusterGenerationStatus{
+								Reconciled: 1,
+							},
+							Configured: true,
+						},
+					}
+				}
+			})
+
+			It("should ignore the process groups and the database configuration", func() {
+				cluster := createCluster()
+
+				result, err := cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled: 2,
+				}))
+			})
+
+			It("should not be reconciled if the database is unavailable", func() {
+				cluster := createCluster()
+				cluster.Status.Health.Available = false
+
+				result, err := cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeFalse())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled:          1,
+					DatabaseUnavailable: 2,
+				}))
+			})
+
+			It("should not be reconciled if the client config is outdated", func() {
+				cluster := createCluster()
+				cluster.Status.HasIncorrectConfigMap = true
+
+				result, err := cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeFalse())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled:             1,
+					NeedsMonitorConfUpdate: 2,
+				}))
+			})
+		})
 	})
 
 	When("getting the process settings", func() {
@@ -3452,6 +3805,8 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 								},
 							},
 							CustomParameters: FoundationDBCustomParameters{"test_knob=value1"},
+							DNS:              &PodDNSSettings{Policy: corev1.DNSClusterFirstWithHostNet},
+							Architecture:     "amd64",
 						},
 						ProcessClassStorage: {
 							PodTemplate: &corev1.PodTemplateSpec{
@@ -3459,6 +3814,7 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 									Labels: map[string]string{"test-label": "label2"},
 								},
 							},
+							Architecture: "arm64",
 						},
 						ProcessClassStateless: {
 							PodTemplate: &corev1.PodTemplateSpec{
@@ -3473,20 +3829,144 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 			settings := cluster.GetProcessSettings(ProcessClassStorage)
 			Expect(settings.PodTemplate.ObjectMeta.Labels).To(Equal(map[string]string{"test-label": "label2"}))
 			Expect(settings.CustomParameters).To(Equal(FoundationDBCustomParameters{"test_knob=value1"}))
+			Expect(settings.Parameters).To(Equal(FoundationDBParameters{{Name: "test_knob", Value: "value1"}}))
+			Expect(settings.DNS).To(Equal(&PodDNSSettings{Policy: corev1.DNSClusterFirstWithHostNet}))
+			Expect(settings.Architecture).To(Equal("arm64"))
+			Expect(cluster.GetProcessSettings(ProcessClassStateless).Architecture).To(Equal("amd64"))
 		})
-	})
-
-	When("getting the lock options", func() {
-		It("should return the correct lock options", func() {
-			cluster := &FoundationDBCluster{}
 
-			Expect(cluster.GetLockPrefix()).To(Equal("\xff\x02/org.foundationdb.kubernetes-operator"))
-			Expect(cluster.GetLockDuration()).To(Equal(10 * time.Minute))
+		When("getting the architecture of a process class", func() {
+			var cluster *FoundationDBCluster
 
-			var disabled = true
-			cluster.Spec.LockOptions.DisableLocks = &disabled
-			Expect(cluster.ShouldUseLocks()).To(BeFalse())
-			disabled = false
+			BeforeEach(func() {
+				cluster = &FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {Architecture: "amd64"},
+						},
+						MainContainer: ContainerOverrides{
+							ImageConfigs: []ImageConfig{{Architecture: "arm64", TagSuffix: "-arm64"}, {BaseImage: "foundationdb/foundationdb"}},
+						},
+					},
+				}
+			})
+
+			It("should use the architecture of the process settings", func() {
+				Expect(cluster.GetProcessArchitecture(ProcessClassStorage)).To(Equal("amd64"))
+			})
+
+			It("should use the architecture of the image configs for process classes without an architecture", func() {
+				Expect(cluster.GetProcessArchitecture(ProcessClassLog)).To(Equal("arm64"))
+			})
+
+			When("the image configs define images for multiple architectures", func() {
+				BeforeEach(func() {
+					cluster.Spec.SidecarContainer.ImageConfigs = []ImageConfig{{Architecture: "amd64", TagSuffix: "-1-amd64"}}
+				})
+
+				It("should not use an architecture for process classes without an architecture", func() {
+					Expect(cluster.GetProcessArchitecture(ProcessClassLog)).To(BeEmpty())
+				})
+			})
+		})
+
+		When("parameters are defined", func() {
+			var cluster *FoundationDBCluster
+
+			BeforeEach(func() {
+				cluster = &FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {
+								Parameters: FoundationDBParameters{
+									{Name: "knob_a", Value: "1"},
+									{Name: "knob_b", Value: "2"},
+								},
+							},
+							ProcessClassStorage: {
+								Parameters: FoundationDBParameters{
+									{Name: "knob_a", Remove: true},
+									{Name: "knob_b", Value: "3"},
+									{Name: "knob_c", Value: "4"},
+								},
+							},
+							ProcessClassLog: {
+								CustomParameters: FoundationDBCustomParameters{"knob_c=5"},
+							},
+						},
+					},
+				}
+			})
+
+			It("should use the general parameters as defaults", func() {
+				Expect(cluster.GetProcessSettings(ProcessClassStateless).Parameters).To(Equal(FoundationDBParameters{
+					{Name: "knob_a", Value: "1"},
+					{Name: "knob_b", Value: "2"},
+				}))
+			})
+
+			It("should merge the parameters of the process class", func() {
+				Expect(cluster.GetProcessSettings(ProcessClassStorage).Parameters).To(Equal(FoundationDBParameters{
+					{Name: "knob_b", Value: "3"},
+					{Name: "knob_c", Value: "4"},
+				}))
+			})
+
+			It("should replace the defaults with the custom parameters of the process class", func() {
+				Expect(cluster.GetProcessSettings(ProcessClassLog).Parameters).To(Equal(FoundationDBParameters{
+					{Name: "knob_c", Value: "5"},
+				}))
+			})
+		})
+
+		When("environment variables are defined", func() {
+			var cluster *FoundationDBCluster
+
+			BeforeEach(func() {
+				cluster = &FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {
+								Env: []corev1.EnvVar{
+									{Name: "KNOB", Value: "1"},
+									{Name: "KNOB_VALUE", Value: "2"},
+									{Name: "HTTP_PROXY", Value: "proxy:8080"},
+								},
+								Parameters: FoundationDBParameters{
+									{Name: "knob_a", Value: "$KNOB_VALUE"},
+								},
+							},
+							ProcessClassStorage: {
+								Env: []corev1.EnvVar{{Name: "KNOB", Value: "3"}},
+							},
+						},
+					},
+				}
+			})
+
+			It("should use the environment of the process class", func() {
+				Expect(cluster.GetProcessSettings(ProcessClassStorage).Env).To(Equal([]corev1.EnvVar{{Name: "KNOB", Value: "3"}}))
+				Expect(cluster.GetProcessSettings(ProcessClassLog).Env).To(HaveLen(3))
+			})
+
+			It("should return the environment variables that are referenced in the parameters", func() {
+				Expect(cluster.GetProcessSettings(ProcessClassLog).GetParameterEnvironmentVariables()).To(Equal([]corev1.EnvVar{{Name: "KNOB_VALUE", Value: "2"}}))
+				Expect(cluster.GetProcessSettings(ProcessClassStorage).GetParameterEnvironmentVariables()).To(BeEmpty())
+			})
+		})
+	})
+
+	When("getting the lock options", func() {
+		It("should return the correct lock options", func() {
+			cluster := &FoundationDBCluster{}
+
+			Expect(cluster.GetLockPrefix()).To(Equal("\xff\x02/org.foundationdb.kubernetes-operator"))
+			Expect(cluster.GetLockDuration()).To(Equal(10 * time.Minute))
+
+			var disabled = true
+			cluster.Spec.LockOptions.DisableLocks = &disabled
+			Expect(cluster.ShouldUseLocks()).To(BeFalse())
+			disabled = false
 			Expect(cluster.ShouldUseLocks()).To(BeTrue())
 			cluster.Spec.LockOptions.DisableLocks = nil
 
@@ -3941,6 +4421,20 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 			Expect(cluster.NeedsExplicitListenAddress()).To(BeTrue())
 		})
 
+		It("is required with the node as the public IP", func() {
+			source := PublicIPSourceNode
+			cluster.Spec.Routing.PublicIPSource = &source
+			cluster.Spec.UseExplicitListenAddress = pointer.Bool(false)
+			Expect(cluster.NeedsExplicitListenAddress()).To(BeTrue())
+		})
+
+		It("is required with a load balancer as the public IP", func() {
+			source := PublicIPSourceLoadBalancer
+			cluster.Spec.Routing.PublicIPSource = &source
+			cluster.Spec.UseExplicitListenAddress = pointer.Bool(false)
+			Expect(cluster.NeedsExplicitListenAddress()).To(BeTrue())
+		})
+
 		It("is required with a pod as the public IP", func() {
 			source := PublicIPSourcePod
 			cluster.Spec.Routing.PublicIPSource = &source
@@ -3958,6 +4452,44 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	When("checking the external access configuration", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{}
+		})
+
+		It("is disabled by default", func() {
+			Expect(cluster.IsExternalAccessEnabled()).To(BeFalse())
+			Expect(cluster.GetExternalAccessServiceType()).To(Equal(corev1.ServiceTypeLoadBalancer))
+		})
+
+		It("is enabled with the flag set to true", func() {
+			cluster.Spec.Routing.ExternalAccess = &ExternalAccessConfig{Enabled: pointer.Bool(true)}
+			Expect(cluster.IsExternalAccessEnabled()).To(BeTrue())
+		})
+
+		It("uses the configured service type", func() {
+			serviceType := corev1.ServiceTypeNodePort
+			cluster.Spec.Routing.ExternalAccess = &ExternalAccessConfig{ServiceType: &serviceType}
+			Expect(cluster.GetExternalAccessServiceType()).To(Equal(corev1.ServiceTypeNodePort))
+		})
+	})
+
+	DescribeTable("checking if the public IP is provided by a service", func(source PublicIPSource, expected bool) {
+		cluster := &FoundationDBCluster{}
+		if source != "" {
+			cluster.Spec.Routing.PublicIPSource = &source
+		}
+		Expect(cluster.UsePublicIPFromService()).To(Equal(expected))
+	},
+		Entry("no source is set", PublicIPSource(""), false),
+		Entry("the pod is the source", PublicIPSourcePod, false),
+		Entry("the service is the source", PublicIPSourceService, true),
+		Entry("the node is the source", PublicIPSourceNode, false),
+		Entry("the load balancer is the source", PublicIPSourceLoadBalancer, true),
+	)
+
 	When("checking whether the process group should be skipped or not", func() {
 		type testCase struct {
 			cluster  *FoundationDBCluster
@@ -4320,6 +4852,28 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
 				Expect(cluster.UseDNSInClusterFile()).To(BeTrue())
 			})
+
+			It("only uses DNS names if the running version supports them", func() {
+				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+				cluster.Status.RunningVersion = Versions.Default.String()
+				Expect(cluster.UseDNSInClusterFile()).To(BeFalse())
+				Expect(cluster.DefineDNSLocalityFields()).To(BeTrue())
+
+				cluster.Status.RunningVersion = Versions.SupportsDNSInClusterFile.String()
+				Expect(cluster.UseDNSInClusterFile()).To(BeTrue())
+			})
+		})
+
+		When("getting the unsupported features", func() {
+			It("reports the features that are not supported by the version", func() {
+				Expect(cluster.GetUnsupportedFeatures(Versions.Default)).To(BeEmpty())
+
+				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+				Expect(cluster.GetUnsupportedFeatures(Versions.Default)).To(ConsistOf(
+					fmt.Sprintf("DNS names in the cluster file are not supported on version %s, IP addresses will be used", Versions.Default),
+				))
+				Expect(cluster.GetUnsupportedFeatures(Versions.SupportsDNSInClusterFile)).To(BeEmpty())
+			})
 		})
 
 		When("checking whether we use DNS in the locality fields", func() {
@@ -4644,6 +5198,28 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				},
 				fmt.Errorf("stateless is not a valid process class for coordinators"),
 			),
+			Entry("downgrading to a protocol compatible version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.1.25",
+					},
+					Status: FoundationDBClusterStatus{
+						RunningVersion: "7.1.27",
+					},
+				},
+				nil,
+			),
+			Entry("downgrading to a protocol incompatible version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.0.0",
+					},
+					Status: FoundationDBClusterStatus{
+						RunningVersion: "7.1.27",
+					},
+				},
+				fmt.Errorf("downgrade from version 7.1.27 to version 7.0.0 is only supported for protocol compatible versions"),
+			),
 			Entry("multiple validations",
 				&FoundationDBCluster{
 					Spec: FoundationDBClusterSpec{
@@ -4682,125 +5258,895 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				},
 				nil,
 			),
-		)
-	})
-
-	When("adding processes to the no-schedule list", func() {
-		var cluster *FoundationDBCluster
-
-		BeforeEach(func() {
-			cluster = &FoundationDBCluster{}
-		})
-
-		When("the no-schedule list is empty", func() {
-			type testCase struct {
-				Instances                     []ProcessGroupID
-				ExpectedInstancesInNoSchedule []ProcessGroupID
-			}
-
-			DescribeTable("should add all targeted processes to the no-schedule list",
-				func(tc testCase) {
-					cluster.AddProcessGroupsToNoScheduleList(tc.Instances)
-					Expect(cluster.Spec.Buggify.NoSchedule).To(ContainElements(tc.ExpectedInstancesInNoSchedule))
-					Expect(len(cluster.Spec.Buggify.NoSchedule)).To(Equal(len(tc.ExpectedInstancesInNoSchedule)))
+			Entry("managing only the connection without a seed connection string",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:               "7.1.26",
+						ManagedConnectionOnly: pointer.Bool(true),
+					},
 				},
-				Entry("Adding single instance",
-					testCase{
-						Instances:                     []ProcessGroupID{"instance-1"},
-						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1"},
-					}),
-				Entry("Adding multiple instances",
-					testCase{
-						Instances:                     []ProcessGroupID{"instance-1", "instance-2"},
-						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1", "instance-2"},
-					}),
-			)
-		})
-
-		When("the no-schedule list is not empty", func() {
-			BeforeEach(func() {
-				cluster.Spec.Buggify.NoSchedule = []ProcessGroupID{"instance-1"}
-			})
-
-			type testCase struct {
-				Instances                     []ProcessGroupID
-				ExpectedInstancesInNoSchedule []ProcessGroupID
-			}
-
-			DescribeTable("should add all targeted processes to no-schedule list",
-				func(tc testCase) {
-					cluster.AddProcessGroupsToNoScheduleList(tc.Instances)
-					Expect(cluster.Spec.Buggify.NoSchedule).To(ContainElements(tc.ExpectedInstancesInNoSchedule))
-					Expect(len(cluster.Spec.Buggify.NoSchedule)).To(Equal(len(tc.ExpectedInstancesInNoSchedule)))
+				fmt.Errorf("seedConnectionString must be set if managedConnectionOnly is enabled"),
+			),
+			Entry("managing only the connection with a seed connection string",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:               "7.1.26",
+						ManagedConnectionOnly: pointer.Bool(true),
+						SeedConnectionString:  "test:test@127.0.0.1:4501",
+					},
 				},
-				Entry("Adding single instance",
-					testCase{
-						Instances:                     []ProcessGroupID{"instance-2"},
-						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1", "instance-2"},
-					}),
-				Entry("Adding multiple instances",
-					testCase{
-						Instances:                     []ProcessGroupID{"instance-2", "instance-3"},
-						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1", "instance-2", "instance-3"},
-					}),
-			)
-		})
-
-	})
-
-	When("removing processes from the no-schedule list", func() {
-		var cluster *FoundationDBCluster
-
-		BeforeEach(func() {
-			cluster = &FoundationDBCluster{
-				Spec: FoundationDBClusterSpec{
-					Buggify: BuggifyConfig{
-						NoSchedule: []ProcessGroupID{"instance-1", "instance-2", "instance-3"},
+				nil,
+			),
+			Entry("using tenants on an unsupported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.0.0",
+						Tenants: []TenantSpec{{Name: "tenant1"}},
 					},
 				},
-			}
-		})
-
-		type testCase struct {
-			Instances                         []ProcessGroupID
-			ExpectedInstancesInNoScheduleList []ProcessGroupID
-		}
-
-		DescribeTable("should remove all targeted processes from the no-schedule list",
-			func(tc testCase) {
-				cluster.RemoveProcessGroupsFromNoScheduleList(tc.Instances)
-				Expect(cluster.Spec.Buggify.NoSchedule).To(ContainElements(tc.ExpectedInstancesInNoScheduleList))
-				Expect(len(cluster.Spec.Buggify.NoSchedule)).To(Equal(len(tc.ExpectedInstancesInNoScheduleList)))
-			},
-			Entry("Removing single instance",
-				testCase{
-					Instances:                         []ProcessGroupID{"instance-1"},
-					ExpectedInstancesInNoScheduleList: []ProcessGroupID{"instance-2", "instance-3"},
-				}),
-			Entry("Removing multiple instances",
-				testCase{
-					Instances:                         []ProcessGroupID{"instance-2", "instance-3"},
-					ExpectedInstancesInNoScheduleList: []ProcessGroupID{"instance-1"},
-				}),
-		)
-
-	})
-
-	When("adding processes to the crash-loop list", func() {
-		var cluster *FoundationDBCluster
-
-		BeforeEach(func() {
-			cluster = &FoundationDBCluster{}
-		})
-
-		When("the crash-loop list is empty", func() {
-			type testCase struct {
-				Instances                    []ProcessGroupID
-				ExpectedInstancesInCrashLoop []ProcessGroupID
-			}
-
-			DescribeTable("should add all targeted processes to the crash-loop list",
-				func(tc testCase) {
+				fmt.Errorf("tenants are not supported on version 7.0.0"),
+			),
+			Entry("using duplicate tenants",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsTenants.String(),
+						Tenants: []TenantSpec{{Name: "tenant1"}, {Name: "tenant1", Remove: true}},
+					},
+				},
+				fmt.Errorf("tenant tenant1 is defined multiple times"),
+			),
+			Entry("using tenants on a supported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsTenants.String(),
+						Tenants: []TenantSpec{{Name: "tenant1"}, {Name: "tenant2", Remove: true}},
+					},
+				},
+				nil,
+			),
+			Entry("using the perpetual storage wiggle on an unsupported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "6.3.24",
+						DatabaseConfiguration: DatabaseConfiguration{
+							PerpetualStorageWiggle: pointer.Int(1),
+						},
+					},
+				},
+				fmt.Errorf("perpetual storage wiggle is not supported on version 6.3.24"),
+			),
+			Entry("using the perpetual storage wiggle on a supported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsPerpetualStorageWiggle.String(),
+						DatabaseConfiguration: DatabaseConfiguration{
+							PerpetualStorageWiggle: pointer.Int(1),
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using a perpetual storage wiggle locality on an unsupported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.0.0",
+						DatabaseConfiguration: DatabaseConfiguration{
+							PerpetualStorageWiggleLocality: "zoneid:zone1",
+						},
+					},
+				},
+				fmt.Errorf("perpetual storage wiggle locality is not supported on version 7.0.0"),
+			),
+			Entry("using an invalid perpetual storage wiggle locality",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsPerpetualStorageWiggleLocality.String(),
+						DatabaseConfiguration: DatabaseConfiguration{
+							PerpetualStorageWiggleLocality: "zone1",
+						},
+					},
+				},
+				fmt.Errorf("perpetual storage wiggle locality zone1 must have the format <key>:<value> or be 0"),
+			),
+			Entry("using a valid perpetual storage wiggle locality",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsPerpetualStorageWiggleLocality.String(),
+						DatabaseConfiguration: DatabaseConfiguration{
+							PerpetualStorageWiggleLocality: "zoneid:zone1",
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using tag quotas on an unsupported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:   "7.1.0",
+						TagQuotas: []TagQuota{{Tag: "tenant1", TotalThroughput: 1000}},
+					},
+				},
+				fmt.Errorf("tag quotas are not supported on version 7.1.0"),
+			),
+			Entry("using duplicate tag quotas",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:   Versions.SupportsTagQuotas.String(),
+						TagQuotas: []TagQuota{{Tag: "tenant1", TotalThroughput: 1000}, {Tag: "tenant1"}},
+					},
+				},
+				fmt.Errorf("tag quota for tenant1 is defined multiple times"),
+			),
+			Entry("using a reserved throughput greater than the total throughput",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:   Versions.SupportsTagQuotas.String(),
+						TagQuotas: []TagQuota{{Tag: "tenant1", TotalThroughput: 100, ReservedThroughput: 1000}},
+					},
+				},
+				fmt.Errorf("reserved throughput for tag tenant1 must not be greater than the total throughput"),
+			),
+			Entry("using the ProcessPerContainer pod layout without the unified image",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:   Versions.Default.String(),
+						PodLayout: PodLayoutProcessPerContainer,
+					},
+				},
+				fmt.Errorf("podLayout ProcessPerContainer requires the unified image"),
+			),
+			Entry("using the ProcessPerContainer pod layout with the unified image",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:         Versions.Default.String(),
+						PodLayout:       PodLayoutProcessPerContainer,
+						UseUnifiedImage: pointer.Bool(true),
+					},
+				},
+				nil,
+			),
+			Entry("using the ProcessPerContainer pod layout with a parameter that references a secret",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:         Versions.Default.String(),
+						PodLayout:       PodLayoutProcessPerContainer,
+						UseUnifiedImage: pointer.Bool(true),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {
+								Env: []corev1.EnvVar{{
+									Name: "KNOB_VALUE",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "knobs"},
+											Key:                  "value",
+										},
+									},
+								}},
+								Parameters: FoundationDBParameters{{Name: "knob_test", Value: "$KNOB_VALUE"}},
+							},
+						},
+					},
+				},
+				fmt.Errorf("environment variable KNOB_VALUE of process class storage must have a literal value to be referenced in the parameters with podLayout ProcessPerContainer"),
+			),
+			Entry("using an additional container with a name reserved for the process containers",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:              Versions.Default.String(),
+						PodLayout:            PodLayoutProcessPerContainer,
+						UseUnifiedImage:      pointer.Bool(true),
+						StorageServersPerPod: 2,
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {
+								AdditionalContainers: []corev1.Container{{Name: "foundationdb-2"}},
+							},
+						},
+					},
+				},
+				fmt.Errorf("additional container foundationdb-2 for process class general must have a unique name that is not used by the operator"),
+			),
+			Entry("using tag quotas on a supported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:   Versions.SupportsTagQuotas.String(),
+						TagQuotas: []TagQuota{{Tag: "tenant1", TotalThroughput: 1000, ReservedThroughput: 100}},
+					},
+				},
+				nil,
+			),
+			Entry("using a pin without a node name and a zone",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:             Versions.Default.String(),
+						PinnedProcessGroups: map[ProcessGroupID]ProcessGroupPin{"storage-1": {}},
+					},
+				},
+				fmt.Errorf("pin for process group storage-1 must define either a node name or a zone"),
+			),
+			Entry("using a pin with a node name and a zone",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:             Versions.Default.String(),
+						PinnedProcessGroups: map[ProcessGroupID]ProcessGroupPin{"storage-1": {NodeName: "node-1", Zone: "zone-a"}},
+					},
+				},
+				fmt.Errorf("pin for process group storage-1 must define either a node name or a zone"),
+			),
+			Entry("using a zone pin without a fault domain",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						FaultDomain: FoundationDBClusterFaultDomain{
+							Key: NoneFaultDomainKey,
+						},
+						PinnedProcessGroups: map[ProcessGroupID]ProcessGroupPin{"storage-1": {Zone: "zone-a"}},
+					},
+				},
+				fmt.Errorf("pin for process group storage-1 cannot use a zone with the fault domain key foundationdb.org/none"),
+			),
+			Entry("using a valid node pin",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:             Versions.Default.String(),
+						PinnedProcessGroups: map[ProcessGroupID]ProcessGroupPin{"storage-1": {NodeName: "node-1"}},
+					},
+				},
+				nil,
+			),
+			Entry("reassigning a process group to an unknown process class",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:                 Versions.Default.String(),
+						ProcessGroupsToReassign: map[ProcessGroupID]ProcessClass{"storage-1": "unknown"},
+					},
+				},
+				fmt.Errorf("process group storage-1 cannot be reassigned to the unknown process class unknown"),
+			),
+			Entry("reassigning a process group to a known process class",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:                 Versions.Default.String(),
+						ProcessGroupsToReassign: map[ProcessGroupID]ProcessClass{"storage-1": ProcessClassLog},
+					},
+				},
+				nil,
+			),
+			Entry("reassigning more process groups than the process count of the process class",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:                 Versions.Default.String(),
+						ProcessGroupsToReassign: map[ProcessGroupID]ProcessClass{"storage-1": ProcessClassLog, "storage-2": ProcessClassLog},
+						ProcessCounts:           ProcessCounts{Log: 1},
+					},
+				},
+				fmt.Errorf("process count for process class log must be at least 2 to keep the reassigned process groups"),
+			),
+			Entry("reassigning a process group that already has the new process class",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:                 Versions.Default.String(),
+						ProcessGroupsToReassign: map[ProcessGroupID]ProcessClass{"storage-1": ProcessClassLog, "log-2": ProcessClassLog},
+						ProcessCounts:           ProcessCounts{Log: 1},
+					},
+					Status: FoundationDBClusterStatus{
+						ProcessGroups: []*ProcessGroupStatus{{ProcessGroupID: "log-2", ProcessClass: ProcessClassLog}},
+					},
+				},
+				nil,
+			),
+			Entry("using image configs for multiple architectures with an architecture for every process class",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {Architecture: "amd64"},
+							ProcessClassStorage: {Architecture: "arm64"},
+						},
+						MainContainer: ContainerOverrides{
+							ImageConfigs: []ImageConfig{{Architecture: "arm64", TagSuffix: "-arm64"}, {Architecture: "amd64", TagSuffix: "-amd64"}},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using image configs for multiple architectures without an architecture for the process classes",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {Architecture: "arm64"},
+						},
+						MainContainer: ContainerOverrides{
+							ImageConfigs: []ImageConfig{{Architecture: "arm64", TagSuffix: "-arm64"}},
+						},
+						SidecarContainer: ContainerOverrides{
+							ImageConfigs: []ImageConfig{{Architecture: "amd64", TagSuffix: "-1-amd64"}},
+						},
+					},
+				},
+				fmt.Errorf("process class log must define an architecture as the image configs define images for the architectures amd64, arm64, process class stateless must define an architecture as the image configs define images for the architectures amd64, arm64"),
+			),
+			Entry("using the token based authorization on an unsupported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.1.0",
+						MainContainer: ContainerOverrides{
+							EnableTLS: true,
+						},
+						Authorization: &AuthorizationSpec{
+							PublicKeySecrets: []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "keys"}, Key: "jwks.json"}},
+						},
+					},
+				},
+				fmt.Errorf("token based authorization is not supported on version 7.1.0"),
+			),
+			Entry("using the token based authorization without TLS",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsAuthorization.String(),
+						Authorization: &AuthorizationSpec{
+							PublicKeySecrets: []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "keys"}, Key: "jwks.json"}},
+						},
+					},
+				},
+				fmt.Errorf("token based authorization requires TLS to be enabled for the main container"),
+			),
+			Entry("using the token based authorization with a secret without a key",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsAuthorization.String(),
+						MainContainer: ContainerOverrides{
+							EnableTLS: true,
+						},
+						Authorization: &AuthorizationSpec{
+							PublicKeySecrets: []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "keys"}}},
+						},
+					},
+				},
+				fmt.Errorf("public key secrets for the token based authorization must define a name and a key"),
+			),
+			Entry("using the token based authorization on a supported version",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.SupportsAuthorization.String(),
+						MainContainer: ContainerOverrides{
+							EnableTLS: true,
+						},
+						Authorization: &AuthorizationSpec{
+							PublicKeySecrets: []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "keys"}, Key: "jwks.json"}},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using an additional container with a name used by the operator",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {
+								AdditionalContainers: []corev1.Container{{Name: SidecarContainerName}},
+							},
+						},
+					},
+				},
+				fmt.Errorf("additional container foundationdb-kubernetes-sidecar for process class general must have a unique name that is not used by the operator"),
+			),
+			Entry("cloning a cluster without a source cluster",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						CloneFrom: &CloneFromSpec{
+							VolumeSnapshots: map[ProcessGroupID]string{"storage-1": "snapshot-storage-1"},
+						},
+					},
+				},
+				fmt.Errorf("cloneFrom.clusterName must be set to the name of another cluster"),
+			),
+			Entry("cloning a cluster without VolumeSnapshots",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						CloneFrom: &CloneFromSpec{
+							ClusterName: "source",
+						},
+					},
+				},
+				fmt.Errorf("cloneFrom.volumeSnapshots must contain at least one VolumeSnapshot"),
+			),
+			Entry("cloning a cluster with a seed connection string",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:              Versions.Default.String(),
+						SeedConnectionString: "test:abcd@127.0.0.1:4501",
+						CloneFrom: &CloneFromSpec{
+							ClusterName:     "source",
+							VolumeSnapshots: map[ProcessGroupID]string{"storage-1": "snapshot-storage-1"},
+						},
+					},
+				},
+				fmt.Errorf("cloneFrom and seedConnectionString cannot be used together"),
+			),
+			Entry("cloning a cluster",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						CloneFrom: &CloneFromSpec{
+							ClusterName:     "source",
+							VolumeSnapshots: map[ProcessGroupID]string{"storage-1": "snapshot-storage-1"},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using a trace log forwarder with the name of the main container",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						TraceLogs: &TraceLogSpec{
+							Forwarder: &corev1.Container{Name: MainContainerName, Image: "log-forwarder:1.0"},
+						},
+					},
+				},
+				fmt.Errorf("trace log forwarder container must not use the name foundationdb"),
+			),
+			Entry("using a trace log forwarder without an image",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						TraceLogs: &TraceLogSpec{
+							Forwarder: &corev1.Container{},
+						},
+					},
+				},
+				fmt.Errorf("trace log forwarder container must define an image"),
+			),
+			Entry("using a valid trace log forwarder",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						TraceLogs: &TraceLogSpec{
+							Format:    TraceLogFormatJSON,
+							Forwarder: &corev1.Container{Image: "log-forwarder:1.0"},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using additional containers with duplicate names",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {
+								AdditionalContainers:     []corev1.Container{{Name: "agent"}},
+								AdditionalInitContainers: []corev1.Container{{Name: "agent"}},
+							},
+						},
+					},
+				},
+				fmt.Errorf("additional container agent for process class general must have a unique name that is not used by the operator"),
+			),
+			Entry("using additional containers with unique names",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {
+								AdditionalContainers:     []corev1.Container{{Name: "agent"}},
+								AdditionalInitContainers: []corev1.Container{{Name: "setup"}},
+							},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using the DNS policy None without nameservers",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {
+								DNS: &PodDNSSettings{Policy: corev1.DNSNone},
+							},
+						},
+					},
+				},
+				fmt.Errorf("DNS policy None for process class storage requires nameservers in the dnsConfig of the Pod template"),
+			),
+			Entry("using the DNS policy None with nameservers",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {
+								PodTemplate: &corev1.PodTemplateSpec{
+									Spec: corev1.PodSpec{
+										DNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}},
+									},
+								},
+								DNS: &PodDNSSettings{Policy: corev1.DNSNone},
+							},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using a minimum storage process count greater than the maximum",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: Versions.Default.String(),
+						StorageAutoscaling: &StorageAutoscalingSpec{
+							MinStorageProcesses: 10,
+							MaxStorageProcesses: 5,
+						},
+					},
+				},
+				fmt.Errorf("minStorageProcesses 10 must not be greater than maxStorageProcesses 5"),
+			),
+			Entry("migrating an external cluster without a seed connection string",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.1.26",
+						ExternalMigration: &ExternalMigrationSpec{
+							ExternalProcessAddresses: []string{"127.0.0.1:4501"},
+						},
+					},
+				},
+				fmt.Errorf("seedConnectionString must be set if externalMigration is defined"),
+			),
+			Entry("migrating an external cluster with an invalid address",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:              "7.1.26",
+						SeedConnectionString: "test:test@127.0.0.1:4501",
+						ExternalMigration: &ExternalMigrationSpec{
+							ExternalProcessAddresses: []string{"127.0.0.1:port"},
+						},
+					},
+				},
+				fmt.Errorf("externalProcessAddresses contains an invalid address: strconv.Atoi: parsing \"port\": invalid syntax"),
+			),
+			Entry("migrating an external cluster",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:              "7.1.26",
+						SeedConnectionString: "test:test@127.0.0.1:4501",
+						ExternalMigration: &ExternalMigrationSpec{
+							ExternalProcessAddresses: []string{"127.0.0.1:4501", "127.0.0.2:4501:tls"},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using the host network",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.1.26",
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {UseHostNetwork: pointer.Bool(true)},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("using the host network with too many storage servers per Pod",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:              "7.1.26",
+						StorageServersPerPod: 11,
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {UseHostNetwork: pointer.Bool(true)},
+						},
+					},
+				},
+				fmt.Errorf("storageServersPerPod 11 must not be greater than 10 if the storage processes use the host network"),
+			),
+			Entry("using the host network with the unified image",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version:         "7.1.26",
+						UseUnifiedImage: pointer.Bool(true),
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassGeneral: {UseHostNetwork: pointer.Bool(true)},
+						},
+					},
+				},
+				fmt.Errorf("useHostNetwork is not supported with the unified image"),
+			),
+			Entry("valid volume claim template selectors",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.1.26",
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {VolumeClaimTemplateSelectors: []VolumeClaimTemplateSelector{
+								{Name: "zone-a", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "a"}},
+								{Name: "zone-b", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "b"}},
+							}},
+						},
+					},
+				},
+				nil,
+			),
+			Entry("volume claim template selectors with duplicate names and without node selector",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.1.26",
+						Processes: map[ProcessClass]ProcessSettings{
+							ProcessClassStorage: {VolumeClaimTemplateSelectors: []VolumeClaimTemplateSelector{
+								{Name: "zone-a", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "a"}},
+								{Name: "zone-a"},
+							}},
+						},
+					},
+				},
+				fmt.Errorf("volume claim template selector zone-a for process class storage must have a unique name, volume claim template selector zone-a for process class storage must define a node selector"),
+			),
+		)
+	})
+
+	When("getting the volume claim template", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Processes: map[ProcessClass]ProcessSettings{
+						ProcessClassGeneral: {
+							VolumeClaimTemplate: &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "general"}},
+						},
+						ProcessClassStorage: {
+							VolumeClaimTemplateSelectors: []VolumeClaimTemplateSelector{
+								{
+									Name:                "zone-a",
+									NodeSelector:        map[string]string{"topology.kubernetes.io/zone": "a"},
+									VolumeClaimTemplate: &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "nvme"}},
+								},
+								{Name: "zone-b", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "b"}},
+								{Name: "zone-c", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "c"}},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("should use the entry recorded in the status of the process group", func() {
+			cluster.Status.ProcessGroups = []*ProcessGroupStatus{
+				{ProcessGroupID: "storage-1", ProcessClass: ProcessClassStorage, VolumeClaimTemplateSelector: "zone-b"},
+				{ProcessGroupID: "storage-2", ProcessClass: ProcessClassStorage, VolumeClaimTemplateSelector: "zone-d"},
+				{ProcessGroupID: "storage-3", ProcessClass: ProcessClassStorage},
+				{ProcessGroupID: "log-1", ProcessClass: ProcessClassLog, VolumeClaimTemplateSelector: "zone-a"},
+			}
+
+			Expect(cluster.GetVolumeClaimTemplateSelector(ProcessClassStorage, "storage-1").Name).To(Equal("zone-b"))
+			Expect(cluster.GetVolumeClaimTemplateSelector(ProcessClassStorage, "storage-2")).To(BeNil())
+			Expect(cluster.GetVolumeClaimTemplateSelector(ProcessClassStorage, "storage-3")).To(BeNil())
+			Expect(cluster.GetVolumeClaimTemplateSelector(ProcessClassStorage, "storage-4")).To(BeNil())
+			Expect(cluster.GetVolumeClaimTemplateSelector(ProcessClassLog, "log-1")).To(BeNil())
+
+			Expect(cluster.NeedsVolumeClaimTemplateSelector(cluster.Status.ProcessGroups[0])).To(BeFalse())
+			Expect(cluster.NeedsVolumeClaimTemplateSelector(cluster.Status.ProcessGroups[1])).To(BeTrue())
+			Expect(cluster.NeedsVolumeClaimTemplateSelector(cluster.Status.ProcessGroups[2])).To(BeTrue())
+			Expect(cluster.NeedsVolumeClaimTemplateSelector(cluster.Status.ProcessGroups[3])).To(BeFalse())
+		})
+
+		It("should choose the entry with the fewest process groups", func() {
+			Expect(cluster.ChooseVolumeClaimTemplateSelector(ProcessClassStorage)).To(Equal("zone-a"))
+			Expect(cluster.ChooseVolumeClaimTemplateSelector(ProcessClassLog)).To(BeEmpty())
+
+			removed := &ProcessGroupStatus{ProcessGroupID: "storage-4", ProcessClass: ProcessClassStorage, VolumeClaimTemplateSelector: "zone-b"}
+			removed.MarkForRemoval()
+			cluster.Status.ProcessGroups = []*ProcessGroupStatus{
+				{ProcessGroupID: "storage-1", ProcessClass: ProcessClassStorage, VolumeClaimTemplateSelector: "zone-a"},
+				{ProcessGroupID: "storage-2", ProcessClass: ProcessClassStorage, VolumeClaimTemplateSelector: "zone-c"},
+				removed,
+			}
+			Expect(cluster.ChooseVolumeClaimTemplateSelector(ProcessClassStorage)).To(Equal("zone-b"))
+		})
+
+		It("should fall back to the volume claim template of the process class", func() {
+			cluster.Status.ProcessGroups = []*ProcessGroupStatus{
+				{ProcessGroupID: "storage-1", ProcessClass: ProcessClassStorage, VolumeClaimTemplateSelector: "zone-b"},
+				{ProcessGroupID: "storage-3", ProcessClass: ProcessClassStorage, VolumeClaimTemplateSelector: "zone-a"},
+			}
+
+			Expect(cluster.GetVolumeClaimTemplate(ProcessClassStorage, "storage-3").Name).To(Equal("nvme"))
+			Expect(cluster.GetVolumeClaimTemplate(ProcessClassStorage, "storage-1").Name).To(Equal("general"))
+			Expect(cluster.GetVolumeClaimTemplate(ProcessClassLog, "log-3").Name).To(Equal("general"))
+		})
+	})
+
+	DescribeTable("getting the ports for a process class", func(cluster *FoundationDBCluster, processClass ProcessClass, expectedPort int, expectedSidecarPort int) {
+		Expect(cluster.GetProcessPortForProcessClass(processClass, 1, false)).To(Equal(expectedPort))
+		Expect(cluster.GetSidecarPort(processClass)).To(Equal(expectedSidecarPort))
+	},
+		Entry("the host network is not used",
+			&FoundationDBCluster{},
+			ProcessClassLog,
+			4501,
+			8080,
+		),
+		Entry("the host network is used for another process class",
+			&FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Processes: map[ProcessClass]ProcessSettings{
+						ProcessClassStorage: {UseHostNetwork: pointer.Bool(true)},
+					},
+				},
+			},
+			ProcessClassLog,
+			4501,
+			8080,
+		),
+		Entry("the host network is used for the storage processes",
+			&FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Processes: map[ProcessClass]ProcessSettings{
+						ProcessClassStorage: {UseHostNetwork: pointer.Bool(true)},
+					},
+				},
+			},
+			ProcessClassStorage,
+			4521,
+			8082,
+		),
+		Entry("the host network is used for all process classes",
+			&FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Processes: map[ProcessClass]ProcessSettings{
+						ProcessClassGeneral: {UseHostNetwork: pointer.Bool(true)},
+					},
+				},
+			},
+			ProcessClassLog,
+			4721,
+			8092,
+		),
+	)
+
+	When("getting the pending tenant changes", func() {
+		It("should return the tenants to create and to remove", func() {
+			cluster := &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Tenants: []TenantSpec{
+						{Name: "missing"},
+						{Name: "existing"},
+						{Name: "removed", Remove: true},
+						{Name: "to-remove", Remove: true},
+					},
+				},
+				Status: FoundationDBClusterStatus{
+					Tenants: []TenantStatus{
+						{Name: "existing"},
+						{Name: "to-remove"},
+						{Name: "unmanaged"},
+					},
+				},
+			}
+
+			tenantsToCreate, tenantsToRemove := cluster.GetPendingTenantChanges()
+			Expect(tenantsToCreate).To(ConsistOf("missing"))
+			Expect(tenantsToRemove).To(ConsistOf("to-remove"))
+		})
+	})
+
+	When("adding processes to the no-schedule list", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{}
+		})
+
+		When("the no-schedule list is empty", func() {
+			type testCase struct {
+				Instances                     []ProcessGroupID
+				ExpectedInstancesInNoSchedule []ProcessGroupID
+			}
+
+			DescribeTable("should add all targeted processes to the no-schedule list",
+				func(tc testCase) {
+					cluster.AddProcessGroupsToNoScheduleList(tc.Instances)
+					Expect(cluster.Spec.Buggify.NoSchedule).To(ContainElements(tc.ExpectedInstancesInNoSchedule))
+					Expect(len(cluster.Spec.Buggify.NoSchedule)).To(Equal(len(tc.ExpectedInstancesInNoSchedule)))
+				},
+				Entry("Adding single instance",
+					testCase{
+						Instances:                     []ProcessGroupID{"instance-1"},
+						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1"},
+					}),
+				Entry("Adding multiple instances",
+					testCase{
+						Instances:                     []ProcessGroupID{"instance-1", "instance-2"},
+						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1", "instance-2"},
+					}),
+			)
+		})
+
+		When("the no-schedule list is not empty", func() {
+			BeforeEach(func() {
+				cluster.Spec.Buggify.NoSchedule = []ProcessGroupID{"instance-1"}
+			})
+
+			type testCase struct {
+				Instances                     []ProcessGroupID
+				ExpectedInstancesInNoSchedule []ProcessGroupID
+			}
+
+			DescribeTable("should add all targeted processes to no-schedule list",
+				func(tc testCase) {
+					cluster.AddProcessGroupsToNoScheduleList(tc.Instances)
+					Expect(cluster.Spec.Buggify.NoSchedule).To(ContainElements(tc.ExpectedInstancesInNoSchedule))
+					Expect(len(cluster.Spec.Buggify.NoSchedule)).To(Equal(len(tc.ExpectedInstancesInNoSchedule)))
+				},
+				Entry("Adding single instance",
+					testCase{
+						Instances:                     []ProcessGroupID{"instance-2"},
+						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1", "instance-2"},
+					}),
+				Entry("Adding multiple instances",
+					testCase{
+						Instances:                     []ProcessGroupID{"instance-2", "instance-3"},
+						ExpectedInstancesInNoSchedule: []ProcessGroupID{"instance-1", "instance-2", "instance-3"},
+					}),
+			)
+		})
+
+	})
+
+	When("removing processes from the no-schedule list", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Buggify: BuggifyConfig{
+						NoSchedule: []ProcessGroupID{"instance-1", "instance-2", "instance-3"},
+					},
+				},
+			}
+		})
+
+		type testCase struct {
+			Instances                         []ProcessGroupID
+			ExpectedInstancesInNoScheduleList []ProcessGroupID
+		}
+
+		DescribeTable("should remove all targeted processes from the no-schedule list",
+			func(tc testCase) {
+				cluster.RemoveProcessGroupsFromNoScheduleList(tc.Instances)
+				Expect(cluster.Spec.Buggify.NoSchedule).To(ContainElements(tc.ExpectedInstancesInNoScheduleList))
+				Expect(len(cluster.Spec.Buggify.NoSchedule)).To(Equal(len(tc.ExpectedInstancesInNoScheduleList)))
+			},
+			Entry("Removing single instance",
+				testCase{
+					Instances:                         []ProcessGroupID{"instance-1"},
+					ExpectedInstancesInNoScheduleList: []ProcessGroupID{"instance-2", "instance-3"},
+				}),
+			Entry("Removing multiple instances",
+				testCase{
+					Instances:                         []ProcessGroupID{"instance-2", "instance-3"},
+					ExpectedInstancesInNoScheduleList: []ProcessGroupID{"instance-1"},
+				}),
+		)
+
+	})
+
+	When("adding processes to the crash-loop list", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{}
+		})
+
+		When("the crash-loop list is empty", func() {
+			type testCase struct {
+				Instances                    []ProcessGroupID
+				ExpectedInstancesInCrashLoop []ProcessGroupID
+			}
+
+			DescribeTable("should add all targeted processes to the crash-loop list",
+				func(tc testCase) {
 					cluster.AddProcessGroupsToCrashLoopList(tc.Instances)
 					Expect(cluster.Spec.Buggify.CrashLoop).To(ContainElements(tc.ExpectedInstancesInCrashLoop))
 					Expect(len(cluster.Spec.Buggify.CrashLoop)).To(Equal(len(tc.ExpectedInstancesInCrashLoop)))
@@ -5018,4 +6364,174 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				},
 			}, true, false),
 	)
+
+	DescribeTable("when checking if the cluster is being downgraded", func(cluster *FoundationDBCluster, expected bool) {
+		Expect(cluster.IsBeingDowngraded()).To(Equal(expected))
+	}, Entry("no version change in progress",
+		&FoundationDBCluster{
+			Spec: FoundationDBClusterSpec{
+				Version: "7.1.27",
+			},
+			Status: FoundationDBClusterStatus{
+				RunningVersion: "7.1.27",
+			},
+		}, false),
+		Entry("patch upgrade",
+			&FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: "7.1.29",
+				},
+				Status: FoundationDBClusterStatus{
+					RunningVersion: "7.1.27",
+				},
+			}, false),
+		Entry("patch downgrade",
+			&FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: "7.1.25",
+				},
+				Status: FoundationDBClusterStatus{
+					RunningVersion: "7.1.27",
+				},
+			}, true),
+		Entry("minor downgrade",
+			&FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: "7.0.0",
+				},
+				Status: FoundationDBClusterStatus{
+					RunningVersion: "7.1.27",
+				},
+			}, true),
+	)
+
+	DescribeTable("getting the command timeout", func(cluster *FoundationDBCluster, expected time.Duration) {
+		Expect(cluster.GetCommandTimeout(10 * time.Second)).To(Equal(expected))
+	},
+		Entry("no command timeout is defined",
+			&FoundationDBCluster{}, 10*time.Second),
+		Entry("a command timeout is defined",
+			&FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					AutomationOptions: FoundationDBClusterAutomationOptions{
+						CommandTimeoutSeconds: pointer.Int(30),
+					},
+				},
+			}, 30*time.Second),
+	)
+
+	DescribeTable("getting the storage selector", func(cluster *FoundationDBCluster, expected string) {
+		Expect(cluster.GetStorageSelector()).To(Equal(expected))
+	},
+		Entry("default labels",
+			&FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+			}, "foundationdb.org/fdb-cluster-name=test,foundationdb.org/fdb-process-class=storage"),
+		Entry("custom labels",
+			&FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: FoundationDBClusterSpec{
+					LabelConfig: LabelConfig{
+						MatchLabels: map[string]string{
+							"app": "fdb",
+						},
+						ProcessClassLabels: []string{"fdb-class"},
+					},
+				},
+			}, "app=fdb,fdb-class=storage"),
+	)
+
+	DescribeTable("checking if a database configuration drift should be reverted", func(mode ConfigureDatabaseMode, modify func(configuration *DatabaseConfiguration), expected bool) {
+		cluster := &FoundationDBCluster{
+			Spec: FoundationDBClusterSpec{
+				Version: Versions.Default.String(),
+				DatabaseConfiguration: DatabaseConfiguration{
+					RedundancyMode: RedundancyModeDouble,
+					StorageEngine:  StorageEngineSSD2,
+					UsableRegions:  1,
+				},
+				AutomationOptions: FoundationDBClusterAutomationOptions{
+					ConfigureDatabaseMode: mode,
+				},
+			},
+		}
+		cluster.Status.DatabaseConfiguration = cluster.DesiredDatabaseConfiguration()
+		modify(&cluster.Status.DatabaseConfiguration)
+
+		Expect(cluster.ShouldRevertDatabaseConfigurationDrift()).To(Equal(expected))
+	},
+		Entry("default mode with changed role counts", ConfigureDatabaseMode(""), func(configuration *DatabaseConfiguration) {
+			configuration.Logs = 10
+		}, true),
+		Entry("default mode with changed redundancy mode", ConfigureDatabaseMode(""), func(configuration *DatabaseConfiguration) {
+			configuration.RedundancyMode = RedundancyModeTriple
+		}, true),
+		Entry("IfSafe mode with changed role counts", ConfigureDatabaseModeIfSafe, func(configuration *DatabaseConfiguration) {
+			configuration.Logs = 10
+		}, true),
+		Entry("IfSafe mode with changed redundancy mode", ConfigureDatabaseModeIfSafe, func(configuration *DatabaseConfiguration) {
+			configuration.RedundancyMode = RedundancyModeTriple
+		}, false),
+		Entry("IfSafe mode with changed storage engine", ConfigureDatabaseModeIfSafe, func(configuration *DatabaseConfiguration) {
+			configuration.StorageEngine = StorageEngineMemory
+		}, false),
+		Entry("IfSafe mode with changed regions", ConfigureDatabaseModeIfSafe, func(configuration *DatabaseConfiguration) {
+			configuration.Regions = []Region{{DataCenters: []DataCenter{{ID: "dc1"}}}}
+		}, false),
+		Entry("Never mode with changed role counts", ConfigureDatabaseModeNever, func(configuration *DatabaseConfiguration) {
+			configuration.Logs = 10
+		}, false),
+	)
+
+	When("tracking the tombstones of removed process groups", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{}
+			cluster.AddProcessGroupTombstone("storage-1")
+		})
+
+		It("should add a tombstone for the process group", func() {
+			Expect(cluster.Status.ProcessGroupTombstones).To(HaveLen(1))
+			Expect(cluster.HasProcessGroupTombstone("storage-1")).To(BeTrue())
+			Expect(cluster.HasProcessGroupTombstone("storage-2")).To(BeFalse())
+		})
+
+		When("the tombstone is expired", func() {
+			BeforeEach(func() {
+				expired := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+				cluster.Status.ProcessGroupTombstones[0].RemovalTimestamp = &expired
+			})
+
+			It("should not report the tombstone", func() {
+				Expect(cluster.GetActiveProcessGroupTombstones()).To(BeEmpty())
+				Expect(cluster.HasProcessGroupTombstone("storage-1")).To(BeFalse())
+			})
+
+			When("the tombstone is added again", func() {
+				BeforeEach(func() {
+					cluster.AddProcessGroupTombstone("storage-1")
+				})
+
+				It("should refresh the existing tombstone", func() {
+					Expect(cluster.Status.ProcessGroupTombstones).To(HaveLen(1))
+					Expect(cluster.HasProcessGroupTombstone("storage-1")).To(BeTrue())
+				})
+			})
+
+			When("the tombstone duration is increased", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.ProcessGroupTombstoneSeconds = pointer.Int(3 * 3600)
+				})
+
+				It("should report the tombstone", func() {
+					Expect(cluster.HasProcessGroupTombstone("storage-1")).To(BeTrue())
+				})
+			})
+		})
+	})
 })
@@ -0,0 +1,129 @@
+/*
+Copyright 2020-2022 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdbstatusreport
+// +kubebuilder:printcolumn:name="Available",type="boolean",JSONPath=".status.available"
+// +kubebuilder:printcolumn:name="Healthy",type="boolean",JSONPath=".status.healthy"
+// +kubebuilder:printcolumn:name="FullReplication",type="boolean",JSONPath=".status.fullReplication"
+// +kubebuilder:printcolumn:name="DataState",type="string",JSONPath=".status.dataState"
+// +kubebuilder:printcolumn:name="LastUpdated",type="date",JSONPath=".status.lastUpdated"
+// +kubebuilder:storageversion
+
+// FoundationDBClusterStatusReport is the Schema for the foundationdbclusterstatusreports API. A status report is
+// maintained by the operator for every cluster that has the status report enabled and has the same name as the
+// cluster. It contains a periodically updated snapshot of the machine-readable status of the cluster.
+type FoundationDBClusterStatusReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status FoundationDBClusterStatusReportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBClusterStatusReportList contains a list of FoundationDBClusterStatusReport objects
+type FoundationDBClusterStatusReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBClusterStatusReport `json:"items"`
+}
+
+// FoundationDBClusterStatusReportStatus contains the snapshot of the machine-readable status of a cluster and a
+// summary of the health of the cluster.
+type FoundationDBClusterStatusReportStatus struct {
+	// LastUpdated defines when the snapshot of the machine-readable status
+	// was taken.
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Error contains the error of the last attempt to fetch the
+	// machine-readable status. If the last attempt failed, the report
+	// contains the snapshot of the last successful attempt.
+	Error string `json:"error,omitempty"`
+
+	// Available defines if the database is available.
+	Available bool `json:"available,omitempty"`
+
+	// Healthy defines if the database is healthy.
+	Healthy bool `json:"healthy,omitempty"`
+
+	// FullReplication defines if the data in the database is fully
+	// replicated.
+	FullReplication bool `json:"fullReplication,omitempty"`
+
+	// DataState contains the name of the state of the data distribution.
+	DataState string `json:"dataState,omitempty"`
+
+	// RecoveryState contains the name of the current recovery state.
+	RecoveryState string `json:"recoveryState,omitempty"`
+
+	// FaultTolerance contains the fault tolerance of the cluster.
+	FaultTolerance FaultTolerance `json:"faultTolerance,omitempty"`
+
+	// DatabaseStatus contains the snapshot of the machine-readable status as
+	// parsed by the operator. The schema is defined by the FoundationDBStatus
+	// type.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	DatabaseStatus runtime.RawExtension `json:"databaseStatus,omitempty"`
+}
+
+// SetDatabaseStatus updates the report with the snapshot of the machine-readable status taken at the provided time.
+func (report *FoundationDBClusterStatusReport) SetDatabaseStatus(status *FoundationDBStatus, timestamp metav1.Time) error {
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	report.Status.LastUpdated = &timestamp
+	report.Status.Error = ""
+	report.Status.Available = status.Client.DatabaseStatus.Available
+	report.Status.Healthy = status.Client.DatabaseStatus.Healthy
+	report.Status.FullReplication = status.Cluster.FullReplication
+	report.Status.DataState = status.Cluster.Data.State.Name
+	report.Status.RecoveryState = status.Cluster.RecoveryState.Name
+	report.Status.FaultTolerance = status.Cluster.FaultTolerance
+	report.Status.DatabaseStatus = runtime.RawExtension{Raw: raw}
+
+	return nil
+}
+
+// GetDatabaseStatus returns the snapshot of the machine-readable status or nil if the report contains no snapshot.
+func (report *FoundationDBClusterStatusReport) GetDatabaseStatus() (*FoundationDBStatus, error) {
+	if len(report.Status.DatabaseStatus.Raw) == 0 {
+		return nil, nil
+	}
+
+	status := &FoundationDBStatus{}
+	err := json.Unmarshal(report.Status.DatabaseStatus.Raw, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBClusterStatusReport{}, &FoundationDBClusterStatusReportList{})
+}
@@ -0,0 +1,93 @@
+/*
+ * foundationdbclusterstatusreport_types_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("[api] FoundationDBClusterStatusReport", func() {
+	var report *FoundationDBClusterStatusReport
+
+	BeforeEach(func() {
+		report = &FoundationDBClusterStatusReport{}
+	})
+
+	When("the report contains no snapshot", func() {
+		It("should return no status", func() {
+			status, err := report.GetDatabaseStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(BeNil())
+		})
+	})
+
+	When("setting the snapshot of the status", func() {
+		var status *FoundationDBStatus
+		var timestamp metav1.Time
+
+		BeforeEach(func() {
+			status = &FoundationDBStatus{
+				Client: FoundationDBStatusLocalClientInfo{
+					DatabaseStatus: FoundationDBStatusClientDBStatus{
+						Available: true,
+					},
+				},
+				Cluster: FoundationDBStatusClusterInfo{
+					FullReplication: true,
+					Data: FoundationDBStatusDataStatistics{
+						State: FoundationDBStatusDataState{
+							Name: "healthy_repartitioning",
+						},
+					},
+					RecoveryState: RecoveryState{
+						Name: "fully_recovered",
+					},
+					FaultTolerance: FaultTolerance{
+						MaxZoneFailuresWithoutLosingData:         1,
+						MaxZoneFailuresWithoutLosingAvailability: 1,
+					},
+				},
+			}
+			timestamp = metav1.NewTime(time.Now())
+			report.Status.Error = "previous attempt failed"
+			Expect(report.SetDatabaseStatus(status, timestamp)).NotTo(HaveOccurred())
+		})
+
+		It("should update the summary", func() {
+			Expect(report.Status.LastUpdated).To(Equal(&timestamp))
+			Expect(report.Status.Error).To(BeEmpty())
+			Expect(report.Status.Available).To(BeTrue())
+			Expect(report.Status.Healthy).To(BeFalse())
+			Expect(report.Status.FullReplication).To(BeTrue())
+			Expect(report.Status.DataState).To(Equal("healthy_repartitioning"))
+			Expect(report.Status.RecoveryState).To(Equal("fully_recovered"))
+			Expect(report.Status.FaultTolerance).To(Equal(status.Cluster.FaultTolerance))
+		})
+
+		It("should return the snapshot", func() {
+			Expect(report.GetDatabaseStatus()).To(Equal(status))
+		})
+	})
+})
@@ -0,0 +1,81 @@
+/*
+Copyright 2020-2022 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultOperatorConfigName is the name of the FoundationDBOperatorConfig that is used for clusters that don't define
+// the operatorConfigName.
+const DefaultOperatorConfigName = "default"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=fdboperatorconfig
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+
+// FoundationDBOperatorConfig is the Schema for the foundationdboperatorconfigs API. An operator config contains the
+// defaults for all clusters that reference the config, the settings in the cluster spec take precedence over the
+// defaults. This resource is only used if the operator runs with the operator configs enabled.
+type FoundationDBOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FoundationDBOperatorConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBOperatorConfigList contains a list of FoundationDBOperatorConfig objects
+type FoundationDBOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBOperatorConfig `json:"items"`
+}
+
+// FoundationDBOperatorConfigSpec defines the defaults for the clusters that reference the config.
+type FoundationDBOperatorConfigSpec struct {
+	// MainContainerImageConfigs defines the default image configs for the
+	// main container. The image configs of the cluster take precedence over
+	// these image configs.
+	// +kubebuilder:validation:MaxItems=100
+	MainContainerImageConfigs []ImageConfig `json:"mainContainerImageConfigs,omitempty"`
+
+	// SidecarContainerImageConfigs defines the default image configs for the
+	// sidecar container. The image configs of the cluster take precedence
+	// over these image configs.
+	// +kubebuilder:validation:MaxItems=100
+	SidecarContainerImageConfigs []ImageConfig `json:"sidecarContainerImageConfigs,omitempty"`
+
+	// AutomationOptions defines the default automation options. Every option
+	// that is set in the cluster spec takes precedence over the option in the
+	// config.
+	AutomationOptions FoundationDBClusterAutomationOptions `json:"automationOptions,omitempty"`
+
+	// ResourcePresets defines the default resources of the main container per
+	// process class. A preset is only used if the pod template of the process
+	// class in the cluster spec defines no resources for the main container.
+	// The preset of the general process class is used for all process classes
+	// without their own preset.
+	ResourcePresets map[ProcessClass]corev1.ResourceRequirements `json:"resourcePresets,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBOperatorConfig{}, &FoundationDBOperatorConfigList{})
+}
@@ -17,12 +17,19 @@ limitations under the License.
 package v1beta2
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:shortName=fdbrestore
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.destinationClusterName",description="Name of the destination cluster"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Running",type="boolean",JSONPath=".status.running",description="Restore running"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:storageversion
 
@@ -53,20 +60,80 @@ type FoundationDBRestoreSpec struct {
 	// The key ranges to restore.
 	KeyRanges []FoundationDBKeyRange `json:"keyRanges,omitempty"`
 
+	// AddPrefix defines a prefix that is added to all keys that are
+	// restored, which allows to restore the data into a separate part of the
+	// key space of an existing cluster.
+	// +kubebuilder:validation:Pattern:=^[A-Za-z0-9\/\\-]+$
+	AddPrefix string `json:"addPrefix,omitempty"`
+
+	// RemovePrefix defines a prefix that is removed from all keys that are
+	// restored. All key ranges must start with this prefix, so the key ranges
+	// must be defined if a prefix should be removed.
+	// +kubebuilder:validation:Pattern:=^[A-Za-z0-9\/\\-]+$
+	RemovePrefix string `json:"removePrefix,omitempty"`
+
 	// This is the configuration of the target blobstore for this backup.
 	BlobStoreConfiguration *BlobStoreConfiguration `json:"blobStoreConfiguration,omitempty"`
 
 	// CustomParameters defines additional parameters to pass to the backup
 	// agents.
 	CustomParameters FoundationDBCustomParameters `json:"customParameters,omitempty"`
+
+	// DestinationClusterTemplate defines the cluster that the operator
+	// creates as the destination of the restore, if the destination cluster
+	// doesn't exist. The operator waits until the new cluster is available
+	// before starting the restore and tracks the restore until it is completed.
+	DestinationClusterTemplate *FoundationDBClusterTemplate `json:"destinationClusterTemplate,omitempty"`
+}
+
+// FoundationDBClusterTemplate describes a cluster that is created by the
+// operator.
+type FoundationDBClusterTemplate struct {
+	// Metadata defines the labels and annotations of the cluster.
+	Metadata *metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the spec of the cluster. The spec is validated when the
+	// cluster is created.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec FoundationDBClusterSpec `json:"spec"`
 }
 
 // FoundationDBRestoreStatus describes the current status of the restore for a cluster.
 type FoundationDBRestoreStatus struct {
 	// Running describes whether the restore is currently running.
 	Running bool `json:"running,omitempty"`
+
+	// Phase describes the phase of a restore that uses a destination cluster
+	// template.
+	Phase RestorePhase `json:"phase,omitempty"`
+
+	// Conditions provides the results of the checks the operator performs
+	// before it starts the restore, e.g. if the blobstore is reachable.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// RestorePhase describes the phase of a restore into a cluster that is
+// created by the operator.
+// +kubebuilder:validation:MaxLength=64
+type RestorePhase string
+
+const (
+	// RestorePhaseCreatingCluster indicates that the operator waits until the
+	// destination cluster is available.
+	RestorePhaseCreatingCluster RestorePhase = "CreatingCluster"
+	// RestorePhaseRestoring indicates that the restore is running.
+	RestorePhaseRestoring RestorePhase = "Restoring"
+	// RestorePhaseCompleted indicates that the restore is completed and the
+	// destination cluster is ready to use.
+	RestorePhaseCompleted RestorePhase = "Completed"
+	// RestorePhaseFailed indicates that the restore was aborted.
+	RestorePhaseFailed RestorePhase = "Failed"
+)
+
 // FoundationDBKeyRange describes a range of keys for a command.
 //
 // The keys in the key range must match the following pattern:
@@ -97,6 +164,43 @@ func (restore *FoundationDBRestore) BackupURL() string {
 	return restore.Spec.BlobStoreConfiguration.getURL(restore.BackupName(), restore.Spec.BlobStoreConfiguration.BucketName())
 }
 
+// UsesDestinationClusterTemplate determines whether the operator creates the
+// destination cluster and tracks the restore until it is completed.
+func (restore *FoundationDBRestore) UsesDestinationClusterTemplate() bool {
+	return restore.Spec.DestinationClusterTemplate != nil
+}
+
+// Validate checks if the settings of the restore are valid for the provided
+// version of the destination cluster, if not an error will be returned. If
+// multiple issues are found all of them will be returned in a single error.
+func (restore *FoundationDBRestore) Validate(version Version) error {
+	var validations []string
+
+	if restore.Spec.AddPrefix != "" || restore.Spec.RemovePrefix != "" {
+		if !version.SupportsRestorePrefixRemapping() {
+			validations = append(validations, fmt.Sprintf("adding or removing key prefixes is not supported on version %s", version))
+		}
+	}
+
+	if restore.Spec.RemovePrefix != "" {
+		if len(restore.Spec.KeyRanges) == 0 {
+			validations = append(validations, "removePrefix requires keyRanges that start with the prefix")
+		}
+
+		for _, keyRange := range restore.Spec.KeyRanges {
+			if !strings.HasPrefix(keyRange.Start, restore.Spec.RemovePrefix) || !strings.HasPrefix(keyRange.End, restore.Spec.RemovePrefix) {
+				validations = append(validations, fmt.Sprintf("key range %s - %s must start with the removePrefix %s", keyRange.Start, keyRange.End, restore.Spec.RemovePrefix))
+			}
+		}
+	}
+
+	if len(validations) > 0 {
+		return errors.New(strings.Join(validations, ", "))
+	}
+
+	return nil
+}
+
 func init() {
 	SchemeBuilder.Register(&FoundationDBRestore{}, &FoundationDBRestoreList{})
 }
@@ -118,4 +118,62 @@ var _ = Describe("[api] FoundationDBRestore", func() {
 				"blobstore://account@account/mybackup?bucket=fdb-backups&secure_connection=0"),
 		)
 	})
+
+	When("validating the restore", func() {
+		DescribeTable("should validate the key ranges and prefixes",
+			func(spec FoundationDBRestoreSpec, version Version, expected string) {
+				restore := FoundationDBRestore{Spec: spec}
+				err := restore.Validate(version)
+				if expected == "" {
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+
+				Expect(err).To(MatchError(expected))
+			},
+			Entry("a full restore",
+				FoundationDBRestoreSpec{},
+				Versions.Default,
+				""),
+			Entry("a restore of key ranges",
+				FoundationDBRestoreSpec{
+					KeyRanges: []FoundationDBKeyRange{{Start: "a", End: "b"}},
+				},
+				Versions.Default,
+				""),
+			Entry("a restore that adds a prefix",
+				FoundationDBRestoreSpec{
+					AddPrefix: "restored/",
+				},
+				Versions.SupportsRestorePrefixRemapping,
+				""),
+			Entry("a restore that adds a prefix on a version without prefix support",
+				FoundationDBRestoreSpec{
+					AddPrefix: "restored/",
+				},
+				Versions.Default,
+				"adding or removing key prefixes is not supported on version 6.2.21"),
+			Entry("a restore that replaces the prefix of the key ranges",
+				FoundationDBRestoreSpec{
+					KeyRanges:    []FoundationDBKeyRange{{Start: "app/a", End: "app/b"}},
+					AddPrefix:    "restored/",
+					RemovePrefix: "app/",
+				},
+				Versions.SupportsRestorePrefixRemapping,
+				""),
+			Entry("a restore that removes a prefix without key ranges",
+				FoundationDBRestoreSpec{
+					RemovePrefix: "app/",
+				},
+				Versions.SupportsRestorePrefixRemapping,
+				"removePrefix requires keyRanges that start with the prefix"),
+			Entry("a restore that removes a prefix that doesn't match the key ranges",
+				FoundationDBRestoreSpec{
+					KeyRanges:    []FoundationDBKeyRange{{Start: "app/a", End: "b"}},
+					RemovePrefix: "app/",
+				},
+				Versions.SupportsRestorePrefixRemapping,
+				"key range app/a - b must start with the removePrefix app/"),
+		)
+	})
 })
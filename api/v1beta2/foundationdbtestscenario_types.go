@@ -0,0 +1,250 @@
+/*
+Copyright 2020-2022 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdbtestscenario
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Step",type="integer",JSONPath=".status.currentStep"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+
+// FoundationDBTestScenario is the Schema for the foundationdbtestscenarios API. A test scenario runs a list of
+// disruptive actions against a cluster and reports if the cluster recovered from every action. This resource is
+// only handled if the operator runs with the test scenarios enabled.
+type FoundationDBTestScenario struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FoundationDBTestScenarioSpec   `json:"spec,omitempty"`
+	Status FoundationDBTestScenarioStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBTestScenarioList contains a list of FoundationDBTestScenario objects
+type FoundationDBTestScenarioList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBTestScenario `json:"items"`
+}
+
+// FoundationDBTestScenarioSpec describes the steps of a test scenario.
+type FoundationDBTestScenarioSpec struct {
+	// ClusterName provides the name of the cluster that the scenario runs
+	// against. The cluster must be in the same namespace as the scenario.
+	ClusterName string `json:"clusterName"`
+
+	// Steps defines the actions of the scenario. The steps are executed in
+	// order and a step is only started once the previous step passed.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=100
+	Steps []TestScenarioStep `json:"steps"`
+}
+
+// TestScenarioAction defines the action of a test scenario step.
+// +kubebuilder:validation:MaxLength=64
+type TestScenarioAction string
+
+const (
+	// TestScenarioActionKillPods deletes random Pods of the cluster.
+	TestScenarioActionKillPods TestScenarioAction = "KillPods"
+	// TestScenarioActionPartitionSidecar blocks the traffic to the sidecar of random Pods of the cluster.
+	TestScenarioActionPartitionSidecar TestScenarioAction = "PartitionSidecar"
+	// TestScenarioActionUpgrade changes the version of the cluster.
+	TestScenarioActionUpgrade TestScenarioAction = "Upgrade"
+	// TestScenarioActionWait only waits for the provided duration.
+	TestScenarioActionWait TestScenarioAction = "Wait"
+)
+
+// TestScenarioStep defines a single action of a test scenario.
+type TestScenarioStep struct {
+	// Name of the step, the name is used in the status to report the result
+	// of the step.
+	// +kubebuilder:validation:MaxLength=64
+	Name string `json:"name"`
+
+	// Action defines what this step does.
+	// +kubebuilder:validation:Enum=KillPods;PartitionSidecar;Upgrade;Wait
+	Action TestScenarioAction `json:"action"`
+
+	// Count defines how many Pods are targeted by the KillPods and
+	// PartitionSidecar actions.
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	Count *int `json:"count,omitempty"`
+
+	// ProcessClass limits the Pods targeted by the KillPods and
+	// PartitionSidecar actions to the provided process class. If not set,
+	// Pods of all process classes can be targeted.
+	ProcessClass ProcessClass `json:"processClass,omitempty"`
+
+	// Version defines the version of FoundationDB for the Upgrade action.
+	// +kubebuilder:validation:MaxLength=64
+	Version string `json:"version,omitempty"`
+
+	// DurationSeconds defines how long the sidecar is partitioned for the
+	// PartitionSidecar action and how long the Wait action waits. For all
+	// other actions this setting is ignored.
+	// Default: 60
+	// +kubebuilder:validation:Minimum=0
+	DurationSeconds *int `json:"durationSeconds,omitempty"`
+
+	// TimeoutSeconds defines how long the cluster has to become reconciled
+	// and available after the action. If the cluster doesn't recover in time
+	// the step and the scenario will be marked as failed.
+	// Default: 600
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int `json:"timeoutSeconds,omitempty"`
+}
+
+// TestScenarioPhase defines the phase of a test scenario or of a single step.
+type TestScenarioPhase string
+
+const (
+	// TestScenarioPhasePending defines that the scenario or step was not started yet.
+	TestScenarioPhasePending TestScenarioPhase = "Pending"
+	// TestScenarioPhaseRunning defines that the scenario or step is currently running.
+	TestScenarioPhaseRunning TestScenarioPhase = "Running"
+	// TestScenarioPhasePassed defines that the scenario or step finished successfully.
+	TestScenarioPhasePassed TestScenarioPhase = "Passed"
+	// TestScenarioPhaseFailed defines that the scenario or step failed.
+	TestScenarioPhaseFailed TestScenarioPhase = "Failed"
+)
+
+// FoundationDBTestScenarioStatus describes the current status of a test scenario.
+type FoundationDBTestScenarioStatus struct {
+	// Phase defines the current phase of the scenario.
+	Phase TestScenarioPhase `json:"phase,omitempty"`
+
+	// CurrentStep defines the index of the step that is currently running.
+	CurrentStep int `json:"currentStep,omitempty"`
+
+	// Steps contains the status of every step of the scenario.
+	Steps []TestScenarioStepStatus `json:"steps,omitempty"`
+
+	// StartTime defines when the scenario was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime defines when the scenario passed or failed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message provides the reason why the scenario failed.
+	Message string `json:"message,omitempty"`
+}
+
+// TestScenarioStepStatus describes the status of a single step of a test scenario.
+type TestScenarioStepStatus struct {
+	// Name of the step.
+	Name string `json:"name"`
+
+	// Phase defines the current phase of the step.
+	Phase TestScenarioPhase `json:"phase,omitempty"`
+
+	// StartTime defines when the action of the step was executed.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime defines when the step passed or failed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// TargetedPods contains the names of the Pods that were targeted by the
+	// action of the step.
+	TargetedPods []string `json:"targetedPods,omitempty"`
+
+	// Message provides additional information about the result of the step.
+	Message string `json:"message,omitempty"`
+}
+
+// GetCount returns the number of Pods that should be targeted by the step. Defaults to 1.
+func (step TestScenarioStep) GetCount() int {
+	if step.Count == nil {
+		return 1
+	}
+
+	return *step.Count
+}
+
+// GetDuration returns the duration of the PartitionSidecar and Wait action. Defaults to 60 seconds.
+func (step TestScenarioStep) GetDuration() time.Duration {
+	if step.DurationSeconds == nil {
+		return 60 * time.Second
+	}
+
+	return time.Duration(*step.DurationSeconds) * time.Second
+}
+
+// GetTimeout returns the duration the cluster has to recover after the action. Defaults to 600 seconds.
+func (step TestScenarioStep) GetTimeout() time.Duration {
+	if step.TimeoutSeconds == nil {
+		return 600 * time.Second
+	}
+
+	return time.Duration(*step.TimeoutSeconds) * time.Second
+}
+
+// IsFinished returns true if the scenario either passed or failed.
+func (scenario *FoundationDBTestScenario) IsFinished() bool {
+	return scenario.Status.Phase == TestScenarioPhasePassed || scenario.Status.Phase == TestScenarioPhaseFailed
+}
+
+// Validate checks if the steps of the scenario are valid.
+func (scenario *FoundationDBTestScenario) Validate() error {
+	if scenario.Spec.ClusterName == "" {
+		return fmt.Errorf("clusterName must be set")
+	}
+
+	if len(scenario.Spec.Steps) == 0 {
+		return fmt.Errorf("at least one step must be defined")
+	}
+
+	names := make(map[string]None, len(scenario.Spec.Steps))
+	for idx, step := range scenario.Spec.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("step %d has no name", idx)
+		}
+
+		if _, ok := names[step.Name]; ok {
+			return fmt.Errorf("step name %s is used multiple times", step.Name)
+		}
+		names[step.Name] = None{}
+
+		switch step.Action {
+		case TestScenarioActionKillPods, TestScenarioActionPartitionSidecar, TestScenarioActionWait:
+		case TestScenarioActionUpgrade:
+			if _, err := ParseFdbVersion(step.Version); err != nil {
+				return fmt.Errorf("step %s has an invalid version: %w", step.Name, err)
+			}
+		default:
+			return fmt.Errorf("step %s has the unknown action %s", step.Name, step.Action)
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBTestScenario{}, &FoundationDBTestScenarioList{})
+}
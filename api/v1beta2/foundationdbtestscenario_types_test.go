@@ -0,0 +1,112 @@
+/*
+ * foundationdbtestscenario_types_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("[api] FoundationDBTestScenario", func() {
+	When("getting the step defaults", func() {
+		It("should return the defaults if nothing is set", func() {
+			step := TestScenarioStep{}
+			Expect(step.GetCount()).To(Equal(1))
+			Expect(step.GetDuration()).To(Equal(60 * time.Second))
+			Expect(step.GetTimeout()).To(Equal(600 * time.Second))
+		})
+
+		It("should return the configured values", func() {
+			step := TestScenarioStep{
+				Count:           pointer.Int(3),
+				DurationSeconds: pointer.Int(0),
+				TimeoutSeconds:  pointer.Int(30),
+			}
+			Expect(step.GetCount()).To(Equal(3))
+			Expect(step.GetDuration()).To(BeZero())
+			Expect(step.GetTimeout()).To(Equal(30 * time.Second))
+		})
+	})
+
+	When("validating a scenario", func() {
+		DescribeTable("should validate the steps",
+			func(spec FoundationDBTestScenarioSpec, expected string) {
+				scenario := &FoundationDBTestScenario{Spec: spec}
+				err := scenario.Validate()
+				if expected == "" {
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+
+				Expect(err).To(MatchError(ContainSubstring(expected)))
+			},
+			Entry("a valid scenario",
+				FoundationDBTestScenarioSpec{
+					ClusterName: "test",
+					Steps: []TestScenarioStep{
+						{Name: "kill", Action: TestScenarioActionKillPods},
+						{Name: "partition", Action: TestScenarioActionPartitionSidecar},
+						{Name: "upgrade", Action: TestScenarioActionUpgrade, Version: "7.1.25"},
+						{Name: "wait", Action: TestScenarioActionWait},
+					},
+				},
+				""),
+			Entry("a scenario without a cluster",
+				FoundationDBTestScenarioSpec{
+					Steps: []TestScenarioStep{{Name: "kill", Action: TestScenarioActionKillPods}},
+				},
+				"clusterName must be set"),
+			Entry("a scenario without steps",
+				FoundationDBTestScenarioSpec{ClusterName: "test"},
+				"at least one step must be defined"),
+			Entry("a step without a name",
+				FoundationDBTestScenarioSpec{
+					ClusterName: "test",
+					Steps:       []TestScenarioStep{{Action: TestScenarioActionKillPods}},
+				},
+				"step 0 has no name"),
+			Entry("a duplicate step name",
+				FoundationDBTestScenarioSpec{
+					ClusterName: "test",
+					Steps: []TestScenarioStep{
+						{Name: "kill", Action: TestScenarioActionKillPods},
+						{Name: "kill", Action: TestScenarioActionWait},
+					},
+				},
+				"step name kill is used multiple times"),
+			Entry("an upgrade without a valid version",
+				FoundationDBTestScenarioSpec{
+					ClusterName: "test",
+					Steps:       []TestScenarioStep{{Name: "upgrade", Action: TestScenarioActionUpgrade}},
+				},
+				"step upgrade has an invalid version"),
+			Entry("an unknown action",
+				FoundationDBTestScenarioSpec{
+					ClusterName: "test",
+					Steps:       []TestScenarioStep{{Name: "reboot", Action: "RebootNodes"}},
+				},
+				"step reboot has the unknown action RebootNodes"),
+		)
+	})
+})
@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1beta2
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // ImageConfig provides a policy for customizing an image.
 //
@@ -25,7 +28,9 @@ import "fmt"
 // the value from the first entry in the config list that defines a value for
 // that field, and matches the version of FoundationDB the image is for. Any
 // config that specifies a different version than the one under consideration
-// will be ignored for the purposes of defining that image.
+// will be ignored for the purposes of defining that image. Configs that specify
+// an architecture are only used for Pods that are scheduled on nodes of that
+// architecture.
 type ImageConfig struct {
 	// Version is the version of FoundationDB this policy applies to. If this is
 	// blank, the policy applies to all FDB versions.
@@ -44,16 +49,32 @@ type ImageConfig struct {
 	// the full tag.
 	// +kubebuilder:validation:MaxLength=50
 	TagSuffix string `json:"tagSuffix,omitempty"`
+
+	// Architecture is the CPU architecture this policy applies to. If this is
+	// blank, the policy applies to all architectures, e.g. for multi-arch images.
+	// +kubebuilder:validation:Enum=amd64;arm64
+	Architecture string `json:"architecture,omitempty"`
 }
 
 // SelectImageConfig selects image configs that apply to a version of FDB and
-// merges them into a single config.
+// merges them into a single config. Configs that specify an architecture are
+// ignored.
 func SelectImageConfig(allConfigs []ImageConfig, versionString string) ImageConfig {
-	config := ImageConfig{Version: versionString}
+	return SelectImageConfigForArchitecture(allConfigs, versionString, "")
+}
+
+// SelectImageConfigForArchitecture selects image configs that apply to a
+// version of FDB and a CPU architecture and merges them into a single config.
+// Configs that specify a different architecture are ignored.
+func SelectImageConfigForArchitecture(allConfigs []ImageConfig, versionString string, architecture string) ImageConfig {
+	config := ImageConfig{Version: versionString, Architecture: architecture}
 	for _, nextConfig := range allConfigs {
 		if nextConfig.Version != "" && nextConfig.Version != versionString {
 			continue
 		}
+		if nextConfig.Architecture != "" && nextConfig.Architecture != architecture {
+			continue
+		}
 		if config.BaseImage == "" {
 			config.BaseImage = nextConfig.BaseImage
 		}
@@ -67,6 +88,30 @@ func SelectImageConfig(allConfigs []ImageConfig, versionString string) ImageConf
 	return config
 }
 
+// GetImageConfigArchitectures returns the sorted list of CPU architectures that
+// are defined in the provided image configs. Image configs without an
+// architecture are not included.
+func GetImageConfigArchitectures(configLists ...[]ImageConfig) []string {
+	architectures := map[string]None{}
+	for _, configs := range configLists {
+		for _, config := range configs {
+			if config.Architecture == "" {
+				continue
+			}
+
+			architectures[config.Architecture] = None{}
+		}
+	}
+
+	result := make([]string, 0, len(architectures))
+	for architecture := range architectures {
+		result = append(result, architecture)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
 // Image generates an image using a config.
 func (config ImageConfig) Image() string {
 	if config.Tag == "" {
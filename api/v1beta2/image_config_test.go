@@ -54,6 +54,74 @@ var _ = Describe("[api] ImageConfig", func() {
 				TagSuffix: "-1",
 			}))
 		})
+
+		It("ignores configs that are for a specific architecture", func() {
+			configs := []ImageConfig{
+				{
+					Architecture: "arm64",
+					TagSuffix:    "-arm64",
+				},
+				{
+					BaseImage: "foundationdb/foundationdb",
+				},
+			}
+
+			finalConfig := SelectImageConfig(configs, Versions.Default.String())
+			Expect(finalConfig).To(Equal(ImageConfig{
+				BaseImage: "foundationdb/foundationdb",
+				Version:   Versions.Default.String(),
+			}))
+		})
+	})
+
+	When("selecting image configs for an architecture", func() {
+		var configs []ImageConfig
+
+		BeforeEach(func() {
+			configs = []ImageConfig{
+				{
+					Architecture: "arm64",
+					TagSuffix:    "-arm64",
+				},
+				{
+					Architecture: "amd64",
+					TagSuffix:    "-amd64",
+				},
+				{
+					BaseImage: "foundationdb/foundationdb",
+					TagSuffix: "-1",
+				},
+			}
+		})
+
+		It("applies the configs for the architecture and the configs without an architecture", func() {
+			finalConfig := SelectImageConfigForArchitecture(configs, Versions.Default.String(), "arm64")
+			Expect(finalConfig).To(Equal(ImageConfig{
+				BaseImage:    "foundationdb/foundationdb",
+				Version:      Versions.Default.String(),
+				TagSuffix:    "-arm64",
+				Architecture: "arm64",
+			}))
+			Expect(finalConfig.Image()).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s-arm64", Versions.Default)))
+		})
+
+		It("ignores the configs for other architectures", func() {
+			finalConfig := SelectImageConfigForArchitecture(configs, Versions.Default.String(), "amd64")
+			Expect(finalConfig.TagSuffix).To(Equal("-amd64"))
+		})
+	})
+
+	When("getting the architectures of image configs", func() {
+		It("returns the sorted architectures of all image configs", func() {
+			Expect(GetImageConfigArchitectures(
+				[]ImageConfig{{Architecture: "arm64"}, {BaseImage: "foundationdb/foundationdb"}},
+				[]ImageConfig{{Architecture: "amd64"}, {Architecture: "arm64"}},
+			)).To(Equal([]string{"amd64", "arm64"}))
+		})
+
+		It("returns no architectures for image configs without an architecture", func() {
+			Expect(GetImageConfigArchitectures([]ImageConfig{{BaseImage: "foundationdb/foundationdb"}})).To(BeEmpty())
+		})
 	})
 
 	When("building image names", func() {
@@ -24,10 +24,76 @@ package v1beta2
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 	netx "net"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionBudgetOptions) DeepCopyInto(out *ActionBudgetOptions) {
+	*out = *in
+	if in.MaxActionsPerReconcile != nil {
+		in, out := &in.MaxActionsPerReconcile, &out.MaxActionsPerReconcile
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxActionsPerHour != nil {
+		in, out := &in.MaxActionsPerHour, &out.MaxActionsPerHour
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionBudgetOptions.
+func (in *ActionBudgetOptions) DeepCopy() *ActionBudgetOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionBudgetOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionBudgetStatus) DeepCopyInto(out *ActionBudgetStatus) {
+	*out = *in
+	if in.WindowStart != nil {
+		in, out := &in.WindowStart, &out.WindowStart
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionBudgetStatus.
+func (in *ActionBudgetStatus) DeepCopy() *ActionBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthorizationSpec) DeepCopyInto(out *AuthorizationSpec) {
+	*out = *in
+	if in.PublicKeySecrets != nil {
+		in, out := &in.PublicKeySecrets, &out.PublicKeySecrets
+		*out = make([]corev1.SecretKeySelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationSpec.
+func (in *AuthorizationSpec) DeepCopy() *AuthorizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthorizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutomaticReplacementOptions) DeepCopyInto(out *AutomaticReplacementOptions) {
 	*out = *in
@@ -93,6 +159,36 @@ func (in *BlobStoreConfiguration) DeepCopyInto(out *BlobStoreConfiguration) {
 		*out = make([]URLParameter, len(*in))
 		copy(*out, *in)
 	}
+	if in.SecureConnection != nil {
+		in, out := &in.SecureConnection, &out.SecureConnection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RequestsPerSecond != nil {
+		in, out := &in.RequestsPerSecond, &out.RequestsPerSecond
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConcurrentRequests != nil {
+		in, out := &in.ConcurrentRequests, &out.ConcurrentRequests
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxSendBytesPerSecond != nil {
+		in, out := &in.MaxSendBytesPerSecond, &out.MaxSendBytesPerSecond
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxRecvBytesPerSecond != nil {
+		in, out := &in.MaxRecvBytesPerSecond, &out.MaxRecvBytesPerSecond
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentityConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlobStoreConfiguration.
@@ -105,6 +201,50 @@ func (in *BlobStoreConfiguration) DeepCopy() *BlobStoreConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BounceScheduleOptions) DeepCopyInto(out *BounceScheduleOptions) {
+	*out = *in
+	if in.MaxOperationsPerSecond != nil {
+		in, out := &in.MaxOperationsPerSecond, &out.MaxOperationsPerSecond
+		*out = new(int)
+		**out = **in
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]BounceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.UrgentUntil != nil {
+		in, out := &in.UrgentUntil, &out.UrgentUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BounceScheduleOptions.
+func (in *BounceScheduleOptions) DeepCopy() *BounceScheduleOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(BounceScheduleOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BounceWindow) DeepCopyInto(out *BounceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BounceWindow.
+func (in *BounceWindow) DeepCopy() *BounceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BounceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuggifyConfig) DeepCopyInto(out *BuggifyConfig) {
 	*out = *in
@@ -147,6 +287,125 @@ func (in *BuggifyConfig) DeepCopy() *BuggifyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(CertificateScope)
+		**out = **in
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneFromSpec) DeepCopyInto(out *CloneFromSpec) {
+	*out = *in
+	if in.VolumeSnapshots != nil {
+		in, out := &in.VolumeSnapshots, &out.VolumeSnapshots
+		*out = make(map[ProcessGroupID]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneFromSpec.
+func (in *CloneFromSpec) DeepCopy() *CloneFromSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneFromSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAction) DeepCopyInto(out *ClusterAction) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAction.
+func (in *ClusterAction) DeepCopy() *ClusterAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterFileVerificationOptions) DeepCopyInto(out *ClusterFileVerificationOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.FixIncorrectClusterFiles != nil {
+		in, out := &in.FixIncorrectClusterFiles, &out.FixIncorrectClusterFiles
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterFileVerificationOptions.
+func (in *ClusterFileVerificationOptions) DeepCopy() *ClusterFileVerificationOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterFileVerificationOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterGenerationStatus) DeepCopyInto(out *ClusterGenerationStatus) {
 	*out = *in
@@ -227,6 +486,30 @@ func (in *ContainerOverrides) DeepCopy() *ContainerOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatorChangeStatus) DeepCopyInto(out *CoordinatorChangeStatus) {
+	*out = *in
+	if in.Coordinators != nil {
+		in, out := &in.Coordinators, &out.Coordinators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorChangeStatus.
+func (in *CoordinatorChangeStatus) DeepCopy() *CoordinatorChangeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatorChangeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoordinatorSelectionSetting) DeepCopyInto(out *CoordinatorSelectionSetting) {
 	*out = *in
@@ -242,6 +525,31 @@ func (in *CoordinatorSelectionSetting) DeepCopy() *CoordinatorSelectionSetting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashCollectionSpec) DeepCopyInto(out *CrashCollectionSpec) {
+	*out = *in
+	if in.MaxCrashReports != nil {
+		in, out := &in.MaxCrashReports, &out.MaxCrashReports
+		*out = new(int)
+		**out = **in
+	}
+	if in.RetentionSeconds != nil {
+		in, out := &in.RetentionSeconds, &out.RetentionSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashCollectionSpec.
+func (in *CrashCollectionSpec) DeepCopy() *CrashCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CrashLoopContainerObject) DeepCopyInto(out *CrashLoopContainerObject) {
 	*out = *in
@@ -262,6 +570,22 @@ func (in *CrashLoopContainerObject) DeepCopy() *CrashLoopContainerObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashReport) DeepCopyInto(out *CrashReport) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashReport.
+func (in *CrashReport) DeepCopy() *CrashReport {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataCenter) DeepCopyInto(out *DataCenter) {
 	*out = *in
@@ -277,6 +601,26 @@ func (in *DataCenter) DeepCopy() *DataCenter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataDistributionSpec) DeepCopyInto(out *DataDistributionSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDistributionSpec.
+func (in *DataDistributionSpec) DeepCopy() *DataDistributionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataDistributionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseConfiguration) DeepCopyInto(out *DatabaseConfiguration) {
 	*out = *in
@@ -292,6 +636,11 @@ func (in *DatabaseConfiguration) DeepCopyInto(out *DatabaseConfiguration) {
 		*out = make([]ExcludedServers, len(*in))
 		copy(*out, *in)
 	}
+	if in.PerpetualStorageWiggle != nil {
+		in, out := &in.PerpetualStorageWiggle, &out.PerpetualStorageWiggle
+		*out = new(int)
+		**out = **in
+	}
 	out.RoleCounts = in.RoleCounts
 	out.VersionFlags = in.VersionFlags
 }
@@ -307,47 +656,167 @@ func (in *DatabaseConfiguration) DeepCopy() *DatabaseConfiguration {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExcludedServers) DeepCopyInto(out *ExcludedServers) {
+func (in *DatabaseConfigurationDriftStatus) DeepCopyInto(out *DatabaseConfigurationDriftStatus) {
 	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExcludedServers.
-func (in *ExcludedServers) DeepCopy() *ExcludedServers {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseConfigurationDriftStatus.
+func (in *DatabaseConfigurationDriftStatus) DeepCopy() *DatabaseConfigurationDriftStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExcludedServers)
+	out := new(DatabaseConfigurationDriftStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FaultTolerance) DeepCopyInto(out *FaultTolerance) {
+func (in *DestructiveActionStatus) DeepCopyInto(out *DestructiveActionStatus) {
 	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultTolerance.
-func (in *FaultTolerance) DeepCopy() *FaultTolerance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestructiveActionStatus.
+func (in *DestructiveActionStatus) DeepCopy() *DestructiveActionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FaultTolerance)
+	out := new(DestructiveActionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBBackup) DeepCopyInto(out *FoundationDBBackup) {
+func (in *ExcludedServers) DeepCopyInto(out *ExcludedServers) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackup.
-func (in *FoundationDBBackup) DeepCopy() *FoundationDBBackup {
-	if in == nil {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExcludedServers.
+func (in *ExcludedServers) DeepCopy() *ExcludedServers {
+	if in == nil {
+		return nil
+	}
+	out := new(ExcludedServers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAccessConfig) DeepCopyInto(out *ExternalAccessConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceType != nil {
+		in, out := &in.ServiceType, &out.ServiceType
+		*out = new(corev1.ServiceType)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAccessConfig.
+func (in *ExternalAccessConfig) DeepCopy() *ExternalAccessConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAccessConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalMigrationSpec) DeepCopyInto(out *ExternalMigrationSpec) {
+	*out = *in
+	if in.ExternalProcessAddresses != nil {
+		in, out := &in.ExternalProcessAddresses, &out.ExternalProcessAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalMigrationSpec.
+func (in *ExternalMigrationSpec) DeepCopy() *ExternalMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultDomainToDecommission) DeepCopyInto(out *FaultDomainToDecommission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultDomainToDecommission.
+func (in *FaultDomainToDecommission) DeepCopy() *FaultDomainToDecommission {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultDomainToDecommission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultTolerance) DeepCopyInto(out *FaultTolerance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultTolerance.
+func (in *FaultTolerance) DeepCopy() *FaultTolerance {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultTolerance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FaultToleranceStatus) DeepCopyInto(out *FaultToleranceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FaultToleranceStatus.
+func (in *FaultToleranceStatus) DeepCopy() *FaultToleranceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultToleranceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBBackup) DeepCopyInto(out *FoundationDBBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackup.
+func (in *FoundationDBBackup) DeepCopy() *FoundationDBBackup {
+	if in == nil {
 		return nil
 	}
 	out := new(FoundationDBBackup)
@@ -435,6 +904,11 @@ func (in *FoundationDBBackupSpec) DeepCopyInto(out *FoundationDBBackupSpec) {
 	}
 	in.MainContainer.DeepCopyInto(&out.MainContainer)
 	in.SidecarContainer.DeepCopyInto(&out.SidecarContainer)
+	if in.VolumeSnapshotConfiguration != nil {
+		in, out := &in.VolumeSnapshotConfiguration, &out.VolumeSnapshotConfiguration
+		*out = new(VolumeSnapshotConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupSpec.
@@ -453,9 +927,21 @@ func (in *FoundationDBBackupStatus) DeepCopyInto(out *FoundationDBBackupStatus)
 	if in.BackupDetails != nil {
 		in, out := &in.BackupDetails, &out.BackupDetails
 		*out = new(FoundationDBBackupStatusBackupDetails)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	out.Generations = in.Generations
+	if in.VolumeSnapshotBackup != nil {
+		in, out := &in.VolumeSnapshotBackup, &out.VolumeSnapshotBackup
+		*out = new(VolumeSnapshotBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupStatus.
@@ -471,6 +957,16 @@ func (in *FoundationDBBackupStatus) DeepCopy() *FoundationDBBackupStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBBackupStatusBackupDetails) DeepCopyInto(out *FoundationDBBackupStatusBackupDetails) {
 	*out = *in
+	if in.LastRestorableVersion != nil {
+		in, out := &in.LastRestorableVersion, &out.LastRestorableVersion
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SecondsBehind != nil {
+		in, out := &in.SecondsBehind, &out.SecondsBehind
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupStatusBackupDetails.
@@ -483,6 +979,125 @@ func (in *FoundationDBBackupStatusBackupDetails) DeepCopy() *FoundationDBBackupS
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClientLibraryCache) DeepCopyInto(out *FoundationDBClientLibraryCache) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClientLibraryCache.
+func (in *FoundationDBClientLibraryCache) DeepCopy() *FoundationDBClientLibraryCache {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClientLibraryCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBClientLibraryCache) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClientLibraryCacheList) DeepCopyInto(out *FoundationDBClientLibraryCacheList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBClientLibraryCache, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClientLibraryCacheList.
+func (in *FoundationDBClientLibraryCacheList) DeepCopy() *FoundationDBClientLibraryCacheList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClientLibraryCacheList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBClientLibraryCacheList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClientLibraryCacheSpec) DeepCopyInto(out *FoundationDBClientLibraryCacheSpec) {
+	*out = *in
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalVersions != nil {
+		in, out := &in.AdditionalVersions, &out.AdditionalVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageConfigs != nil {
+		in, out := &in.ImageConfigs, &out.ImageConfigs
+		*out = make([]ImageConfig, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClientLibraryCacheSpec.
+func (in *FoundationDBClientLibraryCacheSpec) DeepCopy() *FoundationDBClientLibraryCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClientLibraryCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClientLibraryCacheStatus) DeepCopyInto(out *FoundationDBClientLibraryCacheStatus) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClientLibraryCacheStatus.
+func (in *FoundationDBClientLibraryCacheStatus) DeepCopy() *FoundationDBClientLibraryCacheStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClientLibraryCacheStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBCluster) DeepCopyInto(out *FoundationDBCluster) {
 	*out = *in
@@ -534,6 +1149,11 @@ func (in *FoundationDBClusterAutomationOptions) DeepCopyInto(out *FoundationDBCl
 		*out = new(bool)
 		**out = **in
 	}
+	if in.IncludeReusedAddresses != nil {
+		in, out := &in.IncludeReusedAddresses, &out.IncludeReusedAddresses
+		*out = new(bool)
+		**out = **in
+	}
 	if in.IgnoreTerminatingPodsSeconds != nil {
 		in, out := &in.IgnoreTerminatingPodsSeconds, &out.IgnoreTerminatingPodsSeconds
 		*out = new(int)
@@ -554,11 +1174,71 @@ func (in *FoundationDBClusterAutomationOptions) DeepCopyInto(out *FoundationDBCl
 		*out = new(int)
 		**out = **in
 	}
+	if in.DecommissionBatchSize != nil {
+		in, out := &in.DecommissionBatchSize, &out.DecommissionBatchSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.SettleTimeSeconds != nil {
+		in, out := &in.SettleTimeSeconds, &out.SettleTimeSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.RecoveryFreezeSeconds != nil {
+		in, out := &in.RecoveryFreezeSeconds, &out.RecoveryFreezeSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxRecoveryFreezeSeconds != nil {
+		in, out := &in.MaxRecoveryFreezeSeconds, &out.MaxRecoveryFreezeSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.CommandTimeoutSeconds != nil {
+		in, out := &in.CommandTimeoutSeconds, &out.CommandTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.DetectionOnly != nil {
+		in, out := &in.DetectionOnly, &out.DetectionOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExclusionTimeoutSeconds != nil {
+		in, out := &in.ExclusionTimeoutSeconds, &out.ExclusionTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.ForceRemovalOnExclusionTimeout != nil {
+		in, out := &in.ForceRemovalOnExclusionTimeout, &out.ForceRemovalOnExclusionTimeout
+		*out = new(bool)
+		**out = **in
+	}
 	if in.WaitBetweenRemovalsSeconds != nil {
 		in, out := &in.WaitBetweenRemovalsSeconds, &out.WaitBetweenRemovalsSeconds
 		*out = new(int)
 		**out = **in
 	}
+	if in.StorageWiggleWaitSeconds != nil {
+		in, out := &in.StorageWiggleWaitSeconds, &out.StorageWiggleWaitSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.ProcessGroupTombstoneSeconds != nil {
+		in, out := &in.ProcessGroupTombstoneSeconds, &out.ProcessGroupTombstoneSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.InPlacePodResize != nil {
+		in, out := &in.InPlacePodResize, &out.InPlacePodResize
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RecreatePodsForSchedulingChanges != nil {
+		in, out := &in.RecreatePodsForSchedulingChanges, &out.RecreatePodsForSchedulingChanges
+		*out = new(bool)
+		**out = **in
+	}
 	if in.UseManagementAPI != nil {
 		in, out := &in.UseManagementAPI, &out.UseManagementAPI
 		*out = new(bool)
@@ -570,6 +1250,33 @@ func (in *FoundationDBClusterAutomationOptions) DeepCopyInto(out *FoundationDBCl
 		*out = make([]LogGroup, len(*in))
 		copy(*out, *in)
 	}
+	if in.MaxIncompatibleClientsForUpgrade != nil {
+		in, out := &in.MaxIncompatibleClientsForUpgrade, &out.MaxIncompatibleClientsForUpgrade
+		*out = new(int)
+		**out = **in
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ActionHistoryLimit != nil {
+		in, out := &in.ActionHistoryLimit, &out.ActionHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	in.ClusterFileVerificationOptions.DeepCopyInto(&out.ClusterFileVerificationOptions)
+	in.LatencyProbeOptions.DeepCopyInto(&out.LatencyProbeOptions)
+	in.TLSCertificateRotationOptions.DeepCopyInto(&out.TLSCertificateRotationOptions)
+	in.StatusReportOptions.DeepCopyInto(&out.StatusReportOptions)
+	in.ProxyDrainingOptions.DeepCopyInto(&out.ProxyDrainingOptions)
+	in.BounceScheduleOptions.DeepCopyInto(&out.BounceScheduleOptions)
+	in.ActionBudget.DeepCopyInto(&out.ActionBudget)
+	if in.UseWorkJournal != nil {
+		in, out := &in.UseWorkJournal, &out.UseWorkJournal
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterAutomationOptions.
@@ -641,6 +1348,11 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		}
 	}
 	out.ProcessCounts = in.ProcessCounts
+	if in.DeriveProcessCounts != nil {
+		in, out := &in.DeriveProcessCounts, &out.DeriveProcessCounts
+		*out = new(bool)
+		**out = **in
+	}
 	in.PartialConnectionString.DeepCopyInto(&out.PartialConnectionString)
 	out.FaultDomain = in.FaultDomain
 	if in.ProcessGroupsToRemove != nil {
@@ -653,6 +1365,25 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		*out = make([]ProcessGroupID, len(*in))
 		copy(*out, *in)
 	}
+	if in.FaultDomainsToDecommission != nil {
+		in, out := &in.FaultDomainsToDecommission, &out.FaultDomainsToDecommission
+		*out = make([]FaultDomainToDecommission, len(*in))
+		copy(*out, *in)
+	}
+	if in.PinnedProcessGroups != nil {
+		in, out := &in.PinnedProcessGroups, &out.PinnedProcessGroups
+		*out = make(map[ProcessGroupID]ProcessGroupPin, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProcessGroupsToReassign != nil {
+		in, out := &in.ProcessGroupsToReassign, &out.ProcessGroupsToReassign
+		*out = make(map[ProcessGroupID]ProcessClass, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.ConfigMap != nil {
 		in, out := &in.ConfigMap, &out.ConfigMap
 		*out = new(corev1.ConfigMap)
@@ -695,6 +1426,66 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ManagedConnectionOnly != nil {
+		in, out := &in.ManagedConnectionOnly, &out.ManagedConnectionOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExternalMigration != nil {
+		in, out := &in.ExternalMigration, &out.ExternalMigration
+		*out = new(ExternalMigrationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]TenantSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagQuotas != nil {
+		in, out := &in.TagQuotas, &out.TagQuotas
+		*out = make([]TagQuota, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataDistribution != nil {
+		in, out := &in.DataDistribution, &out.DataDistribution
+		*out = new(DataDistributionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageAutoscaling != nil {
+		in, out := &in.StorageAutoscaling, &out.StorageAutoscaling
+		*out = new(StorageAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloneFrom != nil {
+		in, out := &in.CloneFrom, &out.CloneFrom
+		*out = new(CloneFromSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TraceLogs != nil {
+		in, out := &in.TraceLogs, &out.TraceLogs
+		*out = new(TraceLogSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrashCollection != nil {
+		in, out := &in.CrashCollection, &out.CrashCollection
+		*out = new(CrashCollectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(AuthorizationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterSpec.
@@ -714,6 +1505,16 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 	out.Generations = in.Generations
 	out.Health = in.Health
 	out.RequiredAddresses = in.RequiredAddresses
+	if in.UnsupportedFeatures != nil {
+		in, out := &in.UnsupportedFeatures, &out.UnsupportedFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalCoordinators != nil {
+		in, out := &in.ExternalCoordinators, &out.ExternalCoordinators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.StorageServersPerDisk != nil {
 		in, out := &in.StorageServersPerDisk, &out.StorageServersPerDisk
 		*out = make([]int, len(*in))
@@ -735,8 +1536,151 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 			}
 		}
 	}
+	if in.ProcessGroupTombstones != nil {
+		in, out := &in.ProcessGroupTombstones, &out.ProcessGroupTombstones
+		*out = make([]ProcessGroupTombstone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OperatorExclusions != nil {
+		in, out := &in.OperatorExclusions, &out.OperatorExclusions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Locks.DeepCopyInto(&out.Locks)
 	in.MaintenanceModeInfo.DeepCopyInto(&out.MaintenanceModeInfo)
+	if in.ReconciliationBlocked != nil {
+		in, out := &in.ReconciliationBlocked, &out.ReconciliationBlocked
+		*out = new(ReconciliationBlockedStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRunActions != nil {
+		in, out := &in.DryRunActions, &out.DryRunActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActionHistory != nil {
+		in, out := &in.ActionHistory, &out.ActionHistory
+		*out = make([]ClusterAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]TenantStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagQuotas != nil {
+		in, out := &in.TagQuotas, &out.TagQuotas
+		*out = make([]TagQuota, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuthorizationPublicKeyIDs != nil {
+		in, out := &in.AuthorizationPublicKeyIDs, &out.AuthorizationPublicKeyIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProcessEnvironmentHashes != nil {
+		in, out := &in.ProcessEnvironmentHashes, &out.ProcessEnvironmentHashes
+		*out = make(map[ProcessClass]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSCertificateHashes != nil {
+		in, out := &in.TLSCertificateHashes, &out.TLSCertificateHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSCertificates != nil {
+		in, out := &in.TLSCertificates, &out.TLSCertificates
+		*out = make([]TLSCertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StorageAutoscaling != nil {
+		in, out := &in.StorageAutoscaling, &out.StorageAutoscaling
+		*out = new(StorageAutoscalingStatus)
+		**out = **in
+	}
+	if in.DatabaseConfigurationDrift != nil {
+		in, out := &in.DatabaseConfigurationDrift, &out.DatabaseConfigurationDrift
+		*out = new(DatabaseConfigurationDriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrashReports != nil {
+		in, out := &in.CrashReports, &out.CrashReports
+		*out = make([]CrashReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDestructiveAction != nil {
+		in, out := &in.LastDestructiveAction, &out.LastDestructiveAction
+		*out = new(DestructiveActionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastClusterFileVerification != nil {
+		in, out := &in.LastClusterFileVerification, &out.LastClusterFileVerification
+		*out = (*in).DeepCopy()
+	}
+	if in.LastObservedRecovery != nil {
+		in, out := &in.LastObservedRecovery, &out.LastObservedRecovery
+		*out = (*in).DeepCopy()
+	}
+	if in.RecoveringSince != nil {
+		in, out := &in.RecoveringSince, &out.RecoveringSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationStatus)
+		**out = **in
+	}
+	if in.ActionBudget != nil {
+		in, out := &in.ActionBudget, &out.ActionBudget
+		*out = new(ActionBudgetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CoordinatorChange != nil {
+		in, out := &in.CoordinatorChange, &out.CoordinatorChange
+		*out = new(CoordinatorChangeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FaultTolerance != nil {
+		in, out := &in.FaultTolerance, &out.FaultTolerance
+		*out = new(FaultToleranceStatus)
+		**out = **in
+	}
+	if in.IncompatibleClients != nil {
+		in, out := &in.IncompatibleClients, &out.IncompatibleClients
+		*out = new(IncompatibleClientsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProcessClassReassignments != nil {
+		in, out := &in.ProcessClassReassignments, &out.ProcessClassReassignments
+		*out = make([]ProcessClassReassignmentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]ProcessClassRevisionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProxyDrain != nil {
+		in, out := &in.ProxyDrain, &out.ProxyDrain
+		*out = new(ProxyDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatus.
@@ -749,6 +1693,106 @@ func (in *FoundationDBClusterStatus) DeepCopy() *FoundationDBClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterStatusReport) DeepCopyInto(out *FoundationDBClusterStatusReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatusReport.
+func (in *FoundationDBClusterStatusReport) DeepCopy() *FoundationDBClusterStatusReport {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterStatusReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBClusterStatusReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterStatusReportList) DeepCopyInto(out *FoundationDBClusterStatusReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBClusterStatusReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatusReportList.
+func (in *FoundationDBClusterStatusReportList) DeepCopy() *FoundationDBClusterStatusReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterStatusReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBClusterStatusReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterStatusReportStatus) DeepCopyInto(out *FoundationDBClusterStatusReportStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	out.FaultTolerance = in.FaultTolerance
+	in.DatabaseStatus.DeepCopyInto(&out.DatabaseStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatusReportStatus.
+func (in *FoundationDBClusterStatusReportStatus) DeepCopy() *FoundationDBClusterStatusReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterStatusReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterTemplate) DeepCopyInto(out *FoundationDBClusterTemplate) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(v1.ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterTemplate.
+func (in *FoundationDBClusterTemplate) DeepCopy() *FoundationDBClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in FoundationDBCustomParameters) DeepCopyInto(out *FoundationDBCustomParameters) {
 	{
@@ -787,6 +1831,11 @@ func (in *FoundationDBKeyRange) DeepCopy() *FoundationDBKeyRange {
 func (in *FoundationDBLiveBackupStatus) DeepCopyInto(out *FoundationDBLiveBackupStatus) {
 	*out = *in
 	out.Status = in.Status
+	if in.LatestRestorablePoint != nil {
+		in, out := &in.LatestRestorablePoint, &out.LatestRestorablePoint
+		*out = new(FoundationDBLiveBackupStatusRestorablePoint)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatus.
@@ -799,6 +1848,21 @@ func (in *FoundationDBLiveBackupStatus) DeepCopy() *FoundationDBLiveBackupStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveBackupStatusRestorablePoint) DeepCopyInto(out *FoundationDBLiveBackupStatusRestorablePoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatusRestorablePoint.
+func (in *FoundationDBLiveBackupStatusRestorablePoint) DeepCopy() *FoundationDBLiveBackupStatusRestorablePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveBackupStatusRestorablePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBLiveBackupStatusState) DeepCopyInto(out *FoundationDBLiveBackupStatusState) {
 	*out = *in
@@ -815,26 +1879,25 @@ func (in *FoundationDBLiveBackupStatusState) DeepCopy() *FoundationDBLiveBackupS
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBRestore) DeepCopyInto(out *FoundationDBRestore) {
+func (in *FoundationDBOperatorConfig) DeepCopyInto(out *FoundationDBOperatorConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestore.
-func (in *FoundationDBRestore) DeepCopy() *FoundationDBRestore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBOperatorConfig.
+func (in *FoundationDBOperatorConfig) DeepCopy() *FoundationDBOperatorConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBRestore)
+	out := new(FoundationDBOperatorConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FoundationDBRestore) DeepCopyObject() runtime.Object {
+func (in *FoundationDBOperatorConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -842,31 +1905,31 @@ func (in *FoundationDBRestore) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBRestoreList) DeepCopyInto(out *FoundationDBRestoreList) {
+func (in *FoundationDBOperatorConfigList) DeepCopyInto(out *FoundationDBOperatorConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]FoundationDBRestore, len(*in))
+		*out = make([]FoundationDBOperatorConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreList.
-func (in *FoundationDBRestoreList) DeepCopy() *FoundationDBRestoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBOperatorConfigList.
+func (in *FoundationDBOperatorConfigList) DeepCopy() *FoundationDBOperatorConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBRestoreList)
+	out := new(FoundationDBOperatorConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FoundationDBRestoreList) DeepCopyObject() runtime.Object {
+func (in *FoundationDBOperatorConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -874,65 +1937,203 @@ func (in *FoundationDBRestoreList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBRestoreSpec) DeepCopyInto(out *FoundationDBRestoreSpec) {
+func (in *FoundationDBOperatorConfigSpec) DeepCopyInto(out *FoundationDBOperatorConfigSpec) {
 	*out = *in
-	if in.KeyRanges != nil {
-		in, out := &in.KeyRanges, &out.KeyRanges
-		*out = make([]FoundationDBKeyRange, len(*in))
+	if in.MainContainerImageConfigs != nil {
+		in, out := &in.MainContainerImageConfigs, &out.MainContainerImageConfigs
+		*out = make([]ImageConfig, len(*in))
 		copy(*out, *in)
 	}
-	if in.BlobStoreConfiguration != nil {
-		in, out := &in.BlobStoreConfiguration, &out.BlobStoreConfiguration
-		*out = new(BlobStoreConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.CustomParameters != nil {
-		in, out := &in.CustomParameters, &out.CustomParameters
-		*out = make(FoundationDBCustomParameters, len(*in))
+	if in.SidecarContainerImageConfigs != nil {
+		in, out := &in.SidecarContainerImageConfigs, &out.SidecarContainerImageConfigs
+		*out = make([]ImageConfig, len(*in))
 		copy(*out, *in)
 	}
+	in.AutomationOptions.DeepCopyInto(&out.AutomationOptions)
+	if in.ResourcePresets != nil {
+		in, out := &in.ResourcePresets, &out.ResourcePresets
+		*out = make(map[ProcessClass]corev1.ResourceRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreSpec.
-func (in *FoundationDBRestoreSpec) DeepCopy() *FoundationDBRestoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBOperatorConfigSpec.
+func (in *FoundationDBOperatorConfigSpec) DeepCopy() *FoundationDBOperatorConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBRestoreSpec)
+	out := new(FoundationDBOperatorConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBRestoreStatus) DeepCopyInto(out *FoundationDBRestoreStatus) {
+func (in *FoundationDBParameter) DeepCopyInto(out *FoundationDBParameter) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreStatus.
-func (in *FoundationDBRestoreStatus) DeepCopy() *FoundationDBRestoreStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBParameter.
+func (in *FoundationDBParameter) DeepCopy() *FoundationDBParameter {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBRestoreStatus)
+	out := new(FoundationDBParameter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBStatus) DeepCopyInto(out *FoundationDBStatus) {
-	*out = *in
-	in.Client.DeepCopyInto(&out.Client)
-	in.Cluster.DeepCopyInto(&out.Cluster)
+func (in FoundationDBParameters) DeepCopyInto(out *FoundationDBParameters) {
+	{
+		in := &in
+		*out = make(FoundationDBParameters, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatus.
-func (in *FoundationDBStatus) DeepCopy() *FoundationDBStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBParameters.
+func (in FoundationDBParameters) DeepCopy() FoundationDBParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBStatus)
+	out := new(FoundationDBParameters)
 	in.DeepCopyInto(out)
-	return out
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestore) DeepCopyInto(out *FoundationDBRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestore.
+func (in *FoundationDBRestore) DeepCopy() *FoundationDBRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreList) DeepCopyInto(out *FoundationDBRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreList.
+func (in *FoundationDBRestoreList) DeepCopy() *FoundationDBRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreSpec) DeepCopyInto(out *FoundationDBRestoreSpec) {
+	*out = *in
+	if in.KeyRanges != nil {
+		in, out := &in.KeyRanges, &out.KeyRanges
+		*out = make([]FoundationDBKeyRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlobStoreConfiguration != nil {
+		in, out := &in.BlobStoreConfiguration, &out.BlobStoreConfiguration
+		*out = new(BlobStoreConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomParameters != nil {
+		in, out := &in.CustomParameters, &out.CustomParameters
+		*out = make(FoundationDBCustomParameters, len(*in))
+		copy(*out, *in)
+	}
+	if in.DestinationClusterTemplate != nil {
+		in, out := &in.DestinationClusterTemplate, &out.DestinationClusterTemplate
+		*out = new(FoundationDBClusterTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreSpec.
+func (in *FoundationDBRestoreSpec) DeepCopy() *FoundationDBRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreStatus) DeepCopyInto(out *FoundationDBRestoreStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreStatus.
+func (in *FoundationDBRestoreStatus) DeepCopy() *FoundationDBRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatus) DeepCopyInto(out *FoundationDBStatus) {
+	*out = *in
+	in.Client.DeepCopyInto(&out.Client)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatus.
+func (in *FoundationDBStatus) DeepCopy() *FoundationDBStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -1030,6 +2231,8 @@ func (in *FoundationDBStatusClusterInfo) DeepCopyInto(out *FoundationDBStatusClu
 		copy(*out, *in)
 	}
 	out.RecoveryState = in.RecoveryState
+	in.StorageWiggler.DeepCopyInto(&out.StorageWiggler)
+	out.Workload = in.Workload
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusClusterInfo.
@@ -1175,6 +2378,21 @@ func (in *FoundationDBStatusMovingData) DeepCopy() *FoundationDBStatusMovingData
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusProcessDiskInfo) DeepCopyInto(out *FoundationDBStatusProcessDiskInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessDiskInfo.
+func (in *FoundationDBStatusProcessDiskInfo) DeepCopy() *FoundationDBStatusProcessDiskInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusProcessDiskInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusProcessInfo) DeepCopyInto(out *FoundationDBStatusProcessInfo) {
 	*out = *in
@@ -1196,6 +2414,8 @@ func (in *FoundationDBStatusProcessInfo) DeepCopyInto(out *FoundationDBStatusPro
 		*out = make([]FoundationDBStatusProcessMessage, len(*in))
 		copy(*out, *in)
 	}
+	out.Disk = in.Disk
+	out.Network = in.Network
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessInfo.
@@ -1224,185 +2444,424 @@ func (in *FoundationDBStatusProcessMessage) DeepCopy() *FoundationDBStatusProces
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBStatusProcessRoleInfo) DeepCopyInto(out *FoundationDBStatusProcessRoleInfo) {
+func (in *FoundationDBStatusProcessNetworkInfo) DeepCopyInto(out *FoundationDBStatusProcessNetworkInfo) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessRoleInfo.
-func (in *FoundationDBStatusProcessRoleInfo) DeepCopy() *FoundationDBStatusProcessRoleInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessNetworkInfo.
+func (in *FoundationDBStatusProcessNetworkInfo) DeepCopy() *FoundationDBStatusProcessNetworkInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBStatusProcessRoleInfo)
+	out := new(FoundationDBStatusProcessNetworkInfo)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBStatusSupportedVersion) DeepCopyInto(out *FoundationDBStatusSupportedVersion) {
+func (in *FoundationDBStatusProcessRoleInfo) DeepCopyInto(out *FoundationDBStatusProcessRoleInfo) {
 	*out = *in
-	if in.ConnectedClients != nil {
-		in, out := &in.ConnectedClients, &out.ConnectedClients
-		*out = make([]FoundationDBStatusConnectedClient, len(*in))
-		copy(*out, *in)
-	}
-	if in.MaxProtocolClients != nil {
-		in, out := &in.MaxProtocolClients, &out.MaxProtocolClients
-		*out = make([]FoundationDBStatusConnectedClient, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusSupportedVersion.
-func (in *FoundationDBStatusSupportedVersion) DeepCopy() *FoundationDBStatusSupportedVersion {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessRoleInfo.
+func (in *FoundationDBStatusProcessRoleInfo) DeepCopy() *FoundationDBStatusProcessRoleInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBStatusSupportedVersion)
+	out := new(FoundationDBStatusProcessRoleInfo)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageConfig) DeepCopyInto(out *ImageConfig) {
+func (in *FoundationDBStatusRate) DeepCopyInto(out *FoundationDBStatusRate) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfig.
-func (in *ImageConfig) DeepCopy() *ImageConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusRate.
+func (in *FoundationDBStatusRate) DeepCopy() *FoundationDBStatusRate {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageConfig)
+	out := new(FoundationDBStatusRate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LabelConfig) DeepCopyInto(out *LabelConfig) {
+func (in *FoundationDBStatusStorageWiggler) DeepCopyInto(out *FoundationDBStatusStorageWiggler) {
 	*out = *in
-	if in.MatchLabels != nil {
-		in, out := &in.MatchLabels, &out.MatchLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ResourceLabels != nil {
-		in, out := &in.ResourceLabels, &out.ResourceLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ProcessGroupIDLabels != nil {
-		in, out := &in.ProcessGroupIDLabels, &out.ProcessGroupIDLabels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.ProcessClassLabels != nil {
-		in, out := &in.ProcessClassLabels, &out.ProcessClassLabels
+	if in.WiggleServerAddresses != nil {
+		in, out := &in.WiggleServerAddresses, &out.WiggleServerAddresses
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.FilterOnOwnerReferences != nil {
-		in, out := &in.FilterOnOwnerReferences, &out.FilterOnOwnerReferences
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelConfig.
-func (in *LabelConfig) DeepCopy() *LabelConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusStorageWiggler.
+func (in *FoundationDBStatusStorageWiggler) DeepCopy() *FoundationDBStatusStorageWiggler {
 	if in == nil {
 		return nil
 	}
-	out := new(LabelConfig)
+	out := new(FoundationDBStatusStorageWiggler)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LockDenyListEntry) DeepCopyInto(out *LockDenyListEntry) {
+func (in *FoundationDBStatusSupportedVersion) DeepCopyInto(out *FoundationDBStatusSupportedVersion) {
 	*out = *in
+	if in.ConnectedClients != nil {
+		in, out := &in.ConnectedClients, &out.ConnectedClients
+		*out = make([]FoundationDBStatusConnectedClient, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxProtocolClients != nil {
+		in, out := &in.MaxProtocolClients, &out.MaxProtocolClients
+		*out = make([]FoundationDBStatusConnectedClient, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockDenyListEntry.
-func (in *LockDenyListEntry) DeepCopy() *LockDenyListEntry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusSupportedVersion.
+func (in *FoundationDBStatusSupportedVersion) DeepCopy() *FoundationDBStatusSupportedVersion {
 	if in == nil {
 		return nil
 	}
-	out := new(LockDenyListEntry)
+	out := new(FoundationDBStatusSupportedVersion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LockOptions) DeepCopyInto(out *LockOptions) {
+func (in *FoundationDBStatusWorkload) DeepCopyInto(out *FoundationDBStatusWorkload) {
 	*out = *in
-	if in.DisableLocks != nil {
-		in, out := &in.DisableLocks, &out.DisableLocks
-		*out = new(bool)
-		**out = **in
-	}
-	if in.LockDurationMinutes != nil {
-		in, out := &in.LockDurationMinutes, &out.LockDurationMinutes
-		*out = new(int)
-		**out = **in
-	}
-	if in.DenyList != nil {
-		in, out := &in.DenyList, &out.DenyList
-		*out = make([]LockDenyListEntry, len(*in))
-		copy(*out, *in)
-	}
+	out.Operations = in.Operations
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockOptions.
-func (in *LockOptions) DeepCopy() *LockOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusWorkload.
+func (in *FoundationDBStatusWorkload) DeepCopy() *FoundationDBStatusWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(LockOptions)
+	out := new(FoundationDBStatusWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LockSystemStatus) DeepCopyInto(out *LockSystemStatus) {
+func (in *FoundationDBStatusWorkloadOperations) DeepCopyInto(out *FoundationDBStatusWorkloadOperations) {
 	*out = *in
-	if in.DenyList != nil {
-		in, out := &in.DenyList, &out.DenyList
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.Reads = in.Reads
+	out.Writes = in.Writes
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockSystemStatus.
-func (in *LockSystemStatus) DeepCopy() *LockSystemStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusWorkloadOperations.
+func (in *FoundationDBStatusWorkloadOperations) DeepCopy() *FoundationDBStatusWorkloadOperations {
 	if in == nil {
 		return nil
 	}
-	out := new(LockSystemStatus)
+	out := new(FoundationDBStatusWorkloadOperations)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceModeInfo) DeepCopyInto(out *MaintenanceModeInfo) {
+func (in *FoundationDBTestScenario) DeepCopyInto(out *FoundationDBTestScenario) {
 	*out = *in
-	if in.StartTimestamp != nil {
-		in, out := &in.StartTimestamp, &out.StartTimestamp
-		*out = (*in).DeepCopy()
-	}
-	if in.ProcessGroups != nil {
-		in, out := &in.ProcessGroups, &out.ProcessGroups
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeInfo.
-func (in *MaintenanceModeInfo) DeepCopy() *MaintenanceModeInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTestScenario.
+func (in *FoundationDBTestScenario) DeepCopy() *FoundationDBTestScenario {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTestScenario)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBTestScenario) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBTestScenarioList) DeepCopyInto(out *FoundationDBTestScenarioList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBTestScenario, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTestScenarioList.
+func (in *FoundationDBTestScenarioList) DeepCopy() *FoundationDBTestScenarioList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTestScenarioList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBTestScenarioList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBTestScenarioSpec) DeepCopyInto(out *FoundationDBTestScenarioSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TestScenarioStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTestScenarioSpec.
+func (in *FoundationDBTestScenarioSpec) DeepCopy() *FoundationDBTestScenarioSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTestScenarioSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBTestScenarioStatus) DeepCopyInto(out *FoundationDBTestScenarioStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TestScenarioStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTestScenarioStatus.
+func (in *FoundationDBTestScenarioStatus) DeepCopy() *FoundationDBTestScenarioStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTestScenarioStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageConfig) DeepCopyInto(out *ImageConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfig.
+func (in *ImageConfig) DeepCopy() *ImageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncompatibleClientsStatus) DeepCopyInto(out *IncompatibleClientsStatus) {
+	*out = *in
+	if in.Clients != nil {
+		in, out := &in.Clients, &out.Clients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncompatibleClientsStatus.
+func (in *IncompatibleClientsStatus) DeepCopy() *IncompatibleClientsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IncompatibleClientsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelConfig) DeepCopyInto(out *LabelConfig) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceLabels != nil {
+		in, out := &in.ResourceLabels, &out.ResourceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProcessGroupIDLabels != nil {
+		in, out := &in.ProcessGroupIDLabels, &out.ProcessGroupIDLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProcessClassLabels != nil {
+		in, out := &in.ProcessClassLabels, &out.ProcessClassLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FilterOnOwnerReferences != nil {
+		in, out := &in.FilterOnOwnerReferences, &out.FilterOnOwnerReferences
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelConfig.
+func (in *LabelConfig) DeepCopy() *LabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencyProbeOptions) DeepCopyInto(out *LatencyProbeOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencyProbeOptions.
+func (in *LatencyProbeOptions) DeepCopy() *LatencyProbeOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencyProbeOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockDenyListEntry) DeepCopyInto(out *LockDenyListEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockDenyListEntry.
+func (in *LockDenyListEntry) DeepCopy() *LockDenyListEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(LockDenyListEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockOptions) DeepCopyInto(out *LockOptions) {
+	*out = *in
+	if in.DisableLocks != nil {
+		in, out := &in.DisableLocks, &out.DisableLocks
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LockDurationMinutes != nil {
+		in, out := &in.LockDurationMinutes, &out.LockDurationMinutes
+		*out = new(int)
+		**out = **in
+	}
+	if in.DenyList != nil {
+		in, out := &in.DenyList, &out.DenyList
+		*out = make([]LockDenyListEntry, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockOptions.
+func (in *LockOptions) DeepCopy() *LockOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(LockOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockSystemStatus) DeepCopyInto(out *LockSystemStatus) {
+	*out = *in
+	if in.DenyList != nil {
+		in, out := &in.DenyList, &out.DenyList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockSystemStatus.
+func (in *LockSystemStatus) DeepCopy() *LockSystemStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LockSystemStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceModeInfo) DeepCopyInto(out *MaintenanceModeInfo) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.ProcessGroups != nil {
+		in, out := &in.ProcessGroups, &out.ProcessGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeInfo.
+func (in *MaintenanceModeInfo) DeepCopy() *MaintenanceModeInfo {
 	if in == nil {
 		return nil
 	}
@@ -1412,332 +2871,1042 @@ func (in *MaintenanceModeInfo) DeepCopy() *MaintenanceModeInfo {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceModeOptions) DeepCopyInto(out *MaintenanceModeOptions) {
+func (in *MaintenanceModeOptions) DeepCopyInto(out *MaintenanceModeOptions) {
+	*out = *in
+	if in.UseMaintenanceModeChecker != nil {
+		in, out := &in.UseMaintenanceModeChecker, &out.UseMaintenanceModeChecker
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaintenanceModeTimeSeconds != nil {
+		in, out := &in.MaintenanceModeTimeSeconds, &out.MaintenanceModeTimeSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeOptions.
+func (in *MaintenanceModeOptions) DeepCopy() *MaintenanceModeOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceModeOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *None) DeepCopyInto(out *None) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new None.
+func (in *None) DeepCopy() *None {
+	if in == nil {
+		return nil
+	}
+	out := new(None)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSpec) DeepCopyInto(out *NotificationSpec) {
+	*out = *in
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]NotificationWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReconciliationBlockedThresholdSeconds != nil {
+		in, out := &in.ReconciliationBlockedThresholdSeconds, &out.ReconciliationBlockedThresholdSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSpec.
+func (in *NotificationSpec) DeepCopy() *NotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationStatus) DeepCopyInto(out *NotificationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationStatus.
+func (in *NotificationStatus) DeepCopy() *NotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationWebhook) DeepCopyInto(out *NotificationWebhook) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationWebhook.
+func (in *NotificationWebhook) DeepCopy() *NotificationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDNSSettings) DeepCopyInto(out *PodDNSSettings) {
+	*out = *in
+	if in.Ndots != nil {
+		in, out := &in.Ndots, &out.Ndots
+		*out = new(int)
+		**out = **in
+	}
+	if in.Searches != nil {
+		in, out := &in.Searches, &out.Searches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subdomain != nil {
+		in, out := &in.Subdomain, &out.Subdomain
+		*out = new(string)
+		**out = **in
+	}
+	if in.SetHostname != nil {
+		in, out := &in.SetHostname, &out.SetHostname
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDNSSettings.
+func (in *PodDNSSettings) DeepCopy() *PodDNSSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDNSSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessAddress) DeepCopyInto(out *ProcessAddress) {
+	*out = *in
+	if in.IPAddress != nil {
+		in, out := &in.IPAddress, &out.IPAddress
+		*out = make(netx.IP, len(*in))
+		copy(*out, *in)
+	}
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessAddress.
+func (in *ProcessAddress) DeepCopy() *ProcessAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessClassReassignmentStatus) DeepCopyInto(out *ProcessClassReassignmentStatus) {
+	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessClassReassignmentStatus.
+func (in *ProcessClassReassignmentStatus) DeepCopy() *ProcessClassReassignmentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessClassReassignmentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessClassRevisionStatus) DeepCopyInto(out *ProcessClassRevisionStatus) {
+	*out = *in
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]RevisionCount, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessClassRevisionStatus.
+func (in *ProcessClassRevisionStatus) DeepCopy() *ProcessClassRevisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessClassRevisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessCounts) DeepCopyInto(out *ProcessCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessCounts.
+func (in *ProcessCounts) DeepCopy() *ProcessCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupCondition) DeepCopyInto(out *ProcessGroupCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupCondition.
+func (in *ProcessGroupCondition) DeepCopy() *ProcessGroupCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupPin) DeepCopyInto(out *ProcessGroupPin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupPin.
+func (in *ProcessGroupPin) DeepCopy() *ProcessGroupPin {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupPin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupStatus) DeepCopyInto(out *ProcessGroupStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovalTimestamp != nil {
+		in, out := &in.RemovalTimestamp, &out.RemovalTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.ExclusionTimestamp != nil {
+		in, out := &in.ExclusionTimestamp, &out.ExclusionTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.ProcessGroupConditions != nil {
+		in, out := &in.ProcessGroupConditions, &out.ProcessGroupConditions
+		*out = make([]*ProcessGroupCondition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ProcessGroupCondition)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupStatus.
+func (in *ProcessGroupStatus) DeepCopy() *ProcessGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupTombstone) DeepCopyInto(out *ProcessGroupTombstone) {
+	*out = *in
+	if in.RemovalTimestamp != nil {
+		in, out := &in.RemovalTimestamp, &out.RemovalTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupTombstone.
+func (in *ProcessGroupTombstone) DeepCopy() *ProcessGroupTombstone {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupTombstone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessHealthProbes) DeepCopyInto(out *ProcessHealthProbes) {
+	*out = *in
+	if in.EnableLivenessProbe != nil {
+		in, out := &in.EnableLivenessProbe, &out.EnableLivenessProbe
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableReadinessProbe != nil {
+		in, out := &in.EnableReadinessProbe, &out.EnableReadinessProbe
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessHealthProbes.
+func (in *ProcessHealthProbes) DeepCopy() *ProcessHealthProbes {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessHealthProbes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessSettings) DeepCopyInto(out *ProcessSettings) {
+	*out = *in
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomParameters != nil {
+		in, out := &in.CustomParameters, &out.CustomParameters
+		*out = make(FoundationDBCustomParameters, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(FoundationDBParameters, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalContainers != nil {
+		in, out := &in.AdditionalContainers, &out.AdditionalContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalInitContainers != nil {
+		in, out := &in.AdditionalInitContainers, &out.AdditionalInitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(PodDNSSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthProbes != nil {
+		in, out := &in.HealthProbes, &out.HealthProbes
+		*out = new(ProcessHealthProbes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UseHostNetwork != nil {
+		in, out := &in.UseHostNetwork, &out.UseHostNetwork
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeClaimTemplateSelectors != nil {
+		in, out := &in.VolumeClaimTemplateSelectors, &out.VolumeClaimTemplateSelectors
+		*out = make([]VolumeClaimTemplateSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessSettings.
+func (in *ProcessSettings) DeepCopy() *ProcessSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyDrainStatus) DeepCopyInto(out *ProxyDrainStatus) {
+	*out = *in
+	if in.ProcessGroupIDs != nil {
+		in, out := &in.ProcessGroupIDs, &out.ProcessGroupIDs
+		*out = make([]ProcessGroupID, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyDrainStatus.
+func (in *ProxyDrainStatus) DeepCopy() *ProxyDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyDrainingOptions) DeepCopyInto(out *ProxyDrainingOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DelaySeconds != nil {
+		in, out := &in.DelaySeconds, &out.DelaySeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyDrainingOptions.
+func (in *ProxyDrainingOptions) DeepCopy() *ProxyDrainingOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyDrainingOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconciliationBlockedStatus) DeepCopyInto(out *ReconciliationBlockedStatus) {
+	*out = *in
+	out.Delay = in.Delay
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconciliationBlockedStatus.
+func (in *ReconciliationBlockedStatus) DeepCopy() *ReconciliationBlockedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconciliationBlockedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryState) DeepCopyInto(out *RecoveryState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryState.
+func (in *RecoveryState) DeepCopy() *RecoveryState {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Region) DeepCopyInto(out *Region) {
+	*out = *in
+	if in.DataCenters != nil {
+		in, out := &in.DataCenters, &out.DataCenters
+		*out = make([]DataCenter, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Region.
+func (in *Region) DeepCopy() *Region {
+	if in == nil {
+		return nil
+	}
+	out := new(Region)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredAddressSet) DeepCopyInto(out *RequiredAddressSet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredAddressSet.
+func (in *RequiredAddressSet) DeepCopy() *RequiredAddressSet {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredAddressSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionCount) DeepCopyInto(out *RevisionCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionCount.
+func (in *RevisionCount) DeepCopy() *RevisionCount {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleCounts) DeepCopyInto(out *RoleCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleCounts.
+func (in *RoleCounts) DeepCopy() *RoleCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutingConfig) DeepCopyInto(out *RoutingConfig) {
+	*out = *in
+	if in.HeadlessService != nil {
+		in, out := &in.HeadlessService, &out.HeadlessService
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PublicIPSource != nil {
+		in, out := &in.PublicIPSource, &out.PublicIPSource
+		*out = new(PublicIPSource)
+		**out = **in
+	}
+	if in.PodIPFamily != nil {
+		in, out := &in.PodIPFamily, &out.PodIPFamily
+		*out = new(int)
+		**out = **in
+	}
+	if in.UseDNSInClusterFile != nil {
+		in, out := &in.UseDNSInClusterFile, &out.UseDNSInClusterFile
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefineDNSLocalityFields != nil {
+		in, out := &in.DefineDNSLocalityFields, &out.DefineDNSLocalityFields
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DNSDomain != nil {
+		in, out := &in.DNSDomain, &out.DNSDomain
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExternalAccess != nil {
+		in, out := &in.ExternalAccess, &out.ExternalAccess
+		*out = new(ExternalAccessConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingConfig.
+func (in *RoutingConfig) DeepCopy() *RoutingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RoutingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusReportOptions) DeepCopyInto(out *StatusReportOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusReportOptions.
+func (in *StatusReportOptions) DeepCopy() *StatusReportOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusReportOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutoscalingSpec) DeepCopyInto(out *StorageAutoscalingSpec) {
 	*out = *in
-	if in.UseMaintenanceModeChecker != nil {
-		in, out := &in.UseMaintenanceModeChecker, &out.UseMaintenanceModeChecker
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MaintenanceModeTimeSeconds != nil {
-		in, out := &in.MaintenanceModeTimeSeconds, &out.MaintenanceModeTimeSeconds
+	if in.DiskUtilizationThreshold != nil {
+		in, out := &in.DiskUtilizationThreshold, &out.DiskUtilizationThreshold
 		*out = new(int)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeOptions.
-func (in *MaintenanceModeOptions) DeepCopy() *MaintenanceModeOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscalingSpec.
+func (in *StorageAutoscalingSpec) DeepCopy() *StorageAutoscalingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MaintenanceModeOptions)
+	out := new(StorageAutoscalingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *None) DeepCopyInto(out *None) {
+func (in *StorageAutoscalingStatus) DeepCopyInto(out *StorageAutoscalingStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new None.
-func (in *None) DeepCopy() *None {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutoscalingStatus.
+func (in *StorageAutoscalingStatus) DeepCopy() *StorageAutoscalingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(None)
+	out := new(StorageAutoscalingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessAddress) DeepCopyInto(out *ProcessAddress) {
+func (in *TLSCertificateRotationOptions) DeepCopyInto(out *TLSCertificateRotationOptions) {
 	*out = *in
-	if in.IPAddress != nil {
-		in, out := &in.IPAddress, &out.IPAddress
-		*out = make(netx.IP, len(*in))
-		copy(*out, *in)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Flags != nil {
-		in, out := &in.Flags, &out.Flags
-		*out = make(map[string]bool, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.RotationWindowSeconds != nil {
+		in, out := &in.RotationWindowSeconds, &out.RotationWindowSeconds
+		*out = new(int)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessAddress.
-func (in *ProcessAddress) DeepCopy() *ProcessAddress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateRotationOptions.
+func (in *TLSCertificateRotationOptions) DeepCopy() *TLSCertificateRotationOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessAddress)
+	out := new(TLSCertificateRotationOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessCounts) DeepCopyInto(out *ProcessCounts) {
+func (in *TLSCertificateStatus) DeepCopyInto(out *TLSCertificateStatus) {
 	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessCounts.
-func (in *ProcessCounts) DeepCopy() *ProcessCounts {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateStatus.
+func (in *TLSCertificateStatus) DeepCopy() *TLSCertificateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessCounts)
+	out := new(TLSCertificateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessGroupCondition) DeepCopyInto(out *ProcessGroupCondition) {
+func (in *TagQuota) DeepCopyInto(out *TagQuota) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupCondition.
-func (in *ProcessGroupCondition) DeepCopy() *ProcessGroupCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TagQuota.
+func (in *TagQuota) DeepCopy() *TagQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessGroupCondition)
+	out := new(TagQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessGroupStatus) DeepCopyInto(out *ProcessGroupStatus) {
+func (in *TaintReplacementOption) DeepCopyInto(out *TaintReplacementOption) {
 	*out = *in
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(string)
+		**out = **in
 	}
-	if in.RemovalTimestamp != nil {
-		in, out := &in.RemovalTimestamp, &out.RemovalTimestamp
-		*out = (*in).DeepCopy()
+	if in.DurationInSeconds != nil {
+		in, out := &in.DurationInSeconds, &out.DurationInSeconds
+		*out = new(int64)
+		**out = **in
 	}
-	if in.ExclusionTimestamp != nil {
-		in, out := &in.ExclusionTimestamp, &out.ExclusionTimestamp
-		*out = (*in).DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaintReplacementOption.
+func (in *TaintReplacementOption) DeepCopy() *TaintReplacementOption {
+	if in == nil {
+		return nil
 	}
-	if in.ProcessGroupConditions != nil {
-		in, out := &in.ProcessGroupConditions, &out.ProcessGroupConditions
-		*out = make([]*ProcessGroupCondition, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ProcessGroupCondition)
-				**out = **in
-			}
-		}
+	out := new(TaintReplacementOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
+func (in *TenantSpec) DeepCopy() *TenantSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(TenantSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupStatus.
-func (in *ProcessGroupStatus) DeepCopy() *ProcessGroupStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
+func (in *TenantStatus) DeepCopy() *TenantStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessGroupStatus)
+	out := new(TenantStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProcessSettings) DeepCopyInto(out *ProcessSettings) {
+func (in *TestScenarioStep) DeepCopyInto(out *TestScenarioStep) {
 	*out = *in
-	if in.PodTemplate != nil {
-		in, out := &in.PodTemplate, &out.PodTemplate
-		*out = new(corev1.PodTemplateSpec)
-		(*in).DeepCopyInto(*out)
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int)
+		**out = **in
 	}
-	if in.VolumeClaimTemplate != nil {
-		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
-		*out = new(corev1.PersistentVolumeClaim)
-		(*in).DeepCopyInto(*out)
+	if in.DurationSeconds != nil {
+		in, out := &in.DurationSeconds, &out.DurationSeconds
+		*out = new(int)
+		**out = **in
 	}
-	if in.CustomParameters != nil {
-		in, out := &in.CustomParameters, &out.CustomParameters
-		*out = make(FoundationDBCustomParameters, len(*in))
-		copy(*out, *in)
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessSettings.
-func (in *ProcessSettings) DeepCopy() *ProcessSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestScenarioStep.
+func (in *TestScenarioStep) DeepCopy() *TestScenarioStep {
 	if in == nil {
 		return nil
 	}
-	out := new(ProcessSettings)
+	out := new(TestScenarioStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RecoveryState) DeepCopyInto(out *RecoveryState) {
+func (in *TestScenarioStepStatus) DeepCopyInto(out *TestScenarioStepStatus) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.TargetedPods != nil {
+		in, out := &in.TargetedPods, &out.TargetedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryState.
-func (in *RecoveryState) DeepCopy() *RecoveryState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestScenarioStepStatus.
+func (in *TestScenarioStepStatus) DeepCopy() *TestScenarioStepStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RecoveryState)
+	out := new(TestScenarioStepStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Region) DeepCopyInto(out *Region) {
+func (in *TraceLogSpec) DeepCopyInto(out *TraceLogSpec) {
 	*out = *in
-	if in.DataCenters != nil {
-		in, out := &in.DataCenters, &out.DataCenters
-		*out = make([]DataCenter, len(*in))
-		copy(*out, *in)
+	if in.MaxLogsSize != nil {
+		in, out := &in.MaxLogsSize, &out.MaxLogsSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Forwarder != nil {
+		in, out := &in.Forwarder, &out.Forwarder
+		*out = new(corev1.Container)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Region.
-func (in *Region) DeepCopy() *Region {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraceLogSpec.
+func (in *TraceLogSpec) DeepCopy() *TraceLogSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Region)
+	out := new(TraceLogSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RequiredAddressSet) DeepCopyInto(out *RequiredAddressSet) {
+func (in *Version) DeepCopyInto(out *Version) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredAddressSet.
-func (in *RequiredAddressSet) DeepCopy() *RequiredAddressSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Version.
+func (in *Version) DeepCopy() *Version {
 	if in == nil {
 		return nil
 	}
-	out := new(RequiredAddressSet)
+	out := new(Version)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoleCounts) DeepCopyInto(out *RoleCounts) {
+func (in *VersionFlags) DeepCopyInto(out *VersionFlags) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleCounts.
-func (in *RoleCounts) DeepCopy() *RoleCounts {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionFlags.
+func (in *VersionFlags) DeepCopy() *VersionFlags {
 	if in == nil {
 		return nil
 	}
-	out := new(RoleCounts)
+	out := new(VersionFlags)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoutingConfig) DeepCopyInto(out *RoutingConfig) {
+func (in *VolumeClaimTemplateSelector) DeepCopyInto(out *VolumeClaimTemplateSelector) {
 	*out = *in
-	if in.HeadlessService != nil {
-		in, out := &in.HeadlessService, &out.HeadlessService
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PublicIPSource != nil {
-		in, out := &in.PublicIPSource, &out.PublicIPSource
-		*out = new(PublicIPSource)
-		**out = **in
-	}
-	if in.PodIPFamily != nil {
-		in, out := &in.PodIPFamily, &out.PodIPFamily
-		*out = new(int)
-		**out = **in
-	}
-	if in.UseDNSInClusterFile != nil {
-		in, out := &in.UseDNSInClusterFile, &out.UseDNSInClusterFile
-		*out = new(bool)
-		**out = **in
-	}
-	if in.DefineDNSLocalityFields != nil {
-		in, out := &in.DefineDNSLocalityFields, &out.DefineDNSLocalityFields
-		*out = new(bool)
-		**out = **in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.DNSDomain != nil {
-		in, out := &in.DNSDomain, &out.DNSDomain
-		*out = new(string)
-		**out = **in
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingConfig.
-func (in *RoutingConfig) DeepCopy() *RoutingConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTemplateSelector.
+func (in *VolumeClaimTemplateSelector) DeepCopy() *VolumeClaimTemplateSelector {
 	if in == nil {
 		return nil
 	}
-	out := new(RoutingConfig)
+	out := new(VolumeClaimTemplateSelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TaintReplacementOption) DeepCopyInto(out *TaintReplacementOption) {
+func (in *VolumeSnapshotBackupStatus) DeepCopyInto(out *VolumeSnapshotBackupStatus) {
 	*out = *in
-	if in.Key != nil {
-		in, out := &in.Key, &out.Key
-		*out = new(string)
-		**out = **in
+	if in.VolumeSnapshots != nil {
+		in, out := &in.VolumeSnapshots, &out.VolumeSnapshots
+		*out = make(map[ProcessGroupID]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.DurationInSeconds != nil {
-		in, out := &in.DurationInSeconds, &out.DurationInSeconds
-		*out = new(int64)
-		**out = **in
+	if in.LockDeadline != nil {
+		in, out := &in.LockDeadline, &out.LockDeadline
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailureTime != nil {
+		in, out := &in.FailureTime, &out.FailureTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaintReplacementOption.
-func (in *TaintReplacementOption) DeepCopy() *TaintReplacementOption {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotBackupStatus.
+func (in *VolumeSnapshotBackupStatus) DeepCopy() *VolumeSnapshotBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TaintReplacementOption)
+	out := new(VolumeSnapshotBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Version) DeepCopyInto(out *Version) {
+func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfiguration) {
 	*out = *in
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.LockDatabase != nil {
+		in, out := &in.LockDatabase, &out.LockDatabase
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LockTimeoutSeconds != nil {
+		in, out := &in.LockTimeoutSeconds, &out.LockTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Version.
-func (in *Version) DeepCopy() *Version {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotConfiguration.
+func (in *VolumeSnapshotConfiguration) DeepCopy() *VolumeSnapshotConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(Version)
+	out := new(VolumeSnapshotConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VersionFlags) DeepCopyInto(out *VersionFlags) {
+func (in *WorkloadIdentityConfiguration) DeepCopyInto(out *WorkloadIdentityConfiguration) {
 	*out = *in
+	if in.TokenExpirationSeconds != nil {
+		in, out := &in.TokenExpirationSeconds, &out.TokenExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionFlags.
-func (in *VersionFlags) DeepCopy() *VersionFlags {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentityConfiguration.
+func (in *WorkloadIdentityConfiguration) DeepCopy() *WorkloadIdentityConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(VersionFlags)
+	out := new(WorkloadIdentityConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,188 @@
+/*
+ * action_budget.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// actionBudgetWindow defines the length of the window for the hourly action budget.
+const actionBudgetWindow = time.Hour
+
+// clusterActionBudget is the action budget shared by all cluster reconcilers.
+var clusterActionBudget = newActionBudget()
+
+// actionBudget tracks the Pod actions that the cluster reconciler performed in the current reconciliation loop.
+// The actions of the hourly window are tracked in the cluster status.
+type actionBudget struct {
+	lock sync.Mutex
+
+	// usedActions contains the number of Pod actions performed in the current reconciliation loop of each cluster.
+	usedActions map[types.NamespacedName]int
+}
+
+// newActionBudget creates a new empty action budget.
+func newActionBudget() *actionBudget {
+	return &actionBudget{
+		usedActions: map[types.NamespacedName]int{},
+	}
+}
+
+// used returns the number of Pod actions performed in the current reconciliation loop of the cluster.
+func (budget *actionBudget) used(cluster *fdbv1beta2.FoundationDBCluster) int {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	return budget.usedActions[types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}]
+}
+
+// add adds the Pod actions to the current reconciliation loop of the cluster.
+func (budget *actionBudget) add(cluster *fdbv1beta2.FoundationDBCluster, count int) {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	budget.usedActions[types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}] += count
+}
+
+// reset removes the Pod actions of the current reconciliation loop of the cluster.
+func (budget *actionBudget) reset(cluster *fdbv1beta2.FoundationDBCluster) {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	delete(budget.usedActions, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name})
+}
+
+// getActionBudget returns the action budget for the Pod actions of the cluster reconciler.
+func (r *FoundationDBClusterReconciler) getActionBudget() *actionBudget {
+	return clusterActionBudget
+}
+
+// getUsedActionsInWindow returns the number of Pod actions performed in the current hourly window and the time when
+// the window ends. If the window has ended, no actions will be returned.
+func getUsedActionsInWindow(cluster *fdbv1beta2.FoundationDBCluster) (int, time.Time) {
+	status := cluster.Status.ActionBudget
+	if status == nil || status.WindowStart == nil {
+		return 0, time.Time{}
+	}
+
+	windowEnd := status.WindowStart.Add(actionBudgetWindow)
+	if time.Now().After(windowEnd) {
+		return 0, time.Time{}
+	}
+
+	return status.UsedActions, windowEnd
+}
+
+// getRemainingActionBudget returns how many Pod actions the operator may perform for the cluster. If no action budget
+// is defined, math.MaxInt will be returned.
+func getRemainingActionBudget(r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) int {
+	remaining := math.MaxInt
+	if limit := cluster.GetMaxActionsPerReconcile(); limit > 0 {
+		remaining = limit - r.getActionBudget().used(cluster)
+	}
+
+	if limit := cluster.GetMaxActionsPerHour(); limit > 0 {
+		usedActions, _ := getUsedActionsInWindow(cluster)
+		if limit-usedActions < remaining {
+			remaining = limit - usedActions
+		}
+	}
+
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// checkActionBudget returns a requeue if the action budget of the cluster is exhausted.
+func checkActionBudget(logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, action string) *requeue {
+	if getRemainingActionBudget(r, cluster) > 0 {
+		return nil
+	}
+
+	var delay time.Duration
+	if limit := cluster.GetMaxActionsPerHour(); limit > 0 {
+		usedActions, windowEnd := getUsedActionsInWindow(cluster)
+		if usedActions >= limit {
+			delay = time.Until(windowEnd)
+		}
+	}
+
+	logger.Info("Action budget is exhausted", "action", action, "waitTime", delay)
+	r.Recorder.Event(cluster, corev1.EventTypeWarning, "ActionBudgetExhausted", fmt.Sprintf("Action budget is exhausted, waiting before %s", action))
+
+	return &requeue{
+		message:        fmt.Sprintf("Action budget is exhausted, waiting before %s", action),
+		delay:          delay,
+		delayedRequeue: true,
+	}
+}
+
+// consumeActionBudget adds the Pod actions to the action budget of the cluster. If an hourly limit is defined, the
+// actions are recorded in the cluster status.
+func consumeActionBudget(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, count int) error {
+	addActionBudgetUsage(r, cluster, count)
+
+	return persistActionBudget(ctx, r, cluster, count)
+}
+
+// addActionBudgetUsage adds the Pod actions to the action budget of the cluster without persisting the cluster status.
+// This allows callers that perform multiple actions in one reconciliation loop to check the budget for every action
+// and to persist the hourly budget once with persistActionBudget.
+func addActionBudgetUsage(r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, count int) {
+	if count <= 0 {
+		return
+	}
+
+	r.getActionBudget().add(cluster, count)
+	if cluster.GetMaxActionsPerHour() <= 0 {
+		return
+	}
+
+	usedActions, windowEnd := getUsedActionsInWindow(cluster)
+	if windowEnd.IsZero() {
+		cluster.Status.ActionBudget = &fdbv1beta2.ActionBudgetStatus{
+			WindowStart: &metav1.Time{Time: time.Now()},
+		}
+	}
+
+	cluster.Status.ActionBudget.UsedActions = usedActions + count
+}
+
+// persistActionBudget persists the hourly action budget in the cluster status if Pod actions were added to it.
+func persistActionBudget(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, count int) error {
+	if count <= 0 || cluster.GetMaxActionsPerHour() <= 0 {
+		return nil
+	}
+
+	return r.updateOrApply(ctx, cluster)
+}
@@ -0,0 +1,255 @@
+/*
+ * action_budget_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("action_budget", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		clusterReconciler.getActionBudget().reset(cluster)
+	})
+
+	AfterEach(func() {
+		clusterReconciler.getActionBudget().reset(cluster)
+	})
+
+	When("getting the remaining action budget", func() {
+		When("no action budget is defined", func() {
+			It("should not limit the actions", func() {
+				Expect(getRemainingActionBudget(clusterReconciler, cluster)).To(Equal(math.MaxInt))
+			})
+		})
+
+		When("a limit per reconciliation loop is defined", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile = pointer.Int(5)
+				clusterReconciler.getActionBudget().add(cluster, 2)
+			})
+
+			It("should return the remaining actions of the reconciliation loop", func() {
+				Expect(getRemainingActionBudget(clusterReconciler, cluster)).To(Equal(3))
+			})
+
+			When("the budget was reset", func() {
+				BeforeEach(func() {
+					clusterReconciler.getActionBudget().reset(cluster)
+				})
+
+				It("should return the full budget", func() {
+					Expect(getRemainingActionBudget(clusterReconciler, cluster)).To(Equal(5))
+				})
+			})
+		})
+
+		When("a limit per hour is defined", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(10)
+				cluster.Status.ActionBudget = &fdbv1beta2.ActionBudgetStatus{
+					WindowStart: &metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
+					UsedActions: 8,
+				}
+			})
+
+			It("should return the remaining actions of the window", func() {
+				Expect(getRemainingActionBudget(clusterReconciler, cluster)).To(Equal(2))
+			})
+
+			When("the window has ended", func() {
+				BeforeEach(func() {
+					cluster.Status.ActionBudget.WindowStart = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+				})
+
+				It("should return the full budget", func() {
+					Expect(getRemainingActionBudget(clusterReconciler, cluster)).To(Equal(10))
+				})
+			})
+
+			When("a lower limit per reconciliation loop is defined", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile = pointer.Int(1)
+				})
+
+				It("should return the lower limit", func() {
+					Expect(getRemainingActionBudget(clusterReconciler, cluster)).To(Equal(1))
+				})
+			})
+		})
+	})
+
+	When("checking the action budget", func() {
+		var result *requeue
+
+		JustBeforeEach(func() {
+			result = checkActionBudget(logr.Discard(), clusterReconciler, cluster, "creating Pods")
+		})
+
+		When("budget is left", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile = pointer.Int(1)
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+
+		When("the budget of the reconciliation loop is exhausted", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile = pointer.Int(1)
+				clusterReconciler.getActionBudget().add(cluster, 1)
+			})
+
+			It("should delay the requeue", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.delay).To(BeZero())
+				Expect(result.message).To(Equal("Action budget is exhausted, waiting before creating Pods"))
+			})
+		})
+
+		When("the budget of the window is exhausted", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(10)
+				cluster.Status.ActionBudget = &fdbv1beta2.ActionBudgetStatus{
+					WindowStart: &metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
+					UsedActions: 10,
+				}
+			})
+
+			It("should requeue once the window ends", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.delay).To(BeNumerically("~", 30*time.Minute, time.Minute))
+			})
+		})
+	})
+
+	When("consuming the action budget", func() {
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+		})
+
+		When("no limit per hour is defined", func() {
+			BeforeEach(func() {
+				Expect(consumeActionBudget(context.TODO(), clusterReconciler, cluster, 2)).NotTo(HaveOccurred())
+			})
+
+			It("should only track the actions of the reconciliation loop", func() {
+				Expect(clusterReconciler.getActionBudget().used(cluster)).To(Equal(2))
+				Expect(cluster.Status.ActionBudget).To(BeNil())
+			})
+		})
+
+		When("a limit per hour is defined", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(10)
+				Expect(consumeActionBudget(context.TODO(), clusterReconciler, cluster, 2)).NotTo(HaveOccurred())
+				Expect(consumeActionBudget(context.TODO(), clusterReconciler, cluster, 3)).NotTo(HaveOccurred())
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should record the actions in the cluster status", func() {
+				Expect(clusterReconciler.getActionBudget().used(cluster)).To(Equal(5))
+				Expect(cluster.Status.ActionBudget).NotTo(BeNil())
+				Expect(cluster.Status.ActionBudget.WindowStart).NotTo(BeNil())
+				Expect(cluster.Status.ActionBudget.UsedActions).To(Equal(5))
+			})
+		})
+
+		When("the actions are only added", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(10)
+				addActionBudgetUsage(clusterReconciler, cluster, 2)
+			})
+
+			It("should track the actions without persisting the cluster status", func() {
+				Expect(clusterReconciler.getActionBudget().used(cluster)).To(Equal(2))
+				Expect(cluster.Status.ActionBudget.UsedActions).To(Equal(2))
+
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.Status.ActionBudget).To(BeNil())
+			})
+
+			When("the action budget is persisted", func() {
+				BeforeEach(func() {
+					Expect(persistActionBudget(context.TODO(), clusterReconciler, cluster, 2)).NotTo(HaveOccurred())
+					_, err := reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should record the actions in the cluster status", func() {
+					Expect(cluster.Status.ActionBudget).NotTo(BeNil())
+					Expect(cluster.Status.ActionBudget.UsedActions).To(Equal(2))
+				})
+			})
+		})
+	})
+
+	When("the budget of the window is exhausted during a reconciliation", func() {
+		var result reconcile.Result
+
+		BeforeEach(func() {
+			Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+			cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(10)
+			cluster.Spec.ProcessCounts.Storage++
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			cluster.Status.ActionBudget = &fdbv1beta2.ActionBudgetStatus{
+				WindowStart: &metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
+				UsedActions: 10,
+			}
+			Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			var err error
+			result, err = clusterReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			k8sClient.Clear()
+		})
+
+		It("should requeue once the window ends", func() {
+			Expect(result.Requeue).To(BeTrue())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+		})
+	})
+})
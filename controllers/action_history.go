@@ -0,0 +1,142 @@
+/*
+ * action_history.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterActionHistory is the action history shared by all cluster reconcilers.
+var clusterActionHistory = newActionHistory()
+
+// actionHistory collects the actions the cluster reconciler performed until they are persisted in the cluster
+// status.
+type actionHistory struct {
+	lock sync.Mutex
+
+	// reconcilers contains the sub-reconciler that is currently running for each cluster.
+	reconcilers map[types.NamespacedName]string
+
+	// pendingActions contains the actions that are not yet persisted in the status of each cluster.
+	pendingActions map[types.NamespacedName][]fdbv1beta2.ClusterAction
+}
+
+// newActionHistory creates a new empty action history.
+func newActionHistory() *actionHistory {
+	return &actionHistory{
+		reconcilers:    map[types.NamespacedName]string{},
+		pendingActions: map[types.NamespacedName][]fdbv1beta2.ClusterAction{},
+	}
+}
+
+// setReconciler sets the sub-reconciler that performs all following actions for the cluster.
+func (history *actionHistory) setReconciler(cluster *fdbv1beta2.FoundationDBCluster, reconciler string) {
+	history.lock.Lock()
+	defer history.lock.Unlock()
+
+	history.reconcilers[types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}] = reconciler
+}
+
+// record keeps the action until it is persisted in the status of the cluster.
+func (history *actionHistory) record(cluster *fdbv1beta2.FoundationDBCluster, action string, reason string) {
+	history.lock.Lock()
+	defer history.lock.Unlock()
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	history.pendingActions[key] = append(history.pendingActions[key], fdbv1beta2.ClusterAction{
+		Timestamp:  metav1.Now(),
+		Reconciler: history.reconcilers[key],
+		Action:     truncateActionMessage(action),
+		Reason:     truncateActionMessage(reason),
+		Generation: cluster.ObjectMeta.Generation,
+	})
+}
+
+// takePendingActions returns and removes the pending actions of the cluster.
+func (history *actionHistory) takePendingActions(cluster *fdbv1beta2.FoundationDBCluster) []fdbv1beta2.ClusterAction {
+	history.lock.Lock()
+	defer history.lock.Unlock()
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	actions := history.pendingActions[key]
+	delete(history.pendingActions, key)
+	delete(history.reconcilers, key)
+
+	return actions
+}
+
+// flush appends the pending actions of the cluster to the action history in the cluster status and only keeps the
+// latest actions up to the limit of the cluster.
+func (history *actionHistory) flush(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) error {
+	actions := history.takePendingActions(cluster)
+	limit := cluster.GetActionHistoryLimit()
+	if len(actions) == 0 && (limit > 0 || len(cluster.Status.ActionHistory) == 0) {
+		return nil
+	}
+
+	currentCluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(cluster), currentCluster)
+	if err != nil {
+		return err
+	}
+
+	actions = append(currentCluster.Status.ActionHistory, actions...)
+	if len(actions) > limit {
+		actions = actions[len(actions)-limit:]
+	}
+
+	if len(actions) == 0 {
+		actions = nil
+	}
+
+	currentCluster.Status.ActionHistory = actions
+	return r.updateOrApply(ctx, currentCluster)
+}
+
+// truncateActionMessage limits the message to the maximum length of the fields of an action.
+func truncateActionMessage(message string) string {
+	if len(message) <= 1024 {
+		return message
+	}
+
+	return message[:1021] + "..."
+}
+
+// recordAction adds the action to the action history of the cluster. Actions are not recorded in dry-run mode, as
+// they are never performed.
+func (r *FoundationDBClusterReconciler) recordAction(cluster *fdbv1beta2.FoundationDBCluster, action string, reason string) {
+	if r.dryRunActions != nil {
+		return
+	}
+
+	r.getActionHistory().record(cluster, action, reason)
+}
+
+// getActionHistory returns the action history for the actions of the cluster reconciler.
+func (r *FoundationDBClusterReconciler) getActionHistory() *actionHistory {
+	return clusterActionHistory
+}
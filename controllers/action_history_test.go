@@ -0,0 +1,174 @@
+/*
+ * action_history_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("action_history", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+	})
+
+	When("reconciling a new cluster", func() {
+		BeforeEach(func() {
+			// Drop actions that were recorded by tests that run sub-reconcilers directly.
+			clusterActionHistory.takePendingActions(cluster)
+			Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should record the actions in the cluster status", func() {
+			actions := make([]string, 0, len(cluster.Status.ActionHistory))
+			for _, action := range cluster.Status.ActionHistory {
+				Expect(action.Timestamp.IsZero()).To(BeFalse())
+				Expect(action.Reconciler).NotTo(BeEmpty())
+				Expect(action.Reason).NotTo(BeEmpty())
+				actions = append(actions, action.Action)
+			}
+
+			Expect(actions).To(ContainElements(
+				"added 4 storage process groups",
+				"added 4 log process groups",
+				HavePrefix("configured new database with"),
+			))
+		})
+
+		It("should record the sub-reconciler of the actions", func() {
+			for _, action := range cluster.Status.ActionHistory {
+				if strings.HasPrefix(action.Action, "configured new database") {
+					Expect(action.Reconciler).To(Equal("controllers.updateDatabaseConfiguration"))
+				}
+			}
+		})
+
+		When("the cluster is scaled down", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 3
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+				_, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should record the removal", func() {
+				actions := make([]string, 0, len(cluster.Status.ActionHistory))
+				for _, action := range cluster.Status.ActionHistory {
+					actions = append(actions, action.Action)
+				}
+
+				Expect(actions).To(ContainElements(
+					"marked process groups [storage-4] for removal",
+					HavePrefix("excluded processes"),
+					"removed process groups [storage-4]",
+				))
+			})
+		})
+	})
+
+	When("flushing the pending actions", func() {
+		var history *actionHistory
+
+		BeforeEach(func() {
+			Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+			history = newActionHistory()
+			cluster.Status.ActionHistory = nil
+			Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+		})
+
+		When("more actions than the limit are recorded", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionHistoryLimit = pointer.Int(3)
+				history.setReconciler(cluster, "test")
+				for i := 0; i < 5; i++ {
+					history.record(cluster, fmt.Sprintf("action %d", i), "testing")
+				}
+
+				Expect(history.flush(context.TODO(), clusterReconciler, cluster)).NotTo(HaveOccurred())
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should only keep the latest actions", func() {
+				Expect(cluster.Status.ActionHistory).To(HaveLen(3))
+				Expect(cluster.Status.ActionHistory[0].Action).To(Equal("action 2"))
+				Expect(cluster.Status.ActionHistory[2].Action).To(Equal("action 4"))
+				Expect(cluster.Status.ActionHistory[2].Reconciler).To(Equal("test"))
+				Expect(cluster.Status.ActionHistory[2].Reason).To(Equal("testing"))
+			})
+
+			It("should remove the pending actions", func() {
+				Expect(history.takePendingActions(cluster)).To(BeEmpty())
+			})
+		})
+
+		When("the action history is disabled", func() {
+			BeforeEach(func() {
+				cluster.Status.ActionHistory = []fdbv1beta2.ClusterAction{{Action: "old action"}}
+				Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+				cluster.Spec.AutomationOptions.ActionHistoryLimit = pointer.Int(0)
+				history.record(cluster, "new action", "testing")
+				Expect(history.flush(context.TODO(), clusterReconciler, cluster)).NotTo(HaveOccurred())
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should clear the action history", func() {
+				Expect(cluster.Status.ActionHistory).To(BeEmpty())
+			})
+		})
+
+		When("the action is too long", func() {
+			BeforeEach(func() {
+				history.record(cluster, strings.Repeat("a", 2000), "testing")
+				Expect(history.flush(context.TODO(), clusterReconciler, cluster)).NotTo(HaveOccurred())
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should truncate the action", func() {
+				Expect(cluster.Status.ActionHistory).To(HaveLen(1))
+				Expect(cluster.Status.ActionHistory[0].Action).To(HaveLen(1024))
+				Expect(cluster.Status.ActionHistory[0].Action).To(HaveSuffix("..."))
+			})
+		})
+	})
+
+	When("the reconciler runs in dry-run mode", func() {
+		It("should not record any actions", func() {
+			dryRunReconciler := clusterReconciler.newDryRunReconciler(cluster)
+			dryRunReconciler.recordAction(cluster, "dry-run action", "testing")
+			Expect(clusterReconciler.getActionHistory().takePendingActions(cluster)).To(BeEmpty())
+		})
+	})
+})
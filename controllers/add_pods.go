@@ -39,7 +39,7 @@ type addPods struct{}
 
 // reconcile runs the reconciler's work.
 func (a addPods) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
-	configMap, err := internal.GetConfigMap(cluster)
+	configMap, err := internal.GetConfigMap(ctx, cluster)
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "addPods")
 	if err != nil {
 		return &requeue{curError: err}
@@ -62,7 +62,25 @@ func (a addPods) reconcile(ctx context.Context, r *FoundationDBClusterReconciler
 	}
 
 	podMap := internal.CreatePodMap(cluster, pods)
+	healedProcessGroups := make(map[fdbv1beta2.ProcessClass]int)
+	defer func() {
+		recordProcessCountHealing(r, cluster, healedProcessGroups)
+	}()
+
+	// The hourly action budget is persisted once for all created Pods instead of once per Pod.
+	createdPods, req := createMissingPods(ctx, logger, r, cluster, configMap, podMap, healedProcessGroups)
+	err = persistActionBudget(ctx, r, cluster, createdPods)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return req
+}
 
+// createMissingPods creates the Pods for the process groups that have no Pod and returns the number of created Pods.
+// The created Pods are added to the action budget, but the cluster status is not persisted.
+func createMissingPods(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, configMap *corev1.ConfigMap, podMap map[fdbv1beta2.ProcessGroupID]*corev1.Pod, healedProcessGroups map[fdbv1beta2.ProcessClass]int) (int, *requeue) {
+	createdPods := 0
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		if _, podExists := podMap[processGroup.ProcessGroupID]; podExists {
 			continue
@@ -81,52 +99,103 @@ func (a addPods) reconcile(ctx context.Context, r *FoundationDBClusterReconciler
 
 		_, idNum, err := podmanager.ParseProcessGroupID(processGroup.ProcessGroupID)
 		if err != nil {
-			return &requeue{curError: err}
+			return createdPods, &requeue{curError: err}
 		}
 
 		pod, err := internal.GetPod(cluster, processGroup.ProcessClass, idNum)
 		if err != nil {
 			r.Recorder.Event(cluster, corev1.EventTypeWarning, "GetPod", fmt.Sprintf("failed to get the PodSpec for %s/%d with error: %s", processGroup.ProcessClass, idNum, err))
-			return &requeue{curError: err}
+			return createdPods, &requeue{curError: err}
 		}
 
 		serverPerPod, err := internal.GetStorageServersPerPodForPod(pod)
 		if err != nil {
-			return &requeue{curError: err}
+			return createdPods, &requeue{curError: err}
 		}
 
 		imageType := internal.GetImageType(pod)
 
 		configMapHash, err := internal.GetDynamicConfHash(configMap, processGroup.ProcessClass, imageType, serverPerPod)
 		if err != nil {
-			return &requeue{curError: err}
+			return createdPods, &requeue{curError: err}
 		}
 
 		pod.ObjectMeta.Annotations[fdbv1beta2.LastConfigMapKey] = configMapHash
 
-		if cluster.GetPublicIPSource() == fdbv1beta2.PublicIPSourceService {
+		if cluster.UsePublicIPFromService() {
 			service := &corev1.Service{}
 			err = r.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, service)
 			if err != nil {
-				return &requeue{curError: err}
+				return createdPods, &requeue{curError: err}
 			}
-			ip := service.Spec.ClusterIP
+			ip := getServicePublicIP(cluster, service)
 			if ip == "" {
 				logger.Info("Service does not have an IP address", "processGroupID", processGroup.ProcessGroupID)
-				return &requeue{message: fmt.Sprintf("Service %s does not have an IP address", service.Name)}
+				return createdPods, &requeue{message: fmt.Sprintf("Service %s does not have an IP address", service.Name)}
 			}
 			pod.Annotations[fdbv1beta2.PublicIPAnnotation] = ip
 		}
 
+		if req := checkActionBudget(logger, r, cluster, "creating Pods"); req != nil {
+			return createdPods, req
+		}
+
 		err = r.PodLifecycleManager.CreatePod(logr.NewContext(ctx, logger), r, pod)
 		if err != nil {
 			if internal.IsQuotaExceeded(err) {
-				return &requeue{curError: err, delayedRequeue: true}
+				return createdPods, &requeue{curError: err, delayedRequeue: true}
 			}
 
-			return &requeue{curError: err}
+			return createdPods, &requeue{curError: err}
+		}
+
+		// If the process group has addresses, the Pod was running before and got deleted, e.g. manually, so the running
+		// process groups diverged from the desired process counts. Pods that the operator deleted itself, to update
+		// them or to remove the process group, are not counted.
+		if len(processGroup.Addresses) > 0 && !processGroup.IsMarkedForRemoval() && processGroup.GetConditionTime(fdbv1beta2.IncorrectPodSpec) == nil {
+			healedProcessGroups[processGroup.ProcessClass]++
+		}
+
+		addActionBudgetUsage(r, cluster, 1)
+		createdPods++
+	}
+
+	return createdPods, nil
+}
+
+// recordProcessCountHealing records the Pods that were recreated for process groups with missing Pods in the metrics,
+// the events and the action history of the cluster.
+func recordProcessCountHealing(r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, healedProcessGroups map[fdbv1beta2.ProcessClass]int) {
+	if len(healedProcessGroups) == 0 {
+		return
+	}
+
+	desiredCounts, err := cluster.GetProcessCountsWithDefaults()
+	if err != nil {
+		desiredCounts = fdbv1beta2.ProcessCounts{}
+	}
+	desiredCountMap := desiredCounts.Map()
+
+	for processClass, count := range healedProcessGroups {
+		processCountHealingCounter.WithLabelValues(cluster.Namespace, cluster.Name, string(processClass)).Add(float64(count))
+		message := fmt.Sprintf("Recreated %d missing %s Pods to converge to %d desired %s process groups", count, processClass, desiredCountMap[processClass], processClass)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ProcessCountHealing", message)
+		r.recordAction(cluster, fmt.Sprintf("recreated %d missing %s Pods", count, processClass), "running process groups diverged from the desired process counts")
+	}
+}
+
+// getServicePublicIP returns the IP of the Service that should be used as public IP for the Pod. For the
+// loadBalancer public IP source the IP of the load balancer will be used, otherwise the cluster IP.
+func getServicePublicIP(cluster *fdbv1beta2.FoundationDBCluster, service *corev1.Service) string {
+	if cluster.GetPublicIPSource() != fdbv1beta2.PublicIPSourceLoadBalancer {
+		return service.Spec.ClusterIP
+	}
+
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
 		}
 	}
 
-	return nil
+	return ""
 }
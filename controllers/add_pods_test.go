@@ -29,7 +29,9 @@ import (
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("add_pods", func() {
@@ -131,7 +133,130 @@ var _ = Describe("add_pods", func() {
 				})
 			})
 		})
+
+		When("the Pod of the process group was deleted", func() {
+			var healedBefore float64
+
+			BeforeEach(func() {
+				healedBefore = testutil.ToFloat64(processCountHealingCounter.WithLabelValues(cluster.Namespace, cluster.Name, string(fdbv1beta2.ProcessClassStorage)))
+			})
+
+			It("should create an extra pod", func() {
+				expectNewPodToHaveBeenCreated(initialPods, newPods, cluster)
+			})
+
+			It("should record the healing", func() {
+				Expect(testutil.ToFloat64(processCountHealingCounter.WithLabelValues(cluster.Namespace, cluster.Name, string(fdbv1beta2.ProcessClassStorage)))).To(Equal(healedBefore + 1))
+			})
+
+			When("the Pod was deleted by the operator to update it", func() {
+				BeforeEach(func() {
+					processGroupWithoutPod.UpdateCondition(fdbv1beta2.IncorrectPodSpec, true, nil, "")
+				})
+
+				It("should create an extra pod", func() {
+					expectNewPodToHaveBeenCreated(initialPods, newPods, cluster)
+				})
+
+				It("should not record a healing", func() {
+					Expect(testutil.ToFloat64(processCountHealingCounter.WithLabelValues(cluster.Namespace, cluster.Name, string(fdbv1beta2.ProcessClassStorage)))).To(Equal(healedBefore))
+				})
+			})
+
+			When("the process group is marked for removal", func() {
+				BeforeEach(func() {
+					processGroupWithoutPod.MarkForRemoval()
+				})
+
+				It("should create an extra pod", func() {
+					expectNewPodToHaveBeenCreated(initialPods, newPods, cluster)
+				})
+
+				It("should not record a healing", func() {
+					Expect(testutil.ToFloat64(processCountHealingCounter.WithLabelValues(cluster.Namespace, cluster.Name, string(fdbv1beta2.ProcessClassStorage)))).To(Equal(healedBefore))
+				})
+			})
+		})
+
+		When("the Pod of the process group was never created", func() {
+			var healedBefore float64
+
+			BeforeEach(func() {
+				processGroupWithoutPod.Addresses = nil
+				healedBefore = testutil.ToFloat64(processCountHealingCounter.WithLabelValues(cluster.Namespace, cluster.Name, string(fdbv1beta2.ProcessClassStorage)))
+			})
+
+			It("should create an extra pod", func() {
+				expectNewPodToHaveBeenCreated(initialPods, newPods, cluster)
+			})
+
+			It("should not record a healing", func() {
+				Expect(testutil.ToFloat64(processCountHealingCounter.WithLabelValues(cluster.Namespace, cluster.Name, string(fdbv1beta2.ProcessClassStorage)))).To(Equal(healedBefore))
+			})
+		})
+
+		When("the action budget is exhausted", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile = pointer.Int(1)
+				clusterReconciler.getActionBudget().add(cluster, 1)
+			})
+
+			AfterEach(func() {
+				clusterReconciler.getActionBudget().reset(cluster)
+			})
+
+			It("should delay the requeue", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+			})
+
+			It("should not create any pods", func() {
+				Expect(newPods.Items).To(HaveLen(len(initialPods.Items)))
+			})
+		})
+	})
+
+	Context("with multiple storage process groups with no pod defined and an hourly action budget", func() {
+		BeforeEach(func() {
+			cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups,
+				fdbv1beta2.NewProcessGroupStatus("storage-9", "storage", nil),
+				fdbv1beta2.NewProcessGroupStatus("storage-10", "storage", nil),
+			)
+			cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(10)
+		})
+
+		AfterEach(func() {
+			clusterReconciler.getActionBudget().reset(cluster)
+		})
+
+		It("should create both pods", func() {
+			Expect(requeue).To(BeNil())
+			Expect(newPods.Items).To(HaveLen(len(initialPods.Items) + 2))
+		})
+
+		It("should record both pods in the hourly action budget", func() {
+			Expect(cluster.Status.ActionBudget).NotTo(BeNil())
+			Expect(cluster.Status.ActionBudget.UsedActions).To(Equal(2))
+		})
+
+		When("the hourly action budget only allows one pod", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(1)
+			})
+
+			It("should only create one pod", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(newPods.Items).To(HaveLen(len(initialPods.Items) + 1))
+			})
+
+			It("should record the created pod in the hourly action budget", func() {
+				Expect(cluster.Status.ActionBudget).NotTo(BeNil())
+				Expect(cluster.Status.ActionBudget.UsedActions).To(Equal(1))
+			})
+		})
 	})
+
 })
 
 func expectNewPodToHaveBeenCreated(initialPods *corev1.PodList, newPods *corev1.PodList, cluster *fdbv1beta2.FoundationDBCluster) {
@@ -23,6 +23,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 
@@ -44,8 +45,39 @@ func (a addProcessGroups) reconcile(ctx context.Context, r *FoundationDBClusterR
 	}
 	desiredCounts := desiredCountStruct.Map()
 
+	hasNewProcessGroups := false
+	// A clone starts with the process groups that are created from the VolumeSnapshots of the source cluster.
+	if cluster.IsBeingCloned() && len(cluster.Status.ProcessGroups) == 0 {
+		_, cloneProcessGroups, err := r.getCloneProcessGroups(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		processGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0, len(cloneProcessGroups))
+		for processGroupID := range cloneProcessGroups {
+			processGroupIDs = append(processGroupIDs, processGroupID)
+		}
+		sort.Slice(processGroupIDs, func(i, j int) bool {
+			return processGroupIDs[i] < processGroupIDs[j]
+		})
+
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "AddingProcesses", fmt.Sprintf("Adding %d process groups cloned from %s", len(processGroupIDs), cluster.Spec.CloneFrom.ClusterName))
+		for _, processGroupID := range processGroupIDs {
+			cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, fdbv1beta2.NewProcessGroupStatus(processGroupID, cloneProcessGroups[processGroupID].ProcessClass, nil))
+		}
+		hasNewProcessGroups = len(processGroupIDs) > 0
+	}
+
 	processCounts := make(map[fdbv1beta2.ProcessClass]int)
 	processGroupIDs := make(map[fdbv1beta2.ProcessClass]map[int]bool)
+	orphanedPins := getOrphanedPins(cluster)
+	inheritedPins := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.PinnedFrom != "" {
+			inheritedPins[processGroup.PinnedFrom] = fdbv1beta2.None{}
+		}
+	}
+
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		processGroupID := processGroup.ProcessGroupID
 		_, num, err := podmanager.ParseProcessGroupID(processGroupID)
@@ -65,7 +97,6 @@ func (a addProcessGroups) reconcile(ctx context.Context, r *FoundationDBClusterR
 		}
 	}
 
-	hasNewProcessGroups := false
 	for _, processClass := range fdbv1beta2.ProcessClasses {
 		desiredCount := desiredCounts[processClass]
 		if desiredCount < 0 {
@@ -76,6 +107,7 @@ func (a addProcessGroups) reconcile(ctx context.Context, r *FoundationDBClusterR
 			continue
 		}
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "AddingProcesses", fmt.Sprintf("Adding %d %s processes", newCount, processClass))
+		r.recordAction(cluster, fmt.Sprintf("added %d %s process groups", newCount, processClass), fmt.Sprintf("cluster has %d of %d desired %s process groups", processCounts[processClass], desiredCount, processClass))
 		idNum := 1
 
 		if processGroupIDs[processClass] == nil {
@@ -86,14 +118,24 @@ func (a addProcessGroups) reconcile(ctx context.Context, r *FoundationDBClusterR
 			for idNum > 0 {
 				_, processGroupID := internal.GetProcessGroupID(cluster, processClass, idNum)
 
-				if !cluster.ProcessGroupIsBeingRemoved(processGroupID) && !processGroupIDs[processClass][idNum] {
+				// The ID of a pinned process group that was replaced is not reused, as the pin belongs to its replacement.
+				_, inherited := inheritedPins[processGroupID]
+				if !cluster.ProcessGroupIsBeingRemoved(processGroupID) && !processGroupIDs[processClass][idNum] && !cluster.HasProcessGroupTombstone(processGroupID) && !inherited {
 					break
 				}
 
 				idNum++
 			}
 			_, processGroupID := internal.GetProcessGroupID(cluster, processClass, idNum)
-			cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, fdbv1beta2.NewProcessGroupStatus(processGroupID, processClass, nil))
+			processGroup := fdbv1beta2.NewProcessGroupStatus(processGroupID, processClass, nil)
+			processGroup.VolumeClaimTemplateSelector = cluster.ChooseVolumeClaimTemplateSelector(processClass)
+			// A new process group replaces a pinned process group that is removed, so it inherits the pin.
+			if len(orphanedPins[processClass]) > 0 {
+				processGroup.PinnedFrom = orphanedPins[processClass][0]
+				orphanedPins[processClass] = orphanedPins[processClass][1:]
+				inheritedPins[processGroup.PinnedFrom] = fdbv1beta2.None{}
+			}
+			cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, processGroup)
 
 			idNum++
 		}
@@ -109,3 +151,37 @@ func (a addProcessGroups) reconcile(ctx context.Context, r *FoundationDBClusterR
 
 	return nil
 }
+
+// getOrphanedPins returns the pins of the process groups that are marked for removal and that are not inherited by
+// another process group by their process class. The pins are identified by the ID of the pinned process group.
+func getOrphanedPins(cluster *fdbv1beta2.FoundationDBCluster) map[fdbv1beta2.ProcessClass][]fdbv1beta2.ProcessGroupID {
+	activePins := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !processGroup.IsMarkedForRemoval() {
+			activePins[cluster.GetPinnedProcessGroupID(processGroup.ProcessGroupID)] = fdbv1beta2.None{}
+		}
+	}
+
+	orphanedPins := make(map[fdbv1beta2.ProcessClass][]fdbv1beta2.ProcessGroupID)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !processGroup.IsMarkedForRemoval() || !cluster.IsProcessGroupPinned(processGroup.ProcessGroupID) {
+			continue
+		}
+
+		pinnedProcessGroupID := cluster.GetPinnedProcessGroupID(processGroup.ProcessGroupID)
+		if _, ok := activePins[pinnedProcessGroupID]; ok {
+			continue
+		}
+
+		activePins[pinnedProcessGroupID] = fdbv1beta2.None{}
+		orphanedPins[processGroup.ProcessClass] = append(orphanedPins[processGroup.ProcessClass], pinnedProcessGroupID)
+	}
+
+	for _, pins := range orphanedPins {
+		sort.Slice(pins, func(i, j int) bool {
+			return pins[i] < pins[j]
+		})
+	}
+
+	return orphanedPins
+}
@@ -75,6 +75,44 @@ var _ = Describe("add_process_groups", func() {
 		})
 	})
 
+	Context("with volume claim template selectors for the storage processes", func() {
+		BeforeEach(func() {
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{
+				VolumeClaimTemplateSelectors: []fdbv1beta2.VolumeClaimTemplateSelector{
+					{Name: "zone-a", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "a"}},
+					{Name: "zone-b", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "b"}},
+				},
+			}
+
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage {
+					continue
+				}
+
+				if processGroup.ProcessGroupID == "storage-1" {
+					processGroup.MarkForRemoval()
+				}
+
+				processGroup.VolumeClaimTemplateSelector = "zone-a"
+			}
+
+			cluster.Spec.ProcessCounts.Storage = initialProcessCounts.Storage + 1
+		})
+
+		It("should choose the entries with the fewest process groups for the new process groups", func() {
+			selectors := map[string]int{}
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage || processGroup.IsMarkedForRemoval() {
+					continue
+				}
+
+				selectors[processGroup.VolumeClaimTemplateSelector]++
+			}
+
+			Expect(selectors).To(Equal(map[string]int{"zone-a": initialProcessCounts.Storage - 1, "zone-b": 2}))
+		})
+	})
+
 	Context("with a storage process group marked for removal", func() {
 		BeforeEach(func() {
 			for _, processGroup := range cluster.Status.ProcessGroups {
@@ -142,6 +180,47 @@ var _ = Describe("add_process_groups", func() {
 		})
 	})
 
+	Context("with a pinned process group that is marked for removal", func() {
+		BeforeEach(func() {
+			cluster.Spec.PinnedProcessGroups = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessGroupPin{
+				"storage-2": {NodeName: "node-1"},
+			}
+			fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-2").MarkForRemoval()
+		})
+
+		It("should add a storage process that inherits the pin", func() {
+			processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-5")
+			Expect(processGroup).NotTo(BeNil())
+			Expect(processGroup.PinnedFrom).To(Equal(fdbv1beta2.ProcessGroupID("storage-2")))
+			Expect(cluster.GetProcessGroupPin("storage-5")).To(Equal(&fdbv1beta2.ProcessGroupPin{NodeName: "node-1"}))
+		})
+
+		When("the pinned process group was removed", func() {
+			BeforeEach(func() {
+				processGroups := make([]*fdbv1beta2.ProcessGroupStatus, 0, len(cluster.Status.ProcessGroups))
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if processGroup.ProcessGroupID == "storage-2" {
+						continue
+					}
+
+					if processGroup.ProcessGroupID == "storage-4" {
+						processGroup.PinnedFrom = "storage-2"
+					}
+
+					processGroups = append(processGroups, processGroup)
+				}
+				cluster.Status.ProcessGroups = processGroups
+			})
+
+			It("should not reuse the process group ID of the pinned process group", func() {
+				Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-2")).To(BeNil())
+				processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-5")
+				Expect(processGroup).NotTo(BeNil())
+				Expect(processGroup.PinnedFrom).To(BeEmpty())
+			})
+		})
+	})
+
 	Context("with an increase to the desired storage count", func() {
 		BeforeEach(func() {
 			cluster.Spec.ProcessCounts.Storage += 2
@@ -203,6 +282,76 @@ var _ = Describe("add_process_groups", func() {
 				Expect(storageProcesses).To(ContainElements(expectedStorageProcesses))
 			})
 		})
+
+		Context("with a tombstone for a removed process group", func() {
+			BeforeEach(func() {
+				cluster.AddProcessGroupTombstone("storage-5")
+			})
+
+			It("should not reuse the process group ID of the tombstone", func() {
+				storageProcesses := make([]fdbv1beta2.ProcessGroupID, 0, newProcessCounts.Storage)
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if processGroup.ProcessClass == "storage" {
+						storageProcesses = append(storageProcesses, processGroup.ProcessGroupID)
+					}
+				}
+				expectedStorageProcesses := []fdbv1beta2.ProcessGroupID{
+					"storage-1",
+					"storage-2",
+					"storage-3",
+					"storage-4",
+					"storage-6",
+					"storage-7",
+				}
+				Expect(storageProcesses).To(ConsistOf(expectedStorageProcesses))
+				Expect(cluster.HasProcessGroupTombstone("storage-5")).To(BeTrue())
+			})
+		})
+	})
+
+	When("the cluster is cloned from another cluster", func() {
+		var clone *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			clone = internal.CreateDefaultCluster()
+			clone.Name = "operator-test-clone"
+			clone.Spec.ProcessGroupIDPrefix = "clone"
+			clone.Spec.CloneFrom = &fdbv1beta2.CloneFromSpec{
+				ClusterName: cluster.Name,
+				VolumeSnapshots: map[fdbv1beta2.ProcessGroupID]string{
+					"storage-1": "snapshot-storage-1",
+					"log-1":     "snapshot-log-1",
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), clone)).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			requeue = addProcessGroups{}.reconcile(context.TODO(), clusterReconciler, clone)
+		})
+
+		It("should add the cloned process groups with the process group ID prefix of the clone", func() {
+			Expect(requeue).To(BeNil())
+			Expect(fdbv1beta2.FindProcessGroupByID(clone.Status.ProcessGroups, "clone-storage-1")).NotTo(BeNil())
+			Expect(fdbv1beta2.FindProcessGroupByID(clone.Status.ProcessGroups, "clone-log-1")).NotTo(BeNil())
+		})
+
+		It("should add the remaining process groups", func() {
+			counts := fdbv1beta2.CreateProcessCountsFromProcessGroupStatus(clone.Status.ProcessGroups, true)
+			Expect(counts).To(Equal(initialProcessCounts))
+		})
+
+		When("a snapshotted process group does not exist in the source cluster", func() {
+			BeforeEach(func() {
+				clone.Spec.CloneFrom.VolumeSnapshots["storage-99"] = "snapshot-storage-99"
+			})
+
+			It("should return an error", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.curError).To(MatchError("could not find process group storage-99 in cluster operator-test-1"))
+				Expect(clone.Status.ProcessGroups).To(BeEmpty())
+			})
+		})
 	})
 
 	When("a new processGroup is created", func() {
@@ -39,6 +39,16 @@ type addPVCs struct{}
 // reconcile runs the reconciler's work.
 func (a addPVCs) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "addPVCs")
+
+	var cloneProcessGroups map[fdbv1beta2.ProcessGroupID]internal.CloneProcessGroup
+	if cluster.IsBeingCloned() {
+		var err error
+		_, cloneProcessGroups, err = r.getCloneProcessGroups(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		if processGroup.IsMarkedForRemoval() {
 			continue
@@ -67,6 +77,14 @@ func (a addPVCs) reconcile(ctx context.Context, r *FoundationDBClusterReconciler
 
 			owner := internal.BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
 			pvc.ObjectMeta.OwnerReferences = owner
+
+			// The data source is not part of the spec hash, so the PVC of a clone will not be replaced after the
+			// clone was created.
+			if cloneProcessGroup, ok := cloneProcessGroups[processGroup.ProcessGroupID]; ok {
+				logger.Info("Creating PVC from VolumeSnapshot", "name", pvc.Name, "volumeSnapshot", cloneProcessGroup.VolumeSnapshot, "sourceProcessGroupID", cloneProcessGroup.SourceProcessGroupID)
+				pvc.Spec.DataSource = internal.GetVolumeSnapshotDataSource(cloneProcessGroup.VolumeSnapshot)
+			}
+
 			logger.V(1).Info("Creating PVC", "name", pvc.Name)
 			err = r.Create(ctx, pvc)
 			if err != nil {
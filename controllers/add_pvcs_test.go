@@ -30,6 +30,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("add_pvcs", func() {
@@ -117,6 +118,44 @@ var _ = Describe("add_pvcs", func() {
 		})
 	})
 
+	When("the cluster is cloned from another cluster", func() {
+		var clone *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			clone = internal.CreateDefaultCluster()
+			clone.Name = "operator-test-clone"
+			clone.Spec.ProcessGroupIDPrefix = "clone"
+			clone.Spec.CloneFrom = &fdbv1beta2.CloneFromSpec{
+				ClusterName: cluster.Name,
+				VolumeSnapshots: map[fdbv1beta2.ProcessGroupID]string{
+					"storage-1": "snapshot-storage-1",
+				},
+			}
+			clone.Status.ProcessGroups = []*fdbv1beta2.ProcessGroupStatus{
+				fdbv1beta2.NewProcessGroupStatus("clone-storage-1", fdbv1beta2.ProcessClassStorage, nil),
+				fdbv1beta2.NewProcessGroupStatus("clone-storage-2", fdbv1beta2.ProcessClassStorage, nil),
+			}
+		})
+
+		JustBeforeEach(func() {
+			requeue = addPVCs{}.reconcile(context.TODO(), clusterReconciler, clone)
+		})
+
+		It("should create the PVC of the cloned process group from the VolumeSnapshot", func() {
+			Expect(requeue).To(BeNil())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: clone.Namespace, Name: "operator-test-clone-storage-1-data"}, pvc)).NotTo(HaveOccurred())
+			Expect(pvc.Spec.DataSource).To(Equal(internal.GetVolumeSnapshotDataSource("snapshot-storage-1")))
+		})
+
+		It("should create the PVC of other process groups without a data source", func() {
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: clone.Namespace, Name: "operator-test-clone-storage-2-data"}, pvc)).NotTo(HaveOccurred())
+			Expect(pvc.Spec.DataSource).To(BeNil())
+		})
+	})
+
 	Context("with a stateless process group with no PVC defined", func() {
 		BeforeEach(func() {
 			cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, fdbv1beta2.NewProcessGroupStatus("stateless-9", "stateless", nil))
@@ -65,7 +65,7 @@ func (a addServices) reconcile(ctx context.Context, r *FoundationDBClusterReconc
 		}
 	}
 
-	if cluster.GetPublicIPSource() == fdbv1beta2.PublicIPSourceService {
+	if cluster.UsePublicIPFromService() {
 		for _, processGroup := range cluster.Status.ProcessGroups {
 			if processGroup.IsMarkedForRemoval() {
 				continue
@@ -0,0 +1,433 @@
+/*
+ * admin_client_audit.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// adminClientAuditResultSuccess defines the result of an operation that succeeded.
+	adminClientAuditResultSuccess = "Success"
+
+	// adminClientAuditResultFailure defines the result of an operation that failed.
+	adminClientAuditResultFailure = "Failure"
+)
+
+// auditLogger is the logger for the admin client audit entries, so that they can be filtered from the other logs.
+var auditLogger = log.WithName("audit")
+
+// clusterAdminClientAuditLog is the audit log shared by all cluster reconcilers.
+var clusterAdminClientAuditLog = newAdminClientAuditLog()
+
+// adminClientAuditEntry describes a single mutating operation that was run by an admin client.
+type adminClientAuditEntry struct {
+	// Timestamp defines when the operation was run.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Operation defines the admin client operation, e.g. ExcludeProcesses.
+	Operation string `json:"operation"`
+
+	// Arguments defines the arguments of the operation.
+	Arguments string `json:"arguments,omitempty"`
+
+	// Reconciler defines the sub-reconciler that initiated the operation.
+	Reconciler string `json:"reconciler,omitempty"`
+
+	// Generation defines the generation of the cluster spec at the time of the operation.
+	Generation int64 `json:"generation"`
+
+	// Result defines if the operation succeeded.
+	Result string `json:"result"`
+
+	// Error defines the error message of a failed operation.
+	Error string `json:"error,omitempty"`
+}
+
+// adminClientAuditLog collects the audit entries of all admin clients of the cluster reconciler.
+type adminClientAuditLog struct {
+	lock sync.Mutex
+
+	// reconcilers contains the sub-reconciler that is currently running for each cluster.
+	reconcilers map[types.NamespacedName]string
+
+	// pendingEntries contains the entries that are not yet written to the audit ConfigMap of each cluster.
+	pendingEntries map[types.NamespacedName][]adminClientAuditEntry
+}
+
+// newAdminClientAuditLog creates a new empty audit log.
+func newAdminClientAuditLog() *adminClientAuditLog {
+	return &adminClientAuditLog{
+		reconcilers:    map[types.NamespacedName]string{},
+		pendingEntries: map[types.NamespacedName][]adminClientAuditEntry{},
+	}
+}
+
+// setReconciler sets the sub-reconciler that initiates all following operations for the cluster.
+func (auditLog *adminClientAuditLog) setReconciler(cluster *fdbv1beta2.FoundationDBCluster, reconciler string) {
+	auditLog.lock.Lock()
+	defer auditLog.lock.Unlock()
+
+	auditLog.reconcilers[types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}] = reconciler
+}
+
+// record logs the result of an operation and keeps the entry for the audit ConfigMap of the cluster.
+func (auditLog *adminClientAuditLog) record(cluster *fdbv1beta2.FoundationDBCluster, operation string, arguments string, err error) {
+	auditLog.lock.Lock()
+	defer auditLog.lock.Unlock()
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	entry := adminClientAuditEntry{
+		Timestamp:  metav1.Now(),
+		Operation:  operation,
+		Arguments:  internal.RedactString(arguments),
+		Reconciler: auditLog.reconcilers[key],
+		Generation: cluster.ObjectMeta.Generation,
+		Result:     adminClientAuditResultSuccess,
+	}
+
+	if err != nil {
+		entry.Result = adminClientAuditResultFailure
+		entry.Error = internal.RedactString(err.Error())
+	}
+
+	auditLog.pendingEntries[key] = append(auditLog.pendingEntries[key], entry)
+	auditLogger.Info("Admin client operation",
+		"namespace", cluster.Namespace,
+		"cluster", cluster.Name,
+		"operation", entry.Operation,
+		"arguments", entry.Arguments,
+		"reconciler", entry.Reconciler,
+		"generation", entry.Generation,
+		"result", entry.Result,
+		"error", entry.Error)
+}
+
+// takePendingEntries returns and removes the pending entries of the cluster.
+func (auditLog *adminClientAuditLog) takePendingEntries(cluster *fdbv1beta2.FoundationDBCluster) []adminClientAuditEntry {
+	auditLog.lock.Lock()
+	defer auditLog.lock.Unlock()
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	entries := auditLog.pendingEntries[key]
+	delete(auditLog.pendingEntries, key)
+	delete(auditLog.reconcilers, key)
+
+	return entries
+}
+
+// restorePendingEntries adds the entries that could not be written to the audit ConfigMap back in front of the
+// pending entries of the cluster, so that they are written with the next flush. At most size entries are kept, as
+// only the latest size entries are kept in the audit ConfigMap.
+func (auditLog *adminClientAuditLog) restorePendingEntries(cluster *fdbv1beta2.FoundationDBCluster, entries []adminClientAuditEntry, size int) {
+	auditLog.lock.Lock()
+	defer auditLog.lock.Unlock()
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	entries = append(entries, auditLog.pendingEntries[key]...)
+	if len(entries) > size {
+		entries = entries[len(entries)-size:]
+	}
+
+	auditLog.pendingEntries[key] = entries
+}
+
+// flush appends the pending entries of the cluster to the audit ConfigMap and only keeps the latest size entries.
+// If size is 0 the pending entries are discarded, as they are already part of the log stream. If the audit ConfigMap
+// can't be updated, the entries are kept for the next flush.
+func (auditLog *adminClientAuditLog) flush(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, size int) error {
+	entries := auditLog.takePendingEntries(cluster)
+	if size <= 0 || len(entries) == 0 {
+		return nil
+	}
+
+	err := writeAuditEntries(ctx, r, cluster, entries, size)
+	if err != nil {
+		auditLog.restorePendingEntries(cluster, entries, size)
+	}
+
+	return err
+}
+
+// writeAuditEntries appends the entries to the audit ConfigMap of the cluster and only keeps the latest size entries.
+func writeAuditEntries(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, entries []adminClientAuditEntry, size int) error {
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: internal.GetAdminClientAuditConfigMapName(cluster)}, configMap)
+	create := false
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		create = true
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       cluster.Namespace,
+				Name:            internal.GetAdminClientAuditConfigMapName(cluster),
+				Labels:          cluster.GetMatchLabels(),
+				OwnerReferences: internal.BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta),
+			},
+		}
+	}
+
+	var existingEntries []adminClientAuditEntry
+	if configMap.Data[internal.AdminClientAuditLogKey] != "" {
+		err = json.Unmarshal([]byte(configMap.Data[internal.AdminClientAuditLogKey]), &existingEntries)
+		if err != nil {
+			return fmt.Errorf("could not parse audit log in ConfigMap %s: %w", configMap.Name, err)
+		}
+	}
+
+	entries = append(existingEntries, entries...)
+	if len(entries) > size {
+		entries = entries[len(entries)-size:]
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[internal.AdminClientAuditLogKey] = string(data)
+
+	if create {
+		return r.Create(ctx, configMap)
+	}
+
+	return r.Update(ctx, configMap)
+}
+
+// auditingDatabaseClientProvider provides admin clients that record all mutating operations in the audit log.
+type auditingDatabaseClientProvider struct {
+	fdbadminclient.DatabaseClientProvider
+	auditLog *adminClientAuditLog
+}
+
+// GetAdminClient generates a client for performing administrative actions against the database.
+func (provider auditingDatabaseClientProvider) GetAdminClient(cluster *fdbv1beta2.FoundationDBCluster, kubernetesClient client.Client) (fdbadminclient.AdminClient, error) {
+	adminClient, err := provider.DatabaseClientProvider.GetAdminClient(cluster, kubernetesClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditingAdminClient{AdminClient: adminClient, cluster: cluster, auditLog: provider.auditLog}, nil
+}
+
+// auditingAdminClient records all mutating operations of the wrapped admin client in the audit log.
+type auditingAdminClient struct {
+	fdbadminclient.AdminClient
+	cluster  *fdbv1beta2.FoundationDBCluster
+	auditLog *adminClientAuditLog
+}
+
+// ConfigureDatabase sets the database configuration
+func (client *auditingAdminClient) ConfigureDatabase(ctx context.Context, configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) error {
+	err := client.AdminClient.ConfigureDatabase(ctx, configuration, newDatabase, version)
+	configurationString, _ := configuration.GetConfigurationString(version)
+	client.auditLog.record(client.cluster, "ConfigureDatabase", fmt.Sprintf("%s newDatabase=%t", configurationString, newDatabase), err)
+
+	return err
+}
+
+// ExcludeProcesses starts evacuating processes so that they can be removed from the database.
+func (client *auditingAdminClient) ExcludeProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error {
+	err := client.AdminClient.ExcludeProcesses(ctx, addresses)
+	client.auditLog.record(client.cluster, "ExcludeProcesses", fmt.Sprintf("%v", addresses), err)
+
+	return err
+}
+
+// IncludeProcesses removes processes from the exclusion list and allows them to take on roles again.
+func (client *auditingAdminClient) IncludeProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error {
+	err := client.AdminClient.IncludeProcesses(ctx, addresses)
+	client.auditLog.record(client.cluster, "IncludeProcesses", fmt.Sprintf("%v", addresses), err)
+
+	return err
+}
+
+// KillProcesses restarts processes
+func (client *auditingAdminClient) KillProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error {
+	err := client.AdminClient.KillProcesses(ctx, addresses)
+	client.auditLog.record(client.cluster, "KillProcesses", fmt.Sprintf("%v", addresses), err)
+
+	return err
+}
+
+// ChangeCoordinators changes the coordinator set
+func (client *auditingAdminClient) ChangeCoordinators(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) (string, error) {
+	connectionString, err := client.AdminClient.ChangeCoordinators(ctx, addresses)
+	client.auditLog.record(client.cluster, "ChangeCoordinators", fmt.Sprintf("%v", addresses), err)
+
+	return connectionString, err
+}
+
+// ChangeClusterDescription changes the description in the connection string while keeping the current coordinators.
+func (client *auditingAdminClient) ChangeClusterDescription(ctx context.Context, description string) (string, error) {
+	connectionString, err := client.AdminClient.ChangeClusterDescription(ctx, description)
+	client.auditLog.record(client.cluster, "ChangeClusterDescription", description, err)
+
+	return connectionString, err
+}
+
+// StartBackup starts a new backup.
+func (client *auditingAdminClient) StartBackup(ctx context.Context, url string, snapshotPeriodSeconds int, stopAfterSnapshot bool) error {
+	err := client.AdminClient.StartBackup(ctx, url, snapshotPeriodSeconds, stopAfterSnapshot)
+	client.auditLog.record(client.cluster, "StartBackup", fmt.Sprintf("%s snapshotPeriodSeconds=%d stopAfterSnapshot=%t", url, snapshotPeriodSeconds, stopAfterSnapshot), err)
+
+	return err
+}
+
+// StopBackup stops a backup.
+func (client *auditingAdminClient) StopBackup(ctx context.Context, url string) error {
+	err := client.AdminClient.StopBackup(ctx, url)
+	client.auditLog.record(client.cluster, "StopBackup", url, err)
+
+	return err
+}
+
+// PauseBackups pauses the backups.
+func (client *auditingAdminClient) PauseBackups(ctx context.Context) error {
+	err := client.AdminClient.PauseBackups(ctx)
+	client.auditLog.record(client.cluster, "PauseBackups", "", err)
+
+	return err
+}
+
+// ResumeBackups resumes the backups.
+func (client *auditingAdminClient) ResumeBackups(ctx context.Context) error {
+	err := client.AdminClient.ResumeBackups(ctx)
+	client.auditLog.record(client.cluster, "ResumeBackups", "", err)
+
+	return err
+}
+
+// ModifyBackup modifies the configuration of the backup.
+func (client *auditingAdminClient) ModifyBackup(ctx context.Context, snapshotPeriodSeconds int) error {
+	err := client.AdminClient.ModifyBackup(ctx, snapshotPeriodSeconds)
+	client.auditLog.record(client.cluster, "ModifyBackup", fmt.Sprintf("snapshotPeriodSeconds=%d", snapshotPeriodSeconds), err)
+
+	return err
+}
+
+// StartRestore starts a new restore.
+func (client *auditingAdminClient) StartRestore(ctx context.Context, url string, keyRanges []fdbv1beta2.FoundationDBKeyRange, addPrefix string, removePrefix string) error {
+	err := client.AdminClient.StartRestore(ctx, url, keyRanges, addPrefix, removePrefix)
+	client.auditLog.record(client.cluster, "StartRestore", fmt.Sprintf("%s keyRanges=%v addPrefix=%s removePrefix=%s", url, keyRanges, addPrefix, removePrefix), err)
+
+	return err
+}
+
+// SetMaintenanceZone places zone into maintenance mode.
+func (client *auditingAdminClient) SetMaintenanceZone(ctx context.Context, zone string, timeoutSeconds int) error {
+	err := client.AdminClient.SetMaintenanceZone(ctx, zone, timeoutSeconds)
+	client.auditLog.record(client.cluster, "SetMaintenanceZone", fmt.Sprintf("%s timeoutSeconds=%d", zone, timeoutSeconds), err)
+
+	return err
+}
+
+// ResetMaintenanceMode resets the maintenance zone.
+func (client *auditingAdminClient) ResetMaintenanceMode(ctx context.Context) error {
+	err := client.AdminClient.ResetMaintenanceMode(ctx)
+	client.auditLog.record(client.cluster, "ResetMaintenanceMode", "", err)
+
+	return err
+}
+
+// CreateTenant creates a tenant in the database.
+func (client *auditingAdminClient) CreateTenant(ctx context.Context, name string) error {
+	err := client.AdminClient.CreateTenant(ctx, name)
+	client.auditLog.record(client.cluster, "CreateTenant", name, err)
+
+	return err
+}
+
+// DeleteTenant deletes a tenant from the database.
+func (client *auditingAdminClient) DeleteTenant(ctx context.Context, name string) error {
+	err := client.AdminClient.DeleteTenant(ctx, name)
+	client.auditLog.record(client.cluster, "DeleteTenant", name, err)
+
+	return err
+}
+
+// SetTagQuota sets the quota of a transaction tag.
+func (client *auditingAdminClient) SetTagQuota(ctx context.Context, quota fdbv1beta2.TagQuota) error {
+	err := client.AdminClient.SetTagQuota(ctx, quota)
+	client.auditLog.record(client.cluster, "SetTagQuota", fmt.Sprintf("%+v", quota), err)
+
+	return err
+}
+
+// SetDataDistributionMode enables or disables the data distribution.
+func (client *auditingAdminClient) SetDataDistributionMode(ctx context.Context, enabled bool) error {
+	err := client.AdminClient.SetDataDistributionMode(ctx, enabled)
+	client.auditLog.record(client.cluster, "SetDataDistributionMode", fmt.Sprintf("enabled=%t", enabled), err)
+
+	return err
+}
+
+// LockDatabase locks the database.
+func (client *auditingAdminClient) LockDatabase(ctx context.Context) (string, error) {
+	lockUID, err := client.AdminClient.LockDatabase(ctx)
+	client.auditLog.record(client.cluster, "LockDatabase", "", err)
+
+	return lockUID, err
+}
+
+// UnlockDatabase unlocks the database.
+func (client *auditingAdminClient) UnlockDatabase(ctx context.Context, lockUID string) error {
+	err := client.AdminClient.UnlockDatabase(ctx, lockUID)
+	client.auditLog.record(client.cluster, "UnlockDatabase", lockUID, err)
+
+	return err
+}
+
+// SetKnobs sets the Knobs that should be used for the commandline call.
+func (client *auditingAdminClient) SetKnobs(knobs []string) {
+	client.AdminClient.SetKnobs(knobs)
+	client.auditLog.record(client.cluster, "SetKnobs", fmt.Sprintf("%v", knobs), nil)
+}
+
+// UpdateWorkJournal stores the work journal in the database.
+func (client *auditingAdminClient) UpdateWorkJournal(ctx context.Context, journal *fdbadminclient.WorkJournal) error {
+	err := client.AdminClient.UpdateWorkJournal(ctx, journal)
+	var arguments string
+	if journal != nil {
+		arguments = fmt.Sprintf("pendingExclusions=%v coordinatorChange=%t", journal.PendingExclusions, journal.CoordinatorChange != nil)
+	}
+	client.auditLog.record(client.cluster, "UpdateWorkJournal", arguments, err)
+
+	return err
+}
@@ -63,7 +63,7 @@ var _ = Describe("admin_client_test", func() {
 		var status *fdbv1beta2.FoundationDBStatus
 
 		JustBeforeEach(func() {
-			status, err = mockAdminClient.GetStatus()
+			status, err = mockAdminClient.GetStatus(context.TODO())
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -193,7 +193,7 @@ var _ = Describe("admin_client_test", func() {
 
 				It("should have DNS names in the locality", func() {
 					locality := status.Cluster.Processes["operator-test-1-storage-1-1"].Locality
-					Expect(locality[fdbv1beta2.FDBLocalityDNSNameKey]).To(Equal(internal.GetPodDNSName(cluster, "operator-test-1-storage-1")))
+					Expect(locality[fdbv1beta2.FDBLocalityDNSNameKey]).To(Equal(internal.GetPodDNSName(cluster, fdbv1beta2.ProcessClassStorage, "operator-test-1-storage-1")))
 				})
 			})
 		})
@@ -227,7 +227,7 @@ var _ = Describe("admin_client_test", func() {
 
 		Context("with a backup running", func() {
 			BeforeEach(func() {
-				err = mockAdminClient.StartBackup("blobstore://test@test-service/test-backup", 10)
+				err = mockAdminClient.StartBackup(context.TODO(), "blobstore://test@test-service/test-backup", 10, false)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -244,7 +244,7 @@ var _ = Describe("admin_client_test", func() {
 
 			Context("with a paused backup", func() {
 				BeforeEach(func() {
-					err = mockAdminClient.PauseBackups()
+					err = mockAdminClient.PauseBackups(context.TODO())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -255,9 +255,9 @@ var _ = Describe("admin_client_test", func() {
 
 			Context("with an resume backup", func() {
 				BeforeEach(func() {
-					err = mockAdminClient.PauseBackups()
+					err = mockAdminClient.PauseBackups(context.TODO())
 					Expect(err).NotTo(HaveOccurred())
-					err = mockAdminClient.ResumeBackups()
+					err = mockAdminClient.ResumeBackups(context.TODO())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -268,7 +268,7 @@ var _ = Describe("admin_client_test", func() {
 
 			Context("with a stopped backup", func() {
 				BeforeEach(func() {
-					err = mockAdminClient.StopBackup("blobstore://test@test-service/test-backup")
+					err = mockAdminClient.StopBackup(context.TODO(), "blobstore://test@test-service/test-backup")
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -288,7 +288,7 @@ var _ = Describe("admin_client_test", func() {
 	Describe("backup status", func() {
 		var status *fdbv1beta2.FoundationDBLiveBackupStatus
 		JustBeforeEach(func() {
-			status, err = mockAdminClient.GetBackupStatus()
+			status, err = mockAdminClient.GetBackupStatus(context.TODO())
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -302,7 +302,7 @@ var _ = Describe("admin_client_test", func() {
 
 		Context("with a backup running", func() {
 			BeforeEach(func() {
-				err = mockAdminClient.StartBackup("blobstore://test@test-service/test-backup", 10)
+				err = mockAdminClient.StartBackup(context.TODO(), "blobstore://test@test-service/test-backup", 10, false)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -315,7 +315,7 @@ var _ = Describe("admin_client_test", func() {
 
 			Context("with a paused backup", func() {
 				BeforeEach(func() {
-					err = mockAdminClient.PauseBackups()
+					err = mockAdminClient.PauseBackups(context.TODO())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -326,9 +326,9 @@ var _ = Describe("admin_client_test", func() {
 
 			Context("with a resumed backup", func() {
 				BeforeEach(func() {
-					err = mockAdminClient.PauseBackups()
+					err = mockAdminClient.PauseBackups(context.TODO())
 					Expect(err).NotTo(HaveOccurred())
-					err = mockAdminClient.ResumeBackups()
+					err = mockAdminClient.ResumeBackups(context.TODO())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -339,7 +339,7 @@ var _ = Describe("admin_client_test", func() {
 
 			Context("with a stopped backup", func() {
 				BeforeEach(func() {
-					err = mockAdminClient.StopBackup("blobstore://test@test-service/test-backup")
+					err = mockAdminClient.StopBackup(context.TODO(), "blobstore://test@test-service/test-backup")
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -350,7 +350,7 @@ var _ = Describe("admin_client_test", func() {
 
 			Context("with a modification to the snapshot time", func() {
 				BeforeEach(func() {
-					err = mockAdminClient.ModifyBackup(20)
+					err = mockAdminClient.ModifyBackup(context.TODO(), 20)
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -366,7 +366,7 @@ var _ = Describe("admin_client_test", func() {
 
 		Context("with no restore running", func() {
 			BeforeEach(func() {
-				status, err = mockAdminClient.GetRestoreStatus()
+				status, err = mockAdminClient.GetRestoreStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -377,10 +377,10 @@ var _ = Describe("admin_client_test", func() {
 
 		Context("with a restore running", func() {
 			BeforeEach(func() {
-				err = mockAdminClient.StartRestore("blobstore://test@test-service/test-backup", nil)
+				err = mockAdminClient.StartRestore(context.TODO(), "blobstore://test@test-service/test-backup", nil, "", "")
 				Expect(err).NotTo(HaveOccurred())
 
-				status, err = mockAdminClient.GetRestoreStatus()
+				status, err = mockAdminClient.GetRestoreStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -0,0 +1,195 @@
+/*
+ * api_throttling.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	flowcontrolv1beta2 "k8s.io/api/flowcontrol/v1beta2"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+const (
+	// throttlingSourceClient is the source of the wait time for the client-side rate limiter.
+	throttlingSourceClient = "client"
+	// throttlingSourceServer is the source of the wait time for the API server throttling.
+	throttlingSourceServer = "server"
+	// defaultThrottlingDelay defines how long requests are delayed after a throttled response without a Retry-After
+	// header.
+	defaultThrottlingDelay = 1 * time.Second
+)
+
+// APIThrottlingOptions defines how the operator limits its requests to the Kubernetes API server.
+type APIThrottlingOptions struct {
+	// QPS defines the maximum number of requests per second of the client-side rate limiter.
+	QPS float32
+	// Burst defines the maximum burst of requests of the client-side rate limiter.
+	Burst int
+	// MaxThrottlingDelay defines the maximum duration that all requests are delayed after the API server throttled a
+	// request. If 0, requests are not delayed after a throttled request.
+	MaxThrottlingDelay time.Duration
+}
+
+// ConfigureAPIThrottling configures the client-side rate limiter of the rest config and delays all requests that are
+// sent with the rest config after the API server throttled a request. Throttled requests themselves are retried by
+// client-go according to the Retry-After header, which the API priority and fairness sets. The leader election and the
+// informers of the cache use a copy of the rest config without the rate limiter and the delay, as a delayed lease
+// renewal could make the operator lose its leadership and the watches shouldn't compete with the requests of the
+// reconcilers.
+func ConfigureAPIThrottling(config *rest.Config, managerOptions *ctrl.Options, options APIThrottlingOptions) {
+	unthrottledConfig := rest.CopyConfig(config)
+	// A negative QPS disables the client-side rate limiter of client-go.
+	unthrottledConfig.QPS = -1
+	unthrottledConfig.RateLimiter = nil
+	managerOptions.LeaderElectionConfig = unthrottledConfig
+
+	newCache := managerOptions.NewCache
+	if newCache == nil {
+		newCache = cache.New
+	}
+	managerOptions.NewCache = func(_ *rest.Config, cacheOptions cache.Options) (cache.Cache, error) {
+		return newCache(unthrottledConfig, cacheOptions)
+	}
+
+	config.QPS = options.QPS
+	config.Burst = options.Burst
+	config.RateLimiter = &meteredRateLimiter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(options.QPS, options.Burst),
+	}
+
+	// All clients that are created from the config share the same throttle.
+	throttle := &apiThrottle{maxDelay: options.MaxThrottlingDelay}
+	config.WrapTransport = transport.Wrappers(config.WrapTransport, func(delegate http.RoundTripper) http.RoundTripper {
+		return &throttlingRoundTripper{
+			delegate: delegate,
+			throttle: throttle,
+		}
+	})
+}
+
+// meteredRateLimiter records the time that requests wait for the client-side rate limiter.
+type meteredRateLimiter struct {
+	flowcontrol.RateLimiter
+}
+
+// Accept returns once a token becomes available.
+func (limiter *meteredRateLimiter) Accept() {
+	start := time.Now()
+	limiter.RateLimiter.Accept()
+	apiThrottlingWaitHistogram.WithLabelValues(throttlingSourceClient).Observe(time.Since(start).Seconds())
+}
+
+// Wait returns nil if a token is taken before the Context is done.
+func (limiter *meteredRateLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := limiter.RateLimiter.Wait(ctx)
+	apiThrottlingWaitHistogram.WithLabelValues(throttlingSourceClient).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// apiThrottle tracks until when requests are delayed because the API server throttled a request.
+type apiThrottle struct {
+	maxDelay       time.Duration
+	lock           sync.Mutex
+	throttledUntil time.Time
+}
+
+// throttlingRoundTripper records the requests that were throttled by the API server and delays all further requests
+// until the duration of the Retry-After header of the throttled response has passed.
+type throttlingRoundTripper struct {
+	delegate http.RoundTripper
+	throttle *apiThrottle
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (roundTripper *throttlingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	err := roundTripper.throttle.wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := roundTripper.delegate.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	apiThrottledRequestsCounter.WithLabelValues(req.Method, resp.Header.Get(flowcontrolv1beta2.ResponseHeaderMatchedPriorityLevelConfigurationUID)).Inc()
+	roundTripper.throttle.setThrottled(getRetryAfter(resp))
+
+	return resp, nil
+}
+
+// wait waits until the delay of the latest throttled request has passed.
+func (throttle *apiThrottle) wait(ctx context.Context) error {
+	throttle.lock.Lock()
+	delay := time.Until(throttle.throttledUntil)
+	throttle.lock.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		apiThrottlingWaitHistogram.WithLabelValues(throttlingSourceServer).Observe(delay.Seconds())
+		return nil
+	}
+}
+
+// setThrottled delays all further requests by the provided delay, limited by the maximum delay.
+func (throttle *apiThrottle) setThrottled(delay time.Duration) {
+	if delay > throttle.maxDelay {
+		delay = throttle.maxDelay
+	}
+
+	throttle.lock.Lock()
+	defer throttle.lock.Unlock()
+
+	throttledUntil := time.Now().Add(delay)
+	if throttledUntil.After(throttle.throttledUntil) {
+		throttle.throttledUntil = throttledUntil
+	}
+}
+
+// getRetryAfter returns the duration of the Retry-After header of the response or the default delay if the header is
+// missing or invalid.
+func getRetryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return defaultThrottlingDelay
+	}
+
+	return time.Duration(seconds) * time.Second
+}
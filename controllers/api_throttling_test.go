@@ -0,0 +1,173 @@
+/*
+ * api_throttling_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	flowcontrolv1beta2 "k8s.io/api/flowcontrol/v1beta2"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// roundTripperFunc implements the http.RoundTripper interface for a function.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements the http.RoundTripper interface.
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("api_throttling", func() {
+	When("configuring the API throttling", func() {
+		var config *rest.Config
+		var managerOptions ctrl.Options
+
+		BeforeEach(func() {
+			config = &rest.Config{}
+			managerOptions = ctrl.Options{}
+			ConfigureAPIThrottling(config, &managerOptions, APIThrottlingOptions{QPS: 50, Burst: 100, MaxThrottlingDelay: 5 * time.Second})
+		})
+
+		It("should set the rate limiter and wrap the transport", func() {
+			Expect(config.QPS).To(BeNumerically("==", 50))
+			Expect(config.Burst).To(Equal(100))
+			Expect(config.RateLimiter).NotTo(BeNil())
+			Expect(config.RateLimiter.QPS()).To(BeNumerically("==", 50))
+			Expect(config.WrapTransport).NotTo(BeNil())
+		})
+
+		It("should share the throttle between the round trippers", func() {
+			first, ok := config.WrapTransport(http.DefaultTransport).(*throttlingRoundTripper)
+			Expect(ok).To(BeTrue())
+			second, ok := config.WrapTransport(http.DefaultTransport).(*throttlingRoundTripper)
+			Expect(ok).To(BeTrue())
+			Expect(first.throttle).To(BeIdenticalTo(second.throttle))
+		})
+
+		It("should use a config without the rate limiter and the delay for the leader election and the cache", func() {
+			Expect(managerOptions.LeaderElectionConfig).NotTo(BeNil())
+			Expect(managerOptions.LeaderElectionConfig.QPS).To(BeNumerically("<", 0))
+			Expect(managerOptions.LeaderElectionConfig.RateLimiter).To(BeNil())
+			Expect(managerOptions.LeaderElectionConfig.WrapTransport).To(BeNil())
+			Expect(managerOptions.NewCache).NotTo(BeNil())
+		})
+	})
+
+	When("sending requests", func() {
+		var roundTripper *throttlingRoundTripper
+		var statusCode int
+		var requests int
+		var throttledBefore float64
+
+		BeforeEach(func() {
+			requests = 0
+			statusCode = http.StatusOK
+			roundTripper = &throttlingRoundTripper{
+				delegate: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					requests++
+					recorder := httptest.NewRecorder()
+					recorder.Header().Set("Retry-After", "1")
+					recorder.Header().Set(flowcontrolv1beta2.ResponseHeaderMatchedPriorityLevelConfigurationUID, "workload-low")
+					recorder.WriteHeader(statusCode)
+					return recorder.Result(), nil
+				}),
+				throttle: &apiThrottle{maxDelay: 200 * time.Millisecond},
+			}
+			throttledBefore = testutil.ToFloat64(apiThrottledRequestsCounter.WithLabelValues(http.MethodGet, "workload-low"))
+		})
+
+		sendRequest := func(ctx context.Context) (*http.Response, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://kubernetes.default.svc/api/v1/pods", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			return roundTripper.RoundTrip(req)
+		}
+
+		When("the API server doesn't throttle the request", func() {
+			It("should not delay the next request", func() {
+				resp, err := sendRequest(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(roundTripper.throttle.throttledUntil.IsZero()).To(BeTrue())
+				Expect(testutil.ToFloat64(apiThrottledRequestsCounter.WithLabelValues(http.MethodGet, "workload-low"))).To(Equal(throttledBefore))
+			})
+		})
+
+		When("the API server throttles the request", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusTooManyRequests
+			})
+
+			It("should return the response and record the throttled request", func() {
+				resp, err := sendRequest(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+				Expect(testutil.ToFloat64(apiThrottledRequestsCounter.WithLabelValues(http.MethodGet, "workload-low"))).To(Equal(throttledBefore + 1))
+			})
+
+			It("should delay the next request by at most the maximum delay", func() {
+				_, err := sendRequest(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+
+				start := time.Now()
+				statusCode = http.StatusOK
+				_, err = sendRequest(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+				Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+				Expect(requests).To(Equal(2))
+			})
+
+			It("should return an error if the context is cancelled while the request is delayed", func() {
+				_, err := sendRequest(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+
+				ctx, cancel := context.WithCancel(context.TODO())
+				cancel()
+				_, err = sendRequest(ctx)
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(requests).To(Equal(1))
+			})
+		})
+	})
+
+	DescribeTable("getting the Retry-After duration",
+		func(header string, expected time.Duration) {
+			resp := &http.Response{Header: http.Header{}}
+			if header != "" {
+				resp.Header.Set("Retry-After", header)
+			}
+
+			Expect(getRetryAfter(resp)).To(Equal(expected))
+		},
+		Entry("without header", "", defaultThrottlingDelay),
+		Entry("with seconds", "3", 3*time.Second),
+		Entry("with an invalid value", "soon", defaultThrottlingDelay),
+	)
+})
@@ -0,0 +1,149 @@
+/*
+ * autoscale_storage_processes.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// autoscaleStorageProcesses provides a reconciliation step for scaling the storage processes based on their disk
+// utilization.
+type autoscaleStorageProcesses struct{}
+
+// reconcile runs the reconciler's work.
+func (autoscaleStorageProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if cluster.Spec.StorageAutoscaling == nil || !cluster.Status.Configured {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "autoscaleStorageProcesses")
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus(ctx)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	diskUtilization, ok := getStorageDiskUtilization(status)
+	if !ok {
+		logger.Info("Skipping storage autoscaling because no disk information is reported")
+		return nil
+	}
+
+	processCounts, err := cluster.GetProcessCountsWithDefaults()
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	originalStatus := cluster.Status.StorageAutoscaling.DeepCopy()
+	if cluster.Status.StorageAutoscaling == nil {
+		cluster.Status.StorageAutoscaling = &fdbv1beta2.StorageAutoscalingStatus{}
+	}
+	autoscalingStatus := cluster.Status.StorageAutoscaling
+	autoscalingStatus.DiskUtilization = diskUtilization
+	autoscalingStatus.RecommendedStorageProcesses = cluster.GetRecommendedStorageProcessCount(processCounts.Storage, diskUtilization)
+
+	var result *requeue
+	if autoscalingStatus.RecommendedStorageProcesses > processCounts.Storage {
+		if !cluster.IsStorageAutoscalingEnabled() {
+			// Only emit the recommendation if it changed, otherwise every reconciliation would emit an event.
+			if originalStatus == nil || originalStatus.RecommendedStorageProcesses != autoscalingStatus.RecommendedStorageProcesses {
+				r.Recorder.Event(cluster, corev1.EventTypeNormal, "StorageScalingRecommended",
+					fmt.Sprintf("Disk utilization of storage processes is %d%%, recommended storage process count is %d", diskUtilization, autoscalingStatus.RecommendedStorageProcesses))
+			}
+		} else if ready, message := canScaleStorageProcesses(cluster, status, processCounts.Storage); !ready {
+			result = &requeue{message: message, delayedRequeue: true}
+		} else {
+			logger.Info("Scaling storage processes", "diskUtilization", diskUtilization, "current", processCounts.Storage, "desired", autoscalingStatus.RecommendedStorageProcesses)
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, "ScalingStorageProcesses",
+				fmt.Sprintf("Disk utilization of storage processes is %d%%, increasing storage process count from %d to %d", diskUtilization, processCounts.Storage, autoscalingStatus.RecommendedStorageProcesses))
+			autoscalingStatus.StorageProcesses = autoscalingStatus.RecommendedStorageProcesses
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(originalStatus, cluster.Status.StorageAutoscaling) {
+		err = r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return result
+}
+
+// getStorageDiskUtilization returns the average disk utilization in percent of all storage processes that are not
+// excluded. If no storage process reports disk information, false will be returned.
+func getStorageDiskUtilization(status *fdbv1beta2.FoundationDBStatus) (int, bool) {
+	var usedBytes, totalBytes int64
+	for _, process := range status.Cluster.Processes {
+		if process.ProcessClass != fdbv1beta2.ProcessClassStorage || process.Excluded || process.Disk.TotalBytes == 0 {
+			continue
+		}
+
+		usedBytes += process.Disk.TotalBytes - process.Disk.FreeBytes
+		totalBytes += process.Disk.TotalBytes
+	}
+
+	if totalBytes == 0 {
+		return 0, false
+	}
+
+	return int(usedBytes * 100 / totalBytes), true
+}
+
+// canScaleStorageProcesses checks if the storage processes can be scaled. The storage processes will only be scaled
+// if all storage processes from the previous scaling are running and no data is moved, otherwise the autoscaler
+// would increase the count again before the data is distributed to the new processes.
+func canScaleStorageProcesses(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, desiredStorageProcesses int) (bool, string) {
+	storageProcessGroups := 0
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage || processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		if len(processGroup.ProcessGroupConditions) > 0 {
+			return false, fmt.Sprintf("Waiting for storage process group %s to be ready before scaling storage processes", processGroup.ProcessGroupID)
+		}
+
+		storageProcessGroups++
+	}
+
+	if storageProcessGroups < desiredStorageProcesses {
+		return false, fmt.Sprintf("Waiting for %d storage process groups to be created before scaling storage processes", desiredStorageProcesses-storageProcessGroups)
+	}
+
+	movingData := status.Cluster.Data.MovingData
+	if movingData.InFlightBytes > 0 || movingData.InQueueBytes > 0 {
+		return false, "Waiting for data movement to finish before scaling storage processes"
+	}
+
+	return true, ""
+}
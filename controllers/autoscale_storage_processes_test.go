@@ -0,0 +1,177 @@
+/*
+ * autoscale_storage_processes_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("autoscale_storage_processes", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		generation, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generation).To(Equal(int64(1)))
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+		adminClient.StorageDiskInfo = fdbv1beta2.FoundationDBStatusProcessDiskInfo{TotalBytes: 100, FreeBytes: 10}
+	})
+
+	JustBeforeEach(func() {
+		requeue = autoscaleStorageProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("storage autoscaling is not configured", func() {
+		It("should not report a recommendation", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.StorageAutoscaling).To(BeNil())
+		})
+	})
+
+	When("storage autoscaling is disabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.StorageAutoscaling = &fdbv1beta2.StorageAutoscalingSpec{
+				MaxStorageProcesses: 10,
+			}
+		})
+
+		It("should only report the recommendation", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.StorageAutoscaling).NotTo(BeNil())
+			Expect(cluster.Status.StorageAutoscaling.DiskUtilization).To(Equal(90))
+			Expect(cluster.Status.StorageAutoscaling.RecommendedStorageProcesses).To(Equal(5))
+			Expect(cluster.Status.StorageAutoscaling.StorageProcesses).To(BeZero())
+
+			counts, err := cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Storage).To(Equal(4))
+		})
+
+		It("should only emit the recommendation if it changes", func() {
+			getRecommendationEvents := func() []corev1.Event {
+				events := &corev1.EventList{}
+				Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+				var recommendations []corev1.Event
+				for _, event := range events.Items {
+					if event.InvolvedObject.UID == cluster.ObjectMeta.UID && event.Reason == "StorageScalingRecommended" {
+						recommendations = append(recommendations, event)
+					}
+				}
+
+				return recommendations
+			}
+
+			Expect(getRecommendationEvents()).To(HaveLen(1))
+			Expect(autoscaleStorageProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(getRecommendationEvents()).To(HaveLen(1))
+
+			cluster.Spec.StorageAutoscaling.DiskUtilizationThreshold = pointer.Int(50)
+			Expect(autoscaleStorageProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(cluster.Status.StorageAutoscaling.RecommendedStorageProcesses).To(Equal(8))
+			Expect(getRecommendationEvents()).To(HaveLen(2))
+		})
+	})
+
+	When("storage autoscaling is enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.StorageAutoscaling = &fdbv1beta2.StorageAutoscalingSpec{
+				Enabled:             pointer.Bool(true),
+				MaxStorageProcesses: 10,
+			}
+		})
+
+		It("should increase the storage process count", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.StorageAutoscaling.StorageProcesses).To(Equal(5))
+
+			counts, err := cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Storage).To(Equal(5))
+		})
+
+		When("the disk utilization is below the threshold", func() {
+			BeforeEach(func() {
+				adminClient.StorageDiskInfo = fdbv1beta2.FoundationDBStatusProcessDiskInfo{TotalBytes: 100, FreeBytes: 50}
+			})
+
+			It("should not change the storage process count", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.StorageAutoscaling.DiskUtilization).To(Equal(50))
+				Expect(cluster.Status.StorageAutoscaling.RecommendedStorageProcesses).To(Equal(4))
+				Expect(cluster.Status.StorageAutoscaling.StorageProcesses).To(BeZero())
+			})
+		})
+
+		When("the maximum storage process count is reached", func() {
+			BeforeEach(func() {
+				cluster.Spec.StorageAutoscaling.MaxStorageProcesses = 4
+			})
+
+			It("should not change the storage process count", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.StorageAutoscaling.RecommendedStorageProcesses).To(Equal(4))
+				Expect(cluster.Status.StorageAutoscaling.StorageProcesses).To(BeZero())
+			})
+		})
+
+		When("a storage process group has a condition", func() {
+			BeforeEach(func() {
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage {
+						continue
+					}
+
+					processGroup.UpdateCondition(fdbv1beta2.MissingProcesses, true, nil, "")
+					break
+				}
+			})
+
+			It("should wait before scaling", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(cluster.Status.StorageAutoscaling.RecommendedStorageProcesses).To(Equal(5))
+				Expect(cluster.Status.StorageAutoscaling.StorageProcesses).To(BeZero())
+			})
+		})
+	})
+})
@@ -22,9 +22,11 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -40,6 +42,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// backupStatusRefreshInterval defines how often the status of a running backup is refreshed, so the reported progress
+// and the backup metrics don't go stale between changes of the backup resource.
+const backupStatusRefreshInterval = 1 * time.Minute
+
 // FoundationDBBackupReconciler reconciles a FoundationDBCluster object
 type FoundationDBBackupReconciler struct {
 	client.Client
@@ -48,11 +54,14 @@ type FoundationDBBackupReconciler struct {
 	InSimulation           bool
 	DatabaseClientProvider fdbadminclient.DatabaseClientProvider
 	ServerSideApply        bool
+	DeprecationOptions     internal.DeprecationOptions
 }
 
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbbackups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile runs the reconciliation logic.
@@ -73,11 +82,16 @@ func (r *FoundationDBBackupReconciler) Reconcile(ctx context.Context, request ct
 		return ctrl.Result{}, err
 	}
 
+	internal.NormalizeBackupSpec(backup, r.DeprecationOptions)
+
 	backupLog := log.WithValues("namespace", backup.Namespace, "backup", backup.Name)
 
 	subReconcilers := []backupSubReconciler{
 		updateBackupStatus{},
+		updateBackupServiceAccount{},
+		checkBackupBlobStore{},
 		updateBackupAgents{},
+		takeVolumeSnapshotBackup{},
 		startBackup{},
 		stopBackup{},
 		toggleBackupPaused{},
@@ -85,7 +99,12 @@ func (r *FoundationDBBackupReconciler) Reconcile(ctx context.Context, request ct
 		updateBackupStatus{},
 	}
 
+	normalizedSpec := backup.Spec.DeepCopy()
+
 	for _, subReconciler := range subReconcilers {
+		// We have to set the normalized spec here again otherwise any call to Update() for the status of the backup
+		// will reset all normalized fields...
+		backup.Spec = *(normalizedSpec.DeepCopy())
 		requeue := subReconciler.reconcile(ctx, r, backup)
 		if requeue == nil {
 			continue
@@ -101,6 +120,10 @@ func (r *FoundationDBBackupReconciler) Reconcile(ctx context.Context, request ct
 
 	backupLog.Info("Reconciliation complete")
 
+	if backup.Status.BackupDetails != nil && backup.Status.BackupDetails.Running {
+		return ctrl.Result{RequeueAfter: backupStatusRefreshInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
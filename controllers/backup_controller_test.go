@@ -34,8 +34,12 @@ import (
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 )
 
 func reloadBackup(backup *fdbv1beta2.FoundationDBBackup) (int64, error) {
@@ -131,7 +135,10 @@ var _ = Describe("backup_controller", func() {
 			})
 
 			It("should update the status on the resource", func() {
-				Expect(backup.Status).To(Equal(fdbv1beta2.FoundationDBBackupStatus{
+				status := backup.Status.DeepCopy()
+				Expect(meta.IsStatusConditionTrue(status.Conditions, fdbv1beta2.BlobStoreReadyCondition)).To(BeTrue())
+				status.Conditions = nil
+				Expect(*status).To(Equal(fdbv1beta2.FoundationDBBackupStatus{
 					AgentCount:           3,
 					DeploymentConfigured: true,
 					BackupDetails: &fdbv1beta2.FoundationDBBackupStatusBackupDetails{
@@ -146,12 +153,18 @@ var _ = Describe("backup_controller", func() {
 			})
 
 			It("should start a backup", func() {
-				status, err := adminClient.GetBackupStatus()
+				status, err := adminClient.GetBackupStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status.DestinationURL).To(Equal("blobstore://test@test-service/test-backup?bucket=fdb-backups"))
 				Expect(status.Status.Running).To(BeTrue())
 				Expect(status.BackupAgentsPaused).To(BeFalse())
 			})
+
+			It("should requeue to refresh the backup status", func() {
+				result, err := reconcileBackup(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(backupStatusRefreshInterval))
+			})
 		})
 
 		Context("with a nil backup agent count", func() {
@@ -194,10 +207,16 @@ var _ = Describe("backup_controller", func() {
 			})
 
 			It("should stop the backup", func() {
-				status, err := adminClient.GetBackupStatus()
+				status, err := adminClient.GetBackupStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status.Status.Running).To(BeFalse())
 			})
+
+			It("should not requeue to refresh the backup status", func() {
+				result, err := reconcileBackup(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeZero())
+			})
 		})
 
 		Context("when pausing a backup", func() {
@@ -208,7 +227,7 @@ var _ = Describe("backup_controller", func() {
 			})
 
 			It("should pause the backup", func() {
-				status, err := adminClient.GetBackupStatus()
+				status, err := adminClient.GetBackupStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status.BackupAgentsPaused).To(BeTrue())
 			})
@@ -216,7 +235,7 @@ var _ = Describe("backup_controller", func() {
 
 		Context("when resuming a backup", func() {
 			BeforeEach(func() {
-				err = adminClient.PauseBackups()
+				err = adminClient.PauseBackups(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 
 				backup.Spec.BackupState = ""
@@ -225,12 +244,71 @@ var _ = Describe("backup_controller", func() {
 			})
 
 			It("should resume the backup", func() {
-				status, err := adminClient.GetBackupStatus()
+				status, err := adminClient.GetBackupStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status.BackupAgentsPaused).To(BeFalse())
 			})
 		})
 
+		When("using the Snapshot mode", func() {
+			BeforeEach(func() {
+				adminClient.Backups = map[string]fdbv1beta2.FoundationDBBackupStatusBackupDetails{}
+				backup.Spec.BackupMode = fdbv1beta2.BackupModeSnapshot
+				Expect(k8sClient.Update(context.TODO(), backup)).NotTo(HaveOccurred())
+			})
+
+			It("should start a backup that stops after the snapshot", func() {
+				status, err := adminClient.GetBackupStatus(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Status.Running).To(BeTrue())
+				Expect(status.StopAfterSnapshot).To(BeTrue())
+				Expect(backup.Status.BackupDetails).NotTo(BeNil())
+				Expect(backup.Status.BackupDetails.StopAfterSnapshot).To(BeTrue())
+			})
+
+			When("the snapshot has completed", func() {
+				JustBeforeEach(func() {
+					details := adminClient.Backups["default"]
+					details.Running = false
+					details.Completed = true
+					adminClient.Backups["default"] = details
+
+					result, err := reconcileBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Requeue).To(BeFalse())
+					_, err = reloadBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should not start the backup again", func() {
+					status, err := adminClient.GetBackupStatus(context.TODO())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(status.Status.Running).To(BeFalse())
+					Expect(status.Status.Completed).To(BeTrue())
+					Expect(backup.Status.BackupDetails.Completed).To(BeTrue())
+					Expect(backup.Status.Generations.Reconciled).To(Equal(backup.ObjectMeta.Generation))
+				})
+			})
+		})
+
+		When("the backup is restorable", func() {
+			BeforeEach(func() {
+				details := adminClient.Backups["default"]
+				details.Restorable = true
+				details.LastRestorableVersion = pointer.Int64(1592184263)
+				details.SecondsBehind = pointer.Int64(7)
+				adminClient.Backups["default"] = details
+				generationGap = 0
+			})
+
+			It("should record the lag of the backup in the status", func() {
+				Expect(backup.Status.BackupDetails).NotTo(BeNil())
+				Expect(backup.Status.BackupDetails.Restorable).To(BeTrue())
+				Expect(backup.Status.BackupDetails.LastRestorableVersion).To(Equal(pointer.Int64(1592184263)))
+				Expect(backup.Status.BackupDetails.SecondsBehind).To(Equal(pointer.Int64(7)))
+			})
+		})
+
 		Context("when changing a backup snapshot time", func() {
 			BeforeEach(func() {
 				period := 100000
@@ -240,7 +318,7 @@ var _ = Describe("backup_controller", func() {
 			})
 
 			It("should modify the backup", func() {
-				status, err := adminClient.GetBackupStatus()
+				status, err := adminClient.GetBackupStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status.SnapshotIntervalSeconds).To(Equal(100000))
 			})
@@ -267,6 +345,33 @@ var _ = Describe("backup_controller", func() {
 			})
 		})
 
+		Context("when configuring a workload identity", func() {
+			BeforeEach(func() {
+				backup.Spec.BlobStoreConfiguration.WorkloadIdentity = &fdbv1beta2.WorkloadIdentityConfiguration{
+					Provider: fdbv1beta2.WorkloadIdentityProviderAWS,
+					Role:     "arn:aws:iam::123456789012:role/fdb-backup",
+				}
+				err = k8sClient.Update(context.TODO(), backup)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should create the service account with the role annotation", func() {
+				serviceAccount := &corev1.ServiceAccount{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: "operator-test-1-backup-agents"}, serviceAccount)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(serviceAccount.Annotations).To(HaveKeyWithValue("eks.amazonaws.com/role-arn", "arn:aws:iam::123456789012:role/fdb-backup"))
+				Expect(serviceAccount.OwnerReferences).To(HaveLen(1))
+			})
+
+			It("should use the service account for the backup agents", func() {
+				deployments := &appsv1.DeploymentList{}
+				err = k8sClient.List(context.TODO(), deployments)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(deployments.Items)).To(Equal(1))
+				Expect(deployments.Items[0].Spec.Template.Spec.ServiceAccountName).To(Equal("operator-test-1-backup-agents"))
+			})
+		})
+
 		Context("when changing annotations", func() {
 			BeforeEach(func() {
 				deployments := &appsv1.DeploymentList{}
@@ -312,5 +417,123 @@ var _ = Describe("backup_controller", func() {
 				Expect(adminClient.Knobs).To(HaveKey("--knob_http_verbose_level=3"))
 			})
 		})
+
+		When("the operator defines default image configs", func() {
+			BeforeEach(func() {
+				// The mock client persists the normalized spec with the status update, so the image configs
+				// must be reset like the API server would do it.
+				backup.Spec.MainContainer.ImageConfigs = nil
+				backup.Spec.SidecarContainer.ImageConfigs = nil
+				Expect(k8sClient.Update(context.TODO(), backup)).To(Succeed())
+				backupReconciler.DeprecationOptions = internal.DeprecationOptions{
+					DefaultMainContainerImageConfigs: []fdbv1beta2.ImageConfig{
+						{BaseImage: "registry.example/foundationdb"},
+					},
+				}
+			})
+
+			AfterEach(func() {
+				backupReconciler.DeprecationOptions = internal.DeprecationOptions{}
+			})
+
+			It("should use the default image configs of the operator for the backup deployment", func() {
+				deployment := &appsv1.Deployment{}
+				deploymentName := fmt.Sprintf("%s-backup-agents", cluster.Name)
+
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: deploymentName}, deployment)).To(Succeed())
+				Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("registry.example/foundationdb:%s", cluster.Spec.Version)))
+			})
+		})
+	})
+
+	When("checking the blobstore credentials", func() {
+		var credentials []byte
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			backup.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: fdbv1beta2.MainContainerName,
+							Env: []corev1.EnvVar{
+								{
+									Name:  internal.BlobCredentialsEnvironmentVariable,
+									Value: "/var/backup-credentials/credentials",
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "backup-credentials",
+									MountPath: "/var/backup-credentials",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "backup-credentials",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: "backup-credentials"},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "backup-credentials",
+					Namespace: backup.Namespace,
+				},
+				Data: map[string][]byte{
+					"credentials": credentials,
+				},
+			})).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(context.TODO(), backup)).NotTo(HaveOccurred())
+			_, err = reconcileBackup(backup)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.Name}, backup)).NotTo(HaveOccurred())
+		})
+
+		When("the credentials are valid", func() {
+			BeforeEach(func() {
+				credentials = []byte(`{"accounts":{"test@test-service":{"secret":"secret"}}}`)
+			})
+
+			It("should mark the blobstore as ready and create the backup deployment", func() {
+				condition := meta.FindStatusCondition(backup.Status.Conditions, fdbv1beta2.BlobStoreReadyCondition)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal(fdbv1beta2.BlobStoreValidatedReason))
+
+				deployment := &appsv1.Deployment{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: fmt.Sprintf("%s-backup-agents", backup.Name)}, deployment)).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the credentials are invalid", func() {
+			BeforeEach(func() {
+				credentials = []byte(`{"accounts":{"other@test-service":{"secret":"secret"}}}`)
+			})
+
+			It("should report the invalid credentials and not create the backup deployment", func() {
+				condition := meta.FindStatusCondition(backup.Status.Conditions, fdbv1beta2.BlobStoreReadyCondition)
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Reason).To(Equal(fdbv1beta2.BlobStoreCredentialsInvalidReason))
+				Expect(condition.Message).To(Equal("credentials file contains no credentials for account test@test-service"))
+
+				deployment := &appsv1.Deployment{}
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: fmt.Sprintf("%s-backup-agents", backup.Name)}, deployment)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
 	})
 })
@@ -53,12 +53,12 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 	}
 	defer adminClient.Close()
 
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 
-	minimumUptime, addressMap, err := internal.GetMinimumUptimeAndAddressMap(logger, cluster, status, r.EnableRecoveryState)
+	minimumUptime, addressMap, err := internal.GetMinimumUptimeAndAddressMap(logger, cluster, status, r.getRuntimeSettings().EnableRecoveryState)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -90,6 +90,31 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		}
 	}
 
+	if req := checkSettleTime(logger, cluster, status, "bouncing processes"); req != nil {
+		return req
+	}
+
+	if req := checkRecoveryFreeze(logger, cluster, status, "bouncing processes"); req != nil {
+		return req
+	}
+
+	if req := checkRestoreInProgress(ctx, logger, r, cluster, "bouncing processes"); req != nil {
+		return req
+	}
+
+	if req := checkBounceSchedule(logger, cluster, status, "bouncing processes", time.Now()); req != nil {
+		return req
+	}
+
+	// During a version incompatible upgrade the fault tolerance drops once the first processes are restarted and an
+	// unavailable database might require the bounce to recover, so the fault tolerance is only checked for other
+	// bounces.
+	if status.Client.DatabaseStatus.Available && !cluster.IsBeingUpgradedWithVersionIncompatibleVersion() {
+		if req := checkFaultTolerance(logger, cluster, status, "bouncing processes"); req != nil {
+			return req
+		}
+	}
+
 	var lockClient fdbadminclient.LockClient
 	useLocks := cluster.ShouldUseLocks()
 	if useLocks {
@@ -143,9 +168,51 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		return nil
 	}
 
+	// Bounces during a version incompatible upgrade must restart all processes at the same time, so they are only
+	// counted against the action budget.
+	if !upgrading {
+		if req := checkActionBudget(logger, r, cluster, "bouncing processes"); req != nil {
+			return req
+		}
+
+		if remaining := getRemainingActionBudget(r, cluster); len(addresses) > remaining {
+			logger.Info("Limiting bounced processes to the remaining action budget", "count", len(addresses), "remaining", remaining)
+			addresses = addresses[:remaining]
+		}
+	}
+
+	// During a version incompatible upgrade all processes must be restarted at the same time, so the proxies are not
+	// drained.
+	if !upgrading {
+		if req := checkProxyDrain(ctx, logger, r, cluster, status, addresses); req != nil {
+			return req
+		}
+	}
+
 	logger.Info("Bouncing processes", "addresses", addresses, "upgrading", upgrading)
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "BouncingProcesses", fmt.Sprintf("Bouncing processes: %v", addresses))
-	err = adminClient.KillProcesses(addresses)
+	err = adminClient.KillProcesses(ctx, addresses)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	reason := "processes have a pending configuration change"
+	if upgrading {
+		reason = fmt.Sprintf("upgrading cluster to version %s", cluster.Spec.Version)
+	}
+	r.recordAction(cluster, fmt.Sprintf("bounced processes %v", addresses), reason)
+
+	err = consumeActionBudget(ctx, r, cluster, len(addresses))
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	err = recordDestructiveAction(ctx, r, cluster, "bouncing processes")
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	err = clearProxyDrain(ctx, r, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
@@ -96,6 +97,79 @@ var _ = Describe("bounceProcesses", func() {
 		})
 	})
 
+	Context("with incorrect processes and a settle time", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.SettleTimeSeconds = pointer.Int(300)
+			processGroup := cluster.Status.ProcessGroups[len(cluster.Status.ProcessGroups)-4]
+			Expect(processGroup.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+			processGroup.UpdateCondition(fdbv1beta2.IncorrectCommandLine, true, nil, "")
+		})
+
+		When("no destructive action was performed before", func() {
+			It("should kill the targeted processes", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.KilledAddresses).NotTo(BeEmpty())
+			})
+
+			It("should record the destructive action", func() {
+				Expect(cluster.Status.LastDestructiveAction).NotTo(BeNil())
+				Expect(cluster.Status.LastDestructiveAction.Action).To(Equal("bouncing processes"))
+				Expect(cluster.Status.LastDestructiveAction.Timestamp).NotTo(BeNil())
+			})
+		})
+
+		When("the last destructive action was performed recently", func() {
+			BeforeEach(func() {
+				cluster.Status.LastDestructiveAction = &fdbv1beta2.DestructiveActionStatus{
+					Action:    "changing coordinators",
+					Timestamp: &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+				}
+			})
+
+			It("should requeue until the cluster has settled", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.message).To(HavePrefix("Waiting 4m0s for the cluster to settle before bouncing processes"))
+				Expect(requeue.delay).To(BeNumerically("~", 4*time.Minute, time.Second))
+			})
+
+			It("should not kill any processes", func() {
+				Expect(adminClient.KilledAddresses).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("with incorrect processes and a bounce schedule", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.BounceScheduleOptions.MaxOperationsPerSecond = pointer.Int(0)
+			processGroup := cluster.Status.ProcessGroups[len(cluster.Status.ProcessGroups)-4]
+			Expect(processGroup.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+			processGroup.UpdateCondition(fdbv1beta2.IncorrectCommandLine, true, nil, "")
+		})
+
+		When("the load of the cluster is above the maximum", func() {
+			It("should defer the bounce", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.message).To(Equal("Deferring bouncing processes until the load is low or until the next bounce window"))
+				Expect(requeue.delayedRequeue).To(BeTrue())
+			})
+
+			It("should not kill any processes", func() {
+				Expect(adminClient.KilledAddresses).To(BeEmpty())
+			})
+		})
+
+		When("an urgent rollout is active", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.BounceScheduleOptions.UrgentUntil = &metav1.Time{Time: time.Now().Add(1 * time.Hour)}
+			})
+
+			It("should kill the targeted processes", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.KilledAddresses).NotTo(BeEmpty())
+			})
+		})
+	})
+
 	Context("with incorrect processes and process marked for removal", func() {
 		BeforeEach(func() {
 			processGroup := cluster.Status.ProcessGroups[len(cluster.Status.ProcessGroups)-4]
@@ -130,7 +204,7 @@ var _ = Describe("bounceProcesses", func() {
 			Expect(processGroup.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
 			processGroup.UpdateCondition(fdbv1beta2.IncorrectCommandLine, true, nil, "")
 			for _, address := range processGroup.Addresses {
-				err := adminClient.ExcludeProcesses([]fdbv1beta2.ProcessAddress{{StringAddress: address, Port: 4501}})
+				err := adminClient.ExcludeProcesses(context.TODO(), []fdbv1beta2.ProcessAddress{{StringAddress: address, Port: 4501}})
 				Expect(err).To(BeNil())
 			}
 		})
@@ -509,7 +583,7 @@ var _ = Describe("bounceProcesses", func() {
 			var removed bool
 
 			BeforeEach(func() {
-				status, err := adminClient.GetStatus()
+				status, err := adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				processAddresses := make([]fdbv1beta2.ProcessAddress, 0, len(cluster.Status.ProcessGroups))
 				for _, process := range status.Cluster.Processes {
@@ -534,7 +608,7 @@ var _ = Describe("bounceProcesses", func() {
 					processGroup.UpdateCondition(fdbv1beta2.IncorrectCommandLine, processGroup.ProcessGroupID == ignoredProcessGroup.ProcessGroupID, nil, "")
 				}
 
-				status, err := adminClient.GetStatus()
+				status, err := adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				processAddresses := make([]fdbv1beta2.ProcessAddress, 0, len(cluster.Status.ProcessGroups))
 				for _, process := range status.Cluster.Processes {
@@ -0,0 +1,279 @@
+/*
+ * bounce_renewed_certificates.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// tlsCertificateSyncDelay defines how long the operator waits after cert-manager issued a certificate before the
+// processes are restarted, so the kubelet has updated the mounted secret in the meantime.
+const tlsCertificateSyncDelay = 2 * time.Minute
+
+// bounceRenewedCertificates provides a reconciliation step for restarting the processes that use a certificate that
+// was renewed by cert-manager. The processes only read the certificate during startup, so the processes are restarted
+// one fault domain at a time, instead of recreating the Pods.
+type bounceRenewedCertificates struct{}
+
+// reconcile runs the reconciler's work.
+func (bounceRenewedCertificates) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !cluster.CertManagerEnabled() || !pointer.BoolDeref(cluster.Spec.AutomationOptions.KillProcesses, true) {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "bounceRenewedCertificates")
+
+	pending, delay, err := getProcessGroupsWithRenewedCertificate(ctx, r, cluster, time.Now())
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if len(pending) == 0 {
+		if delay > 0 {
+			return &requeue{message: "Waiting for the renewed certificates to be updated in the Pods", delay: delay, delayedRequeue: true}
+		}
+
+		return nil
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus(ctx)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	minimumUptime, addressMap, err := internal.GetMinimumUptimeAndAddressMap(logger, cluster, status, r.getRuntimeSettings().EnableRecoveryState)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if minimumUptime < float64(cluster.GetMinimumUptimeSecondsForBounce()) {
+		return &requeue{
+			message:        "Cluster needs to stabilize before restarting the processes with renewed certificates",
+			delay:          time.Second * time.Duration(cluster.GetMinimumUptimeSecondsForBounce()-int(minimumUptime)),
+			delayedRequeue: true,
+		}
+	}
+
+	faultDomain, processGroups := getNextFaultDomainForRestart(status, pending, addressMap)
+	if len(processGroups) == 0 {
+		return &requeue{message: "Waiting for the processes with renewed certificates to report their addresses", delayedRequeue: true}
+	}
+
+	action := "restarting processes with renewed certificates"
+	if req := checkSettleTime(logger, cluster, status, action); req != nil {
+		return req
+	}
+
+	if req := checkRecoveryFreeze(logger, cluster, status, action); req != nil {
+		return req
+	}
+
+	if req := checkRestoreInProgress(ctx, logger, r, cluster, action); req != nil {
+		return req
+	}
+
+	if req := checkBounceSchedule(logger, cluster, status, action, time.Now()); req != nil {
+		return req
+	}
+
+	if req := checkFaultTolerance(logger, cluster, status, action); req != nil {
+		return req
+	}
+
+	if req := checkActionBudget(logger, r, cluster, action); req != nil {
+		return req
+	}
+
+	// Only whole process groups are restarted, so the hash of every restarted process group can be updated.
+	remaining := getRemainingActionBudget(r, cluster)
+	addresses := make([]fdbv1beta2.ProcessAddress, 0, len(processGroups))
+	restarted := make([]*fdbv1beta2.ProcessGroupStatus, 0, len(processGroups))
+	for _, processGroup := range processGroups {
+		processGroupAddresses := addressMap[processGroup.ProcessGroupID]
+		if len(restarted) > 0 && len(addresses)+len(processGroupAddresses) > remaining {
+			break
+		}
+
+		addresses = append(addresses, processGroupAddresses...)
+		restarted = append(restarted, processGroup)
+	}
+
+	hasLock, err := r.takeLock(cluster, fmt.Sprintf("%s: %v", action, addresses))
+	if !hasLock {
+		return &requeue{curError: err}
+	}
+
+	logger.Info("Restarting processes with renewed certificates", "faultDomain", faultDomain, "addresses", addresses)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "RestartingProcessesForRenewedCertificates", fmt.Sprintf("Restarting processes in fault domain %s to use the renewed certificates: %v", faultDomain, addresses))
+	err = adminClient.KillProcesses(ctx, addresses)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	r.recordAction(cluster, fmt.Sprintf("bounced processes %v", addresses), "certificates were renewed")
+
+	for _, processGroup := range restarted {
+		processGroup.TLSCertificateHash = cluster.Status.TLSCertificateHashes[cluster.GetCertificateName(processGroup.ProcessGroupID)]
+	}
+
+	// The action budget is persisted together with the updated hashes.
+	addActionBudgetUsage(r, cluster, len(addresses))
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	err = recordDestructiveAction(ctx, r, cluster, action)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if len(pending) > len(restarted) || delay > 0 {
+		return &requeue{
+			message:        fmt.Sprintf("Restarted processes in fault domain %s, waiting to restart the remaining processes with renewed certificates", faultDomain),
+			delay:          time.Second * time.Duration(cluster.GetMinimumUptimeSecondsForBounce()),
+			delayedRequeue: true,
+		}
+	}
+
+	return nil
+}
+
+// getProcessGroupsWithRenewedCertificate returns the process groups whose processes were started with a previous
+// certificate and the certificate was issued at least tlsCertificateSyncDelay ago. If a renewed certificate was issued
+// more recently, the time until the processes that use it can be restarted will be returned.
+func getProcessGroupsWithRenewedCertificate(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, now time.Time) ([]*fdbv1beta2.ProcessGroupStatus, time.Duration, error) {
+	var pending []*fdbv1beta2.ProcessGroupStatus
+	var delay time.Duration
+	issueTimes := map[string]time.Time{}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() || processGroup.TLSCertificateHash == "" {
+			continue
+		}
+
+		name := cluster.GetCertificateName(processGroup.ProcessGroupID)
+		hash, ok := cluster.Status.TLSCertificateHashes[name]
+		if !ok || hash == processGroup.TLSCertificateHash {
+			continue
+		}
+
+		issueTime, ok := issueTimes[name]
+		if !ok {
+			var err error
+			issueTime, err = getCertificateIssueTime(ctx, r, cluster, name)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			issueTimes[name] = issueTime
+		}
+
+		if remaining := issueTime.Add(tlsCertificateSyncDelay).Sub(now); remaining > 0 {
+			if delay == 0 || remaining < delay {
+				delay = remaining
+			}
+
+			continue
+		}
+
+		pending = append(pending, processGroup)
+	}
+
+	return pending, delay, nil
+}
+
+// getCertificateIssueTime returns the time when cert-manager issued the current certificate, based on the notBefore
+// field of the Certificate status. If the time is unknown, the zero time will be returned, so the processes are
+// restarted without waiting.
+func getCertificateIssueTime(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, name string) (time.Time, error) {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(internal.CertificateGroupVersionKind)
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: name}, certificate)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, err
+	}
+
+	notBefore, found, err := unstructured.NestedString(certificate.Object, "status", "notBefore")
+	if err != nil || !found {
+		return time.Time{}, nil
+	}
+
+	issueTime, err := time.Parse(time.RFC3339, notBefore)
+	if err != nil {
+		return time.Time{}, nil
+	}
+
+	return issueTime, nil
+}
+
+// getNextFaultDomainForRestart returns the fault domain with the lowest name that has process groups with renewed
+// certificates and those process groups. Process groups without known addresses are skipped.
+func getNextFaultDomainForRestart(status *fdbv1beta2.FoundationDBStatus, pending []*fdbv1beta2.ProcessGroupStatus, addressMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.ProcessAddress) (string, []*fdbv1beta2.ProcessGroupStatus) {
+	faultDomains := make(map[fdbv1beta2.ProcessGroupID]string, len(status.Cluster.Processes))
+	for _, process := range status.Cluster.Processes {
+		faultDomains[fdbv1beta2.ProcessGroupID(process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])] = process.Locality[fdbv1beta2.FDBLocalityZoneIDKey]
+	}
+
+	byFaultDomain := map[string][]*fdbv1beta2.ProcessGroupStatus{}
+	for _, processGroup := range pending {
+		if len(addressMap[processGroup.ProcessGroupID]) == 0 {
+			continue
+		}
+
+		faultDomain := faultDomains[processGroup.ProcessGroupID]
+		byFaultDomain[faultDomain] = append(byFaultDomain[faultDomain], processGroup)
+	}
+
+	if len(byFaultDomain) == 0 {
+		return "", nil
+	}
+
+	names := make([]string, 0, len(byFaultDomain))
+	for faultDomain := range byFaultDomain {
+		names = append(names, faultDomain)
+	}
+	sort.Strings(names)
+
+	return names[0], byFaultDomain[names[0]]
+}
@@ -0,0 +1,125 @@
+/*
+ * bounce_renewed_certificates_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("bounceRenewedCertificates", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+	var certificateName string
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		var err error
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		cluster.Spec.CertManager = &fdbv1beta2.CertManagerSpec{
+			IssuerRef: fdbv1beta2.CertManagerIssuerReference{Name: "fdb-issuer"},
+		}
+		certificateName = cluster.GetCertificateName("")
+		cluster.Status.TLSCertificateHashes = map[string]string{certificateName: "renewed"}
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			processGroup.TLSCertificateHash = "renewed"
+		}
+	})
+
+	JustBeforeEach(func() {
+		requeue = bounceRenewedCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("all processes use the current certificate", func() {
+		It("should not restart any processes", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.KilledAddresses).To(BeEmpty())
+		})
+	})
+
+	When("processes in multiple fault domains use a previous certificate", func() {
+		BeforeEach(func() {
+			for _, processGroupID := range []fdbv1beta2.ProcessGroupID{"storage-1", "storage-2"} {
+				fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID).TLSCertificateHash = "previous"
+			}
+		})
+
+		It("should restart the processes of one fault domain", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.delayedRequeue).To(BeTrue())
+
+			addresses := map[string]fdbv1beta2.None{}
+			for _, address := range fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1").Addresses {
+				addresses[fmt.Sprintf("%s:4501", address)] = fdbv1beta2.None{}
+			}
+			Expect(adminClient.KilledAddresses).To(Equal(addresses))
+		})
+
+		It("should only update the hash of the restarted process group", func() {
+			Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1").TLSCertificateHash).To(Equal("renewed"))
+			Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-2").TLSCertificateHash).To(Equal("previous"))
+		})
+
+		When("the certificate was issued recently", func() {
+			BeforeEach(func() {
+				certificate, err := internal.GetCertificate(cluster, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(unstructured.SetNestedField(certificate.Object, time.Now().Format(time.RFC3339), "status", "notBefore")).To(Succeed())
+				Expect(k8sClient.Create(context.TODO(), certificate)).To(Succeed())
+			})
+
+			It("should wait until the kubelet updated the secret", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.delay).To(BeNumerically(">", 0))
+				Expect(requeue.delay).To(BeNumerically("<=", tlsCertificateSyncDelay))
+				Expect(adminClient.KilledAddresses).To(BeEmpty())
+			})
+		})
+
+		When("killing processes is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.KillProcesses = pointer.Bool(false)
+			})
+
+			It("should not restart any processes", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.KilledAddresses).To(BeEmpty())
+			})
+		})
+	})
+})
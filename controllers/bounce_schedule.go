@@ -0,0 +1,116 @@
+/*
+ * bounce_schedule.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// bounceScheduleRetryDelay defines how long the operator waits before it checks the load of the cluster again.
+const bounceScheduleRetryDelay = 1 * time.Minute
+
+// checkBounceSchedule returns a requeue if the bounce schedule of the cluster defers the action. The action is
+// allowed if the urgent override is active, if the current time is in one of the bounce windows or if the load of
+// the cluster is below the maximum load.
+func checkBounceSchedule(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, action string, now time.Time) *requeue {
+	if !cluster.IsBounceScheduleEnabled() {
+		return nil
+	}
+
+	options := cluster.Spec.AutomationOptions.BounceScheduleOptions
+	if options.UrgentUntil != nil && now.Before(options.UrgentUntil.Time) {
+		logger.Info("Ignoring the bounce schedule for an urgent rollout", "action", action, "urgentUntil", options.UrgentUntil.Time)
+		return nil
+	}
+
+	inWindow, untilNextWindow, err := getBounceWindowState(options.Windows, now)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if inWindow {
+		return nil
+	}
+
+	var operations float64
+	if status != nil {
+		operations = status.Cluster.Workload.Operations.Reads.Hz + status.Cluster.Workload.Operations.Writes.Hz
+	}
+
+	delay := untilNextWindow
+	if options.MaxOperationsPerSecond != nil {
+		if operations < float64(*options.MaxOperationsPerSecond) {
+			return nil
+		}
+
+		if delay == 0 || delay > bounceScheduleRetryDelay {
+			delay = bounceScheduleRetryDelay
+		}
+	}
+
+	logger.Info("Deferring action until the load is low or until the next bounce window", "action", action, "operationsPerSecond", operations, "waitTime", delay)
+	return &requeue{
+		message:        fmt.Sprintf("Deferring %s until the load is low or until the next bounce window", action),
+		delay:          delay,
+		delayedRequeue: true,
+	}
+}
+
+// getBounceWindowState returns true if the current time is in one of the windows. If the current time is not in any
+// window, the time until the start of the next window will be returned.
+func getBounceWindowState(windows []fdbv1beta2.BounceWindow, now time.Time) (bool, time.Duration, error) {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var untilNextWindow time.Duration
+	for _, window := range windows {
+		start, err := time.Parse("15:04", window.Start)
+		if err != nil {
+			return false, 0, fmt.Errorf("invalid start %s of bounce window: %w", window.Start, err)
+		}
+
+		duration := time.Duration(window.DurationMinutes) * time.Minute
+		todayStart := midnight.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+
+		// A window that started yesterday can still be active if it spans midnight.
+		for _, windowStart := range []time.Time{todayStart.AddDate(0, 0, -1), todayStart} {
+			if !now.Before(windowStart) && now.Before(windowStart.Add(duration)) {
+				return true, 0, nil
+			}
+		}
+
+		nextStart := todayStart
+		if !nextStart.After(now) {
+			nextStart = nextStart.AddDate(0, 0, 1)
+		}
+
+		if until := nextStart.Sub(now); untilNextWindow == 0 || until < untilNextWindow {
+			untilNextWindow = until
+		}
+	}
+
+	return false, untilNextWindow, nil
+}
@@ -0,0 +1,181 @@
+/*
+ * bounce_schedule_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("bounce schedule", func() {
+	// now is a Monday at 12:00 UTC.
+	now := time.Date(2023, 6, 5, 12, 0, 0, 0, time.UTC)
+
+	DescribeTable("getting the bounce window state", func(windows []fdbv1beta2.BounceWindow, expectedInWindow bool, expectedUntilNextWindow time.Duration) {
+		inWindow, untilNextWindow, err := getBounceWindowState(windows, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inWindow).To(Equal(expectedInWindow))
+		Expect(untilNextWindow).To(Equal(expectedUntilNextWindow))
+	},
+		Entry("no windows",
+			nil,
+			false,
+			time.Duration(0),
+		),
+		Entry("the current time is in a window",
+			[]fdbv1beta2.BounceWindow{{Start: "11:30", DurationMinutes: 60}},
+			true,
+			time.Duration(0),
+		),
+		Entry("the window ends at the current time",
+			[]fdbv1beta2.BounceWindow{{Start: "11:00", DurationMinutes: 60}},
+			false,
+			23*time.Hour,
+		),
+		Entry("the window starts later today",
+			[]fdbv1beta2.BounceWindow{{Start: "14:15", DurationMinutes: 60}},
+			false,
+			2*time.Hour+15*time.Minute,
+		),
+		Entry("the window spans midnight and started yesterday",
+			[]fdbv1beta2.BounceWindow{{Start: "22:00", DurationMinutes: 900}},
+			true,
+			time.Duration(0),
+		),
+		Entry("multiple windows",
+			[]fdbv1beta2.BounceWindow{{Start: "02:00", DurationMinutes: 120}, {Start: "18:00", DurationMinutes: 60}},
+			false,
+			6*time.Hour,
+		),
+	)
+
+	When("the start of a window is invalid", func() {
+		It("should return an error", func() {
+			_, _, err := getBounceWindowState([]fdbv1beta2.BounceWindow{{Start: "25:00", DurationMinutes: 60}}, now)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("checkBounceSchedule", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var status *fdbv1beta2.FoundationDBStatus
+		var result *requeue
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			status = &fdbv1beta2.FoundationDBStatus{
+				Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+					Workload: fdbv1beta2.FoundationDBStatusWorkload{
+						Operations: fdbv1beta2.FoundationDBStatusWorkloadOperations{
+							Reads:  fdbv1beta2.FoundationDBStatusRate{Hz: 800},
+							Writes: fdbv1beta2.FoundationDBStatusRate{Hz: 400},
+						},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			result = checkBounceSchedule(logr.Discard(), cluster, status, "bouncing processes", now)
+		})
+
+		When("no bounce schedule is defined", func() {
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+
+		When("a maximum load is defined", func() {
+			When("the load is below the maximum", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.BounceScheduleOptions.MaxOperationsPerSecond = pointer.Int(2000)
+				})
+
+				It("should not requeue", func() {
+					Expect(result).To(BeNil())
+				})
+			})
+
+			When("the load is above the maximum", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.BounceScheduleOptions.MaxOperationsPerSecond = pointer.Int(1000)
+				})
+
+				It("should requeue to check the load again", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.message).To(Equal("Deferring bouncing processes until the load is low or until the next bounce window"))
+					Expect(result.delay).To(Equal(bounceScheduleRetryDelay))
+					Expect(result.delayedRequeue).To(BeTrue())
+				})
+
+				When("the current time is in a window", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.BounceScheduleOptions.Windows = []fdbv1beta2.BounceWindow{{Start: "11:00", DurationMinutes: 120}}
+					})
+
+					It("should not requeue", func() {
+						Expect(result).To(BeNil())
+					})
+				})
+
+				When("an urgent rollout is active", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.BounceScheduleOptions.UrgentUntil = &metav1.Time{Time: now.Add(1 * time.Hour)}
+					})
+
+					It("should not requeue", func() {
+						Expect(result).To(BeNil())
+					})
+				})
+
+				When("an urgent rollout has expired", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.BounceScheduleOptions.UrgentUntil = &metav1.Time{Time: now.Add(-1 * time.Hour)}
+					})
+
+					It("should requeue", func() {
+						Expect(result).NotTo(BeNil())
+					})
+				})
+			})
+		})
+
+		When("only windows are defined", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.BounceScheduleOptions.Windows = []fdbv1beta2.BounceWindow{{Start: "20:00", DurationMinutes: 120}}
+			})
+
+			It("should requeue until the next window", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delay).To(Equal(8 * time.Hour))
+				Expect(result.delayedRequeue).To(BeTrue())
+			})
+		})
+	})
+})
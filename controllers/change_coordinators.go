@@ -23,11 +23,13 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/locality"
 	"github.com/go-logr/logr"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 )
@@ -50,7 +52,12 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 	}
 	defer adminClient.Close()
 
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	err = resolveProposedCoordinatorChange(ctx, logger, r, cluster, status.Cluster.ConnectionString)
 	if err != nil {
 		return &requeue{curError: err, delayedRequeue: true}
 	}
@@ -80,12 +87,27 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 		return nil
 	}
 
+	// During a migration from an external cluster the coordinators will only be changed once the external processes
+	// are excluded.
+	if cluster.IsExternalMigrationInProgress() && cluster.Status.MigrationPhase != fdbv1beta2.MigrationPhaseChangingCoordinators {
+		logger.Info("Deferring coordinator change until the external processes are excluded", "phase", cluster.Status.MigrationPhase)
+		return nil
+	}
+
 	if !allAddressesValid {
 		logger.Info("Deferring coordinator change")
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "DeferringCoordinatorChange", "Deferring coordinator change until all processes have consistent address TLS settings")
 		return nil
 	}
 
+	if req := checkSettleTime(logger, cluster, status, "changing coordinators"); req != nil {
+		return req
+	}
+
+	if req := checkRestoreInProgress(ctx, logger, r, cluster, "changing coordinators"); req != nil {
+		return req
+	}
+
 	hasLock, err := r.takeLock(cluster, "changing coordinators")
 	if !hasLock {
 		return &requeue{curError: err, delayedRequeue: true}
@@ -105,19 +127,79 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 	}
 
 	logger.Info("Final coordinators candidates", "coordinators", coordinatorAddresses)
-	connectionString, err := adminClient.ChangeCoordinators(coordinatorAddresses)
+
+	// Persist the proposed change before changing the coordinators, so that an interrupted change can be resolved
+	// in the next reconciliation.
+	coordinatorStrings := make([]string, len(coordinatorAddresses))
+	for index, address := range coordinatorAddresses {
+		coordinatorStrings[index] = address.String()
+	}
+	cluster.Status.CoordinatorChange = &fdbv1beta2.CoordinatorChangeStatus{
+		State:                    fdbv1beta2.CoordinatorChangeStateProposed,
+		PreviousConnectionString: status.Cluster.ConnectionString,
+		Coordinators:             coordinatorStrings,
+		Timestamp:                &metav1.Time{Time: time.Now()},
+	}
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	err = recordWorkJournal(ctx, cluster, adminClient)
 	if err != nil {
 		return &requeue{curError: err, delayedRequeue: true}
 	}
+
+	connectionString, err := adminClient.ChangeCoordinators(ctx, coordinatorAddresses)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	r.recordAction(cluster, fmt.Sprintf("changed coordinators to %v", coordinatorAddresses), "current coordinators are not valid")
 	cluster.Status.ConnectionString = connectionString
+	cluster.Status.CoordinatorChange.State = fdbv1beta2.CoordinatorChangeStateCommitted
+	cluster.Status.CoordinatorChange.PendingConnectionString = connectionString
+	cluster.Status.CoordinatorChange.Timestamp = &metav1.Time{Time: time.Now()}
 	err = r.updateOrApply(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err, delayedRequeue: true}
 	}
 
+	err = recordWorkJournal(ctx, cluster, adminClient)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	err = recordDestructiveAction(ctx, r, cluster, "changing coordinators")
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
 	return nil
 }
 
+// resolveProposedCoordinatorChange resolves a coordinator change that was proposed but not confirmed, e.g. because
+// the operator was interrupted during the change. If the connection string reported by the database differs from the
+// previous connection string the change was committed, otherwise the proposal is discarded.
+func resolveProposedCoordinatorChange(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, connectionString string) error {
+	coordinatorChange := cluster.Status.CoordinatorChange
+	if coordinatorChange == nil || coordinatorChange.State != fdbv1beta2.CoordinatorChangeStateProposed {
+		return nil
+	}
+
+	if connectionString == coordinatorChange.PreviousConnectionString {
+		logger.Info("Discarding coordinator change that was not committed", "coordinators", coordinatorChange.Coordinators)
+		cluster.Status.CoordinatorChange = nil
+	} else {
+		logger.Info("Resolving interrupted coordinator change as committed", "connectionString", connectionString)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "CoordinatorChangeCommitted", fmt.Sprintf("Interrupted coordinator change was committed with connection string %s", connectionString))
+		coordinatorChange.State = fdbv1beta2.CoordinatorChangeStateCommitted
+		coordinatorChange.PendingConnectionString = connectionString
+		coordinatorChange.Timestamp = &metav1.Time{Time: time.Now()}
+	}
+
+	return r.updateOrApply(ctx, cluster)
+}
+
 // TODO move them into separate package?
 // selectCandidates is a helper for Reconcile that picks non-excluded, not-being-removed class-matching process groups.
 func selectCandidates(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus) ([]locality.Info, error) {
@@ -135,6 +217,11 @@ func selectCandidates(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta
 			continue
 		}
 
+		// Processes in a fault domain that will be decommissioned will be removed soon.
+		if cluster.IsFaultDomainDecommissioned(process.Locality) {
+			continue
+		}
+
 		currentLocality, err := locality.InfoForProcess(process, cluster.Spec.MainContainer.EnableTLS)
 		if err != nil {
 			return candidates, err
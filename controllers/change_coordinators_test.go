@@ -73,7 +73,7 @@ var _ = Describe("Change coordinators", func() {
 
 			JustBeforeEach(func() {
 				var err error
-				status, err = adminClient.GetStatus()
+				status, err = adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 
 				candidates, err = selectCoordinators(logr.Discard(), cluster, status)
@@ -114,6 +114,29 @@ var _ = Describe("Change coordinators", func() {
 				})
 			})
 
+			When("when one storage process is in a decommissioned fault domain", func() {
+				BeforeEach(func() {
+					adminClient.MockLocalityInfo("storage-2", map[string]string{
+						fdbv1beta2.FDBLocalityZoneIDKey: "zone-a",
+					})
+					cluster.Spec.FaultDomainsToDecommission = []fdbv1beta2.FaultDomainToDecommission{
+						{
+							Key:   fdbv1beta2.FDBLocalityZoneIDKey,
+							Value: "zone-a",
+						},
+					}
+				})
+
+				It("should only select storage processes and exclude the process in the decommissioned fault domain", func() {
+					Expect(len(candidates)).To(BeNumerically("==", cluster.DesiredCoordinatorCount()))
+
+					for _, candidate := range candidates {
+						Expect(candidate.ID).NotTo(Equal("storage-2"))
+						Expect(strings.HasPrefix(candidate.ID, "storage")).To(BeTrue())
+					}
+				})
+			})
+
 			When("when one storage process is excluded", func() {
 				BeforeEach(func() {
 					address := cluster.Status.ProcessGroups[firstStorageIndex+1].Addresses[0]
@@ -228,7 +251,7 @@ var _ = Describe("Change coordinators", func() {
 				cluster.Spec.ProcessGroupsToRemove = removals
 
 				var err error
-				status, err = adminClient.GetStatus()
+				status, err = adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 
 				// generate status for 2 dcs and 1 sate
@@ -638,6 +661,7 @@ var _ = Describe("Change coordinators", func() {
 
 		When("enabling DNS in the cluster file", func() {
 			BeforeEach(func() {
+				cluster.Status.RunningVersion = fdbv1beta2.Versions.SupportsDNSInClusterFile.String()
 				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
 			})
 
@@ -658,7 +682,7 @@ var _ = Describe("Change coordinators", func() {
 
 					for _, pod := range pods.Items {
 						container := pod.Spec.Containers[1]
-						container.Env = append(container.Env, corev1.EnvVar{Name: "FDB_DNS_NAME", Value: internal.GetPodDNSName(cluster, pod.Name)})
+						container.Env = append(container.Env, corev1.EnvVar{Name: "FDB_DNS_NAME", Value: internal.GetPodDNSName(cluster, fdbv1beta2.ProcessClassStorage, pod.Name)})
 						pod.Spec.Containers[1] = container
 						Expect(k8sClient.Update(context.TODO(), &pod)).NotTo(HaveOccurred())
 					}
@@ -682,7 +706,7 @@ var _ = Describe("Change coordinators", func() {
 				adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 
-				status, err := adminClient.GetStatus()
+				status, err := adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 
 				for _, process := range status.Cluster.Processes {
@@ -702,6 +726,76 @@ var _ = Describe("Change coordinators", func() {
 				Expect(cluster.Status.ConnectionString).NotTo(Equal(originalConnectionString))
 				Expect(cluster.Status.ConnectionString).NotTo(ContainSubstring(badCoordinator.Address.IPAddress.String()))
 			})
+
+			It("should record the committed coordinator change", func() {
+				coordinatorChange := cluster.Status.CoordinatorChange
+				Expect(coordinatorChange).NotTo(BeNil())
+				Expect(coordinatorChange.State).To(Equal(fdbv1beta2.CoordinatorChangeStateCommitted))
+				Expect(coordinatorChange.PreviousConnectionString).To(Equal(originalConnectionString))
+				Expect(coordinatorChange.PendingConnectionString).To(Equal(cluster.Status.ConnectionString))
+				Expect(coordinatorChange.Coordinators).To(HaveLen(cluster.DesiredCoordinatorCount()))
+				Expect(coordinatorChange.Timestamp).NotTo(BeNil())
+			})
+
+			When("the work journal is enabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.UseWorkJournal = pointer.Bool(true)
+				})
+
+				It("should store the committed coordinator change in the work journal", func() {
+					journal, err := adminClient.GetWorkJournal(context.TODO())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(journal).NotTo(BeNil())
+					Expect(journal.CoordinatorChange).NotTo(BeNil())
+					Expect(journal.CoordinatorChange.State).To(Equal(fdbv1beta2.CoordinatorChangeStateCommitted))
+					Expect(journal.CoordinatorChange.PendingConnectionString).To(Equal(cluster.Status.ConnectionString))
+				})
+			})
+
+			When("the config map is updated", func() {
+				JustBeforeEach(func() {
+					Expect(updateConfigMap{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				})
+
+				It("should mark the coordinator change as distributed", func() {
+					Expect(cluster.Status.CoordinatorChange).NotTo(BeNil())
+					Expect(cluster.Status.CoordinatorChange.State).To(Equal(fdbv1beta2.CoordinatorChangeStateDistributed))
+				})
+			})
+		})
+
+		When("a proposed coordinator change was interrupted", func() {
+			BeforeEach(func() {
+				cluster.Status.CoordinatorChange = &fdbv1beta2.CoordinatorChangeStatus{
+					State:        fdbv1beta2.CoordinatorChangeStateProposed,
+					Coordinators: []string{"1.1.1.1:4501"},
+				}
+			})
+
+			When("the database still reports the previous connection string", func() {
+				BeforeEach(func() {
+					cluster.Status.CoordinatorChange.PreviousConnectionString = originalConnectionString
+				})
+
+				It("should discard the proposed change", func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.CoordinatorChange).To(BeNil())
+					Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+				})
+			})
+
+			When("the database reports a new connection string", func() {
+				BeforeEach(func() {
+					cluster.Status.CoordinatorChange.PreviousConnectionString = "operator_test:previous@1.1.1.1:4501"
+				})
+
+				It("should mark the change as committed", func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.CoordinatorChange).NotTo(BeNil())
+					Expect(cluster.Status.CoordinatorChange.State).To(Equal(fdbv1beta2.CoordinatorChangeStateCommitted))
+					Expect(cluster.Status.CoordinatorChange.PendingConnectionString).To(Equal(originalConnectionString))
+				})
+			})
 		})
 	})
 })
@@ -0,0 +1,199 @@
+/*
+ * check_blob_store.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// blobStoreCheckRequeueDelay defines how long the operator waits before checking the blobstore again if the check
+// failed.
+const blobStoreCheckRequeueDelay = time.Minute
+
+// checkBackupBlobStore provides a reconciliation step for validating the blobstore credentials and the connectivity to
+// the blobstore before the backup agents are started.
+type checkBackupBlobStore struct{}
+
+// reconcile runs the reconciler's work.
+func (c checkBackupBlobStore) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	if backup.UsesVolumeSnapshots() || !backup.ShouldRun() || backup.Spec.BlobStoreConfiguration == nil {
+		return nil
+	}
+
+	reason, checkErr := checkBackupBlobStoreAccess(ctx, r, backup)
+	changed := setBlobStoreReadyCondition(&backup.Status.Conditions, backup.Generation, reason, checkErr)
+	if changed {
+		err := r.updateOrApply(ctx, backup)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if checkErr == nil {
+		return nil
+	}
+
+	r.Recorder.Event(backup, corev1.EventTypeWarning, reason, internal.RedactString(checkErr.Error()))
+
+	// A running backup should not be blocked by a temporary issue with the blobstore.
+	if backup.Status.BackupDetails != nil && backup.Status.BackupDetails.Running {
+		return nil
+	}
+
+	return &requeue{message: checkErr.Error(), delay: blobStoreCheckRequeueDelay}
+}
+
+// checkBackupBlobStoreAccess validates the credentials that are mounted into the backup agents and checks that the
+// blobstore is reachable. If the check fails, the reason for the condition will be returned alongside the error.
+func checkBackupBlobStoreAccess(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) (string, error) {
+	var podSpec *corev1.PodSpec
+	if backup.Spec.PodTemplateSpec != nil {
+		podSpec = &backup.Spec.PodTemplateSpec.Spec
+	}
+
+	secretKey := internal.GetBlobCredentialsSecret(podSpec)
+	if secretKey != nil {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: secretKey.Name}, secret)
+		if err != nil {
+			return fdbv1beta2.BlobStoreCredentialsInvalidReason, fmt.Errorf("could not read credentials secret %s: %w", secretKey.Name, err)
+		}
+
+		data, ok := secret.Data[secretKey.Key]
+		if !ok {
+			return fdbv1beta2.BlobStoreCredentialsInvalidReason, fmt.Errorf("credentials secret %s has no key %s", secretKey.Name, secretKey.Key)
+		}
+
+		err = internal.ValidateBlobCredentials(data, backup.Spec.BlobStoreConfiguration.AccountName)
+		if err != nil {
+			return fdbv1beta2.BlobStoreCredentialsInvalidReason, err
+		}
+	}
+
+	if r.InSimulation {
+		return fdbv1beta2.BlobStoreValidatedReason, nil
+	}
+
+	err := internal.ProbeBlobStore(ctx, backup.Spec.BlobStoreConfiguration, backup.Bucket())
+	if err != nil {
+		return fdbv1beta2.BlobStoreUnreachableReason, err
+	}
+
+	return fdbv1beta2.BlobStoreValidatedReason, nil
+}
+
+// checkRestoreBlobStore provides a reconciliation step for validating the blobstore credentials of the operator and the
+// connectivity to the blobstore before the restore is started.
+type checkRestoreBlobStore struct{}
+
+// reconcile runs the reconciler's work.
+func (c checkRestoreBlobStore) reconcile(ctx context.Context, r *FoundationDBRestoreReconciler, restore *fdbv1beta2.FoundationDBRestore) *requeue {
+	if restore.Spec.BlobStoreConfiguration == nil || restore.Status.Phase == fdbv1beta2.RestorePhaseCompleted || restore.Status.Phase == fdbv1beta2.RestorePhaseFailed {
+		return nil
+	}
+
+	reason, checkErr := checkRestoreBlobStoreAccess(ctx, r, restore)
+	changed := setBlobStoreReadyCondition(&restore.Status.Conditions, restore.Generation, reason, checkErr)
+	if changed {
+		err := r.updateOrApply(ctx, restore)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if checkErr == nil {
+		return nil
+	}
+
+	r.Recorder.Event(restore, corev1.EventTypeWarning, reason, internal.RedactString(checkErr.Error()))
+
+	if restore.Status.Running {
+		return nil
+	}
+
+	return &requeue{message: checkErr.Error(), delay: blobStoreCheckRequeueDelay}
+}
+
+// checkRestoreBlobStoreAccess validates the credentials file of the operator, which is used by fdbrestore, and checks
+// that the blobstore is reachable. If the check fails, the reason for the condition will be returned alongside the error.
+func checkRestoreBlobStoreAccess(ctx context.Context, r *FoundationDBRestoreReconciler, restore *fdbv1beta2.FoundationDBRestore) (string, error) {
+	credentialsFile := os.Getenv(internal.BlobCredentialsEnvironmentVariable)
+	if credentialsFile != "" {
+		data, err := os.ReadFile(credentialsFile)
+		if err != nil {
+			return fdbv1beta2.BlobStoreCredentialsInvalidReason, fmt.Errorf("could not read credentials file %s: %w", credentialsFile, err)
+		}
+
+		err = internal.ValidateBlobCredentials(data, restore.Spec.BlobStoreConfiguration.AccountName)
+		if err != nil {
+			return fdbv1beta2.BlobStoreCredentialsInvalidReason, err
+		}
+	}
+
+	if r.InSimulation {
+		return fdbv1beta2.BlobStoreValidatedReason, nil
+	}
+
+	err := internal.ProbeBlobStore(ctx, restore.Spec.BlobStoreConfiguration, restore.Spec.BlobStoreConfiguration.BucketName())
+	if err != nil {
+		return fdbv1beta2.BlobStoreUnreachableReason, err
+	}
+
+	return fdbv1beta2.BlobStoreValidatedReason, nil
+}
+
+// setBlobStoreReadyCondition updates the BlobStoreReady condition based on the result of the blobstore check and
+// returns true if the condition was changed.
+func setBlobStoreReadyCondition(conditions *[]metav1.Condition, generation int64, reason string, checkErr error) bool {
+	condition := metav1.Condition{
+		Type:               fdbv1beta2.BlobStoreReadyCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            "Blobstore credentials are valid and the blobstore is reachable",
+	}
+
+	if checkErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = internal.RedactString(checkErr.Error())
+	}
+
+	existing := meta.FindStatusCondition(*conditions, condition.Type)
+	if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason &&
+		existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration {
+		return false
+	}
+
+	meta.SetStatusCondition(conditions, condition)
+
+	return true
+}
@@ -0,0 +1,187 @@
+/*
+ * check_certificate_expiry.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// checkCertificateExpiry provides a reconciliation step for tracking the expiration of the certificates that are
+// mounted into the fdbserver processes. If the rotation of the certificates is enabled, the hash of the certificate
+// that is used by the processes is part of the Pod spec and is only updated to the renewed certificate shortly before
+// the current certificate expires.
+type checkCertificateExpiry struct{}
+
+// reconcile runs the reconciler's work.
+func (checkCertificateExpiry) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "checkCertificateExpiry")
+
+	// The certificate only depends on the process class, unless cert-manager issues a certificate for every
+	// process group.
+	perProcessGroup := cluster.CertManagerEnabled() && cluster.GetCertificateScope() == fdbv1beta2.CertificateScopeProcessGroup
+	checkedProcessClasses := map[fdbv1beta2.ProcessClass]bool{}
+	sources := map[internal.TLSCertificateSource]fdbv1beta2.None{}
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !perProcessGroup {
+			if checkedProcessClasses[processGroup.ProcessClass] {
+				continue
+			}
+
+			checkedProcessClasses[processGroup.ProcessClass] = true
+		}
+
+		_, idNum, err := podmanager.ParseProcessGroupID(processGroup.ProcessGroupID)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		podSpec, err := internal.GetPodSpec(cluster, processGroup.ProcessClass, idNum)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		source, ok := internal.GetTLSCertificateSource(podSpec)
+		if !ok {
+			continue
+		}
+
+		sources[source] = fdbv1beta2.None{}
+	}
+
+	rotationWindow := cluster.GetTLSCertificateRotationWindow()
+	rotate := cluster.TLSCertificateRotationEnabled() && !cluster.CertManagerEnabled()
+	certificates := make([]fdbv1beta2.TLSCertificateStatus, 0, len(sources))
+	for source := range sources {
+		var previous *fdbv1beta2.TLSCertificateStatus
+		for idx, certificate := range cluster.Status.TLSCertificates {
+			if certificate.SecretName == source.SecretName && certificate.Key == source.Key {
+				previous = &cluster.Status.TLSCertificates[idx]
+				break
+			}
+		}
+
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: source.SecretName}, secret)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return &requeue{curError: err}
+			}
+
+			// The secret could be created after the Pods, e.g. by cert-manager, so a missing secret is no error.
+			logger.Info("Secret with the certificate is missing", "secret", source.SecretName)
+			if previous != nil {
+				certificates = append(certificates, *previous)
+			}
+
+			continue
+		}
+
+		data, ok := secret.Data[source.Key]
+		if !ok {
+			return &requeue{curError: fmt.Errorf("secret %s has no key %s", source.SecretName, source.Key), delayedRequeue: true}
+		}
+
+		certificate, err := internal.ParseTLSCertificate(data)
+		if err != nil {
+			return &requeue{curError: fmt.Errorf("could not parse the certificate in secret %s with key %s: %w", source.SecretName, source.Key, err), delayedRequeue: true}
+		}
+
+		current := fdbv1beta2.TLSCertificateStatus{
+			SecretName: source.SecretName,
+			Key:        source.Key,
+			Hash:       internal.GetTLSCertificateDataHash(data),
+			NotAfter:   &metav1.Time{Time: certificate.NotAfter},
+		}
+
+		loaded := getLoadedCertificate(logger, r, cluster, previous, current, rotate, rotationWindow)
+		loaded.Expiring = loaded.NotAfter != nil && time.Until(loaded.NotAfter.Time) < rotationWindow
+
+		// The event is only emitted once the certificate that is used by the processes starts to expire and not in
+		// every reconciliation.
+		if loaded.Expiring && (previous == nil || !previous.Expiring || previous.Hash != loaded.Hash) {
+			logger.Info("Certificate expires soon", "secret", source.SecretName, "key", source.Key, "notAfter", loaded.NotAfter.Time)
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "TLSCertificateExpiring",
+				fmt.Sprintf("Certificate in secret %s with key %s expires at %s", source.SecretName, source.Key, loaded.NotAfter.Format(time.RFC3339)))
+		}
+
+		certificates = append(certificates, loaded)
+	}
+
+	sort.Slice(certificates, func(i, j int) bool {
+		if certificates[i].SecretName != certificates[j].SecretName {
+			return certificates[i].SecretName < certificates[j].SecretName
+		}
+
+		return certificates[i].Key < certificates[j].Key
+	})
+
+	if len(certificates) == 0 {
+		certificates = nil
+	}
+
+	if equality.Semantic.DeepEqual(cluster.Status.TLSCertificates, certificates) {
+		return nil
+	}
+
+	cluster.Status.TLSCertificates = certificates
+	err := r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// getLoadedCertificate returns the certificate that the processes should use. If the rotation of the certificates is
+// enabled, the processes keep the previous certificate until it expires within the rotation window, so the renewed
+// certificate is rolled out once and not for every renewal of the certificate. Otherwise, the processes use the
+// current certificate of the secret.
+func getLoadedCertificate(logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, previous *fdbv1beta2.TLSCertificateStatus, current fdbv1beta2.TLSCertificateStatus, rotate bool, rotationWindow time.Duration) fdbv1beta2.TLSCertificateStatus {
+	if !rotate || previous == nil || previous.Hash == current.Hash || previous.NotAfter == nil {
+		return current
+	}
+
+	rotationTime := previous.NotAfter.Add(-rotationWindow)
+	if time.Now().Before(rotationTime) {
+		logger.V(1).Info("Renewed certificate will be rolled out before the current certificate expires", "secret", current.SecretName, "key", current.Key, "rotationTime", rotationTime)
+		return *previous
+	}
+
+	logger.Info("Rotating certificate", "secret", current.SecretName, "key", current.Key, "previousNotAfter", previous.NotAfter.Time, "notAfter", current.NotAfter.Time)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "RotatingTLSCertificate",
+		fmt.Sprintf("Certificate in secret %s with key %s expires at %s, the Pods will be updated to use the renewed certificate", current.SecretName, current.Key, previous.NotAfter.Format(time.RFC3339)))
+
+	return current
+}
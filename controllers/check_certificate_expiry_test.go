@@ -0,0 +1,263 @@
+/*
+ * check_certificate_expiry_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("check_certificate_expiry", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var secret *corev1.Secret
+	var requeue *requeue
+	var notAfter time.Time
+
+	getEventReasons := func() []string {
+		events := &corev1.EventList{}
+		Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+		var reasons []string
+		for _, event := range events.Items {
+			if event.InvolvedObject.UID == cluster.ObjectMeta.UID {
+				reasons = append(reasons, event.Reason)
+			}
+		}
+
+		return reasons
+	}
+
+	// renewCertificate updates the certificate in the secret to a certificate that expires at the provided time.
+	renewCertificate := func(expiry time.Time) {
+		data, err := internal.CreateTestCertificate(expiry)
+		Expect(err).NotTo(HaveOccurred())
+		secret.Data["tls.crt"] = data
+		Expect(k8sClient.Update(context.TODO(), secret)).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+		notAfter = time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	})
+
+	JustBeforeEach(func() {
+		requeue = checkCertificateExpiry{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the processes use no certificate", func() {
+		It("should not track any certificates", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.TLSCertificates).To(BeEmpty())
+		})
+	})
+
+	When("the processes use a certificate from a secret", func() {
+		BeforeEach(func() {
+			settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			settings.PodTemplate.Spec.Volumes = append(settings.PodTemplate.Spec.Volumes, corev1.Volume{
+				Name:         "fdb-certs",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "fdb-certs"}},
+			})
+			for idx, container := range settings.PodTemplate.Spec.Containers {
+				if container.Name != fdbv1beta2.MainContainerName {
+					continue
+				}
+
+				settings.PodTemplate.Spec.Containers[idx].Env = append(container.Env, corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: "/tmp/fdb-certs/tls.crt"})
+				settings.PodTemplate.Spec.Containers[idx].VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "fdb-certs", MountPath: "/tmp/fdb-certs"})
+			}
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+		})
+
+		When("the secret is missing", func() {
+			It("should not track any certificates", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.TLSCertificates).To(BeEmpty())
+			})
+		})
+
+		When("the secret has no valid certificate", func() {
+			BeforeEach(func() {
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "fdb-certs", Namespace: cluster.Namespace},
+					Data:       map[string][]byte{"tls.crt": []byte("certificate")},
+				}
+				Expect(k8sClient.Create(context.TODO(), secret)).NotTo(HaveOccurred())
+			})
+
+			It("should return an error", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.curError).To(HaveOccurred())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+			})
+		})
+
+		When("the secret contains a certificate", func() {
+			BeforeEach(func() {
+				data, err := internal.CreateTestCertificate(notAfter)
+				Expect(err).NotTo(HaveOccurred())
+
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "fdb-certs", Namespace: cluster.Namespace},
+					Data:       map[string][]byte{"tls.crt": data},
+				}
+				Expect(k8sClient.Create(context.TODO(), secret)).NotTo(HaveOccurred())
+			})
+
+			It("should track the expiration of the certificate", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.TLSCertificates).To(HaveLen(1))
+				certificate := cluster.Status.TLSCertificates[0]
+				Expect(certificate.SecretName).To(Equal("fdb-certs"))
+				Expect(certificate.Key).To(Equal("tls.crt"))
+				Expect(certificate.Hash).To(Equal(internal.GetTLSCertificateDataHash(secret.Data["tls.crt"])))
+				Expect(certificate.NotAfter.Time.Equal(notAfter)).To(BeTrue())
+				Expect(getEventReasons()).NotTo(ContainElement("TLSCertificateExpiring"))
+			})
+
+			When("the certificate expires within the rotation window", func() {
+				BeforeEach(func() {
+					notAfter = time.Now().Add(24 * time.Hour).Truncate(time.Second)
+					renewCertificate(notAfter)
+				})
+
+				It("should emit a warning event", func() {
+					Expect(requeue).To(BeNil())
+					Expect(getEventReasons()).To(ContainElement("TLSCertificateExpiring"))
+					Expect(cluster.Status.TLSCertificates).To(HaveLen(1))
+					Expect(cluster.Status.TLSCertificates[0].Expiring).To(BeTrue())
+				})
+
+				When("the certificate is checked again", func() {
+					JustBeforeEach(func() {
+						Expect(requeue).To(BeNil())
+						requeue = checkCertificateExpiry{}.reconcile(context.TODO(), clusterReconciler, cluster)
+					})
+
+					It("should emit the warning event only once", func() {
+						Expect(requeue).To(BeNil())
+						Expect(getEventReasons()).To(ContainElements("TLSCertificateExpiring"))
+
+						count := 0
+						for _, reason := range getEventReasons() {
+							if reason == "TLSCertificateExpiring" {
+								count++
+							}
+						}
+						Expect(count).To(Equal(1))
+					})
+				})
+			})
+
+			When("the certificate is renewed", func() {
+				var previousHash string
+
+				JustBeforeEach(func() {
+					Expect(requeue).To(BeNil())
+					previousHash = cluster.Status.TLSCertificates[0].Hash
+
+					renewCertificate(notAfter.Add(60 * 24 * time.Hour))
+					requeue = checkCertificateExpiry{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should track the renewed certificate", func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.TLSCertificates).To(HaveLen(1))
+					Expect(cluster.Status.TLSCertificates[0].Hash).NotTo(Equal(previousHash))
+				})
+
+				When("the rotation is enabled", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.TLSCertificateRotationOptions.Enabled = pointer.Bool(true)
+					})
+
+					It("should keep the previous certificate until it expires within the rotation window", func() {
+						Expect(requeue).To(BeNil())
+						Expect(cluster.Status.TLSCertificates).To(HaveLen(1))
+						Expect(cluster.Status.TLSCertificates[0].Hash).To(Equal(previousHash))
+						Expect(cluster.Status.TLSCertificates[0].NotAfter.Time.Equal(notAfter)).To(BeTrue())
+					})
+
+					It("should keep the hash of the previous certificate in the Pod spec", func() {
+						spec, err := internal.GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+							Name:  internal.TLSCertificateRotationHashVariable,
+							Value: previousHash,
+						}))
+					})
+
+					When("the previous certificate expires within the rotation window", func() {
+						BeforeEach(func() {
+							cluster.Spec.AutomationOptions.TLSCertificateRotationOptions.RotationWindowSeconds = pointer.Int(int((31 * 24 * time.Hour).Seconds()))
+						})
+
+						It("should rotate the certificate", func() {
+							Expect(requeue).To(BeNil())
+							Expect(cluster.Status.TLSCertificates).To(HaveLen(1))
+							Expect(cluster.Status.TLSCertificates[0].Hash).NotTo(Equal(previousHash))
+							Expect(getEventReasons()).To(ContainElement("RotatingTLSCertificate"))
+
+							spec, err := internal.GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+								Name:  internal.TLSCertificateRotationHashVariable,
+								Value: cluster.Status.TLSCertificates[0].Hash,
+							}))
+						})
+					})
+				})
+			})
+
+			When("the processes no longer use the certificate", func() {
+				JustBeforeEach(func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.TLSCertificates).To(HaveLen(1))
+
+					settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+					for idx, container := range settings.PodTemplate.Spec.Containers {
+						if container.Name == fdbv1beta2.MainContainerName {
+							settings.PodTemplate.Spec.Containers[idx].Env = nil
+						}
+					}
+					cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+					requeue = checkCertificateExpiry{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should remove the certificate from the status", func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.TLSCertificates).To(BeEmpty())
+				})
+			})
+		})
+	})
+})
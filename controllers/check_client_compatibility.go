@@ -27,16 +27,20 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 )
 
+// maxListedIncompatibleClients defines how many incompatible clients will be listed in the cluster status.
+const maxListedIncompatibleClients = 100
+
 // checkClientCompatibility confirms that all clients are compatible with the
 // version of FoundationDB configured on the cluster.
 type checkClientCompatibility struct{}
 
 // reconcile runs the reconciler's work.
-func (c checkClientCompatibility) reconcile(_ context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+func (c checkClientCompatibility) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "checkClientCompatibility")
 	if !cluster.Status.Configured && !cluster.IsBeingUpgraded() {
 		return nil
@@ -52,15 +56,16 @@ func (c checkClientCompatibility) reconcile(_ context.Context, r *FoundationDBCl
 		return &requeue{curError: err}
 	}
 
-	if !version.IsAtLeast(runningVersion) && !version.IsProtocolCompatible(runningVersion) {
+	if cluster.IsBeingDowngraded() && !version.IsProtocolCompatible(runningVersion) {
 		return &requeue{message: fmt.Sprintf("cluster downgrade operation is only supported for protocol compatible versions, running version %s and desired version %s are not compatible", runningVersion, version)}
 	}
 
-	if version.IsProtocolCompatible(runningVersion) {
-		return nil
-	}
+	if version.IsProtocolCompatible(runningVersion) || cluster.Spec.IgnoreUpgradabilityChecks {
+		_, err = updateIncompatibleClients(ctx, r, cluster, nil)
+		if err != nil {
+			return &requeue{curError: err}
+		}
 
-	if cluster.Spec.IgnoreUpgradabilityChecks {
 		return nil
 	}
 
@@ -69,12 +74,12 @@ func (c checkClientCompatibility) reconcile(_ context.Context, r *FoundationDBCl
 		return &requeue{curError: err}
 	}
 	defer adminClient.Close()
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 
-	protocolVersion, err := adminClient.GetProtocolVersion(cluster.Spec.Version)
+	protocolVersion, err := adminClient.GetProtocolVersion(ctx, cluster.Spec.Version)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -85,17 +90,59 @@ func (c checkClientCompatibility) reconcile(_ context.Context, r *FoundationDBCl
 	}
 	unsupportedClients := getUnsupportedClients(status.Cluster.Clients.SupportedVersions, protocolVersion, ignoredLogGroups)
 
+	var incompatibleClients *fdbv1beta2.IncompatibleClientsStatus
 	if len(unsupportedClients) > 0 {
-		message := fmt.Sprintf(
-			"%d clients do not support version %s: %s", len(unsupportedClients),
-			cluster.Spec.Version, strings.Join(unsupportedClients, ", "),
-		)
-		r.Recorder.Event(cluster, corev1.EventTypeNormal, "UnsupportedClient", message)
-		logger.Info("Deferring reconciliation due to unsupported clients", "message", message)
-		return &requeue{message: message, delay: 1 * time.Minute}
+		listedClients := unsupportedClients
+		if len(listedClients) > maxListedIncompatibleClients {
+			listedClients = listedClients[:maxListedIncompatibleClients]
+		}
+
+		incompatibleClients = &fdbv1beta2.IncompatibleClientsStatus{
+			Version: cluster.Spec.Version,
+			Count:   len(unsupportedClients),
+			Clients: listedClients,
+		}
+	}
+
+	changed, err := updateIncompatibleClients(ctx, r, cluster, incompatibleClients)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if len(unsupportedClients) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf(
+		"%d clients do not support version %s: %s", len(unsupportedClients),
+		cluster.Spec.Version, strings.Join(unsupportedClients, ", "),
+	)
+
+	if len(unsupportedClients) <= cluster.GetMaxIncompatibleClientsForUpgrade() {
+		// The warning is only emitted when the incompatible clients changed, otherwise it would be repeated in every
+		// reconciliation until the upgrade is done.
+		if changed {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "UpgradingWithUnsupportedClients", message)
+		}
+		logger.Info("Continuing upgrade with unsupported clients below the threshold", "message", message, "threshold", cluster.GetMaxIncompatibleClientsForUpgrade())
+		return nil
+	}
+
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "UnsupportedClient", message)
+	logger.Info("Deferring reconciliation due to unsupported clients", "message", message)
+	return &requeue{message: message, delay: 1 * time.Minute}
+}
+
+// updateIncompatibleClients updates the incompatible clients in the cluster status if they changed. The returned bool
+// is true if the incompatible clients changed.
+func updateIncompatibleClients(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, incompatibleClients *fdbv1beta2.IncompatibleClientsStatus) (bool, error) {
+	if equality.Semantic.DeepEqual(cluster.Status.IncompatibleClients, incompatibleClients) {
+		return false, nil
 	}
 
-	return nil
+	cluster.Status.IncompatibleClients = incompatibleClients
+
+	return true, r.updateOrApply(ctx, cluster)
 }
 
 func getUnsupportedClients(supportedVersions []fdbv1beta2.FoundationDBStatusSupportedVersion, protocolVersion string, ignoredLogGroups map[fdbv1beta2.LogGroup]fdbv1beta2.None) []string {
@@ -21,6 +21,14 @@
 package controllers
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -244,4 +252,66 @@ var _ = Describe("check client compatibility", func() {
 				}),
 		)
 	})
+
+	When("upgrading with unsupported clients below the threshold", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var adminClient *mock.AdminClient
+
+		getWarnings := func() []corev1.Event {
+			events := &corev1.EventList{}
+			Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+			var warnings []corev1.Event
+			for _, event := range events.Items {
+				if event.InvolvedObject.UID == cluster.ObjectMeta.UID && event.Reason == "UpgradingWithUnsupportedClients" {
+					warnings = append(warnings, event)
+				}
+			}
+
+			return warnings
+		}
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+			var err error
+			adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			adminClient.MockClientVersion(fdbv1beta2.Versions.NextMajorVersion.String(), []string{"127.0.0.2:3687"})
+			adminClient.MockClientVersion(fdbv1beta2.Versions.Default.String(), []string{"127.0.0.3:85891"})
+
+			cluster.Spec.Version = fdbv1beta2.Versions.NextMajorVersion.String()
+			cluster.Spec.AutomationOptions.MaxIncompatibleClientsForUpgrade = pointer.Int(1)
+
+			Expect(checkClientCompatibility{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+		})
+
+		It("should warn about the unsupported clients", func() {
+			warnings := getWarnings()
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0].Message).To(Equal(fmt.Sprintf("1 clients do not support version %s: 127.0.0.3:85891 (%s)", fdbv1beta2.Versions.NextMajorVersion, cluster.Name)))
+		})
+
+		When("the unsupported clients didn't change", func() {
+			BeforeEach(func() {
+				Expect(checkClientCompatibility{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			})
+
+			It("should not repeat the warning", func() {
+				Expect(getWarnings()).To(HaveLen(1))
+			})
+		})
+
+		When("the unsupported clients changed", func() {
+			BeforeEach(func() {
+				adminClient.MockClientVersion(fdbv1beta2.Versions.Default.String(), []string{"127.0.0.4:85891"})
+				Expect(checkClientCompatibility{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			})
+
+			It("should warn about the changed unsupported clients", func() {
+				Expect(getWarnings()).To(HaveLen(2))
+			})
+		})
+	})
 })
@@ -0,0 +1,112 @@
+/*
+ * check_restore_completion.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// restoreCompletionRequeueDelay defines how long the operator waits before checking the restore status again.
+const restoreCompletionRequeueDelay = time.Minute
+
+// restoreStateRegex matches the state of the restore in the output of fdbrestore status.
+var restoreStateRegex = regexp.MustCompile(`State:\s*(\w+)`)
+
+// checkRestoreCompletion provides a reconciliation step for tracking a restore into a destination cluster that was
+// created from the destination cluster template until the restore is completed.
+type checkRestoreCompletion struct{}
+
+// reconcile runs the reconciler's work.
+func (c checkRestoreCompletion) reconcile(ctx context.Context, r *FoundationDBRestoreReconciler, restore *fdbv1beta2.FoundationDBRestore) *requeue {
+	if !restore.UsesDestinationClusterTemplate() || restore.Status.Phase != fdbv1beta2.RestorePhaseRestoring {
+		return nil
+	}
+
+	adminClient, err := r.adminClientForRestore(ctx, restore)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetRestoreStatus(ctx)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	state := getRestoreState(status)
+	switch state {
+	case "completed":
+		restore.Status.Phase = fdbv1beta2.RestorePhaseCompleted
+	case "aborted":
+		restore.Status.Phase = fdbv1beta2.RestorePhaseFailed
+	default:
+		return &requeue{
+			message: fmt.Sprintf("Waiting for the restore to complete, current state: %s", state),
+			delay:   restoreCompletionRequeueDelay,
+		}
+	}
+
+	err = setRestoreInProgressMarker(ctx, r, restore, false)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	restore.Status.Running = false
+	err = r.updateOrApply(ctx, restore)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if restore.Status.Phase == fdbv1beta2.RestorePhaseFailed {
+		r.Recorder.Event(restore, corev1.EventTypeWarning, "RestoreAborted", fmt.Sprintf("Restore into cluster %s was aborted", cluster.Name))
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "RestoreAborted", fmt.Sprintf("Restore %s was aborted", restore.Name))
+		return nil
+	}
+
+	r.Recorder.Event(restore, corev1.EventTypeNormal, "RestoreCompleted", fmt.Sprintf("Restore into cluster %s is completed", cluster.Name))
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "RestoreCompleted", fmt.Sprintf("Restore %s is completed, the cluster is ready to use", restore.Name))
+
+	return nil
+}
+
+// getRestoreState returns the state of the restore from the output of fdbrestore status or an empty string if no state
+// is reported.
+func getRestoreState(status string) string {
+	matches := restoreStateRegex.FindStringSubmatch(status)
+	if len(matches) < 2 {
+		return ""
+	}
+
+	return matches[1]
+}
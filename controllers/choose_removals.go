@@ -46,6 +46,13 @@ func (c chooseRemovals) reconcile(ctx context.Context, r *FoundationDBClusterRec
 		}
 	}
 
+	// Process groups that are reassigned to a different process class are marked for removal by the
+	// reassignProcessClasses sub-reconciler, so they must not be replaced by the removal of another process group.
+	reassigned := make(map[fdbv1beta2.ProcessGroupID]bool, len(cluster.Status.ProcessClassReassignments))
+	for _, reassignment := range cluster.Status.ProcessClassReassignments {
+		reassigned[reassignment.ProcessGroupID] = true
+	}
+
 	currentCounts := fdbv1beta2.CreateProcessCountsFromProcessGroupStatus(cluster.Status.ProcessGroups, true).Map()
 	desiredCountStruct, err := cluster.GetProcessCountsWithDefaults()
 	if err != nil {
@@ -58,7 +65,7 @@ func (c chooseRemovals) reconcile(ctx context.Context, r *FoundationDBClusterRec
 		return &requeue{curError: err}
 	}
 	defer adminClient.Close()
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -78,6 +85,9 @@ func (c chooseRemovals) reconcile(ctx context.Context, r *FoundationDBClusterRec
 		for _, processGroup := range cluster.Status.ProcessGroupsByProcessClass(processClass) {
 			if processGroup.IsMarkedForRemoval() {
 				removedCount--
+			} else if reassigned[processGroup.ProcessGroupID] {
+				removedCount--
+				remainingProcessMap[string(processGroup.ProcessGroupID)] = true
 			} else {
 				locality, present := localityMap[string(processGroup.ProcessGroupID)]
 				if present {
@@ -112,11 +122,16 @@ func (c chooseRemovals) reconcile(ctx context.Context, r *FoundationDBClusterRec
 	}
 
 	if hasNewRemovals {
+		var removals []fdbv1beta2.ProcessGroupID
 		for _, processGroup := range cluster.Status.ProcessGroups {
 			if !remainingProcessMap[string(processGroup.ProcessGroupID)] {
+				if !processGroup.IsMarkedForRemoval() {
+					removals = append(removals, processGroup.ProcessGroupID)
+				}
 				processGroup.MarkForRemoval()
 			}
 		}
+		r.recordAction(cluster, fmt.Sprintf("marked process groups %v for removal", removals), "cluster has more process groups than desired")
 		err := r.updateOrApply(ctx, cluster)
 		if err != nil {
 			return &requeue{curError: err}
@@ -0,0 +1,373 @@
+/*
+ * client_library_cache_controller.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// clientLibraryCacheCheckInterval defines how often the readiness of a client library cache is checked while the
+// workload is rolled out.
+const clientLibraryCacheCheckInterval = 15 * time.Second
+
+// FoundationDBClientLibraryCacheReconciler reconciles a FoundationDBClientLibraryCache object
+type FoundationDBClientLibraryCacheReconciler struct {
+	client.Client
+	Recorder        record.EventRecorder
+	Log             logr.Logger
+	ServerSideApply bool
+	// HostPathRoot defines the directory on the nodes that contains the host paths of the caches in the Node mode.
+	HostPathRoot string
+}
+
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclientlibrarycaches,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclientlibrarycaches/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs the reconciliation logic.
+func (r *FoundationDBClientLibraryCacheReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	cache := &fdbv1beta2.FoundationDBClientLibraryCache{}
+	err := r.Get(ctx, request.NamespacedName, cache)
+
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Object not found, return.  Created objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	cacheLog := log.WithValues("namespace", cache.Namespace, "clientLibraryCache", cache.Name)
+
+	err = internal.ValidateClientLibraryCache(cache, r.HostPathRoot)
+	if err != nil {
+		r.Recorder.Event(cache, corev1.EventTypeWarning, "InvalidSpec", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	clusters, err := r.getSelectedClusters(ctx, cache)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	versions, err := cache.GetDesiredVersions(clusters)
+	if err != nil {
+		r.Recorder.Event(cache, corev1.EventTypeWarning, "InvalidVersion", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	var ready bool
+	if len(versions) == 0 {
+		cacheLog.Info("No versions to cache, removing workloads")
+		err = r.deleteWorkloads(ctx, cache, true, true, cacheLog)
+	} else if cache.GetMode() == fdbv1beta2.ClientLibraryCacheModeNamespace {
+		err = r.deleteWorkloads(ctx, cache, true, false, cacheLog)
+		if err == nil {
+			ready, err = r.updateDeployment(ctx, cache, versions, cacheLog)
+		}
+	} else {
+		err = r.deleteWorkloads(ctx, cache, false, true, cacheLog)
+		if err == nil {
+			ready, err = r.updateDaemonSet(ctx, cache, versions, cacheLog)
+		}
+	}
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	versionStrings := make([]string, 0, len(versions))
+	for _, version := range versions {
+		versionStrings = append(versionStrings, version.String())
+	}
+
+	originalStatus := cache.Status.DeepCopy()
+	cache.Status.Versions = versionStrings
+	cache.Status.Ready = ready
+	cache.Status.ObservedGeneration = cache.Generation
+	if !reflect.DeepEqual(*originalStatus, cache.Status) {
+		cacheLog.Info("Updating client library cache status", "versions", versionStrings, "ready", ready)
+		err = r.updateOrApply(ctx, cache)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !ready && len(versions) > 0 {
+		return ctrl.Result{RequeueAfter: clientLibraryCacheCheckInterval}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// getSelectedClusters returns the clusters in the namespace of the cache that match the cluster selector.
+func (r *FoundationDBClientLibraryCacheReconciler) getSelectedClusters(ctx context.Context, cache *fdbv1beta2.FoundationDBClientLibraryCache) ([]fdbv1beta2.FoundationDBCluster, error) {
+	selector := labels.Everything()
+	if cache.Spec.ClusterSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(cache.Spec.ClusterSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clusters := &fdbv1beta2.FoundationDBClusterList{}
+	err := r.List(ctx, clusters, client.InNamespace(cache.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	return clusters.Items, nil
+}
+
+// updateDaemonSet creates or updates the DaemonSet of the cache and returns if all Pods of the DaemonSet are ready.
+func (r *FoundationDBClientLibraryCacheReconciler) updateDaemonSet(ctx context.Context, cache *fdbv1beta2.FoundationDBClientLibraryCache, versions []fdbv1beta2.Version, logger logr.Logger) (bool, error) {
+	daemonSet, err := internal.GetClientLibraryCacheDaemonSet(cache, versions, r.HostPathRoot)
+	if err != nil {
+		return false, err
+	}
+
+	existingDaemonSet := &appsv1.DaemonSet{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: cache.Namespace, Name: daemonSet.Name}, existingDaemonSet)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+
+		logger.Info("Creating DaemonSet", "name", daemonSet.Name)
+		return false, r.Create(ctx, daemonSet)
+	}
+
+	if existingDaemonSet.Annotations[fdbv1beta2.LastSpecKey] != daemonSet.Annotations[fdbv1beta2.LastSpecKey] {
+		logger.Info("Updating DaemonSet", "name", daemonSet.Name)
+		existingDaemonSet.Labels = daemonSet.Labels
+		existingDaemonSet.Annotations = daemonSet.Annotations
+		existingDaemonSet.Spec = daemonSet.Spec
+		return false, r.Update(ctx, existingDaemonSet)
+	}
+
+	status := existingDaemonSet.Status
+	return status.ObservedGeneration >= existingDaemonSet.Generation &&
+		status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+		status.NumberReady == status.DesiredNumberScheduled, nil
+}
+
+// updateDeployment creates or updates the PersistentVolumeClaim and the Deployment of the cache and returns if the
+// Pod of the Deployment is ready.
+func (r *FoundationDBClientLibraryCacheReconciler) updateDeployment(ctx context.Context, cache *fdbv1beta2.FoundationDBClientLibraryCache, versions []fdbv1beta2.Version, logger logr.Logger) (bool, error) {
+	pvc := internal.GetClientLibraryCachePVC(cache)
+	err := r.Get(ctx, client.ObjectKey{Namespace: cache.Namespace, Name: pvc.Name}, &corev1.PersistentVolumeClaim{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+
+		logger.Info("Creating PVC", "name", pvc.Name)
+		err = r.Create(ctx, pvc)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	deployment, err := internal.GetClientLibraryCacheDeployment(cache, versions)
+	if err != nil {
+		return false, err
+	}
+
+	existingDeployment := &appsv1.Deployment{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: cache.Namespace, Name: deployment.Name}, existingDeployment)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+
+		logger.Info("Creating Deployment", "name", deployment.Name)
+		return false, r.Create(ctx, deployment)
+	}
+
+	if existingDeployment.Annotations[fdbv1beta2.LastSpecKey] != deployment.Annotations[fdbv1beta2.LastSpecKey] {
+		logger.Info("Updating Deployment", "name", deployment.Name)
+		existingDeployment.Labels = deployment.Labels
+		existingDeployment.Annotations = deployment.Annotations
+		existingDeployment.Spec = deployment.Spec
+		return false, r.Update(ctx, existingDeployment)
+	}
+
+	status := existingDeployment.Status
+	return status.ObservedGeneration >= existingDeployment.Generation &&
+		status.UpdatedReplicas == status.Replicas &&
+		status.ReadyReplicas == status.Replicas &&
+		status.Replicas > 0, nil
+}
+
+// deleteWorkloads removes the workloads of the cache that are not needed for the current mode. The
+// PersistentVolumeClaim is kept, so the client libraries are not lost when the Deployment is recreated.
+func (r *FoundationDBClientLibraryCacheReconciler) deleteWorkloads(ctx context.Context, cache *fdbv1beta2.FoundationDBClientLibraryCache, deleteDaemonSet bool, deleteDeployment bool, logger logr.Logger) error {
+	var objects []client.Object
+	if deleteDaemonSet {
+		objects = append(objects, &appsv1.DaemonSet{})
+	}
+	if deleteDeployment {
+		objects = append(objects, &appsv1.Deployment{})
+	}
+
+	for _, object := range objects {
+		err := r.Get(ctx, client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, object)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+
+			return err
+		}
+
+		// Only remove the workloads that are created by the operator.
+		if object.GetLabels()[fdbv1beta2.ClientLibraryCacheLabel] != cache.Name {
+			continue
+		}
+
+		logger.Info("Deleting workload", "name", object.GetName(), "kind", reflect.TypeOf(object).Elem().Name())
+		err = r.Delete(ctx, object)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findCachesForCluster returns the requests for all client library caches in the namespace of the cluster that select
+// the cluster.
+func (r *FoundationDBClientLibraryCacheReconciler) findCachesForCluster(object client.Object) []reconcile.Request {
+	caches := &fdbv1beta2.FoundationDBClientLibraryCacheList{}
+	err := r.List(context.Background(), caches, client.InNamespace(object.GetNamespace()))
+	if err != nil {
+		log.Error(err, "could not list client library caches", "namespace", object.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(caches.Items))
+	for _, cache := range caches.Items {
+		if cache.Spec.ClusterSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(cache.Spec.ClusterSelector)
+			if err != nil || !selector.Matches(labels.Set(object.GetLabels())) {
+				continue
+			}
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cache.Namespace, Name: cache.Name}})
+	}
+
+	return requests
+}
+
+// clusterVersionChangedPredicate only passes events for clusters that were created or deleted, or whose desired or
+// running version has changed.
+var clusterVersionChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldCluster, ok := updateEvent.ObjectOld.(*fdbv1beta2.FoundationDBCluster)
+		if !ok {
+			return false
+		}
+
+		newCluster, ok := updateEvent.ObjectNew.(*fdbv1beta2.FoundationDBCluster)
+		if !ok {
+			return false
+		}
+
+		return oldCluster.Spec.Version != newCluster.Spec.Version ||
+			oldCluster.Status.RunningVersion != newCluster.Status.RunningVersion ||
+			!reflect.DeepEqual(oldCluster.Labels, newCluster.Labels)
+	},
+}
+
+// SetupWithManager prepares a reconciler for use.
+func (r *FoundationDBClientLibraryCacheReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int, selector metav1.LabelSelector) error {
+	labelSelectorPredicate, err := predicate.LabelSelectorPredicate(selector)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxConcurrentReconciles},
+		).
+		For(&fdbv1beta2.FoundationDBClientLibraryCache{}, builder.WithPredicates(
+			labelSelectorPredicate,
+			predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.AnnotationChangedPredicate{},
+			),
+		)).
+		Owns(&appsv1.DaemonSet{}).
+		Owns(&appsv1.Deployment{}).
+		Watches(
+			&source.Kind{Type: &fdbv1beta2.FoundationDBCluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.findCachesForCluster),
+			builder.WithPredicates(labelSelectorPredicate, clusterVersionChangedPredicate),
+		).
+		Complete(r)
+}
+
+// updateOrApply updates the status either with server-side apply or if disabled with the normal update call.
+func (r *FoundationDBClientLibraryCacheReconciler) updateOrApply(ctx context.Context, cache *fdbv1beta2.FoundationDBClientLibraryCache) error {
+	if r.ServerSideApply {
+		patch := &fdbv1beta2.FoundationDBClientLibraryCache{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       cache.Kind,
+				APIVersion: cache.APIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cache.Name,
+				Namespace: cache.Namespace,
+			},
+			Status: cache.Status,
+		}
+
+		return r.Status().Patch(ctx, patch, client.Apply, client.FieldOwner("fdb-operator"), client.ForceOwnership)
+	}
+
+	return r.Status().Update(ctx, cache)
+}
@@ -0,0 +1,217 @@
+/*
+ * client_library_cache_controller_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func reloadClientLibraryCache(cache *fdbv1beta2.FoundationDBClientLibraryCache) error {
+	return k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cache.Namespace, Name: cache.Name}, cache)
+}
+
+var _ = Describe("client_library_cache_controller", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var cache *fdbv1beta2.FoundationDBClientLibraryCache
+	var result reconcile.Result
+	var err error
+
+	getInitContainerArgs := func(podTemplate corev1.PodTemplateSpec) [][]string {
+		args := make([][]string, 0, len(podTemplate.Spec.InitContainers))
+		for _, container := range podTemplate.Spec.InitContainers {
+			args = append(args, container.Args)
+		}
+
+		return args
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		cache = &fdbv1beta2.FoundationDBClientLibraryCache{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "client-libraries",
+				Namespace: cluster.Namespace,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), cache)).NotTo(HaveOccurred())
+		result, err = reconcileClientLibraryCache(cache)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reloadClientLibraryCache(cache)).NotTo(HaveOccurred())
+	})
+
+	When("the cache uses the default node mode", func() {
+		It("should create a DaemonSet for the version of the cluster", func() {
+			daemonSet := &appsv1.DaemonSet{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, daemonSet)).NotTo(HaveOccurred())
+			Expect(daemonSet.Labels).To(HaveKeyWithValue(fdbv1beta2.ClientLibraryCacheLabel, cache.Name))
+			Expect(getInitContainerArgs(daemonSet.Spec.Template)).To(Equal([][]string{
+				{"--copy-library", "6.2", "--output-dir", "/var/output-files", "--init-mode"},
+			}))
+			Expect(daemonSet.Spec.Template.Spec.InitContainers[0].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:6.2.21-1"))
+			Expect(daemonSet.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			Expect(daemonSet.Spec.Template.Spec.Volumes[0].HostPath.Path).To(Equal("/var/lib/fdb-client-libraries/my-ns/client-libraries"))
+		})
+
+		It("should report the versions and wait for the DaemonSet", func() {
+			Expect(cache.Status.Versions).To(ConsistOf(fdbv1beta2.Versions.Default.String()))
+			Expect(cache.Status.Ready).To(BeFalse())
+			Expect(result.RequeueAfter).To(Equal(clientLibraryCacheCheckInterval))
+		})
+
+		When("the DaemonSet is ready", func() {
+			JustBeforeEach(func() {
+				daemonSet := &appsv1.DaemonSet{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, daemonSet)).NotTo(HaveOccurred())
+				daemonSet.Status.DesiredNumberScheduled = 3
+				daemonSet.Status.UpdatedNumberScheduled = 3
+				daemonSet.Status.NumberReady = 3
+				daemonSet.Status.ObservedGeneration = daemonSet.Generation
+				Expect(k8sClient.Status().Update(context.TODO(), daemonSet)).NotTo(HaveOccurred())
+
+				result, err = reconcileClientLibraryCache(cache)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadClientLibraryCache(cache)).NotTo(HaveOccurred())
+			})
+
+			It("should mark the cache as ready", func() {
+				Expect(cache.Status.Ready).To(BeTrue())
+				Expect(result.RequeueAfter).To(BeZero())
+			})
+		})
+
+		When("the cluster gets upgraded", func() {
+			JustBeforeEach(func() {
+				cluster.Spec.Version = fdbv1beta2.Versions.NextMajorVersion.String()
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+				result, err = reconcileClientLibraryCache(cache)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadClientLibraryCache(cache)).NotTo(HaveOccurred())
+			})
+
+			It("should cache the running and the desired version", func() {
+				Expect(cache.Status.Versions).To(Equal([]string{
+					fdbv1beta2.Versions.Default.String(),
+					fdbv1beta2.Versions.NextMajorVersion.String(),
+				}))
+				Expect(cache.Status.Ready).To(BeFalse())
+
+				daemonSet := &appsv1.DaemonSet{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, daemonSet)).NotTo(HaveOccurred())
+				Expect(getInitContainerArgs(daemonSet.Spec.Template)).To(ConsistOf(
+					[]string{"--copy-library", "6.2", "--output-dir", "/var/output-files", "--init-mode"},
+					[]string{"--copy-library", "7.0", "--output-dir", "/var/output-files", "--init-mode"},
+				))
+				Expect(daemonSet.Spec.Template.Spec.Containers[0].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:7.0.0-1"))
+			})
+		})
+
+		When("the cache is switched to the namespace mode", func() {
+			JustBeforeEach(func() {
+				cache.Spec.Mode = fdbv1beta2.ClientLibraryCacheModeNamespace
+				Expect(k8sClient.Update(context.TODO(), cache)).NotTo(HaveOccurred())
+
+				result, err = reconcileClientLibraryCache(cache)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should replace the DaemonSet with a Deployment", func() {
+				err = k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, &appsv1.DaemonSet{})
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, &appsv1.Deployment{})).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	When("the cache uses the namespace mode", func() {
+		BeforeEach(func() {
+			cache.Spec.Mode = fdbv1beta2.ClientLibraryCacheModeNamespace
+			cache.Spec.AdditionalVersions = []string{"7.1.26", "7.1.25"}
+		})
+
+		It("should create a PVC and a Deployment", func() {
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, pvc)).NotTo(HaveOccurred())
+			Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, deployment)).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(cache.Name))
+			Expect(getInitContainerArgs(deployment.Spec.Template)).To(ConsistOf(
+				[]string{"--copy-library", "6.2", "--output-dir", "/var/output-files", "--init-mode"},
+				[]string{"--copy-library", "7.1", "--output-dir", "/var/output-files", "--init-mode"},
+			))
+
+			err = k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, &appsv1.DaemonSet{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should only report the newest patch version", func() {
+			Expect(cache.Status.Versions).To(Equal([]string{fdbv1beta2.Versions.Default.String(), "7.1.26"}))
+		})
+	})
+
+	When("the cluster selector doesn't match any cluster", func() {
+		BeforeEach(func() {
+			cache.Spec.ClusterSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"client-libraries": "true"}}
+		})
+
+		It("should not create any workload", func() {
+			Expect(cache.Status.Versions).To(BeEmpty())
+			err = k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cache.Namespace, Name: cache.Name}, &appsv1.DaemonSet{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not be triggered by changes of the cluster", func() {
+			Expect(clientLibraryCacheReconciler.findCachesForCluster(cluster)).To(BeEmpty())
+		})
+
+		When("the cluster gets the label", func() {
+			JustBeforeEach(func() {
+				cluster.Labels = map[string]string{"client-libraries": "true"}
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should be triggered by changes of the cluster", func() {
+				Expect(clientLibraryCacheReconciler.findCachesForCluster(cluster)).To(ConsistOf(reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: cache.Namespace, Name: cache.Name},
+				}))
+			})
+		})
+	})
+})
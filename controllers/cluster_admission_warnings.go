@@ -0,0 +1,149 @@
+/*
+ * cluster_admission_warnings.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/validation"
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ClusterAdmissionWarningsPath defines the path of the webhook that returns warnings for risky changes of clusters.
+const ClusterAdmissionWarningsPath = "/validate-foundationdbcluster"
+
+// +kubebuilder:webhook:path=/validate-foundationdbcluster,mutating=false,failurePolicy=ignore,sideEffects=None,groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=create;update,versions=v1beta2,name=foundationdbcluster.foundationdb.org,admissionReviewVersions=v1
+
+// ClusterAdmissionWarnings is an admission webhook that returns warnings for risky settings and changes of a
+// FoundationDBCluster, e.g. role counts below the recommended counts or upgrades that skip versions. The warnings are
+// shown to the user, e.g. by kubectl. The only requests that are rejected are requests that make the cluster require
+// more resources than the hard limits of the ResourceQuotas in the namespace allow, as those clusters can never be
+// scheduled completely.
+type ClusterAdmissionWarnings struct {
+	// Client is used to read the ResourceQuotas of the namespace of the cluster. If no client is defined, the
+	// resource quotas are not checked.
+	Client  client.Reader
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder for the admission requests.
+func (clusterWarnings *ClusterAdmissionWarnings) InjectDecoder(decoder *admission.Decoder) error {
+	clusterWarnings.decoder = decoder
+	return nil
+}
+
+// Handle returns the warnings for the created or updated cluster.
+func (clusterWarnings *ClusterAdmissionWarnings) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := clusterWarnings.decoder.DecodeRaw(req.Object, cluster)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var previous *fdbv1beta2.FoundationDBCluster
+	if req.Operation == admissionv1.Update {
+		previous = &fdbv1beta2.FoundationDBCluster{}
+		err = clusterWarnings.decoder.DecodeRaw(req.OldObject, previous)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	warnings := validation.GetWarnings(cluster, previous)
+	if clusterWarnings.Client != nil {
+		quotaWarnings, err := clusterWarnings.checkResourceQuotas(ctx, cluster, previous)
+		if err != nil {
+			log.Info("Denying cluster due to exceeded resource quotas", "namespace", cluster.Namespace, "cluster", cluster.Name, "user", req.UserInfo.Username, "error", err.Error())
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
+		}
+
+		warnings = append(warnings, quotaWarnings...)
+	}
+
+	if len(warnings) == 0 {
+		return admission.Allowed("")
+	}
+
+	log.Info("Returning warnings for cluster", "namespace", cluster.Namespace, "cluster", cluster.Name, "user", req.UserInfo.Username, "warnings", warnings)
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// checkResourceQuotas compares the resources that the cluster requires against the ResourceQuotas of the namespace.
+// An error will be returned if the request makes the cluster exceed a hard limit that it didn't exceed before,
+// otherwise the violations will be returned as warnings.
+func (clusterWarnings *ClusterAdmissionWarnings) checkResourceQuotas(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, previous *fdbv1beta2.FoundationDBCluster) ([]string, error) {
+	violations, err := getClusterResourceQuotaViolations(ctx, clusterWarnings.Client, cluster)
+	if err != nil {
+		// The quota check is best effort, errors while reading the quotas must not block changes to the cluster.
+		log.Error(err, "could not check the resource quotas", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		return nil, nil
+	}
+
+	var previousExceeded map[string]fdbv1beta2.None
+	if previous != nil {
+		previousViolations, err := getClusterResourceQuotaViolations(ctx, clusterWarnings.Client, previous)
+		if err == nil {
+			previousExceeded = make(map[string]fdbv1beta2.None, len(previousViolations.exceeded))
+			for _, exceeded := range previousViolations.exceeded {
+				previousExceeded[exceeded] = fdbv1beta2.None{}
+			}
+		}
+	}
+
+	var warnings []string
+	for _, exceeded := range violations.exceeded {
+		if _, ok := previousExceeded[exceeded]; !ok {
+			return nil, fmt.Errorf("cluster requires more resources than the resource quotas allow: %s", strings.Join(violations.exceeded, ", "))
+		}
+	}
+
+	if len(violations.exceeded) > 0 {
+		warnings = append(warnings, fmt.Sprintf("cluster requires more resources than the resource quotas allow: %s", strings.Join(violations.exceeded, ", ")))
+	}
+
+	if len(violations.insufficient) > 0 {
+		warnings = append(warnings, fmt.Sprintf("remaining resource quotas are not sufficient for the new resources: %s", strings.Join(violations.insufficient, ", ")))
+	}
+
+	return warnings, nil
+}
+
+// getClusterResourceQuotaViolations returns the resource quota violations for the normalized spec of the cluster.
+func getClusterResourceQuotaViolations(ctx context.Context, reader client.Reader, cluster *fdbv1beta2.FoundationDBCluster) (resourceQuotaViolations, error) {
+	normalized := cluster.DeepCopy()
+	err := internal.NormalizeClusterSpec(normalized, internal.DeprecationOptions{})
+	if err != nil {
+		return resourceQuotaViolations{}, err
+	}
+
+	return getResourceQuotaViolations(ctx, reader, normalized)
+}
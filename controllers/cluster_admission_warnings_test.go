@@ -0,0 +1,171 @@
+/*
+ * cluster_admission_warnings_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("cluster_admission_warnings", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var previous *fdbv1beta2.FoundationDBCluster
+	var clusterWarnings *ClusterAdmissionWarnings
+	var response admission.Response
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		previous = nil
+
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		clusterWarnings = &ClusterAdmissionWarnings{Client: k8sClient}
+		Expect(clusterWarnings.InjectDecoder(decoder)).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		content, err := json.Marshal(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: content},
+			},
+		}
+
+		if previous != nil {
+			oldContent, err := json.Marshal(previous)
+			Expect(err).NotTo(HaveOccurred())
+			req.Operation = admissionv1.Update
+			req.OldObject = runtime.RawExtension{Raw: oldContent}
+		}
+
+		response = clusterWarnings.Handle(context.TODO(), req)
+	})
+
+	When("the cluster is created with the default settings", func() {
+		It("should allow the request without warnings", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+	})
+
+	When("the cluster is created with fewer logs than recommended", func() {
+		BeforeEach(func() {
+			cluster.Spec.DatabaseConfiguration.Logs = 1
+		})
+
+		It("should allow the request with a warning", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(ConsistOf("the logs count 1 is below the recommended count of 3 for the redundancy mode double"))
+		})
+	})
+
+	When("the cluster is upgraded", func() {
+		BeforeEach(func() {
+			previous = cluster.DeepCopy()
+			previous.Status.RunningVersion = "7.1.26"
+			cluster.Spec.Version = "7.3.0"
+		})
+
+		It("should allow the request with a warning for the skipped version", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(ConsistOf("the upgrade from version 7.1.26 to version 7.3.0 skips at least one release, make sure that the upgrade path is supported"))
+		})
+	})
+
+	When("the namespace has a resource quota", func() {
+		BeforeEach(func() {
+			quota := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "quota",
+					Namespace: cluster.Namespace,
+				},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList{
+						corev1.ResourcePods: resource.MustParse("17"),
+					},
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), quota)).NotTo(HaveOccurred())
+		})
+
+		When("the cluster is created within the quota", func() {
+			It("should allow the request without warnings", func() {
+				Expect(response.Allowed).To(BeTrue())
+				Expect(response.Warnings).To(BeEmpty())
+			})
+		})
+
+		When("the cluster is created with more Pods than the quota allows", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 5
+			})
+
+			It("should deny the request", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(string(response.Result.Reason)).To(Equal("cluster requires more resources than the resource quotas allow: quota/pods: required 18, hard limit 17"))
+			})
+		})
+
+		When("a cluster that already exceeds the quota is updated", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 5
+				previous = cluster.DeepCopy()
+				cluster.Spec.AutomationOptions.MaxConcurrentReplacements = pointer.Int(1)
+			})
+
+			It("should allow the request with a warning", func() {
+				Expect(response.Allowed).To(BeTrue())
+				Expect(response.Warnings).To(ConsistOf("cluster requires more resources than the resource quotas allow: quota/pods: required 18, hard limit 17"))
+			})
+		})
+
+		When("a cluster that already exceeds the quota is scaled up", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 5
+				previous = cluster.DeepCopy()
+				cluster.Spec.ProcessCounts.Storage = 6
+			})
+
+			It("should deny the request", func() {
+				Expect(response.Allowed).To(BeFalse())
+			})
+		})
+	})
+})
@@ -24,14 +24,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"regexp"
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -40,6 +47,7 @@ import (
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -61,12 +69,42 @@ type FoundationDBClusterReconciler struct {
 	DeprecationOptions                 internal.DeprecationOptions
 	GetTimeout                         time.Duration
 	PostTimeout                        time.Duration
+	// SidecarProxy selects the proxy for the requests to the sidecar. If nil, no proxy is used.
+	SidecarProxy func(*http.Request) (*url.URL, error)
+	// AdminClientAuditLogSize defines how many entries of the admin client audit log are kept in the audit ConfigMap
+	// of each cluster. If 0 the audit entries are only written to the log.
+	AdminClientAuditLogSize int
+	// DryRun defines if the operator should only report the actions it would take for all clusters without performing
+	// any changes.
+	DryRun bool
+	// EnableOperatorConfigs defines if the defaults of the FoundationDBOperatorConfig of a cluster should be applied to
+	// the cluster spec. The FoundationDBOperatorConfig CRD must be installed if this is enabled.
+	EnableOperatorConfigs bool
+	// EnableCertManager defines if the reconciler watches the cert-manager Certificates that are owned by the clusters.
+	// The cert-manager CRDs must be installed if this is enabled.
+	EnableCertManager bool
+	// dryRunActions collects the suppressed actions of the current reconciliation if the reconciler runs in dry-run
+	// mode.
+	dryRunActions *dryRunActions
+	// reconciliationSteps contains the custom steps that were added to the reconciliation pipeline.
+	reconciliationSteps []registeredReconciliationStep
+	// remediationHandlers contains the custom remediation handlers that are subscribed to the findings of the
+	// detectors.
+	remediationHandlers []registeredRemediationHandler
+	// runtimeSettings contains the settings that were updated while the operator is running, see
+	// UpdateRuntimeSettings.
+	runtimeSettings *runtimeSettings
+	// subReconcilerObserver is called with the duration of every sub-reconciler, this is used by the reconcile
+	// benchmark.
+	subReconcilerObserver func(name string, duration time.Duration)
 }
 
 // NewFoundationDBClusterReconciler creates a new FoundationDBClusterReconciler with defaults.
 func NewFoundationDBClusterReconciler(podLifecycleManager podmanager.PodLifecycleManager) *FoundationDBClusterReconciler {
 	r := &FoundationDBClusterReconciler{
 		PodLifecycleManager: podLifecycleManager,
+		SidecarProxy:        http.ProxyFromEnvironment,
+		runtimeSettings:     &runtimeSettings{},
 	}
 	r.PodClientProvider = r.newFdbPodClient
 
@@ -75,9 +113,14 @@ func NewFoundationDBClusterReconciler(podLifecycleManager podmanager.PodLifecycl
 
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups="",resources=pods;configmaps;persistentvolumeclaims;events;secrets;services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods;configmaps;persistentvolumeclaims;services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile runs the reconciliation logic.
 func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
@@ -92,7 +135,23 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		return ctrl.Result{}, err
 	}
 
+	clusterStatuses.observe(cluster)
 	clusterLog := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name)
+	settings := r.getRuntimeSettings()
+
+	defer func() {
+		auditErr := r.getAdminClientAuditLog().flush(ctx, r, cluster, settings.AdminClientAuditLogSize)
+		if auditErr != nil {
+			clusterLog.Error(auditErr, "could not update admin client audit log")
+		}
+
+		historyErr := r.getActionHistory().flush(ctx, r, cluster)
+		if historyErr != nil {
+			clusterLog.Error(historyErr, "could not update the action history in the cluster status")
+		}
+
+		r.getActionBudget().reset(cluster)
+	}()
 
 	if cluster.Spec.Skip {
 		clusterLog.Info("Skipping cluster with skip value true", "skip", cluster.Spec.Skip)
@@ -100,7 +159,16 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		return ctrl.Result{}, nil
 	}
 
-	err = internal.NormalizeClusterSpec(cluster, r.DeprecationOptions)
+	// The defaults of the operator config must be applied before the spec is normalized, otherwise the defaults of
+	// the operator would take precedence.
+	if r.EnableOperatorConfigs {
+		err = r.applyOperatorConfig(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	err = internal.NormalizeClusterSpec(cluster, settings.DeprecationOptions)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -111,7 +179,7 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 	}
 	defer adminClient.Close()
 
-	supportedVersion, err := adminClient.VersionSupported(cluster.Spec.Version)
+	supportedVersion, err := adminClient.VersionSupported(ctx, cluster.Spec.Version)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -125,76 +193,288 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		return ctrl.Result{}, fmt.Errorf("ClusterSpec is not valid: %w", err)
 	}
 
-	subReconcilers := []clusterSubReconciler{
-		updateStatus{},
-		updateLockConfiguration{},
-		updateConfigMap{},
-		checkClientCompatibility{},
-		deletePodsForBuggification{},
-		replaceMisconfiguredProcessGroups{},
-		replaceFailedProcessGroups{},
-		addProcessGroups{},
-		addServices{},
-		addPVCs{},
-		addPods{},
-		generateInitialClusterFile{},
-		removeIncompatibleProcesses{},
-		updateSidecarVersions{},
-		updatePodConfig{},
-		updateLabels{},
-		updateDatabaseConfiguration{},
-		chooseRemovals{},
-		excludeProcesses{},
-		changeCoordinators{},
-		bounceProcesses{},
-		maintenanceModeChecker{},
-		updatePods{},
-		removeProcessGroups{},
-		removeServices{},
-		updateStatus{},
+	// In dry-run mode all sub-reconcilers run against a reconciler that suppresses and reports all mutating
+	// operations.
+	if r.isDryRun(cluster) {
+		reconciler := r
+		r = r.newDryRunReconciler(cluster)
+		defer func() {
+			dryRunErr := reconciler.updateDryRunActions(ctx, cluster, r)
+			if dryRunErr != nil {
+				clusterLog.Error(dryRunErr, "could not update the dry-run actions in the cluster status")
+			}
+		}()
 	}
 
+	subReconcilers := r.getSubReconcilers(cluster)
+
+	// The sub-reconcilers run with a context that is cancelled once this reconciliation is superseded by a newer
+	// generation of the cluster, to make sure a hanging command doesn't block the worker.
+	subReconcilerCtx, cancel := r.getSupersededContext(ctx, cluster, clusterLog)
+	defer cancel()
+
 	originalGeneration := cluster.ObjectMeta.Generation
 	normalizedSpec := cluster.Spec.DeepCopy()
 	delayedRequeue := false
+	// delayedRequeueAfter is the smallest non-zero delay requested by the delayed requeues.
+	var delayedRequeueAfter time.Duration
+	completedSubReconcilers := 0
 
 	for _, subReconciler := range subReconcilers {
 		// We have to set the normalized spec here again otherwise any call to Update() for the status of the cluster
 		// will reset all normalized fields...
 		cluster.Spec = *(normalizedSpec.DeepCopy())
-		clusterLog.Info("Attempting to run sub-reconciler", "subReconciler", fmt.Sprintf("%T", subReconciler))
+		clusterLog.Info("Attempting to run sub-reconciler", "subReconciler", getSubReconcilerName(subReconciler))
+		r.getAdminClientAuditLog().setReconciler(cluster, getSubReconcilerName(subReconciler))
+		r.getActionHistory().setReconciler(cluster, getSubReconcilerName(subReconciler))
+
+		subReconcilerStart := time.Now()
+		requeue := subReconciler.reconcile(subReconcilerCtx, r, cluster)
+		if r.subReconcilerObserver != nil {
+			r.subReconcilerObserver(getSubReconcilerName(subReconciler), time.Since(subReconcilerStart))
+		}
+		if subReconcilerCtx.Err() != nil && ctx.Err() == nil {
+			clusterLog.Info("Reconciliation was superseded, requeue reconciliation", "subReconciler", getSubReconcilerName(subReconciler))
+			return ctrl.Result{Requeue: true}, nil
+		}
 
-		requeue := subReconciler.reconcile(ctx, r, cluster)
 		if requeue == nil {
+			completedSubReconcilers++
 			continue
 		}
 
 		if requeue.delayedRequeue {
 			clusterLog.Info("Delaying requeue for sub-reconciler",
-				"subReconciler", fmt.Sprintf("%T", subReconciler),
+				"subReconciler", getSubReconcilerName(subReconciler),
 				"message", requeue.message,
 				"error", requeue.curError)
+			// Only the first delayed requeue will be reported, the final updateStatus will persist the information.
+			if !delayedRequeue {
+				cluster.Status.ReconciliationBlocked = newReconciliationBlockedStatus(subReconciler, requeue, cluster.Status.ReconciliationBlocked)
+			}
 			delayedRequeue = true
+			if requeue.delay > 0 && (delayedRequeueAfter == 0 || requeue.delay < delayedRequeueAfter) {
+				delayedRequeueAfter = requeue.delay
+			}
 			continue
 		}
 
-		return processRequeue(requeue, subReconciler, cluster, r.Recorder, clusterLog)
+		result, err := processRequeue(requeue, subReconciler, cluster, r.Recorder, clusterLog)
+		cluster.Status.ReconciliationProgress = getReconciliationProgress(completedSubReconcilers, len(subReconcilers))
+		r.updateReconciliationBlocked(ctx, cluster, newReconciliationBlockedStatus(subReconciler, requeue, cluster.Status.ReconciliationBlocked), clusterLog)
+
+		return result, err
 	}
 
+	cluster.Status.ReconciliationProgress = getReconciliationProgress(completedSubReconcilers, len(subReconcilers))
 	if cluster.Status.Generations.Reconciled < originalGeneration || delayedRequeue {
 		clusterLog.Info("Cluster was not fully reconciled by reconciliation process", "status", cluster.Status.Generations,
 			"CurrentGeneration", cluster.Status.Generations.Reconciled,
 			"OriginalGeneration", originalGeneration, "DelayedRequeue", delayedRequeue)
 
-		return ctrl.Result{Requeue: true}, nil
+		if delayedRequeue {
+			// Report the delay that is actually used for the requeue.
+			if cluster.Status.ReconciliationBlocked != nil {
+				cluster.Status.ReconciliationBlocked.Delay = metav1.Duration{Duration: delayedRequeueAfter}
+			}
+			// The final updateStatus only persists the status if other fields have changed.
+			r.updateReconciliationBlocked(ctx, cluster, cluster.Status.ReconciliationBlocked, clusterLog)
+		} else {
+			now := &metav1.Time{Time: time.Now()}
+			r.updateReconciliationBlocked(ctx, cluster, &fdbv1beta2.ReconciliationBlockedStatus{
+				Message:   "Cluster was not fully reconciled by reconciliation process",
+				Timestamp: now,
+				Since:     getReconciliationBlockedSince(cluster.Status.ReconciliationBlocked, now),
+			}, clusterLog)
+		}
+
+		return ctrl.Result{Requeue: true, RequeueAfter: delayedRequeueAfter}, nil
 	}
 
+	r.updateReconciliationBlocked(ctx, cluster, nil, clusterLog)
 	clusterLog.Info("Reconciliation complete", "generation", cluster.Status.Generations.Reconciled)
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ReconciliationComplete", fmt.Sprintf("Reconciled generation %d", cluster.Status.Generations.Reconciled))
 
 	return ctrl.Result{}, nil
 }
 
+// supersededCheckInterval defines how often the operator checks if a running reconciliation was superseded.
+var supersededCheckInterval = 5 * time.Second
+
+// getSupersededContext returns a context that will be cancelled once the reconciliation of the provided cluster is
+// superseded. A reconciliation is superseded if the cluster was deleted or if the generation of the cluster is newer
+// than the generation that is currently reconciled. The returned cancel function must be called once the
+// reconciliation is done.
+func (r *FoundationDBClusterReconciler) getSupersededContext(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, logger logr.Logger) (context.Context, context.CancelFunc) {
+	supersededCtx, cancel := context.WithCancel(ctx)
+	key := client.ObjectKeyFromObject(cluster)
+	generation := cluster.Generation
+
+	go func() {
+		ticker := time.NewTicker(supersededCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-supersededCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := &fdbv1beta2.FoundationDBCluster{}
+			err := r.Get(supersededCtx, key, current)
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					logger.Info("Cancelling reconciliation, cluster was deleted")
+					cancel()
+					return
+				}
+
+				continue
+			}
+
+			if !current.DeletionTimestamp.IsZero() {
+				logger.Info("Cancelling reconciliation, cluster is being deleted")
+				cancel()
+				return
+			}
+
+			if current.Generation > generation {
+				logger.Info("Cancelling reconciliation, cluster has a newer generation", "generation", generation, "newGeneration", current.Generation)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return supersededCtx, cancel
+}
+
+// isDeletionStarted returns true if the deletion of the new object was started with the update.
+func isDeletionStarted(updateEvent event.UpdateEvent) bool {
+	return updateEvent.ObjectOld.GetDeletionTimestamp().IsZero() && !updateEvent.ObjectNew.GetDeletionTimestamp().IsZero()
+}
+
+// podChangedPredicate only passes updates of Pods that changed their phase or IP addresses or that are being deleted.
+var podChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldPod, ok := updateEvent.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+
+		newPod, ok := updateEvent.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+
+		return isDeletionStarted(updateEvent) ||
+			oldPod.Status.Phase != newPod.Status.Phase ||
+			oldPod.Status.PodIP != newPod.Status.PodIP ||
+			!equality.Semantic.DeepEqual(oldPod.Status.PodIPs, newPod.Status.PodIPs)
+	},
+}
+
+// cliCommandPodPredicate filters out the Pods that run CLI commands for a cluster. Those Pods are created and deleted
+// for every command, so their events must not trigger a reconciliation of the cluster.
+var cliCommandPodPredicate = predicate.NewPredicateFuncs(func(object client.Object) bool {
+	_, isCommandPod := object.GetLabels()[fdbv1beta2.CliCommandLabel]
+	return !isCommandPod
+})
+
+// pvcChangedPredicate only passes updates of PVCs that changed their phase or that are being deleted.
+var pvcChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldPVC, ok := updateEvent.ObjectOld.(*corev1.PersistentVolumeClaim)
+		if !ok {
+			return false
+		}
+
+		newPVC, ok := updateEvent.ObjectNew.(*corev1.PersistentVolumeClaim)
+		if !ok {
+			return false
+		}
+
+		return isDeletionStarted(updateEvent) || oldPVC.Status.Phase != newPVC.Status.Phase
+	},
+}
+
+// configMapChangedPredicate only passes updates of config maps that changed their data or that are being deleted.
+var configMapChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldConfigMap, ok := updateEvent.ObjectOld.(*corev1.ConfigMap)
+		if !ok {
+			return false
+		}
+
+		newConfigMap, ok := updateEvent.ObjectNew.(*corev1.ConfigMap)
+		if !ok {
+			return false
+		}
+
+		return isDeletionStarted(updateEvent) || !equality.Semantic.DeepEqual(oldConfigMap.Data, newConfigMap.Data)
+	},
+}
+
+// environmentSourceChangedPredicate only passes updates of Secrets and ConfigMaps that changed their data.
+var environmentSourceChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		switch oldObject := updateEvent.ObjectOld.(type) {
+		case *corev1.Secret:
+			newSecret, ok := updateEvent.ObjectNew.(*corev1.Secret)
+			return ok && !equality.Semantic.DeepEqual(oldObject.Data, newSecret.Data)
+		case *corev1.ConfigMap:
+			newConfigMap, ok := updateEvent.ObjectNew.(*corev1.ConfigMap)
+			return ok && (!equality.Semantic.DeepEqual(oldObject.Data, newConfigMap.Data) || !equality.Semantic.DeepEqual(oldObject.BinaryData, newConfigMap.BinaryData))
+		}
+
+		return false
+	},
+}
+
+// serviceChangedPredicate only passes updates of services that changed their cluster IP or load balancer ingress or
+// that are being deleted.
+var serviceChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldService, ok := updateEvent.ObjectOld.(*corev1.Service)
+		if !ok {
+			return false
+		}
+
+		newService, ok := updateEvent.ObjectNew.(*corev1.Service)
+		if !ok {
+			return false
+		}
+
+		return isDeletionStarted(updateEvent) ||
+			oldService.Spec.ClusterIP != newService.Spec.ClusterIP ||
+			!equality.Semantic.DeepEqual(oldService.Status.LoadBalancer.Ingress, newService.Status.LoadBalancer.Ingress)
+	},
+}
+
+// certificateChangedPredicate only passes updates of cert-manager Certificates that changed their revision or their
+// readiness or that are being deleted. The revision is increased for every issued certificate.
+var certificateChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+		oldCertificate, ok := updateEvent.ObjectOld.(*unstructured.Unstructured)
+		if !ok {
+			return false
+		}
+
+		newCertificate, ok := updateEvent.ObjectNew.(*unstructured.Unstructured)
+		if !ok {
+			return false
+		}
+
+		oldRevision, _, _ := unstructured.NestedInt64(oldCertificate.Object, "status", "revision")
+		newRevision, _, _ := unstructured.NestedInt64(newCertificate.Object, "status", "revision")
+
+		return isDeletionStarted(updateEvent) ||
+			oldRevision != newRevision ||
+			internal.IsCertificateReady(oldCertificate) != internal.IsCertificateReady(newCertificate)
+	},
+}
+
 // SetupWithManager prepares a reconciler for use.
 func (r *FoundationDBClusterReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int, selector metav1.LabelSelector, watchedObjects ...client.Object) error {
 	err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, "metadata.name", func(o client.Object) []string {
@@ -222,33 +502,56 @@ func (r *FoundationDBClusterReconciler) SetupWithManager(mgr ctrl.Manager, maxCo
 		return err
 	}
 
+	// Only react on generation changes or annotation changes and only watch resources with the provided label
+	// selector. For the owned resources, changes of the fields that the operator acts on will trigger a
+	// reconciliation too, but status heartbeats will be ignored.
+	defaultPredicates := predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+	)
+
 	builder := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles},
 		).
-		For(&fdbv1beta2.FoundationDBCluster{}).
-		Owns(&corev1.Pod{}).
-		Owns(&corev1.PersistentVolumeClaim{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.Service{}).
-		// Only react on generation changes or annotation changes and only watch
-		// resources with the provided label selector.
-		WithEventFilter(
-			predicate.And(
-				labelSelectorPredicate,
-				predicate.Or(
-					predicate.GenerationChangedPredicate{},
-					predicate.AnnotationChangedPredicate{},
-				),
-			))
+		For(&fdbv1beta2.FoundationDBCluster{}, ctrlbuilder.WithPredicates(labelSelectorPredicate, defaultPredicates)).
+		Owns(&corev1.Pod{}, ctrlbuilder.WithPredicates(labelSelectorPredicate, cliCommandPodPredicate, predicate.Or(defaultPredicates, podChangedPredicate))).
+		Owns(&corev1.PersistentVolumeClaim{}, ctrlbuilder.WithPredicates(labelSelectorPredicate, predicate.Or(defaultPredicates, pvcChangedPredicate))).
+		Owns(&corev1.ConfigMap{}, ctrlbuilder.WithPredicates(labelSelectorPredicate, predicate.Or(defaultPredicates, configMapChangedPredicate))).
+		Owns(&corev1.Service{}, ctrlbuilder.WithPredicates(labelSelectorPredicate, predicate.Or(defaultPredicates, serviceChangedPredicate)))
 
 	for _, object := range watchedObjects {
-		builder.Owns(object)
+		builder.Owns(object, ctrlbuilder.WithPredicates(labelSelectorPredicate, defaultPredicates))
+	}
+
+	if r.EnableCertManager {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(internal.CertificateGroupVersionKind)
+		builder.Owns(certificate, ctrlbuilder.WithPredicates(labelSelectorPredicate, predicate.Or(defaultPredicates, certificateChangedPredicate)))
+	}
+
+	// The Secrets and ConfigMaps that are referenced in the environment of the processes are not owned by the cluster,
+	// so changes of their data are mapped to the clusters that reference them.
+	for _, object := range []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}} {
+		builder.Watches(
+			&source.Kind{Type: object},
+			handler.EnqueueRequestsFromMapFunc(r.findClustersForEnvironmentSource),
+			ctrlbuilder.WithPredicates(environmentSourceChangedPredicate),
+		)
+	}
+
+	if r.EnableOperatorConfigs {
+		builder.Watches(
+			&source.Kind{Type: &fdbv1beta2.FoundationDBOperatorConfig{}},
+			handler.EnqueueRequestsFromMapFunc(r.findClustersForOperatorConfig),
+			ctrlbuilder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		)
 	}
+
 	return builder.Complete(r)
 }
 
-func (r *FoundationDBClusterReconciler) updatePodDynamicConf(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (bool, error) {
+func (r *FoundationDBClusterReconciler) updatePodDynamicConf(ctx context.Context, logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (bool, error) {
 	if cluster.ProcessGroupIsBeingRemoved(podmanager.GetProcessGroupID(cluster, pod)) {
 		return true, nil
 	}
@@ -287,14 +590,14 @@ func (r *FoundationDBClusterReconciler) updatePodDynamicConf(logger logr.Logger,
 		}
 		expectedConf = string(configData)
 	} else {
-		expectedConf, err = internal.GetMonitorConf(cluster, processClass, podClient, serversPerPod)
+		expectedConf, err = internal.GetMonitorConf(ctx, cluster, processClass, podClient, serversPerPod)
 		if err != nil {
 			return false, err
 		}
 	}
 
-	syncedFDBcluster, clusterErr := podClient.UpdateFile("fdb.cluster", cluster.Status.ConnectionString)
-	syncedFDBMonitor, err := podClient.UpdateFile("fdbmonitor.conf", expectedConf)
+	syncedFDBcluster, clusterErr := podClient.UpdateFile(ctx, "fdb.cluster", cluster.GetDesiredConnectionString())
+	syncedFDBMonitor, err := podClient.UpdateFile(ctx, "fdbmonitor.conf", expectedConf)
 	if !syncedFDBcluster || !syncedFDBMonitor {
 		if clusterErr != nil {
 			return false, clusterErr
@@ -304,17 +607,39 @@ func (r *FoundationDBClusterReconciler) updatePodDynamicConf(logger logr.Logger,
 	}
 
 	if cluster.IsBeingUpgradedWithVersionIncompatibleVersion() {
-		return podClient.IsPresent(fmt.Sprintf("bin/%s/fdbserver", cluster.Spec.Version))
+		return podClient.IsPresent(ctx, fmt.Sprintf("bin/%s/fdbserver", cluster.Spec.Version))
 	}
 
 	return true, nil
 }
 
+// getCloneProcessGroups fetches the source cluster of a clone and maps the process groups of the source cluster that
+// have a VolumeSnapshot to the process groups of the clone.
+func (r *FoundationDBClusterReconciler) getCloneProcessGroups(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) (*fdbv1beta2.FoundationDBCluster, map[fdbv1beta2.ProcessGroupID]internal.CloneProcessGroup, error) {
+	source := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.CloneFrom.ClusterName}, source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	processGroups, err := internal.GetCloneProcessGroups(cluster, source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return source, processGroups, nil
+}
+
 func (r *FoundationDBClusterReconciler) getPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, string) {
 	if pod == nil {
 		return nil, fmt.Sprintf("Process group in cluster %s/%s does not have pod defined", cluster.Namespace, cluster.Name)
 	}
 
+	notReady := internal.GetAdditionalContainersNotReady(cluster, pod)
+	if len(notReady) > 0 {
+		return nil, fmt.Sprintf("Waiting for additional containers %v of pod %s to be ready", notReady, pod.Name)
+	}
+
 	podClient, err := r.PodClientProvider(cluster, pod)
 	if err != nil {
 		return nil, err.Error()
@@ -326,12 +651,28 @@ func (r *FoundationDBClusterReconciler) getPodClient(cluster *fdbv1beta2.Foundat
 // getDatabaseClientProvider gets the client provider for a reconciler.
 func (r *FoundationDBClusterReconciler) getDatabaseClientProvider() fdbadminclient.DatabaseClientProvider {
 	if r.DatabaseClientProvider != nil {
-		return r.DatabaseClientProvider
+		// Suppressed operations are not audited, as they are never run against the database.
+		if r.dryRunActions != nil {
+			return dryRunDatabaseClientProvider{
+				DatabaseClientProvider: r.DatabaseClientProvider,
+				actions:                r.dryRunActions,
+			}
+		}
+
+		return auditingDatabaseClientProvider{
+			DatabaseClientProvider: r.DatabaseClientProvider,
+			auditLog:               r.getAdminClientAuditLog(),
+		}
 	}
 
 	panic("Cluster reconciler does not have a DatabaseClientProvider defined")
 }
 
+// getAdminClientAuditLog returns the audit log for the mutating operations of the admin clients.
+func (r *FoundationDBClusterReconciler) getAdminClientAuditLog() *adminClientAuditLog {
+	return clusterAdminClientAuditLog
+}
+
 func (r *FoundationDBClusterReconciler) getLockClient(cluster *fdbv1beta2.FoundationDBCluster) (fdbadminclient.LockClient, error) {
 	return r.getDatabaseClientProvider().GetLockClient(cluster)
 }
@@ -376,21 +717,90 @@ type clusterSubReconciler interface {
 
 // newFdbPodClient builds a client for working with an FDB Pod
 func (r *FoundationDBClusterReconciler) newFdbPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, error) {
-	return internal.NewFdbPodClient(cluster, pod, log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "pod", pod.Name), r.GetTimeout, r.PostTimeout)
+	settings := r.getRuntimeSettings()
+	return internal.NewFdbPodClient(cluster, pod, log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "pod", pod.Name), settings.GetTimeout, settings.PostTimeout, r.SidecarProxy)
 }
 
-func (r *FoundationDBClusterReconciler) getCoordinatorSet(cluster *fdbv1beta2.FoundationDBCluster) (map[string]fdbv1beta2.None, error) {
-	adminClient, err := r.DatabaseClientProvider.GetAdminClient(cluster, r)
+func (r *FoundationDBClusterReconciler) getCoordinatorSet(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) (map[string]fdbv1beta2.None, error) {
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
 	if err != nil {
 		return map[string]fdbv1beta2.None{}, err
 	}
 	defer adminClient.Close()
 
-	return adminClient.GetCoordinatorSet()
+	return adminClient.GetCoordinatorSet(ctx)
 }
 
-// updateOrApply updates the status either with server-side apply or if disabled with the normal update call.
+// newReconciliationBlockedStatus creates the status information for a requeue from the provided sub-reconciler.
+func newReconciliationBlockedStatus(subReconciler clusterSubReconciler, requeue *requeue, previous *fdbv1beta2.ReconciliationBlockedStatus) *fdbv1beta2.ReconciliationBlockedStatus {
+	message := requeue.message
+	if message == "" && requeue.curError != nil {
+		message = requeue.curError.Error()
+	}
+
+	now := &metav1.Time{Time: time.Now()}
+	return &fdbv1beta2.ReconciliationBlockedStatus{
+		SubReconciler: getSubReconcilerName(subReconciler),
+		Message:       message,
+		Delay:         metav1.Duration{Duration: requeue.delay},
+		Timestamp:     now,
+		Since:         getReconciliationBlockedSince(previous, now),
+	}
+}
+
+// getReconciliationBlockedSince returns the timestamp since when the reconciliation is blocked. If the reconciliation
+// was already blocked before, the timestamp of the previous status will be kept.
+func getReconciliationBlockedSince(previous *fdbv1beta2.ReconciliationBlockedStatus, now *metav1.Time) *metav1.Time {
+	if previous == nil || previous.Since == nil {
+		return now
+	}
+
+	return previous.Since
+}
+
+// updateReconciliationBlocked updates the information why the reconciliation was blocked in the cluster status. If
+// the status is already up to date no update will be issued. Errors will only be logged as the reconciliation result
+// should not be changed by this update.
+func (r *FoundationDBClusterReconciler) updateReconciliationBlocked(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, blocked *fdbv1beta2.ReconciliationBlockedStatus, logger logr.Logger) {
+	if blocked == nil && cluster.Status.ReconciliationBlocked == nil && !r.hasReconciliationProgressChanged(ctx, cluster) {
+		return
+	}
+
+	cluster.Status.ReconciliationBlocked = blocked
+	err := r.updateOrApply(ctx, cluster)
+	if err != nil {
+		logger.Error(err, "Error updating the reconciliation blocked information in the cluster status")
+	}
+}
+
+// getReconciliationProgress returns the percentage of the completed sub-reconcilers.
+func getReconciliationProgress(completed int, total int) int {
+	if total == 0 {
+		return 100
+	}
+
+	return completed * 100 / total
+}
+
+// hasReconciliationProgressChanged returns true if the reconciliation progress of the cluster differs from the
+// persisted reconciliation progress.
+func (r *FoundationDBClusterReconciler) hasReconciliationProgressChanged(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) bool {
+	current := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(cluster), current)
+	if err != nil {
+		return true
+	}
+
+	return current.Status.ReconciliationProgress != cluster.Status.ReconciliationProgress
+}
+
+// updateOrApply updates the status either with server-side apply or if disabled with the normal update call. The
+// status updates of a cluster are serialized and conflicts are resolved by patching the status of the latest version
+// of the cluster, see updateStatusOnConflict.
 func (r *FoundationDBClusterReconciler) updateOrApply(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) error {
+	tracked, unlock := clusterStatuses.lock(client.ObjectKeyFromObject(cluster))
+	defer unlock()
+
 	if r.ServerSideApply {
 		// TODO(johscheuer): We have to set the TypeMeta otherwise the Patch command will fail. This is the rudimentary
 		// support for server side apply which should be enough for the status use case. The controller runtime will
@@ -410,5 +820,17 @@ func (r *FoundationDBClusterReconciler) updateOrApply(ctx context.Context, clust
 		return r.Status().Patch(ctx, patch, client.Apply, client.FieldOwner("fdb-operator"), client.ForceOwnership)
 	}
 
-	return r.Status().Update(ctx, cluster)
+	err := r.Status().Update(ctx, cluster)
+	if err == nil {
+		tracked.record(cluster)
+		return nil
+	}
+
+	if !k8serrors.IsConflict(err) {
+		return err
+	}
+
+	// The cluster could have been changed in the meantime, e.g. the spec by the scale subresource. The status is
+	// owned by the operator, so we can update the status of the latest version without overwriting those changes.
+	return r.updateStatusOnConflict(ctx, cluster, tracked)
 }
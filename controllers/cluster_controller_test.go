@@ -50,6 +50,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 )
@@ -161,6 +162,20 @@ var _ = Describe("cluster_controller", func() {
 				generationGap = 0
 			})
 
+			It("should not report a blocked reconciliation", func() {
+				Expect(cluster.Status.ReconciliationBlocked).To(BeNil())
+			})
+
+			It("should report the cluster as ready", func() {
+				Expect(cluster.Status.Phase).To(Equal(fdbv1beta2.ClusterPhaseReady))
+				Expect(cluster.Status.ReconciliationProgress).To(Equal(100))
+			})
+
+			It("should report the storage process groups for the scale subresource", func() {
+				Expect(cluster.Status.StorageProcessGroups).To(Equal(4))
+				Expect(cluster.Status.StorageSelector).To(Equal("foundationdb.org/fdb-cluster-name=operator-test-1,foundationdb.org/fdb-process-class=storage"))
+			})
+
 			It("should create pods", func() {
 				pods := &corev1.PodList{}
 				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
@@ -219,7 +234,7 @@ var _ = Describe("cluster_controller", func() {
 				configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
 				err = k8sClient.Get(context.TODO(), configMapName, configMap)
 				Expect(err).NotTo(HaveOccurred())
-				expectedConfigMap, _ := internal.GetConfigMap(cluster)
+				expectedConfigMap, _ := internal.GetConfigMap(context.TODO(), cluster)
 				Expect(configMap.Data).To(Equal(expectedConfigMap.Data))
 			})
 
@@ -275,7 +290,7 @@ var _ = Describe("cluster_controller", func() {
 				Expect(len(fdbv1beta2.FilterByCondition(cluster.Status.ProcessGroups, fdbv1beta2.IncorrectCommandLine, false))).To(Equal(0))
 				Expect(len(fdbv1beta2.FilterByCondition(cluster.Status.ProcessGroups, fdbv1beta2.MissingProcesses, false))).To(Equal(0))
 
-				status, err := adminClient.GetStatus()
+				status, err := adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 
 				configuration := status.Cluster.DatabaseConfiguration.DeepCopy()
@@ -337,7 +352,7 @@ var _ = Describe("cluster_controller", func() {
 					for _, envVar := range pod.Spec.InitContainers[0].Env {
 						env[envVar.Name] = envVar.Value
 					}
-					Expect(env["FDB_DNS_NAME"]).To(Equal(internal.GetPodDNSName(cluster, pod.Name)))
+					Expect(env["FDB_DNS_NAME"]).To(Equal(internal.GetPodDNSName(cluster, internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta), pod.Name)))
 				}
 			})
 		})
@@ -379,7 +394,7 @@ var _ = Describe("cluster_controller", func() {
 				Expect(len(pods.Items)).To(Equal(len(originalPods.Items)))
 				sortPodsByName(pods)
 
-				cm, _ := internal.GetConfigMap(cluster)
+				cm, _ := internal.GetConfigMap(context.TODO(), cluster)
 
 				for _, file := range cm.Data {
 					Expect(file).To(Not(ContainSubstring("fdbserver")))
@@ -466,7 +481,7 @@ var _ = Describe("cluster_controller", func() {
 				configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
 				err = k8sClient.Get(context.TODO(), configMapName, configMap)
 				Expect(err).NotTo(HaveOccurred())
-				expectedConfigMap, _ := internal.GetConfigMap(cluster)
+				expectedConfigMap, _ := internal.GetConfigMap(context.TODO(), cluster)
 				Expect(configMap.Data).To(Equal(expectedConfigMap.Data))
 			})
 		})
@@ -497,7 +512,7 @@ var _ = Describe("cluster_controller", func() {
 				configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
 				err = k8sClient.Get(context.TODO(), configMapName, configMap)
 				Expect(err).NotTo(HaveOccurred())
-				expectedConfigMap, _ := internal.GetConfigMap(cluster)
+				expectedConfigMap, _ := internal.GetConfigMap(context.TODO(), cluster)
 				Expect(configMap.Data).To(Equal(expectedConfigMap.Data))
 			})
 		})
@@ -936,6 +951,23 @@ var _ = Describe("cluster_controller", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(len(pods.Items)).To(Equal(18))
 			})
+
+			It("should report why the reconciliation is blocked", func() {
+				_, err = reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.Status.ReconciliationBlocked).NotTo(BeNil())
+				Expect(cluster.Status.ReconciliationBlocked.SubReconciler).NotTo(BeEmpty())
+				Expect(cluster.Status.ReconciliationBlocked.Message).NotTo(BeEmpty())
+				Expect(cluster.Status.ReconciliationBlocked.Timestamp).NotTo(BeNil())
+			})
+
+			It("should report the progress of the reconciliation", func() {
+				_, err = reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.Status.Phase).To(Equal(fdbv1beta2.ClusterPhaseReconciling))
+				Expect(cluster.Status.ReconciliationProgress).To(BeNumerically(">", 0))
+				Expect(cluster.Status.ReconciliationProgress).To(BeNumerically("<", 100))
+			})
 		})
 
 		Context("with multiple replacements", func() {
@@ -1023,7 +1055,7 @@ var _ = Describe("cluster_controller", func() {
 					configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
 					err = k8sClient.Get(context.TODO(), configMapName, configMap)
 					Expect(err).NotTo(HaveOccurred())
-					expectedConfigMap, _ := internal.GetConfigMap(cluster)
+					expectedConfigMap, _ := internal.GetConfigMap(context.TODO(), cluster)
 					Expect(configMap.Data).To(Equal(expectedConfigMap.Data))
 				})
 			})
@@ -1076,7 +1108,7 @@ var _ = Describe("cluster_controller", func() {
 					configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
 					err = k8sClient.Get(context.TODO(), configMapName, configMap)
 					Expect(err).NotTo(HaveOccurred())
-					expectedConfigMap, _ := internal.GetConfigMap(cluster)
+					expectedConfigMap, _ := internal.GetConfigMap(context.TODO(), cluster)
 					Expect(configMap.Data).To(Equal(expectedConfigMap.Data))
 				})
 			})
@@ -1132,13 +1164,13 @@ var _ = Describe("cluster_controller", func() {
 				adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 
-				status, err := adminClient.GetStatus()
+				status, err := adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status.Cluster.DatabaseConfiguration.RedundancyMode).To(Equal(fdbv1beta2.RedundancyModeDouble))
 
 				cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbv1beta2.RedundancyModeTriple
 
-				status, err = adminClient.GetStatus()
+				status, err = adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status.Cluster.DatabaseConfiguration.RedundancyMode).To(Equal(fdbv1beta2.RedundancyModeDouble))
 			})
@@ -1176,7 +1208,7 @@ var _ = Describe("cluster_controller", func() {
 
 					configuration := cluster.DesiredDatabaseConfiguration()
 					configuration.LogVersion = 3
-					err = adminClient.ConfigureDatabase(configuration, false, cluster.Spec.Version)
+					err = adminClient.ConfigureDatabase(context.TODO(), configuration, false, cluster.Spec.Version)
 					Expect(err).NotTo(HaveOccurred())
 
 					generationGap = 1
@@ -1189,6 +1221,28 @@ var _ = Describe("cluster_controller", func() {
 				})
 			})
 
+			Context("with perpetual storage wiggle settings that are not set in the spec", func() {
+				BeforeEach(func() {
+					cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbv1beta2.RedundancyModeDouble
+					cluster.Spec.SeedConnectionString = "touch"
+
+					configuration := cluster.DesiredDatabaseConfiguration()
+					configuration.PerpetualStorageWiggle = pointer.Int(1)
+					configuration.PerpetualStorageWiggleLocality = "zoneid:zone1"
+					err = adminClient.ConfigureDatabase(context.TODO(), configuration, false, cluster.Spec.Version)
+					Expect(err).NotTo(HaveOccurred())
+
+					generationGap = 1
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should not reconfigure the database", func() {
+					Expect(adminClient.DatabaseConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(1)))
+					Expect(adminClient.DatabaseConfiguration.PerpetualStorageWiggleLocality).To(Equal("zoneid:zone1"))
+				})
+			})
+
 			Context("with changes disabled", func() {
 				BeforeEach(func() {
 					shouldCompleteReconciliation = false
@@ -1730,6 +1784,97 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with a change to the public IP source to the load balancer", func() {
+			BeforeEach(func() {
+				source := fdbv1beta2.PublicIPSourceLoadBalancer
+				cluster.Spec.Routing.PublicIPSource = &source
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should create load balancer services for the pods", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pods.Items).NotTo(BeEmpty())
+
+				for _, pod := range pods.Items {
+					Expect(pod.Annotations[fdbv1beta2.PublicIPSourceAnnotation]).To(Equal("loadBalancer"))
+
+					service := &corev1.Service{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, service)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+					Expect(service.Status.LoadBalancer.Ingress).To(HaveLen(1))
+					Expect(pod.Annotations[fdbv1beta2.PublicIPAnnotation]).To(Equal(service.Status.LoadBalancer.Ingress[0].IP))
+				}
+			})
+
+			It("should use the load balancer IPs as process addresses", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+
+				publicIPs := map[fdbv1beta2.ProcessGroupID]string{}
+				for _, pod := range pods.Items {
+					publicIPs[fdbv1beta2.ProcessGroupID(pod.Labels[fdbv1beta2.FDBProcessGroupIDLabel])] = pod.Annotations[fdbv1beta2.PublicIPAnnotation]
+				}
+
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					Expect(processGroup.Addresses).To(ConsistOf(publicIPs[processGroup.ProcessGroupID]))
+				}
+			})
+		})
+
+		Context("with a change to the public IP source to the node", func() {
+			BeforeEach(func() {
+				source := fdbv1beta2.PublicIPSourceNode
+				cluster.Spec.Routing.PublicIPSource = &source
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should expose the process ports on the node", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pods.Items).NotTo(BeEmpty())
+
+				for _, pod := range pods.Items {
+					Expect(pod.Annotations[fdbv1beta2.PublicIPSourceAnnotation]).To(Equal("node"))
+					Expect(pod.Spec.Containers[0].Ports).To(ContainElement(corev1.ContainerPort{
+						Name:          "non-tls",
+						ContainerPort: 4501,
+						HostPort:      4501,
+						Protocol:      corev1.ProtocolTCP,
+					}))
+				}
+			})
+
+			It("should not create services for the pods", func() {
+				services := &corev1.ServiceList{}
+				err = k8sClient.List(context.TODO(), services, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(services.Items).To(BeEmpty())
+			})
+
+			It("should use the node IPs as process addresses", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+
+				hostIPs := map[fdbv1beta2.ProcessGroupID]string{}
+				for _, pod := range pods.Items {
+					Expect(pod.Status.HostIP).NotTo(Equal(pod.Status.PodIP))
+					hostIPs[fdbv1beta2.ProcessGroupID(pod.Labels[fdbv1beta2.FDBProcessGroupIDLabel])] = pod.Status.HostIP
+				}
+
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					Expect(processGroup.Addresses).To(ConsistOf(hostIPs[processGroup.ProcessGroupID]))
+				}
+			})
+		})
+
 		Context("when enabling explicit listen addresses", func() {
 			BeforeEach(func() {
 				cluster.Spec.UseExplicitListenAddress = pointer.Bool(false)
@@ -2405,6 +2550,47 @@ var _ = Describe("cluster_controller", func() {
 							fmt.Sprintf("1 clients do not support version %s: 127.0.0.3:85891 (%s)", fdbv1beta2.Versions.NextMajorVersion, cluster.Name),
 						))
 					})
+
+					It("should list the unsupported clients in the status", func() {
+						_, err := reloadCluster(cluster)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(cluster.Status.IncompatibleClients).To(Equal(&fdbv1beta2.IncompatibleClientsStatus{
+							Version: fdbv1beta2.Versions.NextMajorVersion.String(),
+							Count:   1,
+							Clients: []string{fmt.Sprintf("127.0.0.3:85891 (%s)", cluster.Name)},
+						}))
+					})
+
+					It("should not update the running version", func() {
+						_, err := reloadCluster(cluster)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(cluster.Status.RunningVersion).NotTo(Equal(cluster.Spec.Version))
+					})
+				})
+
+				Context("with a threshold that is not exceeded", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.MaxIncompatibleClientsForUpgrade = pointer.Int(1)
+						Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+					})
+
+					It("should warn about the unsupported clients", func() {
+						events := &corev1.EventList{}
+						Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+						var matchingEvents []corev1.Event
+						for _, event := range events.Items {
+							if event.InvolvedObject.UID == cluster.ObjectMeta.UID && event.Reason == "UpgradingWithUnsupportedClients" {
+								matchingEvents = append(matchingEvents, event)
+							}
+						}
+						Expect(matchingEvents).NotTo(BeEmpty())
+					})
+
+					It("should update the running version and clear the unsupported clients", func() {
+						Expect(cluster.Status.RunningVersion).To(Equal(cluster.Spec.Version))
+						Expect(cluster.Status.IncompatibleClients).To(BeNil())
+					})
 				})
 
 				Context("with the check disabled", func() {
@@ -2999,6 +3185,54 @@ var _ = Describe("cluster_controller", func() {
 		})
 	})
 
+	Describe("Reconciliation with a managed connection only", func() {
+		BeforeEach(func() {
+			cluster.Spec.ManagedConnectionOnly = pointer.Bool(true)
+			cluster.Spec.SeedConnectionString = fakeConnectionString
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			// The external cluster is already configured.
+			adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminClient.ConfigureDatabase(context.TODO(), cluster.Spec.DatabaseConfiguration, true, cluster.Spec.Version)).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			generation, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(generation).To(Equal(int64(1)))
+		})
+
+		AfterEach(func() {
+			k8sClient.Clear()
+		})
+
+		It("should not create any pods", func() {
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)).NotTo(HaveOccurred())
+			Expect(pods.Items).To(BeEmpty())
+		})
+
+		It("should not create any PVCs", func() {
+			pvcs := &corev1.PersistentVolumeClaimList{}
+			Expect(k8sClient.List(context.TODO(), pvcs, getListOptions(cluster)...)).NotTo(HaveOccurred())
+			Expect(pvcs.Items).To(BeEmpty())
+		})
+
+		It("should distribute the connection string in the config map", func() {
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: fmt.Sprintf("%s-config", cluster.Name)}, configMap)).NotTo(HaveOccurred())
+			Expect(configMap.Data[internal.ClusterFileKey]).To(Equal(fakeConnectionString))
+			Expect(cluster.Status.ConnectionString).To(Equal(fakeConnectionString))
+		})
+
+		It("should not track any process groups", func() {
+			Expect(cluster.Status.ProcessGroups).To(BeEmpty())
+		})
+	})
+
 	Describe("GetMonitorConf", func() {
 		var conf string
 		var err error
@@ -3009,7 +3243,7 @@ var _ = Describe("cluster_controller", func() {
 
 		Context("with a test process group", func() {
 			BeforeEach(func() {
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassTest, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassTest, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3036,7 +3270,7 @@ var _ = Describe("cluster_controller", func() {
 
 		Context("with a basic storage process group", func() {
 			BeforeEach(func() {
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3064,7 +3298,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with a basic storage process group with multiple storage servers per Pod", func() {
 			BeforeEach(func() {
 				cluster.Spec.StorageServersPerPod = 2
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3107,7 +3341,7 @@ var _ = Describe("cluster_controller", func() {
 			BeforeEach(func() {
 				source := fdbv1beta2.PublicIPSourcePod
 				cluster.Spec.Routing.PublicIPSource = &source
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3137,7 +3371,7 @@ var _ = Describe("cluster_controller", func() {
 				source := fdbv1beta2.PublicIPSourceService
 				cluster.Spec.Routing.PublicIPSource = &source
 				cluster.Status.HasListenIPsForAllPods = true
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3165,7 +3399,7 @@ var _ = Describe("cluster_controller", func() {
 			Context("with pods without the listen IP environment variable", func() {
 				BeforeEach(func() {
 					cluster.Status.HasListenIPsForAllPods = false
-					conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+					conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -3196,7 +3430,7 @@ var _ = Describe("cluster_controller", func() {
 				cluster.Spec.MainContainer.EnableTLS = true
 				cluster.Status.RequiredAddresses.NonTLS = false
 				cluster.Status.RequiredAddresses.TLS = true
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3227,7 +3461,7 @@ var _ = Describe("cluster_controller", func() {
 				cluster.Status.RequiredAddresses.NonTLS = true
 				cluster.Status.RequiredAddresses.TLS = true
 
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3258,7 +3492,7 @@ var _ = Describe("cluster_controller", func() {
 				cluster.Status.RequiredAddresses.NonTLS = true
 				cluster.Status.RequiredAddresses.TLS = true
 
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3289,7 +3523,7 @@ var _ = Describe("cluster_controller", func() {
 					cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{fdbv1beta2.ProcessClassGeneral: {CustomParameters: fdbv1beta2.FoundationDBCustomParameters{
 						"knob_disable_posix_kernel_aio = 1",
 					}}}
-					conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+					conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -3328,7 +3562,7 @@ var _ = Describe("cluster_controller", func() {
 							"knob_test = test2",
 						}},
 					}
-					conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+					conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -3361,7 +3595,7 @@ var _ = Describe("cluster_controller", func() {
 					Key:       "rack",
 					ValueFrom: "$RACK",
 				}
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3390,7 +3624,7 @@ var _ = Describe("cluster_controller", func() {
 			BeforeEach(func() {
 				cluster.Spec.Version = fdbv1beta2.Versions.Default.String()
 				cluster.Status.RunningVersion = fdbv1beta2.Versions.Default.String()
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 
 			})
@@ -3419,7 +3653,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with peer verification rules", func() {
 			BeforeEach(func() {
 				cluster.Spec.MainContainer.PeerVerificationRules = "S.CN=foundationdb.org"
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3448,7 +3682,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with a custom log group", func() {
 			BeforeEach(func() {
 				cluster.Spec.LogGroup = "test-fdb-cluster"
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3476,7 +3710,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with a data center", func() {
 			BeforeEach(func() {
 				cluster.Spec.DataCenter = "dc01"
-				conf, err = internal.GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3621,6 +3855,189 @@ var _ = Describe("cluster_controller", func() {
 		})
 	})
 
+	Describe("getting the pod client", func() {
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})).NotTo(HaveOccurred())
+
+			settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			settings.AdditionalContainers = []corev1.Container{{Name: "log-agent"}}
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+
+			var err error
+			pod, err = internal.GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("an additional container is not ready", func() {
+			It("should not return a pod client", func() {
+				podClient, message := clusterReconciler.getPodClient(cluster, pod)
+				Expect(podClient).To(BeNil())
+				Expect(message).To(Equal(fmt.Sprintf("Waiting for additional containers [log-agent] of pod %s to be ready", pod.Name)))
+			})
+		})
+
+		When("all additional containers are ready", func() {
+			BeforeEach(func() {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "log-agent", Ready: true}}
+			})
+
+			It("should return a pod client", func() {
+				podClient, message := clusterReconciler.getPodClient(cluster, pod)
+				Expect(podClient).NotTo(BeNil())
+				Expect(message).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("updating the cluster status", func() {
+		var staleCluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			staleCluster = cluster.DeepCopy()
+			clusterStatuses.observe(staleCluster)
+		})
+
+		When("the spec was changed by the scale subresource", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 5
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+				staleCluster.Status.Health.DataMovementPriority = 42
+				Expect(clusterReconciler.updateOrApply(context.TODO(), staleCluster)).NotTo(HaveOccurred())
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should update the status without reverting the spec", func() {
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+				Expect(cluster.Status.Health.DataMovementPriority).To(Equal(42))
+				Expect(staleCluster.ResourceVersion).To(Equal(cluster.ResourceVersion))
+			})
+		})
+
+		When("the status was changed by another writer", func() {
+			BeforeEach(func() {
+				cluster.Status.Health.Available = false
+				Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			When("the changes of both writers don't overlap", func() {
+				BeforeEach(func() {
+					staleCluster.Status.Health.DataMovementPriority = 42
+					Expect(clusterReconciler.updateOrApply(context.TODO(), staleCluster)).NotTo(HaveOccurred())
+					_, err := reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should merge the status into the latest version", func() {
+					Expect(cluster.Status.Health.DataMovementPriority).To(Equal(42))
+					Expect(cluster.Status.Health.Available).To(BeFalse())
+					Expect(staleCluster.Status.Health.Available).To(BeFalse())
+					Expect(staleCluster.ResourceVersion).To(Equal(cluster.ResourceVersion))
+				})
+			})
+
+			When("the status would roll back a newer condition", func() {
+				var err error
+
+				BeforeEach(func() {
+					processGroupID := cluster.Status.ProcessGroups[0].ProcessGroupID
+					cluster.Status.ProcessGroups[0].ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+						{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 200},
+					}
+					Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+					for _, processGroup := range staleCluster.Status.ProcessGroups {
+						if processGroup.ProcessGroupID != processGroupID {
+							continue
+						}
+
+						processGroup.ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+							{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 100},
+						}
+					}
+					staleCluster.Status.Health.DataMovementPriority = 42
+
+					err = clusterReconciler.updateOrApply(context.TODO(), staleCluster)
+				})
+
+				It("should reject the update with a conflict", func() {
+					Expect(k8serrors.IsConflict(err)).To(BeTrue())
+					_, err = reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Status.Health.DataMovementPriority).NotTo(Equal(42))
+					Expect(cluster.Status.ProcessGroups[0].ProcessGroupConditions[0].Timestamp).To(BeNumerically("==", 200))
+				})
+			})
+		})
+	})
+
+	Describe("getting the superseded context", func() {
+		var supersededCtx context.Context
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			interval := supersededCheckInterval
+			supersededCheckInterval = 10 * time.Millisecond
+			DeferCleanup(func() {
+				supersededCheckInterval = interval
+			})
+
+			var cancel context.CancelFunc
+			supersededCtx, cancel = clusterReconciler.getSupersededContext(context.TODO(), cluster, log)
+			DeferCleanup(cancel)
+		})
+
+		When("the cluster is not changed", func() {
+			It("should not cancel the context", func() {
+				Consistently(supersededCtx.Err).WithTimeout(100 * time.Millisecond).Should(BeNil())
+			})
+		})
+
+		When("the cluster spec is changed", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 5
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should cancel the context", func() {
+				Eventually(supersededCtx.Err).WithTimeout(time.Second).Should(MatchError(context.Canceled))
+			})
+		})
+
+		When("only the cluster status is changed", func() {
+			BeforeEach(func() {
+				cluster.Status.Health.DataMovementPriority = 42
+				Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should not cancel the context", func() {
+				Consistently(supersededCtx.Err).WithTimeout(100 * time.Millisecond).Should(BeNil())
+			})
+		})
+
+		When("the cluster is deleted", func() {
+			BeforeEach(func() {
+				Expect(k8sClient.Delete(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should cancel the context", func() {
+				Eventually(supersededCtx.Err).WithTimeout(time.Second).Should(MatchError(context.Canceled))
+			})
+		})
+	})
+
 	Describe("GetPublicIPs", func() {
 		var pod *corev1.Pod
 
@@ -3701,6 +4118,40 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with the node as public IP source", func() {
+			BeforeEach(func() {
+				var err error
+				source := fdbv1beta2.PublicIPSourceNode
+				cluster.Spec.Routing.PublicIPSource = &source
+				pod, err = internal.GetPod(cluster, "storage", 1)
+				Expect(err).NotTo(HaveOccurred())
+				pod.Status.PodIP = "1.1.1.1"
+				pod.Status.HostIP = "10.1.1.1"
+			})
+
+			It("should be the IP of the node", func() {
+				result := podmanager.GetPublicIPs(pod, log)
+				Expect(result).To(Equal([]string{"10.1.1.1"}))
+			})
+		})
+
+		Context("with the load balancer as public IP source", func() {
+			BeforeEach(func() {
+				var err error
+				source := fdbv1beta2.PublicIPSourceLoadBalancer
+				cluster.Spec.Routing.PublicIPSource = &source
+				pod, err = internal.GetPod(cluster, "storage", 1)
+				Expect(err).NotTo(HaveOccurred())
+				pod.Status.PodIP = "1.1.1.1"
+				pod.Annotations[fdbv1beta2.PublicIPAnnotation] = "172.16.1.1"
+			})
+
+			It("should be the IP from the annotation", func() {
+				result := podmanager.GetPublicIPs(pod, log)
+				Expect(result).To(Equal([]string{"172.16.1.1"}))
+			})
+		})
+
 		Context("with no pod", func() {
 			It("should be empty", func() {
 				result := podmanager.GetPublicIPs(nil, log)
@@ -3721,7 +4172,7 @@ func getProcessClassMap(cluster *fdbv1beta2.FoundationDBCluster, pods []corev1.P
 
 // getConfigMapHash gets the hash of the data for a cluster's dynamic config.
 func getConfigMapHash(cluster *fdbv1beta2.FoundationDBCluster, pClass fdbv1beta2.ProcessClass, pod *corev1.Pod) (string, error) {
-	configMap, err := internal.GetConfigMap(cluster)
+	configMap, err := internal.GetConfigMap(context.TODO(), cluster)
 	if err != nil {
 		return "", err
 	}
@@ -3735,3 +4186,146 @@ func getConfigMapHash(cluster *fdbv1beta2.FoundationDBCluster, pClass fdbv1beta2
 
 	return internal.GetDynamicConfHash(configMap, pClass, imageType, serversPerPod)
 }
+
+var _ = Describe("cluster_controller predicates", func() {
+	var oldObject, newObject client.Object
+
+	When("a Pod is updated", func() {
+		BeforeEach(func() {
+			oldObject = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "storage-1", Namespace: "my-ns"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					PodIP: "1.1.1.1",
+				},
+			}
+			newObject = oldObject.DeepCopyObject().(client.Object)
+		})
+
+		It("should ignore status heartbeats", func() {
+			newObject.(*corev1.Pod).Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastProbeTime: metav1.Now()}}
+			Expect(podChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeFalse())
+		})
+
+		It("should pass phase changes", func() {
+			newObject.(*corev1.Pod).Status.Phase = corev1.PodFailed
+			Expect(podChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeTrue())
+		})
+
+		It("should pass IP changes", func() {
+			newObject.(*corev1.Pod).Status.PodIP = "1.1.1.2"
+			Expect(podChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeTrue())
+		})
+
+		It("should pass the start of the deletion", func() {
+			newObject.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			Expect(podChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeTrue())
+		})
+	})
+
+	When("a Pod that runs a CLI command changes", func() {
+		BeforeEach(func() {
+			oldObject = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "operator-test-1-cli-abcde",
+					Namespace: "my-ns",
+					Labels:    map[string]string{fdbv1beta2.CliCommandLabel: "operator-test-1"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodPending},
+			}
+			newObject = oldObject.DeepCopyObject().(client.Object)
+			newObject.(*corev1.Pod).Status.Phase = corev1.PodSucceeded
+		})
+
+		It("should not enqueue the cluster", func() {
+			Expect(cliCommandPodPredicate.Create(event.CreateEvent{Object: oldObject})).To(BeFalse())
+			Expect(cliCommandPodPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeFalse())
+			Expect(cliCommandPodPredicate.Delete(event.DeleteEvent{Object: newObject})).To(BeFalse())
+		})
+
+		It("should pass the events of the cluster Pods", func() {
+			oldObject.SetLabels(map[string]string{fdbv1beta2.FDBClusterLabel: "operator-test-1"})
+			Expect(cliCommandPodPredicate.Create(event.CreateEvent{Object: oldObject})).To(BeTrue())
+		})
+	})
+
+	When("a PVC is updated", func() {
+		BeforeEach(func() {
+			oldObject = &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "storage-1-data", Namespace: "my-ns"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			}
+			newObject = oldObject.DeepCopyObject().(client.Object)
+		})
+
+		It("should ignore unrelated changes", func() {
+			newObject.(*corev1.PersistentVolumeClaim).Status.Conditions = []corev1.PersistentVolumeClaimCondition{{Type: corev1.PersistentVolumeClaimResizing}}
+			Expect(pvcChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeFalse())
+		})
+
+		It("should pass phase changes", func() {
+			newObject.(*corev1.PersistentVolumeClaim).Status.Phase = corev1.ClaimBound
+			Expect(pvcChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeTrue())
+		})
+	})
+
+	When("a config map is updated", func() {
+		BeforeEach(func() {
+			oldObject = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "operator-test-1-config", Namespace: "my-ns"},
+				Data:       map[string]string{"cluster-file": "test:abc@1.1.1.1:4501"},
+			}
+			newObject = oldObject.DeepCopyObject().(client.Object)
+		})
+
+		It("should ignore unchanged data", func() {
+			newObject.SetResourceVersion("2")
+			Expect(configMapChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeFalse())
+		})
+
+		It("should pass data changes", func() {
+			newObject.(*corev1.ConfigMap).Data["cluster-file"] = "test:def@1.1.1.2:4501"
+			Expect(configMapChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeTrue())
+		})
+	})
+
+	When("a service is updated", func() {
+		BeforeEach(func() {
+			oldObject = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "storage-1", Namespace: "my-ns"},
+				Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.1"},
+			}
+			newObject = oldObject.DeepCopyObject().(client.Object)
+		})
+
+		It("should ignore unchanged services", func() {
+			newObject.SetResourceVersion("2")
+			Expect(serviceChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeFalse())
+		})
+
+		It("should pass load balancer changes", func() {
+			newObject.(*corev1.Service).Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}
+			Expect(serviceChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeTrue())
+		})
+	})
+
+	When("a secret is updated", func() {
+		BeforeEach(func() {
+			oldObject = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls-password", Namespace: "my-ns"},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			}
+			newObject = oldObject.DeepCopyObject().(client.Object)
+		})
+
+		It("should ignore unchanged data", func() {
+			newObject.SetResourceVersion("2")
+			Expect(environmentSourceChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeFalse())
+		})
+
+		It("should pass data changes", func() {
+			newObject.(*corev1.Secret).Data["password"] = []byte("updated")
+			Expect(environmentSourceChangedPredicate.Update(event.UpdateEvent{ObjectOld: oldObject, ObjectNew: newObject})).To(BeTrue())
+		})
+	})
+})
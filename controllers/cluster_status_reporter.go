@@ -0,0 +1,204 @@
+/*
+ * cluster_status_reporter.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controllers
+
+import (
+	"context"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statusReporterCheckInterval defines how often the status reporter checks which status reports must be updated.
+var statusReporterCheckInterval = 5 * time.Second
+
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusterstatusreports,verbs=get;list;watch;create;update;patch;delete
+
+// ClusterStatusReporter periodically updates the FoundationDBClusterStatusReport of all clusters that have the status
+// report enabled with a snapshot of the machine-readable status.
+type ClusterStatusReporter struct {
+	reconciler *FoundationDBClusterReconciler
+	log        logr.Logger
+	// lastReports contains the time of the last update of the status report for every cluster.
+	lastReports map[types.NamespacedName]time.Time
+}
+
+// NewClusterStatusReporter creates a new ClusterStatusReporter that uses the client and database client provider of
+// the reconciler.
+func NewClusterStatusReporter(reconciler *FoundationDBClusterReconciler) *ClusterStatusReporter {
+	return &ClusterStatusReporter{
+		reconciler:  reconciler,
+		log:         reconciler.Log.WithName("ClusterStatusReporter"),
+		lastReports: map[types.NamespacedName]time.Time{},
+	}
+}
+
+// Start implements the manager.Runnable interface and updates the status reports until the context is done.
+func (reporter *ClusterStatusReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(statusReporterCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		reporter.reportClusters(ctx, time.Now())
+	}
+}
+
+// reportClusters updates the status reports of all clusters that have the status report enabled and where the status
+// report interval has passed since the last update.
+func (reporter *ClusterStatusReporter) reportClusters(ctx context.Context, now time.Time) {
+	clusters := &fdbv1beta2.FoundationDBClusterList{}
+	err := reporter.reconciler.List(ctx, clusters)
+	if err != nil {
+		reporter.log.Error(err, "could not list clusters")
+		return
+	}
+
+	reportedClusters := make(map[types.NamespacedName]time.Time, len(clusters.Items))
+	disabledClusters := map[types.NamespacedName]fdbv1beta2.None{}
+	for idx := range clusters.Items {
+		cluster := &clusters.Items[idx]
+		// In dry-run mode the operator must not create, update or delete resources.
+		if cluster.Spec.Skip || reporter.reconciler.isDryRun(cluster) {
+			continue
+		}
+
+		if !cluster.StatusReportEnabled() {
+			disabledClusters[client.ObjectKeyFromObject(cluster)] = fdbv1beta2.None{}
+			continue
+		}
+
+		// The database doesn't exist before the cluster is configured.
+		if !cluster.Status.Configured {
+			continue
+		}
+
+		key := client.ObjectKeyFromObject(cluster)
+		lastReport, ok := reporter.lastReports[key]
+		if ok && now.Sub(lastReport) < cluster.GetStatusReportInterval() {
+			reportedClusters[key] = lastReport
+			continue
+		}
+
+		reportedClusters[key] = now
+		err = reporter.reportCluster(ctx, cluster, now)
+		if err != nil {
+			reporter.log.Error(err, "could not update status report", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		}
+	}
+
+	// Clusters that were deleted or that have the status report disabled are dropped.
+	reporter.lastReports = reportedClusters
+	reporter.deleteDisabledReports(ctx, disabledClusters)
+}
+
+// deleteDisabledReports deletes the status reports of the clusters that have the status report disabled. The reports
+// of deleted clusters are removed by the garbage collection, as they are owned by the cluster.
+func (reporter *ClusterStatusReporter) deleteDisabledReports(ctx context.Context, disabledClusters map[types.NamespacedName]fdbv1beta2.None) {
+	if len(disabledClusters) == 0 {
+		return
+	}
+
+	reports := &fdbv1beta2.FoundationDBClusterStatusReportList{}
+	err := reporter.reconciler.List(ctx, reports)
+	if err != nil {
+		// The CRD for the status reports is only required if the status report is enabled for any cluster.
+		if !meta.IsNoMatchError(err) {
+			reporter.log.Error(err, "could not list status reports")
+		}
+
+		return
+	}
+
+	for idx := range reports.Items {
+		report := &reports.Items[idx]
+		if _, ok := disabledClusters[client.ObjectKeyFromObject(report)]; !ok {
+			continue
+		}
+
+		reporter.log.Info("Deleting status report of cluster with disabled status report", "namespace", report.Namespace, "cluster", report.Name)
+		err = reporter.reconciler.Delete(ctx, report)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			reporter.log.Error(err, "could not delete status report", "namespace", report.Namespace, "cluster", report.Name)
+		}
+	}
+}
+
+// reportCluster fetches the machine-readable status of the cluster and creates or updates the status report of the
+// cluster. If the status can't be fetched, the error is recorded in the report and the last snapshot is kept.
+func (reporter *ClusterStatusReporter) reportCluster(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, now time.Time) error {
+	report := &fdbv1beta2.FoundationDBClusterStatusReport{}
+	err := reporter.reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), report)
+	exists := err == nil
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if !exists {
+		report.ObjectMeta = metav1.ObjectMeta{
+			Name:            cluster.Name,
+			Namespace:       cluster.Namespace,
+			Labels:          cluster.GetMatchLabels(),
+			OwnerReferences: internal.BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta),
+		}
+	}
+
+	status, err := reporter.getStatus(ctx, cluster)
+	if err != nil {
+		report.Status.Error = internal.RedactString(err.Error())
+	} else {
+		err = report.SetDatabaseStatus(status, metav1.NewTime(now))
+		if err != nil {
+			return err
+		}
+
+		// The status contains the command lines of the processes, which can contain TLS passwords and blob credentials.
+		report.Status.DatabaseStatus.Raw = []byte(internal.RedactString(string(report.Status.DatabaseStatus.Raw)))
+	}
+
+	if !exists {
+		return reporter.reconciler.Create(ctx, report)
+	}
+
+	return reporter.reconciler.Update(ctx, report)
+}
+
+// getStatus fetches the machine-readable status of the cluster.
+func (reporter *ClusterStatusReporter) getStatus(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) (*fdbv1beta2.FoundationDBStatus, error) {
+	adminClient, err := reporter.reconciler.getDatabaseClientProvider().GetAdminClient(cluster, reporter.reconciler)
+	if err != nil {
+		return nil, err
+	}
+	defer adminClient.Close()
+
+	return adminClient.GetStatus(ctx)
+}
@@ -0,0 +1,179 @@
+/*
+ * cluster_status_reporter_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("cluster_status_reporter", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var reporter *ClusterStatusReporter
+	var now time.Time
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		var err error
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		reporter = NewClusterStatusReporter(clusterReconciler)
+		now = time.Now().Truncate(time.Second)
+	})
+
+	JustBeforeEach(func() {
+		Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+		reporter.reportClusters(context.TODO(), now)
+	})
+
+	getReport := func() (*fdbv1beta2.FoundationDBClusterStatusReport, error) {
+		report := &fdbv1beta2.FoundationDBClusterStatusReport{}
+		err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), report)
+		return report, err
+	}
+
+	When("the status report is disabled", func() {
+		It("should not create a status report", func() {
+			_, err := getReport()
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			Expect(reporter.lastReports).To(BeEmpty())
+		})
+	})
+
+	When("the status report is enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.StatusReportOptions.Enabled = pointer.Bool(true)
+		})
+
+		It("should create the status report with a snapshot of the status", func() {
+			report, err := getReport()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.OwnerReferences).To(HaveLen(1))
+			Expect(report.OwnerReferences[0].UID).To(Equal(cluster.UID))
+			Expect(report.Status.LastUpdated.Time).To(BeTemporally("==", now))
+			Expect(report.Status.Error).To(BeEmpty())
+			Expect(report.Status.Available).To(BeTrue())
+			Expect(report.Status.FullReplication).To(BeTrue())
+
+			status, err := report.GetDatabaseStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).NotTo(BeNil())
+			Expect(status.Cluster.Processes).NotTo(BeEmpty())
+			Expect(reporter.lastReports).To(HaveKeyWithValue(client.ObjectKeyFromObject(cluster), now))
+		})
+
+		When("the interval has not passed since the last update", func() {
+			It("should not update the status report", func() {
+				reporter.reportClusters(context.TODO(), now.Add(10*time.Second))
+				report, err := getReport()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report.Status.LastUpdated.Time).To(BeTemporally("==", now))
+			})
+		})
+
+		When("the interval has passed since the last update", func() {
+			It("should update the status report", func() {
+				adminClient.FrozenStatus = &fdbv1beta2.FoundationDBStatus{
+					Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+						Data: fdbv1beta2.FoundationDBStatusDataStatistics{
+							State: fdbv1beta2.FoundationDBStatusDataState{
+								Name: "healing",
+							},
+						},
+					},
+				}
+
+				next := now.Add(cluster.GetStatusReportInterval())
+				reporter.reportClusters(context.TODO(), next)
+				report, err := getReport()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report.Status.LastUpdated.Time).To(BeTemporally("==", next))
+				Expect(report.Status.Available).To(BeFalse())
+				Expect(report.Status.DataState).To(Equal("healing"))
+			})
+		})
+
+		When("the status contains credentials", func() {
+			It("should redact the credentials in the snapshot", func() {
+				adminClient.FrozenStatus = &fdbv1beta2.FoundationDBStatus{
+					Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+						Layers: fdbv1beta2.FoundationDBStatusLayerInfo{
+							Backup: fdbv1beta2.FoundationDBStatusBackupInfo{
+								Tags: map[string]fdbv1beta2.FoundationDBStatusBackupTag{
+									"default": {CurrentContainer: "blobstore://AKIA:abc/def@blob.example.com/backup"},
+								},
+							},
+						},
+					},
+				}
+
+				reporter.reportClusters(context.TODO(), now.Add(cluster.GetStatusReportInterval()))
+				report, err := getReport()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(report.Status.DatabaseStatus.Raw)).NotTo(ContainSubstring("abc/def"))
+
+				status, err := report.GetDatabaseStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Cluster.Layers.Backup.Tags["default"].CurrentContainer).To(Equal("blobstore://AKIA:<redacted>@blob.example.com/backup"))
+			})
+		})
+
+		When("the status report is disabled afterwards", func() {
+			It("should delete the status report", func() {
+				_, err := getReport()
+				Expect(err).NotTo(HaveOccurred())
+
+				cluster.Spec.AutomationOptions.StatusReportOptions.Enabled = pointer.Bool(false)
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+				reporter.reportClusters(context.TODO(), now.Add(10*time.Second))
+
+				_, err = getReport()
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				Expect(reporter.lastReports).To(BeEmpty())
+			})
+		})
+
+		When("the cluster is in dry-run mode", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DryRun = pointer.Bool(true)
+			})
+
+			It("should not create a status report", func() {
+				_, err := getReport()
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+})
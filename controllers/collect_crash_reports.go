@@ -0,0 +1,157 @@
+/*
+ * collect_crash_reports.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// collectCrashReports provides a reconciliation step for recording the metadata of crashed containers in the
+// cluster status.
+type collectCrashReports struct{}
+
+// reconcile runs the reconciler's work.
+func (collectCrashReports) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if cluster.Spec.CrashCollection == nil {
+		if len(cluster.Status.CrashReports) == 0 {
+			return nil
+		}
+
+		cluster.Status.CrashReports = nil
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "collectCrashReports")
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	knownReports := make(map[string]fdbv1beta2.None, len(cluster.Status.CrashReports))
+	for _, report := range cluster.Status.CrashReports {
+		knownReports[getCrashReportKey(report)] = fdbv1beta2.None{}
+	}
+
+	reports := append([]fdbv1beta2.CrashReport{}, cluster.Status.CrashReports...)
+	for _, pod := range pods {
+		for _, report := range getCrashReports(cluster, pod) {
+			if _, ok := knownReports[getCrashReportKey(report)]; ok {
+				continue
+			}
+
+			logger.Info("Detected crashed container", "processGroupID", report.ProcessGroupID, "pod", report.PodName, "container", report.ContainerName, "exitCode", report.ExitCode, "reason", report.Reason, "location", report.Location)
+			message := fmt.Sprintf("Container %s of Pod %s terminated with exit code %d (%s)", report.ContainerName, report.PodName, report.ExitCode, report.Reason)
+			if report.Location != "" {
+				message += fmt.Sprintf(", crash artifacts are stored in %s", report.Location)
+			}
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "ProcessCrashed", message)
+
+			knownReports[getCrashReportKey(report)] = fdbv1beta2.None{}
+			reports = append(reports, report)
+		}
+	}
+
+	reports = pruneCrashReports(reports, cluster.GetMaxCrashReports(), cluster.GetCrashReportRetention())
+	if equality.Semantic.DeepEqual(reports, cluster.Status.CrashReports) {
+		return nil
+	}
+
+	cluster.Status.CrashReports = reports
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// getCrashReports returns the crash reports for all containers of the Pod that were terminated with a non-zero
+// exit code.
+func getCrashReports(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) []fdbv1beta2.CrashReport {
+	var reports []fdbv1beta2.CrashReport
+	for _, status := range pod.Status.ContainerStatuses {
+		terminated := status.LastTerminationState.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+
+		reports = append(reports, fdbv1beta2.CrashReport{
+			ProcessGroupID: podmanager.GetProcessGroupID(cluster, pod),
+			PodName:        pod.Name,
+			ContainerName:  status.Name,
+			ExitCode:       terminated.ExitCode,
+			Signal:         terminated.Signal,
+			Reason:         terminated.Reason,
+			Timestamp:      terminated.FinishedAt,
+			Location:       internal.GetCrashCollectionLocation(cluster, pod.Name),
+		})
+	}
+
+	return reports
+}
+
+// getCrashReportKey returns a key that identifies a single crash of a container.
+func getCrashReportKey(report fdbv1beta2.CrashReport) string {
+	return fmt.Sprintf("%s/%s/%d", report.PodName, report.ContainerName, report.Timestamp.Unix())
+}
+
+// pruneCrashReports removes the crash reports that are older than the retention and keeps at most maxReports
+// of the most recent crash reports, sorted by their timestamp.
+func pruneCrashReports(reports []fdbv1beta2.CrashReport, maxReports int, retention time.Duration) []fdbv1beta2.CrashReport {
+	cutoff := time.Now().Add(-retention)
+	pruned := make([]fdbv1beta2.CrashReport, 0, len(reports))
+	for _, report := range reports {
+		if report.Timestamp.Time.Before(cutoff) {
+			continue
+		}
+
+		pruned = append(pruned, report)
+	}
+
+	sort.SliceStable(pruned, func(i, j int) bool {
+		return pruned[i].Timestamp.Before(&pruned[j].Timestamp)
+	})
+
+	if len(pruned) > maxReports {
+		pruned = pruned[len(pruned)-maxReports:]
+	}
+
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	return pruned
+}
@@ -0,0 +1,184 @@
+/*
+ * collect_crash_reports_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("collect_crash_reports", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var result *requeue
+	var finishedAt metav1.Time
+
+	getCrashEvents := func() []corev1.Event {
+		events := &corev1.EventList{}
+		Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+		var matchingEvents []corev1.Event
+		for _, event := range events.Items {
+			if event.InvolvedObject.UID == cluster.ObjectMeta.UID && event.Reason == "ProcessCrashed" {
+				matchingEvents = append(matchingEvents, event)
+			}
+		}
+
+		return matchingEvents
+	}
+
+	crashContainer := func(podName string, containerName string, exitCode int32, timestamp metav1.Time) {
+		pod := &corev1.Pod{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: podName}, pod)).NotTo(HaveOccurred())
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name:         containerName,
+				RestartCount: 1,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode:   exitCode,
+						Reason:     "Error",
+						FinishedAt: timestamp,
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Status().Update(context.TODO(), pod)).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.CrashCollection = &fdbv1beta2.CrashCollectionSpec{
+			VolumeClaimName: "crash-dumps",
+		}
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+		finishedAt = metav1.NewTime(time.Now().Add(-1 * time.Minute).Truncate(time.Second))
+	})
+
+	JustBeforeEach(func() {
+		result = collectCrashReports{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		_, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("no container has crashed", func() {
+		It("should not report any crashes", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Status.CrashReports).To(BeEmpty())
+			Expect(getCrashEvents()).To(BeEmpty())
+		})
+	})
+
+	When("the main container of a Pod has crashed", func() {
+		BeforeEach(func() {
+			crashContainer("operator-test-1-storage-1", fdbv1beta2.MainContainerName, 139, finishedAt)
+		})
+
+		It("should report the crash", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Status.CrashReports).To(HaveLen(1))
+			report := cluster.Status.CrashReports[0]
+			Expect(report.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+			Expect(report.PodName).To(Equal("operator-test-1-storage-1"))
+			Expect(report.ContainerName).To(Equal(fdbv1beta2.MainContainerName))
+			Expect(report.ExitCode).To(BeNumerically("==", 139))
+			Expect(report.Location).To(Equal("crash-dumps:/operator-test-1-storage-1"))
+		})
+
+		It("should emit an event with the location", func() {
+			events := getCrashEvents()
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Type).To(Equal(corev1.EventTypeWarning))
+			Expect(events[0].Message).To(ContainSubstring("crash-dumps:/operator-test-1-storage-1"))
+		})
+
+		When("the reconciler runs again", func() {
+			JustBeforeEach(func() {
+				result = collectCrashReports{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not report the crash again", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Status.CrashReports).To(HaveLen(1))
+				Expect(getCrashEvents()).To(HaveLen(1))
+			})
+		})
+
+		When("the crash is older than the retention", func() {
+			BeforeEach(func() {
+				cluster.Spec.CrashCollection.RetentionSeconds = pointer.Int(30)
+			})
+
+			It("should not keep the crash report", func() {
+				Expect(cluster.Status.CrashReports).To(BeEmpty())
+			})
+		})
+	})
+
+	When("multiple containers have crashed", func() {
+		BeforeEach(func() {
+			cluster.Spec.CrashCollection.MaxCrashReports = pointer.Int(2)
+			crashContainer("operator-test-1-storage-1", fdbv1beta2.MainContainerName, 1, metav1.NewTime(finishedAt.Add(-2*time.Second)))
+			crashContainer("operator-test-1-storage-2", fdbv1beta2.MainContainerName, 1, metav1.NewTime(finishedAt.Add(-1*time.Second)))
+			crashContainer("operator-test-1-log-1", fdbv1beta2.SidecarContainerName, 1, finishedAt)
+		})
+
+		It("should only keep the most recent crash reports", func() {
+			Expect(cluster.Status.CrashReports).To(HaveLen(2))
+			Expect(cluster.Status.CrashReports[0].PodName).To(Equal("operator-test-1-storage-2"))
+			Expect(cluster.Status.CrashReports[1].PodName).To(Equal("operator-test-1-log-1"))
+		})
+	})
+
+	When("a container exited successfully", func() {
+		BeforeEach(func() {
+			crashContainer("operator-test-1-storage-1", fdbv1beta2.MainContainerName, 0, finishedAt)
+		})
+
+		It("should not report a crash", func() {
+			Expect(cluster.Status.CrashReports).To(BeEmpty())
+		})
+	})
+
+	When("the crash collection is disabled", func() {
+		BeforeEach(func() {
+			cluster.Status.CrashReports = []fdbv1beta2.CrashReport{{PodName: "operator-test-1-storage-1", Timestamp: finishedAt}}
+			Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			cluster.Spec.CrashCollection = nil
+		})
+
+		It("should remove the crash reports", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Status.CrashReports).To(BeEmpty())
+		})
+	})
+})
@@ -93,9 +93,24 @@ type requeue struct {
 	delayedRequeue bool
 }
 
+// namedSubReconciler describes a sub-reconciler that is not identified by its type, e.g. a custom reconciliation step.
+type namedSubReconciler interface {
+	// name returns the name of the sub-reconciler.
+	name() string
+}
+
+// getSubReconcilerName returns the name of the sub-reconciler that is used in the logs and in the cluster status.
+func getSubReconcilerName(subReconciler interface{}) string {
+	if named, ok := subReconciler.(namedSubReconciler); ok {
+		return named.name()
+	}
+
+	return fmt.Sprintf("%T", subReconciler)
+}
+
 // processRequeue interprets a requeue result from a subreconciler.
 func processRequeue(requeue *requeue, subReconciler interface{}, object runtime.Object, recorder record.EventRecorder, logger logr.Logger) (ctrl.Result, error) {
-	curLog := logger.WithValues("subReconciler", fmt.Sprintf("%T", subReconciler), "requeueAfter", requeue.delay)
+	curLog := logger.WithValues("subReconciler", getSubReconcilerName(subReconciler), "requeueAfter", requeue.delay)
 	if requeue.message == "" && requeue.curError != nil {
 		requeue.message = requeue.curError.Error()
 	}
@@ -0,0 +1,105 @@
+/*
+ * create_restore_destination_cluster.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// restoreDestinationClusterRequeueDelay defines how long the operator waits before checking the destination cluster again.
+const restoreDestinationClusterRequeueDelay = 15 * time.Second
+
+// createRestoreDestinationCluster provides a reconciliation step for creating the destination cluster of a restore
+// from the destination cluster template.
+type createRestoreDestinationCluster struct{}
+
+// reconcile runs the reconciler's work.
+func (c createRestoreDestinationCluster) reconcile(ctx context.Context, r *FoundationDBRestoreReconciler, restore *fdbv1beta2.FoundationDBRestore) *requeue {
+	if !restore.UsesDestinationClusterTemplate() || restore.Status.Running || restore.Status.Phase == fdbv1beta2.RestorePhaseCompleted || restore.Status.Phase == fdbv1beta2.RestorePhaseFailed {
+		return nil
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, cluster)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return &requeue{curError: err}
+		}
+
+		cluster = newRestoreDestinationCluster(restore)
+		err = cluster.Validate()
+		if err != nil {
+			r.Recorder.Event(restore, corev1.EventTypeWarning, "DestinationClusterTemplate not valid", err.Error())
+			return &requeue{curError: fmt.Errorf("DestinationClusterTemplate is not valid: %w", err)}
+		}
+
+		err = r.Create(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		r.Recorder.Event(restore, corev1.EventTypeNormal, "DestinationClusterCreated", fmt.Sprintf("Created destination cluster %s", cluster.Name))
+	}
+
+	if restore.Status.Phase != fdbv1beta2.RestorePhaseCreatingCluster {
+		restore.Status.Phase = fdbv1beta2.RestorePhaseCreatingCluster
+		err = r.updateOrApply(ctx, restore)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if !cluster.Status.Configured || !cluster.Status.Health.Available {
+		return &requeue{
+			message: fmt.Sprintf("Waiting for destination cluster %s to become available", cluster.Name),
+			delay:   restoreDestinationClusterRequeueDelay,
+		}
+	}
+
+	return nil
+}
+
+// newRestoreDestinationCluster creates the destination cluster of the restore from the destination cluster template.
+func newRestoreDestinationCluster(restore *fdbv1beta2.FoundationDBRestore) *fdbv1beta2.FoundationDBCluster {
+	template := restore.Spec.DestinationClusterTemplate
+	cluster := &fdbv1beta2.FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: restore.Namespace,
+			Name:      restore.Spec.DestinationClusterName,
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+
+	if template.Metadata != nil {
+		cluster.ObjectMeta.Labels = template.Metadata.Labels
+		cluster.ObjectMeta.Annotations = template.Metadata.Annotations
+	}
+
+	return cluster
+}
@@ -0,0 +1,164 @@
+/*
+ * decommission_fault_domains.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// decommissionFaultDomains marks the process groups that are running in a fault domain that should be
+// decommissioned for removal. The process groups are marked in batches to make sure the cluster keeps its
+// fault tolerance during the decommissioning.
+type decommissionFaultDomains struct{}
+
+// reconcile runs the reconciler's work.
+func (c decommissionFaultDomains) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if len(cluster.Spec.FaultDomainsToDecommission) == 0 || !cluster.Status.Configured {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "decommissionFaultDomains")
+
+	// A data center can only be decommissioned once the database configuration doesn't reference it anymore,
+	// otherwise the operator would remove the processes of a data center that is still in use.
+	for _, faultDomain := range cluster.Spec.FaultDomainsToDecommission {
+		if faultDomain.Key != fdbv1beta2.FDBLocalityDCIDKey {
+			continue
+		}
+
+		if dataCenterIsConfigured(cluster.Status.DatabaseConfiguration, faultDomain.Value) {
+			return &requeue{message: fmt.Sprintf("data center %s is still part of the database configuration", faultDomain.Value), delayedRequeue: true}
+		}
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus(ctx)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	processGroups := make(map[fdbv1beta2.ProcessGroupID]*fdbv1beta2.ProcessGroupStatus, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processGroups[processGroup.ProcessGroupID] = processGroup
+	}
+
+	candidates := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	pendingRemovals := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	remainingZones := map[string]fdbv1beta2.None{}
+	for _, process := range status.Cluster.Processes {
+		processGroupID := fdbv1beta2.ProcessGroupID(process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])
+		processGroup, ok := processGroups[processGroupID]
+		if !ok || process.ProcessClass == fdbv1beta2.ProcessClassTest {
+			continue
+		}
+
+		if !cluster.IsFaultDomainDecommissioned(process.Locality) {
+			if !processGroup.IsMarkedForRemoval() {
+				remainingZones[process.Locality[fdbv1beta2.FDBLocalityZoneIDKey]] = fdbv1beta2.None{}
+			}
+
+			continue
+		}
+
+		if processGroup.IsMarkedForRemoval() {
+			pendingRemovals[processGroupID] = fdbv1beta2.None{}
+			continue
+		}
+
+		candidates[processGroupID] = fdbv1beta2.None{}
+	}
+
+	if len(candidates) == 0 {
+		if len(pendingRemovals) > 0 {
+			logger.V(1).Info("Waiting for process groups of decommissioned fault domains to be removed", "pendingRemovals", len(pendingRemovals))
+		}
+
+		return nil
+	}
+
+	if req := checkFaultTolerance(logger, cluster, status, "decommissioning fault domains"); req != nil {
+		return req
+	}
+
+	// Make sure that the remaining fault domains are enough to hold all the replicas.
+	requiredZones := cluster.MinimumFaultDomains() + cluster.DesiredFaultTolerance()
+	if len(remainingZones) < requiredZones {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "DecommissionBlocked", fmt.Sprintf("Only %d fault domains would remain, but %d are required", len(remainingZones), requiredZones))
+		return &requeue{message: fmt.Sprintf("not enough fault domains remaining to decommission fault domains, remaining: %d, required: %d", len(remainingZones), requiredZones), delayedRequeue: true}
+	}
+
+	batchSize := cluster.GetDecommissionBatchSize() - len(pendingRemovals)
+	if batchSize <= 0 {
+		logger.V(1).Info("Waiting for the current batch to be removed", "pendingRemovals", len(pendingRemovals))
+		return nil
+	}
+
+	processGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0, len(candidates))
+	for processGroupID := range candidates {
+		processGroupIDs = append(processGroupIDs, processGroupID)
+	}
+
+	sort.Slice(processGroupIDs, func(i, j int) bool {
+		return processGroupIDs[i] < processGroupIDs[j]
+	})
+
+	if len(processGroupIDs) > batchSize {
+		processGroupIDs = processGroupIDs[:batchSize]
+	}
+
+	for _, processGroupID := range processGroupIDs {
+		processGroups[processGroupID].MarkForRemoval()
+	}
+
+	logger.Info("Marking process groups of decommissioned fault domains for removal", "processGroupIDs", processGroupIDs, "remaining", len(candidates)-len(processGroupIDs))
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "DecommissioningFaultDomain", fmt.Sprintf("Marked process groups %v for removal", processGroupIDs))
+	r.recordAction(cluster, fmt.Sprintf("marked process groups %v for removal", processGroupIDs), "fault domains are decommissioned")
+
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return &requeue{message: "Removals have been updated in the cluster status"}
+}
+
+// dataCenterIsConfigured returns true if the provided data center is part of the region configuration.
+func dataCenterIsConfigured(configuration fdbv1beta2.DatabaseConfiguration, dataCenterID string) bool {
+	for _, region := range configuration.Regions {
+		for _, dataCenter := range region.DataCenters {
+			if dataCenter.ID == dataCenterID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,165 @@
+/*
+ * decommission_fault_domains_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("decommission_fault_domains", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var result *requeue
+
+	getMarkedProcessGroups := func() []fdbv1beta2.ProcessGroupID {
+		var marked []fdbv1beta2.ProcessGroupID
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			if processGroup.IsMarkedForRemoval() {
+				marked = append(marked, processGroup.ProcessGroupID)
+			}
+		}
+
+		return marked
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		_, err = reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, processGroupID := range []fdbv1beta2.ProcessGroupID{"storage-1", "storage-2"} {
+			adminClient.MockLocalityInfo(processGroupID, map[string]string{
+				fdbv1beta2.FDBLocalityZoneIDKey: "zone-a",
+			})
+		}
+	})
+
+	JustBeforeEach(func() {
+		result = decommissionFaultDomains{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no fault domain should be decommissioned", func() {
+		It("should not mark any process group for removal", func() {
+			Expect(result).To(BeNil())
+			Expect(getMarkedProcessGroups()).To(BeEmpty())
+		})
+	})
+
+	When("a zone should be decommissioned", func() {
+		BeforeEach(func() {
+			cluster.Spec.FaultDomainsToDecommission = []fdbv1beta2.FaultDomainToDecommission{
+				{
+					Key:   fdbv1beta2.FDBLocalityZoneIDKey,
+					Value: "zone-a",
+				},
+			}
+		})
+
+		It("should mark the first process group of the zone for removal", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.message).To(Equal("Removals have been updated in the cluster status"))
+			Expect(getMarkedProcessGroups()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-1")))
+		})
+
+		When("the reconciler runs again", func() {
+			JustBeforeEach(func() {
+				result = decommissionFaultDomains{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should wait until the current batch is removed", func() {
+				Expect(result).To(BeNil())
+				Expect(getMarkedProcessGroups()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-1")))
+			})
+		})
+
+		When("the batch size is increased", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DecommissionBatchSize = pointer.Int(2)
+			})
+
+			It("should mark all process groups of the zone for removal", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(getMarkedProcessGroups()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-1"), fdbv1beta2.ProcessGroupID("storage-2")))
+			})
+		})
+
+		When("not enough fault domains would remain", func() {
+			BeforeEach(func() {
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					adminClient.MockLocalityInfo(processGroup.ProcessGroupID, map[string]string{
+						fdbv1beta2.FDBLocalityZoneIDKey: "zone-a",
+					})
+				}
+			})
+
+			It("should not mark any process group for removal", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.message).To(Equal("not enough fault domains remaining to decommission fault domains, remaining: 0, required: 3"))
+				Expect(getMarkedProcessGroups()).To(BeEmpty())
+			})
+		})
+	})
+
+	When("a data center should be decommissioned that is still configured", func() {
+		BeforeEach(func() {
+			cluster.Spec.FaultDomainsToDecommission = []fdbv1beta2.FaultDomainToDecommission{
+				{
+					Key:   fdbv1beta2.FDBLocalityDCIDKey,
+					Value: "dc1",
+				},
+			}
+			cluster.Status.DatabaseConfiguration.Regions = []fdbv1beta2.Region{
+				{
+					DataCenters: []fdbv1beta2.DataCenter{
+						{
+							ID: "dc1",
+						},
+					},
+				},
+			}
+		})
+
+		It("should wait until the data center is removed from the database configuration", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.delayedRequeue).To(BeTrue())
+			Expect(result.message).To(Equal("data center dc1 is still part of the database configuration"))
+			Expect(getMarkedProcessGroups()).To(BeEmpty())
+		})
+	})
+})
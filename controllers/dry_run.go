@@ -0,0 +1,379 @@
+/*
+ * dry_run.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// maxDryRunActions defines how many suppressed actions are reported in the cluster status.
+const maxDryRunActions = 100
+
+// dryRunActions collects the actions that were suppressed during a single reconciliation in dry-run mode.
+type dryRunActions struct {
+	lock     sync.Mutex
+	cluster  *fdbv1beta2.FoundationDBCluster
+	recorder record.EventRecorder
+	logger   logr.Logger
+	actions  []string
+}
+
+// record reports an action that was suppressed. Every action is only reported once per reconciliation.
+func (dryRun *dryRunActions) record(action string) {
+	dryRun.lock.Lock()
+	defer dryRun.lock.Unlock()
+
+	for _, existingAction := range dryRun.actions {
+		if existingAction == action {
+			return
+		}
+	}
+
+	if len(dryRun.actions) < maxDryRunActions {
+		dryRun.actions = append(dryRun.actions, action)
+	}
+
+	dryRun.logger.Info("Suppressed action in dry-run mode", "action", action)
+	dryRun.recorder.Event(dryRun.cluster, corev1.EventTypeNormal, "DryRun", fmt.Sprintf("Would %s", action))
+}
+
+// getActions returns the suppressed actions.
+func (dryRun *dryRunActions) getActions() []string {
+	dryRun.lock.Lock()
+	defer dryRun.lock.Unlock()
+
+	return append([]string(nil), dryRun.actions...)
+}
+
+// isDryRun returns true if the cluster should be reconciled without performing any changes.
+func (r *FoundationDBClusterReconciler) isDryRun(cluster *fdbv1beta2.FoundationDBCluster) bool {
+	return r.getRuntimeSettings().DryRun || cluster.IsDryRun()
+}
+
+// newDryRunReconciler returns a copy of the reconciler that suppresses all mutating operations against the
+// Kubernetes API and the FoundationDB cluster and records them instead.
+func (r *FoundationDBClusterReconciler) newDryRunReconciler(cluster *fdbv1beta2.FoundationDBCluster) *FoundationDBClusterReconciler {
+	actions := &dryRunActions{
+		cluster:  cluster,
+		recorder: r.Recorder,
+		logger:   log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "dryRun", true),
+	}
+
+	dryRunReconciler := *r
+	dryRunReconciler.Client = dryRunClient{Client: r.Client, actions: actions}
+	dryRunReconciler.dryRunActions = actions
+	podClientProvider := r.PodClientProvider
+	dryRunReconciler.PodClientProvider = func(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, error) {
+		podClient, err := podClientProvider(cluster, pod)
+		if err != nil {
+			return nil, err
+		}
+
+		return dryRunPodClient{FdbPodClient: podClient, pod: pod, actions: actions}, nil
+	}
+
+	return &dryRunReconciler
+}
+
+// updateDryRunActions persists the suppressed actions of the dry-run reconciler in the cluster status.
+func (r *FoundationDBClusterReconciler) updateDryRunActions(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, dryRunReconciler *FoundationDBClusterReconciler) error {
+	currentCluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(cluster), currentCluster)
+	if err != nil {
+		return err
+	}
+
+	actions := dryRunReconciler.dryRunActions.getActions()
+	if equality.Semantic.DeepEqual(currentCluster.Status.DryRunActions, actions) {
+		return nil
+	}
+
+	currentCluster.Status.DryRunActions = actions
+	return r.updateOrApply(ctx, currentCluster)
+}
+
+// getObjectDescription returns the kind and the name of the object for the reported actions.
+func getObjectDescription(kubernetesClient client.Client, obj client.Object) string {
+	kind := fmt.Sprintf("%T", obj)
+	gvk, err := apiutil.GVKForObject(obj, kubernetesClient.Scheme())
+	if err == nil {
+		kind = gvk.Kind
+	}
+
+	return fmt.Sprintf("%s %s/%s", kind, obj.GetNamespace(), obj.GetName())
+}
+
+// dryRunClient runs all mutating requests as server-side dry-run requests, so that they are validated but never
+// persisted.
+type dryRunClient struct {
+	client.Client
+	actions *dryRunActions
+}
+
+// Create validates the creation of an object without persisting it.
+func (dryRun dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	dryRun.actions.record(fmt.Sprintf("create %s", getObjectDescription(dryRun.Client, obj)))
+	return dryRun.Client.Create(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+// Update validates the update of an object without persisting it.
+func (dryRun dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	dryRun.actions.record(fmt.Sprintf("update %s", getObjectDescription(dryRun.Client, obj)))
+	return dryRun.Client.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+// Patch validates the patch of an object without persisting it.
+func (dryRun dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	dryRun.actions.record(fmt.Sprintf("patch %s", getObjectDescription(dryRun.Client, obj)))
+	return dryRun.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+// Delete validates the deletion of an object without deleting it.
+func (dryRun dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	dryRun.actions.record(fmt.Sprintf("delete %s", getObjectDescription(dryRun.Client, obj)))
+	return dryRun.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+// DeleteAllOf validates the deletion of all matching objects without deleting them.
+func (dryRun dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	dryRun.actions.record(fmt.Sprintf("delete all of %s", getObjectDescription(dryRun.Client, obj)))
+	return dryRun.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+// Status returns a writer that validates status updates without persisting them.
+func (dryRun dryRunClient) Status() client.StatusWriter {
+	return dryRunStatusWriter{StatusWriter: dryRun.Client.Status(), client: dryRun.Client, actions: dryRun.actions}
+}
+
+// dryRunStatusWriter runs all status updates as server-side dry-run requests.
+type dryRunStatusWriter struct {
+	client.StatusWriter
+	client  client.Client
+	actions *dryRunActions
+}
+
+// Update validates the status update of an object without persisting it.
+func (dryRun dryRunStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	dryRun.actions.record(fmt.Sprintf("update status of %s", getObjectDescription(dryRun.client, obj)))
+	return dryRun.StatusWriter.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+// Patch validates the status patch of an object without persisting it.
+func (dryRun dryRunStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	dryRun.actions.record(fmt.Sprintf("update status of %s", getObjectDescription(dryRun.client, obj)))
+	return dryRun.StatusWriter.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+// dryRunPodClient reports all file updates instead of writing the files.
+type dryRunPodClient struct {
+	podclient.FdbPodClient
+	pod     *corev1.Pod
+	actions *dryRunActions
+}
+
+// UpdateFile reports the file update. The file is always reported as not being synced, as it is not possible to check
+// the file without updating it.
+func (dryRun dryRunPodClient) UpdateFile(_ context.Context, name string, _ string) (bool, error) {
+	dryRun.actions.record(fmt.Sprintf("update file %s in Pod %s/%s", name, dryRun.pod.Namespace, dryRun.pod.Name))
+	return false, nil
+}
+
+// dryRunDatabaseClientProvider provides admin and lock clients that report all mutating operations instead of running
+// them.
+type dryRunDatabaseClientProvider struct {
+	fdbadminclient.DatabaseClientProvider
+	actions *dryRunActions
+}
+
+// GetLockClient generates a client for working with locks through the database.
+func (provider dryRunDatabaseClientProvider) GetLockClient(cluster *fdbv1beta2.FoundationDBCluster) (fdbadminclient.LockClient, error) {
+	lockClient, err := provider.DatabaseClientProvider.GetLockClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return dryRunLockClient{LockClient: lockClient, actions: provider.actions}, nil
+}
+
+// GetAdminClient generates a client for performing administrative actions against the database.
+func (provider dryRunDatabaseClientProvider) GetAdminClient(cluster *fdbv1beta2.FoundationDBCluster, kubernetesClient client.Client) (fdbadminclient.AdminClient, error) {
+	// The admin client gets the real Kubernetes client, as it might create resources that are required to read from
+	// the database, e.g. the Pods that run the CLI commands. The mutating operations against the database are
+	// suppressed by the dryRunAdminClient.
+	adminClient, err := provider.DatabaseClientProvider.GetAdminClient(cluster, unwrapDryRunClient(kubernetesClient))
+	if err != nil {
+		return nil, err
+	}
+
+	return dryRunAdminClient{AdminClient: adminClient, actions: provider.actions}, nil
+}
+
+// unwrapDryRunClient returns the Kubernetes client that is wrapped by the dry-run client. The reconciler is passed as
+// client in most places, so the client of a dry-run reconciler is unwrapped as well.
+func unwrapDryRunClient(kubernetesClient client.Client) client.Client {
+	if reconciler, ok := kubernetesClient.(*FoundationDBClusterReconciler); ok {
+		kubernetesClient = reconciler.Client
+	}
+
+	if dryRunKubernetesClient, ok := kubernetesClient.(dryRunClient); ok {
+		return dryRunKubernetesClient.Client
+	}
+
+	return kubernetesClient
+}
+
+// dryRunLockClient reports all changes to the locks instead of writing them.
+type dryRunLockClient struct {
+	fdbadminclient.LockClient
+	actions *dryRunActions
+}
+
+// TakeLock always succeeds without acquiring the lock, so that the following actions can be reported.
+func (dryRun dryRunLockClient) TakeLock() (bool, error) {
+	return true, nil
+}
+
+// AddPendingUpgrades reports the pending upgrades without storing them.
+func (dryRun dryRunLockClient) AddPendingUpgrades(version fdbv1beta2.Version, processGroupIDs []fdbv1beta2.ProcessGroupID) error {
+	dryRun.actions.record(fmt.Sprintf("add pending upgrades to version %s for %v", version, processGroupIDs))
+	return nil
+}
+
+// ClearPendingUpgrades reports the clearing of the pending upgrades without clearing them.
+func (dryRun dryRunLockClient) ClearPendingUpgrades() error {
+	dryRun.actions.record("clear pending upgrades")
+	return nil
+}
+
+// UpdateDenyList reports the update of the deny list without updating it.
+func (dryRun dryRunLockClient) UpdateDenyList(locks []fdbv1beta2.LockDenyListEntry) error {
+	dryRun.actions.record(fmt.Sprintf("update lock deny list with %v", locks))
+	return nil
+}
+
+// dryRunAdminClient reports all mutating operations instead of running them against the database.
+type dryRunAdminClient struct {
+	fdbadminclient.AdminClient
+	actions *dryRunActions
+}
+
+// ConfigureDatabase reports the database configuration without changing it.
+func (dryRun dryRunAdminClient) ConfigureDatabase(_ context.Context, configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) error {
+	configurationString, _ := configuration.GetConfigurationString(version)
+	dryRun.actions.record(fmt.Sprintf("configure database with %s newDatabase=%t", configurationString, newDatabase))
+	return nil
+}
+
+// ExcludeProcesses reports the exclusion without excluding the processes.
+func (dryRun dryRunAdminClient) ExcludeProcesses(_ context.Context, addresses []fdbv1beta2.ProcessAddress) error {
+	dryRun.actions.record(fmt.Sprintf("exclude processes %v", addresses))
+	return nil
+}
+
+// IncludeProcesses reports the inclusion without including the processes.
+func (dryRun dryRunAdminClient) IncludeProcesses(_ context.Context, addresses []fdbv1beta2.ProcessAddress) error {
+	dryRun.actions.record(fmt.Sprintf("include processes %v", addresses))
+	return nil
+}
+
+// KillProcesses reports the restart without restarting the processes.
+func (dryRun dryRunAdminClient) KillProcesses(_ context.Context, addresses []fdbv1beta2.ProcessAddress) error {
+	dryRun.actions.record(fmt.Sprintf("kill processes %v", addresses))
+	return nil
+}
+
+// ChangeCoordinators reports the coordinator change and returns the current connection string.
+func (dryRun dryRunAdminClient) ChangeCoordinators(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) (string, error) {
+	dryRun.actions.record(fmt.Sprintf("change coordinators to %v", addresses))
+	return dryRun.AdminClient.GetConnectionString(ctx)
+}
+
+// ChangeClusterDescription reports the description change and returns the current connection string.
+func (dryRun dryRunAdminClient) ChangeClusterDescription(ctx context.Context, description string) (string, error) {
+	dryRun.actions.record(fmt.Sprintf("change cluster description to %s", description))
+	return dryRun.AdminClient.GetConnectionString(ctx)
+}
+
+// SetMaintenanceZone reports the maintenance zone without setting it.
+func (dryRun dryRunAdminClient) SetMaintenanceZone(_ context.Context, zone string, timeoutSeconds int) error {
+	dryRun.actions.record(fmt.Sprintf("set maintenance zone %s for %d seconds", zone, timeoutSeconds))
+	return nil
+}
+
+// ResetMaintenanceMode reports the reset of the maintenance mode without resetting it.
+func (dryRun dryRunAdminClient) ResetMaintenanceMode(_ context.Context) error {
+	dryRun.actions.record("reset maintenance mode")
+	return nil
+}
+
+// CreateTenant reports the tenant creation without creating the tenant.
+func (dryRun dryRunAdminClient) CreateTenant(_ context.Context, name string) error {
+	dryRun.actions.record(fmt.Sprintf("create tenant %s", name))
+	return nil
+}
+
+// DeleteTenant reports the tenant deletion without deleting the tenant.
+func (dryRun dryRunAdminClient) DeleteTenant(_ context.Context, name string) error {
+	dryRun.actions.record(fmt.Sprintf("delete tenant %s", name))
+	return nil
+}
+
+// SetTagQuota reports the tag quota without setting it.
+func (dryRun dryRunAdminClient) SetTagQuota(_ context.Context, quota fdbv1beta2.TagQuota) error {
+	dryRun.actions.record(fmt.Sprintf("set tag quota for tag %s", quota.Tag))
+	return nil
+}
+
+// SetDataDistributionMode reports the data distribution mode without changing it.
+func (dryRun dryRunAdminClient) SetDataDistributionMode(_ context.Context, enabled bool) error {
+	dryRun.actions.record(fmt.Sprintf("set data distribution enabled=%t", enabled))
+	return nil
+}
+
+// LockDatabase reports the database lock without locking the database.
+func (dryRun dryRunAdminClient) LockDatabase(_ context.Context) (string, error) {
+	dryRun.actions.record("lock database")
+	return "", nil
+}
+
+// UnlockDatabase reports the database unlock without unlocking the database.
+func (dryRun dryRunAdminClient) UnlockDatabase(_ context.Context, lockUID string) error {
+	dryRun.actions.record(fmt.Sprintf("unlock database with lock UID %s", lockUID))
+	return nil
+}
+
+// UpdateWorkJournal reports the update of the work journal without storing it in the database.
+func (dryRun dryRunAdminClient) UpdateWorkJournal(_ context.Context, _ *fdbadminclient.WorkJournal) error {
+	dryRun.actions.record("update work journal")
+	return nil
+}
@@ -0,0 +1,218 @@
+/*
+ * dry_run_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("dry_run", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+
+	getDryRunEvents := func() []corev1.Event {
+		events := &corev1.EventList{}
+		Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+		var matchingEvents []corev1.Event
+		for _, event := range events.Items {
+			if event.InvolvedObject.UID == cluster.ObjectMeta.UID && event.Reason == "DryRun" {
+				matchingEvents = append(matchingEvents, event)
+			}
+		}
+
+		return matchingEvents
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+	})
+
+	When("reconciling a new cluster in dry-run mode", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.DryRun = pointer.Bool(true)
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			_, err := reconcileClusterWithCustomRequeueLimit(cluster, 1)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not create any Pods", func() {
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(context.TODO(), pods, client.InNamespace(cluster.Namespace))).NotTo(HaveOccurred())
+			Expect(pods.Items).To(BeEmpty())
+		})
+
+		It("should not configure the database", func() {
+			Expect(cluster.Status.Configured).To(BeFalse())
+			Expect(cluster.Status.ProcessGroups).To(BeEmpty())
+		})
+
+		It("should report the actions in the status", func() {
+			Expect(cluster.Status.DryRunActions).To(ContainElement("create Pod my-ns/operator-test-1-storage-1"))
+		})
+
+		It("should report the actions as events", func() {
+			var messages []string
+			for _, event := range getDryRunEvents() {
+				messages = append(messages, event.Message)
+			}
+
+			Expect(messages).To(ContainElement("Would create Pod my-ns/operator-test-1-storage-1"))
+		})
+
+		When("the dry-run mode is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DryRun = nil
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+				result, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+				_, err = reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should reconcile the cluster and reset the reported actions", func() {
+				Expect(cluster.Status.Configured).To(BeTrue())
+				Expect(cluster.Status.DryRunActions).To(BeEmpty())
+			})
+		})
+	})
+
+	When("the dry-run mode is enabled for all clusters", func() {
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			clusterReconciler.DryRun = true
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbv1beta2.RedundancyModeTriple
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			_, err = reconcileClusterWithCustomRequeueLimit(cluster, 1)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			clusterReconciler.DryRun = false
+		})
+
+		It("should not change the database configuration", func() {
+			adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminClient.DatabaseConfiguration.RedundancyMode).To(Equal(fdbv1beta2.RedundancyModeDouble))
+			Expect(cluster.Status.DatabaseConfiguration.RedundancyMode).To(Equal(fdbv1beta2.RedundancyModeDouble))
+		})
+
+		It("should report the configuration change", func() {
+			Expect(cluster.Status.DryRunActions).To(ContainElement(ContainSubstring("configure database with triple")))
+		})
+	})
+
+	When("getting an admin client in dry-run mode", func() {
+		var provider *kubernetesClientRecordingProvider
+		var adminClient fdbadminclient.AdminClient
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			provider = &kubernetesClientRecordingProvider{DatabaseClientProvider: mock.DatabaseClientProvider{}}
+			reconciler := createTestClusterReconciler()
+			reconciler.DatabaseClientProvider = provider
+			dryRunReconciler := reconciler.newDryRunReconciler(cluster)
+
+			var err error
+			adminClient, err = dryRunReconciler.getDatabaseClientProvider().GetAdminClient(cluster, dryRunReconciler)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should pass the real Kubernetes client to the admin client, so the CLI command Pods can be created", func() {
+			Expect(provider.kubernetesClient).NotTo(BeNil())
+			Expect(provider.kubernetesClient).NotTo(BeAssignableToTypeOf(dryRunClient{}))
+			Expect(provider.kubernetesClient).To(Equal(k8sClient))
+		})
+
+		It("should suppress the mutating operations", func() {
+			Expect(adminClient).To(BeAssignableToTypeOf(dryRunAdminClient{}))
+		})
+	})
+
+	When("using the dry-run admin client", func() {
+		var adminClient *mock.AdminClient
+		var actions *dryRunActions
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			var err error
+			adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			actions = clusterReconciler.newDryRunReconciler(cluster).dryRunActions
+			dryRunClient := dryRunAdminClient{AdminClient: adminClient, actions: actions}
+			address := fdbv1beta2.NewProcessAddress(nil, "1.1.1.1", 4501, nil)
+			Expect(dryRunClient.ExcludeProcesses(context.TODO(), []fdbv1beta2.ProcessAddress{address})).NotTo(HaveOccurred())
+			Expect(dryRunClient.ExcludeProcesses(context.TODO(), []fdbv1beta2.ProcessAddress{address})).NotTo(HaveOccurred())
+		})
+
+		It("should not exclude the processes", func() {
+			exclusions, err := adminClient.GetExclusions(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exclusions).To(BeEmpty())
+		})
+
+		It("should report the exclusion once", func() {
+			Expect(actions.getActions()).To(ConsistOf("exclude processes [1.1.1.1:4501]"))
+		})
+	})
+})
+
+// kubernetesClientRecordingProvider records the Kubernetes client that is passed to GetAdminClient, e.g. to create
+// the Pods that run the CLI commands.
+type kubernetesClientRecordingProvider struct {
+	fdbadminclient.DatabaseClientProvider
+	kubernetesClient client.Client
+}
+
+// GetAdminClient records the Kubernetes client and returns the admin client of the wrapped provider.
+func (provider *kubernetesClientRecordingProvider) GetAdminClient(cluster *fdbv1beta2.FoundationDBCluster, kubernetesClient client.Client) (fdbadminclient.AdminClient, error) {
+	provider.kubernetesClient = kubernetesClient
+	return provider.DatabaseClientProvider.GetAdminClient(cluster, kubernetesClient)
+}
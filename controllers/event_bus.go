@@ -0,0 +1,283 @@
+/*
+ * event_bus.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/replacements"
+	"github.com/go-logr/logr"
+)
+
+// FindingType describes the kind of issue a detector has found.
+type FindingType string
+
+const (
+	// FindingMissingProcess is published for process groups whose processes are missing for longer than the failure
+	// detection time.
+	FindingMissingProcess FindingType = "MissingProcess"
+
+	// FindingFailedPod is published for process groups that need a replacement because of their Pod or its
+	// resources, e.g. a failing, pending or missing Pod or a tainted node.
+	FindingFailedPod FindingType = "FailedPod"
+
+	// FindingLaggingExclusion is published for process groups that are marked for removal but were not excluded
+	// within the failure detection time.
+	FindingLaggingExclusion FindingType = "LaggingExclusion"
+)
+
+// Finding describes an issue of a process group that was found by a detector.
+type Finding struct {
+	// Type defines the kind of the issue.
+	Type FindingType
+
+	// ProcessGroupID defines the process group that has the issue.
+	ProcessGroupID fdbv1beta2.ProcessGroupID
+
+	// Timestamp defines the unix timestamp since when the issue exists.
+	Timestamp int64
+}
+
+// findingDetector inspects the cluster and returns the issues it found.
+type findingDetector func(cluster *fdbv1beta2.FoundationDBCluster) []Finding
+
+// RemediationHandler describes a handler that subscribes to the findings of the detectors and remediates them, e.g.
+// by replacing failed process groups.
+type RemediationHandler interface {
+	// Remediate handles the findings of the types the handler is subscribed to. It returns true if the status of the
+	// cluster was changed, so the status will be persisted.
+	Remediate(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, findings []Finding) (bool, error)
+}
+
+// RemediationHandlerFunc allows to use a function as a RemediationHandler.
+type RemediationHandlerFunc func(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, findings []Finding) (bool, error)
+
+// Remediate handles the findings.
+func (f RemediationHandlerFunc) Remediate(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, findings []Finding) (bool, error) {
+	return f(ctx, r, cluster, findings)
+}
+
+// remediationSubscription defines a remediation handler and the finding types it is subscribed to.
+type remediationSubscription struct {
+	// name is the name of the handler that is used in the logs.
+	name string
+
+	// handler is the remediation handler.
+	handler RemediationHandler
+
+	// findingTypes are the finding types the handler is subscribed to.
+	findingTypes map[FindingType]fdbv1beta2.None
+}
+
+// eventBus passes the findings of the detectors to the subscribed remediation handlers.
+type eventBus struct {
+	// subscriptions are the subscribed remediation handlers in the order of the subscription.
+	subscriptions []remediationSubscription
+}
+
+// subscribe adds the handler for the provided finding types.
+func (bus *eventBus) subscribe(name string, handler RemediationHandler, findingTypes ...FindingType) {
+	types := make(map[FindingType]fdbv1beta2.None, len(findingTypes))
+	for _, findingType := range findingTypes {
+		types[findingType] = fdbv1beta2.None{}
+	}
+
+	bus.subscriptions = append(bus.subscriptions, remediationSubscription{
+		name:         name,
+		handler:      handler,
+		findingTypes: types,
+	})
+}
+
+// publish passes the findings to all handlers that are subscribed to at least one of the finding types. Each handler
+// will be called once with all findings it is subscribed to. All handlers will be called, even if a previous handler
+// returned an error, and the first error will be returned. The returned bool is true if any handler changed the
+// cluster status.
+func (bus *eventBus) publish(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, findings []Finding) (bool, error) {
+	var changed bool
+	var firstErr error
+
+	for _, subscription := range bus.subscriptions {
+		subscribed := make([]Finding, 0, len(findings))
+		for _, finding := range findings {
+			if _, ok := subscription.findingTypes[finding.Type]; ok {
+				subscribed = append(subscribed, finding)
+			}
+		}
+
+		if len(subscribed) == 0 {
+			continue
+		}
+
+		handlerChanged, err := subscription.handler.Remediate(ctx, r, cluster, subscribed)
+		if err != nil {
+			logger.Error(err, "Remediation handler failed", "handler", subscription.name)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		changed = changed || handlerChanged
+	}
+
+	return changed, firstErr
+}
+
+// registeredRemediationHandler defines a custom remediation handler and the finding types it is subscribed to.
+type registeredRemediationHandler struct {
+	// name is the name of the handler that is used in the logs.
+	name string
+
+	// handler is the custom remediation handler.
+	handler RemediationHandler
+
+	// findingTypes are the finding types the handler is subscribed to.
+	findingTypes []FindingType
+}
+
+// AddRemediationHandler adds a custom remediation handler that will be called with the findings of the provided
+// types. Custom handlers are called after the handlers of the operator, e.g. the automatic replacements, and are not
+// called if the cluster has detectionOnly set in its automation options. Handlers must be added before the reconciler
+// is started.
+func (r *FoundationDBClusterReconciler) AddRemediationHandler(name string, handler RemediationHandler, findingTypes ...FindingType) error {
+	if name == "" {
+		return fmt.Errorf("remediation handler must have a name")
+	}
+
+	if handler == nil {
+		return fmt.Errorf("remediation handler %s must not be nil", name)
+	}
+
+	if len(findingTypes) == 0 {
+		return fmt.Errorf("remediation handler %s must subscribe to at least one finding type", name)
+	}
+
+	for _, registered := range r.remediationHandlers {
+		if registered.name == name {
+			return fmt.Errorf("remediation handler %s already exists", name)
+		}
+	}
+
+	r.remediationHandlers = append(r.remediationHandlers, registeredRemediationHandler{
+		name:         name,
+		handler:      handler,
+		findingTypes: findingTypes,
+	})
+
+	return nil
+}
+
+// getEventBus returns the event bus with the remediation handlers that are enabled for the cluster.
+func (r *FoundationDBClusterReconciler) getEventBus(cluster *fdbv1beta2.FoundationDBCluster) *eventBus {
+	bus := &eventBus{}
+	if cluster.GetDetectionOnly() {
+		return bus
+	}
+
+	if cluster.GetEnableAutomaticReplacements() {
+		bus.subscribe("replacements", RemediationHandlerFunc(replaceProcessGroupsForFindings), FindingMissingProcess, FindingFailedPod)
+	}
+
+	for _, registered := range r.remediationHandlers {
+		bus.subscribe(registered.name, registered.handler, registered.findingTypes...)
+	}
+
+	return bus
+}
+
+// getFindingDetectors returns the detectors that inspect the cluster.
+func getFindingDetectors() []findingDetector {
+	return []findingDetector{
+		detectFailedProcessGroups,
+		detectLaggingExclusions,
+	}
+}
+
+// detectFailedProcessGroups returns the process groups that need a replacement. Process groups with missing
+// processes are reported as FindingMissingProcess, all others as FindingFailedPod.
+func detectFailedProcessGroups(cluster *fdbv1beta2.FoundationDBCluster) []Finding {
+	failed := replacements.GetFailedProcessGroups(cluster)
+	findings := make([]Finding, 0, len(failed))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		timestamp, ok := failed[processGroup.ProcessGroupID]
+		if !ok {
+			continue
+		}
+
+		findingType := FindingFailedPod
+		if processGroup.GetConditionTime(fdbv1beta2.MissingProcesses) != nil {
+			findingType = FindingMissingProcess
+		}
+
+		findings = append(findings, Finding{
+			Type:           findingType,
+			ProcessGroupID: processGroup.ProcessGroupID,
+			Timestamp:      timestamp,
+		})
+	}
+
+	return findings
+}
+
+// detectLaggingExclusions returns the process groups that are marked for removal for longer than the failure
+// detection time without being excluded.
+func detectLaggingExclusions(cluster *fdbv1beta2.FoundationDBCluster) []Finding {
+	var findings []Finding
+	threshold := time.Now().Add(-time.Duration(cluster.GetFailureDetectionTimeSeconds()) * time.Second)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !processGroup.IsMarkedForRemoval() || processGroup.IsExcluded() {
+			continue
+		}
+
+		if !processGroup.RemovalTimestamp.Time.Before(threshold) {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Type:           FindingLaggingExclusion,
+			ProcessGroupID: processGroup.ProcessGroupID,
+			Timestamp:      processGroup.RemovalTimestamp.Unix(),
+		})
+	}
+
+	return findings
+}
+
+// replaceProcessGroupsForFindings is the remediation handler for the automatic replacements, which marks the failed
+// process groups for removal.
+func replaceProcessGroupsForFindings(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, findings []Finding) (bool, error) {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "handler", "replacements")
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return false, err
+	}
+	defer adminClient.Close()
+
+	failed := make(map[fdbv1beta2.ProcessGroupID]int64, len(findings))
+	for _, finding := range findings {
+		failed[finding.ProcessGroupID] = finding.Timestamp
+	}
+
+	return replacements.ReplaceProcessGroups(ctx, logger, cluster, adminClient, failed), nil
+}
@@ -0,0 +1,192 @@
+/*
+ * event_bus_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("event_bus", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Status.ProcessGroups = []*fdbv1beta2.ProcessGroupStatus{
+			fdbv1beta2.NewProcessGroupStatus("storage-1", fdbv1beta2.ProcessClassStorage, nil),
+			fdbv1beta2.NewProcessGroupStatus("storage-2", fdbv1beta2.ProcessClassStorage, nil),
+			fdbv1beta2.NewProcessGroupStatus("log-1", fdbv1beta2.ProcessClassLog, nil),
+		}
+		// Remove the default conditions of new process groups.
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			processGroup.ProcessGroupConditions = nil
+		}
+	})
+
+	When("publishing findings", func() {
+		var bus *eventBus
+		var received map[string][]Finding
+		var changed bool
+		var err error
+
+		newHandler := func(name string, changes bool, handlerErr error) RemediationHandler {
+			return RemediationHandlerFunc(func(_ context.Context, _ *FoundationDBClusterReconciler, _ *fdbv1beta2.FoundationDBCluster, findings []Finding) (bool, error) {
+				received[name] = findings
+				return changes, handlerErr
+			})
+		}
+
+		BeforeEach(func() {
+			bus = &eventBus{}
+			received = map[string][]Finding{}
+		})
+
+		JustBeforeEach(func() {
+			changed, err = bus.publish(context.Background(), log, clusterReconciler, cluster, []Finding{
+				{Type: FindingMissingProcess, ProcessGroupID: "storage-1"},
+				{Type: FindingFailedPod, ProcessGroupID: "storage-2"},
+				{Type: FindingMissingProcess, ProcessGroupID: "log-1"},
+			})
+		})
+
+		When("the handlers are subscribed to different finding types", func() {
+			BeforeEach(func() {
+				bus.subscribe("missing", newHandler("missing", false, nil), FindingMissingProcess)
+				bus.subscribe("all", newHandler("all", true, nil), FindingMissingProcess, FindingFailedPod)
+				bus.subscribe("lagging", newHandler("lagging", true, nil), FindingLaggingExclusion)
+			})
+
+			It("should pass the subscribed findings to each handler", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changed).To(BeTrue())
+				Expect(received).To(HaveLen(2))
+				Expect(received["missing"]).To(ConsistOf(
+					Finding{Type: FindingMissingProcess, ProcessGroupID: "storage-1"},
+					Finding{Type: FindingMissingProcess, ProcessGroupID: "log-1"},
+				))
+				Expect(received["all"]).To(HaveLen(3))
+			})
+		})
+
+		When("a handler returns an error", func() {
+			BeforeEach(func() {
+				bus.subscribe("failing", newHandler("failing", false, fmt.Errorf("remediation failed")), FindingFailedPod)
+				bus.subscribe("changing", newHandler("changing", true, nil), FindingFailedPod)
+			})
+
+			It("should call the remaining handlers and return the error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(changed).To(BeTrue())
+				Expect(received).To(HaveKey("changing"))
+			})
+		})
+	})
+
+	When("adding a custom remediation handler", func() {
+		var reconciler *FoundationDBClusterReconciler
+		var handler RemediationHandler
+
+		BeforeEach(func() {
+			reconciler = &FoundationDBClusterReconciler{}
+			handler = RemediationHandlerFunc(func(_ context.Context, _ *FoundationDBClusterReconciler, _ *fdbv1beta2.FoundationDBCluster, _ []Finding) (bool, error) {
+				return false, nil
+			})
+		})
+
+		It("should subscribe the handler after the replacements", func() {
+			Expect(reconciler.AddRemediationHandler("custom", handler, FindingLaggingExclusion)).NotTo(HaveOccurred())
+
+			bus := reconciler.getEventBus(cluster)
+			Expect(bus.subscriptions).To(HaveLen(2))
+			Expect(bus.subscriptions[0].name).To(Equal("replacements"))
+			Expect(bus.subscriptions[1].name).To(Equal("custom"))
+			Expect(bus.subscriptions[1].findingTypes).To(HaveKey(FindingLaggingExclusion))
+		})
+
+		It("should not subscribe any handler if detection only is enabled", func() {
+			Expect(reconciler.AddRemediationHandler("custom", handler, FindingLaggingExclusion)).NotTo(HaveOccurred())
+			cluster.Spec.AutomationOptions.DetectionOnly = pointer.Bool(true)
+
+			Expect(reconciler.getEventBus(cluster).subscriptions).To(BeEmpty())
+		})
+
+		It("should not subscribe the replacements if they are disabled", func() {
+			cluster.Spec.AutomationOptions.Replacements.Enabled = pointer.Bool(false)
+
+			Expect(reconciler.getEventBus(cluster).subscriptions).To(BeEmpty())
+		})
+
+		It("should reject invalid handlers", func() {
+			Expect(reconciler.AddRemediationHandler("", handler, FindingFailedPod)).To(HaveOccurred())
+			Expect(reconciler.AddRemediationHandler("custom", nil, FindingFailedPod)).To(HaveOccurred())
+			Expect(reconciler.AddRemediationHandler("custom", handler)).To(HaveOccurred())
+			Expect(reconciler.AddRemediationHandler("custom", handler, FindingFailedPod)).NotTo(HaveOccurred())
+			Expect(reconciler.AddRemediationHandler("custom", handler, FindingFailedPod)).To(HaveOccurred())
+		})
+	})
+
+	When("detecting failed process groups", func() {
+		BeforeEach(func() {
+			failureTime := time.Now().Add(-3 * time.Hour).Unix()
+			cluster.Status.ProcessGroups[0].ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+				{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: failureTime},
+			}
+			cluster.Status.ProcessGroups[1].ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+				{ProcessGroupConditionType: fdbv1beta2.PodFailing, Timestamp: failureTime},
+			}
+			cluster.Status.ProcessGroups[2].ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+				{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: time.Now().Unix()},
+			}
+		})
+
+		It("should report the process groups that failed for longer than the failure detection time", func() {
+			Expect(detectFailedProcessGroups(cluster)).To(ConsistOf(
+				HaveField("Type", FindingMissingProcess),
+				HaveField("Type", FindingFailedPod),
+			))
+			Expect(detectFailedProcessGroups(cluster)[0].ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+		})
+	})
+
+	When("detecting lagging exclusions", func() {
+		BeforeEach(func() {
+			cluster.Status.ProcessGroups[0].RemovalTimestamp = &metav1.Time{Time: time.Now().Add(-3 * time.Hour)}
+			cluster.Status.ProcessGroups[1].RemovalTimestamp = &metav1.Time{Time: time.Now().Add(-3 * time.Hour)}
+			cluster.Status.ProcessGroups[1].SetExclude()
+			cluster.Status.ProcessGroups[2].MarkForRemoval()
+		})
+
+		It("should report the process groups that are not excluded within the failure detection time", func() {
+			findings := detectLaggingExclusions(cluster)
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Type).To(Equal(FindingLaggingExclusion))
+			Expect(findings[0].ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+		})
+	})
+})
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -40,7 +41,7 @@ var missingProcessThreshold = 0.8
 type excludeProcesses struct{}
 
 // reconcile runs the reconciler's work.
-func (e excludeProcesses) reconcile(_ context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+func (e excludeProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "excludeProcesses")
 	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
 	if err != nil {
@@ -58,7 +59,7 @@ func (e excludeProcesses) reconcile(_ context.Context, r *FoundationDBClusterRec
 	var fdbProcessesToExclude []fdbv1beta2.ProcessAddress
 	var processClassesToExclude map[fdbv1beta2.ProcessClass]fdbv1beta2.None
 	if removalCount > 0 {
-		exclusions, err := adminClient.GetExclusions()
+		exclusions, err := adminClient.GetExclusions(ctx)
 		if err != nil {
 			return &requeue{curError: err, delayedRequeue: true}
 		}
@@ -79,17 +80,124 @@ func (e excludeProcesses) reconcile(_ context.Context, r *FoundationDBClusterRec
 			}
 		}
 
+		_, excludesStorage := processClassesToExclude[fdbv1beta2.ProcessClassStorage]
+		var status *fdbv1beta2.FoundationDBStatus
+		if excludesStorage || cluster.IsRecoveryFreezeEnabled() {
+			status, err = adminClient.GetStatus(ctx)
+			if err != nil {
+				return &requeue{curError: err, delayedRequeue: true}
+			}
+		}
+
+		if req := checkRecoveryFreeze(logger, cluster, status, "excluding processes"); req != nil {
+			return req
+		}
+
+		if req := checkRestoreInProgress(ctx, logger, r, cluster, "excluding processes"); req != nil {
+			return req
+		}
+
+		// Excluding storage processes while the perpetual storage wiggle is moving data away from other storage
+		// servers would reduce the fault tolerance of the cluster, so we wait until the wiggle is done with those.
+		// The wait is bounded, so a wiggle that makes no progress doesn't block the removal forever.
+		if excludesStorage {
+			wiggledAddresses, err := getConflictingWiggledAddresses(cluster, status)
+			if err != nil {
+				return &requeue{curError: err, delayedRequeue: true}
+			}
+
+			if len(wiggledAddresses) > 0 {
+				remaining := getRemainingStorageWiggleWait(cluster, time.Now())
+				if remaining > 0 {
+					return &requeue{
+						message:        fmt.Sprintf("Waiting for the perpetual storage wiggle of %v to finish. Addresses to exclude: %v", wiggledAddresses, fdbProcessesToExclude),
+						delay:          remaining,
+						delayedRequeue: true,
+					}
+				}
+
+				logger.Info("Perpetual storage wiggle didn't finish in time, excluding processes anyway", "wiggledAddresses", wiggledAddresses, "storageWiggleWaitSeconds", cluster.GetStorageWiggleWaitSeconds())
+				r.Recorder.Event(cluster, corev1.EventTypeWarning, "ExcludingDuringStorageWiggle", fmt.Sprintf("Excluding %v while the perpetual storage wiggle of %v is in progress", fdbProcessesToExclude, wiggledAddresses))
+			}
+		}
+
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ExcludingProcesses", fmt.Sprintf("Excluding %v", fdbProcessesToExclude))
 
-		err = adminClient.ExcludeProcesses(fdbProcessesToExclude)
+		err = adminClient.ExcludeProcesses(ctx, fdbProcessesToExclude)
 		if err != nil {
 			return &requeue{curError: err, delayedRequeue: true}
 		}
+		r.recordAction(cluster, fmt.Sprintf("excluded processes %v", fdbProcessesToExclude), "process groups are marked for removal")
+
+		// The exclusions are tracked, so only exclusions that were done by the operator will be included again if
+		// the address is reused by an active process group.
+		if addOperatorExclusions(cluster, fdbProcessesToExclude) {
+			err = r.updateOrApply(ctx, cluster)
+			if err != nil {
+				return &requeue{curError: err, delayedRequeue: true}
+			}
+		}
 	}
 
 	return nil
 }
 
+// addOperatorExclusions adds the addresses to the exclusions that were done by the operator and returns true if the
+// cluster status was changed.
+func addOperatorExclusions(cluster *fdbv1beta2.FoundationDBCluster, addresses []fdbv1beta2.ProcessAddress) bool {
+	current := make(map[string]fdbv1beta2.None, len(cluster.Status.OperatorExclusions))
+	for _, exclusion := range cluster.Status.OperatorExclusions {
+		current[exclusion] = fdbv1beta2.None{}
+	}
+
+	changed := false
+	for _, address := range addresses {
+		exclusion := address.StringWithoutFlags()
+		if _, ok := current[exclusion]; ok {
+			continue
+		}
+
+		current[exclusion] = fdbv1beta2.None{}
+		cluster.Status.OperatorExclusions = append(cluster.Status.OperatorExclusions, exclusion)
+		changed = true
+	}
+
+	return changed
+}
+
+// removeOperatorExclusions removes the addresses from the exclusions that were done by the operator and returns true if
+// the cluster status was changed.
+func removeOperatorExclusions(cluster *fdbv1beta2.FoundationDBCluster, addresses []fdbv1beta2.ProcessAddress) bool {
+	if len(cluster.Status.OperatorExclusions) == 0 {
+		return false
+	}
+
+	removed := make(map[string]fdbv1beta2.None, len(addresses))
+	for _, address := range addresses {
+		removed[address.StringWithoutFlags()] = fdbv1beta2.None{}
+	}
+
+	exclusions := make([]string, 0, len(cluster.Status.OperatorExclusions))
+	for _, exclusion := range cluster.Status.OperatorExclusions {
+		if _, ok := removed[exclusion]; ok {
+			continue
+		}
+
+		exclusions = append(exclusions, exclusion)
+	}
+
+	if len(exclusions) == len(cluster.Status.OperatorExclusions) {
+		return false
+	}
+
+	if len(exclusions) == 0 {
+		exclusions = nil
+	}
+	cluster.Status.OperatorExclusions = exclusions
+
+	return true
+}
+
 func getProcessesToExclude(exclusions []fdbv1beta2.ProcessAddress, cluster *fdbv1beta2.FoundationDBCluster, removalCount int) ([]fdbv1beta2.ProcessAddress, map[fdbv1beta2.ProcessClass]fdbv1beta2.None) {
 	processClassesToExclude := make(map[fdbv1beta2.ProcessClass]fdbv1beta2.None)
 	fdbProcessesToExclude := make([]fdbv1beta2.ProcessAddress, 0, removalCount)
@@ -128,6 +236,65 @@ func getProcessesToExclude(exclusions []fdbv1beta2.ProcessAddress, cluster *fdbv
 	return fdbProcessesToExclude, processClassesToExclude
 }
 
+// getConflictingWiggledAddresses returns the addresses of the storage servers that are currently wiggled by the
+// perpetual storage wiggle and that are not marked for removal by the operator.
+func getConflictingWiggledAddresses(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus) ([]string, error) {
+	wiggledAddresses := status.Cluster.StorageWiggler.WiggleServerAddresses
+	if len(wiggledAddresses) == 0 {
+		return nil, nil
+	}
+
+	removalAddresses := make(map[string]fdbv1beta2.None)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		for _, address := range processGroup.Addresses {
+			removalAddresses[address] = fdbv1beta2.None{}
+		}
+	}
+
+	var conflictingAddresses []string
+	for _, wiggledAddress := range wiggledAddresses {
+		address, err := fdbv1beta2.ParseProcessAddress(wiggledAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := removalAddresses[address.MachineAddress()]; ok {
+			continue
+		}
+
+		conflictingAddresses = append(conflictingAddresses, wiggledAddress)
+	}
+
+	return conflictingAddresses, nil
+}
+
+// getRemainingStorageWiggleWait returns how long the operator still waits for the perpetual storage wiggle before it
+// excludes the storage processes anyway. The wait starts with the oldest storage process group that is marked for
+// removal and not excluded yet.
+func getRemainingStorageWiggleWait(cluster *fdbv1beta2.FoundationDBCluster, now time.Time) time.Duration {
+	var oldest *time.Time
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage || !processGroup.IsMarkedForRemoval() || processGroup.IsExcluded() {
+			continue
+		}
+
+		removalTime := processGroup.RemovalTimestamp.Time
+		if oldest == nil || removalTime.Before(*oldest) {
+			oldest = &removalTime
+		}
+	}
+
+	if oldest == nil {
+		return 0
+	}
+
+	return oldest.Add(time.Duration(cluster.GetStorageWiggleWaitSeconds()) * time.Second).Sub(now)
+}
+
 func canExcludeNewProcesses(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass) (bool, []fdbv1beta2.ProcessGroupID) {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "excludeProcesses")
 
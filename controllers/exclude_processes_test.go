@@ -24,10 +24,13 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2"
@@ -350,6 +353,91 @@ var _ = Describe("exclude_processes", func() {
 			})
 		})
 	})
+
+	When("the perpetual storage wiggle is running", func() {
+		var adminClient *mock.AdminClient
+		var requeue *requeue
+		var removedProcessGroup fdbv1beta2.ProcessGroupStatus
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			generation, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(generation).To(Equal(int64(1)))
+
+			adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage {
+					continue
+				}
+
+				processGroup.MarkForRemoval()
+				removedProcessGroup = *processGroup
+				break
+			}
+		})
+
+		JustBeforeEach(func() {
+			requeue = excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		When("another storage server is wiggled", func() {
+			BeforeEach(func() {
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage || processGroup.IsMarkedForRemoval() {
+						continue
+					}
+
+					adminClient.WiggledAddresses = []string{processGroup.Addresses[0] + ":4501"}
+					break
+				}
+			})
+
+			It("should wait for the wiggle before excluding the process", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.message).To(HavePrefix("Waiting for the perpetual storage wiggle of"))
+				Expect(requeue.delay).To(BeNumerically(">", 0))
+				Expect(requeue.delay).To(BeNumerically("<=", time.Duration(cluster.GetStorageWiggleWaitSeconds())*time.Second))
+				Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+			})
+
+			When("the operator waited longer than the storage wiggle wait", func() {
+				BeforeEach(func() {
+					processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, removedProcessGroup.ProcessGroupID)
+					processGroup.RemovalTimestamp = &metav1.Time{Time: time.Now().Add(-time.Duration(cluster.GetStorageWiggleWaitSeconds()+1) * time.Second)}
+				})
+
+				It("should exclude the process anyway", func() {
+					Expect(requeue).To(BeNil())
+					Expect(adminClient.ExcludedAddresses).To(HaveKey(removedProcessGroup.Addresses[0]))
+				})
+			})
+		})
+
+		When("the storage server that should be removed is wiggled", func() {
+			BeforeEach(func() {
+				adminClient.WiggledAddresses = []string{removedProcessGroup.Addresses[0] + ":4501"}
+			})
+
+			It("should exclude the process", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.ExcludedAddresses).To(HaveKey(removedProcessGroup.Addresses[0]))
+			})
+
+			It("should track the exclusion of the operator", func() {
+				Expect(cluster.Status.OperatorExclusions).To(ConsistOf(removedProcessGroup.Addresses[0]))
+			})
+		})
+	})
 })
 
 func createMissingProcesses(cluster *fdbv1beta2.FoundationDBCluster, count int, processClass fdbv1beta2.ProcessClass) {
@@ -0,0 +1,49 @@
+/*
+ * fault_tolerance.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+)
+
+// faultToleranceRequeueDelay defines how long the operator waits before checking the fault tolerance again.
+const faultToleranceRequeueDelay = 15 * time.Second
+
+// checkFaultTolerance returns a requeue if the fault tolerance that the machine-readable status reports doesn't meet the
+// desired fault tolerance of the cluster. The check uses the live status instead of the fault tolerance recorded in the
+// cluster status, as the recorded fault tolerance can be outdated by the actions of previous subreconcilers.
+func checkFaultTolerance(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, action string) *requeue {
+	if internal.HasDesiredFaultToleranceFromStatus(logger.WithValues("action", action), status, cluster) {
+		return nil
+	}
+
+	return &requeue{
+		message:        fmt.Sprintf("Waiting for the desired fault tolerance before %s", action),
+		delay:          faultToleranceRequeueDelay,
+		delayedRequeue: true,
+	}
+}
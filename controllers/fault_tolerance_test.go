@@ -0,0 +1,94 @@
+/*
+ * fault_tolerance_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/go-logr/logr"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fault_tolerance", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var status *fdbv1beta2.FoundationDBStatus
+	var result *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		status = &fdbv1beta2.FoundationDBStatus{}
+		status.Client.DatabaseStatus.Available = true
+		status.Cluster.FaultTolerance = fdbv1beta2.FaultTolerance{
+			MaxZoneFailuresWithoutLosingData:         1,
+			MaxZoneFailuresWithoutLosingAvailability: 1,
+		}
+	})
+
+	JustBeforeEach(func() {
+		result = checkFaultTolerance(logr.Discard(), cluster, status, "deleting pods")
+	})
+
+	When("the cluster has the desired fault tolerance", func() {
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+		})
+
+		When("the recorded fault tolerance is outdated", func() {
+			BeforeEach(func() {
+				cluster.Status.FaultTolerance = &fdbv1beta2.FaultToleranceStatus{
+					MaxZoneFailuresWithoutLosingData:         0,
+					MaxZoneFailuresWithoutLosingAvailability: 0,
+					DesiredFaultTolerance:                    1,
+				}
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+	})
+
+	When("the database is unavailable", func() {
+		BeforeEach(func() {
+			status.Client.DatabaseStatus.Available = false
+		})
+
+		It("should requeue", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.delayedRequeue).To(BeTrue())
+			Expect(result.message).To(Equal("Waiting for the desired fault tolerance before deleting pods"))
+		})
+	})
+
+	When("the fault tolerance is below the desired fault tolerance", func() {
+		BeforeEach(func() {
+			status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingAvailability = 0
+		})
+
+		It("should requeue", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.delay).To(Equal(faultToleranceRequeueDelay))
+		})
+	})
+})
@@ -44,6 +44,10 @@ func (g generateInitialClusterFile) reconcile(ctx context.Context, r *Foundation
 		return nil
 	}
 
+	if cluster.IsBeingCloned() {
+		return generateCloneClusterFile(ctx, r, cluster)
+	}
+
 	logger.Info("Generating initial cluster file")
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ChangingCoordinators", "Choosing initial coordinators")
 
@@ -114,7 +118,7 @@ func (g generateInitialClusterFile) reconcile(ctx context.Context, r *Foundation
 		if client == nil {
 			return &requeue{message: message, delay: podSchedulingDelayDuration}
 		}
-		currentLocality, err := locality.InfoFromSidecar(cluster, client)
+		currentLocality, err := locality.InfoFromSidecar(ctx, cluster, internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta), client)
 		if err != nil {
 			return &requeue{curError: err}
 		}
@@ -144,3 +148,87 @@ func (g generateInitialClusterFile) reconcile(ctx context.Context, r *Foundation
 
 	return nil
 }
+
+// generateCloneClusterFile generates the cluster file for a clone. The clone keeps the description and generation ID of
+// the source cluster, as the coordinators store their state under this key, but uses the Pods of the clone that were
+// created from the VolumeSnapshots of the source coordinators as coordinators.
+func generateCloneClusterFile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "generateInitialClusterFile")
+
+	source, cloneProcessGroups, err := r.getCloneProcessGroups(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	sourceConnectionString, err := fdbv1beta2.ParseConnectionString(source.Status.ConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	cloneProcessGroupIDs := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessGroupID, len(cloneProcessGroups))
+	for processGroupID, cloneProcessGroup := range cloneProcessGroups {
+		cloneProcessGroupIDs[cloneProcessGroup.SourceProcessGroupID] = processGroupID
+	}
+
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	podMap := internal.CreatePodMap(cluster, pods)
+
+	connectionString := fdbv1beta2.ConnectionString{
+		DatabaseName: sourceConnectionString.DatabaseName,
+		GenerationID: sourceConnectionString.GenerationID,
+	}
+
+	for _, coordinator := range sourceConnectionString.Coordinators {
+		sourceAddress, err := fdbv1beta2.ParseProcessAddress(coordinator)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		sourceProcessGroup, err := internal.GetSourceProcessGroupForAddress(source, sourceAddress)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		processGroupID, ok := cloneProcessGroupIDs[sourceProcessGroup.ProcessGroupID]
+		if !ok {
+			return &requeue{curError: fmt.Errorf("coordinator %s of cluster %s has no VolumeSnapshot", coordinator, source.Name)}
+		}
+
+		pod, ok := podMap[processGroupID]
+		if !ok || pod.Status.Phase != corev1.PodRunning {
+			return &requeue{
+				message: fmt.Sprintf("waiting for the Pod of process group %s to recruit it as coordinator", processGroupID),
+				delay:   podSchedulingDelayDuration,
+			}
+		}
+
+		client, message := r.getPodClient(cluster, pod)
+		if client == nil {
+			return &requeue{message: message, delay: podSchedulingDelayDuration}
+		}
+
+		currentLocality, err := locality.InfoFromSidecar(ctx, cluster, internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta), client)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		// Keep the port of the source coordinator, to use the process with the same process number.
+		address := getCoordinatorAddress(cluster, currentLocality)
+		address.Port = sourceAddress.Port
+		connectionString.Coordinators = append(connectionString.Coordinators, address.String())
+	}
+
+	logger.Info("Generating cluster file for clone", "source", source.Name, "connectionString", connectionString.String())
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ChangingCoordinators", fmt.Sprintf("Choosing the coordinators of the clone from the coordinators of %s", source.Name))
+	cluster.Status.ConnectionString = connectionString.String()
+
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+/*
+ * include_reused_addresses.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// includeReusedAddresses provides a reconciliation step for including addresses that are still excluded
+// but are now used by an active process group. This can happen if an IP address of a removed process group
+// is reused by a new Pod before the address was included again. Without this step the new processes would
+// be excluded and wouldn't take any roles. Only exclusions that were done by the operator will be included.
+type includeReusedAddresses struct{}
+
+// reconcile runs the reconciler's work.
+func (i includeReusedAddresses) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !cluster.Status.Configured || !cluster.ShouldIncludeReusedAddresses() || len(cluster.Status.OperatorExclusions) == 0 {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "includeReusedAddresses")
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus(ctx)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	exclusions, err := adminClient.GetExclusions(ctx)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	// Exclusions of the operator that were included by someone else don't have to be tracked anymore.
+	if removeOperatorExclusions(cluster, getIncludedOperatorExclusions(cluster, exclusions)) {
+		err = r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err, delayedRequeue: true}
+		}
+	}
+
+	addressesToInclude := getReusedAddressesToInclude(cluster, status, exclusions)
+	if len(addressesToInclude) == 0 {
+		return nil
+	}
+
+	logger.Info("Including addresses that are reused by active process groups", "addresses", addressesToInclude)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "IncludingReusedAddresses", fmt.Sprintf("Including reused addresses: %v", addressesToInclude))
+
+	err = adminClient.IncludeProcesses(ctx, addressesToInclude)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	r.recordAction(cluster, fmt.Sprintf("included processes %v", addressesToInclude), "addresses are reused by active process groups")
+
+	removeOperatorExclusions(cluster, addressesToInclude)
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	return nil
+}
+
+// getIncludedOperatorExclusions returns the exclusions of the operator that are not part of the current exclusions
+// anymore.
+func getIncludedOperatorExclusions(cluster *fdbv1beta2.FoundationDBCluster, exclusions []fdbv1beta2.ProcessAddress) []fdbv1beta2.ProcessAddress {
+	current := make(map[string]fdbv1beta2.None, len(exclusions))
+	for _, exclusion := range exclusions {
+		current[exclusion.StringWithoutFlags()] = fdbv1beta2.None{}
+	}
+
+	var included []fdbv1beta2.ProcessAddress
+	for _, exclusion := range cluster.Status.OperatorExclusions {
+		if _, ok := current[exclusion]; ok {
+			continue
+		}
+
+		included = append(included, fdbv1beta2.ProcessAddress{StringAddress: exclusion})
+	}
+
+	return included
+}
+
+// getReusedAddressesToInclude returns the exclusions of the operator that match the address of an excluded process
+// that belongs to a process group that is not marked for removal.
+func getReusedAddressesToInclude(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, exclusions []fdbv1beta2.ProcessAddress) []fdbv1beta2.ProcessAddress {
+	operatorExclusions := make(map[string]fdbv1beta2.None, len(cluster.Status.OperatorExclusions))
+	for _, exclusion := range cluster.Status.OperatorExclusions {
+		operatorExclusions[exclusion] = fdbv1beta2.None{}
+	}
+
+	// Addresses that are still assigned to a process group that is marked for removal must stay excluded, those
+	// will be included once the process group is removed.
+	activeProcessGroups := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	removalAddresses := map[string]fdbv1beta2.None{}
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			for _, address := range processGroup.Addresses {
+				removalAddresses[address] = fdbv1beta2.None{}
+			}
+
+			continue
+		}
+
+		activeProcessGroups[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+	}
+
+	reusedAddresses := map[string]fdbv1beta2.ProcessAddress{}
+	for _, process := range status.Cluster.Processes {
+		if !process.Excluded || process.Address.IPAddress == nil {
+			continue
+		}
+
+		processGroupID := fdbv1beta2.ProcessGroupID(process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])
+		if _, ok := activeProcessGroups[processGroupID]; !ok {
+			continue
+		}
+
+		if _, ok := removalAddresses[process.Address.IPAddress.String()]; ok {
+			continue
+		}
+
+		reusedAddresses[process.Address.StringWithoutFlags()] = process.Address
+	}
+
+	if len(reusedAddresses) == 0 {
+		return nil
+	}
+
+	var addressesToInclude []fdbv1beta2.ProcessAddress
+	for _, exclusion := range exclusions {
+		// Exclusions based on localities are not affected by reused addresses.
+		if exclusion.IPAddress == nil {
+			continue
+		}
+
+		// Exclusions that were not done by the operator are never included.
+		if _, ok := operatorExclusions[exclusion.StringWithoutFlags()]; !ok {
+			continue
+		}
+
+		for _, address := range reusedAddresses {
+			if !exclusion.IPAddress.Equal(address.IPAddress) {
+				continue
+			}
+
+			if exclusion.Port != 0 && exclusion.Port != address.Port {
+				continue
+			}
+
+			addressesToInclude = append(addressesToInclude, exclusion)
+			break
+		}
+	}
+
+	return addressesToInclude
+}
@@ -0,0 +1,144 @@
+/*
+ * include_reused_addresses_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("include_reused_addresses", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var processGroup *fdbv1beta2.ProcessGroupStatus
+	var result *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		_, err = reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		processGroup = cluster.Status.ProcessGroups[firstStorageIndex]
+	})
+
+	JustBeforeEach(func() {
+		result = includeReusedAddresses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no address is excluded", func() {
+		It("should not include any address", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.ReincludedAddresses).To(BeEmpty())
+		})
+	})
+
+	When("the address of an active process group was excluded by the operator", func() {
+		BeforeEach(func() {
+			adminClient.ExcludedAddresses[processGroup.Addresses[0]] = fdbv1beta2.None{}
+			cluster.Status.OperatorExclusions = []string{processGroup.Addresses[0]}
+		})
+
+		It("should include the address", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+			Expect(adminClient.ReincludedAddresses).To(HaveKeyWithValue(processGroup.Addresses[0], true))
+			Expect(cluster.Status.OperatorExclusions).To(BeEmpty())
+		})
+
+		When("including reused addresses is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.IncludeReusedAddresses = pointer.Bool(false)
+			})
+
+			It("should keep the address excluded", func() {
+				Expect(result).To(BeNil())
+				Expect(adminClient.ExcludedAddresses).To(HaveKey(processGroup.Addresses[0]))
+				Expect(adminClient.ReincludedAddresses).To(BeEmpty())
+			})
+		})
+	})
+
+	When("the address of an active process group was not excluded by the operator", func() {
+		BeforeEach(func() {
+			adminClient.ExcludedAddresses[processGroup.Addresses[0]] = fdbv1beta2.None{}
+			cluster.Status.OperatorExclusions = []string{"192.168.0.1"}
+		})
+
+		It("should keep the address excluded", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.ExcludedAddresses).To(HaveKey(processGroup.Addresses[0]))
+			Expect(adminClient.ReincludedAddresses).To(BeEmpty())
+		})
+
+		It("should stop tracking the operator exclusion that was included", func() {
+			Expect(cluster.Status.OperatorExclusions).To(BeEmpty())
+		})
+	})
+
+	When("the address is still assigned to a process group that is marked for removal", func() {
+		BeforeEach(func() {
+			adminClient.ExcludedAddresses[processGroup.Addresses[0]] = fdbv1beta2.None{}
+			cluster.Status.OperatorExclusions = []string{processGroup.Addresses[0]}
+			cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, &fdbv1beta2.ProcessGroupStatus{
+				ProcessGroupID: "storage-removed",
+				ProcessClass:   fdbv1beta2.ProcessClassStorage,
+				Addresses:      processGroup.Addresses,
+			})
+			cluster.Status.ProcessGroups[len(cluster.Status.ProcessGroups)-1].MarkForRemoval()
+		})
+
+		It("should keep the address excluded", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.ExcludedAddresses).To(HaveKey(processGroup.Addresses[0]))
+			Expect(adminClient.ReincludedAddresses).To(BeEmpty())
+		})
+	})
+
+	When("the process group is marked for removal", func() {
+		BeforeEach(func() {
+			adminClient.ExcludedAddresses[processGroup.Addresses[0]] = fdbv1beta2.None{}
+			cluster.Status.OperatorExclusions = []string{processGroup.Addresses[0]}
+			processGroup.MarkForRemoval()
+		})
+
+		It("should keep the address excluded", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.ExcludedAddresses).To(HaveKey(processGroup.Addresses[0]))
+			Expect(adminClient.ReincludedAddresses).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,127 @@
+/*
+ * latency_prober.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// latencyProberCheckInterval defines how often the latency prober checks which clusters must be probed.
+var latencyProberCheckInterval = 5 * time.Second
+
+// LatencyProber periodically probes the latency of getting a read version, reading a key and committing a transaction
+// for all clusters that have the latency probes enabled and exports the measurements as histograms.
+type LatencyProber struct {
+	reconciler *FoundationDBClusterReconciler
+	log        logr.Logger
+	// lastProbes contains the time of the last probe for every cluster.
+	lastProbes map[types.NamespacedName]time.Time
+}
+
+// NewLatencyProber creates a new LatencyProber that uses the client and database client provider of the reconciler.
+func NewLatencyProber(reconciler *FoundationDBClusterReconciler) *LatencyProber {
+	return &LatencyProber{
+		reconciler: reconciler,
+		log:        reconciler.Log.WithName("LatencyProber"),
+		lastProbes: map[types.NamespacedName]time.Time{},
+	}
+}
+
+// Start implements the manager.Runnable interface and probes the clusters until the context is done.
+func (prober *LatencyProber) Start(ctx context.Context) error {
+	ticker := time.NewTicker(latencyProberCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		prober.probeClusters(ctx, time.Now())
+	}
+}
+
+// probeClusters probes all clusters that have the latency probes enabled and where the latency probe interval has
+// passed since the last probe.
+func (prober *LatencyProber) probeClusters(ctx context.Context, now time.Time) {
+	clusters := &fdbv1beta2.FoundationDBClusterList{}
+	err := prober.reconciler.List(ctx, clusters)
+	if err != nil {
+		prober.log.Error(err, "could not list clusters")
+		return
+	}
+
+	probedClusters := make(map[types.NamespacedName]time.Time, len(clusters.Items))
+	for idx := range clusters.Items {
+		cluster := &clusters.Items[idx]
+		// The database doesn't exist before the cluster is configured and in dry-run mode the operator must not
+		// write to the database.
+		if !cluster.LatencyProbesEnabled() || cluster.Spec.Skip || !cluster.Status.Configured || prober.reconciler.isDryRun(cluster) {
+			continue
+		}
+
+		key := client.ObjectKeyFromObject(cluster)
+		lastProbe, ok := prober.lastProbes[key]
+		if ok && now.Sub(lastProbe) < cluster.GetLatencyProbeInterval() {
+			probedClusters[key] = lastProbe
+			continue
+		}
+
+		probedClusters[key] = now
+		prober.probeCluster(ctx, cluster)
+	}
+
+	// Clusters that were deleted or that have the latency probes disabled are dropped.
+	prober.lastProbes = probedClusters
+}
+
+// probeCluster runs a single latency probe against the cluster and records the measured latencies.
+func (prober *LatencyProber) probeCluster(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) {
+	logger := prober.log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name)
+
+	adminClient, err := prober.reconciler.getDatabaseClientProvider().GetAdminClient(cluster, prober.reconciler)
+	if err != nil {
+		logger.Error(err, "could not create admin client")
+		latencyProbeErrorsCounter.WithLabelValues(cluster.Namespace, cluster.Name).Inc()
+		return
+	}
+	defer adminClient.Close()
+
+	result, err := adminClient.ProbeLatency(ctx)
+	if err != nil {
+		logger.Error(err, "latency probe failed")
+		latencyProbeErrorsCounter.WithLabelValues(cluster.Namespace, cluster.Name).Inc()
+		return
+	}
+
+	logger.V(1).Info("Latency probe completed", "grv", result.GRVLatency.String(), "read", result.ReadLatency.String(), "commit", result.CommitLatency.String())
+	latencyProbeHistogram.WithLabelValues(cluster.Namespace, cluster.Name, "grv").Observe(result.GRVLatency.Seconds())
+	latencyProbeHistogram.WithLabelValues(cluster.Namespace, cluster.Name, "read").Observe(result.ReadLatency.Seconds())
+	latencyProbeHistogram.WithLabelValues(cluster.Namespace, cluster.Name, "commit").Observe(result.CommitLatency.Seconds())
+}
@@ -0,0 +1,120 @@
+/*
+ * latency_prober_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("latency_prober", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var prober *LatencyProber
+	var now time.Time
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		var err error
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		prober = NewLatencyProber(clusterReconciler)
+		now = time.Now()
+	})
+
+	JustBeforeEach(func() {
+		Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+		prober.probeClusters(context.TODO(), now)
+	})
+
+	When("the latency probes are disabled", func() {
+		It("should not probe the cluster", func() {
+			Expect(adminClient.LatencyProbes).To(BeZero())
+			Expect(prober.lastProbes).To(BeEmpty())
+		})
+	})
+
+	When("the latency probes are enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.LatencyProbeOptions.Enabled = pointer.Bool(true)
+		})
+
+		It("should probe the cluster and record the latencies", func() {
+			Expect(adminClient.LatencyProbes).To(Equal(1))
+			Expect(prober.lastProbes).To(HaveKeyWithValue(client.ObjectKeyFromObject(cluster), now))
+			Expect(testutil.CollectAndCount(latencyProbeHistogram)).To(BeNumerically(">=", 3))
+		})
+
+		When("the interval has not passed since the last probe", func() {
+			It("should not probe the cluster again", func() {
+				prober.probeClusters(context.TODO(), now.Add(10*time.Second))
+				Expect(adminClient.LatencyProbes).To(Equal(1))
+				Expect(prober.lastProbes).To(HaveKeyWithValue(client.ObjectKeyFromObject(cluster), now))
+			})
+		})
+
+		When("the interval has passed since the last probe", func() {
+			It("should probe the cluster again", func() {
+				prober.probeClusters(context.TODO(), now.Add(cluster.GetLatencyProbeInterval()))
+				Expect(adminClient.LatencyProbes).To(Equal(2))
+			})
+		})
+
+		When("the latency probe fails", func() {
+			var errorsBefore float64
+
+			BeforeEach(func() {
+				adminClient.LatencyProbeError = fmt.Errorf("probe failed")
+				errorsBefore = testutil.ToFloat64(latencyProbeErrorsCounter.WithLabelValues(cluster.Namespace, cluster.Name))
+			})
+
+			It("should count the failed probe", func() {
+				Expect(adminClient.LatencyProbes).To(Equal(1))
+				Expect(testutil.ToFloat64(latencyProbeErrorsCounter.WithLabelValues(cluster.Namespace, cluster.Name))).To(Equal(errorsBefore + 1))
+			})
+		})
+
+		When("the cluster is in dry-run mode", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DryRun = pointer.Bool(true)
+			})
+
+			It("should not probe the cluster", func() {
+				Expect(adminClient.LatencyProbes).To(BeZero())
+			})
+		})
+	})
+})
@@ -45,7 +45,7 @@ func (maintenanceModeChecker) reconcile(ctx context.Context, r *FoundationDBClus
 	}
 	defer adminClient.Close()
 
-	maintenanceZone, err := adminClient.GetMaintenanceZone()
+	maintenanceZone, err := adminClient.GetMaintenanceZone(ctx)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -73,7 +73,7 @@ func (maintenanceModeChecker) reconcile(ctx context.Context, r *FoundationDBClus
 	}
 	logger.Info("Cluster in maintenance mode", "zone", maintenanceZone)
 	// FDB Cluster is in maintenance mode due to this operator actions
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -102,10 +102,11 @@ func (maintenanceModeChecker) reconcile(ctx context.Context, r *FoundationDBClus
 		return &requeue{curError: err}
 	}
 	logger.Info("Switching off maintenance mode", "zone", maintenanceZone)
-	err = adminClient.ResetMaintenanceMode()
+	err = adminClient.ResetMaintenanceMode(ctx)
 	if err != nil {
 		return &requeue{curError: err}
 	}
+	r.recordAction(cluster, fmt.Sprintf("reset maintenance mode for zone %s", maintenanceZone), "all processes in the zone are up")
 	cluster.Status.MaintenanceModeInfo = fdbv1beta2.MaintenanceModeInfo{}
 	err = r.updateOrApply(ctx, cluster)
 	if err != nil {
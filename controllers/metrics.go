@@ -22,8 +22,10 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/featuregates"
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -100,6 +102,121 @@ var (
 		append(descClusterDefaultLabels, "process_class"),
 		nil,
 	)
+
+	descStorageDiskUtilization = prometheus.NewDesc(
+		"fdb_operator_storage_disk_utilization_percent",
+		"the average disk utilization of the storage processes in percent.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descRecommendedStorageProcesses = prometheus.NewDesc(
+		"fdb_operator_recommended_storage_processes_total",
+		"the count of storage processes that is recommended based on the disk utilization.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descTLSCertificateExpiry = prometheus.NewDesc(
+		"fdb_operator_tls_certificate_expiry_days",
+		"the days until the first certificate that is used by the Fdb processes expires.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupStatus = prometheus.NewDesc(
+		"fdb_operator_backup_status",
+		"status of the Fdb backup.",
+		append(descClusterDefaultLabels, "status_type"),
+		nil,
+	)
+
+	descBackupAgents = prometheus.NewDesc(
+		"fdb_operator_backup_agents_total",
+		"the count of backup agents that are up-to-date and ready.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupDesiredAgents = prometheus.NewDesc(
+		"fdb_operator_backup_desired_agents_total",
+		"the count of the desired backup agents.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupSecondsBehind = prometheus.NewDesc(
+		"fdb_operator_backup_seconds_behind",
+		"the number of seconds that the latest restorable version of the backup lags behind the database.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupLastRestorableVersion = prometheus.NewDesc(
+		"fdb_operator_backup_last_restorable_version",
+		"the latest version of the database that can be restored from the backup.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descFeatureGate = prometheus.NewDesc(
+		"fdb_operator_feature_enabled",
+		"status if a feature of the operator is enabled by the feature gates.",
+		[]string{"name", "stage"},
+		nil,
+	)
+
+	severeTraceEventsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fdb_operator_severe_trace_events_total",
+			Help: "the count of severe trace events received from the Fdb processes.",
+		},
+		append(descClusterDefaultLabels, "type"),
+	)
+
+	latencyProbeHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "fdb_operator_latency_probe_seconds",
+			Help: "the latency of the latency probes against the Fdb Cluster in seconds.",
+			// The buckets range from 1ms to ~8s.
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		},
+		append(descClusterDefaultLabels, "probe"),
+	)
+
+	latencyProbeErrorsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fdb_operator_latency_probe_errors_total",
+			Help: "the count of latency probes against the Fdb Cluster that failed.",
+		},
+		descClusterDefaultLabels,
+	)
+
+	processCountHealingCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fdb_operator_process_count_healing_total",
+			Help: "the count of Pods that were recreated because the running process groups diverged from the desired process counts.",
+		},
+		append(descClusterDefaultLabels, "process_class"),
+	)
+
+	apiThrottledRequestsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fdb_operator_kube_api_throttled_requests_total",
+			Help: "the count of requests to the Kubernetes API server that were throttled by the API server.",
+		},
+		[]string{"method", "priority_level"},
+	)
+
+	apiThrottlingWaitHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "fdb_operator_kube_api_throttling_wait_seconds",
+			Help: "the time that requests to the Kubernetes API server waited because of the client-side rate limiter or the API server throttling in seconds.",
+			// The buckets range from 1ms to ~16s.
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+		},
+		[]string{"source"},
+	)
 )
 
 type fdbClusterCollector struct {
@@ -118,6 +235,8 @@ func (c *fdbClusterCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect implements the prometheus.Collector interface
 func (c *fdbClusterCollector) Collect(ch chan<- prometheus.Metric) {
+	collectFeatureGateMetrics(ch, c.reconciler.getRuntimeSettings().DeprecationOptions.FeatureGates)
+
 	clusters := &fdbv1beta2.FoundationDBClusterList{}
 	err := c.reconciler.List(context.Background(), clusters)
 	if err != nil {
@@ -147,6 +266,15 @@ func collectMetrics(ch chan<- prometheus.Metric, cluster *fdbv1beta2.FoundationD
 	addGauge(descProcessGroupsToRemove, float64(len(cluster.Spec.ProcessGroupsToRemove)))
 	addGauge(descProcessGroupsToRemoveWithoutExclusion, float64(len(cluster.Spec.ProcessGroupsToRemoveWithoutExclusion)))
 
+	if cluster.Status.StorageAutoscaling != nil {
+		addGauge(descStorageDiskUtilization, float64(cluster.Status.StorageAutoscaling.DiskUtilization))
+		addGauge(descRecommendedStorageProcesses, float64(cluster.Status.StorageAutoscaling.RecommendedStorageProcesses))
+	}
+
+	if expiry := getTLSCertificateExpiry(cluster); expiry != nil {
+		addGauge(descTLSCertificateExpiry, time.Until(*expiry).Hours()/24)
+	}
+
 	// Calculate the process group metrics
 	conditionMap, removals, exclusions := getProcessGroupMetrics(cluster)
 
@@ -169,6 +297,87 @@ func collectMetrics(ch chan<- prometheus.Metric, cluster *fdbv1beta2.FoundationD
 	}
 }
 
+type fdbBackupCollector struct {
+	reconciler *FoundationDBClusterReconciler
+}
+
+func newFDBBackupCollector(reconciler *FoundationDBClusterReconciler) *fdbBackupCollector {
+	return &fdbBackupCollector{reconciler: reconciler}
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *fdbBackupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descBackupStatus
+	ch <- descBackupAgents
+	ch <- descBackupDesiredAgents
+	ch <- descBackupSecondsBehind
+	ch <- descBackupLastRestorableVersion
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *fdbBackupCollector) Collect(ch chan<- prometheus.Metric) {
+	backups := &fdbv1beta2.FoundationDBBackupList{}
+	err := c.reconciler.List(context.Background(), backups)
+	if err != nil {
+		return
+	}
+	for _, backup := range backups.Items {
+		collectBackupMetrics(ch, &backup)
+	}
+}
+
+func collectBackupMetrics(ch chan<- prometheus.Metric, backup *fdbv1beta2.FoundationDBBackup) {
+	addGauge := func(desc *prometheus.Desc, v float64, lv ...string) {
+		lv = append([]string{backup.Namespace, backup.Name}, lv...)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, lv...)
+	}
+
+	addGauge(descBackupAgents, float64(backup.Status.AgentCount))
+	addGauge(descBackupDesiredAgents, float64(backup.GetDesiredAgentCount()))
+
+	details := backup.Status.BackupDetails
+	if details == nil {
+		return
+	}
+
+	addGauge(descBackupStatus, boolFloat64(details.Running), "running")
+	addGauge(descBackupStatus, boolFloat64(details.Paused), "paused")
+	addGauge(descBackupStatus, boolFloat64(details.Restorable), "restorable")
+
+	if details.SecondsBehind != nil {
+		addGauge(descBackupSecondsBehind, float64(*details.SecondsBehind))
+	}
+
+	if details.LastRestorableVersion != nil {
+		addGauge(descBackupLastRestorableVersion, float64(*details.LastRestorableVersion))
+	}
+}
+
+// collectFeatureGateMetrics reports for every known feature if it's enabled.
+func collectFeatureGateMetrics(ch chan<- prometheus.Metric, featureGates featuregates.FeatureGates) {
+	for _, status := range featureGates.Status() {
+		ch <- prometheus.MustNewConstMetric(descFeatureGate, prometheus.GaugeValue, boolFloat64(status.Enabled), string(status.Feature), string(status.Stage))
+	}
+}
+
+// getTLSCertificateExpiry returns the expiration of the certificate that expires first or nil if the cluster has no
+// certificates.
+func getTLSCertificateExpiry(cluster *fdbv1beta2.FoundationDBCluster) *time.Time {
+	var expiry *time.Time
+	for _, certificate := range cluster.Status.TLSCertificates {
+		if certificate.NotAfter == nil {
+			continue
+		}
+
+		if expiry == nil || certificate.NotAfter.Time.Before(*expiry) {
+			notAfter := certificate.NotAfter.Time
+			expiry = &notAfter
+		}
+	}
+
+	return expiry
+}
+
 func getProcessGroupMetrics(cluster *fdbv1beta2.FoundationDBCluster) (map[fdbv1beta2.ProcessClass]map[fdbv1beta2.ProcessGroupConditionType]int, map[fdbv1beta2.ProcessClass]int, map[fdbv1beta2.ProcessClass]int) {
 	metricMap := map[fdbv1beta2.ProcessClass]map[fdbv1beta2.ProcessGroupConditionType]int{}
 	removals := map[fdbv1beta2.ProcessClass]int{}
@@ -218,6 +427,13 @@ func getProcessGroupMetrics(cluster *fdbv1beta2.FoundationDBCluster) (map[fdbv1b
 func InitCustomMetrics(reconciler *FoundationDBClusterReconciler) {
 	metrics.Registry.MustRegister(
 		newFDBClusterCollector(reconciler),
+		newFDBBackupCollector(reconciler),
+		severeTraceEventsCounter,
+		latencyProbeHistogram,
+		latencyProbeErrorsCounter,
+		processCountHealingCounter,
+		apiThrottledRequestsCounter,
+		apiThrottlingWaitHistogram,
 	)
 }
 
@@ -24,9 +24,13 @@ import (
 	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/featuregates"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("metrics", func() {
@@ -75,4 +79,117 @@ var _ = Describe("metrics", func() {
 			Expect(exclusions[fdbv1beta2.ProcessClassStateless]).To(BeNumerically("==", 1))
 		})
 	})
+
+	When("getting the expiry of the certificates", func() {
+		It("should return nil without certificates", func() {
+			Expect(getTLSCertificateExpiry(cluster)).To(BeNil())
+		})
+
+		It("should return the expiry of the certificate that expires first", func() {
+			first := time.Now().Add(24 * time.Hour)
+			cluster.Status.TLSCertificates = []fdbv1beta2.TLSCertificateStatus{
+				{SecretName: "fdb-certs", Key: "cert.pem", NotAfter: &metav1.Time{Time: first.Add(time.Hour)}},
+				{SecretName: "fdb-log-certs", Key: "cert.pem", NotAfter: &metav1.Time{Time: first}},
+				{SecretName: "fdb-pending-certs", Key: "cert.pem"},
+			}
+
+			expiry := getTLSCertificateExpiry(cluster)
+			Expect(expiry).NotTo(BeNil())
+			Expect(expiry.Equal(first)).To(BeTrue())
+		})
+	})
+
+	When("collecting the feature gate metrics", func() {
+		It("should report the status of all features", func() {
+			featureGates, err := featuregates.FeatureGates{}.With(map[string]bool{string(featuregates.UnifiedImage): true})
+			Expect(err).NotTo(HaveOccurred())
+
+			ch := make(chan prometheus.Metric, 10)
+			collectFeatureGateMetrics(ch, featureGates)
+			close(ch)
+
+			enabled := map[string]float64{}
+			for metric := range ch {
+				Expect(metric.Desc()).To(Equal(descFeatureGate))
+				result := &dto.Metric{}
+				Expect(metric.Write(result)).NotTo(HaveOccurred())
+				for _, label := range result.GetLabel() {
+					if label.GetName() == "name" {
+						enabled[label.GetValue()] = result.GetGauge().GetValue()
+					}
+				}
+			}
+
+			Expect(enabled).To(HaveKeyWithValue(string(featuregates.UnifiedImage), 1.0))
+			Expect(enabled).To(HaveKeyWithValue(string(featuregates.DNSInClusterFile), 0.0))
+			Expect(enabled).To(HaveKeyWithValue(string(featuregates.LocalitiesForExclusion), 0.0))
+		})
+	})
+
+	When("collecting the backup metrics", func() {
+		var backup *fdbv1beta2.FoundationDBBackup
+		var values map[string]float64
+
+		BeforeEach(func() {
+			backup = &fdbv1beta2.FoundationDBBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test",
+					Name:      "backup",
+				},
+				Spec: fdbv1beta2.FoundationDBBackupSpec{
+					AgentCount: pointer.Int(3),
+				},
+				Status: fdbv1beta2.FoundationDBBackupStatus{
+					AgentCount: 2,
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			ch := make(chan prometheus.Metric, 10)
+			collectBackupMetrics(ch, backup)
+			close(ch)
+
+			values = map[string]float64{}
+			for metric := range ch {
+				result := &dto.Metric{}
+				Expect(metric.Write(result)).NotTo(HaveOccurred())
+				key := metric.Desc().String()
+				for _, label := range result.GetLabel() {
+					if label.GetName() == "status_type" {
+						key = label.GetValue()
+					}
+				}
+				values[key] = result.GetGauge().GetValue()
+			}
+		})
+
+		When("no backup details are present", func() {
+			It("should only report the agent counts", func() {
+				Expect(values).To(HaveLen(2))
+				Expect(values).To(HaveKeyWithValue(descBackupAgents.String(), 2.0))
+				Expect(values).To(HaveKeyWithValue(descBackupDesiredAgents.String(), 3.0))
+			})
+		})
+
+		When("the backup is restorable", func() {
+			BeforeEach(func() {
+				backup.Status.BackupDetails = &fdbv1beta2.FoundationDBBackupStatusBackupDetails{
+					Running:               true,
+					Restorable:            true,
+					LastRestorableVersion: pointer.Int64(1592184263),
+					SecondsBehind:         pointer.Int64(7),
+				}
+			})
+
+			It("should report the lag of the backup", func() {
+				Expect(values).To(HaveLen(7))
+				Expect(values).To(HaveKeyWithValue("running", 1.0))
+				Expect(values).To(HaveKeyWithValue("paused", 0.0))
+				Expect(values).To(HaveKeyWithValue("restorable", 1.0))
+				Expect(values).To(HaveKeyWithValue(descBackupSecondsBehind.String(), 7.0))
+				Expect(values).To(HaveKeyWithValue(descBackupLastRestorableVersion.String(), 1592184263.0))
+			})
+		})
+	})
 })
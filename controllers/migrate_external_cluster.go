@@ -0,0 +1,203 @@
+/*
+ * migrate_external_cluster.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// migrateExternalCluster provides a reconciliation step for migrating an external cluster into operator-managed Pods.
+type migrateExternalCluster struct{}
+
+// reconcile runs the reconciler's work.
+func (migrateExternalCluster) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "migrateExternalCluster")
+
+	if !cluster.IsExternalMigrationInProgress() || !cluster.Status.Configured {
+		return nil
+	}
+
+	externalAddresses, err := cluster.GetExternalProcessAddresses()
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	defer adminClient.Close()
+
+	if cluster.Status.MigrationPhase == "" {
+		err = setMigrationPhase(ctx, r, cluster, fdbv1beta2.MigrationPhaseJoiningProcesses, logger)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if cluster.Status.MigrationPhase == fdbv1beta2.MigrationPhaseJoiningProcesses {
+		missingProcesses := fdbv1beta2.FilterByCondition(cluster.Status.ProcessGroups, fdbv1beta2.MissingProcesses, false)
+		if len(cluster.Status.ProcessGroups) == 0 || len(missingProcesses) > 0 {
+			return &requeue{
+				message:        fmt.Sprintf("Waiting for processes to join the external cluster: %v", missingProcesses),
+				delayedRequeue: true,
+			}
+		}
+
+		err = setMigrationPhase(ctx, r, cluster, fdbv1beta2.MigrationPhaseExcludingExternalProcesses, logger)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if cluster.Status.MigrationPhase == fdbv1beta2.MigrationPhaseExcludingExternalProcesses {
+		excluded, req := excludeExternalProcesses(ctx, logger, r, cluster, adminClient, externalAddresses)
+		if req != nil {
+			return req
+		}
+
+		if !excluded {
+			return &requeue{
+				message:        "Waiting for the data to be moved from the external processes",
+				delayedRequeue: true,
+			}
+		}
+
+		err = setMigrationPhase(ctx, r, cluster, fdbv1beta2.MigrationPhaseChangingCoordinators, logger)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	// The coordinators will be changed by the changeCoordinators reconciler, so we only have to wait until none of
+	// the external processes is a coordinator anymore.
+	connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	externalAddressMap := make(map[string]fdbv1beta2.None, len(externalAddresses))
+	for _, address := range externalAddresses {
+		externalAddressMap[address.StringWithoutFlags()] = fdbv1beta2.None{}
+	}
+
+	for _, coordinator := range connectionString.Coordinators {
+		address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if _, ok := externalAddressMap[address.StringWithoutFlags()]; ok {
+			return &requeue{
+				message:        fmt.Sprintf("Waiting for coordinator %s to be moved to an operator-managed process", coordinator),
+				delayedRequeue: true,
+			}
+		}
+	}
+
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ExternalMigrationCompleted", "Migration from external cluster is completed")
+	err = setMigrationPhase(ctx, r, cluster, fdbv1beta2.MigrationPhaseCompleted, logger)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// excludeExternalProcesses excludes all external processes that are not already excluded and returns true if all
+// external processes can be safely removed. The exclusions are deferred during a recovery and limited by the action
+// budget of the cluster, like the exclusions of the other destructive actions.
+func excludeExternalProcesses(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient, externalAddresses []fdbv1beta2.ProcessAddress) (bool, *requeue) {
+	exclusions, err := adminClient.GetExclusions(ctx)
+	if err != nil {
+		return false, &requeue{curError: err, delayedRequeue: true}
+	}
+
+	currentExclusionMap := make(map[string]fdbv1beta2.None, len(exclusions))
+	for _, exclusion := range exclusions {
+		currentExclusionMap[exclusion.String()] = fdbv1beta2.None{}
+	}
+
+	processesToExclude := make([]fdbv1beta2.ProcessAddress, 0, len(externalAddresses))
+	for _, address := range externalAddresses {
+		if _, ok := currentExclusionMap[address.String()]; ok {
+			continue
+		}
+
+		processesToExclude = append(processesToExclude, address)
+	}
+
+	if len(processesToExclude) > 0 {
+		var status *fdbv1beta2.FoundationDBStatus
+		if cluster.IsRecoveryFreezeEnabled() {
+			status, err = adminClient.GetStatus(ctx)
+			if err != nil {
+				return false, &requeue{curError: err, delayedRequeue: true}
+			}
+		}
+
+		if req := checkRecoveryFreeze(logger, cluster, status, "excluding external processes"); req != nil {
+			return false, req
+		}
+
+		if req := checkActionBudget(logger, r, cluster, "excluding external processes"); req != nil {
+			return false, req
+		}
+
+		if remaining := getRemainingActionBudget(r, cluster); len(processesToExclude) > remaining {
+			logger.Info("Limiting exclusions of external processes to the remaining action budget", "count", len(processesToExclude), "remaining", remaining)
+			processesToExclude = processesToExclude[:remaining]
+		}
+
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ExcludingExternalProcesses", fmt.Sprintf("Excluding %v", processesToExclude))
+		err = adminClient.ExcludeProcesses(ctx, processesToExclude)
+		if err != nil {
+			return false, &requeue{curError: err, delayedRequeue: true}
+		}
+
+		err = consumeActionBudget(ctx, r, cluster, len(processesToExclude))
+		if err != nil {
+			return false, &requeue{curError: err}
+		}
+	}
+
+	remaining, err := adminClient.CanSafelyRemove(ctx, externalAddresses)
+	if err != nil {
+		return false, &requeue{curError: err, delayedRequeue: true}
+	}
+
+	return len(remaining) == 0, nil
+}
+
+// setMigrationPhase updates the migration phase in the cluster status.
+func setMigrationPhase(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, phase fdbv1beta2.MigrationPhase, logger logr.Logger) error {
+	logger.Info("Updating migration phase", "phase", phase)
+	cluster.Status.MigrationPhase = phase
+
+	return r.updateOrApply(ctx, cluster)
+}
@@ -0,0 +1,180 @@
+/*
+ * migrate_external_cluster_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("migrate_external_cluster", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+	externalAddresses := []string{"192.168.0.1:4501", "192.168.0.2:4501", "192.168.0.3:4501"}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		generation, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generation).To(Equal(int64(1)))
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = migrateExternalCluster{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		_, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("no external migration is defined", func() {
+		It("should not requeue", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.MigrationPhase).To(BeEmpty())
+			Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+		})
+	})
+
+	When("an external migration is defined", func() {
+		BeforeEach(func() {
+			cluster.Spec.ExternalMigration = &fdbv1beta2.ExternalMigrationSpec{
+				ExternalProcessAddresses: externalAddresses,
+			}
+		})
+
+		When("all processes have joined the cluster", func() {
+			It("should exclude the external processes and complete the migration", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.MigrationPhase).To(Equal(fdbv1beta2.MigrationPhaseCompleted))
+				Expect(adminClient.ExcludedAddresses).To(HaveLen(len(externalAddresses)))
+				for _, address := range externalAddresses {
+					Expect(adminClient.ExcludedAddresses).To(HaveKey(address))
+				}
+			})
+		})
+
+		When("the database was recovered recently", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.RecoveryFreezeSeconds = pointer.Int(300)
+				cluster.Status.LastObservedRecovery = &metav1.Time{Time: time.Now().Add(-1 * time.Minute)}
+			})
+
+			It("should defer the exclusions", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.message).To(HavePrefix("Waiting"))
+				Expect(requeue.message).To(HaveSuffix("for the database to be recovered before excluding external processes"))
+				Expect(cluster.Status.MigrationPhase).To(Equal(fdbv1beta2.MigrationPhaseExcludingExternalProcesses))
+				Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+			})
+		})
+
+		When("the action budget is smaller than the number of external processes", func() {
+			BeforeEach(func() {
+				clusterReconciler.getActionBudget().reset(cluster)
+				cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile = pointer.Int(2)
+			})
+
+			AfterEach(func() {
+				clusterReconciler.getActionBudget().reset(cluster)
+			})
+
+			It("should only exclude the processes within the action budget", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.message).To(Equal("Waiting for the data to be moved from the external processes"))
+				Expect(cluster.Status.MigrationPhase).To(Equal(fdbv1beta2.MigrationPhaseExcludingExternalProcesses))
+				Expect(adminClient.ExcludedAddresses).To(HaveLen(2))
+			})
+
+			When("the action budget is exhausted", func() {
+				BeforeEach(func() {
+					clusterReconciler.getActionBudget().add(cluster, 2)
+				})
+
+				It("should not exclude any process", func() {
+					Expect(requeue).NotTo(BeNil())
+					Expect(requeue.delayedRequeue).To(BeTrue())
+					Expect(requeue.message).To(Equal("Action budget is exhausted, waiting before excluding external processes"))
+					Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+				})
+			})
+		})
+
+		When("a process is missing", func() {
+			BeforeEach(func() {
+				cluster.Status.ProcessGroups[0].UpdateCondition(fdbv1beta2.MissingProcesses, true, nil, "")
+			})
+
+			It("should wait for the process to join the cluster", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.message).To(HavePrefix("Waiting for processes to join the external cluster"))
+				Expect(cluster.Status.MigrationPhase).To(Equal(fdbv1beta2.MigrationPhaseJoiningProcesses))
+				Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+			})
+		})
+
+		When("an external process is still a coordinator", func() {
+			BeforeEach(func() {
+				cluster.Status.MigrationPhase = fdbv1beta2.MigrationPhaseChangingCoordinators
+				Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+				cluster.Status.ConnectionString = "operator-test:asdfasf@" + externalAddresses[0]
+			})
+
+			It("should wait for the coordinator change", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.message).To(Equal("Waiting for coordinator 192.168.0.1:4501 to be moved to an operator-managed process"))
+				Expect(cluster.Status.MigrationPhase).To(Equal(fdbv1beta2.MigrationPhaseChangingCoordinators))
+			})
+		})
+
+		When("the migration is completed", func() {
+			BeforeEach(func() {
+				cluster.Status.MigrationPhase = fdbv1beta2.MigrationPhaseCompleted
+			})
+
+			It("should not requeue", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+			})
+		})
+	})
+})
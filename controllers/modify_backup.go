@@ -33,6 +33,10 @@ type modifyBackup struct {
 
 // reconcile runs the reconciler's work.
 func (s modifyBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	if backup.UsesVolumeSnapshots() {
+		return nil
+	}
+
 	if backup.Status.BackupDetails == nil || !backup.ShouldRun() {
 		return nil
 	}
@@ -45,7 +49,7 @@ func (s modifyBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconc
 		}
 		defer adminClient.Close()
 
-		err = adminClient.ModifyBackup(snapshotPeriod)
+		err = adminClient.ModifyBackup(ctx, snapshotPeriod)
 		if err != nil {
 			return &requeue{curError: err}
 		}
@@ -0,0 +1,82 @@
+/*
+ * operator_config.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdboperatorconfigs,verbs=get;list;watch
+
+// getOperatorConfig fetches the FoundationDBOperatorConfig that provides the defaults for the cluster. If the cluster
+// uses the default config and the default config doesn't exist, nil will be returned.
+func (r *FoundationDBClusterReconciler) getOperatorConfig(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) (*fdbv1beta2.FoundationDBOperatorConfig, error) {
+	config := &fdbv1beta2.FoundationDBOperatorConfig{}
+	err := r.Get(ctx, client.ObjectKey{Name: cluster.GetOperatorConfigName()}, config)
+	if err != nil {
+		if k8serrors.IsNotFound(err) && cluster.Spec.OperatorConfigName == "" {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not get operator config %s: %w", cluster.GetOperatorConfigName(), err)
+	}
+
+	return config, nil
+}
+
+// applyOperatorConfig applies the defaults of the FoundationDBOperatorConfig of the cluster to the cluster spec.
+func (r *FoundationDBClusterReconciler) applyOperatorConfig(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) error {
+	config, err := r.getOperatorConfig(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	return internal.ApplyOperatorConfig(cluster, config)
+}
+
+// findClustersForOperatorConfig returns the requests for all clusters that use the operator config.
+func (r *FoundationDBClusterReconciler) findClustersForOperatorConfig(object client.Object) []reconcile.Request {
+	clusters := &fdbv1beta2.FoundationDBClusterList{}
+	err := r.List(context.Background(), clusters)
+	if err != nil {
+		log.Error(err, "could not list clusters", "operatorConfig", object.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		if cluster.GetOperatorConfigName() != object.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}})
+	}
+
+	return requests
+}
@@ -0,0 +1,125 @@
+/*
+ * operator_config_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("operator_config", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var config *fdbv1beta2.FoundationDBOperatorConfig
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		config = &fdbv1beta2.FoundationDBOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: fdbv1beta2.DefaultOperatorConfigName},
+			Spec: fdbv1beta2.FoundationDBOperatorConfigSpec{
+				MainContainerImageConfigs: []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb"}},
+				AutomationOptions: fdbv1beta2.FoundationDBClusterAutomationOptions{
+					SettleTimeSeconds: pointer.Int(30),
+				},
+			},
+		}
+	})
+
+	When("applying the operator config", func() {
+		var err error
+
+		JustBeforeEach(func() {
+			err = clusterReconciler.applyOperatorConfig(context.TODO(), cluster)
+		})
+
+		When("the default config doesn't exist", func() {
+			It("should not change the cluster", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.Spec.MainContainer.ImageConfigs).To(BeEmpty())
+				Expect(cluster.Spec.AutomationOptions.SettleTimeSeconds).To(BeNil())
+			})
+		})
+
+		When("the default config exists", func() {
+			BeforeEach(func() {
+				Expect(k8sClient.Create(context.TODO(), config)).NotTo(HaveOccurred())
+			})
+
+			It("should apply the defaults of the config", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.Spec.MainContainer.ImageConfigs).To(ConsistOf(fdbv1beta2.ImageConfig{BaseImage: "registry.example/foundationdb"}))
+				Expect(cluster.Spec.AutomationOptions.SettleTimeSeconds).To(Equal(pointer.Int(30)))
+			})
+
+			When("the cluster overrides an automation option", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.SettleTimeSeconds = pointer.Int(120)
+				})
+
+				It("should keep the setting of the cluster", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.AutomationOptions.SettleTimeSeconds).To(Equal(pointer.Int(120)))
+				})
+			})
+
+			When("the cluster references another config", func() {
+				BeforeEach(func() {
+					cluster.Spec.OperatorConfigName = "production"
+				})
+
+				It("should return an error", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(cluster.Spec.MainContainer.ImageConfigs).To(BeEmpty())
+				})
+			})
+		})
+	})
+
+	When("finding the clusters for an operator config", func() {
+		var requests []reconcile.Request
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			otherCluster := internal.CreateDefaultCluster()
+			otherCluster.Name = "other-cluster"
+			otherCluster.Spec.OperatorConfigName = "production"
+			Expect(k8sClient.Create(context.TODO(), otherCluster)).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			requests = clusterReconciler.findClustersForOperatorConfig(config)
+		})
+
+		It("should only return the clusters that use the config", func() {
+			Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}}))
+		})
+	})
+})
@@ -0,0 +1,286 @@
+/*
+ * pod_deletion_protection.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PodDeletionProtectionPath defines the path of the webhook that validates the deletion of Pods.
+const PodDeletionProtectionPath = "/validate-pod-deletion"
+
+// +kubebuilder:webhook:path=/validate-pod-deletion,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=delete,versions=v1,name=pod-deletion.foundationdb.org,admissionReviewVersions=v1
+
+// unavailableProcessGroupConditions contains the conditions that mark a process group as unavailable.
+var unavailableProcessGroupConditions = []fdbv1beta2.ProcessGroupConditionType{
+	fdbv1beta2.MissingProcesses,
+	fdbv1beta2.PodFailing,
+	fdbv1beta2.PodPending,
+	fdbv1beta2.MissingPod,
+	fdbv1beta2.SidecarUnreachable,
+}
+
+// PodDeletionProtection is an admission webhook that rejects the deletion of a Pod of a FoundationDBCluster if
+// other fault domains of the cluster are already unavailable and the deletion would exceed the fault tolerance of the
+// cluster. The deletion is always allowed if the Pod has the force deletion annotation, in this case the response
+// contains a warning if the deletion exceeds the fault tolerance.
+type PodDeletionProtection struct {
+	client.Client
+	// OperatorUser defines the user of the operator, e.g. the username of its service account. The deletions of the
+	// operator are never rejected, as the operator performs its own fault tolerance checks.
+	OperatorUser string
+	// ExemptUsers contains additional users whose deletions are never rejected. Users with the prefix system:node:
+	// and service accounts in the kube-system namespace are always exempt.
+	ExemptUsers []string
+	decoder     *admission.Decoder
+}
+
+// InjectDecoder injects the decoder for the admission requests.
+func (protection *PodDeletionProtection) InjectDecoder(decoder *admission.Decoder) error {
+	protection.decoder = decoder
+	return nil
+}
+
+// Handle validates the deletion of a Pod.
+func (protection *PodDeletionProtection) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Delete || protection.isExempt(req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+
+	pod := &corev1.Pod{}
+	err := protection.decoder.DecodeRaw(req.OldObject, pod)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	forced := pod.Annotations[fdbv1beta2.ForceDeletionAnnotation] == "true"
+	cluster, err := protection.getOwningCluster(ctx, pod)
+	if err != nil {
+		if forced {
+			return admission.Allowed("deletion is forced by annotation")
+		}
+
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if cluster == nil {
+		return admission.Allowed("")
+	}
+
+	err = protection.checkDeletion(ctx, cluster, pod)
+	if forced {
+		// A forced deletion is always allowed, but the user should know if it exceeds the fault tolerance.
+		response := admission.Allowed("deletion is forced by annotation")
+		if err != nil {
+			response = response.WithWarnings(err.Error())
+		}
+
+		return response
+	}
+
+	if err != nil {
+		log.Info("Rejecting Pod deletion", "namespace", pod.Namespace, "pod", pod.Name, "user", req.UserInfo.Username, "reason", err.Error())
+		return admission.Denied(fmt.Sprintf("%s, set the annotation %s=true on the Pod to force the deletion", err.Error(), fdbv1beta2.ForceDeletionAnnotation))
+	}
+
+	return admission.Allowed("")
+}
+
+// isExempt returns true if the deletions of the user are never rejected.
+func (protection *PodDeletionProtection) isExempt(username string) bool {
+	if protection.OperatorUser != "" && username == protection.OperatorUser {
+		return true
+	}
+
+	if strings.HasPrefix(username, "system:node:") || strings.HasPrefix(username, "system:serviceaccount:kube-system:") {
+		return true
+	}
+
+	for _, user := range protection.ExemptUsers {
+		if user == username {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getOwningCluster returns the FoundationDBCluster that owns the Pod or nil if the Pod is not owned by a cluster.
+func (protection *PodDeletionProtection) getOwningCluster(ctx context.Context, pod *corev1.Pod) (*fdbv1beta2.FoundationDBCluster, error) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind != "FoundationDBCluster" || !strings.HasPrefix(owner.APIVersion, fdbv1beta2.GroupVersion.Group+"/") {
+			continue
+		}
+
+		cluster := &fdbv1beta2.FoundationDBCluster{}
+		err := protection.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: owner.Name}, cluster)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return cluster, nil
+	}
+
+	return nil, nil
+}
+
+// checkDeletion returns an error if the deletion of the Pod would make more fault domains unavailable than the
+// cluster tolerates.
+func (protection *PodDeletionProtection) checkDeletion(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) error {
+	processGroups := make(map[fdbv1beta2.ProcessGroupID]*fdbv1beta2.ProcessGroupStatus, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processGroups[processGroup.ProcessGroupID] = processGroup
+	}
+
+	// Deleting a Pod that is already unavailable or whose processes are excluded doesn't reduce the fault
+	// tolerance.
+	processGroup := processGroups[podmanager.GetProcessGroupID(cluster, pod)]
+	if processGroup == nil || isExcludedFromFaultTolerance(processGroup) || isProcessGroupUnavailable(processGroup, pod) {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	err := protection.List(ctx, pods, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return err
+	}
+
+	podsByProcessGroup := make(map[fdbv1beta2.ProcessGroupID]*corev1.Pod, len(pods.Items))
+	for index := range pods.Items {
+		podsByProcessGroup[podmanager.GetProcessGroupID(cluster, &pods.Items[index])] = &pods.Items[index]
+	}
+
+	faultDomain, err := protection.getFaultDomain(ctx, cluster, pod)
+	if err != nil {
+		return err
+	}
+
+	unavailableFaultDomains := map[string]fdbv1beta2.None{}
+	for processGroupID, otherProcessGroup := range processGroups {
+		otherPod := podsByProcessGroup[processGroupID]
+		if isExcludedFromFaultTolerance(otherProcessGroup) || !isProcessGroupUnavailable(otherProcessGroup, otherPod) {
+			continue
+		}
+
+		// The fault domain of a process group without a Pod is unknown, so it is counted as its own fault domain.
+		otherFaultDomain := string(processGroupID)
+		if otherPod != nil {
+			otherFaultDomain, err = protection.getFaultDomain(ctx, cluster, otherPod)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Deleting another Pod in a fault domain that is already unavailable doesn't reduce the fault tolerance.
+		if otherFaultDomain == faultDomain {
+			return nil
+		}
+
+		unavailableFaultDomains[otherFaultDomain] = fdbv1beta2.None{}
+	}
+
+	if len(unavailableFaultDomains) < cluster.DesiredFaultTolerance() {
+		return nil
+	}
+
+	faultDomains := make([]string, 0, len(unavailableFaultDomains))
+	for unavailableFaultDomain := range unavailableFaultDomains {
+		faultDomains = append(faultDomains, unavailableFaultDomain)
+	}
+	sort.Strings(faultDomains)
+
+	return fmt.Errorf("deleting Pod %s would exceed the fault tolerance of cluster %s, the fault domains %s are already unavailable and the cluster tolerates %d unavailable fault domains",
+		pod.Name, cluster.Name, strings.Join(faultDomains, ", "), cluster.DesiredFaultTolerance())
+}
+
+// getFaultDomain returns the fault domain of the Pod according to the fault domain settings of the cluster.
+func (protection *PodDeletionProtection) getFaultDomain(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (string, error) {
+	faultDomainKey := cluster.Spec.FaultDomain.Key
+	if faultDomainKey == fdbv1beta2.NoneFaultDomainKey {
+		return pod.Name, nil
+	}
+
+	if faultDomainKey == "foundationdb.org/kubernetes-cluster" {
+		return cluster.Spec.FaultDomain.Value, nil
+	}
+
+	faultDomainSource := cluster.Spec.FaultDomain.ValueFrom
+	if faultDomainKey == "" || faultDomainKey == corev1.LabelHostname || faultDomainSource == "" || faultDomainSource == "spec.nodeName" || pod.Spec.NodeName == "" {
+		return pod.Spec.NodeName, nil
+	}
+
+	// For all other fault domain sources the fault domain is taken from the label of the node.
+	node := &corev1.Node{}
+	err := protection.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return pod.Spec.NodeName, nil
+		}
+
+		return "", err
+	}
+
+	faultDomain, ok := node.Labels[faultDomainKey]
+	if !ok {
+		return pod.Spec.NodeName, nil
+	}
+
+	return faultDomain, nil
+}
+
+// isExcludedFromFaultTolerance returns true if the processes of the process group are excluded, so the availability
+// of the process group doesn't affect the fault tolerance.
+func isExcludedFromFaultTolerance(processGroup *fdbv1beta2.ProcessGroupStatus) bool {
+	return processGroup.IsMarkedForRemoval() && processGroup.IsExcluded()
+}
+
+// isProcessGroupUnavailable returns true if the process group has a condition that makes it unavailable or if its Pod
+// is missing or terminating.
+func isProcessGroupUnavailable(processGroup *fdbv1beta2.ProcessGroupStatus, pod *corev1.Pod) bool {
+	if pod == nil || pod.DeletionTimestamp != nil {
+		return true
+	}
+
+	for _, conditionType := range unavailableProcessGroupConditions {
+		if processGroup.GetConditionTime(conditionType) != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,206 @@
+/*
+ * pod_deletion_protection_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("pod_deletion_protection", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var protection *PodDeletionProtection
+	var pod *corev1.Pod
+	var username string
+	var response admission.Response
+
+	getPod := func(processGroupID fdbv1beta2.ProcessGroupID) *corev1.Pod {
+		pods := &corev1.PodList{}
+		Expect(k8sClient.List(context.TODO(), pods, internal.GetSinglePodListOptions(cluster, processGroupID)...)).NotTo(HaveOccurred())
+		Expect(pods.Items).To(HaveLen(1))
+
+		return &pods.Items[0]
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.FaultDomain = fdbv1beta2.FoundationDBClusterFaultDomain{
+			Key:       corev1.LabelHostname,
+			ValueFrom: "spec.nodeName",
+		}
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		_, err = reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+
+		protection = &PodDeletionProtection{Client: k8sClient}
+		Expect(protection.InjectDecoder(decoder)).NotTo(HaveOccurred())
+
+		pod = getPod("storage-1")
+		username = "admin"
+	})
+
+	JustBeforeEach(func() {
+		content, err := json.Marshal(pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		response = protection.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Delete,
+				OldObject: runtime.RawExtension{Raw: content},
+				UserInfo:  authenticationv1.UserInfo{Username: username},
+			},
+		})
+	})
+
+	When("all process groups are available", func() {
+		It("should allow the deletion", func() {
+			Expect(response.Allowed).To(BeTrue())
+		})
+
+		When("the Pod has the force deletion annotation", func() {
+			BeforeEach(func() {
+				pod.Annotations[fdbv1beta2.ForceDeletionAnnotation] = "true"
+			})
+
+			It("should allow the deletion without a warning", func() {
+				Expect(response.Allowed).To(BeTrue())
+				Expect(response.Warnings).To(BeEmpty())
+			})
+		})
+	})
+
+	When("a process group in another fault domain is unavailable", func() {
+		BeforeEach(func() {
+			processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-2")
+			Expect(processGroup).NotTo(BeNil())
+			processGroup.UpdateCondition(fdbv1beta2.MissingProcesses, true, nil, "")
+			Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should reject the deletion", func() {
+			Expect(response.Allowed).To(BeFalse())
+			Expect(string(response.Result.Reason)).To(ContainSubstring("would exceed the fault tolerance"))
+			Expect(string(response.Result.Reason)).To(ContainSubstring(fdbv1beta2.ForceDeletionAnnotation))
+		})
+
+		When("the Pod is in the same fault domain", func() {
+			BeforeEach(func() {
+				pod.Spec.NodeName = getPod("storage-2").Spec.NodeName
+			})
+
+			It("should allow the deletion", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("the process group of the Pod is unavailable too", func() {
+			BeforeEach(func() {
+				processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1")
+				Expect(processGroup).NotTo(BeNil())
+				processGroup.UpdateCondition(fdbv1beta2.PodFailing, true, nil, "")
+				Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should allow the deletion", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("the Pod has the force deletion annotation", func() {
+			BeforeEach(func() {
+				pod.Annotations[fdbv1beta2.ForceDeletionAnnotation] = "true"
+			})
+
+			It("should allow the deletion with a warning", func() {
+				Expect(response.Allowed).To(BeTrue())
+				Expect(response.Warnings).To(HaveLen(1))
+				Expect(response.Warnings[0]).To(ContainSubstring("would exceed the fault tolerance"))
+			})
+		})
+
+		When("the user is exempt", func() {
+			BeforeEach(func() {
+				protection.ExemptUsers = []string{"system:serviceaccount:default:fdb-kubernetes-operator-controller-manager"}
+				username = "system:serviceaccount:default:fdb-kubernetes-operator-controller-manager"
+			})
+
+			It("should allow the deletion", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("the user is the operator", func() {
+			BeforeEach(func() {
+				protection.OperatorUser = "system:serviceaccount:default:fdb-kubernetes-operator-controller-manager"
+				username = "system:serviceaccount:default:fdb-kubernetes-operator-controller-manager"
+			})
+
+			It("should allow the deletion", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("the unavailable process group is excluded", func() {
+			BeforeEach(func() {
+				processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-2")
+				Expect(processGroup).NotTo(BeNil())
+				processGroup.MarkForRemoval()
+				processGroup.SetExclude()
+				Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should allow the deletion", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+	})
+
+	When("the Pod is not owned by a cluster", func() {
+		BeforeEach(func() {
+			pod.OwnerReferences = nil
+		})
+
+		It("should allow the deletion", func() {
+			Expect(response.Allowed).To(BeTrue())
+		})
+	})
+})
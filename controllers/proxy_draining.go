@@ -0,0 +1,138 @@
+/*
+ * proxy_draining.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// checkProxyDrain returns a requeue if the addresses contain processes with a commit proxy or GRV proxy role that
+// were not drained yet. The drain is started by recording the process groups in the cluster status and the processes
+// are drained once the drain delay has passed. The operator can't stop clients from sending new transactions to the
+// proxies, so the drain only delays the bounce.
+func checkProxyDrain(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, addresses []fdbv1beta2.ProcessAddress) *requeue {
+	if !cluster.ProxyDrainingEnabled() {
+		return nil
+	}
+
+	processGroupIDs := getProxiesForAddresses(status, addresses)
+	if len(processGroupIDs) == 0 {
+		return nil
+	}
+
+	drain := cluster.Status.ProxyDrain
+	if drain == nil || drain.Timestamp == nil || !containsAllProcessGroupIDs(drain.ProcessGroupIDs, processGroupIDs) {
+		logger.Info("Draining processes with proxy roles before bouncing them", "processGroupIDs", processGroupIDs)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "DrainingProxies", fmt.Sprintf("Draining processes with proxy roles before bouncing them: %v", processGroupIDs))
+		cluster.Status.ProxyDrain = &fdbv1beta2.ProxyDrainStatus{
+			ProcessGroupIDs: processGroupIDs,
+			Timestamp:       &metav1.Time{Time: time.Now()},
+		}
+
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return &requeue{
+			message: fmt.Sprintf("Draining processes with proxy roles %v before bouncing them", processGroupIDs),
+			delay:   cluster.GetProxyDrainingDelay(),
+		}
+	}
+
+	if remaining := cluster.GetProxyDrainingDelay() - time.Since(drain.Timestamp.Time); remaining > 0 {
+		return &requeue{
+			message: fmt.Sprintf("Waiting %s for the drain of the processes with proxy roles %v", remaining.Round(time.Second), processGroupIDs),
+			delay:   remaining,
+		}
+	}
+
+	return nil
+}
+
+// clearProxyDrain removes the state of the proxy drain from the cluster status once the drained processes were
+// bounced.
+func clearProxyDrain(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) error {
+	if cluster.Status.ProxyDrain == nil {
+		return nil
+	}
+
+	cluster.Status.ProxyDrain = nil
+	return r.updateOrApply(ctx, cluster)
+}
+
+// getProxiesForAddresses returns the sorted process group IDs of the processes with a commit proxy or GRV proxy role
+// that use one of the addresses.
+func getProxiesForAddresses(status *fdbv1beta2.FoundationDBStatus, addresses []fdbv1beta2.ProcessAddress) []fdbv1beta2.ProcessGroupID {
+	bouncedAddresses := make(map[string]fdbv1beta2.None, len(addresses))
+	for _, address := range addresses {
+		bouncedAddresses[address.StringWithoutFlags()] = fdbv1beta2.None{}
+	}
+
+	processGroups := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	for _, process := range status.Cluster.Processes {
+		if !process.HasRole(fdbv1beta2.ProcessRoleCommitProxy) && !process.HasRole(fdbv1beta2.ProcessRoleGrvProxy) {
+			continue
+		}
+
+		if _, ok := bouncedAddresses[process.Address.StringWithoutFlags()]; !ok {
+			continue
+		}
+
+		processGroups[fdbv1beta2.ProcessGroupID(process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])] = fdbv1beta2.None{}
+	}
+
+	processGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0, len(processGroups))
+	for processGroupID := range processGroups {
+		processGroupIDs = append(processGroupIDs, processGroupID)
+	}
+
+	sort.Slice(processGroupIDs, func(i, j int) bool {
+		return processGroupIDs[i] < processGroupIDs[j]
+	})
+
+	return processGroupIDs
+}
+
+// containsAllProcessGroupIDs returns true if all process group IDs of the subset are part of the process group IDs.
+func containsAllProcessGroupIDs(processGroupIDs []fdbv1beta2.ProcessGroupID, subset []fdbv1beta2.ProcessGroupID) bool {
+	ids := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(processGroupIDs))
+	for _, processGroupID := range processGroupIDs {
+		ids[processGroupID] = fdbv1beta2.None{}
+	}
+
+	for _, processGroupID := range subset {
+		if _, ok := ids[processGroupID]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
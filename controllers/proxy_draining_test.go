@@ -0,0 +1,189 @@
+/*
+ * proxy_draining_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("proxy draining", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var status *fdbv1beta2.FoundationDBStatus
+	var addresses []fdbv1beta2.ProcessAddress
+
+	newProcess := func(processGroupID string, ip string, role fdbv1beta2.ProcessRole) fdbv1beta2.FoundationDBStatusProcessInfo {
+		return fdbv1beta2.FoundationDBStatusProcessInfo{
+			Address: fdbv1beta2.ProcessAddress{IPAddress: net.ParseIP(ip), Port: 4501},
+			Locality: map[string]string{
+				fdbv1beta2.FDBLocalityInstanceIDKey: processGroupID,
+			},
+			Roles: []fdbv1beta2.FoundationDBStatusProcessRoleInfo{{Role: string(role)}},
+		}
+	}
+
+	BeforeEach(func() {
+		status = &fdbv1beta2.FoundationDBStatus{
+			Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+				Processes: map[fdbv1beta2.ProcessGroupID]fdbv1beta2.FoundationDBStatusProcessInfo{
+					"stateless-1": newProcess("stateless-1", "192.168.0.1", fdbv1beta2.ProcessRoleCommitProxy),
+					"stateless-2": newProcess("stateless-2", "192.168.0.2", fdbv1beta2.ProcessRoleGrvProxy),
+					"storage-1":   newProcess("storage-1", "192.168.0.3", fdbv1beta2.ProcessRoleStorage),
+				},
+			},
+		}
+	})
+
+	Describe("getProxiesForAddresses", func() {
+		var processGroupIDs []fdbv1beta2.ProcessGroupID
+
+		JustBeforeEach(func() {
+			processGroupIDs = getProxiesForAddresses(status, addresses)
+		})
+
+		When("all processes are bounced", func() {
+			BeforeEach(func() {
+				addresses = nil
+				for _, process := range status.Cluster.Processes {
+					addresses = append(addresses, process.Address)
+				}
+			})
+
+			It("should return the processes with proxy roles", func() {
+				Expect(processGroupIDs).To(Equal([]fdbv1beta2.ProcessGroupID{"stateless-1", "stateless-2"}))
+			})
+		})
+
+		When("only the storage process is bounced", func() {
+			BeforeEach(func() {
+				addresses = []fdbv1beta2.ProcessAddress{status.Cluster.Processes["storage-1"].Address}
+			})
+
+			It("should return no processes", func() {
+				Expect(processGroupIDs).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("checkProxyDrain", func() {
+		var result *requeue
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+			cluster.Spec.AutomationOptions.ProxyDrainingOptions.Enabled = pointer.Bool(true)
+			addresses = []fdbv1beta2.ProcessAddress{
+				status.Cluster.Processes["stateless-1"].Address,
+				status.Cluster.Processes["storage-1"].Address,
+			}
+		})
+
+		JustBeforeEach(func() {
+			result = checkProxyDrain(context.TODO(), logr.Discard(), clusterReconciler, cluster, status, addresses)
+		})
+
+		When("proxy draining is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ProxyDrainingOptions.Enabled = pointer.Bool(false)
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Status.ProxyDrain).To(BeNil())
+			})
+		})
+
+		When("no process with a proxy role is bounced", func() {
+			BeforeEach(func() {
+				addresses = []fdbv1beta2.ProcessAddress{status.Cluster.Processes["storage-1"].Address}
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Status.ProxyDrain).To(BeNil())
+			})
+		})
+
+		When("the drain was not started", func() {
+			It("should start the drain and requeue", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.message).To(Equal("Draining processes with proxy roles [stateless-1] before bouncing them"))
+				Expect(result.delay).To(Equal(10 * time.Second))
+				Expect(cluster.Status.ProxyDrain).NotTo(BeNil())
+				Expect(cluster.Status.ProxyDrain.ProcessGroupIDs).To(Equal([]fdbv1beta2.ProcessGroupID{"stateless-1"}))
+				Expect(cluster.Status.ProxyDrain.Timestamp).NotTo(BeNil())
+			})
+		})
+
+		When("the drain was started for different processes", func() {
+			BeforeEach(func() {
+				cluster.Status.ProxyDrain = &fdbv1beta2.ProxyDrainStatus{
+					ProcessGroupIDs: []fdbv1beta2.ProcessGroupID{"stateless-2"},
+					Timestamp:       &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+				}
+			})
+
+			It("should restart the drain and requeue", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delay).To(Equal(10 * time.Second))
+				Expect(cluster.Status.ProxyDrain.ProcessGroupIDs).To(Equal([]fdbv1beta2.ProcessGroupID{"stateless-1"}))
+			})
+		})
+
+		When("the drain delay has not passed", func() {
+			BeforeEach(func() {
+				cluster.Status.ProxyDrain = &fdbv1beta2.ProxyDrainStatus{
+					ProcessGroupIDs: []fdbv1beta2.ProcessGroupID{"stateless-1"},
+					Timestamp:       &metav1.Time{Time: time.Now().Add(-5 * time.Second)},
+				}
+			})
+
+			It("should requeue for the remaining delay", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.message).To(HavePrefix("Waiting"))
+				Expect(result.delay).To(BeNumerically("<=", 5*time.Second))
+			})
+		})
+
+		When("the drain delay has passed", func() {
+			BeforeEach(func() {
+				cluster.Status.ProxyDrain = &fdbv1beta2.ProxyDrainStatus{
+					ProcessGroupIDs: []fdbv1beta2.ProcessGroupID{"stateless-1"},
+					Timestamp:       &metav1.Time{Time: time.Now().Add(-15 * time.Second)},
+				}
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+	})
+})
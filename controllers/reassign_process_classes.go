@@ -0,0 +1,191 @@
+/*
+ * reassign_process_classes.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reassignProcessClasses provides a reconciliation step for assigning process groups to a different process class.
+// The process class is part of the process group ID, so the operator adds a process group with the new process class
+// and removes the reassigned process group. The strategy defines when the reassigned process group is removed:
+// process groups of classes without data are removed right away, process groups of classes with data are only removed
+// once the new process group is running, so the cluster doesn't lose capacity while the data is moved.
+type reassignProcessClasses struct{}
+
+// reconcile runs the reconciler's work.
+func (reassignProcessClasses) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if len(cluster.Spec.ProcessGroupsToReassign) == 0 && len(cluster.Status.ProcessClassReassignments) == 0 {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "reassignProcessClasses")
+
+	processGroups := make(map[fdbv1beta2.ProcessGroupID]*fdbv1beta2.ProcessGroupStatus, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processGroups[processGroup.ProcessGroupID] = processGroup
+	}
+
+	// Reassignments are done once the reassigned process group is removed.
+	changed := false
+	reassignments := make([]fdbv1beta2.ProcessClassReassignmentStatus, 0, len(cluster.Status.ProcessClassReassignments))
+	inProgress := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	for _, reassignment := range cluster.Status.ProcessClassReassignments {
+		if _, ok := processGroups[reassignment.ProcessGroupID]; !ok {
+			logger.Info("Process class reassignment is done", "processGroupID", reassignment.ProcessGroupID, "newProcessGroupID", reassignment.NewProcessGroupID)
+			changed = true
+			continue
+		}
+
+		reassignments = append(reassignments, reassignment)
+		inProgress[reassignment.ProcessGroupID] = fdbv1beta2.None{}
+	}
+
+	processGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0, len(cluster.Spec.ProcessGroupsToReassign))
+	for processGroupID := range cluster.Spec.ProcessGroupsToReassign {
+		processGroupIDs = append(processGroupIDs, processGroupID)
+	}
+
+	sort.Slice(processGroupIDs, func(i, j int) bool {
+		return processGroupIDs[i] < processGroupIDs[j]
+	})
+
+	for _, processGroupID := range processGroupIDs {
+		processClass := cluster.Spec.ProcessGroupsToReassign[processGroupID]
+		processGroup, ok := processGroups[processGroupID]
+		if !ok || processGroup.ProcessClass == processClass || processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		if _, ok := inProgress[processGroupID]; ok {
+			continue
+		}
+
+		newProcessGroupID, err := getReassignedProcessGroupID(cluster, processGroupID, processClass)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		strategy := processGroup.ProcessClass.GetReassignmentStrategy(processClass)
+		logger.Info("Reassigning process group", "processGroupID", processGroupID, "processClass", processClass, "newProcessGroupID", newProcessGroupID, "strategy", strategy)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ReassigningProcessClass", fmt.Sprintf("Reassigning process group %s from %s to %s as %s with strategy %s", processGroupID, processGroup.ProcessClass, processClass, newProcessGroupID, strategy))
+		r.recordAction(cluster, fmt.Sprintf("added process group %s to reassign process group %s with strategy %s", newProcessGroupID, processGroupID, strategy), fmt.Sprintf("process group %s should be reassigned to process class %s", processGroupID, processClass))
+
+		newProcessGroup := fdbv1beta2.NewProcessGroupStatus(newProcessGroupID, processClass, nil)
+		cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, newProcessGroup)
+		processGroups[newProcessGroupID] = newProcessGroup
+		reassignments = append(reassignments, fdbv1beta2.ProcessClassReassignmentStatus{
+			ProcessGroupID:    processGroupID,
+			ProcessClass:      processClass,
+			NewProcessGroupID: newProcessGroupID,
+			Strategy:          strategy,
+			Timestamp:         &metav1.Time{Time: time.Now()},
+		})
+		changed = true
+	}
+
+	// The reassigned process groups are marked for removal according to their strategy, the removal itself is
+	// done by the chooseRemovals, excludeProcesses and removeProcessGroups sub-reconcilers.
+	var waiting []fdbv1beta2.ProcessGroupID
+	for _, reassignment := range reassignments {
+		processGroup := processGroups[reassignment.ProcessGroupID]
+		if processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		if reassignment.Strategy == fdbv1beta2.ProcessClassReassignmentStrategyReplacement {
+			newProcessGroup, ok := processGroups[reassignment.NewProcessGroupID]
+			if ok && len(newProcessGroup.ProcessGroupConditions) > 0 {
+				waiting = append(waiting, reassignment.ProcessGroupID)
+				continue
+			}
+		}
+
+		logger.Info("Marking reassigned process group for removal", "processGroupID", reassignment.ProcessGroupID, "newProcessGroupID", reassignment.NewProcessGroupID, "strategy", reassignment.Strategy)
+		processGroup.MarkForRemoval()
+		changed = true
+	}
+
+	if len(reassignments) == 0 {
+		reassignments = nil
+	}
+	cluster.Status.ProcessClassReassignments = reassignments
+
+	if changed {
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if len(waiting) > 0 {
+		return &requeue{message: fmt.Sprintf("waiting for the new process groups of the reassigned process groups %v to be running", waiting), delayedRequeue: true}
+	}
+
+	return nil
+}
+
+// getReassignedProcessGroupID returns the ID of the process group that replaces the reassigned process group. The ID
+// number of the reassigned process group is kept if it is not used by another process group of the new process class.
+func getReassignedProcessGroupID(cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID, processClass fdbv1beta2.ProcessClass) (fdbv1beta2.ProcessGroupID, error) {
+	_, idNum, err := podmanager.ParseProcessGroupID(processGroupID)
+	if err != nil {
+		return "", err
+	}
+
+	usedIDs := map[int]bool{}
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.ProcessClass != processClass {
+			continue
+		}
+
+		_, num, err := podmanager.ParseProcessGroupID(processGroup.ProcessGroupID)
+		if err != nil {
+			return "", err
+		}
+
+		usedIDs[num] = true
+	}
+
+	isAvailable := func(num int) (fdbv1beta2.ProcessGroupID, bool) {
+		_, newProcessGroupID := internal.GetProcessGroupID(cluster, processClass, num)
+		return newProcessGroupID, !usedIDs[num] && !cluster.ProcessGroupIsBeingRemoved(newProcessGroupID) && !cluster.HasProcessGroupTombstone(newProcessGroupID)
+	}
+
+	if newProcessGroupID, ok := isAvailable(idNum); ok {
+		return newProcessGroupID, nil
+	}
+
+	for num := 1; ; num++ {
+		if newProcessGroupID, ok := isAvailable(num); ok {
+			return newProcessGroupID, nil
+		}
+	}
+}
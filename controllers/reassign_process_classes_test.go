@@ -0,0 +1,193 @@
+/*
+ * reassign_process_classes_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("reassign_process_classes", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var result *requeue
+
+	getMarkedProcessGroups := func() []fdbv1beta2.ProcessGroupID {
+		var marked []fdbv1beta2.ProcessGroupID
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			if processGroup.IsMarkedForRemoval() {
+				marked = append(marked, processGroup.ProcessGroupID)
+			}
+		}
+
+		return marked
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		_, err = reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		result = reassignProcessClasses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no process group should be reassigned", func() {
+		It("should not change the process groups", func() {
+			Expect(result).To(BeNil())
+			Expect(getMarkedProcessGroups()).To(BeEmpty())
+			Expect(cluster.Status.ProcessClassReassignments).To(BeEmpty())
+		})
+	})
+
+	When("a stateless process group is reassigned to another class without data", func() {
+		BeforeEach(func() {
+			cluster.Spec.ProcessGroupsToReassign = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessClass{
+				"stateless-1": fdbv1beta2.ProcessClassCommitProxy,
+			}
+		})
+
+		It("should mark the reassigned process group for removal right away", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Status.ProcessClassReassignments).To(HaveLen(1))
+			reassignment := cluster.Status.ProcessClassReassignments[0]
+			Expect(reassignment.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("stateless-1")))
+			Expect(reassignment.NewProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("commit_proxy-1")))
+			Expect(reassignment.Strategy).To(Equal(fdbv1beta2.ProcessClassReassignmentStrategyImmediate))
+
+			newProcessGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "commit_proxy-1")
+			Expect(newProcessGroup).NotTo(BeNil())
+			Expect(newProcessGroup.ProcessClass).To(Equal(fdbv1beta2.ProcessClassCommitProxy))
+			Expect(getMarkedProcessGroups()).To(ConsistOf(fdbv1beta2.ProcessGroupID("stateless-1")))
+		})
+	})
+
+	When("a storage process group is reassigned to the log class", func() {
+		BeforeEach(func() {
+			cluster.Spec.ProcessGroupsToReassign = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessClass{
+				"storage-1": fdbv1beta2.ProcessClassLog,
+			}
+		})
+
+		It("should add a log process group and wait for it before removing the storage process group", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.delayedRequeue).To(BeTrue())
+			Expect(result.message).To(Equal("waiting for the new process groups of the reassigned process groups [storage-1] to be running"))
+
+			Expect(cluster.Status.ProcessClassReassignments).To(HaveLen(1))
+			reassignment := cluster.Status.ProcessClassReassignments[0]
+			Expect(reassignment.Strategy).To(Equal(fdbv1beta2.ProcessClassReassignmentStrategyReplacement))
+			// The ID number 1 is already used by another log process group.
+			Expect(reassignment.NewProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("log-5")))
+			Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "log-5")).NotTo(BeNil())
+			Expect(getMarkedProcessGroups()).To(BeEmpty())
+		})
+
+		When("the new process group is running", func() {
+			JustBeforeEach(func() {
+				newProcessGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "log-5")
+				Expect(newProcessGroup).NotTo(BeNil())
+				newProcessGroup.ProcessGroupConditions = nil
+
+				result = reassignProcessClasses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should mark the storage process group for removal", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Status.ProcessClassReassignments).To(HaveLen(1))
+				Expect(getMarkedProcessGroups()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-1")))
+			})
+		})
+
+		When("the reassigned process group was removed", func() {
+			JustBeforeEach(func() {
+				processGroups := make([]*fdbv1beta2.ProcessGroupStatus, 0, len(cluster.Status.ProcessGroups))
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if processGroup.ProcessGroupID != "storage-1" {
+						processGroups = append(processGroups, processGroup)
+					}
+				}
+				cluster.Status.ProcessGroups = processGroups
+
+				result = reassignProcessClasses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should remove the reassignment from the status", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Status.ProcessClassReassignments).To(BeEmpty())
+			})
+		})
+	})
+
+	When("the process group already has the new process class", func() {
+		BeforeEach(func() {
+			cluster.Spec.ProcessGroupsToReassign = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessClass{
+				"storage-1": fdbv1beta2.ProcessClassStorage,
+			}
+		})
+
+		It("should not reassign the process group", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Status.ProcessClassReassignments).To(BeEmpty())
+		})
+	})
+
+	When("the cluster is reconciled with a reassignment and updated process counts", func() {
+		BeforeEach(func() {
+			cluster.Spec.ProcessGroupsToReassign = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessClass{
+				"storage-1": fdbv1beta2.ProcessClassLog,
+			}
+			cluster.Spec.ProcessCounts.Storage = 3
+			cluster.Spec.ProcessCounts.Log = 5
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			// Run the reconciliation a few times to add the new process group and to remove the reassigned one.
+			for i := 0; i < 5; i++ {
+				_, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should replace the storage process group with a log process group", func() {
+			Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1")).To(BeNil())
+			Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "log-5")).NotTo(BeNil())
+			Expect(cluster.Status.ProcessClassReassignments).To(BeEmpty())
+
+			counts := fdbv1beta2.CreateProcessCountsFromProcessGroupStatus(cluster.Status.ProcessGroups, true)
+			Expect(counts.Storage).To(Equal(3))
+			Expect(counts.Log).To(Equal(5))
+		})
+	})
+})
@@ -0,0 +1,363 @@
+/*
+ * reconcile_benchmark_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	mockclient "github.com/FoundationDB/fdb-kubernetes-operator/mock-kubernetes-client/client"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	mockpodclient "github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient/mock"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+const (
+	// benchmarkLogCount defines the number of log processes of the synthetic cluster of the reconcile benchmark.
+	benchmarkLogCount = 3
+	// benchmarkStatelessCount defines the number of stateless processes of the synthetic cluster of the reconcile
+	// benchmark.
+	benchmarkStatelessCount = 3
+	// benchmarkMinimumPods defines the minimum number of Pods of the synthetic cluster of the reconcile benchmark,
+	// this contains at least one storage Pod and one cluster controller Pod.
+	benchmarkMinimumPods = benchmarkLogCount + benchmarkStatelessCount + 2
+	// benchmarkMaxInitialReconciliations defines how often the synthetic cluster is reconciled before the benchmark
+	// gives up on reaching a reconciled cluster.
+	benchmarkMaxInitialReconciliations = 20
+)
+
+// reconcileBenchmarkOptions defines the synthetic cluster and the number of iterations of the reconcile benchmark.
+type reconcileBenchmarkOptions struct {
+	// Pods defines the number of Pods of the synthetic cluster.
+	Pods int
+	// Iterations defines how often the reconciled cluster is reconciled again.
+	Iterations int
+}
+
+// subReconcilerTiming contains the durations of a sub-reconciler across all iterations of the reconcile benchmark.
+type subReconcilerTiming struct {
+	// Name of the sub-reconciler.
+	Name string
+	// Runs defines how often the sub-reconciler was run.
+	Runs int
+	// Total is the sum of the durations of all runs.
+	Total time.Duration
+	// Min is the shortest duration of a run.
+	Min time.Duration
+	// Max is the longest duration of a run.
+	Max time.Duration
+}
+
+// average returns the average duration of a run of the sub-reconciler.
+func (timing subReconcilerTiming) average() time.Duration {
+	if timing.Runs == 0 {
+		return 0
+	}
+
+	return timing.Total / time.Duration(timing.Runs)
+}
+
+// reconcileBenchmarkResult contains the results of the reconcile benchmark.
+type reconcileBenchmarkResult struct {
+	// Options contains the options the benchmark was run with.
+	Options reconcileBenchmarkOptions
+	// InitialReconciliation defines how long it took to reconcile the newly created cluster.
+	InitialReconciliation time.Duration
+	// Total defines how long all iterations took.
+	Total time.Duration
+	// Timings contains the durations of the sub-reconcilers in the order of the reconciliation pipeline.
+	Timings []subReconcilerTiming
+}
+
+// print writes the results of the reconcile benchmark as a table to the writer.
+func (result *reconcileBenchmarkResult) print(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, "Reconcile benchmark with %d Pods and %d iterations\n", result.Options.Pods, result.Options.Iterations)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "Initial reconciliation: %s\n", result.InitialReconciliation)
+	if err != nil {
+		return err
+	}
+
+	var perIteration time.Duration
+	if result.Options.Iterations > 0 {
+		perIteration = result.Total / time.Duration(result.Options.Iterations)
+	}
+
+	_, err = fmt.Fprintf(writer, "Reconciliation of a reconciled cluster: %s per iteration\n\n", perIteration)
+	if err != nil {
+		return err
+	}
+
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	_, err = fmt.Fprintln(tabWriter, "SUB-RECONCILER\tRUNS\tAVERAGE\tMIN\tMAX\tTOTAL")
+	if err != nil {
+		return err
+	}
+
+	for _, timing := range result.Timings {
+		_, err = fmt.Fprintf(tabWriter, "%s\t%d\t%s\t%s\t%s\t%s\n", timing.Name, timing.Runs, timing.average(), timing.Min, timing.Max, timing.Total)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tabWriter.Flush()
+}
+
+// runReconcileBenchmark creates a synthetic cluster with the defined number of Pods against the mock clients,
+// reconciles it and then measures the duration of every sub-reconciler while reconciling the reconciled cluster for
+// the defined number of iterations.
+func runReconcileBenchmark(ctx context.Context, scheme *runtime.Scheme, options reconcileBenchmarkOptions) (*reconcileBenchmarkResult, error) {
+	if options.Pods < benchmarkMinimumPods {
+		return nil, fmt.Errorf("the reconcile benchmark requires at least %d Pods, got %d", benchmarkMinimumPods, options.Pods)
+	}
+
+	if options.Iterations < 1 {
+		return nil, fmt.Errorf("the reconcile benchmark requires at least one iteration, got %d", options.Iterations)
+	}
+
+	kubeClient := mockclient.NewMockClient(scheme)
+	r := &FoundationDBClusterReconciler{
+		Client:                 kubeClient,
+		Log:                    ctrl.Log.WithName("controllers").WithName("FoundationDBCluster"),
+		Recorder:               kubeClient,
+		InSimulation:           true,
+		PodLifecycleManager:    podmanager.StandardPodLifecycleManager{},
+		PodClientProvider:      mockpodclient.NewMockFdbPodClient,
+		DatabaseClientProvider: mock.DatabaseClientProvider{},
+	}
+
+	cluster := newBenchmarkCluster(options.Pods)
+	defer mock.ClearMockAdminClients()
+
+	err := kubeClient.Create(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}}
+	result := &reconcileBenchmarkResult{Options: options}
+
+	start := time.Now()
+	reconciled := false
+	for attempt := 0; attempt < benchmarkMaxInitialReconciliations; attempt++ {
+		reconcileResult, err := r.Reconcile(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reconcileResult.Requeue {
+			reconciled = true
+			break
+		}
+	}
+	result.InitialReconciliation = time.Since(start)
+
+	if !reconciled {
+		return nil, fmt.Errorf("synthetic cluster was not reconciled after %d reconciliations", benchmarkMaxInitialReconciliations)
+	}
+
+	// The names are kept in the order of the first run to report the timings in the order of the pipeline.
+	var names []string
+	timings := map[string]*subReconcilerTiming{}
+	r.subReconcilerObserver = func(name string, duration time.Duration) {
+		timing, ok := timings[name]
+		if !ok {
+			timing = &subReconcilerTiming{Name: name, Min: duration}
+			timings[name] = timing
+			names = append(names, name)
+		}
+
+		timing.Runs++
+		timing.Total += duration
+		if duration < timing.Min {
+			timing.Min = duration
+		}
+		if duration > timing.Max {
+			timing.Max = duration
+		}
+	}
+
+	start = time.Now()
+	for iteration := 0; iteration < options.Iterations; iteration++ {
+		_, err = r.Reconcile(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.Total = time.Since(start)
+
+	result.Timings = make([]subReconcilerTiming, 0, len(names))
+	for _, name := range names {
+		result.Timings = append(result.Timings, *timings[name])
+	}
+
+	return result, nil
+}
+
+// newBenchmarkCluster returns the synthetic cluster of the reconcile benchmark with the defined number of Pods.
+func newBenchmarkCluster(pods int) *fdbv1beta2.FoundationDBCluster {
+	return &fdbv1beta2.FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "benchmark-cluster",
+			Namespace: "benchmark",
+		},
+		Spec: fdbv1beta2.FoundationDBClusterSpec{
+			Version: fdbv1beta2.Versions.Default.String(),
+			ProcessCounts: fdbv1beta2.ProcessCounts{
+				Storage:           pods - benchmarkLogCount - benchmarkStatelessCount - 1,
+				Log:               benchmarkLogCount,
+				Stateless:         benchmarkStatelessCount,
+				ClusterController: 1,
+			},
+			FaultDomain: fdbv1beta2.FoundationDBClusterFaultDomain{
+				Key: fdbv1beta2.NoneFaultDomainKey,
+			},
+			AutomationOptions: fdbv1beta2.FoundationDBClusterAutomationOptions{
+				WaitBetweenRemovalsSeconds: pointer.Int(0),
+			},
+			MinimumUptimeSecondsForBounce: 1,
+		},
+		Status: fdbv1beta2.FoundationDBClusterStatus{
+			RequiredAddresses: fdbv1beta2.RequiredAddressSet{
+				NonTLS: true,
+			},
+			ProcessGroups:  make([]*fdbv1beta2.ProcessGroupStatus, 0),
+			RunningVersion: fdbv1beta2.Versions.Default.String(),
+		},
+	}
+}
+
+// benchmarkReconcilePods defines the number of Pods of the synthetic cluster of BenchmarkReconcile.
+var benchmarkReconcilePods = flag.Int("benchmark-reconcile-pods", 200, "Defines the number of Pods of the synthetic cluster of the reconcile benchmark.")
+
+// BenchmarkReconcile measures the reconciliation of a reconciled synthetic cluster and logs the durations of every
+// sub-reconciler, e.g. with: go test ./controllers/ -run '^$' -bench BenchmarkReconcile -args -benchmark-reconcile-pods=500
+func BenchmarkReconcile(b *testing.B) {
+	// The sub-reconcilers log every step, which would hide the results of the benchmark.
+	ctrl.SetLogger(zap.New(zap.WriteTo(io.Discard)))
+
+	benchmarkScheme := runtime.NewScheme()
+	if err := scheme.AddToScheme(benchmarkScheme); err != nil {
+		b.Fatal(err)
+	}
+	if err := fdbv1beta2.AddToScheme(benchmarkScheme); err != nil {
+		b.Fatal(err)
+	}
+
+	result, err := runReconcileBenchmark(context.Background(), benchmarkScheme, reconcileBenchmarkOptions{
+		Pods:       *benchmarkReconcilePods,
+		Iterations: b.N,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	if err := result.print(&buffer); err != nil {
+		b.Fatal(err)
+	}
+	b.Log("\n" + buffer.String())
+
+	// The initial reconciliation is not part of the measured time.
+	b.ReportMetric(float64(result.Total.Nanoseconds())/float64(b.N), "ns/op")
+}
+
+var _ = Describe("reconcile benchmark", func() {
+	var options reconcileBenchmarkOptions
+	var result *reconcileBenchmarkResult
+	var err error
+
+	BeforeEach(func() {
+		options = reconcileBenchmarkOptions{
+			Pods:       benchmarkMinimumPods,
+			Iterations: 2,
+		}
+	})
+
+	JustBeforeEach(func() {
+		result, err = runReconcileBenchmark(context.TODO(), scheme.Scheme, options)
+	})
+
+	When("the synthetic cluster is reconciled", func() {
+		It("should report the timings of the sub-reconcilers", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.InitialReconciliation).To(BeNumerically(">", 0))
+			Expect(result.Total).To(BeNumerically(">", 0))
+			Expect(result.Timings).NotTo(BeEmpty())
+			Expect(result.Timings[0].Name).To(Equal("controllers.updateStatus"))
+			for _, timing := range result.Timings {
+				// Some sub-reconcilers, like updateStatus, run multiple times per reconciliation.
+				Expect(timing.Runs).To(BeNumerically(">=", options.Iterations), timing.Name)
+				Expect(timing.Min).To(BeNumerically("<=", timing.average()), timing.Name)
+				Expect(timing.Max).To(BeNumerically(">=", timing.average()), timing.Name)
+			}
+		})
+
+		It("should print the timings", func() {
+			Expect(err).NotTo(HaveOccurred())
+			var buffer bytes.Buffer
+			Expect(result.print(&buffer)).To(Succeed())
+			Expect(buffer.String()).To(HavePrefix("Reconcile benchmark with 8 Pods and 2 iterations\n"))
+			Expect(buffer.String()).To(ContainSubstring("controllers.updateStatus"))
+		})
+	})
+
+	When("the cluster has too few Pods", func() {
+		BeforeEach(func() {
+			options.Pods = 3
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(MatchError("the reconcile benchmark requires at least 8 Pods, got 3"))
+		})
+	})
+
+	When("no iterations are defined", func() {
+		BeforeEach(func() {
+			options.Iterations = 0
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(MatchError("the reconcile benchmark requires at least one iteration, got 0"))
+		})
+	})
+})
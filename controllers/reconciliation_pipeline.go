@@ -0,0 +1,255 @@
+/*
+ * reconciliation_pipeline.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// ReconciliationStep describes a custom step that can be added to the reconciliation pipeline of the
+// FoundationDBClusterReconciler, e.g. to run additional checks before Pods are deleted.
+type ReconciliationStep interface {
+	// Reconcile runs the work of the step. If the reconciliation can continue, this should return nil.
+	Reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *ReconciliationStepResult
+}
+
+// ReconciliationStepFunc allows to use a function as a ReconciliationStep.
+type ReconciliationStepFunc func(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *ReconciliationStepResult
+
+// Reconcile runs the work of the step.
+func (f ReconciliationStepFunc) Reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *ReconciliationStepResult {
+	return f(ctx, r, cluster)
+}
+
+// ReconciliationStepResult defines why a custom reconciliation step couldn't complete its work.
+type ReconciliationStepResult struct {
+	// Error defines the error that forced a requeue.
+	Error error
+
+	// Message provides a human-readable explanation why the reconciliation was requeued.
+	Message string
+
+	// Delay defines an optional delay before the reconciliation is requeued.
+	Delay time.Duration
+
+	// DelayedRequeue defines that the remaining steps should still run and the requeue should be delayed to the end.
+	DelayedRequeue bool
+}
+
+// registeredReconciliationStep defines a custom step and its position in the reconciliation pipeline.
+type registeredReconciliationStep struct {
+	// step is the custom step.
+	step customReconciliationStep
+
+	// anchor is the name of the step this step will be inserted next to.
+	anchor string
+
+	// before defines if the step will be inserted before or after the anchor.
+	before bool
+}
+
+// customReconciliationStep adapts a ReconciliationStep to the clusterSubReconciler interface.
+type customReconciliationStep struct {
+	// stepName is the name of the step that is used in the logs and in the cluster status.
+	stepName string
+
+	// step is the custom step.
+	step ReconciliationStep
+}
+
+// name returns the name of the step.
+func (s customReconciliationStep) name() string {
+	return s.stepName
+}
+
+// reconcile runs the reconciler's work.
+func (s customReconciliationStep) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	result := s.step.Reconcile(ctx, r, cluster)
+	if result == nil {
+		return nil
+	}
+
+	return &requeue{
+		curError:       result.Error,
+		message:        result.Message,
+		delay:          result.Delay,
+		delayedRequeue: result.DelayedRequeue,
+	}
+}
+
+// AddReconciliationStepBefore adds a custom step to the reconciliation pipeline before the step with the name anchor.
+// The names of the steps are the names reported in the logs and in the reconciliationBlocked status, e.g.
+// "controllers.updatePods". If the anchor occurs multiple times in the pipeline, the step is added next to the
+// first occurrence. Steps must be added before the reconciler is started.
+func (r *FoundationDBClusterReconciler) AddReconciliationStepBefore(anchor string, name string, step ReconciliationStep) error {
+	return r.addReconciliationStep(anchor, name, step, true)
+}
+
+// AddReconciliationStepAfter adds a custom step to the reconciliation pipeline after the step with the name anchor.
+// See AddReconciliationStepBefore for the naming of the steps.
+func (r *FoundationDBClusterReconciler) AddReconciliationStepAfter(anchor string, name string, step ReconciliationStep) error {
+	return r.addReconciliationStep(anchor, name, step, false)
+}
+
+// addReconciliationStep validates and registers a custom step.
+func (r *FoundationDBClusterReconciler) addReconciliationStep(anchor string, name string, step ReconciliationStep, before bool) error {
+	if name == "" {
+		return fmt.Errorf("reconciliation step must have a name")
+	}
+
+	if step == nil {
+		return fmt.Errorf("reconciliation step %s must not be nil", name)
+	}
+
+	names := map[string]fdbv1beta2.None{}
+	for _, subReconciler := range getDefaultSubReconcilers() {
+		names[getSubReconcilerName(subReconciler)] = fdbv1beta2.None{}
+	}
+	for _, registered := range r.reconciliationSteps {
+		names[registered.step.stepName] = fdbv1beta2.None{}
+	}
+
+	if _, ok := names[name]; ok {
+		return fmt.Errorf("reconciliation step %s already exists", name)
+	}
+
+	if _, ok := names[anchor]; !ok {
+		return fmt.Errorf("unknown reconciliation step %s", anchor)
+	}
+
+	r.reconciliationSteps = append(r.reconciliationSteps, registeredReconciliationStep{
+		step: customReconciliationStep{
+			stepName: name,
+			step:     step,
+		},
+		anchor: anchor,
+		before: before,
+	})
+
+	return nil
+}
+
+// GetReconciliationSteps returns the names of the steps that will be run for the cluster in the order of execution.
+func (r *FoundationDBClusterReconciler) GetReconciliationSteps(cluster *fdbv1beta2.FoundationDBCluster) []string {
+	subReconcilers := r.getSubReconcilers(cluster)
+	names := make([]string, 0, len(subReconcilers))
+	for _, subReconciler := range subReconcilers {
+		names = append(names, getSubReconcilerName(subReconciler))
+	}
+
+	return names
+}
+
+// getSubReconcilers returns the sub-reconcilers for the cluster including the custom steps. Custom steps whose
+// anchor is not part of the pipeline for this cluster are skipped.
+func (r *FoundationDBClusterReconciler) getSubReconcilers(cluster *fdbv1beta2.FoundationDBCluster) []clusterSubReconciler {
+	var subReconcilers []clusterSubReconciler
+	// If the operator only manages the connection to an existing cluster we must not touch any Pods, PVCs or
+	// Services, so only the status and the client configuration will be reconciled.
+	if cluster.IsManagedConnectionOnly() {
+		subReconcilers = []clusterSubReconciler{
+			updateStatus{},
+			updateConfigMap{},
+			updateStatus{},
+		}
+	} else {
+		subReconcilers = getDefaultSubReconcilers()
+	}
+
+	for _, registered := range r.reconciliationSteps {
+		index := -1
+		for idx, subReconciler := range subReconcilers {
+			if getSubReconcilerName(subReconciler) == registered.anchor {
+				index = idx
+				break
+			}
+		}
+
+		if index < 0 {
+			continue
+		}
+
+		if !registered.before {
+			index++
+		}
+
+		subReconcilers = append(subReconcilers[:index], append([]clusterSubReconciler{registered.step}, subReconcilers[index:]...)...)
+	}
+
+	return subReconcilers
+}
+
+// getDefaultSubReconcilers returns the sub-reconcilers of the default reconciliation pipeline.
+func getDefaultSubReconcilers() []clusterSubReconciler {
+	return []clusterSubReconciler{
+		updateStatus{},
+		sendNotifications{},
+		collectCrashReports{},
+		updateLockConfiguration{},
+		updateWorkJournal{},
+		updateConfigMap{},
+		updateAuthorization{},
+		updateTraceEventReceiverToken{},
+		updateProcessEnvironment{},
+		checkClientCompatibility{},
+		deletePodsForBuggification{},
+		updatePodMetadata{},
+		replaceMisconfiguredProcessGroups{},
+		replaceFailedProcessGroups{},
+		decommissionFaultDomains{},
+		reassignProcessClasses{},
+		autoscaleStorageProcesses{},
+		addProcessGroups{},
+		updateCertificates{},
+		checkCertificateExpiry{},
+		addServices{},
+		addPVCs{},
+		addPods{},
+		generateInitialClusterFile{},
+		removeIncompatibleProcesses{},
+		updateSidecarVersions{},
+		updatePodConfig{},
+		verifyClusterFiles{},
+		rotateConnectionString{},
+		updateLabels{},
+		updateDatabaseConfiguration{},
+		updateTenants{},
+		updateTagQuotas{},
+		updateDataDistributionMode{},
+		chooseRemovals{},
+		includeReusedAddresses{},
+		excludeProcesses{},
+		migrateExternalCluster{},
+		changeCoordinators{},
+		updateExternalAccess{},
+		bounceProcesses{},
+		bounceRenewedCertificates{},
+		maintenanceModeChecker{},
+		updatePods{},
+		removeProcessGroups{},
+		removeServices{},
+		updateStatus{},
+	}
+}
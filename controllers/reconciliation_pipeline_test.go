@@ -0,0 +1,172 @@
+/*
+ * reconciliation_pipeline_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("reconciliation_pipeline", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var reconciler *FoundationDBClusterReconciler
+	noopStep := ReconciliationStepFunc(func(_ context.Context, _ *FoundationDBClusterReconciler, _ *fdbv1beta2.FoundationDBCluster) *ReconciliationStepResult {
+		return nil
+	})
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		reconciler = &FoundationDBClusterReconciler{}
+	})
+
+	When("no custom steps are added", func() {
+		It("should return the default pipeline", func() {
+			steps := reconciler.GetReconciliationSteps(cluster)
+			Expect(steps).To(HaveLen(len(getDefaultSubReconcilers())))
+			Expect(steps[0]).To(Equal("controllers.updateStatus"))
+			Expect(steps[len(steps)-1]).To(Equal("controllers.updateStatus"))
+		})
+	})
+
+	When("adding custom steps", func() {
+		BeforeEach(func() {
+			Expect(reconciler.AddReconciliationStepBefore("controllers.updatePods", "complianceGate", noopStep)).NotTo(HaveOccurred())
+			Expect(reconciler.AddReconciliationStepAfter("complianceGate", "audit", noopStep)).NotTo(HaveOccurred())
+			Expect(reconciler.AddReconciliationStepAfter("controllers.updateStatus", "afterStatus", noopStep)).NotTo(HaveOccurred())
+		})
+
+		It("should insert the steps at the right position", func() {
+			steps := reconciler.GetReconciliationSteps(cluster)
+			Expect(steps).To(HaveLen(len(getDefaultSubReconcilers()) + 3))
+			Expect(steps[:3]).To(Equal([]string{"controllers.updateStatus", "afterStatus", "controllers.sendNotifications"}))
+
+			var index int
+			for idx, step := range steps {
+				if step == "controllers.updatePods" {
+					index = idx
+				}
+			}
+			Expect(steps[index-2 : index+1]).To(Equal([]string{"complianceGate", "audit", "controllers.updatePods"}))
+		})
+
+		It("should reject a duplicate name", func() {
+			Expect(reconciler.AddReconciliationStepBefore("controllers.updatePods", "audit", noopStep)).To(HaveOccurred())
+			Expect(reconciler.AddReconciliationStepBefore("controllers.updatePods", "controllers.updateLabels", noopStep)).To(HaveOccurred())
+		})
+
+		It("should reject an unknown anchor", func() {
+			Expect(reconciler.AddReconciliationStepBefore("controllers.missing", "test", noopStep)).To(HaveOccurred())
+		})
+
+		It("should reject a step without a name", func() {
+			Expect(reconciler.AddReconciliationStepBefore("controllers.updatePods", "", noopStep)).To(HaveOccurred())
+		})
+
+		When("the cluster only manages the connection", func() {
+			BeforeEach(func() {
+				cluster.Spec.ManagedConnectionOnly = pointer.Bool(true)
+			})
+
+			It("should skip the steps with an anchor that is not part of the pipeline", func() {
+				Expect(reconciler.GetReconciliationSteps(cluster)).To(Equal([]string{
+					"controllers.updateStatus",
+					"afterStatus",
+					"controllers.updateConfigMap",
+					"controllers.updateStatus",
+				}))
+			})
+		})
+	})
+
+	When("a custom step blocks the reconciliation", func() {
+		var calls int
+
+		BeforeEach(func() {
+			Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+			calls = 0
+			Expect(clusterReconciler.AddReconciliationStepBefore("controllers.updatePods", "complianceGate", ReconciliationStepFunc(func(_ context.Context, _ *FoundationDBClusterReconciler, _ *fdbv1beta2.FoundationDBCluster) *ReconciliationStepResult {
+				calls++
+				return &ReconciliationStepResult{Message: "waiting for approval", DelayedRequeue: true}
+			}))).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			clusterReconciler.reconciliationSteps = nil
+		})
+
+		It("should run the step and report it in the status", func() {
+			Expect(calls).To(BeNumerically(">", 0))
+			Expect(cluster.Status.ReconciliationBlocked).NotTo(BeNil())
+			Expect(cluster.Status.ReconciliationBlocked.SubReconciler).To(Equal("complianceGate"))
+			Expect(cluster.Status.ReconciliationBlocked.Message).To(Equal("waiting for approval"))
+		})
+	})
+
+	When("multiple custom steps delay the reconciliation", func() {
+		var result reconcile.Result
+
+		BeforeEach(func() {
+			Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+			for name, delay := range map[string]time.Duration{
+				"noDelayGate":    0,
+				"longDelayGate":  5 * time.Minute,
+				"shortDelayGate": 2 * time.Minute,
+			} {
+				stepDelay := delay
+				Expect(clusterReconciler.AddReconciliationStepBefore("controllers.updatePods", name, ReconciliationStepFunc(func(_ context.Context, _ *FoundationDBClusterReconciler, _ *fdbv1beta2.FoundationDBCluster) *ReconciliationStepResult {
+					return &ReconciliationStepResult{Message: "waiting", Delay: stepDelay, DelayedRequeue: true}
+				}))).NotTo(HaveOccurred())
+			}
+
+			var err error
+			result, err = clusterReconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			clusterReconciler.reconciliationSteps = nil
+		})
+
+		It("should requeue after the smallest non-zero delay", func() {
+			Expect(result.Requeue).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(2 * time.Minute))
+			Expect(cluster.Status.ReconciliationBlocked).NotTo(BeNil())
+			Expect(cluster.Status.ReconciliationBlocked.Delay.Duration).To(Equal(2 * time.Minute))
+		})
+	})
+})
@@ -0,0 +1,165 @@
+/*
+ * recovery_freeze.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+const (
+	// recoveryStateFullyRecovered is the name of the recovery state of a database that has completed its recovery.
+	recoveryStateFullyRecovered = "fully_recovered"
+	// recoveryFreezeRetryDelay defines how long the operator waits before it checks again if an active recovery has
+	// completed.
+	recoveryFreezeRetryDelay = 15 * time.Second
+)
+
+// reportsRecoveryState returns true if the status contains the recovery state and the running version of the cluster
+// reports the recovery state.
+func reportsRecoveryState(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus) (bool, error) {
+	if status == nil || status.Cluster.RecoveryState.Name == "" {
+		return false, nil
+	}
+
+	version, err := fdbv1beta2.ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil {
+		return false, err
+	}
+
+	return version.SupportsRecoveryState(), nil
+}
+
+// isDatabaseRecovering returns true if the status reports an active recovery of the database. If the running version
+// doesn't report the recovery state, false will be returned.
+func isDatabaseRecovering(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus) (bool, error) {
+	hasRecoveryState, err := reportsRecoveryState(cluster, status)
+	if err != nil || !hasRecoveryState {
+		return false, err
+	}
+
+	return status.Cluster.RecoveryState.Name != recoveryStateFullyRecovered, nil
+}
+
+// getLastObservedRecovery returns the last time an active recovery of the database was observed. If the recovery
+// freeze is disabled, nil will be returned.
+func getLastObservedRecovery(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus) (*metav1.Time, error) {
+	if !cluster.IsRecoveryFreezeEnabled() {
+		return nil, nil
+	}
+
+	lastObservedRecovery := cluster.Status.LastObservedRecovery
+	recovering, err := isDatabaseRecovering(cluster, status)
+	if err != nil {
+		return lastObservedRecovery, err
+	}
+
+	if recovering {
+		return &metav1.Time{Time: time.Now()}, nil
+	}
+
+	return lastObservedRecovery, nil
+}
+
+// getRecoveringSince returns the time when the current active recovery of the database was first observed. If the
+// recovery freeze is disabled or the database is not in an active recovery, nil will be returned.
+func getRecoveringSince(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus) (*metav1.Time, error) {
+	if !cluster.IsRecoveryFreezeEnabled() {
+		return nil, nil
+	}
+
+	recovering, err := isDatabaseRecovering(cluster, status)
+	if err != nil {
+		return cluster.Status.RecoveringSince, err
+	}
+
+	if !recovering {
+		return nil, nil
+	}
+
+	if cluster.Status.RecoveringSince != nil {
+		return cluster.Status.RecoveringSince, nil
+	}
+
+	return &metav1.Time{Time: time.Now()}, nil
+}
+
+// checkRecoveryFreeze returns a requeue if the database is in an active recovery or if the database has not been fully
+// recovered for the recovery freeze time of the cluster. If status is nil, only the last observed recovery from the
+// cluster status will be checked. If the database stays in an active recovery for longer than the maximum recovery
+// freeze time, the destructive action will not be deferred anymore.
+func checkRecoveryFreeze(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, action string) *requeue {
+	if !cluster.IsRecoveryFreezeEnabled() {
+		return nil
+	}
+
+	hasRecoveryState, err := reportsRecoveryState(cluster, status)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if hasRecoveryState && status.Cluster.RecoveryState.Name != recoveryStateFullyRecovered {
+		recoveringSince := cluster.Status.RecoveringSince
+		if recoveringSince != nil && time.Since(recoveringSince.Time) >= cluster.GetMaxRecoveryFreezeTime() {
+			logger.Info("Database is in an active recovery for longer than the maximum recovery freeze time, performing the destructive action", "action", action, "recoveryState", status.Cluster.RecoveryState.Name, "recoveringSince", recoveringSince.Time)
+			return nil
+		}
+
+		logger.Info("Deferring destructive action during an active recovery", "action", action, "recoveryState", status.Cluster.RecoveryState.Name)
+		return &requeue{
+			message:        fmt.Sprintf("Deferring %s while the database is in recovery state %s", action, status.Cluster.RecoveryState.Name),
+			delay:          recoveryFreezeRetryDelay,
+			delayedRequeue: true,
+		}
+	}
+
+	freezeTime := cluster.GetRecoveryFreezeTime()
+	var waitTime time.Duration
+	lastObservedRecovery := cluster.Status.LastObservedRecovery
+	if lastObservedRecovery != nil {
+		if remaining := freezeTime - time.Since(lastObservedRecovery.Time); remaining > waitTime {
+			waitTime = remaining
+		}
+	}
+
+	if hasRecoveryState {
+		sinceRecovery := time.Duration(status.Cluster.RecoveryState.SecondsSinceLastRecovered * float64(time.Second))
+		if remaining := freezeTime - sinceRecovery; remaining > waitTime {
+			waitTime = remaining
+		}
+	}
+
+	if waitTime <= 0 {
+		return nil
+	}
+
+	logger.Info("Waiting for the database to be recovered for the recovery freeze time", "action", action, "waitTime", waitTime)
+	return &requeue{
+		message:        fmt.Sprintf("Waiting %s for the database to be recovered before %s", waitTime.Round(time.Second), action),
+		delay:          waitTime,
+		delayedRequeue: true,
+	}
+}
@@ -0,0 +1,259 @@
+/*
+ * recovery_freeze_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("recovery_freeze", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var status *fdbv1beta2.FoundationDBStatus
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Version = fdbv1beta2.Versions.SupportsRecoveryState.String()
+		cluster.Status.RunningVersion = cluster.Spec.Version
+		cluster.Spec.AutomationOptions.RecoveryFreezeSeconds = pointer.Int(300)
+		status = &fdbv1beta2.FoundationDBStatus{
+			Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+				RecoveryState: fdbv1beta2.RecoveryState{
+					Name:                      recoveryStateFullyRecovered,
+					SecondsSinceLastRecovered: 3600,
+				},
+			},
+		}
+	})
+
+	When("checking the recovery freeze", func() {
+		var result *requeue
+
+		JustBeforeEach(func() {
+			result = checkRecoveryFreeze(logr.Discard(), cluster, status, "bouncing processes")
+		})
+
+		When("the recovery freeze is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.RecoveryFreezeSeconds = nil
+				status.Cluster.RecoveryState.Name = "accepting_commits"
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+
+		When("the database is fully recovered for longer than the recovery freeze time", func() {
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+
+		When("the database is in an active recovery", func() {
+			BeforeEach(func() {
+				status.Cluster.RecoveryState.Name = "accepting_commits"
+				status.Cluster.RecoveryState.SecondsSinceLastRecovered = 1
+			})
+
+			It("should requeue with the retry delay", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delay).To(Equal(recoveryFreezeRetryDelay))
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.message).To(Equal("Deferring bouncing processes while the database is in recovery state accepting_commits"))
+			})
+
+			When("the recovery is active for less than the maximum recovery freeze time", func() {
+				BeforeEach(func() {
+					cluster.Status.RecoveringSince = &metav1.Time{Time: time.Now().Add(-30 * time.Minute)}
+				})
+
+				It("should requeue with the retry delay", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.delay).To(Equal(recoveryFreezeRetryDelay))
+				})
+			})
+
+			When("the recovery is active for longer than the maximum recovery freeze time", func() {
+				BeforeEach(func() {
+					cluster.Status.RecoveringSince = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+				})
+
+				It("should not requeue", func() {
+					Expect(result).To(BeNil())
+				})
+
+				When("the maximum recovery freeze time is increased", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.MaxRecoveryFreezeSeconds = pointer.Int(3 * 3600)
+					})
+
+					It("should requeue with the retry delay", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.delay).To(Equal(recoveryFreezeRetryDelay))
+					})
+				})
+			})
+
+			When("the running version doesn't report the recovery state", func() {
+				BeforeEach(func() {
+					cluster.Spec.Version = fdbv1beta2.Versions.Default.String()
+					cluster.Status.RunningVersion = cluster.Spec.Version
+				})
+
+				It("should not requeue", func() {
+					Expect(result).To(BeNil())
+				})
+			})
+		})
+
+		When("the last recovery is more recent than the recovery freeze time", func() {
+			BeforeEach(func() {
+				status.Cluster.RecoveryState.SecondsSinceLastRecovered = 60
+			})
+
+			It("should requeue for the remaining recovery freeze time", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delay).To(BeNumerically("~", 240*time.Second, time.Second))
+				Expect(result.delayedRequeue).To(BeTrue())
+			})
+		})
+
+		When("the last observed recovery is more recent than the recovery freeze time", func() {
+			BeforeEach(func() {
+				cluster.Status.LastObservedRecovery = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+			})
+
+			It("should requeue for the remaining recovery freeze time", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delay).To(BeNumerically("~", 180*time.Second, time.Second))
+			})
+
+			When("no database status is provided", func() {
+				BeforeEach(func() {
+					status = nil
+				})
+
+				It("should requeue for the remaining recovery freeze time", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.delay).To(BeNumerically("~", 180*time.Second, time.Second))
+				})
+			})
+		})
+	})
+
+	When("getting the last observed recovery", func() {
+		var lastObservedRecovery *metav1.Time
+		var previous *metav1.Time
+
+		BeforeEach(func() {
+			previous = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			cluster.Status.LastObservedRecovery = previous
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			lastObservedRecovery, err = getLastObservedRecovery(cluster, status)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("the database is fully recovered", func() {
+			It("should keep the previous observation", func() {
+				Expect(lastObservedRecovery).To(Equal(previous))
+			})
+		})
+
+		When("the database is in an active recovery", func() {
+			BeforeEach(func() {
+				status.Cluster.RecoveryState.Name = "accepting_commits"
+			})
+
+			It("should record the current time", func() {
+				Expect(lastObservedRecovery).NotTo(BeNil())
+				Expect(lastObservedRecovery.Time).To(BeTemporally("~", time.Now(), time.Second))
+			})
+		})
+
+		When("the recovery freeze is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.RecoveryFreezeSeconds = nil
+				status.Cluster.RecoveryState.Name = "accepting_commits"
+			})
+
+			It("should reset the observation", func() {
+				Expect(lastObservedRecovery).To(BeNil())
+			})
+		})
+	})
+
+	When("getting the start of the active recovery", func() {
+		var recoveringSince *metav1.Time
+
+		JustBeforeEach(func() {
+			var err error
+			recoveringSince, err = getRecoveringSince(cluster, status)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("the database is fully recovered", func() {
+			BeforeEach(func() {
+				cluster.Status.RecoveringSince = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			})
+
+			It("should reset the observation", func() {
+				Expect(recoveringSince).To(BeNil())
+			})
+		})
+
+		When("the database is in an active recovery", func() {
+			BeforeEach(func() {
+				status.Cluster.RecoveryState.Name = "accepting_commits"
+			})
+
+			It("should record the current time", func() {
+				Expect(recoveringSince).NotTo(BeNil())
+				Expect(recoveringSince.Time).To(BeTemporally("~", time.Now(), time.Second))
+			})
+
+			When("the recovery was observed before", func() {
+				var previous *metav1.Time
+
+				BeforeEach(func() {
+					previous = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+					cluster.Status.RecoveringSince = previous
+				})
+
+				It("should keep the previous observation", func() {
+					Expect(recoveringSince).To(Equal(previous))
+				})
+			})
+		})
+	})
+})
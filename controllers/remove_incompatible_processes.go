@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -49,7 +50,7 @@ func (removeIncompatibleProcesses) reconcile(ctx context.Context, r *FoundationD
 }
 
 func processIncompatibleProcesses(ctx context.Context, r *FoundationDBClusterReconciler, logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster) error {
-	if !r.EnableRestartIncompatibleProcesses {
+	if !r.getRuntimeSettings().EnableRestartIncompatibleProcesses {
 		logger.Info("skipping disabled subreconciler")
 		return nil
 	}
@@ -70,7 +71,7 @@ func processIncompatibleProcesses(ctx context.Context, r *FoundationDBClusterRec
 	}
 	defer adminClient.Close()
 
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
 	if err != nil {
 		return err
 	}
@@ -110,6 +111,10 @@ func processIncompatibleProcesses(ctx context.Context, r *FoundationDBClusterRec
 		}
 	}
 
+	if len(incompatiblePods) > 0 {
+		r.recordAction(cluster, fmt.Sprintf("recreated Pods %v", getPodNames(incompatiblePods)), "processes have an incompatible version")
+	}
+
 	// Do an unsafe update of the Pods since they are not reachable anyway
 	return r.PodLifecycleManager.UpdatePods(ctx, r, cluster, incompatiblePods, true)
 }
@@ -25,9 +25,11 @@ import (
 	"fmt"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/buggify"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/removals"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/go-logr/logr"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
@@ -43,28 +45,31 @@ type removeProcessGroups struct{}
 // reconcile runs the reconciler's work.
 func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "removeProcessGroups")
-	adminClient, err := r.DatabaseClientProvider.GetAdminClient(cluster, r)
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 	defer adminClient.Close()
 
-	remainingMap, err := removals.GetRemainingMap(logger, adminClient, cluster)
+	// The update status step is not able to mark process groups for removal if the database is unavailable.
+	processGroupsWithoutExclusion := getProcessGroupsWithoutExclusion(cluster)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if _, ok := processGroupsWithoutExclusion[processGroup.ProcessGroupID]; ok {
+			markProcessGroupForRemoval(processGroup, processGroupsWithoutExclusion)
+		}
+	}
 
+	remainingMap, err := removals.GetRemainingMap(ctx, logger, adminClient, cluster)
 	if err != nil {
-		return &requeue{curError: err}
+		return handleRemovalError(ctx, logger, r, adminClient, cluster, err)
 	}
 
-	allExcluded, newExclusions, processGroupsToRemove := r.getProcessGroupsToRemove(cluster, remainingMap)
-	// If no process groups are marked to remove we have to check if all process groups are excluded.
-	if len(processGroupsToRemove) == 0 {
-		if !allExcluded {
-			return &requeue{message: "Reconciliation needs to exclude more processes"}
-		}
-		return nil
+	allExcluded, newExclusions, processGroupsToRemove, err := r.getProcessGroupsToRemove(ctx, cluster, remainingMap)
+	if err != nil {
+		return handleRemovalError(ctx, logger, r, adminClient, cluster, err)
 	}
 
-	// Update the cluster to reflect the new exclusions in our status
+	// Update the cluster to reflect the new exclusions and timed out exclusions in our status
 	if newExclusions {
 		err = r.updateOrApply(ctx, cluster)
 		if err != nil {
@@ -72,6 +77,14 @@ func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClust
 		}
 	}
 
+	// If no process groups are marked to remove we have to check if all process groups are excluded.
+	if len(processGroupsToRemove) == 0 {
+		if !allExcluded {
+			return &requeue{message: "Reconciliation needs to exclude more processes"}
+		}
+		return nil
+	}
+
 	// Ensure we only remove process groups that are not blocked to be removed by the buggify config.
 	processGroupsToRemove = buggify.FilterBlockedRemovals(cluster, processGroupsToRemove)
 	// If all of the process groups are filtered out we can stop doing the next steps.
@@ -79,9 +92,9 @@ func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClust
 		return nil
 	}
 
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
 	if err != nil {
-		return &requeue{curError: err}
+		return handleRemovalError(ctx, logger, r, adminClient, cluster, err)
 	}
 
 	// We don't use the "cached" of the cluster status from the CRD to minimize the window between data loss (e.g. a node
@@ -97,6 +110,18 @@ func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClust
 		}
 	}
 
+	if req := checkRecoveryFreeze(logger, cluster, status, "removing process groups"); req != nil {
+		return req
+	}
+
+	if req := checkRestoreInProgress(ctx, logger, r, cluster, "removing process groups"); req != nil {
+		return req
+	}
+
+	if req := checkBounceSchedule(logger, cluster, status, "removing process groups", time.Now()); req != nil {
+		return req
+	}
+
 	// In addition to that we should add the same logic as in the exclude step
 	// to ensure we never exclude/remove more process groups than desired.
 	zonedRemovals, lastDeletion, err := removals.GetZonedRemovals(status, processGroupsToRemove)
@@ -104,6 +129,13 @@ func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClust
 		return &requeue{curError: err}
 	}
 
+	return r.removeZonedProcessGroups(ctx, logger, cluster, zonedRemovals, lastDeletion, true)
+}
+
+// removeZonedProcessGroups removes the process groups of the next zone based on the removal mode. If includeProcesses
+// is true the processes of the removed process groups will be included afterwards, otherwise the removed process
+// groups will only be removed from the cluster status.
+func (r *FoundationDBClusterReconciler) removeZonedProcessGroups(ctx context.Context, logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, zonedRemovals map[string][]fdbv1beta2.ProcessGroupID, lastDeletion int64, includeProcesses bool) *requeue {
 	// If the operator is allowed to delete all process groups at the same time we don't enforce any safety checks.
 	if cluster.GetRemovalMode() != fdbv1beta2.PodUpdateModeAll {
 		// To ensure we are not deletion zones faster than Kubernetes actually removes Pods we are adding a wait time
@@ -120,66 +152,224 @@ func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClust
 		return &requeue{curError: err}
 	}
 
+	if len(zoneRemovals) > 0 {
+		if req := checkActionBudget(logger, r, cluster, "removing process groups"); req != nil {
+			return req
+		}
+
+		if remaining := getRemainingActionBudget(r, cluster); len(zoneRemovals) > remaining {
+			logger.Info("Limiting process group removals to the remaining action budget", "count", len(zoneRemovals), "remaining", remaining)
+			zoneRemovals = zoneRemovals[:remaining]
+		}
+	}
+
 	logger.Info("Removing process groups", "zone", zone, "count", len(zoneRemovals), "deletionMode", cluster.GetRemovalMode())
 
 	// This will return a map of the newly removed ProcessGroups and the ProcessGroups with the ResourcesTerminating condition
-	removedProcessGroups := r.removeProcessGroups(ctx, cluster, zoneRemovals, zonedRemovals[removals.TerminatingZone])
-
-	err = includeProcessGroup(ctx, r, cluster, removedProcessGroups)
+	removedProcessGroups, deletedPods := r.removeProcessGroups(ctx, cluster, zoneRemovals, zonedRemovals[removals.TerminatingZone])
+	err = consumeActionBudget(ctx, r, cluster, deletedPods)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 
+	if includeProcesses {
+		err = includeProcessGroup(ctx, r, cluster, removedProcessGroups)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return nil
+	}
+
+	// The processes of those process groups were never excluded, so there is nothing to include and the process
+	// groups can be removed from the status.
+	if len(removedProcessGroups) > 0 {
+		_ = getProcessesToInclude(cluster, removedProcessGroups)
+		err = r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
 	return nil
 }
 
-func removeProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) error {
+// handleRemovalError checks if the error was caused by an unavailable database. In this case the process groups that
+// should be removed without exclusion will be removed, for all other errors the removal will be retried.
+func handleRemovalError(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, adminClient fdbadminclient.AdminClient, cluster *fdbv1beta2.FoundationDBCluster, err error) *requeue {
+	if !isDatabaseUnavailable(ctx, adminClient, err) {
+		return &requeue{curError: err}
+	}
+
+	return removeProcessGroupsWithoutDatabase(ctx, logger, r, cluster, err)
+}
+
+// isDatabaseUnavailable returns true if the error is a timeout error and a new status request confirms that the
+// database is unavailable. Other errors, e.g. from the Kubernetes API or a wrong configuration, must not be treated
+// as an unavailable database.
+func isDatabaseUnavailable(ctx context.Context, adminClient fdbadminclient.AdminClient, err error) bool {
+	if !internal.IsTimeoutError(err) {
+		return false
+	}
+
+	status, err := adminClient.GetStatus(ctx)
+	if err != nil {
+		return internal.IsTimeoutError(err)
+	}
+
+	return !status.Client.DatabaseStatus.Available
+}
+
+// removeProcessGroupsWithoutDatabase removes the process groups defined in processGroupsToRemoveWithoutExclusion if the
+// database is unavailable. Those process groups will never be excluded, so their removal doesn't have to wait for the
+// database, e.g. if FDB is permanently down or the data is already lost. All other removals, including the forced
+// removals after an exclusion timeout, stay blocked until the database is available again.
+func removeProcessGroupsWithoutDatabase(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, databaseErr error) *requeue {
+	if len(cluster.Spec.ProcessGroupsToRemoveWithoutExclusion) == 0 {
+		return &requeue{curError: databaseErr, delayedRequeue: true}
+	}
+
+	coordinators, err := getCoordinatorsFromConnectionString(cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	processGroupsWithoutExclusion := getProcessGroupsWithoutExclusion(cluster)
+	processGroupsToRemove := make([]*fdbv1beta2.ProcessGroupStatus, 0, len(processGroupsWithoutExclusion))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if _, ok := processGroupsWithoutExclusion[processGroup.ProcessGroupID]; !ok {
+			continue
+		}
+
+		if _, ok := coordinators[processGroup.ProcessGroupID]; ok {
+			logger.Info("Block removal of Coordinator", "processGroupID", processGroup.ProcessGroupID)
+			continue
+		}
+
+		processGroupsToRemove = append(processGroupsToRemove, processGroup)
+	}
+
+	processGroupsToRemove = buggify.FilterBlockedRemovals(cluster, processGroupsToRemove)
+	if len(processGroupsToRemove) == 0 {
+		return &requeue{curError: databaseErr, delayedRequeue: true}
+	}
+
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	zonedRemovals, lastDeletion, err := removals.GetZonedRemovalsFromPods(cluster, pods, processGroupsToRemove)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	logger.Info("Database is unavailable, removing process groups without exclusion", "error", databaseErr.Error())
+	r.Recorder.Event(cluster, corev1.EventTypeWarning, "RemovingProcessesWithoutDatabase", fmt.Sprintf("Removing pods without exclusion while the database is unavailable: %s", databaseErr.Error()))
+
+	req := r.removeZonedProcessGroups(ctx, logger, cluster, zonedRemovals, lastDeletion, false)
+	if req != nil {
+		return req
+	}
+
+	return &requeue{curError: databaseErr, delayedRequeue: true}
+}
+
+// getCoordinatorsFromConnectionString returns the process groups that serve as coordinators based on the connection
+// string in the cluster status. This allows to block the removal of coordinators without querying the database.
+func getCoordinatorsFromConnectionString(cluster *fdbv1beta2.FoundationDBCluster) (map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, error) {
+	coordinators := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	if cluster.Status.ConnectionString == "" {
+		return coordinators, nil
+	}
+
+	connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinatorAddresses := make(map[string]fdbv1beta2.None, len(connectionString.Coordinators))
+	for _, coordinator := range connectionString.Coordinators {
+		address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+		if err != nil {
+			return nil, err
+		}
+
+		machineAddress := address.MachineAddress()
+		coordinatorAddresses[machineAddress] = fdbv1beta2.None{}
+
+		// If DNS names are used in the cluster file the first part of the DNS name is the Pod name.
+		if address.StringAddress != "" {
+			podName := strings.Split(machineAddress, ".")[0]
+			if strings.HasPrefix(podName, cluster.Name+"-") {
+				coordinators[internal.GetProcessGroupIDFromPodName(cluster, podName)] = fdbv1beta2.None{}
+			}
+		}
+	}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		for _, address := range processGroup.Addresses {
+			if _, ok := coordinatorAddresses[address]; ok {
+				coordinators[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+				break
+			}
+		}
+	}
+
+	return coordinators, nil
+}
+
+// removeProcessGroup deletes the Pod, PVC and Service of the process group. The returned bool is true if a Pod was
+// deleted, so the deletion can be counted against the action budget.
+func removeProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) (bool, error) {
 	listOptions := internal.GetSinglePodListOptions(cluster, processGroupID)
 	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, listOptions...)
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	var deletedPod bool
 	if len(pods) == 1 && pods[0].DeletionTimestamp.IsZero() {
 		err = r.PodLifecycleManager.DeletePod(ctx, r, pods[0])
 		if err != nil {
-			return err
+			return false, err
 		}
+		deletedPod = true
 	} else if len(pods) > 1 {
-		return fmt.Errorf("multiple pods found for cluster %s, processGroupID %s", cluster.Name, processGroupID)
+		return false, fmt.Errorf("multiple pods found for cluster %s, processGroupID %s", cluster.Name, processGroupID)
 	}
 
 	pvcs := &corev1.PersistentVolumeClaimList{}
 	err = r.List(ctx, pvcs, listOptions...)
 	if err != nil {
-		return err
+		return deletedPod, err
 	}
 	if len(pvcs.Items) == 1 && pvcs.Items[0].DeletionTimestamp.IsZero() {
 		logr.FromContextOrDiscard(ctx).V(1).Info("Deleting pvc", "name", pvcs.Items[0].Name)
 		err = r.Delete(ctx, &pvcs.Items[0])
 		if err != nil {
-			return err
+			return deletedPod, err
 		}
 	} else if len(pvcs.Items) > 1 {
-		return fmt.Errorf("multiple PVCs found for cluster %s, processGroupID %s", cluster.Name, processGroupID)
+		return deletedPod, fmt.Errorf("multiple PVCs found for cluster %s, processGroupID %s", cluster.Name, processGroupID)
 	}
 
 	services := &corev1.ServiceList{}
 	err = r.List(ctx, services, listOptions...)
 	if err != nil {
-		return err
+		return deletedPod, err
 	}
 	if len(services.Items) == 1 && services.Items[0].DeletionTimestamp.IsZero() {
 		logr.FromContextOrDiscard(ctx).V(1).Info("Deleting service", "name", services.Items[0].Name)
 		err = r.Delete(ctx, &services.Items[0])
 		if err != nil {
-			return err
+			return deletedPod, err
 		}
 	} else if len(services.Items) > 1 {
-		return fmt.Errorf("multiple services found for cluster %s, processGroupID %s", cluster.Name, processGroupID)
+		return deletedPod, fmt.Errorf("multiple services found for cluster %s, processGroupID %s", cluster.Name, processGroupID)
 	}
 
-	return nil
+	return deletedPod, nil
 }
 
 func confirmRemoval(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) (bool, bool, error) {
@@ -252,10 +442,12 @@ func includeProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 	if len(fdbProcessesToInclude) > 0 {
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "IncludingProcesses", fmt.Sprintf("Including removed processes: %v", fdbProcessesToInclude))
 
-		err = adminClient.IncludeProcesses(fdbProcessesToInclude)
+		err = adminClient.IncludeProcesses(ctx, fdbProcessesToInclude)
 		if err != nil {
 			return err
 		}
+		r.recordAction(cluster, fmt.Sprintf("included processes %v", fdbProcessesToInclude), "process groups were removed")
+		removeOperatorExclusions(cluster, fdbProcessesToInclude)
 
 		err := r.updateOrApply(ctx, cluster)
 		if err != nil {
@@ -276,6 +468,8 @@ func getProcessesToInclude(cluster *fdbv1beta2.FoundationDBCluster, removedProce
 	idx := 0
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		if processGroup.IsMarkedForRemoval() && removedProcessGroups[processGroup.ProcessGroupID] {
+			// The ID of the removed process group will not be reused until the tombstone expires.
+			cluster.AddProcessGroupTombstone(processGroup.ProcessGroupID)
 			if cluster.UseLocalitiesForExclusion() {
 				fdbProcessesToInclude = append(fdbProcessesToInclude, fdbv1beta2.ProcessAddress{StringAddress: processGroup.GetExclusionString()})
 			}
@@ -294,7 +488,7 @@ func getProcessesToInclude(cluster *fdbv1beta2.FoundationDBCluster, removedProce
 	return fdbProcessesToInclude
 }
 
-func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(cluster *fdbv1beta2.FoundationDBCluster, remainingMap map[string]bool) (bool, bool, []*fdbv1beta2.ProcessGroupStatus) {
+func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, remainingMap map[string]bool) (bool, bool, []*fdbv1beta2.ProcessGroupStatus, error) {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "removeProcessGroups")
 	var cordSet map[string]fdbv1beta2.None
 	allExcluded := true
@@ -309,11 +503,10 @@ func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(cluster *fdbv1b
 		// Only query FDB if we have a pending removal otherwise don't query FDB
 		if len(cordSet) == 0 {
 			var err error
-			cordSet, err = r.getCoordinatorSet(cluster)
+			cordSet, err = r.getCoordinatorSet(ctx, cluster)
 
 			if err != nil {
-				logger.Error(err, "Fetching coordinator set for removal")
-				return false, false, nil
+				return false, false, nil, err
 			}
 		}
 
@@ -333,6 +526,13 @@ func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(cluster *fdbv1b
 		excluded, err := processGroup.AllAddressesExcluded(remainingMap)
 		if !excluded || err != nil {
 			logger.Info("Incomplete exclusion still present in removeProcessGroups step", "processGroupID", processGroup.ProcessGroupID, "error", err)
+			timedOut, forceRemoval := r.checkExclusionTimeout(logger, cluster, processGroup)
+			newExclusions = newExclusions || timedOut || forceRemoval
+			if forceRemoval {
+				processGroupsToRemove = append(processGroupsToRemove, processGroup)
+				continue
+			}
+
 			allExcluded = false
 			continue
 		}
@@ -343,17 +543,76 @@ func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(cluster *fdbv1b
 		newExclusions = true
 	}
 
-	return allExcluded, newExclusions, processGroupsToRemove
+	return allExcluded, newExclusions, processGroupsToRemove, nil
 }
 
-func (r *FoundationDBClusterReconciler) removeProcessGroups(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, processGroupsToRemove []fdbv1beta2.ProcessGroupID, terminatingProcessGroups []fdbv1beta2.ProcessGroupID) map[fdbv1beta2.ProcessGroupID]bool {
+// checkExclusionTimeout checks if the exclusion of a process group that is marked for removal has timed out. The
+// first return value is true if the ExclusionTimedOut condition was added to the process group. The second return
+// value is true if the process group should be removed without completing the exclusion, which is only the case if
+// the processes of the process group are missing for longer than the timeout and the cluster has the desired fault
+// tolerance, so the data of the process group is already replicated to other processes.
+func (r *FoundationDBClusterReconciler) checkExclusionTimeout(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, processGroup *fdbv1beta2.ProcessGroupStatus) (bool, bool) {
+	timeout := cluster.GetExclusionTimeout()
+	if timeout <= 0 || processGroup.RemovalTimestamp == nil || time.Since(processGroup.RemovalTimestamp.Time) < timeout {
+		return false, false
+	}
+
+	var timedOut bool
+	if processGroup.GetConditionTime(fdbv1beta2.ExclusionTimedOut) == nil {
+		logger.Info("Exclusion timed out", "processGroupID", processGroup.ProcessGroupID, "timeout", timeout.String())
+		processGroup.UpdateCondition(fdbv1beta2.ExclusionTimedOut, true, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "ExclusionTimedOut", fmt.Sprintf("Exclusion of process group %s did not complete within %s", processGroup.ProcessGroupID, timeout.String()))
+		timedOut = true
+	}
+
+	if !cluster.GetForceRemovalOnExclusionTimeout() {
+		return timedOut, false
+	}
+
+	// The forced removal was already started in a previous reconciliation.
+	if processGroup.ExclusionSkipped {
+		return timedOut, true
+	}
+
+	// Processes that are still reporting might hold data that is not replicated to other processes, so only process
+	// groups whose processes are missing for the whole timeout are removed.
+	missingTime := processGroup.GetConditionTime(fdbv1beta2.MissingProcesses)
+	if missingTime == nil || time.Since(time.Unix(*missingTime, 0)) < timeout {
+		logger.Info("Skip forced removal of process group with reporting processes", "processGroupID", processGroup.ProcessGroupID, "timeout", timeout.String())
+		return timedOut, false
+	}
+
+	if !cluster.HasDesiredFaultTolerance() {
+		logger.Info("Skip forced removal of process group because cluster has degraded fault tolerance", "processGroupID", processGroup.ProcessGroupID)
+		return timedOut, false
+	}
+
+	logger.Info("Forcing removal of process group with incomplete exclusion", "processGroupID", processGroup.ProcessGroupID, "timeout", timeout.String())
+	processGroup.ExclusionSkipped = true
+	r.Recorder.Event(cluster, corev1.EventTypeWarning, "ForcedRemoval", fmt.Sprintf("Removing process group %s without completing the exclusion after %s", processGroup.ProcessGroupID, timeout.String()))
+	r.recordAction(cluster, fmt.Sprintf("forced removal of process group %s", processGroup.ProcessGroupID), fmt.Sprintf("exclusion did not complete within %s", timeout.String()))
+
+	return timedOut, true
+}
+
+// removeProcessGroups removes the resources of the provided process groups and returns the process groups that are
+// completely removed and the number of deleted Pods.
+func (r *FoundationDBClusterReconciler) removeProcessGroups(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, processGroupsToRemove []fdbv1beta2.ProcessGroupID, terminatingProcessGroups []fdbv1beta2.ProcessGroupID) (map[fdbv1beta2.ProcessGroupID]bool, int) {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "removeProcessGroups")
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "RemovingProcesses", fmt.Sprintf("Removing pods: %v", processGroupsToRemove))
+	if len(processGroupsToRemove) > 0 {
+		r.recordAction(cluster, fmt.Sprintf("removed process groups %v", processGroupsToRemove), "process groups are marked for removal and fully excluded")
+	}
 
 	processGroups := append(processGroupsToRemove, terminatingProcessGroups...)
 
+	var deletedPods int
 	for _, id := range processGroups {
-		err := removeProcessGroup(logr.NewContext(ctx, logger), r, cluster, id)
+		deletedPod, err := removeProcessGroup(logr.NewContext(ctx, logger), r, cluster, id)
+		if deletedPod {
+			deletedPods++
+		}
+
 		if err != nil {
 			logger.Error(err, "Error during remove process group", "processGroupID", id)
 			continue
@@ -378,5 +637,5 @@ func (r *FoundationDBClusterReconciler) removeProcessGroups(ctx context.Context,
 		}
 	}
 
-	return removedProcessGroups
+	return removedProcessGroups, deletedPods
 }
@@ -26,7 +26,9 @@ import (
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"github.com/go-logr/logr"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
@@ -80,13 +82,115 @@ var _ = Describe("remove_process_groups", func() {
 					coordinatorIP: false,
 				}
 
-				allExcluded, newExclusions, processes := clusterReconciler.getProcessGroupsToRemove(cluster, remaining)
+				allExcluded, newExclusions, processes, err := clusterReconciler.getProcessGroupsToRemove(context.TODO(), cluster, remaining)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(allExcluded).To(BeFalse())
 				Expect(processes).To(BeEmpty())
 				Expect(newExclusions).To(BeFalse())
 			})
 		})
 
+		When("the exclusion of a process group doesn't complete", func() {
+			var processGroupID fdbv1beta2.ProcessGroupID
+
+			BeforeEach(func() {
+				processGroup := cluster.Status.ProcessGroups[0]
+				processGroupID = processGroup.ProcessGroupID
+				processGroup.MarkForRemoval()
+				processGroup.RemovalTimestamp = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+			})
+
+			When("no exclusion timeout is defined", func() {
+				It("should not remove the process group", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.message).To(Equal("Reconciliation needs to exclude more processes"))
+					processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+					Expect(processGroup).NotTo(BeNil())
+					Expect(processGroup.GetConditionTime(fdbv1beta2.ExclusionTimedOut)).To(BeNil())
+				})
+			})
+
+			When("the exclusion timeout is exceeded", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.ExclusionTimeoutSeconds = pointer.Int(3600)
+				})
+
+				It("should add the condition but not remove the process group", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.message).To(Equal("Reconciliation needs to exclude more processes"))
+
+					_, err := reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+					Expect(processGroup).NotTo(BeNil())
+					Expect(processGroup.GetConditionTime(fdbv1beta2.ExclusionTimedOut)).NotTo(BeNil())
+					Expect(processGroup.ExclusionSkipped).To(BeFalse())
+				})
+
+				When("the forced removal is enabled", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.ForceRemovalOnExclusionTimeout = pointer.Bool(true)
+					})
+
+					When("the processes of the process group are still reporting", func() {
+						It("should not remove the process group", func() {
+							Expect(result).NotTo(BeNil())
+							Expect(result.message).To(Equal("Reconciliation needs to exclude more processes"))
+							processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+							Expect(processGroup).NotTo(BeNil())
+							Expect(processGroup.ExclusionSkipped).To(BeFalse())
+						})
+					})
+
+					When("the processes of the process group are missing for the exclusion timeout", func() {
+						BeforeEach(func() {
+							processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+							processGroup.ProcessGroupConditions = append(processGroup.ProcessGroupConditions, &fdbv1beta2.ProcessGroupCondition{
+								ProcessGroupConditionType: fdbv1beta2.MissingProcesses,
+								Timestamp:                 time.Now().Add(-2 * time.Hour).Unix(),
+							})
+						})
+
+						It("should remove the process group without completing the exclusion", func() {
+							Expect(result).To(BeNil())
+							Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)).To(BeNil())
+						})
+					})
+
+					When("the processes of the process group are missing for less than the exclusion timeout", func() {
+						BeforeEach(func() {
+							processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+							processGroup.ProcessGroupConditions = append(processGroup.ProcessGroupConditions, &fdbv1beta2.ProcessGroupCondition{
+								ProcessGroupConditionType: fdbv1beta2.MissingProcesses,
+								Timestamp:                 time.Now().Add(-10 * time.Minute).Unix(),
+							})
+						})
+
+						It("should not remove the process group", func() {
+							Expect(result).NotTo(BeNil())
+							Expect(result.message).To(Equal("Reconciliation needs to exclude more processes"))
+							Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)).NotTo(BeNil())
+						})
+					})
+				})
+			})
+
+			When("the exclusion timeout is not exceeded", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.ExclusionTimeoutSeconds = pointer.Int(3 * 3600)
+					cluster.Spec.AutomationOptions.ForceRemovalOnExclusionTimeout = pointer.Bool(true)
+				})
+
+				It("should not remove the process group", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.message).To(Equal("Reconciliation needs to exclude more processes"))
+					processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+					Expect(processGroup).NotTo(BeNil())
+					Expect(processGroup.GetConditionTime(fdbv1beta2.ExclusionTimedOut)).To(BeNil())
+				})
+			})
+		})
+
 		When("removing a process group", func() {
 			var removedProcessGroup *fdbv1beta2.ProcessGroupStatus
 
@@ -112,6 +216,59 @@ var _ = Describe("remove_process_groups", func() {
 						Expect(err).To(BeNil())
 						Expect(removed).To(BeTrue())
 						Expect(include).To(BeTrue())
+						Expect(cluster.HasProcessGroupTombstone(removedProcessGroup.ProcessGroupID)).To(BeTrue())
+					})
+				})
+
+				When("an action budget is defined", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerHour = pointer.Int(10)
+					})
+
+					AfterEach(func() {
+						clusterReconciler.getActionBudget().reset(cluster)
+					})
+
+					It("should count the deleted pod against the action budget", func() {
+						Expect(result).To(BeNil())
+						Expect(clusterReconciler.getActionBudget().used(cluster)).To(Equal(1))
+						Expect(cluster.Status.ActionBudget).NotTo(BeNil())
+						Expect(cluster.Status.ActionBudget.UsedActions).To(Equal(1))
+					})
+
+					When("the action budget is exhausted", func() {
+						BeforeEach(func() {
+							cluster.Spec.AutomationOptions.ActionBudget.MaxActionsPerReconcile = pointer.Int(1)
+							clusterReconciler.getActionBudget().add(cluster, 1)
+						})
+
+						It("should not remove that process group", func() {
+							Expect(result).NotTo(BeNil())
+							Expect(result.delayedRequeue).To(BeTrue())
+							Expect(result.message).To(Equal("Action budget is exhausted, waiting before removing process groups"))
+							removed, include, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+							Expect(err).To(BeNil())
+							Expect(removed).To(BeFalse())
+							Expect(include).To(BeFalse())
+						})
+					})
+				})
+
+				When("the bounce schedule defers the removal", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.BounceScheduleOptions.Windows = []fdbv1beta2.BounceWindow{
+							{Start: time.Now().UTC().Add(2 * time.Hour).Format("15:04"), DurationMinutes: 30},
+						}
+					})
+
+					It("should not remove that process group", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.delayedRequeue).To(BeTrue())
+						Expect(result.message).To(Equal("Deferring removing process groups until the load is low or until the next bounce window"))
+						removed, include, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+						Expect(err).To(BeNil())
+						Expect(removed).To(BeFalse())
+						Expect(include).To(BeFalse())
 					})
 				})
 
@@ -218,7 +375,7 @@ var _ = Describe("remove_process_groups", func() {
 				When("a process group is marked as terminating and all resources are removed it should be removed", func() {
 					BeforeEach(func() {
 						secondRemovedProcessGroup.ProcessGroupConditions = append(secondRemovedProcessGroup.ProcessGroupConditions, fdbv1beta2.NewProcessGroupCondition(fdbv1beta2.ResourcesTerminating))
-						err := removeProcessGroup(context.Background(), clusterReconciler, cluster, secondRemovedProcessGroup.ProcessGroupID)
+						_, err := removeProcessGroup(context.Background(), clusterReconciler, cluster, secondRemovedProcessGroup.ProcessGroupID)
 						Expect(err).NotTo(HaveOccurred())
 						// Sleep here to prevent some timeing issues.
 						time.Sleep(10 * time.Microsecond)
@@ -289,6 +446,133 @@ var _ = Describe("remove_process_groups", func() {
 			})
 		})
 
+		When("the database is not reachable", func() {
+			var removedProcessGroup *fdbv1beta2.ProcessGroupStatus
+			var initialCnt int
+			var adminClient *mock.AdminClient
+
+			BeforeEach(func() {
+				initialCnt = len(cluster.Status.ProcessGroups)
+				coordinators, err := getCoordinatorsFromConnectionString(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if _, ok := coordinators[processGroup.ProcessGroupID]; ok {
+						continue
+					}
+
+					removedProcessGroup = processGroup
+					break
+				}
+				Expect(removedProcessGroup).NotTo(BeNil())
+
+				adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				adminClient.StatusError = fdbv1beta2.TimeoutError{Err: fmt.Errorf("unable to connect to the database")}
+			})
+
+			When("the process group is marked for removal with exclusion", func() {
+				BeforeEach(func() {
+					marked, processGroup := fdbv1beta2.MarkProcessGroupForRemoval(cluster.Status.ProcessGroups, removedProcessGroup.ProcessGroupID, removedProcessGroup.ProcessClass, removedProcessGroup.Addresses[0])
+					Expect(marked).To(BeTrue())
+					Expect(processGroup).To(BeNil())
+				})
+
+				It("should not remove the process group", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.curError).To(HaveOccurred())
+					Expect(len(cluster.Status.ProcessGroups)).To(Equal(initialCnt))
+					// Ensure resources are not deleted
+					removed, _, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(removed).To(BeFalse())
+				})
+			})
+
+			When("the process group is marked for removal without exclusion", func() {
+				BeforeEach(func() {
+					cluster.Spec.ProcessGroupsToRemoveWithoutExclusion = []fdbv1beta2.ProcessGroupID{removedProcessGroup.ProcessGroupID}
+					Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+				})
+
+				It("should remove the process group", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.curError).To(HaveOccurred())
+					Expect(result.delayedRequeue).To(BeTrue())
+					Expect(len(cluster.Status.ProcessGroups)).To(Equal(initialCnt - 1))
+					// Ensure resources are deleted
+					removed, _, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(removed).To(BeTrue())
+				})
+
+				When("the removal is blocked by buggify", func() {
+					BeforeEach(func() {
+						cluster.Spec.Buggify.BlockRemoval = []fdbv1beta2.ProcessGroupID{removedProcessGroup.ProcessGroupID}
+					})
+
+					It("should not remove the process group", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.curError).To(HaveOccurred())
+						Expect(len(cluster.Status.ProcessGroups)).To(Equal(initialCnt))
+					})
+				})
+
+				When("the error doesn't confirm that the database is unavailable", func() {
+					BeforeEach(func() {
+						adminClient.StatusError = fmt.Errorf("unable to connect to the database")
+					})
+
+					It("should not remove the process group", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.curError).To(HaveOccurred())
+						Expect(result.delayedRequeue).To(BeFalse())
+						Expect(len(cluster.Status.ProcessGroups)).To(Equal(initialCnt))
+						removed, _, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(removed).To(BeFalse())
+					})
+				})
+
+				When("the process group is a coordinator", func() {
+					BeforeEach(func() {
+						coordinators, err := getCoordinatorsFromConnectionString(cluster)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(coordinators).NotTo(BeEmpty())
+
+						cluster.Spec.ProcessGroupsToRemoveWithoutExclusion = nil
+						for processGroupID := range coordinators {
+							cluster.Spec.ProcessGroupsToRemoveWithoutExclusion = append(cluster.Spec.ProcessGroupsToRemoveWithoutExclusion, processGroupID)
+						}
+						Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+					})
+
+					It("should not remove the process group", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.curError).To(HaveOccurred())
+						Expect(len(cluster.Status.ProcessGroups)).To(Equal(initialCnt))
+					})
+				})
+			})
+
+			When("the process group was marked for a forced removal after the exclusion timeout", func() {
+				BeforeEach(func() {
+					marked, processGroup := fdbv1beta2.MarkProcessGroupForRemoval(cluster.Status.ProcessGroups, removedProcessGroup.ProcessGroupID, removedProcessGroup.ProcessClass, removedProcessGroup.Addresses[0])
+					Expect(marked).To(BeTrue())
+					Expect(processGroup).To(BeNil())
+					removedProcessGroup.ExclusionSkipped = true
+				})
+
+				It("should not remove the process group", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.curError).To(HaveOccurred())
+					Expect(len(cluster.Status.ProcessGroups)).To(Equal(initialCnt))
+					removed, _, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(removed).To(BeFalse())
+				})
+			})
+		})
+
 		AfterEach(func() {
 			k8sClient.Clear()
 		})
@@ -392,4 +676,60 @@ var _ = Describe("remove_process_groups", func() {
 			})
 		})
 	})
+
+	When("checking the exclusion timeout", func() {
+		var processGroup *fdbv1beta2.ProcessGroupStatus
+		var timedOut, forceRemoval bool
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.AutomationOptions.ExclusionTimeoutSeconds = pointer.Int(3600)
+			cluster.Spec.AutomationOptions.ForceRemovalOnExclusionTimeout = pointer.Bool(true)
+			cluster.Status.Health.Available = true
+			cluster.Status.FaultTolerance = &fdbv1beta2.FaultToleranceStatus{
+				MaxZoneFailuresWithoutLosingData:         1,
+				MaxZoneFailuresWithoutLosingAvailability: 1,
+			}
+			processGroup = fdbv1beta2.NewProcessGroupStatus("storage-1", fdbv1beta2.ProcessClassStorage, []string{"1.1.1.1"})
+			processGroup.MarkForRemoval()
+			processGroup.RemovalTimestamp = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+			processGroup.UpdateConditionTime(fdbv1beta2.MissingProcesses, time.Now().Add(-2*time.Hour).Unix())
+			cluster.Status.ProcessGroups = []*fdbv1beta2.ProcessGroupStatus{processGroup}
+			clusterActionHistory.takePendingActions(cluster)
+		})
+
+		JustBeforeEach(func() {
+			timedOut, forceRemoval = clusterReconciler.checkExclusionTimeout(logr.Discard(), cluster, processGroup)
+		})
+
+		It("should force the removal once", func() {
+			Expect(timedOut).To(BeTrue())
+			Expect(forceRemoval).To(BeTrue())
+			Expect(processGroup.ExclusionSkipped).To(BeTrue())
+			Expect(clusterActionHistory.takePendingActions(cluster)).To(HaveLen(1))
+
+			timedOut, forceRemoval = clusterReconciler.checkExclusionTimeout(logr.Discard(), cluster, processGroup)
+			Expect(timedOut).To(BeFalse())
+			Expect(forceRemoval).To(BeTrue())
+			Expect(clusterActionHistory.takePendingActions(cluster)).To(BeEmpty())
+		})
+
+		It("should keep the forced removal when the process group is marked for removal again", func() {
+			markProcessGroupForRemoval(processGroup, getProcessGroupsWithoutExclusion(cluster))
+			Expect(processGroup.ExclusionSkipped).To(BeTrue())
+		})
+
+		When("the cluster has degraded fault tolerance", func() {
+			BeforeEach(func() {
+				cluster.Status.FaultTolerance.MaxZoneFailuresWithoutLosingData = 0
+			})
+
+			It("should not force the removal", func() {
+				Expect(timedOut).To(BeTrue())
+				Expect(forceRemoval).To(BeFalse())
+				Expect(processGroup.ExclusionSkipped).To(BeFalse())
+				Expect(clusterActionHistory.takePendingActions(cluster)).To(BeEmpty())
+			})
+		})
+	})
 })
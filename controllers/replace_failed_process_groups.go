@@ -22,13 +22,14 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
-	"github.com/FoundationDB/fdb-kubernetes-operator/internal/replacements"
 )
 
-// replaceFailedProcessGroups identifies processes groups that have failed and need to be
-// replaced.
+// replaceFailedProcessGroups runs the detectors for failed process groups and publishes their findings to the
+// remediation handlers, e.g. the automatic replacements.
 type replaceFailedProcessGroups struct{}
 
 // return non-nil requeue if a process has been replaced
@@ -41,18 +42,55 @@ func (c replaceFailedProcessGroups) reconcile(ctx context.Context, r *Foundation
 		return nil
 	}
 
-	adminClient, err := r.DatabaseClientProvider.GetAdminClient(cluster, r)
-	if err != nil {
-		return &requeue{curError: err}
+	var findings []Finding
+	for _, detector := range getFindingDetectors() {
+		findings = append(findings, detector(cluster)...)
 	}
-	defer adminClient.Close()
 
-	if replacements.ReplaceFailedProcessGroups(logger, cluster, adminClient) {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	for _, finding := range findings {
+		logger.Info("Detected issue", "type", finding.Type, "processGroupID", finding.ProcessGroupID, "since", time.Unix(finding.Timestamp, 0).UTC().String())
+	}
+
+	markedForRemoval := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			markedForRemoval[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+		}
+	}
+
+	changed, remediationErr := r.getEventBus(cluster).publish(ctx, logger, r, cluster, findings)
+	if changed {
 		err := r.updateOrApply(ctx, cluster)
 		if err != nil {
 			return &requeue{curError: err}
 		}
 
+		var replaced []fdbv1beta2.ProcessGroupID
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			if _, ok := markedForRemoval[processGroup.ProcessGroupID]; !ok && processGroup.IsMarkedForRemoval() {
+				replaced = append(replaced, processGroup.ProcessGroupID)
+				conditions := make([]fdbv1beta2.ProcessGroupConditionType, 0, len(processGroup.ProcessGroupConditions))
+				for _, condition := range processGroup.ProcessGroupConditions {
+					conditions = append(conditions, condition.ProcessGroupConditionType)
+				}
+				r.recordAction(cluster, fmt.Sprintf("replaced process group %s", processGroup.ProcessGroupID), fmt.Sprintf("process group has failed with the conditions %v", conditions))
+			}
+		}
+
+		if len(replaced) > 0 {
+			r.notify(ctx, logger, cluster, "ProcessGroupsReplaced", fdbv1beta2.NotificationSeverityInfo, fmt.Sprintf("Replacing failed process groups: %v", replaced))
+		}
+	}
+
+	if remediationErr != nil {
+		return &requeue{curError: remediationErr}
+	}
+
+	if changed {
 		return &requeue{message: "Removals have been updated in the cluster status"}
 	}
 
@@ -105,7 +105,7 @@ var _ = Describe("replace_failed_process_groups", func() {
 			adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
 
 			Expect(err).NotTo(HaveOccurred())
-			databaseStatus, err := adminClient.GetStatus()
+			databaseStatus, err := adminClient.GetStatus(ctx.TODO())
 			Expect(err).NotTo(HaveOccurred())
 			processMap = make(map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.FoundationDBStatusProcessInfo)
 			for _, process := range databaseStatus.Cluster.Processes {
@@ -685,6 +685,37 @@ var _ = Describe("replace_failed_process_groups", func() {
 				})
 			})
 
+			When("detection only is enabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.DetectionOnly = pointer.Bool(true)
+				})
+
+				It("should return nil", func() {
+					Expect(result).To(BeNil())
+				})
+
+				It("should not mark the process group for removal", func() {
+					Expect(getRemovedProcessGroupIDs(cluster)).To(Equal([]fdbv1beta2.ProcessGroupID{}))
+				})
+			})
+
+			When("the process group is pinned", func() {
+				BeforeEach(func() {
+					cluster.Spec.PinnedProcessGroups = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessGroupPin{
+						"storage-2": {NodeName: "node-1"},
+					}
+				})
+
+				It("should requeue", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.message).To(Equal("Removals have been updated in the cluster status"))
+				})
+
+				It("should mark the process group for removal", func() {
+					Expect(getRemovedProcessGroupIDs(cluster)).To(Equal([]fdbv1beta2.ProcessGroupID{"storage-2"}))
+				})
+			})
+
 			When("Crash loop is set for all process groups", func() {
 				BeforeEach(func() {
 					cluster.Spec.Buggify.CrashLoop = []fdbv1beta2.ProcessGroupID{"*"}
@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/replacements"
 
@@ -50,6 +51,13 @@ func (c replaceMisconfiguredProcessGroups) reconcile(ctx context.Context, r *Fou
 		return &requeue{curError: err}
 	}
 
+	markedForRemoval := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			markedForRemoval[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+		}
+	}
+
 	hasReplacements, err := replacements.ReplaceMisconfiguredProcessGroups(logger, cluster, internal.CreatePVCMap(cluster, pvcs), internal.CreatePodMap(cluster, pods))
 	if err != nil {
 		return &requeue{curError: err}
@@ -61,6 +69,14 @@ func (c replaceMisconfiguredProcessGroups) reconcile(ctx context.Context, r *Fou
 			return &requeue{curError: err}
 		}
 
+		var replaced []fdbv1beta2.ProcessGroupID
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			if _, ok := markedForRemoval[processGroup.ProcessGroupID]; !ok && processGroup.IsMarkedForRemoval() {
+				replaced = append(replaced, processGroup.ProcessGroupID)
+			}
+		}
+		r.recordAction(cluster, fmt.Sprintf("replaced process groups %v", replaced), "process groups are misconfigured")
+
 		log.Info("Removals have been updated in the cluster status")
 	}
 
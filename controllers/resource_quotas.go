@@ -0,0 +1,130 @@
+/*
+ * resource_quotas.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// resourceQuotaViolations contains the resource quotas of a namespace that a cluster violates.
+type resourceQuotaViolations struct {
+	// exceeded contains the hard limits of the quotas that are lower than the resources the cluster requires. A
+	// cluster with exceeded quotas can never be scheduled completely.
+	exceeded []string
+	// insufficient contains the quotas whose remaining resources are not sufficient for the process groups of the
+	// cluster that are not created yet.
+	insufficient []string
+}
+
+// getResourceQuotaViolations estimates the resources the cluster will request and compares them against the
+// ResourceQuotas of the namespace of the cluster. Quotas with scopes only apply to a subset of the resources and are
+// ignored.
+func getResourceQuotaViolations(ctx context.Context, reader client.Reader, cluster *fdbv1beta2.FoundationDBCluster) (resourceQuotaViolations, error) {
+	violations := resourceQuotaViolations{}
+
+	quotas := &corev1.ResourceQuotaList{}
+	err := reader.List(ctx, quotas, client.InNamespace(cluster.Namespace))
+	if err != nil {
+		return violations, err
+	}
+
+	if len(quotas.Items) == 0 {
+		return violations, nil
+	}
+
+	desiredCounts, err := cluster.GetProcessCountsWithDefaults()
+	if err != nil {
+		return violations, err
+	}
+
+	// Only the process groups that are not yet created will request additional resources.
+	currentCounts := fdbv1beta2.CreateProcessCountsFromProcessGroupStatus(cluster.Status.ProcessGroups, true)
+	additionalCounts := fdbv1beta2.ProcessCounts{}
+	for processClass, diff := range desiredCounts.Diff(currentCounts) {
+		if diff > 0 {
+			additionalCounts.IncreaseCount(processClass, int(diff))
+		}
+	}
+
+	required, err := internal.GetRequiredResources(cluster, desiredCounts)
+	if err != nil {
+		return violations, err
+	}
+
+	additional, err := internal.GetRequiredResources(cluster, additionalCounts)
+	if err != nil {
+		return violations, err
+	}
+
+	for _, quota := range quotas.Items {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			log.V(1).Info("Skipping resource quota with scopes", "namespace", cluster.Namespace, "cluster", cluster.Name, "quota", quota.Name)
+			continue
+		}
+
+		for name, hard := range quota.Spec.Hard {
+			quotaName := getQuotaResourceName(name)
+
+			requiredQuantity, ok := required[quotaName]
+			if ok && requiredQuantity.Cmp(hard) > 0 {
+				violations.exceeded = append(violations.exceeded, fmt.Sprintf("%s/%s: required %s, hard limit %s", quota.Name, name, requiredQuantity.String(), hard.String()))
+				continue
+			}
+
+			additionalQuantity, ok := additional[quotaName]
+			if !ok || additionalQuantity.IsZero() {
+				continue
+			}
+
+			available := hard.DeepCopy()
+			available.Sub(quota.Status.Used[name])
+			if additionalQuantity.Cmp(available) > 0 {
+				violations.insufficient = append(violations.insufficient, fmt.Sprintf("%s/%s: additionally required %s, available %s", quota.Name, name, additionalQuantity.String(), available.String()))
+			}
+		}
+	}
+
+	sort.Strings(violations.exceeded)
+	sort.Strings(violations.insufficient)
+
+	return violations, nil
+}
+
+// getQuotaResourceName returns the name of the resource as returned by internal.GetRequiredResources. ResourceQuotas
+// allow to use cpu and memory as a short form of requests.cpu and requests.memory.
+func getQuotaResourceName(name corev1.ResourceName) corev1.ResourceName {
+	switch name {
+	case corev1.ResourceCPU:
+		return corev1.ResourceRequestsCPU
+	case corev1.ResourceMemory:
+		return corev1.ResourceRequestsMemory
+	}
+
+	return name
+}
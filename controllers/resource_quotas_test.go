@@ -0,0 +1,133 @@
+/*
+ * resource_quotas_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("resource_quotas", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var violations resourceQuotaViolations
+
+	createQuota := func(hard corev1.ResourceList, used corev1.ResourceList) {
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quota",
+				Namespace: cluster.Namespace,
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: hard,
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), quota)).NotTo(HaveOccurred())
+
+		quota.Status.Used = used
+		Expect(k8sClient.Status().Update(context.TODO(), quota)).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		violations, err = getResourceQuotaViolations(context.TODO(), k8sClient, cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("no resource quota is defined", func() {
+		It("should not report any violations", func() {
+			Expect(violations.exceeded).To(BeEmpty())
+			Expect(violations.insufficient).To(BeEmpty())
+		})
+	})
+
+	When("the resource quota is sufficient", func() {
+		BeforeEach(func() {
+			createQuota(corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("100"),
+				corev1.ResourceCPU:  resource.MustParse("100"),
+			}, nil)
+		})
+
+		It("should not report any violations", func() {
+			Expect(violations.exceeded).To(BeEmpty())
+			Expect(violations.insufficient).To(BeEmpty())
+		})
+	})
+
+	When("the cluster requires more resources than the hard limit", func() {
+		BeforeEach(func() {
+			createQuota(corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("10"),
+			}, nil)
+		})
+
+		It("should report the exceeded quota", func() {
+			Expect(violations.exceeded).To(ConsistOf("quota/pods: required 17, hard limit 10"))
+			Expect(violations.insufficient).To(BeEmpty())
+		})
+	})
+
+	When("the remaining resource quota is not sufficient", func() {
+		BeforeEach(func() {
+			createQuota(corev1.ResourceList{
+				corev1.ResourceRequestsMemory: resource.MustParse("100Gi"),
+			}, corev1.ResourceList{
+				corev1.ResourceRequestsMemory: resource.MustParse("90Gi"),
+			})
+		})
+
+		It("should report the insufficient quota", func() {
+			Expect(violations.exceeded).To(BeEmpty())
+			Expect(violations.insufficient).To(HaveLen(1))
+			Expect(violations.insufficient[0]).To(HavePrefix("quota/requests.memory: additionally required"))
+		})
+
+		When("all process groups are already created", func() {
+			BeforeEach(func() {
+				Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+				result, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+				_, err = reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})).NotTo(HaveOccurred())
+			})
+
+			It("should not report any violations", func() {
+				Expect(violations.exceeded).To(BeEmpty())
+				Expect(violations.insufficient).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -44,6 +44,7 @@ type FoundationDBRestoreReconciler struct {
 	client.Client
 	Recorder               record.EventRecorder
 	Log                    logr.Logger
+	InSimulation           bool
 	DatabaseClientProvider fdbadminclient.DatabaseClientProvider
 	ServerSideApply        bool
 }
@@ -70,7 +71,10 @@ func (r *FoundationDBRestoreReconciler) Reconcile(ctx context.Context, request c
 	restoreLog := log.WithValues("namespace", restore.Namespace, "restore", restore.Name)
 
 	subReconcilers := []restoreSubReconciler{
+		createRestoreDestinationCluster{},
+		checkRestoreBlobStore{},
 		startRestore{},
+		checkRestoreCompletion{},
 	}
 
 	for _, subReconciler := range subReconcilers {
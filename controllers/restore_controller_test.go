@@ -29,6 +29,7 @@ import (
 	"context"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -92,7 +93,7 @@ var _ = Describe("restore_controller", func() {
 
 		Context("when reconciling a new restore", func() {
 			It("should start a restore", func() {
-				status, err := adminClient.GetRestoreStatus()
+				status, err := adminClient.GetRestoreStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status).To(Equal("blobstore://test@test-service/test-backup?bucket=fdb-backups\n"))
 			})
@@ -113,4 +114,164 @@ var _ = Describe("restore_controller", func() {
 			})
 		})
 	})
+
+	When("restoring into a key prefix", func() {
+		var reconcileErr error
+
+		BeforeEach(func() {
+			restore.Spec.KeyRanges = []fdbv1beta2.FoundationDBKeyRange{
+				{
+					Start: "app/a",
+					End:   "app/b",
+				},
+			}
+			restore.Spec.AddPrefix = "restored/"
+			restore.Spec.RemovePrefix = "app/"
+		})
+
+		JustBeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			Expect(k8sClient.Create(context.TODO(), restore)).NotTo(HaveOccurred())
+			_, reconcileErr = reconcileRestore(restore)
+			Expect(reloadRestore(restore)).NotTo(HaveOccurred())
+		})
+
+		When("the version supports prefix remapping", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = fdbv1beta2.Versions.NextMajorVersion.String()
+			})
+
+			It("should start the restore with the prefixes", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(restore.Status.Running).To(BeTrue())
+				Expect(adminClient.RestoreKeyRanges).To(Equal(restore.Spec.KeyRanges))
+				Expect(adminClient.RestoreAddPrefix).To(Equal("restored/"))
+				Expect(adminClient.RestoreRemovePrefix).To(Equal("app/"))
+			})
+		})
+
+		When("the version doesn't support prefix remapping", func() {
+			It("should not start the restore", func() {
+				Expect(reconcileErr).To(MatchError("RestoreSpec is not valid: adding or removing key prefixes is not supported on version 6.2.21"))
+				Expect(restore.Status.Running).To(BeFalse())
+				status, err := adminClient.GetRestoreStatus(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal("\n"))
+			})
+		})
+	})
+
+	When("restoring into a cluster that is created from a template", func() {
+		BeforeEach(func() {
+			restore.Spec.DestinationClusterTemplate = &fdbv1beta2.FoundationDBClusterTemplate{
+				Metadata: &metav1.ObjectMeta{
+					Labels: map[string]string{
+						"purpose": "disaster-recovery",
+					},
+				},
+				Spec: *cluster.Spec.DeepCopy(),
+			}
+			Expect(k8sClient.Create(context.TODO(), restore)).NotTo(HaveOccurred())
+
+			result, err := reconcileRestore(restore)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(reloadRestore(restore)).NotTo(HaveOccurred())
+		})
+
+		It("should create the destination cluster and wait until it is available", func() {
+			Expect(restore.Status.Phase).To(Equal(fdbv1beta2.RestorePhaseCreatingCluster))
+			Expect(restore.Status.Running).To(BeFalse())
+
+			destination := &fdbv1beta2.FoundationDBCluster{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, destination)).NotTo(HaveOccurred())
+			Expect(destination.Labels).To(HaveKeyWithValue("purpose", "disaster-recovery"))
+			Expect(destination.Spec.Version).To(Equal(cluster.Spec.Version))
+
+			status, err := adminClient.GetRestoreStatus(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal("\n"))
+		})
+
+		When("the destination cluster is available", func() {
+			BeforeEach(func() {
+				result, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				result, err = reconcileRestore(restore)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+				Expect(reloadRestore(restore)).NotTo(HaveOccurred())
+			})
+
+			It("should start the restore and wait until it is completed", func() {
+				Expect(restore.Status.Phase).To(Equal(fdbv1beta2.RestorePhaseRestoring))
+				Expect(restore.Status.Running).To(BeTrue())
+
+				status, err := adminClient.GetRestoreStatus(context.TODO())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal("blobstore://test@test-service/test-backup?bucket=fdb-backups\n"))
+
+				destination := &fdbv1beta2.FoundationDBCluster{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, destination)).NotTo(HaveOccurred())
+				Expect(destination.Annotations).To(HaveKeyWithValue(fdbv1beta2.RestoreInProgressAnnotation, restore.Name))
+			})
+
+			When("the restore is completed", func() {
+				BeforeEach(func() {
+					adminClient.RestoreState = "completed"
+
+					result, err := reconcileRestore(restore)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Requeue).To(BeFalse())
+					Expect(reloadRestore(restore)).NotTo(HaveOccurred())
+				})
+
+				It("should mark the restore as completed", func() {
+					Expect(restore.Status.Phase).To(Equal(fdbv1beta2.RestorePhaseCompleted))
+					Expect(restore.Status.Running).To(BeFalse())
+
+					destination := &fdbv1beta2.FoundationDBCluster{}
+					Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, destination)).NotTo(HaveOccurred())
+					Expect(destination.Annotations).NotTo(HaveKey(fdbv1beta2.RestoreInProgressAnnotation))
+				})
+			})
+
+			When("the restore is aborted", func() {
+				BeforeEach(func() {
+					adminClient.RestoreState = "aborted"
+
+					result, err := reconcileRestore(restore)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Requeue).To(BeFalse())
+					Expect(reloadRestore(restore)).NotTo(HaveOccurred())
+				})
+
+				It("should mark the restore as failed", func() {
+					Expect(restore.Status.Phase).To(Equal(fdbv1beta2.RestorePhaseFailed))
+					Expect(restore.Status.Running).To(BeFalse())
+
+					destination := &fdbv1beta2.FoundationDBCluster{}
+					Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, destination)).NotTo(HaveOccurred())
+					Expect(destination.Annotations).NotTo(HaveKey(fdbv1beta2.RestoreInProgressAnnotation))
+				})
+			})
+		})
+	})
+})
+
+var _ = Describe("getRestoreState", func() {
+	DescribeTable("should parse the state from the restore status",
+		func(status string, expected string) {
+			Expect(getRestoreState(status)).To(Equal(expected))
+		},
+		Entry("no restore", "\n", ""),
+		Entry("a running restore", "Tag: default  UID: 4b7c2a  State: running  Blocks: 10/100  BlocksInProgress: 5", "running"),
+		Entry("a completed restore", "Tag: default  UID: 4b7c2a  State: completed  Blocks: 100/100  BlocksInProgress: 0", "completed"),
+	)
 })
@@ -0,0 +1,68 @@
+/*
+ * restore_in_progress.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// restoreInProgressRetryDelay defines how long the operator waits before it checks again if the restore into the
+// cluster has finished.
+const restoreInProgressRetryDelay = time.Minute
+
+// checkRestoreInProgress returns a requeue if the cluster is marked as the destination of a restore that has not
+// finished yet. A marker of a restore that was deleted or that has finished will be ignored.
+func checkRestoreInProgress(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, action string) *requeue {
+	restoreName, ok := cluster.Annotations[fdbv1beta2.RestoreInProgressAnnotation]
+	if !ok {
+		return nil
+	}
+
+	restore := &fdbv1beta2.FoundationDBRestore{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: restoreName}, restore)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logger.Info("Ignoring the restore marker of a restore that doesn't exist", "action", action, "restore", restoreName)
+			return nil
+		}
+
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	if restore.Status.Phase == fdbv1beta2.RestorePhaseCompleted || restore.Status.Phase == fdbv1beta2.RestorePhaseFailed {
+		return nil
+	}
+
+	logger.Info("Deferring action while a restore into the cluster is in progress", "action", action, "restore", restoreName)
+	return &requeue{
+		message:        fmt.Sprintf("Deferring %s while restore %s is in progress", action, restoreName),
+		delay:          restoreInProgressRetryDelay,
+		delayedRequeue: true,
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * restore_in_progress_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("restore_in_progress", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var restore *fdbv1beta2.FoundationDBRestore
+	var result *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		restore = &fdbv1beta2.FoundationDBRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "operator-test-1-restore",
+				Namespace: cluster.Namespace,
+			},
+			Spec: fdbv1beta2.FoundationDBRestoreSpec{
+				DestinationClusterName: cluster.Name,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		result = checkRestoreInProgress(context.TODO(), logr.Discard(), clusterReconciler, cluster, "bouncing processes")
+	})
+
+	When("the cluster is not marked as the destination of a restore", func() {
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+		})
+	})
+
+	When("the cluster is marked as the destination of a restore", func() {
+		BeforeEach(func() {
+			cluster.Annotations = map[string]string{
+				fdbv1beta2.RestoreInProgressAnnotation: restore.Name,
+			}
+		})
+
+		When("the restore doesn't exist", func() {
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+
+		When("the restore is in progress", func() {
+			BeforeEach(func() {
+				restore.Status.Phase = fdbv1beta2.RestorePhaseRestoring
+				Expect(k8sClient.Create(context.TODO(), restore)).NotTo(HaveOccurred())
+			})
+
+			It("should defer the action", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.delay).To(Equal(restoreInProgressRetryDelay))
+				Expect(result.message).To(Equal("Deferring bouncing processes while restore operator-test-1-restore is in progress"))
+			})
+		})
+
+		When("the restore is completed", func() {
+			BeforeEach(func() {
+				restore.Status.Phase = fdbv1beta2.RestorePhaseCompleted
+				Expect(k8sClient.Create(context.TODO(), restore)).NotTo(HaveOccurred())
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+
+		When("the restore has failed", func() {
+			BeforeEach(func() {
+				restore.Status.Phase = fdbv1beta2.RestorePhaseFailed
+				Expect(k8sClient.Create(context.TODO(), restore)).NotTo(HaveOccurred())
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,186 @@
+/*
+ * rotate_connection_string.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// rotateConnectionString provides a reconciliation step for changing the description or the generation ID of the
+// connection string.
+type rotateConnectionString struct{}
+
+// reconcile runs the reconciler's work.
+func (rotateConnectionString) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "rotateConnectionString")
+
+	if !cluster.Status.Configured || cluster.Status.ConnectionString == "" {
+		return nil
+	}
+
+	if cluster.NeedsConnectionStringRotation() {
+		return rotateToSeedConnectionString(ctx, logger, r, cluster)
+	}
+
+	// Remember the seed connection string, so that later changes to it can be detected.
+	if cluster.Spec.SeedConnectionString != cluster.Status.AppliedSeedConnectionString {
+		cluster.Status.AppliedSeedConnectionString = cluster.Spec.SeedConnectionString
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err, delayedRequeue: true}
+		}
+	}
+
+	// A clone uses the description of the source cluster until it is available. Afterwards it gets its own
+	// description to separate it from the source cluster.
+	desiredDescription := cluster.Spec.ClusterDescription
+	if desiredDescription == "" && cluster.Spec.CloneFrom != nil {
+		desiredDescription = connectionStringNameRegex.ReplaceAllString(cluster.Name, "_")
+	}
+
+	if desiredDescription == "" {
+		return nil
+	}
+
+	connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if connectionString.DatabaseName == desiredDescription {
+		return nil
+	}
+
+	hasLock, err := r.takeLock(cluster, "changing cluster description")
+	if !hasLock {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	defer adminClient.Close()
+
+	logger.Info("Changing cluster description", "current", connectionString.DatabaseName, "desired", desiredDescription)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ChangingClusterDescription", fmt.Sprintf("Changing cluster description from %s to %s", connectionString.DatabaseName, desiredDescription))
+	newConnectionString, err := adminClient.ChangeClusterDescription(ctx, desiredDescription)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	cluster.Status.ConnectionString = newConnectionString
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	return nil
+}
+
+// rotateToSeedConnectionString rewrites the cluster file on all pods with the seed connection string and restarts all
+// processes at the same time, so that all processes pick up the new connection string together.
+func rotateToSeedConnectionString(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !pointer.BoolDeref(cluster.Spec.AutomationOptions.KillProcesses, true) {
+		return &requeue{message: "Rotating the connection string requires the operator to restart all processes", delayedRequeue: true}
+	}
+
+	hasLock, err := r.takeLock(cluster, "rotating connection string")
+	if !hasLock {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	podMap := internal.CreatePodMap(cluster, pods)
+	var notSynced []fdbv1beta2.ProcessGroupID
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		pod, ok := podMap[processGroup.ProcessGroupID]
+		if !ok || pod == nil {
+			notSynced = append(notSynced, processGroup.ProcessGroupID)
+			continue
+		}
+
+		synced, err := r.updatePodDynamicConf(ctx, logger, cluster, pod)
+		if err != nil {
+			logger.Error(err, "Could not update cluster file", "processGroupID", processGroup.ProcessGroupID)
+		}
+
+		if !synced {
+			notSynced = append(notSynced, processGroup.ProcessGroupID)
+		}
+	}
+
+	if len(notSynced) > 0 {
+		logger.Info("Waiting for the new cluster file on all pods", "processGroupIDs", notSynced)
+		return &requeue{message: fmt.Sprintf("Waiting for the new cluster file on process groups: %v", notSynced), delayedRequeue: true}
+	}
+
+	// The admin client still uses the current connection string, as the processes only pick up the new cluster file
+	// after they were restarted.
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus(ctx)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	addresses := make([]fdbv1beta2.ProcessAddress, 0, len(status.Cluster.Processes))
+	for _, process := range status.Cluster.Processes {
+		addresses = append(addresses, process.Address)
+	}
+
+	logger.Info("Restarting all processes with the new connection string", "connectionString", cluster.Spec.SeedConnectionString, "addresses", addresses)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "RotatingConnectionString", fmt.Sprintf("Restarting all processes with the connection string %s", cluster.Spec.SeedConnectionString))
+	err = adminClient.KillProcesses(ctx, addresses)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	r.recordAction(cluster, fmt.Sprintf("restarted all processes with the connection string %s", cluster.Spec.SeedConnectionString), "seed connection string has changed")
+
+	cluster.Status.ConnectionString = cluster.Spec.SeedConnectionString
+	cluster.Status.AppliedSeedConnectionString = cluster.Spec.SeedConnectionString
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	return &requeue{message: "fetch latest status after connection string rotation"}
+}
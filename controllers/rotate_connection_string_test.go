@@ -0,0 +1,182 @@
+/*
+ * rotate_connection_string_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("rotate_connection_string", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var requeue *requeue
+	var originalConnectionString fdbv1beta2.ConnectionString
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		generation, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generation).To(Equal(int64(1)))
+
+		originalConnectionString, err = fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = rotateConnectionString{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("neither the seed connection string nor the cluster description is set", func() {
+		It("should not change the connection string", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString.String()))
+			Expect(cluster.Status.AppliedSeedConnectionString).To(BeEmpty())
+		})
+	})
+
+	When("the seed connection string is set for the first time", func() {
+		BeforeEach(func() {
+			cluster.Spec.SeedConnectionString = "clone:abcdefgh@" + originalConnectionString.Coordinators[0]
+		})
+
+		It("should only record the seed connection string", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString.String()))
+			Expect(cluster.Status.AppliedSeedConnectionString).To(Equal(cluster.Spec.SeedConnectionString))
+		})
+	})
+
+	When("the seed connection string was changed", func() {
+		BeforeEach(func() {
+			cluster.Status.AppliedSeedConnectionString = originalConnectionString.String()
+			Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+		})
+
+		When("the new seed connection string has a different generation ID", func() {
+			var seedConnectionString string
+
+			BeforeEach(func() {
+				newConnectionString := originalConnectionString
+				Expect(newConnectionString.GenerateNewGenerationID()).NotTo(HaveOccurred())
+				seedConnectionString = newConnectionString.String()
+				cluster.Spec.SeedConnectionString = seedConnectionString
+			})
+
+			It("should rotate the connection string", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.message).To(Equal("fetch latest status after connection string rotation"))
+
+				_, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.Status.ConnectionString).To(Equal(seedConnectionString))
+				Expect(cluster.Status.AppliedSeedConnectionString).To(Equal(seedConnectionString))
+			})
+
+			When("killing processes is disabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.KillProcesses = pointer.Bool(false)
+				})
+
+				It("should not rotate the connection string", func() {
+					Expect(requeue).NotTo(BeNil())
+					Expect(requeue.delayedRequeue).To(BeTrue())
+					Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString.String()))
+				})
+			})
+		})
+
+		When("the new seed connection string only has different coordinators", func() {
+			BeforeEach(func() {
+				newConnectionString := originalConnectionString
+				newConnectionString.Coordinators = originalConnectionString.Coordinators[:1]
+				cluster.Spec.SeedConnectionString = newConnectionString.String()
+			})
+
+			It("should only record the seed connection string", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString.String()))
+				Expect(cluster.Status.AppliedSeedConnectionString).To(Equal(cluster.Spec.SeedConnectionString))
+			})
+		})
+	})
+
+	When("the cluster description is changed", func() {
+		BeforeEach(func() {
+			cluster.Spec.ClusterDescription = "new_description"
+		})
+
+		It("should change the description of the connection string", func() {
+			Expect(requeue).To(BeNil())
+
+			_, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(connectionString.DatabaseName).To(Equal("new_description"))
+			Expect(connectionString.GenerationID).NotTo(Equal(originalConnectionString.GenerationID))
+			Expect(connectionString.Coordinators).To(Equal(originalConnectionString.Coordinators))
+		})
+	})
+
+	When("the cluster was cloned from another cluster", func() {
+		BeforeEach(func() {
+			cluster.Spec.CloneFrom = &fdbv1beta2.CloneFromSpec{
+				ClusterName:     "source",
+				VolumeSnapshots: map[fdbv1beta2.ProcessGroupID]string{"storage-1": "snapshot-storage-1"},
+			}
+			originalConnectionString.DatabaseName = "source"
+			cluster.Status.ConnectionString = originalConnectionString.String()
+		})
+
+		It("should change the description to the name of the clone", func() {
+			Expect(requeue).To(BeNil())
+
+			connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(connectionString.DatabaseName).To(Equal("operator_test_1"))
+		})
+	})
+
+	When("the cluster description matches the current description", func() {
+		BeforeEach(func() {
+			cluster.Spec.ClusterDescription = originalConnectionString.DatabaseName
+		})
+
+		It("should not change the connection string", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString.String()))
+		})
+	})
+})
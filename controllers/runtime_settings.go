@@ -0,0 +1,82 @@
+/*
+ * runtime_settings.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+)
+
+// RuntimeSettings contains the settings of the FoundationDBClusterReconciler that can be updated while the operator is
+// running, e.g. when the config file of the operator was changed.
+type RuntimeSettings struct {
+	EnableRestartIncompatibleProcesses bool
+	EnableRecoveryState                bool
+	DryRun                             bool
+	GetTimeout                         time.Duration
+	PostTimeout                        time.Duration
+	AdminClientAuditLogSize            int
+	DeprecationOptions                 internal.DeprecationOptions
+}
+
+// runtimeSettings guards the RuntimeSettings of a reconciler against concurrent updates.
+type runtimeSettings struct {
+	mutex    sync.RWMutex
+	settings *RuntimeSettings
+}
+
+// UpdateRuntimeSettings replaces the settings of the reconciler that can be updated while the operator is running. The
+// new settings will be used by all reconciliations that start after the update. Reconcilers that were not created with
+// NewFoundationDBClusterReconciler must not be updated concurrently to a running reconciliation.
+func (r *FoundationDBClusterReconciler) UpdateRuntimeSettings(settings RuntimeSettings) {
+	if r.runtimeSettings == nil {
+		r.runtimeSettings = &runtimeSettings{}
+	}
+
+	r.runtimeSettings.mutex.Lock()
+	defer r.runtimeSettings.mutex.Unlock()
+	r.runtimeSettings.settings = &settings
+}
+
+// getRuntimeSettings returns the latest runtime settings of the reconciler or the settings of the reconciler fields
+// if the runtime settings were never updated.
+func (r *FoundationDBClusterReconciler) getRuntimeSettings() RuntimeSettings {
+	if r.runtimeSettings != nil {
+		r.runtimeSettings.mutex.RLock()
+		defer r.runtimeSettings.mutex.RUnlock()
+
+		if r.runtimeSettings.settings != nil {
+			return *r.runtimeSettings.settings
+		}
+	}
+
+	return RuntimeSettings{
+		EnableRestartIncompatibleProcesses: r.EnableRestartIncompatibleProcesses,
+		EnableRecoveryState:                r.EnableRecoveryState,
+		DryRun:                             r.DryRun,
+		GetTimeout:                         r.GetTimeout,
+		PostTimeout:                        r.PostTimeout,
+		AdminClientAuditLogSize:            r.AdminClientAuditLogSize,
+		DeprecationOptions:                 r.DeprecationOptions,
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * runtime_settings_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("runtime_settings", func() {
+	var reconciler *FoundationDBClusterReconciler
+
+	BeforeEach(func() {
+		reconciler = NewFoundationDBClusterReconciler(nil)
+		reconciler.GetTimeout = 5 * time.Second
+		reconciler.EnableRecoveryState = true
+	})
+
+	When("the runtime settings were never updated", func() {
+		It("should return the settings of the reconciler", func() {
+			settings := reconciler.getRuntimeSettings()
+			Expect(settings.GetTimeout).To(Equal(5 * time.Second))
+			Expect(settings.EnableRecoveryState).To(BeTrue())
+			Expect(settings.DryRun).To(BeFalse())
+		})
+	})
+
+	When("the runtime settings were updated", func() {
+		BeforeEach(func() {
+			reconciler.UpdateRuntimeSettings(RuntimeSettings{
+				GetTimeout: 30 * time.Second,
+				DryRun:     true,
+				DeprecationOptions: internal.DeprecationOptions{
+					UseFutureDefaults: true,
+				},
+			})
+		})
+
+		It("should return the updated settings", func() {
+			settings := reconciler.getRuntimeSettings()
+			Expect(settings.GetTimeout).To(Equal(30 * time.Second))
+			Expect(settings.EnableRecoveryState).To(BeFalse())
+			Expect(settings.DeprecationOptions.UseFutureDefaults).To(BeTrue())
+		})
+
+		It("should use the updated settings for the dry-run mode", func() {
+			Expect(reconciler.isDryRun(internal.CreateDefaultCluster())).To(BeTrue())
+		})
+
+		When("a copy of the reconciler is used", func() {
+			It("should share the runtime settings", func() {
+				copied := *reconciler
+				reconciler.UpdateRuntimeSettings(RuntimeSettings{GetTimeout: time.Minute})
+				Expect(copied.getRuntimeSettings().GetTimeout).To(Equal(time.Minute))
+			})
+		})
+	})
+})
@@ -0,0 +1,171 @@
+/*
+ * send_notifications.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/notifications"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// notificationClient is the HTTP client that is used to send the notifications to the webhooks.
+var notificationClient = &http.Client{Timeout: 10 * time.Second}
+
+// sendNotifications provides a reconciliation step for notifying the configured webhooks about a reconciliation that
+// is blocked for too long and about changes of the fault tolerance.
+type sendNotifications struct{}
+
+// reconcile runs the reconciler's work.
+func (sendNotifications) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if cluster.Spec.Notifications == nil || len(cluster.Spec.Notifications.Webhooks) == 0 {
+		if cluster.Status.Notifications == nil {
+			return nil
+		}
+
+		cluster.Status.Notifications = nil
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "sendNotifications")
+	state := fdbv1beta2.NotificationStatus{}
+	if cluster.Status.Notifications != nil {
+		state = *cluster.Status.Notifications
+	}
+	originalState := state
+
+	blocked := cluster.Status.ReconciliationBlocked
+	if blocked == nil {
+		state.ReconciliationBlocked = false
+	} else if !state.ReconciliationBlocked && blocked.Since != nil && time.Since(blocked.Since.Time) >= cluster.GetReconciliationBlockedNotificationThreshold() {
+		r.notify(ctx, logger, cluster, "ReconciliationBlocked", fdbv1beta2.NotificationSeverityWarning,
+			fmt.Sprintf("Reconciliation is blocked since %s: %s", blocked.Since.UTC().Format(time.RFC3339), blocked.Message))
+		state.ReconciliationBlocked = true
+	}
+
+	// The health information is only valid once the database is configured.
+	if cluster.Status.Configured {
+		degraded := !cluster.Status.Health.FullReplication
+		if degraded && !state.FaultToleranceDegraded {
+			r.notify(ctx, logger, cluster, "FaultToleranceDegraded", fdbv1beta2.NotificationSeverityCritical, "Data is not fully replicated")
+		} else if !degraded && state.FaultToleranceDegraded {
+			r.notify(ctx, logger, cluster, "FaultToleranceRestored", fdbv1beta2.NotificationSeverityInfo, "Data is fully replicated again")
+		}
+		state.FaultToleranceDegraded = degraded
+	}
+
+	if equality.Semantic.DeepEqual(state, originalState) && cluster.Status.Notifications != nil {
+		return nil
+	}
+
+	cluster.Status.Notifications = &state
+	err := r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// notify sends the notification to all webhooks of the cluster that accept the severity. Errors are only logged, as
+// notifications should never block the reconciliation.
+func (r *FoundationDBClusterReconciler) notify(ctx context.Context, logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, reason string, severity fdbv1beta2.NotificationSeverity, message string) {
+	if cluster.Spec.Notifications == nil {
+		return
+	}
+
+	notification := notifications.Notification{
+		Cluster:   cluster.Name,
+		Namespace: cluster.Namespace,
+		Reason:    reason,
+		Severity:  severity,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+
+	for _, webhook := range cluster.Spec.Notifications.Webhooks {
+		if !severity.IsAtLeast(webhook.GetMinimumSeverity()) {
+			continue
+		}
+
+		err := r.sendNotification(ctx, cluster, webhook, notification)
+		if err != nil {
+			logger.Error(err, "could not send notification", "webhook", webhook.Name, "reason", reason)
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "NotificationFailed", fmt.Sprintf("Could not send %s notification to webhook %s: %s", reason, webhook.Name, err.Error()))
+			continue
+		}
+
+		logger.Info("Sent notification", "webhook", webhook.Name, "reason", reason, "severity", severity)
+	}
+}
+
+// sendNotification sends the notification to a single webhook.
+func (r *FoundationDBClusterReconciler) sendNotification(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, webhook fdbv1beta2.NotificationWebhook, notification notifications.Notification) error {
+	var secretValue string
+	if webhook.SecretKeyRef != nil {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: webhook.SecretKeyRef.Name}, secret)
+		if err != nil {
+			return err
+		}
+
+		value, ok := secret.Data[webhook.SecretKeyRef.Key]
+		if !ok {
+			return fmt.Errorf("secret %s/%s has no key %s", cluster.Namespace, webhook.SecretKeyRef.Name, webhook.SecretKeyRef.Key)
+		}
+		secretValue = string(value)
+	}
+
+	url := webhook.URL
+	var routingKey string
+	if webhook.GetType() == fdbv1beta2.NotificationWebhookTypePagerDuty {
+		routingKey = secretValue
+		if url == "" {
+			url = notifications.PagerDutyEventsURL
+		}
+	} else if secretValue != "" {
+		url = secretValue
+	}
+
+	if url == "" {
+		return fmt.Errorf("webhook %s has no URL", webhook.Name)
+	}
+
+	payload, err := notifications.GetPayload(webhook.GetType(), routingKey, notification)
+	if err != nil {
+		return err
+	}
+
+	return notifications.Send(ctx, notificationClient, url, payload)
+}
@@ -0,0 +1,196 @@
+/*
+ * send_notifications_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/notifications"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("send_notifications", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var server *httptest.Server
+	var received []notifications.Notification
+	var lock sync.Mutex
+
+	getReasons := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+
+		reasons := make([]string, 0, len(received))
+		for _, notification := range received {
+			reasons = append(reasons, notification.Reason)
+		}
+
+		return reasons
+	}
+
+	runReconciler := func() {
+		Expect(sendNotifications{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+		_, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, err := io.ReadAll(req.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			notification := notifications.Notification{}
+			Expect(json.Unmarshal(body, &notification)).NotTo(HaveOccurred())
+
+			lock.Lock()
+			received = append(received, notification)
+			lock.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+		cluster.Spec.Notifications = &fdbv1beta2.NotificationSpec{
+			Webhooks: []fdbv1beta2.NotificationWebhook{
+				{
+					Name: "test",
+					URL:  server.URL,
+				},
+			},
+		}
+		Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	When("the cluster is healthy", func() {
+		BeforeEach(func() {
+			runReconciler()
+		})
+
+		It("should not send a notification", func() {
+			Expect(getReasons()).To(BeEmpty())
+			Expect(cluster.Status.Notifications).NotTo(BeNil())
+			Expect(cluster.Status.Notifications.FaultToleranceDegraded).To(BeFalse())
+		})
+	})
+
+	When("the data is not fully replicated", func() {
+		BeforeEach(func() {
+			cluster.Status.Health.FullReplication = false
+			runReconciler()
+		})
+
+		It("should send the notification once", func() {
+			Expect(getReasons()).To(ConsistOf("FaultToleranceDegraded"))
+			Expect(cluster.Status.Notifications.FaultToleranceDegraded).To(BeTrue())
+
+			runReconciler()
+			Expect(getReasons()).To(ConsistOf("FaultToleranceDegraded"))
+		})
+
+		When("the data is fully replicated again", func() {
+			BeforeEach(func() {
+				cluster.Spec.Notifications.Webhooks[0].MinimumSeverity = fdbv1beta2.NotificationSeverityInfo
+				cluster.Status.Health.FullReplication = true
+				runReconciler()
+			})
+
+			It("should send a notification for the restored fault tolerance", func() {
+				Expect(getReasons()).To(ConsistOf("FaultToleranceDegraded", "FaultToleranceRestored"))
+				Expect(cluster.Status.Notifications.FaultToleranceDegraded).To(BeFalse())
+			})
+		})
+
+		When("the webhook uses the default minimum severity", func() {
+			BeforeEach(func() {
+				cluster.Status.Health.FullReplication = true
+				runReconciler()
+			})
+
+			It("should not send the info notification", func() {
+				Expect(getReasons()).To(ConsistOf("FaultToleranceDegraded"))
+				Expect(cluster.Status.Notifications.FaultToleranceDegraded).To(BeFalse())
+			})
+		})
+	})
+
+	When("the reconciliation is blocked", func() {
+		var since time.Time
+
+		JustBeforeEach(func() {
+			cluster.Status.ReconciliationBlocked = &fdbv1beta2.ReconciliationBlockedStatus{
+				SubReconciler: "controllers.bounceProcesses",
+				Message:       "waiting",
+				Since:         &metav1.Time{Time: since},
+			}
+			runReconciler()
+		})
+
+		When("the reconciliation is blocked for less than the threshold", func() {
+			BeforeEach(func() {
+				since = time.Now().Add(-1 * time.Minute)
+			})
+
+			It("should not send a notification", func() {
+				Expect(getReasons()).To(BeEmpty())
+				Expect(cluster.Status.Notifications.ReconciliationBlocked).To(BeFalse())
+			})
+		})
+
+		When("the reconciliation is blocked for longer than the threshold", func() {
+			BeforeEach(func() {
+				since = time.Now().Add(-2 * time.Hour)
+			})
+
+			It("should send a notification", func() {
+				Expect(getReasons()).To(ConsistOf("ReconciliationBlocked"))
+				Expect(cluster.Status.Notifications.ReconciliationBlocked).To(BeTrue())
+			})
+		})
+	})
+
+	When("no webhooks are configured", func() {
+		BeforeEach(func() {
+			cluster.Status.Notifications = &fdbv1beta2.NotificationStatus{FaultToleranceDegraded: true}
+			cluster.Spec.Notifications = nil
+			runReconciler()
+		})
+
+		It("should reset the notification status", func() {
+			Expect(getReasons()).To(BeEmpty())
+			Expect(cluster.Status.Notifications).To(BeNil())
+		})
+	})
+})
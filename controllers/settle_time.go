@@ -0,0 +1,88 @@
+/*
+ * settle_time.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// checkSettleTime returns a requeue if the settle time of the cluster has not passed since the last recovery of
+// the database or since the last destructive action of the operator. If status is nil, only the last destructive
+// action will be checked.
+func checkSettleTime(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, action string) *requeue {
+	settleTime := cluster.GetSettleTime()
+	if settleTime <= 0 {
+		return nil
+	}
+
+	var waitTime time.Duration
+	lastAction := cluster.Status.LastDestructiveAction
+	if lastAction != nil && lastAction.Timestamp != nil {
+		if remaining := settleTime - time.Since(lastAction.Timestamp.Time); remaining > waitTime {
+			waitTime = remaining
+		}
+	}
+
+	if status != nil && status.Cluster.RecoveryState.Name != "" {
+		version, err := fdbv1beta2.ParseFdbVersion(cluster.GetRunningVersion())
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if version.SupportsRecoveryState() {
+			sinceRecovery := time.Duration(status.Cluster.RecoveryState.SecondsSinceLastRecovered * float64(time.Second))
+			if remaining := settleTime - sinceRecovery; remaining > waitTime {
+				waitTime = remaining
+			}
+		}
+	}
+
+	if waitTime <= 0 {
+		return nil
+	}
+
+	logger.Info("Waiting for the cluster to settle before the next destructive action", "action", action, "waitTime", waitTime)
+	return &requeue{
+		message: fmt.Sprintf("Waiting %s for the cluster to settle before %s", waitTime.Round(time.Second), action),
+		delay:   waitTime,
+	}
+}
+
+// recordDestructiveAction records the destructive action in the cluster status, if a settle time is defined.
+func recordDestructiveAction(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, action string) error {
+	if cluster.GetSettleTime() <= 0 {
+		return nil
+	}
+
+	cluster.Status.LastDestructiveAction = &fdbv1beta2.DestructiveActionStatus{
+		Action:    action,
+		Timestamp: &metav1.Time{Time: time.Now()},
+	}
+
+	return r.updateOrApply(ctx, cluster)
+}
@@ -0,0 +1,122 @@
+/*
+ * settle_time_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("settle_time", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var status *fdbv1beta2.FoundationDBStatus
+	var result *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Version = fdbv1beta2.Versions.SupportsRecoveryState.String()
+		cluster.Status.RunningVersion = cluster.Spec.Version
+		cluster.Spec.AutomationOptions.SettleTimeSeconds = pointer.Int(300)
+		status = &fdbv1beta2.FoundationDBStatus{}
+	})
+
+	JustBeforeEach(func() {
+		result = checkSettleTime(logr.Discard(), cluster, status, "bouncing processes")
+	})
+
+	When("no settle time is defined", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.SettleTimeSeconds = nil
+			cluster.Status.LastDestructiveAction = &fdbv1beta2.DestructiveActionStatus{
+				Timestamp: &metav1.Time{Time: time.Now()},
+			}
+		})
+
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+		})
+	})
+
+	When("no destructive action was performed and no recovery state is reported", func() {
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+		})
+	})
+
+	When("the last destructive action is older than the settle time", func() {
+		BeforeEach(func() {
+			cluster.Status.LastDestructiveAction = &fdbv1beta2.DestructiveActionStatus{
+				Timestamp: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)},
+			}
+		})
+
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+		})
+	})
+
+	When("the last recovery happened recently", func() {
+		BeforeEach(func() {
+			status.Cluster.RecoveryState = fdbv1beta2.RecoveryState{
+				Name:                      "fully_recovered",
+				SecondsSinceLastRecovered: 60,
+			}
+		})
+
+		It("should requeue until the cluster has settled", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.delay).To(Equal(4 * time.Minute))
+		})
+
+		When("the version doesn't support the recovery state", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = fdbv1beta2.Versions.Default.String()
+				cluster.Status.RunningVersion = cluster.Spec.Version
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+		})
+
+		When("the last destructive action requires a longer wait", func() {
+			BeforeEach(func() {
+				cluster.Status.LastDestructiveAction = &fdbv1beta2.DestructiveActionStatus{
+					Timestamp: &metav1.Time{Time: time.Now().Add(-30 * time.Second)},
+				}
+			})
+
+			It("should wait for the longer duration", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delay).To(BeNumerically("~", 270*time.Second, time.Second))
+			})
+		})
+	})
+})
@@ -32,17 +32,26 @@ type startBackup struct {
 
 // reconcile runs the reconciler's work.
 func (s startBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	if backup.UsesVolumeSnapshots() {
+		return nil
+	}
+
 	if !backup.ShouldRun() || (backup.Status.BackupDetails != nil && backup.Status.BackupDetails.Running) {
 		return nil
 	}
 
+	// A backup in the Snapshot mode is only taken once.
+	if backup.IsSnapshotCompleted() {
+		return nil
+	}
+
 	adminClient, err := r.adminClientForBackup(ctx, backup)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 	defer adminClient.Close()
 
-	err = adminClient.StartBackup(backup.BackupURL(), backup.SnapshotPeriodSeconds())
+	err = adminClient.StartBackup(ctx, backup.BackupURL(), backup.SnapshotPeriodSeconds(), backup.ShouldStopAfterSnapshot())
 	if err != nil {
 		return &requeue{curError: err}
 	}
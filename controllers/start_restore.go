@@ -22,9 +22,13 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // startRestore provides a reconciliation step for starting a new restore.
@@ -39,18 +43,34 @@ func (s startRestore) reconcile(ctx context.Context, r *FoundationDBRestoreRecon
 	}
 	defer adminClient.Close()
 
-	status, err := adminClient.GetRestoreStatus()
+	status, err := adminClient.GetRestoreStatus(ctx)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 
 	if len(strings.TrimSpace(status)) == 0 {
-		err = adminClient.StartRestore(restore.BackupURL(), restore.Spec.KeyRanges)
+		err = validateRestore(ctx, r, restore)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if restore.UsesDestinationClusterTemplate() {
+			err = setRestoreInProgressMarker(ctx, r, restore, true)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+		}
+
+		err = adminClient.StartRestore(ctx, restore.BackupURL(), restore.Spec.KeyRanges, restore.Spec.AddPrefix, restore.Spec.RemovePrefix)
 		if err != nil {
 			return &requeue{curError: err}
 		}
 
 		restore.Status.Running = true
+		if restore.UsesDestinationClusterTemplate() {
+			restore.Status.Phase = fdbv1beta2.RestorePhaseRestoring
+		}
+
 		err = r.updateOrApply(ctx, restore)
 		if err != nil {
 			return &requeue{curError: err}
@@ -59,3 +79,53 @@ func (s startRestore) reconcile(ctx context.Context, r *FoundationDBRestoreRecon
 
 	return nil
 }
+
+// validateRestore checks that the restore is valid for the version of the destination cluster.
+func validateRestore(ctx context.Context, r *FoundationDBRestoreReconciler, restore *fdbv1beta2.FoundationDBRestore) error {
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, cluster)
+	if err != nil {
+		return err
+	}
+
+	version, err := fdbv1beta2.ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil {
+		return err
+	}
+
+	err = restore.Validate(version)
+	if err != nil {
+		r.Recorder.Event(restore, corev1.EventTypeWarning, "RestoreSpec not valid", err.Error())
+		return fmt.Errorf("RestoreSpec is not valid: %w", err)
+	}
+
+	return nil
+}
+
+// setRestoreInProgressMarker adds or removes the annotation on the destination cluster that marks the cluster as the
+// destination of the restore. The operator defers conflicting operations on a marked cluster until the restore has
+// finished.
+func setRestoreInProgressMarker(ctx context.Context, r *FoundationDBRestoreReconciler, restore *fdbv1beta2.FoundationDBRestore, inProgress bool) error {
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, cluster)
+	if err != nil {
+		return err
+	}
+
+	current, marked := cluster.Annotations[fdbv1beta2.RestoreInProgressAnnotation]
+	if (inProgress && current == restore.Name) || (!inProgress && (!marked || current != restore.Name)) {
+		return nil
+	}
+
+	original := cluster.DeepCopy()
+	if inProgress {
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[fdbv1beta2.RestoreInProgressAnnotation] = restore.Name
+	} else {
+		delete(cluster.Annotations, fdbv1beta2.RestoreInProgressAnnotation)
+	}
+
+	return r.Patch(ctx, cluster, client.MergeFrom(original))
+}
@@ -0,0 +1,267 @@
+/*
+ * status_updater.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/mergepatch"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterStatuses tracks the last known status of the clusters and serializes their status writes. This is shared by
+// all reconcilers, including the copies used for dry runs.
+var clusterStatuses statusTracker
+
+// statusTracker serializes the status writes per cluster, so that concurrent status updates of the same cluster
+// don't overwrite each other. The zero value is ready to use.
+type statusTracker struct {
+	mutex    sync.Mutex
+	statuses map[types.NamespacedName]*trackedStatus
+}
+
+// trackedStatus is the last status of a cluster that was read or written by the operator.
+type trackedStatus struct {
+	mutex sync.Mutex
+	// resourceVersion is the resource version of the cluster that contained the status.
+	resourceVersion string
+	// status is the status of the cluster at the resource version.
+	status *fdbv1beta2.FoundationDBClusterStatus
+}
+
+// get returns the tracked status for the provided cluster.
+func (tracker *statusTracker) get(key types.NamespacedName) *trackedStatus {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if tracker.statuses == nil {
+		tracker.statuses = map[types.NamespacedName]*trackedStatus{}
+	}
+
+	status, ok := tracker.statuses[key]
+	if !ok {
+		status = &trackedStatus{}
+		tracker.statuses[key] = status
+	}
+
+	return status
+}
+
+// lock acquires the lock for the status writes of the provided cluster and returns the tracked status together with
+// the function to release the lock.
+func (tracker *statusTracker) lock(key types.NamespacedName) (*trackedStatus, func()) {
+	status := tracker.get(key)
+	status.mutex.Lock()
+
+	return status, status.mutex.Unlock
+}
+
+// observe records the status of the cluster as the base for resolving conflicts of later status updates.
+func (tracker *statusTracker) observe(cluster *fdbv1beta2.FoundationDBCluster) {
+	status, unlock := tracker.lock(client.ObjectKeyFromObject(cluster))
+	defer unlock()
+
+	status.record(cluster)
+}
+
+// record sets the status of the cluster as the tracked status. The caller must hold the lock.
+func (status *trackedStatus) record(cluster *fdbv1beta2.FoundationDBCluster) {
+	status.resourceVersion = cluster.ObjectMeta.ResourceVersion
+	status.status = cluster.Status.DeepCopy()
+}
+
+// baseFor returns the tracked status if it belongs to the resource version of the provided cluster. The caller must
+// hold the lock.
+func (status *trackedStatus) baseFor(cluster *fdbv1beta2.FoundationDBCluster) *fdbv1beta2.FoundationDBClusterStatus {
+	if status.status == nil || status.resourceVersion != cluster.ObjectMeta.ResourceVersion {
+		return nil
+	}
+
+	return status.status
+}
+
+// updateStatusOnConflict updates the status of the latest version of the cluster with a merge patch after the
+// initial update failed with a conflict. The patch uses optimistic locking and will be retried if the cluster was
+// changed in the meantime. If the update would overwrite changes of another writer, the update will be rejected with
+// a conflict error without retrying, so the reconciliation is retried with the latest status. The caller must hold
+// the lock of the tracked status.
+func (r *FoundationDBClusterReconciler) updateStatusOnConflict(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, tracked *trackedStatus) error {
+	base := tracked.baseFor(cluster)
+	var rejected bool
+	retriable := func(err error) bool {
+		return !rejected && k8serrors.IsConflict(err)
+	}
+
+	return retry.OnError(retry.DefaultRetry, retriable, func() error {
+		latest := &fdbv1beta2.FoundationDBCluster{}
+		err := r.Get(ctx, client.ObjectKeyFromObject(cluster), latest)
+		if err != nil {
+			return err
+		}
+
+		status, err := mergeStatus(cluster, base, latest)
+		if err != nil {
+			rejected = true
+			return err
+		}
+
+		patch := client.MergeFromWithOptions(latest.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		latest.Status = *status
+		err = r.Status().Patch(ctx, latest, patch)
+		if err != nil {
+			return err
+		}
+
+		// The merged status contains the changes of the other writers, so the following status updates must be based
+		// on it to not roll them back.
+		cluster.ObjectMeta.ResourceVersion = latest.ObjectMeta.ResourceVersion
+		cluster.Status = *latest.Status.DeepCopy()
+		tracked.record(latest)
+
+		return nil
+	})
+}
+
+// mergeStatus merges the status changes of the cluster into the status of the latest version of the cluster. The
+// changes are the difference between the base status, which was the status when the cluster was read, and the status
+// of the cluster. If the changes overlap with the changes of another writer, a conflict error will be returned. If
+// the base status is unknown, the status of the cluster will only be used if the spec was changed in the meantime and
+// the status doesn't roll back a newer process group condition.
+func mergeStatus(cluster *fdbv1beta2.FoundationDBCluster, base *fdbv1beta2.FoundationDBClusterStatus, latest *fdbv1beta2.FoundationDBCluster) (*fdbv1beta2.FoundationDBClusterStatus, error) {
+	if base == nil {
+		if latest.ObjectMeta.Generation == cluster.ObjectMeta.Generation {
+			return nil, newStatusConflict(cluster, fmt.Errorf("status was changed by another writer"))
+		}
+
+		err := checkStatusIsNotStale(cluster, latest)
+		if err != nil {
+			return nil, err
+		}
+
+		return cluster.Status.DeepCopy(), nil
+	}
+
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+
+	currentJSON, err := json.Marshal(cluster.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	latestJSON, err := json.Marshal(latest.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := jsonpatch.CreateMergePatch(baseJSON, currentJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	otherChanges, err := jsonpatch.CreateMergePatch(baseJSON, latestJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var changesMap, otherChangesMap map[string]interface{}
+	err = json.Unmarshal(changes, &changesMap)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(otherChanges, &otherChangesMap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lists like the process groups are replaced as a whole by a merge patch, so a newer condition of another writer
+	// will be detected as conflict here.
+	hasConflicts, err := mergepatch.HasConflicts(changesMap, otherChangesMap)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasConflicts {
+		return nil, newStatusConflict(cluster, fmt.Errorf("status update would overwrite the changes of another writer"))
+	}
+
+	mergedJSON, err := jsonpatch.MergePatch(latestJSON, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &fdbv1beta2.FoundationDBClusterStatus{}
+	err = json.Unmarshal(mergedJSON, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// checkStatusIsNotStale returns a conflict error if the status of the cluster has an older timestamp for a process
+// group condition than the status of the latest version of the cluster.
+func checkStatusIsNotStale(cluster *fdbv1beta2.FoundationDBCluster, latest *fdbv1beta2.FoundationDBCluster) error {
+	conditions := make(map[fdbv1beta2.ProcessGroupID]map[fdbv1beta2.ProcessGroupConditionType]int64, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		timestamps := make(map[fdbv1beta2.ProcessGroupConditionType]int64, len(processGroup.ProcessGroupConditions))
+		for _, condition := range processGroup.ProcessGroupConditions {
+			timestamps[condition.ProcessGroupConditionType] = condition.Timestamp
+		}
+
+		conditions[processGroup.ProcessGroupID] = timestamps
+	}
+
+	for _, processGroup := range latest.Status.ProcessGroups {
+		timestamps, ok := conditions[processGroup.ProcessGroupID]
+		if !ok {
+			continue
+		}
+
+		for _, condition := range processGroup.ProcessGroupConditions {
+			timestamp, ok := timestamps[condition.ProcessGroupConditionType]
+			if !ok || timestamp >= condition.Timestamp {
+				continue
+			}
+
+			return newStatusConflict(cluster, fmt.Errorf("status update would roll back the newer condition %s of process group %s", condition.ProcessGroupConditionType, processGroup.ProcessGroupID))
+		}
+	}
+
+	return nil
+}
+
+// newStatusConflict returns a conflict error for a rejected status update of the cluster.
+func newStatusConflict(cluster *fdbv1beta2.FoundationDBCluster, err error) error {
+	return k8serrors.NewConflict(fdbv1beta2.GroupVersion.WithResource("foundationdbclusters").GroupResource(), cluster.Name, err)
+}
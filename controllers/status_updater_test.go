@@ -0,0 +1,228 @@
+/*
+ * status_updater_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("status_updater", func() {
+	When("locking the status of a cluster", func() {
+		var tracker statusTracker
+		var key types.NamespacedName
+
+		BeforeEach(func() {
+			tracker = statusTracker{}
+			key = types.NamespacedName{Namespace: "test", Name: "cluster"}
+		})
+
+		It("should serialize the status updates of the same cluster", func() {
+			_, unlock := tracker.lock(key)
+			acquired := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				_, unlock := tracker.lock(key)
+				unlock()
+				close(acquired)
+			}()
+
+			Consistently(acquired, 100*time.Millisecond).ShouldNot(BeClosed())
+			unlock()
+			Eventually(acquired).Should(BeClosed())
+		})
+
+		It("should not block the status updates of other clusters", func() {
+			_, unlock := tracker.lock(key)
+			defer unlock()
+
+			acquired := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				_, unlock := tracker.lock(types.NamespacedName{Namespace: "test", Name: "other"})
+				unlock()
+				close(acquired)
+			}()
+
+			Eventually(acquired).Should(BeClosed())
+		})
+	})
+
+	When("observing the status of a cluster", func() {
+		var tracker statusTracker
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			tracker = statusTracker{}
+			cluster = &fdbv1beta2.FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "test",
+					Name:            "cluster",
+					ResourceVersion: "1",
+				},
+			}
+			cluster.Status.Generations.Reconciled = 1
+			tracker.observe(cluster)
+		})
+
+		It("should return the status as base for the same resource version", func() {
+			tracked, unlock := tracker.lock(client.ObjectKeyFromObject(cluster))
+			defer unlock()
+
+			base := tracked.baseFor(cluster)
+			Expect(base).NotTo(BeNil())
+			Expect(base.Generations.Reconciled).To(BeNumerically("==", 1))
+		})
+
+		It("should not return a base for a different resource version", func() {
+			tracked, unlock := tracker.lock(client.ObjectKeyFromObject(cluster))
+			defer unlock()
+
+			cluster.ResourceVersion = "2"
+			Expect(tracked.baseFor(cluster)).To(BeNil())
+		})
+	})
+
+	When("merging the status", func() {
+		var cluster, latest *fdbv1beta2.FoundationDBCluster
+		var base *fdbv1beta2.FoundationDBClusterStatus
+
+		BeforeEach(func() {
+			cluster = &fdbv1beta2.FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "cluster",
+					Generation: 1,
+				},
+				Status: fdbv1beta2.FoundationDBClusterStatus{
+					ProcessGroups: []*fdbv1beta2.ProcessGroupStatus{
+						{ProcessGroupID: "storage-1"},
+					},
+				},
+			}
+			base = cluster.Status.DeepCopy()
+			latest = cluster.DeepCopy()
+		})
+
+		When("the changes don't overlap", func() {
+			It("should keep the changes of both writers", func() {
+				cluster.Status.Health.DataMovementPriority = 42
+				latest.Status.Health.Available = true
+
+				status, err := mergeStatus(cluster, base, latest)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Health.DataMovementPriority).To(Equal(42))
+				Expect(status.Health.Available).To(BeTrue())
+				Expect(status.ProcessGroups).To(HaveLen(1))
+			})
+		})
+
+		When("both writers changed the process groups", func() {
+			It("should reject the update with a conflict", func() {
+				cluster.Status.ProcessGroups[0].ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+					{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 100},
+				}
+				latest.Status.ProcessGroups[0].ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+					{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 200},
+				}
+
+				_, err := mergeStatus(cluster, base, latest)
+				Expect(k8serrors.IsConflict(err)).To(BeTrue())
+			})
+		})
+
+		When("the base is unknown", func() {
+			It("should reject the update if only the status was changed", func() {
+				_, err := mergeStatus(cluster, nil, latest)
+				Expect(k8serrors.IsConflict(err)).To(BeTrue())
+			})
+
+			It("should use the status if the spec was changed", func() {
+				cluster.Status.Health.DataMovementPriority = 42
+				latest.Generation = 2
+
+				status, err := mergeStatus(cluster, nil, latest)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Health.DataMovementPriority).To(Equal(42))
+			})
+		})
+	})
+
+	DescribeTable("checking if the status is stale", func(current []*fdbv1beta2.ProcessGroupCondition, latest []*fdbv1beta2.ProcessGroupCondition, expectedStale bool) {
+		cluster := &fdbv1beta2.FoundationDBCluster{
+			Status: fdbv1beta2.FoundationDBClusterStatus{
+				ProcessGroups: []*fdbv1beta2.ProcessGroupStatus{
+					{ProcessGroupID: "storage-1", ProcessGroupConditions: current},
+				},
+			},
+		}
+		latestCluster := &fdbv1beta2.FoundationDBCluster{
+			Status: fdbv1beta2.FoundationDBClusterStatus{
+				ProcessGroups: []*fdbv1beta2.ProcessGroupStatus{
+					{ProcessGroupID: "storage-1", ProcessGroupConditions: latest},
+					{ProcessGroupID: "storage-2", ProcessGroupConditions: latest},
+				},
+			},
+		}
+
+		err := checkStatusIsNotStale(cluster, latestCluster)
+		if !expectedStale {
+			Expect(err).NotTo(HaveOccurred())
+			return
+		}
+
+		Expect(k8serrors.IsConflict(err)).To(BeTrue())
+	},
+		Entry("no conditions are present",
+			nil,
+			nil,
+			false,
+		),
+		Entry("the conditions have the same timestamps",
+			[]*fdbv1beta2.ProcessGroupCondition{{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 100}},
+			[]*fdbv1beta2.ProcessGroupCondition{{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 100}},
+			false,
+		),
+		Entry("the current condition is newer",
+			[]*fdbv1beta2.ProcessGroupCondition{{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 200}},
+			[]*fdbv1beta2.ProcessGroupCondition{{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 100}},
+			false,
+		),
+		Entry("the current status removed the condition",
+			nil,
+			[]*fdbv1beta2.ProcessGroupCondition{{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 100}},
+			false,
+		),
+		Entry("the latest condition is newer",
+			[]*fdbv1beta2.ProcessGroupCondition{{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 100}},
+			[]*fdbv1beta2.ProcessGroupCondition{{ProcessGroupConditionType: fdbv1beta2.MissingProcesses, Timestamp: 200}},
+			true,
+		),
+	)
+})
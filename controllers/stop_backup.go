@@ -32,6 +32,10 @@ type stopBackup struct {
 
 // reconcile runs the reconciler's work.
 func (s stopBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	if backup.UsesVolumeSnapshots() {
+		return nil
+	}
+
 	if backup.ShouldRun() || backup.Status.BackupDetails == nil || !backup.Status.BackupDetails.Running {
 		return nil
 	}
@@ -42,7 +46,7 @@ func (s stopBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconcil
 	}
 	defer adminClient.Close()
 
-	err = adminClient.StopBackup(backup.BackupURL())
+	err = adminClient.StopBackup(ctx, backup.BackupURL())
 	if err != nil {
 		return &requeue{curError: err}
 	}
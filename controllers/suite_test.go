@@ -59,6 +59,8 @@ var k8sClient *mockclient.MockClient
 var clusterReconciler *FoundationDBClusterReconciler
 var backupReconciler *FoundationDBBackupReconciler
 var restoreReconciler *FoundationDBRestoreReconciler
+var testScenarioReconciler *FoundationDBTestScenarioReconciler
+var clientLibraryCacheReconciler *FoundationDBClientLibraryCacheReconciler
 var requeueLimit = 20
 
 func TestAPIs(t *testing.T) {
@@ -92,8 +94,21 @@ var _ = BeforeSuite(func() {
 		Client:                 k8sClient,
 		Log:                    ctrl.Log.WithName("controllers").WithName("FoundationDBRestore"),
 		Recorder:               k8sClient,
+		InSimulation:           true,
 		DatabaseClientProvider: mock.DatabaseClientProvider{},
 	}
+
+	testScenarioReconciler = &FoundationDBTestScenarioReconciler{
+		Client:   k8sClient,
+		Log:      ctrl.Log.WithName("controllers").WithName("FoundationDBTestScenario"),
+		Recorder: k8sClient,
+	}
+
+	clientLibraryCacheReconciler = &FoundationDBClientLibraryCacheReconciler{
+		Client:   k8sClient,
+		Log:      ctrl.Log.WithName("controllers").WithName("FoundationDBClientLibraryCache"),
+		Recorder: k8sClient,
+	}
 })
 
 var _ = AfterSuite(func() {
@@ -141,6 +156,14 @@ func reconcileRestore(restore *fdbv1beta2.FoundationDBRestore) (reconcile.Result
 	return reconcileObject(restoreReconciler, restore.ObjectMeta, requeueLimit)
 }
 
+func reconcileTestScenario(scenario *fdbv1beta2.FoundationDBTestScenario) (reconcile.Result, error) {
+	return reconcileObject(testScenarioReconciler, scenario.ObjectMeta, requeueLimit)
+}
+
+func reconcileClientLibraryCache(cache *fdbv1beta2.FoundationDBClientLibraryCache) (reconcile.Result, error) {
+	return reconcileObject(clientLibraryCacheReconciler, cache.ObjectMeta, requeueLimit)
+}
+
 func reconcileObject(reconciler reconcile.Reconciler, metadata metav1.ObjectMeta, requeueLimit int) (reconcile.Result, error) {
 	attempts := requeueLimit + 1
 	result := reconcile.Result{Requeue: true}
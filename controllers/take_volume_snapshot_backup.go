@@ -0,0 +1,293 @@
+/*
+ * take_volume_snapshot_backup.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// takeVolumeSnapshotBackup provides a reconciliation step for taking a backup with VolumeSnapshots of the data volumes
+// of the cluster.
+type takeVolumeSnapshotBackup struct{}
+
+// reconcile runs the reconciler's work.
+func (takeVolumeSnapshotBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	if !backup.UsesVolumeSnapshots() || !backup.ShouldRun() {
+		return nil
+	}
+
+	snapshotBackup := backup.Status.VolumeSnapshotBackup
+	if snapshotBackup != nil && snapshotBackup.CompletionTime != nil {
+		return nil
+	}
+
+	// A failed backup is not retried, but the database might still be locked if the previous unlock failed.
+	if snapshotBackup != nil && snapshotBackup.FailureTime != nil && snapshotBackup.LockUID == "" {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", backup.Namespace, "backup", backup.Name, "reconciler", "takeVolumeSnapshotBackup")
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: backup.Namespace, Name: backup.Spec.ClusterName}, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	adminClient, err := r.adminClientForBackup(ctx, backup)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	if snapshotBackup == nil {
+		snapshotBackup, err = startVolumeSnapshotBackup(ctx, logger, r, backup, cluster, adminClient)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		backup.Status.VolumeSnapshotBackup = snapshotBackup
+		err = r.updateOrApply(ctx, backup)
+		if err != nil {
+			// Without the lock UID in the status the database could not be unlocked later.
+			if snapshotBackup.LockUID != "" {
+				unlockErr := adminClient.UnlockDatabase(ctx, snapshotBackup.LockUID)
+				if unlockErr != nil {
+					logger.Error(unlockErr, "could not unlock database")
+				}
+			}
+
+			return &requeue{curError: err}
+		}
+	}
+
+	if snapshotBackup.FailureTime != nil {
+		return failVolumeSnapshotBackup(ctx, logger, r, backup, adminClient, nil)
+	}
+
+	// The database must not stay locked for writes if the VolumeSnapshots can't be taken.
+	if snapshotBackup.LockUID != "" && snapshotBackup.LockDeadline != nil && !time.Now().Before(snapshotBackup.LockDeadline.Time) {
+		return failVolumeSnapshotBackup(ctx, logger, r, backup, adminClient, fmt.Errorf("VolumeSnapshots were not taken within the lock timeout of %s", backup.GetVolumeSnapshotLockTimeout()))
+	}
+
+	processGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0, len(snapshotBackup.VolumeSnapshots))
+	for processGroupID := range snapshotBackup.VolumeSnapshots {
+		processGroupIDs = append(processGroupIDs, processGroupID)
+	}
+	sort.Slice(processGroupIDs, func(i, j int) bool {
+		return processGroupIDs[i] < processGroupIDs[j]
+	})
+
+	allTaken := true
+	allReady := true
+	for _, processGroupID := range processGroupIDs {
+		snapshot, err := getOrCreateVolumeSnapshot(ctx, logger, r, backup, cluster, processGroupID, snapshotBackup.Version)
+		if err != nil {
+			if snapshotBackup.LockUID != "" {
+				return failVolumeSnapshotBackup(ctx, logger, r, backup, adminClient, err)
+			}
+
+			return &requeue{curError: err}
+		}
+
+		if !internal.IsVolumeSnapshotTaken(snapshot) {
+			allTaken = false
+		}
+
+		if !internal.IsVolumeSnapshotReady(snapshot) {
+			allReady = false
+		}
+	}
+
+	// The lock can be released as soon as all snapshots are taken, even if they are not ready to use yet.
+	if allTaken && snapshotBackup.LockUID != "" {
+		logger.Info("Unlocking database after all VolumeSnapshots were taken")
+		err = adminClient.UnlockDatabase(ctx, snapshotBackup.LockUID)
+		if err != nil {
+			return failVolumeSnapshotBackup(ctx, logger, r, backup, adminClient, err)
+		}
+
+		snapshotBackup.LockUID = ""
+		snapshotBackup.LockDeadline = nil
+		err = r.updateOrApply(ctx, backup)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if !allReady {
+		return &requeue{message: "Waiting for VolumeSnapshots to become ready", delayedRequeue: true}
+	}
+
+	now := metav1.Now()
+	snapshotBackup.CompletionTime = &now
+	err = r.updateOrApply(ctx, backup)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	logger.Info("VolumeSnapshot backup completed", "version", snapshotBackup.Version)
+	r.Recorder.Event(backup, corev1.EventTypeNormal, "VolumeSnapshotBackupCompleted", fmt.Sprintf("Took %d VolumeSnapshots that can be restored at version %d", len(snapshotBackup.VolumeSnapshots), snapshotBackup.Version))
+
+	return nil
+}
+
+// failVolumeSnapshotBackup marks the VolumeSnapshot backup as failed and unlocks the database, if the operator holds a
+// lock on it. If the unlock fails, the failure is recorded and the unlock will be retried in the next reconciliation.
+func failVolumeSnapshotBackup(ctx context.Context, logger logr.Logger, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup, adminClient fdbadminclient.AdminClient, cause error) *requeue {
+	snapshotBackup := backup.Status.VolumeSnapshotBackup
+	if snapshotBackup.FailureTime == nil {
+		now := metav1.Now()
+		snapshotBackup.FailureTime = &now
+		snapshotBackup.FailureMessage = cause.Error()
+		logger.Error(cause, "VolumeSnapshot backup failed")
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "VolumeSnapshotBackupFailed", fmt.Sprintf("VolumeSnapshot backup failed: %s", cause.Error()))
+	}
+
+	var unlockErr error
+	if snapshotBackup.LockUID != "" {
+		logger.Info("Unlocking database after the VolumeSnapshot backup failed")
+		unlockErr = adminClient.UnlockDatabase(ctx, snapshotBackup.LockUID)
+		if unlockErr == nil {
+			snapshotBackup.LockUID = ""
+			snapshotBackup.LockDeadline = nil
+		}
+	}
+
+	err := r.updateOrApply(ctx, backup)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if unlockErr != nil {
+		return &requeue{curError: unlockErr}
+	}
+
+	return nil
+}
+
+// startVolumeSnapshotBackup locks the database if required, records the version of the backup and selects the
+// process groups whose data volumes will be snapshotted.
+func startVolumeSnapshotBackup(ctx context.Context, logger logr.Logger, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient) (*fdbv1beta2.VolumeSnapshotBackupStatus, error) {
+	now := metav1.Now()
+	snapshotBackup := &fdbv1beta2.VolumeSnapshotBackupStatus{
+		VolumeSnapshots: map[fdbv1beta2.ProcessGroupID]string{},
+		StartTime:       &now,
+	}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() || !processGroup.ProcessClass.IsStateful() {
+			continue
+		}
+
+		snapshotBackup.VolumeSnapshots[processGroup.ProcessGroupID] = internal.GetVolumeSnapshotName(backup, processGroup.ProcessGroupID)
+	}
+
+	if len(snapshotBackup.VolumeSnapshots) == 0 {
+		return nil, fmt.Errorf("cluster %s has no process groups with data volumes", cluster.Name)
+	}
+
+	if backup.ShouldLockDatabaseForVolumeSnapshots() {
+		lockUID, err := adminClient.LockDatabase(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotBackup.LockUID = lockUID
+		snapshotBackup.LockDeadline = &metav1.Time{Time: time.Now().Add(backup.GetVolumeSnapshotLockTimeout())}
+	}
+
+	version, err := adminClient.GetReadVersion(ctx)
+	if err != nil {
+		if snapshotBackup.LockUID != "" {
+			unlockErr := adminClient.UnlockDatabase(ctx, snapshotBackup.LockUID)
+			if unlockErr != nil {
+				logger.Error(unlockErr, "could not unlock database")
+			}
+		}
+
+		return nil, err
+	}
+
+	snapshotBackup.Version = version
+	logger.Info("Starting VolumeSnapshot backup", "version", version, "locked", snapshotBackup.LockUID != "", "processGroups", len(snapshotBackup.VolumeSnapshots))
+	r.Recorder.Event(backup, corev1.EventTypeNormal, "StartingVolumeSnapshotBackup", fmt.Sprintf("Taking VolumeSnapshots of %d process groups at version %d", len(snapshotBackup.VolumeSnapshots), version))
+
+	return snapshotBackup, nil
+}
+
+// getOrCreateVolumeSnapshot fetches the VolumeSnapshot of the data volume of a process group and creates it if it
+// doesn't exist.
+func getOrCreateVolumeSnapshot(ctx context.Context, logger logr.Logger, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup, cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID, version int64) (*unstructured.Unstructured, error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(internal.VolumeSnapshotGroupVersionKind)
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: internal.GetVolumeSnapshotName(backup, processGroupID)}, snapshot)
+	if err == nil {
+		return snapshot, nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+	if processGroup == nil {
+		return nil, fmt.Errorf("could not find process group %s in cluster %s", processGroupID, cluster.Name)
+	}
+
+	_, idNum, err := internal.ParseProcessGroupID(processGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	pvc, err := internal.GetPvc(cluster, processGroup.ProcessClass, idNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if pvc == nil {
+		return nil, fmt.Errorf("process group %s in cluster %s has no data volume", processGroupID, cluster.Name)
+	}
+
+	snapshot = internal.GetVolumeSnapshot(backup, processGroupID, pvc.Name, version)
+	logger.V(1).Info("Creating VolumeSnapshot", "name", snapshot.GetName(), "pvc", pvc.Name)
+	err = r.Create(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
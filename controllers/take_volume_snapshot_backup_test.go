@@ -0,0 +1,226 @@
+/*
+ * take_volume_snapshot_backup_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("take_volume_snapshot_backup", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var backup *fdbv1beta2.FoundationDBBackup
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+
+	getVolumeSnapshot := func(processGroupID fdbv1beta2.ProcessGroupID) *unstructured.Unstructured {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(internal.VolumeSnapshotGroupVersionKind)
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: backup.Namespace, Name: internal.GetVolumeSnapshotName(backup, processGroupID)}, snapshot)).NotTo(HaveOccurred())
+
+		return snapshot
+	}
+
+	// setVolumeSnapshotStatus simulates the progress of the snapshot controller.
+	setVolumeSnapshotStatus := func(ready bool) {
+		for processGroupID := range backup.Status.VolumeSnapshotBackup.VolumeSnapshots {
+			snapshot := getVolumeSnapshot(processGroupID)
+			Expect(unstructured.SetNestedField(snapshot.Object, "2023-01-01T00:00:00Z", "status", "creationTime")).NotTo(HaveOccurred())
+			Expect(unstructured.SetNestedField(snapshot.Object, ready, "status", "readyToUse")).NotTo(HaveOccurred())
+			Expect(k8sClient.Update(context.TODO(), snapshot)).NotTo(HaveOccurred())
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		_, err = reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		backup = internal.CreateDefaultBackup(cluster)
+		backup.Spec.BackupMode = fdbv1beta2.BackupModeVolumeSnapshot
+		Expect(k8sClient.Create(context.TODO(), backup)).NotTo(HaveOccurred())
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = takeVolumeSnapshotBackup{}.reconcile(context.TODO(), backupReconciler, backup)
+	})
+
+	When("the backup uses the backup agents", func() {
+		BeforeEach(func() {
+			backup.Spec.BackupMode = fdbv1beta2.BackupModeContinuous
+		})
+
+		It("should not take any VolumeSnapshots", func() {
+			Expect(requeue).To(BeNil())
+			Expect(backup.Status.VolumeSnapshotBackup).To(BeNil())
+		})
+	})
+
+	When("the backup is started", func() {
+		It("should lock the database and create the VolumeSnapshots", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.message).To(Equal("Waiting for VolumeSnapshots to become ready"))
+			Expect(requeue.delayedRequeue).To(BeTrue())
+
+			snapshotBackup := backup.Status.VolumeSnapshotBackup
+			Expect(snapshotBackup).NotTo(BeNil())
+			Expect(snapshotBackup.Version).To(BeNumerically(">", 0))
+			Expect(snapshotBackup.LockUID).NotTo(BeEmpty())
+			Expect(snapshotBackup.LockUID).To(Equal(adminClient.LockUID))
+			Expect(snapshotBackup.CompletionTime).To(BeNil())
+
+			var statefulProcessGroups []fdbv1beta2.ProcessGroupID
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				if processGroup.ProcessClass.IsStateful() {
+					statefulProcessGroups = append(statefulProcessGroups, processGroup.ProcessGroupID)
+				}
+			}
+			Expect(snapshotBackup.VolumeSnapshots).To(HaveLen(len(statefulProcessGroups)))
+
+			snapshot := getVolumeSnapshot("storage-1")
+			Expect(snapshot.GetAnnotations()).To(HaveKeyWithValue(fdbv1beta2.RestorableVersionAnnotation, strconv.FormatInt(snapshotBackup.Version, 10)))
+			pvcName, _, err := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pvcName).To(Equal("operator-test-1-storage-1-data"))
+		})
+
+		When("all VolumeSnapshots were taken", func() {
+			BeforeEach(func() {
+				Expect(takeVolumeSnapshotBackup{}.reconcile(context.TODO(), backupReconciler, backup)).NotTo(BeNil())
+				setVolumeSnapshotStatus(false)
+			})
+
+			It("should unlock the database and wait for the VolumeSnapshots", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.message).To(Equal("Waiting for VolumeSnapshots to become ready"))
+				Expect(backup.Status.VolumeSnapshotBackup.LockUID).To(BeEmpty())
+				Expect(adminClient.LockUID).To(BeEmpty())
+			})
+		})
+
+		When("all VolumeSnapshots are ready to use", func() {
+			BeforeEach(func() {
+				Expect(takeVolumeSnapshotBackup{}.reconcile(context.TODO(), backupReconciler, backup)).NotTo(BeNil())
+				setVolumeSnapshotStatus(true)
+			})
+
+			It("should complete the backup", func() {
+				Expect(requeue).To(BeNil())
+				Expect(backup.Status.VolumeSnapshotBackup.CompletionTime).NotTo(BeNil())
+				Expect(adminClient.LockUID).To(BeEmpty())
+			})
+		})
+	})
+
+	When("the VolumeSnapshots are not taken within the lock timeout", func() {
+		BeforeEach(func() {
+			Expect(takeVolumeSnapshotBackup{}.reconcile(context.TODO(), backupReconciler, backup)).NotTo(BeNil())
+			Expect(adminClient.LockUID).NotTo(BeEmpty())
+			backup.Status.VolumeSnapshotBackup.LockDeadline = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		})
+
+		It("should unlock the database and fail the backup", func() {
+			Expect(requeue).To(BeNil())
+			snapshotBackup := backup.Status.VolumeSnapshotBackup
+			Expect(snapshotBackup.LockUID).To(BeEmpty())
+			Expect(snapshotBackup.FailureTime).NotTo(BeNil())
+			Expect(snapshotBackup.FailureMessage).To(ContainSubstring("lock timeout"))
+			Expect(snapshotBackup.CompletionTime).To(BeNil())
+			Expect(adminClient.LockUID).To(BeEmpty())
+		})
+
+		When("the backup is reconciled again", func() {
+			JustBeforeEach(func() {
+				Expect(takeVolumeSnapshotBackup{}.reconcile(context.TODO(), backupReconciler, backup)).To(BeNil())
+			})
+
+			It("should not retry the backup", func() {
+				Expect(backup.Status.VolumeSnapshotBackup.FailureTime).NotTo(BeNil())
+				Expect(adminClient.LockUID).To(BeEmpty())
+			})
+		})
+	})
+
+	When("a VolumeSnapshot can't be created while the database is locked", func() {
+		BeforeEach(func() {
+			Expect(takeVolumeSnapshotBackup{}.reconcile(context.TODO(), backupReconciler, backup)).NotTo(BeNil())
+			Expect(adminClient.LockUID).NotTo(BeEmpty())
+			Expect(k8sClient.Delete(context.TODO(), getVolumeSnapshot("storage-1"))).NotTo(HaveOccurred())
+
+			processGroups := make([]*fdbv1beta2.ProcessGroupStatus, 0, len(cluster.Status.ProcessGroups))
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				if processGroup.ProcessGroupID == "storage-1" {
+					continue
+				}
+				processGroups = append(processGroups, processGroup)
+			}
+			cluster.Status.ProcessGroups = processGroups
+			Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should unlock the database and fail the backup", func() {
+			Expect(requeue).To(BeNil())
+			snapshotBackup := backup.Status.VolumeSnapshotBackup
+			Expect(snapshotBackup.LockUID).To(BeEmpty())
+			Expect(snapshotBackup.FailureTime).NotTo(BeNil())
+			Expect(snapshotBackup.FailureMessage).To(ContainSubstring("storage-1"))
+			Expect(adminClient.LockUID).To(BeEmpty())
+		})
+	})
+
+	When("the database should not be locked", func() {
+		BeforeEach(func() {
+			backup.Spec.VolumeSnapshotConfiguration = &fdbv1beta2.VolumeSnapshotConfiguration{
+				LockDatabase: pointer.Bool(false),
+			}
+		})
+
+		It("should create the VolumeSnapshots without locking the database", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(backup.Status.VolumeSnapshotBackup).NotTo(BeNil())
+			Expect(backup.Status.VolumeSnapshotBackup.Version).To(BeNumerically(">", 0))
+			Expect(backup.Status.VolumeSnapshotBackup.LockUID).To(BeEmpty())
+			Expect(adminClient.LockUID).To(BeEmpty())
+		})
+	})
+})
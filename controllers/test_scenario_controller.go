@@ -0,0 +1,513 @@
+/*
+ * test_scenario_controller.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// testScenarioCheckInterval defines how often a running step checks if the cluster recovered.
+	testScenarioCheckInterval = 10 * time.Second
+
+	// testScenarioSidecarPort defines the port of the sidecar that is blocked by the PartitionSidecar action.
+	testScenarioSidecarPort = 8080
+)
+
+// FoundationDBTestScenarioReconciler reconciles a FoundationDBTestScenario object
+type FoundationDBTestScenarioReconciler struct {
+	client.Client
+	Recorder        record.EventRecorder
+	Log             logr.Logger
+	ServerSideApply bool
+}
+
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbtestscenarios,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbtestscenarios/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;delete
+
+// Reconcile runs the reconciliation logic.
+func (r *FoundationDBTestScenarioReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	scenario := &fdbv1beta2.FoundationDBTestScenario{}
+	err := r.Get(ctx, request.NamespacedName, scenario)
+
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Object not found, return.  Created objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	scenarioLog := log.WithValues("namespace", scenario.Namespace, "testScenario", scenario.Name)
+
+	if scenario.IsFinished() {
+		scenarioLog.V(1).Info("Test scenario is already finished", "phase", scenario.Status.Phase)
+		return ctrl.Result{}, nil
+	}
+
+	err = scenario.Validate()
+	if err != nil {
+		return ctrl.Result{}, r.finishScenario(ctx, scenario, fdbv1beta2.TestScenarioPhaseFailed, err.Error(), scenarioLog)
+	}
+
+	if scenario.Status.Phase == "" || scenario.Status.Phase == fdbv1beta2.TestScenarioPhasePending {
+		now := metav1.Now()
+		scenario.Status.Phase = fdbv1beta2.TestScenarioPhaseRunning
+		scenario.Status.StartTime = &now
+		scenario.Status.CurrentStep = 0
+		scenario.Status.Steps = make([]fdbv1beta2.TestScenarioStepStatus, 0, len(scenario.Spec.Steps))
+		for _, step := range scenario.Spec.Steps {
+			scenario.Status.Steps = append(scenario.Status.Steps, fdbv1beta2.TestScenarioStepStatus{
+				Name:  step.Name,
+				Phase: fdbv1beta2.TestScenarioPhasePending,
+			})
+		}
+
+		scenarioLog.Info("Starting test scenario", "steps", len(scenario.Spec.Steps))
+		err = r.updateOrApply(ctx, scenario)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if scenario.Status.CurrentStep >= len(scenario.Spec.Steps) {
+		return ctrl.Result{}, r.finishScenario(ctx, scenario, fdbv1beta2.TestScenarioPhasePassed, "", scenarioLog)
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: scenario.Namespace, Name: scenario.Spec.ClusterName}, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	step := scenario.Spec.Steps[scenario.Status.CurrentStep]
+	stepStatus := &scenario.Status.Steps[scenario.Status.CurrentStep]
+	stepLog := scenarioLog.WithValues("step", step.Name, "action", step.Action)
+
+	if stepStatus.Phase == fdbv1beta2.TestScenarioPhasePending {
+		targetedPods, err := r.selectTestScenarioTargets(ctx, cluster, step)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// The step is persisted as running before the action is executed, so a retry after a failed status update
+		// will never execute the action of the step a second time.
+		now := metav1.Now()
+		stepStatus.Phase = fdbv1beta2.TestScenarioPhaseRunning
+		stepStatus.StartTime = &now
+		stepStatus.TargetedPods = targetedPods
+		err = r.updateOrApply(ctx, scenario)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.runTestScenarioAction(ctx, scenario, cluster, step, targetedPods)
+		if err != nil {
+			stepStatus.Phase = fdbv1beta2.TestScenarioPhaseFailed
+			stepStatus.CompletionTime = &now
+			stepStatus.Message = err.Error()
+
+			return ctrl.Result{}, r.finishScenario(ctx, scenario, fdbv1beta2.TestScenarioPhaseFailed, fmt.Sprintf("step %s failed: %s", step.Name, err.Error()), scenarioLog)
+		}
+
+		stepLog.Info("Started test scenario step", "targetedPods", targetedPods)
+		r.Recorder.Event(scenario, corev1.EventTypeNormal, "TestScenarioStepStarted", fmt.Sprintf("Started step %s with action %s", step.Name, step.Action))
+
+		return ctrl.Result{RequeueAfter: testScenarioCheckInterval}, nil
+	}
+
+	// The PartitionSidecar and the Wait action only check the cluster after the configured duration.
+	var actionDuration time.Duration
+	if step.Action == fdbv1beta2.TestScenarioActionPartitionSidecar || step.Action == fdbv1beta2.TestScenarioActionWait {
+		actionDuration = step.GetDuration()
+	}
+
+	elapsed := time.Since(stepStatus.StartTime.Time)
+	if elapsed < actionDuration {
+		return ctrl.Result{RequeueAfter: actionDuration - elapsed}, nil
+	}
+
+	if step.Action == fdbv1beta2.TestScenarioActionPartitionSidecar {
+		err = r.removeSidecarPartition(ctx, scenario, stepStatus.TargetedPods)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	message, err := r.checkClusterRecovered(ctx, cluster, step, stepStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if message == "" {
+		now := metav1.Now()
+		stepStatus.Phase = fdbv1beta2.TestScenarioPhasePassed
+		stepStatus.CompletionTime = &now
+		stepStatus.Message = ""
+		scenario.Status.CurrentStep++
+		stepLog.Info("Test scenario step passed", "duration", elapsed.String())
+		r.Recorder.Event(scenario, corev1.EventTypeNormal, "TestScenarioStepPassed", fmt.Sprintf("Step %s passed after %s", step.Name, elapsed.Round(time.Second)))
+
+		return ctrl.Result{Requeue: true}, r.updateOrApply(ctx, scenario)
+	}
+
+	if elapsed > actionDuration+step.GetTimeout() {
+		now := metav1.Now()
+		stepStatus.Phase = fdbv1beta2.TestScenarioPhaseFailed
+		stepStatus.CompletionTime = &now
+		stepStatus.Message = message
+
+		return ctrl.Result{}, r.finishScenario(ctx, scenario, fdbv1beta2.TestScenarioPhaseFailed, fmt.Sprintf("step %s failed: %s", step.Name, message), scenarioLog)
+	}
+
+	stepLog.V(1).Info("Waiting for the cluster to recover", "reason", message)
+	if stepStatus.Message != message {
+		stepStatus.Message = message
+		err = r.updateOrApply(ctx, scenario)
+	}
+
+	return ctrl.Result{RequeueAfter: testScenarioCheckInterval}, err
+}
+
+// finishScenario marks the scenario as passed or failed and emits an event with the result.
+func (r *FoundationDBTestScenarioReconciler) finishScenario(ctx context.Context, scenario *fdbv1beta2.FoundationDBTestScenario, phase fdbv1beta2.TestScenarioPhase, message string, logger logr.Logger) error {
+	// Make sure that a sidecar partition is not left behind if the scenario failed during the partition.
+	if phase == fdbv1beta2.TestScenarioPhaseFailed && scenario.Status.CurrentStep < len(scenario.Status.Steps) {
+		stepStatus := scenario.Status.Steps[scenario.Status.CurrentStep]
+		err := r.removeSidecarPartition(ctx, scenario, stepStatus.TargetedPods)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := metav1.Now()
+	scenario.Status.Phase = phase
+	scenario.Status.CompletionTime = &now
+	scenario.Status.Message = message
+
+	if phase == fdbv1beta2.TestScenarioPhaseFailed {
+		logger.Info("Test scenario failed", "message", message)
+		r.Recorder.Event(scenario, corev1.EventTypeWarning, "TestScenarioFailed", message)
+	} else {
+		logger.Info("Test scenario passed")
+		r.Recorder.Event(scenario, corev1.EventTypeNormal, "TestScenarioPassed", "All steps passed")
+	}
+
+	return r.updateOrApply(ctx, scenario)
+}
+
+// selectTestScenarioTargets returns the names of the Pods that should be targeted by the action of the step.
+func (r *FoundationDBTestScenarioReconciler) selectTestScenarioTargets(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, step fdbv1beta2.TestScenarioStep) ([]string, error) {
+	if step.Action != fdbv1beta2.TestScenarioActionKillPods && step.Action != fdbv1beta2.TestScenarioActionPartitionSidecar {
+		return nil, nil
+	}
+
+	pods, err := r.selectTestScenarioPods(ctx, cluster, step)
+	if err != nil {
+		return nil, err
+	}
+
+	targetedPods := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		targetedPods = append(targetedPods, pod.Name)
+	}
+
+	return targetedPods, nil
+}
+
+// runTestScenarioAction executes the action of the step against the targeted Pods.
+func (r *FoundationDBTestScenarioReconciler) runTestScenarioAction(ctx context.Context, scenario *fdbv1beta2.FoundationDBTestScenario, cluster *fdbv1beta2.FoundationDBCluster, step fdbv1beta2.TestScenarioStep, targetedPods []string) error {
+	switch step.Action {
+	case fdbv1beta2.TestScenarioActionKillPods:
+		for _, podName := range targetedPods {
+			err := r.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: scenario.Namespace, Name: podName}})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+		}
+
+		return nil
+	case fdbv1beta2.TestScenarioActionPartitionSidecar:
+		return r.partitionSidecar(ctx, scenario, targetedPods)
+	case fdbv1beta2.TestScenarioActionUpgrade:
+		if cluster.Spec.Version != step.Version {
+			cluster.Spec.Version = step.Version
+			return r.Update(ctx, cluster)
+		}
+
+		return nil
+	case fdbv1beta2.TestScenarioActionWait:
+		return nil
+	}
+
+	return fmt.Errorf("unknown action %s", step.Action)
+}
+
+// selectTestScenarioPods returns random running Pods of the cluster that should be targeted by the step.
+func (r *FoundationDBTestScenarioReconciler) selectTestScenarioPods(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, step fdbv1beta2.TestScenarioStep) ([]corev1.Pod, error) {
+	matchLabels := make(map[string]string, len(cluster.GetMatchLabels())+1)
+	for key, value := range cluster.GetMatchLabels() {
+		matchLabels[key] = value
+	}
+
+	if step.ProcessClass != "" {
+		matchLabels[cluster.GetProcessClassLabel()] = string(step.ProcessClass)
+	}
+
+	podList := &corev1.PodList{}
+	err := r.List(ctx, podList, client.InNamespace(cluster.Namespace), client.MatchingLabels(matchLabels))
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp.IsZero() {
+			candidates = append(candidates, pod)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("could not find any Pods for step %s", step.Name)
+	}
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	if step.GetCount() < len(candidates) {
+		return candidates[:step.GetCount()], nil
+	}
+
+	return candidates, nil
+}
+
+// getSidecarPartitionName returns the name of the NetworkPolicy that partitions the sidecars for the scenario.
+func getSidecarPartitionName(scenario *fdbv1beta2.FoundationDBTestScenario) string {
+	return fmt.Sprintf("%s-sidecar-partition", scenario.Name)
+}
+
+// partitionSidecar blocks all incoming traffic to the sidecar of the provided Pods. The Pods are labeled and a
+// NetworkPolicy is created that only allows incoming traffic to all other ports of the labeled Pods.
+func (r *FoundationDBTestScenarioReconciler) partitionSidecar(ctx context.Context, scenario *fdbv1beta2.FoundationDBTestScenario, podNames []string) error {
+	tcp := corev1.ProtocolTCP
+	lowerPorts := intstr.FromInt(1)
+	upperPorts := intstr.FromInt(testScenarioSidecarPort + 1)
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getSidecarPartitionName(scenario),
+			Namespace:       scenario.Namespace,
+			OwnerReferences: internal.BuildOwnerReference(scenario.TypeMeta, scenario.ObjectMeta),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					fdbv1beta2.TestScenarioPartitionLabel: scenario.Name,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{
+							Protocol: &tcp,
+							Port:     &lowerPorts,
+							EndPort:  pointer.Int32(testScenarioSidecarPort - 1),
+						},
+						{
+							Protocol: &tcp,
+							Port:     &upperPorts,
+							EndPort:  pointer.Int32(65535),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := r.Create(ctx, policy)
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	for _, podName := range podNames {
+		pod := &corev1.Pod{}
+		err = r.Get(ctx, types.NamespacedName{Namespace: scenario.Namespace, Name: podName}, pod)
+		if err != nil {
+			return err
+		}
+
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+
+		pod.Labels[fdbv1beta2.TestScenarioPartitionLabel] = scenario.Name
+		err = r.Update(ctx, pod)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeSidecarPartition deletes the NetworkPolicy of the scenario and removes the partition label from the
+// provided Pods.
+func (r *FoundationDBTestScenarioReconciler) removeSidecarPartition(ctx context.Context, scenario *fdbv1beta2.FoundationDBTestScenario, podNames []string) error {
+	policy := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: scenario.Namespace, Name: getSidecarPartitionName(scenario)}, policy)
+	if err == nil {
+		err = r.Delete(ctx, policy)
+	}
+
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	for _, podName := range podNames {
+		pod := &corev1.Pod{}
+		err = r.Get(ctx, types.NamespacedName{Namespace: scenario.Namespace, Name: podName}, pod)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+
+			return err
+		}
+
+		if _, ok := pod.Labels[fdbv1beta2.TestScenarioPartitionLabel]; !ok {
+			continue
+		}
+
+		delete(pod.Labels, fdbv1beta2.TestScenarioPartitionLabel)
+		err = r.Update(ctx, pod)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkClusterRecovered checks if the cluster recovered from the action of the step. If the cluster didn't recover
+// the reason will be returned, otherwise the returned message is empty.
+func (r *FoundationDBTestScenarioReconciler) checkClusterRecovered(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, step fdbv1beta2.TestScenarioStep, stepStatus *fdbv1beta2.TestScenarioStepStatus) (string, error) {
+	if cluster.Status.Generations.Reconciled != cluster.Generation {
+		return "cluster is not reconciled", nil
+	}
+
+	if !cluster.Status.Health.Available {
+		return "database is not available", nil
+	}
+
+	if step.Action == fdbv1beta2.TestScenarioActionUpgrade && cluster.Status.RunningVersion != step.Version {
+		return fmt.Sprintf("cluster is running version %s", cluster.Status.RunningVersion), nil
+	}
+
+	if step.Action == fdbv1beta2.TestScenarioActionKillPods {
+		for _, podName := range stepStatus.TargetedPods {
+			pod := &corev1.Pod{}
+			err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: podName}, pod)
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					return fmt.Sprintf("Pod %s was not recreated", podName), nil
+				}
+
+				return "", err
+			}
+
+			if !pod.DeletionTimestamp.IsZero() {
+				return fmt.Sprintf("Pod %s is still terminating", podName), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// SetupWithManager prepares a reconciler for use.
+func (r *FoundationDBTestScenarioReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int, selector metav1.LabelSelector) error {
+	labelSelectorPredicate, err := predicate.LabelSelectorPredicate(selector)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxConcurrentReconciles},
+		).
+		For(&fdbv1beta2.FoundationDBTestScenario{}).
+		// Only react on generation changes or annotation changes and only watch
+		// resources with the provided label selector.
+		WithEventFilter(
+			predicate.And(
+				labelSelectorPredicate,
+				predicate.Or(
+					predicate.GenerationChangedPredicate{},
+					predicate.AnnotationChangedPredicate{},
+				),
+			)).
+		Complete(r)
+}
+
+// updateOrApply updates the status either with server-side apply or if disabled with the normal update call.
+func (r *FoundationDBTestScenarioReconciler) updateOrApply(ctx context.Context, scenario *fdbv1beta2.FoundationDBTestScenario) error {
+	if r.ServerSideApply {
+		patch := &fdbv1beta2.FoundationDBTestScenario{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       scenario.Kind,
+				APIVersion: scenario.APIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      scenario.Name,
+				Namespace: scenario.Namespace,
+			},
+			Status: scenario.Status,
+		}
+
+		return r.Status().Patch(ctx, patch, client.Apply, client.FieldOwner("fdb-operator"), client.ForceOwnership)
+	}
+
+	return r.Status().Update(ctx, scenario)
+}
@@ -0,0 +1,318 @@
+/*
+ * test_scenario_controller_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func reloadTestScenario(scenario *fdbv1beta2.FoundationDBTestScenario) error {
+	return k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: scenario.Namespace, Name: scenario.Name}, scenario)
+}
+
+var _ = Describe("test_scenario_controller", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var scenario *fdbv1beta2.FoundationDBTestScenario
+	var result reconcile.Result
+	var err error
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		scenario = &fdbv1beta2.FoundationDBTestScenario{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "scenario",
+				Namespace: cluster.Namespace,
+			},
+			Spec: fdbv1beta2.FoundationDBTestScenarioSpec{
+				ClusterName: cluster.Name,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), scenario)).NotTo(HaveOccurred())
+		result, err = reconcileTestScenario(scenario)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+	})
+
+	When("the scenario is invalid", func() {
+		BeforeEach(func() {
+			scenario.Spec.Steps = []fdbv1beta2.TestScenarioStep{
+				{Name: "upgrade", Action: fdbv1beta2.TestScenarioActionUpgrade},
+			}
+		})
+
+		It("should fail the scenario", func() {
+			Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhaseFailed))
+			Expect(scenario.Status.Message).To(HavePrefix("step upgrade has an invalid version"))
+			Expect(scenario.Status.CompletionTime).NotTo(BeNil())
+		})
+	})
+
+	When("killing Pods", func() {
+		var originalPods *corev1.PodList
+
+		BeforeEach(func() {
+			scenario.Spec.Steps = []fdbv1beta2.TestScenarioStep{
+				{
+					Name:         "kill-storage",
+					Action:       fdbv1beta2.TestScenarioActionKillPods,
+					Count:        pointer.Int(2),
+					ProcessClass: fdbv1beta2.ProcessClassStorage,
+				},
+			}
+
+			originalPods = &corev1.PodList{}
+			Expect(k8sClient.List(context.TODO(), originalPods, client.InNamespace(cluster.Namespace))).NotTo(HaveOccurred())
+		})
+
+		It("should delete the Pods and start the step", func() {
+			Expect(result.RequeueAfter).To(Equal(testScenarioCheckInterval))
+			Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+			Expect(scenario.Status.Steps).To(HaveLen(1))
+
+			stepStatus := scenario.Status.Steps[0]
+			Expect(stepStatus.Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+			Expect(stepStatus.StartTime).NotTo(BeNil())
+			Expect(stepStatus.TargetedPods).To(HaveLen(2))
+
+			for _, podName := range stepStatus.TargetedPods {
+				pod := &corev1.Pod{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: podName}, pod)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				Expect(podName).To(ContainSubstring("storage"))
+			}
+
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(context.TODO(), pods, client.InNamespace(cluster.Namespace))).NotTo(HaveOccurred())
+			Expect(pods.Items).To(HaveLen(len(originalPods.Items) - 2))
+		})
+
+		When("the Pods were not recreated", func() {
+			JustBeforeEach(func() {
+				result, err = reconcileTestScenario(scenario)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+			})
+
+			It("should wait for the cluster to recover", func() {
+				Expect(result.RequeueAfter).To(Equal(testScenarioCheckInterval))
+				Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+				Expect(scenario.Status.Steps[0].Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+				Expect(scenario.Status.Steps[0].Message).To(HaveSuffix("was not recreated"))
+			})
+
+			It("should not delete any additional Pods", func() {
+				pods := &corev1.PodList{}
+				Expect(k8sClient.List(context.TODO(), pods, client.InNamespace(cluster.Namespace))).NotTo(HaveOccurred())
+				Expect(pods.Items).To(HaveLen(len(originalPods.Items) - 2))
+			})
+		})
+
+		When("the cluster does not recover before the timeout", func() {
+			JustBeforeEach(func() {
+				scenario.Status.Steps[0].StartTime = &metav1.Time{Time: time.Now().Add(-1 * time.Hour)}
+				Expect(k8sClient.Status().Update(context.TODO(), scenario)).NotTo(HaveOccurred())
+
+				result, err = reconcileTestScenario(scenario)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+			})
+
+			It("should fail the scenario", func() {
+				Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhaseFailed))
+				Expect(scenario.Status.Message).To(HavePrefix("step kill-storage failed: Pod"))
+				Expect(scenario.Status.Steps[0].Phase).To(Equal(fdbv1beta2.TestScenarioPhaseFailed))
+				Expect(scenario.Status.Steps[0].CompletionTime).NotTo(BeNil())
+			})
+		})
+
+		When("the cluster recovers", func() {
+			JustBeforeEach(func() {
+				_, err = reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+
+				result, err = reconcileTestScenario(scenario)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+			})
+
+			It("should pass the scenario", func() {
+				Expect(result.IsZero()).To(BeTrue())
+				Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhasePassed))
+				Expect(scenario.Status.CurrentStep).To(Equal(1))
+				Expect(scenario.Status.Steps[0].Phase).To(Equal(fdbv1beta2.TestScenarioPhasePassed))
+				Expect(scenario.Status.Steps[0].Message).To(BeEmpty())
+				Expect(scenario.Status.CompletionTime).NotTo(BeNil())
+			})
+		})
+	})
+
+	When("partitioning the sidecar", func() {
+		BeforeEach(func() {
+			scenario.Spec.Steps = []fdbv1beta2.TestScenarioStep{
+				{
+					Name:            "partition",
+					Action:          fdbv1beta2.TestScenarioActionPartitionSidecar,
+					DurationSeconds: pointer.Int(60),
+				},
+			}
+		})
+
+		It("should create the network policy and label the Pod", func() {
+			Expect(scenario.Status.Steps[0].TargetedPods).To(HaveLen(1))
+
+			policy := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: scenario.Namespace, Name: "scenario-sidecar-partition"}, policy)).NotTo(HaveOccurred())
+			Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{fdbv1beta2.TestScenarioPartitionLabel: scenario.Name}))
+			Expect(policy.Spec.Ingress).To(HaveLen(1))
+			Expect(policy.Spec.Ingress[0].Ports).To(HaveLen(2))
+			Expect(*policy.Spec.Ingress[0].Ports[0].EndPort).To(BeNumerically("==", 8079))
+			Expect(policy.Spec.Ingress[0].Ports[1].Port.IntValue()).To(Equal(8081))
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: scenario.Status.Steps[0].TargetedPods[0]}, pod)).NotTo(HaveOccurred())
+			Expect(pod.Labels).To(HaveKeyWithValue(fdbv1beta2.TestScenarioPartitionLabel, scenario.Name))
+		})
+
+		When("the partition is still active", func() {
+			JustBeforeEach(func() {
+				result, err = reconcileTestScenario(scenario)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+			})
+
+			It("should wait until the duration passed", func() {
+				Expect(result.RequeueAfter).To(BeNumerically(">", 50*time.Second))
+				Expect(scenario.Status.Steps[0].Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+			})
+		})
+
+		When("the partition duration passed", func() {
+			JustBeforeEach(func() {
+				scenario.Status.Steps[0].StartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+				Expect(k8sClient.Status().Update(context.TODO(), scenario)).NotTo(HaveOccurred())
+
+				result, err = reconcileTestScenario(scenario)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+			})
+
+			It("should remove the partition and pass the scenario", func() {
+				Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhasePassed))
+
+				policy := &networkingv1.NetworkPolicy{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: scenario.Namespace, Name: "scenario-sidecar-partition"}, policy)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+				pod := &corev1.Pod{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: scenario.Status.Steps[0].TargetedPods[0]}, pod)).NotTo(HaveOccurred())
+				Expect(pod.Labels).NotTo(HaveKey(fdbv1beta2.TestScenarioPartitionLabel))
+			})
+		})
+	})
+
+	When("upgrading the cluster", func() {
+		var version string
+
+		BeforeEach(func() {
+			version = fdbv1beta2.Versions.NextPatchVersion.String()
+			scenario.Spec.Steps = []fdbv1beta2.TestScenarioStep{
+				{
+					Name:    "upgrade",
+					Action:  fdbv1beta2.TestScenarioActionUpgrade,
+					Version: version,
+				},
+			}
+		})
+
+		It("should update the version of the cluster", func() {
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Spec.Version).To(Equal(version))
+			Expect(scenario.Status.Steps[0].Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+		})
+
+		When("the upgrade is done", func() {
+			JustBeforeEach(func() {
+				// The operator has to do multiple things before the upgrade is reconciled.
+				_, err = reconcileClusterWithCustomRequeueLimit(cluster, 50)
+				Expect(err).NotTo(HaveOccurred())
+
+				result, err = reconcileTestScenario(scenario)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+			})
+
+			It("should pass the scenario", func() {
+				Expect(scenario.Status.Steps[0].Message).To(BeEmpty())
+				Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhasePassed))
+			})
+		})
+	})
+
+	When("the scenario has multiple steps", func() {
+		BeforeEach(func() {
+			scenario.Spec.Steps = []fdbv1beta2.TestScenarioStep{
+				{
+					Name:            "wait",
+					Action:          fdbv1beta2.TestScenarioActionWait,
+					DurationSeconds: pointer.Int(0),
+				},
+				{
+					Name:   "kill",
+					Action: fdbv1beta2.TestScenarioActionKillPods,
+				},
+			}
+		})
+
+		It("should run the next step after the previous step passed", func() {
+			Expect(scenario.Status.Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+			Expect(scenario.Status.Steps[0].Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+
+			result, err = reconcileTestScenario(scenario)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadTestScenario(scenario)).NotTo(HaveOccurred())
+
+			Expect(scenario.Status.CurrentStep).To(Equal(1))
+			Expect(scenario.Status.Steps[0].Phase).To(Equal(fdbv1beta2.TestScenarioPhasePassed))
+			Expect(scenario.Status.Steps[1].Phase).To(Equal(fdbv1beta2.TestScenarioPhaseRunning))
+			Expect(scenario.Status.Steps[1].TargetedPods).To(HaveLen(1))
+		})
+	})
+})
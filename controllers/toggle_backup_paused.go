@@ -32,6 +32,10 @@ type toggleBackupPaused struct{}
 
 // reconcile runs the reconciler's work.
 func (s toggleBackupPaused) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	if backup.UsesVolumeSnapshots() {
+		return nil
+	}
+
 	if backup.Status.BackupDetails == nil {
 		if backup.ShouldRun() {
 			return &requeue{message: "Cannot toggle backup state because backup is not running"}
@@ -46,7 +50,7 @@ func (s toggleBackupPaused) reconcile(ctx context.Context, r *FoundationDBBackup
 		}
 		defer adminClient.Close()
 
-		err = adminClient.PauseBackups()
+		err = adminClient.PauseBackups(ctx)
 		if err != nil {
 			return &requeue{curError: err}
 		}
@@ -58,7 +62,7 @@ func (s toggleBackupPaused) reconcile(ctx context.Context, r *FoundationDBBackup
 		}
 		defer adminClient.Close()
 
-		err = adminClient.ResumeBackups()
+		err = adminClient.ResumeBackups(ctx)
 		if err != nil {
 			return &requeue{curError: err}
 		}
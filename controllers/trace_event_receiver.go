@@ -0,0 +1,275 @@
+/*
+ * trace_event_receiver.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// severeTraceEventSeverity defines the minimum severity of a trace event to be reported by the TraceEventReceiver.
+	severeTraceEventSeverity = 40
+
+	// maxTraceEventRequestSize defines the maximum size of a request body that will be read by the TraceEventReceiver.
+	maxTraceEventRequestSize = 1 << 20
+
+	// otherTraceEventType is the type label of the metrics for all trace event types that are not in
+	// reportedTraceEventTypes.
+	otherTraceEventType = "Other"
+)
+
+// reportedTraceEventTypes contains the trace event types that are reported with their own type label in the metrics.
+// All other types are reported as otherTraceEventType, so the cardinality of the metrics doesn't depend on the content
+// of the requests.
+var reportedTraceEventTypes = map[string]fdbv1beta2.None{
+	"InternalError":       {},
+	"AssertFailure":       {},
+	"StorageServerFailed": {},
+	"TLogError":           {},
+	"WorkerFailed":        {},
+	"IOTimeoutError":      {},
+	"FileOpenError":       {},
+	"ProcessFailed":       {},
+}
+
+// TraceEventReceiver receives trace events from the trace log forwarders of the FoundationDB pods and converts
+// severe trace events into Kubernetes events and metrics on the owning cluster.
+type TraceEventReceiver struct {
+	Client   client.Client
+	Recorder record.EventRecorder
+	Log      logr.Logger
+}
+
+// NewTraceEventReceiver creates a new TraceEventReceiver that uses the client and recorder of the reconciler.
+func NewTraceEventReceiver(reconciler *FoundationDBClusterReconciler) *TraceEventReceiver {
+	return &TraceEventReceiver{
+		Client:   reconciler.Client,
+		Recorder: reconciler.Recorder,
+		Log:      reconciler.Log.WithName("TraceEventReceiver"),
+	}
+}
+
+// severeTraceEvents aggregates the severe trace events of a single type.
+type severeTraceEvents struct {
+	count    int
+	machines map[string]fdbv1beta2.None
+}
+
+// ServeHTTP handles a list of trace events in JSON format, e.g. as sent by the fluent-bit http output. The namespace and
+// the name of the cluster are taken from the namespace and cluster query parameters. The request must be authenticated
+// with the token of the cluster as bearer token and must be sent from one of the Pods of the cluster.
+func (receiver *TraceEventReceiver) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(writer, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	namespace := request.URL.Query().Get("namespace")
+	clusterName := request.URL.Query().Get("cluster")
+	if namespace == "" || clusterName == "" {
+		http.Error(writer, "namespace and cluster must be provided", http.StatusBadRequest)
+		return
+	}
+
+	logger := receiver.Log.WithValues("namespace", namespace, "cluster", clusterName)
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := receiver.Client.Get(request.Context(), client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			http.Error(writer, fmt.Sprintf("cluster %s/%s not found", namespace, clusterName), http.StatusNotFound)
+			return
+		}
+
+		logger.Error(err, "could not fetch cluster")
+		http.Error(writer, "could not fetch cluster", http.StatusInternalServerError)
+		return
+	}
+
+	authorized, err := receiver.isAuthorized(request, cluster)
+	if err != nil {
+		logger.Error(err, "could not fetch trace event receiver token")
+		http.Error(writer, "could not authorize request", http.StatusInternalServerError)
+		return
+	}
+
+	if !authorized {
+		http.Error(writer, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	fromPod, err := receiver.isSentFromPod(request, cluster)
+	if err != nil {
+		logger.Error(err, "could not fetch Pods")
+		http.Error(writer, "could not authorize request", http.StatusInternalServerError)
+		return
+	}
+
+	if !fromPod {
+		logger.Info("Rejecting trace events that were not sent from a Pod of the cluster", "remoteAddr", request.RemoteAddr)
+		http.Error(writer, "request was not sent from a Pod of the cluster", http.StatusForbidden)
+		return
+	}
+
+	var traceEvents []map[string]interface{}
+	err = json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxTraceEventRequestSize)).Decode(&traceEvents)
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("could not parse trace events: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	severeEvents := getSevereTraceEvents(traceEvents)
+	eventTypes := make([]string, 0, len(severeEvents))
+	for eventType := range severeEvents {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+
+	for _, eventType := range eventTypes {
+		events := severeEvents[eventType]
+		machines := make([]string, 0, len(events.machines))
+		for machine := range events.machines {
+			machines = append(machines, machine)
+		}
+		sort.Strings(machines)
+
+		logger.Info("Received severe trace events", "type", eventType, "count", events.count, "machines", machines)
+		severeTraceEventsCounter.WithLabelValues(cluster.Namespace, cluster.Name, getTraceEventTypeLabel(eventType)).Add(float64(events.count))
+		receiver.Recorder.Event(cluster, corev1.EventTypeWarning, "SevereTraceEvent",
+			fmt.Sprintf("Received %d trace events of type %s with severity %d or higher from %v", events.count, eventType, severeTraceEventSeverity, machines))
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// isAuthorized checks if the request contains the token of the trace event receiver secret of the cluster as bearer
+// token.
+func (receiver *TraceEventReceiver) isAuthorized(request *http.Request, cluster *fdbv1beta2.FoundationDBCluster) (bool, error) {
+	if !cluster.UsesTraceEventReceiver() {
+		return false, nil
+	}
+
+	token := strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return false, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := receiver.Client.Get(request.Context(), client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetTraceEventReceiverSecretName()}, secret)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	expected := secret.Data[internal.TraceEventReceiverTokenKey]
+	if len(expected) == 0 {
+		return false, nil
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), expected) == 1, nil
+}
+
+// isSentFromPod checks if the source address of the request is the IP address of one of the Pods of the cluster.
+func (receiver *TraceEventReceiver) isSentFromPod(request *http.Request, cluster *fdbv1beta2.FoundationDBCluster) (bool, error) {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+
+	sourceIP := net.ParseIP(host)
+	if sourceIP == nil {
+		return false, nil
+	}
+
+	pods := &corev1.PodList{}
+	err = receiver.Client.List(request.Context(), pods, client.InNamespace(cluster.Namespace), client.MatchingLabels(cluster.GetMatchLabels()))
+	if err != nil {
+		return false, err
+	}
+
+	for _, pod := range pods.Items {
+		for _, podIP := range pod.Status.PodIPs {
+			if sourceIP.Equal(net.ParseIP(podIP.IP)) {
+				return true, nil
+			}
+		}
+
+		if sourceIP.Equal(net.ParseIP(pod.Status.PodIP)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getTraceEventTypeLabel returns the type label of the metrics for the trace event type.
+func getTraceEventTypeLabel(eventType string) string {
+	if _, ok := reportedTraceEventTypes[eventType]; ok {
+		return eventType
+	}
+
+	return otherTraceEventType
+}
+
+// getSevereTraceEvents groups all trace events with a severity of at least severeTraceEventSeverity by their type.
+func getSevereTraceEvents(traceEvents []map[string]interface{}) map[string]*severeTraceEvents {
+	severeEvents := map[string]*severeTraceEvents{}
+
+	for _, traceEvent := range traceEvents {
+		// The JSON trace logs of fdbserver contain all values as strings.
+		severity, err := strconv.Atoi(fmt.Sprint(traceEvent["Severity"]))
+		if err != nil || severity < severeTraceEventSeverity {
+			continue
+		}
+
+		eventType := fmt.Sprint(traceEvent["Type"])
+		events, ok := severeEvents[eventType]
+		if !ok {
+			events = &severeTraceEvents{machines: map[string]fdbv1beta2.None{}}
+			severeEvents[eventType] = events
+		}
+
+		events.count++
+		if machine, ok := traceEvent["Machine"]; ok {
+			events.machines[fmt.Sprint(machine)] = fdbv1beta2.None{}
+		}
+	}
+
+	return severeEvents
+}
@@ -0,0 +1,211 @@
+/*
+ * trace_event_receiver_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("trace_event_receiver", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var receiver *TraceEventReceiver
+	var response *httptest.ResponseRecorder
+	var method string
+	var url string
+	var body string
+	var token string
+	var remoteAddr string
+
+	getSevereTraceEvents := func() []corev1.Event {
+		events := &corev1.EventList{}
+		Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+		var matchingEvents []corev1.Event
+		for _, event := range events.Items {
+			if event.InvolvedObject.UID == cluster.ObjectMeta.UID && event.Reason == "SevereTraceEvent" {
+				matchingEvents = append(matchingEvents, event)
+			}
+		}
+
+		return matchingEvents
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.TraceLogs = &fdbv1beta2.TraceLogSpec{EventReceiverAddress: "fdb-kubernetes-operator.default.svc:8080"}
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.GetTraceEventReceiverSecretName()}, secret)).NotTo(HaveOccurred())
+		token = string(secret.Data[internal.TraceEventReceiverTokenKey])
+
+		pods := &corev1.PodList{}
+		Expect(k8sClient.List(context.TODO(), pods, client.InNamespace(cluster.Namespace), client.MatchingLabels(cluster.GetMatchLabels()))).NotTo(HaveOccurred())
+		Expect(pods.Items).NotTo(BeEmpty())
+		remoteAddr = net.JoinHostPort(pods.Items[0].Status.PodIP, "43210")
+
+		receiver = NewTraceEventReceiver(clusterReconciler)
+		method = http.MethodPost
+		url = internal.TraceEventReceiverPath + "?namespace=my-ns&cluster=operator-test-1"
+		body = `[{"Severity": "10", "Type": "Role", "Machine": "1.1.1.1:4501"},
+{"Severity": "40", "Type": "InternalError", "Machine": "1.1.1.1:4501"},
+{"Severity": "40", "Type": "InternalError", "Machine": "1.1.1.2:4501"},
+{"Severity": "40", "Type": "DiskError", "Machine": "1.1.1.2:4501"}]`
+	})
+
+	JustBeforeEach(func() {
+		response = httptest.NewRecorder()
+		request := httptest.NewRequest(method, url, strings.NewReader(body))
+		request.RemoteAddr = remoteAddr
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		receiver.ServeHTTP(response, request)
+	})
+
+	When("receiving severe trace events", func() {
+		It("should accept the request", func() {
+			Expect(response.Code).To(Equal(http.StatusNoContent))
+		})
+
+		It("should create an event for each type of severe trace event", func() {
+			messages := make([]string, 0, 2)
+			for _, event := range getSevereTraceEvents() {
+				Expect(event.Type).To(Equal(corev1.EventTypeWarning))
+				messages = append(messages, event.Message)
+			}
+
+			Expect(messages).To(ConsistOf(
+				"Received 1 trace events of type DiskError with severity 40 or higher from [1.1.1.2:4501]",
+				"Received 2 trace events of type InternalError with severity 40 or higher from [1.1.1.1:4501 1.1.1.2:4501]",
+			))
+		})
+
+		It("should update the metrics", func() {
+			Expect(testutil.ToFloat64(severeTraceEventsCounter.WithLabelValues(cluster.Namespace, cluster.Name, "InternalError"))).To(BeNumerically(">=", 2))
+		})
+
+		It("should report unknown trace event types as other types in the metrics", func() {
+			Expect(testutil.ToFloat64(severeTraceEventsCounter.WithLabelValues(cluster.Namespace, cluster.Name, otherTraceEventType))).To(BeNumerically(">=", 1))
+			Expect(testutil.ToFloat64(severeTraceEventsCounter.WithLabelValues(cluster.Namespace, cluster.Name, "DiskError"))).To(BeZero())
+		})
+	})
+
+	When("the request has no token", func() {
+		BeforeEach(func() {
+			token = ""
+		})
+
+		It("should reject the request", func() {
+			Expect(response.Code).To(Equal(http.StatusUnauthorized))
+			Expect(getSevereTraceEvents()).To(BeEmpty())
+		})
+	})
+
+	When("the request has an invalid token", func() {
+		BeforeEach(func() {
+			token = "invalid"
+		})
+
+		It("should reject the request", func() {
+			Expect(response.Code).To(Equal(http.StatusUnauthorized))
+			Expect(getSevereTraceEvents()).To(BeEmpty())
+		})
+	})
+
+	When("the request is not sent from a Pod of the cluster", func() {
+		BeforeEach(func() {
+			remoteAddr = "192.0.2.1:43210"
+		})
+
+		It("should reject the request", func() {
+			Expect(response.Code).To(Equal(http.StatusForbidden))
+			Expect(getSevereTraceEvents()).To(BeEmpty())
+		})
+	})
+
+	When("receiving no severe trace events", func() {
+		BeforeEach(func() {
+			body = `[{"Severity": "20", "Type": "SlowTask", "Machine": "1.1.1.1:4501"}]`
+		})
+
+		It("should accept the request without creating an event", func() {
+			Expect(response.Code).To(Equal(http.StatusNoContent))
+			Expect(getSevereTraceEvents()).To(BeEmpty())
+		})
+	})
+
+	When("the cluster does not exist", func() {
+		BeforeEach(func() {
+			url = internal.TraceEventReceiverPath + "?namespace=my-ns&cluster=missing"
+		})
+
+		It("should return not found", func() {
+			Expect(response.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	When("the cluster is not specified", func() {
+		BeforeEach(func() {
+			url = internal.TraceEventReceiverPath + "?namespace=my-ns"
+		})
+
+		It("should reject the request", func() {
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	When("the body is not a list of trace events", func() {
+		BeforeEach(func() {
+			body = `{"Severity": "40"}`
+		})
+
+		It("should reject the request", func() {
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+			Expect(getSevereTraceEvents()).To(BeEmpty())
+		})
+	})
+
+	When("using a GET request", func() {
+		BeforeEach(func() {
+			method = http.MethodGet
+		})
+
+		It("should reject the request", func() {
+			Expect(response.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
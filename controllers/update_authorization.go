@@ -0,0 +1,105 @@
+/*
+ * update_authorization.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateAuthorization provides a reconciliation step for distributing the public keys for the token based
+// authorization to the fdbserver processes.
+type updateAuthorization struct{}
+
+// reconcile runs the reconciler's work.
+func (updateAuthorization) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	var keyIDs []string
+	if cluster.AuthorizationEnabled() {
+		logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateAuthorization")
+
+		keySets := make([][]byte, 0, len(cluster.Spec.Authorization.PublicKeySecrets))
+		for _, selector := range cluster.Spec.Authorization.PublicKeySecrets {
+			secret := &corev1.Secret{}
+			err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: selector.Name}, secret)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			keySet, ok := secret.Data[selector.Key]
+			if !ok {
+				return &requeue{curError: fmt.Errorf("secret %s has no key %s", selector.Name, selector.Key)}
+			}
+
+			keySets = append(keySets, keySet)
+		}
+
+		// If any of the key sets is invalid, the previously distributed keys are kept, so a broken secret doesn't
+		// lock out the clients.
+		publicKeys, mergedKeyIDs, err := internal.MergePublicKeySets(keySets)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		keyIDs = mergedKeyIDs
+
+		desired := internal.GetAuthorizationSecret(cluster, publicKeys)
+		existing := &corev1.Secret{}
+		err = r.Get(ctx, types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, existing)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return &requeue{curError: err}
+			}
+
+			logger.Info("Creating authorization secret", "name", desired.Name, "keyIDs", keyIDs)
+			err = r.Create(ctx, desired)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+		} else if !bytes.Equal(existing.Data[internal.AuthorizationPublicKeysKey], publicKeys) {
+			logger.Info("Updating authorization secret", "name", desired.Name, "keyIDs", keyIDs)
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingAuthorizationPublicKeys", fmt.Sprintf("Distributing public keys: %v", keyIDs))
+			existing.Data = desired.Data
+			err = r.Update(ctx, existing)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+		}
+	}
+
+	if equality.Semantic.DeepEqual(cluster.Status.AuthorizationPublicKeyIDs, keyIDs) {
+		return nil
+	}
+
+	cluster.Status.AuthorizationPublicKeyIDs = keyIDs
+	err := r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ * update_authorization_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_authorization", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var requeue *requeue
+
+	createKeySecret := func(name string, keySet string) {
+		Expect(k8sClient.Create(context.TODO(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+			Data:       map[string][]byte{"jwks.json": []byte(keySet)},
+		})).NotTo(HaveOccurred())
+	}
+
+	getAuthorizationSecret := func() (*corev1.Secret, error) {
+		secret := &corev1.Secret{}
+		err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.GetAuthorizationSecretName()}, secret)
+		return secret, err
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateAuthorization{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the token based authorization is disabled", func() {
+		It("should not create the authorization secret", func() {
+			Expect(requeue).To(BeNil())
+			_, err := getAuthorizationSecret()
+			Expect(err).To(HaveOccurred())
+			Expect(cluster.Status.AuthorizationPublicKeyIDs).To(BeEmpty())
+		})
+	})
+
+	When("the token based authorization is enabled", func() {
+		BeforeEach(func() {
+			createKeySecret("keys-1", `{"keys": [{"kid": "key-1", "kty": "EC"}]}`)
+			cluster.Spec.Authorization = &fdbv1beta2.AuthorizationSpec{
+				PublicKeySecrets: []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "keys-1"}, Key: "jwks.json"}},
+			}
+		})
+
+		It("should create the authorization secret", func() {
+			Expect(requeue).To(BeNil())
+			secret, err := getAuthorizationSecret()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(secret.Data[internal.AuthorizationPublicKeysKey])).To(Equal(`{"keys":[{"kid":"key-1","kty":"EC"}]}`))
+			Expect(cluster.Status.AuthorizationPublicKeyIDs).To(Equal([]string{"key-1"}))
+		})
+
+		When("a second key is added", func() {
+			JustBeforeEach(func() {
+				Expect(requeue).To(BeNil())
+				createKeySecret("keys-2", `{"keys": [{"kid": "key-2", "kty": "EC"}]}`)
+				cluster.Spec.Authorization.PublicKeySecrets = append(cluster.Spec.Authorization.PublicKeySecrets, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "keys-2"}, Key: "jwks.json"})
+				requeue = updateAuthorization{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should distribute both keys", func() {
+				Expect(requeue).To(BeNil())
+				secret, err := getAuthorizationSecret()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(secret.Data[internal.AuthorizationPublicKeysKey])).To(Equal(`{"keys":[{"kid":"key-1","kty":"EC"},{"kid":"key-2","kty":"EC"}]}`))
+				Expect(cluster.Status.AuthorizationPublicKeyIDs).To(Equal([]string{"key-1", "key-2"}))
+			})
+		})
+
+		When("a referenced secret is missing", func() {
+			JustBeforeEach(func() {
+				Expect(requeue).To(BeNil())
+				cluster.Spec.Authorization.PublicKeySecrets = []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "jwks.json"}}
+				requeue = updateAuthorization{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should keep the distributed keys", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.curError).To(HaveOccurred())
+				secret, err := getAuthorizationSecret()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(secret.Data[internal.AuthorizationPublicKeysKey])).To(Equal(`{"keys":[{"kid":"key-1","kty":"EC"}]}`))
+				Expect(cluster.Status.AuthorizationPublicKeyIDs).To(Equal([]string{"key-1"}))
+			})
+		})
+
+		When("a referenced secret contains an invalid key set", func() {
+			BeforeEach(func() {
+				createKeySecret("invalid", `{"keys": [{"kty": "EC"}]}`)
+				cluster.Spec.Authorization.PublicKeySecrets = []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "invalid"}, Key: "jwks.json"}}
+			})
+
+			It("should not create the authorization secret", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.curError).To(MatchError("JSON Web Key must define a kid and a kty"))
+				_, err := getAuthorizationSecret()
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
@@ -0,0 +1,86 @@
+/*
+ * update_backup_service_account.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateBackupServiceAccount provides a reconciliation step for creating the
+// service account of the backup agents that is bound to the role of the
+// workload identity.
+type updateBackupServiceAccount struct{}
+
+// reconcile runs the reconciler's work.
+func (u updateBackupServiceAccount) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	// A user provided service account must be bound to the role by the user.
+	if backup.Spec.BlobStoreConfiguration == nil || backup.Spec.BlobStoreConfiguration.WorkloadIdentity == nil || backup.Spec.BlobStoreConfiguration.WorkloadIdentity.ServiceAccountName != "" {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", backup.Namespace, "backup", backup.Name, "reconciler", "updateBackupServiceAccount")
+	desired := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       backup.Namespace,
+			Name:            backup.GetBackupAgentServiceAccountName(),
+			Annotations:     backup.Spec.BlobStoreConfiguration.WorkloadIdentity.GetRoleAnnotations(),
+			OwnerReferences: internal.BuildOwnerReference(backup.TypeMeta, backup.ObjectMeta),
+		},
+	}
+
+	existing := &corev1.ServiceAccount{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: desired.Namespace, Name: desired.Name}, existing)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return &requeue{curError: err}
+		}
+
+		logger.V(1).Info("Creating service account", "name", desired.Name)
+		err = r.Create(ctx, desired)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return nil
+	}
+
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+
+	if mergeAnnotations(&existing.ObjectMeta, desired.ObjectMeta) {
+		logger.V(1).Info("Updating service account", "name", existing.Name)
+		err = r.Update(ctx, existing)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
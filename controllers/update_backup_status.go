@@ -28,6 +28,7 @@ import (
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -39,6 +40,7 @@ type updateBackupStatus struct{}
 func (s updateBackupStatus) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
 	status := fdbv1beta2.FoundationDBBackupStatus{}
 	status.Generations.Reconciled = backup.Status.Generations.Reconciled
+	status.Conditions = backup.Status.Conditions
 
 	backupDeployments := &appsv1.DeploymentList{}
 	err := r.List(ctx, backupDeployments, client.InNamespace(backup.Namespace), client.MatchingLabels(map[string]string{fdbv1beta2.BackupDeploymentLabel: string(backup.ObjectMeta.UID)}))
@@ -76,22 +78,35 @@ func (s updateBackupStatus) reconcile(ctx context.Context, r *FoundationDBBackup
 		status.DeploymentConfigured = false
 	}
 
-	adminClient, err := r.adminClientForBackup(ctx, backup)
-	if err != nil {
-		return &requeue{curError: err}
-	}
-	defer adminClient.Close()
+	// Backups with VolumeSnapshots don't use the backup agents, so there is no live backup status.
+	if backup.UsesVolumeSnapshots() {
+		status.VolumeSnapshotBackup = backup.Status.VolumeSnapshotBackup
+	} else {
+		adminClient, err := r.adminClientForBackup(ctx, backup)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		defer adminClient.Close()
 
-	liveStatus, err := adminClient.GetBackupStatus()
-	if err != nil {
-		return &requeue{curError: err}
-	}
+		liveStatus, err := adminClient.GetBackupStatus(ctx)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		status.BackupDetails = &fdbv1beta2.FoundationDBBackupStatusBackupDetails{
+			URL:                   internal.RedactString(liveStatus.DestinationURL),
+			Running:               liveStatus.Status.Running,
+			Paused:                liveStatus.BackupAgentsPaused,
+			SnapshotPeriodSeconds: liveStatus.SnapshotIntervalSeconds,
+			Restorable:            liveStatus.Restorable,
+			StopAfterSnapshot:     liveStatus.StopAfterSnapshot,
+			Completed:             liveStatus.Status.Completed,
+		}
 
-	status.BackupDetails = &fdbv1beta2.FoundationDBBackupStatusBackupDetails{
-		URL:                   liveStatus.DestinationURL,
-		Running:               liveStatus.Status.Running,
-		Paused:                liveStatus.BackupAgentsPaused,
-		SnapshotPeriodSeconds: liveStatus.SnapshotIntervalSeconds,
+		if liveStatus.LatestRestorablePoint != nil {
+			status.BackupDetails.LastRestorableVersion = pointer.Int64(liveStatus.LatestRestorablePoint.Version)
+			status.BackupDetails.SecondsBehind = pointer.Int64(liveStatus.LatestRestorablePoint.LagSeconds)
+		}
 	}
 
 	originalStatus := backup.Status.DeepCopy()
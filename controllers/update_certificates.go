@@ -0,0 +1,251 @@
+/*
+ * update_certificates.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// certificateIssuanceRetryDelay defines how long the operator waits before it checks again if cert-manager has issued
+// the pending certificates.
+const certificateIssuanceRetryDelay = 5 * time.Second
+
+// updateCertificates provides a reconciliation step for requesting the certificates of the processes from
+// cert-manager. The hash of every issued certificate is recorded in the cluster status, the processes that use a
+// renewed certificate are restarted by the bounceRenewedCertificates sub-reconciler.
+type updateCertificates struct{}
+
+// reconcile runs the reconciler's work.
+func (updateCertificates) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateCertificates")
+
+	var hashes map[string]string
+	var pending []string
+	if cluster.CertManagerEnabled() {
+		processGroupIDs := []fdbv1beta2.ProcessGroupID{""}
+		if cluster.GetCertificateScope() == fdbv1beta2.CertificateScopeProcessGroup {
+			processGroupIDs = make([]fdbv1beta2.ProcessGroupID, 0, len(cluster.Status.ProcessGroups))
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				processGroupIDs = append(processGroupIDs, processGroup.ProcessGroupID)
+			}
+		}
+
+		hashes = make(map[string]string, len(processGroupIDs))
+		for _, processGroupID := range processGroupIDs {
+			desired, err := internal.GetCertificate(cluster, processGroupID)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			ready, err := ensureCertificate(ctx, logger, r, desired)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			name := desired.GetName()
+			if !ready {
+				pending = append(pending, name)
+				// Keep the hash of the previous certificate, so the processes are not restarted while a certificate
+				// is reissued.
+				if hash, ok := cluster.Status.TLSCertificateHashes[name]; ok {
+					hashes[name] = hash
+				}
+
+				continue
+			}
+
+			secret := &corev1.Secret{}
+			err = r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: name}, secret)
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					pending = append(pending, name)
+					continue
+				}
+
+				return &requeue{curError: err}
+			}
+
+			hash, err := internal.GetTLSCertificateHash(secret)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			if previous, ok := cluster.Status.TLSCertificateHashes[name]; ok && previous != hash {
+				logger.Info("Certificate was renewed", "name", name)
+				r.Recorder.Event(cluster, corev1.EventTypeNormal, "TLSCertificateRenewed", fmt.Sprintf("Certificate %s was renewed, the processes that use the certificate will be restarted", name))
+			}
+
+			hashes[name] = hash
+		}
+	}
+
+	// Certificates that are no longer used, e.g. the certificates of removed process groups, are deleted together
+	// with their secrets.
+	for name := range cluster.Status.TLSCertificateHashes {
+		if _, ok := hashes[name]; ok {
+			continue
+		}
+
+		err := deleteCertificate(ctx, logger, r, cluster, name)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	// The processes of new process groups are started with the issued certificate, so the hash is recorded directly.
+	processGroupsChanged := false
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		hash, ok := hashes[cluster.GetCertificateName(processGroup.ProcessGroupID)]
+		if !ok {
+			if processGroup.TLSCertificateHash != "" && !cluster.CertManagerEnabled() {
+				processGroup.TLSCertificateHash = ""
+				processGroupsChanged = true
+			}
+
+			continue
+		}
+
+		if processGroup.TLSCertificateHash == "" {
+			processGroup.TLSCertificateHash = hash
+			processGroupsChanged = true
+		}
+	}
+
+	if processGroupsChanged || !equality.Semantic.DeepEqual(cluster.Status.TLSCertificateHashes, hashes) {
+		cluster.Status.TLSCertificateHashes = hashes
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		logger.Info("Waiting for cert-manager to issue the certificates", "certificates", pending)
+		return &requeue{
+			message:        fmt.Sprintf("Waiting for cert-manager to issue the certificates: %v", pending),
+			delay:          certificateIssuanceRetryDelay,
+			delayedRequeue: true,
+		}
+	}
+
+	return nil
+}
+
+// ensureCertificate creates the Certificate or updates the spec of an existing Certificate and returns if the
+// certificate is ready.
+func ensureCertificate(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, desired *unstructured.Unstructured) (bool, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(internal.CertificateGroupVersionKind)
+	err := r.Get(ctx, types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}, existing)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+
+		logger.Info("Creating certificate", "name", desired.GetName())
+		return false, r.Create(ctx, desired)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return internal.IsCertificateReady(existing), nil
+	}
+
+	logger.Info("Updating certificate", "name", desired.GetName())
+	existing.Object["spec"] = desired.Object["spec"]
+	existing.SetLabels(desired.GetLabels())
+
+	// cert-manager reissues the certificate after the spec was changed, so the certificate is not ready until the
+	// status was updated.
+	return false, r.Update(ctx, existing)
+}
+
+// deleteCertificate deletes the Certificate and the secret with the issued certificate. Only the Certificate that was
+// created by the operator and the secret that cert-manager issued for it are deleted, as the name of the Certificate
+// could collide with resources that were created by the user.
+func deleteCertificate(ctx context.Context, logger logr.Logger, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, name string) error {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(internal.CertificateGroupVersionKind)
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: name}, certificate)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if err == nil {
+		if !hasClusterLabels(cluster, certificate.GetLabels()) {
+			logger.Info("Skipping deletion of certificate that was not created by the operator", "name", name)
+			return nil
+		}
+
+		logger.Info("Deleting certificate", "name", name)
+		err = r.Delete(ctx, certificate)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	secret := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: name}, secret)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if secret.Annotations[internal.CertificateNameAnnotation] != name || !hasClusterLabels(cluster, secret.Labels) {
+		logger.Info("Skipping deletion of secret that was not issued for the certificate", "name", name)
+		return nil
+	}
+
+	logger.Info("Deleting certificate secret", "name", name)
+	err = r.Delete(ctx, secret)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// hasClusterLabels returns true if the labels contain all match labels of the cluster.
+func hasClusterLabels(cluster *fdbv1beta2.FoundationDBCluster, labels map[string]string) bool {
+	for key, value := range cluster.GetMatchLabels() {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
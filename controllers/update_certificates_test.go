@@ -0,0 +1,274 @@
+/*
+ * update_certificates_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_certificates", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var requeue *requeue
+
+	getCertificate := func(name string) (*unstructured.Unstructured, error) {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(internal.CertificateGroupVersionKind)
+		err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: name}, certificate)
+		return certificate, err
+	}
+
+	// issueCertificate simulates cert-manager by marking the certificate as ready and creating the secret.
+	issueCertificate := func(name string, content string) {
+		certificate, err := getCertificate(name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unstructured.SetNestedSlice(certificate.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		}, "status", "conditions")).NotTo(HaveOccurred())
+		Expect(k8sClient.Update(context.TODO(), certificate)).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		err = k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: name}, secret)
+		if k8serrors.IsNotFound(err) {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   cluster.Namespace,
+					Name:        name,
+					Labels:      certificate.GetLabels(),
+					Annotations: map[string]string{internal.CertificateNameAnnotation: name},
+				},
+				Data: map[string][]byte{corev1.TLSCertKey: []byte(content)},
+			}
+			Expect(k8sClient.Create(context.TODO(), secret)).NotTo(HaveOccurred())
+			return
+		}
+
+		Expect(err).NotTo(HaveOccurred())
+		secret.Data[corev1.TLSCertKey] = []byte(content)
+		Expect(k8sClient.Update(context.TODO(), secret)).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("cert-manager is not used", func() {
+		It("should not track any hashes", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.TLSCertificateHashes).To(BeEmpty())
+		})
+	})
+
+	When("cert-manager is used for the cluster", func() {
+		var certificateName string
+
+		BeforeEach(func() {
+			cluster.Spec.CertManager = &fdbv1beta2.CertManagerSpec{
+				IssuerRef: fdbv1beta2.CertManagerIssuerReference{Name: "fdb-issuer"},
+			}
+			certificateName = cluster.GetCertificateName("")
+		})
+
+		It("should create the certificate and wait until it is issued", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).NotTo(HaveOccurred())
+			Expect(requeue.message).To(Equal("Waiting for cert-manager to issue the certificates: [operator-test-1-tls]"))
+			Expect(requeue.delayedRequeue).To(BeTrue())
+
+			_, err := getCertificate(certificateName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Status.TLSCertificateHashes).To(BeEmpty())
+		})
+
+		When("the certificate was issued", func() {
+			JustBeforeEach(func() {
+				issueCertificate(certificateName, "certificate")
+				requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should track the hash of the certificate", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.TLSCertificateHashes).To(HaveLen(1))
+				Expect(cluster.Status.TLSCertificateHashes).To(HaveKey(certificateName))
+			})
+
+			It("should record the hash for the process groups", func() {
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					Expect(processGroup.TLSCertificateHash).To(Equal(cluster.Status.TLSCertificateHashes[certificateName]))
+				}
+			})
+
+			When("the certificate is renewed", func() {
+				var previousHash string
+
+				JustBeforeEach(func() {
+					Expect(requeue).To(BeNil())
+					previousHash = cluster.Status.TLSCertificateHashes[certificateName]
+
+					issueCertificate(certificateName, "renewed")
+					requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should update the hash", func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.TLSCertificateHashes[certificateName]).NotTo(Equal(previousHash))
+				})
+
+				It("should keep the hash that the processes were started with", func() {
+					for _, processGroup := range cluster.Status.ProcessGroups {
+						Expect(processGroup.TLSCertificateHash).To(Equal(previousHash))
+					}
+				})
+			})
+
+			When("the cert-manager settings are changed", func() {
+				var previousHash string
+
+				JustBeforeEach(func() {
+					Expect(requeue).To(BeNil())
+					previousHash = cluster.Status.TLSCertificateHashes[certificateName]
+
+					cluster.Spec.CertManager.IssuerRef.Kind = "ClusterIssuer"
+					requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should update the certificate and keep the previous hash until it is reissued", func() {
+					Expect(requeue).NotTo(BeNil())
+					Expect(requeue.message).To(HavePrefix("Waiting for cert-manager to issue the certificates"))
+					Expect(cluster.Status.TLSCertificateHashes).To(HaveKeyWithValue(certificateName, previousHash))
+
+					certificate, err := getCertificate(certificateName)
+					Expect(err).NotTo(HaveOccurred())
+					kind, _, err := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(kind).To(Equal("ClusterIssuer"))
+				})
+			})
+
+			When("cert-manager is disabled", func() {
+				JustBeforeEach(func() {
+					Expect(requeue).To(BeNil())
+
+					cluster.Spec.CertManager = nil
+					requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should delete the certificate", func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.TLSCertificateHashes).To(BeEmpty())
+
+					_, err := getCertificate(certificateName)
+					Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+					err = k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: certificateName}, &corev1.Secret{})
+					Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+
+			When("cert-manager is disabled and the secret was not issued by cert-manager", func() {
+				JustBeforeEach(func() {
+					Expect(requeue).To(BeNil())
+
+					secret := &corev1.Secret{}
+					Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: certificateName}, secret)).NotTo(HaveOccurred())
+					secret.Annotations = nil
+					Expect(k8sClient.Update(context.TODO(), secret)).NotTo(HaveOccurred())
+
+					cluster.Spec.CertManager = nil
+					requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should delete the certificate and keep the secret", func() {
+					Expect(requeue).To(BeNil())
+					Expect(cluster.Status.TLSCertificateHashes).To(BeEmpty())
+
+					_, err := getCertificate(certificateName)
+					Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+					Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: certificateName}, &corev1.Secret{})).NotTo(HaveOccurred())
+				})
+			})
+		})
+	})
+
+	When("cert-manager is used for every process group", func() {
+		BeforeEach(func() {
+			scope := fdbv1beta2.CertificateScopeProcessGroup
+			cluster.Spec.CertManager = &fdbv1beta2.CertManagerSpec{
+				IssuerRef: fdbv1beta2.CertManagerIssuerReference{Name: "fdb-issuer"},
+				Scope:     &scope,
+			}
+		})
+
+		It("should create a certificate for every process group", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(cluster.Status.ProcessGroups).NotTo(BeEmpty())
+
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				_, err := getCertificate(cluster.GetCertificateName(processGroup.ProcessGroupID))
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		When("a process group was removed", func() {
+			var removedName string
+
+			JustBeforeEach(func() {
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					issueCertificate(cluster.GetCertificateName(processGroup.ProcessGroupID), string(processGroup.ProcessGroupID))
+				}
+
+				requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.TLSCertificateHashes).To(HaveLen(len(cluster.Status.ProcessGroups)))
+
+				removedName = cluster.GetCertificateName(cluster.Status.ProcessGroups[0].ProcessGroupID)
+				cluster.Status.ProcessGroups = cluster.Status.ProcessGroups[1:]
+				requeue = updateCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should delete the certificate of the process group", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.TLSCertificateHashes).To(HaveLen(len(cluster.Status.ProcessGroups)))
+				Expect(cluster.Status.TLSCertificateHashes).NotTo(HaveKey(removedName))
+
+				_, err := getCertificate(removedName)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+})
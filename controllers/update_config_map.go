@@ -23,6 +23,7 @@ package controllers
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 
@@ -31,6 +32,7 @@ import (
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -40,7 +42,7 @@ type updateConfigMap struct{}
 
 // reconcile runs the reconciler's work.
 func (u updateConfigMap) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
-	configMap, err := internal.GetConfigMap(cluster)
+	configMap, err := internal.GetConfigMap(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -78,5 +80,18 @@ func (u updateConfigMap) reconcile(ctx context.Context, r *FoundationDBClusterRe
 		}
 	}
 
+	// Once the pending connection string of a committed coordinator change is part of the config map, the change
+	// was distributed.
+	coordinatorChange := cluster.Status.CoordinatorChange
+	if coordinatorChange != nil && coordinatorChange.State == fdbtypes.CoordinatorChangeStateCommitted && existing.Data[internal.ClusterFileKey] == coordinatorChange.PendingConnectionString {
+		logger.Info("Pending connection string was distributed", "connectionString", coordinatorChange.PendingConnectionString)
+		coordinatorChange.State = fdbtypes.CoordinatorChangeStateDistributed
+		coordinatorChange.Timestamp = &metav1.Time{Time: time.Now()}
+		err = r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,57 @@
+/*
+ * update_data_distribution_mode.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateDataDistributionMode provides a reconciliation step for enabling or disabling data distribution.
+type updateDataDistributionMode struct{}
+
+// reconcile runs the reconciler's work.
+func (updateDataDistributionMode) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !cluster.Status.Configured || !cluster.NeedsDataDistributionModeChange() {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateDataDistributionMode")
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	enabled := *cluster.Spec.DataDistribution.Enabled
+	logger.Info("Updating data distribution mode", "enabled", enabled)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingDataDistributionMode", fmt.Sprintf("Setting data distribution enabled to %t", enabled))
+	err = adminClient.SetDataDistributionMode(ctx, enabled)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+ * update_data_distribution_mode_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_data_distribution_mode", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		generation, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generation).To(Equal(int64(1)))
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateDataDistributionMode{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no data distribution mode is defined", func() {
+		BeforeEach(func() {
+			adminClient.DataDistributionDisabled = true
+			cluster.Status.DataDistributionDisabled = true
+		})
+
+		It("should not change the data distribution mode", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.DataDistributionDisabled).To(BeTrue())
+		})
+	})
+
+	When("data distribution should be disabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.DataDistribution = &fdbv1beta2.DataDistributionSpec{Enabled: pointer.Bool(false)}
+		})
+
+		It("should disable data distribution", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.DataDistributionDisabled).To(BeTrue())
+		})
+	})
+
+	When("data distribution should be enabled", func() {
+		BeforeEach(func() {
+			adminClient.DataDistributionDisabled = true
+			cluster.Status.DataDistributionDisabled = true
+			cluster.Spec.DataDistribution = &fdbv1beta2.DataDistributionSpec{Enabled: pointer.Bool(true)}
+		})
+
+		It("should enable data distribution", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.DataDistributionDisabled).To(BeFalse())
+		})
+	})
+
+	When("reconciling the cluster with data distribution disabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.DataDistribution = &fdbv1beta2.DataDistributionSpec{Enabled: pointer.Bool(false)}
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			generation, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(generation).To(Equal(int64(2)))
+		})
+
+		It("should report the data distribution mode in the status", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.DataDistributionDisabled).To(BeTrue())
+			Expect(cluster.Status.Generations.NeedsDataDistributionModeChange).To(BeZero())
+		})
+	})
+})
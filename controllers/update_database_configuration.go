@@ -47,12 +47,18 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 	}
 	defer adminClient.Close()
 
-	status, err := adminClient.GetStatus()
+	status, err := adminClient.GetStatus(ctx)
 	if err != nil {
 		return &requeue{curError: err, delayedRequeue: true}
 	}
 
 	initialConfig := !cluster.Status.Configured
+	// A clone already contains the database of the source cluster and must not be configured as a new database.
+	if initialConfig && cluster.IsBeingCloned() {
+		logger.Info("Waiting for the cloned database to become available")
+		return &requeue{message: "Waiting for the cloned database to become available", delayedRequeue: true}
+	}
+
 	if !(initialConfig || status.Client.DatabaseStatus.Available) {
 		logger.Info("Skipping database configuration change because database is unavailable")
 		return nil
@@ -66,6 +72,11 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 	currentConfiguration.ExcludedServers = nil
 	cluster.ClearMissingVersionFlags(&currentConfiguration)
 
+	if !initialConfig && cluster.Status.DatabaseConfigurationDrift != nil && !cluster.ShouldRevertDatabaseConfigurationDrift() {
+		logger.Info("Skipping reversion of database configuration drift", "mode", cluster.GetConfigureDatabaseMode(), "current configuration", currentConfiguration, "desired configuration", desiredConfiguration)
+		return nil
+	}
+
 	if initialConfig || !equality.Semantic.DeepEqual(desiredConfiguration, currentConfiguration) {
 		var nextConfiguration fdbtypes.DatabaseConfiguration
 		if initialConfig {
@@ -84,6 +95,14 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 		}
 
 		if !initialConfig {
+			if req := checkSettleTime(logger, cluster, status, "changing the database configuration"); req != nil {
+				return req
+			}
+
+			if req := checkRestoreInProgress(ctx, logger, r, cluster, "changing the database configuration"); req != nil {
+				return req
+			}
+
 			hasLock, err := r.takeLock(cluster,
 				fmt.Sprintf("reconfiguring the database to `%s`", configurationString))
 			if !hasLock {
@@ -95,10 +114,15 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ConfiguringDatabase",
 			fmt.Sprintf("Setting database configuration to `%s`", configurationString),
 		)
-		err = adminClient.ConfigureDatabase(nextConfiguration, initialConfig, cluster.Spec.Version)
+		err = adminClient.ConfigureDatabase(ctx, nextConfiguration, initialConfig, cluster.Spec.Version)
 		if err != nil {
 			return &requeue{curError: err}
 		}
+		if initialConfig {
+			r.recordAction(cluster, fmt.Sprintf("configured new database with `%s`", configurationString), "database was not configured yet")
+		} else {
+			r.recordAction(cluster, fmt.Sprintf("changed database configuration to `%s`", configurationString), "database configuration differs from the spec")
+		}
 		if initialConfig {
 			cluster.Status.Configured = true
 			err = r.updateOrApply(ctx, cluster)
@@ -109,6 +133,11 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 		}
 		logger.Info("Configured database")
 
+		err = recordDestructiveAction(ctx, r, cluster, "changing the database configuration")
+		if err != nil {
+			return &requeue{curError: err, delayedRequeue: true}
+		}
+
 		if !equality.Semantic.DeepEqual(nextConfiguration, desiredConfiguration) {
 			return &requeue{message: "Requeuing for next stage of database configuration change", delayedRequeue: true}
 		}
@@ -0,0 +1,208 @@
+/*
+ * update_database_configuration_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_database_configuration", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var driftedConfiguration fdbv1beta2.DatabaseConfiguration
+
+	getEventReasons := func() []string {
+		events := &corev1.EventList{}
+		Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+		var reasons []string
+		for _, event := range events.Items {
+			if event.InvolvedObject.UID == cluster.ObjectMeta.UID {
+				reasons = append(reasons, event.Reason)
+			}
+		}
+
+		return reasons
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+	})
+
+	JustBeforeEach(func() {
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		var err error
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(adminClient.ConfigureDatabase(context.TODO(), driftedConfiguration, false, cluster.Spec.Version)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		_, err = reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("the role counts were changed outside of the operator", func() {
+		BeforeEach(func() {
+			driftedConfiguration = cluster.DesiredDatabaseConfiguration()
+			driftedConfiguration.Logs = 5
+		})
+
+		When("the configure database mode is Always", func() {
+			It("should revert the change", func() {
+				Expect(adminClient.DatabaseConfiguration.Logs).To(Equal(cluster.DesiredDatabaseConfiguration().Logs))
+				Expect(cluster.Status.DatabaseConfigurationDrift).To(BeNil())
+			})
+		})
+
+		When("the configure database mode is IfSafe", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ConfigureDatabaseMode = fdbv1beta2.ConfigureDatabaseModeIfSafe
+			})
+
+			It("should revert the change", func() {
+				Expect(adminClient.DatabaseConfiguration.Logs).To(Equal(cluster.DesiredDatabaseConfiguration().Logs))
+				Expect(cluster.Status.DatabaseConfigurationDrift).To(BeNil())
+			})
+		})
+
+		When("the configure database mode is Never", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ConfigureDatabaseMode = fdbv1beta2.ConfigureDatabaseModeNever
+			})
+
+			It("should not revert the change", func() {
+				Expect(adminClient.DatabaseConfiguration.Logs).To(Equal(5))
+			})
+
+			It("should report the drift", func() {
+				Expect(cluster.Status.DatabaseConfigurationDrift).NotTo(BeNil())
+				Expect(cluster.Status.DatabaseConfigurationDrift.Configuration).To(ContainSubstring("logs=5"))
+				Expect(cluster.Status.DatabaseConfigurationDrift.Timestamp).NotTo(BeNil())
+				Expect(getEventReasons()).To(ContainElement("DatabaseConfigurationDrift"))
+			})
+
+			It("should mark the cluster as reconciled", func() {
+				Expect(cluster.Status.Generations.Reconciled).To(Equal(cluster.ObjectMeta.Generation))
+			})
+
+			When("an unrelated field of the spec is changed", func() {
+				var previousDrift *fdbv1beta2.DatabaseConfigurationDriftStatus
+
+				JustBeforeEach(func() {
+					previousDrift = cluster.Status.DatabaseConfigurationDrift.DeepCopy()
+					cluster.Spec.AutomationOptions.MaxConcurrentReplacements = pointer.Int(1)
+					Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+					result, err := reconcileCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Requeue).To(BeFalse())
+
+					_, err = reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should not revert the change", func() {
+					Expect(adminClient.DatabaseConfiguration.Logs).To(Equal(5))
+				})
+
+				It("should keep the previous drift", func() {
+					Expect(cluster.Status.DatabaseConfigurationDrift).To(Equal(previousDrift))
+				})
+
+				It("should only report the drift once", func() {
+					var driftEvents int
+					for _, reason := range getEventReasons() {
+						if reason == "DatabaseConfigurationDrift" {
+							driftEvents++
+						}
+					}
+
+					Expect(driftEvents).To(Equal(1))
+				})
+			})
+
+			When("the spec is changed", func() {
+				JustBeforeEach(func() {
+					cluster.Spec.DatabaseConfiguration.Resolvers = 2
+					Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+					result, err := reconcileCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Requeue).To(BeFalse())
+
+					_, err = reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should apply the spec", func() {
+					Expect(adminClient.DatabaseConfiguration.Resolvers).To(Equal(2))
+					Expect(adminClient.DatabaseConfiguration.Logs).To(Equal(cluster.DesiredDatabaseConfiguration().Logs))
+					Expect(cluster.Status.DatabaseConfigurationDrift).To(BeNil())
+				})
+			})
+		})
+	})
+
+	When("the redundancy mode was changed outside of the operator", func() {
+		BeforeEach(func() {
+			driftedConfiguration = cluster.DesiredDatabaseConfiguration()
+			driftedConfiguration.RedundancyMode = fdbv1beta2.RedundancyModeTriple
+		})
+
+		When("the configure database mode is Always", func() {
+			It("should revert the change", func() {
+				Expect(adminClient.DatabaseConfiguration.RedundancyMode).To(Equal(fdbv1beta2.RedundancyModeDouble))
+				Expect(cluster.Status.DatabaseConfigurationDrift).To(BeNil())
+			})
+		})
+
+		When("the configure database mode is IfSafe", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ConfigureDatabaseMode = fdbv1beta2.ConfigureDatabaseModeIfSafe
+			})
+
+			It("should not revert the change", func() {
+				Expect(adminClient.DatabaseConfiguration.RedundancyMode).To(Equal(fdbv1beta2.RedundancyModeTriple))
+			})
+
+			It("should report the drift", func() {
+				Expect(cluster.Status.DatabaseConfigurationDrift).NotTo(BeNil())
+				Expect(cluster.Status.DatabaseConfigurationDrift.Configuration).To(ContainSubstring("triple"))
+				Expect(cluster.Status.Generations.Reconciled).To(Equal(cluster.ObjectMeta.Generation))
+			})
+		})
+	})
+})
@@ -0,0 +1,260 @@
+/*
+ * update_external_access.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateExternalAccess provides a reconciliation step for exposing the coordinators outside of the Kubernetes cluster.
+// For every coordinator a Service of type LoadBalancer or NodePort is created and the external addresses of the
+// coordinators are stored in the cluster status. Clients also connect to the public addresses of all other processes,
+// so the external addresses of the coordinators are not published as a cluster file.
+type updateExternalAccess struct{}
+
+// reconcile runs the reconciler's work.
+func (updateExternalAccess) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateExternalAccess")
+
+	services := &corev1.ServiceList{}
+	err := r.List(ctx, services, getExternalAccessServiceListOptions(cluster)...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if !cluster.IsExternalAccessEnabled() || cluster.Status.ConnectionString == "" {
+		for idx := range services.Items {
+			logger.Info("Deleting external access service", "name", services.Items[idx].Name)
+			err = r.Delete(ctx, &services.Items[idx])
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return &requeue{curError: err}
+			}
+		}
+
+		if len(cluster.Status.ExternalCoordinators) == 0 {
+			return nil
+		}
+
+		cluster.Status.ExternalCoordinators = nil
+		err = r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return nil
+	}
+
+	connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	existingServices := make(map[fdbv1beta2.ProcessGroupID]*corev1.Service, len(services.Items))
+	for idx, service := range services.Items {
+		existingServices[fdbv1beta2.ProcessGroupID(service.Labels[fdbv1beta2.ExternalAccessLabel])] = &services.Items[idx]
+	}
+
+	externalCoordinators := make([]string, 0, len(connectionString.Coordinators))
+	coordinatorProcessGroups := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(connectionString.Coordinators))
+	var missingAddresses []string
+	for _, coordinator := range connectionString.Coordinators {
+		address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		processGroup := getProcessGroupForCoordinator(cluster, address)
+		if processGroup == nil {
+			return &requeue{message: fmt.Sprintf("could not find process group for coordinator %s", coordinator), delayedRequeue: true}
+		}
+		coordinatorProcessGroups[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+
+		service, err := ensureExternalAccessService(ctx, r, cluster, logger, existingServices[processGroup.ProcessGroupID], processGroup.ProcessGroupID, address.Port)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		externalAddress, err := getExternalAddress(ctx, r, cluster, service, processGroup, address)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if externalAddress == nil {
+			missingAddresses = append(missingAddresses, string(processGroup.ProcessGroupID))
+			continue
+		}
+
+		externalCoordinators = append(externalCoordinators, externalAddress.String())
+	}
+
+	// Remove the services of process groups that are not serving as coordinators anymore.
+	for processGroupID, service := range existingServices {
+		if _, ok := coordinatorProcessGroups[processGroupID]; ok {
+			continue
+		}
+
+		logger.Info("Deleting external access service", "name", service.Name, "processGroupID", processGroupID)
+		err = r.Delete(ctx, service)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return &requeue{curError: err}
+		}
+	}
+
+	if len(missingAddresses) > 0 {
+		return &requeue{message: fmt.Sprintf("waiting for external addresses of coordinators %v", missingAddresses), delayedRequeue: true}
+	}
+
+	if equality.Semantic.DeepEqual(cluster.Status.ExternalCoordinators, externalCoordinators) {
+		return nil
+	}
+
+	logger.Info("Updating external coordinator addresses", "externalCoordinators", externalCoordinators)
+	cluster.Status.ExternalCoordinators = externalCoordinators
+	r.recordAction(cluster, fmt.Sprintf("updated external coordinator addresses to %v", externalCoordinators), "external addresses of the coordinators changed")
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// getExternalAccessServiceListOptions returns the list options to fetch all external access services of the cluster.
+func getExternalAccessServiceListOptions(cluster *fdbv1beta2.FoundationDBCluster) []client.ListOption {
+	return []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels(cluster.GetMatchLabels()),
+		client.HasLabels{fdbv1beta2.ExternalAccessLabel},
+	}
+}
+
+// getProcessGroupForCoordinator returns the process group that is serving the coordinator with the provided address.
+// If no process group matches the address nil will be returned.
+func getProcessGroupForCoordinator(cluster *fdbv1beta2.FoundationDBCluster, address fdbv1beta2.ProcessAddress) *fdbv1beta2.ProcessGroupStatus {
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if address.IPAddress != nil {
+			for _, processGroupAddress := range processGroup.Addresses {
+				if address.IPAddress.Equal(net.ParseIP(processGroupAddress)) {
+					return processGroup
+				}
+			}
+
+			continue
+		}
+
+		_, idNum, err := podmanager.ParseProcessGroupID(processGroup.ProcessGroupID)
+		if err != nil {
+			continue
+		}
+
+		podName, _ := internal.GetProcessGroupID(cluster, processGroup.ProcessClass, idNum)
+		if address.StringAddress == internal.GetPodDNSName(cluster, processGroup.ProcessClass, podName) {
+			return processGroup
+		}
+	}
+
+	return nil
+}
+
+// ensureExternalAccessService creates the external access service for the process group or updates the existing
+// service if the port or type has changed.
+func ensureExternalAccessService(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, logger logr.Logger, existingService *corev1.Service, processGroupID fdbv1beta2.ProcessGroupID, port int) (*corev1.Service, error) {
+	service := internal.GetExternalAccessService(cluster, processGroupID, port)
+	if existingService == nil {
+		logger.Info("Creating external access service", "name", service.Name, "processGroupID", processGroupID)
+		return service, r.Create(ctx, service)
+	}
+
+	needsUpdate := existingService.Spec.Type != service.Spec.Type
+	if len(existingService.Spec.Ports) != 1 || existingService.Spec.Ports[0].Port != service.Spec.Ports[0].Port {
+		needsUpdate = true
+	}
+	if mergeLabelsInMetadata(&existingService.ObjectMeta, service.ObjectMeta) {
+		needsUpdate = true
+	}
+	if mergeAnnotations(&existingService.ObjectMeta, service.ObjectMeta) {
+		needsUpdate = true
+	}
+
+	if !needsUpdate {
+		return existingService, nil
+	}
+
+	// Keep the allocated node port if the port of the coordinator has not changed.
+	if len(existingService.Spec.Ports) == 1 && existingService.Spec.Ports[0].Port == service.Spec.Ports[0].Port {
+		service.Spec.Ports[0].NodePort = existingService.Spec.Ports[0].NodePort
+	}
+
+	existingService.Spec.Type = service.Spec.Type
+	existingService.Spec.Selector = service.Spec.Selector
+	existingService.Spec.Ports = service.Spec.Ports
+	logger.Info("Updating external access service", "name", existingService.Name, "processGroupID", processGroupID)
+	return existingService, r.Update(ctx, existingService)
+}
+
+// getExternalAddress returns the address under which the coordinator is reachable from outside of the Kubernetes
+// cluster. If the address is not yet assigned nil will be returned.
+func getExternalAddress(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, service *corev1.Service, processGroup *fdbv1beta2.ProcessGroupStatus, address fdbv1beta2.ProcessAddress) (*fdbv1beta2.ProcessAddress, error) {
+	if service.Spec.Type == corev1.ServiceTypeNodePort {
+		if len(service.Spec.Ports) == 0 || service.Spec.Ports[0].NodePort == 0 {
+			return nil, nil
+		}
+
+		pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetSinglePodListOptions(cluster, processGroup.ProcessGroupID)...)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pods) != 1 || pods[0].Status.HostIP == "" {
+			return nil, nil
+		}
+
+		externalAddress := fdbv1beta2.NewProcessAddress(nil, pods[0].Status.HostIP, int(service.Spec.Ports[0].NodePort), address.Flags)
+		return &externalAddress, nil
+	}
+
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		host := ingress.IP
+		if host == "" {
+			host = ingress.Hostname
+		}
+
+		if host == "" {
+			continue
+		}
+
+		externalAddress := fdbv1beta2.NewProcessAddress(nil, host, address.Port, address.Flags)
+		return &externalAddress, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,204 @@
+/*
+ * update_external_access_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("updateExternalAccess", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var req *requeue
+
+	getExternalAccessServices := func() []corev1.Service {
+		services := &corev1.ServiceList{}
+		Expect(k8sClient.List(context.TODO(), services, getExternalAccessServiceListOptions(cluster)...)).NotTo(HaveOccurred())
+		return services.Items
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+	})
+
+	JustBeforeEach(func() {
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+		req = updateExternalAccess{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("external access is disabled", func() {
+		It("should not create any services", func() {
+			Expect(req).To(BeNil())
+			Expect(getExternalAccessServices()).To(BeEmpty())
+			Expect(cluster.Status.ExternalCoordinators).To(BeEmpty())
+		})
+	})
+
+	When("external access is enabled with load balancers", func() {
+		BeforeEach(func() {
+			cluster.Spec.Routing.ExternalAccess = &fdbv1beta2.ExternalAccessConfig{
+				Enabled: pointer.Bool(true),
+				Annotations: map[string]string{
+					"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+				},
+			}
+		})
+
+		It("should create a service for every coordinator", func() {
+			Expect(req).To(BeNil())
+
+			connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+			Expect(err).NotTo(HaveOccurred())
+
+			services := getExternalAccessServices()
+			Expect(services).To(HaveLen(len(connectionString.Coordinators)))
+			for _, service := range services {
+				processGroupID := service.Labels[fdbv1beta2.ExternalAccessLabel]
+				Expect(service.Name).To(Equal(fmt.Sprintf("%s-external-%s", cluster.Name, processGroupID)))
+				Expect(service.Labels).NotTo(HaveKey(fdbv1beta2.FDBProcessGroupIDLabel))
+				Expect(service.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+				Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+				Expect(service.Spec.Selector).To(HaveKeyWithValue(fdbv1beta2.FDBProcessGroupIDLabel, processGroupID))
+				Expect(service.Spec.Ports).To(HaveLen(1))
+				Expect(service.Spec.Ports[0].Port).To(BeNumerically("==", 4501))
+			}
+		})
+
+		It("should set the external coordinator addresses", func() {
+			connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cluster.Status.ExternalCoordinators).To(HaveLen(len(connectionString.Coordinators)))
+			for _, coordinator := range cluster.Status.ExternalCoordinators {
+				Expect(coordinator).To(HavePrefix("172.16."))
+				Expect(coordinator).To(HaveSuffix(":4501"))
+			}
+		})
+
+		It("should not publish the external coordinator addresses as a cluster file", func() {
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: fmt.Sprintf("%s-config", cluster.Name)}, configMap)).NotTo(HaveOccurred())
+			for _, value := range configMap.Data {
+				for _, coordinator := range cluster.Status.ExternalCoordinators {
+					Expect(value).NotTo(ContainSubstring(coordinator))
+				}
+			}
+		})
+
+		When("the coordinators change", func() {
+			var previousCoordinator fdbv1beta2.ProcessGroupID
+			var newCoordinator fdbv1beta2.ProcessGroupID
+
+			JustBeforeEach(func() {
+				connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+				Expect(err).NotTo(HaveOccurred())
+
+				address, err := fdbv1beta2.ParseProcessAddress(connectionString.Coordinators[0])
+				Expect(err).NotTo(HaveOccurred())
+				previousCoordinator = getProcessGroupForCoordinator(cluster, address).ProcessGroupID
+
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage || len(processGroup.Addresses) == 0 {
+						continue
+					}
+
+					if strings.Contains(cluster.Status.ConnectionString, processGroup.Addresses[0]+":") {
+						continue
+					}
+
+					newCoordinator = processGroup.ProcessGroupID
+					connectionString.Coordinators[0] = fdbv1beta2.NewProcessAddress(nil, processGroup.Addresses[0], address.Port, address.Flags).String()
+					break
+				}
+
+				Expect(newCoordinator).NotTo(BeEmpty())
+				Expect(connectionString.GenerateNewGenerationID()).NotTo(HaveOccurred())
+				cluster.Status.ConnectionString = connectionString.String()
+				req = updateExternalAccess{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should update the services and the external coordinator addresses", func() {
+				Expect(req).To(BeNil())
+
+				processGroupIDs := make([]string, 0, 3)
+				for _, service := range getExternalAccessServices() {
+					processGroupIDs = append(processGroupIDs, service.Labels[fdbv1beta2.ExternalAccessLabel])
+				}
+				Expect(processGroupIDs).To(ContainElement(string(newCoordinator)))
+				Expect(processGroupIDs).NotTo(ContainElement(string(previousCoordinator)))
+
+				connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.Status.ExternalCoordinators).To(HaveLen(len(connectionString.Coordinators)))
+			})
+		})
+
+		When("external access gets disabled", func() {
+			JustBeforeEach(func() {
+				cluster.Spec.Routing.ExternalAccess.Enabled = pointer.Bool(false)
+				req = updateExternalAccess{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should remove the services and the external coordinator addresses", func() {
+				Expect(req).To(BeNil())
+				Expect(getExternalAccessServices()).To(BeEmpty())
+				Expect(cluster.Status.ExternalCoordinators).To(BeEmpty())
+			})
+		})
+	})
+
+	When("external access is enabled with node ports", func() {
+		BeforeEach(func() {
+			serviceType := corev1.ServiceTypeNodePort
+			cluster.Spec.Routing.ExternalAccess = &fdbv1beta2.ExternalAccessConfig{
+				Enabled:     pointer.Bool(true),
+				ServiceType: &serviceType,
+			}
+		})
+
+		It("should use the host IPs and node ports in the external coordinator addresses", func() {
+			Expect(req).To(BeNil())
+
+			nodePorts := map[string]fdbv1beta2.None{}
+			for _, service := range getExternalAccessServices() {
+				Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+				nodePorts[fmt.Sprintf(":%d", service.Spec.Ports[0].NodePort)] = fdbv1beta2.None{}
+			}
+
+			Expect(cluster.Status.ExternalCoordinators).To(HaveLen(len(nodePorts)))
+			for _, coordinator := range cluster.Status.ExternalCoordinators {
+				Expect(coordinator).To(HavePrefix("10.1."))
+				address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodePorts).To(HaveKey(fmt.Sprintf(":%d", address.Port)))
+			}
+		})
+	})
+})
@@ -30,20 +30,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// updateLabels provides a reconciliation step for updating the labels on pods.
+// updateLabels provides a reconciliation step for updating the labels on PVCs. The metadata of the Pods is updated
+// by the updatePodMetadata reconciler.
 type updateLabels struct{}
 
 // reconcile runs the reconciler's work.
 func (updateLabels) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateLabels")
-	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
-	if err != nil {
-		return &requeue{curError: err}
-	}
-	podMap := internal.CreatePodMap(cluster, pods)
-
 	pvcs := &corev1.PersistentVolumeClaimList{}
-	err = r.List(ctx, pvcs, internal.GetPodListOptions(cluster, "", "")...)
+	err := r.List(ctx, pvcs, internal.GetPodListOptions(cluster, "", "")...)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -56,24 +51,6 @@ func (updateLabels) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 			continue
 		}
 
-		pod, ok := podMap[processGroup.ProcessGroupID]
-		if ok {
-			metadata := internal.GetPodMetadata(cluster, processGroup.ProcessClass, processGroup.ProcessGroupID, "")
-			if metadata.Annotations == nil {
-				metadata.Annotations = make(map[string]string, 1)
-			}
-
-			if !metadataCorrect(metadata, &pod.ObjectMeta) {
-				err = r.PodLifecycleManager.UpdateMetadata(ctx, r, cluster, pod)
-				if err != nil {
-					return &requeue{curError: err}
-				}
-			}
-		} else {
-			logger.V(1).Info("Could not find Pod for process group ID",
-				"processGroupID", processGroup.ProcessGroupID)
-		}
-
 		// We can skip all stateless processes because they won't have a PVC attached.
 		if !processGroup.ProcessClass.IsStateful() {
 			continue
@@ -104,6 +81,9 @@ func (updateLabels) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 
 func metadataCorrect(desiredMetadata metav1.ObjectMeta, currentMetadata *metav1.ObjectMeta) bool {
 	desiredMetadata.Annotations[fdbtypes.LastSpecKey] = currentMetadata.Annotations[fdbtypes.LastSpecKey]
-	// If the annotations or labels have changed the metadata has to be updated.
-	return !mergeLabelsInMetadata(currentMetadata, desiredMetadata) && !mergeAnnotations(currentMetadata, desiredMetadata)
+	// If the annotations or labels have changed the metadata has to be updated. Both maps have to be merged, so all
+	// changes are applied with a single update.
+	labelsChanged := mergeLabelsInMetadata(currentMetadata, desiredMetadata)
+	annotationsChanged := mergeAnnotations(currentMetadata, desiredMetadata)
+	return !labelsChanged && !annotationsChanged
 }
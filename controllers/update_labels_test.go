@@ -246,5 +246,39 @@ var _ = Describe("Update labels", func() {
 				},
 			},
 		),
+		Entry("Label and annotation have wrong value",
+			testCase{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							fdbtypes.LastSpecKey: "1",
+							"controller/X":       "true",
+						},
+						Labels: map[string]string{
+							fdbtypes.FDBProcessClassLabel: "storage",
+						},
+					},
+				},
+				metadata: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						fdbtypes.LastSpecKey: "1",
+						"controller/X":       "wrong",
+					},
+					Labels: map[string]string{
+						fdbtypes.FDBProcessClassLabel: "log",
+					},
+				},
+				expected: false,
+				expectedMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						fdbtypes.LastSpecKey: "1",
+						"controller/X":       "wrong",
+					},
+					Labels: map[string]string{
+						fdbtypes.FDBProcessClassLabel: "log",
+					},
+				},
+			},
+		),
 	)
 })
@@ -40,7 +40,7 @@ type updatePodConfig struct{}
 // reconcile runs the reconciler's work.
 func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updatePodConfig")
-	configMap, err := internal.GetConfigMap(cluster)
+	configMap, err := internal.GetConfigMap(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -104,7 +104,7 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 			continue
 		}
 
-		synced, err := r.updatePodDynamicConf(curLogger, cluster, pod)
+		synced, err := r.updatePodDynamicConf(ctx, curLogger, cluster, pod)
 		if !synced {
 			allSynced = false
 			if err != nil {
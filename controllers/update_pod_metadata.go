@@ -0,0 +1,121 @@
+/*
+ * update_pod_metadata.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// updatePodMetadata provides a reconciliation step for updating the labels, annotations and owner references of
+// existing Pods. The metadata is not part of the spec hash, so those changes are applied to the running Pods through
+// the PodLifecycleManager without recreating them.
+type updatePodMetadata struct{}
+
+// reconcile runs the reconciler's work.
+func (updatePodMetadata) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updatePodMetadata")
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	podMap := internal.CreatePodMap(cluster, pods)
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			logger.V(1).Info("Ignore process group marked for removal",
+				"processGroupID", processGroup.ProcessGroupID)
+			continue
+		}
+
+		pod, ok := podMap[processGroup.ProcessGroupID]
+		if !ok || pod == nil {
+			logger.V(1).Info("Could not find Pod for process group ID",
+				"processGroupID", processGroup.ProcessGroupID)
+			continue
+		}
+
+		// Pods that are terminating will be recreated with the current metadata.
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+
+		metadata := internal.GetPodMetadata(cluster, processGroup.ProcessClass, processGroup.ProcessGroupID, "")
+		metadataUpdated := !metadataCorrect(metadata, &pod.ObjectMeta)
+		ownerReferenceUpdated := repairOwnerReference(cluster, &pod.ObjectMeta)
+		if !metadataUpdated && !ownerReferenceUpdated {
+			continue
+		}
+
+		logger.Info("Updating Pod metadata",
+			"processGroupID", processGroup.ProcessGroupID,
+			"metadataUpdated", metadataUpdated,
+			"ownerReferenceUpdated", ownerReferenceUpdated)
+		err = r.PodLifecycleManager.UpdateMetadata(ctx, r, cluster, pod)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
+
+// repairOwnerReference makes sure that the object references the cluster as its owner. References to a previous
+// cluster with the same name but a different UID will be replaced. Objects that are controlled by another resource are
+// not changed. This will return whether the owner references have changed.
+func repairOwnerReference(cluster *fdbv1beta2.FoundationDBCluster, metadata *metav1.ObjectMeta) bool {
+	// Without the UID of the cluster no valid reference can be created.
+	if cluster.UID == "" {
+		return false
+	}
+
+	ownerReferences := make([]metav1.OwnerReference, 0, len(metadata.OwnerReferences)+1)
+	for _, reference := range metadata.OwnerReferences {
+		if reference.UID == cluster.UID {
+			return false
+		}
+
+		if reference.Kind == cluster.Kind && reference.Name == cluster.Name {
+			continue
+		}
+
+		if pointer.BoolDeref(reference.Controller, false) {
+			return false
+		}
+
+		ownerReferences = append(ownerReferences, reference)
+	}
+
+	metadata.OwnerReferences = append(ownerReferences, internal.BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)...)
+
+	return true
+}
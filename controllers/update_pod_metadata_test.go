@@ -0,0 +1,162 @@
+/*
+ * update_pod_metadata_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("updatePodMetadata", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var req *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		req = updatePodMetadata{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the metadata of the Pods is up to date", func() {
+		It("should not requeue", func() {
+			Expect(req).To(BeNil())
+		})
+	})
+
+	When("a label is added to the Pod template", func() {
+		BeforeEach(func() {
+			cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{fdbv1beta2.ProcessClassGeneral: {PodTemplate: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"monitoring": "enabled",
+					},
+					Annotations: map[string]string{
+						"monitoring/port": "8080",
+					},
+				},
+			}}}
+		})
+
+		It("should patch the metadata of the Pods", func() {
+			Expect(req).To(BeNil())
+
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)).NotTo(HaveOccurred())
+			Expect(pods.Items).NotTo(BeEmpty())
+			for _, pod := range pods.Items {
+				Expect(pod.Labels).To(HaveKeyWithValue("monitoring", "enabled"))
+				Expect(pod.Annotations).To(HaveKeyWithValue("monitoring/port", "8080"))
+				Expect(pod.Annotations).To(HaveKey(fdbv1beta2.LastSpecKey))
+			}
+		})
+
+		When("the cluster is reconciled", func() {
+			BeforeEach(func() {
+				pods := &corev1.PodList{}
+				Expect(k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)).NotTo(HaveOccurred())
+				for _, pod := range pods.Items {
+					pod.Annotations["test/original-pod"] = "true"
+					Expect(k8sClient.Update(context.TODO(), &pod)).NotTo(HaveOccurred())
+				}
+
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+				result, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+			})
+
+			It("should not recreate the Pods", func() {
+				pods := &corev1.PodList{}
+				Expect(k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)).NotTo(HaveOccurred())
+				Expect(pods.Items).NotTo(BeEmpty())
+				for _, pod := range pods.Items {
+					Expect(pod.Labels).To(HaveKeyWithValue("monitoring", "enabled"))
+					Expect(pod.Annotations).To(HaveKeyWithValue("test/original-pod", "true"))
+				}
+			})
+		})
+	})
+
+	When("the owner reference of a Pod is missing", func() {
+		BeforeEach(func() {
+			cluster.UID = "cluster-uid"
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: "operator-test-1-storage-1"}, pod)).NotTo(HaveOccurred())
+			pod.OwnerReferences = nil
+			Expect(k8sClient.Update(context.TODO(), pod)).NotTo(HaveOccurred())
+		})
+
+		It("should add the owner reference", func() {
+			Expect(req).To(BeNil())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: "operator-test-1-storage-1"}, pod)).NotTo(HaveOccurred())
+			Expect(pod.OwnerReferences).To(HaveLen(1))
+			Expect(pod.OwnerReferences[0].UID).To(Equal(cluster.UID))
+			Expect(pod.OwnerReferences[0].Name).To(Equal(cluster.Name))
+			Expect(pod.OwnerReferences[0].Controller).To(Equal(pointer.Bool(true)))
+		})
+	})
+
+	DescribeTable("repairing the owner reference",
+		func(ownerReferences []metav1.OwnerReference, expectedChange bool, expected []metav1.OwnerReference) {
+			cluster.UID = "cluster-uid"
+			metadata := &metav1.ObjectMeta{OwnerReferences: ownerReferences}
+			Expect(repairOwnerReference(cluster, metadata)).To(Equal(expectedChange))
+			Expect(metadata.OwnerReferences).To(Equal(expected))
+		},
+		Entry("the reference is correct",
+			[]metav1.OwnerReference{{Kind: "FoundationDBCluster", Name: "operator-test-1", UID: "cluster-uid", Controller: pointer.Bool(true)}},
+			false,
+			[]metav1.OwnerReference{{Kind: "FoundationDBCluster", Name: "operator-test-1", UID: "cluster-uid", Controller: pointer.Bool(true)}},
+		),
+		Entry("the reference points to a previous cluster",
+			[]metav1.OwnerReference{{Kind: "FoundationDBCluster", Name: "operator-test-1", UID: "old-uid", Controller: pointer.Bool(true)}},
+			true,
+			[]metav1.OwnerReference{{APIVersion: "apps.foundationdb.org/v1beta2", Kind: "FoundationDBCluster", Name: "operator-test-1", UID: "cluster-uid", Controller: pointer.Bool(true)}},
+		),
+		Entry("the Pod has another owner",
+			[]metav1.OwnerReference{{Kind: "ConfigMap", Name: "test", UID: "other-uid"}},
+			true,
+			[]metav1.OwnerReference{
+				{Kind: "ConfigMap", Name: "test", UID: "other-uid"},
+				{APIVersion: "apps.foundationdb.org/v1beta2", Kind: "FoundationDBCluster", Name: "operator-test-1", UID: "cluster-uid", Controller: pointer.Bool(true)},
+			},
+		),
+		Entry("the Pod is controlled by another resource",
+			[]metav1.OwnerReference{{Kind: "StatefulSet", Name: "test", UID: "other-uid", Controller: pointer.Bool(true)}},
+			false,
+			[]metav1.OwnerReference{{Kind: "StatefulSet", Name: "test", UID: "other-uid", Controller: pointer.Bool(true)}},
+		),
+	)
+})
@@ -23,6 +23,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
@@ -31,6 +32,7 @@ import (
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,18 +49,65 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 		return &requeue{curError: err, delayedRequeue: true}
 	}
 
-	updates, err := getPodsToUpdate(logger, r, cluster, internal.CreatePodMap(cluster, pods))
+	updates, err := getPodsToUpdate(ctx, logger, r, cluster, internal.CreatePodMap(cluster, pods))
 	if err != nil {
 		return &requeue{curError: err, delay: podSchedulingDelayDuration, delayedRequeue: true}
 	}
 
+	deletionMode := r.PodLifecycleManager.GetDeletionMode(cluster)
+	resourceUpdates, err := getResourceUpdates(logger, cluster, updates)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	// Resource only changes are rolled out first, one process class at a time and one fault domain at a time.
+	inPlaceResize := len(resourceUpdates) > 0 && cluster.UseInPlacePodResize()
+	if len(resourceUpdates) > 0 {
+		updates = resourceUpdates
+		if deletionMode == fdbv1beta2.PodUpdateModeAll {
+			deletionMode = fdbv1beta2.PodUpdateModeZone
+		}
+	}
+
+	// Changes of the sidecar TLS setting are rolled out next, one fault domain at a time. The operator selects the
+	// protocol for every Pod based on the current arguments of its sidecar, so it can talk to the updated and the
+	// remaining Pods during the transition.
+	if len(resourceUpdates) == 0 {
+		sidecarTLSUpdates, err := getSidecarTLSUpdates(cluster, updates)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if len(sidecarTLSUpdates) > 0 {
+			if cluster.Spec.SidecarContainer.EnableTLS && !internal.HasSidecarTLSConfiguration() {
+				r.Recorder.Event(cluster, corev1.EventTypeWarning, "MissingSidecarTLSConfiguration",
+					"Spec requires enabling TLS for the sidecar, but the operator has no TLS configuration to talk to the sidecar")
+				return &requeue{message: "Operator is missing the TLS configuration to talk to sidecars with TLS enabled", delayedRequeue: true}
+			}
+
+			updates = sidecarTLSUpdates
+			if deletionMode == fdbv1beta2.PodUpdateModeAll {
+				deletionMode = fdbv1beta2.PodUpdateModeZone
+			}
+		}
+	}
+
 	if len(updates) > 0 {
+		// With the Replacement strategy only resource only changes that are resized in place and scheduling only
+		// changes are rolled out by this step, all other changes are rolled out by replacing the process groups.
 		if cluster.Spec.AutomationOptions.PodUpdateStrategy == fdbv1beta2.PodUpdateStrategyReplacement {
-			logger.Info("Requeuing reconciliation to replace pods")
-			return &requeue{message: "Requeueing reconciliation to replace pods"}
+			updates, err = getUpdatesWithoutReplacement(cluster, updates)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			if len(updates) == 0 {
+				logger.Info("Requeuing reconciliation to replace pods")
+				return &requeue{message: "Requeueing reconciliation to replace pods"}
+			}
 		}
 
-		if r.PodLifecycleManager.GetDeletionMode(cluster) == fdbv1beta2.PodUpdateModeNone {
+		if deletionMode == fdbv1beta2.PodUpdateModeNone {
 			r.Recorder.Event(cluster, corev1.EventTypeNormal,
 				"NeedsPodsDeletion", "Spec require deleting some pods, but deleting pods is disabled")
 			cluster.Status.Generations.NeedsPodDeletion = cluster.ObjectMeta.Generation
@@ -80,11 +129,66 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 	}
 	defer adminClient.Close()
 
-	return deletePodsForUpdates(ctx, r, cluster, adminClient, updates, logger)
+	if inPlaceResize {
+		return resizePodsForUpdates(ctx, r, cluster, adminClient, updates, deletionMode, logger)
+	}
+
+	return deletePodsForUpdates(ctx, r, cluster, adminClient, updates, deletionMode, logger)
+}
+
+// canUpdateWithoutReplacement returns true if the Pod of a process group that requires a replacement can be updated
+// without replacing the process group, either by resizing the Pod in place or by recreating the Pod for scheduling only
+// changes.
+func canUpdateWithoutReplacement(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, idNum int, pod *corev1.Pod) (bool, error) {
+	if cluster.UseInPlacePodResize() {
+		resourceOnly, err := internal.IsResourceOnlyUpdate(cluster, processClass, idNum, pod)
+		if err != nil || resourceOnly {
+			return resourceOnly, err
+		}
+	}
+
+	if cluster.UseRecreatePodsForSchedulingChanges() {
+		return internal.IsSchedulingOnlyUpdate(cluster, processClass, idNum, pod)
+	}
+
+	return false, nil
+}
+
+// getUpdatesWithoutReplacement returns the updates that can be rolled out without replacing the process group, either
+// by resizing the Pod in place or by recreating the Pod for scheduling only changes.
+func getUpdatesWithoutReplacement(cluster *fdbv1beta2.FoundationDBCluster, updates map[string][]*corev1.Pod) (map[string][]*corev1.Pod, error) {
+	updatesWithoutReplacement := make(map[string][]*corev1.Pod)
+
+	for zone, pods := range updates {
+		for _, pod := range pods {
+			processClass, err := podmanager.GetProcessClass(cluster, pod)
+			if err != nil {
+				return nil, err
+			}
+
+			_, idNum, err := podmanager.ParseProcessGroupID(internal.GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta))
+			if err != nil {
+				return nil, err
+			}
+
+			updateWithoutReplacement, err := canUpdateWithoutReplacement(cluster, processClass, idNum, pod)
+			if err != nil {
+				return nil, err
+			}
+
+			if !updateWithoutReplacement {
+				continue
+			}
+
+			updatesWithoutReplacement[zone] = append(updatesWithoutReplacement[zone], pod)
+		}
+	}
+
+	return updatesWithoutReplacement, nil
 }
 
 // getPodsToUpdate returns a map of Zone to Pods mapping. The map has the fault domain as key and all Pods in that fault domain will be present as a slice of *corev1.Pod.
-func getPodsToUpdate(logger logr.Logger, reconciler *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, podMap map[fdbv1beta2.ProcessGroupID]*corev1.Pod) (map[string][]*corev1.Pod, error) {
+func getPodsToUpdate(ctx context.Context, logger logr.Logger, reconciler *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, podMap map[fdbv1beta2.ProcessGroupID]*corev1.Pod) (map[string][]*corev1.Pod, error) {
 	updates := make(map[string][]*corev1.Pod)
 
 	for _, processGroup := range cluster.Status.ProcessGroups {
@@ -100,7 +204,10 @@ func getPodsToUpdate(logger logr.Logger, reconciler *FoundationDBClusterReconcil
 			continue
 		}
 
-		if cluster.NeedsReplacement(processGroup) {
+		// Process groups that require a replacement can still be resized in place or recreated for scheduling only
+		// changes, this is checked below with canUpdateWithoutReplacement.
+		needsReplacement := cluster.NeedsReplacement(processGroup)
+		if needsReplacement && !cluster.UseInPlacePodResize() && !cluster.UseRecreatePodsForSchedulingChanges() {
 			logger.V(1).Info("Skip process group for deletion, requires a replacement",
 				"processGroupID", processGroup.ProcessGroupID)
 			continue
@@ -146,32 +253,27 @@ func getPodsToUpdate(logger logr.Logger, reconciler *FoundationDBClusterReconcil
 			continue
 		}
 
+		if needsReplacement {
+			updateWithoutReplacement, err := canUpdateWithoutReplacement(cluster, processClass, idNum, pod)
+			if err != nil || !updateWithoutReplacement {
+				logger.V(1).Info("Skip process group for deletion, requires a replacement",
+					"processGroupID", processGroup.ProcessGroupID)
+				continue
+			}
+		}
+
 		logger.Info("Update Pod",
 			"processGroupID", processGroup.ProcessGroupID,
 			"reason", fmt.Sprintf("specHash has changed from %s to %s", specHash, pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]))
 
-		podClient, message := reconciler.getPodClient(cluster, pod)
-		if podClient == nil {
-			logger.Info("Skipping Pod due to missing Pod client information",
-				"processGroupID", processGroup.ProcessGroupID,
-				"message", message)
-			continue
-		}
-
-		substitutions, err := podClient.GetVariableSubstitutions()
+		zone, err := getZoneForUpdate(ctx, logger, reconciler, cluster, processClass, idNum, pod)
 		if err != nil {
-			logger.Info("Skipping Pod due to missing variable substitutions",
-				"processGroupID", processGroup.ProcessGroupID)
-			continue
-		}
-
-		if substitutions == nil {
 			logger.Info("Skipping Pod due to missing locality information",
-				"processGroupID", processGroup.ProcessGroupID)
+				"processGroupID", processGroup.ProcessGroupID,
+				"error", err.Error())
 			continue
 		}
 
-		zone := substitutions["FDB_ZONE_ID"]
 		if reconciler.InSimulation {
 			zone = "simulation"
 		}
@@ -185,6 +287,40 @@ func getPodsToUpdate(logger logr.Logger, reconciler *FoundationDBClusterReconcil
 	return updates, nil
 }
 
+// getZoneForUpdate returns the zone of the Pod from the variable substitutions of its sidecar. If the operator can't
+// talk to the sidecar of a Pod that changes the TLS setting of its sidecar, e.g. because the operator has no TLS
+// configuration anymore after TLS was disabled for the sidecar, the zone is derived from the Pod. Otherwise the Pod
+// could never be updated.
+func getZoneForUpdate(ctx context.Context, logger logr.Logger, reconciler *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, idNum int, pod *corev1.Pod) (string, error) {
+	podClient, message := reconciler.getPodClient(cluster, pod)
+	if podClient == nil {
+		sidecarTLSUpdate, err := internal.IsSidecarTLSUpdate(cluster, processClass, idNum, pod)
+		if err != nil {
+			return "", err
+		}
+
+		if !sidecarTLSUpdate {
+			return "", fmt.Errorf("missing Pod client information: %s", message)
+		}
+
+		logger.Info("Using zone from Pod due to missing Pod client information during sidecar TLS update",
+			"pod", pod.Name,
+			"message", message)
+		return internal.GetZoneIDFromPod(cluster, pod)
+	}
+
+	substitutions, err := podClient.GetVariableSubstitutions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if substitutions == nil {
+		return "", fmt.Errorf("missing variable substitutions")
+	}
+
+	return substitutions["FDB_ZONE_ID"], nil
+}
+
 func shouldRequeueDueToTerminatingPod(pod *corev1.Pod, cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) bool {
 	return pod.DeletionTimestamp != nil &&
 		pod.DeletionTimestamp.Add(time.Duration(cluster.GetIgnoreTerminatingPodsSeconds())*time.Second).After(time.Now()) &&
@@ -228,9 +364,188 @@ func getPodsToDelete(deletionMode fdbv1beta2.PodUpdateMode, updates map[string][
 	return "", nil, fmt.Errorf("unknown deletion mode: \"%s\"", deletionMode)
 }
 
+// getResourceUpdates returns the Pods that only require a change of the resource requirements of their containers,
+// limited to a single process class. Process classes that are not part of the transaction system are updated first,
+// the remaining process classes are updated in alphabetical order. If no Pod only requires a resource change, an
+// empty map will be returned.
+func getResourceUpdates(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, updates map[string][]*corev1.Pod) (map[string][]*corev1.Pod, error) {
+	resourceUpdates := make(map[fdbv1beta2.ProcessClass]map[string][]*corev1.Pod)
+
+	for zone, pods := range updates {
+		for _, pod := range pods {
+			processClass, err := podmanager.GetProcessClass(cluster, pod)
+			if err != nil {
+				return nil, err
+			}
+
+			_, idNum, err := podmanager.ParseProcessGroupID(internal.GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta))
+			if err != nil {
+				return nil, err
+			}
+
+			resourceOnly, err := internal.IsResourceOnlyUpdate(cluster, processClass, idNum, pod)
+			if err != nil {
+				return nil, err
+			}
+
+			if !resourceOnly {
+				continue
+			}
+
+			if resourceUpdates[processClass] == nil {
+				resourceUpdates[processClass] = make(map[string][]*corev1.Pod)
+			}
+			resourceUpdates[processClass][zone] = append(resourceUpdates[processClass][zone], pod)
+		}
+	}
+
+	if len(resourceUpdates) == 0 {
+		return nil, nil
+	}
+
+	processClasses := make([]fdbv1beta2.ProcessClass, 0, len(resourceUpdates))
+	for processClass := range resourceUpdates {
+		processClasses = append(processClasses, processClass)
+	}
+
+	sort.Slice(processClasses, func(i, j int) bool {
+		if processClasses[i].IsTransaction() != processClasses[j].IsTransaction() {
+			return !processClasses[i].IsTransaction()
+		}
+
+		return processClasses[i] < processClasses[j]
+	})
+
+	logger.V(1).Info("Updating resources of process class", "processClass", processClasses[0])
+	return resourceUpdates[processClasses[0]], nil
+}
+
+// getSidecarTLSUpdates returns the Pods that change the TLS setting of their sidecar. If no Pod changes the TLS
+// setting of its sidecar, an empty map will be returned.
+func getSidecarTLSUpdates(cluster *fdbv1beta2.FoundationDBCluster, updates map[string][]*corev1.Pod) (map[string][]*corev1.Pod, error) {
+	sidecarTLSUpdates := make(map[string][]*corev1.Pod)
+
+	for zone, pods := range updates {
+		for _, pod := range pods {
+			processClass, err := podmanager.GetProcessClass(cluster, pod)
+			if err != nil {
+				return nil, err
+			}
+
+			_, idNum, err := podmanager.ParseProcessGroupID(internal.GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta))
+			if err != nil {
+				return nil, err
+			}
+
+			sidecarTLSUpdate, err := internal.IsSidecarTLSUpdate(cluster, processClass, idNum, pod)
+			if err != nil {
+				return nil, err
+			}
+
+			if !sidecarTLSUpdate {
+				continue
+			}
+
+			sidecarTLSUpdates[zone] = append(sidecarTLSUpdates[zone], pod)
+		}
+	}
+
+	if len(sidecarTLSUpdates) == 0 {
+		return nil, nil
+	}
+
+	return sidecarTLSUpdates, nil
+}
+
+// resizePodsForUpdates will resize the Pods of a single fault domain in place. If the resize is rejected by the
+// Kubernetes API, the Pods will be recreated instead.
+func resizePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient, updates map[string][]*corev1.Pod, deletionMode fdbv1beta2.PodUpdateMode, logger logr.Logger) *requeue {
+	zone, resizes, err := getPodsToDelete(deletionMode, updates)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	ready, err := r.PodLifecycleManager.CanDeletePods(logr.NewContext(ctx, logger), adminClient, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	if !ready {
+		return &requeue{message: "Reconciliation requires resizing pods, but resizing is currently not safe", delay: podSchedulingDelayDuration}
+	}
+
+	hasLock, err := r.takeLock(cluster, "resizing pods")
+	if !hasLock {
+		return &requeue{curError: err}
+	}
+
+	logger.Info("Resizing pods", "zone", zone, "count", len(resizes))
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ResizingPods", fmt.Sprintf("Resizing pods in zone %s", zone))
+
+	var rejected []*corev1.Pod
+	for _, pod := range resizes {
+		err = resizePod(logr.NewContext(ctx, logger), r, cluster, pod)
+		if err == nil {
+			r.recordAction(cluster, fmt.Sprintf("resized Pod %s in place", pod.Name), "resource requirements have changed")
+			continue
+		}
+
+		if !k8serrors.IsInvalid(err) && !k8serrors.IsForbidden(err) {
+			return &requeue{curError: err}
+		}
+
+		logger.Info("Resizing pod in place was rejected, pod will be recreated", "pod", pod.Name, "error", err.Error())
+		rejected = append(rejected, pod)
+	}
+
+	if len(rejected) > 0 {
+		return deletePodsForUpdates(ctx, r, cluster, adminClient, map[string][]*corev1.Pod{zone: rejected}, fdbv1beta2.PodUpdateModeZone, logger)
+	}
+
+	return &requeue{message: "Pods need to be resized", delayedRequeue: true}
+}
+
+// resizePod updates the resource requirements of the Pod's containers to the desired spec.
+func resizePod(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) error {
+	processClass, err := podmanager.GetProcessClass(cluster, pod)
+	if err != nil {
+		return err
+	}
+
+	_, idNum, err := podmanager.ParseProcessGroupID(internal.GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta))
+	if err != nil {
+		return err
+	}
+
+	spec, err := internal.GetPodSpec(cluster, processClass, idNum)
+	if err != nil {
+		return err
+	}
+
+	specHash, err := internal.GetPodSpecHash(cluster, processClass, idNum, spec)
+	if err != nil {
+		return err
+	}
+
+	resizedPod := pod.DeepCopy()
+	for _, container := range spec.Containers {
+		currentContainer := internal.GetContainer(resizedPod.Spec.Containers, container.Name)
+		if currentContainer == nil {
+			return fmt.Errorf("could not find container %s in pod %s", container.Name, pod.Name)
+		}
+
+		currentContainer.Resources = container.Resources
+	}
+
+	if resizedPod.ObjectMeta.Annotations == nil {
+		resizedPod.ObjectMeta.Annotations = make(map[string]string)
+	}
+	resizedPod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey] = specHash
+
+	return r.PodLifecycleManager.UpdateResources(ctx, r, cluster, resizedPod)
+}
+
 // deletePodsForUpdates will delete Pods with the specified deletion mode
-func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient, updates map[string][]*corev1.Pod, logger logr.Logger) *requeue {
-	deletionMode := r.PodLifecycleManager.GetDeletionMode(cluster)
+func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient, updates map[string][]*corev1.Pod, deletionMode fdbv1beta2.PodUpdateMode, logger logr.Logger) *requeue {
 	zone, deletions, err := getPodsToDelete(deletionMode, updates)
 	if err != nil {
 		return &requeue{curError: err}
@@ -244,6 +559,38 @@ func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler,
 		return &requeue{message: "Reconciliation requires deleting pods, but deletion is currently not safe", delay: podSchedulingDelayDuration}
 	}
 
+	if req := checkSettleTime(logger, cluster, nil, "deleting pods"); req != nil {
+		return req
+	}
+
+	if req := checkRestoreInProgress(ctx, logger, r, cluster, "deleting pods"); req != nil {
+		return req
+	}
+
+	if cluster.IsRecoveryFreezeEnabled() || cluster.IsBounceScheduleEnabled() {
+		status, err := adminClient.GetStatus(ctx)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if req := checkRecoveryFreeze(logger, cluster, status, "deleting pods"); req != nil {
+			return req
+		}
+
+		if req := checkBounceSchedule(logger, cluster, status, "deleting pods", time.Now()); req != nil {
+			return req
+		}
+	}
+
+	if req := checkActionBudget(logger, r, cluster, "deleting pods"); req != nil {
+		return req
+	}
+
+	if remaining := getRemainingActionBudget(r, cluster); len(deletions) > remaining {
+		logger.Info("Limiting pod deletions to the remaining action budget", "count", len(deletions), "remaining", remaining)
+		deletions = deletions[:remaining]
+	}
+
 	// Only lock the cluster if we are not running in the delete "All" mode.
 	// Otherwise, we want to delete all Pods and don't require a lock to sync with other clusters.
 	if deletionMode != fdbv1beta2.PodUpdateModeAll {
@@ -269,10 +616,11 @@ func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler,
 		if err != nil {
 			return &requeue{curError: err}
 		}
-		err = adminClient.SetMaintenanceZone(zone, cluster.GetMaintenaceModeTimeoutSeconds())
+		err = adminClient.SetMaintenanceZone(ctx, zone, cluster.GetMaintenaceModeTimeoutSeconds())
 		if err != nil {
 			return &requeue{curError: err}
 		}
+		r.recordAction(cluster, fmt.Sprintf("set maintenance zone %s", zone), "Pods in the zone are recreated")
 	}
 
 	logger.Info("Deleting pods", "zone", zone, "count", len(deletions), "deletionMode", string(cluster.Spec.AutomationOptions.DeletionMode))
@@ -282,6 +630,27 @@ func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler,
 	if err != nil {
 		return &requeue{curError: err}
 	}
+	r.recordAction(cluster, fmt.Sprintf("recreated Pods %v in zone %s", getPodNames(deletions), zone), "Pod specs have changed")
+
+	err = consumeActionBudget(ctx, r, cluster, len(deletions))
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	err = recordDestructiveAction(ctx, r, cluster, "deleting pods")
+	if err != nil {
+		return &requeue{curError: err}
+	}
 
 	return &requeue{message: "Pods need to be recreated", delayedRequeue: true}
 }
+
+// getPodNames returns the names of the provided Pods.
+func getPodNames(pods []*corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+
+	return names
+}
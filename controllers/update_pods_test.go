@@ -34,6 +34,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -218,7 +219,7 @@ var _ = Describe("update_pods", func() {
 			pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
 			Expect(err).NotTo(HaveOccurred())
 
-			updates, err = getPodsToUpdate(log, clusterReconciler, cluster, internal.CreatePodMap(cluster, pods))
+			updates, err = getPodsToUpdate(context.TODO(), log, clusterReconciler, cluster, internal.CreatePodMap(cluster, pods))
 			if !expectedError {
 				Expect(err).NotTo(HaveOccurred())
 			} else {
@@ -247,4 +248,333 @@ var _ = Describe("update_pods", func() {
 			})
 		})
 	})
+
+	When("only the scheduling constraints of the processes have changed", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var req *requeue
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+			Expect(k8sClient.Get(context.TODO(), ctrlClient.ObjectKeyFromObject(cluster), cluster)).NotTo(HaveOccurred())
+
+			generalSettings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			generalSettings.PodTemplate = generalSettings.PodTemplate.DeepCopy()
+			generalSettings.PodTemplate.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "fdb"}}
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = generalSettings
+		})
+
+		JustBeforeEach(func() {
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			req = updatePods{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		When("recreating Pods for scheduling changes is disabled", func() {
+			It("should only recreate the storage Pods", func() {
+				Expect(req).NotTo(BeNil())
+				Expect(req.message).To(Equal("Pods need to be recreated"))
+
+				pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pods).NotTo(BeEmpty())
+				for _, pod := range pods {
+					Expect(internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta)).NotTo(Equal(fdbv1beta2.ProcessClassStorage))
+				}
+			})
+		})
+
+		When("recreating Pods for scheduling changes is enabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.RecreatePodsForSchedulingChanges = pointer.Bool(true)
+			})
+
+			It("should recreate all Pods without replacing the process groups", func() {
+				Expect(req).NotTo(BeNil())
+				Expect(req.message).To(Equal("Pods need to be recreated"))
+
+				pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pods).To(BeEmpty())
+
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					Expect(processGroup.IsMarkedForRemoval()).To(BeFalse())
+				}
+			})
+
+			When("the bounce schedule defers the deletions", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.BounceScheduleOptions.Windows = []fdbv1beta2.BounceWindow{
+						{Start: time.Now().UTC().Add(2 * time.Hour).Format("15:04"), DurationMinutes: 30},
+					}
+				})
+
+				It("should not delete any Pods", func() {
+					Expect(req).NotTo(BeNil())
+					Expect(req.delayedRequeue).To(BeTrue())
+					Expect(req.message).To(Equal("Deferring deleting pods until the load is low or until the next bounce window"))
+
+					pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pods).To(HaveLen(len(cluster.Status.ProcessGroups)))
+				})
+			})
+
+			When("the Replacement strategy is used", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.PodUpdateStrategy = fdbv1beta2.PodUpdateStrategyReplacement
+				})
+
+				It("should recreate all Pods without replacing the process groups", func() {
+					Expect(req).NotTo(BeNil())
+					Expect(req.message).To(Equal("Pods need to be recreated"))
+
+					pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pods).To(BeEmpty())
+				})
+
+				When("the change is not only a scheduling change", func() {
+					BeforeEach(func() {
+						generalSettings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+						generalSettings.PodTemplate.Spec.Containers = append(generalSettings.PodTemplate.Spec.Containers, corev1.Container{
+							Name: fdbv1beta2.MainContainerName,
+							Env:  []corev1.EnvVar{{Name: "TEST", Value: "test"}},
+						})
+						cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = generalSettings
+					})
+
+					It("should not recreate the Pods", func() {
+						Expect(req).To(BeNil())
+
+						pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(pods).To(HaveLen(len(cluster.Status.ProcessGroups)))
+					})
+				})
+			})
+		})
+	})
+
+	When("only the resource requirements of the processes have changed", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var originalPods map[string]*corev1.Pod
+		var req *requeue
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+			Expect(k8sClient.Get(context.TODO(), ctrlClient.ObjectKeyFromObject(cluster), cluster)).NotTo(HaveOccurred())
+
+			pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+			Expect(err).NotTo(HaveOccurred())
+			originalPods = make(map[string]*corev1.Pod, len(pods))
+			for _, pod := range pods {
+				originalPods[pod.Name] = pod
+			}
+
+			generalSettings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			generalSettings.PodTemplate = generalSettings.PodTemplate.DeepCopy()
+			for idx, container := range generalSettings.PodTemplate.Spec.Containers {
+				if container.Name != fdbv1beta2.MainContainerName {
+					continue
+				}
+
+				generalSettings.PodTemplate.Spec.Containers[idx].Resources = corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("2"),
+					},
+				}
+			}
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = generalSettings
+		})
+
+		JustBeforeEach(func() {
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			req = updatePods{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		When("pod deletion is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DeletionMode = fdbv1beta2.PodUpdateModeNone
+			})
+
+			It("should only plan the update of the storage processes", func() {
+				Expect(req).NotTo(BeNil())
+				Expect(req.message).To(Equal("Pod deletion is disabled"))
+
+				pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+
+				updates, err := getPodsToUpdate(context.TODO(), log, clusterReconciler, cluster, internal.CreatePodMap(cluster, pods))
+				Expect(err).NotTo(HaveOccurred())
+
+				resourceUpdates, err := getResourceUpdates(log, cluster, updates)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resourceUpdates).To(HaveLen(1))
+				for _, zonePods := range resourceUpdates {
+					for _, pod := range zonePods {
+						Expect(internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta)).To(Equal(fdbv1beta2.ProcessClassStorage))
+					}
+				}
+			})
+		})
+
+		When("in-place resizing is disabled", func() {
+			It("should recreate the storage Pods", func() {
+				Expect(req).NotTo(BeNil())
+				Expect(req.message).To(Equal("Pods need to be recreated"))
+
+				pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+				for _, pod := range pods {
+					Expect(internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta)).NotTo(Equal(fdbv1beta2.ProcessClassStorage))
+				}
+			})
+
+			When("the Replacement strategy is used and recreating Pods for scheduling changes is enabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.PodUpdateStrategy = fdbv1beta2.PodUpdateStrategyReplacement
+					cluster.Spec.AutomationOptions.RecreatePodsForSchedulingChanges = pointer.Bool(true)
+				})
+
+				It("should not recreate any Pods", func() {
+					Expect(req).To(BeNil())
+
+					pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pods).To(HaveLen(len(originalPods)))
+				})
+			})
+		})
+
+		When("in-place resizing is enabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.InPlacePodResize = pointer.Bool(true)
+			})
+
+			It("should resize the storage Pods in place", func() {
+				Expect(req).NotTo(BeNil())
+				Expect(req.message).To(Equal("Pods need to be resized"))
+
+				pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pods).To(HaveLen(len(originalPods)))
+
+				for _, pod := range pods {
+					originalPod, ok := originalPods[pod.Name]
+					Expect(ok).To(BeTrue())
+					Expect(pod.UID).To(Equal(originalPod.UID))
+
+					cpuRequest := pod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]
+					if internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta) != fdbv1beta2.ProcessClassStorage {
+						Expect(pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]).To(Equal(originalPod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]))
+						Expect(cpuRequest.String()).NotTo(Equal("2"))
+						continue
+					}
+
+					Expect(cpuRequest.String()).To(Equal("2"))
+					Expect(pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]).NotTo(Equal(originalPod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]))
+
+					_, idNum, err := internal.ParseProcessGroupID(internal.GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta))
+					Expect(err).NotTo(HaveOccurred())
+					updated, err := internal.IsResourceOnlyUpdate(cluster, fdbv1beta2.ProcessClassStorage, idNum, pod)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(updated).To(BeFalse())
+				}
+			})
+		})
+	})
+
+	When("the TLS setting of the sidecar has changed", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var originalPods []*corev1.Pod
+		var req *requeue
+
+		getEvents := func() []corev1.Event {
+			events := &corev1.EventList{}
+			Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+			var clusterEvents []corev1.Event
+			for _, event := range events.Items {
+				if event.InvolvedObject.UID == cluster.ObjectMeta.UID {
+					clusterEvents = append(clusterEvents, event)
+				}
+			}
+
+			return clusterEvents
+		}
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+			Expect(k8sClient.Get(context.TODO(), ctrlClient.ObjectKeyFromObject(cluster), cluster)).NotTo(HaveOccurred())
+
+			originalPods, err = clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster.Spec.SidecarContainer.EnableTLS = true
+			cluster.Spec.AutomationOptions.DeletionMode = fdbv1beta2.PodUpdateModeAll
+		})
+
+		JustBeforeEach(func() {
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			req = updatePods{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should plan the update of all Pods", func() {
+			updates, err := getPodsToUpdate(context.TODO(), log, clusterReconciler, cluster, internal.CreatePodMap(cluster, originalPods))
+			Expect(err).NotTo(HaveOccurred())
+
+			sidecarTLSUpdates, err := getSidecarTLSUpdates(cluster, updates)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sidecarTLSUpdates).To(Equal(updates))
+		})
+
+		When("the operator has no TLS configuration", func() {
+			It("should not recreate the Pods", func() {
+				Expect(req).NotTo(BeNil())
+				Expect(req.message).To(Equal("Operator is missing the TLS configuration to talk to sidecars with TLS enabled"))
+
+				pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), k8sClient, cluster, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pods).To(HaveLen(len(originalPods)))
+
+				var reasons []string
+				for _, event := range getEvents() {
+					reasons = append(reasons, event.Reason)
+				}
+				Expect(reasons).To(ContainElement("MissingSidecarTLSConfiguration"))
+			})
+		})
+
+		When("the operator has a TLS configuration", func() {
+			BeforeEach(func() {
+				GinkgoT().Setenv("FDB_TLS_CERTIFICATE_FILE", "/tmp/fdb-certs/tls.crt")
+				GinkgoT().Setenv("FDB_TLS_KEY_FILE", "/tmp/fdb-certs/tls.key")
+				GinkgoT().Setenv("FDB_TLS_CA_FILE", "/tmp/fdb-certs/ca.crt")
+			})
+
+			It("should recreate the Pods one fault domain at a time", func() {
+				Expect(req).NotTo(BeNil())
+				Expect(req.message).To(Equal("Pods need to be recreated"))
+
+				var messages []string
+				for _, event := range getEvents() {
+					if event.Reason == "UpdatingPods" {
+						messages = append(messages, event.Message)
+					}
+				}
+				Expect(messages).To(ConsistOf("Recreating pods in zone simulation"))
+			})
+		})
+	})
 })
@@ -0,0 +1,200 @@
+/*
+ * update_process_environment.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateProcessEnvironment provides a reconciliation step for tracking the data of the Secrets and ConfigMaps that are
+// referenced in the environment variables of the process classes. The hash of the data is part of the Pod spec, so a
+// change of the data will update the Pods.
+type updateProcessEnvironment struct{}
+
+// reconcile runs the reconciler's work.
+func (updateProcessEnvironment) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateProcessEnvironment")
+
+	processCounts, err := cluster.GetProcessCountsWithDefaults()
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	processClasses := processCounts.Map()
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processClasses[processGroup.ProcessClass]++
+	}
+
+	var hashes map[fdbv1beta2.ProcessClass]string
+	sourceData := map[internal.EnvironmentSource]map[string]string{}
+	for processClass := range processClasses {
+		sources := internal.GetProcessEnvironmentSources(cluster.GetProcessSettings(processClass))
+		if len(sources) == 0 {
+			continue
+		}
+
+		data := make(map[string]map[string]string, len(sources))
+		for _, source := range sources {
+			values, ok := sourceData[source]
+			if !ok {
+				values, err = getEnvironmentSourceData(ctx, r, cluster.Namespace, source)
+				if err != nil {
+					return &requeue{curError: err}
+				}
+
+				sourceData[source] = values
+			}
+
+			data[fmt.Sprintf("%s/%s/%s", source.Kind, source.Name, source.Key)] = values
+		}
+
+		hash, err := internal.GetJSONHash(data)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if hashes == nil {
+			hashes = make(map[fdbv1beta2.ProcessClass]string)
+		}
+		hashes[processClass] = hash
+	}
+
+	if equality.Semantic.DeepEqual(cluster.Status.ProcessEnvironmentHashes, hashes) {
+		return nil
+	}
+
+	logger.Info("Updating process environment hashes", "hashes", hashes)
+	cluster.Status.ProcessEnvironmentHashes = hashes
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// getEnvironmentSourceData returns the data of the Secret or ConfigMap that is referenced in the environment. If the
+// source references a single key, only this key is returned. A missing optional source returns no data.
+func getEnvironmentSourceData(ctx context.Context, r *FoundationDBClusterReconciler, namespace string, source internal.EnvironmentSource) (map[string]string, error) {
+	values := map[string]string{}
+	key := types.NamespacedName{Namespace: namespace, Name: source.Name}
+
+	if source.Kind == internal.EnvironmentSourceSecret {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, key, secret)
+		if err != nil {
+			if k8serrors.IsNotFound(err) && source.Optional {
+				return values, nil
+			}
+
+			return nil, err
+		}
+
+		for dataKey, value := range secret.Data {
+			values[dataKey] = string(value)
+		}
+	} else {
+		configMap := &corev1.ConfigMap{}
+		err := r.Get(ctx, key, configMap)
+		if err != nil {
+			if k8serrors.IsNotFound(err) && source.Optional {
+				return values, nil
+			}
+
+			return nil, err
+		}
+
+		for dataKey, value := range configMap.Data {
+			values[dataKey] = value
+		}
+
+		for dataKey, value := range configMap.BinaryData {
+			values[dataKey] = string(value)
+		}
+	}
+
+	if source.Key == "" {
+		return values, nil
+	}
+
+	value, ok := values[source.Key]
+	if !ok {
+		if source.Optional {
+			return map[string]string{}, nil
+		}
+
+		return nil, fmt.Errorf("%s %s has no key %s", source.Kind, source.Name, source.Key)
+	}
+
+	return map[string]string{source.Key: value}, nil
+}
+
+// findClustersForEnvironmentSource returns the requests for all clusters that reference the Secret or ConfigMap in the
+// environment of their process classes.
+func (r *FoundationDBClusterReconciler) findClustersForEnvironmentSource(object client.Object) []reconcile.Request {
+	kind := internal.EnvironmentSourceConfigMap
+	if _, ok := object.(*corev1.Secret); ok {
+		kind = internal.EnvironmentSourceSecret
+	}
+
+	clusters := &fdbv1beta2.FoundationDBClusterList{}
+	err := r.List(context.Background(), clusters, client.InNamespace(object.GetNamespace()))
+	if err != nil {
+		log.Error(err, "could not list clusters", "namespace", object.GetNamespace(), "kind", kind, "name", object.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, cluster := range clusters.Items {
+		if !referencesEnvironmentSource(&cluster, kind, object.GetName()) {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}})
+	}
+
+	return requests
+}
+
+// referencesEnvironmentSource returns true if any process class of the cluster references the Secret or ConfigMap
+// with the provided name in its environment.
+func referencesEnvironmentSource(cluster *fdbv1beta2.FoundationDBCluster, kind internal.EnvironmentSourceKind, name string) bool {
+	for processClass := range cluster.Spec.Processes {
+		for _, source := range internal.GetProcessEnvironmentSources(cluster.GetProcessSettings(processClass)) {
+			if source.Kind == kind && source.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
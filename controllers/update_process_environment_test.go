@@ -0,0 +1,218 @@
+/*
+ * update_process_environment_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_process_environment", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var secret *corev1.Secret
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls-password", Namespace: cluster.Namespace},
+			Data:       map[string][]byte{"password": []byte("secret")},
+		}
+		Expect(k8sClient.Create(context.TODO(), secret)).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateProcessEnvironment{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no environment variables are defined", func() {
+		It("should not track any hashes", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.ProcessEnvironmentHashes).To(BeEmpty())
+		})
+	})
+
+	When("an environment variable references a secret", func() {
+		BeforeEach(func() {
+			settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage]
+			settings.Env = []corev1.EnvVar{
+				{
+					Name: "FDB_TLS_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+							Key:                  "password",
+						},
+					},
+				},
+			}
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = settings
+		})
+
+		It("should track the hash for the process class", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.ProcessEnvironmentHashes).To(HaveLen(1))
+			Expect(cluster.Status.ProcessEnvironmentHashes).To(HaveKey(fdbv1beta2.ProcessClassStorage))
+		})
+
+		It("should add the hash to the Pod spec", func() {
+			spec, err := internal.GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name:  internal.ProcessEnvironmentHashVariable,
+				Value: cluster.Status.ProcessEnvironmentHashes[fdbv1beta2.ProcessClassStorage],
+			}))
+		})
+
+		When("the secret is changed", func() {
+			var previousHash string
+
+			JustBeforeEach(func() {
+				Expect(requeue).To(BeNil())
+				previousHash = cluster.Status.ProcessEnvironmentHashes[fdbv1beta2.ProcessClassStorage]
+
+				secret.Data["password"] = []byte("updated")
+				Expect(k8sClient.Update(context.TODO(), secret)).NotTo(HaveOccurred())
+				requeue = updateProcessEnvironment{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should update the hash", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.ProcessEnvironmentHashes[fdbv1beta2.ProcessClassStorage]).NotTo(Equal(previousHash))
+			})
+		})
+
+		When("another key of the secret is changed", func() {
+			var previousHash string
+
+			JustBeforeEach(func() {
+				Expect(requeue).To(BeNil())
+				previousHash = cluster.Status.ProcessEnvironmentHashes[fdbv1beta2.ProcessClassStorage]
+
+				secret.Data["other"] = []byte("updated")
+				Expect(k8sClient.Update(context.TODO(), secret)).NotTo(HaveOccurred())
+				requeue = updateProcessEnvironment{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should not update the hash", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.ProcessEnvironmentHashes[fdbv1beta2.ProcessClassStorage]).To(Equal(previousHash))
+			})
+		})
+	})
+
+	When("the environment is loaded from a missing config map", func() {
+		BeforeEach(func() {
+			settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			settings.EnvFrom = []corev1.EnvFromSource{
+				{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-settings"},
+					},
+				},
+			}
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+		})
+
+		It("should return an error", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).To(HaveOccurred())
+		})
+
+		When("the config map is optional", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].EnvFrom[0].ConfigMapRef.Optional = pointer.Bool(true)
+			})
+
+			It("should track the hashes for all process classes", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.ProcessEnvironmentHashes).To(HaveKey(fdbv1beta2.ProcessClassStorage))
+				Expect(cluster.Status.ProcessEnvironmentHashes).To(HaveKey(fdbv1beta2.ProcessClassLog))
+				Expect(cluster.Status.ProcessEnvironmentHashes).To(HaveKey(fdbv1beta2.ProcessClassStateless))
+			})
+		})
+	})
+
+	When("finding the clusters for a secret", func() {
+		var requests []reconcile.Request
+
+		JustBeforeEach(func() {
+			requests = clusterReconciler.findClustersForEnvironmentSource(secret)
+		})
+
+		When("no cluster references the secret", func() {
+			It("should not return any requests", func() {
+				Expect(requests).To(BeEmpty())
+			})
+		})
+
+		When("the cluster references the secret", func() {
+			BeforeEach(func() {
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.EnvFrom = []corev1.EnvFromSource{
+					{
+						SecretRef: &corev1.SecretEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+						},
+					},
+				}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should return the cluster", func() {
+				Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}}))
+			})
+		})
+
+		When("the cluster references a config map with the same name", func() {
+			BeforeEach(func() {
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.EnvFrom = []corev1.EnvFromSource{
+					{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+						},
+					},
+				}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should not return any requests", func() {
+				Expect(requests).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/locality"
@@ -40,6 +41,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 )
@@ -55,6 +57,28 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	status := fdbv1beta2.FoundationDBClusterStatus{}
 	// Pass through Maintenance Mode Info as the maintenance_mode_checker reconciler takes care of updating it
 	originalStatus.MaintenanceModeInfo.DeepCopyInto(&status.MaintenanceModeInfo)
+	// Pass through the reconciliation blocked information as the cluster reconciler takes care of updating it
+	status.ReconciliationBlocked = originalStatus.ReconciliationBlocked
+	status.MigrationPhase = originalStatus.MigrationPhase
+	status.StorageAutoscaling = originalStatus.StorageAutoscaling
+	status.CrashReports = originalStatus.CrashReports
+	status.LastDestructiveAction = originalStatus.LastDestructiveAction
+	status.LastClusterFileVerification = originalStatus.LastClusterFileVerification
+	status.Notifications = originalStatus.Notifications
+	status.ActionHistory = originalStatus.ActionHistory
+	status.AppliedSeedConnectionString = originalStatus.AppliedSeedConnectionString
+	status.ExternalCoordinators = originalStatus.ExternalCoordinators
+	status.ActionBudget = originalStatus.ActionBudget
+	status.AuthorizationPublicKeyIDs = originalStatus.AuthorizationPublicKeyIDs
+	status.ProcessEnvironmentHashes = originalStatus.ProcessEnvironmentHashes
+	status.TLSCertificateHashes = originalStatus.TLSCertificateHashes
+	status.TLSCertificates = originalStatus.TLSCertificates
+	status.CoordinatorChange = originalStatus.CoordinatorChange
+	status.IncompatibleClients = originalStatus.IncompatibleClients
+	status.ProcessClassReassignments = originalStatus.ProcessClassReassignments
+	status.ProxyDrain = originalStatus.ProxyDrain
+	status.OperatorExclusions = originalStatus.OperatorExclusions
+	status.ReconciliationProgress = originalStatus.ReconciliationProgress
 	status.Generations.Reconciled = cluster.Status.Generations.Reconciled
 
 	// Initialize with the current desired storage servers per Pod
@@ -73,7 +97,7 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 			},
 		}
 	} else {
-		connectionString, err := tryConnectionOptions(logger, cluster, r)
+		connectionString, err := tryConnectionOptions(ctx, logger, cluster, r)
 		if err != nil {
 			return &requeue{curError: err}
 		}
@@ -84,7 +108,7 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 			return &requeue{curError: err}
 		}
 
-		databaseStatus, err = adminClient.GetStatus()
+		databaseStatus, err = adminClient.GetStatus(ctx)
 		_ = adminClient.Close()
 
 		if err != nil {
@@ -125,6 +149,13 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	status.DatabaseConfiguration.ExcludedServers = nil
 	cluster.ClearMissingVersionFlags(&status.DatabaseConfiguration)
 	status.Configured = cluster.Status.Configured || (databaseStatus.Client.DatabaseStatus.Available && databaseStatus.Cluster.Layers.Error != "configurationMissing")
+	if cluster.Status.Configured && databaseStatus.Client.DatabaseStatus.Available {
+		status.DatabaseConfigurationDrift = getDatabaseConfigurationDrift(cluster, status.DatabaseConfiguration, originalStatus.DatabaseConfigurationDrift)
+		if status.DatabaseConfigurationDrift != nil && !equality.Semantic.DeepEqual(status.DatabaseConfigurationDrift, originalStatus.DatabaseConfigurationDrift) {
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, "DatabaseConfigurationDrift",
+				fmt.Sprintf("Running database configuration `%s` differs from the spec `%s`, configure database mode: %s", status.DatabaseConfigurationDrift.Configuration, status.DatabaseConfigurationDrift.DesiredConfiguration, cluster.GetConfigureDatabaseMode()))
+		}
+	}
 
 	if cluster.Spec.MainContainer.EnableTLS {
 		status.RequiredAddresses.TLS = true
@@ -150,11 +181,19 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		status.Health.Healthy = databaseStatus.Client.DatabaseStatus.Healthy
 		status.Health.FullReplication = databaseStatus.Cluster.FullReplication
 		status.Health.DataMovementPriority = databaseStatus.Cluster.Data.MovingData.HighestPriority
+
+		if status.Configured && databaseStatus.Client.DatabaseStatus.Available {
+			status.FaultTolerance = &fdbv1beta2.FaultToleranceStatus{
+				MaxZoneFailuresWithoutLosingData:         databaseStatus.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData,
+				MaxZoneFailuresWithoutLosingAvailability: databaseStatus.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingAvailability,
+				DesiredFaultTolerance:                    cluster.DesiredFaultTolerance(),
+			}
+		}
 	}
 
 	cluster.Status.RequiredAddresses = status.RequiredAddresses
 
-	configMap, err := internal.GetConfigMap(cluster)
+	configMap, err := internal.GetConfigMap(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: fmt.Errorf("update_status skipped due to error in GetConfigMap: %w", err)}
 	}
@@ -176,6 +215,19 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		return &requeue{curError: fmt.Errorf("update_status skipped due to error in validateProcessGroups: %w", err)}
 	}
 	removeDuplicateConditions(status)
+	status.ProcessGroupTombstones = getProcessGroupTombstones(cluster, status.ProcessGroups, databaseStatus)
+	status.Revisions, err = getProcessClassRevisions(cluster, status.ProcessGroups)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	status.LastObservedRecovery, err = getLastObservedRecovery(cluster, databaseStatus)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	status.RecoveringSince, err = getRecoveringSince(cluster, databaseStatus)
+	if err != nil {
+		return &requeue{curError: err}
+	}
 
 	existingConfigMap := &corev1.ConfigMap{}
 	err = r.Get(ctx, types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, existingConfigMap)
@@ -198,6 +250,17 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		status.RunningVersion = cluster.Spec.Version
 	}
 
+	runningVersion, err := fdbv1beta2.ParseFdbVersion(status.RunningVersion)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	status.UnsupportedFeatures = cluster.GetUnsupportedFeatures(runningVersion)
+	if len(status.UnsupportedFeatures) > 0 && !equality.Semantic.DeepEqual(status.UnsupportedFeatures, originalStatus.UnsupportedFeatures) {
+		logger.Info("Spec contains features that are not supported by the running version", "unsupportedFeatures", status.UnsupportedFeatures)
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "UnsupportedFeatures", strings.Join(status.UnsupportedFeatures, ", "))
+	}
+
 	status.ConnectionString = cluster.Status.ConnectionString
 	if status.ConnectionString == "" {
 		status.ConnectionString = existingConfigMap.Data[internal.ClusterFileKey]
@@ -220,7 +283,8 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 
 	status.HasIncorrectServiceConfig = (service == nil) != (existingService == nil)
 
-	if status.Configured && cluster.Status.ConnectionString != "" {
+	// The coordinators of a cluster that is not managed by the operator cannot be validated against the process groups.
+	if status.Configured && cluster.Status.ConnectionString != "" && !cluster.IsManagedConnectionOnly() {
 		coordinatorStatus := make(map[string]bool, len(databaseStatus.Client.Coordinators.Coordinators))
 		for _, coordinator := range databaseStatus.Client.Coordinators.Coordinators {
 			coordinatorStatus[coordinator.Address.String()] = false
@@ -249,6 +313,30 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		status.Locks.DenyList = denyList
 	}
 
+	if len(cluster.Spec.Tenants) > 0 && status.Configured {
+		tenants, err := getManagedTenants(ctx, r, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		status.Tenants = tenants
+	}
+
+	if (len(cluster.Spec.TagQuotas) > 0 || len(originalStatus.TagQuotas) > 0) && status.Configured {
+		tagQuotas, err := getTagQuotas(ctx, r, cluster, originalStatus.TagQuotas)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		status.TagQuotas = tagQuotas
+	}
+
+	if status.Configured {
+		dataDistributionEnabled, err := getDataDistributionMode(ctx, r, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		status.DataDistributionDisabled = !dataDistributionEnabled
+	}
+
 	// Sort slices that are assembled based on pods to prevent a reordering from
 	// issuing a new reconcile loop.
 	sort.Ints(status.StorageServersPerDisk)
@@ -269,6 +357,8 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		return &requeue{curError: err}
 	}
 
+	cluster.Status.Phase = getClusterPhase(cluster)
+
 	// See: https://github.com/kubernetes-sigs/kubebuilder/issues/592
 	// If we use the default reflect.DeepEqual method it will be recreating the
 	// status multiple times because the pointers are different.
@@ -283,6 +373,205 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	return nil
 }
 
+// getProcessGroupTombstones returns the tombstones of the removed process groups. Expired tombstones are dropped unless
+// the process group ID is still used in the localities or the exclusions of the database. Process group IDs of the
+// cluster that are used by the database but don't belong to a process group get a new tombstone, so they will not be
+// reused for new process groups.
+func getProcessGroupTombstones(cluster *fdbv1beta2.FoundationDBCluster, processGroups []*fdbv1beta2.ProcessGroupStatus, databaseStatus *fdbv1beta2.FoundationDBStatus) []fdbv1beta2.ProcessGroupTombstone {
+	usedProcessGroupIDs := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	if databaseStatus != nil {
+		for _, process := range databaseStatus.Cluster.Processes {
+			usedProcessGroupIDs[fdbv1beta2.ProcessGroupID(process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])] = fdbv1beta2.None{}
+		}
+
+		for _, excludedServer := range databaseStatus.Cluster.DatabaseConfiguration.ExcludedServers {
+			if !strings.HasPrefix(excludedServer.Locality, fdbv1beta2.FDBLocalityInstanceIDExclusionPrefix) {
+				continue
+			}
+
+			usedProcessGroupIDs[fdbv1beta2.ProcessGroupID(strings.TrimPrefix(excludedServer.Locality, fdbv1beta2.FDBLocalityInstanceIDExclusionPrefix))] = fdbv1beta2.None{}
+		}
+	}
+
+	existingProcessGroupIDs := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(processGroups))
+	for _, processGroup := range processGroups {
+		existingProcessGroupIDs[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+	}
+
+	activeTombstones := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	for _, tombstone := range cluster.GetActiveProcessGroupTombstones() {
+		activeTombstones[tombstone.ProcessGroupID] = fdbv1beta2.None{}
+	}
+
+	var tombstones []fdbv1beta2.ProcessGroupTombstone
+	tombstonedProcessGroupIDs := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	for _, tombstone := range cluster.Status.ProcessGroupTombstones {
+		if _, ok := existingProcessGroupIDs[tombstone.ProcessGroupID]; ok {
+			continue
+		}
+
+		_, active := activeTombstones[tombstone.ProcessGroupID]
+		_, used := usedProcessGroupIDs[tombstone.ProcessGroupID]
+		if !active && !used {
+			continue
+		}
+
+		tombstones = append(tombstones, tombstone)
+		tombstonedProcessGroupIDs[tombstone.ProcessGroupID] = fdbv1beta2.None{}
+	}
+
+	newProcessGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0)
+	for processGroupID := range usedProcessGroupIDs {
+		if _, ok := existingProcessGroupIDs[processGroupID]; ok {
+			continue
+		}
+
+		if _, ok := tombstonedProcessGroupIDs[processGroupID]; ok {
+			continue
+		}
+
+		if !isProcessGroupIDOfCluster(cluster, processGroupID) {
+			continue
+		}
+
+		newProcessGroupIDs = append(newProcessGroupIDs, processGroupID)
+	}
+
+	sort.Slice(newProcessGroupIDs, func(i, j int) bool {
+		return newProcessGroupIDs[i] < newProcessGroupIDs[j]
+	})
+
+	now := metav1.Now()
+	for _, processGroupID := range newProcessGroupIDs {
+		tombstones = append(tombstones, fdbv1beta2.ProcessGroupTombstone{
+			ProcessGroupID:   processGroupID,
+			RemovalTimestamp: &now,
+		})
+	}
+
+	return tombstones
+}
+
+// isProcessGroupIDOfCluster returns true if the process group ID matches the format of the process group IDs that the
+// operator generates for the cluster.
+func isProcessGroupIDOfCluster(cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) bool {
+	id := string(processGroupID)
+	if cluster.Spec.ProcessGroupIDPrefix != "" {
+		if !strings.HasPrefix(id, cluster.Spec.ProcessGroupIDPrefix+"-") {
+			return false
+		}
+
+		id = strings.TrimPrefix(id, cluster.Spec.ProcessGroupIDPrefix+"-")
+	}
+
+	processClass, idNum, err := internal.ParseProcessGroupID(fdbv1beta2.ProcessGroupID(id))
+	if err != nil {
+		return false
+	}
+
+	isKnownProcessClass := false
+	for _, knownProcessClass := range fdbv1beta2.ProcessClasses {
+		if knownProcessClass == processClass {
+			isKnownProcessClass = true
+			break
+		}
+	}
+
+	if !isKnownProcessClass {
+		return false
+	}
+
+	_, expectedProcessGroupID := internal.GetProcessGroupID(cluster, processClass, idNum)
+
+	return expectedProcessGroupID == processGroupID
+}
+
+// getProcessClassRevisions returns the rollout state of the Pod template for every process class of the process
+// groups. Process groups that are marked for removal are ignored, since they will not be updated anymore.
+func getProcessClassRevisions(cluster *fdbv1beta2.FoundationDBCluster, processGroups []*fdbv1beta2.ProcessGroupStatus) ([]fdbv1beta2.ProcessClassRevisionStatus, error) {
+	revisionCounts := map[fdbv1beta2.ProcessClass]map[string]int{}
+	processGroupCounts := map[fdbv1beta2.ProcessClass]int{}
+	for _, processGroup := range processGroups {
+		if processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		processGroupCounts[processGroup.ProcessClass]++
+		if _, ok := revisionCounts[processGroup.ProcessClass]; !ok {
+			revisionCounts[processGroup.ProcessClass] = map[string]int{}
+		}
+
+		if processGroup.Revision != "" {
+			revisionCounts[processGroup.ProcessClass][processGroup.Revision]++
+		}
+	}
+
+	processClasses := make([]fdbv1beta2.ProcessClass, 0, len(processGroupCounts))
+	for processClass := range processGroupCounts {
+		processClasses = append(processClasses, processClass)
+	}
+
+	sort.Slice(processClasses, func(i, j int) bool {
+		return processClasses[i] < processClasses[j]
+	})
+
+	revisions := make([]fdbv1beta2.ProcessClassRevisionStatus, 0, len(processClasses))
+	for _, processClass := range processClasses {
+		updateRevision, err := internal.GetPodTemplateHash(cluster, processClass)
+		if err != nil {
+			return nil, err
+		}
+
+		revisionStatus := fdbv1beta2.ProcessClassRevisionStatus{
+			ProcessClass:         processClass,
+			UpdateRevision:       updateRevision,
+			ProcessGroups:        processGroupCounts[processClass],
+			UpdatedProcessGroups: revisionCounts[processClass][updateRevision],
+		}
+
+		for revision, count := range revisionCounts[processClass] {
+			revisionStatus.Revisions = append(revisionStatus.Revisions, fdbv1beta2.RevisionCount{
+				Revision:      revision,
+				ProcessGroups: count,
+			})
+		}
+
+		sort.Slice(revisionStatus.Revisions, func(i, j int) bool {
+			return revisionStatus.Revisions[i].Revision < revisionStatus.Revisions[j].Revision
+		})
+
+		revisions = append(revisions, revisionStatus)
+	}
+
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+
+	return revisions, nil
+}
+
+// getClusterPhase returns the phase of the cluster based on the reconciled generation, the running version and the
+// health of the database.
+func getClusterPhase(cluster *fdbv1beta2.FoundationDBCluster) fdbv1beta2.ClusterPhase {
+	if !cluster.Status.Configured {
+		return fdbv1beta2.ClusterPhaseCreating
+	}
+
+	if !cluster.Status.Health.Available || !cluster.Status.Health.FullReplication {
+		return fdbv1beta2.ClusterPhaseDegraded
+	}
+
+	if cluster.Status.RunningVersion != cluster.Spec.Version {
+		return fdbv1beta2.ClusterPhaseUpgrading
+	}
+
+	if cluster.Status.Generations.Reconciled < cluster.Generation {
+		return fdbv1beta2.ClusterPhaseReconciling
+	}
+
+	return fdbv1beta2.ClusterPhaseReady
+}
+
 // containsAll determines if one map contains all the keys and matching values
 // from another map.
 func containsAll(current map[string]string, desired map[string]string) bool {
@@ -310,7 +599,7 @@ func optionList(options ...string) []string {
 
 // tryConnectionOptions attempts to connect with all the connection strings for this cluster and
 // returns the connection string that allows connecting to the cluster.
-func tryConnectionOptions(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, r *FoundationDBClusterReconciler) (string, error) {
+func tryConnectionOptions(ctx context.Context, logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, r *FoundationDBClusterReconciler) (string, error) {
 	connectionStrings := optionList(cluster.Status.ConnectionString, cluster.Spec.SeedConnectionString)
 
 	if len(connectionStrings) == 1 {
@@ -330,7 +619,7 @@ func tryConnectionOptions(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCl
 			return originalConnectionString, clientErr
 		}
 
-		activeConnectionString, err := adminClient.GetConnectionString()
+		activeConnectionString, err := adminClient.GetConnectionString(ctx)
 
 		closeErr := adminClient.Close()
 		if closeErr != nil {
@@ -348,7 +637,7 @@ func tryConnectionOptions(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCl
 }
 
 // checkAndSetProcessStatus checks the status of the Process and if missing or incorrect add it to the related status field
-func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, processMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.FoundationDBStatusProcessInfo, processNumber int, processCount int, processGroupStatus *fdbv1beta2.ProcessGroupStatus) error {
+func checkAndSetProcessStatus(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, processMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.FoundationDBStatusProcessInfo, processNumber int, processCount int, processGroupStatus *fdbv1beta2.ProcessGroupStatus) error {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateStatus")
 	processID := processGroupStatus.ProcessGroupID
 
@@ -372,7 +661,13 @@ func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1be
 	correct := false
 	versionCompatibleUpgrade := cluster.VersionCompatibleUpgradeInProgress()
 	for _, process := range processStatus {
-		commandLine, err := internal.GetStartCommand(cluster, processGroupStatus.ProcessClass, podClient, processNumber, processCount)
+		var commandLine string
+		var err error
+		if internal.GetPodLayout(pod) == fdbv1beta2.PodLayoutProcessPerContainer {
+			commandLine, err = internal.GetStartCommandForProcessContainer(ctx, pod, podClient, processNumber)
+		} else {
+			commandLine, err = internal.GetStartCommand(ctx, cluster, processGroupStatus.ProcessClass, podClient, processNumber, processCount)
+		}
 		if err != nil {
 			if internal.IsNetworkError(err) {
 				processGroupStatus.UpdateCondition(fdbv1beta2.SidecarUnreachable, true, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
@@ -389,11 +684,15 @@ func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1be
 			versionMatch = process.Version == cluster.Spec.Version || process.Version == fmt.Sprintf("%s-PRERELEASE", cluster.Spec.Version)
 		}
 
+		// Only the arguments are compared, so a different order of the arguments doesn't trigger a bounce.
+		missingArguments, unexpectedArguments := internal.GetCommandLineDivergence(commandLine, process.CommandLine)
+
 		// If the `EmptyMonitorConf` is set, the commandline is by definition wrong since there should be no running processes.
-		correct = commandLine == process.CommandLine && versionMatch && !cluster.Spec.Buggify.EmptyMonitorConf
+		correct = len(missingArguments) == 0 && len(unexpectedArguments) == 0 && versionMatch && !cluster.Spec.Buggify.EmptyMonitorConf
 
 		if !correct {
-			log.Info("IncorrectProcess", "expected", commandLine, "got", process.CommandLine,
+			log.Info("IncorrectProcess", "expected", internal.RedactString(commandLine), "got", internal.RedactString(process.CommandLine),
+				"missingArguments", internal.RedactArguments(missingArguments), "unexpectedArguments", internal.RedactArguments(unexpectedArguments),
 				"expectedVersion", cluster.Spec.Version,
 				"version", process.Version, "processGroupID", processGroupStatus.ProcessGroupID,
 				"emptyMonitorConf is ", cluster.Spec.Buggify.EmptyMonitorConf)
@@ -407,19 +706,40 @@ func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1be
 	return nil
 }
 
+// getProcessGroupsWithoutExclusion returns the set of process groups that should be removed without exclusion.
+func getProcessGroupsWithoutExclusion(cluster *fdbv1beta2.FoundationDBCluster) map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None {
+	processGroupsWithoutExclusion := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(cluster.Spec.ProcessGroupsToRemoveWithoutExclusion))
+	for _, processGroupID := range cluster.Spec.ProcessGroupsToRemoveWithoutExclusion {
+		processGroupsWithoutExclusion[processGroupID] = fdbv1beta2.None{}
+	}
+
+	return processGroupsWithoutExclusion
+}
+
+// markProcessGroupForRemoval marks the process group for removal and sets ExclusionSkipped if the process group
+// should be removed without exclusion.
+func markProcessGroupForRemoval(processGroup *fdbv1beta2.ProcessGroupStatus, processGroupsWithoutExclusion map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None) {
+	if processGroup.RemovalTimestamp.IsZero() {
+		processGroup.MarkForRemoval()
+	}
+
+	// Check if we should skip exclusion for the process group
+	_, ok := processGroupsWithoutExclusion[processGroup.ProcessGroupID]
+	// Keep the forced removal of a process group whose exclusion timed out.
+	forcedRemoval := processGroup.ExclusionSkipped && processGroup.GetConditionTime(fdbv1beta2.ExclusionTimedOut) != nil
+	processGroup.ExclusionSkipped = ok || forcedRemoval
+}
+
 // Validate and set progressGroup's status
 func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBClusterStatus, processMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.FoundationDBStatusProcessInfo, configMap *corev1.ConfigMap, pods []*corev1.Pod, pvcs *corev1.PersistentVolumeClaimList, logger logr.Logger) ([]*fdbv1beta2.ProcessGroupStatus, error) {
 	var err error
 	nodeMap := make(map[string]*corev1.Node)
 	processGroups := status.ProcessGroups
-	processGroupsWithoutExclusion := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(cluster.Spec.ProcessGroupsToRemoveWithoutExclusion))
-
-	for _, processGroupID := range cluster.Spec.ProcessGroupsToRemoveWithoutExclusion {
-		processGroupsWithoutExclusion[processGroupID] = fdbv1beta2.None{}
-	}
+	processGroupsWithoutExclusion := getProcessGroupsWithoutExclusion(cluster)
 
 	podMap := internal.CreatePodMap(cluster, pods)
 	pvcMap := internal.CreatePVCMap(cluster, pvcs)
+	revisions := map[fdbv1beta2.ProcessClass]string{}
 
 	disableTaintFeature := cluster.IsTaintFeatureDisabled()
 	if disableTaintFeature {
@@ -491,12 +811,7 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 		}
 
 		if isBeingRemoved {
-			if processGroup.RemovalTimestamp.IsZero() {
-				processGroup.MarkForRemoval()
-			}
-			// Check if we should skip exclusion for the process group
-			_, ok := processGroupsWithoutExclusion[processGroup.ProcessGroupID]
-			processGroup.ExclusionSkipped = ok
+			markProcessGroupForRemoval(processGroup, processGroupsWithoutExclusion)
 		}
 
 		if pod.ObjectMeta.DeletionTimestamp.IsZero() && status.HasListenIPsForAllPods {
@@ -517,7 +832,7 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 
 		// In theory we could also support multiple processes per pod for different classes
 		for i := 1; i <= processCount; i++ {
-			err = checkAndSetProcessStatus(r, cluster, pod, processMap, i, processCount, processGroup)
+			err = checkAndSetProcessStatus(ctx, r, cluster, pod, processMap, i, processCount, processGroup)
 			if err != nil {
 				return processGroups, err
 			}
@@ -538,6 +853,21 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 		if err != nil {
 			return processGroups, err
 		}
+
+		// The revision is only updated once the Pod matches the desired spec, so process groups with a pending
+		// update keep the revision they were last updated to.
+		if processGroup.GetConditionTime(fdbv1beta2.IncorrectPodSpec) == nil {
+			revision, ok := revisions[processGroup.ProcessClass]
+			if !ok {
+				revision, err = internal.GetPodTemplateHash(cluster, processGroup.ProcessClass)
+				if err != nil {
+					return processGroups, err
+				}
+				revisions[processGroup.ProcessClass] = revision
+			}
+
+			processGroup.Revision = revision
+		}
 	}
 
 	return processGroups, nil
@@ -580,7 +910,7 @@ func validateProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 	// process group si ready to be restarted.
 	var synced bool
 	if cluster.IsBeingUpgradedWithVersionIncompatibleVersion() {
-		synced, err = r.updatePodDynamicConf(logger, cluster, pod)
+		synced, err = r.updatePodDynamicConf(ctx, logger, cluster, pod)
 		if err != nil {
 			logger.Info("error when checking if Pod has the correct files")
 			synced = false
@@ -850,3 +1180,116 @@ func hasExactMatchedTaintKey(taintReplacementOptions []fdbv1beta2.TaintReplaceme
 	}
 	return false
 }
+
+// getManagedTenants returns the status of the tenants that are defined in the cluster spec and exist in the cluster.
+func getManagedTenants(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) ([]fdbv1beta2.TenantStatus, error) {
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return nil, err
+	}
+	defer adminClient.Close()
+
+	tenants, err := adminClient.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	managedTenants := make(map[string]fdbv1beta2.None, len(cluster.Spec.Tenants))
+	for _, tenant := range cluster.Spec.Tenants {
+		managedTenants[tenant.Name] = fdbv1beta2.None{}
+	}
+
+	var result []fdbv1beta2.TenantStatus
+	for _, tenant := range tenants {
+		if _, ok := managedTenants[tenant.Name]; !ok {
+			continue
+		}
+
+		result = append(result, tenant)
+	}
+
+	return result, nil
+}
+
+// getTagQuotas returns the current quotas of the transaction tags that are defined in the cluster spec. Tags from the
+// previous status that were removed from the spec are only returned as long as their quota is not cleared.
+func getTagQuotas(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, previousQuotas []fdbv1beta2.TagQuota) ([]fdbv1beta2.TagQuota, error) {
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return nil, err
+	}
+	defer adminClient.Close()
+
+	specTags := make(map[string]fdbv1beta2.None, len(cluster.Spec.TagQuotas))
+	result := make([]fdbv1beta2.TagQuota, 0, len(cluster.Spec.TagQuotas))
+	for _, quota := range cluster.Spec.TagQuotas {
+		currentQuota, err := adminClient.GetTagQuota(ctx, quota.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		specTags[quota.Tag] = fdbv1beta2.None{}
+		result = append(result, currentQuota)
+	}
+
+	for _, quota := range previousQuotas {
+		if _, ok := specTags[quota.Tag]; ok {
+			continue
+		}
+
+		currentQuota, err := adminClient.GetTagQuota(ctx, quota.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if currentQuota.TotalThroughput == 0 && currentQuota.ReservedThroughput == 0 {
+			continue
+		}
+
+		result = append(result, currentQuota)
+	}
+
+	return result, nil
+}
+
+// getDataDistributionMode returns true if data distribution is enabled in the cluster.
+func getDataDistributionMode(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) (bool, error) {
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return false, err
+	}
+	defer adminClient.Close()
+
+	return adminClient.IsDataDistributionEnabled(ctx)
+}
+
+// getDatabaseConfigurationDrift returns the drift between the running database configuration and the desired
+// database configuration. While a new generation of the cluster is reconciled, the previous drift will be kept as long
+// as the desired database configuration has not changed, so the drift is not reverted by unrelated spec changes. If no
+// drift is detected, nil will be returned.
+func getDatabaseConfigurationDrift(cluster *fdbv1beta2.FoundationDBCluster, currentConfiguration fdbv1beta2.DatabaseConfiguration, previousDrift *fdbv1beta2.DatabaseConfigurationDriftStatus) *fdbv1beta2.DatabaseConfigurationDriftStatus {
+	desiredConfiguration := cluster.DesiredDatabaseConfiguration()
+	if equality.Semantic.DeepEqual(currentConfiguration, desiredConfiguration) {
+		return nil
+	}
+
+	desiredConfigurationString, _ := desiredConfiguration.GetConfigurationString(cluster.GetRunningVersion())
+	if cluster.Status.Generations.Reconciled != cluster.ObjectMeta.Generation {
+		if previousDrift != nil && previousDrift.DesiredConfiguration == desiredConfigurationString {
+			return previousDrift
+		}
+
+		return nil
+	}
+
+	configurationString, _ := currentConfiguration.GetConfigurationString(cluster.GetRunningVersion())
+	if previousDrift != nil && previousDrift.Configuration == configurationString && previousDrift.DesiredConfiguration == desiredConfigurationString {
+		return previousDrift
+	}
+
+	return &fdbv1beta2.DatabaseConfigurationDriftStatus{
+		Configuration:        configurationString,
+		DesiredConfiguration: desiredConfigurationString,
+		Timestamp:            &metav1.Time{Time: time.Now()},
+	}
+}
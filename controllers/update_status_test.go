@@ -22,6 +22,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -321,7 +323,7 @@ var _ = Describe("update_status", func() {
 		})
 
 		JustBeforeEach(func() {
-			databaseStatus, err := adminClient.GetStatus()
+			databaseStatus, err := adminClient.GetStatus(context.TODO())
 			Expect(err).NotTo(HaveOccurred())
 			processMap = make(map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.FoundationDBStatusProcessInfo)
 			for _, process := range databaseStatus.Cluster.Processes {
@@ -421,6 +423,23 @@ var _ = Describe("update_status", func() {
 			})
 		})
 
+		When("a process group reports the command line arguments in a different order", func() {
+			It("should not get a condition assigned", func() {
+				processes := processMap[storageOneProcessGroupID]
+				Expect(processes).To(HaveLen(1))
+				arguments := strings.Fields(processes[0].CommandLine)
+				Expect(len(arguments)).To(BeNumerically(">", 2))
+				arguments[1], arguments[len(arguments)-1] = arguments[len(arguments)-1], arguments[1]
+				processes[0].CommandLine = strings.Join(arguments, "  ")
+
+				processGroupStatus, err := validateProcessGroups(context.TODO(), clusterReconciler, cluster, &cluster.Status, processMap, configMap, allPods, allPvcs, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				incorrectProcesses := fdbv1beta2.FilterByCondition(processGroupStatus, fdbv1beta2.IncorrectCommandLine, false)
+				Expect(incorrectProcesses).To(BeEmpty())
+			})
+		})
+
 		When("a process group is not reporting to the cluster", func() {
 			BeforeEach(func() {
 				adminClient.MockMissingProcessGroup(storageOneProcessGroupID, true)
@@ -767,6 +786,70 @@ var _ = Describe("update_status", func() {
 			Expect(cluster.Status.Generations.Reconciled).To(Equal(cluster.ObjectMeta.Generation))
 		})
 
+		When("the status contains fields that are managed by other reconcilers", func() {
+			BeforeEach(func() {
+				cluster.Status.ActionBudget = &fdbv1beta2.ActionBudgetStatus{UsedActions: 3}
+				cluster.Status.AuthorizationPublicKeyIDs = []string{"key-1"}
+				cluster.Status.CoordinatorChange = &fdbv1beta2.CoordinatorChangeStatus{
+					State:                    fdbv1beta2.CoordinatorChangeStateDistributed,
+					PreviousConnectionString: "operator_test:previous@1.1.1.1:4501",
+				}
+				Expect(k8sClient.Status().Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should keep the fields", func() {
+				Expect(cluster.Status.ActionBudget).NotTo(BeNil())
+				Expect(cluster.Status.ActionBudget.UsedActions).To(Equal(3))
+				Expect(cluster.Status.AuthorizationPublicKeyIDs).To(ConsistOf("key-1"))
+				Expect(cluster.Status.CoordinatorChange).NotTo(BeNil())
+				Expect(cluster.Status.CoordinatorChange.State).To(Equal(fdbv1beta2.CoordinatorChangeStateDistributed))
+			})
+		})
+
+		It("should record the fault tolerance", func() {
+			Expect(cluster.Status.FaultTolerance).To(Equal(&fdbv1beta2.FaultToleranceStatus{
+				MaxZoneFailuresWithoutLosingData:         1,
+				MaxZoneFailuresWithoutLosingAvailability: 1,
+				DesiredFaultTolerance:                    1,
+			}))
+		})
+
+		It("should record the revisions of the process classes", func() {
+			Expect(cluster.Status.Revisions).NotTo(BeEmpty())
+			for _, revision := range cluster.Status.Revisions {
+				Expect(revision.UpdatedProcessGroups).To(Equal(revision.ProcessGroups))
+				Expect(revision.Revisions).To(ConsistOf(fdbv1beta2.RevisionCount{Revision: revision.UpdateRevision, ProcessGroups: revision.ProcessGroups}))
+			}
+		})
+
+		When("the Pod template of the storage processes is changed", func() {
+			var previousRevision string
+
+			BeforeEach(func() {
+				previousRevision, err = internal.GetPodTemplateHash(cluster, fdbv1beta2.ProcessClassStorage)
+				Expect(err).NotTo(HaveOccurred())
+
+				storageSettings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				storageSettings.PodTemplate = storageSettings.PodTemplate.DeepCopy()
+				storageSettings.PodTemplate.Spec.PriorityClassName = "fdb"
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = storageSettings
+			})
+
+			It("should keep the previous revision for the storage process groups", func() {
+				var storageRevision *fdbv1beta2.ProcessClassRevisionStatus
+				for idx, revision := range cluster.Status.Revisions {
+					if revision.ProcessClass == fdbv1beta2.ProcessClassStorage {
+						storageRevision = &cluster.Status.Revisions[idx]
+					}
+				}
+
+				Expect(storageRevision).NotTo(BeNil())
+				Expect(storageRevision.UpdateRevision).NotTo(Equal(previousRevision))
+				Expect(storageRevision.UpdatedProcessGroups).To(BeZero())
+				Expect(storageRevision.Revisions).To(ConsistOf(fdbv1beta2.RevisionCount{Revision: previousRevision, ProcessGroups: storageRevision.ProcessGroups}))
+			})
+		})
+
 		When("disabling an explicit listen address", func() {
 			BeforeEach(func() {
 				result, err := reconcileCluster(cluster)
@@ -781,6 +864,19 @@ var _ = Describe("update_status", func() {
 				})
 			})
 		})
+
+		When("enabling DNS in the cluster file on a version without DNS support", func() {
+			BeforeEach(func() {
+				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+			})
+
+			It("should report the feature as unsupported", func() {
+				Expect(cluster.Status.UnsupportedFeatures).To(ConsistOf(
+					fmt.Sprintf("DNS names in the cluster file are not supported on version %s, IP addresses will be used", cluster.Status.RunningVersion),
+				))
+			})
+		})
+
 	})
 
 	DescribeTable("when getting the running version from the running processes", func(versionMap map[string]int, fallback string, expected string) {
@@ -798,4 +894,179 @@ var _ = Describe("update_status", func() {
 			"7.1.15": 50,
 		}, "0", "7.1.15"),
 		Entry("when the versionMap is empty", map[string]int{}, "7.1.15", "7.1.15"))
+
+	DescribeTable("when getting the phase of the cluster", func(status fdbv1beta2.FoundationDBClusterStatus, expected fdbv1beta2.ClusterPhase) {
+		cluster := &fdbv1beta2.FoundationDBCluster{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       fdbv1beta2.FoundationDBClusterSpec{Version: "7.1.25"},
+			Status:     status,
+		}
+		Expect(getClusterPhase(cluster)).To(Equal(expected))
+	},
+		Entry("when the database is not configured", fdbv1beta2.FoundationDBClusterStatus{}, fdbv1beta2.ClusterPhaseCreating),
+		Entry("when the database is not available", fdbv1beta2.FoundationDBClusterStatus{
+			Configured:     true,
+			RunningVersion: "7.1.25",
+			Health:         fdbv1beta2.ClusterHealth{FullReplication: true},
+			Generations:    fdbv1beta2.ClusterGenerationStatus{Reconciled: 2},
+		}, fdbv1beta2.ClusterPhaseDegraded),
+		Entry("when the database is upgraded", fdbv1beta2.FoundationDBClusterStatus{
+			Configured:     true,
+			RunningVersion: "7.1.21",
+			Health:         fdbv1beta2.ClusterHealth{Available: true, FullReplication: true},
+			Generations:    fdbv1beta2.ClusterGenerationStatus{Reconciled: 1},
+		}, fdbv1beta2.ClusterPhaseUpgrading),
+		Entry("when the latest generation is not reconciled", fdbv1beta2.FoundationDBClusterStatus{
+			Configured:     true,
+			RunningVersion: "7.1.25",
+			Health:         fdbv1beta2.ClusterHealth{Available: true, FullReplication: true},
+			Generations:    fdbv1beta2.ClusterGenerationStatus{Reconciled: 1},
+		}, fdbv1beta2.ClusterPhaseReconciling),
+		Entry("when the cluster is reconciled", fdbv1beta2.FoundationDBClusterStatus{
+			Configured:     true,
+			RunningVersion: "7.1.25",
+			Health:         fdbv1beta2.ClusterHealth{Available: true, FullReplication: true},
+			Generations:    fdbv1beta2.ClusterGenerationStatus{Reconciled: 2},
+		}, fdbv1beta2.ClusterPhaseReady))
+
+	When("getting the process group tombstones", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var processGroups []*fdbv1beta2.ProcessGroupStatus
+		var databaseStatus *fdbv1beta2.FoundationDBStatus
+		var tombstones []fdbv1beta2.ProcessGroupTombstone
+
+		getTombstoneIDs := func() []fdbv1beta2.ProcessGroupID {
+			ids := make([]fdbv1beta2.ProcessGroupID, 0, len(tombstones))
+			for _, tombstone := range tombstones {
+				ids = append(ids, tombstone.ProcessGroupID)
+			}
+
+			return ids
+		}
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			processGroups = []*fdbv1beta2.ProcessGroupStatus{
+				fdbv1beta2.NewProcessGroupStatus("storage-1", fdbv1beta2.ProcessClassStorage, nil),
+			}
+			databaseStatus = &fdbv1beta2.FoundationDBStatus{}
+			databaseStatus.Cluster.Processes = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.FoundationDBStatusProcessInfo{
+				"storage-1": {Locality: map[string]string{fdbv1beta2.FDBLocalityInstanceIDKey: "storage-1"}},
+			}
+
+			cluster.AddProcessGroupTombstone("storage-2")
+			cluster.AddProcessGroupTombstone("storage-3")
+			expired := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			cluster.Status.ProcessGroupTombstones[1].RemovalTimestamp = &expired
+		})
+
+		JustBeforeEach(func() {
+			tombstones = getProcessGroupTombstones(cluster, processGroups, databaseStatus)
+		})
+
+		It("should keep the active tombstones and drop the expired tombstones", func() {
+			Expect(getTombstoneIDs()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-2")))
+		})
+
+		When("the process group ID of the expired tombstone is still excluded", func() {
+			BeforeEach(func() {
+				databaseStatus.Cluster.DatabaseConfiguration.ExcludedServers = []fdbv1beta2.ExcludedServers{
+					{Locality: fdbv1beta2.FDBLocalityInstanceIDExclusionPrefix + "storage-3"},
+				}
+			})
+
+			It("should keep the expired tombstone", func() {
+				Expect(getTombstoneIDs()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-2"), fdbv1beta2.ProcessGroupID("storage-3")))
+			})
+		})
+
+		When("the database reports processes without a process group", func() {
+			BeforeEach(func() {
+				databaseStatus.Cluster.Processes["storage-4"] = fdbv1beta2.FoundationDBStatusProcessInfo{
+					Locality: map[string]string{fdbv1beta2.FDBLocalityInstanceIDKey: "storage-4"},
+				}
+				databaseStatus.Cluster.Processes["other-storage-1"] = fdbv1beta2.FoundationDBStatusProcessInfo{
+					Locality: map[string]string{fdbv1beta2.FDBLocalityInstanceIDKey: "other-storage-1"},
+				}
+			})
+
+			It("should add a tombstone for the process group IDs of the cluster", func() {
+				Expect(getTombstoneIDs()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-2"), fdbv1beta2.ProcessGroupID("storage-4")))
+			})
+		})
+
+		When("a process group with the ID of a tombstone exists", func() {
+			BeforeEach(func() {
+				processGroups = append(processGroups, fdbv1beta2.NewProcessGroupStatus("storage-2", fdbv1beta2.ProcessClassStorage, nil))
+			})
+
+			It("should drop the tombstone", func() {
+				Expect(tombstones).To(BeEmpty())
+			})
+		})
+	})
+
+	When("getting the revisions of the process classes", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var processGroups []*fdbv1beta2.ProcessGroupStatus
+		var revisions []fdbv1beta2.ProcessClassRevisionStatus
+		var updateRevision string
+
+		BeforeEach(func() {
+			var err error
+			cluster = internal.CreateDefaultCluster()
+			Expect(internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})).NotTo(HaveOccurred())
+			updateRevision, err = internal.GetPodTemplateHash(cluster, fdbv1beta2.ProcessClassStorage)
+			Expect(err).NotTo(HaveOccurred())
+
+			processGroups = []*fdbv1beta2.ProcessGroupStatus{
+				{ProcessGroupID: "storage-1", ProcessClass: fdbv1beta2.ProcessClassStorage, Revision: updateRevision},
+				{ProcessGroupID: "storage-2", ProcessClass: fdbv1beta2.ProcessClassStorage, Revision: "old"},
+				{ProcessGroupID: "storage-3", ProcessClass: fdbv1beta2.ProcessClassStorage},
+				{ProcessGroupID: "storage-4", ProcessClass: fdbv1beta2.ProcessClassStorage, Revision: "old"},
+			}
+			processGroups[3].MarkForRemoval()
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			revisions, err = getProcessClassRevisions(cluster, processGroups)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should count the process groups per revision", func() {
+			Expect(revisions).To(ConsistOf(fdbv1beta2.ProcessClassRevisionStatus{
+				ProcessClass:         fdbv1beta2.ProcessClassStorage,
+				UpdateRevision:       updateRevision,
+				ProcessGroups:        3,
+				UpdatedProcessGroups: 1,
+				Revisions: []fdbv1beta2.RevisionCount{
+					{Revision: updateRevision, ProcessGroups: 1},
+					{Revision: "old", ProcessGroups: 1},
+				},
+			}))
+		})
+
+		When("no process groups exist", func() {
+			BeforeEach(func() {
+				processGroups = nil
+			})
+
+			It("should return no revisions", func() {
+				Expect(revisions).To(BeNil())
+			})
+		})
+	})
+
+	DescribeTable("when checking if a process group ID belongs to the cluster", func(prefix string, processGroupID fdbv1beta2.ProcessGroupID, expected bool) {
+		cluster := internal.CreateDefaultCluster()
+		cluster.Spec.ProcessGroupIDPrefix = prefix
+		Expect(isProcessGroupIDOfCluster(cluster, processGroupID)).To(Equal(expected))
+	},
+		Entry("without prefix", "", fdbv1beta2.ProcessGroupID("storage-1"), true),
+		Entry("without prefix and a foreign ID", "", fdbv1beta2.ProcessGroupID("dc2-storage-1"), false),
+		Entry("with prefix", "dc1", fdbv1beta2.ProcessGroupID("dc1-storage-1"), true),
+		Entry("with prefix and a foreign ID", "dc1", fdbv1beta2.ProcessGroupID("dc2-storage-1"), false),
+		Entry("with an invalid ID", "", fdbv1beta2.ProcessGroupID("storage"), false),
+	)
 })
@@ -0,0 +1,67 @@
+/*
+ * update_tag_quotas.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateTagQuotas provides a reconciliation step for updating the throughput quotas of transaction tags.
+type updateTagQuotas struct{}
+
+// reconcile runs the reconciler's work.
+func (updateTagQuotas) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if (len(cluster.Spec.TagQuotas) == 0 && len(cluster.Status.TagQuotas) == 0) || !cluster.Status.Configured {
+		return nil
+	}
+
+	pendingQuotas := cluster.GetPendingTagQuotas()
+	if len(pendingQuotas) == 0 {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateTagQuotas")
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	for _, quota := range pendingQuotas {
+		logger.Info("Updating tag quota", "tag", quota.Tag, "totalThroughput", quota.TotalThroughput, "reservedThroughput", quota.ReservedThroughput)
+		message := fmt.Sprintf("Updating quota for tag %s", quota.Tag)
+		if quota.TotalThroughput == 0 && quota.ReservedThroughput == 0 {
+			message = fmt.Sprintf("Clearing quota for tag %s", quota.Tag)
+		}
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingTagQuota", message)
+		err = adminClient.SetTagQuota(ctx, quota)
+		if err != nil {
+			return &requeue{curError: err, delayedRequeue: true}
+		}
+	}
+
+	return nil
+}
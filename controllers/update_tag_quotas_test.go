@@ -0,0 +1,142 @@
+/*
+ * update_tag_quotas_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_tag_quotas", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Version = fdbv1beta2.Versions.SupportsTagQuotas.String()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		generation, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generation).To(Equal(int64(1)))
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateTagQuotas{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no tag quotas are defined", func() {
+		It("should not set any quotas", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.TagQuotas).To(BeEmpty())
+		})
+	})
+
+	When("a new tag quota is defined", func() {
+		quota := fdbv1beta2.TagQuota{Tag: "tenant1", TotalThroughput: 1000, ReservedThroughput: 100}
+
+		BeforeEach(func() {
+			cluster.Spec.TagQuotas = []fdbv1beta2.TagQuota{quota}
+		})
+
+		It("should set the quota", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.TagQuotas).To(HaveKeyWithValue("tenant1", quota))
+		})
+	})
+
+	When("a tag quota should be cleared", func() {
+		BeforeEach(func() {
+			Expect(adminClient.SetTagQuota(context.TODO(), fdbv1beta2.TagQuota{Tag: "tenant1", TotalThroughput: 1000})).NotTo(HaveOccurred())
+			Expect(adminClient.SetTagQuota(context.TODO(), fdbv1beta2.TagQuota{Tag: "tenant2", TotalThroughput: 1000})).NotTo(HaveOccurred())
+			cluster.Spec.TagQuotas = []fdbv1beta2.TagQuota{{Tag: "tenant1"}}
+			cluster.Status.TagQuotas = []fdbv1beta2.TagQuota{adminClient.TagQuotas["tenant1"]}
+		})
+
+		It("should only clear the quota of the defined tag", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.TagQuotas).NotTo(HaveKey("tenant1"))
+			Expect(adminClient.TagQuotas).To(HaveKey("tenant2"))
+		})
+	})
+
+	When("reconciling the cluster with tag quotas", func() {
+		quota := fdbv1beta2.TagQuota{Tag: "tenant1", TotalThroughput: 1000}
+
+		BeforeEach(func() {
+			cluster.Spec.TagQuotas = []fdbv1beta2.TagQuota{quota}
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			generation, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(generation).To(Equal(int64(2)))
+		})
+
+		It("should report the quotas in the status", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.TagQuotas).To(ConsistOf(quota))
+			Expect(cluster.Status.Generations.NeedsTagQuotaChanges).To(BeZero())
+		})
+
+		When("the tag quota is removed from the spec", func() {
+			BeforeEach(func() {
+				Expect(adminClient.SetTagQuota(context.TODO(), fdbv1beta2.TagQuota{Tag: "tenant2", TotalThroughput: 1000})).NotTo(HaveOccurred())
+				cluster.Spec.TagQuotas = nil
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+				result, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				generation, err := reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(generation).To(Equal(int64(3)))
+			})
+
+			It("should clear the quota", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.TagQuotas).NotTo(HaveKey("tenant1"))
+				Expect(adminClient.TagQuotas).To(HaveKey("tenant2"))
+				Expect(cluster.Status.TagQuotas).To(BeEmpty())
+				Expect(cluster.Status.Generations.NeedsTagQuotaChanges).To(BeZero())
+			})
+		})
+	})
+})
@@ -0,0 +1,72 @@
+/*
+ * update_tenants.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateTenants provides a reconciliation step for creating and deleting the tenants defined in the cluster spec.
+type updateTenants struct{}
+
+// reconcile runs the reconciler's work.
+func (updateTenants) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if len(cluster.Spec.Tenants) == 0 || !cluster.Status.Configured {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateTenants")
+	tenantsToCreate, tenantsToRemove := cluster.GetPendingTenantChanges()
+	if len(tenantsToCreate) == 0 && len(tenantsToRemove) == 0 {
+		return nil
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	for _, name := range tenantsToCreate {
+		logger.Info("Creating tenant", "tenant", name)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "CreatingTenant", fmt.Sprintf("Creating tenant %s", name))
+		err = adminClient.CreateTenant(ctx, name)
+		if err != nil {
+			return &requeue{curError: err, delayedRequeue: true}
+		}
+	}
+
+	for _, name := range tenantsToRemove {
+		logger.Info("Deleting tenant", "tenant", name)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "DeletingTenant", fmt.Sprintf("Deleting tenant %s", name))
+		err = adminClient.DeleteTenant(ctx, name)
+		if err != nil {
+			return &requeue{curError: err, delayedRequeue: true}
+		}
+	}
+
+	return nil
+}
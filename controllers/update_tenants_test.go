@@ -0,0 +1,127 @@
+/*
+ * update_tenants_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_tenants", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Version = fdbv1beta2.Versions.SupportsTenants.String()
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		generation, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generation).To(Equal(int64(1)))
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateTenants{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no tenants are defined", func() {
+		It("should not create any tenants", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.Tenants).To(BeEmpty())
+		})
+	})
+
+	When("a new tenant is defined", func() {
+		BeforeEach(func() {
+			cluster.Spec.Tenants = []fdbv1beta2.TenantSpec{{Name: "tenant1"}}
+		})
+
+		It("should create the tenant", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.Tenants).To(HaveKey("tenant1"))
+		})
+	})
+
+	When("a tenant should be removed", func() {
+		BeforeEach(func() {
+			Expect(adminClient.CreateTenant(context.TODO(), "tenant1")).NotTo(HaveOccurred())
+			Expect(adminClient.CreateTenant(context.TODO(), "tenant2")).NotTo(HaveOccurred())
+			cluster.Spec.Tenants = []fdbv1beta2.TenantSpec{{Name: "tenant1", Remove: true}}
+			cluster.Status.Tenants = []fdbv1beta2.TenantStatus{adminClient.Tenants["tenant1"]}
+		})
+
+		It("should only delete the managed tenant", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.Tenants).NotTo(HaveKey("tenant1"))
+			Expect(adminClient.Tenants).To(HaveKey("tenant2"))
+		})
+	})
+
+	When("the tenant already exists", func() {
+		BeforeEach(func() {
+			Expect(adminClient.CreateTenant(context.TODO(), "tenant1")).NotTo(HaveOccurred())
+			cluster.Spec.Tenants = []fdbv1beta2.TenantSpec{{Name: "tenant1"}}
+			cluster.Status.Tenants = []fdbv1beta2.TenantStatus{adminClient.Tenants["tenant1"]}
+		})
+
+		It("should not requeue", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.Tenants).To(HaveLen(1))
+		})
+	})
+
+	When("reconciling the cluster with tenants", func() {
+		BeforeEach(func() {
+			Expect(adminClient.CreateTenant(context.TODO(), "unmanaged")).NotTo(HaveOccurred())
+			cluster.Spec.Tenants = []fdbv1beta2.TenantSpec{{Name: "tenant1"}}
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			generation, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(generation).To(Equal(int64(2)))
+		})
+
+		It("should report the managed tenants in the status", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.Tenants).To(ConsistOf(adminClient.Tenants["tenant1"]))
+		})
+	})
+})
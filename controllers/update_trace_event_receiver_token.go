@@ -0,0 +1,68 @@
+/*
+ * update_trace_event_receiver_token.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateTraceEventReceiverToken provides a reconciliation step for creating the secret with the token that the trace
+// log forwarders use to authenticate against the trace event receiver.
+type updateTraceEventReceiverToken struct{}
+
+// reconcile runs the reconciler's work.
+func (updateTraceEventReceiverToken) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !cluster.UsesTraceEventReceiver() {
+		return nil
+	}
+
+	existing := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.GetTraceEventReceiverSecretName()}, existing)
+	if err == nil {
+		// The token is never rotated by the operator, deleting the secret will create a new token.
+		return nil
+	}
+
+	if !k8serrors.IsNotFound(err) {
+		return &requeue{curError: err}
+	}
+
+	secret, err := internal.GetTraceEventReceiverSecret(cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateTraceEventReceiverToken")
+	logger.Info("Creating trace event receiver secret", "name", secret.Name)
+	err = r.Create(ctx, secret)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
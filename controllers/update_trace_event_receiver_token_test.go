@@ -0,0 +1,94 @@
+/*
+ * update_trace_event_receiver_token_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_trace_event_receiver_token", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var requeue *requeue
+
+	getTokenSecret := func() (*corev1.Secret, error) {
+		secret := &corev1.Secret{}
+		err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.GetTraceEventReceiverSecretName()}, secret)
+		return secret, err
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateTraceEventReceiverToken{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the trace event receiver is not used", func() {
+		It("should not create the secret", func() {
+			Expect(requeue).To(BeNil())
+			_, err := getTokenSecret()
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	When("the trace event receiver is used", func() {
+		BeforeEach(func() {
+			cluster.Spec.TraceLogs = &fdbv1beta2.TraceLogSpec{EventReceiverAddress: "fdb-kubernetes-operator.default.svc:8080"}
+		})
+
+		It("should create the secret with a token", func() {
+			Expect(requeue).To(BeNil())
+			secret, err := getTokenSecret()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Data[internal.TraceEventReceiverTokenKey]).To(HaveLen(64))
+		})
+
+		When("the secret already exists", func() {
+			var token []byte
+
+			BeforeEach(func() {
+				Expect(updateTraceEventReceiverToken{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				secret, err := getTokenSecret()
+				Expect(err).NotTo(HaveOccurred())
+				token = secret.Data[internal.TraceEventReceiverTokenKey]
+			})
+
+			It("should keep the token", func() {
+				Expect(requeue).To(BeNil())
+				secret, err := getTokenSecret()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(secret.Data[internal.TraceEventReceiverTokenKey]).To(Equal(token))
+			})
+		})
+	})
+})
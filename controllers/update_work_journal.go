@@ -0,0 +1,155 @@
+/*
+ * update_work_journal.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateWorkJournal provides a reconciliation step for storing the work journal of the cluster in the database. If
+// the status of the cluster is missing the state of an operation that is part of the work journal, e.g. because the
+// cluster resource was recreated, the state will be restored from the work journal.
+type updateWorkJournal struct{}
+
+// reconcile runs the reconciler's work.
+func (updateWorkJournal) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !cluster.UseWorkJournal() || !cluster.Status.Configured {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateWorkJournal")
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+	defer adminClient.Close()
+
+	journal, err := adminClient.GetWorkJournal(ctx)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	restored := restoreFromWorkJournal(logger, cluster, journal)
+	if len(restored) > 0 {
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "RestoredFromWorkJournal", fmt.Sprintf("Restored %v from the work journal", restored))
+		err = r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err, delayedRequeue: true}
+		}
+	}
+
+	err = storeWorkJournal(ctx, cluster, adminClient, journal)
+	if err != nil {
+		return &requeue{curError: err, delayedRequeue: true}
+	}
+
+	return nil
+}
+
+// restoreFromWorkJournal restores the state of the operations in the work journal that is missing in the status of
+// the cluster and returns the restored operations.
+func restoreFromWorkJournal(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, journal *fdbadminclient.WorkJournal) []string {
+	if journal.IsEmpty() {
+		return nil
+	}
+
+	var restored []string
+	if cluster.Status.CoordinatorChange == nil && journal.CoordinatorChange != nil {
+		logger.Info("Restoring coordinator change from work journal", "state", journal.CoordinatorChange.State, "coordinators", journal.CoordinatorChange.Coordinators)
+		cluster.Status.CoordinatorChange = journal.CoordinatorChange.DeepCopy()
+		restored = append(restored, "coordinator change")
+	}
+
+	pendingExclusions := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(journal.PendingExclusions))
+	for _, processGroupID := range journal.PendingExclusions {
+		pendingExclusions[processGroupID] = fdbv1beta2.None{}
+	}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if _, ok := pendingExclusions[processGroup.ProcessGroupID]; !ok || processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		logger.Info("Restoring removal of process group from work journal", "processGroupID", processGroup.ProcessGroupID)
+		processGroup.MarkForRemoval()
+		restored = append(restored, fmt.Sprintf("removal of process group %s", processGroup.ProcessGroupID))
+	}
+
+	return restored
+}
+
+// storeWorkJournal stores the work journal for the current status of the cluster in the database, if the work journal
+// is enabled and differs from the previous work journal.
+func storeWorkJournal(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient, previous *fdbadminclient.WorkJournal) error {
+	if !cluster.UseWorkJournal() {
+		return nil
+	}
+
+	journal := getWorkJournal(cluster)
+	if journal.IsEmpty() && previous.IsEmpty() || equality.Semantic.DeepEqual(journal, previous) {
+		return nil
+	}
+
+	return adminClient.UpdateWorkJournal(ctx, journal)
+}
+
+// recordWorkJournal reads the previous work journal from the database and stores the work journal for the current
+// status of the cluster, if the work journal is enabled.
+func recordWorkJournal(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient) error {
+	if !cluster.UseWorkJournal() {
+		return nil
+	}
+
+	previous, err := adminClient.GetWorkJournal(ctx)
+	if err != nil {
+		return err
+	}
+
+	return storeWorkJournal(ctx, cluster, adminClient, previous)
+}
+
+// getWorkJournal returns the work journal for the current status of the cluster.
+func getWorkJournal(cluster *fdbv1beta2.FoundationDBCluster) *fdbadminclient.WorkJournal {
+	journal := &fdbadminclient.WorkJournal{
+		CoordinatorChange: cluster.Status.CoordinatorChange.DeepCopy(),
+	}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			journal.PendingExclusions = append(journal.PendingExclusions, processGroup.ProcessGroupID)
+		}
+	}
+
+	sort.Slice(journal.PendingExclusions, func(i, j int) bool {
+		return journal.PendingExclusions[i] < journal.PendingExclusions[j]
+	})
+
+	return journal
+}
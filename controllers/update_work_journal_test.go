@@ -0,0 +1,138 @@
+/*
+ * update_work_journal_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+var _ = Describe("update_work_journal", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		var err error
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateWorkJournal{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the work journal is disabled", func() {
+		BeforeEach(func() {
+			cluster.Status.ProcessGroups[0].MarkForRemoval()
+		})
+
+		It("should not store a work journal", func() {
+			Expect(requeue).To(BeNil())
+			Expect(adminClient.WorkJournal).To(BeNil())
+		})
+	})
+
+	When("the work journal is enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.UseWorkJournal = pointer.Bool(true)
+		})
+
+		When("no operation is pending", func() {
+			It("should not store a work journal", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.WorkJournal).To(BeNil())
+			})
+		})
+
+		When("a process group is marked for removal", func() {
+			var processGroupID fdbv1beta2.ProcessGroupID
+
+			BeforeEach(func() {
+				processGroupID = cluster.Status.ProcessGroups[0].ProcessGroupID
+				cluster.Status.ProcessGroups[0].MarkForRemoval()
+			})
+
+			It("should store the pending exclusion", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.WorkJournal).NotTo(BeNil())
+				Expect(adminClient.WorkJournal.PendingExclusions).To(ConsistOf(processGroupID))
+			})
+
+			When("the process group was removed", func() {
+				JustBeforeEach(func() {
+					Expect(requeue).To(BeNil())
+					cluster.Status.ProcessGroups = cluster.Status.ProcessGroups[1:]
+					requeue = updateWorkJournal{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should remove the work journal", func() {
+					Expect(requeue).To(BeNil())
+					Expect(adminClient.WorkJournal).To(BeNil())
+				})
+			})
+		})
+
+		When("the work journal contains operations that are missing in the status", func() {
+			var processGroupID fdbv1beta2.ProcessGroupID
+
+			BeforeEach(func() {
+				processGroupID = cluster.Status.ProcessGroups[0].ProcessGroupID
+				adminClient.WorkJournal = &fdbadminclient.WorkJournal{
+					PendingExclusions: []fdbv1beta2.ProcessGroupID{processGroupID, "removed-1"},
+					CoordinatorChange: &fdbv1beta2.CoordinatorChangeStatus{
+						State:                    fdbv1beta2.CoordinatorChangeStateProposed,
+						PreviousConnectionString: cluster.Status.ConnectionString,
+						Coordinators:             []string{"1.1.1.1:4501"},
+					},
+				}
+			})
+
+			It("should restore the operations", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.CoordinatorChange).NotTo(BeNil())
+				Expect(cluster.Status.CoordinatorChange.State).To(Equal(fdbv1beta2.CoordinatorChangeStateProposed))
+				Expect(cluster.Status.CoordinatorChange.Coordinators).To(ConsistOf("1.1.1.1:4501"))
+
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					Expect(processGroup.IsMarkedForRemoval()).To(Equal(processGroup.ProcessGroupID == processGroupID))
+				}
+			})
+
+			It("should only keep the operations of the cluster in the work journal", func() {
+				Expect(requeue).To(BeNil())
+				Expect(adminClient.WorkJournal.PendingExclusions).To(ConsistOf(processGroupID))
+			})
+		})
+	})
+})
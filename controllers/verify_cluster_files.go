@@ -0,0 +1,141 @@
+/*
+ * verify_cluster_files.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// verifyClusterFiles provides a reconciliation step for periodically verifying that the cluster file of every Pod
+// matches the connection string of the cluster.
+type verifyClusterFiles struct{}
+
+// reconcile runs the reconciler's work.
+func (verifyClusterFiles) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !cluster.ClusterFileVerificationEnabled() {
+		originalStatus := cluster.Status.DeepCopy()
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			processGroup.UpdateCondition(fdbv1beta2.IncorrectClusterFile, false, nil, "")
+		}
+		cluster.Status.LastClusterFileVerification = nil
+
+		if equality.Semantic.DeepEqual(cluster.Status, *originalStatus) {
+			return nil
+		}
+
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return nil
+	}
+
+	lastVerification := cluster.Status.LastClusterFileVerification
+	if lastVerification != nil && time.Since(lastVerification.Time) < cluster.GetClusterFileVerificationInterval() {
+		return nil
+	}
+
+	if cluster.Status.ConnectionString == "" {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "verifyClusterFiles")
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	podMap := internal.CreatePodMap(cluster, pods)
+	connectionString := cluster.GetDesiredConnectionString()
+	var incorrectPods []string
+	var errs []error
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		curLogger := logger.WithValues("processGroupID", processGroup.ProcessGroupID)
+		if processGroup.IsMarkedForRemoval() || processGroup.GetConditionTime(fdbv1beta2.ResourcesTerminating) != nil {
+			continue
+		}
+
+		pod, ok := podMap[processGroup.ProcessGroupID]
+		if !ok || pod == nil {
+			continue
+		}
+
+		podClient, message := r.getPodClient(cluster, pod)
+		if podClient == nil {
+			curLogger.Info("Unable to generate pod client", "message", message)
+			continue
+		}
+
+		correct, err := podClient.CheckHash(ctx, "fdb.cluster", connectionString)
+		if err != nil {
+			curLogger.Error(err, "Error when verifying cluster file")
+			errs = append(errs, err)
+			continue
+		}
+
+		if !correct && cluster.FixIncorrectClusterFiles() {
+			curLogger.Info("Updating incorrect cluster file", "pod", pod.Name)
+			correct, err = podClient.UpdateFile(ctx, "fdb.cluster", connectionString)
+			if err != nil {
+				curLogger.Error(err, "Error when updating cluster file")
+				errs = append(errs, err)
+			}
+		}
+
+		if !correct {
+			curLogger.Info("Detected incorrect cluster file", "pod", pod.Name)
+			incorrectPods = append(incorrectPods, pod.Name)
+		}
+
+		processGroup.UpdateCondition(fdbv1beta2.IncorrectClusterFile, !correct, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+	}
+
+	if len(incorrectPods) > 0 {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "IncorrectClusterFile", fmt.Sprintf("Pods with incorrect cluster file: %s", strings.Join(incorrectPods, ", ")))
+	}
+
+	// If an error occurred the verification will be retried during the next reconciliation.
+	if len(errs) == 0 {
+		cluster.Status.LastClusterFileVerification = &metav1.Time{Time: time.Now()}
+	}
+
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if len(errs) > 0 {
+		return &requeue{message: "errors occurred during cluster file verification", delayedRequeue: true}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+ * verify_cluster_files_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
+	mockpodclient "github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// staleClusterFilePodClient reports the cluster file of the Pods in the incorrectClusterFiles set as incorrect until
+// the file is updated.
+type staleClusterFilePodClient struct {
+	podclient.FdbPodClient
+	pod                   *corev1.Pod
+	incorrectClusterFiles map[string]fdbv1beta2.None
+}
+
+// CheckHash checks whether a file has the expected contents.
+func (client staleClusterFilePodClient) CheckHash(_ context.Context, _ string, _ string) (bool, error) {
+	_, incorrect := client.incorrectClusterFiles[client.pod.Name]
+	return !incorrect, nil
+}
+
+// UpdateFile checks if a file is up-to-date and tries to update it.
+func (client staleClusterFilePodClient) UpdateFile(_ context.Context, _ string, _ string) (bool, error) {
+	delete(client.incorrectClusterFiles, client.pod.Name)
+	return true, nil
+}
+
+var _ = Describe("verify_cluster_files", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var result *requeue
+	var incorrectClusterFiles map[string]fdbv1beta2.None
+
+	getProcessGroupsWithIncorrectClusterFile := func() []fdbv1beta2.ProcessGroupID {
+		var processGroupIDs []fdbv1beta2.ProcessGroupID
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			if processGroup.GetConditionTime(fdbv1beta2.IncorrectClusterFile) != nil {
+				processGroupIDs = append(processGroupIDs, processGroup.ProcessGroupID)
+			}
+		}
+
+		return processGroupIDs
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+		cluster.Spec.AutomationOptions.ClusterFileVerificationOptions.Enabled = pointer.Bool(true)
+
+		incorrectClusterFiles = map[string]fdbv1beta2.None{}
+		clusterReconciler.PodClientProvider = func(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, error) {
+			podClient, err := mockpodclient.NewMockFdbPodClient(cluster, pod)
+			if err != nil {
+				return nil, err
+			}
+
+			return staleClusterFilePodClient{FdbPodClient: podClient, pod: pod, incorrectClusterFiles: incorrectClusterFiles}, nil
+		}
+	})
+
+	AfterEach(func() {
+		clusterReconciler.PodClientProvider = mockpodclient.NewMockFdbPodClient
+	})
+
+	JustBeforeEach(func() {
+		result = verifyClusterFiles{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		_, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("all cluster files are correct", func() {
+		It("should record the verification", func() {
+			Expect(result).To(BeNil())
+			Expect(getProcessGroupsWithIncorrectClusterFile()).To(BeEmpty())
+			Expect(cluster.Status.LastClusterFileVerification).NotTo(BeNil())
+		})
+	})
+
+	When("a Pod has an incorrect cluster file", func() {
+		BeforeEach(func() {
+			incorrectClusterFiles["operator-test-1-storage-1"] = fdbv1beta2.None{}
+		})
+
+		It("should add the condition to the process group", func() {
+			Expect(result).To(BeNil())
+			Expect(getProcessGroupsWithIncorrectClusterFile()).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-1")))
+			Expect(incorrectClusterFiles).To(HaveKey("operator-test-1-storage-1"))
+		})
+
+		When("the operator should fix incorrect cluster files", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ClusterFileVerificationOptions.FixIncorrectClusterFiles = pointer.Bool(true)
+			})
+
+			It("should update the cluster file", func() {
+				Expect(result).To(BeNil())
+				Expect(getProcessGroupsWithIncorrectClusterFile()).To(BeEmpty())
+				Expect(incorrectClusterFiles).To(BeEmpty())
+			})
+		})
+
+		When("the last verification is more recent than the interval", func() {
+			BeforeEach(func() {
+				cluster.Status.LastClusterFileVerification = &metav1.Time{Time: time.Now().Add(-1 * time.Minute)}
+			})
+
+			It("should not verify the cluster files", func() {
+				Expect(result).To(BeNil())
+				Expect(getProcessGroupsWithIncorrectClusterFile()).To(BeEmpty())
+			})
+		})
+	})
+
+	When("the verification is disabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.ClusterFileVerificationOptions.Enabled = nil
+			cluster.Status.ProcessGroups[0].UpdateCondition(fdbv1beta2.IncorrectClusterFile, true, nil, "")
+			cluster.Status.LastClusterFileVerification = &metav1.Time{Time: time.Now()}
+		})
+
+		It("should remove the conditions and the last verification", func() {
+			Expect(result).To(BeNil())
+			Expect(getProcessGroupsWithIncorrectClusterFile()).To(BeEmpty())
+			Expect(cluster.Status.LastClusterFileVerification).To(BeNil())
+		})
+	})
+})
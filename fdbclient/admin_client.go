@@ -39,6 +39,8 @@ import (
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -85,6 +87,10 @@ type cliAdminClient struct {
 	// the mock runner we can define mocked output for better integration tests,.
 	cmdRunner commandRunner
 
+	// commandStartupTimeout is added to the timeout of every command to give the command runner time to start the
+	// command, e.g. when the command is run in a Pod.
+	commandStartupTimeout time.Duration
+
 	// fdbLibClient is an interface to interact with a FDB cluster over the FDB client libraries. In the real fdb lib client
 	// we will issue the actual requests against FDB. In the mock runner we will return predefined output.
 	fdbLibClient fdbLibClient
@@ -110,13 +116,53 @@ func NewCliAdminClient(cluster *fdbv1beta2.FoundationDBCluster, _ client.Client,
 	}, nil
 }
 
-// getMaxTimeout returns the maximum timeout, this is either the default of 40 seconds or if the provided default timeout
-// is higher it will be the default cli timeout.
-func (client *cliAdminClient) getMaxTimeout() time.Duration {
-	if DefaultCLITimeout > 40*time.Second {
+// NewPodCliAdminClient generates an Admin client for a cluster that runs the CLI commands in short-lived Pods in the
+// namespace of the cluster.
+func NewPodCliAdminClient(cluster *fdbv1beta2.FoundationDBCluster, kubeClient client.Client, kubeClientSet kubernetes.Interface, log logr.Logger) (fdbadminclient.AdminClient, error) {
+	clusterFile, err := createClusterFile(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name)
+	return &cliAdminClient{
+		Cluster:         cluster,
+		clusterFilePath: clusterFile,
+		log:             logger,
+		cmdRunner: &podCommandRunner{
+			log:             logger,
+			cluster:         cluster,
+			kubeClient:      kubeClient,
+			kubeClientSet:   kubeClientSet,
+			clusterFilePath: clusterFile,
+			pollInterval:    time.Second,
+		},
+		commandStartupTimeout: DefaultCommandPodStartupTimeout,
+		fdbLibClient: &realFdbLibClient{
+			cluster: cluster,
+			logger:  logger,
+		},
+	}, nil
+}
+
+// getCommandTimeout returns the timeout for a single command against the cluster. This is either the timeout defined
+// in the cluster spec or the default cli timeout.
+func (client *cliAdminClient) getCommandTimeout() time.Duration {
+	if client.Cluster == nil {
 		return DefaultCLITimeout
 	}
 
+	return client.Cluster.GetCommandTimeout(DefaultCLITimeout)
+}
+
+// getMaxTimeout returns the maximum timeout, this is either the default of 40 seconds or if the command timeout
+// is higher it will be the command timeout.
+func (client *cliAdminClient) getMaxTimeout() time.Duration {
+	commandTimeout := client.getCommandTimeout()
+	if commandTimeout > 40*time.Second {
+		return commandTimeout
+	}
+
 	return 40 * time.Second
 }
 
@@ -163,15 +209,6 @@ func (command cliCommand) getClusterFileFlag() string {
 	return "-C"
 }
 
-// getTimeout returns the timeout for the command
-func (command cliCommand) getTimeout() time.Duration {
-	if command.timeout != 0 {
-		return command.timeout
-	}
-
-	return DefaultCLITimeout
-}
-
 // getVersion returns the versions defined in the command or if not present returns the running version of the
 // cluster.
 func (command cliCommand) getVersion(cluster *fdbv1beta2.FoundationDBCluster) string {
@@ -202,6 +239,15 @@ func getBinaryPath(binaryName string, version string) string {
 	return path.Join(os.Getenv("FDB_BINARY_DIR"), parsed.GetBinaryVersion(), binaryName)
 }
 
+// getTimeout returns the timeout for the command, if unset the command timeout of the cluster will be returned.
+func (client *cliAdminClient) getTimeout(command cliCommand) time.Duration {
+	if command.timeout != 0 {
+		return command.timeout
+	}
+
+	return client.getCommandTimeout()
+}
+
 func (client *cliAdminClient) getArgsAndTimeout(command cliCommand) ([]string, time.Duration) {
 	args := make([]string, len(command.args))
 	copy(args, command.args)
@@ -231,26 +277,34 @@ func (client *cliAdminClient) getArgsAndTimeout(command cliCommand) ([]string, t
 		args = append(args, command.getLogDirParameter(), traceDir)
 	}
 
-	hardTimeout := command.getTimeout()
+	timeout := client.getTimeout(command)
+	hardTimeout := timeout
 	if command.hasTimeoutArg() {
-		args = append(args, "--timeout", strconv.Itoa(int(command.getTimeout().Seconds())))
-		hardTimeout += command.getTimeout()
+		args = append(args, "--timeout", strconv.Itoa(int(timeout.Seconds())))
+		hardTimeout += timeout
 	}
 
 	return args, hardTimeout
 }
 
 // runCommand executes a command in the CLI.
-func (client *cliAdminClient) runCommand(command cliCommand) (string, error) {
+func (client *cliAdminClient) runCommand(ctx context.Context, command cliCommand) (string, error) {
 	args, hardTimeout := client.getArgsAndTimeout(command)
-	timeoutContext, cancelFunction := context.WithTimeout(context.Background(), hardTimeout)
+	timeoutContext, cancelFunction := context.WithTimeout(ctx, hardTimeout+client.commandStartupTimeout)
 	defer cancelFunction()
 
-	output, err := client.cmdRunner.runCommand(timeoutContext, getBinaryPath(command.getBinary(), command.getVersion(client.Cluster)), args...)
+	version := command.getVersion(client.Cluster)
+	output, err := client.cmdRunner.runCommand(timeoutContext, version, getBinaryPath(command.getBinary(), version), args...)
 	if err != nil {
+		// If the parent context was cancelled, e.g. because the reconciliation was superseded, report the cancellation
+		// instead of the error from the killed command.
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
 		var exitError *exec.ExitError
 		if errors.As(err, &exitError) {
-			client.log.Error(exitError, "Error from FDB command", "code", exitError.ProcessState.ExitCode(), "stdout", string(output), "stderr", string(exitError.Stderr))
+			client.log.Error(exitError, "Error from FDB command", "code", exitError.ProcessState.ExitCode(), "stdout", internal.RedactString(string(output)), "stderr", internal.RedactString(string(exitError.Stderr)))
 		}
 
 		// If we hit a timeout report it as a timeout error
@@ -271,14 +325,14 @@ func (client *cliAdminClient) runCommand(command cliCommand) (string, error) {
 	} else {
 		debugOutput = outputString
 	}
-	client.log.Info("Command completed", "output", debugOutput)
+	client.log.Info("Command completed", "output", internal.RedactString(debugOutput))
 
 	return outputString, nil
 }
 
 // runCommandWithBackoff is a wrapper around runCommand which allows retrying commands if they hit a timeout.
-func (client *cliAdminClient) runCommandWithBackoff(command string) (string, error) {
-	currentTimeout := DefaultCLITimeout
+func (client *cliAdminClient) runCommandWithBackoff(ctx context.Context, command string) (string, error) {
+	currentTimeout := client.getCommandTimeout()
 
 	var rawResult string
 	var err error
@@ -288,7 +342,7 @@ func (client *cliAdminClient) runCommandWithBackoff(command string) (string, err
 	// seen that during upgrades of version incompatible version, when not all coordinators are properly restarted that
 	// the response time will be increased.
 	for currentTimeout <= client.getMaxTimeout() {
-		rawResult, err = client.runCommand(cliCommand{command: command, timeout: currentTimeout})
+		rawResult, err = client.runCommand(ctx, cliCommand{command: command, timeout: currentTimeout})
 		if err == nil {
 			break
 		}
@@ -308,8 +362,8 @@ func (client *cliAdminClient) runCommandWithBackoff(command string) (string, err
 }
 
 // getStatusFromCli uses the fdbcli to connect to the FDB cluster
-func (client *cliAdminClient) getStatusFromCli() (*fdbv1beta2.FoundationDBStatus, error) {
-	output, err := client.runCommandWithBackoff("status json")
+func (client *cliAdminClient) getStatusFromCli(ctx context.Context) (*fdbv1beta2.FoundationDBStatus, error) {
+	output, err := client.runCommandWithBackoff(ctx, "status json")
 	if err != nil {
 		return nil, err
 	}
@@ -319,19 +373,13 @@ func (client *cliAdminClient) getStatusFromCli() (*fdbv1beta2.FoundationDBStatus
 		return nil, err
 	}
 
-	status := &fdbv1beta2.FoundationDBStatus{}
-	err = json.Unmarshal(contents, status)
-	if err != nil {
-		return nil, err
-	}
-
-	return status, nil
+	return parseStatus(contents, client.log)
 }
 
 // getStatus uses fdbcli to connect to the FDB cluster, if the cluster is upgraded and the initial version returns no processes
 // the new version for fdbcli will be tried.
-func (client *cliAdminClient) getStatus() (*fdbv1beta2.FoundationDBStatus, error) {
-	status, err := client.getStatusFromCli()
+func (client *cliAdminClient) getStatus(ctx context.Context) (*fdbv1beta2.FoundationDBStatus, error) {
+	status, err := client.getStatusFromCli(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -344,39 +392,39 @@ func (client *cliAdminClient) getStatus() (*fdbv1beta2.FoundationDBStatus, error
 		clusterCopy.Status.RunningVersion = clusterCopy.Spec.Version
 		client.Cluster = clusterCopy
 
-		return client.getStatusFromCli()
+		return client.getStatusFromCli(ctx)
 	}
 
 	return status, nil
 }
 
 // GetStatus gets the database's status
-func (client *cliAdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
+func (client *cliAdminClient) GetStatus(ctx context.Context) (*fdbv1beta2.FoundationDBStatus, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
 	// This will call directly the database and fetch the status information from the system key space.
-	status, err := getStatusFromDB(client.fdbLibClient)
+	status, err := getStatusFromDB(ctx, client.fdbLibClient, client.log, client.getCommandTimeout())
 	// There is a limitation in the multi version client if the cluster is only partially upgraded e.g. because not
 	// all fdbserver processes are restarted, then the multi version client sometimes picks the wrong version
 	// to connect to the cluster. This will result in an empty status only reporting the unreachable coordinators.
 	// In this case we want to fall back to use fdbcli which is version specific and will (hopefully) work.
 	// If we hit a timeout we will also use fdbcli to retry the get status command.
-	client.log.V(1).Info("Result from multi version client (bindings)", "error", err, "status", status)
+	client.log.V(1).Info("Result from multi version client (bindings)", "error", err, "status", internal.RedactStatus(status))
 	if client.Cluster.Status.Configured {
 		if (err != nil && internal.IsTimeoutError(err)) || (status == nil || len(status.Cluster.Processes) == 0) {
 			client.log.Info("retry fetching status with fdbcli instead of using the client library")
-			status, err = client.getStatus()
+			status, err = client.getStatus(ctx)
 		}
 	}
 
-	client.log.V(1).Info("Completed GetStatus() call", "error", err, "status", status)
+	client.log.V(1).Info("Completed GetStatus() call", "error", err, "status", internal.RedactStatus(status))
 
 	return status, err
 }
 
 // ConfigureDatabase sets the database configuration
-func (client *cliAdminClient) ConfigureDatabase(configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) error {
+func (client *cliAdminClient) ConfigureDatabase(ctx context.Context, configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) error {
 	configurationString, err := configuration.GetConfigurationString(version)
 	if err != nil {
 		return err
@@ -386,14 +434,14 @@ func (client *cliAdminClient) ConfigureDatabase(configuration fdbv1beta2.Databas
 		configurationString = "new " + configurationString
 	}
 
-	_, err = client.runCommand(cliCommand{command: fmt.Sprintf("configure %s", configurationString)})
+	_, err = client.runCommand(ctx, cliCommand{command: fmt.Sprintf("configure %s", configurationString)})
 	return err
 }
 
 // GetMaintenanceZone gets current maintenance zone, if any. Returns empty string if maintenance mode is off
-func (client *cliAdminClient) GetMaintenanceZone() (string, error) {
+func (client *cliAdminClient) GetMaintenanceZone(ctx context.Context) (string, error) {
 	// TODO: Use special keyspace to just read the maintenance zone info instead of reading the whole status
-	status, err := client.GetStatus()
+	status, err := client.GetStatus(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -401,8 +449,8 @@ func (client *cliAdminClient) GetMaintenanceZone() (string, error) {
 }
 
 // SetMaintenanceZone places zone into maintenance mode
-func (client *cliAdminClient) SetMaintenanceZone(zone string, timeoutSeconds int) error {
-	_, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) SetMaintenanceZone(ctx context.Context, zone string, timeoutSeconds int) error {
+	_, err := client.runCommand(ctx, cliCommand{
 		command: fmt.Sprintf(
 			"maintenance on %s %s",
 			zone,
@@ -412,15 +460,15 @@ func (client *cliAdminClient) SetMaintenanceZone(zone string, timeoutSeconds int
 }
 
 // ResetMaintenanceMode switches of maintenance mode
-func (client *cliAdminClient) ResetMaintenanceMode() error {
-	_, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) ResetMaintenanceMode(ctx context.Context) error {
+	_, err := client.runCommand(ctx, cliCommand{
 		command: "maintenance off",
 	})
 	return err
 }
 
 // ExcludeProcesses starts evacuating processes so that they can be removed from the database.
-func (client *cliAdminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+func (client *cliAdminClient) ExcludeProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error {
 	if len(addresses) == 0 {
 		return nil
 	}
@@ -438,27 +486,153 @@ func (client *cliAdminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAdd
 
 	excludeCommand.WriteString(fdbv1beta2.ProcessAddressesString(addresses, " "))
 
-	_, err = client.runCommand(cliCommand{command: excludeCommand.String(), timeout: client.getMaxTimeout()})
+	_, err = client.runCommand(ctx, cliCommand{command: excludeCommand.String(), timeout: client.getMaxTimeout()})
 
 	return err
 }
 
 // IncludeProcesses removes processes from the exclusion list and allows them to take on roles again.
-func (client *cliAdminClient) IncludeProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+func (client *cliAdminClient) IncludeProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error {
 	if len(addresses) == 0 {
 		return nil
 	}
-	_, err := client.runCommand(cliCommand{command: fmt.Sprintf(
+	_, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf(
 		"include %s",
 		fdbv1beta2.ProcessAddressesString(addresses, " "),
 	)})
 	return err
 }
 
+// CreateTenant creates a new tenant with the provided name.
+func (client *cliAdminClient) CreateTenant(ctx context.Context, name string) error {
+	_, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf("createtenant %s", name)})
+	return err
+}
+
+// DeleteTenant deletes the tenant with the provided name. The tenant must be empty.
+func (client *cliAdminClient) DeleteTenant(ctx context.Context, name string) error {
+	_, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf("deletetenant %s", name)})
+	return err
+}
+
+// GetTagQuota returns the throughput quota of the provided transaction tag.
+func (client *cliAdminClient) GetTagQuota(ctx context.Context, tag string) (fdbv1beta2.TagQuota, error) {
+	quota := fdbv1beta2.TagQuota{Tag: tag}
+
+	output, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf("quota get %s total_throughput", tag)})
+	if err != nil {
+		return quota, err
+	}
+
+	quota.TotalThroughput, err = parseTagQuotaValue(output)
+	if err != nil {
+		return quota, err
+	}
+
+	output, err = client.runCommand(ctx, cliCommand{command: fmt.Sprintf("quota get %s reserved_throughput", tag)})
+	if err != nil {
+		return quota, err
+	}
+
+	quota.ReservedThroughput, err = parseTagQuotaValue(output)
+
+	return quota, err
+}
+
+// SetTagQuota sets the throughput quota of a transaction tag. If both throughput values are 0, the quota
+// will be cleared.
+func (client *cliAdminClient) SetTagQuota(ctx context.Context, quota fdbv1beta2.TagQuota) error {
+	if quota.TotalThroughput == 0 && quota.ReservedThroughput == 0 {
+		_, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf("quota clear %s", quota.Tag)})
+		return err
+	}
+
+	_, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf(
+		"quota set %s total_throughput %d; quota set %s reserved_throughput %d",
+		quota.Tag,
+		quota.TotalThroughput,
+		quota.Tag,
+		quota.ReservedThroughput,
+	)})
+
+	return err
+}
+
+// IsDataDistributionEnabled returns true if data distribution is enabled in the cluster.
+func (client *cliAdminClient) IsDataDistributionEnabled(ctx context.Context) (bool, error) {
+	return getDataDistributionModeFromDB(ctx, client.fdbLibClient, client.getCommandTimeout())
+}
+
+// SetDataDistributionMode enables or disables data distribution.
+func (client *cliAdminClient) SetDataDistributionMode(ctx context.Context, enabled bool) error {
+	mode := "off"
+	if enabled {
+		mode = "on"
+	}
+
+	_, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf("datadistribution %s", mode)})
+	return err
+}
+
+// LockDatabase locks the database, so that only lock aware transactions can commit. The returned lock UID
+// must be used to unlock the database.
+func (client *cliAdminClient) LockDatabase(ctx context.Context) (string, error) {
+	output, err := client.runCommand(ctx, cliCommand{command: "lock"})
+	if err != nil {
+		return "", err
+	}
+
+	return parseLockUID(output)
+}
+
+// UnlockDatabase removes the lock with the provided lock UID from the database. The unlock command of fdbcli
+// requires an interactive confirmation, so the lock is removed with the client libraries.
+func (client *cliAdminClient) UnlockDatabase(ctx context.Context, lockUID string) error {
+	return unlockDatabase(ctx, client.fdbLibClient, lockUID, client.getCommandTimeout())
+}
+
+// GetReadVersion returns the current read version of the database. This also works if the database is locked.
+func (client *cliAdminClient) GetReadVersion(ctx context.Context) (int64, error) {
+	output, err := client.runCommand(ctx, cliCommand{command: "option on LOCK_AWARE; getversion"})
+	if err != nil {
+		return 0, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strconv.ParseInt(strings.TrimSpace(lines[len(lines)-1]), 10, 64)
+}
+
+// ProbeLatency measures the latency of getting a read version, reading a key and committing a transaction.
+func (client *cliAdminClient) ProbeLatency(ctx context.Context) (*fdbadminclient.LatencyProbeResult, error) {
+	return probeLatency(ctx, client.fdbLibClient, client.getCommandTimeout())
+}
+
+// GetWorkJournal returns the work journal of the cluster that is stored in the database. If no work journal is
+// stored, nil will be returned.
+func (client *cliAdminClient) GetWorkJournal(ctx context.Context) (*fdbadminclient.WorkJournal, error) {
+	return getWorkJournal(ctx, client.fdbLibClient, client.Cluster, client.getCommandTimeout())
+}
+
+// UpdateWorkJournal stores the work journal of the cluster in the database. An empty work journal will be removed
+// from the database.
+func (client *cliAdminClient) UpdateWorkJournal(ctx context.Context, journal *fdbadminclient.WorkJournal) error {
+	return updateWorkJournal(ctx, client.fdbLibClient, client.Cluster, journal, client.getCommandTimeout())
+}
+
+// ListTenants returns all tenants of the cluster.
+func (client *cliAdminClient) ListTenants(ctx context.Context) ([]fdbv1beta2.TenantStatus, error) {
+	version, err := fdbv1beta2.ParseFdbVersion(client.Cluster.GetRunningVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	return getTenantsFromDB(ctx, client.fdbLibClient, version, client.getCommandTimeout())
+}
+
 // GetExclusions gets a list of the addresses currently excluded from the
 // database.
-func (client *cliAdminClient) GetExclusions() ([]fdbv1beta2.ProcessAddress, error) {
-	status, err := client.GetStatus()
+func (client *cliAdminClient) GetExclusions(ctx context.Context) ([]fdbv1beta2.ProcessAddress, error) {
+	status, err := client.GetStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -569,11 +743,84 @@ func getRemainingAndExcludedFromStatus(status *fdbv1beta2.FoundationDBStatus, ad
 	return exclusions
 }
 
+// filterInProgressExclusions removes the addresses of excluded servers that still hold data from the fully excluded
+// addresses and the addresses that are missing in the status and returns them as not safe to remove. Addresses that are
+// missing in the status and are excluded in the database configuration are safe to remove if they don't hold any data,
+// so the exclude command doesn't have to be issued for them.
+func filterInProgressExclusions(status *fdbv1beta2.FoundationDBStatus, exclusions exclusionStatus, inProgressExclusions []fdbv1beta2.ProcessAddress) (exclusionStatus, []fdbv1beta2.ProcessAddress) {
+	withData := make(map[string]fdbv1beta2.None, 2*len(inProgressExclusions))
+	for _, addr := range inProgressExclusions {
+		withData[addr.StringWithoutFlags()] = fdbv1beta2.None{}
+		withData[addr.MachineAddress()] = fdbv1beta2.None{}
+	}
+
+	excludedServers := make(map[string]fdbv1beta2.None, len(status.Cluster.DatabaseConfiguration.ExcludedServers))
+	for _, excludedServer := range status.Cluster.DatabaseConfiguration.ExcludedServers {
+		if excludedServer.Address != "" {
+			excludedServers[excludedServer.Address] = fdbv1beta2.None{}
+			continue
+		}
+
+		excludedServers[excludedServer.Locality] = fdbv1beta2.None{}
+	}
+
+	var notSafe []fdbv1beta2.ProcessAddress
+	fullyExcluded := make([]fdbv1beta2.ProcessAddress, 0, len(exclusions.fullyExcluded))
+	for _, addr := range exclusions.fullyExcluded {
+		if _, ok := withData[addr.StringWithoutFlags()]; ok {
+			notSafe = append(notSafe, addr)
+			continue
+		}
+
+		fullyExcluded = append(fullyExcluded, addr)
+	}
+
+	missingInStatus := make([]fdbv1beta2.ProcessAddress, 0, len(exclusions.missingInStatus))
+	for _, addr := range exclusions.missingInStatus {
+		if _, ok := withData[addr.StringWithoutFlags()]; ok {
+			notSafe = append(notSafe, addr)
+			continue
+		}
+
+		_, isExcluded := excludedServers[addr.StringWithoutFlags()]
+		if !isExcluded {
+			_, isExcluded = excludedServers[addr.MachineAddress()]
+		}
+
+		if isExcluded {
+			fullyExcluded = append(fullyExcluded, addr)
+			continue
+		}
+
+		missingInStatus = append(missingInStatus, addr)
+	}
+
+	exclusions.fullyExcluded = fullyExcluded
+	exclusions.missingInStatus = missingInStatus
+
+	return exclusions, notSafe
+}
+
+// GetInProgressExclusions returns the addresses of the excluded servers that still hold data or still host a
+// transaction log. This requires FDB 7.0 or newer.
+func (client *cliAdminClient) GetInProgressExclusions(ctx context.Context) ([]fdbv1beta2.ProcessAddress, error) {
+	version, err := fdbv1beta2.ParseFdbVersion(client.Cluster.GetRunningVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if !version.SupportsManagementAPIExclusions() {
+		return nil, fmt.Errorf("version %s doesn't support exclusions in the management API", version)
+	}
+
+	return getInProgressExclusionsFromDB(ctx, client.fdbLibClient, client.getCommandTimeout())
+}
+
 // CanSafelyRemove checks whether it is safe to remove processes from the cluster
 //
 // The list returned by this method will be the addresses that are *not* safe to remove.
-func (client *cliAdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error) {
-	status, err := client.GetStatus()
+func (client *cliAdminClient) CanSafelyRemove(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error) {
+	status, err := client.GetStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -586,17 +833,37 @@ func (client *cliAdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddr
 		"missingInStatus", exclusions.missingInStatus)
 
 	notSafeToDelete := append(exclusions.notExcluded, exclusions.inProgress...)
+
+	version, err := fdbv1beta2.ParseFdbVersion(client.Cluster.GetRunningVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	// Newer versions report the excluded servers that still hold data in the management API, so we don't have to rely
+	// on the roles in the status or on the exclude command to check if the data was moved away.
+	if version.SupportsManagementAPIExclusions() {
+		inProgressExclusions, err := client.GetInProgressExclusions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var notSafe []fdbv1beta2.ProcessAddress
+		exclusions, notSafe = filterInProgressExclusions(status, exclusions, inProgressExclusions)
+		notSafeToDelete = append(notSafeToDelete, notSafe...)
+		client.log.Info("Filtering excluded processes with data", "inProgressExclusions", inProgressExclusions, "notSafe", notSafe)
+	}
+
 	// When we have at least one process that is missing in the status, we have to issue the exclude command to make sure, that those
 	// missing processes can be removed or not.
 	if len(exclusions.missingInStatus) > 0 {
-		err = client.ExcludeProcesses(exclusions.missingInStatus)
+		err = client.ExcludeProcesses(ctx, exclusions.missingInStatus)
 		// When we hit a timeout error here we know that at least one of the missingInStatus is still not fully excluded for safety
 		// we just return the whole slice and don't do any further distinction. We have to return all addresses that are not excluded
 		// and are still in progress, but we don't want to return an error to block further actions on the successfully excluded
 		// addresses.
 		if err != nil {
 			if internal.IsTimeoutError(err) {
-				return append(exclusions.notExcluded, exclusions.missingInStatus...), nil
+				return append(notSafeToDelete, exclusions.missingInStatus...), nil
 			}
 
 			return nil, err
@@ -608,7 +875,7 @@ func (client *cliAdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddr
 }
 
 // KillProcesses restarts processes
-func (client *cliAdminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+func (client *cliAdminClient) KillProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error {
 	if len(addresses) == 0 {
 		return nil
 	}
@@ -617,14 +884,14 @@ func (client *cliAdminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddres
 		"kill; kill %[1]s; sleep 1; kill %[1]s; sleep 5",
 		fdbv1beta2.ProcessAddressesStringWithoutFlags(addresses, " "),
 	)
-	_, err := client.runCommandWithBackoff(killCommand)
+	_, err := client.runCommandWithBackoff(ctx, killCommand)
 
 	return err
 }
 
 // ChangeCoordinators changes the coordinator set
-func (client *cliAdminClient) ChangeCoordinators(addresses []fdbv1beta2.ProcessAddress) (string, error) {
-	_, err := client.runCommand(cliCommand{command: fmt.Sprintf(
+func (client *cliAdminClient) ChangeCoordinators(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) (string, error) {
+	_, err := client.runCommand(ctx, cliCommand{command: fmt.Sprintf(
 		"coordinators %s",
 		fdbv1beta2.ProcessAddressesString(addresses, " "),
 	)})
@@ -644,6 +911,80 @@ func (client *cliAdminClient) ChangeCoordinators(addresses []fdbv1beta2.ProcessA
 	return connectionString.String(), nil
 }
 
+// ChangeClusterDescription changes the description in the connection string while keeping the current coordinators.
+func (client *cliAdminClient) ChangeClusterDescription(ctx context.Context, description string) (string, error) {
+	connectionString, err := fdbv1beta2.ParseConnectionString(client.Cluster.Status.ConnectionString)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = client.runCommand(ctx, cliCommand{command: fmt.Sprintf(
+		"coordinators %s description=%s",
+		strings.Join(connectionString.Coordinators, " "),
+		description,
+	)})
+	if err != nil {
+		return "", err
+	}
+
+	connectionStringBytes, err := os.ReadFile(client.clusterFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	connectionString, err = fdbv1beta2.ParseConnectionString(string(connectionStringBytes))
+	if err != nil {
+		return "", err
+	}
+	return connectionString.String(), nil
+}
+
+// parseTagQuotaValue parses the output of the quota get command. If no quota is set, 0 will be returned.
+func parseTagQuotaValue(output string) (int64, error) {
+	value := strings.TrimSpace(output)
+	if value == "<empty>" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// parseTenantMetadata parses the metadata of a tenant from the tenant map in the management module.
+func parseTenantMetadata(name string, value []byte) (fdbv1beta2.TenantStatus, error) {
+	metadata := struct {
+		ID     int64           `json:"id"`
+		Prefix json.RawMessage `json:"prefix"`
+	}{}
+
+	err := json.Unmarshal(value, &metadata)
+	if err != nil {
+		return fdbv1beta2.TenantStatus{}, err
+	}
+
+	tenant := fdbv1beta2.TenantStatus{
+		Name: name,
+		ID:   metadata.ID,
+	}
+
+	// Older versions report the prefix as string, newer versions report an object with different representations.
+	var prefix string
+	if json.Unmarshal(metadata.Prefix, &prefix) == nil {
+		tenant.Prefix = fdb.Printable([]byte(prefix))
+		return tenant, nil
+	}
+
+	prefixObject := struct {
+		Printable string `json:"printable"`
+	}{}
+	err = json.Unmarshal(metadata.Prefix, &prefixObject)
+	if err != nil {
+		return fdbv1beta2.TenantStatus{}, err
+	}
+	tenant.Prefix = prefixObject.Printable
+
+	return tenant, nil
+}
+
 // cleanConnectionStringOutput is a helper method to remove unrelated output from the get command in the connection string
 // output.
 func cleanConnectionStringOutput(input string) string {
@@ -657,10 +998,10 @@ func cleanConnectionStringOutput(input string) string {
 }
 
 // GetConnectionString fetches the latest connection string.
-func (client *cliAdminClient) GetConnectionString() (string, error) {
+func (client *cliAdminClient) GetConnectionString(ctx context.Context) (string, error) {
 	// This will call directly the database and fetch the connection string
 	// from the system key space.
-	outputBytes, err := getConnectionStringFromDB(client.fdbLibClient)
+	outputBytes, err := getConnectionStringFromDB(ctx, client.fdbLibClient, client.getCommandTimeout())
 
 	if err != nil {
 		return "", err
@@ -676,7 +1017,7 @@ func (client *cliAdminClient) GetConnectionString() (string, error) {
 
 // VersionSupported reports whether we can support a cluster with a given
 // version.
-func (client *cliAdminClient) VersionSupported(versionString string) (bool, error) {
+func (client *cliAdminClient) VersionSupported(_ context.Context, versionString string) (bool, error) {
 	version, err := fdbv1beta2.ParseFdbVersion(versionString)
 	if err != nil {
 		return false, err
@@ -686,6 +1027,11 @@ func (client *cliAdminClient) VersionSupported(versionString string) (bool, erro
 		return false, nil
 	}
 
+	// If the commands are run in Pods the binaries are provided by the image of the command Pod.
+	if _, ok := client.cmdRunner.(*podCommandRunner); ok {
+		return true, nil
+	}
+
 	_, err = os.Stat(getBinaryPath(fdbcliStr, versionString))
 	if err != nil {
 		return false, err
@@ -696,8 +1042,8 @@ func (client *cliAdminClient) VersionSupported(versionString string) (bool, erro
 
 // GetProtocolVersion determines the protocol version that is used by a
 // version of FDB.
-func (client *cliAdminClient) GetProtocolVersion(version string) (string, error) {
-	output, err := client.runCommand(cliCommand{args: []string{"--version"}, version: version})
+func (client *cliAdminClient) GetProtocolVersion(ctx context.Context, version string) (string, error) {
+	output, err := client.runCommand(ctx, cliCommand{args: []string{"--version"}, version: version})
 	if err != nil {
 		return "", err
 	}
@@ -711,24 +1057,30 @@ func (client *cliAdminClient) GetProtocolVersion(version string) (string, error)
 	return protocolVersionMatch[1], nil
 }
 
-func (client *cliAdminClient) StartBackup(url string, snapshotPeriodSeconds int) error {
-	_, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) StartBackup(ctx context.Context, url string, snapshotPeriodSeconds int, stopAfterSnapshot bool) error {
+	args := []string{
+		"start",
+		"-d",
+		url,
+		"-s",
+		fmt.Sprintf("%d", snapshotPeriodSeconds),
+	}
+
+	// Without the -z flag the backup stops once it is restorable.
+	if !stopAfterSnapshot {
+		args = append(args, "-z")
+	}
+
+	_, err := client.runCommand(ctx, cliCommand{
 		binary: fdbbackupStr,
-		args: []string{
-			"start",
-			"-d",
-			url,
-			"-s",
-			fmt.Sprintf("%d", snapshotPeriodSeconds),
-			"-z",
-		},
+		args:   args,
 	})
 	return err
 }
 
 // StopBackup stops a backup.
-func (client *cliAdminClient) StopBackup(_ string) error {
-	_, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) StopBackup(ctx context.Context, _ string) error {
+	_, err := client.runCommand(ctx, cliCommand{
 		binary: fdbbackupStr,
 		args: []string{
 			"discontinue",
@@ -738,8 +1090,8 @@ func (client *cliAdminClient) StopBackup(_ string) error {
 }
 
 // PauseBackups pauses the backups.
-func (client *cliAdminClient) PauseBackups() error {
-	_, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) PauseBackups(ctx context.Context) error {
+	_, err := client.runCommand(ctx, cliCommand{
 		binary: fdbbackupStr,
 		args: []string{
 			"pause",
@@ -749,8 +1101,8 @@ func (client *cliAdminClient) PauseBackups() error {
 }
 
 // ResumeBackups resumes the backups.
-func (client *cliAdminClient) ResumeBackups() error {
-	_, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) ResumeBackups(ctx context.Context) error {
+	_, err := client.runCommand(ctx, cliCommand{
 		binary: fdbbackupStr,
 		args: []string{
 			"resume",
@@ -760,8 +1112,8 @@ func (client *cliAdminClient) ResumeBackups() error {
 }
 
 // ModifyBackup updates the backup parameters.
-func (client *cliAdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
-	_, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) ModifyBackup(ctx context.Context, snapshotPeriodSeconds int) error {
+	_, err := client.runCommand(ctx, cliCommand{
 		binary: fdbbackupStr,
 		args: []string{
 			"modify",
@@ -773,8 +1125,8 @@ func (client *cliAdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
 }
 
 // GetBackupStatus gets the status of the current backup.
-func (client *cliAdminClient) GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBackupStatus, error) {
-	statusString, err := client.runCommand(cliCommand{
+func (client *cliAdminClient) GetBackupStatus(ctx context.Context) (*fdbv1beta2.FoundationDBLiveBackupStatus, error) {
+	statusString, err := client.runCommand(ctx, cliCommand{
 		binary: fdbbackupStr,
 		args: []string{
 			"status",
@@ -801,7 +1153,7 @@ func (client *cliAdminClient) GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBac
 }
 
 // StartRestore starts a new restore.
-func (client *cliAdminClient) StartRestore(url string, keyRanges []fdbv1beta2.FoundationDBKeyRange) error {
+func (client *cliAdminClient) StartRestore(ctx context.Context, url string, keyRanges []fdbv1beta2.FoundationDBKeyRange, addPrefix string, removePrefix string) error {
 	args := []string{
 		"start",
 		"-r",
@@ -818,7 +1170,16 @@ func (client *cliAdminClient) StartRestore(url string, keyRanges []fdbv1beta2.Fo
 		}
 		args = append(args, "-k", keyRangeString)
 	}
-	_, err := client.runCommand(cliCommand{
+
+	if addPrefix != "" {
+		args = append(args, "--add_prefix", addPrefix)
+	}
+
+	if removePrefix != "" {
+		args = append(args, "--remove_prefix", removePrefix)
+	}
+
+	_, err := client.runCommand(ctx, cliCommand{
 		binary: fdbrestoreStr,
 		args:   args,
 	})
@@ -826,8 +1187,8 @@ func (client *cliAdminClient) StartRestore(url string, keyRanges []fdbv1beta2.Fo
 }
 
 // GetRestoreStatus gets the status of the current restore.
-func (client *cliAdminClient) GetRestoreStatus() (string, error) {
-	return client.runCommand(cliCommand{
+func (client *cliAdminClient) GetRestoreStatus(ctx context.Context) (string, error) {
+	return client.runCommand(ctx, cliCommand{
 		binary: fdbrestoreStr,
 		args: []string{
 			"status",
@@ -842,8 +1203,8 @@ func (client *cliAdminClient) Close() error {
 }
 
 // GetCoordinatorSet gets the current coordinators from the status
-func (client *cliAdminClient) GetCoordinatorSet() (map[string]fdbv1beta2.None, error) {
-	status, err := client.GetStatus()
+func (client *cliAdminClient) GetCoordinatorSet(ctx context.Context) (map[string]fdbv1beta2.None, error) {
+	status, err := client.GetStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
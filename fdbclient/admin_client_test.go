@@ -21,6 +21,7 @@
 package fdbclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -175,6 +176,74 @@ var _ = Describe("admin_client_test", func() {
 		)
 	})
 
+	When("parsing the tag quota value", func() {
+		DescribeTable("it should return the correct value",
+			func(input string, expected int64, expectedErr bool) {
+				value, err := parseTagQuotaValue(input)
+				if expectedErr {
+					Expect(err).To(HaveOccurred())
+					return
+				}
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(value).To(Equal(expected))
+			},
+			Entry("without a quota",
+				"<empty>\n",
+				int64(0),
+				false,
+			),
+			Entry("with a quota",
+				"1000\n",
+				int64(1000),
+				false,
+			),
+			Entry("with an invalid output",
+				"ERROR: Unknown tag\n",
+				int64(0),
+				true,
+			),
+		)
+	})
+
+	When("parsing the tenant metadata", func() {
+		DescribeTable("it should return the correct tenant information",
+			func(input string, expected fdbv1beta2.TenantStatus, expectedErr bool) {
+				tenant, err := parseTenantMetadata("tenant1", []byte(input))
+				if expectedErr {
+					Expect(err).To(HaveOccurred())
+					return
+				}
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tenant).To(Equal(expected))
+			},
+			Entry("with the prefix as string",
+				`{"id":1,"prefix":"\u0000\u0000\u0000\u0000\u0000\u0000\u0000\u0001"}`,
+				fdbv1beta2.TenantStatus{
+					Name:   "tenant1",
+					ID:     1,
+					Prefix: "\\x00\\x00\\x00\\x00\\x00\\x00\\x00\\x01",
+				},
+				false,
+			),
+			Entry("with the prefix as object",
+				`{"id":2,"prefix":{"base64":"AAAAAAAAAAI=","printable":"\\x00\\x00\\x00\\x00\\x00\\x00\\x00\\x02"}}`,
+				fdbv1beta2.TenantStatus{
+					Name:   "tenant1",
+					ID:     2,
+					Prefix: "\\x00\\x00\\x00\\x00\\x00\\x00\\x00\\x02",
+				},
+				false,
+			),
+			Entry("with invalid metadata",
+				`not json`,
+				fdbv1beta2.TenantStatus{},
+				true,
+			),
+		)
+	})
+
 	When("getting the log dir parameter", func() {
 		DescribeTable("it should return the correct format of the log dir paramater",
 			func(cmd cliCommand, expected string) {
@@ -417,6 +486,41 @@ var _ = Describe("admin_client_test", func() {
 			})
 		})
 
+		When("the used command doesn't define a timeout and the cluster defines a command timeout", func() {
+			BeforeEach(func() {
+				command = cliCommand{
+					command: "maintenance off",
+				}
+
+				client = &cliAdminClient{
+					Cluster: &fdbv1beta2.FoundationDBCluster{
+						Spec: fdbv1beta2.FoundationDBClusterSpec{
+							AutomationOptions: fdbv1beta2.FoundationDBClusterAutomationOptions{
+								CommandTimeoutSeconds: pointer.Int(3),
+							},
+						},
+					},
+					clusterFilePath: "test",
+					log:             logr.Discard(),
+				}
+
+				expectedTimeout = 6 * time.Second
+				expectedArgs = []string{
+					"--exec",
+					"maintenance off",
+					"-C",
+					"test",
+					"--log",
+					"--timeout",
+					"3",
+				}
+			})
+
+			It("should use the command timeout of the cluster", func() {
+				Expect(timeout).To(Equal(expectedTimeout))
+			})
+		})
+
 		When("the used command is a fdbrestore command", func() {
 			BeforeEach(func() {
 				command = cliCommand{
@@ -488,7 +592,7 @@ var _ = Describe("admin_client_test", func() {
 				cmdRunner:       mockRunner,
 			}
 
-			protocolVersion, err = cliClient.GetProtocolVersion("7.1.21")
+			protocolVersion, err = cliClient.GetProtocolVersion(context.TODO(), "7.1.21")
 		})
 
 		When("the fdbcli call returns the expected output", func() {
@@ -559,7 +663,7 @@ protocol fdb00b071010000`,
 				cmdRunner:       mockRunner,
 			}
 
-			supported, err = cliClient.VersionSupported(fdbv1beta2.Versions.Default.String())
+			supported, err = cliClient.VersionSupported(context.TODO(), fdbv1beta2.Versions.Default.String())
 		})
 
 		When("the binary does not exist", func() {
@@ -620,7 +724,7 @@ protocol fdb00b071010000`,
 				cmdRunner:       mockRunner,
 			}
 
-			status, err = cliClient.getStatus()
+			status, err = cliClient.getStatus(context.TODO())
 		})
 
 		BeforeEach(func() {
@@ -722,7 +826,7 @@ protocol fdb00b071010000`,
 				cmdRunner:       mockRunner,
 			}
 
-			Expect(cliClient.ExcludeProcesses([]fdbv1beta2.ProcessAddress{{
+			Expect(cliClient.ExcludeProcesses(context.TODO(), []fdbv1beta2.ProcessAddress{{
 				IPAddress: net.ParseIP("127.0.0.1"),
 				Port:      4500,
 			}})).NotTo(HaveOccurred())
@@ -765,12 +869,13 @@ protocol fdb00b071010000`,
 		var mockFdbClient *mockFdbLibClient
 		var addressesToCheck []fdbv1beta2.ProcessAddress
 		var result []fdbv1beta2.ProcessAddress
+		var version fdbv1beta2.Version
 		var err error
 
 		JustBeforeEach(func() {
 			cluster := &fdbv1beta2.FoundationDBCluster{
 				Spec: fdbv1beta2.FoundationDBClusterSpec{
-					Version: fdbv1beta2.Versions.Default.String(),
+					Version: version.String(),
 				},
 			}
 
@@ -782,21 +887,32 @@ protocol fdb00b071010000`,
 				fdbLibClient:    mockFdbClient,
 			}
 
-			result, err = cliClient.CanSafelyRemove(addressesToCheck)
+			result, err = cliClient.CanSafelyRemove(context.TODO(), addressesToCheck)
 			Expect(mockFdbClient.requestedKey).To(Equal("\xff\xff/status/json"))
 		})
 
 		BeforeEach(func() {
+			version = fdbv1beta2.Versions.Default
 			tmpDir := GinkgoT().TempDir()
 			GinkgoT().Setenv("FDB_BINARY_DIR", tmpDir)
 
-			binaryDir := path.Join(tmpDir, fdbv1beta2.Versions.Default.GetBinaryVersion())
-			Expect(os.MkdirAll(binaryDir, 0700)).NotTo(HaveOccurred())
-			_, err := os.Create(path.Join(binaryDir, fdbcliStr))
-			Expect(err).NotTo(HaveOccurred())
+			for _, binaryVersion := range []fdbv1beta2.Version{fdbv1beta2.Versions.Default, fdbv1beta2.Versions.SupportsManagementAPIExclusions} {
+				binaryDir := path.Join(tmpDir, binaryVersion.GetBinaryVersion())
+				Expect(os.MkdirAll(binaryDir, 0700)).NotTo(HaveOccurred())
+				_, err := os.Create(path.Join(binaryDir, fdbcliStr))
+				Expect(err).NotTo(HaveOccurred())
+			}
 
 			status := &fdbv1beta2.FoundationDBStatus{
 				Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+					DatabaseConfiguration: fdbv1beta2.DatabaseConfiguration{
+						ExcludedServers: []fdbv1beta2.ExcludedServers{
+							{Address: "192.168.0.1:4500"},
+							{Address: "192.168.0.2:4500"},
+							{Address: "192.168.0.4:4500"},
+							{Address: "192.168.0.6:4500"},
+						},
+					},
 					Processes: map[fdbv1beta2.ProcessGroupID]fdbv1beta2.FoundationDBStatusProcessInfo{
 						"1": { // This process is fully excluded
 							Address: fdbv1beta2.ProcessAddress{
@@ -1042,6 +1158,146 @@ protocol fdb00b071010000`,
 				Expect(err).To(HaveOccurred())
 			})
 		})
+
+		When("the version supports exclusions in the management API", func() {
+			BeforeEach(func() {
+				version = fdbv1beta2.Versions.SupportsManagementAPIExclusions
+				mockRunner.mockedError = nil
+				mockFdbClient.mockedKeys = []string{"192.168.0.2:4500"}
+				addressesToCheck = []fdbv1beta2.ProcessAddress{
+					{
+						IPAddress: net.ParseIP("192.168.0.1"),
+						Port:      4500,
+					},
+					{
+						IPAddress: net.ParseIP("192.168.0.2"),
+						Port:      4500,
+					},
+					{
+						IPAddress: net.ParseIP("192.168.0.6"),
+						Port:      4500,
+					},
+				}
+			})
+
+			It("should read the in progress exclusions from the management API", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mockFdbClient.requestedPrefix).To(Equal("\xff\xff/management/in_progress_exclusion/"))
+			})
+
+			It("should return the process that still holds data", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(ConsistOf(fdbv1beta2.ProcessAddress{
+					IPAddress: net.ParseIP("192.168.0.2"),
+					Port:      4500,
+				}))
+			})
+
+			It("should not issue an exclude command for the excluded process that is missing in the status", func() {
+				Expect(mockRunner.receivedBinary).To(BeEmpty())
+			})
+
+			When("a process that is not excluded is missing in the status", func() {
+				BeforeEach(func() {
+					addressesToCheck = append(addressesToCheck, fdbv1beta2.ProcessAddress{
+						IPAddress: net.ParseIP("192.168.0.5"),
+						Port:      4500,
+					})
+				})
+
+				It("should issue an exclude command for the process", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(mockRunner.receivedArgs).To(ContainElements("exclude 192.168.0.5:4500"))
+					Expect(result).To(ConsistOf(fdbv1beta2.ProcessAddress{
+						IPAddress: net.ParseIP("192.168.0.2"),
+						Port:      4500,
+					}))
+				})
+			})
+
+			When("a process that is missing in the status still holds data", func() {
+				BeforeEach(func() {
+					mockFdbClient.mockedKeys = []string{"192.168.0.6:4500"}
+				})
+
+				It("should return the process", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(ConsistOf(fdbv1beta2.ProcessAddress{
+						IPAddress: net.ParseIP("192.168.0.6"),
+						Port:      4500,
+					}))
+				})
+			})
+		})
+	})
+
+	When("getting the status with a cancelled context", func() {
+		var mockRunner *mockCommandRunner
+		var mockFdbClient *mockFdbLibClient
+		var err error
+
+		BeforeEach(func() {
+			mockRunner = &mockCommandRunner{}
+			mockFdbClient = &mockFdbLibClient{}
+
+			cliClient := &cliAdminClient{
+				Cluster: &fdbv1beta2.FoundationDBCluster{
+					Spec: fdbv1beta2.FoundationDBClusterSpec{
+						Version: "7.1.25",
+						AutomationOptions: fdbv1beta2.FoundationDBClusterAutomationOptions{
+							CommandTimeoutSeconds: pointer.Int(20),
+						},
+					},
+				},
+				clusterFilePath: "test",
+				log:             logr.Discard(),
+				cmdRunner:       mockRunner,
+				fdbLibClient:    mockFdbClient,
+			}
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			_, err = cliClient.GetStatus(ctx)
+		})
+
+		It("should return the error of the context", func() {
+			Expect(err).To(MatchError(context.Canceled))
+		})
+
+		It("should use the command timeout of the cluster", func() {
+			Expect(mockFdbClient.receivedTimeout).To(Equal(20 * time.Second))
+		})
+	})
+
+	When("running a command with a cancelled context", func() {
+		var mockRunner *mockCommandRunner
+		var err error
+
+		BeforeEach(func() {
+			mockRunner = &mockCommandRunner{
+				mockedOutput: "Specified timeout reached",
+			}
+
+			cliClient := &cliAdminClient{
+				Cluster: &fdbv1beta2.FoundationDBCluster{
+					Spec: fdbv1beta2.FoundationDBClusterSpec{
+						Version: "7.1.25",
+					},
+				},
+				clusterFilePath: "test",
+				log:             logr.Discard(),
+				cmdRunner:       mockRunner,
+			}
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			_, err = cliClient.runCommandWithBackoff(ctx, "status json")
+		})
+
+		It("should return the error of the context without retrying", func() {
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(mockRunner.receivedArgs).To(ContainElements("--timeout", "10"))
+		})
 	})
 
 	// TODO(johscheuer): Add test case for timeout.
@@ -23,6 +23,7 @@ package fdbclient
 import (
 	"context"
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/go-logr/logr"
 	"os"
 	"os/exec"
@@ -31,8 +32,9 @@ import (
 
 // commandRunner is an interface to run commands.
 type commandRunner interface {
-	// runCommand is a method to execute a binary with the given arguments.
-	runCommand(ctx context.Context, name string, arg ...string) ([]byte, error)
+	// runCommand is a method to execute a binary with the given arguments. The version is the FDB version the binary
+	// belongs to.
+	runCommand(ctx context.Context, version string, name string, arg ...string) ([]byte, error)
 }
 
 // realCommandRunner is a struct that implements the commandRunner interface and executes commands with the exec package.
@@ -63,10 +65,10 @@ func getEnvironmentVariablesWithoutExcludedFdbEnv() []string {
 	return cmdEnvironmentVariables
 }
 
-func (runner *realCommandRunner) runCommand(ctx context.Context, name string, arg ...string) ([]byte, error) {
+func (runner *realCommandRunner) runCommand(ctx context.Context, _ string, name string, arg ...string) ([]byte, error) {
 	execCommand := exec.CommandContext(ctx, name, arg...)
 	execCommand.Env = getEnvironmentVariablesWithoutExcludedFdbEnv()
-	runner.log.Info("Running command", "path", execCommand.Path, "args", execCommand.Args)
+	runner.log.Info("Running command", "path", execCommand.Path, "args", internal.RedactArguments(execCommand.Args))
 	return execCommand.CombinedOutput()
 }
 
@@ -78,6 +80,8 @@ type mockCommandRunner struct {
 	mockedError error
 	// receivedBinary will be the binary that was used to call runCommand.
 	receivedBinary string
+	// receivedVersion will be the version that was used to call runCommand.
+	receivedVersion string
 	// receivedArgs will be the args that were used to call runCommand.
 	receivedArgs []string
 	// mockedOutputPerBinary is the output returned if the binary is matching. This can be helpful to test the behaviour for
@@ -85,10 +89,15 @@ type mockCommandRunner struct {
 	mockedOutputPerBinary map[string]string
 }
 
-func (runner *mockCommandRunner) runCommand(_ context.Context, name string, arg ...string) ([]byte, error) {
+func (runner *mockCommandRunner) runCommand(ctx context.Context, version string, name string, arg ...string) ([]byte, error) {
 	runner.receivedBinary = name
+	runner.receivedVersion = version
 	runner.receivedArgs = arg
 
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	var mockedOutput string
 	if output, ok := runner.mockedOutputPerBinary[name]; ok {
 		mockedOutput = output
@@ -38,7 +38,7 @@ var _ = Describe("command_runner", func() {
 				log: logr.Discard(),
 			}
 
-			output, err = runner.runCommand(context.TODO(), "echo", "hello")
+			output, err = runner.runCommand(context.TODO(), "", "echo", "hello")
 		})
 
 		It("should execute the command successfully", func() {
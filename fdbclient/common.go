@@ -21,16 +21,22 @@
 package fdbclient
 
 import (
+	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	"github.com/go-logr/logr"
 	"io/fs"
+	"k8s.io/client-go/kubernetes"
 	"os"
 	"path"
+	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
 	"time"
 )
 
@@ -88,29 +94,223 @@ func ensureClusterFileIsPresent(dir string, uid string, connectionString string)
 }
 
 // getConnectionStringFromDB gets the database's connection string directly from the system key
-func getConnectionStringFromDB(libClient fdbLibClient) ([]byte, error) {
-	return libClient.getValueFromDBUsingKey("\xff/coordinators", DefaultCLITimeout)
+func getConnectionStringFromDB(ctx context.Context, libClient fdbLibClient, timeout time.Duration) ([]byte, error) {
+	return libClient.getValueFromDBUsingKey(ctx, "\xff/coordinators", timeout)
+}
+
+// getDataDistributionModeFromDB returns true if data distribution is enabled, based on the data distribution mode
+// in the system key space.
+func getDataDistributionModeFromDB(ctx context.Context, libClient fdbLibClient, timeout time.Duration) (bool, error) {
+	contents, err := libClient.getValueFromDBUsingKey(ctx, "\xff/dataDistributionMode", timeout)
+	if err != nil {
+		return false, err
+	}
+
+	return parseDataDistributionMode(contents)
+}
+
+// parseDataDistributionMode parses the value of the data distribution mode key. If the key is not set, data
+// distribution is enabled.
+func parseDataDistributionMode(value []byte) (bool, error) {
+	if len(value) == 0 {
+		return true, nil
+	}
+
+	if len(value) != 4 {
+		return false, fmt.Errorf("could not parse data distribution mode from value %s", fdb.Printable(value))
+	}
+
+	return binary.LittleEndian.Uint32(value) != 0, nil
+}
+
+// lockUIDRegex matches the lock UID in the output of the fdbcli lock command.
+var lockUIDRegex = regexp.MustCompile(`lockUID: ([0-9a-f]{32})`)
+
+// parseLockUID parses the lock UID from the output of the fdbcli lock command.
+func parseLockUID(output string) (string, error) {
+	matches := lockUIDRegex.FindStringSubmatch(output)
+	if matches == nil {
+		return "", fmt.Errorf("could not parse lock UID from output: %s", output)
+	}
+
+	return matches[1], nil
+}
+
+// parseLockUIDFromValue parses the lock UID from the value of the database locked key. The value contains the
+// versionstamp of the lock followed by the two parts of the UID.
+func parseLockUIDFromValue(value []byte) (string, error) {
+	if len(value) != 26 {
+		return "", fmt.Errorf("could not parse lock UID from value %s", fdb.Printable(value))
+	}
+
+	return fmt.Sprintf("%016x%016x", binary.LittleEndian.Uint64(value[10:18]), binary.LittleEndian.Uint64(value[18:26])), nil
+}
+
+// unlockDatabase removes the database lock if it was taken with the provided lock UID.
+func unlockDatabase(ctx context.Context, libClient fdbLibClient, lockUID string, timeout time.Duration) error {
+	return libClient.clearKeyIfValue(ctx, "\xff/dbLocked", func(value []byte) error {
+		currentLockUID, err := parseLockUIDFromValue(value)
+		if err != nil {
+			return err
+		}
+
+		if currentLockUID != lockUID {
+			return fmt.Errorf("database is locked with lock UID %s instead of %s", currentLockUID, lockUID)
+		}
+
+		return nil
+	}, timeout)
+}
+
+// latencyProbeKey is the key that will be read and written by the latency probes.
+const latencyProbeKey = "\xff\x02/fdbKubernetesOperator/latencyProbe"
+
+// probeLatency measures the latencies of the database with a transaction that reads and writes the latency probe key.
+func probeLatency(ctx context.Context, libClient fdbLibClient, timeout time.Duration) (*fdbadminclient.LatencyProbeResult, error) {
+	return libClient.probeLatency(ctx, latencyProbeKey, timeout)
+}
+
+// workJournalKeyPrefix is the prefix of the keys that contain the work journals of the clusters.
+const workJournalKeyPrefix = "\xff\x02/fdbKubernetesOperator/workJournal/"
+
+// getWorkJournalKey returns the key that contains the work journal of the cluster. A database can be managed by
+// multiple FoundationDBCluster resources, e.g. in a multi-region setup, so every resource has its own work journal.
+func getWorkJournalKey(cluster *fdbv1beta2.FoundationDBCluster) string {
+	return fmt.Sprintf("%s%s/%s", workJournalKeyPrefix, cluster.Namespace, cluster.Name)
+}
+
+// getWorkJournal reads the work journal of the cluster from the database. If no work journal is stored, nil will be
+// returned.
+func getWorkJournal(ctx context.Context, libClient fdbLibClient, cluster *fdbv1beta2.FoundationDBCluster, timeout time.Duration) (*fdbadminclient.WorkJournal, error) {
+	value, err := libClient.getValueFromDBUsingKey(ctx, getWorkJournalKey(cluster), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	journal := &fdbadminclient.WorkJournal{}
+	err = json.Unmarshal(value, journal)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse work journal: %w", err)
+	}
+
+	return journal, nil
+}
+
+// updateWorkJournal stores the work journal of the cluster in the database. An empty work journal will be cleared.
+func updateWorkJournal(ctx context.Context, libClient fdbLibClient, cluster *fdbv1beta2.FoundationDBCluster, journal *fdbadminclient.WorkJournal, timeout time.Duration) error {
+	if journal.IsEmpty() {
+		return libClient.setValueForKey(ctx, getWorkJournalKey(cluster), nil, timeout)
+	}
+
+	value, err := json.Marshal(journal)
+	if err != nil {
+		return err
+	}
+
+	return libClient.setValueForKey(ctx, getWorkJournalKey(cluster), value, timeout)
+}
+
+// inProgressExclusionPrefix is the prefix of the keys in the management module of the special key space that contain
+// the addresses of the excluded servers that still hold data or still host a transaction log.
+const inProgressExclusionPrefix = "\xff\xff/management/in_progress_exclusion/"
+
+// getInProgressExclusionsFromDB returns the addresses of the excluded servers that still hold data or still host a
+// transaction log.
+func getInProgressExclusionsFromDB(ctx context.Context, libClient fdbLibClient, timeout time.Duration) ([]fdbv1beta2.ProcessAddress, error) {
+	keys, err := libClient.getKeysWithPrefix(ctx, inProgressExclusionPrefix, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]fdbv1beta2.ProcessAddress, 0, len(keys))
+	for _, key := range keys {
+		address, err := fdbv1beta2.ParseProcessAddress(key)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+const (
+	// tenantMapPrefix is the prefix of the keys in the management module of the special key space that contain the
+	// metadata of the tenants.
+	tenantMapPrefix = "\xff\xff/management/tenant/map/"
+	// legacyTenantMapPrefix is the prefix of the tenant metadata in the management module on FDB 7.1.
+	legacyTenantMapPrefix = "\xff\xff/management/tenant_map/"
+)
+
+// getTenantsFromDB returns all tenants of the cluster with a single read of the tenant map in the management module.
+func getTenantsFromDB(ctx context.Context, libClient fdbLibClient, version fdbv1beta2.Version, timeout time.Duration) ([]fdbv1beta2.TenantStatus, error) {
+	prefix := tenantMapPrefix
+	if !version.IsAtLeast(fdbv1beta2.Version{Major: 7, Minor: 2, Patch: 0}) {
+		prefix = legacyTenantMapPrefix
+	}
+
+	values, err := libClient.getValuesWithPrefix(ctx, prefix, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tenants := make([]fdbv1beta2.TenantStatus, 0, len(names))
+	for _, name := range names {
+		tenant, err := parseTenantMetadata(name, values[name])
+		if err != nil {
+			return nil, err
+		}
+
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants, nil
 }
 
 // getStatusFromDB gets the database's status directly from the system key
-func getStatusFromDB(libClient fdbLibClient) (*fdbv1beta2.FoundationDBStatus, error) {
-	contents, err := libClient.getValueFromDBUsingKey("\xff\xff/status/json", DefaultCLITimeout)
+func getStatusFromDB(ctx context.Context, libClient fdbLibClient, logger logr.Logger, timeout time.Duration) (*fdbv1beta2.FoundationDBStatus, error) {
+	contents, err := libClient.getValueFromDBUsingKey(ctx, "\xff\xff/status/json", timeout)
 	if err != nil {
 		return nil, err
 	}
 
-	status := &fdbv1beta2.FoundationDBStatus{}
-	err = json.Unmarshal(contents, status)
+	return parseStatus(contents, logger)
+}
+
+// parseStatus parses the machine-readable status. Fields with a value that doesn't match the expected type, e.g.
+// because a newer version of FoundationDB changed the schema, are skipped and logged.
+func parseStatus(contents []byte, logger logr.Logger) (*fdbv1beta2.FoundationDBStatus, error) {
+	status, result, err := fdbv1beta2.ParseFoundationDBStatus(contents)
 	if err != nil {
 		return nil, err
 	}
 
+	invalidFields := result.InvalidFields()
+	if len(invalidFields) > 0 {
+		logger.Info("Ignoring fields of the machine-readable status with an unexpected type", "fields", invalidFields)
+	}
+
 	return status, nil
 }
 
 type realDatabaseClientProvider struct {
 	// log implementation for logging output
 	log logr.Logger
+
+	// kubeClientSet is used to read the logs of the command Pods. If set the CLI commands will be run in short-lived
+	// Pods instead of the operator Pod.
+	kubeClientSet kubernetes.Interface
 }
 
 // GetLockClient generates a client for working with locks through the database.
@@ -121,6 +321,10 @@ func (p *realDatabaseClientProvider) GetLockClient(cluster *fdbv1beta2.Foundatio
 // GetAdminClient generates a client for performing administrative actions
 // against the database.
 func (p *realDatabaseClientProvider) GetAdminClient(cluster *fdbv1beta2.FoundationDBCluster, kubernetesClient client.Client) (fdbadminclient.AdminClient, error) {
+	if p.kubeClientSet != nil {
+		return NewPodCliAdminClient(cluster, kubernetesClient, p.kubeClientSet, p.log)
+	}
+
 	return NewCliAdminClient(cluster, kubernetesClient, p.log)
 }
 
@@ -131,3 +335,12 @@ func NewDatabaseClientProvider(log logr.Logger) fdbadminclient.DatabaseClientPro
 		log: log.WithName("fdbclient"),
 	}
 }
+
+// NewPodDatabaseClientProvider generates a client provider for talking to real databases that runs the CLI commands in
+// short-lived Pods in the namespace of the cluster.
+func NewPodDatabaseClientProvider(log logr.Logger, kubeClientSet kubernetes.Interface) fdbadminclient.DatabaseClientProvider {
+	return &realDatabaseClientProvider{
+		log:           log.WithName("fdbclient"),
+		kubeClientSet: kubeClientSet,
+	}
+}
@@ -21,11 +21,17 @@
 package fdbclient
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path"
+	"time"
 
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("common_test", func() {
@@ -79,4 +85,216 @@ var _ = Describe("common_test", func() {
 			})
 		})
 	})
+
+	When("getting the data distribution mode", func() {
+		DescribeTable("it should return the correct mode",
+			func(mockedOutput []byte, mockedError error, expected bool, expectedErr bool) {
+				libClient := &mockFdbLibClient{
+					mockedOutput: mockedOutput,
+					mockedError:  mockedError,
+				}
+
+				enabled, err := getDataDistributionModeFromDB(context.TODO(), libClient, DefaultCLITimeout)
+				Expect(libClient.requestedKey).To(Equal("\xff/dataDistributionMode"))
+				if expectedErr {
+					Expect(err).To(HaveOccurred())
+					return
+				}
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(enabled).To(Equal(expected))
+			},
+			Entry("the mode key is not set",
+				[]byte{},
+				nil,
+				true,
+				false,
+			),
+			Entry("data distribution is enabled",
+				[]byte{1, 0, 0, 0},
+				nil,
+				true,
+				false,
+			),
+			Entry("data distribution is disabled",
+				[]byte{0, 0, 0, 0},
+				nil,
+				false,
+				false,
+			),
+			Entry("the value is malformed",
+				[]byte{1},
+				nil,
+				false,
+				true,
+			),
+			Entry("reading the key fails",
+				nil,
+				fmt.Errorf("timeout"),
+				false,
+				true,
+			),
+		)
+	})
+
+	When("parsing the lock UID", func() {
+		It("should parse the lock UID from the lock command output", func() {
+			lockUID, err := parseLockUID("Locking database with lockUID: 0123456789abcdef0123456789abcdef\nDatabase locked.\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lockUID).To(Equal("0123456789abcdef0123456789abcdef"))
+		})
+
+		It("should return an error for unexpected output", func() {
+			_, err := parseLockUID("ERROR: Database is locked")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("unlocking the database", func() {
+		// The value contains a 10 byte versionstamp followed by the two parts of the UID in little endian.
+		lockedValue := append(make([]byte, 10), 0xef, 0xcd, 0xab, 0x89, 0x67, 0x45, 0x23, 0x01, 0x10, 0, 0, 0, 0, 0, 0, 0)
+
+		DescribeTable("it should only clear the lock with the matching lock UID",
+			func(mockedOutput []byte, lockUID string, expectedClear bool, expectedErr bool) {
+				libClient := &mockFdbLibClient{
+					mockedOutput: mockedOutput,
+				}
+
+				err := unlockDatabase(context.TODO(), libClient, lockUID, DefaultCLITimeout)
+				Expect(libClient.requestedKey).To(Equal("\xff/dbLocked"))
+				if expectedErr {
+					Expect(err).To(HaveOccurred())
+				} else {
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				if expectedClear {
+					Expect(libClient.clearedKey).To(Equal("\xff/dbLocked"))
+				} else {
+					Expect(libClient.clearedKey).To(BeEmpty())
+				}
+			},
+			Entry("the database is not locked",
+				[]byte{},
+				"0123456789abcdef0000000000000010",
+				false,
+				false,
+			),
+			Entry("the database is locked with the lock UID",
+				lockedValue,
+				"0123456789abcdef0000000000000010",
+				true,
+				false,
+			),
+			Entry("the database is locked with another lock UID",
+				lockedValue,
+				"00000000000000000000000000000000",
+				false,
+				true,
+			),
+			Entry("the value is malformed",
+				[]byte{1},
+				"0123456789abcdef0000000000000010",
+				false,
+				true,
+			),
+		)
+	})
+
+	When("probing the latency of the database", func() {
+		It("should use the latency probe key", func() {
+			libClient := &mockFdbLibClient{
+				mockedLatencyProbeResult: &fdbadminclient.LatencyProbeResult{
+					GRVLatency:    time.Millisecond,
+					ReadLatency:   2 * time.Millisecond,
+					CommitLatency: 3 * time.Millisecond,
+				},
+			}
+
+			result, err := probeLatency(context.TODO(), libClient, DefaultCLITimeout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(libClient.requestedKey).To(Equal("\xff\x02/fdbKubernetesOperator/latencyProbe"))
+			Expect(libClient.receivedTimeout).To(Equal(DefaultCLITimeout))
+			Expect(result.CommitLatency).To(Equal(3 * time.Millisecond))
+		})
+
+		It("should return the error of the probe", func() {
+			libClient := &mockFdbLibClient{
+				mockedError: fmt.Errorf("probe failed"),
+			}
+
+			_, err := probeLatency(context.TODO(), libClient, DefaultCLITimeout)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("storing the work journal", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &fdbv1beta2.FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			}
+		})
+
+		It("should store the work journal under the key of the cluster", func() {
+			libClient := &mockFdbLibClient{}
+			journal := &fdbadminclient.WorkJournal{
+				PendingExclusions: []fdbv1beta2.ProcessGroupID{"storage-1"},
+			}
+
+			err := updateWorkJournal(context.TODO(), libClient, cluster, journal, DefaultCLITimeout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(libClient.requestedKey).To(Equal("\xff\x02/fdbKubernetesOperator/workJournal/default/test"))
+			Expect(string(libClient.setValue)).To(Equal(`{"pendingExclusions":["storage-1"]}`))
+
+			libClient.mockedOutput = libClient.setValue
+			storedJournal, err := getWorkJournal(context.TODO(), libClient, cluster, DefaultCLITimeout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedJournal).To(Equal(journal))
+		})
+
+		It("should clear an empty work journal", func() {
+			libClient := &mockFdbLibClient{setValue: []byte("previous")}
+
+			err := updateWorkJournal(context.TODO(), libClient, cluster, &fdbadminclient.WorkJournal{}, DefaultCLITimeout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(libClient.setValue).To(BeEmpty())
+		})
+
+		It("should return nil if no work journal is stored", func() {
+			journal, err := getWorkJournal(context.TODO(), &mockFdbLibClient{}, cluster, DefaultCLITimeout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(journal).To(BeNil())
+		})
+	})
+
+	When("getting the tenants", func() {
+		var libClient *mockFdbLibClient
+
+		BeforeEach(func() {
+			libClient = &mockFdbLibClient{
+				mockedValues: map[string][]byte{
+					"tenant2": []byte(`{"id":2,"prefix":"\u0000\u0000\u0000\u0000\u0000\u0000\u0000\u0002"}`),
+					"tenant1": []byte(`{"id":1,"prefix":"\u0000\u0000\u0000\u0000\u0000\u0000\u0000\u0001"}`),
+				},
+			}
+		})
+
+		It("should read the tenant map with a single range read", func() {
+			tenants, err := getTenantsFromDB(context.TODO(), libClient, fdbv1beta2.Version{Major: 7, Minor: 3, Patch: 0}, DefaultCLITimeout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(libClient.requestedPrefix).To(Equal(tenantMapPrefix))
+			Expect(tenants).To(Equal([]fdbv1beta2.TenantStatus{
+				{Name: "tenant1", ID: 1, Prefix: "\\x00\\x00\\x00\\x00\\x00\\x00\\x00\\x01"},
+				{Name: "tenant2", ID: 2, Prefix: "\\x00\\x00\\x00\\x00\\x00\\x00\\x00\\x02"},
+			}))
+		})
+
+		It("should use the legacy tenant map on 7.1", func() {
+			_, err := getTenantsFromDB(context.TODO(), libClient, fdbv1beta2.Version{Major: 7, Minor: 1, Patch: 27}, DefaultCLITimeout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(libClient.requestedPrefix).To(Equal(legacyTenantMapPrefix))
+		})
+	})
 })
@@ -21,18 +21,39 @@
 package fdbclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	"github.com/go-logr/logr"
+	"strings"
 	"time"
 )
 
 // fdbLibClient is an interface to interact with FDB over the client libraries
 type fdbLibClient interface {
 	// getValueFromDBUsingKey returns the value of the provided key.
-	getValueFromDBUsingKey(fdbKey string, timeout time.Duration) ([]byte, error)
+	getValueFromDBUsingKey(ctx context.Context, fdbKey string, timeout time.Duration) ([]byte, error)
+
+	// clearKeyIfValue clears the provided key in a lock aware transaction if the check of the current value passes.
+	// Missing keys will not be checked.
+	clearKeyIfValue(ctx context.Context, fdbKey string, check func(value []byte) error, timeout time.Duration) error
+
+	// probeLatency measures the latency of getting a read version, reading the provided key and committing a
+	// transaction that writes the provided key.
+	probeLatency(ctx context.Context, fdbKey string, timeout time.Duration) (*fdbadminclient.LatencyProbeResult, error)
+
+	// getKeysWithPrefix returns all keys that start with the provided prefix, without the prefix.
+	getKeysWithPrefix(ctx context.Context, prefix string, timeout time.Duration) ([]string, error)
+
+	// getValuesWithPrefix returns the values of all keys that start with the provided prefix by their key, without the
+	// prefix.
+	getValuesWithPrefix(ctx context.Context, prefix string, timeout time.Duration) (map[string][]byte, error)
+
+	// setValueForKey sets the value of the provided key in a lock aware transaction. An empty value clears the key.
+	setValueForKey(ctx context.Context, fdbKey string, value []byte, timeout time.Duration) error
 }
 
 // realFdbLibClient represents the actual FDB client that will interact with FDB.
@@ -41,7 +62,38 @@ type realFdbLibClient struct {
 	logger  logr.Logger
 }
 
-func (fdbClient *realFdbLibClient) getValueFromDBUsingKey(fdbKey string, timeout time.Duration) ([]byte, error) {
+// getTransactionTimeout returns the provided timeout or the remaining time until the deadline of the context, if the
+// context has an earlier deadline. The FDB bindings don't support contexts, so we use the transaction timeout to
+// make sure a transaction doesn't outlive the context. If the context is already done, the error of the context will be
+// returned.
+func getTransactionTimeout(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout, nil
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+
+	if remaining < timeout {
+		return remaining, nil
+	}
+
+	return timeout, nil
+}
+
+func (fdbClient *realFdbLibClient) getValueFromDBUsingKey(ctx context.Context, fdbKey string, timeout time.Duration) ([]byte, error) {
+	timeout, err := getTransactionTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+
 	fdbClient.logger.Info("Fetch values from FDB", "key", fdbKey)
 	defer func() {
 		fdbClient.logger.Info("Done fetching values from FDB", "key", fdbKey)
@@ -61,33 +113,253 @@ func (fdbClient *realFdbLibClient) getValueFromDBUsingKey(fdbKey string, timeout
 			return nil, err
 		}
 
-		rawResult := transaction.Get(fdb.Key(fdbKey)).MustGet()
-		if len(rawResult) == 0 {
+		// A missing key will return an empty byte slice.
+		return transaction.Get(fdb.Key(fdbKey)).MustGet(), nil
+	})
+
+	if err != nil {
+		return nil, convertTimeoutError(err)
+	}
+
+	byteResult, ok := result.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("could not cast result into byte slice")
+	}
+
+	return byteResult, nil
+}
+
+func (fdbClient *realFdbLibClient) clearKeyIfValue(ctx context.Context, fdbKey string, check func(value []byte) error, timeout time.Duration) error {
+	timeout, err := getTransactionTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
+
+	fdbClient.logger.Info("Clear key in FDB", "key", fdbKey)
+	database, err := getFDBDatabase(fdbClient.cluster)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetAccessSystemKeys()
+		if err != nil {
+			return nil, err
+		}
+		err = transaction.Options().SetLockAware()
+		if err != nil {
 			return nil, err
 		}
+		err = transaction.Options().SetTimeout(timeout.Milliseconds())
+		if err != nil {
+			return nil, err
+		}
+
+		value := transaction.Get(fdb.Key(fdbKey)).MustGet()
+		if len(value) == 0 {
+			return nil, nil
+		}
 
-		return rawResult, err
+		err = check(value)
+		if err != nil {
+			return nil, err
+		}
+
+		transaction.Clear(fdb.Key(fdbKey))
+		return nil, nil
 	})
 
+	return err
+}
+
+func (fdbClient *realFdbLibClient) probeLatency(ctx context.Context, fdbKey string, timeout time.Duration) (*fdbadminclient.LatencyProbeResult, error) {
+	timeout, err := getTransactionTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := getFDBDatabase(fdbClient.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// The probe uses a single transaction without retries, as every retry would distort the measured latencies.
+	transaction, err := database.CreateTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	err = transaction.Options().SetAccessSystemKeys()
+	if err != nil {
+		return nil, err
+	}
+	err = transaction.Options().SetTimeout(timeout.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fdbadminclient.LatencyProbeResult{}
+	start := time.Now()
+	_, err = transaction.GetReadVersion().Get()
+	if err != nil {
+		return nil, convertTimeoutError(err)
+	}
+	result.GRVLatency = time.Since(start)
+
+	start = time.Now()
+	_, err = transaction.Get(fdb.Key(fdbKey)).Get()
+	if err != nil {
+		return nil, convertTimeoutError(err)
+	}
+	result.ReadLatency = time.Since(start)
+
+	transaction.Set(fdb.Key(fdbKey), []byte(time.Now().UTC().Format(time.RFC3339)))
+	start = time.Now()
+	err = transaction.Commit().Get()
 	if err != nil {
-		var fdbError *fdb.Error
-		if errors.As(err, &fdbError) {
-			// See: https://apple.github.io/foundationdb/api-error-codes.html
-			// 1031: Operation aborted because the transaction timed out
-			if fdbError.Code == 1031 {
-				return nil, fdbv1beta2.TimeoutError{Err: err}
-			}
+		return nil, convertTimeoutError(err)
+	}
+	result.CommitLatency = time.Since(start)
+
+	return result, nil
+}
+
+func (fdbClient *realFdbLibClient) getKeysWithPrefix(ctx context.Context, prefix string, timeout time.Duration) ([]string, error) {
+	timeout, err := getTransactionTimeout(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	fdbClient.logger.Info("Fetch keys from FDB", "prefix", prefix)
+	database, err := getFDBDatabase(fdbClient.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetTimeout(timeout.Milliseconds())
+		if err != nil {
+			return nil, err
 		}
 
+		keyValues, err := transaction.GetRange(fdb.KeyRange{Begin: fdb.Key(prefix), End: fdb.Key(prefix + "\xff")}, fdb.RangeOptions{}).GetSliceWithError()
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(keyValues))
+		for _, keyValue := range keyValues {
+			keys = append(keys, strings.TrimPrefix(string(keyValue.Key), prefix))
+		}
+
+		return keys, nil
+	})
+
+	if err != nil {
+		return nil, convertTimeoutError(err)
+	}
+
+	keys, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("could not cast result into string slice")
+	}
+
+	return keys, nil
+}
+
+func (fdbClient *realFdbLibClient) getValuesWithPrefix(ctx context.Context, prefix string, timeout time.Duration) (map[string][]byte, error) {
+	timeout, err := getTransactionTimeout(ctx, timeout)
+	if err != nil {
 		return nil, err
 	}
 
-	byteResult, ok := result.([]byte)
+	fdbClient.logger.Info("Fetch values from FDB", "prefix", prefix)
+	database, err := getFDBDatabase(fdbClient.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetTimeout(timeout.Milliseconds())
+		if err != nil {
+			return nil, err
+		}
+
+		keyValues, err := transaction.GetRange(fdb.KeyRange{Begin: fdb.Key(prefix), End: fdb.Key(prefix + "\xff")}, fdb.RangeOptions{}).GetSliceWithError()
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string][]byte, len(keyValues))
+		for _, keyValue := range keyValues {
+			values[strings.TrimPrefix(string(keyValue.Key), prefix)] = keyValue.Value
+		}
+
+		return values, nil
+	})
+
+	if err != nil {
+		return nil, convertTimeoutError(err)
+	}
+
+	values, ok := result.(map[string][]byte)
 	if !ok {
-		return nil, fmt.Errorf("could not cast result into byte slice")
+		return nil, fmt.Errorf("could not cast result into map")
 	}
 
-	return byteResult, nil
+	return values, nil
+}
+
+func (fdbClient *realFdbLibClient) setValueForKey(ctx context.Context, fdbKey string, value []byte, timeout time.Duration) error {
+	timeout, err := getTransactionTimeout(ctx, timeout)
+	if err != nil {
+		return err
+	}
+
+	fdbClient.logger.Info("Set key in FDB", "key", fdbKey)
+	database, err := getFDBDatabase(fdbClient.cluster)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetAccessSystemKeys()
+		if err != nil {
+			return nil, err
+		}
+		err = transaction.Options().SetLockAware()
+		if err != nil {
+			return nil, err
+		}
+		err = transaction.Options().SetTimeout(timeout.Milliseconds())
+		if err != nil {
+			return nil, err
+		}
+
+		if len(value) == 0 {
+			transaction.Clear(fdb.Key(fdbKey))
+			return nil, nil
+		}
+
+		transaction.Set(fdb.Key(fdbKey), value)
+		return nil, nil
+	})
+
+	return convertTimeoutError(err)
+}
+
+// convertTimeoutError converts the FDB error for a timed out transaction into a fdbv1beta2.TimeoutError.
+func convertTimeoutError(err error) error {
+	var fdbError *fdb.Error
+	if errors.As(err, &fdbError) {
+		// See: https://apple.github.io/foundationdb/api-error-codes.html
+		// 1031: Operation aborted because the transaction timed out
+		if fdbError.Code == 1031 {
+			return fdbv1beta2.TimeoutError{Err: err}
+		}
+	}
+
+	return err
 }
 
 // mockFdbLibClient is a mock for unit testing.
@@ -96,12 +368,95 @@ type mockFdbLibClient struct {
 	mockedOutput []byte
 	// mockedError is the error returned by getValueFromDBUsingKey.
 	mockedError error
-	// requestedKey will be the key that was used to call getValueFromDBUsingKey.
+	// mockedLatencyProbeResult is the result returned by probeLatency.
+	mockedLatencyProbeResult *fdbadminclient.LatencyProbeResult
+	// mockedKeys is the result returned by getKeysWithPrefix.
+	mockedKeys []string
+	// mockedValues is the result returned by getValuesWithPrefix.
+	mockedValues map[string][]byte
+	// requestedPrefix will be the prefix that was used to call getKeysWithPrefix or getValuesWithPrefix.
+	requestedPrefix string
+	// requestedKey will be the key that was used to call getValueFromDBUsingKey, clearKeyIfValue or probeLatency.
 	requestedKey string
+	// clearedKey will be the key that was cleared by clearKeyIfValue.
+	clearedKey string
+	// receivedTimeout will be the timeout that was used to call getValueFromDBUsingKey or clearKeyIfValue.
+	receivedTimeout time.Duration
+	// setValue will be the value that was set by setValueForKey.
+	setValue []byte
 }
 
-func (fdbClient *mockFdbLibClient) getValueFromDBUsingKey(fdbKey string, _ time.Duration) ([]byte, error) {
+func (fdbClient *mockFdbLibClient) getValueFromDBUsingKey(ctx context.Context, fdbKey string, timeout time.Duration) ([]byte, error) {
 	fdbClient.requestedKey = fdbKey
+	fdbClient.receivedTimeout = timeout
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 
 	return fdbClient.mockedOutput, fdbClient.mockedError
 }
+
+func (fdbClient *mockFdbLibClient) clearKeyIfValue(ctx context.Context, fdbKey string, check func(value []byte) error, timeout time.Duration) error {
+	fdbClient.requestedKey = fdbKey
+	fdbClient.receivedTimeout = timeout
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if fdbClient.mockedError != nil {
+		return fdbClient.mockedError
+	}
+
+	if len(fdbClient.mockedOutput) == 0 {
+		return nil
+	}
+
+	err := check(fdbClient.mockedOutput)
+	if err != nil {
+		return err
+	}
+
+	fdbClient.clearedKey = fdbKey
+	return nil
+}
+
+func (fdbClient *mockFdbLibClient) probeLatency(ctx context.Context, fdbKey string, timeout time.Duration) (*fdbadminclient.LatencyProbeResult, error) {
+	fdbClient.requestedKey = fdbKey
+	fdbClient.receivedTimeout = timeout
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return fdbClient.mockedLatencyProbeResult, fdbClient.mockedError
+}
+
+func (fdbClient *mockFdbLibClient) getKeysWithPrefix(ctx context.Context, prefix string, _ time.Duration) ([]string, error) {
+	fdbClient.requestedPrefix = prefix
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return fdbClient.mockedKeys, nil
+}
+
+func (fdbClient *mockFdbLibClient) getValuesWithPrefix(ctx context.Context, prefix string, _ time.Duration) (map[string][]byte, error) {
+	fdbClient.requestedPrefix = prefix
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return fdbClient.mockedValues, nil
+}
+
+func (fdbClient *mockFdbLibClient) setValueForKey(ctx context.Context, fdbKey string, value []byte, timeout time.Duration) error {
+	fdbClient.requestedKey = fdbKey
+	fdbClient.receivedTimeout = timeout
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if fdbClient.mockedError != nil {
+		return fdbClient.mockedError
+	}
+
+	fdbClient.setValue = value
+	return nil
+}
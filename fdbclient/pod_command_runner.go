@@ -0,0 +1,447 @@
+/*
+ * pod_command_runner.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fdbclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CliCommandLabel is the label that is added to the Pods and Secrets that run CLI commands for a cluster. The value
+	// is the name of the cluster.
+	CliCommandLabel = fdbv1beta2.CliCommandLabel
+
+	// cliCommandContainerName is the name of the container that runs the CLI command.
+	cliCommandContainerName = "fdb-cli"
+
+	// podClusterFilePath is the path of the cluster file inside the command Pod.
+	podClusterFilePath = "/tmp/fdb.cluster"
+
+	// podLogDir is the directory inside the command Pod that is used for the trace files of the CLI command.
+	podLogDir = "/tmp"
+
+	// podBlobCredentialsDir is the directory inside the command Pod that contains the blob credentials.
+	podBlobCredentialsDir = "/var/blob-credentials"
+
+	// podBlobCredentialsKey is the key of the blob credentials in the command Secret.
+	podBlobCredentialsKey = "blob_credentials.json"
+)
+
+// DefaultCommandPodStartupTimeout is the default time a command Pod has to start up, this time is added to the timeout
+// of the command.
+var DefaultCommandPodStartupTimeout = 2 * time.Minute
+
+// staleCommandResourceAge defines the age after which the command Pods and Secrets of a cluster are considered left
+// over, e.g. because the operator was restarted while a command was running. This is much longer than the timeout of
+// any command, so running commands are never affected.
+var staleCommandResourceAge = time.Hour
+
+// podCommandRunner is a struct that implements the commandRunner interface and executes commands in short-lived Pods
+// in the namespace of the cluster. This allows the operator to run commands with a matching client version without
+// shipping all fdbcli binaries in the operator image.
+type podCommandRunner struct {
+	// log implementation for logging output
+	log logr.Logger
+	// cluster is the cluster the commands are run against.
+	cluster *fdbv1beta2.FoundationDBCluster
+	// kubeClient is used to create, get and delete the command Pods.
+	kubeClient client.Client
+	// kubeClientSet is used to read the logs of the command Pods.
+	kubeClientSet kubernetes.Interface
+	// clusterFilePath is the local path of the cluster file, this path will be replaced by the path inside the Pod.
+	clusterFilePath string
+	// pollInterval defines how often the state of the command Pod will be checked.
+	pollInterval time.Duration
+}
+
+// getClusterFileContent returns the content of the local cluster file. The local cluster file will be updated after
+// every command, so it contains the current connection string even if the cluster status is not yet updated, e.g.
+// after the coordinators were changed.
+func (runner *podCommandRunner) getClusterFileContent() string {
+	content, err := os.ReadFile(runner.clusterFilePath)
+	if err != nil || len(content) == 0 {
+		return runner.cluster.Status.ConnectionString
+	}
+
+	return string(content)
+}
+
+// getCommandSecret returns the Secret that contains the arguments with credentials and the blob credentials of the
+// operator. Those values are passed to the command Pod as environment variables and volumes to prevent that they are
+// exposed in the Pod spec. If no credentials must be passed to the command Pod, nil will be returned.
+func (runner *podCommandRunner) getCommandSecret(arg ...string) (*corev1.Secret, error) {
+	data := map[string][]byte{}
+	for idx, value := range internal.RedactArguments(arg) {
+		if value == arg[idx] {
+			continue
+		}
+
+		data[getCommandArgumentKey(idx)] = []byte(arg[idx])
+	}
+
+	blobCredentials := os.Getenv("FDB_BLOB_CREDENTIALS")
+	if blobCredentials != "" {
+		content, err := os.ReadFile(blobCredentials)
+		if err != nil {
+			return nil, err
+		}
+
+		data[podBlobCredentialsKey] = content
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-cli-", runner.cluster.Name),
+			Namespace:    runner.cluster.Namespace,
+			Labels:       map[string]string{CliCommandLabel: runner.cluster.Name},
+		},
+		Data: data,
+	}, nil
+}
+
+// getCommandArgumentKey returns the key in the command Secret for the argument at the provided index.
+func getCommandArgumentKey(idx int) string {
+	return fmt.Sprintf("arg-%d", idx)
+}
+
+// getCommandPod returns the Pod that runs the provided binary with the provided arguments. The arguments that contain
+// credentials will be read from the provided Secret. The TLS configuration is taken from the sidecar container of the
+// cluster, so the command Pod can connect to the cluster the same way the sidecar does.
+func (runner *podCommandRunner) getCommandPod(version string, secret *corev1.Secret, name string, arg ...string) (*corev1.Pod, error) {
+	imageConfigs := runner.cluster.Spec.MainContainer.ImageConfigs
+	if len(imageConfigs) == 0 {
+		imageConfigs = []fdbv1beta2.ImageConfig{{BaseImage: "foundationdb/foundationdb"}}
+	}
+
+	image, err := internal.GetImage("", imageConfigs, version, false)
+	if err != nil {
+		return nil, err
+	}
+
+	podSpec, err := internal.GetPodSpec(runner.cluster, fdbv1beta2.ProcessClassStorage, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var sidecarContainer *corev1.Container
+	for idx, container := range podSpec.Containers {
+		if container.Name == fdbv1beta2.SidecarContainerName {
+			sidecarContainer = &podSpec.Containers[idx]
+			break
+		}
+	}
+
+	if sidecarContainer == nil {
+		return nil, fmt.Errorf("could not find sidecar container for cluster %s/%s", runner.cluster.Namespace, runner.cluster.Name)
+	}
+
+	env := []corev1.EnvVar{
+		{
+			Name:  "FDB_CLUSTER_FILE_CONTENT",
+			Value: runner.getClusterFileContent(),
+		},
+	}
+
+	for _, envVar := range sidecarContainer.Env {
+		if strings.HasPrefix(envVar.Name, "FDB_TLS_") {
+			env = append(env, envVar)
+		}
+	}
+
+	volumeMounts := sidecarContainer.VolumeMounts
+	mountedVolumes := make(map[string]fdbv1beta2.None, len(volumeMounts))
+	for _, volumeMount := range volumeMounts {
+		mountedVolumes[volumeMount.Name] = fdbv1beta2.None{}
+	}
+
+	volumes := make([]corev1.Volume, 0, len(mountedVolumes))
+	for _, volume := range podSpec.Volumes {
+		if _, ok := mountedVolumes[volume.Name]; ok {
+			volumes = append(volumes, volume)
+		}
+	}
+
+	traceDir := os.Getenv("FDB_NETWORK_OPTION_TRACE_ENABLE")
+	command := make([]string, 0, len(arg)+1)
+	command = append(command, shellQuote(name))
+	for idx, value := range arg {
+		if secret != nil {
+			if _, ok := secret.Data[getCommandArgumentKey(idx)]; ok {
+				envName := fmt.Sprintf("FDB_CLI_ARG_%d", idx)
+				env = append(env, corev1.EnvVar{
+					Name: envName,
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+						Key:                  getCommandArgumentKey(idx),
+					}},
+				})
+				command = append(command, fmt.Sprintf(`"$%s"`, envName))
+				continue
+			}
+		}
+
+		if value == runner.clusterFilePath {
+			command = append(command, podClusterFilePath)
+			continue
+		}
+
+		if traceDir != "" && value == traceDir {
+			command = append(command, podLogDir)
+			continue
+		}
+
+		command = append(command, shellQuote(value))
+	}
+
+	if secret != nil {
+		if _, ok := secret.Data[podBlobCredentialsKey]; ok {
+			env = append(env, corev1.EnvVar{Name: "FDB_BLOB_CREDENTIALS", Value: path.Join(podBlobCredentialsDir, podBlobCredentialsKey)})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "blob-credentials", MountPath: podBlobCredentialsDir, ReadOnly: true})
+			volumes = append(volumes, corev1.Volume{Name: "blob-credentials", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+				SecretName: secret.Name,
+				Items:      []corev1.KeyToPath{{Key: podBlobCredentialsKey, Path: podBlobCredentialsKey}},
+			}}})
+		}
+	}
+
+	// The cluster file will be written to the termination message after the command has finished, this allows the
+	// operator to update the local cluster file, e.g. if the coordinators were changed by the command.
+	script := fmt.Sprintf(`printf "%%s" "$FDB_CLUSTER_FILE_CONTENT" > %[1]s && %[2]s; exit_code=$?; cat %[1]s > %[3]s; exit $exit_code`,
+		podClusterFilePath,
+		strings.Join(command, " "),
+		corev1.TerminationMessagePathDefault,
+	)
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-cli-", runner.cluster.Name),
+			Namespace:    runner.cluster.Namespace,
+			Labels:       map[string]string{CliCommandLabel: runner.cluster.Name},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: pointer.Int64(0),
+			SecurityContext:               podSpec.SecurityContext,
+			ImagePullSecrets:              podSpec.ImagePullSecrets,
+			Containers: []corev1.Container{
+				{
+					Name:                     cliCommandContainerName,
+					Image:                    image,
+					Command:                  []string{"sh"},
+					Args:                     []string{"-c", script},
+					WorkingDir:               podLogDir,
+					Env:                      env,
+					VolumeMounts:             volumeMounts,
+					SecurityContext:          sidecarContainer.SecurityContext,
+					TerminationMessagePath:   corev1.TerminationMessagePathDefault,
+					TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+				},
+			},
+			Volumes: volumes,
+		},
+	}, nil
+}
+
+// shellQuote quotes the provided value, so it can be safely used as a single argument in a shell command.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// deleteStaleCommandResources deletes the command Pods and Secrets of the cluster that are older than
+// staleCommandResourceAge. The command Pods and Secrets are not owned by the cluster, so they don't trigger a
+// reconciliation of the cluster, which means they are not garbage collected and are cleaned up by their label instead.
+func (runner *podCommandRunner) deleteStaleCommandResources(ctx context.Context) {
+	listOptions := []client.ListOption{
+		client.InNamespace(runner.cluster.Namespace),
+		client.MatchingLabels{CliCommandLabel: runner.cluster.Name},
+	}
+
+	pods := &corev1.PodList{}
+	err := runner.kubeClient.List(ctx, pods, listOptions...)
+	if err != nil {
+		runner.log.Error(err, "could not list command Pods")
+		return
+	}
+
+	for idx := range pods.Items {
+		runner.deleteIfStale(ctx, &pods.Items[idx])
+	}
+
+	secrets := &corev1.SecretList{}
+	err = runner.kubeClient.List(ctx, secrets, listOptions...)
+	if err != nil {
+		runner.log.Error(err, "could not list command Secrets")
+		return
+	}
+
+	for idx := range secrets.Items {
+		runner.deleteIfStale(ctx, &secrets.Items[idx])
+	}
+}
+
+// deleteIfStale deletes the provided command resource if it is older than staleCommandResourceAge.
+func (runner *podCommandRunner) deleteIfStale(ctx context.Context, object client.Object) {
+	if time.Since(object.GetCreationTimestamp().Time) < staleCommandResourceAge {
+		return
+	}
+
+	runner.log.Info("Deleting stale command resource", "name", object.GetName())
+	err := runner.kubeClient.Delete(ctx, object)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		runner.log.Error(err, "could not delete stale command resource", "name", object.GetName())
+	}
+}
+
+// runCommand runs the command in a new Pod and returns the logs of the Pod as output. The Pod will be deleted once
+// the command has finished or the context is done.
+func (runner *podCommandRunner) runCommand(ctx context.Context, version string, name string, arg ...string) ([]byte, error) {
+	runner.deleteStaleCommandResources(ctx)
+
+	secret, err := runner.getCommandSecret(arg...)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret != nil {
+		err = runner.kubeClient.Create(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			// Use a new context here as the provided context might already be done.
+			deleteErr := runner.kubeClient.Delete(context.Background(), secret)
+			if deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+				runner.log.Error(deleteErr, "could not delete command Secret", "secret", secret.Name)
+			}
+		}()
+	}
+
+	pod, err := runner.getCommandPod(version, secret, name, arg...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runner.kubeClient.Create(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := runner.log.WithValues("pod", pod.Name)
+	logger.Info("Running command in Pod", "image", pod.Spec.Containers[0].Image, "args", internal.RedactArguments(arg))
+
+	defer func() {
+		// Use a new context here as the provided context might already be done.
+		deleteErr := runner.kubeClient.Delete(context.Background(), pod)
+		if deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+			logger.Error(deleteErr, "could not delete command Pod")
+		}
+	}()
+
+	ticker := time.NewTicker(runner.pollInterval)
+	defer ticker.Stop()
+
+	for pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("command Pod %s/%s did not finish: %w", pod.Namespace, pod.Name, ctx.Err())
+		case <-ticker.C:
+		}
+
+		err = runner.kubeClient.Get(ctx, client.ObjectKeyFromObject(pod), pod)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = runner.updateClusterFile(pod)
+	if err != nil {
+		logger.Error(err, "could not update the local cluster file")
+	}
+
+	output, err := runner.kubeClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: cliCommandContainerName}).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pod.Status.Phase == corev1.PodFailed {
+		logger.Info("Command Pod failed", "output", internal.RedactString(string(output)))
+		return output, fmt.Errorf("command Pod %s/%s failed with exit code %d", pod.Namespace, pod.Name, getCommandExitCode(pod))
+	}
+
+	return output, nil
+}
+
+// updateClusterFile updates the local cluster file with the cluster file from the termination message of the command
+// Pod, if the command has changed the cluster file.
+func (runner *podCommandRunner) updateClusterFile(pod *corev1.Pod) error {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != cliCommandContainerName || status.State.Terminated == nil {
+			continue
+		}
+
+		message := strings.TrimSpace(status.State.Terminated.Message)
+		if message == "" || message == strings.TrimSpace(runner.getClusterFileContent()) {
+			return nil
+		}
+
+		_, err := fdbv1beta2.ParseConnectionString(message)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(runner.clusterFilePath, []byte(message), 0777)
+	}
+
+	return nil
+}
+
+// getCommandExitCode returns the exit code of the CLI container or -1 if the container has not terminated.
+func getCommandExitCode(pod *corev1.Pod) int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != cliCommandContainerName || status.State.Terminated == nil {
+			continue
+		}
+
+		return status.State.Terminated.ExitCode
+	}
+
+	return -1
+}
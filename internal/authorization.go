@@ -0,0 +1,156 @@
+/*
+ * authorization.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AuthorizationPublicKeysKey defines the key in the authorization secret that contains the merged public keys.
+	AuthorizationPublicKeysKey = "public-keys.json"
+
+	// authorizationDirectory defines the directory in the main container where the authorization secret is mounted.
+	authorizationDirectory = "/var/fdb/authorization"
+)
+
+// jsonWebKeySet represents a JSON Web Key Set as defined in RFC 7517.
+type jsonWebKeySet struct {
+	Keys []json.RawMessage `json:"keys"`
+}
+
+// jsonWebKey contains the fields of a JSON Web Key that are validated by the operator.
+type jsonWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+}
+
+// MergePublicKeySets merges the provided JSON Web Key Sets into a single key set and returns the merged key set and
+// the IDs of the keys. Every key must define a key ID and a key type. Keys with the same key ID are only allowed if
+// they are identical, so the same key can be present in multiple key sets during a rotation.
+func MergePublicKeySets(keySets [][]byte) ([]byte, []string, error) {
+	keys := map[string]json.RawMessage{}
+	for _, content := range keySets {
+		keySet := &jsonWebKeySet{}
+		err := json.Unmarshal(content, keySet)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse JSON Web Key Set: %w", err)
+		}
+
+		for _, rawKey := range keySet.Keys {
+			key := &jsonWebKey{}
+			err = json.Unmarshal(rawKey, key)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not parse JSON Web Key: %w", err)
+			}
+
+			if key.KeyID == "" || key.KeyType == "" {
+				return nil, nil, fmt.Errorf("JSON Web Key must define a kid and a kty")
+			}
+
+			compacted := &bytes.Buffer{}
+			err = json.Compact(compacted, rawKey)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			existing, ok := keys[key.KeyID]
+			if ok && !bytes.Equal(existing, compacted.Bytes()) {
+				return nil, nil, fmt.Errorf("JSON Web Key %s is defined multiple times with different values", key.KeyID)
+			}
+
+			keys[key.KeyID] = compacted.Bytes()
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("no public keys are defined for the token based authorization")
+	}
+
+	keyIDs := make([]string, 0, len(keys))
+	for keyID := range keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Strings(keyIDs)
+
+	merged := &jsonWebKeySet{Keys: make([]json.RawMessage, 0, len(keyIDs))}
+	for _, keyID := range keyIDs {
+		merged.Keys = append(merged.Keys, keys[keyID])
+	}
+
+	content, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return content, keyIDs, nil
+}
+
+// GetAuthorizationSecret builds the secret that contains the merged public keys for the token based authorization.
+func GetAuthorizationSecret(cluster *fdbv1beta2.FoundationDBCluster, publicKeys []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cluster.GetAuthorizationSecretName(),
+			Namespace:       cluster.Namespace,
+			Labels:          cluster.GetMatchLabels(),
+			OwnerReferences: BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta),
+		},
+		Data: map[string][]byte{
+			AuthorizationPublicKeysKey: publicKeys,
+		},
+	}
+}
+
+// getAuthorizationArguments returns the arguments for the fdbserver processes to verify the tokens of clients with
+// the merged public keys.
+func getAuthorizationArguments(cluster *fdbv1beta2.FoundationDBCluster) []monitorapi.Argument {
+	if !cluster.AuthorizationEnabled() {
+		return nil
+	}
+
+	return []monitorapi.Argument{{Value: fmt.Sprintf("--authorization-public-key-file=%s/%s", authorizationDirectory, AuthorizationPublicKeysKey)}}
+}
+
+// configureAuthorization mounts the secret with the merged public keys into the main container. The secret is not
+// mounted with a sub path, so the kubelet updates the file when the secret changes and the fdbserver processes
+// reload the public keys without a restart.
+func configureAuthorization(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, mainContainer *corev1.Container) {
+	if !cluster.AuthorizationEnabled() {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "authorization",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: cluster.GetAuthorizationSecretName(),
+			},
+		},
+	})
+	mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, corev1.VolumeMount{Name: "authorization", MountPath: authorizationDirectory, ReadOnly: true})
+}
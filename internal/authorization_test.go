@@ -0,0 +1,116 @@
+/*
+ * authorization_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("authorization", func() {
+	Describe("MergePublicKeySets", func() {
+		It("should merge the keys of all key sets sorted by the key ID", func() {
+			publicKeys, keyIDs, err := MergePublicKeySets([][]byte{
+				[]byte(`{"keys": [{"kid": "key-2", "kty": "EC", "crv": "P-256"}]}`),
+				[]byte(`{"keys": [{"kid": "key-1", "kty": "EC", "crv": "P-256"}]}`),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keyIDs).To(Equal([]string{"key-1", "key-2"}))
+			Expect(string(publicKeys)).To(Equal(`{"keys":[{"kid":"key-1","kty":"EC","crv":"P-256"},{"kid":"key-2","kty":"EC","crv":"P-256"}]}`))
+		})
+
+		It("should allow the same key in multiple key sets", func() {
+			_, keyIDs, err := MergePublicKeySets([][]byte{
+				[]byte(`{"keys": [{"kid": "key-1", "kty": "EC"}]}`),
+				[]byte(`{"keys":[{"kid":"key-1","kty":"EC"}]}`),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keyIDs).To(Equal([]string{"key-1"}))
+		})
+
+		It("should reject different keys with the same key ID", func() {
+			_, _, err := MergePublicKeySets([][]byte{
+				[]byte(`{"keys": [{"kid": "key-1", "kty": "EC"}]}`),
+				[]byte(`{"keys": [{"kid": "key-1", "kty": "RSA"}]}`),
+			})
+			Expect(err).To(MatchError("JSON Web Key key-1 is defined multiple times with different values"))
+		})
+
+		It("should reject a key without a key ID", func() {
+			_, _, err := MergePublicKeySets([][]byte{[]byte(`{"keys": [{"kty": "EC"}]}`)})
+			Expect(err).To(MatchError("JSON Web Key must define a kid and a kty"))
+		})
+
+		It("should reject an invalid key set", func() {
+			_, _, err := MergePublicKeySets([][]byte{[]byte(`not json`)})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject an empty key set", func() {
+			_, _, err := MergePublicKeySets([][]byte{[]byte(`{"keys": []}`)})
+			Expect(err).To(MatchError("no public keys are defined for the token based authorization"))
+		})
+	})
+
+	When("the token based authorization is enabled", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = CreateDefaultCluster()
+			cluster.Spec.MainContainer.EnableTLS = true
+			cluster.Spec.Authorization = &fdbv1beta2.AuthorizationSpec{
+				PublicKeySecrets: []corev1.SecretKeySelector{{LocalObjectReference: corev1.LocalObjectReference{Name: "keys"}, Key: "jwks.json"}},
+			}
+			Expect(NormalizeClusterSpec(cluster, DeprecationOptions{})).NotTo(HaveOccurred())
+		})
+
+		It("should build the secret with the merged public keys", func() {
+			secret := GetAuthorizationSecret(cluster, []byte(`{"keys":[]}`))
+			Expect(secret.Name).To(Equal("operator-test-1-authorization"))
+			Expect(secret.Namespace).To(Equal(cluster.Namespace))
+			Expect(secret.Data).To(HaveKeyWithValue(AuthorizationPublicKeysKey, []byte(`{"keys":[]}`)))
+		})
+
+		It("should mount the secret into the main container", func() {
+			podSpec, err := GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "authorization",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: "operator-test-1-authorization"},
+				},
+			}))
+
+			mainContainer := podSpec.Containers[0]
+			Expect(mainContainer.Name).To(Equal(fdbv1beta2.MainContainerName))
+			Expect(mainContainer.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "authorization", MountPath: "/var/fdb/authorization", ReadOnly: true}))
+		})
+
+		It("should pass the public key file to the fdbserver processes", func() {
+			configuration, err := GetMonitorProcessConfiguration(cluster, fdbv1beta2.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configuration.Arguments).To(ContainElement(monitorapi.Argument{Value: "--authorization-public-key-file=/var/fdb/authorization/public-keys.json"}))
+		})
+	})
+})
@@ -0,0 +1,277 @@
+/*
+ * blob_store.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// BlobCredentialsEnvironmentVariable defines the environment variable that points to the credentials file of the
+// blobstore.
+const BlobCredentialsEnvironmentVariable = "FDB_BLOB_CREDENTIALS"
+
+// blobStoreProbeTimeout defines how long the connectivity probe waits for a response of the blobstore.
+const blobStoreProbeTimeout = 10 * time.Second
+
+// blobCredentials describes the format of the credentials file of the blobstore.
+type blobCredentials struct {
+	Accounts map[string]blobCredentialsAccount `json:"accounts"`
+}
+
+// blobCredentialsAccount describes the credentials of a single account in the credentials file.
+type blobCredentialsAccount struct {
+	Secret string `json:"secret"`
+}
+
+// GetBlobCredentialsSecret returns the Secret and the key in the Secret that contains the credentials file that is
+// defined in the main container of the Pod spec. If the credentials file is not defined or is not provided by a
+// Secret, nil will be returned.
+func GetBlobCredentialsSecret(spec *corev1.PodSpec) *corev1.SecretKeySelector {
+	if spec == nil {
+		return nil
+	}
+
+	for _, container := range spec.Containers {
+		if container.Name != fdbv1beta2.MainContainerName {
+			continue
+		}
+
+		var credentialsPath string
+		for _, env := range container.Env {
+			if env.Name == BlobCredentialsEnvironmentVariable {
+				credentialsPath = path.Clean(env.Value)
+			}
+		}
+
+		if credentialsPath == "" {
+			return nil
+		}
+
+		for _, mount := range container.VolumeMounts {
+			if path.Dir(credentialsPath) != path.Clean(mount.MountPath) {
+				continue
+			}
+
+			return getSecretKeyForVolume(spec.Volumes, mount.Name, path.Base(credentialsPath))
+		}
+	}
+
+	return nil
+}
+
+// getSecretKeyForVolume returns the Secret and the key in the Secret for the file in the volume with the provided
+// name. If the volume is not a Secret volume, nil will be returned.
+func getSecretKeyForVolume(volumes []corev1.Volume, volumeName string, fileName string) *corev1.SecretKeySelector {
+	for _, volume := range volumes {
+		if volume.Name != volumeName || volume.Secret == nil {
+			continue
+		}
+
+		key := fileName
+		for _, item := range volume.Secret.Items {
+			if item.Path == fileName {
+				key = item.Key
+			}
+		}
+
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: volume.Secret.SecretName},
+			Key:                  key,
+		}
+	}
+
+	return nil
+}
+
+// ValidateBlobCredentials checks that the credentials file is valid and contains the credentials of the account.
+func ValidateBlobCredentials(data []byte, accountName string) error {
+	credentials := blobCredentials{}
+	err := json.Unmarshal(data, &credentials)
+	if err != nil {
+		return fmt.Errorf("credentials file is not valid JSON: %w", err)
+	}
+
+	if len(credentials.Accounts) == 0 {
+		return fmt.Errorf("credentials file contains no accounts")
+	}
+
+	// The credentials can be defined for the account with or without the port.
+	account, ok := credentials.Accounts[accountName]
+	if !ok {
+		account, ok = credentials.Accounts[stripPort(accountName)]
+	}
+
+	if !ok {
+		return fmt.Errorf("credentials file contains no credentials for account %s", accountName)
+	}
+
+	if account.Secret == "" {
+		return fmt.Errorf("credentials file contains no secret for account %s", accountName)
+	}
+
+	return nil
+}
+
+// stripPort removes the port from the account name.
+func stripPort(accountName string) string {
+	idx := strings.LastIndex(accountName, ":")
+	if idx < 0 || idx < strings.LastIndex(accountName, "@") {
+		return accountName
+	}
+
+	return accountName[:idx]
+}
+
+// GetBlobStoreEndpoint returns the HTTP endpoint of the bucket in the blobstore.
+func GetBlobStoreEndpoint(configuration *fdbv1beta2.BlobStoreConfiguration, bucket string) string {
+	host := configuration.AccountName
+	if idx := strings.LastIndex(host, "@"); idx >= 0 {
+		host = host[idx+1:]
+	}
+
+	scheme := "https"
+	if !configuration.UsesSecureConnection() {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s/%s", scheme, host, bucket)
+}
+
+// isProbeAddressAllowed returns true if the probe of the blobstore is allowed to connect to the provided IP
+// address. Loopback, link-local, multicast and unspecified addresses, as well as the address of the Kubernetes API
+// server, are refused, to prevent the probe from reaching services that are internal to the operator's environment,
+// e.g. the metadata service of the cloud provider.
+var isProbeAddressAllowed = func(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	apiServer := net.ParseIP(os.Getenv("KUBERNETES_SERVICE_HOST"))
+
+	return apiServer == nil || !apiServer.Equal(ip)
+}
+
+// isClusterInternalHost returns true if the host is a name that is resolved by the DNS of the Kubernetes cluster.
+func isClusterInternalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	return host == "localhost" || host == "kubernetes" || strings.HasPrefix(host, "kubernetes.default") ||
+		strings.HasSuffix(host, ".svc") || strings.Contains(host, ".svc.") || strings.HasSuffix(host, ".cluster.local")
+}
+
+// getBlobStoreProbeClient returns the HTTP client for the probe of the blobstore. The client trusts the CA of
+// FDB_TLS_CA_FILE in addition to the system CAs, doesn't follow redirects or use a proxy and refuses to connect to
+// addresses that are not allowed by isProbeAddressAllowed. The addresses are checked after the name resolution, so
+// the check can't be bypassed by a DNS name that resolves to an internal address.
+func getBlobStoreProbeClient() (*http.Client, error) {
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		certPool = x509.NewCertPool()
+	}
+
+	caFile := os.Getenv("FDB_TLS_CA_FILE")
+	if caFile != "" {
+		caList, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		certPool.AppendCertsFromPEM(caList)
+	}
+
+	dialer := &net.Dialer{
+		Timeout: blobStoreProbeTimeout,
+		Control: func(_ string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || !isProbeAddressAllowed(ip) {
+				return fmt.Errorf("refusing to probe the internal address %s", host)
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: blobStoreProbeTimeout,
+		Transport: &http.Transport{
+			DialContext:     dialer.DialContext,
+			TLSClientConfig: &tls.Config{RootCAs: certPool},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// ProbeBlobStore sends an unauthenticated HEAD request to the bucket in the blobstore to check if the blobstore is
+// reachable. Only a successful response or an authentication challenge, which is returned by the blobstore because the
+// request is not authenticated, is accepted. Cluster-internal hosts and internal addresses are refused.
+func ProbeBlobStore(ctx context.Context, configuration *fdbv1beta2.BlobStoreConfiguration, bucket string) error {
+	endpoint := GetBlobStoreEndpoint(configuration, bucket)
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	if isClusterInternalHost(request.URL.Hostname()) {
+		return fmt.Errorf("refusing to probe the cluster-internal blobstore %s", endpoint)
+	}
+
+	probeClient, err := getBlobStoreProbeClient()
+	if err != nil {
+		return err
+	}
+
+	response, err := probeClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("blobstore %s is not reachable: %w", endpoint, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		return nil
+	}
+
+	return fmt.Errorf("blobstore %s returned unexpected status %s", endpoint, response.Status)
+}
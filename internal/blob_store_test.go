@@ -0,0 +1,244 @@
+/*
+ * blob_store_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("blob_store", func() {
+	When("getting the credentials secret", func() {
+		var spec *corev1.PodSpec
+
+		BeforeEach(func() {
+			spec = &corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: fdbv1beta2.MainContainerName,
+						Env: []corev1.EnvVar{
+							{
+								Name:  BlobCredentialsEnvironmentVariable,
+								Value: "/var/backup-credentials/credentials.json",
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{
+								Name:      "backup-credentials",
+								MountPath: "/var/backup-credentials/",
+							},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "backup-credentials",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "backup-secret"},
+						},
+					},
+				},
+			}
+		})
+
+		It("should return the secret and the file name as key", func() {
+			Expect(GetBlobCredentialsSecret(spec)).To(Equal(&corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "backup-secret"},
+				Key:                  "credentials.json",
+			}))
+		})
+
+		When("the secret volume maps the key to a different path", func() {
+			BeforeEach(func() {
+				spec.Volumes[0].Secret.Items = []corev1.KeyToPath{
+					{
+						Key:  "blob-credentials",
+						Path: "credentials.json",
+					},
+				}
+			})
+
+			It("should return the mapped key", func() {
+				Expect(GetBlobCredentialsSecret(spec).Key).To(Equal("blob-credentials"))
+			})
+		})
+
+		When("the credentials are not provided by a secret", func() {
+			BeforeEach(func() {
+				spec.Volumes[0].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+			})
+
+			It("should return nil", func() {
+				Expect(GetBlobCredentialsSecret(spec)).To(BeNil())
+			})
+		})
+
+		When("no credentials file is defined", func() {
+			BeforeEach(func() {
+				spec.Containers[0].Env = nil
+			})
+
+			It("should return nil", func() {
+				Expect(GetBlobCredentialsSecret(spec)).To(BeNil())
+			})
+		})
+	})
+
+	DescribeTable("validating the credentials", func(data string, accountName string, expected string) {
+		err := ValidateBlobCredentials([]byte(data), accountName)
+		if expected == "" {
+			Expect(err).NotTo(HaveOccurred())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(expected))
+	},
+		Entry("valid credentials",
+			`{"accounts":{"test@test-service":{"secret":"secret"}}}`,
+			"test@test-service",
+			""),
+		Entry("valid credentials without the port",
+			`{"accounts":{"test@test-service":{"secret":"secret"}}}`,
+			"test@test-service:443",
+			""),
+		Entry("invalid JSON",
+			`accounts: test`,
+			"test@test-service",
+			"credentials file is not valid JSON"),
+		Entry("no accounts",
+			`{"accounts":{}}`,
+			"test@test-service",
+			"credentials file contains no accounts"),
+		Entry("missing account",
+			`{"accounts":{"other@test-service":{"secret":"secret"}}}`,
+			"test@test-service",
+			"credentials file contains no credentials for account test@test-service"),
+		Entry("missing secret",
+			`{"accounts":{"test@test-service":{}}}`,
+			"test@test-service",
+			"credentials file contains no secret for account test@test-service"),
+	)
+
+	DescribeTable("getting the blobstore endpoint", func(configuration *fdbv1beta2.BlobStoreConfiguration, expected string) {
+		Expect(GetBlobStoreEndpoint(configuration, configuration.BucketName())).To(Equal(expected))
+	},
+		Entry("default configuration",
+			&fdbv1beta2.BlobStoreConfiguration{AccountName: "test@test-service"},
+			"https://test-service/fdb-backups"),
+		Entry("custom bucket and port",
+			&fdbv1beta2.BlobStoreConfiguration{AccountName: "test@test-service:9000", Bucket: "my-bucket"},
+			"https://test-service:9000/my-bucket"),
+		Entry("insecure connection",
+			&fdbv1beta2.BlobStoreConfiguration{AccountName: "test@test-service", URLParameters: []fdbv1beta2.URLParameter{"secure_connection=0"}},
+			"http://test-service/fdb-backups"),
+		Entry("insecure connection in the configuration",
+			&fdbv1beta2.BlobStoreConfiguration{AccountName: "test@test-service", SecureConnection: pointer.Bool(false)},
+			"http://test-service/fdb-backups"),
+	)
+
+	When("probing the blobstore", func() {
+		var server *httptest.Server
+		var status int
+		var configuration *fdbv1beta2.BlobStoreConfiguration
+		var previousCheck func(net.IP) bool
+
+		BeforeEach(func() {
+			status = http.StatusForbidden
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			}))
+			// The test server listens on the loopback address, which is refused by default.
+			previousCheck = isProbeAddressAllowed
+			isProbeAddressAllowed = func(net.IP) bool {
+				return true
+			}
+			configuration = &fdbv1beta2.BlobStoreConfiguration{
+				AccountName:   "test@" + strings.TrimPrefix(server.URL, "http://"),
+				URLParameters: []fdbv1beta2.URLParameter{"sc=0"},
+			}
+		})
+
+		AfterEach(func() {
+			isProbeAddressAllowed = previousCheck
+			server.Close()
+		})
+
+		It("should accept an authentication challenge of the blobstore", func() {
+			Expect(ProbeBlobStore(context.Background(), configuration, configuration.BucketName())).To(Succeed())
+		})
+
+		It("should accept a successful response of the blobstore", func() {
+			status = http.StatusOK
+			Expect(ProbeBlobStore(context.Background(), configuration, configuration.BucketName())).To(Succeed())
+		})
+
+		It("should return an error if the bucket is not found", func() {
+			status = http.StatusNotFound
+			Expect(ProbeBlobStore(context.Background(), configuration, configuration.BucketName())).NotTo(Succeed())
+		})
+
+		It("should return an error for a redirect", func() {
+			status = http.StatusMovedPermanently
+			Expect(ProbeBlobStore(context.Background(), configuration, configuration.BucketName())).NotTo(Succeed())
+		})
+
+		It("should return an error if the blobstore is not reachable", func() {
+			server.Close()
+			Expect(ProbeBlobStore(context.Background(), configuration, configuration.BucketName())).NotTo(Succeed())
+		})
+
+		It("should refuse the loopback address by default", func() {
+			isProbeAddressAllowed = previousCheck
+			err := ProbeBlobStore(context.Background(), configuration, configuration.BucketName())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("refusing to probe the internal address"))
+		})
+
+		It("should refuse a cluster-internal host", func() {
+			configuration.AccountName = "test@minio.default.svc.cluster.local:9000"
+			err := ProbeBlobStore(context.Background(), configuration, configuration.BucketName())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("refusing to probe the cluster-internal blobstore"))
+		})
+	})
+
+	DescribeTable("checking if the probe may connect to an address", func(address string, expected bool) {
+		Expect(isProbeAddressAllowed(net.ParseIP(address))).To(Equal(expected))
+	},
+		Entry("a public address", "203.0.113.10", true),
+		Entry("a loopback address", "127.0.0.1", false),
+		Entry("the metadata service", "169.254.169.254", false),
+		Entry("an IPv6 link-local address", "fe80::1", false),
+		Entry("the unspecified address", "0.0.0.0", false),
+		Entry("a multicast address", "224.0.0.1", false),
+	)
+})
@@ -0,0 +1,215 @@
+/*
+ * cert_manager.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// tlsCertificateDirectory defines the directory where the certificates issued by cert-manager are mounted.
+	tlsCertificateDirectory = "/var/fdb-certs"
+
+	// tlsCertificateVolumeName defines the name of the volume for the certificates issued by cert-manager.
+	tlsCertificateVolumeName = "fdb-certs"
+
+	// tlsCACertificateKey defines the key of the CA certificate in the secrets created by cert-manager.
+	tlsCACertificateKey = "ca.crt"
+
+	// CertificateNameAnnotation defines the annotation that cert-manager sets on the secrets it creates, the value is
+	// the name of the Certificate that the secret was issued for.
+	CertificateNameAnnotation = "cert-manager.io/certificate-name"
+)
+
+// CertificateGroupVersionKind defines the kind of the Certificates of cert-manager. The operator uses unstructured
+// objects for Certificates, so it doesn't depend on the cert-manager client libraries.
+var CertificateGroupVersionKind = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// GetCertificate builds the cert-manager Certificate for the process group. If the certificates have the Cluster
+// scope, the process group is ignored.
+func GetCertificate(cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) (*unstructured.Unstructured, error) {
+	config := cluster.Spec.CertManager
+	if config == nil {
+		return nil, fmt.Errorf("cluster %s/%s doesn't define the cert-manager settings", cluster.Namespace, cluster.Name)
+	}
+
+	name := cluster.GetCertificateName(processGroupID)
+	labels := cluster.GetMatchLabels()
+
+	commonName := config.CommonName
+	if commonName == "" {
+		commonName = cluster.Name
+	}
+
+	dnsNames := make([]interface{}, 0, len(config.DNSNames)+1)
+	for _, dnsName := range config.DNSNames {
+		dnsNames = append(dnsNames, dnsName)
+	}
+
+	if cluster.GetCertificateScope() == fdbv1beta2.CertificateScopeProcessGroup {
+		labels[fdbv1beta2.FDBProcessGroupIDLabel] = string(processGroupID)
+
+		if cluster.UseDNSInClusterFile() {
+			processClass, idNum, err := ParseProcessGroupID(processGroupID)
+			if err != nil {
+				return nil, err
+			}
+
+			podName, _ := GetProcessGroupID(cluster, processClass, idNum)
+			dnsNames = append(dnsNames, GetPodDNSName(cluster, processClass, podName))
+		}
+	}
+
+	issuerRef := map[string]interface{}{
+		"name":  config.IssuerRef.Name,
+		"kind":  "Issuer",
+		"group": CertificateGroupVersionKind.Group,
+	}
+
+	if config.IssuerRef.Kind != "" {
+		issuerRef["kind"] = config.IssuerRef.Kind
+	}
+
+	if config.IssuerRef.Group != "" {
+		issuerRef["group"] = config.IssuerRef.Group
+	}
+
+	secretLabels := make(map[string]interface{}, len(labels))
+	for key, value := range labels {
+		secretLabels[key] = value
+	}
+
+	// The processes use the same certificate for incoming and outgoing connections.
+	spec := map[string]interface{}{
+		"secretName": name,
+		"commonName": commonName,
+		"issuerRef":  issuerRef,
+		"usages":     []interface{}{"digital signature", "key encipherment", "server auth", "client auth"},
+		"privateKey": map[string]interface{}{
+			"rotationPolicy": "Always",
+		},
+		"secretTemplate": map[string]interface{}{
+			"labels": secretLabels,
+		},
+	}
+
+	if len(dnsNames) > 0 {
+		spec["dnsNames"] = dnsNames
+	}
+
+	if config.Duration != nil {
+		spec["duration"] = config.Duration.Duration.String()
+	}
+
+	if config.RenewBefore != nil {
+		spec["renewBefore"] = config.RenewBefore.Duration.String()
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(CertificateGroupVersionKind)
+	certificate.SetNamespace(cluster.Namespace)
+	certificate.SetName(name)
+	certificate.SetLabels(labels)
+	certificate.SetOwnerReferences(BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta))
+	certificate.Object["spec"] = spec
+
+	return certificate, nil
+}
+
+// IsCertificateReady returns true if cert-manager has issued the certificate and the certificate is up to date.
+func IsCertificateReady(certificate *unstructured.Unstructured) bool {
+	conditions, found, err := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	if err != nil || !found {
+		return false
+	}
+
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if conditionMap["type"] == "Ready" {
+			return conditionMap["status"] == string(corev1.ConditionTrue)
+		}
+	}
+
+	return false
+}
+
+// GetTLSCertificateHash returns the hash of the certificate in the secret that was created by cert-manager.
+func GetTLSCertificateHash(secret *corev1.Secret) (string, error) {
+	certificate, ok := secret.Data[corev1.TLSCertKey]
+	if !ok || len(certificate) == 0 {
+		return "", fmt.Errorf("secret %s has no key %s", secret.Name, corev1.TLSCertKey)
+	}
+
+	return GetTLSCertificateDataHash(certificate), nil
+}
+
+// configureCertManager mounts the certificate issued by cert-manager into the containers and sets the environment
+// variables for the certificate files. Environment variables that are defined in the Pod template take precedence.
+// A renewed certificate doesn't change the Pod spec, the kubelet updates the mounted secret and the processes are
+// restarted by the bounceRenewedCertificates sub-reconciler.
+func configureCertManager(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, processGroupID fdbv1beta2.ProcessGroupID, mainContainer *corev1.Container, sidecarContainer *corev1.Container) {
+	if !cluster.CertManagerEnabled() {
+		return
+	}
+
+	certificateName := cluster.GetCertificateName(processGroupID)
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: tlsCertificateVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: certificateName,
+			},
+		},
+	})
+
+	env := []corev1.EnvVar{
+		{Name: "FDB_TLS_CERTIFICATE_FILE", Value: fmt.Sprintf("%s/%s", tlsCertificateDirectory, corev1.TLSCertKey)},
+		{Name: "FDB_TLS_KEY_FILE", Value: fmt.Sprintf("%s/%s", tlsCertificateDirectory, corev1.TLSPrivateKeyKey)},
+	}
+
+	// If no trusted CAs are defined, the CA of the issuer is used.
+	if len(cluster.Spec.TrustedCAs) == 0 {
+		env = append(env, corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: fmt.Sprintf("%s/%s", tlsCertificateDirectory, tlsCACertificateKey)})
+	}
+
+	for _, container := range []*corev1.Container{mainContainer, sidecarContainer} {
+		if container == nil {
+			continue
+		}
+
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: tlsCertificateVolumeName, MountPath: tlsCertificateDirectory, ReadOnly: true})
+		extendEnv(container, env...)
+	}
+
+}
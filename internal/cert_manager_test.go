@@ -0,0 +1,266 @@
+/*
+ * cert_manager_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("cert-manager", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		cluster.Spec.CertManager = &fdbv1beta2.CertManagerSpec{
+			IssuerRef: fdbv1beta2.CertManagerIssuerReference{Name: "fdb-issuer"},
+		}
+	})
+
+	Describe("GetCertificate", func() {
+		When("the certificates have the Cluster scope", func() {
+			It("should build one certificate for the cluster", func() {
+				certificate, err := GetCertificate(cluster, "storage-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(certificate.GroupVersionKind()).To(Equal(CertificateGroupVersionKind))
+				Expect(certificate.GetName()).To(Equal("operator-test-1-tls"))
+				Expect(certificate.GetNamespace()).To(Equal(cluster.Namespace))
+				Expect(certificate.GetLabels()).To(Equal(cluster.GetMatchLabels()))
+				Expect(certificate.GetOwnerReferences()).To(HaveLen(1))
+				Expect(certificate.Object["spec"]).To(Equal(map[string]interface{}{
+					"secretName": "operator-test-1-tls",
+					"commonName": cluster.Name,
+					"issuerRef": map[string]interface{}{
+						"name":  "fdb-issuer",
+						"kind":  "Issuer",
+						"group": "cert-manager.io",
+					},
+					"usages": []interface{}{"digital signature", "key encipherment", "server auth", "client auth"},
+					"privateKey": map[string]interface{}{
+						"rotationPolicy": "Always",
+					},
+					"secretTemplate": map[string]interface{}{
+						"labels": map[string]interface{}{
+							fdbv1beta2.FDBClusterLabel: cluster.Name,
+						},
+					},
+				}))
+			})
+		})
+
+		When("the cert-manager settings are customized", func() {
+			BeforeEach(func() {
+				cluster.Spec.CertManager.IssuerRef.Kind = "ClusterIssuer"
+				cluster.Spec.CertManager.CommonName = "sample-cluster.foundationdb.example"
+				cluster.Spec.CertManager.DNSNames = []string{"sample-cluster.example"}
+				cluster.Spec.CertManager.Duration = &metav1.Duration{Duration: 90 * 24 * time.Hour}
+				cluster.Spec.CertManager.RenewBefore = &metav1.Duration{Duration: 15 * 24 * time.Hour}
+			})
+
+			It("should use the settings in the certificate", func() {
+				certificate, err := GetCertificate(cluster, "")
+				Expect(err).NotTo(HaveOccurred())
+
+				spec := certificate.Object["spec"].(map[string]interface{})
+				Expect(spec["commonName"]).To(Equal("sample-cluster.foundationdb.example"))
+				Expect(spec["dnsNames"]).To(Equal([]interface{}{"sample-cluster.example"}))
+				Expect(spec["duration"]).To(Equal("2160h0m0s"))
+				Expect(spec["renewBefore"]).To(Equal("360h0m0s"))
+				Expect(spec["issuerRef"]).To(HaveKeyWithValue("kind", "ClusterIssuer"))
+			})
+		})
+
+		When("the certificates have the ProcessGroup scope", func() {
+			BeforeEach(func() {
+				scope := fdbv1beta2.CertificateScopeProcessGroup
+				cluster.Spec.CertManager.Scope = &scope
+			})
+
+			It("should build a certificate for the process group", func() {
+				certificate, err := GetCertificate(cluster, "storage-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(certificate.GetName()).To(Equal("operator-test-1-storage-1-tls"))
+				Expect(certificate.GetLabels()).To(HaveKeyWithValue(fdbv1beta2.FDBProcessGroupIDLabel, "storage-1"))
+
+				spec := certificate.Object["spec"].(map[string]interface{})
+				Expect(spec["secretName"]).To(Equal("operator-test-1-storage-1-tls"))
+				Expect(spec).NotTo(HaveKey("dnsNames"))
+			})
+
+			When("the cluster uses DNS names in the cluster file", func() {
+				BeforeEach(func() {
+					cluster.Spec.Version = fdbv1beta2.Versions.SupportsDNSInClusterFile.String()
+					cluster.Status.RunningVersion = cluster.Spec.Version
+					cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+				})
+
+				It("should add the DNS name of the Pod", func() {
+					certificate, err := GetCertificate(cluster, "storage-1")
+					Expect(err).NotTo(HaveOccurred())
+
+					spec := certificate.Object["spec"].(map[string]interface{})
+					Expect(spec["dnsNames"]).To(Equal([]interface{}{
+						GetPodDNSName(cluster, fdbv1beta2.ProcessClassStorage, "operator-test-1-storage-1"),
+					}))
+				})
+			})
+		})
+
+		When("the cert-manager settings are missing", func() {
+			BeforeEach(func() {
+				cluster.Spec.CertManager = nil
+			})
+
+			It("should return an error", func() {
+				_, err := GetCertificate(cluster, "")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	DescribeTable("checking if a certificate is ready", func(status map[string]interface{}, expected bool) {
+		certificate := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if status != nil {
+			certificate.Object["status"] = status
+		}
+
+		Expect(IsCertificateReady(certificate)).To(Equal(expected))
+	},
+		Entry("without a status", nil, false),
+		Entry("with a ready certificate", map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Issuing", "status": "False"},
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		}, true),
+		Entry("with a certificate that is not ready", map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+			},
+		}, false),
+	)
+
+	Describe("GetTLSCertificateHash", func() {
+		It("should only depend on the certificate", func() {
+			hash, err := GetTLSCertificateHash(&corev1.Secret{Data: map[string][]byte{
+				corev1.TLSCertKey:       []byte("certificate"),
+				corev1.TLSPrivateKeyKey: []byte("key"),
+			}})
+			Expect(err).NotTo(HaveOccurred())
+
+			otherHash, err := GetTLSCertificateHash(&corev1.Secret{Data: map[string][]byte{
+				corev1.TLSCertKey: []byte("certificate"),
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal(otherHash))
+		})
+
+		It("should return an error if the secret has no certificate", func() {
+			_, err := GetTLSCertificateHash(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "fdb-certs"}})
+			Expect(err).To(MatchError("secret fdb-certs has no key tls.crt"))
+		})
+	})
+
+	Describe("configuring the Pod spec", func() {
+		var spec *corev1.PodSpec
+
+		JustBeforeEach(func() {
+			err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should mount the certificate into the main and the sidecar container", func() {
+			Expect(spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "fdb-certs",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: "operator-test-1-tls"},
+				},
+			}))
+
+			for _, container := range spec.Containers {
+				Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "fdb-certs", MountPath: "/var/fdb-certs", ReadOnly: true}))
+				Expect(container.Env).To(ContainElements(
+					corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: "/var/fdb-certs/tls.crt"},
+					corev1.EnvVar{Name: "FDB_TLS_KEY_FILE", Value: "/var/fdb-certs/tls.key"},
+					corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: "/var/fdb-certs/ca.crt"},
+				))
+			}
+		})
+
+		When("the certificate was renewed", func() {
+			BeforeEach(func() {
+				cluster.Status.TLSCertificateHashes = map[string]string{"operator-test-1-tls": "abc"}
+			})
+
+			It("should not change the Pod spec", func() {
+				cluster.Status.TLSCertificateHashes["operator-test-1-tls"] = "def"
+				renewedSpec, err := GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(renewedSpec).To(Equal(spec))
+			})
+		})
+
+		When("the cluster defines trusted CAs", func() {
+			BeforeEach(func() {
+				cluster.Spec.TrustedCAs = []string{"ca"}
+			})
+
+			It("should use the CA file of the trusted CAs", func() {
+				Expect(spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: "/var/dynamic-conf/ca.pem"}))
+				Expect(spec.Containers[0].Env).NotTo(ContainElement(corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: "/var/fdb-certs/ca.crt"}))
+			})
+		})
+
+		When("the Pod template defines the certificate file", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+					fdbv1beta2.ProcessClassGeneral: {
+						PodTemplate: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: fdbv1beta2.MainContainerName,
+										Env:  []corev1.EnvVar{{Name: "FDB_TLS_CERTIFICATE_FILE", Value: "/var/custom/cert.pem"}},
+									},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("should keep the environment variable of the Pod template", func() {
+				Expect(spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: "/var/custom/cert.pem"}))
+				Expect(spec.Containers[0].Env).NotTo(ContainElement(corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: "/var/fdb-certs/tls.crt"}))
+			})
+		})
+	})
+})
@@ -0,0 +1,303 @@
+/*
+ * client_library_cache.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// ClientLibraryCacheContainerName defines the name of the container that keeps the Pods of a client library
+	// cache running.
+	ClientLibraryCacheContainerName = "client-library-cache"
+
+	// clientLibraryCacheVolumeName defines the name of the volume that contains the client libraries.
+	clientLibraryCacheVolumeName = "client-libraries"
+
+	// clientLibraryCacheOutputDir defines the directory in the containers where the volume is mounted.
+	clientLibraryCacheOutputDir = "/var/output-files"
+)
+
+// ValidateClientLibraryCache checks that the host path of the cache is inside the directory of the namespace of the
+// cache in the hostPathRoot and that the Pod template doesn't request any host access or privileges, since the
+// workloads of a cache are created by the operator on behalf of users that might not be allowed to create them.
+func ValidateClientLibraryCache(cache *fdbv1beta2.FoundationDBClientLibraryCache, hostPathRoot string) error {
+	if hostPathRoot == "" {
+		hostPathRoot = fdbv1beta2.DefaultClientLibraryCacheHostPathRoot
+	}
+
+	if cache.GetMode() == fdbv1beta2.ClientLibraryCacheModeNode {
+		namespaceDir := path.Join(hostPathRoot, cache.Namespace)
+		hostPath := cache.GetHostPath(hostPathRoot)
+		if !path.IsAbs(hostPath) || !strings.HasPrefix(path.Clean(hostPath), namespaceDir+"/") {
+			return fmt.Errorf("host path %s must be a directory inside %s", hostPath, namespaceDir)
+		}
+	}
+
+	if cache.Spec.PodTemplate == nil {
+		return nil
+	}
+
+	podSpec := cache.Spec.PodTemplate.Spec
+	if podSpec.HostNetwork || podSpec.HostPID || podSpec.HostIPC {
+		return fmt.Errorf("pod template must not use the host network, PID or IPC namespace")
+	}
+
+	for _, volume := range podSpec.Volumes {
+		if volume.HostPath != nil {
+			return fmt.Errorf("pod template must not contain the hostPath volume %s", volume.Name)
+		}
+	}
+
+	containers := make([]corev1.Container, 0, len(podSpec.InitContainers)+len(podSpec.Containers))
+	containers = append(containers, podSpec.InitContainers...)
+	containers = append(containers, podSpec.Containers...)
+	for _, container := range containers {
+		if container.SecurityContext == nil {
+			continue
+		}
+
+		if pointer.BoolDeref(container.SecurityContext.Privileged, false) {
+			return fmt.Errorf("container %s in the pod template must not be privileged", container.Name)
+		}
+
+		if container.SecurityContext.Capabilities != nil && len(container.SecurityContext.Capabilities.Add) > 0 {
+			return fmt.Errorf("container %s in the pod template must not add capabilities", container.Name)
+		}
+	}
+
+	return nil
+}
+
+// GetClientLibraryCacheDaemonSet builds the DaemonSet that copies the client libraries into a directory on every node.
+// The directory must be inside the hostPathRoot of the operator.
+func GetClientLibraryCacheDaemonSet(cache *fdbv1beta2.FoundationDBClientLibraryCache, versions []fdbv1beta2.Version, hostPathRoot string) (*appsv1.DaemonSet, error) {
+	err := ValidateClientLibraryCache(cache, hostPathRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	hostPathType := corev1.HostPathDirectoryOrCreate
+	podTemplate, err := getClientLibraryCachePodTemplate(cache, versions, corev1.VolumeSource{
+		HostPath: &corev1.HostPathVolumeSource{
+			Path: path.Clean(cache.GetHostPath(hostPathRoot)),
+			Type: &hostPathType,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: getClientLibraryCacheMetadata(cache),
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				fdbv1beta2.ClientLibraryCacheLabel: cache.Name,
+			}},
+			Template: *podTemplate,
+		},
+	}
+
+	return daemonSet, setClientLibraryCacheSpecHash(&daemonSet.ObjectMeta, daemonSet.Spec)
+}
+
+// GetClientLibraryCacheDeployment builds the Deployment that copies the client libraries into the
+// PersistentVolumeClaim of the cache.
+func GetClientLibraryCacheDeployment(cache *fdbv1beta2.FoundationDBClientLibraryCache, versions []fdbv1beta2.Version) (*appsv1.Deployment, error) {
+	err := ValidateClientLibraryCache(cache, "")
+	if err != nil {
+		return nil, err
+	}
+
+	podTemplate, err := getClientLibraryCachePodTemplate(cache, versions, corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: cache.Name,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: getClientLibraryCacheMetadata(cache),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				fdbv1beta2.ClientLibraryCacheLabel: cache.Name,
+			}},
+			Template: *podTemplate,
+			// The volume might only be attachable to a single node, so the old Pod must be removed before the new
+			// Pod is created.
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+		},
+	}
+
+	return deployment, setClientLibraryCacheSpecHash(&deployment.ObjectMeta, deployment.Spec)
+}
+
+// GetClientLibraryCachePVC builds the PersistentVolumeClaim that stores the client libraries in the Namespace mode.
+func GetClientLibraryCachePVC(cache *fdbv1beta2.FoundationDBClientLibraryCache) *corev1.PersistentVolumeClaim {
+	var pvc *corev1.PersistentVolumeClaim
+	if cache.Spec.VolumeClaimTemplate != nil {
+		pvc = cache.Spec.VolumeClaimTemplate.DeepCopy()
+	} else {
+		pvc = &corev1.PersistentVolumeClaim{}
+	}
+
+	metadata := getClientLibraryCacheMetadata(cache)
+	for key, value := range pvc.Labels {
+		metadata.Labels[key] = value
+	}
+	metadata.Annotations = pvc.Annotations
+	pvc.ObjectMeta = metadata
+
+	if len(pvc.Spec.AccessModes) == 0 {
+		pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+	}
+
+	if pvc.Spec.Resources.Requests == nil {
+		pvc.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+
+	if _, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("1Gi")
+	}
+
+	return pvc
+}
+
+// getClientLibraryCacheMetadata returns the metadata for the resources that are created for a client library cache.
+func getClientLibraryCacheMetadata(cache *fdbv1beta2.FoundationDBClientLibraryCache) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cache.Namespace,
+		Name:      cache.Name,
+		Labels: map[string]string{
+			fdbv1beta2.ClientLibraryCacheLabel: cache.Name,
+		},
+		OwnerReferences: BuildOwnerReference(cache.TypeMeta, cache.ObjectMeta),
+	}
+}
+
+// getClientLibraryCachePodTemplate builds the Pod template for a client library cache. The template contains an init
+// container for every version that copies the client library of this version into the provided volume.
+func getClientLibraryCachePodTemplate(cache *fdbv1beta2.FoundationDBClientLibraryCache, versions []fdbv1beta2.Version, volumeSource corev1.VolumeSource) (*corev1.PodTemplateSpec, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions to cache for client library cache %s", cache.Name)
+	}
+
+	var podTemplate *corev1.PodTemplateSpec
+	if cache.Spec.PodTemplate != nil {
+		podTemplate = cache.Spec.PodTemplate.DeepCopy()
+	} else {
+		podTemplate = &corev1.PodTemplateSpec{}
+	}
+
+	if podTemplate.ObjectMeta.Labels == nil {
+		podTemplate.ObjectMeta.Labels = make(map[string]string, 1)
+	}
+	podTemplate.ObjectMeta.Labels[fdbv1beta2.ClientLibraryCacheLabel] = cache.Name
+
+	volumeMount := corev1.VolumeMount{Name: clientLibraryCacheVolumeName, MountPath: clientLibraryCacheOutputDir}
+	imageConfigs := cache.GetImageConfigs()
+	architecture, err := getArchitectureFromImageConfigs("client library cache", cache.Name, imageConfigs)
+	if err != nil {
+		return nil, err
+	}
+
+	initContainers := make([]corev1.Container, 0, len(versions)+len(podTemplate.Spec.InitContainers))
+	for _, version := range versions {
+		initContainers = append(initContainers, corev1.Container{
+			Name:         fmt.Sprintf("copy-library-%s", strings.ReplaceAll(version.Compact(), ".", "-")),
+			Image:        fdbv1beta2.SelectImageConfigForArchitecture(imageConfigs, version.String(), architecture).Image(),
+			Args:         []string{"--copy-library", version.Compact(), "--output-dir", clientLibraryCacheOutputDir, "--init-mode"},
+			VolumeMounts: []corev1.VolumeMount{volumeMount},
+		})
+	}
+	podTemplate.Spec.InitContainers = append(initContainers, podTemplate.Spec.InitContainers...)
+
+	var mainContainer *corev1.Container
+	for index, container := range podTemplate.Spec.Containers {
+		if container.Name == ClientLibraryCacheContainerName {
+			mainContainer = &podTemplate.Spec.Containers[index]
+		}
+	}
+
+	if mainContainer == nil {
+		containers := []corev1.Container{
+			{
+				Name: ClientLibraryCacheContainerName,
+			},
+		}
+		containers = append(containers, podTemplate.Spec.Containers...)
+		mainContainer = &containers[0]
+		podTemplate.Spec.Containers = containers
+	}
+
+	// The main container keeps the Pod running and refreshes the library of the newest version.
+	newestVersion := versions[len(versions)-1]
+	image, err := GetImageForArchitecture(mainContainer.Image, imageConfigs, newestVersion.String(), architecture, false)
+	if err != nil {
+		return nil, err
+	}
+	mainContainer.Image = image
+	mainContainer.Args = []string{"--copy-library", newestVersion.Compact(), "--output-dir", clientLibraryCacheOutputDir}
+	mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, volumeMount)
+
+	if mainContainer.Resources.Requests == nil {
+		mainContainer.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
+
+	configureNodeSelectorForArchitecture(&podTemplate.Spec, architecture)
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, corev1.Volume{
+		Name:         clientLibraryCacheVolumeName,
+		VolumeSource: volumeSource,
+	})
+
+	return podTemplate, nil
+}
+
+// setClientLibraryCacheSpecHash stores the hash of the workload spec in the annotations, so changes can be detected
+// without comparing the defaulted fields.
+func setClientLibraryCacheSpecHash(metadata *metav1.ObjectMeta, spec interface{}) error {
+	specHash, err := GetJSONHash(spec)
+	if err != nil {
+		return err
+	}
+
+	metadata.Annotations = map[string]string{
+		fdbv1beta2.LastSpecKey: specHash,
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+/*
+ * client_library_cache_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("client_library_cache", func() {
+	var cache *fdbv1beta2.FoundationDBClientLibraryCache
+	var versions []fdbv1beta2.Version
+
+	BeforeEach(func() {
+		cache = &fdbv1beta2.FoundationDBClientLibraryCache{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "libraries",
+				Namespace: "my-ns",
+			},
+		}
+		versions = []fdbv1beta2.Version{
+			{Major: 6, Minor: 3, Patch: 24},
+			{Major: 7, Minor: 1, Patch: 26},
+		}
+	})
+
+	Describe("GetClientLibraryCacheDaemonSet", func() {
+		It("should add an init container for every version", func() {
+			daemonSet, err := GetClientLibraryCacheDaemonSet(cache, versions, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(daemonSet.Annotations).To(HaveKey(fdbv1beta2.LastSpecKey))
+
+			initContainers := daemonSet.Spec.Template.Spec.InitContainers
+			Expect(initContainers).To(HaveLen(2))
+			Expect(initContainers[0].Name).To(Equal("copy-library-6-3"))
+			Expect(initContainers[0].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:6.3.24-1"))
+			Expect(initContainers[1].Name).To(Equal("copy-library-7-1"))
+			Expect(initContainers[1].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:7.1.26-1"))
+
+			containers := daemonSet.Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(1))
+			Expect(containers[0].Name).To(Equal(ClientLibraryCacheContainerName))
+			Expect(containers[0].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:7.1.26-1"))
+			Expect(containers[0].Args).To(Equal([]string{"--copy-library", "7.1", "--output-dir", "/var/output-files"}))
+		})
+
+		It("should change the hash when the versions change", func() {
+			daemonSet, err := GetClientLibraryCacheDaemonSet(cache, versions, "")
+			Expect(err).NotTo(HaveOccurred())
+			updatedDaemonSet, err := GetClientLibraryCacheDaemonSet(cache, versions[:1], "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updatedDaemonSet.Annotations[fdbv1beta2.LastSpecKey]).NotTo(Equal(daemonSet.Annotations[fdbv1beta2.LastSpecKey]))
+		})
+
+		It("should return an error without versions", func() {
+			_, err := GetClientLibraryCacheDaemonSet(cache, nil, "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		When("the image configs define images for a single architecture", func() {
+			BeforeEach(func() {
+				cache.Spec.ImageConfigs = []fdbv1beta2.ImageConfig{
+					{Architecture: "arm64", TagSuffix: "-1-arm64"},
+					{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar"},
+				}
+			})
+
+			It("should schedule the Pods on nodes of the architecture and use the images for the architecture", func() {
+				daemonSet, err := GetClientLibraryCacheDaemonSet(cache, versions, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(daemonSet.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelArchStable, "arm64"))
+				Expect(daemonSet.Spec.Template.Spec.InitContainers[0].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:6.3.24-1-arm64"))
+				Expect(daemonSet.Spec.Template.Spec.Containers[0].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:7.1.26-1-arm64"))
+			})
+
+			When("the image configs define images for multiple architectures", func() {
+				BeforeEach(func() {
+					cache.Spec.ImageConfigs = append(cache.Spec.ImageConfigs, fdbv1beta2.ImageConfig{Architecture: "amd64", TagSuffix: "-1-amd64"})
+				})
+
+				It("should return an error", func() {
+					_, err := GetClientLibraryCacheDaemonSet(cache, versions, "")
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+
+		When("a pod template is provided", func() {
+			BeforeEach(func() {
+				cache.Spec.PodTemplate = &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+						Containers: []corev1.Container{
+							{
+								Name: ClientLibraryCacheContainerName,
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("should merge the pod template", func() {
+				daemonSet, err := GetClientLibraryCacheDaemonSet(cache, versions, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(daemonSet.Spec.Template.Spec.Tolerations).To(HaveLen(1))
+				Expect(daemonSet.Spec.Template.Spec.Containers).To(HaveLen(1))
+				Expect(daemonSet.Spec.Template.Spec.Containers[0].Resources.Requests).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("1")))
+			})
+		})
+
+		When("a host path root is provided", func() {
+			It("should use the host path root for the default host path", func() {
+				daemonSet, err := GetClientLibraryCacheDaemonSet(cache, versions, "/mnt/libraries")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(daemonSet.Spec.Template.Spec.Volumes).To(HaveLen(1))
+				Expect(daemonSet.Spec.Template.Spec.Volumes[0].HostPath.Path).To(Equal("/mnt/libraries/my-ns/libraries"))
+			})
+
+			It("should reject a host path outside of the host path root", func() {
+				cache.Spec.HostPath = "/etc"
+				_, err := GetClientLibraryCacheDaemonSet(cache, versions, "/mnt/libraries")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ValidateClientLibraryCache", func() {
+		It("should accept the defaults", func() {
+			Expect(ValidateClientLibraryCache(cache, "")).To(Succeed())
+		})
+
+		It("should accept a host path inside the directory of the namespace", func() {
+			cache.Spec.HostPath = "/var/lib/fdb-client-libraries/my-ns/custom"
+			Expect(ValidateClientLibraryCache(cache, "")).To(Succeed())
+		})
+
+		It("should reject a host path of another namespace", func() {
+			cache.Spec.HostPath = "/var/lib/fdb-client-libraries/other-ns/libraries"
+			Expect(ValidateClientLibraryCache(cache, "")).NotTo(Succeed())
+		})
+
+		It("should reject a host path that leaves the directory of the namespace", func() {
+			cache.Spec.HostPath = "/var/lib/fdb-client-libraries/my-ns/../../../../etc"
+			Expect(ValidateClientLibraryCache(cache, "")).NotTo(Succeed())
+		})
+
+		It("should ignore the host path in the Namespace mode", func() {
+			cache.Spec.Mode = fdbv1beta2.ClientLibraryCacheModeNamespace
+			cache.Spec.HostPath = "/etc"
+			Expect(ValidateClientLibraryCache(cache, "")).To(Succeed())
+		})
+
+		It("should reject a pod template with a hostPath volume", func() {
+			cache.Spec.PodTemplate = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{Name: "root", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}},
+					},
+				},
+			}
+			Expect(ValidateClientLibraryCache(cache, "")).NotTo(Succeed())
+		})
+
+		It("should reject a pod template with a privileged container", func() {
+			cache.Spec.PodTemplate = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            ClientLibraryCacheContainerName,
+							SecurityContext: &corev1.SecurityContext{Privileged: pointer.Bool(true)},
+						},
+					},
+				},
+			}
+			Expect(ValidateClientLibraryCache(cache, "")).NotTo(Succeed())
+		})
+
+		It("should reject a pod template that uses the host network", func() {
+			cache.Spec.PodTemplate = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{HostNetwork: true},
+			}
+			Expect(ValidateClientLibraryCache(cache, "")).NotTo(Succeed())
+		})
+	})
+
+	Describe("GetClientLibraryCachePVC", func() {
+		It("should use the defaults", func() {
+			pvc := GetClientLibraryCachePVC(cache)
+			Expect(pvc.Name).To(Equal("libraries"))
+			Expect(pvc.Labels).To(HaveKeyWithValue(fdbv1beta2.ClientLibraryCacheLabel, "libraries"))
+			Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+			Expect(pvc.Spec.Resources.Requests).To(HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("1Gi")))
+		})
+
+		When("a volume claim template is provided", func() {
+			BeforeEach(func() {
+				storageClass := "nfs"
+				cache.Spec.VolumeClaimTemplate = &corev1.PersistentVolumeClaim{
+					Spec: corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &storageClass,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+						},
+					},
+				}
+			})
+
+			It("should use the template", func() {
+				pvc := GetClientLibraryCachePVC(cache)
+				Expect(*pvc.Spec.StorageClassName).To(Equal("nfs"))
+				Expect(pvc.Spec.Resources.Requests).To(HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("5Gi")))
+			})
+		})
+	})
+})
@@ -0,0 +1,114 @@
+/*
+ * clone.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// CloneProcessGroup defines a process group of a clone and the process group of the source cluster it is created from.
+type CloneProcessGroup struct {
+	// ProcessGroupID defines the ID of the process group in the clone.
+	ProcessGroupID fdbv1beta2.ProcessGroupID
+
+	// ProcessClass defines the process class of the process group.
+	ProcessClass fdbv1beta2.ProcessClass
+
+	// SourceProcessGroupID defines the ID of the process group in the source cluster.
+	SourceProcessGroupID fdbv1beta2.ProcessGroupID
+
+	// VolumeSnapshot defines the name of the VolumeSnapshot of the data volume of the source process group.
+	VolumeSnapshot string
+}
+
+// GetCloneProcessGroups maps the process groups of the source cluster that have a VolumeSnapshot to the process groups
+// of the clone. The process groups of the clone have the same process class and number as the source process groups,
+// but use the process group ID prefix of the clone. The result is keyed by the process group ID of the clone.
+func GetCloneProcessGroups(cluster *fdbv1beta2.FoundationDBCluster, source *fdbv1beta2.FoundationDBCluster) (map[fdbv1beta2.ProcessGroupID]CloneProcessGroup, error) {
+	if cluster.Spec.CloneFrom == nil {
+		return nil, nil
+	}
+
+	processGroups := make(map[fdbv1beta2.ProcessGroupID]CloneProcessGroup, len(cluster.Spec.CloneFrom.VolumeSnapshots))
+	for sourceProcessGroupID, volumeSnapshot := range cluster.Spec.CloneFrom.VolumeSnapshots {
+		sourceProcessGroup := fdbv1beta2.FindProcessGroupByID(source.Status.ProcessGroups, sourceProcessGroupID)
+		if sourceProcessGroup == nil {
+			return nil, fmt.Errorf("could not find process group %s in cluster %s", sourceProcessGroupID, source.Name)
+		}
+
+		_, idNum, err := ParseProcessGroupID(sourceProcessGroupID)
+		if err != nil {
+			return nil, err
+		}
+
+		_, processGroupID := GetProcessGroupID(cluster, sourceProcessGroup.ProcessClass, idNum)
+		processGroups[processGroupID] = CloneProcessGroup{
+			ProcessGroupID:       processGroupID,
+			ProcessClass:         sourceProcessGroup.ProcessClass,
+			SourceProcessGroupID: sourceProcessGroupID,
+			VolumeSnapshot:       volumeSnapshot,
+		}
+	}
+
+	return processGroups, nil
+}
+
+// GetSourceProcessGroupForAddress returns the process group of the source cluster that serves the provided address.
+// Addresses that use a DNS name are matched against the Pod name of the process group.
+func GetSourceProcessGroupForAddress(source *fdbv1beta2.FoundationDBCluster, address fdbv1beta2.ProcessAddress) (*fdbv1beta2.ProcessGroupStatus, error) {
+	for _, processGroup := range source.Status.ProcessGroups {
+		if address.StringAddress != "" {
+			_, idNum, err := ParseProcessGroupID(processGroup.ProcessGroupID)
+			if err != nil {
+				return nil, err
+			}
+
+			podName, _ := GetProcessGroupID(source, processGroup.ProcessClass, idNum)
+			if strings.Split(address.StringAddress, ".")[0] == podName {
+				return processGroup, nil
+			}
+
+			continue
+		}
+
+		for _, processGroupAddress := range processGroup.Addresses {
+			if processGroupAddress == address.MachineAddress() {
+				return processGroup, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("could not find the process group for address %s in cluster %s", address.String(), source.Name)
+}
+
+// GetVolumeSnapshotDataSource returns the data source for a PVC that is created from the provided VolumeSnapshot.
+func GetVolumeSnapshotDataSource(volumeSnapshot string) *corev1.TypedLocalObjectReference {
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: pointer.String(VolumeSnapshotGroupVersionKind.Group),
+		Kind:     VolumeSnapshotGroupVersionKind.Kind,
+		Name:     volumeSnapshot,
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * clone_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"net"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("clone", func() {
+	var source *fdbv1beta2.FoundationDBCluster
+	var cluster *fdbv1beta2.FoundationDBCluster
+
+	BeforeEach(func() {
+		source = CreateDefaultCluster()
+		source.Status.ProcessGroups = []*fdbv1beta2.ProcessGroupStatus{
+			fdbv1beta2.NewProcessGroupStatus("storage-1", fdbv1beta2.ProcessClassStorage, []string{"1.1.1.1"}),
+			fdbv1beta2.NewProcessGroupStatus("log-1", fdbv1beta2.ProcessClassLog, []string{"1.1.1.2"}),
+		}
+
+		cluster = CreateDefaultCluster()
+		cluster.Name = "operator-test-clone"
+		cluster.Spec.ProcessGroupIDPrefix = "clone"
+		cluster.Spec.CloneFrom = &fdbv1beta2.CloneFromSpec{
+			ClusterName: source.Name,
+			VolumeSnapshots: map[fdbv1beta2.ProcessGroupID]string{
+				"storage-1": "snapshot-storage-1",
+				"log-1":     "snapshot-log-1",
+			},
+		}
+	})
+
+	When("getting the process groups of a clone", func() {
+		It("should map the process groups of the source cluster", func() {
+			processGroups, err := GetCloneProcessGroups(cluster, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processGroups).To(Equal(map[fdbv1beta2.ProcessGroupID]CloneProcessGroup{
+				"clone-storage-1": {
+					ProcessGroupID:       "clone-storage-1",
+					ProcessClass:         fdbv1beta2.ProcessClassStorage,
+					SourceProcessGroupID: "storage-1",
+					VolumeSnapshot:       "snapshot-storage-1",
+				},
+				"clone-log-1": {
+					ProcessGroupID:       "clone-log-1",
+					ProcessClass:         fdbv1beta2.ProcessClassLog,
+					SourceProcessGroupID: "log-1",
+					VolumeSnapshot:       "snapshot-log-1",
+				},
+			}))
+		})
+
+		When("a process group is missing in the source cluster", func() {
+			BeforeEach(func() {
+				cluster.Spec.CloneFrom.VolumeSnapshots["storage-2"] = "snapshot-storage-2"
+			})
+
+			It("should return an error", func() {
+				_, err := GetCloneProcessGroups(cluster, source)
+				Expect(err).To(MatchError("could not find process group storage-2 in cluster operator-test-1"))
+			})
+		})
+	})
+
+	When("getting the process group of the source cluster for an address", func() {
+		It("should match IP addresses", func() {
+			processGroup, err := GetSourceProcessGroupForAddress(source, fdbv1beta2.ProcessAddress{IPAddress: net.ParseIP("1.1.1.2"), Port: 4501})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processGroup.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("log-1")))
+		})
+
+		It("should match DNS names by the Pod name", func() {
+			processGroup, err := GetSourceProcessGroupForAddress(source, fdbv1beta2.ProcessAddress{StringAddress: "operator-test-1-storage-1.operator-test-1.my-ns.svc.cluster.local", Port: 4501})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processGroup.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+		})
+
+		It("should return an error for unknown addresses", func() {
+			_, err := GetSourceProcessGroupForAddress(source, fdbv1beta2.ProcessAddress{IPAddress: net.ParseIP("1.1.1.9"), Port: 4501})
+			Expect(err).To(MatchError("could not find the process group for address 1.1.1.9:4501 in cluster operator-test-1"))
+		})
+	})
+})
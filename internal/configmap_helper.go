@@ -21,6 +21,7 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -33,13 +34,22 @@ import (
 const (
 	// ClusterFileKey defines the key name in the ConfigMap
 	ClusterFileKey = "cluster-file"
+
+	// AdminClientAuditLogKey defines the key name in the audit ConfigMap that contains the audit entries
+	AdminClientAuditLogKey = "audit-log.json"
 )
 
+// GetAdminClientAuditConfigMapName returns the name of the ConfigMap that contains the admin client audit log of a
+// cluster.
+func GetAdminClientAuditConfigMapName(cluster *fdbv1beta2.FoundationDBCluster) string {
+	return fmt.Sprintf("%s-admin-audit", cluster.Name)
+}
+
 // GetConfigMap builds a config map for a cluster's dynamic config
-func GetConfigMap(cluster *fdbv1beta2.FoundationDBCluster) (*corev1.ConfigMap, error) {
+func GetConfigMap(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) (*corev1.ConfigMap, error) {
 	data := make(map[string]string)
 
-	connectionString := cluster.Status.ConnectionString
+	connectionString := cluster.GetDesiredConnectionString()
 	data[ClusterFileKey] = connectionString
 	data["running-version"] = cluster.Status.RunningVersion
 
@@ -108,7 +118,7 @@ func GetConfigMap(cluster *fdbv1beta2.FoundationDBCluster) (*corev1.ConfigMap, e
 		if _, useSplitImage := imageTypes[FDBImageTypeSplit]; useSplitImage {
 			if processClass == fdbv1beta2.ProcessClassStorage {
 				for _, serversPerPod := range storageServersPerDisk {
-					err := setMonitorConfForFilename(cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, serversPerPod), connectionString, processClass, serversPerPod)
+					err := setMonitorConfForFilename(ctx, cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, serversPerPod), connectionString, processClass, serversPerPod)
 					if err != nil {
 						return nil, err
 					}
@@ -116,7 +126,7 @@ func GetConfigMap(cluster *fdbv1beta2.FoundationDBCluster) (*corev1.ConfigMap, e
 				continue
 			}
 
-			err := setMonitorConfForFilename(cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, 1), connectionString, processClass, 1)
+			err := setMonitorConfForFilename(ctx, cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, 1), connectionString, processClass, 1)
 			if err != nil {
 				return nil, err
 			}
@@ -155,11 +165,11 @@ func getConfigMapMetadata(cluster *fdbv1beta2.FoundationDBCluster) metav1.Object
 	return metadata
 }
 
-func setMonitorConfForFilename(cluster *fdbv1beta2.FoundationDBCluster, data map[string]string, filename string, connectionString string, processClass fdbv1beta2.ProcessClass, serversPerPod int) error {
+func setMonitorConfForFilename(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, data map[string]string, filename string, connectionString string, processClass fdbv1beta2.ProcessClass, serversPerPod int) error {
 	if connectionString == "" {
 		data[filename] = ""
 	} else {
-		conf, err := GetMonitorConf(cluster, processClass, nil, serversPerPod)
+		conf, err := GetMonitorConf(ctx, cluster, processClass, nil, serversPerPod)
 		if err != nil {
 			return err
 		}
@@ -21,6 +21,7 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -57,7 +58,7 @@ var _ = Describe("configmap_helper", func() {
 		})
 
 		JustBeforeEach(func() {
-			configMap, err = GetConfigMap(cluster)
+			configMap, err = GetConfigMap(context.TODO(), cluster)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -72,7 +73,7 @@ var _ = Describe("configmap_helper", func() {
 			})
 
 			It("should have the basic files", func() {
-				expectedConf, err := GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+				expectedConf, err := GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(configMap.Data[ClusterFileKey]).To(Equal("operator-test:asdfasf@127.0.0.1:4501"))
@@ -110,7 +111,7 @@ var _ = Describe("configmap_helper", func() {
 			})
 
 			It("includes the data for the split monitor conf", func() {
-				expectedConf, err := GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+				expectedConf, err := GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(configMap.Data["fdbmonitor-conf-storage"]).To(Equal(expectedConf))
 			})
@@ -144,11 +145,11 @@ var _ = Describe("configmap_helper", func() {
 					cluster.Status.ImageTypes = []fdbv1beta2.ImageType{"split"}
 				})
 				It("includes the data for both configurations", func() {
-					expectedConf, err := GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+					expectedConf, err := GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(configMap.Data["fdbmonitor-conf-storage"]).To(Equal(expectedConf))
 
-					expectedConf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 2)
+					expectedConf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 2)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(configMap.Data["fdbmonitor-conf-storage-density-2"]).To(Equal(expectedConf))
 				})
@@ -22,9 +22,11 @@ package internal
 
 import (
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/featuregates"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
 )
 
 // DeprecationOptions controls how deprecations and changes to defaults
@@ -37,6 +39,22 @@ type DeprecationOptions struct {
 	// Whether we should only fill in defaults that have changes between major
 	// versions of the operator.
 	OnlyShowChanges bool
+
+	// DefaultMainContainerImageConfigs defines image configs for the main
+	// container that take precedence over the default image configs of the
+	// operator. The image configs of the cluster spec still take precedence
+	// over those image configs.
+	DefaultMainContainerImageConfigs []fdbv1beta2.ImageConfig
+
+	// DefaultSidecarContainerImageConfigs defines image configs for the
+	// sidecar container that take precedence over the default image configs of
+	// the operator. The image configs of the cluster spec still take
+	// precedence over those image configs.
+	DefaultSidecarContainerImageConfigs []fdbv1beta2.ImageConfig
+
+	// FeatureGates defines the features that are enabled for all clusters.
+	// The settings of disabled features are ignored.
+	FeatureGates featuregates.FeatureGates
 }
 
 // NormalizeClusterSpec converts a cluster spec into an unambiguous,
@@ -57,6 +75,22 @@ func NormalizeClusterSpec(cluster *fdbv1beta2.FoundationDBCluster, options Depre
 		}
 	}
 
+	convertCustomParameters(&cluster.Spec)
+
+	// Validate parameters
+	for _, setting := range cluster.Spec.Processes {
+		if setting.Parameters == nil {
+			continue
+		}
+
+		err := setting.Parameters.ValidateParameters()
+		if err != nil {
+			return err
+		}
+	}
+
+	applyFeatureGates(&cluster.Spec, options.FeatureGates)
+
 	if !options.OnlyShowChanges {
 		// Set up resource requirements for the main container.
 		updatePodTemplates(&cluster.Spec, func(template *corev1.PodTemplateSpec) {
@@ -94,7 +128,7 @@ func NormalizeClusterSpec(cluster *fdbv1beta2.FoundationDBCluster, options Depre
 			template.Spec.Containers = customizeContainerFromList(template.Spec.Containers, fdbv1beta2.SidecarContainerName, sidecarUpdater)
 		})
 
-		updateImageConfigs(&cluster.Spec, cluster.GetUseUnifiedImage())
+		updateImageConfigs(&cluster.Spec.MainContainer, &cluster.Spec.SidecarContainer, cluster.GetUseUnifiedImage(), options)
 	}
 
 	if len(cluster.Spec.Buggify.CrashLoop) > 0 {
@@ -127,12 +161,85 @@ func NormalizeClusterSpec(cluster *fdbv1beta2.FoundationDBCluster, options Depre
 	return nil
 }
 
-func updateImageConfigs(spec *fdbv1beta2.FoundationDBClusterSpec, useUnifiedImage bool) {
+// convertCustomParameters converts the deprecated customParameters into parameters. The customParameters of a process
+// class replaced the customParameters of the general process settings, so the converted parameters will contain
+// removal markers for all general parameters that are not defined for the process class. If parameters are already
+// defined, the customParameters will be dropped, as they are ignored by the operator.
+func convertCustomParameters(spec *fdbv1beta2.FoundationDBClusterSpec) {
+	generalSettings := spec.Processes[fdbv1beta2.ProcessClassGeneral]
+	defaults := generalSettings.Parameters
+	if defaults == nil {
+		defaults = generalSettings.CustomParameters.ToParameters()
+	}
+
+	for processClass, settings := range spec.Processes {
+		if settings.CustomParameters == nil {
+			continue
+		}
+
+		if settings.Parameters == nil {
+			settings.Parameters = settings.CustomParameters.ToParameters()
+
+			if processClass != fdbv1beta2.ProcessClassGeneral {
+				parameterMap := settings.Parameters.GetParameterMap()
+				for _, parameter := range defaults.Merge(nil) {
+					if _, ok := parameterMap[parameter.GetNormalizedName()]; ok {
+						continue
+					}
+
+					settings.Parameters = append(settings.Parameters, fdbv1beta2.FoundationDBParameter{Name: parameter.Name, Remove: true})
+				}
+			}
+		}
+
+		settings.CustomParameters = nil
+		spec.Processes[processClass] = settings
+	}
+}
+
+// applyFeatureGates enables the settings of all features that are enabled
+// by the feature gates, if the settings are not defined in the spec. The
+// feature gates only change the defaults, settings that are explicitly set
+// in the spec are never overridden.
+func applyFeatureGates(spec *fdbv1beta2.FoundationDBClusterSpec, featureGates featuregates.FeatureGates) {
+	if featureGates.Enabled(featuregates.DNSInClusterFile) && spec.Routing.UseDNSInClusterFile == nil {
+		spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+	}
+
+	if featureGates.Enabled(featuregates.UnifiedImage) && spec.UseUnifiedImage == nil {
+		spec.UseUnifiedImage = pointer.Bool(true)
+	}
+
+	if featureGates.Enabled(featuregates.LocalitiesForExclusion) && spec.AutomationOptions.UseLocalitiesForExclusion == nil {
+		spec.AutomationOptions.UseLocalitiesForExclusion = pointer.Bool(true)
+	}
+}
+
+// NormalizeBackupSpec applies the default image configs of the operator to
+// the backup spec, so the backup agents use the same registries and tags as
+// the clusters. The backup agents always use the split images.
+func NormalizeBackupSpec(backup *fdbv1beta2.FoundationDBBackup, options DeprecationOptions) {
+	updateImageConfigs(&backup.Spec.MainContainer, &backup.Spec.SidecarContainer, false, options)
+}
+
+// updateImageConfigs appends the default image configs of the operator and the
+// image configs for the public images to the image configs of the main and
+// the sidecar container. The image configs of the spec still take precedence,
+// as the first config that defines a field will be used for that field.
+func updateImageConfigs(mainContainer *fdbv1beta2.ContainerOverrides, sidecarContainer *fdbv1beta2.ContainerOverrides, useUnifiedImage bool, options DeprecationOptions) {
+	for _, imageConfig := range options.DefaultMainContainerImageConfigs {
+		ensureImageConfigPresent(&mainContainer.ImageConfigs, imageConfig)
+	}
+
 	if useUnifiedImage {
-		ensureImageConfigPresent(&spec.MainContainer.ImageConfigs, fdbv1beta2.ImageConfig{BaseImage: "foundationdb/foundationdb-kubernetes"})
+		ensureImageConfigPresent(&mainContainer.ImageConfigs, fdbv1beta2.ImageConfig{BaseImage: "foundationdb/foundationdb-kubernetes"})
 	} else {
-		ensureImageConfigPresent(&spec.MainContainer.ImageConfigs, fdbv1beta2.ImageConfig{BaseImage: "foundationdb/foundationdb"})
-		ensureImageConfigPresent(&spec.SidecarContainer.ImageConfigs, fdbv1beta2.ImageConfig{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"})
+		for _, imageConfig := range options.DefaultSidecarContainerImageConfigs {
+			ensureImageConfigPresent(&sidecarContainer.ImageConfigs, imageConfig)
+		}
+
+		ensureImageConfigPresent(&mainContainer.ImageConfigs, fdbv1beta2.ImageConfig{BaseImage: "foundationdb/foundationdb"})
+		ensureImageConfigPresent(&sidecarContainer.ImageConfigs, fdbv1beta2.ImageConfig{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"})
 	}
 }
 
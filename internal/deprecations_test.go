@@ -22,6 +22,7 @@ package internal
 
 import (
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/featuregates"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -78,6 +79,68 @@ var _ = Describe("[internal] deprecations", func() {
 			})
 		})
 
+		Describe("converting custom parameters", func() {
+			BeforeEach(func() {
+				spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+					fdbv1beta2.ProcessClassGeneral: {
+						CustomParameters: fdbv1beta2.FoundationDBCustomParameters{
+							"knob_a = 1",
+							"knob_b=2",
+						},
+					},
+					fdbv1beta2.ProcessClassStorage: {
+						CustomParameters: fdbv1beta2.FoundationDBCustomParameters{
+							"knob_b=3",
+						},
+					},
+					fdbv1beta2.ProcessClassLog: {
+						CustomParameters: fdbv1beta2.FoundationDBCustomParameters{
+							"knob_c=4",
+						},
+						Parameters: fdbv1beta2.FoundationDBParameters{
+							{Name: "knob_d", Value: "5"},
+						},
+					},
+				}
+			})
+
+			JustBeforeEach(func() {
+				Expect(NormalizeClusterSpec(cluster, DeprecationOptions{})).NotTo(HaveOccurred())
+			})
+
+			It("should convert the custom parameters of the general process class", func() {
+				settings := spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				Expect(settings.CustomParameters).To(BeNil())
+				Expect(settings.Parameters).To(Equal(fdbv1beta2.FoundationDBParameters{
+					{Name: "knob_a", Value: "1"},
+					{Name: "knob_b", Value: "2"},
+				}))
+			})
+
+			It("should remove the general parameters that were replaced by the process class", func() {
+				settings := spec.Processes[fdbv1beta2.ProcessClassStorage]
+				Expect(settings.CustomParameters).To(BeNil())
+				Expect(settings.Parameters).To(Equal(fdbv1beta2.FoundationDBParameters{
+					{Name: "knob_b", Value: "3"},
+					{Name: "knob_a", Remove: true},
+				}))
+			})
+
+			It("should keep the effective parameters", func() {
+				Expect(cluster.GetProcessSettings(fdbv1beta2.ProcessClassStorage).Parameters).To(Equal(fdbv1beta2.FoundationDBParameters{
+					{Name: "knob_b", Value: "3"},
+				}))
+			})
+
+			It("should prefer the parameters over the custom parameters", func() {
+				settings := spec.Processes[fdbv1beta2.ProcessClassLog]
+				Expect(settings.CustomParameters).To(BeNil())
+				Expect(settings.Parameters).To(Equal(fdbv1beta2.FoundationDBParameters{
+					{Name: "knob_d", Value: "5"},
+				}))
+			})
+		})
+
 		Describe("defaults", func() {
 			BeforeEach(func() {
 				cluster.Spec.MainContainer.ImageConfigs = append(cluster.Spec.MainContainer.ImageConfigs, fdbv1beta2.ImageConfig{BaseImage: "foundationdb/foundationdb-test"})
@@ -279,6 +342,63 @@ var _ = Describe("[internal] deprecations", func() {
 					})
 				})
 			})
+
+			Context("with default image configs of the operator", func() {
+				JustBeforeEach(func() {
+					err := NormalizeClusterSpec(cluster, DeprecationOptions{
+						DefaultMainContainerImageConfigs:    []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb"}},
+						DefaultSidecarContainerImageConfigs: []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb-kubernetes-sidecar"}},
+					})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should add the default image configs before the image configs of the operator", func() {
+					Expect(spec.MainContainer.ImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{
+						{BaseImage: "foundationdb/foundationdb-test"},
+						{BaseImage: "registry.example/foundationdb"},
+						{BaseImage: "foundationdb/foundationdb"},
+					}))
+					Expect(spec.SidecarContainer.ImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{
+						{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar-test"},
+						{BaseImage: "registry.example/foundationdb-kubernetes-sidecar"},
+						{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"},
+					}))
+				})
+			})
+		})
+
+		When("a feature is enabled by the feature gates", func() {
+			BeforeEach(func() {
+				spec.Version = fdbv1beta2.Versions.SupportsDNSInClusterFile.String()
+			})
+
+			JustBeforeEach(func() {
+				featureGates, err := featuregates.FeatureGates{}.With(map[string]bool{string(featuregates.UnifiedImage): true})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(NormalizeClusterSpec(cluster, DeprecationOptions{FeatureGates: featureGates})).NotTo(HaveOccurred())
+			})
+
+			It("should enable the unset settings of the enabled feature", func() {
+				Expect(spec.UseUnifiedImage).To(HaveValue(BeTrue()))
+				Expect(cluster.GetUseUnifiedImage()).To(BeTrue())
+				Expect(spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate.Spec.InitContainers).To(BeEmpty())
+			})
+
+			It("should not change the settings of the disabled features", func() {
+				Expect(spec.Routing.UseDNSInClusterFile).To(BeNil())
+				Expect(cluster.UseDNSInClusterFile()).To(BeFalse())
+			})
+
+			When("the setting of the enabled feature is explicitly disabled", func() {
+				BeforeEach(func() {
+					spec.UseUnifiedImage = pointer.Bool(false)
+				})
+
+				It("should keep the setting", func() {
+					Expect(cluster.GetUseUnifiedImage()).To(BeFalse())
+					Expect(spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate.Spec.InitContainers).To(HaveLen(1))
+				})
+			})
 		})
 
 		When("adding an image config", func() {
@@ -304,4 +424,61 @@ var _ = Describe("[internal] deprecations", func() {
 			})
 		})
 	})
+
+	Describe("NormalizeBackupSpec", func() {
+		var backup *fdbv1beta2.FoundationDBBackup
+
+		BeforeEach(func() {
+			backup = &fdbv1beta2.FoundationDBBackup{
+				Spec: fdbv1beta2.FoundationDBBackupSpec{
+					MainContainer: fdbv1beta2.ContainerOverrides{
+						ImageConfigs: []fdbv1beta2.ImageConfig{{Version: "7.1.26", Tag: "7.1.26-patched"}},
+					},
+				},
+			}
+		})
+
+		When("no default image configs are defined", func() {
+			BeforeEach(func() {
+				NormalizeBackupSpec(backup, DeprecationOptions{})
+			})
+
+			It("should add the image configs for the public images", func() {
+				Expect(backup.Spec.MainContainer.ImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{
+					{Version: "7.1.26", Tag: "7.1.26-patched"},
+					{BaseImage: "foundationdb/foundationdb"},
+				}))
+				Expect(backup.Spec.SidecarContainer.ImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{
+					{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"},
+				}))
+			})
+		})
+
+		When("default image configs of the operator are defined", func() {
+			BeforeEach(func() {
+				NormalizeBackupSpec(backup, DeprecationOptions{
+					DefaultMainContainerImageConfigs:    []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb"}},
+					DefaultSidecarContainerImageConfigs: []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb-kubernetes-sidecar"}},
+				})
+			})
+
+			It("should add the default image configs before the image configs for the public images", func() {
+				Expect(backup.Spec.MainContainer.ImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{
+					{Version: "7.1.26", Tag: "7.1.26-patched"},
+					{BaseImage: "registry.example/foundationdb"},
+					{BaseImage: "foundationdb/foundationdb"},
+				}))
+				Expect(backup.Spec.SidecarContainer.ImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{
+					{BaseImage: "registry.example/foundationdb-kubernetes-sidecar"},
+					{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"},
+				}))
+			})
+
+			It("should resolve the image from the combined image configs", func() {
+				image, err := GetImage("", backup.Spec.MainContainer.ImageConfigs, "7.1.26", false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(image).To(Equal("registry.example/foundationdb:7.1.26-patched"))
+			})
+		})
+	})
 })
@@ -0,0 +1,189 @@
+/*
+ * featuregates.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package featuregates provides the feature gates of the operator. A feature gate allows to toggle a feature for all
+// clusters that are managed by an operator deployment.
+package featuregates
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature is the name of a feature that can be toggled with a feature gate.
+type Feature string
+
+// Stage is the maturity of a feature.
+type Stage string
+
+const (
+	// Alpha features are disabled by default and might change or be removed in any release.
+	Alpha Stage = "Alpha"
+	// Beta features are enabled by default and can be disabled if they cause issues.
+	Beta Stage = "Beta"
+	// GA features are always enabled, the feature gate will be removed in a future release.
+	GA Stage = "GA"
+)
+
+const (
+	// DNSInClusterFile makes clusters use DNS names instead of IP addresses in the cluster file, if the
+	// routing.useDNSInClusterFile setting is not set.
+	DNSInClusterFile Feature = "DNSInClusterFile"
+	// UnifiedImage makes clusters use the unified image, if the useUnifiedImage setting is not set.
+	UnifiedImage Feature = "UnifiedImage"
+	// LocalitiesForExclusion makes clusters exclude processes by their locality instead of their IP address, if the
+	// automationOptions.useLocalitiesForExclusion setting is not set.
+	LocalitiesForExclusion Feature = "LocalitiesForExclusion"
+)
+
+// FeatureSpec defines the default and the stage of a feature.
+type FeatureSpec struct {
+	// Default defines if the feature is enabled if the feature gate is not set.
+	Default bool
+	// Stage defines the maturity of the feature.
+	Stage Stage
+}
+
+// knownFeatures contains all features that can be toggled with a feature gate.
+var knownFeatures = map[Feature]FeatureSpec{
+	DNSInClusterFile:       {Default: false, Stage: Alpha},
+	UnifiedImage:           {Default: false, Stage: Alpha},
+	LocalitiesForExclusion: {Default: false, Stage: Alpha},
+}
+
+// FeatureStatus reports if a feature is enabled.
+type FeatureStatus struct {
+	Feature Feature
+	Stage   Stage
+	Enabled bool
+}
+
+// FeatureGates contains the feature gates that were set for the operator. The zero value uses the defaults of all
+// features. FeatureGates must not be modified after they were created, so they can be shared between goroutines.
+type FeatureGates struct {
+	gates map[Feature]bool
+}
+
+// Enabled returns true if the feature is enabled. Unknown features are disabled.
+func (featureGates FeatureGates) Enabled(feature Feature) bool {
+	spec, ok := knownFeatures[feature]
+	if !ok {
+		return false
+	}
+
+	if enabled, ok := featureGates.gates[feature]; ok {
+		return enabled
+	}
+
+	return spec.Default
+}
+
+// With returns a copy of the feature gates with the provided feature gates set. An error is returned for unknown
+// features and for disabling features that are GA.
+func (featureGates FeatureGates) With(gates map[string]bool) (FeatureGates, error) {
+	result := FeatureGates{gates: make(map[Feature]bool, len(featureGates.gates)+len(gates))}
+	for feature, enabled := range featureGates.gates {
+		result.gates[feature] = enabled
+	}
+
+	for name, enabled := range gates {
+		feature := Feature(name)
+		spec, ok := knownFeatures[feature]
+		if !ok {
+			return featureGates, fmt.Errorf("unknown feature gate %s", name)
+		}
+
+		if spec.Stage == GA && !enabled {
+			return featureGates, fmt.Errorf("feature gate %s is GA and can't be disabled", name)
+		}
+
+		result.gates[feature] = enabled
+	}
+
+	return result, nil
+}
+
+// Status returns the status of all known features sorted by their name.
+func (featureGates FeatureGates) Status() []FeatureStatus {
+	result := make([]FeatureStatus, 0, len(knownFeatures))
+	for feature, spec := range knownFeatures {
+		result = append(result, FeatureStatus{
+			Feature: feature,
+			Stage:   spec.Stage,
+			Enabled: featureGates.Enabled(feature),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Feature < result[j].Feature
+	})
+
+	return result
+}
+
+// String implements the flag.Value interface and returns the feature gates that were set in the format of Set.
+func (featureGates *FeatureGates) String() string {
+	if featureGates == nil {
+		return ""
+	}
+
+	gates := make([]string, 0, len(featureGates.gates))
+	for feature, enabled := range featureGates.gates {
+		gates = append(gates, fmt.Sprintf("%s=%t", feature, enabled))
+	}
+	sort.Strings(gates)
+
+	return strings.Join(gates, ",")
+}
+
+// Set implements the flag.Value interface and sets the feature gates from a comma separated list of feature=bool
+// pairs, e.g. "UnifiedImage=true,DNSInClusterFile=false".
+func (featureGates *FeatureGates) Set(value string) error {
+	gates := map[string]bool{}
+	for _, gate := range strings.Split(value, ",") {
+		gate = strings.TrimSpace(gate)
+		if gate == "" {
+			continue
+		}
+
+		name, rawValue, ok := strings.Cut(gate, "=")
+		if !ok {
+			return fmt.Errorf("missing value for feature gate %s", gate)
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(rawValue))
+		if err != nil {
+			return fmt.Errorf("invalid value %s for feature gate %s: %w", rawValue, name, err)
+		}
+
+		gates[strings.TrimSpace(name)] = enabled
+	}
+
+	result, err := featureGates.With(gates)
+	if err != nil {
+		return err
+	}
+
+	*featureGates = result
+
+	return nil
+}
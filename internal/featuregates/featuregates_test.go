@@ -0,0 +1,125 @@
+/*
+ * featuregates_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package featuregates
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("featuregates", func() {
+	const alphaFeature Feature = "AlphaFeature"
+	const betaFeature Feature = "BetaFeature"
+	const gaFeature Feature = "GAFeature"
+
+	BeforeEach(func() {
+		knownFeatures[alphaFeature] = FeatureSpec{Default: false, Stage: Alpha}
+		knownFeatures[betaFeature] = FeatureSpec{Default: true, Stage: Beta}
+		knownFeatures[gaFeature] = FeatureSpec{Default: true, Stage: GA}
+	})
+
+	AfterEach(func() {
+		delete(knownFeatures, alphaFeature)
+		delete(knownFeatures, betaFeature)
+		delete(knownFeatures, gaFeature)
+	})
+
+	When("no feature gates are set", func() {
+		It("should use the defaults of the features", func() {
+			gates := FeatureGates{}
+			Expect(gates.Enabled(alphaFeature)).To(BeFalse())
+			Expect(gates.Enabled(betaFeature)).To(BeTrue())
+			Expect(gates.Enabled(UnifiedImage)).To(BeFalse())
+			Expect(gates.Enabled(gaFeature)).To(BeTrue())
+			Expect(gates.Enabled("Unknown")).To(BeFalse())
+		})
+	})
+
+	When("feature gates are set", func() {
+		var gates FeatureGates
+		var err error
+
+		JustBeforeEach(func() {
+			gates, err = FeatureGates{}.With(map[string]bool{
+				string(alphaFeature): true,
+				string(betaFeature):  false,
+			})
+		})
+
+		It("should use the feature gates", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gates.Enabled(alphaFeature)).To(BeTrue())
+			Expect(gates.Enabled(betaFeature)).To(BeFalse())
+			Expect(gates.Enabled(DNSInClusterFile)).To(BeFalse())
+		})
+
+		It("should not modify the original feature gates", func() {
+			updated, err := gates.With(map[string]bool{string(betaFeature): true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Enabled(betaFeature)).To(BeTrue())
+			Expect(updated.Enabled(alphaFeature)).To(BeTrue())
+			Expect(gates.Enabled(betaFeature)).To(BeFalse())
+		})
+
+		It("should report the status of all features", func() {
+			Expect(gates.Status()).To(ContainElements(
+				FeatureStatus{Feature: alphaFeature, Stage: Alpha, Enabled: true},
+				FeatureStatus{Feature: betaFeature, Stage: Beta, Enabled: false},
+				FeatureStatus{Feature: UnifiedImage, Stage: Alpha, Enabled: false},
+				FeatureStatus{Feature: gaFeature, Stage: GA, Enabled: true},
+			))
+		})
+	})
+
+	When("an unknown feature gate is set", func() {
+		It("should return an error", func() {
+			_, err := FeatureGates{}.With(map[string]bool{"Unknown": true})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("a GA feature gate is disabled", func() {
+		It("should return an error", func() {
+			_, err := FeatureGates{}.With(map[string]bool{string(gaFeature): false})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	DescribeTable("parsing the feature gates from a flag",
+		func(value string, expectedErr bool, expected string) {
+			gates := &FeatureGates{}
+			err := gates.Set(value)
+			if expectedErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gates.String()).To(Equal(expected))
+		},
+		Entry("an empty value", "", false, ""),
+		Entry("a single feature gate", "UnifiedImage=false", false, "UnifiedImage=false"),
+		Entry("multiple feature gates", "UnifiedImage=false, DNSInClusterFile=true", false, "DNSInClusterFile=true,UnifiedImage=false"),
+		Entry("a missing value", "UnifiedImage", true, ""),
+		Entry("an invalid value", "UnifiedImage=maybe", true, ""),
+		Entry("an unknown feature gate", "Unknown=true", true, ""),
+	)
+})
@@ -21,12 +21,14 @@
 package locality
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"sort"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
 	"github.com/go-logr/logr"
 )
@@ -94,8 +96,8 @@ func InfoForProcess(process fdbv1beta2.FoundationDBStatusProcessInfo, mainContai
 
 // InfoFromSidecar converts the process information from the sidecar's
 // context into locality info for selecting processes.
-func InfoFromSidecar(cluster *fdbv1beta2.FoundationDBCluster, client podclient.FdbPodClient) (Info, error) {
-	substitutions, err := client.GetVariableSubstitutions()
+func InfoFromSidecar(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, client podclient.FdbPodClient) (Info, error) {
+	substitutions, err := client.GetVariableSubstitutions(ctx)
 	if err != nil {
 		return Info{}, err
 	}
@@ -109,7 +111,7 @@ func InfoFromSidecar(cluster *fdbv1beta2.FoundationDBCluster, client podclient.F
 	// This has the implication that in the initial cluster file only the first processes will be used.
 	return Info{
 		ID:      substitutions["FDB_INSTANCE_ID"],
-		Address: cluster.GetFullAddress(substitutions["FDB_PUBLIC_IP"], 1),
+		Address: cluster.GetFullAddressForProcessClass(substitutions["FDB_PUBLIC_IP"], 1, processClass),
 		LocalityData: map[string]string{
 			fdbv1beta2.FDBLocalityZoneIDKey:  substitutions["FDB_ZONE_ID"],
 			fdbv1beta2.FDBLocalityDNSNameKey: substitutions["FDB_DNS_NAME"],
@@ -289,7 +291,9 @@ func CheckCoordinatorValidity(logger logr.Logger, cluster *fdbv1beta2.Foundation
 		}
 
 		processGroupStatus := processGroups[fdbv1beta2.ProcessGroupID(processGroupID)]
-		pendingRemoval := processGroupStatus != nil && processGroupStatus.IsMarkedForRemoval()
+		// Coordinators in a decommissioned fault domain are treated like coordinators that are pending removal,
+		// this makes sure that the coordinators are moved before the fault domain is removed.
+		pendingRemoval := (processGroupStatus != nil && processGroupStatus.IsMarkedForRemoval()) || cluster.IsFaultDomainDecommissioned(process.Locality)
 		if processGroupStatus != nil && cluster.SkipProcessGroup(processGroupStatus) {
 			pLogger.Info("Skipping process group with pending Pod",
 				"namespace", cluster.Namespace,
@@ -305,7 +309,7 @@ func CheckCoordinatorValidity(logger logr.Logger, cluster *fdbv1beta2.Foundation
 			// is not parsable e.g. no IP address is assigned.
 			allAddressesValid = false
 			// add: command_line
-			pLogger.Info("Could not parse address from command_line", "command_line", process.CommandLine)
+			pLogger.Info("Could not parse address from command_line", "command_line", internal.RedactString(process.CommandLine))
 			continue
 		}
 
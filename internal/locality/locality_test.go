@@ -21,6 +21,7 @@ package locality
  */
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net"
@@ -464,7 +465,7 @@ var _ = Describe("Change coordinators", func() {
 		client, err := mock.NewMockFdbPodClient(cluster, pod)
 		Expect(err).NotTo(HaveOccurred())
 
-		info, err := InfoFromSidecar(cluster, client)
+		info, err := InfoFromSidecar(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, client)
 		if expectedError {
 			Expect(err).To(HaveOccurred())
 			return
@@ -943,6 +944,8 @@ var _ = Describe("Change coordinators", func() {
 
 		When("enabling DNS names in the cluster file", func() {
 			BeforeEach(func() {
+				cluster.Spec.Version = fdbv1beta2.Versions.SupportsDNSInClusterFile.String()
+				cluster.Status.RunningVersion = fdbv1beta2.Versions.SupportsDNSInClusterFile.String()
 				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
 			})
 
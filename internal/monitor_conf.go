@@ -21,6 +21,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"sort"
@@ -29,12 +30,13 @@ import (
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
 	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/pointer"
 )
 
 // GetStartCommand builds the expected start command for a process group.
-func GetStartCommand(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, podClient podclient.FdbPodClient, processNumber int, processCount int) (string, error) {
-	substitutions, err := podClient.GetVariableSubstitutions()
+func GetStartCommand(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, podClient podclient.FdbPodClient, processNumber int, processCount int) (string, error) {
+	substitutions, err := podClient.GetVariableSubstitutions(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -54,6 +56,12 @@ func getStartCommandWithSubstitutions(cluster *fdbv1beta2.FoundationDBCluster, p
 		return "", err
 	}
 
+	// fdbmonitor doesn't pass the hot reloadable parameters to the fdbserver processes, so they are not part of the
+	// command line and a change of those parameters doesn't require a restart.
+	if imageType == FDBImageTypeSplit {
+		config.Arguments = removeHotReloadableArguments(config.Arguments)
+	}
+
 	extractPlaceholderEnvVars(substitutions, config.Arguments)
 
 	config.BinaryPath = fmt.Sprintf("%s/fdbserver", substitutions["BINARY_DIR"])
@@ -76,6 +84,55 @@ func getStartCommandWithSubstitutions(cluster *fdbv1beta2.FoundationDBCluster, p
 	return command + " " + strings.Join(arguments, " "), nil
 }
 
+// GetCommandLineDivergence compares the expected command line of a process with the command line that the process
+// reported in the machine-readable status. The arguments are compared independent of their order and the whitespace
+// between them, so only actual divergences are reported. The first return value contains the arguments that are
+// missing in the reported command line and the second return value the arguments that are only present in the
+// reported command line.
+func GetCommandLineDivergence(expected string, reported string) ([]string, []string) {
+	reportedArguments := make(map[string]int)
+	for _, argument := range strings.Fields(reported) {
+		reportedArguments[argument]++
+	}
+
+	missing := make([]string, 0)
+	for _, argument := range strings.Fields(expected) {
+		if reportedArguments[argument] > 0 {
+			reportedArguments[argument]--
+			continue
+		}
+
+		missing = append(missing, argument)
+	}
+
+	unexpected := make([]string, 0)
+	for _, argument := range strings.Fields(reported) {
+		if reportedArguments[argument] > 0 {
+			reportedArguments[argument]--
+			unexpected = append(unexpected, argument)
+		}
+	}
+
+	return missing, unexpected
+}
+
+// removeHotReloadableArguments returns the arguments without the custom parameters that are hot reloadable by
+// fdbmonitor.
+func removeHotReloadableArguments(arguments []monitorapi.Argument) []monitorapi.Argument {
+	filtered := make([]monitorapi.Argument, 0, len(arguments))
+	for _, argument := range arguments {
+		if argument.ArgumentType == monitorapi.LiteralArgumentType || argument.ArgumentType == "" {
+			if fdbv1beta2.FoundationDBCustomParameter(strings.TrimPrefix(argument.Value, "--")).IsHotReloadable() {
+				continue
+			}
+		}
+
+		filtered = append(filtered, argument)
+	}
+
+	return filtered
+}
+
 // extractPlaceholderEnvVars builds a map of every environment variable
 // referenced in the monitor conf.
 func extractPlaceholderEnvVars(env map[string]string, arguments []monitorapi.Argument) {
@@ -90,8 +147,68 @@ func extractPlaceholderEnvVars(env map[string]string, arguments []monitorapi.Arg
 	}
 }
 
+// processContainerBinaryPath defines the path of the fdbserver binary in the unified image.
+const processContainerBinaryPath = "/usr/bin/fdbserver"
+
+// GetProcessContainerArguments builds the command and the arguments for the container that runs the fdbserver process
+// with the provided process number in the ProcessPerContainer layout. Environment variables are referenced with the
+// $(VAR) syntax, so Kubernetes substitutes them when the container is started.
+func GetProcessContainerArguments(config monitorapi.ProcessConfiguration, processNumber int) ([]string, error) {
+	env := map[string]string{}
+	extractPlaceholderEnvVars(env, config.Arguments)
+	for key := range env {
+		env[key] = fmt.Sprintf("$(%s)", key)
+	}
+
+	config.BinaryPath = processContainerBinaryPath
+
+	return config.GenerateArguments(processNumber, env)
+}
+
+// kubernetesVariableReference matches a reference to an environment variable in the arguments of a container.
+var kubernetesVariableReference = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+// GetStartCommandForProcessContainer builds the expected start command for a process that runs in its own container,
+// based on the arguments of the container and the variable substitutions of the Pod. In the ProcessPerContainer layout
+// the configuration is delivered through the Pod spec, so changes are rolled out by updating the Pods.
+func GetStartCommandForProcessContainer(ctx context.Context, pod *corev1.Pod, podClient podclient.FdbPodClient, processNumber int) (string, error) {
+	substitutions, err := podClient.GetVariableSubstitutions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if substitutions == nil {
+		return "", nil
+	}
+
+	containerName := fdbv1beta2.GetProcessContainerName(processNumber)
+	for _, container := range pod.Spec.Containers {
+		if container.Name != containerName {
+			continue
+		}
+
+		arguments := make([]string, 0, len(container.Command)+len(container.Args))
+		arguments = append(arguments, container.Command...)
+		arguments = append(arguments, container.Args...)
+		for index, argument := range arguments {
+			arguments[index] = kubernetesVariableReference.ReplaceAllStringFunc(argument, func(reference string) string {
+				value, ok := substitutions[reference[2:len(reference)-1]]
+				if !ok {
+					return reference
+				}
+
+				return value
+			})
+		}
+
+		return strings.Join(arguments, " "), nil
+	}
+
+	return "", fmt.Errorf("could not find container %s for process %d", containerName, processNumber)
+}
+
 // GetMonitorConf builds the monitor conf template
-func GetMonitorConf(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, podClient podclient.FdbPodClient, serversPerPod int) (string, error) {
+func GetMonitorConf(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, podClient podclient.FdbPodClient, serversPerPod int) (string, error) {
 	if cluster.Status.ConnectionString == "" {
 		return "", nil
 	}
@@ -107,7 +224,7 @@ func GetMonitorConf(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1b
 	var err error
 
 	if podClient != nil {
-		substitutions, err = podClient.GetVariableSubstitutions()
+		substitutions, err = podClient.GetVariableSubstitutions(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -186,17 +303,27 @@ func GetMonitorProcessConfiguration(cluster *fdbv1beta2.FoundationDBCluster, pro
 		zoneVariable = "FDB_ZONE_ID"
 	}
 
-	sampleAddresses := cluster.GetFullAddressList("FDB_PUBLIC_IP", false, 1)
+	sampleAddresses := cluster.GetFullAddressListForProcessClass("FDB_PUBLIC_IP", false, 1, processClass)
 
 	configuration.Arguments = append(configuration.Arguments,
 		monitorapi.Argument{Value: "--cluster_file=/var/fdb/data/fdb.cluster"},
 		monitorapi.Argument{Value: "--seed_cluster_file=/var/dynamic-conf/fdb.cluster"},
 		monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: buildIPArgument("public_address", "FDB_PUBLIC_IP", imageType, sampleAddresses)},
 		monitorapi.Argument{Value: fmt.Sprintf("--class=%s", processClass)},
-		monitorapi.Argument{Value: "--logdir=/var/log/fdb-trace-logs"},
+		monitorapi.Argument{Value: fmt.Sprintf("--logdir=%s", cluster.GetTraceLogDirectory())},
 		monitorapi.Argument{Value: fmt.Sprintf("--loggroup=%s", logGroup)},
 	)
 
+	if cluster.Spec.TraceLogs != nil {
+		if cluster.Spec.TraceLogs.Format != "" {
+			configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: fmt.Sprintf("--trace_format=%s", cluster.Spec.TraceLogs.Format)})
+		}
+
+		if cluster.Spec.TraceLogs.MaxLogsSize != nil {
+			configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: fmt.Sprintf("--maxlogssize=%d", cluster.Spec.TraceLogs.MaxLogsSize.Value())})
+		}
+	}
+
 	if processCount > 1 {
 		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{
 			ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -237,20 +364,17 @@ func GetMonitorProcessConfiguration(cluster *fdbv1beta2.FoundationDBCluster, pro
 		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: fmt.Sprintf("--tls_verify_peers=%s", cluster.Spec.MainContainer.PeerVerificationRules)})
 	}
 
+	configuration.Arguments = append(configuration.Arguments, getAuthorizationArguments(cluster)...)
+
 	podSettings := cluster.GetProcessSettings(processClass)
+	parameterVariables := podSettings.GetParameterEnvironmentVariables()
 
-	if podSettings.CustomParameters != nil {
-		equalPattern, err := regexp.Compile(`\s*=\s*`)
-		if err != nil {
-			return configuration, err
-		}
-		for _, argument := range podSettings.CustomParameters {
-			sanitizedArgument := "--" + equalPattern.ReplaceAllString(string(argument), "=")
-			for key, value := range customParameterSubstitutions {
-				sanitizedArgument = strings.Replace(sanitizedArgument, "$"+key, value, -1)
-			}
-			configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: sanitizedArgument})
+	for _, parameter := range podSettings.Parameters {
+		argument := parameter.GetArgument()
+		for key, value := range customParameterSubstitutions {
+			argument = strings.Replace(argument, "$"+key, value, -1)
 		}
+		configuration.Arguments = append(configuration.Arguments, buildParameterArgument(argument, parameterVariables))
 	}
 
 	if cluster.Spec.DataCenter != "" {
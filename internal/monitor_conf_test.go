@@ -21,6 +21,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -31,6 +32,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 )
 
@@ -100,6 +102,32 @@ var _ = Describe("monitor_conf", func() {
 			})
 		})
 
+		When("a parameter references an environment variable of the process class", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{
+					Env:        []corev1.EnvVar{{Name: "KNOB_VALUE", Value: "10"}},
+					Parameters: fdbv1beta2.FoundationDBParameters{{Name: "knob_test", Value: "$KNOB_VALUE-$UNKNOWN"}},
+				}
+			})
+
+			It("fills in the environment variable when the process is started", func() {
+				config, err := GetMonitorProcessConfiguration(cluster, fdbv1beta2.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
+				Expect(config.Arguments[baseArgumentLength]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+					{Value: "--knob_test="},
+					{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "KNOB_VALUE"},
+					{Value: "-$UNKNOWN"},
+				}}))
+			})
+
+			It("substitutes the variable in the monitor conf of the split image", func() {
+				conf, err := GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(conf).To(ContainSubstring("knob_test = $KNOB_VALUE-$UNKNOWN"))
+			})
+		})
+
 		When("running a log instance", func() {
 			It("generates the conf", func() {
 				config, err := GetMonitorProcessConfiguration(cluster, fdbv1beta2.ProcessClassLog, 1, FDBImageTypeUnified, nil)
@@ -351,6 +379,36 @@ var _ = Describe("monitor_conf", func() {
 					Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--knob_test=test1"}))
 				})
 			})
+
+			When("typed parameters are defined", func() {
+				BeforeEach(func() {
+					cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+						fdbv1beta2.ProcessClassGeneral: {Parameters: fdbv1beta2.FoundationDBParameters{
+							{Name: "knob_disable_posix_kernel_aio", Value: "1"},
+							{Name: "knob_test", Value: "test1"},
+						}},
+						fdbv1beta2.ProcessClassStorage: {Parameters: fdbv1beta2.FoundationDBParameters{
+							{Name: "knob_disable_posix_kernel_aio", Remove: true},
+							{Name: "knob_test", Value: "test2"},
+						}},
+					}
+				})
+
+				It("includes the merged parameters for that class", func() {
+					config, err := GetMonitorProcessConfiguration(cluster, fdbv1beta2.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
+					Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--knob_test=test2"}))
+				})
+
+				It("includes the default parameters for other classes", func() {
+					config, err := GetMonitorProcessConfiguration(cluster, fdbv1beta2.ProcessClassStateless, 1, FDBImageTypeUnified, nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(config.Arguments).To(HaveLen(baseArgumentLength + 2))
+					Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--knob_disable_posix_kernel_aio=1"}))
+					Expect(config.Arguments[11]).To(Equal(monitorapi.Argument{Value: "--knob_test=test1"}))
+				})
+			})
 		})
 
 		When("the cluster has an alternative fault domain variable", func() {
@@ -424,6 +482,26 @@ var _ = Describe("monitor_conf", func() {
 				Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--locality_data_hall=dh01"}))
 			})
 		})
+
+		When("the spec has trace log settings", func() {
+			BeforeEach(func() {
+				maxLogsSize := resource.MustParse("1Gi")
+				cluster.Spec.TraceLogs = &fdbv1beta2.TraceLogSpec{
+					Format:      fdbv1beta2.TraceLogFormatJSON,
+					Directory:   "/var/log/fdb",
+					MaxLogsSize: &maxLogsSize,
+				}
+			})
+
+			It("adds the trace log arguments", func() {
+				config, err := GetMonitorProcessConfiguration(cluster, fdbv1beta2.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 2))
+				Expect(config.Arguments[4]).To(Equal(monitorapi.Argument{Value: "--logdir=/var/log/fdb"}))
+				Expect(config.Arguments[6]).To(Equal(monitorapi.Argument{Value: "--trace_format=json"}))
+				Expect(config.Arguments[7]).To(Equal(monitorapi.Argument{Value: "--maxlogssize=1073741824"}))
+			})
+		})
 	})
 
 	Describe("GetStartCommand", func() {
@@ -539,6 +617,37 @@ var _ = Describe("monitor_conf", func() {
 					}, " ")))
 				})
 			})
+
+			When("using the ProcessPerContainer layout", func() {
+				BeforeEach(func() {
+					cluster.Spec.PodLayout = fdbv1beta2.PodLayoutProcessPerContainer
+					cluster.Spec.StorageServersPerPod = 2
+					pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+					Expect(err).NotTo(HaveOccurred())
+					pod.Status.PodIP = address
+					pod.Status.PodIPs = []corev1.PodIP{{IP: address}}
+				})
+
+				It("should build the command from the arguments of the process container", func() {
+					podClient, err := NewFdbPodClient(cluster, pod, logr.Discard(), 0, 0, nil)
+					Expect(err).NotTo(HaveOccurred())
+					command, err = GetStartCommandForProcessContainer(context.TODO(), pod, podClient, 2)
+					Expect(err).NotTo(HaveOccurred())
+
+					substitutions, err := GetSubstitutionsFromClusterAndPod(logr.Discard(), cluster, pod)
+					Expect(err).NotTo(HaveOccurred())
+					expected, err := getStartCommandWithSubstitutions(cluster, processClass, substitutions, 2, 2)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(command).To(Equal(expected))
+				})
+
+				It("should return an error for an unknown process", func() {
+					podClient, err := NewFdbPodClient(cluster, pod, logr.Discard(), 0, 0, nil)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = GetStartCommandForProcessContainer(context.TODO(), pod, podClient, 3)
+					Expect(err).To(HaveOccurred())
+				})
+			})
 		})
 
 		When("using the split image", func() {
@@ -566,6 +675,53 @@ var _ = Describe("monitor_conf", func() {
 					"--seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 				}, " ")))
 			})
+			When("hot reloadable custom parameters are defined", func() {
+				BeforeEach(func() {
+					settings := cluster.Spec.Processes["general"]
+					settings.CustomParameters = []fdbv1beta2.FoundationDBCustomParameter{
+						"restart_delay=10",
+						"knob_disable_posix_kernel_aio=1",
+					}
+					cluster.Spec.Processes["general"] = settings
+				})
+
+				It("should not include the hot reloadable parameters in the command-line", func() {
+					substitutions, err := GetSubstitutionsFromClusterAndPod(logr.Discard(), cluster, pod)
+					Expect(err).NotTo(HaveOccurred())
+					command, err = getStartCommandWithSubstitutions(cluster, processClass, substitutions, 1, 1)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(command).To(Equal(strings.Join([]string{
+						"/usr/bin/fdbserver",
+						"--class=storage",
+						"--cluster_file=/var/fdb/data/fdb.cluster",
+						"--datadir=/var/fdb/data",
+						"--knob_disable_posix_kernel_aio=1",
+						fmt.Sprintf("--locality_instance_id=%s", processGroupID),
+						fmt.Sprintf("--locality_machineid=%s-%s", cluster.Name, processGroupID),
+						fmt.Sprintf("--locality_zoneid=%s-%s", cluster.Name, processGroupID),
+						"--logdir=/var/log/fdb-trace-logs",
+						"--loggroup=" + cluster.Name,
+						fmt.Sprintf("--public_address=%s:4501", address),
+						"--seed_cluster_file=/var/dynamic-conf/fdb.cluster",
+					}, " ")))
+				})
+			})
+		})
+
+		When("the storage processes use the host network", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{UseHostNetwork: pointer.Bool(true)}
+			})
+
+			It("should use the port range of the storage processes", func() {
+				substitutions, err := GetSubstitutionsFromClusterAndPod(logr.Discard(), cluster, pod)
+				Expect(err).NotTo(HaveOccurred())
+				command, err = getStartCommandWithSubstitutions(cluster, processClass, substitutions, 1, 1)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(command).To(ContainSubstring(fmt.Sprintf("--public_address=%s:4521", address)))
+			})
 		})
 
 		Context("for a basic storage process with multiple storage servers per Pod", func() {
@@ -709,7 +865,7 @@ var _ = Describe("monitor_conf", func() {
 
 		Context("with a basic storage instance", func() {
 			BeforeEach(func() {
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -736,7 +892,7 @@ var _ = Describe("monitor_conf", func() {
 
 		Context("with a test instance", func() {
 			BeforeEach(func() {
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassTest, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassTest, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -764,7 +920,7 @@ var _ = Describe("monitor_conf", func() {
 		Context("with DNS names enabled", func() {
 			BeforeEach(func() {
 				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -793,7 +949,7 @@ var _ = Describe("monitor_conf", func() {
 		Context("with DNS names in locality fields", func() {
 			BeforeEach(func() {
 				cluster.Spec.Routing.DefineDNSLocalityFields = pointer.Bool(true)
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -822,7 +978,7 @@ var _ = Describe("monitor_conf", func() {
 		Context("with a basic storage instance with multiple storage servers per Pod", func() {
 			BeforeEach(func() {
 				cluster.Spec.StorageServersPerPod = 2
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -865,7 +1021,7 @@ var _ = Describe("monitor_conf", func() {
 			BeforeEach(func() {
 				source := fdbv1beta2.PublicIPSourcePod
 				cluster.Spec.Routing.PublicIPSource = &source
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -895,7 +1051,7 @@ var _ = Describe("monitor_conf", func() {
 				source := fdbv1beta2.PublicIPSourceService
 				cluster.Spec.Routing.PublicIPSource = &source
 				cluster.Status.HasListenIPsForAllPods = true
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -923,7 +1079,7 @@ var _ = Describe("monitor_conf", func() {
 			Context("with pods without the listen IP environment variable", func() {
 				BeforeEach(func() {
 					cluster.Status.HasListenIPsForAllPods = false
-					conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
+					conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, 1)
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -954,7 +1110,7 @@ var _ = Describe("monitor_conf", func() {
 				cluster.Spec.MainContainer.EnableTLS = true
 				cluster.Status.RequiredAddresses.NonTLS = false
 				cluster.Status.RequiredAddresses.TLS = true
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -985,7 +1141,7 @@ var _ = Describe("monitor_conf", func() {
 				cluster.Status.RequiredAddresses.NonTLS = true
 				cluster.Status.RequiredAddresses.TLS = true
 
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -1016,7 +1172,7 @@ var _ = Describe("monitor_conf", func() {
 				cluster.Status.RequiredAddresses.NonTLS = true
 				cluster.Status.RequiredAddresses.TLS = true
 
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -1047,7 +1203,7 @@ var _ = Describe("monitor_conf", func() {
 					cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{fdbv1beta2.ProcessClassGeneral: {CustomParameters: fdbv1beta2.FoundationDBCustomParameters{
 						"knob_disable_posix_kernel_aio = 1",
 					}}}
-					conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+					conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -1086,7 +1242,7 @@ var _ = Describe("monitor_conf", func() {
 							"knob_test = test2",
 						}},
 					}
-					conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+					conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -1119,7 +1275,7 @@ var _ = Describe("monitor_conf", func() {
 					Key:       "rack",
 					ValueFrom: "$RACK",
 				}
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -1147,7 +1303,7 @@ var _ = Describe("monitor_conf", func() {
 		Context("with peer verification rules", func() {
 			BeforeEach(func() {
 				cluster.Spec.MainContainer.PeerVerificationRules = "S.CN=foundationdb.org"
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -1176,7 +1332,7 @@ var _ = Describe("monitor_conf", func() {
 		Context("with a custom log group", func() {
 			BeforeEach(func() {
 				cluster.Spec.LogGroup = "test-fdb-cluster"
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -1204,7 +1360,7 @@ var _ = Describe("monitor_conf", func() {
 		Context("with a data center", func() {
 			BeforeEach(func() {
 				cluster.Spec.DataCenter = "dc01"
-				conf, err = GetMonitorConf(cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = GetMonitorConf(context.TODO(), cluster, fdbv1beta2.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -1231,4 +1387,41 @@ var _ = Describe("monitor_conf", func() {
 		})
 	})
 
+	DescribeTable("getting the command line divergence",
+		func(expected string, reported string, expectedMissing []string, expectedUnexpected []string) {
+			missing, unexpected := GetCommandLineDivergence(expected, reported)
+			Expect(missing).To(Equal(expectedMissing))
+			Expect(unexpected).To(Equal(expectedUnexpected))
+		},
+		Entry("the same command line",
+			"/usr/bin/fdbserver --class=storage --datadir=/var/fdb/data",
+			"/usr/bin/fdbserver --class=storage --datadir=/var/fdb/data",
+			[]string{},
+			[]string{}),
+		Entry("a different order and whitespace",
+			"/usr/bin/fdbserver --class=storage --datadir=/var/fdb/data",
+			"/usr/bin/fdbserver  --datadir=/var/fdb/data --class=storage ",
+			[]string{},
+			[]string{}),
+		Entry("a changed argument",
+			"/usr/bin/fdbserver --class=storage --datadir=/var/fdb/data",
+			"/usr/bin/fdbserver --class=log --datadir=/var/fdb/data",
+			[]string{"--class=storage"},
+			[]string{"--class=log"}),
+		Entry("a missing argument",
+			"/usr/bin/fdbserver --class=storage --knob_test=1",
+			"/usr/bin/fdbserver --class=storage",
+			[]string{"--knob_test=1"},
+			[]string{}),
+		Entry("a duplicated argument",
+			"/usr/bin/fdbserver --class=storage",
+			"/usr/bin/fdbserver --class=storage --class=storage",
+			[]string{},
+			[]string{"--class=storage"}),
+		Entry("no reported command line",
+			"/usr/bin/fdbserver --class=storage",
+			"",
+			[]string{"/usr/bin/fdbserver", "--class=storage"},
+			[]string{}),
+	)
 })
@@ -0,0 +1,121 @@
+/*
+ * notifications.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// PagerDutyEventsURL is the URL of the PagerDuty Events API that is used if no URL is defined for a PagerDuty webhook.
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// Notification contains the information about an issue with a cluster that is sent to the webhooks.
+type Notification struct {
+	// Cluster is the name of the cluster.
+	Cluster string `json:"cluster"`
+	// Namespace is the namespace of the cluster.
+	Namespace string `json:"namespace"`
+	// Reason is a machine-readable reason for the notification, e.g. ReconciliationBlocked.
+	Reason string `json:"reason"`
+	// Severity is the severity of the notification.
+	Severity fdbv1beta2.NotificationSeverity `json:"severity"`
+	// Message is a human-readable description of the issue.
+	Message string `json:"message"`
+	// Timestamp is the time when the issue was detected.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// slackPayload is the payload of a Slack incoming webhook.
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// pagerDutyPayload is the payload of an event of the PagerDuty Events API v2.
+type pagerDutyPayload struct {
+	RoutingKey  string               `json:"routing_key"`
+	EventAction string               `json:"event_action"`
+	DedupKey    string               `json:"dedup_key"`
+	Payload     pagerDutyEventDetail `json:"payload"`
+}
+
+// pagerDutyEventDetail contains the details of an event of the PagerDuty Events API v2.
+type pagerDutyEventDetail struct {
+	Summary   string    `json:"summary"`
+	Source    string    `json:"source"`
+	Severity  string    `json:"severity"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GetPayload returns the payload of the notification for the provided webhook type. The routing key is only used
+// for PagerDuty webhooks.
+func GetPayload(webhookType fdbv1beta2.NotificationWebhookType, routingKey string, notification Notification) ([]byte, error) {
+	switch webhookType {
+	case fdbv1beta2.NotificationWebhookTypeSlack:
+		return json.Marshal(slackPayload{
+			Text: fmt.Sprintf("[%s] %s/%s: %s", notification.Severity, notification.Namespace, notification.Cluster, notification.Message),
+		})
+	case fdbv1beta2.NotificationWebhookTypePagerDuty:
+		return json.Marshal(pagerDutyPayload{
+			RoutingKey:  routingKey,
+			EventAction: "trigger",
+			DedupKey:    fmt.Sprintf("%s/%s/%s", notification.Namespace, notification.Cluster, notification.Reason),
+			Payload: pagerDutyEventDetail{
+				Summary:   notification.Message,
+				Source:    fmt.Sprintf("%s/%s", notification.Namespace, notification.Cluster),
+				Severity:  strings.ToLower(string(notification.Severity)),
+				Timestamp: notification.Timestamp,
+			},
+		})
+	case fdbv1beta2.NotificationWebhookTypeGeneric:
+		return json.Marshal(notification)
+	}
+
+	return nil, fmt.Errorf("unknown webhook type %s", webhookType)
+}
+
+// Send sends the payload to the provided URL with a POST request.
+func Send(ctx context.Context, client *http.Client, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+ * notifications_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("notifications", func() {
+	notification := Notification{
+		Cluster:   "test",
+		Namespace: "test-ns",
+		Reason:    "FaultToleranceDegraded",
+		Severity:  fdbv1beta2.NotificationSeverityCritical,
+		Message:   "Data is not fully replicated",
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+	}
+
+	DescribeTable("getting the payload", func(webhookType fdbv1beta2.NotificationWebhookType, expected map[string]interface{}) {
+		payload, err := GetPayload(webhookType, "routing-key", notification)
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed := map[string]interface{}{}
+		Expect(json.Unmarshal(payload, &parsed)).NotTo(HaveOccurred())
+		Expect(parsed).To(Equal(expected))
+	},
+		Entry("generic webhook", fdbv1beta2.NotificationWebhookTypeGeneric, map[string]interface{}{
+			"cluster":   "test",
+			"namespace": "test-ns",
+			"reason":    "FaultToleranceDegraded",
+			"severity":  "Critical",
+			"message":   "Data is not fully replicated",
+			"timestamp": "2023-11-14T22:13:20Z",
+		}),
+		Entry("Slack webhook", fdbv1beta2.NotificationWebhookTypeSlack, map[string]interface{}{
+			"text": "[Critical] test-ns/test: Data is not fully replicated",
+		}),
+		Entry("PagerDuty webhook", fdbv1beta2.NotificationWebhookTypePagerDuty, map[string]interface{}{
+			"routing_key":  "routing-key",
+			"event_action": "trigger",
+			"dedup_key":    "test-ns/test/FaultToleranceDegraded",
+			"payload": map[string]interface{}{
+				"summary":   "Data is not fully replicated",
+				"source":    "test-ns/test",
+				"severity":  "critical",
+				"timestamp": "2023-11-14T22:13:20Z",
+			},
+		}),
+	)
+
+	When("sending a payload", func() {
+		var statusCode int
+		var received []byte
+		var server *httptest.Server
+		var err error
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(statusCode)
+			}))
+		})
+
+		JustBeforeEach(func() {
+			err = Send(context.TODO(), server.Client(), server.URL, []byte(`{"text":"test"}`))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should send the payload", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(received)).To(Equal(`{"text":"test"}`))
+		})
+
+		When("the webhook returns an error", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(MatchError("webhook returned status code 500"))
+			})
+		})
+	})
+})
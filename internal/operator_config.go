@@ -0,0 +1,170 @@
+/*
+ * operator_config.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"encoding/json"
+	"sort"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ApplyOperatorConfig applies the defaults of the operator config to the cluster spec. The settings of the cluster
+// spec take precedence over the defaults. This must be called before the cluster spec is normalized, otherwise the
+// defaults of the operator would take precedence over the defaults of the config.
+func ApplyOperatorConfig(cluster *fdbv1beta2.FoundationDBCluster, config *fdbv1beta2.FoundationDBOperatorConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	// The image configs are merged field by field in the order of the list, so appending the image configs of the
+	// operator config gives the image configs of the cluster precedence.
+	cluster.Spec.MainContainer.ImageConfigs = append(cluster.Spec.MainContainer.ImageConfigs, config.Spec.MainContainerImageConfigs...)
+	cluster.Spec.SidecarContainer.ImageConfigs = append(cluster.Spec.SidecarContainer.ImageConfigs, config.Spec.SidecarContainerImageConfigs...)
+
+	automationOptions, err := mergeAutomationOptions(config.Spec.AutomationOptions, cluster.Spec.AutomationOptions)
+	if err != nil {
+		return err
+	}
+	cluster.Spec.AutomationOptions = automationOptions
+
+	applyResourcePresets(cluster, config.Spec.ResourcePresets)
+
+	return nil
+}
+
+// mergeAutomationOptions merges the automation options of the cluster into the default automation options. Every
+// option that is set in the options of the cluster overrides the default option, nested options are merged.
+func mergeAutomationOptions(defaults fdbv1beta2.FoundationDBClusterAutomationOptions, options fdbv1beta2.FoundationDBClusterAutomationOptions) (fdbv1beta2.FoundationDBClusterAutomationOptions, error) {
+	defaultValues, err := toJSONMap(defaults)
+	if err != nil {
+		return options, err
+	}
+
+	values, err := toJSONMap(options)
+	if err != nil {
+		return options, err
+	}
+
+	content, err := json.Marshal(mergeJSONMaps(defaultValues, values))
+	if err != nil {
+		return options, err
+	}
+
+	merged := fdbv1beta2.FoundationDBClusterAutomationOptions{}
+	err = json.Unmarshal(content, &merged)
+
+	return merged, err
+}
+
+// toJSONMap converts the value into its generic JSON representation.
+func toJSONMap(value interface{}) (map[string]interface{}, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{}
+	err = json.Unmarshal(content, &result)
+
+	return result, err
+}
+
+// mergeJSONMaps merges the overrides into the defaults. Nested objects are merged recursively, all other values of
+// the overrides replace the values of the defaults.
+func mergeJSONMaps(defaults map[string]interface{}, overrides map[string]interface{}) map[string]interface{} {
+	for key, value := range overrides {
+		nestedOverrides, isMap := value.(map[string]interface{})
+		nestedDefaults, defaultIsMap := defaults[key].(map[string]interface{})
+		if isMap && defaultIsMap {
+			defaults[key] = mergeJSONMaps(nestedDefaults, nestedOverrides)
+			continue
+		}
+
+		defaults[key] = value
+	}
+
+	return defaults
+}
+
+// applyResourcePresets sets the resources of the main container for every process class whose pod template defines
+// no resources for the main container. The preset of the general process class is used for process classes without
+// their own preset.
+func applyResourcePresets(cluster *fdbv1beta2.FoundationDBCluster, presets map[fdbv1beta2.ProcessClass]corev1.ResourceRequirements) {
+	if len(presets) == 0 {
+		return
+	}
+
+	// The pod templates are looked up in the original spec, otherwise a preset for the general process class would
+	// hide the presets of the other process classes.
+	original := cluster.DeepCopy()
+	if cluster.Spec.Processes == nil {
+		cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{}
+	}
+
+	processClasses := map[fdbv1beta2.ProcessClass]fdbv1beta2.None{
+		fdbv1beta2.ProcessClassGeneral: {},
+	}
+	for processClass := range presets {
+		processClasses[processClass] = fdbv1beta2.None{}
+	}
+	for processClass, settings := range original.Spec.Processes {
+		if settings.PodTemplate != nil {
+			processClasses[processClass] = fdbv1beta2.None{}
+		}
+	}
+
+	sortedProcessClasses := make([]fdbv1beta2.ProcessClass, 0, len(processClasses))
+	for processClass := range processClasses {
+		sortedProcessClasses = append(sortedProcessClasses, processClass)
+	}
+	sort.Slice(sortedProcessClasses, func(i, j int) bool {
+		return sortedProcessClasses[i] < sortedProcessClasses[j]
+	})
+
+	for _, processClass := range sortedProcessClasses {
+		preset, ok := presets[processClass]
+		if !ok {
+			preset, ok = presets[fdbv1beta2.ProcessClassGeneral]
+			if !ok {
+				continue
+			}
+		}
+
+		template := &corev1.PodTemplateSpec{}
+		if originalTemplate := original.GetProcessSettings(processClass).PodTemplate; originalTemplate != nil {
+			template = originalTemplate.DeepCopy()
+		}
+
+		var index int
+		template.Spec.Containers, index = ensureContainerPresent(template.Spec.Containers, fdbv1beta2.MainContainerName, 0)
+		container := &template.Spec.Containers[index]
+		if container.Resources.Requests != nil || container.Resources.Limits != nil {
+			continue
+		}
+
+		container.Resources = *preset.DeepCopy()
+		settings := cluster.Spec.Processes[processClass]
+		settings.PodTemplate = template
+		cluster.Spec.Processes[processClass] = settings
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * operator_config_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("operator_config", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var config *fdbv1beta2.FoundationDBOperatorConfig
+
+	smallResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	}
+	largeResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+	}
+
+	getMainContainerResources := func(processClass fdbv1beta2.ProcessClass) corev1.ResourceRequirements {
+		template := cluster.GetProcessSettings(processClass).PodTemplate
+		Expect(template).NotTo(BeNil())
+		for _, container := range template.Spec.Containers {
+			if container.Name == fdbv1beta2.MainContainerName {
+				return container.Resources
+			}
+		}
+
+		return corev1.ResourceRequirements{}
+	}
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		config = &fdbv1beta2.FoundationDBOperatorConfig{}
+	})
+
+	When("no config is provided", func() {
+		It("should not change the cluster", func() {
+			expected := cluster.DeepCopy()
+			Expect(ApplyOperatorConfig(cluster, nil)).NotTo(HaveOccurred())
+			Expect(cluster).To(Equal(expected))
+		})
+	})
+
+	When("the config defines image configs", func() {
+		BeforeEach(func() {
+			cluster.Spec.MainContainer.ImageConfigs = []fdbv1beta2.ImageConfig{{Tag: "custom"}}
+			config.Spec.MainContainerImageConfigs = []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb", Tag: "latest"}}
+			config.Spec.SidecarContainerImageConfigs = []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/sidecar"}}
+			Expect(ApplyOperatorConfig(cluster, config)).NotTo(HaveOccurred())
+		})
+
+		It("should give the image configs of the cluster precedence", func() {
+			Expect(fdbv1beta2.SelectImageConfig(cluster.Spec.MainContainer.ImageConfigs, cluster.Spec.Version).Image()).To(Equal("registry.example/foundationdb:custom"))
+			Expect(cluster.Spec.SidecarContainer.ImageConfigs).To(ConsistOf(fdbv1beta2.ImageConfig{BaseImage: "registry.example/sidecar"}))
+		})
+	})
+
+	When("the config defines automation options", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.Replacements.Enabled = pointer.Bool(false)
+			cluster.Spec.AutomationOptions.SettleTimeSeconds = pointer.Int(120)
+			config.Spec.AutomationOptions = fdbv1beta2.FoundationDBClusterAutomationOptions{
+				SettleTimeSeconds:             pointer.Int(30),
+				IgnoreMissingProcessesSeconds: pointer.Int(60),
+				Replacements: fdbv1beta2.AutomaticReplacementOptions{
+					Enabled:                   pointer.Bool(true),
+					MaxConcurrentReplacements: pointer.Int(3),
+				},
+			}
+			Expect(ApplyOperatorConfig(cluster, config)).NotTo(HaveOccurred())
+		})
+
+		It("should merge the automation options", func() {
+			Expect(cluster.Spec.AutomationOptions.SettleTimeSeconds).To(Equal(pointer.Int(120)))
+			Expect(cluster.Spec.AutomationOptions.IgnoreMissingProcessesSeconds).To(Equal(pointer.Int(60)))
+			Expect(cluster.Spec.AutomationOptions.Replacements.Enabled).To(Equal(pointer.Bool(false)))
+			Expect(cluster.Spec.AutomationOptions.Replacements.MaxConcurrentReplacements).To(Equal(pointer.Int(3)))
+		})
+	})
+
+	When("the config defines resource presets", func() {
+		BeforeEach(func() {
+			config.Spec.ResourcePresets = map[fdbv1beta2.ProcessClass]corev1.ResourceRequirements{
+				fdbv1beta2.ProcessClassGeneral: smallResources,
+				fdbv1beta2.ProcessClassStorage: largeResources,
+			}
+		})
+
+		When("the cluster defines no resources", func() {
+			BeforeEach(func() {
+				Expect(ApplyOperatorConfig(cluster, config)).NotTo(HaveOccurred())
+			})
+
+			It("should use the presets", func() {
+				Expect(getMainContainerResources(fdbv1beta2.ProcessClassStorage)).To(Equal(largeResources))
+				Expect(getMainContainerResources(fdbv1beta2.ProcessClassLog)).To(Equal(smallResources))
+				Expect(getMainContainerResources(fdbv1beta2.ProcessClassGeneral)).To(Equal(smallResources))
+			})
+
+			It("should keep the remaining pod template for the process class with its own preset", func() {
+				storageTemplate := cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage].PodTemplate
+				generalTemplate := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate
+				Expect(storageTemplate.Spec.Containers).To(HaveLen(len(generalTemplate.Spec.Containers)))
+			})
+		})
+
+		When("the cluster defines resources for the general process class", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+					fdbv1beta2.ProcessClassGeneral: {
+						PodTemplate: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: fdbv1beta2.MainContainerName,
+										Resources: corev1.ResourceRequirements{
+											Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+				Expect(ApplyOperatorConfig(cluster, config)).NotTo(HaveOccurred())
+			})
+
+			It("should keep the resources of the cluster", func() {
+				Expect(getMainContainerResources(fdbv1beta2.ProcessClassStorage).Limits).To(HaveKey(corev1.ResourceMemory))
+				Expect(getMainContainerResources(fdbv1beta2.ProcessClassStorage).Requests).To(BeNil())
+				Expect(getMainContainerResources(fdbv1beta2.ProcessClassLog).Requests).To(BeNil())
+			})
+		})
+	})
+})
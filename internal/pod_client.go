@@ -21,6 +21,7 @@
 package internal
 
 import (
+	"context"
 	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
@@ -34,6 +35,7 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -70,8 +72,41 @@ const (
 	// EnvironmentAnnotation is the annotation we use to store the environment
 	// variables.
 	EnvironmentAnnotation = "foundationdb.org/launcher-environment"
+
+	// ProxyFromEnvironment defines that the proxy is selected by the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+	ProxyFromEnvironment = "environment"
+
+	// ProxyNone defines that no proxy is used.
+	ProxyNone = "none"
+
+	// PodLayoutEnvironmentVariable defines the environment variable in the
+	// process containers that contains the Pod layout.
+	PodLayoutEnvironmentVariable = "FDB_POD_LAYOUT"
 )
 
+// GetProxyFunc returns the function that selects the proxy for HTTP requests. The setting can either be
+// ProxyFromEnvironment, ProxyNone or the URL of a proxy that is used for all requests.
+func GetProxyFunc(setting string) (func(*http.Request) (*url.URL, error), error) {
+	switch setting {
+	case "", ProxyFromEnvironment:
+		return http.ProxyFromEnvironment, nil
+	case ProxyNone:
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %s, the URL must contain a scheme and a host", setting)
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
 // realPodSidecarClient provides a client for use in real environments, using
 // the Kubernetes sidecar.
 type realFdbPodSidecarClient struct {
@@ -96,6 +131,9 @@ type realFdbPodSidecarClient struct {
 
 	// postTimeout defines the timeout for post requests
 	postTimeout time.Duration
+
+	// proxy selects the proxy for the requests to the sidecar.
+	proxy func(*http.Request) (*url.URL, error)
 }
 
 // realPodSidecarClient provides a client for use in real environments, using
@@ -111,9 +149,29 @@ type realFdbPodAnnotationClient struct {
 	logger logr.Logger
 }
 
-// NewFdbPodClient builds a client for working with an FDB Pod
-func NewFdbPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, log logr.Logger, getTimeout time.Duration, postTimeout time.Duration) (podclient.FdbPodClient, error) {
+// realFdbPodProcessContainerClient provides a client for use in real
+// environments for Pods that run every fdbserver process in its own
+// container. The configuration is part of the Pod spec, so there are no files
+// to update in the Pod.
+type realFdbPodProcessContainerClient struct {
+	// Cluster is the cluster we are connecting to.
+	Cluster *fdbv1beta2.FoundationDBCluster
+
+	// Pod is the pod we are connecting to.
+	Pod *corev1.Pod
+
+	// logger is used to add common fields to log messages.
+	logger logr.Logger
+}
+
+// NewFdbPodClient builds a client for working with an FDB Pod. The proxy selects the proxy for the requests to the
+// sidecar, a nil proxy disables the proxy.
+func NewFdbPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, log logr.Logger, getTimeout time.Duration, postTimeout time.Duration, proxy func(*http.Request) (*url.URL, error)) (podclient.FdbPodClient, error) {
 	if GetImageType(pod) == FDBImageTypeUnified {
+		if GetPodLayout(pod) == fdbv1beta2.PodLayoutProcessPerContainer {
+			return &realFdbPodProcessContainerClient{Cluster: cluster, Pod: pod, logger: log}, nil
+		}
+
 		return &realFdbPodAnnotationClient{Cluster: cluster, Pod: pod, logger: log}, nil
 	}
 
@@ -126,18 +184,20 @@ func NewFdbPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, l
 		}
 	}
 
+	// The protocol is selected based on the current arguments of the sidecar and not on the desired spec, so the
+	// operator can talk to the updated and the remaining Pods while the TLS setting of the sidecar is changed.
 	useTLS := podHasSidecarTLS(pod)
 
 	var tlsConfig = &tls.Config{}
 	if useTLS {
+		if !HasSidecarTLSConfiguration() {
+			return nil, errors.New("missing one or more TLS env vars: FDB_TLS_CERTIFICATE_FILE, FDB_TLS_KEY_FILE or FDB_TLS_CA_FILE")
+		}
+
 		certFile := os.Getenv("FDB_TLS_CERTIFICATE_FILE")
 		keyFile := os.Getenv("FDB_TLS_KEY_FILE")
 		caFile := os.Getenv("FDB_TLS_CA_FILE")
 
-		if certFile == "" || keyFile == "" || caFile == "" {
-			return nil, errors.New("missing one or more TLS env vars: FDB_TLS_CERTIFICATE_FILE, FDB_TLS_KEY_FILE or FDB_TLS_CA_FILE")
-		}
-
 		cert, err := tls.LoadX509KeyPair(
 			certFile,
 			keyFile,
@@ -158,7 +218,7 @@ func NewFdbPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, l
 		tlsConfig.RootCAs = certPool
 	}
 
-	return &realFdbPodSidecarClient{Cluster: cluster, Pod: pod, useTLS: useTLS, tlsConfig: tlsConfig, logger: log, getTimeout: getTimeout, postTimeout: postTimeout}, nil
+	return &realFdbPodSidecarClient{Cluster: cluster, Pod: pod, useTLS: useTLS, tlsConfig: tlsConfig, logger: log, getTimeout: getTimeout, postTimeout: postTimeout, proxy: proxy}, nil
 }
 
 // getListenIP gets the IP address that a pod listens on.
@@ -173,14 +233,14 @@ func (client *realFdbPodSidecarClient) getListenIP() string {
 
 // generateRequest will generate a retryablehttp.Request for the provided parameters or an error if a request cannot be
 // generated.
-func generateRequest(retryClient *retryablehttp.Client, url string, method string, getTimeout time.Duration, postTimeout time.Duration) (*retryablehttp.Request, error) {
+func generateRequest(ctx context.Context, retryClient *retryablehttp.Client, url string, method string, getTimeout time.Duration, postTimeout time.Duration) (*retryablehttp.Request, error) {
 	switch method {
 	case http.MethodGet:
 		retryClient.HTTPClient.Timeout = getTimeout
-		return retryablehttp.NewRequest(http.MethodGet, url, nil)
+		return retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	case http.MethodPost:
 		retryClient.HTTPClient.Timeout = postTimeout
-		req, err := retryablehttp.NewRequest(http.MethodPost, url, strings.NewReader(""))
+		req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(""))
 		if err != nil {
 			return nil, err
 		}
@@ -191,13 +251,28 @@ func generateRequest(retryClient *retryablehttp.Client, url string, method strin
 	return nil, fmt.Errorf("unknown HTTP method %s", method)
 }
 
+// configureTransport sets the TLS configuration and the proxy of the client on the transport of the retry client.
+func (client *realFdbPodSidecarClient) configureTransport(retryClient *retryablehttp.Client) {
+	transport, ok := retryClient.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		transport = &http.Transport{}
+		retryClient.HTTPClient.Transport = transport
+	}
+
+	if client.useTLS {
+		transport.TLSClientConfig = client.tlsConfig
+	}
+
+	transport.Proxy = client.proxy
+}
+
 // makeRequest submits a request to the sidecar.
-func (client *realFdbPodSidecarClient) makeRequest(method, path string) (string, int, error) {
+func (client *realFdbPodSidecarClient) makeRequest(ctx context.Context, method, path string) (string, int, error) {
 	var err error
 
 	target := url.URL{
 		Scheme: "http",
-		Host:   client.getListenIP() + ":8080",
+		Host:   net.JoinHostPort(client.getListenIP(), strconv.Itoa(client.Cluster.GetSidecarPort(GetProcessClassFromMeta(client.Cluster, client.Pod.ObjectMeta)))),
 		Path:   path,
 	}
 	retryClient := retryablehttp.NewClient()
@@ -207,12 +282,12 @@ func (client *realFdbPodSidecarClient) makeRequest(method, path string) (string,
 	retryClient.Logger = nil
 	retryClient.CheckRetry = retryablehttp.ErrorPropagatedRetryPolicy
 
+	client.configureTransport(retryClient)
 	if client.useTLS {
-		retryClient.HTTPClient.Transport = &http.Transport{TLSClientConfig: client.tlsConfig}
 		target.Scheme = "https"
 	}
 
-	req, err := generateRequest(retryClient, target.String(), method, client.getTimeout, client.postTimeout)
+	req, err := generateRequest(ctx, retryClient, target.String(), method, client.getTimeout, client.postTimeout)
 	if err != nil {
 		return "", 0, err
 	}
@@ -237,7 +312,7 @@ func (client *realFdbPodSidecarClient) makeRequest(method, path string) (string,
 }
 
 // IsPresent checks whether a file in the sidecar is present.
-func (client *realFdbPodSidecarClient) IsPresent(filename string) (bool, error) {
+func (client *realFdbPodSidecarClient) IsPresent(ctx context.Context, filename string) (bool, error) {
 	version, err := fdbv1beta2.ParseFdbVersion(client.Cluster.Spec.Version)
 	if err != nil {
 		return false, err
@@ -251,7 +326,7 @@ func (client *realFdbPodSidecarClient) IsPresent(filename string) (bool, error)
 		path = "is_present"
 	}
 
-	_, code, err := client.makeRequest("GET", fmt.Sprintf("%s/%s", path, filename))
+	_, code, err := client.makeRequest(ctx, "GET", fmt.Sprintf("%s/%s", path, filename))
 	if err != nil || code != http.StatusOK {
 		client.logger.Info("Waiting for file", "file", filename, "response_code", code)
 		return false, err
@@ -261,8 +336,8 @@ func (client *realFdbPodSidecarClient) IsPresent(filename string) (bool, error)
 }
 
 // CheckHash checks whether a file in the sidecar has the expected contents.
-func (client *realFdbPodSidecarClient) checkHash(filename string, contents string) (bool, error) {
-	response, _, err := client.makeRequest("GET", fmt.Sprintf("check_hash/%s", filename))
+func (client *realFdbPodSidecarClient) CheckHash(ctx context.Context, filename string, contents string) (bool, error) {
+	response, _, err := client.makeRequest(ctx, "GET", fmt.Sprintf("check_hash/%s", filename))
 	if err != nil {
 		return false, err
 	}
@@ -273,60 +348,62 @@ func (client *realFdbPodSidecarClient) checkHash(filename string, contents strin
 }
 
 // GenerateMonitorConf updates the monitor conf file for a pod
-func (client *realFdbPodSidecarClient) generateMonitorConf() error {
-	_, _, err := client.makeRequest("POST", "copy_monitor_conf")
+func (client *realFdbPodSidecarClient) generateMonitorConf(ctx context.Context) error {
+	_, _, err := client.makeRequest(ctx, "POST", "copy_monitor_conf")
 	return err
 }
 
 // copyFiles copies the files from the config map to the shared dynamic conf
 // volume
-func (client *realFdbPodSidecarClient) copyFiles() error {
-	_, _, err := client.makeRequest("POST", "copy_files")
+func (client *realFdbPodSidecarClient) copyFiles(ctx context.Context) error {
+	_, _, err := client.makeRequest(ctx, "POST", "copy_files")
 	return err
 }
 
 // GetVariableSubstitutions gets the current keys and values that this
 // process group will substitute into its monitor conf.
-func (client *realFdbPodSidecarClient) GetVariableSubstitutions() (map[string]string, error) {
-	contents, _, err := client.makeRequest("GET", "substitutions")
+func (client *realFdbPodSidecarClient) GetVariableSubstitutions(ctx context.Context) (map[string]string, error) {
+	contents, _, err := client.makeRequest(ctx, "GET", "substitutions")
 	if err != nil {
 		return nil, err
 	}
 	substitutions := map[string]string{}
 	err = json.Unmarshal([]byte(contents), &substitutions)
 	if err != nil {
-		client.logger.Error(err, "Error deserializing pod substitutions", "responseBody", contents)
+		client.logger.Error(err, "Error deserializing pod substitutions", "responseBody", RedactString(contents))
 	}
 	return substitutions, err
 }
 
 // UpdateFile checks if a file is up-to-date and tries to update it.
-func (client *realFdbPodSidecarClient) UpdateFile(name string, contents string) (bool, error) {
+func (client *realFdbPodSidecarClient) UpdateFile(ctx context.Context, name string, contents string) (bool, error) {
 	if name == "fdbmonitor.conf" {
-		return client.updateDynamicFiles(name, contents, func(client *realFdbPodSidecarClient) error { return client.generateMonitorConf() })
+		return client.updateDynamicFiles(ctx, name, contents, func(ctx context.Context, client *realFdbPodSidecarClient) error {
+			return client.generateMonitorConf(ctx)
+		})
 	}
-	return client.updateDynamicFiles(name, contents, func(client *realFdbPodSidecarClient) error { return client.copyFiles() })
+	return client.updateDynamicFiles(ctx, name, contents, func(ctx context.Context, client *realFdbPodSidecarClient) error { return client.copyFiles(ctx) })
 }
 
 // updateDynamicFiles checks if the files in the dynamic conf volume match the
 // expected contents, and tries to copy the latest files from the input volume
 // if they do not.
-func (client *realFdbPodSidecarClient) updateDynamicFiles(filename string, contents string, updateFunc func(client *realFdbPodSidecarClient) error) (bool, error) {
+func (client *realFdbPodSidecarClient) updateDynamicFiles(ctx context.Context, filename string, contents string, updateFunc func(ctx context.Context, client *realFdbPodSidecarClient) error) (bool, error) {
 	match := false
 	var err error
 
-	match, err = client.checkHash(filename, contents)
+	match, err = client.CheckHash(ctx, filename, contents)
 	if err != nil {
 		return false, err
 	}
 
 	if !match {
-		err = updateFunc(client)
+		err = updateFunc(ctx, client)
 		if err != nil {
 			return false, err
 		}
 		// We check this more or less instantly, maybe we should add some delay?
-		match, err = client.checkHash(filename, contents)
+		match, err = client.CheckHash(ctx, filename, contents)
 		if !match {
 			client.logger.Info("Waiting for config update", "file", filename)
 		}
@@ -339,7 +416,7 @@ func (client *realFdbPodSidecarClient) updateDynamicFiles(filename string, conte
 
 // GetVariableSubstitutions gets the current keys and values that this
 // instance will substitute into its monitor conf.
-func (client *realFdbPodAnnotationClient) GetVariableSubstitutions() (map[string]string, error) {
+func (client *realFdbPodAnnotationClient) GetVariableSubstitutions(_ context.Context) (map[string]string, error) {
 	environmentData, present := client.Pod.Annotations[EnvironmentAnnotation]
 	if !present {
 		client.logger.Info("Waiting for Kubernetes monitor to update annotations", "annotation", EnvironmentAnnotation)
@@ -355,7 +432,7 @@ func (client *realFdbPodAnnotationClient) GetVariableSubstitutions() (map[string
 }
 
 // UpdateFile checks if a file is up-to-date and tries to update it.
-func (client *realFdbPodAnnotationClient) UpdateFile(name string, contents string) (bool, error) {
+func (client *realFdbPodAnnotationClient) UpdateFile(_ context.Context, name string, contents string) (bool, error) {
 	if name == "fdb.cluster" {
 		// We can ignore cluster file updates in the unified image.
 		return true, nil
@@ -364,7 +441,7 @@ func (client *realFdbPodAnnotationClient) UpdateFile(name string, contents strin
 		desiredConfiguration := monitorapi.ProcessConfiguration{}
 		err := json.Unmarshal([]byte(contents), &desiredConfiguration)
 		if err != nil {
-			client.logger.Error(err, "Error parsing desired process configuration", "input", contents)
+			client.logger.Error(err, "Error parsing desired process configuration", "input", RedactString(contents))
 			return false, err
 		}
 		currentConfiguration := monitorapi.ProcessConfiguration{}
@@ -375,7 +452,7 @@ func (client *realFdbPodAnnotationClient) UpdateFile(name string, contents strin
 		}
 		err = json.Unmarshal([]byte(currentData), &currentConfiguration)
 		if err != nil {
-			client.logger.Error(err, "Error parsing current process configuration", "input", currentData)
+			client.logger.Error(err, "Error parsing current process configuration", "input", RedactString(currentData))
 			return false, err
 		}
 		match := reflect.DeepEqual(currentConfiguration, desiredConfiguration)
@@ -389,17 +466,111 @@ func (client *realFdbPodAnnotationClient) UpdateFile(name string, contents strin
 	return false, fmt.Errorf("unknown file %s", name)
 }
 
+// CheckHash checks whether a file has the expected contents. The cluster file is always reported as matching, because
+// the unified image reads the cluster file from the ConfigMap.
+func (client *realFdbPodAnnotationClient) CheckHash(_ context.Context, name string, _ string) (bool, error) {
+	if name == "fdb.cluster" {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("unknown file %s", name)
+}
+
 // IsPresent checks whether a file in the sidecar is present.
 // This implementation always returns true, because the unified image handles
 // these checks internally.
-func (client *realFdbPodAnnotationClient) IsPresent(_ string) (bool, error) {
+func (client *realFdbPodAnnotationClient) IsPresent(_ context.Context, _ string) (bool, error) {
 	return true, nil
 }
 
+// GetVariableSubstitutions gets the current keys and values that this
+// instance will substitute into the arguments of the process containers.
+// Without a monitor process the substitutions are taken from the cluster and
+// the Pod, including the environment variables of the main container that
+// have a literal value.
+func (client *realFdbPodProcessContainerClient) GetVariableSubstitutions(_ context.Context) (map[string]string, error) {
+	substitutions, err := GetSubstitutionsFromClusterAndPod(client.logger, client.Cluster, client.Pod)
+	if err != nil || substitutions == nil {
+		return substitutions, err
+	}
+
+	for _, container := range client.Pod.Spec.Containers {
+		if container.Name != fdbv1beta2.MainContainerName {
+			continue
+		}
+
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				continue
+			}
+
+			if _, ok := substitutions[env.Name]; !ok {
+				substitutions[env.Name] = env.Value
+			}
+		}
+	}
+
+	// The environment variables can contain credentials, e.g. the TLS password, so only log the redacted substitutions.
+	client.logger.V(1).Info("Variable substitutions from the Pod", "substitutions", RedactSubstitutions(substitutions))
+
+	return substitutions, nil
+}
+
+// UpdateFile checks if a file is up-to-date. The cluster file is read from the
+// ConfigMap and the process configuration is part of the Pod spec, so changes
+// of the process configuration are rolled out by updating the Pod.
+func (client *realFdbPodProcessContainerClient) UpdateFile(_ context.Context, name string, contents string) (bool, error) {
+	if name == "fdb.cluster" {
+		return true, nil
+	}
+
+	if name == "fdbmonitor.conf" {
+		desiredConfiguration := monitorapi.ProcessConfiguration{}
+		err := json.Unmarshal([]byte(contents), &desiredConfiguration)
+		if err != nil {
+			client.logger.Error(err, "Error parsing desired process configuration", "input", contents)
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	return false, fmt.Errorf("unknown file %s", name)
+}
+
+// CheckHash checks whether a file has the expected contents. The cluster file is always reported as matching, because
+// the processes read the cluster file from the ConfigMap.
+func (client *realFdbPodProcessContainerClient) CheckHash(_ context.Context, name string, _ string) (bool, error) {
+	if name == "fdb.cluster" {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("unknown file %s", name)
+}
+
+// IsPresent checks whether a file in the Pod is present.
+// This implementation always returns true, because the binaries are part of
+// the image of the process containers.
+func (client *realFdbPodProcessContainerClient) IsPresent(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
+// HasSidecarTLSConfiguration returns true if the operator has the TLS configuration that is required to talk to
+// sidecars with TLS enabled.
+func HasSidecarTLSConfiguration() bool {
+	return os.Getenv("FDB_TLS_CERTIFICATE_FILE") != "" && os.Getenv("FDB_TLS_KEY_FILE") != "" && os.Getenv("FDB_TLS_CA_FILE") != ""
+}
+
 // podHasSidecarTLS determines whether a pod currently has TLS enabled for the
 // sidecar process.
 func podHasSidecarTLS(pod *corev1.Pod) bool {
-	for _, container := range pod.Spec.Containers {
+	return PodSpecHasSidecarTLS(&pod.Spec)
+}
+
+// PodSpecHasSidecarTLS determines whether the Pod spec has TLS enabled for the
+// sidecar process.
+func PodSpecHasSidecarTLS(spec *corev1.PodSpec) bool {
+	for _, container := range spec.Containers {
 		if container.Name == fdbv1beta2.SidecarContainerName {
 			for _, arg := range container.Args {
 				if arg == "--tls" {
@@ -429,6 +600,23 @@ func GetImageType(pod *corev1.Pod) FDBImageType {
 	return FDBImageTypeSplit
 }
 
+// GetPodLayout determines whether a Pod runs every fdbserver process in its
+// own container.
+func GetPodLayout(pod *corev1.Pod) fdbv1beta2.PodLayout {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != fdbv1beta2.MainContainerName {
+			continue
+		}
+		for _, envVar := range container.Env {
+			if envVar.Name == PodLayoutEnvironmentVariable {
+				return fdbv1beta2.PodLayout(envVar.Value)
+			}
+		}
+	}
+
+	return fdbv1beta2.PodLayoutMonitor
+}
+
 // GetDesiredImageType determines whether a cluster is configured to use the
 // unified or the split image.
 func GetDesiredImageType(cluster *fdbv1beta2.FoundationDBCluster) FDBImageType {
@@ -438,6 +626,43 @@ func GetDesiredImageType(cluster *fdbv1beta2.FoundationDBCluster) FDBImageType {
 	return FDBImageTypeSplit
 }
 
+// formatIPForSubstitution validates the IP address and adds brackets around IPv6 addresses.
+func formatIPForSubstitution(ipString string) (string, error) {
+	if ipString == "" {
+		return ipString, nil
+	}
+
+	ip := net.ParseIP(ipString)
+	if ip == nil {
+		return "", fmt.Errorf("failed to parse IP from pod: %s", ipString)
+	}
+
+	if ip.To4() == nil {
+		return fmt.Sprintf("[%s]", ipString), nil
+	}
+
+	return ipString, nil
+}
+
+// GetZoneIDFromPod returns the zone ID of the Pod based on the fault domain of the cluster. This allows to determine
+// the zone ID without asking the sidecar for the variable substitutions.
+func GetZoneIDFromPod(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (string, error) {
+	if cluster.Spec.FaultDomain.Key == fdbv1beta2.NoneFaultDomainKey {
+		return pod.Name, nil
+	}
+
+	if cluster.Spec.FaultDomain.Key == "foundationdb.org/kubernetes-cluster" {
+		return cluster.Spec.FaultDomain.Value, nil
+	}
+
+	faultDomainSource := cluster.Spec.FaultDomain.ValueFrom
+	if faultDomainSource != "" && faultDomainSource != "spec.nodeName" {
+		return "", fmt.Errorf("unsupported fault domain source %s", faultDomainSource)
+	}
+
+	return pod.Spec.NodeName, nil
+}
+
 // GetSubstitutionsFromClusterAndPod returns a map that contains the substitutions based on the provided cluster and Pod.
 // This method is used for testing and in the MockFdbPodClient.
 func GetSubstitutionsFromClusterAndPod(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (map[string]string, error) {
@@ -449,38 +674,30 @@ func GetSubstitutionsFromClusterAndPod(logger logr.Logger, cluster *fdbv1beta2.F
 		}
 	}
 
-	ipString := GetPublicIPsForPod(pod, logger)[0]
-	substitutions["FDB_PUBLIC_IP"] = ipString
-	if ipString != "" {
-		ip := net.ParseIP(ipString)
-		if ip == nil {
-			return nil, fmt.Errorf("failed to parse IP from pod: %s", ipString)
-		}
+	podIP, err := formatIPForSubstitution(GetPublicIPsForPod(pod, logger)[0])
+	if err != nil {
+		return nil, err
+	}
+	substitutions["FDB_POD_IP"] = podIP
 
-		if ip.To4() == nil {
-			substitutions["FDB_PUBLIC_IP"] = fmt.Sprintf("[%s]", ipString)
+	// Processes listen on the Pod IP and use the public IP from the public IP source.
+	substitutions["FDB_PUBLIC_IP"] = podIP
+	if cluster.GetPublicIPSource() != fdbv1beta2.PublicIPSourcePod {
+		substitutions["FDB_PUBLIC_IP"], err = formatIPForSubstitution(GetPublicIPs(pod, logger)[0])
+		if err != nil {
+			return nil, err
 		}
 	}
-	substitutions["FDB_POD_IP"] = substitutions["FDB_PUBLIC_IP"]
 
 	if cluster.Spec.FaultDomain.Key == fdbv1beta2.NoneFaultDomainKey {
 		substitutions["FDB_MACHINE_ID"] = pod.Name
-		substitutions["FDB_ZONE_ID"] = pod.Name
-	} else if cluster.Spec.FaultDomain.Key == "foundationdb.org/kubernetes-cluster" {
-		substitutions["FDB_MACHINE_ID"] = pod.Spec.NodeName
-		substitutions["FDB_ZONE_ID"] = cluster.Spec.FaultDomain.Value
 	} else {
-		faultDomainSource := cluster.Spec.FaultDomain.ValueFrom
-		if faultDomainSource == "" {
-			faultDomainSource = "spec.nodeName"
-		}
 		substitutions["FDB_MACHINE_ID"] = pod.Spec.NodeName
+	}
 
-		if faultDomainSource == "spec.nodeName" {
-			substitutions["FDB_ZONE_ID"] = pod.Spec.NodeName
-		} else {
-			return nil, fmt.Errorf("unsupported fault domain source %s", faultDomainSource)
-		}
+	substitutions["FDB_ZONE_ID"], err = GetZoneIDFromPod(cluster, pod)
+	if err != nil {
+		return nil, err
 	}
 
 	substitutions["FDB_INSTANCE_ID"] = string(GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta))
@@ -21,11 +21,14 @@
 package internal
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-retryablehttp"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -64,6 +67,18 @@ var _ = Describe("pod_client", func() {
 		})
 	})
 
+	When("checking the TLS configuration of the operator", func() {
+		It("should require the certificate, the key and the CA file", func() {
+			GinkgoT().Setenv("FDB_TLS_CERTIFICATE_FILE", "/tmp/fdb-certs/tls.crt")
+			GinkgoT().Setenv("FDB_TLS_KEY_FILE", "/tmp/fdb-certs/tls.key")
+			GinkgoT().Setenv("FDB_TLS_CA_FILE", "")
+			Expect(HasSidecarTLSConfiguration()).To(BeFalse())
+
+			GinkgoT().Setenv("FDB_TLS_CA_FILE", "/tmp/fdb-certs/ca.crt")
+			Expect(HasSidecarTLSConfiguration()).To(BeTrue())
+		})
+	})
+
 	When("generating a request", func() {
 		var retryClient *retryablehttp.Client
 		var target url.URL
@@ -81,7 +96,7 @@ var _ = Describe("pod_client", func() {
 
 		When("generating a http get request", func() {
 			It("should generate the request", func() {
-				req, err := generateRequest(retryClient, target.String(), http.MethodGet, getTimeout, postTimeout)
+				req, err := generateRequest(context.TODO(), retryClient, target.String(), http.MethodGet, getTimeout, postTimeout)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(req.Method).To(Equal(http.MethodGet))
 				Expect(retryClient.HTTPClient.Timeout).To(Equal(getTimeout))
@@ -90,7 +105,7 @@ var _ = Describe("pod_client", func() {
 
 		When("generating a http post request", func() {
 			It("should generate the request", func() {
-				req, err := generateRequest(retryClient, target.String(), http.MethodPost, getTimeout, postTimeout)
+				req, err := generateRequest(context.TODO(), retryClient, target.String(), http.MethodPost, getTimeout, postTimeout)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(req.Method).To(Equal(http.MethodPost))
 				Expect(retryClient.HTTPClient.Timeout).To(Equal(postTimeout))
@@ -100,10 +115,82 @@ var _ = Describe("pod_client", func() {
 
 		When("generating a http delete request", func() {
 			It("should generate the request", func() {
-				req, err := generateRequest(retryClient, target.String(), http.MethodDelete, getTimeout, postTimeout)
+				req, err := generateRequest(context.TODO(), retryClient, target.String(), http.MethodDelete, getTimeout, postTimeout)
 				Expect(err).To(HaveOccurred())
 				Expect(req).To(BeNil())
 			})
 		})
 	})
+
+	When("parsing the proxy setting", func() {
+		DescribeTable("should return the proxy for the request",
+			func(setting string, expected string) {
+				proxy, err := GetProxyFunc(setting)
+				Expect(err).NotTo(HaveOccurred())
+				if expected == "" {
+					Expect(proxy).To(BeNil())
+					return
+				}
+
+				req, err := http.NewRequest(http.MethodGet, "http://1.1.1.1:8080/ready", nil)
+				Expect(err).NotTo(HaveOccurred())
+				proxyURL, err := proxy(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proxyURL.String()).To(Equal(expected))
+			},
+			Entry("no proxy", ProxyNone, ""),
+			Entry("an explicit proxy", "http://proxy.example:3128", "http://proxy.example:3128"),
+		)
+
+		It("should use the environment by default", func() {
+			proxy, err := GetProxyFunc(ProxyFromEnvironment)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxy).NotTo(BeNil())
+		})
+
+		It("should reject an invalid proxy URL", func() {
+			_, err := GetProxyFunc("proxy.example")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("making a request through a proxy", func() {
+		var proxyServer *httptest.Server
+		var requestedHosts []string
+
+		BeforeEach(func() {
+			requestedHosts = nil
+			proxyServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				requestedHosts = append(requestedHosts, req.Host)
+				_, _ = w.Write([]byte("OK"))
+			}))
+		})
+
+		AfterEach(func() {
+			proxyServer.Close()
+		})
+
+		It("should send the request to the proxy", func() {
+			pod, err := GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			pod.Status.PodIP = "192.0.2.1"
+
+			proxy, err := GetProxyFunc(proxyServer.URL)
+			Expect(err).NotTo(HaveOccurred())
+			client := &realFdbPodSidecarClient{
+				Cluster:     cluster,
+				Pod:         pod,
+				logger:      logr.Discard(),
+				getTimeout:  time.Second,
+				postTimeout: time.Second,
+				proxy:       proxy,
+			}
+
+			body, code, err := client.makeRequest(context.TODO(), http.MethodGet, "ready")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(body).To(Equal("OK"))
+			Expect(requestedHosts).To(ConsistOf("192.0.2.1:8080"))
+		})
+	})
 })
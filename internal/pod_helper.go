@@ -114,6 +114,175 @@ func GetPodSpecHash(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1b
 	return GetJSONHash(spec)
 }
 
+// podTemplateHashLength defines the length of the Pod template hash, which is shortened like the pod-template-hash of
+// a ReplicaSet to make the revisions readable.
+const podTemplateHashLength = 10
+
+// GetPodTemplateHash builds the hash of the Pod template for a process class. In contrast to GetPodSpecHash the hash
+// doesn't depend on a specific process group, the spec is generated for the ID 0, which is never assigned to a
+// process group. Settings for a single process group, e.g. a pin, are not part of the hash.
+func GetPodTemplateHash(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass) (string, error) {
+	spec, err := GetPodSpec(cluster, processClass, 0)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := GetJSONHash(spec)
+	if err != nil {
+		return "", err
+	}
+
+	return hash[:podTemplateHashLength], nil
+}
+
+// IsResourceOnlyUpdate returns true if the Pod only differs from its desired spec in the resource requirements of its
+// containers. Changes to the resources of init containers are not included, since they cannot be changed for a
+// running Pod.
+func IsResourceOnlyUpdate(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, id int, pod *corev1.Pod) (bool, error) {
+	spec, err := GetPodSpec(cluster, processClass, id)
+	if err != nil {
+		return false, err
+	}
+
+	specHash, err := GetPodSpecHash(cluster, processClass, id, spec)
+	if err != nil {
+		return false, err
+	}
+
+	lastSpecHash := pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]
+	if lastSpecHash == specHash {
+		return false, nil
+	}
+
+	// Use the current resources in the desired spec, if the hash matches the last applied spec, the resources are the
+	// only difference. Additional containers of the Pod are not part of the desired spec and will be ignored.
+	for idx, container := range spec.Containers {
+		currentContainer := GetContainer(pod.Spec.Containers, container.Name)
+		if currentContainer == nil {
+			return false, nil
+		}
+
+		spec.Containers[idx].Resources = *currentContainer.Resources.DeepCopy()
+	}
+
+	currentSpecHash, err := GetPodSpecHash(cluster, processClass, id, spec)
+	if err != nil {
+		return false, err
+	}
+
+	return lastSpecHash == currentSpecHash, nil
+}
+
+// IsSidecarTLSUpdate returns true if the sidecar of the Pod uses a different TLS setting than the sidecar of its
+// desired spec.
+func IsSidecarTLSUpdate(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, id int, pod *corev1.Pod) (bool, error) {
+	spec, err := GetPodSpec(cluster, processClass, id)
+	if err != nil {
+		return false, err
+	}
+
+	return PodSpecHasSidecarTLS(spec) != podHasSidecarTLS(pod), nil
+}
+
+// defaultTolerationKeys contains the keys of the tolerations that are added to every Pod by the
+// DefaultTolerationSeconds admission plugin of Kubernetes.
+var defaultTolerationKeys = map[string]fdbv1beta2.None{
+	corev1.TaintNodeNotReady:    {},
+	corev1.TaintNodeUnreachable: {},
+}
+
+// IsSchedulingOnlyUpdate returns true if the Pod only differs from its desired spec in the scheduling constraints,
+// which are the node selector, the tolerations and the affinity.
+func IsSchedulingOnlyUpdate(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, id int, pod *corev1.Pod) (bool, error) {
+	spec, err := GetPodSpec(cluster, processClass, id)
+	if err != nil {
+		return false, err
+	}
+
+	specHash, err := GetPodSpecHash(cluster, processClass, id, spec)
+	if err != nil {
+		return false, err
+	}
+
+	lastSpecHash := pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]
+	if lastSpecHash == specHash {
+		return false, nil
+	}
+
+	// Use the current scheduling constraints in the desired spec, if the hash matches the last applied spec, the
+	// scheduling constraints are the only difference.
+	spec.NodeSelector = pod.Spec.NodeSelector
+	spec.Affinity = pod.Spec.Affinity.DeepCopy()
+	spec.Tolerations = getAppliedTolerations(spec.Tolerations, pod.Spec.Tolerations)
+
+	currentSpecHash, err := GetPodSpecHash(cluster, processClass, id, spec)
+	if err != nil {
+		return false, err
+	}
+
+	return lastSpecHash == currentSpecHash, nil
+}
+
+// getAppliedTolerations returns the tolerations of the Pod without the default tolerations that were added by
+// Kubernetes and that are not part of the desired tolerations.
+func getAppliedTolerations(desired []corev1.Toleration, current []corev1.Toleration) []corev1.Toleration {
+	desiredKeys := make(map[string]fdbv1beta2.None, len(desired))
+	for _, toleration := range desired {
+		desiredKeys[toleration.Key] = fdbv1beta2.None{}
+	}
+
+	var applied []corev1.Toleration
+	for _, toleration := range current {
+		_, isDefault := defaultTolerationKeys[toleration.Key]
+		_, isDesired := desiredKeys[toleration.Key]
+		if isDefault && !isDesired {
+			continue
+		}
+
+		applied = append(applied, toleration)
+	}
+
+	return applied
+}
+
+// GetContainer returns the container with the provided name or nil if no container with that name exists.
+func GetContainer(containers []corev1.Container, name string) *corev1.Container {
+	for idx, container := range containers {
+		if container.Name == name {
+			return &containers[idx]
+		}
+	}
+
+	return nil
+}
+
+// GetAdditionalContainersNotReady returns the names of the additional containers of the Pod that are not ready.
+func GetAdditionalContainersNotReady(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) []string {
+	processSettings := cluster.GetProcessSettings(GetProcessClassFromMeta(cluster, pod.ObjectMeta))
+	if len(processSettings.AdditionalContainers) == 0 {
+		return nil
+	}
+
+	readyContainers := make(map[string]bool, len(pod.Status.ContainerStatuses))
+	for _, status := range pod.Status.ContainerStatuses {
+		readyContainers[status.Name] = status.Ready
+	}
+
+	var notReady []string
+	for _, container := range processSettings.AdditionalContainers {
+		// Additional containers are only added to new Pods, so older Pods might not have this container.
+		if GetContainer(pod.Spec.Containers, container.Name) == nil {
+			continue
+		}
+
+		if !readyContainers[container.Name] {
+			notReady = append(notReady, container.Name)
+		}
+	}
+
+	return notReady
+}
+
 // GetJSONHash serializes an object to JSON and takes a hash of the resulting
 // JSON.
 func GetJSONHash(object interface{}) (string, error) {
@@ -194,9 +363,9 @@ func GetPodListOptions(cluster *fdbv1beta2.FoundationDBCluster, processClass fdb
 func GetPvcMetadata(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, id fdbv1beta2.ProcessGroupID) metav1.ObjectMeta {
 	var customMetadata *metav1.ObjectMeta
 
-	processSettings := cluster.GetProcessSettings(processClass)
-	if processSettings.VolumeClaimTemplate != nil {
-		customMetadata = &processSettings.VolumeClaimTemplate.ObjectMeta
+	volumeClaimTemplate := cluster.GetVolumeClaimTemplate(processClass, id)
+	if volumeClaimTemplate != nil {
+		customMetadata = &volumeClaimTemplate.ObjectMeta
 	} else {
 		customMetadata = nil
 	}
@@ -223,7 +392,7 @@ func GetSidecarImage(cluster *fdbv1beta2.FoundationDBCluster, pClass fdbv1beta2.
 		imageConfigs = cluster.Spec.SidecarContainer.ImageConfigs
 	}
 
-	return GetImage(image, imageConfigs, cluster.Spec.Version, false)
+	return GetImageForArchitecture(image, imageConfigs, cluster.Spec.Version, cluster.GetProcessArchitecture(pClass), false)
 }
 
 // CreatePodMap creates a map with the process group ID as a key and the according Pod as a value
@@ -266,3 +435,19 @@ func GetPublicIPSource(pod *corev1.Pod) (fdbv1beta2.PublicIPSource, error) {
 	}
 	return fdbv1beta2.PublicIPSource(source), nil
 }
+
+// GetPublicIPs returns the public IPs of a Pod based on the public IP source of the Pod.
+func GetPublicIPs(pod *corev1.Pod, log logr.Logger) []string {
+	if pod == nil {
+		return []string{}
+	}
+
+	switch fdbv1beta2.PublicIPSource(pod.ObjectMeta.Annotations[fdbv1beta2.PublicIPSourceAnnotation]) {
+	case "", fdbv1beta2.PublicIPSourcePod:
+		return GetPublicIPsForPod(pod, log)
+	case fdbv1beta2.PublicIPSourceNode:
+		return []string{pod.Status.HostIP}
+	default:
+		return []string{pod.ObjectMeta.Annotations[fdbv1beta2.PublicIPAnnotation]}
+	}
+}
@@ -21,12 +21,16 @@
 package internal
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -37,6 +41,12 @@ import (
 
 var processClassSanitizationPattern = regexp.MustCompile("[^a-z0-9-]")
 
+// TraceEventReceiverPath defines the path on the metrics server of the operator that receives trace events.
+const TraceEventReceiverPath = "/trace-events"
+
+// TraceEventReceiverTokenKey defines the key of the token in the trace event receiver secret of a cluster.
+const TraceEventReceiverTokenKey = "token"
+
 // GetProcessGroupIDFromPodName returns the process group ID for a given Pod name.
 func GetProcessGroupIDFromPodName(cluster *fdbv1beta2.FoundationDBCluster, podName string) fdbv1beta2.ProcessGroupID {
 	tmpName := strings.ReplaceAll(podName, cluster.Name, "")[1:]
@@ -61,7 +71,7 @@ func GetProcessGroupID(cluster *fdbv1beta2.FoundationDBCluster, processClass fdb
 	return fmt.Sprintf("%s-%s-%d", cluster.Name, processClassSanitizationPattern.ReplaceAllString(string(processClass), "-"), idNum), processGroupID
 }
 
-func generateServicePorts(processesPerPod int) []corev1.ServicePort {
+func generateServicePorts(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, processesPerPod int) []corev1.ServicePort {
 	ports := make([]corev1.ServicePort, 0, processesPerPod*2)
 
 	for i := 1; i <= processesPerPod; i++ {
@@ -77,10 +87,10 @@ func generateServicePorts(processesPerPod int) []corev1.ServicePort {
 
 		ports = append(ports, corev1.ServicePort{
 			Name: tlsPortName,
-			Port: int32(fdbv1beta2.GetProcessPort(i, true)),
+			Port: int32(cluster.GetProcessPortForProcessClass(processClass, i, true)),
 		}, corev1.ServicePort{
 			Name: nonTlSPortName,
-			Port: int32(fdbv1beta2.GetProcessPort(i, false)),
+			Port: int32(cluster.GetProcessPortForProcessClass(processClass, i, false)),
 		})
 	}
 
@@ -101,11 +111,16 @@ func GetService(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 		processesPerPod = cluster.GetStorageServersPerPod()
 	}
 
+	serviceType := corev1.ServiceTypeClusterIP
+	if cluster.GetPublicIPSource() == fdbv1beta2.PublicIPSourceLoadBalancer {
+		serviceType = corev1.ServiceTypeLoadBalancer
+	}
+
 	return &corev1.Service{
 		ObjectMeta: metadata,
 		Spec: corev1.ServiceSpec{
-			Type:                     corev1.ServiceTypeClusterIP,
-			Ports:                    generateServicePorts(processesPerPod),
+			Type:                     serviceType,
+			Ports:                    generateServicePorts(cluster, processClass, processesPerPod),
 			PublishNotReadyAddresses: true,
 			Selector:                 GetPodMatchLabels(cluster, "", string(id)),
 		},
@@ -131,6 +146,17 @@ func GetPod(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.Pro
 	metadata.Name = name
 	metadata.OwnerReferences = owner
 
+	// The additional containers are not part of the spec hash, so changes to those containers don't require an
+	// update of the Pod.
+	processSettings := cluster.GetProcessSettings(processClass)
+	for _, container := range processSettings.AdditionalInitContainers {
+		spec.InitContainers = append(spec.InitContainers, *container.DeepCopy())
+	}
+
+	for _, container := range processSettings.AdditionalContainers {
+		spec.Containers = append(spec.Containers, *container.DeepCopy())
+	}
+
 	return &corev1.Pod{
 		ObjectMeta: metadata,
 		Spec:       *spec,
@@ -139,6 +165,12 @@ func GetPod(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.Pro
 
 // GetImage returns the image for container
 func GetImage(image string, configs []fdbv1beta2.ImageConfig, versionString string, allowTagOverride bool) (string, error) {
+	return GetImageForArchitecture(image, configs, versionString, "", allowTagOverride)
+}
+
+// GetImageForArchitecture returns the image for container on nodes with the provided CPU architecture. If the
+// architecture is empty, only the image configs without an architecture are used.
+func GetImageForArchitecture(image string, configs []fdbv1beta2.ImageConfig, versionString string, architecture string, allowTagOverride bool) (string, error) {
 	if image != "" {
 		imageComponents := strings.Split(image, ":")
 		if len(imageComponents) > 1 {
@@ -151,7 +183,7 @@ func GetImage(image string, configs []fdbv1beta2.ImageConfig, versionString stri
 		configs = append([]fdbv1beta2.ImageConfig{{BaseImage: image}}, configs...)
 	}
 
-	return fdbv1beta2.SelectImageConfig(configs, versionString).Image(), nil
+	return fdbv1beta2.SelectImageConfigForArchitecture(configs, versionString, architecture).Image(), nil
 }
 
 // getInitContainer returns the init container based on the provided PodSpec. If useUnifiedImages is true the init container
@@ -223,7 +255,7 @@ func configureContainersForUnifiedImages(cluster *fdbv1beta2.FoundationDBCluster
 	}})
 
 	// Configure sidecar
-	sidecarImage, err := GetImage(sidecarContainer.Image, cluster.Spec.MainContainer.ImageConfigs, cluster.GetRunningVersion(), false)
+	sidecarImage, err := GetImageForArchitecture(sidecarContainer.Image, cluster.Spec.MainContainer.ImageConfigs, cluster.GetRunningVersion(), cluster.GetProcessArchitecture(processClass), false)
 	if err != nil {
 		return err
 	}
@@ -306,6 +338,111 @@ func setAffinityForFaultDomain(cluster *fdbv1beta2.FoundationDBCluster, podSpec
 	}
 }
 
+// configureNodeSelectorForVolumeClaimTemplate pins the Pod to the nodes of the fault domain or node pool that
+// matches the volume claim template of the process group.
+func configureNodeSelectorForVolumeClaimTemplate(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, processClass fdbv1beta2.ProcessClass, processGroupID fdbv1beta2.ProcessGroupID) {
+	selector := cluster.GetVolumeClaimTemplateSelector(processClass, processGroupID)
+	if selector == nil {
+		return
+	}
+
+	if podSpec.NodeSelector == nil {
+		podSpec.NodeSelector = make(map[string]string, len(selector.NodeSelector))
+	}
+
+	for key, value := range selector.NodeSelector {
+		podSpec.NodeSelector[key] = value
+	}
+}
+
+// configureNodeSelectorForArchitecture restricts the Pod to the nodes with the CPU architecture of the process class,
+// as the images for this architecture can't run on nodes with a different architecture.
+func configureNodeSelectorForArchitecture(podSpec *corev1.PodSpec, architecture string) {
+	if architecture == "" {
+		return
+	}
+
+	if podSpec.NodeSelector == nil {
+		podSpec.NodeSelector = map[string]string{}
+	}
+
+	podSpec.NodeSelector[corev1.LabelArchStable] = architecture
+}
+
+// getArchitectureFromImageConfigs returns the CPU architecture that is defined in the image configs of a workload
+// whose Pods all use the same images. If the image configs define images for multiple architectures, an error is
+// returned, as the operator can't choose the images for the Pods.
+func getArchitectureFromImageConfigs(kind string, name string, configLists ...[]fdbv1beta2.ImageConfig) (string, error) {
+	architectures := fdbv1beta2.GetImageConfigArchitectures(configLists...)
+	if len(architectures) > 1 {
+		return "", fmt.Errorf("%s %s defines image configs for the architectures %s, but all of its Pods must use the same images", kind, name, strings.Join(architectures, ", "))
+	}
+
+	if len(architectures) == 1 {
+		return architectures[0], nil
+	}
+
+	return "", nil
+}
+
+// configurePinnedProcessGroup restricts the Pod of a pinned process group to the node or the zone of the pin.
+func configurePinnedProcessGroup(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, processGroupID fdbv1beta2.ProcessGroupID) {
+	pin := cluster.GetProcessGroupPin(processGroupID)
+	if pin == nil {
+		return
+	}
+
+	if pin.TolerateUnschedulable {
+		podSpec.Tolerations = append(podSpec.Tolerations, corev1.Toleration{
+			Key:      corev1.TaintNodeUnschedulable,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+
+	if pin.Zone != "" {
+		faultDomainKey := cluster.Spec.FaultDomain.Key
+		if faultDomainKey == "" {
+			faultDomainKey = corev1.LabelHostname
+		}
+
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		podSpec.NodeSelector[faultDomainKey] = pin.Zone
+		return
+	}
+
+	// The node name is matched with a field selector, as the hostname label of a node doesn't have to match the
+	// name of the node. The requirement is added to every existing term, since the terms are ORed.
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      "metadata.name",
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{pin.NodeName},
+	}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+
+	if podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+
+	nodeSelector := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+
+	for idx := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[idx].MatchFields = append(nodeSelector.NodeSelectorTerms[idx].MatchFields, requirement)
+	}
+}
+
 func configureVolumesForContainers(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, volumeClaimTemplate *corev1.PersistentVolumeClaim, podName string, processClass fdbv1beta2.ProcessClass) {
 	useUnifiedImages := pointer.BoolDeref(cluster.Spec.UseUnifiedImage, false)
 	monitorConfKey := GetConfigMapMonitorConfEntry(processClass, GetDesiredImageType(cluster), cluster.GetStorageServersPerPod())
@@ -334,7 +471,7 @@ func configureVolumesForContainers(cluster *fdbv1beta2.FoundationDBCluster, podS
 	}
 
 	var mainVolumeSource corev1.VolumeSource
-	if usePvc(cluster, processClass) {
+	if usePvc(processClass, volumeClaimTemplate) {
 		var volumeClaimSourceName string
 		if volumeClaimTemplate != nil && volumeClaimTemplate.Name != "" {
 			volumeClaimSourceName = fmt.Sprintf("%s-%s", podName, volumeClaimTemplate.Name)
@@ -420,7 +557,7 @@ func GetPodSpec(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 		mainVersion = cluster.Spec.Version
 	}
 
-	image, err := GetImage(mainContainer.Image, cluster.Spec.MainContainer.ImageConfigs, mainVersion, false)
+	image, err := GetImageForArchitecture(mainContainer.Image, cluster.Spec.MainContainer.ImageConfigs, mainVersion, cluster.GetProcessArchitecture(processClass), false)
 	if err != nil {
 		return nil, err
 	}
@@ -466,12 +603,12 @@ func GetPodSpec(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 			corev1.VolumeMount{Name: "fdb-trace-logs", MountPath: "/var/log/fdb-trace-logs"},
 		)
 
-		err = configureSidecarContainerForCluster(cluster, podName, initContainer, true, processGroupID)
+		err = configureSidecarContainerForCluster(cluster, processClass, podName, initContainer, true, processGroupID)
 		if err != nil {
 			return nil, err
 		}
 
-		err = configureSidecarContainerForCluster(cluster, podName, sidecarContainer, false, processGroupID)
+		err = configureSidecarContainerForCluster(cluster, processClass, podName, sidecarContainer, false, processGroupID)
 		if err != nil {
 			return nil, err
 		}
@@ -481,10 +618,26 @@ func GetPodSpec(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 		}
 	}
 
+	traceLogDirectory := cluster.GetTraceLogDirectory()
+	if traceLogDirectory != fdbv1beta2.DefaultTraceLogDirectory {
+		mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, corev1.VolumeMount{Name: "fdb-trace-logs", MountPath: traceLogDirectory})
+	}
+
+	configureProcessEnvironment(cluster, mainContainer, processSettings, processClass)
+	configureProcessHealthProbes(cluster, mainContainer, processSettings.HealthProbes, processClass, useUnifiedImages)
+	configureCrashCollection(cluster, podSpec, mainContainer, podName, useUnifiedImages)
+	configureAuthorization(cluster, podSpec, mainContainer)
+	configureCertManager(cluster, podSpec, processGroupID, mainContainer, sidecarContainer)
+	configureTLSCertificateRotation(cluster, podSpec, mainContainer)
+	configureHostPorts(cluster, mainContainer, processClass)
+	configureHostNetwork(cluster, podSpec, sidecarContainer, processClass)
 	ensureSecurityContextIsPresent(mainContainer)
 	ensureSecurityContextIsPresent(sidecarContainer)
 	setAffinityForFaultDomain(cluster, podSpec, processClass)
-	configureVolumesForContainers(cluster, podSpec, processSettings.VolumeClaimTemplate, podName, processClass)
+	configureNodeSelectorForVolumeClaimTemplate(cluster, podSpec, processClass, processGroupID)
+	configurePinnedProcessGroup(cluster, podSpec, processGroupID)
+	configureNodeSelectorForArchitecture(podSpec, cluster.GetProcessArchitecture(processClass))
+	configureVolumesForContainers(cluster, podSpec, cluster.GetVolumeClaimTemplate(processClass, processGroupID), podName, processClass)
 	configureNoSchedule(podSpec, processGroupID, cluster.Spec.Buggify.NoSchedule)
 
 	if !useUnifiedImages {
@@ -492,30 +645,522 @@ func GetPodSpec(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 	}
 	replaceContainers(podSpec.Containers, mainContainer, sidecarContainer)
 
-	headlessService := GetHeadlessService(cluster)
+	err = configureProcessContainers(cluster, podSpec, processClass, podName)
+	if err != nil {
+		return nil, err
+	}
 
-	if headlessService != nil {
-		podSpec.Hostname = podName
-		podSpec.Subdomain = headlessService.Name
+	configureTraceLogForwarder(cluster, podSpec)
+
+	configurePodDNS(cluster, podSpec, processSettings.DNS, processClass, podName)
+	configureCompatibilityMode(cluster, podSpec)
+
+	if processSettings.PriorityClassName != "" {
+		podSpec.PriorityClassName = processSettings.PriorityClassName
 	}
 
 	return podSpec, nil
 }
 
+// configureProcessEnvironment adds the environment variables of the process settings to the main container. The hash
+// of the referenced Secrets and ConfigMaps is added as well, so that a change of their data updates the Pods.
+func configureProcessEnvironment(cluster *fdbv1beta2.FoundationDBCluster, mainContainer *corev1.Container, processSettings fdbv1beta2.ProcessSettings, processClass fdbv1beta2.ProcessClass) {
+	extendEnv(mainContainer, processSettings.Env...)
+	mainContainer.EnvFrom = append(mainContainer.EnvFrom, processSettings.EnvFrom...)
+
+	hash, ok := cluster.Status.ProcessEnvironmentHashes[processClass]
+	if !ok {
+		return
+	}
+
+	extendEnv(mainContainer, corev1.EnvVar{Name: ProcessEnvironmentHashVariable, Value: hash})
+}
+
+// configureCompatibilityMode adjusts the Pod spec to the compatibility mode of the cluster. In the openshift mode the
+// UIDs and GIDs are removed from the security contexts, so that the security context constraints can assign a random
+// UID, and all containers get the settings that the restricted security context constraints require.
+func configureCompatibilityMode(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec) {
+	if cluster.GetCompatibilityMode() != fdbv1beta2.CompatibilityModeOpenShift {
+		return
+	}
+
+	if podSpec.SecurityContext == nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	podSpec.SecurityContext.RunAsUser = nil
+	podSpec.SecurityContext.RunAsGroup = nil
+	podSpec.SecurityContext.FSGroup = nil
+	// The fsGroup is assigned by the security context constraints, changing the ownership of the volumes only if the
+	// root directory doesn't match prevents a recursive chown of the data volume for every start of the Pod.
+	if podSpec.SecurityContext.FSGroupChangePolicy == nil {
+		policy := corev1.FSGroupChangeOnRootMismatch
+		podSpec.SecurityContext.FSGroupChangePolicy = &policy
+	}
+
+	for idx := range podSpec.InitContainers {
+		configureContainerForOpenShift(&podSpec.InitContainers[idx])
+	}
+
+	for idx := range podSpec.Containers {
+		configureContainerForOpenShift(&podSpec.Containers[idx])
+	}
+}
+
+// configureContainerForOpenShift removes the UID and GID from the security context of the container and sets the
+// defaults that the restricted security context constraints require.
+func configureContainerForOpenShift(container *corev1.Container) {
+	ensureSecurityContextIsPresent(container)
+
+	container.SecurityContext.RunAsUser = nil
+	container.SecurityContext.RunAsGroup = nil
+
+	if container.SecurityContext.RunAsNonRoot == nil {
+		container.SecurityContext.RunAsNonRoot = pointer.Bool(true)
+	}
+
+	if container.SecurityContext.AllowPrivilegeEscalation == nil {
+		container.SecurityContext.AllowPrivilegeEscalation = pointer.Bool(false)
+	}
+
+	if container.SecurityContext.Capabilities == nil {
+		container.SecurityContext.Capabilities = &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		}
+	}
+}
+
+// configurePodDNS sets the hostname, the subdomain and the DNS settings of the Pod spec.
+func configurePodDNS(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, dnsSettings *fdbv1beta2.PodDNSSettings, processClass fdbv1beta2.ProcessClass, podName string) {
+	podSpec.Subdomain = GetPodSubdomain(cluster, processClass)
+	if dnsSettings == nil {
+		if podSpec.Subdomain != "" {
+			podSpec.Hostname = podName
+		}
+
+		return
+	}
+
+	if pointer.BoolDeref(dnsSettings.SetHostname, podSpec.Subdomain != "") {
+		podSpec.Hostname = podName
+	}
+
+	if dnsSettings.Policy != "" {
+		podSpec.DNSPolicy = dnsSettings.Policy
+	}
+
+	if dnsSettings.Ndots == nil && len(dnsSettings.Searches) == 0 {
+		return
+	}
+
+	if podSpec.DNSConfig == nil {
+		podSpec.DNSConfig = &corev1.PodDNSConfig{}
+	}
+
+	searches := make(map[string]fdbv1beta2.None, len(podSpec.DNSConfig.Searches))
+	for _, search := range podSpec.DNSConfig.Searches {
+		searches[search] = fdbv1beta2.None{}
+	}
+
+	for _, search := range dnsSettings.Searches {
+		if _, ok := searches[search]; ok {
+			continue
+		}
+
+		podSpec.DNSConfig.Searches = append(podSpec.DNSConfig.Searches, search)
+		searches[search] = fdbv1beta2.None{}
+	}
+
+	if dnsSettings.Ndots == nil {
+		return
+	}
+
+	ndots := strconv.Itoa(*dnsSettings.Ndots)
+	for idx, option := range podSpec.DNSConfig.Options {
+		if option.Name == "ndots" {
+			podSpec.DNSConfig.Options[idx].Value = &ndots
+			return
+		}
+	}
+
+	podSpec.DNSConfig.Options = append(podSpec.DNSConfig.Options, corev1.PodDNSConfigOption{Name: "ndots", Value: &ndots})
+}
+
+// configureProcessHealthProbes adds the liveness and readiness probes that check the health of the fdbserver
+// processes to the main container. Probes that are already defined in the Pod template will not be changed.
+func configureProcessHealthProbes(cluster *fdbv1beta2.FoundationDBCluster, mainContainer *corev1.Container, healthProbes *fdbv1beta2.ProcessHealthProbes, processClass fdbv1beta2.ProcessClass, useUnifiedImages bool) {
+	if healthProbes == nil {
+		return
+	}
+
+	var handler corev1.ProbeHandler
+	if cluster.UsesProcessPerContainerLayout() {
+		// Without a monitor process the probes check if the fdbserver process accepts connections. The port will be
+		// adjusted for the containers of the other processes.
+		handler.TCPSocket = &corev1.TCPSocketAction{
+			Port: intstr.FromInt(getProcessPort(cluster, processClass, 1)),
+		}
+	} else if useUnifiedImages {
+		handler.HTTPGet = &corev1.HTTPGetAction{
+			Path: "/health",
+			Port: intstr.FromInt(8081),
+		}
+	} else {
+		// fdbmonitor restarts fdbserver processes that have exited, so a missing fdbserver process
+		// indicates that fdbmonitor is not working as expected. The expected number of processes is read from the
+		// monitor conf that fdbmonitor uses, so the probe doesn't fail when the conf defines no processes, e.g. with
+		// the EmptyMonitorConf buggify option, or before the sidecar has copied the updated conf.
+		handler.Exec = &corev1.ExecAction{
+			Command: []string{
+				"sh", "-c",
+				"pgrep -x fdbmonitor > /dev/null && [ \"$(pgrep -x fdbserver | wc -l)\" -ge \"$(cat /var/dynamic-conf/fdbmonitor.conf 2>/dev/null | grep -c '^\\[fdbserver\\.')\" ]",
+			},
+		}
+	}
+
+	newProbe := func() *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler:     *handler.DeepCopy(),
+			PeriodSeconds:    pointer.Int32Deref(healthProbes.PeriodSeconds, 30),
+			TimeoutSeconds:   pointer.Int32Deref(healthProbes.TimeoutSeconds, 5),
+			FailureThreshold: pointer.Int32Deref(healthProbes.FailureThreshold, 5),
+		}
+	}
+
+	if pointer.BoolDeref(healthProbes.EnableLivenessProbe, false) && mainContainer.LivenessProbe == nil {
+		mainContainer.LivenessProbe = newProbe()
+	}
+
+	if pointer.BoolDeref(healthProbes.EnableReadinessProbe, false) && mainContainer.ReadinessProbe == nil {
+		mainContainer.ReadinessProbe = newProbe()
+	}
+}
+
+// configureProcessContainers configures the main container to run the first fdbserver process without a monitor
+// process and adds a copy of the main container for every additional fdbserver process of the Pod, if the cluster uses
+// the ProcessPerContainer layout. The resources, volume mounts and environment of the main container apply to every
+// process container.
+func configureProcessContainers(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, processClass fdbv1beta2.ProcessClass, podName string) error {
+	if !cluster.UsesProcessPerContainerLayout() {
+		return nil
+	}
+
+	mainIndex := -1
+	for index, container := range podSpec.Containers {
+		if container.Name == fdbv1beta2.MainContainerName {
+			mainIndex = index
+			break
+		}
+	}
+
+	if mainIndex < 0 {
+		return fmt.Errorf("could not find main container")
+	}
+
+	processCount := 1
+	if processClass == fdbv1beta2.ProcessClassStorage {
+		processCount = cluster.GetStorageServersPerPod()
+	}
+
+	config, err := GetMonitorProcessConfiguration(cluster, processClass, processCount, FDBImageTypeUnified, nil)
+	if err != nil {
+		return err
+	}
+
+	mainContainer := podSpec.Containers[mainIndex].DeepCopy()
+	extendEnv(mainContainer, corev1.EnvVar{Name: PodLayoutEnvironmentVariable, Value: string(fdbv1beta2.PodLayoutProcessPerContainer)})
+	extendEnv(mainContainer, getProcessContainerEnv(cluster, config, mainContainer, processClass, podName)...)
+	// The resources of the main container are meant for the whole Pod, so every process gets its share of them.
+	mainContainer.Resources.Requests = splitResources(mainContainer.Resources.Requests, processCount)
+	mainContainer.Resources.Limits = splitResources(mainContainer.Resources.Limits, processCount)
+	crashLoop := len(mainContainer.Command) > 0 && mainContainer.Command[0] == "crash-loop"
+	basePort := getProcessPort(cluster, processClass, 1)
+
+	processContainers := make([]corev1.Container, 0, processCount)
+	for processNumber := 1; processNumber <= processCount; processNumber++ {
+		container := mainContainer.DeepCopy()
+		container.Name = fdbv1beta2.GetProcessContainerName(processNumber)
+
+		if !crashLoop {
+			arguments, err := GetProcessContainerArguments(config, processNumber)
+			if err != nil {
+				return err
+			}
+
+			container.Command = arguments[:1]
+			container.Args = arguments[1:]
+		}
+
+		port := getProcessPort(cluster, processClass, processNumber)
+		for _, probe := range []*corev1.Probe{container.LivenessProbe, container.ReadinessProbe} {
+			if probe != nil && probe.TCPSocket != nil && probe.TCPSocket.Port.IntValue() == basePort {
+				probe.TCPSocket.Port = intstr.FromInt(port)
+			}
+		}
+
+		processContainers = append(processContainers, *container)
+	}
+
+	containers := make([]corev1.Container, 0, len(podSpec.Containers)+processCount-1)
+	containers = append(containers, podSpec.Containers[:mainIndex]...)
+	containers = append(containers, processContainers...)
+	containers = append(containers, podSpec.Containers[mainIndex+1:]...)
+	podSpec.Containers = containers
+
+	return nil
+}
+
+// getProcessContainerEnv returns the environment variables that the arguments of the process containers reference, but
+// that are not defined in the main container. In the other layouts those variables are provided by the sidecar or the
+// monitor, e.g. the FDB_DNS_NAME variable.
+func getProcessContainerEnv(cluster *fdbv1beta2.FoundationDBCluster, config monitorapi.ProcessConfiguration, mainContainer *corev1.Container, processClass fdbv1beta2.ProcessClass, podName string) []corev1.EnvVar {
+	referenced := map[string]string{}
+	extractPlaceholderEnvVars(referenced, config.Arguments)
+
+	for _, env := range mainContainer.Env {
+		delete(referenced, env.Name)
+	}
+
+	var env []corev1.EnvVar
+	if _, ok := referenced["FDB_DNS_NAME"]; ok {
+		env = append(env, corev1.EnvVar{Name: "FDB_DNS_NAME", Value: GetPodDNSName(cluster, processClass, podName)})
+	}
+
+	return env
+}
+
+// splitResources returns the resources divided by the provided count. CPU resources are divided in millicores, all
+// other resources in their base unit.
+func splitResources(resources corev1.ResourceList, count int) corev1.ResourceList {
+	if resources == nil || count <= 1 {
+		return resources
+	}
+
+	result := make(corev1.ResourceList, len(resources))
+	for name, quantity := range resources {
+		if name == corev1.ResourceCPU {
+			result[name] = *resource.NewMilliQuantity(quantity.MilliValue()/int64(count), quantity.Format)
+			continue
+		}
+
+		result[name] = *resource.NewQuantity(quantity.Value()/int64(count), quantity.Format)
+	}
+
+	return result
+}
+
+// getProcessPort returns the port the fdbserver process with the provided process number listens on.
+func getProcessPort(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, processNumber int) int {
+	addresses := cluster.GetFullAddressListForProcessClass("FDB_PUBLIC_IP", false, processNumber, processClass)
+	if len(addresses) == 0 {
+		return 0
+	}
+
+	return addresses[0].Port
+}
+
+// configureHostPorts exposes the ports of the fdbserver processes on the node, if the node IP is used as public IP
+// or if the Pod uses the host network. Declaring the host ports allows the scheduler to detect port conflicts, so
+// only a single Pod of a process class can run on a node, as those Pods use the same ports.
+func configureHostPorts(cluster *fdbv1beta2.FoundationDBCluster, mainContainer *corev1.Container, processClass fdbv1beta2.ProcessClass) {
+	if cluster.GetPublicIPSource() != fdbv1beta2.PublicIPSourceNode && !cluster.UseHostNetwork(processClass) {
+		return
+	}
+
+	processesPerPod := 1
+	if processClass == fdbv1beta2.ProcessClassStorage {
+		processesPerPod = cluster.GetStorageServersPerPod()
+	}
+
+	for _, port := range generateServicePorts(cluster, processClass, processesPerPod) {
+		exists := false
+		for _, containerPort := range mainContainer.Ports {
+			if containerPort.ContainerPort == port.Port {
+				exists = true
+				break
+			}
+		}
+
+		if exists {
+			continue
+		}
+
+		mainContainer.Ports = append(mainContainer.Ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.Port,
+			HostPort:      port.Port,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+}
+
+// configureHostNetwork configures the Pod to use the host network, if enabled for the process class. The DNS
+// policy will be set to ClusterFirstWithHostNet, unless the Pod template defines a DNS policy, and the port of the
+// sidecar will be exposed on the node.
+func configureHostNetwork(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, sidecarContainer *corev1.Container, processClass fdbv1beta2.ProcessClass) {
+	if !cluster.UseHostNetwork(processClass) {
+		return
+	}
+
+	podSpec.HostNetwork = true
+	if podSpec.DNSPolicy == "" {
+		podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	}
+
+	sidecarPort := int32(cluster.GetSidecarPort(processClass))
+	for _, containerPort := range sidecarContainer.Ports {
+		if containerPort.ContainerPort == sidecarPort {
+			return
+		}
+	}
+
+	sidecarContainer.Ports = append(sidecarContainer.Ports, corev1.ContainerPort{
+		Name:          "sidecar",
+		ContainerPort: sidecarPort,
+		HostPort:      sidecarPort,
+		Protocol:      corev1.ProtocolTCP,
+	})
+}
+
+// configureCrashCollection mounts the crash collection volume into the main container and uses a subdirectory
+// for the Pod as working directory, so that core files of the processes are written to this volume. With the split
+// image the main container runs fdbmonitor in a shell, so the shell enables core dumps and changes into the directory
+// from FDB_CRASH_DIR before starting fdbmonitor, in case the Pod template defines a different working directory.
+func configureCrashCollection(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, mainContainer *corev1.Container, podName string, useUnifiedImages bool) {
+	if cluster.Spec.CrashCollection == nil || cluster.Spec.CrashCollection.VolumeClaimName == "" {
+		return
+	}
+
+	crashDirectory := "/var/fdb/crash-dumps"
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "fdb-crash-dumps",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: cluster.Spec.CrashCollection.VolumeClaimName,
+			},
+		},
+	})
+	mainContainer.VolumeMounts = append(mainContainer.VolumeMounts, corev1.VolumeMount{Name: "fdb-crash-dumps", MountPath: crashDirectory, SubPath: podName})
+	extendEnv(mainContainer, corev1.EnvVar{Name: "FDB_CRASH_DIR", Value: crashDirectory})
+
+	if mainContainer.WorkingDir == "" {
+		mainContainer.WorkingDir = crashDirectory
+	}
+
+	if useUnifiedImages || len(mainContainer.Args) != 1 || mainContainer.Args[0] == "crash-loop" {
+		return
+	}
+
+	mainContainer.Args[0] = "ulimit -c unlimited 2>/dev/null; cd \"$FDB_CRASH_DIR\" && " + mainContainer.Args[0]
+}
+
+// GetCrashCollectionLocation returns the location of the crash artifacts of the provided Pod or an empty string
+// if the crash collection has no volume.
+func GetCrashCollectionLocation(cluster *fdbv1beta2.FoundationDBCluster, podName string) string {
+	if cluster.Spec.CrashCollection == nil || cluster.Spec.CrashCollection.VolumeClaimName == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:/%s", cluster.Spec.CrashCollection.VolumeClaimName, podName)
+}
+
+// GetPodSubdomain returns the subdomain of the Pods of the process class. If the DNS settings of the process class
+// define no subdomain, the name of the headless service is used if the cluster has a headless service.
+func GetPodSubdomain(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass) string {
+	dnsSettings := cluster.GetProcessSettings(processClass).DNS
+	if dnsSettings != nil && dnsSettings.Subdomain != nil {
+		return *dnsSettings.Subdomain
+	}
+
+	headlessService := GetHeadlessService(cluster)
+	if headlessService == nil {
+		return ""
+	}
+
+	return headlessService.Name
+}
+
+// configureTraceLogForwarder adds the trace log forwarder container to the Pod spec, if the cluster defines one.
+func configureTraceLogForwarder(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec) {
+	if cluster.Spec.TraceLogs == nil || cluster.Spec.TraceLogs.Forwarder == nil {
+		return
+	}
+
+	forwarder := cluster.Spec.TraceLogs.Forwarder.DeepCopy()
+	if forwarder.Name == "" {
+		forwarder.Name = fdbv1beta2.TraceLogForwarderContainerName
+	}
+
+	traceLogDirectory := cluster.GetTraceLogDirectory()
+	extendEnv(forwarder,
+		corev1.EnvVar{Name: "FDB_TRACE_LOG_DIR", Value: traceLogDirectory},
+		corev1.EnvVar{Name: "FDB_TRACE_LOG_FORMAT", Value: string(cluster.GetTraceLogFormat())},
+	)
+	if cluster.UsesTraceEventReceiver() {
+		extendEnv(forwarder,
+			corev1.EnvVar{Name: "FDB_TRACE_EVENT_RECEIVER_URL", Value: GetTraceEventReceiverURL(cluster)},
+			corev1.EnvVar{Name: "FDB_TRACE_EVENT_RECEIVER_TOKEN", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: cluster.GetTraceEventReceiverSecretName()},
+					Key:                  TraceEventReceiverTokenKey,
+				},
+			}},
+		)
+	}
+	forwarder.VolumeMounts = append(forwarder.VolumeMounts, corev1.VolumeMount{Name: "fdb-trace-logs", MountPath: traceLogDirectory, ReadOnly: true})
+	ensureSecurityContextIsPresent(forwarder)
+
+	podSpec.Containers = append(podSpec.Containers, *forwarder)
+}
+
+// GetTraceEventReceiverURL returns the URL of the trace event receiver of the operator for the provided cluster.
+func GetTraceEventReceiverURL(cluster *fdbv1beta2.FoundationDBCluster) string {
+	query := url.Values{}
+	query.Set("namespace", cluster.Namespace)
+	query.Set("cluster", cluster.Name)
+
+	return fmt.Sprintf("http://%s%s?%s", cluster.Spec.TraceLogs.EventReceiverAddress, TraceEventReceiverPath, query.Encode())
+}
+
+// GetTraceEventReceiverSecret builds the secret that contains the token that the trace log forwarders of the
+// cluster use to authenticate against the trace event receiver.
+func GetTraceEventReceiverSecret(cluster *fdbv1beta2.FoundationDBCluster) (*corev1.Secret, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cluster.GetTraceEventReceiverSecretName(),
+			Namespace:       cluster.Namespace,
+			Labels:          cluster.GetMatchLabels(),
+			OwnerReferences: BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta),
+		},
+		Data: map[string][]byte{
+			TraceEventReceiverTokenKey: []byte(hex.EncodeToString(token)),
+		},
+	}, nil
+}
+
 // configureSidecarContainerForCluster sets up a sidecar container for a sidecar
 // in the FDB cluster.
-func configureSidecarContainerForCluster(cluster *fdbv1beta2.FoundationDBCluster, podName string, container *corev1.Container, initMode bool, processGroupID fdbv1beta2.ProcessGroupID) error {
-	return configureSidecarContainer(container, initMode, processGroupID, podName, cluster.GetRunningVersion(), cluster, cluster.Spec.SidecarContainer.ImageConfigs, false)
+func configureSidecarContainerForCluster(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, podName string, container *corev1.Container, initMode bool, processGroupID fdbv1beta2.ProcessGroupID) error {
+	processSettings := cluster.GetProcessSettings(processClass)
+	return configureSidecarContainer(container, initMode, processGroupID, GetPodDNSName(cluster, processClass, podName), cluster.GetRunningVersion(), cluster, cluster.Spec.SidecarContainer.ImageConfigs, cluster.GetProcessArchitecture(processClass), false, cluster.GetSidecarPort(processClass), processSettings.GetParameterEnvironmentVariables())
 }
 
 // configureSidecarContainerForBackup sets up a sidecar container for the init
 // container for a backup process.
-func configureSidecarContainerForBackup(backup *fdbv1beta2.FoundationDBBackup, container *corev1.Container) error {
-	return configureSidecarContainer(container, true, "", "", backup.Spec.Version, nil, backup.Spec.SidecarContainer.ImageConfigs, pointer.BoolDeref(backup.Spec.AllowTagOverride, false))
+func configureSidecarContainerForBackup(backup *fdbv1beta2.FoundationDBBackup, container *corev1.Container, architecture string) error {
+	return configureSidecarContainer(container, true, "", "", backup.Spec.Version, nil, backup.Spec.SidecarContainer.ImageConfigs, architecture, pointer.BoolDeref(backup.Spec.AllowTagOverride, false), fdbv1beta2.DefaultSidecarPort, nil)
 }
 
-// configureSidecarContainer sets up a foundationdb-kubernetes-sidecar container.
-func configureSidecarContainer(container *corev1.Container, initMode bool, processGroupID fdbv1beta2.ProcessGroupID, podName string, versionString string, optionalCluster *fdbv1beta2.FoundationDBCluster, imageConfigs []fdbv1beta2.ImageConfig, allowTagOverride bool) error {
+// configureSidecarContainer sets up a foundationdb-kubernetes-sidecar container. The parameter variables are the
+// environment variables that are referenced in the parameters, the sidecar fills them in when copying the monitor conf.
+// The image is selected for the architecture, so the binaries that the sidecar copies can run on the node.
+func configureSidecarContainer(container *corev1.Container, initMode bool, processGroupID fdbv1beta2.ProcessGroupID, dnsName string, versionString string, optionalCluster *fdbv1beta2.FoundationDBCluster, imageConfigs []fdbv1beta2.ImageConfig, architecture string, allowTagOverride bool, sidecarPort int, parameterVariables []corev1.EnvVar) error {
 	sidecarEnv := make([]corev1.EnvVar, 0, 4)
 
 	hasTrustedCAs := optionalCluster != nil && len(optionalCluster.Spec.TrustedCAs) > 0
@@ -558,11 +1203,16 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 			sidecarArgs = append(sidecarArgs, "--substitute-variable", substitution)
 		}
 
+		for _, variable := range parameterVariables {
+			sidecarArgs = append(sidecarArgs, "--substitute-variable", variable.Name)
+			sidecarEnv = append(sidecarEnv, variable)
+		}
+
 		sidecarEnv = append(sidecarEnv, getEnvForMonitorConfigSubstitution(cluster, processGroupID)...)
 
 		if cluster.DefineDNSLocalityFields() {
 			sidecarArgs = append(sidecarArgs, "--substitute-variable", "FDB_DNS_NAME")
-			sidecarEnv = append(sidecarEnv, corev1.EnvVar{Name: "FDB_DNS_NAME", Value: GetPodDNSName(cluster, podName)})
+			sidecarEnv = append(sidecarEnv, corev1.EnvVar{Name: "FDB_DNS_NAME", Value: dnsName})
 		}
 
 		if !initMode {
@@ -572,7 +1222,7 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 				container.LivenessProbe = &corev1.Probe{
 					ProbeHandler: corev1.ProbeHandler{
 						TCPSocket: &corev1.TCPSocketAction{
-							Port: intstr.IntOrString{IntVal: 8080},
+							Port: intstr.IntOrString{IntVal: int32(sidecarPort)},
 						},
 					},
 					TimeoutSeconds:   1,
@@ -585,7 +1235,7 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 				container.ReadinessProbe = &corev1.Probe{
 					ProbeHandler: corev1.ProbeHandler{
 						TCPSocket: &corev1.TCPSocketAction{
-							Port: intstr.IntOrString{IntVal: 8080},
+							Port: intstr.IntOrString{IntVal: int32(sidecarPort)},
 						},
 					},
 				}
@@ -595,6 +1245,9 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 
 	if initMode {
 		sidecarArgs = append(sidecarArgs, "--init-mode")
+	} else if sidecarPort != fdbv1beta2.DefaultSidecarPort {
+		// The public IP is the node IP if the Pod uses the host network.
+		sidecarArgs = append(sidecarArgs, "--bind-address", fmt.Sprintf("$(FDB_PUBLIC_IP):%d", sidecarPort))
 	}
 
 	extendEnv(container, sidecarEnv...)
@@ -634,7 +1287,7 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 		corev1.VolumeMount{Name: "dynamic-conf", MountPath: "/var/output-files"},
 	)
 
-	image, err := GetImage(container.Image, overrides.ImageConfigs, versionString, allowTagOverride)
+	image, err := GetImageForArchitecture(container.Image, overrides.ImageConfigs, versionString, architecture, allowTagOverride)
 	if err != nil {
 		return err
 	}
@@ -660,12 +1313,11 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 func getEnvForMonitorConfigSubstitution(cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) []corev1.EnvVar {
 	env := make([]corev1.EnvVar, 0)
 
-	publicIPSource := cluster.Spec.Routing.PublicIPSource
-	usePublicIPFromService := publicIPSource != nil && *publicIPSource == fdbv1beta2.PublicIPSourceService
-
 	var publicIPKey string
-	if usePublicIPFromService {
+	if cluster.UsePublicIPFromService() {
 		publicIPKey = fmt.Sprintf("metadata.annotations['%s']", fdbv1beta2.PublicIPAnnotation)
+	} else if cluster.GetPublicIPSource() == fdbv1beta2.PublicIPSourceNode {
+		publicIPKey = "status.hostIP"
 	} else {
 		family := cluster.Spec.Routing.PodIPFamily
 		if family == nil {
@@ -730,12 +1382,11 @@ func getEnvForMonitorConfigSubstitution(cluster *fdbv1beta2.FoundationDBCluster,
 }
 
 // usePvc determines whether we should attach a PVC to a pod.
-func usePvc(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass) bool {
+func usePvc(processClass fdbv1beta2.ProcessClass, volumeClaimTemplate *corev1.PersistentVolumeClaim) bool {
 	var storage *resource.Quantity
-	processSettings := cluster.GetProcessSettings(processClass)
 
-	if processSettings.VolumeClaimTemplate != nil {
-		requests := processSettings.VolumeClaimTemplate.Spec.Resources.Requests
+	if volumeClaimTemplate != nil {
+		requests := volumeClaimTemplate.Spec.Resources.Requests
 		if requests != nil {
 			storageCopy := requests[corev1.ResourceStorage]
 			storage = &storageCopy
@@ -746,15 +1397,15 @@ func usePvc(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.Pro
 
 // GetPvc builds a persistent volume claim for a FoundationDB process group.
 func GetPvc(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, idNum int) (*corev1.PersistentVolumeClaim, error) {
-	if !usePvc(cluster, processClass) {
+	name, id := GetProcessGroupID(cluster, processClass, idNum)
+	volumeClaimTemplate := cluster.GetVolumeClaimTemplate(processClass, id)
+	if !usePvc(processClass, volumeClaimTemplate) {
 		return nil, nil
 	}
-	name, id := GetProcessGroupID(cluster, processClass, idNum)
 
-	processSettings := cluster.GetProcessSettings(processClass)
 	var pvc *corev1.PersistentVolumeClaim
-	if processSettings.VolumeClaimTemplate != nil {
-		pvc = processSettings.VolumeClaimTemplate.DeepCopy()
+	if volumeClaimTemplate != nil {
+		pvc = volumeClaimTemplate.DeepCopy()
 	} else {
 		pvc = &corev1.PersistentVolumeClaim{}
 	}
@@ -822,6 +1473,11 @@ func extendEnv(container *corev1.Container, env ...corev1.EnvVar) {
 
 // GetBackupDeployment builds a deployment for backup agents for a cluster.
 func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deployment, error) {
+	// The image configs for the public images are only appended, so this doesn't override the default image configs
+	// of the operator if the backup spec was already normalized.
+	backup = backup.DeepCopy()
+	NormalizeBackupSpec(backup, DeprecationOptions{})
+
 	agentCount := int32(backup.GetDesiredAgentCount())
 	if agentCount == 0 {
 		return nil, nil
@@ -881,13 +1537,12 @@ func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deploym
 		podTemplate.Spec.Containers = containers
 	}
 
-	if len(backup.Spec.MainContainer.ImageConfigs) == 0 {
-		backup.Spec.MainContainer.ImageConfigs = []fdbv1beta2.ImageConfig{
-			{BaseImage: "foundationdb/foundationdb"},
-		}
+	architecture, err := getArchitectureFromImageConfigs("backup", backup.Name, backup.Spec.MainContainer.ImageConfigs, backup.Spec.SidecarContainer.ImageConfigs)
+	if err != nil {
+		return nil, err
 	}
 
-	image, err := GetImage(mainContainer.Image, backup.Spec.MainContainer.ImageConfigs, backup.Spec.Version, pointer.BoolDeref(backup.Spec.AllowTagOverride, false))
+	image, err := GetImageForArchitecture(mainContainer.Image, backup.Spec.MainContainer.ImageConfigs, backup.Spec.Version, architecture, pointer.BoolDeref(backup.Spec.AllowTagOverride, false))
 	if err != nil {
 		return nil, err
 	}
@@ -934,10 +1589,11 @@ func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deploym
 		initContainer = &podTemplate.Spec.InitContainers[0]
 	}
 
-	err = configureSidecarContainerForBackup(backup, initContainer)
+	err = configureSidecarContainerForBackup(backup, initContainer, architecture)
 	if err != nil {
 		return nil, err
 	}
+	configureNodeSelectorForArchitecture(&podTemplate.Spec, architecture)
 
 	if podTemplate.ObjectMeta.Labels == nil {
 		podTemplate.ObjectMeta.Labels = make(map[string]string, 1)
@@ -961,6 +1617,17 @@ func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deploym
 		},
 	)
 
+	if backup.Spec.PriorityClassName != "" {
+		podTemplate.Spec.PriorityClassName = backup.Spec.PriorityClassName
+	}
+
+	if backup.Spec.BlobStoreConfiguration != nil && backup.Spec.BlobStoreConfiguration.WorkloadIdentity != nil {
+		err = configureWorkloadIdentityForBackup(backup, podTemplate, mainContainer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	deployment.Spec.Template = *podTemplate
 
 	specHash, err := GetJSONHash(deployment.Spec)
@@ -973,6 +1640,63 @@ func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deploym
 	return deployment, nil
 }
 
+// configureWorkloadIdentityForBackup sets up the service account of the backup agents and, for providers that exchange
+// a projected service account token, the token volume and the environment variables for the credential chain.
+func configureWorkloadIdentityForBackup(backup *fdbv1beta2.FoundationDBBackup, podTemplate *corev1.PodTemplateSpec, container *corev1.Container) error {
+	identity := backup.Spec.BlobStoreConfiguration.WorkloadIdentity
+	err := identity.Validate()
+	if err != nil {
+		return err
+	}
+
+	podTemplate.Spec.ServiceAccountName = backup.GetBackupAgentServiceAccountName()
+	// GKE provides the credentials through the metadata server.
+	if identity.Provider == fdbv1beta2.WorkloadIdentityProviderGCP {
+		return nil
+	}
+
+	expirationSeconds := identity.GetTokenExpirationSeconds()
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, corev1.Volume{
+		Name: fdbv1beta2.WorkloadIdentityTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{
+				{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          identity.GetAudience(),
+						ExpirationSeconds: &expirationSeconds,
+						Path:              "token",
+					},
+				},
+			},
+		}},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      fdbv1beta2.WorkloadIdentityTokenVolumeName,
+		MountPath: fdbv1beta2.WorkloadIdentityTokenPath,
+		ReadOnly:  true,
+	})
+
+	tokenFile := fdbv1beta2.WorkloadIdentityTokenPath + "/token"
+	if identity.Provider == fdbv1beta2.WorkloadIdentityProviderAWS {
+		extendEnv(container,
+			corev1.EnvVar{Name: "AWS_ROLE_ARN", Value: identity.Role},
+			corev1.EnvVar{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: tokenFile},
+		)
+
+		return nil
+	}
+
+	extendEnv(container,
+		corev1.EnvVar{Name: "AZURE_CLIENT_ID", Value: identity.Role},
+		corev1.EnvVar{Name: "AZURE_TENANT_ID", Value: identity.TenantID},
+		corev1.EnvVar{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: tokenFile},
+		corev1.EnvVar{Name: "AZURE_AUTHORITY_HOST", Value: "https://login.microsoftonline.com/"},
+	)
+	podTemplate.ObjectMeta.Labels["azure.workload.identity/use"] = "true"
+
+	return nil
+}
+
 // GetStorageServersPerPodForPod returns the value of STORAGE_SERVERS_PER_POD from the sidecar or 1
 func GetStorageServersPerPodForPod(pod *corev1.Pod) (int, error) {
 	// If not specified we will default to 1
@@ -1041,8 +1765,13 @@ func GetObjectMetadata(cluster *fdbv1beta2.FoundationDBCluster, base *metav1.Obj
 }
 
 // GetPodDNSName determines the fully qualified DNS name for a pod.
-func GetPodDNSName(cluster *fdbv1beta2.FoundationDBCluster, podName string) string {
-	return fmt.Sprintf("%s.%s.%s.svc.%s", podName, cluster.Name, cluster.Namespace, cluster.GetDNSDomain())
+func GetPodDNSName(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, podName string) string {
+	subdomain := GetPodSubdomain(cluster, processClass)
+	if subdomain == "" {
+		subdomain = cluster.Name
+	}
+
+	return fmt.Sprintf("%s.%s.%s.svc.%s", podName, subdomain, cluster.Namespace, cluster.GetDNSDomain())
 }
 
 // ContainsPod checks if the given Pod is part of the cluster or not.
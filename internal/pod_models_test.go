@@ -171,6 +171,221 @@ var _ = Describe("pod_models", func() {
 				}))
 			})
 		})
+
+		Context("with a trace log forwarder", func() {
+			BeforeEach(func() {
+				cluster.Spec.TraceLogs = &fdbv1beta2.TraceLogSpec{
+					Format:    fdbv1beta2.TraceLogFormatJSON,
+					Directory: "/var/log/fdb",
+					Forwarder: &corev1.Container{Image: "log-forwarder:1.0"},
+				}
+
+				pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should mount the trace logs at the trace log directory", func() {
+				mainContainer := GetContainer(pod.Spec.Containers, fdbv1beta2.MainContainerName)
+				Expect(mainContainer).NotTo(BeNil())
+				Expect(mainContainer.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "fdb-trace-logs", MountPath: "/var/log/fdb"}))
+			})
+
+			It("should add the forwarder container", func() {
+				forwarder := GetContainer(pod.Spec.Containers, fdbv1beta2.TraceLogForwarderContainerName)
+				Expect(forwarder).NotTo(BeNil())
+				Expect(forwarder.Image).To(Equal("log-forwarder:1.0"))
+				Expect(forwarder.VolumeMounts).To(ConsistOf(corev1.VolumeMount{Name: "fdb-trace-logs", MountPath: "/var/log/fdb", ReadOnly: true}))
+				Expect(forwarder.Env).To(ConsistOf(
+					corev1.EnvVar{Name: "FDB_TRACE_LOG_DIR", Value: "/var/log/fdb"},
+					corev1.EnvVar{Name: "FDB_TRACE_LOG_FORMAT", Value: "json"},
+				))
+			})
+
+			When("the event receiver address is set", func() {
+				BeforeEach(func() {
+					cluster.Spec.TraceLogs.EventReceiverAddress = "fdb-kubernetes-operator.default.svc:8080"
+					pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should pass the URL of the trace event receiver to the forwarder", func() {
+					forwarder := GetContainer(pod.Spec.Containers, fdbv1beta2.TraceLogForwarderContainerName)
+					Expect(forwarder).NotTo(BeNil())
+					Expect(forwarder.Env).To(ContainElement(corev1.EnvVar{
+						Name:  "FDB_TRACE_EVENT_RECEIVER_URL",
+						Value: "http://fdb-kubernetes-operator.default.svc:8080/trace-events?cluster=operator-test-1&namespace=my-ns",
+					}))
+					Expect(forwarder.Env).To(ContainElement(corev1.EnvVar{
+						Name: "FDB_TRACE_EVENT_RECEIVER_TOKEN",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "operator-test-1-trace-event-receiver"},
+								Key:                  "token",
+							},
+						},
+					}))
+				})
+			})
+		})
+
+		Context("with additional containers", func() {
+			var hash string
+
+			BeforeEach(func() {
+				hash, err = GetPodSpecHash(cluster, fdbv1beta2.ProcessClassStorage, 1, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.AdditionalContainers = []corev1.Container{{Name: "log-agent", Image: "log-agent:1.0"}}
+				settings.AdditionalInitContainers = []corev1.Container{{Name: "setup", Image: "setup:1.0"}}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+
+				pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should add the containers without changing the spec hash", func() {
+				Expect(pod.Spec.Containers).To(HaveLen(3))
+				Expect(pod.Spec.Containers[2].Name).To(Equal("log-agent"))
+				Expect(pod.Spec.InitContainers).To(HaveLen(2))
+				Expect(pod.Spec.InitContainers[0].Name).To(Equal(fdbv1beta2.InitContainerName))
+				Expect(pod.Spec.InitContainers[1].Name).To(Equal("setup"))
+				Expect(pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]).To(Equal(hash))
+			})
+
+			It("should report the additional containers that are not ready", func() {
+				Expect(GetAdditionalContainersNotReady(cluster, pod)).To(ConsistOf("log-agent"))
+
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "log-agent", Ready: true}}
+				Expect(GetAdditionalContainersNotReady(cluster, pod)).To(BeEmpty())
+			})
+
+			It("should ignore the additional containers when checking for resource only updates", func() {
+				resourceOnly, err := IsResourceOnlyUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resourceOnly).To(BeFalse())
+
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.PodTemplate = settings.PodTemplate.DeepCopy()
+				settings.PodTemplate.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+
+				resourceOnly, err = IsResourceOnlyUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resourceOnly).To(BeTrue())
+			})
+		})
+	})
+
+	When("building the Pod template hash", func() {
+		var hash string
+
+		BeforeEach(func() {
+			hash, err = GetPodTemplateHash(cluster, fdbv1beta2.ProcessClassStorage)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return a short hash that is stable", func() {
+			Expect(hash).To(HaveLen(10))
+			Expect(GetPodTemplateHash(cluster, fdbv1beta2.ProcessClassStorage)).To(Equal(hash))
+		})
+
+		It("should not depend on the settings of a single process group", func() {
+			cluster.Spec.PinnedProcessGroups = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessGroupPin{"storage-1": {NodeName: "node-1"}}
+			Expect(GetPodTemplateHash(cluster, fdbv1beta2.ProcessClassStorage)).To(Equal(hash))
+		})
+
+		It("should change when the Pod template of the process class changes", func() {
+			settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			settings.PodTemplate = settings.PodTemplate.DeepCopy()
+			settings.PodTemplate.Spec.PriorityClassName = "fdb"
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+
+			Expect(GetPodTemplateHash(cluster, fdbv1beta2.ProcessClassStorage)).NotTo(Equal(hash))
+		})
+
+		It("should differ between process classes", func() {
+			Expect(GetPodTemplateHash(cluster, fdbv1beta2.ProcessClassLog)).NotTo(Equal(hash))
+		})
+	})
+
+	When("checking for scheduling only updates", func() {
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			// Kubernetes adds default tolerations to every Pod.
+			pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+				Key:               corev1.TaintNodeNotReady,
+				Operator:          corev1.TolerationOpExists,
+				Effect:            corev1.TaintEffectNoExecute,
+				TolerationSeconds: pointer.Int64(300),
+			})
+		})
+
+		It("should not report an update if the spec is unchanged", func() {
+			schedulingOnly, err := IsSchedulingOnlyUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedulingOnly).To(BeFalse())
+		})
+
+		It("should detect changes of the scheduling constraints", func() {
+			settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			settings.PodTemplate = settings.PodTemplate.DeepCopy()
+			settings.PodTemplate.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+			settings.PodTemplate.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "fdb"}}
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+
+			schedulingOnly, err := IsSchedulingOnlyUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedulingOnly).To(BeTrue())
+		})
+
+		It("should not report other changes as scheduling only", func() {
+			settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+			settings.PodTemplate = settings.PodTemplate.DeepCopy()
+			settings.PodTemplate.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+			settings.PodTemplate.Spec.PriorityClassName = "fdb"
+			cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+
+			schedulingOnly, err := IsSchedulingOnlyUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedulingOnly).To(BeFalse())
+		})
+	})
+
+	When("checking for sidecar TLS updates", func() {
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not report an update if the spec is unchanged", func() {
+			sidecarTLSUpdate, err := IsSidecarTLSUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sidecarTLSUpdate).To(BeFalse())
+		})
+
+		It("should detect when TLS is enabled for the sidecar", func() {
+			cluster.Spec.SidecarContainer.EnableTLS = true
+
+			sidecarTLSUpdate, err := IsSidecarTLSUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sidecarTLSUpdate).To(BeTrue())
+		})
+
+		It("should not report other changes as sidecar TLS updates", func() {
+			cluster.Spec.MainContainer.EnableTLS = true
+
+			sidecarTLSUpdate, err := IsSidecarTLSUpdate(cluster, fdbv1beta2.ProcessClassStorage, 1, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sidecarTLSUpdate).To(BeFalse())
+		})
 	})
 
 	Describe("GetPodSpec", func() {
@@ -712,6 +927,89 @@ var _ = Describe("pod_models", func() {
 				})
 			})
 
+			When("using the ProcessPerContainer layout", func() {
+				BeforeEach(func() {
+					cluster.Spec.PodLayout = fdbv1beta2.PodLayoutProcessPerContainer
+					cluster.Spec.StorageServersPerPod = 2
+					cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = fdbv1beta2.ProcessSettings{
+						PodTemplate: cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate,
+						HealthProbes: &fdbv1beta2.ProcessHealthProbes{
+							EnableLivenessProbe: pointer.Bool(true),
+						},
+					}
+					spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should run every process in its own container", func() {
+					Expect(spec.Containers).To(HaveLen(3))
+					Expect(spec.Containers[0].Name).To(Equal(fdbv1beta2.MainContainerName))
+					Expect(spec.Containers[1].Name).To(Equal("foundationdb-2"))
+					Expect(spec.Containers[2].Name).To(Equal(fdbv1beta2.SidecarContainerName))
+
+					for index, processContainer := range spec.Containers[:2] {
+						processNumber := index + 1
+						port := 4499 + 2*processNumber
+						Expect(processContainer.Command).To(Equal([]string{"/usr/bin/fdbserver"}))
+						Expect(processContainer.Args).To(ContainElements(
+							"--cluster_file=/var/fdb/data/fdb.cluster",
+							"--seed_cluster_file=/var/dynamic-conf/fdb.cluster",
+							fmt.Sprintf("--public_address=[$(FDB_PUBLIC_IP)]:%d", port),
+							"--class=storage",
+							fmt.Sprintf("--datadir=/var/fdb/data/%d", processNumber),
+							fmt.Sprintf("--locality_process_id=$(FDB_INSTANCE_ID)-%d", processNumber),
+							"--locality_zoneid=$(FDB_ZONE_ID)",
+						))
+						Expect(processContainer.Env).To(ContainElement(corev1.EnvVar{Name: PodLayoutEnvironmentVariable, Value: string(fdbv1beta2.PodLayoutProcessPerContainer)}))
+						Expect(processContainer.VolumeMounts).To(Equal(spec.Containers[0].VolumeMounts))
+						Expect(processContainer.LivenessProbe).NotTo(BeNil())
+						Expect(processContainer.LivenessProbe.TCPSocket).NotTo(BeNil())
+						Expect(processContainer.LivenessProbe.TCPSocket.Port.IntValue()).To(Equal(port))
+					}
+				})
+
+				It("should split the resources of the main container across the process containers", func() {
+					for _, processContainer := range spec.Containers[:2] {
+						Expect(processContainer.Resources.Requests.Cpu().MilliValue()).To(BeNumerically("==", 500))
+						Expect(processContainer.Resources.Requests.Memory().Value()).To(BeNumerically("==", 512*1024*1024))
+						Expect(processContainer.Resources.Limits.Cpu().MilliValue()).To(BeNumerically("==", 500))
+						Expect(processContainer.Resources.Limits.Memory().Value()).To(BeNumerically("==", 512*1024*1024))
+					}
+				})
+
+				When("the DNS locality fields are defined", func() {
+					BeforeEach(func() {
+						cluster.Spec.Routing.DefineDNSLocalityFields = pointer.Bool(true)
+						spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should define the DNS name in every process container", func() {
+						for _, processContainer := range spec.Containers[:2] {
+							Expect(processContainer.Args).To(ContainElement("--locality_dns_name=$(FDB_DNS_NAME)"))
+							Expect(processContainer.Env).To(ContainElement(corev1.EnvVar{Name: "FDB_DNS_NAME", Value: GetPodDNSName(cluster, fdbv1beta2.ProcessClassStorage, "operator-test-1-storage-1")}))
+						}
+					})
+				})
+
+				When("running a log process", func() {
+					BeforeEach(func() {
+						spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassLog, 1)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should run the process in the main container", func() {
+						Expect(spec.Containers).To(HaveLen(2))
+						Expect(spec.Containers[0].Name).To(Equal(fdbv1beta2.MainContainerName))
+						Expect(spec.Containers[0].Command).To(Equal([]string{"/usr/bin/fdbserver"}))
+						Expect(spec.Containers[0].Args).To(ContainElements(
+							"--class=log",
+							"--datadir=/var/fdb/data",
+						))
+					})
+				})
+			})
+
 			Context("with an instance that is crash looping", func() {
 				BeforeEach(func() {
 					cluster.Spec.Buggify.CrashLoop = []fdbv1beta2.ProcessGroupID{"storage-1"}
@@ -1188,6 +1486,132 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with a process group pinned to a node", func() {
+			BeforeEach(func() {
+				cluster.Spec.PinnedProcessGroups = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessGroupPin{
+					"storage-1": {NodeName: "node-1", TolerateUnschedulable: true},
+				}
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should have an affinity rule for the name of the node", func() {
+				Expect(spec.Affinity).NotTo(BeNil())
+				Expect(spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+					{
+						MatchFields: []corev1.NodeSelectorRequirement{
+							{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}},
+						},
+					},
+				}))
+			})
+
+			It("should tolerate unschedulable nodes", func() {
+				Expect(spec.Tolerations).To(ContainElement(corev1.Toleration{
+					Key:      corev1.TaintNodeUnschedulable,
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				}))
+			})
+
+			When("the process group is not pinned", func() {
+				BeforeEach(func() {
+					spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 2)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should not have an affinity rule", func() {
+					Expect(spec.Affinity).To(BeNil())
+				})
+			})
+		})
+
+		Context("with a process group pinned to a zone", func() {
+			BeforeEach(func() {
+				cluster.Spec.PinnedProcessGroups = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessGroupPin{
+					"storage-1": {Zone: "zone-a"},
+				}
+				cluster.Spec.FaultDomain.Key = corev1.LabelTopologyZone
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should select the nodes of the zone", func() {
+				Expect(spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelTopologyZone, "zone-a"))
+			})
+
+			It("should not tolerate unschedulable nodes", func() {
+				for _, toleration := range spec.Tolerations {
+					Expect(toleration.Key).NotTo(Equal(corev1.TaintNodeUnschedulable))
+				}
+			})
+		})
+
+		Context("with the openshift compatibility mode", func() {
+			BeforeEach(func() {
+				cluster.Spec.CompatibilityMode = fdbv1beta2.CompatibilityModeOpenShift
+				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+					fdbv1beta2.ProcessClassGeneral: {
+						PodTemplate: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								SecurityContext: &corev1.PodSecurityContext{
+									RunAsUser:  pointer.Int64(4059),
+									RunAsGroup: pointer.Int64(4059),
+									FSGroup:    pointer.Int64(4059),
+								},
+								Containers: []corev1.Container{
+									{
+										Name: fdbv1beta2.MainContainerName,
+										SecurityContext: &corev1.SecurityContext{
+											RunAsUser: pointer.Int64(0),
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+				Expect(NormalizeClusterSpec(cluster, DeprecationOptions{})).NotTo(HaveOccurred())
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should remove the fixed UIDs from the pod security context", func() {
+				Expect(spec.SecurityContext).NotTo(BeNil())
+				Expect(spec.SecurityContext.RunAsUser).To(BeNil())
+				Expect(spec.SecurityContext.RunAsGroup).To(BeNil())
+				Expect(spec.SecurityContext.FSGroup).To(BeNil())
+				Expect(spec.SecurityContext.FSGroupChangePolicy).To(HaveValue(Equal(corev1.FSGroupChangeOnRootMismatch)))
+			})
+
+			It("should configure all containers for the restricted security context constraints", func() {
+				containers := append(spec.InitContainers, spec.Containers...)
+				Expect(containers).NotTo(BeEmpty())
+				for _, container := range containers {
+					Expect(container.SecurityContext).NotTo(BeNil())
+					Expect(container.SecurityContext.RunAsUser).To(BeNil())
+					Expect(container.SecurityContext.RunAsNonRoot).To(HaveValue(BeTrue()))
+					Expect(container.SecurityContext.AllowPrivilegeEscalation).To(HaveValue(BeFalse()))
+					Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+				}
+			})
+		})
+
+		Context("with the default compatibility mode", func() {
+			BeforeEach(func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not change the security contexts", func() {
+				Expect(spec.SecurityContext).To(BeNil())
+				for _, container := range spec.Containers {
+					Expect(container.SecurityContext.RunAsNonRoot).To(BeNil())
+					Expect(container.SecurityContext.Capabilities).To(BeNil())
+				}
+			})
+		})
+
 		Context("with a basic storage process group with multiple storage servers per disk", func() {
 			BeforeEach(func() {
 				cluster.Spec.StorageServersPerPod = 2
@@ -1498,101 +1922,566 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
-		Context("with a the public IP from the service", func() {
+		Context("with a the public IP from the service", func() {
+			BeforeEach(func() {
+				var source = fdbv1beta2.PublicIPSourceService
+				cluster.Spec.Routing.PublicIPSource = &source
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should have the environment variables for the IPs in the sidecar container", func() {
+				sidecarEnv := GetEnvVars(spec.Containers[1])
+				Expect(sidecarEnv["FDB_PUBLIC_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("metadata.annotations['foundationdb.org/public-ip']"))
+				Expect(sidecarEnv["FDB_POD_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("status.podIP"))
+			})
+
+			It("should have the environment variables for the IPs in the init container", func() {
+				sidecarEnv := GetEnvVars(spec.InitContainers[0])
+				Expect(sidecarEnv["FDB_PUBLIC_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("metadata.annotations['foundationdb.org/public-ip']"))
+				Expect(sidecarEnv["FDB_POD_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("status.podIP"))
+			})
+
+			It("should have the pod IP in the init container args", func() {
+				Expect(len(spec.InitContainers)).To(Equal(1))
+				initContainer := spec.InitContainers[0]
+				Expect(initContainer.Name).To(Equal(fdbv1beta2.InitContainerName))
+				Expect(initContainer.Args).To(Equal([]string{
+					"--copy-file",
+					"fdb.cluster",
+					"--input-monitor-conf",
+					"fdbmonitor.conf",
+					"--copy-binary",
+					"fdbserver",
+					"--copy-binary",
+					"fdbcli",
+					"--main-container-version",
+					cluster.Spec.Version,
+					"--substitute-variable",
+					"FDB_POD_IP",
+					"--init-mode",
+				}))
+			})
+
+			It("should have the pod IP in the sidecar container args", func() {
+				Expect(len(spec.Containers)).To(Equal(2))
+				sidecarContainer := spec.Containers[1]
+				Expect(sidecarContainer.Name).To(Equal(fdbv1beta2.SidecarContainerName))
+				Expect(sidecarContainer.Args).To(Equal([]string{
+					"--copy-file",
+					"fdb.cluster",
+					"--input-monitor-conf",
+					"fdbmonitor.conf",
+					"--copy-binary",
+					"fdbserver",
+					"--copy-binary",
+					"fdbcli",
+					"--main-container-version",
+					cluster.Spec.Version,
+					"--substitute-variable",
+					"FDB_POD_IP",
+				}))
+			})
+		})
+
+		Context("with a the public IP from the load balancer", func() {
+			BeforeEach(func() {
+				var source = fdbv1beta2.PublicIPSourceLoadBalancer
+				cluster.Spec.Routing.PublicIPSource = &source
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should have the environment variables for the IPs in the sidecar container", func() {
+				sidecarEnv := GetEnvVars(spec.Containers[1])
+				Expect(sidecarEnv["FDB_PUBLIC_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("metadata.annotations['foundationdb.org/public-ip']"))
+				Expect(sidecarEnv["FDB_POD_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("status.podIP"))
+			})
+
+			It("should not expose any host ports", func() {
+				Expect(spec.Containers[0].Ports).To(BeEmpty())
+			})
+		})
+
+		Context("with a the public IP from the node", func() {
+			BeforeEach(func() {
+				var source = fdbv1beta2.PublicIPSourceNode
+				cluster.Spec.Routing.PublicIPSource = &source
+				cluster.Spec.StorageServersPerPod = 2
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should have the environment variables for the IPs in the sidecar container", func() {
+				sidecarEnv := GetEnvVars(spec.Containers[1])
+				Expect(sidecarEnv["FDB_PUBLIC_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("status.hostIP"))
+				Expect(sidecarEnv["FDB_POD_IP"]).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom).NotTo(BeNil())
+				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("status.podIP"))
+			})
+
+			It("should expose the ports of all processes on the node", func() {
+				Expect(spec.Containers[0].Name).To(Equal(fdbv1beta2.MainContainerName))
+				Expect(spec.Containers[0].Ports).To(ConsistOf(
+					corev1.ContainerPort{Name: "tls", ContainerPort: 4500, HostPort: 4500, Protocol: corev1.ProtocolTCP},
+					corev1.ContainerPort{Name: "non-tls", ContainerPort: 4501, HostPort: 4501, Protocol: corev1.ProtocolTCP},
+					corev1.ContainerPort{Name: "tls-2", ContainerPort: 4502, HostPort: 4502, Protocol: corev1.ProtocolTCP},
+					corev1.ContainerPort{Name: "non-tls-2", ContainerPort: 4503, HostPort: 4503, Protocol: corev1.ProtocolTCP},
+				))
+			})
+		})
+
+		When("the storage processes use the host network", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+					fdbv1beta2.ProcessClassGeneral: cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral],
+					fdbv1beta2.ProcessClassStorage: {UseHostNetwork: pointer.Bool(true)},
+				}
+				cluster.Spec.StorageServersPerPod = 2
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should use the host network", func() {
+				Expect(spec.HostNetwork).To(BeTrue())
+				Expect(spec.DNSPolicy).To(Equal(corev1.DNSClusterFirstWithHostNet))
+			})
+
+			It("should expose the ports of the storage port range on the node", func() {
+				Expect(spec.Containers[0].Name).To(Equal(fdbv1beta2.MainContainerName))
+				Expect(spec.Containers[0].Ports).To(ConsistOf(
+					corev1.ContainerPort{Name: "tls", ContainerPort: 4520, HostPort: 4520, Protocol: corev1.ProtocolTCP},
+					corev1.ContainerPort{Name: "non-tls", ContainerPort: 4521, HostPort: 4521, Protocol: corev1.ProtocolTCP},
+					corev1.ContainerPort{Name: "tls-2", ContainerPort: 4522, HostPort: 4522, Protocol: corev1.ProtocolTCP},
+					corev1.ContainerPort{Name: "non-tls-2", ContainerPort: 4523, HostPort: 4523, Protocol: corev1.ProtocolTCP},
+				))
+			})
+
+			It("should bind the sidecar to a dedicated port", func() {
+				Expect(spec.Containers[1].Name).To(Equal(fdbv1beta2.SidecarContainerName))
+				Expect(spec.Containers[1].Ports).To(ConsistOf(
+					corev1.ContainerPort{Name: "sidecar", ContainerPort: 8082, HostPort: 8082, Protocol: corev1.ProtocolTCP},
+				))
+				Expect(spec.Containers[1].Args).To(ContainElements("--bind-address", "$(FDB_PUBLIC_IP):8082"))
+				Expect(spec.Containers[1].LivenessProbe.TCPSocket.Port.IntVal).To(BeNumerically("==", 8082))
+				Expect(spec.InitContainers[0].Args).NotTo(ContainElement("--bind-address"))
+			})
+
+			When("the Pod template defines a DNS policy", func() {
+				BeforeEach(func() {
+					settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage]
+					settings.PodTemplate = cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate.DeepCopy()
+					settings.PodTemplate.Spec.DNSPolicy = corev1.DNSDefault
+					cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = settings
+					spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should keep the DNS policy", func() {
+					Expect(spec.HostNetwork).To(BeTrue())
+					Expect(spec.DNSPolicy).To(Equal(corev1.DNSDefault))
+				})
+			})
+
+			When("building the Pod spec for a log process", func() {
+				BeforeEach(func() {
+					spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassLog, 1)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should not use the host network", func() {
+					Expect(spec.HostNetwork).To(BeFalse())
+					Expect(spec.Containers[0].Ports).To(BeEmpty())
+					Expect(spec.Containers[1].Args).NotTo(ContainElement("--bind-address"))
+				})
+			})
+		})
+
+		Context("with a headless service", func() {
+			BeforeEach(func() {
+				var enabled = true
+				cluster.Spec.Routing.HeadlessService = &enabled
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should have the hostname and subdomain set", func() {
+				Expect(spec.Hostname).To(Equal("operator-test-1-storage-1"))
+				Expect(spec.Subdomain).To(Equal("operator-test-1"))
+			})
+		})
+
+		Context("with no headless service", func() {
+			BeforeEach(func() {
+				var enabled = false
+				cluster.Spec.Routing.HeadlessService = &enabled
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not have the hostname and subdomain set", func() {
+				Expect(spec.Hostname).To(Equal(""))
+				Expect(spec.Subdomain).To(Equal(""))
+			})
+		})
+
+		When("a priority class is defined", func() {
+			BeforeEach(func() {
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.PodTemplate.Spec.PriorityClassName = "template-priority"
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{PriorityClassName: "fdb-storage"}
+			})
+
+			It("should use the priority class of the process class", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.PriorityClassName).To(Equal("fdb-storage"))
+			})
+
+			It("should use the priority class of the Pod template for other process classes", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassLog, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.PriorityClassName).To(Equal("template-priority"))
+			})
+		})
+
+		When("an architecture is defined for the process class", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{Architecture: "arm64"}
+				generalSettings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				generalSettings.Architecture = "amd64"
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = generalSettings
+				cluster.Spec.MainContainer.ImageConfigs = []fdbv1beta2.ImageConfig{
+					{Architecture: "arm64", TagSuffix: "-arm64"},
+					{Architecture: "amd64", TagSuffix: "-amd64"},
+					{BaseImage: "foundationdb/foundationdb"},
+				}
+				cluster.Spec.SidecarContainer.ImageConfigs = []fdbv1beta2.ImageConfig{
+					{Architecture: "arm64", TagSuffix: "-1-arm64"},
+					{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", TagSuffix: "-1"},
+				}
+			})
+
+			It("should schedule the Pod only on nodes of the architecture and use the images for the architecture", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelArchStable, "arm64"))
+				Expect(spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s-arm64", cluster.Status.RunningVersion)))
+				Expect(spec.Containers[1].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb-kubernetes-sidecar:%s-1-arm64", cluster.Status.RunningVersion)))
+				Expect(spec.InitContainers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb-kubernetes-sidecar:%s-1-arm64", cluster.Status.RunningVersion)))
+			})
+
+			It("should use the architecture of the general process class for other process classes", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassLog, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelArchStable, "amd64"))
+				Expect(spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s-amd64", cluster.Status.RunningVersion)))
+				Expect(spec.Containers[1].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb-kubernetes-sidecar:%s-1", cluster.Status.RunningVersion)))
+			})
+
+			When("the unified images are enabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.UseUnifiedImage = pointer.Bool(true)
+				})
+
+				It("should use the main container image for the architecture in the sidecar", func() {
+					spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelArchStable, "arm64"))
+					Expect(spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s-arm64", cluster.Status.RunningVersion)))
+					Expect(spec.Containers[1].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s-arm64", cluster.Status.RunningVersion)))
+				})
+			})
+		})
+
+		When("the image configs only define images for a single architecture", func() {
+			BeforeEach(func() {
+				cluster.Spec.MainContainer.ImageConfigs = []fdbv1beta2.ImageConfig{
+					{Architecture: "arm64", TagSuffix: "-arm64"},
+					{BaseImage: "foundationdb/foundationdb"},
+				}
+			})
+
+			It("should use the architecture of the image configs", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassLog, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelArchStable, "arm64"))
+				Expect(spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s-arm64", cluster.Status.RunningVersion)))
+			})
+		})
+
+		When("environment variables are defined for the process class", func() {
+			var passwordVariable corev1.EnvVar
+
+			BeforeEach(func() {
+				passwordVariable = corev1.EnvVar{
+					Name: "FDB_TLS_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "tls-password"},
+							Key:                  "password",
+						},
+					},
+				}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{
+					Env: []corev1.EnvVar{
+						passwordVariable,
+						{Name: "KNOB_VALUE", Value: "10"},
+					},
+					EnvFrom: []corev1.EnvFromSource{
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-settings"}}},
+					},
+					Parameters: fdbv1beta2.FoundationDBParameters{{Name: "knob_test", Value: "$KNOB_VALUE"}},
+				}
+				cluster.Status.ProcessEnvironmentHashes = map[fdbv1beta2.ProcessClass]string{fdbv1beta2.ProcessClassStorage: "hash"}
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should add the environment to the main container", func() {
+				mainContainer := spec.Containers[0]
+				Expect(mainContainer.Env).To(ContainElements(
+					passwordVariable,
+					corev1.EnvVar{Name: "KNOB_VALUE", Value: "10"},
+					corev1.EnvVar{Name: ProcessEnvironmentHashVariable, Value: "hash"},
+				))
+				Expect(mainContainer.EnvFrom).To(HaveLen(1))
+				Expect(mainContainer.EnvFrom[0].ConfigMapRef.Name).To(Equal("proxy-settings"))
+			})
+
+			It("should make the referenced variables available for substitution in the sidecar", func() {
+				for _, container := range []corev1.Container{spec.InitContainers[0], spec.Containers[1]} {
+					Expect(container.Args).To(ContainElement("KNOB_VALUE"))
+					Expect(container.Args).NotTo(ContainElement("FDB_TLS_PASSWORD"))
+					Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "KNOB_VALUE", Value: "10"}))
+					Expect(container.Env).NotTo(ContainElement(passwordVariable))
+				}
+			})
+
+			It("should not add the environment to other process classes", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassLog, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.Containers[0].Env).NotTo(ContainElement(passwordVariable))
+				Expect(spec.Containers[0].EnvFrom).To(BeEmpty())
+			})
+		})
+
+		When("volume claim template selectors are defined", func() {
+			BeforeEach(func() {
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.PodTemplate.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{
+					VolumeClaimTemplateSelectors: []fdbv1beta2.VolumeClaimTemplateSelector{
+						{Name: "zone-a", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "a"}},
+						{Name: "zone-b", NodeSelector: map[string]string{"topology.kubernetes.io/zone": "b"}},
+					},
+				}
+				cluster.Status.ProcessGroups = []*fdbv1beta2.ProcessGroupStatus{
+					{ProcessGroupID: "storage-1", ProcessClass: fdbv1beta2.ProcessClassStorage, VolumeClaimTemplateSelector: "zone-b"},
+					{ProcessGroupID: "storage-2", ProcessClass: fdbv1beta2.ProcessClassStorage, VolumeClaimTemplateSelector: "zone-a"},
+				}
+			})
+
+			It("should pin the Pods to the nodes of their entry", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.NodeSelector).To(Equal(map[string]string{"disk": "ssd", "topology.kubernetes.io/zone": "b"}))
+
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.NodeSelector).To(Equal(map[string]string{"disk": "ssd", "topology.kubernetes.io/zone": "a"}))
+			})
+
+			It("should not change the Pods of other process classes", func() {
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassLog, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.NodeSelector).To(Equal(map[string]string{"disk": "ssd"}))
+			})
+		})
+
+		Context("with custom DNS settings", func() {
+			var dnsSettings *fdbv1beta2.PodDNSSettings
+
+			BeforeEach(func() {
+				cluster.Spec.Routing.HeadlessService = pointer.Bool(true)
+				cluster.Spec.Routing.DefineDNSLocalityFields = pointer.Bool(true)
+				dnsSettings = &fdbv1beta2.PodDNSSettings{
+					Policy:    corev1.DNSClusterFirstWithHostNet,
+					Ndots:     pointer.Int(2),
+					Searches:  []string{"operator-test-1.my-ns.svc.cluster.local", "other-ns.svc.cluster.local"},
+					Subdomain: pointer.String("storage-dns"),
+				}
+			})
+
+			JustBeforeEach(func() {
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.DNS = dnsSettings
+				settings.PodTemplate.Spec.DNSConfig = &corev1.PodDNSConfig{
+					Searches: []string{"other-ns.svc.cluster.local"},
+					Options:  []corev1.PodDNSConfigOption{{Name: "ndots", Value: pointer.String("5")}},
+				}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should use the DNS settings", func() {
+				Expect(spec.Hostname).To(Equal("operator-test-1-storage-1"))
+				Expect(spec.Subdomain).To(Equal("storage-dns"))
+				Expect(spec.DNSPolicy).To(Equal(corev1.DNSClusterFirstWithHostNet))
+				Expect(spec.DNSConfig.Searches).To(Equal([]string{"other-ns.svc.cluster.local", "operator-test-1.my-ns.svc.cluster.local"}))
+				Expect(spec.DNSConfig.Options).To(Equal([]corev1.PodDNSConfigOption{{Name: "ndots", Value: pointer.String("2")}}))
+			})
+
+			It("should use the subdomain for the DNS name", func() {
+				sidecarEnv := map[string]string{}
+				for _, envVar := range spec.Containers[1].Env {
+					sidecarEnv[envVar.Name] = envVar.Value
+				}
+
+				Expect(sidecarEnv).To(HaveKeyWithValue("FDB_DNS_NAME", "operator-test-1-storage-1.storage-dns.my-ns.svc.cluster.local"))
+			})
+
+			When("the hostname should not be set", func() {
+				BeforeEach(func() {
+					dnsSettings.SetHostname = pointer.Bool(false)
+				})
+
+				It("should not set the hostname", func() {
+					Expect(spec.Hostname).To(BeEmpty())
+					Expect(spec.Subdomain).To(Equal("storage-dns"))
+				})
+			})
+		})
+
+		Context("with process health probes", func() {
+			var processClass fdbv1beta2.ProcessClass
+
 			BeforeEach(func() {
-				var source = fdbv1beta2.PublicIPSourceService
-				cluster.Spec.Routing.PublicIPSource = &source
-				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				processClass = fdbv1beta2.ProcessClassStorage
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = fdbv1beta2.ProcessSettings{
+					HealthProbes: &fdbv1beta2.ProcessHealthProbes{
+						EnableLivenessProbe:  pointer.Bool(true),
+						EnableReadinessProbe: pointer.Bool(true),
+						FailureThreshold:     pointer.Int32(3),
+					},
+				}
+			})
+
+			JustBeforeEach(func() {
+				spec, err = GetPodSpec(cluster, processClass, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
-			It("should have the environment variables for the IPs in the sidecar container", func() {
-				sidecarEnv := GetEnvVars(spec.Containers[1])
-				Expect(sidecarEnv["FDB_PUBLIC_IP"]).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("metadata.annotations['foundationdb.org/public-ip']"))
-				Expect(sidecarEnv["FDB_POD_IP"]).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("status.podIP"))
+			It("should add the probes to the main container", func() {
+				mainContainer := spec.Containers[0]
+				Expect(mainContainer.Name).To(Equal(fdbv1beta2.MainContainerName))
+				Expect(mainContainer.LivenessProbe).To(Equal(&corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						Exec: &corev1.ExecAction{
+							Command: []string{"sh", "-c", "pgrep -x fdbmonitor > /dev/null && [ \"$(pgrep -x fdbserver | wc -l)\" -ge \"$(cat /var/dynamic-conf/fdbmonitor.conf 2>/dev/null | grep -c '^\\[fdbserver\\.')\" ]"},
+						},
+					},
+					PeriodSeconds:    30,
+					TimeoutSeconds:   5,
+					FailureThreshold: 3,
+				}))
+				Expect(mainContainer.ReadinessProbe).To(Equal(mainContainer.LivenessProbe))
 			})
 
-			It("should have the environment variables for the IPs in the init container", func() {
-				sidecarEnv := GetEnvVars(spec.InitContainers[0])
-				Expect(sidecarEnv["FDB_PUBLIC_IP"]).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_PUBLIC_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("metadata.annotations['foundationdb.org/public-ip']"))
-				Expect(sidecarEnv["FDB_POD_IP"]).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom).NotTo(BeNil())
-				Expect(sidecarEnv["FDB_POD_IP"].ValueFrom.FieldRef.FieldPath).To(Equal("status.podIP"))
+			When("the monitor conf is emptied by the buggify option", func() {
+				BeforeEach(func() {
+					cluster.Spec.Buggify.EmptyMonitorConf = true
+				})
+
+				It("should not change the probes", func() {
+					Expect(spec.Containers[0].LivenessProbe.Exec.Command).To(ContainElement(ContainSubstring("/var/dynamic-conf/fdbmonitor.conf")))
+				})
 			})
 
-			It("should have the pod IP in the init container args", func() {
-				Expect(len(spec.InitContainers)).To(Equal(1))
-				initContainer := spec.InitContainers[0]
-				Expect(initContainer.Name).To(Equal(fdbv1beta2.InitContainerName))
-				Expect(initContainer.Args).To(Equal([]string{
-					"--copy-file",
-					"fdb.cluster",
-					"--input-monitor-conf",
-					"fdbmonitor.conf",
-					"--copy-binary",
-					"fdbserver",
-					"--copy-binary",
-					"fdbcli",
-					"--main-container-version",
-					cluster.Spec.Version,
-					"--substitute-variable",
-					"FDB_POD_IP",
-					"--init-mode",
-				}))
+			When("the unified image is used", func() {
+				BeforeEach(func() {
+					cluster.Spec.UseUnifiedImage = pointer.Bool(true)
+				})
+
+				It("should query the health endpoint of the monitor", func() {
+					Expect(spec.Containers[0].LivenessProbe.Exec).To(BeNil())
+					Expect(spec.Containers[0].LivenessProbe.HTTPGet).To(Equal(&corev1.HTTPGetAction{
+						Path: "/health",
+						Port: intstr.FromInt(8081),
+					}))
+				})
 			})
 
-			It("should have the pod IP in the sidecar container args", func() {
-				Expect(len(spec.Containers)).To(Equal(2))
-				sidecarContainer := spec.Containers[1]
-				Expect(sidecarContainer.Name).To(Equal(fdbv1beta2.SidecarContainerName))
-				Expect(sidecarContainer.Args).To(Equal([]string{
-					"--copy-file",
-					"fdb.cluster",
-					"--input-monitor-conf",
-					"fdbmonitor.conf",
-					"--copy-binary",
-					"fdbserver",
-					"--copy-binary",
-					"fdbcli",
-					"--main-container-version",
-					cluster.Spec.Version,
-					"--substitute-variable",
-					"FDB_POD_IP",
-				}))
+			When("the Pods of a different process class are created", func() {
+				BeforeEach(func() {
+					processClass = fdbv1beta2.ProcessClassLog
+				})
+
+				It("should not add the probes", func() {
+					Expect(spec.Containers[0].LivenessProbe).To(BeNil())
+					Expect(spec.Containers[0].ReadinessProbe).To(BeNil())
+				})
 			})
 		})
 
-		Context("with a headless service", func() {
+		Context("with crash collection", func() {
 			BeforeEach(func() {
-				var enabled = true
-				cluster.Spec.Routing.HeadlessService = &enabled
+				cluster.Spec.CrashCollection = &fdbv1beta2.CrashCollectionSpec{
+					VolumeClaimName: "crash-dumps",
+				}
 				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
-			It("should have the hostname and subdomain set", func() {
-				Expect(spec.Hostname).To(Equal("operator-test-1-storage-1"))
-				Expect(spec.Subdomain).To(Equal("operator-test-1"))
+			It("should mount the crash volume as working directory of the main container", func() {
+				Expect(spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "fdb-crash-dumps",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "crash-dumps"},
+					},
+				}))
+
+				mainContainer := spec.Containers[0]
+				Expect(mainContainer.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "fdb-crash-dumps", MountPath: "/var/fdb/crash-dumps", SubPath: "operator-test-1-storage-1"}))
+				Expect(mainContainer.WorkingDir).To(Equal("/var/fdb/crash-dumps"))
+				Expect(mainContainer.Env).To(ContainElement(corev1.EnvVar{Name: "FDB_CRASH_DIR", Value: "/var/fdb/crash-dumps"}))
 			})
-		})
 
-		Context("with no headless service", func() {
-			BeforeEach(func() {
-				var enabled = false
-				cluster.Spec.Routing.HeadlessService = &enabled
-				spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
-				Expect(err).NotTo(HaveOccurred())
+			It("should start fdbmonitor in the crash directory with core dumps enabled", func() {
+				Expect(spec.Containers[0].Args).To(HaveLen(1))
+				Expect(spec.Containers[0].Args[0]).To(HavePrefix("ulimit -c unlimited 2>/dev/null; cd \"$FDB_CRASH_DIR\" && fdbmonitor --conffile /var/dynamic-conf/fdbmonitor.conf"))
 			})
 
-			It("should not have the hostname and subdomain set", func() {
-				Expect(spec.Hostname).To(Equal(""))
-				Expect(spec.Subdomain).To(Equal(""))
+			When("the unified image is used", func() {
+				BeforeEach(func() {
+					cluster.Spec.UseUnifiedImage = pointer.Bool(true)
+					spec, err = GetPodSpec(cluster, fdbv1beta2.ProcessClassStorage, 1)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should use the crash directory as working directory without changing the arguments", func() {
+					mainContainer := spec.Containers[0]
+					Expect(mainContainer.WorkingDir).To(Equal("/var/fdb/crash-dumps"))
+					Expect(mainContainer.Env).To(ContainElement(corev1.EnvVar{Name: "FDB_CRASH_DIR", Value: "/var/fdb/crash-dumps"}))
+					Expect(mainContainer.Args).NotTo(ContainElement(ContainSubstring("FDB_CRASH_DIR")))
+				})
 			})
 		})
 
@@ -2628,6 +3517,50 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		When("volume claim template selectors are defined", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{fdbv1beta2.ProcessClassGeneral: {
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+						Spec: corev1.PersistentVolumeClaimSpec{
+							StorageClassName: pointer.String("network-ssd"),
+						},
+					},
+					VolumeClaimTemplateSelectors: []fdbv1beta2.VolumeClaimTemplateSelector{
+						{
+							Name:         "zone-a",
+							NodeSelector: map[string]string{"topology.kubernetes.io/zone": "a"},
+							VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+								Spec: corev1.PersistentVolumeClaimSpec{
+									StorageClassName: pointer.String("local-nvme"),
+								},
+							},
+						},
+						{
+							Name:         "zone-b",
+							NodeSelector: map[string]string{"topology.kubernetes.io/zone": "b"},
+						},
+					},
+				}}
+				cluster.Status.ProcessGroups = []*fdbv1beta2.ProcessGroupStatus{
+					{ProcessGroupID: "storage-1", ProcessClass: fdbv1beta2.ProcessClassStorage, VolumeClaimTemplateSelector: "zone-b"},
+					{ProcessGroupID: "storage-2", ProcessClass: fdbv1beta2.ProcessClassStorage, VolumeClaimTemplateSelector: "zone-a"},
+				}
+			})
+
+			It("should use the volume claim template of the entry", func() {
+				pvc, err = GetPvc(cluster, fdbv1beta2.ProcessClassStorage, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pvc.Name).To(Equal(fmt.Sprintf("%s-storage-2-data", cluster.Name)))
+				Expect(pvc.Spec.StorageClassName).To(Equal(pointer.String("local-nvme")))
+			})
+
+			It("should fall back to the volume claim template of the process class", func() {
+				pvc, err = GetPvc(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pvc.Spec.StorageClassName).To(Equal(pointer.String("network-ssd")))
+			})
+		})
+
 		Context("with a custom storage size", func() {
 			BeforeEach(func() {
 				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{fdbv1beta2.ProcessClassGeneral: {VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
@@ -2793,6 +3726,60 @@ var _ = Describe("pod_models", func() {
 		})
 	})
 
+	Describe("GetExternalAccessService", func() {
+		var service *corev1.Service
+
+		BeforeEach(func() {
+			cluster.Spec.Routing.ExternalAccess = &fdbv1beta2.ExternalAccessConfig{
+				Enabled: pointer.Bool(true),
+				Annotations: map[string]string{
+					"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			service = GetExternalAccessService(cluster, "storage-1", 4501)
+		})
+
+		It("should set the metadata on the service", func() {
+			Expect(service.ObjectMeta.Namespace).To(Equal("my-ns"))
+			Expect(service.ObjectMeta.Name).To(Equal("operator-test-1-external-storage-1"))
+			Expect(service.ObjectMeta.Labels).To(Equal(map[string]string{
+				fdbv1beta2.FDBClusterLabel:     "operator-test-1",
+				fdbv1beta2.ExternalAccessLabel: "storage-1",
+			}))
+			Expect(service.ObjectMeta.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+			Expect(service.ObjectMeta.OwnerReferences).To(HaveLen(1))
+		})
+
+		It("should select the Pod of the process group", func() {
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+			Expect(service.Spec.Selector).To(Equal(map[string]string{
+				fdbv1beta2.FDBClusterLabel:        "operator-test-1",
+				fdbv1beta2.FDBProcessGroupIDLabel: "storage-1",
+			}))
+			Expect(service.Spec.Ports).To(Equal([]corev1.ServicePort{
+				{
+					Name:       "coordinator",
+					Port:       4501,
+					TargetPort: intstr.FromInt(4501),
+				},
+			}))
+		})
+
+		When("node ports are used", func() {
+			BeforeEach(func() {
+				serviceType := corev1.ServiceTypeNodePort
+				cluster.Spec.Routing.ExternalAccess.ServiceType = &serviceType
+			})
+
+			It("should create a node port service", func() {
+				Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			})
+		})
+	})
+
 	Describe("GetHeadlessService", func() {
 		var service *corev1.Service
 		var enabled = true
@@ -3082,6 +4069,78 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		When("the backup spec was normalized with default image configs", func() {
+			BeforeEach(func() {
+				NormalizeBackupSpec(backup, DeprecationOptions{
+					DefaultMainContainerImageConfigs: []fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb"}},
+				})
+				deployment, err = GetBackupDeployment(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should use the default image configs for the backup agents", func() {
+				Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("registry.example/foundationdb:%s", cluster.Spec.Version)))
+			})
+		})
+
+		When("the backup image configs define images for a single architecture", func() {
+			BeforeEach(func() {
+				backup.Spec.MainContainer.ImageConfigs = []fdbv1beta2.ImageConfig{
+					{Architecture: "arm64", TagSuffix: "-arm64"},
+					{BaseImage: "foundationdb/foundationdb"},
+				}
+				deployment, err = GetBackupDeployment(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should schedule the backup agents on nodes of the architecture and use the image for the architecture", func() {
+				Expect(deployment.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue(corev1.LabelArchStable, "arm64"))
+				Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s-arm64", cluster.Spec.Version)))
+			})
+		})
+
+		When("the backup image configs define images for multiple architectures", func() {
+			BeforeEach(func() {
+				backup.Spec.MainContainer.ImageConfigs = []fdbv1beta2.ImageConfig{
+					{Architecture: "arm64", TagSuffix: "-arm64"},
+					{Architecture: "amd64", TagSuffix: "-amd64"},
+				}
+			})
+
+			It("should return an error", func() {
+				_, err = GetBackupDeployment(backup)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the backup spec has no image configs", func() {
+			BeforeEach(func() {
+				deployment, err = GetBackupDeployment(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should not modify the backup spec", func() {
+				Expect(backup.Spec.MainContainer.ImageConfigs).To(BeEmpty())
+				Expect(backup.Spec.SidecarContainer.ImageConfigs).To(BeEmpty())
+			})
+		})
+
+		When("a priority class is defined", func() {
+			BeforeEach(func() {
+				backup.Spec.PriorityClassName = "fdb-backup"
+				deployment, err = GetBackupDeployment(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should set the priority class for the backup agents", func() {
+				Expect(deployment.Spec.Template.Spec.PriorityClassName).To(Equal("fdb-backup"))
+			})
+		})
+
 		Context("with a custom label", func() {
 			BeforeEach(func() {
 				backup.Spec.BackupDeploymentMetadata = &metav1.ObjectMeta{
@@ -3227,6 +4286,105 @@ var _ = Describe("pod_models", func() {
 				Expect(deployment.Spec.Template.Spec.InitContainers[0].Image).To(Equal("test:sidecar"))
 			})
 		})
+
+		Context("with a workload identity for AWS", func() {
+			BeforeEach(func() {
+				backup.Spec.BlobStoreConfiguration.WorkloadIdentity = &fdbv1beta2.WorkloadIdentityConfiguration{
+					Provider: fdbv1beta2.WorkloadIdentityProviderAWS,
+					Role:     "arn:aws:iam::123456789012:role/fdb-backup",
+				}
+				deployment, err = GetBackupDeployment(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should use the service account of the backup agents", func() {
+				Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal("operator-test-1-backup-agents"))
+			})
+
+			It("should mount the projected token", func() {
+				volume := deployment.Spec.Template.Spec.Volumes[len(deployment.Spec.Template.Spec.Volumes)-1]
+				Expect(volume.Name).To(Equal(fdbv1beta2.WorkloadIdentityTokenVolumeName))
+				Expect(volume.Projected).NotTo(BeNil())
+				Expect(volume.Projected.Sources[0].ServiceAccountToken.Audience).To(Equal("sts.amazonaws.com"))
+				Expect(*volume.Projected.Sources[0].ServiceAccountToken.ExpirationSeconds).To(Equal(int64(3600)))
+
+				Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name:      fdbv1beta2.WorkloadIdentityTokenVolumeName,
+					MountPath: fdbv1beta2.WorkloadIdentityTokenPath,
+					ReadOnly:  true,
+				}))
+			})
+
+			It("should set the environment variables for the AWS SDK", func() {
+				Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+					corev1.EnvVar{Name: "AWS_ROLE_ARN", Value: "arn:aws:iam::123456789012:role/fdb-backup"},
+					corev1.EnvVar{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: fdbv1beta2.WorkloadIdentityTokenPath + "/token"},
+				))
+			})
+		})
+
+		Context("with a workload identity for Azure", func() {
+			BeforeEach(func() {
+				backup.Spec.BlobStoreConfiguration.WorkloadIdentity = &fdbv1beta2.WorkloadIdentityConfiguration{
+					Provider:           fdbv1beta2.WorkloadIdentityProviderAzure,
+					ServiceAccountName: "fdb-backup",
+					Role:               "client-id",
+					TenantID:           "tenant-id",
+				}
+				deployment, err = GetBackupDeployment(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should use the provided service account", func() {
+				Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal("fdb-backup"))
+			})
+
+			It("should configure the Azure workload identity", func() {
+				Expect(deployment.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("azure.workload.identity/use", "true"))
+				Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+					corev1.EnvVar{Name: "AZURE_CLIENT_ID", Value: "client-id"},
+					corev1.EnvVar{Name: "AZURE_TENANT_ID", Value: "tenant-id"},
+					corev1.EnvVar{Name: "AZURE_FEDERATED_TOKEN_FILE", Value: fdbv1beta2.WorkloadIdentityTokenPath + "/token"},
+				))
+				volume := deployment.Spec.Template.Spec.Volumes[len(deployment.Spec.Template.Spec.Volumes)-1]
+				Expect(volume.Projected.Sources[0].ServiceAccountToken.Audience).To(Equal("api://AzureADTokenExchange"))
+			})
+		})
+
+		Context("with a workload identity for GCP", func() {
+			BeforeEach(func() {
+				backup.Spec.BlobStoreConfiguration.WorkloadIdentity = &fdbv1beta2.WorkloadIdentityConfiguration{
+					Provider: fdbv1beta2.WorkloadIdentityProviderGCP,
+					Role:     "fdb-backup@project.iam.gserviceaccount.com",
+				}
+				deployment, err = GetBackupDeployment(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should only set the service account", func() {
+				Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal("operator-test-1-backup-agents"))
+				for _, volume := range deployment.Spec.Template.Spec.Volumes {
+					Expect(volume.Name).NotTo(Equal(fdbv1beta2.WorkloadIdentityTokenVolumeName))
+				}
+			})
+		})
+
+		Context("with an invalid workload identity", func() {
+			BeforeEach(func() {
+				backup.Spec.BlobStoreConfiguration.WorkloadIdentity = &fdbv1beta2.WorkloadIdentityConfiguration{
+					Provider: fdbv1beta2.WorkloadIdentityProviderAzure,
+					Role:     "client-id",
+				}
+			})
+
+			It("should return an error", func() {
+				_, err = GetBackupDeployment(backup)
+				Expect(err).To(HaveOccurred())
+			})
+		})
 	})
 
 	Context("Get image for container", func() {
@@ -3285,7 +4443,7 @@ var _ = Describe("pod_models", func() {
 
 			DescribeTable("should return the correct image",
 				func(input testCase, expected string) {
-					err = configureSidecarContainerForCluster(cluster, "operator-test-storage-1", input.container, input.initMode, input.processGroupID)
+					err = configureSidecarContainerForCluster(cluster, fdbv1beta2.ProcessClassStorage, "operator-test-storage-1", input.container, input.initMode, input.processGroupID)
 					if input.hasError {
 						Expect(err).To(HaveOccurred())
 					} else {
@@ -3407,7 +4565,15 @@ var _ = Describe("pod_models", func() {
 	Describe("GetPodDNSName", func() {
 		It("builds the DNS name based on the cluster spec", func() {
 			cluster.Spec.Routing.DNSDomain = pointer.String("cluster.example")
-			Expect(GetPodDNSName(cluster, "operator-test-storage-1")).To(Equal("operator-test-storage-1.operator-test-1.my-ns.svc.cluster.example"))
+			Expect(GetPodDNSName(cluster, fdbv1beta2.ProcessClassStorage, "operator-test-storage-1")).To(Equal("operator-test-storage-1.operator-test-1.my-ns.svc.cluster.example"))
+		})
+
+		It("uses the subdomain of the process class", func() {
+			cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+				fdbv1beta2.ProcessClassStorage: {DNS: &fdbv1beta2.PodDNSSettings{Subdomain: pointer.String("storage-dns")}},
+			}
+			Expect(GetPodDNSName(cluster, fdbv1beta2.ProcessClassStorage, "operator-test-storage-1")).To(Equal("operator-test-storage-1.storage-dns.my-ns.svc.cluster.local"))
+			Expect(GetPodDNSName(cluster, fdbv1beta2.ProcessClassLog, "operator-test-log-1")).To(Equal("operator-test-log-1.operator-test-1.my-ns.svc.cluster.local"))
 		})
 	})
 
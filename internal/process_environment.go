@@ -0,0 +1,153 @@
+/*
+ * process_environment.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"regexp"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// ProcessEnvironmentHashVariable defines the environment variable of the main container that contains the hash of
+// the data in the Secrets and ConfigMaps that are referenced in the environment of the process class. A change of
+// the hash changes the Pod spec, so the Pods will be updated and the processes pick up the new values.
+const ProcessEnvironmentHashVariable = "FDB_PROCESS_ENVIRONMENT_HASH"
+
+// EnvironmentSourceKind defines the kind of object that provides the value of an environment variable.
+type EnvironmentSourceKind string
+
+const (
+	// EnvironmentSourceSecret defines a Secret as the source of an environment variable.
+	EnvironmentSourceSecret EnvironmentSourceKind = "Secret"
+
+	// EnvironmentSourceConfigMap defines a ConfigMap as the source of an environment variable.
+	EnvironmentSourceConfigMap EnvironmentSourceKind = "ConfigMap"
+)
+
+// EnvironmentSource describes a Secret or ConfigMap that is referenced in the environment of a process class.
+type EnvironmentSource struct {
+	// Kind defines if the source is a Secret or a ConfigMap.
+	Kind EnvironmentSourceKind
+
+	// Name defines the name of the Secret or ConfigMap.
+	Name string
+
+	// Key defines the key that is referenced. If the key is empty, all keys are referenced.
+	Key string
+
+	// Optional defines if the source is allowed to be missing.
+	Optional bool
+}
+
+// parameterVariableReference matches a reference to an environment variable in a parameter.
+var parameterVariableReference = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// GetProcessEnvironmentSources returns the Secrets and ConfigMaps that are referenced in the env and envFrom settings
+// of the process settings.
+func GetProcessEnvironmentSources(settings fdbv1beta2.ProcessSettings) []EnvironmentSource {
+	sources := make([]EnvironmentSource, 0, len(settings.Env)+len(settings.EnvFrom))
+
+	for _, env := range settings.Env {
+		if env.ValueFrom == nil {
+			continue
+		}
+
+		if env.ValueFrom.SecretKeyRef != nil {
+			sources = append(sources, EnvironmentSource{
+				Kind:     EnvironmentSourceSecret,
+				Name:     env.ValueFrom.SecretKeyRef.Name,
+				Key:      env.ValueFrom.SecretKeyRef.Key,
+				Optional: pointer.BoolDeref(env.ValueFrom.SecretKeyRef.Optional, false),
+			})
+		}
+
+		if env.ValueFrom.ConfigMapKeyRef != nil {
+			sources = append(sources, EnvironmentSource{
+				Kind:     EnvironmentSourceConfigMap,
+				Name:     env.ValueFrom.ConfigMapKeyRef.Name,
+				Key:      env.ValueFrom.ConfigMapKeyRef.Key,
+				Optional: pointer.BoolDeref(env.ValueFrom.ConfigMapKeyRef.Optional, false),
+			})
+		}
+	}
+
+	for _, envFrom := range settings.EnvFrom {
+		if envFrom.SecretRef != nil {
+			sources = append(sources, EnvironmentSource{
+				Kind:     EnvironmentSourceSecret,
+				Name:     envFrom.SecretRef.Name,
+				Optional: pointer.BoolDeref(envFrom.SecretRef.Optional, false),
+			})
+		}
+
+		if envFrom.ConfigMapRef != nil {
+			sources = append(sources, EnvironmentSource{
+				Kind:     EnvironmentSourceConfigMap,
+				Name:     envFrom.ConfigMapRef.Name,
+				Optional: pointer.BoolDeref(envFrom.ConfigMapRef.Optional, false),
+			})
+		}
+	}
+
+	return sources
+}
+
+// buildParameterArgument builds the argument for a parameter. References to the provided environment variables are
+// converted into environment arguments, so they are filled in when the process is started.
+func buildParameterArgument(argument string, variables []corev1.EnvVar) monitorapi.Argument {
+	if len(variables) == 0 {
+		return monitorapi.Argument{Value: argument}
+	}
+
+	names := make(map[string]fdbv1beta2.None, len(variables))
+	for _, variable := range variables {
+		names[variable.Name] = fdbv1beta2.None{}
+	}
+
+	values := make([]monitorapi.Argument, 0)
+	start := 0
+	for _, match := range parameterVariableReference.FindAllStringSubmatchIndex(argument, -1) {
+		name := argument[match[2]:match[3]]
+		if _, ok := names[name]; !ok {
+			continue
+		}
+
+		if match[0] > start {
+			values = append(values, monitorapi.Argument{Value: argument[start:match[0]]})
+		}
+
+		values = append(values, monitorapi.Argument{ArgumentType: monitorapi.EnvironmentArgumentType, Source: name})
+		start = match[1]
+	}
+
+	if len(values) == 0 {
+		return monitorapi.Argument{Value: argument}
+	}
+
+	if start < len(argument) {
+		values = append(values, monitorapi.Argument{Value: argument[start:]})
+	}
+
+	return monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: values}
+}
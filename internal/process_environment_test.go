@@ -0,0 +1,97 @@
+/*
+ * process_environment_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("process_environment", func() {
+	When("getting the environment sources", func() {
+		It("should return the referenced Secrets and ConfigMaps", func() {
+			settings := fdbv1beta2.ProcessSettings{
+				Env: []corev1.EnvVar{
+					{Name: "LITERAL", Value: "value"},
+					{
+						Name: "FDB_TLS_PASSWORD",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "tls-password"},
+								Key:                  "password",
+							},
+						},
+					},
+					{
+						Name: "FIELD",
+						ValueFrom: &corev1.EnvVarSource{
+							FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+						},
+					},
+				},
+				EnvFrom: []corev1.EnvFromSource{
+					{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-settings"},
+							Optional:             pointer.Bool(true),
+						},
+					},
+				},
+			}
+
+			Expect(GetProcessEnvironmentSources(settings)).To(ConsistOf(
+				EnvironmentSource{Kind: EnvironmentSourceSecret, Name: "tls-password", Key: "password"},
+				EnvironmentSource{Kind: EnvironmentSourceConfigMap, Name: "proxy-settings", Optional: true},
+			))
+		})
+	})
+
+	DescribeTable("building the argument for a parameter", func(argument string, expected monitorapi.Argument) {
+		Expect(buildParameterArgument(argument, []corev1.EnvVar{{Name: "KNOB_VALUE"}, {Name: "KNOB"}})).To(Equal(expected))
+	},
+		Entry("without references",
+			"--knob_test=1",
+			monitorapi.Argument{Value: "--knob_test=1"}),
+		Entry("with a reference to an unknown variable",
+			"--knob_test=$OTHER",
+			monitorapi.Argument{Value: "--knob_test=$OTHER"}),
+		Entry("with a reference to a variable",
+			"--knob_test=$KNOB_VALUE",
+			monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+				{Value: "--knob_test="},
+				{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "KNOB_VALUE"},
+			}}),
+		Entry("with multiple references",
+			"--knob_test=$KNOB:$KNOB_VALUE.",
+			monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+				{Value: "--knob_test="},
+				{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "KNOB"},
+				{Value: ":"},
+				{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "KNOB_VALUE"},
+				{Value: "."},
+			}}),
+	)
+})
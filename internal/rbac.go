@@ -0,0 +1,420 @@
+/*
+ * rbac.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// fdbAPIGroup is the API group of the custom resources of the operator.
+	fdbAPIGroup = "apps.foundationdb.org"
+	// rbacAPIGroup is the API group of the RBAC resources.
+	rbacAPIGroup = "rbac.authorization.k8s.io"
+)
+
+var (
+	// readVerbs are the verbs for resources that the operator only reads through its cache.
+	readVerbs = []string{"get", "list", "watch"}
+	// manageVerbs are the verbs for resources that the operator creates and manages.
+	manageVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+	// statusVerbs are the verbs for the status subresources of the custom resources.
+	statusVerbs = []string{"get", "update", "patch"}
+)
+
+// RBACOptions defines for which namespaces and features of the operator the RBAC rules are generated.
+type RBACOptions struct {
+	// Name is the name of the generated roles and role bindings.
+	Name string
+	// OperatorNamespace is the namespace of the operator Deployment and its service account.
+	OperatorNamespace string
+	// ServiceAccountName is the name of the service account of the operator.
+	ServiceAccountName string
+	// WatchNamespaces are the namespaces that are watched by the operator. If empty, the operator watches all
+	// namespaces and the namespaced rules are granted cluster-wide.
+	WatchNamespaces []string
+	// EnableLeaderElection adds the rules for the leader election in the operator namespace.
+	EnableLeaderElection bool
+	// EnableBackups adds the rules for the backup and restore controllers.
+	EnableBackups bool
+	// EnableTestScenarios adds the rules for the test scenario controller.
+	EnableTestScenarios bool
+	// EnableClientLibraryCaches adds the rules for the client library cache controller.
+	EnableClientLibraryCaches bool
+	// EnableOperatorConfigs adds the cluster-wide rule for reading the FoundationDBOperatorConfigs.
+	EnableOperatorConfigs bool
+	// EnableCertManager adds the rules for the cert-manager Certificates of the clusters.
+	EnableCertManager bool
+}
+
+// GetNamespacedRBACRules returns the rules the operator requires in every watched namespace.
+func GetNamespacedRBACRules(options RBACOptions) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps", "persistentvolumeclaims", "pods", "services"},
+			Verbs:     manageVerbs,
+		},
+		{
+			// The operator only reads the secrets that are referenced by the clusters and manages the secrets for
+			// the authorization public keys, so secrets are never patched. The operator never executes commands in
+			// Pods, so pods/exec is not granted.
+			APIGroups: []string{""},
+			Resources: []string{"secrets"},
+			Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"create", "patch"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods/log"},
+			Verbs:     []string{"get"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"resourcequotas"},
+			Verbs:     readVerbs,
+		},
+		{
+			APIGroups: []string{fdbAPIGroup},
+			Resources: []string{"foundationdbclusters", "foundationdbclusterstatusreports"},
+			Verbs:     manageVerbs,
+		},
+		{
+			APIGroups: []string{fdbAPIGroup},
+			Resources: []string{"foundationdbclusters/status"},
+			Verbs:     statusVerbs,
+		},
+	}
+
+	if options.EnableBackups {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				APIGroups: []string{fdbAPIGroup},
+				Resources: []string{"foundationdbbackups", "foundationdbrestores"},
+				Verbs:     manageVerbs,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{fdbAPIGroup},
+				Resources: []string{"foundationdbbackups/status", "foundationdbrestores/status"},
+				Verbs:     statusVerbs,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments"},
+				Verbs:     manageVerbs,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{"serviceaccounts"},
+				Verbs:     []string{"get", "list", "watch", "create", "update"},
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{"snapshot.storage.k8s.io"},
+				Resources: []string{"volumesnapshots"},
+				Verbs:     []string{"get", "list", "watch", "create"},
+			},
+		)
+	}
+
+	if options.EnableTestScenarios {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				APIGroups: []string{fdbAPIGroup},
+				Resources: []string{"foundationdbtestscenarios"},
+				Verbs:     manageVerbs,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{fdbAPIGroup},
+				Resources: []string{"foundationdbtestscenarios/status"},
+				Verbs:     statusVerbs,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{"networking.k8s.io"},
+				Resources: []string{"networkpolicies"},
+				Verbs:     []string{"get", "list", "watch", "create", "delete"},
+			},
+		)
+	}
+
+	if options.EnableClientLibraryCaches {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				APIGroups: []string{fdbAPIGroup},
+				Resources: []string{"foundationdbclientlibrarycaches"},
+				Verbs:     manageVerbs,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{fdbAPIGroup},
+				Resources: []string{"foundationdbclientlibrarycaches/status"},
+				Verbs:     statusVerbs,
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{"apps"},
+				Resources: []string{"daemonsets"},
+				Verbs:     manageVerbs,
+			},
+		)
+	}
+
+	if options.EnableCertManager {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{"cert-manager.io"},
+			Resources: []string{"certificates"},
+			Verbs:     manageVerbs,
+		})
+	}
+
+	return rules
+}
+
+// GetClusterRBACRules returns the rules for cluster-scoped resources that the operator requires independent of the
+// watched namespaces.
+func GetClusterRBACRules(options RBACOptions) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{
+		{
+			// The nodes are read for the taint based replacements and the pod deletion protection.
+			APIGroups: []string{""},
+			Resources: []string{"nodes"},
+			Verbs:     readVerbs,
+		},
+	}
+
+	if options.EnableOperatorConfigs {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{fdbAPIGroup},
+			Resources: []string{"foundationdboperatorconfigs"},
+			Verbs:     readVerbs,
+		})
+	}
+
+	return rules
+}
+
+// GetLeaderElectionRBACRules returns the rules the operator requires in its own namespace for the leader election.
+func GetLeaderElectionRBACRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     manageVerbs,
+		},
+		{
+			APIGroups: []string{"coordination.k8s.io"},
+			Resources: []string{"leases"},
+			Verbs:     manageVerbs,
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"create", "patch"},
+		},
+	}
+}
+
+// GetRBACManifests returns the ClusterRole, Roles and bindings with the minimal permissions for the options. The
+// namespaced rules are only granted in the watched namespaces, a ClusterRole is only used for cluster-scoped
+// resources and for the namespaced rules if the operator watches all namespaces.
+func GetRBACManifests(options RBACOptions) []client.Object {
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      options.ServiceAccountName,
+			Namespace: options.OperatorNamespace,
+		},
+	}
+
+	clusterRules := GetClusterRBACRules(options)
+	if len(options.WatchNamespaces) == 0 {
+		clusterRules = append(GetNamespacedRBACRules(options), clusterRules...)
+	}
+
+	manifests := []client.Object{
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: options.Name},
+			Rules:      clusterRules,
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: options.Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacAPIGroup, Kind: "ClusterRole", Name: options.Name},
+			Subjects:   subjects,
+		},
+	}
+
+	namespaces := append([]string{}, options.WatchNamespaces...)
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		manifests = append(manifests, getRBACRoleManifests(options.Name, namespace, GetNamespacedRBACRules(options), subjects)...)
+	}
+
+	if options.EnableLeaderElection {
+		manifests = append(manifests, getRBACRoleManifests(options.Name+"-leader-election", options.OperatorNamespace, GetLeaderElectionRBACRules(), subjects)...)
+	}
+
+	return manifests
+}
+
+// getRBACRoleManifests returns a Role with the rules and the RoleBinding for the subjects in the namespace.
+func getRBACRoleManifests(name string, namespace string, rules []rbacv1.PolicyRule, subjects []rbacv1.Subject) []client.Object {
+	return []client.Object{
+		&rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Rules:      rules,
+		},
+		&rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacAPIGroup, Kind: "Role", Name: name},
+			Subjects:   subjects,
+		},
+	}
+}
+
+// CheckRBACPermissions checks the permissions of the operator against the rules for the options. The first returned
+// list contains the permissions that are missing for cluster-scoped resources and in the watched namespaces, or
+// cluster-wide if all namespaces are watched. The second list contains the permissions for pods/exec and secrets that
+// are granted cluster-wide, even though the operator only watches some namespaces.
+func CheckRBACPermissions(ctx context.Context, kubeClient client.Client, options RBACOptions) ([]string, []string, error) {
+	var missing []string
+
+	namespaces := options.WatchNamespaces
+	if len(namespaces) == 0 {
+		// A rules review requires a namespace, the rules of cluster roles are included in every namespace.
+		namespaces = []string{metav1.NamespaceDefault}
+	}
+
+	for idx, namespace := range namespaces {
+		review := &authorizationv1.SelfSubjectRulesReview{
+			Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+		}
+
+		err := kubeClient.Create(ctx, review)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The rules for cluster-scoped resources are the same in every namespace, so they are only checked once.
+		if idx == 0 {
+			for _, rule := range GetClusterRBACRules(options) {
+				missing = append(missing, getMissingPermissions(rule, review.Status.ResourceRules)...)
+			}
+		}
+
+		for _, rule := range GetNamespacedRBACRules(options) {
+			for _, permission := range getMissingPermissions(rule, review.Status.ResourceRules) {
+				missing = append(missing, fmt.Sprintf("%s in namespace %s", permission, namespace))
+			}
+		}
+	}
+
+	if len(options.WatchNamespaces) == 0 {
+		return missing, nil, nil
+	}
+
+	var excessive []string
+	for _, attributes := range []authorizationv1.ResourceAttributes{
+		{Verb: "create", Resource: "pods", Subresource: "exec"},
+		{Verb: "get", Resource: "secrets"},
+	} {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes.DeepCopy()},
+		}
+
+		err := kubeClient.Create(ctx, review)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if review.Status.Allowed {
+			excessive = append(excessive, getPermissionName(attributes.Verb, attributes.Group, strings.TrimSuffix(attributes.Resource+"/"+attributes.Subresource, "/")))
+		}
+	}
+
+	return missing, excessive, nil
+}
+
+// getMissingPermissions returns the permissions of the rule that are not granted by any of the resource rules.
+func getMissingPermissions(rule rbacv1.PolicyRule, resourceRules []authorizationv1.ResourceRule) []string {
+	var missing []string
+
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			for _, verb := range rule.Verbs {
+				if !isPermissionGranted(resourceRules, verb, group, resource) {
+					missing = append(missing, getPermissionName(verb, group, resource))
+				}
+			}
+		}
+	}
+
+	return missing
+}
+
+// isPermissionGranted returns true if any of the resource rules grants the verb on all resources of the resource type.
+func isPermissionGranted(resourceRules []authorizationv1.ResourceRule, verb string, group string, resource string) bool {
+	for _, resourceRule := range resourceRules {
+		// Rules that are restricted to some resources by name are not sufficient for the operator, as it lists and
+		// watches the resources.
+		if len(resourceRule.ResourceNames) > 0 {
+			continue
+		}
+
+		if ruleContains(resourceRule.Verbs, verb) && ruleContains(resourceRule.APIGroups, group) && ruleContains(resourceRule.Resources, resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ruleContains returns true if the values of a rule contain the value or the wildcard.
+func ruleContains(values []string, value string) bool {
+	for _, current := range values {
+		if current == value || current == rbacv1.ResourceAll {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPermissionName returns the human-readable name of a permission.
+func getPermissionName(verb string, group string, resource string) string {
+	if group == "" {
+		return fmt.Sprintf("%s %s", verb, resource)
+	}
+
+	return fmt.Sprintf("%s %s.%s", verb, resource, group)
+}
@@ -0,0 +1,269 @@
+/*
+ * rbac_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// reviewClient answers the access reviews of the operator with the defined rules.
+type reviewClient struct {
+	client.Client
+	resourceRules map[string][]authorizationv1.ResourceRule
+	allowed       bool
+}
+
+// Create fills the status of the access reviews.
+func (c *reviewClient) Create(_ context.Context, object client.Object, _ ...client.CreateOption) error {
+	switch review := object.(type) {
+	case *authorizationv1.SelfSubjectRulesReview:
+		review.Status.ResourceRules = c.resourceRules[review.Spec.Namespace]
+	case *authorizationv1.SelfSubjectAccessReview:
+		review.Status.Allowed = c.allowed
+	}
+
+	return nil
+}
+
+var _ = Describe("rbac", func() {
+	var options RBACOptions
+
+	BeforeEach(func() {
+		options = RBACOptions{
+			Name:                 "fdb-operator",
+			OperatorNamespace:    "fdb-system",
+			ServiceAccountName:   "fdb-operator",
+			WatchNamespaces:      []string{"team-b", "team-a"},
+			EnableLeaderElection: true,
+		}
+	})
+
+	When("getting the namespaced rules", func() {
+		It("should not grant pods/exec", func() {
+			for _, rule := range GetNamespacedRBACRules(options) {
+				Expect(rule.Resources).NotTo(ContainElement("pods/exec"))
+				Expect(rule.Verbs).NotTo(ContainElement(rbacv1.VerbAll))
+			}
+		})
+
+		It("should only grant the rules of the enabled features", func() {
+			var resources []string
+			for _, rule := range GetNamespacedRBACRules(options) {
+				resources = append(resources, rule.Resources...)
+			}
+
+			Expect(resources).NotTo(ContainElements("foundationdbbackups", "deployments", "networkpolicies", "daemonsets", "certificates"))
+
+			options.EnableBackups = true
+			options.EnableCertManager = true
+			resources = nil
+			for _, rule := range GetNamespacedRBACRules(options) {
+				resources = append(resources, rule.Resources...)
+			}
+
+			Expect(resources).To(ContainElements("foundationdbbackups", "deployments", "certificates"))
+			Expect(resources).NotTo(ContainElements("networkpolicies", "daemonsets"))
+		})
+	})
+
+	When("comparing the rules with the role that is generated from the kubebuilder markers", func() {
+		// getPermissions returns every permission of the rules in the format <verb> <group>/<resource>.
+		getPermissions := func(rules ...rbacv1.PolicyRule) map[string]bool {
+			permissions := map[string]bool{}
+			for _, rule := range rules {
+				for _, group := range rule.APIGroups {
+					for _, resource := range rule.Resources {
+						for _, verb := range rule.Verbs {
+							permissions[getPermissionName(verb, group, resource)] = true
+						}
+					}
+				}
+			}
+
+			return permissions
+		}
+
+		It("should grant the same permissions if all features are enabled", func() {
+			content, err := os.ReadFile(filepath.Join("..", "config", "rbac", "role.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+
+			role := &rbacv1.ClusterRole{}
+			Expect(yaml.Unmarshal(content, role)).To(Succeed())
+
+			options.EnableBackups = true
+			options.EnableTestScenarios = true
+			options.EnableClientLibraryCaches = true
+			options.EnableOperatorConfigs = true
+			options.EnableCertManager = true
+
+			var rules []rbacv1.PolicyRule
+			rules = append(rules, GetNamespacedRBACRules(options)...)
+			rules = append(rules, GetClusterRBACRules(options)...)
+			rules = append(rules, GetLeaderElectionRBACRules()...)
+
+			Expect(getPermissions(rules...)).To(Equal(getPermissions(role.Rules...)))
+		})
+	})
+
+	When("getting the manifests", func() {
+		var manifests []client.Object
+
+		JustBeforeEach(func() {
+			manifests = GetRBACManifests(options)
+		})
+
+		When("the operator watches some namespaces", func() {
+			It("should create a Role for every watched namespace", func() {
+				Expect(manifests).To(HaveLen(8))
+
+				clusterRole, ok := manifests[0].(*rbacv1.ClusterRole)
+				Expect(ok).To(BeTrue())
+				Expect(clusterRole.Rules).To(Equal(GetClusterRBACRules(options)))
+
+				role, ok := manifests[2].(*rbacv1.Role)
+				Expect(ok).To(BeTrue())
+				Expect(role.Namespace).To(Equal("team-a"))
+				Expect(role.Rules).To(Equal(GetNamespacedRBACRules(options)))
+
+				binding, ok := manifests[5].(*rbacv1.RoleBinding)
+				Expect(ok).To(BeTrue())
+				Expect(binding.Namespace).To(Equal("team-b"))
+				Expect(binding.RoleRef.Name).To(Equal("fdb-operator"))
+				Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "fdb-operator", Namespace: "fdb-system"}))
+
+				leaderElection, ok := manifests[6].(*rbacv1.Role)
+				Expect(ok).To(BeTrue())
+				Expect(leaderElection.Name).To(Equal("fdb-operator-leader-election"))
+				Expect(leaderElection.Namespace).To(Equal("fdb-system"))
+			})
+		})
+
+		When("the operator watches all namespaces", func() {
+			BeforeEach(func() {
+				options.WatchNamespaces = nil
+				options.EnableLeaderElection = false
+			})
+
+			It("should grant the namespaced rules in the ClusterRole", func() {
+				Expect(manifests).To(HaveLen(2))
+				clusterRole, ok := manifests[0].(*rbacv1.ClusterRole)
+				Expect(ok).To(BeTrue())
+				Expect(clusterRole.Rules).To(Equal(append(GetNamespacedRBACRules(options), GetClusterRBACRules(options)...)))
+			})
+		})
+	})
+
+	When("checking the permissions", func() {
+		var kubeClient *reviewClient
+		var missing, excessive []string
+		var err error
+
+		BeforeEach(func() {
+			kubeClient = &reviewClient{
+				resourceRules: map[string][]authorizationv1.ResourceRule{},
+			}
+
+			for _, namespace := range options.WatchNamespaces {
+				for _, rule := range append(GetNamespacedRBACRules(options), GetClusterRBACRules(options)...) {
+					kubeClient.resourceRules[namespace] = append(kubeClient.resourceRules[namespace], authorizationv1.ResourceRule{
+						Verbs:     rule.Verbs,
+						APIGroups: rule.APIGroups,
+						Resources: rule.Resources,
+					})
+				}
+			}
+		})
+
+		JustBeforeEach(func() {
+			missing, excessive, err = CheckRBACPermissions(context.TODO(), kubeClient, options)
+		})
+
+		When("all permissions are granted", func() {
+			It("should report no missing or excessive permissions", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(missing).To(BeEmpty())
+				Expect(excessive).To(BeEmpty())
+			})
+		})
+
+		When("a permission is only granted for some resource names", func() {
+			BeforeEach(func() {
+				kubeClient.resourceRules["team-b"] = []authorizationv1.ResourceRule{
+					{
+						Verbs:     []string{rbacv1.VerbAll},
+						APIGroups: []string{rbacv1.APIGroupAll},
+						Resources: []string{rbacv1.ResourceAll},
+					},
+				}
+				kubeClient.resourceRules["team-a"] = []authorizationv1.ResourceRule{
+					{
+						Verbs:         []string{rbacv1.VerbAll},
+						APIGroups:     []string{rbacv1.APIGroupAll},
+						Resources:     []string{rbacv1.ResourceAll},
+						ResourceNames: []string{"sample-cluster"},
+					},
+				}
+			})
+
+			It("should report the missing permissions in the namespace", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(missing).To(ContainElements("list pods in namespace team-a", "get foundationdbclusters.apps.foundationdb.org in namespace team-a"))
+				Expect(missing).NotTo(ContainElement("list nodes"))
+				for _, permission := range missing {
+					Expect(permission).To(HaveSuffix("in namespace team-a"))
+				}
+			})
+		})
+
+		When("the nodes can't be read", func() {
+			BeforeEach(func() {
+				// The cluster-scoped rules are only checked in the first watched namespace.
+				kubeClient.resourceRules["team-b"] = kubeClient.resourceRules["team-b"][:len(kubeClient.resourceRules["team-b"])-1]
+			})
+
+			It("should report the missing permissions for the nodes", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(missing).To(ConsistOf("get nodes", "list nodes", "watch nodes"))
+			})
+		})
+
+		When("pods/exec and secrets are granted cluster-wide", func() {
+			BeforeEach(func() {
+				kubeClient.allowed = true
+			})
+
+			It("should report the excessive permissions", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(missing).To(BeEmpty())
+				Expect(excessive).To(ConsistOf("create pods/exec", "get secrets"))
+			})
+		})
+	})
+})
@@ -0,0 +1,110 @@
+/*
+ * redaction.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"regexp"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// RedactedValue is the value that replaces credentials in logs, events and the status.
+const RedactedValue = "<redacted>"
+
+var (
+	// blobCredentialsPattern matches the secret and the security token in a blobstore URL, e.g.
+	// blobstore://<api_key>:<secret>:<security_token>@<hostname>. The credentials end at the last @ before the
+	// hostname, since secrets can contain characters like / or @.
+	blobCredentialsPattern = regexp.MustCompile(`(blobstore://[^:@/\s"']*):[^\s"']*@`)
+	// tlsPasswordPattern matches the TLS password in command lines and environment variables, e.g.
+	// --tls_password=<password> or FDB_TLS_PASSWORD=<password>.
+	tlsPasswordPattern = regexp.MustCompile(`(?i)(tls_password["']?(?:\s*[=:]\s*|\s+)["']?)[^\s"',}]+`)
+	// jsonCredentialsPattern matches the values of JSON keys that contain credentials, e.g. in a blob credentials file.
+	jsonCredentialsPattern = regexp.MustCompile(`(?i)("(?:secret|security_token|token|password)"\s*:\s*")(?:[^"\\]|\\.)*"`)
+	// sensitiveKeyPattern matches keys, e.g. of the variable substitutions, that might contain credentials.
+	sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|secret|token|credential)`)
+)
+
+// RedactString masks the TLS passwords and blob credentials in the provided value. This method should be used for any
+// status json, connection string or command output that is written to logs, events or the status.
+func RedactString(value string) string {
+	value = blobCredentialsPattern.ReplaceAllString(value, "${1}:"+RedactedValue+"@")
+	value = tlsPasswordPattern.ReplaceAllString(value, "${1}"+RedactedValue)
+
+	return jsonCredentialsPattern.ReplaceAllString(value, "${1}"+RedactedValue+`"`)
+}
+
+// RedactArguments returns a copy of the command line arguments with the TLS passwords and blob credentials masked.
+func RedactArguments(args []string) []string {
+	redacted := make([]string, len(args))
+	for idx, arg := range args {
+		if idx > 0 && strings.EqualFold(strings.TrimLeft(args[idx-1], "-"), "tls_password") {
+			redacted[idx] = RedactedValue
+			continue
+		}
+
+		redacted[idx] = RedactString(arg)
+	}
+
+	return redacted
+}
+
+// RedactSubstitutions returns a copy of the variable substitutions with the values of keys that might contain
+// credentials masked.
+func RedactSubstitutions(substitutions map[string]string) map[string]string {
+	if substitutions == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(substitutions))
+	for key, value := range substitutions {
+		if sensitiveKeyPattern.MatchString(key) {
+			redacted[key] = RedactedValue
+			continue
+		}
+
+		redacted[key] = RedactString(value)
+	}
+
+	return redacted
+}
+
+// RedactStatus returns a copy of the machine-readable status with the TLS passwords and blob credentials masked.
+func RedactStatus(status *fdbv1beta2.FoundationDBStatus) *fdbv1beta2.FoundationDBStatus {
+	if status == nil {
+		return nil
+	}
+
+	redacted := status.DeepCopy()
+	redacted.Cluster.ConnectionString = RedactString(redacted.Cluster.ConnectionString)
+	for processID, process := range redacted.Cluster.Processes {
+		process.CommandLine = RedactString(process.CommandLine)
+		redacted.Cluster.Processes[processID] = process
+	}
+
+	for tag, backup := range redacted.Cluster.Layers.Backup.Tags {
+		backup.CurrentContainer = RedactString(backup.CurrentContainer)
+		redacted.Cluster.Layers.Backup.Tags[tag] = backup
+	}
+
+	return redacted
+}
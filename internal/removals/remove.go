@@ -21,11 +21,14 @@
 package removals
 
 import (
+	"context"
 	"fmt"
 	"net"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/pointer"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
@@ -83,13 +86,45 @@ func GetProcessGroupsToRemove(removalMode fdbv1beta2.PodUpdateMode, removals map
 // If the process group has not an associated process in the cluster status the zone will be UnknownZone.
 // if the process group has the ResourcesTerminating condition the zone will be TerminatingZone.
 func GetZonedRemovals(status *fdbv1beta2.FoundationDBStatus, processGroupsToRemove []*fdbv1beta2.ProcessGroupStatus) (map[string][]fdbv1beta2.ProcessGroupID, int64, error) {
-	var lastestRemovalTimestamp int64
 	// Convert the process list into a map with the process group ID as key.
 	processInfo := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.FoundationDBStatusProcessInfo{}
 	for _, p := range status.Cluster.Processes {
 		processInfo[fdbv1beta2.ProcessGroupID(p.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])] = p
 	}
 
+	return getZonedRemovals(processGroupsToRemove, func(processGroup *fdbv1beta2.ProcessGroupStatus) string {
+		p, ok := processInfo[processGroup.ProcessGroupID]
+		if !ok {
+			return UnknownZone
+		}
+
+		return p.Locality[fdbv1beta2.FDBLocalityZoneIDKey]
+	})
+}
+
+// GetZonedRemovalsFromPods returns a map with the zone as key and a list of process groups IDs to be removed.
+// In contrast to GetZonedRemovals the zone is taken from the Pod of the process group, this allows to group the
+// removals if the database status is not available. If the process group has no Pod the zone will be UnknownZone.
+func GetZonedRemovalsFromPods(cluster *fdbv1beta2.FoundationDBCluster, pods []*corev1.Pod, processGroupsToRemove []*fdbv1beta2.ProcessGroupStatus) (map[string][]fdbv1beta2.ProcessGroupID, int64, error) {
+	podMap := internal.CreatePodMap(cluster, pods)
+
+	return getZonedRemovals(processGroupsToRemove, func(processGroup *fdbv1beta2.ProcessGroupStatus) string {
+		pod, ok := podMap[processGroup.ProcessGroupID]
+		if !ok {
+			return UnknownZone
+		}
+
+		zone, err := internal.GetZoneIDFromPod(cluster, pod)
+		if err != nil || zone == "" {
+			return UnknownZone
+		}
+
+		return zone
+	})
+}
+
+func getZonedRemovals(processGroupsToRemove []*fdbv1beta2.ProcessGroupStatus, getZone func(processGroup *fdbv1beta2.ProcessGroupStatus) string) (map[string][]fdbv1beta2.ProcessGroupID, int64, error) {
+	var lastestRemovalTimestamp int64
 	zoneMap := map[string][]fdbv1beta2.ProcessGroupID{}
 	for _, pg := range processGroupsToRemove {
 		// Using the ResourcesTerminating is not a complete precise measurement of the time when we
@@ -104,13 +139,7 @@ func GetZonedRemovals(status *fdbv1beta2.FoundationDBStatus, processGroupsToRemo
 			continue
 		}
 
-		p, ok := processInfo[pg.ProcessGroupID]
-		if !ok {
-			zoneMap[UnknownZone] = append(zoneMap[UnknownZone], pg.ProcessGroupID)
-			continue
-		}
-
-		zone := p.Locality[fdbv1beta2.FDBLocalityZoneIDKey]
+		zone := getZone(pg)
 		zoneMap[zone] = append(zoneMap[zone], pg.ProcessGroupID)
 	}
 
@@ -118,7 +147,7 @@ func GetZonedRemovals(status *fdbv1beta2.FoundationDBStatus, processGroupsToRemo
 }
 
 // GetRemainingMap returns a map that indicates if a process group is fully excluded in the cluster.
-func GetRemainingMap(logger logr.Logger, adminClient fdbadminclient.AdminClient, cluster *fdbv1beta2.FoundationDBCluster) (map[string]bool, error) {
+func GetRemainingMap(ctx context.Context, logger logr.Logger, adminClient fdbadminclient.AdminClient, cluster *fdbv1beta2.FoundationDBCluster) (map[string]bool, error) {
 	var err error
 	addresses := make([]fdbv1beta2.ProcessAddress, 0, len(cluster.Status.ProcessGroups))
 	for _, processGroup := range cluster.Status.ProcessGroups {
@@ -141,7 +170,7 @@ func GetRemainingMap(logger logr.Logger, adminClient fdbadminclient.AdminClient,
 
 	var remaining []fdbv1beta2.ProcessAddress
 	if len(addresses) > 0 {
-		remaining, err = adminClient.CanSafelyRemove(addresses)
+		remaining, err = adminClient.CanSafelyRemove(ctx, addresses)
 		if err != nil {
 			return map[string]bool{}, err
 		}
@@ -26,6 +26,8 @@ import (
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("remove", func() {
@@ -111,6 +113,62 @@ var _ = Describe("remove", func() {
 			Expect(timestamp).To(BeNumerically("==", 42))
 		})
 
+		When("the zones are taken from the Pods", func() {
+			It("should return the correct mapping", func() {
+				cluster := &fdbv1beta2.FoundationDBCluster{}
+				pods := []*corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								fdbv1beta2.FDBProcessGroupIDLabel: "1",
+							},
+						},
+						Spec: corev1.PodSpec{
+							NodeName: "node1",
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								fdbv1beta2.FDBProcessGroupIDLabel: "2",
+							},
+						},
+						Spec: corev1.PodSpec{
+							NodeName: "node2",
+						},
+					},
+				}
+
+				zones, timestamp, err := GetZonedRemovalsFromPods(cluster, pods, []*fdbv1beta2.ProcessGroupStatus{
+					{
+						ProcessGroupID: "1",
+					},
+					{
+						ProcessGroupID: "2",
+					},
+					{
+						ProcessGroupID: "3",
+					},
+					{
+						ProcessGroupID: "4",
+						ProcessGroupConditions: []*fdbv1beta2.ProcessGroupCondition{
+							{
+								ProcessGroupConditionType: fdbv1beta2.ResourcesTerminating,
+								Timestamp:                 42,
+							},
+						},
+					},
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(HaveLen(4))
+				Expect(zones["node1"]).To(ConsistOf(fdbv1beta2.ProcessGroupID("1")))
+				Expect(zones["node2"]).To(ConsistOf(fdbv1beta2.ProcessGroupID("2")))
+				Expect(zones[UnknownZone]).To(ConsistOf(fdbv1beta2.ProcessGroupID("3")))
+				Expect(zones[TerminatingZone]).To(ConsistOf(fdbv1beta2.ProcessGroupID("4")))
+				Expect(timestamp).To(BeNumerically("==", 42))
+			})
+		})
 	})
 
 	When("getting the process groups to remove", func() {
@@ -21,6 +21,7 @@
 package replacements
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -47,19 +48,44 @@ func getMaxReplacements(cluster *fdbv1beta2.FoundationDBCluster, maxReplacements
 
 // ReplaceFailedProcessGroups flags failed processes groups for removal and returns an indicator
 // of whether any processes were thus flagged.
-func ReplaceFailedProcessGroups(log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient) bool {
+func ReplaceFailedProcessGroups(ctx context.Context, log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient) bool {
 	// Automatic replacements are disabled, so we don't have to check anything further
 	if !cluster.GetEnableAutomaticReplacements() {
 		return false
 	}
 
+	return ReplaceProcessGroups(ctx, log, cluster, adminClient, GetFailedProcessGroups(cluster))
+}
+
+// GetFailedProcessGroups returns the process groups that need a replacement together with the time since when they
+// have failed.
+func GetFailedProcessGroups(cluster *fdbv1beta2.FoundationDBCluster) map[fdbv1beta2.ProcessGroupID]int64 {
+	failed := map[fdbv1beta2.ProcessGroupID]int64{}
+	for _, processGroupStatus := range cluster.Status.ProcessGroups {
+		needsReplacement, missingTime := processGroupStatus.NeedsReplacement(cluster.GetFailureDetectionTimeSeconds(), cluster.GetTaintReplacementTimeSeconds())
+		if needsReplacement {
+			failed[processGroupStatus.ProcessGroupID] = missingTime
+		}
+	}
+
+	return failed
+}
+
+// ReplaceProcessGroups flags the provided failed process groups for removal and returns an indicator of whether any
+// processes were thus flagged. The failed process groups map the process group ID to the time since when the process
+// group has failed.
+func ReplaceProcessGroups(ctx context.Context, log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient, failed map[fdbv1beta2.ProcessGroupID]int64) bool {
+	if len(failed) == 0 {
+		return false
+	}
+
 	maxReplacements := getMaxReplacements(cluster, cluster.GetMaxConcurrentAutomaticReplacements())
 	hasReplacement := false
 	crashLoopContainerProcessGroups := cluster.GetCrashLoopContainerProcessGroups()
 
 	// Only replace process groups without an address if the cluster has the desired fault tolerance
 	// and is available.
-	hasDesiredFaultTolerance, err := internal.HasDesiredFaultTolerance(log, adminClient, cluster)
+	hasDesiredFaultTolerance, err := internal.HasDesiredFaultTolerance(ctx, log, adminClient, cluster)
 	if err != nil {
 		log.Error(err, "Could not fetch if cluster has desired fault tolerance")
 		return false
@@ -82,7 +108,7 @@ ProcessGroupLoop:
 			}
 		}
 
-		needsReplacement, missingTime := processGroupStatus.NeedsReplacement(cluster.GetFailureDetectionTimeSeconds(), cluster.GetTaintReplacementTimeSeconds())
+		missingTime, needsReplacement := failed[processGroupStatus.ProcessGroupID]
 		if !needsReplacement {
 			continue
 		}
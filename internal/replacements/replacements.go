@@ -33,6 +33,7 @@ import (
 )
 
 // ReplaceMisconfiguredProcessGroups checks if the cluster has any misconfigured process groups that must be replaced.
+// It returns true if the status of a process group was changed.
 func ReplaceMisconfiguredProcessGroups(log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, pvcMap map[fdbv1beta2.ProcessGroupID]corev1.PersistentVolumeClaim, podMap map[fdbv1beta2.ProcessGroupID]*corev1.Pod) (bool, error) {
 	hasReplacements := false
 
@@ -50,6 +51,23 @@ func ReplaceMisconfiguredProcessGroups(log logr.Logger, cluster *fdbv1beta2.Foun
 		pvc, hasPVC := pvcMap[processGroup.ProcessGroupID]
 		pod, hasPod := podMap[processGroup.ProcessGroupID]
 
+		// Process groups that were created before the volume claim template selectors of their process class were
+		// defined or whose entry was removed must be replaced, as their volume is not in the fault domain or node pool
+		// of an entry.
+		if cluster.NeedsVolumeClaimTemplateSelector(processGroup) {
+			if hasPod {
+				log.Info("Replace process group", "processGroupID", processGroup.ProcessGroupID, "reason", "no volume claim template selector is assigned")
+				processGroup.MarkForRemoval()
+				hasReplacements = true
+				maxReplacements--
+				continue
+			}
+
+			// Without a Pod the process group can use a new entry without a replacement.
+			processGroup.VolumeClaimTemplateSelector = cluster.ChooseVolumeClaimTemplateSelector(processGroup.ProcessClass)
+			hasReplacements = true
+		}
+
 		if hasPVC {
 			needsPVCRemoval, err := processGroupNeedsRemovalForPVC(cluster, pvc, log)
 			if err != nil {
@@ -196,9 +214,20 @@ func processGroupNeedsRemoval(cluster *fdbv1beta2.FoundationDBCluster, pod *core
 		}
 
 		if pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey] != specHash {
-			logger.Info("Replace process group",
-				"reason", fmt.Sprintf("nodeSelector has changed from %s to %s", pod.Spec.NodeSelector, expectedNodeSelector))
-			return true, nil
+			// Scheduling only changes will be rolled out by recreating the Pod.
+			var schedulingOnly bool
+			if cluster.UseRecreatePodsForSchedulingChanges() {
+				schedulingOnly, err = internal.IsSchedulingOnlyUpdate(cluster, processClass, idNum, pod)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			if !schedulingOnly {
+				logger.Info("Replace process group",
+					"reason", fmt.Sprintf("nodeSelector has changed from %s to %s", pod.Spec.NodeSelector, expectedNodeSelector))
+				return true, nil
+			}
 		}
 	}
 
@@ -209,9 +238,29 @@ func processGroupNeedsRemoval(cluster *fdbv1beta2.FoundationDBCluster, pod *core
 		}
 
 		if pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey] != specHash {
-			logger.Info("Replace process group",
-				"reason", fmt.Sprintf("specHash has changed from %s to %s", specHash, pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]))
-			return true, nil
+			// Resource only changes will be rolled out by resizing the Pod in place.
+			var resourceOnly bool
+			if cluster.UseInPlacePodResize() {
+				resourceOnly, err = internal.IsResourceOnlyUpdate(cluster, processClass, idNum, pod)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			// Scheduling only changes will be rolled out by recreating the Pod.
+			var schedulingOnly bool
+			if !resourceOnly && cluster.UseRecreatePodsForSchedulingChanges() {
+				schedulingOnly, err = internal.IsSchedulingOnlyUpdate(cluster, processClass, idNum, pod)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			if !resourceOnly && !schedulingOnly {
+				logger.Info("Replace process group",
+					"reason", fmt.Sprintf("specHash has changed from %s to %s", specHash, pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]))
+				return true, nil
+			}
 		}
 	}
 
@@ -238,9 +287,19 @@ func processGroupNeedsRemoval(cluster *fdbv1beta2.FoundationDBCluster, pod *core
 }
 
 func resourcesNeedsReplacement(desired []corev1.Container, current []corev1.Container) bool {
+	// Additional containers are not part of the desired containers, so only compare the containers managed by the
+	// operator.
+	managedContainers := make([]corev1.Container, 0, len(desired))
+	for _, container := range desired {
+		currentContainer := internal.GetContainer(current, container.Name)
+		if currentContainer != nil {
+			managedContainers = append(managedContainers, *currentContainer)
+		}
+	}
+
 	// We only care about requests since limits are ignored during scheduling
 	desiredCPURequests, desiredMemoryRequests := getCPUandMemoryRequests(desired)
-	currentCPURequests, currentMemoryRequests := getCPUandMemoryRequests(current)
+	currentCPURequests, currentMemoryRequests := getCPUandMemoryRequests(managedContainers)
 
 	return desiredCPURequests.Cmp(*currentCPURequests) == 1 || desiredMemoryRequests.Cmp(*currentMemoryRequests) == 1
 }
@@ -370,6 +370,54 @@ var _ = Describe("replace_misconfigured_pods", func() {
 			})
 		})
 
+		Context("when in-place resizing is enabled and only the resources of a transaction process have changed", func() {
+			BeforeEach(func() {
+				pClass = fdbv1beta2.ProcessClassLog
+				remove = false
+			})
+
+			It("should only need a removal for other changes", func() {
+				cluster.Spec.AutomationOptions.PodUpdateStrategy = fdbv1beta2.PodUpdateStrategyTransactionReplacement
+				cluster.Spec.AutomationOptions.InPlacePodResize = pointer.Bool(true)
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("2"),
+					},
+				}
+
+				needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+				Expect(needsRemoval).To(BeFalse())
+				Expect(err).NotTo(HaveOccurred())
+
+				cluster.Spec.AutomationOptions.InPlacePodResize = nil
+				needsRemoval, err = processGroupNeedsRemoval(cluster, pod, status, log)
+				Expect(needsRemoval).To(BeTrue())
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when recreating Pods for scheduling changes is enabled and only the nodeSelector of a transaction process has changed", func() {
+			BeforeEach(func() {
+				pClass = fdbv1beta2.ProcessClassLog
+				remove = false
+			})
+
+			It("should only need a removal if the option is disabled", func() {
+				cluster.Spec.AutomationOptions.PodUpdateStrategy = fdbv1beta2.PodUpdateStrategyTransactionReplacement
+				cluster.Spec.AutomationOptions.RecreatePodsForSchedulingChanges = pointer.Bool(true)
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+
+				needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+				Expect(needsRemoval).To(BeFalse())
+				Expect(err).NotTo(HaveOccurred())
+
+				cluster.Spec.AutomationOptions.RecreatePodsForSchedulingChanges = nil
+				needsRemoval, err = processGroupNeedsRemoval(cluster, pod, status, log)
+				Expect(needsRemoval).To(BeTrue())
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
 		When("PVC name doesn't match", func() {
 			It("should need a removal", func() {
 				pvc, err := internal.GetPvc(cluster, fdbv1beta2.ProcessClassStorage, 1)
@@ -703,6 +751,34 @@ var _ = Describe("replace_misconfigured_pods", func() {
 			})
 		})
 
+		When("one process group is pinned", func() {
+			var pinnedID fdbv1beta2.ProcessGroupID
+
+			BeforeEach(func() {
+				_, pinnedID = internal.GetProcessGroupID(cluster, fdbv1beta2.ProcessClassStorage, 0)
+				cluster.Spec.PinnedProcessGroups = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.ProcessGroupPin{
+					pinnedID: {NodeName: "node-1"},
+				}
+			})
+
+			It("should replace the pinned process group as well", func() {
+				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasReplacement).To(BeTrue())
+
+				cntReplacements := 0
+				for _, pGroup := range cluster.Status.ProcessGroups {
+					if !pGroup.IsMarkedForRemoval() {
+						continue
+					}
+
+					cntReplacements++
+				}
+
+				Expect(cntReplacements).To(BeNumerically("==", len(cluster.Status.ProcessGroups)))
+			})
+		})
+
 		When("the image doesn't match with the desired image", func() {
 			BeforeEach(func() {
 				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate.Spec.NodeSelector = map[string]string{}
@@ -745,5 +821,53 @@ var _ = Describe("replace_misconfigured_pods", func() {
 				})
 			})
 		})
+
+		When("volume claim template selectors are defined for the storage processes", func() {
+			var missingPodID fdbv1beta2.ProcessGroupID
+
+			BeforeEach(func() {
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral].PodTemplate.Spec.NodeSelector = map[string]string{}
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage]
+				settings.VolumeClaimTemplateSelectors = []fdbv1beta2.VolumeClaimTemplateSelector{
+					{
+						Name:         "zone-a",
+						NodeSelector: map[string]string{"topology.kubernetes.io/zone": "zone-a"},
+					},
+					{
+						Name:         "zone-b",
+						NodeSelector: map[string]string{"topology.kubernetes.io/zone": "zone-b"},
+					},
+				}
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassStorage] = settings
+
+				_, missingPodID = internal.GetProcessGroupID(cluster, fdbv1beta2.ProcessClassStorage, 0)
+				delete(podMap, missingPodID)
+			})
+
+			It("should replace the storage process groups without a selector that have a Pod", func() {
+				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasReplacement).To(BeTrue())
+
+				cntReplacements := 0
+				for _, pGroup := range cluster.Status.ProcessGroups {
+					if pGroup.ProcessGroupID == missingPodID {
+						Expect(pGroup.IsMarkedForRemoval()).To(BeFalse())
+						Expect(pGroup.VolumeClaimTemplateSelector).To(Equal("zone-a"))
+						continue
+					}
+
+					if !pGroup.IsMarkedForRemoval() {
+						Expect(pGroup.ProcessClass).To(Equal(fdbv1beta2.ProcessClassTransaction))
+						continue
+					}
+
+					Expect(pGroup.ProcessClass).To(Equal(fdbv1beta2.ProcessClassStorage))
+					cntReplacements++
+				}
+
+				Expect(cntReplacements).To(BeNumerically("==", 9))
+			})
+		})
 	})
 })
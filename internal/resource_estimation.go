@@ -0,0 +1,105 @@
+/*
+ * resource_estimation.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// GetRequiredResources estimates the resources that the Pods and PVCs for the provided process counts will request.
+// The resources are returned with the names that are used in a ResourceQuota, e.g. requests.cpu or pods.
+func GetRequiredResources(cluster *fdbv1beta2.FoundationDBCluster, counts fdbv1beta2.ProcessCounts) (corev1.ResourceList, error) {
+	required := corev1.ResourceList{}
+
+	for processClass, count := range counts.Map() {
+		if count <= 0 {
+			continue
+		}
+
+		podSpec, err := GetPodSpec(cluster, processClass, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		podResources := getPodResources(podSpec)
+		podResources[corev1.ResourcePods] = resource.MustParse("1")
+
+		pvc, err := GetPvc(cluster, processClass, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if pvc != nil {
+			podResources[corev1.ResourcePersistentVolumeClaims] = resource.MustParse("1")
+			podResources[corev1.ResourceRequestsStorage] = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+
+		for name, quantity := range podResources {
+			total := required[name]
+			for i := 0; i < count; i++ {
+				total.Add(quantity)
+			}
+			required[name] = total
+		}
+	}
+
+	return required, nil
+}
+
+// getPodResources returns the effective requests and limits for cpu and memory of a Pod. The effective value is the
+// sum of all containers or the highest value of a single init container, whichever is higher, plus the Pod overhead.
+func getPodResources(podSpec *corev1.PodSpec) corev1.ResourceList {
+	quotaNames := map[corev1.ResourceName][2]corev1.ResourceName{
+		corev1.ResourceCPU:    {corev1.ResourceRequestsCPU, corev1.ResourceLimitsCPU},
+		corev1.ResourceMemory: {corev1.ResourceRequestsMemory, corev1.ResourceLimitsMemory},
+	}
+
+	result := corev1.ResourceList{}
+	for resourceName, names := range quotaNames {
+		var requests, limits resource.Quantity
+		for _, container := range podSpec.Containers {
+			requests.Add(container.Resources.Requests[resourceName])
+			limits.Add(container.Resources.Limits[resourceName])
+		}
+
+		for _, container := range podSpec.InitContainers {
+			if value, ok := container.Resources.Requests[resourceName]; ok && value.Cmp(requests) > 0 {
+				requests = value.DeepCopy()
+			}
+
+			if value, ok := container.Resources.Limits[resourceName]; ok && value.Cmp(limits) > 0 {
+				limits = value.DeepCopy()
+			}
+		}
+
+		if overhead, ok := podSpec.Overhead[resourceName]; ok {
+			requests.Add(overhead)
+			limits.Add(overhead)
+		}
+
+		result[names[0]] = requests
+		result[names[1]] = limits
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+/*
+ * resource_estimation_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("resource_estimation", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var required corev1.ResourceList
+
+	expectQuantity := func(name corev1.ResourceName, expected string) {
+		quantity, ok := required[name]
+		Expect(ok).To(BeTrue())
+		Expect(quantity.Cmp(resource.MustParse(expected))).To(BeZero(), "%s: %s", name, quantity.String())
+	}
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		Expect(NormalizeClusterSpec(cluster, DeprecationOptions{})).NotTo(HaveOccurred())
+	})
+
+	When("estimating the resources for storage processes", func() {
+		JustBeforeEach(func() {
+			var err error
+			required, err = GetRequiredResources(cluster, fdbv1beta2.ProcessCounts{Storage: 2})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should include the containers, the Pods and the PVCs", func() {
+			// The main container requests 1 CPU and 1Gi, the sidecar 100m and 256Mi.
+			expectQuantity(corev1.ResourceRequestsCPU, "2200m")
+			expectQuantity(corev1.ResourceRequestsMemory, "2560Mi")
+			expectQuantity(corev1.ResourceLimitsCPU, "2200m")
+			expectQuantity(corev1.ResourceLimitsMemory, "2560Mi")
+			expectQuantity(corev1.ResourcePods, "2")
+			expectQuantity(corev1.ResourcePersistentVolumeClaims, "2")
+			expectQuantity(corev1.ResourceRequestsStorage, "256G")
+		})
+
+		When("an init container requests more resources than the containers", func() {
+			BeforeEach(func() {
+				settings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+				settings.PodTemplate = settings.PodTemplate.DeepCopy()
+				settings.PodTemplate.Spec.InitContainers = append(settings.PodTemplate.Spec.InitContainers, corev1.Container{
+					Name: "setup",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				})
+				cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = settings
+			})
+
+			It("should use the requests of the init container", func() {
+				expectQuantity(corev1.ResourceRequestsCPU, "8")
+				expectQuantity(corev1.ResourceRequestsMemory, "2560Mi")
+			})
+		})
+	})
+
+	When("estimating the resources for stateless processes", func() {
+		JustBeforeEach(func() {
+			var err error
+			required, err = GetRequiredResources(cluster, fdbv1beta2.ProcessCounts{Stateless: 3})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not include any PVCs", func() {
+			expectQuantity(corev1.ResourcePods, "3")
+			expectQuantity(corev1.ResourceRequestsCPU, "3300m")
+			Expect(required).NotTo(HaveKey(corev1.ResourcePersistentVolumeClaims))
+			Expect(required).NotTo(HaveKey(corev1.ResourceRequestsStorage))
+		})
+	})
+})
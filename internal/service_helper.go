@@ -21,8 +21,11 @@
 package internal
 
 import (
+	"fmt"
+
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // GetHeadlessService builds a headless service for a FoundationDB cluster.
@@ -40,3 +43,42 @@ func GetHeadlessService(cluster *fdbv1beta2.FoundationDBCluster) *corev1.Service
 
 	return service
 }
+
+// GetExternalAccessService builds a service that exposes a single coordinator
+// process group to clients outside of the Kubernetes cluster.
+func GetExternalAccessService(cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID, port int) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: GetObjectMetadata(cluster, nil, "", ""),
+	}
+	service.ObjectMeta.Name = GetExternalAccessServiceName(cluster, processGroupID)
+	service.ObjectMeta.Labels[fdbv1beta2.ExternalAccessLabel] = string(processGroupID)
+
+	externalAccess := cluster.Spec.Routing.ExternalAccess
+	if externalAccess != nil && len(externalAccess.Annotations) > 0 {
+		if service.ObjectMeta.Annotations == nil {
+			service.ObjectMeta.Annotations = make(map[string]string, len(externalAccess.Annotations))
+		}
+		for key, value := range externalAccess.Annotations {
+			service.ObjectMeta.Annotations[key] = value
+		}
+	}
+
+	service.ObjectMeta.OwnerReferences = BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+	service.Spec.Type = cluster.GetExternalAccessServiceType()
+	service.Spec.Selector = GetPodMatchLabels(cluster, "", string(processGroupID))
+	service.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:       "coordinator",
+			Port:       int32(port),
+			TargetPort: intstr.FromInt(port),
+		},
+	}
+
+	return service
+}
+
+// GetExternalAccessServiceName returns the name of the external access service
+// for the given process group.
+func GetExternalAccessServiceName(cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) string {
+	return fmt.Sprintf("%s-external-%s", cluster.Name, processGroupID)
+}
@@ -0,0 +1,194 @@
+/*
+ * tls_certificates.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TLSCertificateRotationHashVariable defines the environment variable of the main container that contains the hash
+// of the certificate that should be used by the processes. The operator only changes the hash shortly before the
+// certificate that is used by the processes expires, so the Pods are updated before the certificate expires.
+const TLSCertificateRotationHashVariable = "FDB_TLS_CERTIFICATE_ROTATION_HASH"
+
+// TLSCertificateSource represents the key of a secret that contains the certificate of the fdbserver processes.
+type TLSCertificateSource struct {
+	// SecretName defines the name of the secret.
+	SecretName string
+
+	// Key defines the key of the certificate in the secret.
+	Key string
+}
+
+// GetTLSCertificateSource returns the secret and the key that contain the certificate that is used by the fdbserver
+// processes of the Pod spec. The certificate is found by the FDB_TLS_CERTIFICATE_FILE environment variable of the
+// main container and the secret volume that is mounted to the path of the certificate.
+func GetTLSCertificateSource(podSpec *corev1.PodSpec) (TLSCertificateSource, bool) {
+	var certificateFile string
+	var mainContainer *corev1.Container
+	for idx, container := range podSpec.Containers {
+		if container.Name != fdbv1beta2.MainContainerName {
+			continue
+		}
+
+		mainContainer = &podSpec.Containers[idx]
+		for _, envVar := range container.Env {
+			if envVar.Name == "FDB_TLS_CERTIFICATE_FILE" {
+				certificateFile = path.Clean(envVar.Value)
+				break
+			}
+		}
+	}
+
+	if mainContainer == nil || certificateFile == "" {
+		return TLSCertificateSource{}, false
+	}
+
+	for _, mount := range mainContainer.VolumeMounts {
+		mountPath := path.Clean(mount.MountPath)
+
+		var fileName string
+		if mount.SubPath != "" {
+			if mountPath != certificateFile {
+				continue
+			}
+
+			fileName = mount.SubPath
+		} else {
+			if !strings.HasPrefix(certificateFile, mountPath+"/") {
+				continue
+			}
+
+			fileName = strings.TrimPrefix(certificateFile, mountPath+"/")
+		}
+
+		for _, volume := range podSpec.Volumes {
+			if volume.Name != mount.Name {
+				continue
+			}
+
+			if volume.Secret != nil {
+				return TLSCertificateSource{
+					SecretName: volume.Secret.SecretName,
+					Key:        getKeyForPath(volume.Secret.Items, fileName),
+				}, true
+			}
+
+			if volume.Projected == nil {
+				return TLSCertificateSource{}, false
+			}
+
+			for _, projection := range volume.Projected.Sources {
+				if projection.Secret == nil {
+					continue
+				}
+
+				if len(projection.Secret.Items) > 0 && !hasKeyToPath(projection.Secret.Items, fileName) {
+					continue
+				}
+
+				return TLSCertificateSource{
+					SecretName: projection.Secret.Name,
+					Key:        getKeyForPath(projection.Secret.Items, fileName),
+				}, true
+			}
+
+			return TLSCertificateSource{}, false
+		}
+	}
+
+	return TLSCertificateSource{}, false
+}
+
+// getKeyForPath returns the key of the secret that is projected to the file name. Without items every key is
+// projected to a file with the same name.
+func getKeyForPath(items []corev1.KeyToPath, fileName string) string {
+	for _, item := range items {
+		if path.Clean(item.Path) == fileName {
+			return item.Key
+		}
+	}
+
+	return fileName
+}
+
+// hasKeyToPath returns true if one of the items is projected to the file name.
+func hasKeyToPath(items []corev1.KeyToPath, fileName string) bool {
+	for _, item := range items {
+		if path.Clean(item.Path) == fileName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseTLSCertificate parses the first certificate of the PEM encoded data.
+func ParseTLSCertificate(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("could not find a PEM encoded certificate")
+		}
+
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// GetTLSCertificateDataHash returns the hash of the PEM encoded certificate.
+func GetTLSCertificateDataHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// configureTLSCertificateRotation adds the hash of the certificate that should be used by the processes to the main
+// container. Certificates that are issued by cert-manager are rolled out by restarting the processes.
+func configureTLSCertificateRotation(cluster *fdbv1beta2.FoundationDBCluster, podSpec *corev1.PodSpec, mainContainer *corev1.Container) {
+	if !cluster.TLSCertificateRotationEnabled() || cluster.CertManagerEnabled() {
+		return
+	}
+
+	source, ok := GetTLSCertificateSource(podSpec)
+	if !ok {
+		return
+	}
+
+	for _, certificate := range cluster.Status.TLSCertificates {
+		if certificate.SecretName != source.SecretName || certificate.Key != source.Key {
+			continue
+		}
+
+		extendEnv(mainContainer, corev1.EnvVar{Name: TLSCertificateRotationHashVariable, Value: certificate.Hash})
+		return
+	}
+}
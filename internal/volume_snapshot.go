@@ -0,0 +1,85 @@
+/*
+ * volume_snapshot.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"strconv"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// VolumeSnapshotGroupVersionKind defines the kind of the VolumeSnapshots of the CSI snapshot API. The operator uses
+// unstructured objects for VolumeSnapshots, so it doesn't depend on the snapshot client libraries.
+var VolumeSnapshotGroupVersionKind = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// GetVolumeSnapshotName returns the name of the VolumeSnapshot that is taken from the process group for a backup.
+func GetVolumeSnapshotName(backup *fdbv1beta2.FoundationDBBackup, processGroupID fdbv1beta2.ProcessGroupID) string {
+	return fmt.Sprintf("%s-%s", backup.Name, processGroupID)
+}
+
+// GetVolumeSnapshot builds the VolumeSnapshot of the data volume of a process group for a backup.
+func GetVolumeSnapshot(backup *fdbv1beta2.FoundationDBBackup, processGroupID fdbv1beta2.ProcessGroupID, pvcName string, version int64) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGroupVersionKind)
+	snapshot.SetNamespace(backup.Namespace)
+	snapshot.SetName(GetVolumeSnapshotName(backup, processGroupID))
+	snapshot.SetLabels(map[string]string{
+		fdbv1beta2.BackupDeploymentLabel:  string(backup.ObjectMeta.UID),
+		fdbv1beta2.FDBProcessGroupIDLabel: string(processGroupID),
+	})
+	snapshot.SetAnnotations(map[string]string{
+		fdbv1beta2.RestorableVersionAnnotation: strconv.FormatInt(version, 10),
+	})
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+
+	if backup.Spec.VolumeSnapshotConfiguration != nil && backup.Spec.VolumeSnapshotConfiguration.VolumeSnapshotClassName != nil {
+		spec["volumeSnapshotClassName"] = *backup.Spec.VolumeSnapshotConfiguration.VolumeSnapshotClassName
+	}
+
+	snapshot.Object["spec"] = spec
+
+	return snapshot
+}
+
+// IsVolumeSnapshotTaken returns true if the storage system has taken the point-in-time snapshot of the volume. The
+// snapshot might not be ready to use at this point, e.g. while it's uploaded.
+func IsVolumeSnapshotTaken(snapshot *unstructured.Unstructured) bool {
+	creationTime, found, err := unstructured.NestedString(snapshot.Object, "status", "creationTime")
+	return err == nil && found && creationTime != ""
+}
+
+// IsVolumeSnapshotReady returns true if the VolumeSnapshot is ready to be used as the data source of a volume.
+func IsVolumeSnapshotReady(snapshot *unstructured.Unstructured) bool {
+	ready, found, err := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	return err == nil && found && ready
+}
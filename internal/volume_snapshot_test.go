@@ -0,0 +1,94 @@
+/*
+ * volume_snapshot_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("volume_snapshot", func() {
+	var backup *fdbv1beta2.FoundationDBBackup
+
+	BeforeEach(func() {
+		backup = CreateDefaultBackup(CreateDefaultCluster())
+		backup.Spec.BackupMode = fdbv1beta2.BackupModeVolumeSnapshot
+	})
+
+	When("building a VolumeSnapshot", func() {
+		It("should reference the PVC and the restorable version", func() {
+			snapshot := GetVolumeSnapshot(backup, "storage-1", "operator-test-1-storage-1-data", 42)
+			Expect(snapshot.GroupVersionKind()).To(Equal(VolumeSnapshotGroupVersionKind))
+			Expect(snapshot.GetName()).To(Equal("operator-test-1-storage-1"))
+			Expect(snapshot.GetNamespace()).To(Equal(backup.Namespace))
+			Expect(snapshot.GetLabels()).To(HaveKeyWithValue(fdbv1beta2.FDBProcessGroupIDLabel, "storage-1"))
+			Expect(snapshot.GetAnnotations()).To(HaveKeyWithValue(fdbv1beta2.RestorableVersionAnnotation, "42"))
+
+			pvcName, _, err := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pvcName).To(Equal("operator-test-1-storage-1-data"))
+
+			_, found, err := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("should set the VolumeSnapshotClass if defined", func() {
+			backup.Spec.VolumeSnapshotConfiguration = &fdbv1beta2.VolumeSnapshotConfiguration{
+				VolumeSnapshotClassName: pointer.String("csi-snapshot-class"),
+			}
+
+			snapshot := GetVolumeSnapshot(backup, "storage-1", "operator-test-1-storage-1-data", 42)
+			className, _, err := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(className).To(Equal("csi-snapshot-class"))
+		})
+	})
+
+	When("checking the state of a VolumeSnapshot", func() {
+		var snapshot *unstructured.Unstructured
+
+		BeforeEach(func() {
+			snapshot = GetVolumeSnapshot(backup, "storage-1", "operator-test-1-storage-1-data", 42)
+		})
+
+		It("should be neither taken nor ready without a status", func() {
+			Expect(IsVolumeSnapshotTaken(snapshot)).To(BeFalse())
+			Expect(IsVolumeSnapshotReady(snapshot)).To(BeFalse())
+		})
+
+		It("should be taken once the creation time is set", func() {
+			Expect(unstructured.SetNestedField(snapshot.Object, "2023-01-01T00:00:00Z", "status", "creationTime")).NotTo(HaveOccurred())
+			Expect(unstructured.SetNestedField(snapshot.Object, false, "status", "readyToUse")).NotTo(HaveOccurred())
+			Expect(IsVolumeSnapshotTaken(snapshot)).To(BeTrue())
+			Expect(IsVolumeSnapshotReady(snapshot)).To(BeFalse())
+		})
+
+		It("should be ready once it is ready to use", func() {
+			Expect(unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse")).NotTo(HaveOccurred())
+			Expect(IsVolumeSnapshotReady(snapshot)).To(BeTrue())
+		})
+	})
+})
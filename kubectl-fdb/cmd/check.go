@@ -0,0 +1,145 @@
+/*
+ * check.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/validation"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func newCheckCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Validates FoundationDBCluster manifests",
+		Long: `Validates the FoundationDBCluster resources in the given manifests with the defaulting and validation logic of the operator.
+This doesn't require access to a Kubernetes cluster, so it can be used in CI pipelines to check manifests before they are applied.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := cmd.Flags().GetStringSlice("filename")
+			if err != nil {
+				return err
+			}
+			useFutureDefaults, err := cmd.Flags().GetBool("use-future-defaults")
+			if err != nil {
+				return err
+			}
+
+			if len(files) == 0 {
+				return fmt.Errorf("no manifest provided, use -f to define the manifests")
+			}
+
+			return checkManifests(cmd, files, validation.Options{UseFutureDefaults: useFutureDefaults})
+		},
+		Example: `
+# Validates the clusters in the manifest
+kubectl fdb check -f cluster.yaml
+
+# Validates the clusters in multiple manifests
+kubectl fdb check -f cluster.yaml -f other-cluster.yaml
+
+# Validates the clusters in the rendered manifests from stdin
+kustomize build . | kubectl fdb check -f -
+
+# Validates the clusters in the manifest with the latest defaults
+kubectl fdb check -f cluster.yaml --use-future-defaults`,
+	}
+
+	cmd.Flags().StringSliceP("filename", "f", nil, "The manifests that contain the clusters to validate, use - to read the manifest from stdin.")
+	cmd.Flags().Bool("use-future-defaults", false,
+		"Whether we should apply the latest defaults rather than the defaults that were initially established for this major version.",
+	)
+
+	cmd.SetOut(streams.Out)
+	cmd.SetErr(streams.ErrOut)
+	cmd.SetIn(streams.In)
+
+	return cmd
+}
+
+// checkManifests validates the clusters in the provided manifests and prints the result and the warnings for every
+// cluster. An error is returned if any of the clusters is not valid, warnings don't cause an error.
+func checkManifests(cmd *cobra.Command, files []string, options validation.Options) error {
+	clusterCounter := 0
+	invalidCounter := 0
+
+	for _, file := range files {
+		results, err := checkManifest(cmd, file, options)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			clusterCounter++
+			name := result.Name
+			if result.Namespace != "" {
+				name = result.Namespace + "/" + name
+			}
+
+			for _, warning := range result.Warnings {
+				printStatement(cmd, fmt.Sprintf("Cluster %s in %s: %s", name, file, warning), warnMessage)
+			}
+
+			if result.Error != nil {
+				invalidCounter++
+				printStatement(cmd, fmt.Sprintf("Cluster %s in %s is not valid: %s", name, file, result.Error.Error()), errorMessage)
+				continue
+			}
+
+			printStatement(cmd, fmt.Sprintf("Cluster %s in %s is valid", name, file), goodMessage)
+		}
+	}
+
+	if clusterCounter == 0 {
+		return fmt.Errorf("no FoundationDBCluster found in the provided manifests")
+	}
+
+	if invalidCounter > 0 {
+		return fmt.Errorf("%d/%d cluster(s) are not valid", invalidCounter, clusterCounter)
+	}
+
+	return nil
+}
+
+// checkManifest validates the clusters in a single manifest.
+func checkManifest(cmd *cobra.Command, file string, options validation.Options) ([]validation.Result, error) {
+	var reader io.Reader
+	if file == "-" {
+		reader = cmd.InOrStdin()
+	} else {
+		manifest, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer manifest.Close()
+		reader = manifest
+	}
+
+	results, err := validation.ValidateManifest(reader, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest %s: %w", file, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,119 @@
+/*
+ * check_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var _ = Describe("[plugin] check command", func() {
+	var outBuffer bytes.Buffer
+	var errBuffer bytes.Buffer
+	var inBuffer bytes.Buffer
+	var manifestDir string
+
+	validManifest := `apiVersion: apps.foundationdb.org/v1beta2
+kind: FoundationDBCluster
+metadata:
+  name: valid-cluster
+spec:
+  version: ` + fdbv1beta2.Versions.Default.String() + `
+`
+
+	invalidManifest := `apiVersion: apps.foundationdb.org/v1beta2
+kind: FoundationDBCluster
+metadata:
+  name: invalid-cluster
+spec:
+  version: 6.1.0
+`
+
+	writeManifest := func(name string, content string) string {
+		file := path.Join(manifestDir, name)
+		Expect(os.WriteFile(file, []byte(content), 0600)).NotTo(HaveOccurred())
+		return file
+	}
+
+	runCheck := func(args ...string) error {
+		cmd := NewRootCmd(genericclioptions.IOStreams{In: &inBuffer, Out: &outBuffer, ErrOut: &errBuffer})
+		cmd.SetArgs(append([]string{"check"}, args...))
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		outBuffer = bytes.Buffer{}
+		errBuffer = bytes.Buffer{}
+		inBuffer = bytes.Buffer{}
+		manifestDir = GinkgoT().TempDir()
+	})
+
+	When("the manifest contains a valid cluster", func() {
+		It("should report the cluster as valid", func() {
+			Expect(runCheck("-f", writeManifest("cluster.yaml", validManifest))).To(Succeed())
+			Expect(outBuffer.String()).To(ContainSubstring("Cluster valid-cluster in"))
+			Expect(outBuffer.String()).To(ContainSubstring("is valid"))
+		})
+	})
+
+	When("the manifest contains a cluster with risky settings", func() {
+		It("should report the warnings and the cluster as valid", func() {
+			Expect(runCheck("-f", writeManifest("cluster.yaml", validManifest+"  databaseConfiguration:\n    logs: 1\n"))).To(Succeed())
+			Expect(outBuffer.String()).To(ContainSubstring("is valid"))
+			Expect(errBuffer.String()).To(ContainSubstring("the logs count 1 is below the recommended count of 3 for the redundancy mode double"))
+		})
+	})
+
+	When("one of the manifests contains an invalid cluster", func() {
+		It("should report the invalid cluster", func() {
+			err := runCheck("-f", writeManifest("valid.yaml", validManifest), "-f", writeManifest("invalid.yaml", invalidManifest))
+			Expect(err).To(MatchError("1/2 cluster(s) are not valid"))
+			Expect(errBuffer.String()).To(ContainSubstring("Cluster invalid-cluster in"))
+			Expect(errBuffer.String()).To(ContainSubstring("is not valid: version 6.1.0 is not supported"))
+		})
+	})
+
+	When("the manifest is read from stdin", func() {
+		It("should validate the cluster", func() {
+			inBuffer.WriteString(validManifest)
+			Expect(runCheck("-f", "-")).To(Succeed())
+			Expect(outBuffer.String()).To(ContainSubstring("Cluster valid-cluster in - is valid"))
+		})
+	})
+
+	When("the manifest contains no cluster", func() {
+		It("should return an error", func() {
+			Expect(runCheck("-f", writeManifest("empty.yaml", "apiVersion: v1\nkind: ConfigMap\n"))).To(MatchError("no FoundationDBCluster found in the provided manifests"))
+		})
+	})
+
+	When("no manifest is provided", func() {
+		It("should return an error", func() {
+			Expect(runCheck()).To(HaveOccurred())
+		})
+	})
+})
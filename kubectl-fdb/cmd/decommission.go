@@ -0,0 +1,168 @@
+/*
+ * decommission.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	ctx "context"
+	"fmt"
+	"log"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newDecommissionCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "decommission",
+		Short: "Decommissions the given fault domains (zones or data centers) of the given cluster",
+		Long:  "Adds the given fault domains (zones or data centers) to the faultDomainsToDecommission list of the given cluster. The operator will exclude and remove all process groups in those fault domains in batches.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wait, err := cmd.Root().Flags().GetBool("wait")
+			if err != nil {
+				return err
+			}
+			clear, err := cmd.Flags().GetBool("clear")
+			if err != nil {
+				return err
+			}
+			key, err := cmd.Flags().GetString("key")
+			if err != nil {
+				return err
+			}
+			cluster, err := cmd.Flags().GetString("fdb-cluster")
+			if err != nil {
+				return err
+			}
+
+			kubeClient, err := getKubeClient(o)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			return decommissionFaultDomains(kubeClient, cluster, key, args, namespace, wait, clear)
+		},
+		Example: `
+# Decommission the zone zone-a for a cluster in the current namespace
+kubectl fdb decommission -c cluster zone-a
+
+# Decommission the data center dc1 for a cluster in the namespace default
+kubectl fdb -n default decommission -c cluster --key dcid dc1
+
+# Stop the decommissioning of the zone zone-a for a cluster in the current namespace
+kubectl fdb decommission --clear -c cluster zone-a
+`,
+	}
+
+	cmd.Flags().StringP("fdb-cluster", "c", "", "decommission the fault domains of the provided cluster.")
+	cmd.Flags().StringP("key", "k", fdbv1beta2.FDBLocalityZoneIDKey, "the locality key of the fault domains, either zoneid or dcid.")
+	cmd.Flags().Bool("clear", false, "removes the fault domains from the decommission list.")
+	err := cmd.MarkFlagRequired("fdb-cluster")
+	if err != nil {
+		log.Fatal(err)
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// decommissionFaultDomains updates the list of fault domains to decommission of the cluster
+func decommissionFaultDomains(kubeClient client.Client, clusterName string, key string, faultDomains []string, namespace string, wait bool, clear bool) error {
+	if key != fdbv1beta2.FDBLocalityZoneIDKey && key != fdbv1beta2.FDBLocalityDCIDKey {
+		return fmt.Errorf("unsupported key %s, only %s and %s are supported", key, fdbv1beta2.FDBLocalityZoneIDKey, fdbv1beta2.FDBLocalityDCIDKey)
+	}
+
+	if len(faultDomains) == 0 {
+		return fmt.Errorf("please provide at least one fault domain")
+	}
+
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("could not get cluster: %s/%s", namespace, clusterName)
+		}
+		return err
+	}
+
+	if wait {
+		action := "Decommission"
+		if clear {
+			action = "Stop decommissioning"
+		}
+
+		if !confirmAction(fmt.Sprintf("%s %s %v of cluster %s/%s", action, key, faultDomains, namespace, clusterName)) {
+			return fmt.Errorf("user aborted the decommissioning")
+		}
+	}
+
+	patch := client.MergeFrom(cluster.DeepCopy())
+
+	requested := make(map[fdbv1beta2.FaultDomainToDecommission]fdbv1beta2.None, len(faultDomains))
+	for _, faultDomain := range faultDomains {
+		requested[fdbv1beta2.FaultDomainToDecommission{Key: key, Value: faultDomain}] = fdbv1beta2.None{}
+	}
+
+	decommissioned := make([]fdbv1beta2.FaultDomainToDecommission, 0, len(cluster.Spec.FaultDomainsToDecommission)+len(requested))
+	for _, faultDomain := range cluster.Spec.FaultDomainsToDecommission {
+		if faultDomain.Key == "" {
+			faultDomain.Key = fdbv1beta2.FDBLocalityZoneIDKey
+		}
+
+		if _, ok := requested[faultDomain]; ok {
+			if clear {
+				continue
+			}
+
+			delete(requested, faultDomain)
+		}
+
+		decommissioned = append(decommissioned, faultDomain)
+	}
+
+	if !clear {
+		for _, faultDomain := range faultDomains {
+			current := fdbv1beta2.FaultDomainToDecommission{Key: key, Value: faultDomain}
+			if _, ok := requested[current]; !ok {
+				continue
+			}
+
+			delete(requested, current)
+			decommissioned = append(decommissioned, current)
+		}
+	}
+
+	cluster.Spec.FaultDomainsToDecommission = decommissioned
+
+	return kubeClient.Patch(ctx.TODO(), cluster, patch)
+}
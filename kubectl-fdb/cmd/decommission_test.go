@@ -0,0 +1,113 @@
+/*
+ * decommission_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("[plugin] decommission command", func() {
+	When("running the decommission command", func() {
+		type testCase struct {
+			Key                  string
+			FaultDomains         []string
+			Clear                bool
+			ExpectedFaultDomains []fdbv1beta2.FaultDomainToDecommission
+			ExpectedError        string
+		}
+
+		BeforeEach(func() {
+			cluster.Spec.FaultDomainsToDecommission = []fdbv1beta2.FaultDomainToDecommission{
+				{
+					Value: "zone-a",
+				},
+			}
+		})
+
+		DescribeTable("should update the fault domains to decommission",
+			func(tc testCase) {
+				err := decommissionFaultDomains(k8sClient, clusterName, tc.Key, tc.FaultDomains, namespace, false, tc.Clear)
+				if tc.ExpectedError != "" {
+					Expect(err).To(MatchError(tc.ExpectedError))
+					return
+				}
+				Expect(err).NotTo(HaveOccurred())
+
+				var resCluster fdbv1beta2.FoundationDBCluster
+				err = k8sClient.Get(context.Background(), client.ObjectKey{
+					Namespace: namespace,
+					Name:      clusterName,
+				}, &resCluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resCluster.Spec.FaultDomainsToDecommission).To(Equal(tc.ExpectedFaultDomains))
+			},
+			Entry("Adding a zone.",
+				testCase{
+					Key:          fdbv1beta2.FDBLocalityZoneIDKey,
+					FaultDomains: []string{"zone-b"},
+					ExpectedFaultDomains: []fdbv1beta2.FaultDomainToDecommission{
+						{Key: fdbv1beta2.FDBLocalityZoneIDKey, Value: "zone-a"},
+						{Key: fdbv1beta2.FDBLocalityZoneIDKey, Value: "zone-b"},
+					},
+				}),
+			Entry("Adding the same zone.",
+				testCase{
+					Key:          fdbv1beta2.FDBLocalityZoneIDKey,
+					FaultDomains: []string{"zone-a"},
+					ExpectedFaultDomains: []fdbv1beta2.FaultDomainToDecommission{
+						{Key: fdbv1beta2.FDBLocalityZoneIDKey, Value: "zone-a"},
+					},
+				}),
+			Entry("Adding a data center.",
+				testCase{
+					Key:          fdbv1beta2.FDBLocalityDCIDKey,
+					FaultDomains: []string{"zone-a"},
+					ExpectedFaultDomains: []fdbv1beta2.FaultDomainToDecommission{
+						{Key: fdbv1beta2.FDBLocalityZoneIDKey, Value: "zone-a"},
+						{Key: fdbv1beta2.FDBLocalityDCIDKey, Value: "zone-a"},
+					},
+				}),
+			Entry("Clearing a zone.",
+				testCase{
+					Key:                  fdbv1beta2.FDBLocalityZoneIDKey,
+					FaultDomains:         []string{"zone-a"},
+					Clear:                true,
+					ExpectedFaultDomains: nil,
+				}),
+			Entry("Using an unsupported key.",
+				testCase{
+					Key:           "rack",
+					FaultDomains:  []string{"rack-1"},
+					ExpectedError: "unsupported key rack, only zoneid and dcid are supported",
+				}),
+			Entry("Passing no fault domain.",
+				testCase{
+					Key:           fdbv1beta2.FDBLocalityZoneIDKey,
+					ExpectedError: "please provide at least one fault domain",
+				}),
+		)
+	})
+})
@@ -0,0 +1,168 @@
+/*
+ * generate.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+func newGenerateCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Subcommand to generate manifests for the operator",
+		Long:  "Subcommand to generate manifests for the operator",
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+		Example: `
+# Generate the RBAC manifests for an operator that watches the namespace fdb
+kubectl fdb generate rbac --operator-namespace fdb --watch-namespaces fdb
+`,
+	}
+	cmd.SetOut(streams.Out)
+	cmd.SetErr(streams.ErrOut)
+	cmd.SetIn(streams.In)
+
+	cmd.AddCommand(newGenerateRBACCmd(streams))
+
+	return cmd
+}
+
+func newGenerateRBACCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rbac",
+		Short: "Generates the RBAC manifests with the minimal permissions of the operator",
+		Long: `Generates the ClusterRole, Roles and bindings with the minimal permissions the operator requires for the watched namespaces and the enabled features.
+The permissions for Pods and secrets are only granted in the watched namespaces and the operator is never granted pods/exec.
+The manifests are printed to stdout, so they can be applied with kubectl apply -f - or added to a kustomization.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getRBACOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			return printRBACManifests(cmd, options)
+		},
+		Example: `
+# Generate the RBAC manifests for an operator in the namespace fdb-system that watches the namespace fdb
+kubectl fdb generate rbac --operator-namespace fdb-system --watch-namespaces fdb
+
+# Generate the RBAC manifests for an operator that watches multiple namespaces and manages backups
+kubectl fdb generate rbac --operator-namespace fdb-system --watch-namespaces team-a,team-b --enable-backups
+
+# Generate and apply the RBAC manifests
+kubectl fdb generate rbac --operator-namespace fdb --watch-namespaces fdb | kubectl apply -f -
+`,
+	}
+
+	cmd.Flags().String("name", "fdb-kubernetes-operator-manager-role", "The name of the generated roles and role bindings.")
+	cmd.Flags().String("operator-namespace", "", "The namespace of the operator Deployment and its service account.")
+	cmd.Flags().String("service-account", "fdb-kubernetes-operator-controller-manager", "The name of the service account of the operator.")
+	cmd.Flags().StringSlice("watch-namespaces", nil, "The namespaces that are watched by the operator. If empty, the permissions are granted cluster-wide.")
+	cmd.Flags().Bool("enable-leader-election", true, "Whether the permissions for the leader election are granted in the operator namespace.")
+	cmd.Flags().Bool("enable-backups", false, "Whether the permissions for the backup and restore controllers are granted.")
+	cmd.Flags().Bool("enable-test-scenarios", false, "Whether the permissions for the test scenario controller are granted.")
+	cmd.Flags().Bool("enable-client-library-caches", false, "Whether the permissions for the client library cache controller are granted.")
+	cmd.Flags().Bool("enable-operator-configs", false, "Whether the permissions to read the FoundationDBOperatorConfigs are granted.")
+	cmd.Flags().Bool("enable-cert-manager", false, "Whether the permissions for the cert-manager Certificates are granted.")
+
+	cmd.SetOut(streams.Out)
+	cmd.SetErr(streams.ErrOut)
+	cmd.SetIn(streams.In)
+
+	return cmd
+}
+
+// getRBACOptions returns the options for the RBAC manifests from the flags of the command.
+func getRBACOptions(cmd *cobra.Command) (internal.RBACOptions, error) {
+	options := internal.RBACOptions{}
+
+	var err error
+	options.Name, err = cmd.Flags().GetString("name")
+	if err != nil {
+		return options, err
+	}
+	options.OperatorNamespace, err = cmd.Flags().GetString("operator-namespace")
+	if err != nil {
+		return options, err
+	}
+	options.ServiceAccountName, err = cmd.Flags().GetString("service-account")
+	if err != nil {
+		return options, err
+	}
+	options.WatchNamespaces, err = cmd.Flags().GetStringSlice("watch-namespaces")
+	if err != nil {
+		return options, err
+	}
+	options.EnableLeaderElection, err = cmd.Flags().GetBool("enable-leader-election")
+	if err != nil {
+		return options, err
+	}
+	options.EnableBackups, err = cmd.Flags().GetBool("enable-backups")
+	if err != nil {
+		return options, err
+	}
+	options.EnableTestScenarios, err = cmd.Flags().GetBool("enable-test-scenarios")
+	if err != nil {
+		return options, err
+	}
+	options.EnableClientLibraryCaches, err = cmd.Flags().GetBool("enable-client-library-caches")
+	if err != nil {
+		return options, err
+	}
+	options.EnableOperatorConfigs, err = cmd.Flags().GetBool("enable-operator-configs")
+	if err != nil {
+		return options, err
+	}
+	options.EnableCertManager, err = cmd.Flags().GetBool("enable-cert-manager")
+	if err != nil {
+		return options, err
+	}
+
+	if options.OperatorNamespace == "" {
+		return options, fmt.Errorf("no operator namespace provided, use --operator-namespace to define the namespace of the operator")
+	}
+
+	return options, nil
+}
+
+// printRBACManifests prints the RBAC manifests for the options as a multi-document YAML.
+func printRBACManifests(cmd *cobra.Command, options internal.RBACOptions) error {
+	for idx, manifest := range internal.GetRBACManifests(options) {
+		content, err := yaml.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+
+		if idx > 0 {
+			cmd.Println("---")
+		}
+
+		cmd.Print(string(content))
+	}
+
+	return nil
+}
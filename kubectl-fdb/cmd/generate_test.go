@@ -0,0 +1,95 @@
+/*
+ * generate_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("[plugin] generate rbac command", func() {
+	var outBuffer bytes.Buffer
+	var errBuffer bytes.Buffer
+
+	runGenerate := func(args ...string) error {
+		cmd := NewRootCmd(genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: &outBuffer, ErrOut: &errBuffer})
+		cmd.SetArgs(append([]string{"generate", "rbac"}, args...))
+		return cmd.Execute()
+	}
+
+	BeforeEach(func() {
+		outBuffer = bytes.Buffer{}
+		errBuffer = bytes.Buffer{}
+	})
+
+	When("no operator namespace is provided", func() {
+		It("should return an error", func() {
+			Expect(runGenerate()).To(MatchError("no operator namespace provided, use --operator-namespace to define the namespace of the operator"))
+		})
+	})
+
+	When("the operator watches two namespaces", func() {
+		var documents []string
+
+		BeforeEach(func() {
+			Expect(runGenerate("--operator-namespace", "fdb-system", "--watch-namespaces", "team-b,team-a")).To(Succeed())
+			documents = strings.Split(outBuffer.String(), "---\n")
+		})
+
+		It("should print the manifests", func() {
+			Expect(documents).To(HaveLen(8))
+
+			clusterRole := &rbacv1.ClusterRole{}
+			Expect(yaml.Unmarshal([]byte(documents[0]), clusterRole)).To(Succeed())
+			Expect(clusterRole.Kind).To(Equal("ClusterRole"))
+			Expect(clusterRole.Name).To(Equal("fdb-kubernetes-operator-manager-role"))
+			for _, rule := range clusterRole.Rules {
+				Expect(rule.Resources).NotTo(ContainElement("secrets"))
+				Expect(rule.Resources).NotTo(ContainElement("pods"))
+			}
+
+			role := &rbacv1.Role{}
+			Expect(yaml.Unmarshal([]byte(documents[2]), role)).To(Succeed())
+			Expect(role.Kind).To(Equal("Role"))
+			Expect(role.Namespace).To(Equal("team-a"))
+
+			binding := &rbacv1.RoleBinding{}
+			Expect(yaml.Unmarshal([]byte(documents[7]), binding)).To(Succeed())
+			Expect(binding.Name).To(Equal("fdb-kubernetes-operator-manager-role-leader-election"))
+			Expect(binding.Namespace).To(Equal("fdb-system"))
+			Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "fdb-kubernetes-operator-controller-manager",
+				Namespace: "fdb-system",
+			}))
+		})
+
+		It("should never grant pods/exec", func() {
+			Expect(outBuffer.String()).NotTo(ContainSubstring("pods/exec"))
+		})
+	})
+})
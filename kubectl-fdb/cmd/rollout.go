@@ -0,0 +1,145 @@
+/*
+ * rollout.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newRolloutCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "Subcommand to get information about the rollout of changes for a given cluster",
+		Long:  "Subcommand to get information about the rollout of changes for a given cluster",
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+		Example: `
+# Get the rollout status of cluster c1
+kubectl fdb rollout status c1
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	cmd.AddCommand(newRolloutStatusCmd(streams))
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func newRolloutStatusCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the rollout progress of the Pod template revisions of a cluster.",
+		Long:  "Shows the rollout progress of the Pod template revisions of a cluster.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := getKubeClient(o)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			return printRolloutStatus(cmd, kubeClient, namespace, args[0])
+		},
+		Example: `
+This command shows for every process class how many process groups run with the current revision of the Pod template
+and how many process groups run with an older revision.
+
+# Get the rollout status of cluster c1
+kubectl fdb rollout status c1
+
+# Get the rollout status of cluster c1 in the namespace default
+kubectl fdb -n default rollout status c1
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// printRolloutStatus prints the rollout progress across the revisions of every process class of the cluster.
+func printRolloutStatus(cmd *cobra.Command, kubeClient client.Client, namespace string, clusterName string) error {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return err
+	}
+
+	complete := true
+	if cluster.Status.Generations.Reconciled < cluster.ObjectMeta.Generation {
+		complete = false
+		printStatement(cmd, fmt.Sprintf("Cluster %s/%s: waiting for the operator to reconcile generation %d, the revisions may not reflect the latest changes", cluster.Namespace, cluster.Name, cluster.ObjectMeta.Generation), warnMessage)
+	}
+
+	if len(cluster.Status.Revisions) == 0 {
+		return fmt.Errorf("cluster %s/%s has no revisions in its status", cluster.Namespace, cluster.Name)
+	}
+
+	for _, revision := range cluster.Status.Revisions {
+		message := fmt.Sprintf("%s: %d of %d process groups updated to revision %s", revision.ProcessClass, revision.UpdatedProcessGroups, revision.ProcessGroups, revision.UpdateRevision)
+		if revision.UpdatedProcessGroups < revision.ProcessGroups {
+			complete = false
+			printStatement(cmd, message, warnMessage)
+		} else {
+			printStatement(cmd, message, goodMessage)
+		}
+
+		for _, count := range revision.Revisions {
+			cmd.Printf("  revision %s: %d process groups%s\n", count.Revision, count.ProcessGroups, getRevisionSuffix(revision, count))
+		}
+	}
+
+	if complete {
+		printStatement(cmd, fmt.Sprintf("Cluster %s/%s: rollout is complete", cluster.Namespace, cluster.Name), goodMessage)
+		return nil
+	}
+
+	printStatement(cmd, fmt.Sprintf("Cluster %s/%s: rollout is in progress", cluster.Namespace, cluster.Name), warnMessage)
+	return nil
+}
+
+// getRevisionSuffix returns the suffix that marks the current revision of a process class.
+func getRevisionSuffix(revision fdbv1beta2.ProcessClassRevisionStatus, count fdbv1beta2.RevisionCount) string {
+	if count.Revision == revision.UpdateRevision {
+		return " (current)"
+	}
+
+	return ""
+}
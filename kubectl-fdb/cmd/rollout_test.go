@@ -0,0 +1,130 @@
+/*
+ * rollout_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var _ = Describe("[plugin] rollout status command", func() {
+	var outBuffer bytes.Buffer
+	var errBuffer bytes.Buffer
+	var err error
+
+	BeforeEach(func() {
+		outBuffer = bytes.Buffer{}
+		errBuffer = bytes.Buffer{}
+	})
+
+	JustBeforeEach(func() {
+		cmd := newRolloutStatusCmd(genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: &outBuffer, ErrOut: &errBuffer})
+		err = printRolloutStatus(cmd, k8sClient, namespace, clusterName)
+	})
+
+	When("the cluster has no revisions", func() {
+		It("should return an error", func() {
+			Expect(err).To(MatchError("cluster test/test has no revisions in its status"))
+		})
+	})
+
+	When("all process groups run with the current revision", func() {
+		BeforeEach(func() {
+			// The mock client sets the generation to 1 when the cluster is created.
+			cluster.Status.Generations.Reconciled = 1
+			cluster.Status.Revisions = []fdbv1beta2.ProcessClassRevisionStatus{
+				{
+					ProcessClass:         fdbv1beta2.ProcessClassStorage,
+					UpdateRevision:       "rev-2",
+					ProcessGroups:        3,
+					UpdatedProcessGroups: 3,
+					Revisions:            []fdbv1beta2.RevisionCount{{Revision: "rev-2", ProcessGroups: 3}},
+				},
+			}
+		})
+
+		It("should report the rollout as complete", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outBuffer.String()).To(ContainSubstring("storage: 3 of 3 process groups updated to revision rev-2"))
+			Expect(outBuffer.String()).To(ContainSubstring("revision rev-2: 3 process groups (current)"))
+			Expect(outBuffer.String()).To(ContainSubstring("Cluster test/test: rollout is complete"))
+			Expect(errBuffer.String()).To(BeEmpty())
+		})
+	})
+
+	When("some process groups run with an older revision", func() {
+		BeforeEach(func() {
+			cluster.Status.Generations.Reconciled = 1
+			cluster.Status.Revisions = []fdbv1beta2.ProcessClassRevisionStatus{
+				{
+					ProcessClass:         fdbv1beta2.ProcessClassLog,
+					UpdateRevision:       "rev-1",
+					ProcessGroups:        4,
+					UpdatedProcessGroups: 4,
+					Revisions:            []fdbv1beta2.RevisionCount{{Revision: "rev-1", ProcessGroups: 4}},
+				},
+				{
+					ProcessClass:         fdbv1beta2.ProcessClassStorage,
+					UpdateRevision:       "rev-2",
+					ProcessGroups:        3,
+					UpdatedProcessGroups: 1,
+					Revisions: []fdbv1beta2.RevisionCount{
+						{Revision: "rev-1", ProcessGroups: 2},
+						{Revision: "rev-2", ProcessGroups: 1},
+					},
+				},
+			}
+		})
+
+		It("should report the rollout as in progress", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outBuffer.String()).To(ContainSubstring("log: 4 of 4 process groups updated to revision rev-1"))
+			Expect(outBuffer.String()).To(ContainSubstring("revision rev-1: 2 process groups\n"))
+			Expect(outBuffer.String()).To(ContainSubstring("revision rev-2: 1 process groups (current)"))
+			Expect(errBuffer.String()).To(ContainSubstring("storage: 1 of 3 process groups updated to revision rev-2"))
+			Expect(errBuffer.String()).To(ContainSubstring("Cluster test/test: rollout is in progress"))
+		})
+	})
+
+	When("the latest generation is not reconciled", func() {
+		BeforeEach(func() {
+			cluster.Status.Revisions = []fdbv1beta2.ProcessClassRevisionStatus{
+				{
+					ProcessClass:         fdbv1beta2.ProcessClassStorage,
+					UpdateRevision:       "rev-1",
+					ProcessGroups:        3,
+					UpdatedProcessGroups: 3,
+					Revisions:            []fdbv1beta2.RevisionCount{{Revision: "rev-1", ProcessGroups: 3}},
+				},
+			}
+		})
+
+		It("should report the rollout as in progress", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errBuffer.String()).To(ContainSubstring("Cluster test/test: waiting for the operator to reconcile generation 1"))
+			Expect(errBuffer.String()).To(ContainSubstring("Cluster test/test: rollout is in progress"))
+		})
+	})
+})
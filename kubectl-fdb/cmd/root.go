@@ -83,6 +83,7 @@ func NewRootCmd(streams genericclioptions.IOStreams) *cobra.Command {
 		newRemoveCmd(streams),
 		newExecCmd(streams),
 		newCordonCmd(streams),
+		newDecommissionCmd(streams),
 		newRestartCmd(streams),
 		newAnalyzeCmd(streams),
 		newDeprecationCmd(streams),
@@ -90,6 +91,10 @@ func NewRootCmd(streams genericclioptions.IOStreams) *cobra.Command {
 		newGetCmd(streams),
 		newBuggifyCmd(streams),
 		newProfileAnalyzerCmd(streams),
+		newCheckCmd(streams),
+		newSupportBundleCmd(streams),
+		newRolloutCmd(streams),
+		newGenerateCmd(streams),
 	)
 
 	return cmd
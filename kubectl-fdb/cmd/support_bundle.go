@@ -0,0 +1,441 @@
+/*
+ * support_bundle.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	ctx "context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// redactedValue is the value that replaces sensitive information in the support bundle.
+const redactedValue = "<redacted>"
+
+// sensitiveEnvNamePattern matches the names of environment variables that might contain credentials.
+var sensitiveEnvNamePattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private_key|access_key)`)
+
+func newSupportBundleCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "support-bundle",
+		Short: "Collects the state of a cluster into a tarball for support tickets",
+		Long:  "Collects the cluster spec and status, the Pod specs, recent events, the operator logs for the cluster, the machine-readable status and the monitor conf contents into a tarball. Environment variables that might contain credentials are redacted and secrets are never collected.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			operatorName, err := cmd.Root().Flags().GetString("operator-name")
+			if err != nil {
+				return err
+			}
+
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+
+			since, err := cmd.Flags().GetDuration("since")
+			if err != nil {
+				return err
+			}
+
+			skipStatus, err := cmd.Flags().GetBool("skip-status")
+			if err != nil {
+				return err
+			}
+
+			config, err := o.configFlags.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			clientSet, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return err
+			}
+
+			kubeClient, err := getKubeClient(o)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			cluster, err := loadCluster(kubeClient, namespace, args[0])
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				output = fmt.Sprintf("%s-support-bundle-%s.tar.gz", cluster.Name, time.Now().UTC().Format("20060102T150405Z"))
+			}
+
+			file, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			bundle := newSupportBundle(file, cluster.Name)
+			bundle.collectCluster(cluster)
+			pods := bundle.collectPods(kubeClient, cluster)
+			bundle.collectEvents(kubeClient, cluster, pods)
+			bundle.collectConfigMap(kubeClient, cluster)
+			bundle.collectOperatorLogs(kubeClient, clientSet, cluster, operatorName, since)
+			if !skipStatus {
+				bundle.collectStatus(config, clientSet, pods)
+			}
+
+			for _, collectionErr := range bundle.errors {
+				printStatement(cmd, collectionErr, warnMessage)
+			}
+
+			err = bundle.close()
+			if err != nil {
+				return err
+			}
+
+			printStatement(cmd, fmt.Sprintf("support bundle for cluster %s/%s written to %s", cluster.Namespace, cluster.Name, output), goodMessage)
+
+			return nil
+		},
+		Example: `
+# Collect a support bundle for cluster c1
+kubectl fdb support-bundle c1
+
+# Collect a support bundle for cluster c1 in the namespace default with the operator logs of the last 6 hours
+kubectl fdb -n default support-bundle c1 --since=6h
+
+# Collect a support bundle for cluster c1 without running fdbcli, e.g. if the database is unavailable
+kubectl fdb support-bundle c1 --skip-status --output=/tmp/c1.tar.gz
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	cmd.Flags().String("output", "", "The path of the tarball, defaults to <cluster>-support-bundle-<timestamp>.tar.gz in the current directory.")
+	cmd.Flags().Duration("since", 1*time.Hour, "Only collect operator logs that are newer than this duration.")
+	cmd.Flags().Bool("skip-status", false, "Skip the collection of the machine-readable status.")
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// supportBundle writes the collected files of a cluster into a gzipped tarball. Errors during the collection are
+// recorded in the bundle, so that a support bundle can be created even if some information is unavailable.
+type supportBundle struct {
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+	prefix     string
+	errors     []string
+}
+
+// newSupportBundle creates a new support bundle that writes into the provided writer.
+func newSupportBundle(writer io.Writer, clusterName string) *supportBundle {
+	gzipWriter := gzip.NewWriter(writer)
+
+	return &supportBundle{
+		gzipWriter: gzipWriter,
+		tarWriter:  tar.NewWriter(gzipWriter),
+		prefix:     clusterName,
+	}
+}
+
+// addError records an error that occurred during the collection.
+func (bundle *supportBundle) addError(format string, args ...interface{}) {
+	bundle.errors = append(bundle.errors, fmt.Sprintf(format, args...))
+}
+
+// addFile adds a file with the provided content to the bundle.
+func (bundle *supportBundle) addFile(name string, content []byte) {
+	err := bundle.tarWriter.WriteHeader(&tar.Header{
+		Name:    path.Join(bundle.prefix, name),
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		bundle.addError("could not write %s: %s", name, err.Error())
+		return
+	}
+
+	_, err = bundle.tarWriter.Write(content)
+	if err != nil {
+		bundle.addError("could not write %s: %s", name, err.Error())
+	}
+}
+
+// addYAML adds the YAML representation of the object to the bundle.
+func (bundle *supportBundle) addYAML(name string, object interface{}) {
+	content, err := yaml.Marshal(object)
+	if err != nil {
+		bundle.addError("could not marshal %s: %s", name, err.Error())
+		return
+	}
+
+	bundle.addFile(name, content)
+}
+
+// close writes the collection errors into the bundle and flushes the tarball.
+func (bundle *supportBundle) close() error {
+	if len(bundle.errors) > 0 {
+		bundle.addFile("errors.txt", []byte(strings.Join(bundle.errors, "\n")+"\n"))
+	}
+
+	err := bundle.tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return bundle.gzipWriter.Close()
+}
+
+// collectCluster adds the spec and status of the cluster to the bundle.
+func (bundle *supportBundle) collectCluster(cluster *fdbv1beta2.FoundationDBCluster) {
+	sanitized := cluster.DeepCopy()
+	sanitizeObjectMeta(&sanitized.ObjectMeta)
+	for processClass, settings := range sanitized.Spec.Processes {
+		if settings.PodTemplate != nil {
+			redactPodSpec(&settings.PodTemplate.Spec)
+		}
+		settings.CustomParameters = redactCustomParameters(settings.CustomParameters)
+		sanitized.Spec.Processes[processClass] = settings
+	}
+
+	bundle.addYAML("cluster.yaml", sanitized)
+}
+
+// collectPods adds the Pods of the cluster to the bundle and returns them.
+func (bundle *supportBundle) collectPods(kubeClient client.Client, cluster *fdbv1beta2.FoundationDBCluster) *corev1.PodList {
+	pods, err := getPodsForCluster(kubeClient, cluster)
+	if err != nil {
+		bundle.addError("could not list Pods: %s", err.Error())
+		return &corev1.PodList{}
+	}
+
+	for _, pod := range pods.Items {
+		sanitized := pod.DeepCopy()
+		sanitizeObjectMeta(&sanitized.ObjectMeta)
+		redactPodSpec(&sanitized.Spec)
+		bundle.addYAML(path.Join("pods", pod.Name+".yaml"), sanitized)
+	}
+
+	return pods
+}
+
+// collectEvents adds the events of the cluster and its Pods to the bundle.
+func (bundle *supportBundle) collectEvents(kubeClient client.Client, cluster *fdbv1beta2.FoundationDBCluster, pods *corev1.PodList) {
+	eventList := &corev1.EventList{}
+	err := kubeClient.List(ctx.Background(), eventList, client.InNamespace(cluster.Namespace))
+	if err != nil {
+		bundle.addError("could not list events: %s", err.Error())
+		return
+	}
+
+	involvedObjects := map[string]fdbv1beta2.None{
+		cluster.Name: {},
+	}
+	for _, pod := range pods.Items {
+		involvedObjects[pod.Name] = fdbv1beta2.None{}
+	}
+
+	events := make([]corev1.Event, 0, len(eventList.Items))
+	for _, event := range eventList.Items {
+		if _, ok := involvedObjects[event.InvolvedObject.Name]; !ok {
+			continue
+		}
+
+		if event.InvolvedObject.Kind != "Pod" && event.InvolvedObject.Kind != "FoundationDBCluster" {
+			continue
+		}
+
+		sanitizeObjectMeta(&event.ObjectMeta)
+		events = append(events, event)
+	}
+
+	bundle.addYAML("events.yaml", events)
+}
+
+// collectConfigMap adds the monitor conf contents and the other entries of the cluster ConfigMap to the bundle.
+func (bundle *supportBundle) collectConfigMap(kubeClient client.Client, cluster *fdbv1beta2.FoundationDBCluster) {
+	desiredConfigMap, err := internal.GetConfigMap(logr.NewContext(ctx.Background(), logr.Discard()), cluster)
+	if err != nil {
+		bundle.addError("could not generate ConfigMap name: %s", err.Error())
+		return
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = kubeClient.Get(ctx.Background(), types.NamespacedName{Namespace: cluster.Namespace, Name: desiredConfigMap.Name}, configMap)
+	if err != nil {
+		bundle.addError("could not get ConfigMap %s: %s", desiredConfigMap.Name, err.Error())
+		return
+	}
+
+	// The monitor conf contains the custom parameters of the processes, which might contain credentials.
+	for key, value := range configMap.Data {
+		bundle.addFile(path.Join("config", key), []byte(internal.RedactString(value)))
+	}
+}
+
+// collectOperatorLogs adds the log lines of the operator Pods that belong to the cluster to the bundle.
+func (bundle *supportBundle) collectOperatorLogs(kubeClient client.Client, clientSet kubernetes.Interface, cluster *fdbv1beta2.FoundationDBCluster, operatorName string, since time.Duration) {
+	operator, err := getOperator(kubeClient, operatorName, cluster.Namespace)
+	if err != nil {
+		bundle.addError("could not get operator deployment %s: %s", operatorName, err.Error())
+		return
+	}
+
+	if operator.Spec.Selector == nil {
+		bundle.addError("operator deployment %s has no selector", operatorName)
+		return
+	}
+
+	operatorPods := &corev1.PodList{}
+	err = kubeClient.List(ctx.Background(), operatorPods, client.InNamespace(cluster.Namespace), client.MatchingLabels(operator.Spec.Selector.MatchLabels))
+	if err != nil {
+		bundle.addError("could not list operator Pods: %s", err.Error())
+		return
+	}
+
+	sinceSeconds := int64(since.Seconds())
+	for _, pod := range operatorPods.Items {
+		logs, err := clientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{SinceSeconds: &sinceSeconds}).DoRaw(ctx.Background())
+		if err != nil {
+			bundle.addError("could not get logs of operator Pod %s: %s", pod.Name, err.Error())
+			continue
+		}
+
+		bundle.addFile(path.Join("operator-logs", pod.Name+".log"), filterLogsForCluster(logs, cluster))
+	}
+}
+
+// collectStatus adds the machine-readable status of the cluster to the bundle.
+func (bundle *supportBundle) collectStatus(restConfig *rest.Config, clientSet *kubernetes.Clientset, pods *corev1.PodList) {
+	pod, err := chooseRandomPod(pods)
+	if err != nil {
+		bundle.addError("could not get machine-readable status: %s", err.Error())
+		return
+	}
+
+	stdout, stderr, err := executeCmd(restConfig, clientSet, pod.Name, pod.Namespace, "fdbcli --exec 'status json'")
+	if err != nil {
+		bundle.addError("could not get machine-readable status: %s, %s", stderr, err.Error())
+		return
+	}
+
+	content, err := internal.RemoveWarningsInJSON(stdout.String())
+	if err != nil {
+		bundle.addError("could not parse machine-readable status: %s", err.Error())
+		return
+	}
+
+	bundle.addFile("status.json", []byte(internal.RedactString(string(content))))
+}
+
+// filterLogsForCluster returns the structured log lines that contain the namespace and the name of the cluster, with
+// the TLS passwords and blob credentials masked.
+func filterLogsForCluster(logs []byte, cluster *fdbv1beta2.FoundationDBCluster) []byte {
+	clusterMatcher := fmt.Sprintf("%q:%q", "cluster", cluster.Name)
+	namespaceMatcher := fmt.Sprintf("%q:%q", "namespace", cluster.Namespace)
+
+	var filtered bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, clusterMatcher) || !strings.Contains(line, namespaceMatcher) {
+			continue
+		}
+
+		filtered.WriteString(internal.RedactString(line))
+		filtered.WriteString("\n")
+	}
+
+	return filtered.Bytes()
+}
+
+// sanitizeObjectMeta removes the metadata that is not useful for debugging or that might contain a copy of
+// unredacted information.
+func sanitizeObjectMeta(metadata *metav1.ObjectMeta) {
+	metadata.ManagedFields = nil
+	delete(metadata.Annotations, corev1.LastAppliedConfigAnnotation)
+}
+
+// redactPodSpec redacts the values of environment variables that might contain credentials.
+func redactPodSpec(spec *corev1.PodSpec) {
+	for idx := range spec.InitContainers {
+		redactContainer(&spec.InitContainers[idx])
+	}
+
+	for idx := range spec.Containers {
+		redactContainer(&spec.Containers[idx])
+	}
+}
+
+// redactCustomParameters returns a copy of the custom parameters with the TLS passwords and blob credentials masked.
+func redactCustomParameters(parameters fdbv1beta2.FoundationDBCustomParameters) fdbv1beta2.FoundationDBCustomParameters {
+	if parameters == nil {
+		return nil
+	}
+
+	redacted := make(fdbv1beta2.FoundationDBCustomParameters, len(parameters))
+	for idx, parameter := range parameters {
+		redacted[idx] = fdbv1beta2.FoundationDBCustomParameter(internal.RedactString(string(parameter)))
+	}
+
+	return redacted
+}
+
+// redactContainer redacts the values of environment variables of the container that might contain credentials.
+func redactContainer(container *corev1.Container) {
+	for idx, env := range container.Env {
+		if env.Value == "" || !sensitiveEnvNamePattern.MatchString(env.Name) {
+			continue
+		}
+
+		container.Env[idx].Value = redactedValue
+	}
+}
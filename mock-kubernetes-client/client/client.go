@@ -45,6 +45,12 @@ type MockClient struct {
 	// ipCounter provides monotonically incrementing IP addresses.
 	ipCounter int
 
+	// nodePortCounter provides monotonically incrementing node ports for services.
+	nodePortCounter int32
+
+	// hostIPCounter provides monotonically incrementing IP addresses for the nodes of the Pods.
+	hostIPCounter int
+
 	// scheme will be used to initialize or reset the new fake client
 	scheme *runtime.Scheme
 
@@ -73,6 +79,18 @@ func NewMockClientWithHooks(scheme *runtime.Scheme, createHooks []func(ctx conte
 			svc.Spec.ClusterIP = client.generateIP()
 		}
 
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) == 0 {
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: client.generateLoadBalancerIP()}}
+		}
+
+		if svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			for idx := range svc.Spec.Ports {
+				if svc.Spec.Ports[idx].NodePort == 0 {
+					svc.Spec.Ports[idx].NodePort = client.generateNodePort()
+				}
+			}
+		}
+
 		return nil
 	}
 
@@ -85,6 +103,9 @@ func NewMockClientWithHooks(scheme *runtime.Scheme, createHooks []func(ctx conte
 		v4Address := client.generatePodIPv4()
 		pod.Status.PodIP = v4Address
 		pod.Status.PodIPs = []corev1.PodIP{{IP: v4Address}, {IP: client.generatePodIPv6()}}
+		if pod.Status.HostIP == "" {
+			pod.Status.HostIP = client.generateHostIP()
+		}
 
 		if pod.Status.Phase == "" {
 			pod.Status.Phase = corev1.PodRunning
@@ -120,7 +141,7 @@ func (client *MockClient) Clear() {
 
 // Scheme returns the runtime Scheme
 func (client *MockClient) Scheme() *runtime.Scheme {
-	return runtime.NewScheme()
+	return client.scheme
 }
 
 // RESTMapper returns the RESTMapper
@@ -358,6 +379,24 @@ func (client *MockClient) generatePodIPv4() string {
 	return fmt.Sprintf("1.1.%d.%d", client.ipCounter/256, client.ipCounter%256)
 }
 
+// generateHostIP generates a mock IPv4 address for the node of a Pod
+func (client *MockClient) generateHostIP() string {
+	client.hostIPCounter++
+	return fmt.Sprintf("10.1.%d.%d", client.hostIPCounter/256, client.hostIPCounter%256)
+}
+
+// generateNodePort generates a node port in the default node port range
+func (client *MockClient) generateNodePort() int32 {
+	client.nodePortCounter++
+	return 30000 + client.nodePortCounter%2768
+}
+
+// generateLoadBalancerIP generates a mock IPv4 address for a load balancer
+func (client *MockClient) generateLoadBalancerIP() string {
+	client.ipCounter++
+	return fmt.Sprintf("172.16.%d.%d", client.ipCounter/256, client.ipCounter%256)
+}
+
 // generatePodIPv6 generates a mock IPv6 address for Pods
 func (client *MockClient) generatePodIPv6() string {
 	client.ipCounter++
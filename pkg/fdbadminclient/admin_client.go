@@ -21,6 +21,9 @@
 package fdbadminclient
 
 import (
+	"context"
+	"time"
+
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 )
 
@@ -28,87 +31,154 @@ import (
 // cluster
 type AdminClient interface {
 	// GetStatus gets the database's status
-	GetStatus() (*fdbv1beta2.FoundationDBStatus, error)
+	GetStatus(ctx context.Context) (*fdbv1beta2.FoundationDBStatus, error)
 
 	// ConfigureDatabase sets the database configuration
-	ConfigureDatabase(configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) error
+	ConfigureDatabase(ctx context.Context, configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) error
 
 	// ExcludeProcesses starts evacuating processes so that they can be removed
 	// from the database.
-	ExcludeProcesses(addresses []fdbv1beta2.ProcessAddress) error
+	ExcludeProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error
 
 	// IncludeProcesses removes processes from the exclusion list and allows
 	// them to take on roles again.
-	IncludeProcesses(addresses []fdbv1beta2.ProcessAddress) error
+	IncludeProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error
 
 	// GetExclusions gets a list of the addresses currently excluded from the
 	// database.
-	GetExclusions() ([]fdbv1beta2.ProcessAddress, error)
+	GetExclusions(ctx context.Context) ([]fdbv1beta2.ProcessAddress, error)
 
 	// CanSafelyRemove checks whether it is safe to remove processes from the
 	// cluster.
 	//
 	// The list returned by this method will be the addresses that are *not*
 	// safe to remove.
-	CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error)
+	CanSafelyRemove(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error)
+
+	// GetInProgressExclusions returns the addresses of the excluded servers
+	// that still hold data or still host a transaction log. This requires
+	// FDB 7.0 or newer.
+	GetInProgressExclusions(ctx context.Context) ([]fdbv1beta2.ProcessAddress, error)
 
 	// KillProcesses restarts processes
-	KillProcesses(addresses []fdbv1beta2.ProcessAddress) error
+	KillProcesses(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) error
 
 	// ChangeCoordinators changes the coordinator set
-	ChangeCoordinators(addresses []fdbv1beta2.ProcessAddress) (string, error)
+	ChangeCoordinators(ctx context.Context, addresses []fdbv1beta2.ProcessAddress) (string, error)
+
+	// ChangeClusterDescription changes the description in the connection string
+	// while keeping the current coordinators.
+	ChangeClusterDescription(ctx context.Context, description string) (string, error)
 
 	// GetConnectionString fetches the latest connection string.
-	GetConnectionString() (string, error)
+	GetConnectionString(ctx context.Context) (string, error)
 
 	// VersionSupported reports whether we can support a cluster with a given
 	// version.
-	VersionSupported(version string) (bool, error)
+	VersionSupported(ctx context.Context, version string) (bool, error)
 
 	// GetProtocolVersion determines the protocol version that is used by a
 	// version of FDB.
-	GetProtocolVersion(version string) (string, error)
+	GetProtocolVersion(ctx context.Context, version string) (string, error)
 
-	// StartBackup starts a new backup.
-	StartBackup(url string, snapshotPeriodSeconds int) error
+	// StartBackup starts a new backup. If stopAfterSnapshot is true, the
+	// backup stops once a snapshot is restorable instead of writing a
+	// continuous mutation log.
+	StartBackup(ctx context.Context, url string, snapshotPeriodSeconds int, stopAfterSnapshot bool) error
 
 	// StopBackup stops a backup.
-	StopBackup(url string) error
+	StopBackup(ctx context.Context, url string) error
 
 	// PauseBackups pauses the backups.
-	PauseBackups() error
+	PauseBackups(ctx context.Context) error
 
 	// ResumeBackups resumes the backups.
-	ResumeBackups() error
+	ResumeBackups(ctx context.Context) error
 
 	// ModifyBackup modifies the configuration of the backup.
-	ModifyBackup(int) error
+	ModifyBackup(ctx context.Context, snapshotPeriodSeconds int) error
 
 	// GetBackupStatus gets the status of the current backup.
-	GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBackupStatus, error)
+	GetBackupStatus(ctx context.Context) (*fdbv1beta2.FoundationDBLiveBackupStatus, error)
 
-	// StartRestore starts a new restore.
-	StartRestore(url string, keyRanges []fdbv1beta2.FoundationDBKeyRange) error
+	// StartRestore starts a new restore. The addPrefix and removePrefix are
+	// applied to all restored keys if they are not empty.
+	StartRestore(ctx context.Context, url string, keyRanges []fdbv1beta2.FoundationDBKeyRange, addPrefix string, removePrefix string) error
 
 	// GetRestoreStatus gets the status of the current restore.
-	GetRestoreStatus() (string, error)
+	GetRestoreStatus(ctx context.Context) (string, error)
 
 	// Close shuts down any resources for the client once it is no longer
 	// needed.
 	Close() error
 
 	// GetCoordinatorSet returns a set of the current coordinators.
-	GetCoordinatorSet() (map[string]fdbv1beta2.None, error)
+	GetCoordinatorSet(ctx context.Context) (map[string]fdbv1beta2.None, error)
 
 	// SetKnobs sets the Knobs that should be used for the commandline call.
 	SetKnobs([]string)
 
 	// GetMaintenanceZone gets current maintenance zone, if any
-	GetMaintenanceZone() (string, error)
+	GetMaintenanceZone(ctx context.Context) (string, error)
 
 	// SetMaintenanceZone places zone into maintenance mode
-	SetMaintenanceZone(zone string, timeoutSeconds int) error
+	SetMaintenanceZone(ctx context.Context, zone string, timeoutSeconds int) error
 
 	// Reset maintenance mode
-	ResetMaintenanceMode() error
+	ResetMaintenanceMode(ctx context.Context) error
+
+	// CreateTenant creates a new tenant with the provided name.
+	CreateTenant(ctx context.Context, name string) error
+
+	// DeleteTenant deletes the tenant with the provided name. The tenant must be empty.
+	DeleteTenant(ctx context.Context, name string) error
+
+	// ListTenants returns all tenants of the cluster.
+	ListTenants(ctx context.Context) ([]fdbv1beta2.TenantStatus, error)
+
+	// GetTagQuota returns the throughput quota of the provided transaction tag.
+	GetTagQuota(ctx context.Context, tag string) (fdbv1beta2.TagQuota, error)
+
+	// SetTagQuota sets the throughput quota of a transaction tag. If both throughput values are 0, the quota
+	// will be cleared.
+	SetTagQuota(ctx context.Context, quota fdbv1beta2.TagQuota) error
+
+	// IsDataDistributionEnabled returns true if data distribution is enabled in the cluster.
+	IsDataDistributionEnabled(ctx context.Context) (bool, error)
+
+	// SetDataDistributionMode enables or disables data distribution.
+	SetDataDistributionMode(ctx context.Context, enabled bool) error
+
+	// LockDatabase locks the database, so that only lock aware transactions can commit. The returned lock UID
+	// must be used to unlock the database.
+	LockDatabase(ctx context.Context) (string, error)
+
+	// UnlockDatabase removes the lock with the provided lock UID from the database.
+	UnlockDatabase(ctx context.Context, lockUID string) error
+
+	// GetReadVersion returns the current read version of the database. This also works if the database is locked.
+	GetReadVersion(ctx context.Context) (int64, error)
+
+	// ProbeLatency measures the latency of getting a read version, reading a key and committing a transaction.
+	ProbeLatency(ctx context.Context) (*LatencyProbeResult, error)
+
+	// GetWorkJournal returns the work journal of the cluster that is stored in the database. If no work journal is
+	// stored, nil will be returned.
+	GetWorkJournal(ctx context.Context) (*WorkJournal, error)
+
+	// UpdateWorkJournal stores the work journal of the cluster in the database. An empty work journal will be
+	// removed from the database.
+	UpdateWorkJournal(ctx context.Context, journal *WorkJournal) error
+}
+
+// LatencyProbeResult contains the latencies measured by a single latency probe.
+type LatencyProbeResult struct {
+	// GRVLatency is the time it took to get a read version.
+	GRVLatency time.Duration
+
+	// ReadLatency is the time it took to read a single key.
+	ReadLatency time.Duration
+
+	// CommitLatency is the time it took to commit a transaction that writes a single key.
+	CommitLatency time.Duration
 }
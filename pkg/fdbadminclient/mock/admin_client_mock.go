@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +37,8 @@ import (
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -45,12 +48,14 @@ type AdminClient struct {
 	KubeClient                               client.Client
 	DatabaseConfiguration                    *fdbv1beta2.DatabaseConfiguration
 	ExcludedAddresses                        map[string]fdbv1beta2.None
+	InProgressExclusions                     map[string]fdbv1beta2.None
 	KilledAddresses                          map[string]fdbv1beta2.None
 	Knobs                                    map[string]fdbv1beta2.None
 	missingLocalities                        map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None
 	missingProcessGroups                     map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None
 	incorrectCommandLines                    map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None
 	FrozenStatus                             *fdbv1beta2.FoundationDBStatus
+	StatusError                              error
 	Backups                                  map[string]fdbv1beta2.FoundationDBBackupStatusBackupDetails
 	clientVersions                           map[string][]string
 	currentCommandLines                      map[string]string
@@ -62,8 +67,24 @@ type AdminClient struct {
 	MaxZoneFailuresWithoutLosingAvailability *int
 	MaintenanceZone                          string
 	restoreURL                               string
+	RestoreKeyRanges                         []fdbv1beta2.FoundationDBKeyRange
+	RestoreAddPrefix                         string
+	RestoreRemovePrefix                      string
+	RestoreState                             string
 	maintenanceZoneStartTimestamp            time.Time
 	uptimeSecondsForMaintenanceZone          float64
+	Tenants                                  map[string]fdbv1beta2.TenantStatus
+	nextTenantID                             int64
+	TagQuotas                                map[string]fdbv1beta2.TagQuota
+	DataDistributionDisabled                 bool
+	WiggledAddresses                         []string
+	StorageDiskInfo                          fdbv1beta2.FoundationDBStatusProcessDiskInfo
+	LockUID                                  string
+	readVersion                              int64
+	LatencyProbeResult                       *fdbadminclient.LatencyProbeResult
+	LatencyProbeError                        error
+	LatencyProbes                            int
+	WorkJournal                              *fdbadminclient.WorkJournal
 }
 
 // adminClientCache provides a cache of mock admin clients.
@@ -89,6 +110,7 @@ func NewMockAdminClientUncast(cluster *fdbv1beta2.FoundationDBCluster, kubeClien
 			Cluster:               cluster.DeepCopy(),
 			KubeClient:            kubeClient,
 			ExcludedAddresses:     make(map[string]fdbv1beta2.None),
+			InProgressExclusions:  make(map[string]fdbv1beta2.None),
 			ReincludedAddresses:   make(map[string]bool),
 			KilledAddresses:       make(map[string]fdbv1beta2.None),
 			missingProcessGroups:  make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None),
@@ -98,6 +120,8 @@ func NewMockAdminClientUncast(cluster *fdbv1beta2.FoundationDBCluster, kubeClien
 			currentCommandLines:   make(map[string]string),
 			Knobs:                 make(map[string]fdbv1beta2.None),
 			VersionProcessGroups:  make(map[fdbv1beta2.ProcessGroupID]string),
+			Tenants:               make(map[string]fdbv1beta2.TenantStatus),
+			TagQuotas:             make(map[string]fdbv1beta2.TagQuota),
 		}
 		adminClientCache[cluster.Name] = cachedClient
 		cachedClient.Backups = make(map[string]fdbv1beta2.FoundationDBBackupStatusBackupDetails)
@@ -114,10 +138,14 @@ func ClearMockAdminClients() {
 }
 
 // GetStatus gets the database's status
-func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
+func (client *AdminClient) GetStatus(ctx context.Context) (*fdbv1beta2.FoundationDBStatus, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
+	if client.StatusError != nil {
+		return nil, client.StatusError
+	}
+
 	if client.FrozenStatus != nil {
 		return client.FrozenStatus, nil
 	}
@@ -159,7 +187,7 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 			continue
 		}
 
-		subs, err := podClient.GetVariableSubstitutions()
+		subs, err := podClient.GetVariableSubstitutions(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -169,10 +197,15 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 			processIP = pod.Status.PodIP
 		}
 
+		pClass, err := podmanager.GetProcessClass(client.Cluster, &pod)
+		if err != nil {
+			return nil, err
+		}
+
 		for processIndex := 1; processIndex <= processCount; processIndex++ {
 			var fdbRoles []fdbv1beta2.FoundationDBStatusProcessRoleInfo
 
-			fullAddress := client.Cluster.GetFullAddress(processIP, processIndex)
+			fullAddress := client.Cluster.GetFullAddressForProcessClass(processIP, processIndex, pClass)
 			_, ipExcluded := client.ExcludedAddresses[processIP]
 			_, addressExcluded := client.ExcludedAddresses[fullAddress.String()]
 			excluded := ipExcluded || addressExcluded
@@ -182,15 +215,10 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 				fdbRoles = append(fdbRoles, fdbv1beta2.FoundationDBStatusProcessRoleInfo{Role: string(fdbv1beta2.ProcessRoleCoordinator)})
 			}
 
-			pClass, err := podmanager.GetProcessClass(client.Cluster, &pod)
-			if err != nil {
-				return nil, err
-			}
-
 			command, hasCommandLine := client.currentCommandLines[fullAddress.StringWithoutFlags()]
 			if !hasCommandLine {
 				// We only set the command if we don't have the commandline "cached"
-				command, err = internal.GetStartCommand(client.Cluster, pClass, podClient, processIndex, processCount)
+				command, err = internal.GetStartCommand(ctx, client.Cluster, pClass, podClient, processIndex, processCount)
 				if err != nil {
 					return nil, err
 				}
@@ -263,15 +291,22 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 				}
 			}
 
+			processClass := internal.GetProcessClassFromMeta(client.Cluster, pod.ObjectMeta)
+			var disk fdbv1beta2.FoundationDBStatusProcessDiskInfo
+			if processClass == fdbv1beta2.ProcessClassStorage {
+				disk = client.StorageDiskInfo
+			}
+
 			status.Cluster.Processes[fdbv1beta2.ProcessGroupID(fmt.Sprintf("%s-%d", pod.Name, processIndex))] = fdbv1beta2.FoundationDBStatusProcessInfo{
 				Address:       fullAddress,
-				ProcessClass:  internal.GetProcessClassFromMeta(client.Cluster, pod.ObjectMeta),
+				ProcessClass:  processClass,
 				CommandLine:   command,
 				Excluded:      excluded,
 				Locality:      locality,
 				Version:       version,
 				UptimeSeconds: uptimeSeconds,
 				Roles:         fdbRoles,
+				Disk:          disk,
 			}
 		}
 
@@ -294,7 +329,7 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 				}
 			}
 
-			fullAddress := client.Cluster.GetFullAddress(processGroup.Addresses[0], 1)
+			fullAddress := client.Cluster.GetFullAddressForProcessClass(processGroup.Addresses[0], 1, processGroup.ProcessClass)
 			status.Cluster.Processes[processGroup.ProcessGroupID] = fdbv1beta2.FoundationDBStatusProcessInfo{
 				Address:       fullAddress,
 				ProcessClass:  processGroup.ProcessClass,
@@ -308,7 +343,7 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 	if client.clientVersions != nil {
 		supportedVersions := make([]fdbv1beta2.FoundationDBStatusSupportedVersion, 0, len(client.clientVersions))
 		for version, addresses := range client.clientVersions {
-			protocolVersion, err := client.GetProtocolVersion(version)
+			protocolVersion, err := client.GetProtocolVersion(ctx, version)
 			if err != nil {
 				return nil, err
 			}
@@ -382,11 +417,12 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 		status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingAvailability = client.Cluster.DesiredFaultTolerance() - faultToleranceSubtractor
 	}
 	status.Cluster.MaintenanceZone = client.MaintenanceZone
+	status.Cluster.StorageWiggler.WiggleServerAddresses = client.WiggledAddresses
 	return status, nil
 }
 
 // ConfigureDatabase changes the database configuration
-func (client *AdminClient) ConfigureDatabase(configuration fdbv1beta2.DatabaseConfiguration, _ bool, version string) error {
+func (client *AdminClient) ConfigureDatabase(_ context.Context, configuration fdbv1beta2.DatabaseConfiguration, _ bool, version string) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -407,7 +443,7 @@ func (client *AdminClient) ConfigureDatabase(configuration fdbv1beta2.DatabaseCo
 
 // ExcludeProcesses starts evacuating processes so that they can be removed
 // from the database.
-func (client *AdminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+func (client *AdminClient) ExcludeProcesses(_ context.Context, addresses []fdbv1beta2.ProcessAddress) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -420,7 +456,7 @@ func (client *AdminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAddres
 
 // IncludeProcesses removes processes from the exclusion list and allows
 // them to take on roles again.
-func (client *AdminClient) IncludeProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+func (client *AdminClient) IncludeProcesses(_ context.Context, addresses []fdbv1beta2.ProcessAddress) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -440,7 +476,7 @@ func (client *AdminClient) IncludeProcesses(addresses []fdbv1beta2.ProcessAddres
 //
 // The list returned by this method will be the addresses that are *not*
 // safe to remove.
-func (client *AdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error) {
+func (client *AdminClient) CanSafelyRemove(_ context.Context, addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error) {
 	skipExclude := map[string]fdbv1beta2.None{}
 
 	// Check which process groups have the skip exclusion flag or are already
@@ -465,8 +501,9 @@ func (client *AdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress
 	remaining := make([]fdbv1beta2.ProcessAddress, 0, len(addresses))
 
 	for _, addr := range addresses {
-		// Is already excluded or skipped
-		if _, ok := skipExclude[addr.String()]; ok {
+		// Is already excluded or skipped and doesn't hold any data
+		_, hasData := client.InProgressExclusions[addr.String()]
+		if _, ok := skipExclude[addr.String()]; ok && !hasData {
 			continue
 		}
 
@@ -476,9 +513,28 @@ func (client *AdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress
 	return remaining, nil
 }
 
+// GetInProgressExclusions returns the addresses of the excluded servers that still hold data or still host a
+// transaction log.
+func (client *AdminClient) GetInProgressExclusions(_ context.Context) ([]fdbv1beta2.ProcessAddress, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	addresses := make([]fdbv1beta2.ProcessAddress, 0, len(client.InProgressExclusions))
+	for addr := range client.InProgressExclusions {
+		address, err := fdbv1beta2.ParseProcessAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
 // GetExclusions gets a list of the addresses currently excluded from the
 // database.
-func (client *AdminClient) GetExclusions() ([]fdbv1beta2.ProcessAddress, error) {
+func (client *AdminClient) GetExclusions(_ context.Context) ([]fdbv1beta2.ProcessAddress, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -495,7 +551,7 @@ func (client *AdminClient) GetExclusions() ([]fdbv1beta2.ProcessAddress, error)
 }
 
 // KillProcesses restarts processes
-func (client *AdminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+func (client *AdminClient) KillProcesses(_ context.Context, addresses []fdbv1beta2.ProcessAddress) error {
 	adminClientMutex.Lock()
 	for _, addr := range addresses {
 		client.KilledAddresses[addr.String()] = fdbv1beta2.None{}
@@ -520,7 +576,7 @@ func (client *AdminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddress)
 }
 
 // ChangeCoordinators changes the coordinator set
-func (client *AdminClient) ChangeCoordinators(addresses []fdbv1beta2.ProcessAddress) (string, error) {
+func (client *AdminClient) ChangeCoordinators(_ context.Context, addresses []fdbv1beta2.ProcessAddress) (string, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -541,8 +597,26 @@ func (client *AdminClient) ChangeCoordinators(addresses []fdbv1beta2.ProcessAddr
 	return connectionString.String(), err
 }
 
+// ChangeClusterDescription changes the description in the connection string while keeping the current coordinators.
+func (client *AdminClient) ChangeClusterDescription(_ context.Context, description string) (string, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	connectionString, err := fdbv1beta2.ParseConnectionString(client.Cluster.Status.ConnectionString)
+	if err != nil {
+		return "", err
+	}
+	err = connectionString.GenerateNewGenerationID()
+	if err != nil {
+		return "", err
+	}
+
+	connectionString.DatabaseName = description
+	return connectionString.String(), err
+}
+
 // GetConnectionString fetches the latest connection string.
-func (client *AdminClient) GetConnectionString() (string, error) {
+func (client *AdminClient) GetConnectionString(_ context.Context) (string, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -551,7 +625,7 @@ func (client *AdminClient) GetConnectionString() (string, error) {
 
 // VersionSupported reports whether we can support a cluster with a given
 // version.
-func (client *AdminClient) VersionSupported(versionString string) (bool, error) {
+func (client *AdminClient) VersionSupported(_ context.Context, versionString string) (bool, error) {
 	version, err := fdbv1beta2.ParseFdbVersion(versionString)
 	if err != nil {
 		return false, err
@@ -566,12 +640,12 @@ func (client *AdminClient) VersionSupported(versionString string) (bool, error)
 
 // GetProtocolVersion determines the protocol version that is used by a
 // version of FDB.
-func (client *AdminClient) GetProtocolVersion(version string) (string, error) {
+func (client *AdminClient) GetProtocolVersion(_ context.Context, version string) (string, error) {
 	return version, nil
 }
 
 // StartBackup starts a new backup.
-func (client *AdminClient) StartBackup(url string, snapshotPeriodSeconds int) error {
+func (client *AdminClient) StartBackup(_ context.Context, url string, snapshotPeriodSeconds int, stopAfterSnapshot bool) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -579,12 +653,13 @@ func (client *AdminClient) StartBackup(url string, snapshotPeriodSeconds int) er
 		URL:                   url,
 		Running:               true,
 		SnapshotPeriodSeconds: snapshotPeriodSeconds,
+		StopAfterSnapshot:     stopAfterSnapshot,
 	}
 	return nil
 }
 
 // PauseBackups pauses backups.
-func (client *AdminClient) PauseBackups() error {
+func (client *AdminClient) PauseBackups(_ context.Context) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -596,7 +671,7 @@ func (client *AdminClient) PauseBackups() error {
 }
 
 // ResumeBackups resumes backups.
-func (client *AdminClient) ResumeBackups() error {
+func (client *AdminClient) ResumeBackups(_ context.Context) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -608,7 +683,7 @@ func (client *AdminClient) ResumeBackups() error {
 }
 
 // ModifyBackup reconfigures the backup.
-func (client *AdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
+func (client *AdminClient) ModifyBackup(_ context.Context, snapshotPeriodSeconds int) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -619,7 +694,7 @@ func (client *AdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
 }
 
 // StopBackup stops a backup.
-func (client *AdminClient) StopBackup(url string) error {
+func (client *AdminClient) StopBackup(_ context.Context, url string) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -634,7 +709,7 @@ func (client *AdminClient) StopBackup(url string) error {
 }
 
 // GetBackupStatus gets the status of the current backup.
-func (client *AdminClient) GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBackupStatus, error) {
+func (client *AdminClient) GetBackupStatus(_ context.Context) (*fdbv1beta2.FoundationDBLiveBackupStatus, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -645,27 +720,43 @@ func (client *AdminClient) GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBackup
 	if present {
 		status.DestinationURL = backup.URL
 		status.Status.Running = backup.Running
+		status.Status.Completed = backup.Completed
+		status.StopAfterSnapshot = backup.StopAfterSnapshot
 		status.BackupAgentsPaused = backup.Paused
 		status.SnapshotIntervalSeconds = backup.SnapshotPeriodSeconds
+		status.Restorable = backup.Restorable
+		if backup.LastRestorableVersion != nil {
+			status.LatestRestorablePoint = &fdbv1beta2.FoundationDBLiveBackupStatusRestorablePoint{
+				Version:    *backup.LastRestorableVersion,
+				LagSeconds: pointer.Int64Deref(backup.SecondsBehind, 0),
+			}
+		}
 	}
 
 	return status, nil
 }
 
 // StartRestore starts a new restore.
-func (client *AdminClient) StartRestore(url string, _ []fdbv1beta2.FoundationDBKeyRange) error {
+func (client *AdminClient) StartRestore(_ context.Context, url string, keyRanges []fdbv1beta2.FoundationDBKeyRange, addPrefix string, removePrefix string) error {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
 	client.restoreURL = url
+	client.RestoreKeyRanges = keyRanges
+	client.RestoreAddPrefix = addPrefix
+	client.RestoreRemovePrefix = removePrefix
 	return nil
 }
 
 // GetRestoreStatus gets the status of the current restore.
-func (client *AdminClient) GetRestoreStatus() (string, error) {
+func (client *AdminClient) GetRestoreStatus(_ context.Context) (string, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
+	if client.restoreURL != "" && client.RestoreState != "" {
+		return fmt.Sprintf("%s\nState: %s\n", client.restoreURL, client.RestoreState), nil
+	}
+
 	return fmt.Sprintf("%s\n", client.restoreURL), nil
 }
 
@@ -735,7 +826,7 @@ func (client *AdminClient) Close() error {
 // UnfreezeStatus is called, or another method is called which would invalidate
 // the status.
 func (client *AdminClient) FreezeStatus() error {
-	status, err := client.GetStatus()
+	status, err := client.GetStatus(context.TODO())
 	if err != nil {
 		return err
 	}
@@ -757,8 +848,8 @@ func (client *AdminClient) UnfreezeStatus() {
 }
 
 // GetCoordinatorSet gets the current coordinators from the status
-func (client *AdminClient) GetCoordinatorSet() (map[string]fdbv1beta2.None, error) {
-	status, err := client.GetStatus()
+func (client *AdminClient) GetCoordinatorSet(ctx context.Context) (map[string]fdbv1beta2.None, error) {
+	status, err := client.GetStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -775,19 +866,19 @@ func (client *AdminClient) SetKnobs(knobs []string) {
 }
 
 // GetMaintenanceZone gets current maintenance zone, if any
-func (client *AdminClient) GetMaintenanceZone() (string, error) {
+func (client *AdminClient) GetMaintenanceZone(_ context.Context) (string, error) {
 	return client.MaintenanceZone, nil
 }
 
 // SetMaintenanceZone places zone into maintenance mode
-func (client *AdminClient) SetMaintenanceZone(zone string, _ int) error {
+func (client *AdminClient) SetMaintenanceZone(_ context.Context, zone string, _ int) error {
 	client.MaintenanceZone = zone
 	client.maintenanceZoneStartTimestamp = time.Now()
 	return nil
 }
 
 // ResetMaintenanceMode resets the maintenance zone
-func (client *AdminClient) ResetMaintenanceMode() error {
+func (client *AdminClient) ResetMaintenanceMode(_ context.Context) error {
 	client.MaintenanceZone = ""
 	return nil
 }
@@ -796,3 +887,187 @@ func (client *AdminClient) ResetMaintenanceMode() error {
 func (client *AdminClient) MockUptimeSecondsForMaintenanceZone(seconds float64) {
 	client.uptimeSecondsForMaintenanceZone = seconds
 }
+
+// CreateTenant creates a new tenant with the provided name.
+func (client *AdminClient) CreateTenant(_ context.Context, name string) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if _, ok := client.Tenants[name]; ok {
+		return fmt.Errorf("tenant %s already exists", name)
+	}
+
+	id := client.nextTenantID
+	client.nextTenantID++
+	client.Tenants[name] = fdbv1beta2.TenantStatus{
+		Name:   name,
+		ID:     id,
+		Prefix: fmt.Sprintf("\\x00\\x00\\x00\\x00\\x00\\x00\\x00\\x%02x", id),
+	}
+
+	return nil
+}
+
+// DeleteTenant deletes the tenant with the provided name.
+func (client *AdminClient) DeleteTenant(_ context.Context, name string) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if _, ok := client.Tenants[name]; !ok {
+		return fmt.Errorf("tenant %s does not exist", name)
+	}
+
+	delete(client.Tenants, name)
+
+	return nil
+}
+
+// ListTenants returns all tenants of the cluster.
+func (client *AdminClient) ListTenants(_ context.Context) ([]fdbv1beta2.TenantStatus, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	tenants := make([]fdbv1beta2.TenantStatus, 0, len(client.Tenants))
+	for _, tenant := range client.Tenants {
+		tenants = append(tenants, tenant)
+	}
+
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].Name < tenants[j].Name
+	})
+
+	return tenants, nil
+}
+
+// GetTagQuota returns the throughput quota of the provided transaction tag.
+func (client *AdminClient) GetTagQuota(_ context.Context, tag string) (fdbv1beta2.TagQuota, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	quota, ok := client.TagQuotas[tag]
+	if !ok {
+		return fdbv1beta2.TagQuota{Tag: tag}, nil
+	}
+
+	return quota, nil
+}
+
+// SetTagQuota sets the throughput quota of a transaction tag.
+func (client *AdminClient) SetTagQuota(_ context.Context, quota fdbv1beta2.TagQuota) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if quota.TotalThroughput == 0 && quota.ReservedThroughput == 0 {
+		delete(client.TagQuotas, quota.Tag)
+		return nil
+	}
+
+	client.TagQuotas[quota.Tag] = quota
+
+	return nil
+}
+
+// IsDataDistributionEnabled returns true if data distribution is enabled in the cluster.
+func (client *AdminClient) IsDataDistributionEnabled(_ context.Context) (bool, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	return !client.DataDistributionDisabled, nil
+}
+
+// SetDataDistributionMode enables or disables data distribution.
+func (client *AdminClient) SetDataDistributionMode(_ context.Context, enabled bool) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	client.DataDistributionDisabled = !enabled
+
+	return nil
+}
+
+// LockDatabase locks the database, so that only lock aware transactions can commit. The returned lock UID
+// must be used to unlock the database.
+func (client *AdminClient) LockDatabase(_ context.Context) (string, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if client.LockUID != "" {
+		return "", fmt.Errorf("database is already locked")
+	}
+
+	client.LockUID = strings.ReplaceAll(string(uuid.NewUUID()), "-", "")
+
+	return client.LockUID, nil
+}
+
+// UnlockDatabase removes the lock with the provided lock UID from the database.
+func (client *AdminClient) UnlockDatabase(_ context.Context, lockUID string) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if client.LockUID != "" && client.LockUID != lockUID {
+		return fmt.Errorf("database is locked with lock UID %s instead of %s", client.LockUID, lockUID)
+	}
+
+	client.LockUID = ""
+
+	return nil
+}
+
+// GetReadVersion returns the current read version of the database. This also works if the database is locked.
+func (client *AdminClient) GetReadVersion(_ context.Context) (int64, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	client.readVersion += 1000000
+
+	return client.readVersion, nil
+}
+
+// ProbeLatency measures the latency of getting a read version, reading a key and committing a transaction. The mock
+// returns the LatencyProbeResult and LatencyProbeError of the client.
+func (client *AdminClient) ProbeLatency(_ context.Context) (*fdbadminclient.LatencyProbeResult, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	client.LatencyProbes++
+	if client.LatencyProbeError != nil {
+		return nil, client.LatencyProbeError
+	}
+
+	if client.LatencyProbeResult != nil {
+		return client.LatencyProbeResult, nil
+	}
+
+	return &fdbadminclient.LatencyProbeResult{
+		GRVLatency:    time.Millisecond,
+		ReadLatency:   time.Millisecond,
+		CommitLatency: 5 * time.Millisecond,
+	}, nil
+}
+
+// GetWorkJournal returns the work journal that is stored in the mock client.
+func (client *AdminClient) GetWorkJournal(_ context.Context) (*fdbadminclient.WorkJournal, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if client.WorkJournal == nil {
+		return nil, nil
+	}
+
+	return client.WorkJournal.DeepCopy(), nil
+}
+
+// UpdateWorkJournal stores the work journal in the mock client. An empty work journal will be removed.
+func (client *AdminClient) UpdateWorkJournal(_ context.Context, journal *fdbadminclient.WorkJournal) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if journal.IsEmpty() {
+		client.WorkJournal = nil
+		return nil
+	}
+
+	client.WorkJournal = journal.DeepCopy()
+	return nil
+}
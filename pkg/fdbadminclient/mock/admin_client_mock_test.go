@@ -50,10 +50,10 @@ var _ = Describe("mock_client", func() {
 				admin, err := NewMockAdminClient(input.cluster, nil)
 				Expect(err).NotTo(HaveOccurred())
 
-				err = admin.ExcludeProcesses(input.exclusions)
+				err = admin.ExcludeProcesses(context.TODO(), input.exclusions)
 				Expect(err).NotTo(HaveOccurred())
 
-				remaining, err := admin.CanSafelyRemove(input.removals)
+				remaining, err := admin.CanSafelyRemove(context.TODO(), input.removals)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(remaining).To(ContainElements(input.remaining))
@@ -184,6 +184,37 @@ var _ = Describe("mock_client", func() {
 		)
 	})
 
+	When("an excluded process still holds data", func() {
+		var admin *AdminClient
+		var address fdbv1beta2.ProcessAddress
+
+		BeforeEach(func() {
+			var err error
+			admin, err = NewMockAdminClientUncast(&fdbv1beta2.FoundationDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "in-progress-exclusions"}}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			address = fdbv1beta2.ProcessAddress{IPAddress: net.ParseIP("1.1.1.1"), Port: 4500}
+			Expect(admin.ExcludeProcesses(context.TODO(), []fdbv1beta2.ProcessAddress{address})).NotTo(HaveOccurred())
+			admin.InProgressExclusions[address.String()] = fdbv1beta2.None{}
+		})
+
+		AfterEach(func() {
+			ClearMockAdminClients()
+		})
+
+		It("should report the in progress exclusion", func() {
+			exclusions, err := admin.GetInProgressExclusions(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exclusions).To(ConsistOf(address))
+		})
+
+		It("should not be safe to remove the process", func() {
+			remaining, err := admin.CanSafelyRemove(context.TODO(), []fdbv1beta2.ProcessAddress{address})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(ConsistOf(address))
+		})
+	})
+
 	When("changing the commandline arguments", func() {
 		var adminClient *AdminClient
 		var initialCommandline string
@@ -211,7 +242,7 @@ var _ = Describe("mock_client", func() {
 			var err error
 			adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
 			Expect(err).NotTo(HaveOccurred())
-			status, err := adminClient.GetStatus()
+			status, err := adminClient.GetStatus(context.TODO())
 			Expect(err).NotTo(HaveOccurred())
 
 			initialCommandline = getCommandlineForProcessFromStatus(status, targetProcess)
@@ -240,7 +271,7 @@ var _ = Describe("mock_client", func() {
 
 		When("the process is not restarted", func() {
 			It("should not update the command line arguments", func() {
-				status, err := adminClient.GetStatus()
+				status, err := adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				newCommandline := getCommandlineForProcessFromStatus(status, targetProcess)
 				Expect(newCommandline).To(Equal(initialCommandline))
@@ -250,9 +281,9 @@ var _ = Describe("mock_client", func() {
 
 		When("the process is restarted", func() {
 			It("should update the command line arguments", func() {
-				Expect(adminClient.KillProcesses([]fdbv1beta2.ProcessAddress{processAddress})).NotTo(HaveOccurred())
+				Expect(adminClient.KillProcesses(context.TODO(), []fdbv1beta2.ProcessAddress{processAddress})).NotTo(HaveOccurred())
 				Expect(adminClient.KilledAddresses).To(HaveLen(1))
-				status, err := adminClient.GetStatus()
+				status, err := adminClient.GetStatus(context.TODO())
 				Expect(err).NotTo(HaveOccurred())
 				newCommandline := getCommandlineForProcessFromStatus(status, targetProcess)
 				Expect(newCommandline).NotTo(Equal(initialCommandline))
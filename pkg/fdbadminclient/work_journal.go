@@ -0,0 +1,52 @@
+/*
+ * work_journal.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fdbadminclient
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// WorkJournal contains the state of the operations of the operator that span multiple reconciliations. The work
+// journal is stored in the database of the cluster, so another instance of the operator can resume the operations.
+type WorkJournal struct {
+	// PendingExclusions contains the process groups that are marked for removal and are not removed yet.
+	PendingExclusions []fdbv1beta2.ProcessGroupID `json:"pendingExclusions,omitempty"`
+
+	// CoordinatorChange contains the state of the current coordinator change.
+	CoordinatorChange *fdbv1beta2.CoordinatorChangeStatus `json:"coordinatorChange,omitempty"`
+}
+
+// IsEmpty returns true if the work journal contains no pending operations.
+func (journal *WorkJournal) IsEmpty() bool {
+	return journal == nil || (len(journal.PendingExclusions) == 0 && journal.CoordinatorChange == nil)
+}
+
+// DeepCopy returns a deep copy of the work journal.
+func (journal *WorkJournal) DeepCopy() *WorkJournal {
+	if journal == nil {
+		return nil
+	}
+
+	return &WorkJournal{
+		PendingExclusions: append([]fdbv1beta2.ProcessGroupID(nil), journal.PendingExclusions...),
+		CoordinatorChange: journal.CoordinatorChange.DeepCopy(),
+	}
+}
@@ -21,6 +21,7 @@
 package mock
 
 import (
+	"context"
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
@@ -42,17 +43,22 @@ func NewMockFdbPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Po
 }
 
 // UpdateFile checks if a file is up-to-date and tries to update it.
-func (client *FdbPodClient) UpdateFile(_ string, _ string) (bool, error) {
+func (client *FdbPodClient) UpdateFile(_ context.Context, _ string, _ string) (bool, error) {
+	return true, nil
+}
+
+// CheckHash checks whether a file has the expected contents.
+func (client *FdbPodClient) CheckHash(_ context.Context, _ string, _ string) (bool, error) {
 	return true, nil
 }
 
 // IsPresent checks whether a file in the sidecar is present.
-func (client *FdbPodClient) IsPresent(_ string) (bool, error) {
+func (client *FdbPodClient) IsPresent(_ context.Context, _ string) (bool, error) {
 	return true, nil
 }
 
 // GetVariableSubstitutions gets the current keys and values that this
 // process group will substitute into its monitor conf.
-func (client *FdbPodClient) GetVariableSubstitutions() (map[string]string, error) {
+func (client *FdbPodClient) GetVariableSubstitutions(_ context.Context) (map[string]string, error) {
 	return internal.GetSubstitutionsFromClusterAndPod(client.logger, client.Cluster, client.Pod)
 }
@@ -20,15 +20,20 @@
 
 package podclient
 
+import "context"
+
 // FdbPodClient provides methods for working with a FoundationDB pod
 type FdbPodClient interface {
 	// IsPresent checks whether a file is present.
-	IsPresent(path string) (bool, error)
+	IsPresent(ctx context.Context, path string) (bool, error)
 
 	// UpdateFile checks if a file is up-to-date and tries to update it.
-	UpdateFile(name string, contents string) (bool, error)
+	UpdateFile(ctx context.Context, name string, contents string) (bool, error)
+
+	// CheckHash checks whether a file has the expected contents without updating it.
+	CheckHash(ctx context.Context, name string, contents string) (bool, error)
 
 	// GetVariableSubstitutions gets the current keys and values that this
 	// process group will substitute into its monitor conf.
-	GetVariableSubstitutions() (map[string]string, error)
+	GetVariableSubstitutions(ctx context.Context) (map[string]string, error)
 }
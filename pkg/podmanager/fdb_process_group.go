@@ -74,14 +74,5 @@ func GetPublicIPSource(pod *corev1.Pod) (fdbv1beta2.PublicIPSource, error) {
 
 // GetPublicIPs returns the public IP of a pod.
 func GetPublicIPs(pod *corev1.Pod, log logr.Logger) []string {
-	if pod == nil {
-		return []string{}
-	}
-
-	source := pod.ObjectMeta.Annotations[fdbv1beta2.PublicIPSourceAnnotation]
-	if source == "" || source == string(fdbv1beta2.PublicIPSourcePod) {
-		return internal.GetPublicIPsForPod(pod, log)
-	}
-
-	return []string{pod.ObjectMeta.Annotations[fdbv1beta2.PublicIPAnnotation]}
+	return internal.GetPublicIPs(pod, log)
 }
@@ -55,6 +55,9 @@ type PodLifecycleManager interface {
 	// UpdateMetadata updates a Pod's metadata.
 	UpdateMetadata(context.Context, client.Client, *fdbv1beta2.FoundationDBCluster, *corev1.Pod) error
 
+	// UpdateResources updates the resource requirements of a Pod's containers in place.
+	UpdateResources(context.Context, client.Client, *fdbv1beta2.FoundationDBCluster, *corev1.Pod) error
+
 	// PodIsUpdated determines whether a Pod is up to date.
 	//
 	// This does not need to check the metadata or the pod spec hash. This only
@@ -113,7 +116,7 @@ func (manager StandardPodLifecycleManager) DeletePod(ctx context.Context, r clie
 
 // CanDeletePods checks whether it is safe to delete Pods.
 func (manager StandardPodLifecycleManager) CanDeletePods(ctx context.Context, adminClient fdbadminclient.AdminClient, cluster *fdbv1beta2.FoundationDBCluster) (bool, error) {
-	return internal.HasDesiredFaultTolerance(logr.FromContextOrDiscard(ctx), adminClient, cluster)
+	return internal.HasDesiredFaultTolerance(ctx, logr.FromContextOrDiscard(ctx), adminClient, cluster)
 }
 
 // UpdatePods updates a list of Pods to match the latest specs.
@@ -140,6 +143,12 @@ func (manager StandardPodLifecycleManager) UpdateMetadata(ctx context.Context, r
 	return r.Update(ctx, pod)
 }
 
+// UpdateResources updates the resource requirements of a Pod's containers in place.
+func (manager StandardPodLifecycleManager) UpdateResources(ctx context.Context, r client.Client, _ *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) error {
+	logr.FromContextOrDiscard(ctx).V(1).Info("Resizing pod", "name", pod.Name)
+	return r.Update(ctx, pod)
+}
+
 // PodIsUpdated determines whether a Pod is up to date.
 //
 // This does not need to check the metadata or the pod spec hash. This only
@@ -0,0 +1,153 @@
+/*
+ * validation.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package validation provides the defaulting and validation logic of the operator for FoundationDBCluster resources,
+// so manifests can be checked before they are applied, e.g. in a CI pipeline.
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Options controls how the defaults are applied to the cluster spec before it is validated.
+type Options struct {
+	// UseFutureDefaults defines if the latest defaults should be applied rather than the defaults that were initially
+	// established for this major version.
+	UseFutureDefaults bool
+
+	// OperatorConfig defines the FoundationDBOperatorConfig whose defaults are applied to the cluster spec. If nil, no
+	// operator config is applied.
+	OperatorConfig *fdbv1beta2.FoundationDBOperatorConfig
+}
+
+// Result contains the result of the validation of a single cluster in a manifest.
+type Result struct {
+	// Name defines the name of the cluster.
+	Name string
+
+	// Namespace defines the namespace of the cluster.
+	Namespace string
+
+	// Error contains the reason why the cluster is not valid. If the cluster is valid, the error will be nil.
+	Error error
+
+	// Warnings contains the risky settings of the cluster that are allowed but deserve the attention of the user.
+	Warnings []string
+}
+
+// NormalizeCluster applies the defaults of the operator config and of the operator to the cluster spec and moves the
+// configuration from deprecated fields into their replacements, the same way the operator does before reconciling the
+// cluster.
+func NormalizeCluster(cluster *fdbv1beta2.FoundationDBCluster, options Options) error {
+	err := internal.ApplyOperatorConfig(cluster, options.OperatorConfig)
+	if err != nil {
+		return err
+	}
+
+	return internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{UseFutureDefaults: options.UseFutureDefaults})
+}
+
+// ValidateCluster checks if the cluster would be accepted by the operator. The version of the cluster must be
+// supported by the operator and the normalized spec must be valid. The provided cluster will not be modified.
+func ValidateCluster(cluster *fdbv1beta2.FoundationDBCluster, options Options) error {
+	normalized := cluster.DeepCopy()
+	err := NormalizeCluster(normalized, options)
+	if err != nil {
+		return err
+	}
+
+	version, err := fdbv1beta2.ParseFdbVersion(normalized.Spec.Version)
+	if err != nil {
+		return err
+	}
+
+	if !version.IsSupported() {
+		return fmt.Errorf("version %s is not supported", normalized.Spec.Version)
+	}
+
+	return normalized.Validate()
+}
+
+// ValidateManifest validates all FoundationDBCluster resources in the manifest, which can contain multiple YAML or JSON
+// documents. Documents of other kinds are ignored. Unknown fields in a FoundationDBCluster are reported as an error
+// of the cluster. An error will only be returned if the manifest can't be read.
+func ValidateManifest(manifest io.Reader, options Options) ([]Result, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(manifest))
+	results := make([]Result, 0)
+
+	for {
+		document, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return results, nil
+			}
+
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+
+		metadata := metav1.PartialObjectMetadata{}
+		err = yaml.Unmarshal(document, &metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		if metadata.Kind != "FoundationDBCluster" {
+			continue
+		}
+
+		warnings, err := validateDocument(document, metadata.APIVersion, options)
+		results = append(results, Result{
+			Name:      metadata.Name,
+			Namespace: metadata.Namespace,
+			Error:     err,
+			Warnings:  warnings,
+		})
+	}
+}
+
+// validateDocument decodes the FoundationDBCluster in the document, validates it and returns the warnings for the
+// cluster.
+func validateDocument(document []byte, apiVersion string, options Options) ([]string, error) {
+	if apiVersion != fdbv1beta2.GroupVersion.String() {
+		return nil, fmt.Errorf("API version %s is not supported, only %s can be validated", apiVersion, fdbv1beta2.GroupVersion.String())
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := yaml.UnmarshalStrict(document, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetWarnings(cluster, nil), ValidateCluster(cluster, options)
+}
@@ -0,0 +1,194 @@
+/*
+ * validation_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validation", func() {
+	When("validating a cluster", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &fdbv1beta2.FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "sample-cluster"},
+				Spec: fdbv1beta2.FoundationDBClusterSpec{
+					Version: fdbv1beta2.Versions.Default.String(),
+				},
+			}
+		})
+
+		It("should accept a valid cluster", func() {
+			Expect(ValidateCluster(cluster, Options{})).To(Succeed())
+		})
+
+		It("should not modify the cluster", func() {
+			Expect(ValidateCluster(cluster, Options{})).To(Succeed())
+			Expect(cluster.Spec.Processes).To(BeEmpty())
+		})
+
+		When("the version is not supported", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "6.1.0"
+			})
+
+			It("should return an error", func() {
+				Expect(ValidateCluster(cluster, Options{})).To(MatchError("version 6.1.0 is not supported"))
+			})
+		})
+
+		When("the version can't be parsed", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "latest"
+			})
+
+			It("should return an error", func() {
+				Expect(ValidateCluster(cluster, Options{})).NotTo(Succeed())
+			})
+		})
+
+		When("a feature is not supported by the version", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "7.1.0"
+				cluster.Spec.TagQuotas = []fdbv1beta2.TagQuota{{Tag: "tenant1", TotalThroughput: 1000}}
+			})
+
+			It("should return an error", func() {
+				Expect(ValidateCluster(cluster, Options{})).To(MatchError("tag quotas are not supported on version 7.1.0"))
+			})
+		})
+
+		When("a parameter is not valid", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+					fdbv1beta2.ProcessClassGeneral: {
+						Parameters: fdbv1beta2.FoundationDBParameters{{Name: "datadir", Value: "/tmp"}},
+					},
+				}
+			})
+
+			It("should return an error", func() {
+				Expect(ValidateCluster(cluster, Options{})).NotTo(Succeed())
+			})
+		})
+	})
+
+	When("validating a manifest", func() {
+		var results []Result
+		var err error
+		var manifest string
+
+		JustBeforeEach(func() {
+			results, err = ValidateManifest(strings.NewReader(manifest), Options{})
+		})
+
+		When("the manifest contains multiple documents", func() {
+			BeforeEach(func() {
+				manifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+---
+apiVersion: apps.foundationdb.org/v1beta2
+kind: FoundationDBCluster
+metadata:
+  name: valid-cluster
+  namespace: fdb
+spec:
+  version: ` + fdbv1beta2.Versions.Default.String() + `
+---
+apiVersion: apps.foundationdb.org/v1beta2
+kind: FoundationDBCluster
+metadata:
+  name: unsupported-version
+spec:
+  version: 6.1.0
+---
+apiVersion: apps.foundationdb.org/v1beta2
+kind: FoundationDBCluster
+metadata:
+  name: unknown-field
+spec:
+  version: ` + fdbv1beta2.Versions.Default.String() + `
+  storageServersPerPods: 2
+---
+apiVersion: apps.foundationdb.org/v1beta1
+kind: FoundationDBCluster
+metadata:
+  name: old-version
+spec:
+  version: ` + fdbv1beta2.Versions.Default.String() + `
+`
+			})
+
+			It("should validate all clusters", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(4))
+
+				Expect(results[0].Name).To(Equal("valid-cluster"))
+				Expect(results[0].Namespace).To(Equal("fdb"))
+				Expect(results[0].Error).NotTo(HaveOccurred())
+
+				Expect(results[1].Name).To(Equal("unsupported-version"))
+				Expect(results[1].Error).To(MatchError("version 6.1.0 is not supported"))
+
+				Expect(results[2].Name).To(Equal("unknown-field"))
+				Expect(results[2].Error).To(HaveOccurred())
+				Expect(results[2].Error.Error()).To(ContainSubstring("storageServersPerPods"))
+
+				Expect(results[3].Name).To(Equal("old-version"))
+				Expect(results[3].Error).To(MatchError("API version apps.foundationdb.org/v1beta1 is not supported, only apps.foundationdb.org/v1beta2 can be validated"))
+			})
+		})
+
+		When("the manifest is JSON", func() {
+			BeforeEach(func() {
+				manifest = `{"apiVersion": "apps.foundationdb.org/v1beta2", "kind": "FoundationDBCluster", "metadata": {"name": "json-cluster"}, "spec": {"version": "` + fdbv1beta2.Versions.Default.String() + `"}}`
+			})
+
+			It("should validate the cluster", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Name).To(Equal("json-cluster"))
+				Expect(results[0].Error).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the manifest contains no cluster", func() {
+			BeforeEach(func() {
+				manifest = ""
+			})
+
+			It("should return no results", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,105 @@
+/*
+ * warnings.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// GetWarnings returns the warnings for risky settings of the cluster that are allowed but deserve the attention of the
+// user. If the previous cluster is provided, the changes from the previous cluster are checked as well, e.g. if the
+// fault tolerance is reduced or if an upgrade skips versions. The provided clusters will not be modified.
+func GetWarnings(cluster *fdbv1beta2.FoundationDBCluster, previous *fdbv1beta2.FoundationDBCluster) []string {
+	var warnings []string
+
+	configuration := cluster.Spec.DatabaseConfiguration
+	recommended := (&fdbv1beta2.DatabaseConfiguration{UsableRegions: configuration.UsableRegions}).GetRoleCountsWithDefaults(fdbv1beta2.Version{}, cluster.DesiredFaultTolerance())
+	for _, role := range []struct {
+		name        string
+		count       int
+		recommended int
+	}{
+		{name: "storage", count: configuration.Storage, recommended: recommended.Storage},
+		{name: "logs", count: configuration.Logs, recommended: recommended.Logs},
+		{name: "remote_logs", count: configuration.RemoteLogs, recommended: recommended.RemoteLogs},
+	} {
+		if role.count <= 0 || role.count >= role.recommended {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf("the %s count %d is below the recommended count of %d for the redundancy mode %s", role.name, role.count, role.recommended, getRedundancyMode(cluster)))
+	}
+
+	if previous == nil {
+		return warnings
+	}
+
+	if cluster.DesiredFaultTolerance() < previous.DesiredFaultTolerance() {
+		warnings = append(warnings, fmt.Sprintf("changing the redundancy mode from %s to %s reduces the fault tolerance from %d to %d", getRedundancyMode(previous), getRedundancyMode(cluster), previous.DesiredFaultTolerance(), cluster.DesiredFaultTolerance()))
+	}
+
+	warning := getVersionSkipWarning(previous.GetRunningVersion(), cluster.Spec.Version)
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	return warnings
+}
+
+// getRedundancyMode returns the redundancy mode of the cluster, an unset redundancy mode defaults to double.
+func getRedundancyMode(cluster *fdbv1beta2.FoundationDBCluster) fdbv1beta2.RedundancyMode {
+	if cluster.Spec.DatabaseConfiguration.RedundancyMode == fdbv1beta2.RedundancyModeUnset {
+		return fdbv1beta2.RedundancyModeDouble
+	}
+
+	return cluster.Spec.DatabaseConfiguration.RedundancyMode
+}
+
+// getVersionSkipWarning returns a warning if the upgrade from the running version to the desired version skips at
+// least one minor or major version. Versions that can't be parsed are validated by the operator, so no warning is
+// returned for them.
+func getVersionSkipWarning(runningVersion string, desiredVersion string) string {
+	if runningVersion == "" || runningVersion == desiredVersion {
+		return ""
+	}
+
+	running, err := fdbv1beta2.ParseFdbVersion(runningVersion)
+	if err != nil {
+		return ""
+	}
+
+	desired, err := fdbv1beta2.ParseFdbVersion(desiredVersion)
+	if err != nil {
+		return ""
+	}
+
+	if running.IsAtLeast(desired) {
+		return ""
+	}
+
+	if desired.Major > running.NextMajorVersion().Major || (desired.Major == running.Major && desired.Minor > running.NextMinorVersion().Minor) {
+		return fmt.Sprintf("the upgrade from version %s to version %s skips at least one release, make sure that the upgrade path is supported", running, desired)
+	}
+
+	return ""
+}
@@ -0,0 +1,143 @@
+/*
+ * warnings_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("warnings", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var previous *fdbv1beta2.FoundationDBCluster
+	var warnings []string
+
+	BeforeEach(func() {
+		cluster = &fdbv1beta2.FoundationDBCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "sample-cluster"},
+			Spec: fdbv1beta2.FoundationDBClusterSpec{
+				Version: "7.1.26",
+			},
+		}
+		previous = nil
+	})
+
+	JustBeforeEach(func() {
+		warnings = GetWarnings(cluster, previous)
+	})
+
+	When("the cluster uses the default settings", func() {
+		It("should return no warnings", func() {
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	When("the role counts are below the recommended counts", func() {
+		BeforeEach(func() {
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbv1beta2.RedundancyModeTriple
+			cluster.Spec.DatabaseConfiguration.Storage = 3
+			cluster.Spec.DatabaseConfiguration.Logs = 2
+		})
+
+		It("should return a warning for every role", func() {
+			Expect(warnings).To(ConsistOf(
+				"the storage count 3 is below the recommended count of 5 for the redundancy mode triple",
+				"the logs count 2 is below the recommended count of 3 for the redundancy mode triple",
+			))
+		})
+	})
+
+	When("the remote logs are below the recommended count", func() {
+		BeforeEach(func() {
+			cluster.Spec.DatabaseConfiguration.UsableRegions = 2
+			cluster.Spec.DatabaseConfiguration.RemoteLogs = 1
+		})
+
+		It("should return a warning", func() {
+			Expect(warnings).To(ConsistOf("the remote_logs count 1 is below the recommended count of 3 for the redundancy mode double"))
+		})
+	})
+
+	When("the role counts are above the recommended counts", func() {
+		BeforeEach(func() {
+			cluster.Spec.DatabaseConfiguration.Logs = 5
+		})
+
+		It("should return no warnings", func() {
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	When("the cluster is updated", func() {
+		BeforeEach(func() {
+			previous = cluster.DeepCopy()
+			previous.Status.RunningVersion = previous.Spec.Version
+		})
+
+		When("the spec is not changed", func() {
+			It("should return no warnings", func() {
+				Expect(warnings).To(BeEmpty())
+			})
+		})
+
+		When("the fault tolerance is reduced", func() {
+			BeforeEach(func() {
+				cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbv1beta2.RedundancyModeSingle
+			})
+
+			It("should return a warning", func() {
+				Expect(warnings).To(ConsistOf("changing the redundancy mode from double to single reduces the fault tolerance from 1 to 0"))
+			})
+		})
+
+		When("the fault tolerance is increased", func() {
+			BeforeEach(func() {
+				cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbv1beta2.RedundancyModeTriple
+			})
+
+			It("should return no warnings", func() {
+				Expect(warnings).To(BeEmpty())
+			})
+		})
+
+		DescribeTable("upgrading the cluster", func(version string, expected string) {
+			cluster.Spec.Version = version
+			warnings = GetWarnings(cluster, previous)
+			if expected == "" {
+				Expect(warnings).To(BeEmpty())
+				return
+			}
+
+			Expect(warnings).To(ConsistOf(expected))
+		},
+			Entry("to a patch version", "7.1.27", ""),
+			Entry("to the next minor version", "7.2.0", ""),
+			Entry("to the next major version", "8.0.0", ""),
+			Entry("skipping a minor version", "7.3.0", "the upgrade from version 7.1.26 to version 7.3.0 skips at least one release, make sure that the upgrade path is supported"),
+			Entry("skipping a major version", "9.0.0", "the upgrade from version 7.1.26 to version 9.0.0 skips at least one release, make sure that the upgrade path is supported"),
+			Entry("to a lower version", "6.3.24", ""),
+			Entry("to a version that can't be parsed", "latest", ""),
+		)
+	})
+})
@@ -0,0 +1,340 @@
+/*
+ * config.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package setup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/controllers"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// configReloadInterval defines how often the operator checks if the config file was changed.
+var configReloadInterval = 10 * time.Second
+
+// OperatorConfig defines the settings of the operator that can be provided in a YAML config file, e.g. from a ConfigMap
+// that is mounted into the operator Pod. Settings that are not defined in the config file keep the value of the
+// according command line flag.
+type OperatorConfig struct {
+	// WatchNamespace defines which namespace the operator should watch. Changes require a restart of the operator.
+	WatchNamespace *string `json:"watchNamespace,omitempty"`
+
+	// LabelSelector defines a label-selector that will be used to select resources. Changes require a restart of the
+	// operator.
+	LabelSelector *string `json:"labelSelector,omitempty"`
+
+	// MaxConcurrentReconciles defines the maximum number of concurrent reconciles for all controllers. Changes
+	// require a restart of the operator.
+	MaxConcurrentReconciles *int `json:"maxConcurrentReconciles,omitempty"`
+
+	// CliTimeoutSeconds defines the timeout to use for CLI commands in seconds. Changes require a restart of the
+	// operator.
+	CliTimeoutSeconds *int `json:"cliTimeoutSeconds,omitempty"`
+
+	// GetTimeout defines the http timeout for get requests to the FDB sidecar.
+	GetTimeout *metav1.Duration `json:"getTimeout,omitempty"`
+
+	// PostTimeout defines the http timeout for post requests to the FDB sidecar.
+	PostTimeout *metav1.Duration `json:"postTimeout,omitempty"`
+
+	// AdminClientAuditLogSize defines how many entries of the admin client audit log are kept in a ConfigMap for each
+	// cluster.
+	AdminClientAuditLogSize *int `json:"adminClientAuditLogSize,omitempty"`
+
+	// DefaultImages defines the image configs that are used for all clusters if the cluster spec doesn't define the
+	// according image.
+	DefaultImages DefaultImagesConfig `json:"defaultImages,omitempty"`
+
+	// FeatureGates enables or disables features of the operator by their name. This contains the feature gates of the
+	// featuregates package and the feature gates that correspond to a command line flag.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// DefaultImagesConfig defines the image configs that are used for all clusters if the cluster spec doesn't define the
+// according image.
+type DefaultImagesConfig struct {
+	// MainContainer defines the image configs for the main container.
+	MainContainer []fdbv1beta2.ImageConfig `json:"mainContainer,omitempty"`
+
+	// SidecarContainer defines the image configs for the sidecar container.
+	SidecarContainer []fdbv1beta2.ImageConfig `json:"sidecarContainer,omitempty"`
+
+	// ReloadWithoutRestart defines if changed default images are applied while the operator is running. Changing the
+	// default images updates the Pods of all clusters that use them at the same time, so by default changes require
+	// a restart of the operator.
+	ReloadWithoutRestart bool `json:"reloadWithoutRestart,omitempty"`
+}
+
+// parseOperatorConfig parses the content of an operator config file. Unknown fields are rejected to detect typos.
+func parseOperatorConfig(content []byte) (*OperatorConfig, error) {
+	config := &OperatorConfig{}
+	err := yaml.UnmarshalStrict(content, config)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse operator config: %w", err)
+	}
+
+	return config, nil
+}
+
+// featureGates returns the options for all feature gates that correspond to a command line flag.
+func (o *Options) featureGates() map[string]*bool {
+	return map[string]*bool{
+		"RestartIncompatibleProcesses": &o.EnableRestartIncompatibleProcesses,
+		"RecoveryState":                &o.EnableRecoveryState,
+		"DryRun":                       &o.DryRun,
+		"ServerSideApply":              &o.ServerSideApply,
+		"TraceEventReceiver":           &o.EnableTraceEventReceiver,
+		"TestScenarios":                &o.EnableTestScenarios,
+		"ClientLibraryCaches":          &o.EnableClientLibraryCaches,
+		"OperatorConfigs":              &o.EnableOperatorConfigs,
+		"PodDeletionProtection":        &o.EnablePodDeletionProtection,
+		"ClusterAdmissionWarnings":     &o.EnableClusterAdmissionWarnings,
+		"CertManager":                  &o.EnableCertManager,
+		"Pprof":                        &o.EnablePprof,
+		"RunCliCommandsInPods":         &o.RunCliCommandsInPods,
+	}
+}
+
+// ApplyConfig overrides the options with all settings that are defined in the operator config.
+func (o *Options) ApplyConfig(config *OperatorConfig) error {
+	gates := o.featureGates()
+	featureGates := map[string]bool{}
+	for name, enabled := range config.FeatureGates {
+		gate, ok := gates[name]
+		if !ok {
+			featureGates[name] = enabled
+			continue
+		}
+
+		*gate = enabled
+	}
+
+	var err error
+	o.DeprecationOptions.FeatureGates, err = o.DeprecationOptions.FeatureGates.With(featureGates)
+	if err != nil {
+		return err
+	}
+
+	if config.WatchNamespace != nil {
+		o.WatchNamespace = *config.WatchNamespace
+	}
+
+	if config.LabelSelector != nil {
+		o.LabelSelector = *config.LabelSelector
+	}
+
+	if config.MaxConcurrentReconciles != nil {
+		o.MaxConcurrentReconciles = *config.MaxConcurrentReconciles
+	}
+
+	if config.CliTimeoutSeconds != nil {
+		o.CliTimeout = *config.CliTimeoutSeconds
+	}
+
+	if config.GetTimeout != nil {
+		o.GetTimeout = config.GetTimeout.Duration
+	}
+
+	if config.PostTimeout != nil {
+		o.PostTimeout = config.PostTimeout.Duration
+	}
+
+	if config.AdminClientAuditLogSize != nil {
+		o.AdminClientAuditLogSize = *config.AdminClientAuditLogSize
+	}
+
+	o.DeprecationOptions.DefaultMainContainerImageConfigs = config.DefaultImages.MainContainer
+	o.DeprecationOptions.DefaultSidecarContainerImageConfigs = config.DefaultImages.SidecarContainer
+
+	return nil
+}
+
+// getRuntimeSettings returns the settings of the cluster reconciler that can be updated while the operator is running.
+func (o *Options) getRuntimeSettings() controllers.RuntimeSettings {
+	return controllers.RuntimeSettings{
+		EnableRestartIncompatibleProcesses: o.EnableRestartIncompatibleProcesses,
+		EnableRecoveryState:                o.EnableRecoveryState,
+		DryRun:                             o.DryRun,
+		GetTimeout:                         o.GetTimeout,
+		PostTimeout:                        o.PostTimeout,
+		AdminClientAuditLogSize:            o.AdminClientAuditLogSize,
+		DeprecationOptions:                 o.DeprecationOptions,
+	}
+}
+
+// getSettingsRequiringRestart returns the names of the settings that differ between the options and the provided
+// options and that can only be applied by restarting the operator.
+func (o *Options) getSettingsRequiringRestart(other Options) []string {
+	var settings []string
+	if o.WatchNamespace != other.WatchNamespace {
+		settings = append(settings, "watchNamespace")
+	}
+
+	if o.LabelSelector != other.LabelSelector {
+		settings = append(settings, "labelSelector")
+	}
+
+	if o.MaxConcurrentReconciles != other.MaxConcurrentReconciles {
+		settings = append(settings, "maxConcurrentReconciles")
+	}
+
+	if o.CliTimeout != other.CliTimeout {
+		settings = append(settings, "cliTimeoutSeconds")
+	}
+
+	otherGates := other.featureGates()
+	for _, name := range []string{"ServerSideApply", "TraceEventReceiver", "TestScenarios", "ClientLibraryCaches", "OperatorConfigs", "PodDeletionProtection", "ClusterAdmissionWarnings", "CertManager", "Pprof", "RunCliCommandsInPods"} {
+		if *o.featureGates()[name] != *otherGates[name] {
+			settings = append(settings, "featureGates."+name)
+		}
+	}
+
+	sort.Strings(settings)
+
+	return settings
+}
+
+// loadConfigFile reads the operator config file and applies it to a copy of the provided options.
+func loadConfigFile(configFile string, options Options) (Options, []byte, error) {
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return options, nil, err
+	}
+
+	config, err := parseOperatorConfig(content)
+	if err != nil {
+		return options, content, err
+	}
+
+	err = options.ApplyConfig(config)
+
+	return options, content, err
+}
+
+// configReloader periodically reads the operator config file and updates the settings of the cluster reconciler that
+// can be changed while the operator is running.
+type configReloader struct {
+	// flagOptions are the options from the command line flags, the config file is always applied on top of them, so
+	// removing a setting from the config file will restore the value of the flag.
+	flagOptions Options
+	// startupOptions are the options the operator was started with.
+	startupOptions    Options
+	clusterReconciler *controllers.FoundationDBClusterReconciler
+	log               logr.Logger
+	// content is the content of the config file that was read the last time.
+	content []byte
+}
+
+// Start implements the manager.Runnable interface and reloads the config file until the context is done.
+func (reloader *configReloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(configReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		reloader.reload()
+	}
+}
+
+// NeedLeaderElection implements the manager.LeaderElectionRunnable interface. The config is reloaded on all
+// instances, so an instance that gets elected as leader uses the latest config.
+func (reloader *configReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// reload reads the config file and updates the runtime settings of the cluster reconciler if the content of the config
+// file was changed. An invalid config file will be ignored and the current settings will be kept.
+func (reloader *configReloader) reload() {
+	content, err := os.ReadFile(reloader.flagOptions.ConfigFile)
+	if err != nil {
+		reloader.log.Error(err, "could not read operator config file", "configFile", reloader.flagOptions.ConfigFile)
+		return
+	}
+
+	if bytes.Equal(content, reloader.content) {
+		return
+	}
+
+	// The content is recorded before the config is validated, so an invalid config is only reported once.
+	reloader.content = content
+	config, err := parseOperatorConfig(content)
+	if err != nil {
+		reloader.log.Error(err, "ignoring invalid operator config file", "configFile", reloader.flagOptions.ConfigFile)
+		return
+	}
+
+	options, restartSettings, err := reloader.applyReloadedConfig(config)
+	if err != nil {
+		reloader.log.Error(err, "ignoring invalid operator config file", "configFile", reloader.flagOptions.ConfigFile)
+		return
+	}
+
+	if len(restartSettings) > 0 {
+		reloader.log.Info("Operator config contains changes that require a restart of the operator", "settings", restartSettings)
+	}
+
+	reloader.log.Info("Reloaded operator config file", "configFile", reloader.flagOptions.ConfigFile)
+	if reloader.clusterReconciler != nil {
+		reloader.clusterReconciler.UpdateRuntimeSettings(options.getRuntimeSettings())
+	}
+}
+
+// applyReloadedConfig applies the reloaded config on top of the flag options and returns the new options and the
+// settings that require a restart of the operator. Changed default images are only applied if the config opts in with
+// reloadWithoutRestart, otherwise the default images the operator was started with are kept.
+func (reloader *configReloader) applyReloadedConfig(config *OperatorConfig) (Options, []string, error) {
+	options := reloader.flagOptions
+	err := options.ApplyConfig(config)
+	if err != nil {
+		return options, nil, err
+	}
+
+	restartSettings := reloader.startupOptions.getSettingsRequiringRestart(options)
+	if config.DefaultImages.ReloadWithoutRestart {
+		return options, restartSettings, nil
+	}
+
+	startupDeprecationOptions := reloader.startupOptions.DeprecationOptions
+	if !equality.Semantic.DeepEqual(options.DeprecationOptions.DefaultMainContainerImageConfigs, startupDeprecationOptions.DefaultMainContainerImageConfigs) ||
+		!equality.Semantic.DeepEqual(options.DeprecationOptions.DefaultSidecarContainerImageConfigs, startupDeprecationOptions.DefaultSidecarContainerImageConfigs) {
+		restartSettings = append(restartSettings, "defaultImages")
+		sort.Strings(restartSettings)
+	}
+
+	options.DeprecationOptions.DefaultMainContainerImageConfigs = startupDeprecationOptions.DefaultMainContainerImageConfigs
+	options.DeprecationOptions.DefaultSidecarContainerImageConfigs = startupDeprecationOptions.DefaultSidecarContainerImageConfigs
+
+	return options, restartSettings, nil
+}
@@ -0,0 +1,209 @@
+/*
+ * config_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package setup
+
+import (
+	"os"
+	"path"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/controllers"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/featuregates"
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("config", func() {
+	var options Options
+
+	BeforeEach(func() {
+		options = Options{
+			WatchNamespace:                     "default",
+			MaxConcurrentReconciles:            1,
+			CliTimeout:                         10,
+			GetTimeout:                         5 * time.Second,
+			PostTimeout:                        10 * time.Second,
+			EnableRestartIncompatibleProcesses: true,
+			EnableRecoveryState:                true,
+		}
+	})
+
+	When("applying a config", func() {
+		var err error
+
+		JustBeforeEach(func() {
+			var config *OperatorConfig
+			config, err = parseOperatorConfig([]byte(`
+watchNamespace: fdb
+getTimeout: 30s
+adminClientAuditLogSize: 10
+defaultImages:
+  mainContainer:
+  - baseImage: registry.example/foundationdb
+featureGates:
+  RestartIncompatibleProcesses: false
+  DryRun: true
+  UnifiedImage: true
+`))
+			Expect(err).NotTo(HaveOccurred())
+			err = options.ApplyConfig(config)
+		})
+
+		It("should override the options that are defined in the config", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options.WatchNamespace).To(Equal("fdb"))
+			Expect(options.GetTimeout).To(Equal(30 * time.Second))
+			Expect(options.AdminClientAuditLogSize).To(Equal(10))
+			Expect(options.EnableRestartIncompatibleProcesses).To(BeFalse())
+			Expect(options.DryRun).To(BeTrue())
+			Expect(options.DeprecationOptions.FeatureGates.Enabled(featuregates.UnifiedImage)).To(BeTrue())
+			Expect(options.DeprecationOptions.FeatureGates.Enabled(featuregates.DNSInClusterFile)).To(BeFalse())
+			Expect(options.DeprecationOptions.DefaultMainContainerImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb"}}))
+		})
+
+		It("should keep the options that are not defined in the config", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options.PostTimeout).To(Equal(10 * time.Second))
+			Expect(options.CliTimeout).To(Equal(10))
+			Expect(options.EnableRecoveryState).To(BeTrue())
+		})
+	})
+
+	When("the config contains an unknown feature gate", func() {
+		It("should return an error", func() {
+			err := options.ApplyConfig(&OperatorConfig{FeatureGates: map[string]bool{"Unknown": true}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the config contains an unknown field", func() {
+		It("should return an error", func() {
+			_, err := parseOperatorConfig([]byte("unknownField: true"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("checking for settings that require a restart", func() {
+		It("should only report the settings that can't be changed at runtime", func() {
+			other := options
+			other.WatchNamespace = "fdb"
+			other.ServerSideApply = true
+			other.GetTimeout = time.Minute
+			other.DryRun = true
+			other.EnablePprof = true
+
+			Expect(options.getSettingsRequiringRestart(other)).To(ConsistOf("watchNamespace", "featureGates.ServerSideApply", "featureGates.Pprof"))
+		})
+	})
+
+	When("reloading the config file", func() {
+		var reloader *configReloader
+		var configFile string
+
+		BeforeEach(func() {
+			configFile = path.Join(GinkgoT().TempDir(), "config.yaml")
+			Expect(os.WriteFile(configFile, []byte("getTimeout: 30s"), 0600)).NotTo(HaveOccurred())
+			options.ConfigFile = configFile
+
+			reloader = &configReloader{
+				flagOptions:       options,
+				startupOptions:    options,
+				clusterReconciler: controllers.NewFoundationDBClusterReconciler(nil),
+				log:               logr.Discard(),
+			}
+			reloader.reload()
+		})
+
+		It("should record the content of the config file", func() {
+			Expect(reloader.content).To(Equal([]byte("getTimeout: 30s")))
+		})
+
+		When("the config file is changed", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(configFile, []byte("postTimeout: 30s"), 0600)).NotTo(HaveOccurred())
+				reloader.reload()
+			})
+
+			It("should record the new content of the config file", func() {
+				Expect(reloader.content).To(Equal([]byte("postTimeout: 30s")))
+			})
+		})
+
+		When("the default images are changed", func() {
+			var reloadedOptions Options
+			var restartSettings []string
+
+			JustBeforeEach(func() {
+				content, err := os.ReadFile(configFile)
+				Expect(err).NotTo(HaveOccurred())
+				config, err := parseOperatorConfig(content)
+				Expect(err).NotTo(HaveOccurred())
+				reloadedOptions, restartSettings, err = reloader.applyReloadedConfig(config)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			BeforeEach(func() {
+				Expect(os.WriteFile(configFile, []byte(`
+getTimeout: 30s
+defaultImages:
+  mainContainer:
+  - baseImage: registry.example/foundationdb
+`), 0600)).NotTo(HaveOccurred())
+			})
+
+			It("should keep the default images the operator was started with", func() {
+				Expect(reloadedOptions.GetTimeout).To(Equal(30 * time.Second))
+				Expect(reloadedOptions.DeprecationOptions.DefaultMainContainerImageConfigs).To(BeEmpty())
+				Expect(restartSettings).To(ConsistOf("defaultImages"))
+			})
+
+			When("the config opts in to reload the default images", func() {
+				BeforeEach(func() {
+					Expect(os.WriteFile(configFile, []byte(`
+defaultImages:
+  reloadWithoutRestart: true
+  mainContainer:
+  - baseImage: registry.example/foundationdb
+`), 0600)).NotTo(HaveOccurred())
+				})
+
+				It("should apply the changed default images", func() {
+					Expect(reloadedOptions.DeprecationOptions.DefaultMainContainerImageConfigs).To(Equal([]fdbv1beta2.ImageConfig{{BaseImage: "registry.example/foundationdb"}}))
+					Expect(restartSettings).To(BeEmpty())
+				})
+			})
+		})
+
+		When("the config file is invalid", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(configFile, []byte("featureGates: {Unknown: true}"), 0600)).NotTo(HaveOccurred())
+				reloader.reload()
+			})
+
+			It("should record the content to report the invalid config only once", func() {
+				Expect(reloader.content).To(Equal([]byte("featureGates: {Unknown: true}")))
+			})
+		})
+	})
+})
@@ -21,11 +21,16 @@
 package setup
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"io/fs"
 	"log"
+	"net/http"
+	"net/http/pprof"
 	"os"
 	"path"
 	"strconv"
@@ -41,11 +46,14 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var operatorVersion = "latest"
@@ -59,20 +67,39 @@ type Options struct {
 	EnableRestartIncompatibleProcesses bool
 	ServerSideApply                    bool
 	EnableRecoveryState                bool
+	EnableTraceEventReceiver           bool
+	EnableTestScenarios                bool
+	EnableClientLibraryCaches          bool
+	ClientLibraryCacheHostPathRoot     string
+	EnableOperatorConfigs              bool
+	EnablePodDeletionProtection        bool
+	EnableClusterAdmissionWarnings     bool
+	EnableCertManager                  bool
+	EnablePprof                        bool
+	AdminClientAuditLogSize            int
+	DryRun                             bool
+	RunCliCommandsInPods               bool
 	MetricsAddr                        string
 	LeaderElectionID                   string
 	LogFile                            string
+	ConfigFile                         string
 	LogFilePermission                  string
 	LabelSelector                      string
 	WatchNamespace                     string
 	CliTimeout                         int
 	MaxConcurrentReconciles            int
+	KubeAPIQPS                         float64
+	KubeAPIBurst                       int
 	LogFileMaxSize                     int
 	LogFileMaxAge                      int
 	MaxNumberOfOldLogFiles             int
 	LogFileMinAge                      time.Duration
 	GetTimeout                         time.Duration
 	PostTimeout                        time.Duration
+	KubeAPIMaxThrottlingDelay          time.Duration
+	SidecarProxy                       string
+	PodDeletionProtectionExemptUsers   string
+	WebhookCertDir                     string
 	DeprecationOptions                 internal.DeprecationOptions
 }
 
@@ -87,8 +114,12 @@ func (o *Options) BindFlags(fs *flag.FlagSet) {
 		"Apply defaults from the next major version of the operator. This is only intended for use in development.",
 	)
 	fs.StringVar(&o.LogFile, "log-file", "", "The path to a file to write logs to.")
-	fs.IntVar(&o.CliTimeout, "cli-timeout", 10, "The timeout to use for CLI commands in seconds.")
+	fs.StringVar(&o.ConfigFile, "config-file", "", "The path to a YAML config file, e.g. from a mounted ConfigMap, with settings that override the command line flags. Changes to the config file are reloaded while the operator is running.")
+	fs.IntVar(&o.CliTimeout, "cli-timeout", 10, "The timeout to use for CLI commands in seconds. This can be overwritten per cluster with the commandTimeoutSeconds setting in the automation options.")
 	fs.IntVar(&o.MaxConcurrentReconciles, "max-concurrent-reconciles", 1, "Defines the maximum number of concurrent reconciles for all controllers.")
+	fs.Float64Var(&o.KubeAPIQPS, "kube-api-qps", 20, "Defines the maximum number of requests per second of the operator to the Kubernetes API server.")
+	fs.IntVar(&o.KubeAPIBurst, "kube-api-burst", 30, "Defines the maximum burst of requests of the operator to the Kubernetes API server.")
+	fs.DurationVar(&o.KubeAPIMaxThrottlingDelay, "kube-api-max-throttling-delay", 0, "Defines the maximum duration that the operator delays all requests to the Kubernetes API server after the API server throttled a request. The operator waits for the Retry-After duration of the throttled request, limited by this duration. Requests of the leader election are never delayed. If 0, requests are not delayed.")
 	fs.BoolVar(&o.CleanUpOldLogFile, "cleanup-old-cli-logs", true, "Defines if the operator should delete old fdbcli log files.")
 	fs.DurationVar(&o.LogFileMinAge, "log-file-min-age", 5*time.Minute, "Defines the minimum age of fdbcli log files before removing when \"--cleanup-old-cli-logs\" is set.")
 	fs.IntVar(&o.LogFileMaxAge, "log-file-max-age", 28, "Defines the maximum age to retain old operator log file in number of days.")
@@ -102,8 +133,24 @@ func (o *Options) BindFlags(fs *flag.FlagSet) {
 	fs.StringVar(&o.WatchNamespace, "watch-namespace", os.Getenv("WATCH_NAMESPACE"), "Defines which namespace the operator should watch.")
 	fs.DurationVar(&o.GetTimeout, "get-timeout", 5*time.Second, "http timeout for get requests to the FDB sidecar.")
 	fs.DurationVar(&o.PostTimeout, "post-timeout", 10*time.Second, "http timeout for post requests to the FDB sidecar.")
+	fs.StringVar(&o.SidecarProxy, "sidecar-proxy", internal.ProxyFromEnvironment, "Defines the proxy for requests to the FDB sidecar. \"environment\" uses the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables, \"none\" disables the proxy and any other value is used as the URL of the proxy.")
 	fs.BoolVar(&o.EnableRestartIncompatibleProcesses, "enable-restart-incompatible-processes", true, "This flag enables/disables in the operator to restart incompatible fdbserver processes.")
 	fs.BoolVar(&o.ServerSideApply, "server-side-apply", false, "This flag enables server side apply.")
+	fs.BoolVar(&o.EnableTraceEventReceiver, "enable-trace-event-receiver", false, "This flag enables the endpoint on the metrics server that converts severe trace events sent by the trace log forwarders into Kubernetes events and metrics.")
+	fs.IntVar(&o.AdminClientAuditLogSize, "admin-client-audit-log-size", 0, "Defines how many entries of the admin client audit log are kept in a ConfigMap for each cluster. If 0, the audit entries are only written to the operator log.")
+	fs.BoolVar(&o.DryRun, "dry-run", false, "This flag enables the dry-run mode for all clusters. In dry-run mode the operator only reports the actions it would take as events and in the cluster status without performing any changes.")
+	fs.BoolVar(&o.RunCliCommandsInPods, "run-cli-commands-in-pods", false, "This flag enables running the fdbcli, fdbbackup and fdbrestore commands in short-lived Pods in the namespace of the cluster instead of the operator Pod. The Pods use the image of the main container for the version of the command.")
+	fs.BoolVar(&o.EnableTestScenarios, "enable-test-scenarios", false, "This flag enables the controller for the FoundationDBTestScenario resource, which runs disruptive test scenarios like killing Pods against clusters. This is only intended for testing and must not be enabled in production environments.")
+	fs.BoolVar(&o.EnableClientLibraryCaches, "enable-client-library-caches", false, "This flag enables the controller for the FoundationDBClientLibraryCache resource, which provides the client libraries for the versions of the managed clusters in a volume for client applications. The FoundationDBClientLibraryCache CRD must be installed if this flag is enabled.")
+	fs.StringVar(&o.ClientLibraryCacheHostPathRoot, "client-library-cache-host-path-root", v1beta2.DefaultClientLibraryCacheHostPathRoot, "Defines the directory on the nodes that contains the host paths of the client library caches in the Node mode. The host path of a cache must be inside the directory of the namespace of the cache in this directory.")
+	fs.BoolVar(&o.EnableOperatorConfigs, "enable-operator-configs", false, "This flag enables the FoundationDBOperatorConfig resource, which provides the defaults for the clusters that reference the config. The FoundationDBOperatorConfig CRD must be installed and the operator must be allowed to read the cluster-scoped FoundationDBOperatorConfig resources if this flag is enabled.")
+	fs.BoolVar(&o.EnablePodDeletionProtection, "enable-pod-deletion-protection", false, "This flag enables the admission webhook that rejects the deletion of Pods of a FoundationDBCluster if the deletion would exceed the fault tolerance of the cluster. The webhook must be registered with a ValidatingWebhookConfiguration.")
+	fs.BoolVar(&o.EnableClusterAdmissionWarnings, "enable-cluster-admission-warnings", false, "This flag enables the admission webhook that returns warnings for risky but allowed changes of FoundationDBClusters, e.g. role counts below the recommended counts or upgrades that skip versions, and rejects clusters that require more resources than the resource quotas of the namespace allow. The webhook must be registered with a ValidatingWebhookConfiguration.")
+	fs.BoolVar(&o.EnableCertManager, "enable-cert-manager", false, "This flag enables the watch on the cert-manager Certificates of the clusters that request their certificates from cert-manager, so a renewed certificate is rolled out without waiting for the next reconciliation. The cert-manager CRDs must be installed if this flag is enabled.")
+	fs.BoolVar(&o.EnablePprof, "enable-pprof", false, "This flag enables the pprof endpoints under /debug/pprof/ on the metrics server, which allow to collect CPU, memory and goroutine profiles of the operator. The endpoints are not authenticated, so the metrics server must not be reachable from untrusted networks if this flag is enabled.")
+	fs.StringVar(&o.PodDeletionProtectionExemptUsers, "pod-deletion-protection-exempt-users", "", "A comma separated list of additional users whose Pod deletions are never rejected by the pod deletion protection, e.g. \"system:serviceaccount:fdb:fdb-backup-agent\". The service account of the operator is always exempt.")
+	fs.StringVar(&o.WebhookCertDir, "webhook-cert-dir", "", "The directory that contains the tls.crt and tls.key files for the webhook server. If empty, the default directory of the webhook server is used.")
+	fs.Var(&o.DeprecationOptions.FeatureGates, "feature-gates", "A comma separated list of feature=bool pairs that enable or disable features of the operator for all clusters, e.g. \"UnifiedImage=true\".")
 	fs.BoolVar(&o.EnableRecoveryState, "enable-recovery-state", true, "This flag enables the use of the recovery state for the minimum uptime between bounced if the FDB version supports it.")
 }
 
@@ -138,6 +185,21 @@ func StartManager(
 	klog.SetLogger(logger)
 
 	setupLog := logger.WithName("setup")
+
+	// The config file is applied on top of the flags, the flag options are kept to apply later changes of the config
+	// file on top of them.
+	flagOptions := operatorOpts
+	var configContent []byte
+	if operatorOpts.ConfigFile != "" {
+		operatorOpts, configContent, err = loadConfigFile(flagOptions.ConfigFile, flagOptions)
+		if err != nil {
+			setupLog.Error(err, "unable to load operator config file", "configFile", flagOptions.ConfigFile)
+			os.Exit(1)
+		}
+
+		setupLog.Info("Loaded operator config file", "configFile", flagOptions.ConfigFile)
+	}
+
 	fdbclient.DefaultCLITimeout = time.Duration(operatorOpts.CliTimeout) * time.Second
 
 	options := ctrl.Options{
@@ -146,6 +208,7 @@ func StartManager(
 		LeaderElection:     operatorOpts.EnableLeaderElection,
 		LeaderElectionID:   operatorOpts.LeaderElectionID,
 		Port:               9443,
+		CertDir:            operatorOpts.WebhookCertDir,
 	}
 
 	if operatorOpts.WatchNamespace != "" {
@@ -155,17 +218,47 @@ func StartManager(
 		setupLog.Info("Operator starting in Global mode")
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+	restConfig := ctrl.GetConfigOrDie()
+	controllers.ConfigureAPIThrottling(restConfig, &options, controllers.APIThrottlingOptions{
+		QPS:                float32(operatorOpts.KubeAPIQPS),
+		Burst:              operatorOpts.KubeAPIBurst,
+		MaxThrottlingDelay: operatorOpts.KubeAPIMaxThrottlingDelay,
+	})
+
+	mgr, err := ctrl.NewManager(restConfig, options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	checkRBACPermissions(setupLog, mgr.GetClient(), operatorOpts, backupReconciler != nil || restoreReconciler != nil)
+
+	if operatorOpts.EnablePprof && operatorOpts.MetricsAddr != "0" {
+		for handlerPath, handler := range getPprofHandlers() {
+			if err := mgr.AddMetricsExtraHandler(handlerPath, handler); err != nil {
+				setupLog.Error(err, "unable to add pprof endpoint", "path", handlerPath)
+				os.Exit(1)
+			}
+		}
+	}
+
 	if err := moveFDBBinaries(setupLog); err != nil {
 		setupLog.Error(err, "unable to move FDB binaries")
 		os.Exit(1)
 	}
 
+	databaseClientProvider := fdbclient.NewDatabaseClientProvider(logger)
+	if operatorOpts.RunCliCommandsInPods {
+		kubeClientSet, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create Kubernetes client set")
+			os.Exit(1)
+		}
+
+		setupLog.Info("Operator runs CLI commands in Pods")
+		databaseClientProvider = fdbclient.NewPodDatabaseClientProvider(logger, kubeClientSet)
+	}
+
 	labelSelector, err := metav1.ParseToLabelSelector(strings.Trim(operatorOpts.LabelSelector, "\""))
 	if err != nil {
 		setupLog.Error(err, "unable to parse provided label selector")
@@ -176,30 +269,79 @@ func StartManager(
 		clusterReconciler.Client = mgr.GetClient()
 		clusterReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbcluster-controller")
 		clusterReconciler.DeprecationOptions = operatorOpts.DeprecationOptions
-		clusterReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
+		clusterReconciler.DatabaseClientProvider = databaseClientProvider
 		clusterReconciler.GetTimeout = operatorOpts.GetTimeout
 		clusterReconciler.PostTimeout = operatorOpts.PostTimeout
+		clusterReconciler.SidecarProxy, err = internal.GetProxyFunc(operatorOpts.SidecarProxy)
+		if err != nil {
+			setupLog.Error(err, "unable to parse provided sidecar proxy")
+			os.Exit(1)
+		}
 		clusterReconciler.Log = logr.WithName("controllers").WithName("FoundationDBCluster")
 		clusterReconciler.EnableRestartIncompatibleProcesses = operatorOpts.EnableRestartIncompatibleProcesses
 		clusterReconciler.ServerSideApply = operatorOpts.ServerSideApply
 		clusterReconciler.EnableRecoveryState = operatorOpts.EnableRecoveryState
+		clusterReconciler.AdminClientAuditLogSize = operatorOpts.AdminClientAuditLogSize
+		clusterReconciler.DryRun = operatorOpts.DryRun
+		clusterReconciler.EnableOperatorConfigs = operatorOpts.EnableOperatorConfigs
+		clusterReconciler.EnableCertManager = operatorOpts.EnableCertManager
 
 		if err := clusterReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector, watchedObjects...); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBCluster")
 			os.Exit(1)
 		}
 
+		if operatorOpts.EnablePodDeletionProtection {
+			setupLog.Info("Operator runs with the pod deletion protection enabled")
+			mgr.GetWebhookServer().Register(controllers.PodDeletionProtectionPath, &webhook.Admission{
+				Handler: &controllers.PodDeletionProtection{
+					Client:       mgr.GetClient(),
+					OperatorUser: getServiceAccountUsername(mgr.GetConfig()),
+					ExemptUsers:  parseExemptUsers(operatorOpts.PodDeletionProtectionExemptUsers),
+				},
+			})
+		}
+
+		if operatorOpts.EnableClusterAdmissionWarnings {
+			setupLog.Info("Operator runs with the cluster admission warnings enabled")
+			mgr.GetWebhookServer().Register(controllers.ClusterAdmissionWarningsPath, &webhook.Admission{
+				Handler: &controllers.ClusterAdmissionWarnings{
+					Client: mgr.GetClient(),
+				},
+			})
+		}
+
+		// The status reporter only maintains status reports for clusters that have the status report enabled.
+		if err := mgr.Add(controllers.NewClusterStatusReporter(clusterReconciler)); err != nil {
+			setupLog.Error(err, "unable to add cluster status reporter")
+			os.Exit(1)
+		}
+
 		if operatorOpts.MetricsAddr != "0" {
 			controllers.InitCustomMetrics(clusterReconciler)
+
+			// The latency prober only probes clusters that have the latency probes enabled.
+			if err := mgr.Add(controllers.NewLatencyProber(clusterReconciler)); err != nil {
+				setupLog.Error(err, "unable to add latency prober")
+				os.Exit(1)
+			}
+
+			if operatorOpts.EnableTraceEventReceiver {
+				if err := mgr.AddMetricsExtraHandler(internal.TraceEventReceiverPath, controllers.NewTraceEventReceiver(clusterReconciler)); err != nil {
+					setupLog.Error(err, "unable to add trace event receiver")
+					os.Exit(1)
+				}
+			}
 		}
 	}
 
 	if backupReconciler != nil {
 		backupReconciler.Client = mgr.GetClient()
 		backupReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbbackup-controller")
-		backupReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
+		backupReconciler.DatabaseClientProvider = databaseClientProvider
 		backupReconciler.Log = logr.WithName("controllers").WithName("FoundationDBBackup")
 		backupReconciler.ServerSideApply = operatorOpts.ServerSideApply
+		backupReconciler.DeprecationOptions = operatorOpts.DeprecationOptions
 
 		if err := backupReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBBackup")
@@ -210,7 +352,7 @@ func StartManager(
 	if restoreReconciler != nil {
 		restoreReconciler.Client = mgr.GetClient()
 		restoreReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbrestore-controller")
-		restoreReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
+		restoreReconciler.DatabaseClientProvider = databaseClientProvider
 		restoreReconciler.Log = logr.WithName("controllers").WithName("FoundationDBRestore")
 		restoreReconciler.ServerSideApply = operatorOpts.ServerSideApply
 
@@ -220,6 +362,52 @@ func StartManager(
 		}
 	}
 
+	if operatorOpts.EnableTestScenarios {
+		setupLog.Info("Operator runs with test scenarios enabled")
+		testScenarioReconciler := &controllers.FoundationDBTestScenarioReconciler{
+			Client:          mgr.GetClient(),
+			Recorder:        mgr.GetEventRecorderFor("foundationdbtestscenario-controller"),
+			Log:             logr.WithName("controllers").WithName("FoundationDBTestScenario"),
+			ServerSideApply: operatorOpts.ServerSideApply,
+		}
+
+		if err := testScenarioReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBTestScenario")
+			os.Exit(1)
+		}
+	}
+
+	if operatorOpts.EnableClientLibraryCaches {
+		setupLog.Info("Operator runs with client library caches enabled")
+		clientLibraryCacheReconciler := &controllers.FoundationDBClientLibraryCacheReconciler{
+			Client:          mgr.GetClient(),
+			Recorder:        mgr.GetEventRecorderFor("foundationdbclientlibrarycache-controller"),
+			Log:             logr.WithName("controllers").WithName("FoundationDBClientLibraryCache"),
+			ServerSideApply: operatorOpts.ServerSideApply,
+			HostPathRoot:    operatorOpts.ClientLibraryCacheHostPathRoot,
+		}
+
+		if err := clientLibraryCacheReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBClientLibraryCache")
+			os.Exit(1)
+		}
+	}
+
+	if operatorOpts.ConfigFile != "" {
+		reloader := &configReloader{
+			flagOptions:       flagOptions,
+			startupOptions:    operatorOpts,
+			clusterReconciler: clusterReconciler,
+			log:               logger.WithName("configReloader"),
+			content:           configContent,
+		}
+
+		if err := mgr.Add(reloader); err != nil {
+			setupLog.Error(err, "unable to add operator config reloader")
+			os.Exit(1)
+		}
+	}
+
 	if operatorOpts.CleanUpOldLogFile {
 		setupLog.V(1).Info("setup log file cleaner", "LogFileMinAge", operatorOpts.LogFileMinAge.String())
 		cleaner := internal.NewCliLogFileCleaner(logger, operatorOpts.LogFileMinAge)
@@ -365,3 +553,95 @@ func setupLogger(operatorOpts Options) (io.Writer, error) {
 
 	return os.Stdout, nil
 }
+
+// parseExemptUsers parses the comma separated list of users that are exempt from the pod deletion protection.
+func parseExemptUsers(users string) []string {
+	var result []string
+	for _, user := range strings.Split(users, ",") {
+		user = strings.TrimSpace(user)
+		if user == "" {
+			continue
+		}
+
+		result = append(result, user)
+	}
+
+	return result
+}
+
+// getPprofHandlers returns the handlers of the pprof endpoints by their path on the metrics server.
+func getPprofHandlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		"/debug/pprof/":        http.HandlerFunc(pprof.Index),
+		"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
+		"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
+		"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
+		"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
+	}
+}
+
+// checkRBACPermissions checks if the operator has the permissions it requires for the enabled features and logs the
+// missing permissions and the cluster-wide permissions that are not required when watching a single namespace. The
+// operator is started independent of the result, as some permissions are only required by some clusters.
+func checkRBACPermissions(log logr.Logger, kubeClient client.Client, operatorOpts Options, enableBackups bool) {
+	options := internal.RBACOptions{
+		EnableBackups:             enableBackups,
+		EnableTestScenarios:       operatorOpts.EnableTestScenarios,
+		EnableClientLibraryCaches: operatorOpts.EnableClientLibraryCaches,
+		EnableOperatorConfigs:     operatorOpts.EnableOperatorConfigs,
+		EnableCertManager:         operatorOpts.EnableCertManager,
+	}
+
+	if operatorOpts.WatchNamespace != "" {
+		options.WatchNamespaces = []string{operatorOpts.WatchNamespace}
+	}
+
+	missing, excessive, err := internal.CheckRBACPermissions(context.Background(), kubeClient, options)
+	if err != nil {
+		log.Error(err, "unable to check the RBAC permissions of the operator")
+		return
+	}
+
+	if len(missing) > 0 {
+		log.Info("Operator is missing RBAC permissions, reconciliations that require them will fail", "missing", missing)
+	}
+
+	if len(excessive) > 0 {
+		log.Info("Operator has cluster-wide RBAC permissions that are not required in single namespace mode, use kubectl fdb generate rbac to generate minimal RBAC manifests", "permissions", excessive)
+	}
+}
+
+// getServiceAccountUsername returns the username of the service account that the operator uses to authenticate
+// against the Kubernetes API. The username is taken from the subject of the service account token, if the operator
+// doesn't use a service account token an empty string is returned.
+func getServiceAccountUsername(config *rest.Config) string {
+	token := config.BearerToken
+	if token == "" && config.BearerTokenFile != "" {
+		content, err := os.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return ""
+		}
+
+		token = strings.TrimSpace(string(content))
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil || !strings.HasPrefix(claims.Subject, "system:serviceaccount:") {
+		return ""
+	}
+
+	return claims.Subject
+}
@@ -21,10 +21,14 @@
 package setup
 
 import (
+	"encoding/base64"
+	"fmt"
 	"io/fs"
 	"os"
 	"path"
 
+	"k8s.io/client-go/rest"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -108,4 +112,54 @@ var _ = Describe("setup", func() {
 			})
 		})
 	})
+
+	When("getting the username of the service account", func() {
+		var config *rest.Config
+
+		getToken := func(subject string) string {
+			payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"%s"}`, subject)))
+			return "eyJhbGciOiJSUzI1NiJ9." + payload + ".signature"
+		}
+
+		BeforeEach(func() {
+			config = &rest.Config{}
+		})
+
+		When("the token is a service account token", func() {
+			BeforeEach(func() {
+				config.BearerToken = getToken("system:serviceaccount:fdb:fdb-kubernetes-operator-controller-manager")
+			})
+
+			It("should return the username of the service account", func() {
+				Expect(getServiceAccountUsername(config)).To(Equal("system:serviceaccount:fdb:fdb-kubernetes-operator-controller-manager"))
+			})
+		})
+
+		When("the token is read from a file", func() {
+			BeforeEach(func() {
+				config.BearerTokenFile = path.Join(GinkgoT().TempDir(), "token")
+				Expect(os.WriteFile(config.BearerTokenFile, []byte(getToken("system:serviceaccount:fdb:operator")+"\n"), 0600)).To(Succeed())
+			})
+
+			It("should return the username of the service account", func() {
+				Expect(getServiceAccountUsername(config)).To(Equal("system:serviceaccount:fdb:operator"))
+			})
+		})
+
+		When("the token doesn't belong to a service account", func() {
+			BeforeEach(func() {
+				config.BearerToken = getToken("admin")
+			})
+
+			It("should return an empty string", func() {
+				Expect(getServiceAccountUsername(config)).To(BeEmpty())
+			})
+		})
+
+		When("no token is used", func() {
+			It("should return an empty string", func() {
+				Expect(getServiceAccountUsername(config)).To(BeEmpty())
+			})
+		})
+	})
 })